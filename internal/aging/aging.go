@@ -0,0 +1,263 @@
+// Package aging is the policy engine for bead priority aging.
+//
+// Two rules run over a beads database:
+//
+//   - Escalation: a work bead that has been ready (open, unassigned, all
+//     dependencies closed) without an update for BumpAfter gets its
+//     priority raised by one, up to MaxPriority. The update restarts the
+//     clock, so a bead nobody picks up keeps climbing.
+//   - Stale blockers: an open bead that is itself unblocked but has gone
+//     StaleAfter without an update, while holding up BlockedThreshold or
+//     more open beads (directly or through a chain), is labeled
+//     aging:stale-blocker so it stands out in bd list and gt aging status.
+//
+// Evaluate is pure; Apply writes the result back through bd.
+package aging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Policy defaults.
+const (
+	DefaultBumpAfterDays    = 3
+	DefaultMaxPriority      = 1
+	DefaultStaleAfterDays   = 2
+	DefaultBlockedThreshold = 3
+)
+
+// LabelStaleBlocker marks a stale bead holding up a chain of others.
+const LabelStaleBlocker = "aging:stale-blocker"
+
+// workTypes are the bead types aging applies to. Agents, molecules, mail,
+// events and other infrastructure beads are left alone.
+var workTypes = map[string]bool{"task": true, "bug": true, "feature": true, "chore": true}
+
+// Policy holds the aging thresholds.
+type Policy struct {
+	BumpAfter        time.Duration
+	MaxPriority      int
+	StaleAfter       time.Duration
+	BlockedThreshold int
+}
+
+// NewPolicy builds a policy from town settings, applying defaults.
+func NewPolicy(cfg *config.AgingConfig) Policy {
+	p := Policy{
+		BumpAfter:        DefaultBumpAfterDays * 24 * time.Hour,
+		MaxPriority:      DefaultMaxPriority,
+		StaleAfter:       DefaultStaleAfterDays * 24 * time.Hour,
+		BlockedThreshold: DefaultBlockedThreshold,
+	}
+	if cfg == nil {
+		return p
+	}
+	if cfg.BumpAfterDays > 0 {
+		p.BumpAfter = time.Duration(cfg.BumpAfterDays) * 24 * time.Hour
+	}
+	if cfg.MaxPriority != nil {
+		p.MaxPriority = *cfg.MaxPriority
+	}
+	if cfg.StaleAfterDays > 0 {
+		p.StaleAfter = time.Duration(cfg.StaleAfterDays) * 24 * time.Hour
+	}
+	if cfg.BlockedThreshold > 0 {
+		p.BlockedThreshold = cfg.BlockedThreshold
+	}
+	return p
+}
+
+// Bump is a priority raise for a ready bead nobody has claimed.
+type Bump struct {
+	ID    string        `json:"id"`
+	Title string        `json:"title"`
+	From  int           `json:"from"`
+	To    int           `json:"to"`
+	Idle  time.Duration `json:"idle_ns"`
+}
+
+// Blocker is a stale bead holding up others.
+type Blocker struct {
+	ID         string        `json:"id"`
+	Title      string        `json:"title"`
+	Assignee   string        `json:"assignee,omitempty"`
+	Idle       time.Duration `json:"idle_ns"`
+	Downstream []string      `json:"downstream"` // open beads waiting on it, nearest first
+	New        bool          `json:"new"`        // not labeled by an earlier run
+}
+
+// Report is what the policy would change.
+type Report struct {
+	Bumps    []Bump    `json:"bumps"`
+	Blockers []Blocker `json:"blockers"`
+	Cleared  []string  `json:"cleared"` // labeled stale blockers that no longer are
+}
+
+// Empty reports whether the report changes nothing.
+func (r *Report) Empty() bool {
+	return len(r.Bumps) == 0 && len(r.Blockers) == 0 && len(r.Cleared) == 0
+}
+
+// Evaluate applies the policy to issues (as listed with status "all").
+func (p Policy) Evaluate(issues []*beads.Issue, now time.Time) *Report {
+	byID := make(map[string]*beads.Issue, len(issues))
+	dependents := make(map[string][]string)
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	for _, issue := range issues {
+		if isOpen(issue) {
+			for _, dep := range issue.DependsOn {
+				dependents[dep] = append(dependents[dep], issue.ID)
+			}
+		}
+	}
+
+	// unblocked reports whether every dependency is known and closed.
+	// Dependencies outside this database can't be checked, so they
+	// count as blocking.
+	unblocked := func(issue *beads.Issue) bool {
+		for _, dep := range issue.DependsOn {
+			if d, ok := byID[dep]; !ok || isOpen(d) {
+				return false
+			}
+		}
+		return true
+	}
+
+	r := &Report{}
+	flagged := make(map[string]bool)
+	for _, issue := range issues {
+		if !isOpen(issue) || !isWork(issue) || !unblocked(issue) {
+			continue
+		}
+		idle, ok := idleFor(issue, now)
+		if !ok {
+			continue
+		}
+
+		if issue.Status == "open" && issue.Assignee == "" && idle >= p.BumpAfter && issue.Priority > p.MaxPriority {
+			r.Bumps = append(r.Bumps, Bump{ID: issue.ID, Title: issue.Title, From: issue.Priority, To: issue.Priority - 1, Idle: idle})
+		}
+
+		if idle >= p.StaleAfter {
+			if downstream := downstreamOf(issue.ID, dependents, byID); len(downstream) >= p.BlockedThreshold {
+				r.Blockers = append(r.Blockers, Blocker{
+					ID:         issue.ID,
+					Title:      issue.Title,
+					Assignee:   issue.Assignee,
+					Idle:       idle,
+					Downstream: downstream,
+					New:        !hasLabel(issue, LabelStaleBlocker),
+				})
+				flagged[issue.ID] = true
+			}
+		}
+	}
+
+	for _, issue := range issues {
+		if hasLabel(issue, LabelStaleBlocker) && !flagged[issue.ID] {
+			r.Cleared = append(r.Cleared, issue.ID)
+		}
+	}
+
+	sort.Slice(r.Bumps, func(i, j int) bool { return r.Bumps[i].ID < r.Bumps[j].ID })
+	sort.SliceStable(r.Blockers, func(i, j int) bool {
+		return len(r.Blockers[i].Downstream) > len(r.Blockers[j].Downstream)
+	})
+	sort.Strings(r.Cleared)
+	return r
+}
+
+// Apply writes a report back: raising priorities and adding or removing the
+// stale-blocker label. It stops at the first failed update.
+func Apply(b *beads.Beads, r *Report) error {
+	for _, bump := range r.Bumps {
+		to := bump.To
+		if err := b.Update(bump.ID, beads.UpdateOptions{Priority: &to}); err != nil {
+			return fmt.Errorf("raising priority of %s: %w", bump.ID, err)
+		}
+	}
+	for _, blocker := range r.Blockers {
+		if !blocker.New {
+			continue
+		}
+		if err := b.Update(blocker.ID, beads.UpdateOptions{AddLabels: []string{LabelStaleBlocker}}); err != nil {
+			return fmt.Errorf("labeling %s: %w", blocker.ID, err)
+		}
+	}
+	for _, id := range r.Cleared {
+		if err := b.Update(id, beads.UpdateOptions{RemoveLabels: []string{LabelStaleBlocker}}); err != nil {
+			return fmt.Errorf("unlabeling %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// downstreamOf returns the open beads that wait on id, directly or through
+// a chain, in breadth-first order.
+func downstreamOf(id string, dependents map[string][]string, byID map[string]*beads.Issue) []string {
+	seen := map[string]bool{id: true}
+	var out []string
+	queue := []string{id}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for _, d := range dependents[next] {
+			if seen[d] {
+				continue
+			}
+			seen[d] = true
+			if issue := byID[d]; issue != nil && isOpen(issue) {
+				out = append(out, d)
+				queue = append(queue, d)
+			}
+		}
+	}
+	return out
+}
+
+func isOpen(issue *beads.Issue) bool {
+	return issue.Status != "closed" && issue.Status != "tombstone"
+}
+
+// isWork reports whether issue is a work bead rather than an agent,
+// molecule step or other infrastructure bead.
+func isWork(issue *beads.Issue) bool {
+	if !workTypes[issue.Type] || beads.StepRef(issue) != "" {
+		return false
+	}
+	for _, l := range issue.Labels {
+		if strings.HasPrefix(l, "gt:") {
+			return false
+		}
+	}
+	return true
+}
+
+func idleFor(issue *beads.Issue, now time.Time) (time.Duration, bool) {
+	updated := issue.UpdatedAt
+	if updated == "" {
+		updated = issue.CreatedAt
+	}
+	t, err := time.Parse(time.RFC3339, updated)
+	if err != nil {
+		return 0, false
+	}
+	return now.Sub(t), true
+}
+
+func hasLabel(issue *beads.Issue, label string) bool {
+	for _, l := range issue.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package aging
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+var now = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+func daysAgo(d int) string {
+	return now.Add(-time.Duration(d) * 24 * time.Hour).Format(time.RFC3339)
+}
+
+func TestNewPolicyDefaults(t *testing.T) {
+	p := NewPolicy(nil)
+	if p.BumpAfter != 3*24*time.Hour || p.MaxPriority != 1 || p.BlockedThreshold != 3 {
+		t.Errorf("defaults = %+v", p)
+	}
+
+	zero := 0
+	p = NewPolicy(&config.AgingConfig{BumpAfterDays: 7, MaxPriority: &zero})
+	if p.BumpAfter != 7*24*time.Hour || p.MaxPriority != 0 || p.StaleAfter != 2*24*time.Hour {
+		t.Errorf("configured = %+v", p)
+	}
+}
+
+func TestEvaluateBumps(t *testing.T) {
+	issues := []*beads.Issue{
+		{ID: "gt-old", Type: "task", Status: "open", Priority: 3, UpdatedAt: daysAgo(4)},
+		{ID: "gt-fresh", Type: "task", Status: "open", Priority: 3, UpdatedAt: daysAgo(1)},
+		{ID: "gt-claimed", Type: "bug", Status: "open", Priority: 3, Assignee: "gastown/polecats/nux", UpdatedAt: daysAgo(9)},
+		{ID: "gt-urgent", Type: "bug", Status: "open", Priority: 1, UpdatedAt: daysAgo(9)},
+		{ID: "gt-blocked", Type: "task", Status: "open", Priority: 3, UpdatedAt: daysAgo(9), DependsOn: []string{"gt-fresh"}},
+		{ID: "gt-unblocked", Type: "task", Status: "open", Priority: 2, UpdatedAt: daysAgo(9), DependsOn: []string{"gt-done"}},
+		{ID: "gt-done", Type: "task", Status: "closed", Priority: 2, UpdatedAt: daysAgo(9)},
+		{ID: "gt-agent", Type: "agent", Status: "open", Priority: 2, UpdatedAt: daysAgo(9)},
+		{ID: "gt-mol.1", Type: "task", Status: "open", Priority: 2, UpdatedAt: daysAgo(9), Description: "step: build"},
+	}
+
+	r := NewPolicy(nil).Evaluate(issues, now)
+	var got []string
+	for _, b := range r.Bumps {
+		got = append(got, b.ID)
+		if b.To != b.From-1 {
+			t.Errorf("%s bumped %d -> %d, want one level", b.ID, b.From, b.To)
+		}
+	}
+	if want := []string{"gt-old", "gt-unblocked"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bumped %v, want %v", got, want)
+	}
+}
+
+func TestEvaluateStaleBlockers(t *testing.T) {
+	issues := []*beads.Issue{
+		// gt-root holds up a chain of three
+		{ID: "gt-root", Type: "task", Status: "in_progress", Assignee: "gastown/polecats/nux", Priority: 2, UpdatedAt: daysAgo(5)},
+		{ID: "gt-a", Type: "task", Status: "open", Priority: 2, UpdatedAt: daysAgo(5), DependsOn: []string{"gt-root"}},
+		{ID: "gt-b", Type: "task", Status: "open", Priority: 2, UpdatedAt: daysAgo(5), DependsOn: []string{"gt-a"}},
+		{ID: "gt-c", Type: "task", Status: "open", Priority: 2, UpdatedAt: daysAgo(5), DependsOn: []string{"gt-root", "gt-b"}},
+		// gt-busy blocks as many but is being worked on
+		{ID: "gt-busy", Type: "task", Status: "in_progress", Priority: 2, UpdatedAt: daysAgo(0)},
+		{ID: "gt-d", Type: "task", Status: "open", Priority: 2, UpdatedAt: daysAgo(5), DependsOn: []string{"gt-busy"}},
+		{ID: "gt-e", Type: "task", Status: "open", Priority: 2, UpdatedAt: daysAgo(5), DependsOn: []string{"gt-busy"}},
+		{ID: "gt-f", Type: "task", Status: "open", Priority: 2, UpdatedAt: daysAgo(5), DependsOn: []string{"gt-busy"}},
+		// labeled earlier, no longer blocking anything
+		{ID: "gt-was", Type: "task", Status: "open", Priority: 2, UpdatedAt: daysAgo(5), Labels: []string{LabelStaleBlocker}},
+	}
+
+	r := NewPolicy(&config.AgingConfig{BumpAfterDays: 30}).Evaluate(issues, now)
+	if len(r.Blockers) != 1 {
+		t.Fatalf("blockers = %+v, want only gt-root", r.Blockers)
+	}
+	b := r.Blockers[0]
+	if b.ID != "gt-root" || !b.New {
+		t.Errorf("blocker = %+v", b)
+	}
+	if want := []string{"gt-a", "gt-c", "gt-b"}; !reflect.DeepEqual(b.Downstream, want) {
+		t.Errorf("downstream = %v, want %v", b.Downstream, want)
+	}
+	if want := []string{"gt-was"}; !reflect.DeepEqual(r.Cleared, want) {
+		t.Errorf("cleared = %v, want %v", r.Cleared, want)
+	}
+}
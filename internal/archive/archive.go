@@ -0,0 +1,268 @@
+// Package archive offloads old polecat transcripts, step artifacts and
+// event log entries from the town to remote storage (see Store), keeping
+// the harness small on long-lived towns.
+//
+// Each archived file is recorded in <town>/.logs/archive.jsonl under its
+// town-relative path, so 'gt logs' can fetch a transcript back from the
+// archive when its local copy is gone. Events are cut from the head of
+// .events.jsonl into spool files under .logs/events/ and archived from
+// there; a spool file that fails to upload is retried on the next run.
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/artifacts"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+// DefaultAfterDays is how old an item must be to be archived when the
+// town's archive config doesn't say.
+const DefaultAfterDays = 30
+
+// IndexFile records archived files within the town log directory.
+const IndexFile = "archive.jsonl"
+
+// SpoolDir holds event log entries waiting to be archived, within the town
+// log directory.
+const SpoolDir = "events"
+
+// Item kinds.
+const (
+	KindTranscript = "transcript"
+	KindArtifact   = "artifact"
+	KindEvents     = "events"
+)
+
+// ErrNotArchived is returned for a file the archive has no record of.
+var ErrNotArchived = errors.New("not archived")
+
+// Item is one archived file.
+type Item struct {
+	Kind     string    `json:"kind"`
+	Path     string    `json:"path"` // town-relative, slash-separated
+	Key      string    `json:"key"`
+	Size     int64     `json:"size"`
+	Archived time.Time `json:"archived"`
+}
+
+// Run archives the town's transcripts and artifacts last written before
+// cutoff, and its events from before cutoff, removing each local copy once
+// it is stored. It returns the items archived, which are all recorded even
+// when a later one fails.
+func Run(townRoot string, store Store, cutoff time.Time) ([]Item, error) {
+	lock := flock.New(filepath.Join(logging.Dir(townRoot), IndexFile+".lock"))
+	if err := os.MkdirAll(logging.Dir(townRoot), 0755); err != nil {
+		return nil, err
+	}
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking archive index: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	if err := spoolEvents(townRoot, cutoff); err != nil {
+		return nil, fmt.Errorf("trimming events: %w", err)
+	}
+	candidates, err := collect(townRoot, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	var done []Item
+	for _, c := range candidates {
+		rel, err := filepath.Rel(townRoot, c.path)
+		if err != nil {
+			return done, err
+		}
+		item := Item{
+			Kind:     c.kind,
+			Path:     filepath.ToSlash(rel),
+			Key:      now.Format("2006-01-02") + "/" + filepath.ToSlash(rel),
+			Size:     c.size,
+			Archived: now,
+		}
+		if err := store.Put(item.Key, c.path); err != nil {
+			return done, fmt.Errorf("archiving %s: %w", item.Path, err)
+		}
+		if err := record(townRoot, item); err != nil {
+			return done, err
+		}
+		if err := os.Remove(c.path); err != nil {
+			return done, err
+		}
+		done = append(done, item)
+	}
+	pruneEmptyDirs(artifacts.Dir(townRoot))
+	return done, nil
+}
+
+// candidate is a local file due for archiving.
+type candidate struct {
+	kind string
+	path string
+	size int64
+}
+
+// collect finds the files Run archives: transcripts and artifacts not
+// modified since cutoff, and every spooled events file.
+func collect(townRoot string, cutoff time.Time) ([]candidate, error) {
+	var out []candidate
+	add := func(kind, dir string, old func(os.FileInfo) bool) error {
+		err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() || !info.Mode().IsRegular() || !old(info) {
+				return nil
+			}
+			out = append(out, candidate{kind: kind, path: p, size: info.Size()})
+			return nil
+		})
+		return err
+	}
+	olderThanCutoff := func(info os.FileInfo) bool {
+		return info.ModTime().Before(cutoff) && !strings.HasPrefix(info.Name(), ".")
+	}
+
+	if err := add(KindTranscript, transcript.Dir(townRoot), func(info os.FileInfo) bool {
+		return olderThanCutoff(info) && strings.HasSuffix(info.Name(), ".log")
+	}); err != nil {
+		return nil, err
+	}
+	if err := add(KindArtifact, artifacts.Dir(townRoot), olderThanCutoff); err != nil {
+		return nil, err
+	}
+	if err := add(KindEvents, filepath.Join(logging.Dir(townRoot), SpoolDir), func(os.FileInfo) bool { return true }); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// spoolEvents moves the events before cutoff out of the town's log into a
+// spool file.
+func spoolEvents(townRoot string, cutoff time.Time) error {
+	data, err := events.Trim(townRoot, cutoff)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	dir := filepath.Join(logging.Dir(townRoot), SpoolDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	name := "events-" + time.Now().UTC().Format("20060102-150405") + ".jsonl"
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events are non-sensitive operational data
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// pruneEmptyDirs removes directories under root left empty by archiving,
+// keeping root itself.
+func pruneEmptyDirs(root string) {
+	var dirs []string
+	_ = filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() && p != root {
+			dirs = append(dirs, p)
+		}
+		return nil
+	})
+	// Deepest first, so a parent emptied by its children goes too
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, d := range dirs {
+		_ = os.Remove(d) // Fails, harmlessly, unless empty
+	}
+}
+
+func record(townRoot string, item Item) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(logging.Dir(townRoot), IndexFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: index is non-sensitive
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// List returns the archived items, oldest first.
+func List(townRoot string) ([]Item, error) {
+	f, err := os.Open(filepath.Join(logging.Dir(townRoot), IndexFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var items []Item
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var item Item
+		if json.Unmarshal(scanner.Bytes(), &item) == nil && item.Key != "" {
+			items = append(items, item)
+		}
+	}
+	return items, scanner.Err()
+}
+
+// Find returns the latest archived copy of a file, given by absolute or
+// town-relative path, or by archive key.
+func Find(townRoot, target string) (*Item, error) {
+	items, err := List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.IsAbs(target) {
+		if rel, err := filepath.Rel(townRoot, target); err == nil {
+			target = rel
+		}
+	}
+	target = filepath.ToSlash(target)
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].Path == target || items[i].Key == target {
+			return &items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", target, ErrNotArchived)
+}
+
+// Fetch downloads an archived item's contents.
+func Fetch(store Store, item *Item) ([]byte, error) {
+	tmp, err := os.CreateTemp("", "gt-archive-*")
+	if err != nil {
+		return nil, err
+	}
+	_ = tmp.Close()
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := store.Get(item.Key, tmp.Name()); err != nil {
+		return nil, fmt.Errorf("fetching %s from archive: %w", item.Path, err)
+	}
+	return os.ReadFile(tmp.Name())
+}
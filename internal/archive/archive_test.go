@@ -0,0 +1,138 @@
+package archive
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+func writeFile(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	town := t.TempDir()
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	old := now.Add(-40 * 24 * time.Hour)
+	cutoff := now.Add(-30 * 24 * time.Hour)
+
+	oldLog := filepath.Join(transcript.Dir(town), "gt-old.log")
+	newLog := filepath.Join(transcript.Dir(town), "gt-new.log")
+	writeFile(t, oldLog, "old session\n", old)
+	writeFile(t, newLog, "new session\n", now)
+	writeFile(t, filepath.Join(transcript.Dir(town), transcript.IndexFile), "{}\n", old)
+	oldArtifact := filepath.Join(town, ".artifacts", "run1", "design", "doc.md")
+	writeFile(t, oldArtifact, "# design\n", old)
+	oldEvent := `{"ts":"` + old.UTC().Format(time.RFC3339) + `","type":"sling"}` + "\n"
+	newEvent := `{"ts":"` + now.UTC().Format(time.RFC3339) + `","type":"done"}` + "\n"
+	writeFile(t, filepath.Join(town, events.EventsFile), oldEvent+newEvent, now)
+
+	items, err := Run(town, store, cutoff)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	var kinds []string
+	for _, item := range items {
+		kinds = append(kinds, item.Kind)
+	}
+	if want := []string{KindTranscript, KindArtifact, KindEvents}; !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("archived kinds = %v, want %v", kinds, want)
+	}
+
+	if _, err := os.Stat(oldLog); !os.IsNotExist(err) {
+		t.Errorf("old transcript still present")
+	}
+	if _, err := os.Stat(newLog); err != nil {
+		t.Errorf("new transcript removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(town, ".artifacts", "run1")); !os.IsNotExist(err) {
+		t.Errorf("emptied artifact run directory kept")
+	}
+	if data, _ := os.ReadFile(filepath.Join(town, events.EventsFile)); string(data) != newEvent {
+		t.Errorf("events log = %q, want %q", data, newEvent)
+	}
+
+	item, err := Find(town, oldLog)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	data, err := Fetch(store, item)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if string(data) != "old session\n" {
+		t.Errorf("fetched %q", data)
+	}
+	item, err = Find(town, items[2].Path)
+	if err != nil {
+		t.Fatalf("Find events: %v", err)
+	}
+	if data, _ := Fetch(store, item); string(data) != oldEvent {
+		t.Errorf("fetched events %q, want %q", data, oldEvent)
+	}
+
+	if _, err := Find(town, newLog); !errors.Is(err, ErrNotArchived) {
+		t.Errorf("Find(new) error = %v, want ErrNotArchived", err)
+	}
+
+	// Nothing left to archive
+	items, err = Run(town, store, cutoff)
+	if err != nil || len(items) != 0 {
+		t.Errorf("second Run = %v, %v; want nothing", items, err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		url  string
+		tool string
+		put  string
+	}{
+		{"s3://bucket/gt", "aws", "s3 cp --only-show-errors /tmp/f s3://bucket/gt/k/f"},
+		{"s3://bucket", "aws", "s3 cp --only-show-errors /tmp/f s3://bucket/k/f"},
+		{"gs://bucket/a/b/", "gcloud", "storage cp --quiet /tmp/f gs://bucket/a/b/k/f"},
+		{"az://logs/gt", "az", "storage blob upload --only-show-errors --overwrite --container-name logs --name gt/k/f --file /tmp/f"},
+	}
+	for _, tt := range tests {
+		s, err := Open(tt.url)
+		if err != nil {
+			t.Fatalf("Open(%q): %v", tt.url, err)
+		}
+		cs, ok := s.(*cliStore)
+		if !ok {
+			t.Fatalf("Open(%q) = %T, want a CLI store", tt.url, s)
+		}
+		if got := strings.Join(cs.put("k/f", "/tmp/f"), " "); cs.tool != tt.tool || got != tt.put {
+			t.Errorf("Open(%q) put = %s %s, want %s %s", tt.url, cs.tool, got, tt.tool, tt.put)
+		}
+	}
+
+	if s, err := Open("file:///mnt/archive"); err != nil || s.(*dirStore).root != "/mnt/archive" {
+		t.Errorf("Open(file URL) = %v, %v", s, err)
+	}
+	for _, bad := range []string{"", "ftp://host/x", "s3://"} {
+		if _, err := Open(bad); err == nil {
+			t.Errorf("Open(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Store is remote storage for archived files, addressed by slash-separated
+// keys.
+type Store interface {
+	// Put uploads the local file at path under key.
+	Put(key, path string) error
+	// Get downloads key to the local file at path.
+	Get(key, path string) error
+}
+
+// Open returns the store for an archive URL: s3://bucket/prefix,
+// gs://bucket/prefix, az://container/prefix, or a local directory. The
+// cloud stores run the provider's CLI (aws, gcloud, az), which brings its
+// own credentials.
+func Open(url string) (Store, error) {
+	scheme, rest, ok := strings.Cut(url, "://")
+	if !ok {
+		if url == "" {
+			return nil, fmt.Errorf("no archive URL configured")
+		}
+		return &dirStore{root: url}, nil
+	}
+	bucket, prefix, _ := strings.Cut(strings.Trim(rest, "/"), "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("archive URL %q names no bucket", url)
+	}
+	switch scheme {
+	case "file":
+		return &dirStore{root: "/" + strings.TrimLeft(rest, "/")}, nil
+	case "s3":
+		return &cliStore{tool: "aws",
+			put: func(key, p string) []string {
+				return []string{"s3", "cp", "--only-show-errors", p, "s3://" + bucket + "/" + path.Join(prefix, key)}
+			},
+			get: func(key, p string) []string {
+				return []string{"s3", "cp", "--only-show-errors", "s3://" + bucket + "/" + path.Join(prefix, key), p}
+			},
+		}, nil
+	case "gs":
+		return &cliStore{tool: "gcloud",
+			put: func(key, p string) []string {
+				return []string{"storage", "cp", "--quiet", p, "gs://" + bucket + "/" + path.Join(prefix, key)}
+			},
+			get: func(key, p string) []string {
+				return []string{"storage", "cp", "--quiet", "gs://" + bucket + "/" + path.Join(prefix, key), p}
+			},
+		}, nil
+	case "az":
+		return &cliStore{tool: "az",
+			put: func(key, p string) []string {
+				return []string{"storage", "blob", "upload", "--only-show-errors", "--overwrite",
+					"--container-name", bucket, "--name", path.Join(prefix, key), "--file", p}
+			},
+			get: func(key, p string) []string {
+				return []string{"storage", "blob", "download", "--only-show-errors",
+					"--container-name", bucket, "--name", path.Join(prefix, key), "--file", p}
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown archive scheme %q (want s3, gs, az or a directory)", scheme)
+	}
+}
+
+// dirStore keeps the archive in a local directory, such as a mounted
+// network share.
+type dirStore struct {
+	root string
+}
+
+func (s *dirStore) Put(key, p string) error {
+	dst := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return copyFile(p, dst)
+}
+
+func (s *dirStore) Get(key, p string) error {
+	return copyFile(filepath.Join(s.root, filepath.FromSlash(key)), p)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src) //nolint:gosec // G304: paths are constructed internally
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst) //nolint:gosec // G304: paths are constructed internally
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// cliStore runs a cloud provider's CLI to move files.
+type cliStore struct {
+	tool     string
+	put, get func(key, path string) []string
+}
+
+func (s *cliStore) Put(key, p string) error {
+	return s.run(s.put(key, p))
+}
+
+func (s *cliStore) Get(key, p string) error {
+	return s.run(s.get(key, p))
+}
+
+func (s *cliStore) run(args []string) error {
+	cmd := exec.Command(s.tool, args...) //nolint:gosec // G204: tool is fixed per scheme
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("%s %s: %s", s.tool, strings.Join(args[:2], " "), msg)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Package artifacts stores the named outputs of molecule steps (a design
+// doc, a test report, benchmark results) so later steps in the run, and
+// humans afterwards, can read them.
+//
+// An artifact is a file at <town>/.artifacts/<run>/<step>/<name>, where run
+// is the molecule run (wisp root) ID and step is the formula step ID.
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DirName is the artifact directory under the town root.
+const DirName = ".artifacts"
+
+// ErrNotFound is returned for an artifact that hasn't been stored.
+var ErrNotFound = errors.New("no such artifact")
+
+// Artifact is one stored artifact.
+type Artifact struct {
+	Run      string    `json:"run"`
+	Step     string    `json:"step"`
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+	Path     string    `json:"path"`
+}
+
+// Dir returns the artifact directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, DirName)
+}
+
+// Path returns where an artifact is stored. Each part must be a plain
+// name: not empty, no path separators, not "." or "..".
+func Path(townRoot, run, step, name string) (string, error) {
+	for _, part := range []struct{ what, value string }{{"run", run}, {"step", step}, {"artifact name", name}} {
+		if err := checkName(part.value); err != nil {
+			return "", fmt.Errorf("invalid %s %q: %w", part.what, part.value, err)
+		}
+	}
+	return filepath.Join(Dir(townRoot), run, step, name), nil
+}
+
+// Put stores an artifact, replacing any earlier one with the same name.
+func Put(townRoot, run, step, name string, data []byte) (*Artifact, error) {
+	path, err := Path(townRoot, run, step, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating artifact directory: %w", err)
+	}
+	if err := util.AtomicWriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("writing artifact %s/%s: %w", step, name, err)
+	}
+	return stat(run, step, name, path)
+}
+
+// Read returns an artifact's contents.
+func Read(townRoot, run, step, name string) ([]byte, error) {
+	path, err := Path(townRoot, run, step, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from checked names
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s/%s in run %s", ErrNotFound, step, name, run)
+	}
+	return data, err
+}
+
+// List returns a run's artifacts, ordered by step and name. A run with no
+// artifacts has none, not an error.
+func List(townRoot, run string) ([]Artifact, error) {
+	if err := checkName(run); err != nil {
+		return nil, fmt.Errorf("invalid run %q: %w", run, err)
+	}
+	runDir := filepath.Join(Dir(townRoot), run)
+	steps, err := os.ReadDir(runDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Artifact
+	for _, step := range steps {
+		if !step.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(runDir, step.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+				continue
+			}
+			a, err := stat(run, step.Name(), f.Name(), filepath.Join(runDir, step.Name(), f.Name()))
+			if err != nil {
+				continue
+			}
+			list = append(list, *a)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Step != list[j].Step {
+			return list[i].Step < list[j].Step
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// Runs returns the IDs of runs with stored artifacts.
+func Runs(townRoot string) ([]string, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var runs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			runs = append(runs, e.Name())
+		}
+	}
+	return runs, nil
+}
+
+func stat(run, step, name, path string) (*Artifact, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Artifact{Run: run, Step: step, Name: name, Size: info.Size(), Modified: info.ModTime(), Path: path}, nil
+}
+
+func checkName(s string) error {
+	switch {
+	case s == "":
+		return errors.New("empty")
+	case s == "." || s == "..":
+		return errors.New("not a name")
+	case strings.ContainsAny(s, `/\`):
+		return errors.New("contains a path separator")
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package artifacts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPutReadList(t *testing.T) {
+	town := t.TempDir()
+
+	if list, err := List(town, "gt-wisp-abc"); err != nil || list != nil {
+		t.Fatalf("empty run: %v, %v", list, err)
+	}
+
+	for _, a := range []struct{ step, name, data string }{
+		{"test", "report.txt", "ok"},
+		{"design", "design.md", "# Design"},
+		{"design", "api.md", "first"},
+		{"design", "api.md", "replaced"},
+	} {
+		if _, err := Put(town, "gt-wisp-abc", a.step, a.name, []byte(a.data)); err != nil {
+			t.Fatalf("Put %s/%s: %v", a.step, a.name, err)
+		}
+	}
+
+	data, err := Read(town, "gt-wisp-abc", "design", "api.md")
+	if err != nil || string(data) != "replaced" {
+		t.Errorf("Read = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(town, DirName, "gt-wisp-abc", "design", "design.md")); err != nil {
+		t.Errorf("artifact not at <town>/.artifacts/<run>/<step>/<name>: %v", err)
+	}
+
+	list, err := List(town, "gt-wisp-abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, a := range list {
+		got = append(got, a.Step+"/"+a.Name)
+	}
+	want := []string{"design/api.md", "design/design.md", "test/report.txt"}
+	if len(got) != len(want) {
+		t.Fatalf("List = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("List[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+	if list[0].Size != int64(len("replaced")) {
+		t.Errorf("size = %d", list[0].Size)
+	}
+
+	runs, err := Runs(town)
+	if err != nil || len(runs) != 1 || runs[0] != "gt-wisp-abc" {
+		t.Errorf("Runs = %v, %v", runs, err)
+	}
+}
+
+func TestReadMissing(t *testing.T) {
+	_, err := Read(t.TempDir(), "gt-wisp-abc", "design", "design.md")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPathRejectsEscapes(t *testing.T) {
+	for _, parts := range [][3]string{
+		{"", "design", "a.md"},
+		{"gt-wisp-abc", "..", "a.md"},
+		{"gt-wisp-abc", "design", "../../etc/passwd"},
+		{"gt-wisp-abc", `a\b`, "a.md"},
+	} {
+		if _, err := Path("/town", parts[0], parts[1], parts[2]); err == nil {
+			t.Errorf("Path(%q) should fail", parts)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Package audit records every gt command that changes town state.
+//
+// Each command is appended as one JSON line to <town>/.audit.jsonl: who ran
+// it (OS user, agent address and verified role), the command and its
+// arguments, when, how long it took and whether it succeeded. The file is
+// only ever appended to. Read-only commands (status, list, show, ...) and
+// dry runs are not recorded. 'gt audit tail' and 'gt audit export' read
+// the log.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// File is the audit log's name under the town root.
+const File = ".audit.jsonl"
+
+// maxArgLen bounds a recorded argument; long mail bodies and descriptions
+// are cut, not stored whole.
+const maxArgLen = 500
+
+// Entry is one recorded command.
+type Entry struct {
+	Time       time.Time `json:"ts"`
+	User       string    `json:"user"`            // OS user who ran gt
+	Actor      string    `json:"actor"`           // Agent address, or "overseer"
+	Role       string    `json:"role"`            // Verified role (see internal/authz)
+	Command    string    `json:"command"`         // Command path without "gt", e.g. "rig add"
+	Args       []string  `json:"args,omitempty"`  // Full argument list after "gt"
+	ExitCode   int       `json:"exit_code"`       // 0 on success
+	Error      string    `json:"error,omitempty"` // Error message on failure
+	DurationMS int64     `json:"duration_ms"`
+}
+
+// OK reports whether the command succeeded.
+func (e *Entry) OK() bool {
+	return e.ExitCode == 0
+}
+
+// Path returns the audit log path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, File)
+}
+
+// Append adds an entry to the town's audit log. The line is written with a
+// single append so concurrent gt processes don't interleave.
+func Append(townRoot string, e Entry) error {
+	for i, arg := range e.Args {
+		if len(arg) > maxArgLen {
+			e.Args[i] = arg[:maxArgLen] + "…"
+		}
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(Path(townRoot), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644) //nolint:gosec // G302: shared by the town's users
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Filter selects entries from the log.
+type Filter struct {
+	Since      time.Time // Zero means no limit
+	Actor      string    // Substring of the actor or user
+	Role       string
+	Command    string // Command path prefix, e.g. "rig"
+	FailedOnly bool
+}
+
+// Match reports whether an entry passes the filter.
+func (f Filter) Match(e *Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if f.Actor != "" && !strings.Contains(e.Actor, f.Actor) && !strings.Contains(e.User, f.Actor) {
+		return false
+	}
+	if f.Role != "" && e.Role != f.Role {
+		return false
+	}
+	if f.Command != "" && e.Command != f.Command && !strings.HasPrefix(e.Command, f.Command+" ") {
+		return false
+	}
+	return !f.FailedOnly || !e.OK()
+}
+
+// Read returns the entries matching filter, oldest first. A town with no
+// log has no entries; malformed lines are skipped.
+func Read(townRoot string, filter Filter) ([]Entry, error) {
+	f, err := os.Open(Path(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if filter.Match(&e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// readOnlyCommands are top-level commands that never change town state.
+var readOnlyCommands = map[string]bool{
+	"activity": true, "aging": true, "audit": true, "bench": true, "completion": true,
+	"costs": true, "dashboard": true, "diffstat": true, "explain-config": true, "export": true,
+	"feed": true, "graph": true, "help": true, "info": true, "log": true, "logs": true,
+	"orphans": true, "peek": true, "permissions": true, "plan": true, "prime": true,
+	"ready": true, "retro": true, "role": true, "search": true, "seance": true, "snapshot": true,
+	"stale": true, "stats": true, "status": true, "statusline": true,
+	"thanks": true, "tutorial": true, "version": true, "whoami": true,
+	"session-host": true, // Runs for the life of a session
+	"heartbeat":    true, // Runs after every agent tool call
+	"top":          true, // Its actions run, and are audited as, gt subcommands
+}
+
+// readOnlySubcommands are subcommand names that only read, wherever they
+// appear ("rig list", "mail inbox", "mol status", ...).
+var readOnlySubcommands = map[string]bool{
+	"list": true, "show": true, "status": true, "tail": true, "inbox": true,
+	"read": true, "peek": true, "check": true, "git-state": true,
+	"check-recovery": true, "tools": true, "serve": true, "preview": true,
+	"diff": true, "history": true, "current": true, "runs": true, "templates": true,
+	"get": true,
+}
+
+// Mutates reports whether a command (path without "gt") can change state
+// and so belongs in the log. Doctor only changes things with --fix.
+func Mutates(path string, args []string) bool {
+	words := strings.Fields(path)
+	if len(words) == 0 {
+		return false
+	}
+	if words[0] == "doctor" {
+		for _, a := range args {
+			if a == "--fix" || strings.HasPrefix(a, "--fix=") {
+				return true
+			}
+		}
+		return false
+	}
+	if readOnlyCommands[words[0]] {
+		return false
+	}
+	return len(words) == 1 || !readOnlySubcommands[words[len(words)-1]]
+}
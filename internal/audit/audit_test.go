@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendRead(t *testing.T) {
+	town := t.TempDir()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []Entry{
+		{Time: base, User: "ana", Actor: "overseer", Role: "overseer", Command: "rig add", Args: []string{"rig", "add", "web"}},
+		{Time: base.Add(time.Minute), User: "ana", Actor: "web/polecats/Toast", Role: "polecat", Command: "rig remove", ExitCode: 1, Error: "restricted"},
+		{Time: base.Add(2 * time.Minute), User: "bo", Actor: "mayor", Role: "mayor", Command: "sling", Args: []string{"sling", strings.Repeat("x", 2000)}},
+	}
+	for _, e := range entries {
+		if err := Append(town, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := Read(town, Filter{})
+	if err != nil || len(all) != 3 {
+		t.Fatalf("Read = %d entries, %v", len(all), err)
+	}
+	if n := len(all[2].Args[1]); n > maxArgLen+len("…") {
+		t.Errorf("long arg stored at %d bytes", n)
+	}
+
+	// Appending never rewrites earlier lines
+	data, _ := os.ReadFile(Path(town))
+	if err := Append(town, Entry{Time: base.Add(3 * time.Minute), Command: "up"}); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.ReadFile(Path(town))
+	if !strings.HasPrefix(string(after), string(data)) {
+		t.Error("Append changed existing entries")
+	}
+
+	cases := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"rig commands", Filter{Command: "rig"}, 2},
+		{"failed", Filter{FailedOnly: true}, 1},
+		{"by OS user", Filter{Actor: "bo"}, 1},
+		{"by agent", Filter{Actor: "Toast"}, 1},
+		{"by role", Filter{Role: "overseer"}, 1},
+		{"since", Filter{Since: base.Add(90 * time.Second)}, 2},
+	}
+	for _, tc := range cases {
+		got, _ := Read(town, tc.filter)
+		if len(got) != tc.want {
+			t.Errorf("%s: got %d entries, want %d", tc.name, len(got), tc.want)
+		}
+	}
+}
+
+func TestMutates(t *testing.T) {
+	cases := []struct {
+		path string
+		args []string
+		want bool
+	}{
+		{"rig add", nil, true},
+		{"rig list", nil, false},
+		{"mail send", nil, true},
+		{"mail inbox", nil, false},
+		{"sling", nil, true},
+		{"status", nil, false},
+		{"statusline", nil, false},
+		{"doctor", []string{"doctor"}, false},
+		{"doctor", []string{"doctor", "--fix"}, true},
+		{"audit tail", nil, false},
+	}
+	for _, tc := range cases {
+		if got := Mutates(tc.path, tc.args); got != tc.want {
+			t.Errorf("Mutates(%q) = %v, want %v", tc.path, got, tc.want)
+		}
+	}
+}
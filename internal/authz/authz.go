@@ -0,0 +1,210 @@
+// Package authz decides which gt commands each role may run.
+//
+// Agents are spawned with a role token in GT_ROLE_TOKEN: their role, rig
+// and name, signed with a key kept in <town>/.runtime/role.key. gt trusts
+// the token over GT_ROLE, so an agent can't promote itself by changing
+// its environment. The key is readable by anything running as the town's
+// user, so this guards against agents wandering into commands that aren't
+// theirs, not against a hostile process.
+//
+// A Policy maps command paths ("rig remove") to the roles allowed to run
+// them. Commands not in the policy are open to everyone, and the overseer
+// (a human, outside any agent session) may run everything.
+package authz
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// EnvToken is the environment variable that carries an agent's role token.
+const EnvToken = "GT_ROLE_TOKEN"
+
+// Overseer is the role of a human running gt outside an agent session.
+const Overseer = "overseer"
+
+// Unverified is the role of a caller that looks like an agent session but
+// has no valid role token. Nothing proves which agent it is, so it gets
+// the least privileged role, a polecat's.
+const Unverified = constants.RolePolecat
+
+// Identity is who a role token was issued to.
+type Identity struct {
+	Role  string `json:"role"`
+	Rig   string `json:"rig,omitempty"`
+	Agent string `json:"agent,omitempty"`
+}
+
+// KeyPath returns the path of the town's token signing key.
+func KeyPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "role.key")
+}
+
+// loadKey reads the town's signing key, creating it if create is set and
+// the town exists.
+func loadKey(townRoot string, create bool) ([]byte, error) {
+	path := KeyPath(townRoot)
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) < 32 {
+			return nil, fmt.Errorf("%s is truncated", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) || !create {
+		return nil, err
+	}
+	if info, err := os.Stat(townRoot); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("town %s does not exist", townRoot)
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	// O_EXCL: if another spawn created the key first, use theirs
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return loadKey(townRoot, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(key); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return key, f.Close()
+}
+
+func mac(key, payload []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// Sign issues a role token for an agent. The token is deterministic, so a
+// session's expected environment can be recomputed (gt doctor does).
+func Sign(townRoot string, id Identity) (string, error) {
+	key, err := loadKey(townRoot, true)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(id)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(mac(key, payload)), nil
+}
+
+// ErrBadToken is returned for a token this town did not sign.
+var ErrBadToken = errors.New("role token was not issued by this town")
+
+// Verify checks a role token and returns who it was issued to.
+func Verify(townRoot, token string) (*Identity, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrBadToken
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(body)
+	if err != nil {
+		return nil, ErrBadToken
+	}
+	got, err := enc.DecodeString(sig)
+	if err != nil {
+		return nil, ErrBadToken
+	}
+	key, err := loadKey(townRoot, false)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+	if !hmac.Equal(got, mac(key, payload)) {
+		return nil, ErrBadToken
+	}
+	var id Identity
+	if err := json.Unmarshal(payload, &id); err != nil || id.Role == "" {
+		return nil, ErrBadToken
+	}
+	return &id, nil
+}
+
+// Policy maps command paths, without the leading "gt", to the roles that
+// may run them. A rule for "rig" covers every rig subcommand unless a
+// longer path has its own rule. "*" allows every role.
+type Policy map[string][]string
+
+// DefaultPolicy keeps town-shaping commands away from workers. An empty
+// role list means only the overseer.
+var DefaultPolicy = Policy{
+	"install":        {},
+	"uninstall":      {},
+	"git-init":       {"mayor"},
+	"config":         {"mayor"},
+	"harness":        {"mayor"},
+	"models key":     {},
+	"town peer":      {"mayor"},
+	"rig add":        {"mayor"},
+	"rig remove":     {"mayor"},
+	"rig reset":      {"mayor", "deacon"},
+	"rig dock":       {"mayor", "deacon"},
+	"rig undock":     {"mayor", "deacon"},
+	"rig park":       {"mayor", "deacon"},
+	"rig unpark":     {"mayor", "deacon"},
+	"rig config":     {"mayor", "deacon"},
+	"crew add":       {"mayor", "crew"},
+	"crew remove":    {"mayor", "crew"},
+	"polecat nuke":   {"mayor", "deacon", "witness"},
+	"polecat remove": {"mayor", "deacon", "witness"},
+}
+
+// NewPolicy returns the default policy with overrides applied. An
+// override replaces the rule for its path; naming "*" opens the command
+// to everyone.
+func NewPolicy(overrides map[string][]string) Policy {
+	p := make(Policy, len(DefaultPolicy)+len(overrides))
+	for path, roles := range DefaultPolicy {
+		p[path] = roles
+	}
+	for path, roles := range overrides {
+		p[strings.Join(strings.Fields(path), " ")] = roles
+	}
+	return p
+}
+
+// Rule returns the rule that governs a command path: the policy entry for
+// the longest matching prefix. ok is false if the command is unrestricted.
+func (p Policy) Rule(path string) (match string, roles []string, ok bool) {
+	words := strings.Fields(path)
+	for n := len(words); n > 0; n-- {
+		prefix := strings.Join(words[:n], " ")
+		if roles, ok := p[prefix]; ok {
+			return prefix, roles, true
+		}
+	}
+	return "", nil, false
+}
+
+// Allows reports whether role may run the command at path.
+func (p Policy) Allows(role, path string) bool {
+	if role == Overseer {
+		return true
+	}
+	_, roles, ok := p.Rule(path)
+	return !ok || slices.Contains(roles, "*") || slices.Contains(roles, role)
+}
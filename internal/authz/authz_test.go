@@ -0,0 +1,83 @@
+package authz
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	town := t.TempDir()
+	id := Identity{Role: "polecat", Rig: "greenplace", Agent: "Toast"}
+
+	token, err := Sign(town, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := Sign(town, id); again != token {
+		t.Error("tokens should be deterministic")
+	}
+	info, err := os.Stat(KeyPath(town))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("signing key = %v, %v; want mode 0600", info, err)
+	}
+
+	got, err := Verify(town, token)
+	if err != nil || *got != id {
+		t.Fatalf("Verify = %+v, %v", got, err)
+	}
+
+	// A polecat that rewrites its token's role breaks the signature
+	body, sig, _ := strings.Cut(token, ".")
+	mayor, _ := Sign(town, Identity{Role: "mayor"})
+	mayorBody, _, _ := strings.Cut(mayor, ".")
+	if _, err := Verify(town, mayorBody+"."+sig); err != ErrBadToken {
+		t.Errorf("forged token = %v, want ErrBadToken", err)
+	}
+	if _, err := Verify(town, body); err != ErrBadToken {
+		t.Errorf("unsigned token = %v, want ErrBadToken", err)
+	}
+
+	// Another town's token is not valid here
+	if _, err := Verify(t.TempDir(), token); err == nil {
+		t.Error("token from another town should not verify")
+	}
+
+	if _, err := Sign("/nonexistent/town", id); err == nil {
+		t.Error("signing for a missing town should fail, not create it")
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	p := NewPolicy(map[string][]string{
+		"rig add":       {"mayor", "crew"},
+		"mail  send":    {"mayor"},
+		"polecat  nuke": {"*"},
+	})
+
+	cases := []struct {
+		role, path string
+		want       bool
+	}{
+		{"polecat", "rig remove", false},
+		{"mayor", "rig remove", true},
+		{Overseer, "rig remove", true},
+		{"polecat", "rig status", true},
+		{"polecat", "config agent set", false},
+		{"mayor", "config agent set", true},
+		{"deacon", "uninstall", false},
+		{"crew", "rig add", true},
+		{"polecat", "mail send", false},
+		{"polecat", "polecat nuke", true},
+		{"polecat", "hook", true},
+	}
+	for _, tc := range cases {
+		if got := p.Allows(tc.role, tc.path); got != tc.want {
+			t.Errorf("Allows(%s, %q) = %v, want %v", tc.role, tc.path, got, tc.want)
+		}
+	}
+
+	if match, _, _ := p.Rule("config agent set claude"); match != "config" {
+		t.Errorf("Rule matched %q, want config", match)
+	}
+}
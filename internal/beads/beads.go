@@ -10,18 +10,44 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"github.com/steveyegge/gastown/internal/transport"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Common errors
 // ZFC: Only define errors that don't require stderr parsing for decisions.
 // ErrNotARepo and ErrSyncConflict were removed - agents should handle these directly.
 var (
-	ErrNotInstalled = errors.New("bd not installed: run 'pip install beads-cli' or see https://github.com/anthropics/beads")
+	ErrNotInstalled = errors.New("bd not installed: run '" + InstallCommand + "'")
 	ErrNotFound     = errors.New("issue not found")
 )
 
+// InstallCommand installs bd.
+const InstallCommand = "go install github.com/steveyegge/beads/cmd/bd@latest"
+
+var (
+	installedOnce sync.Once
+	installed     bool
+)
+
+// Installed reports whether bd is on PATH. It is checked once per process.
+func Installed() bool {
+	installedOnce.Do(func() {
+		_, err := exec.LookPath("bd")
+		installed = err == nil
+	})
+	return installed
+}
+
 // Issue represents a beads issue.
 type Issue struct {
 	ID          string   `json:"id"`
@@ -41,6 +67,7 @@ type Issue struct {
 	Blocks      []string `json:"blocks,omitempty"`
 	BlockedBy   []string `json:"blocked_by,omitempty"`
 	Labels      []string `json:"labels,omitempty"`
+	ExternalRef string   `json:"external_ref,omitempty"`
 
 	// Agent bead slots (type=agent only)
 	HookBead   string `json:"hook_bead,omitempty"`   // Current work attached to agent's hook
@@ -85,8 +112,10 @@ type CreateOptions struct {
 	Priority    int    // 0-4
 	Description string
 	Parent      string
-	Actor       string // Who is creating this issue (populates created_by)
-	Ephemeral   bool   // Create as ephemeral (wisp) - not exported to JSONL
+	Actor       string   // Who is creating this issue (populates created_by)
+	Ephemeral   bool     // Create as ephemeral (wisp) - not exported to JSONL
+	Labels      []string // Additional labels (e.g., "gh:issue")
+	ExternalRef string   // Link to an external tracker (e.g., "gh-42")
 }
 
 // UpdateOptions specifies options for updating an issue.
@@ -131,8 +160,7 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	// Use --no-daemon for faster read operations (avoids daemon IPC overhead)
 	// The daemon is primarily useful for write coalescing, not reads
 	fullArgs := append([]string{"--no-daemon"}, args...)
-	cmd := exec.Command("bd", fullArgs...) //nolint:gosec // G204: bd is a trusted internal tool
-	cmd.Dir = b.workDir
+	output.Tracef("bd %s", strings.Join(fullArgs, " "))
 
 	// Always explicitly set BEADS_DIR to prevent inherited env vars from
 	// causing prefix mismatches. Use explicit beadsDir if set, otherwise
@@ -141,16 +169,35 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	if beadsDir == "" {
 		beadsDir = ResolveBeadsDir(b.workDir)
 	}
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
+	// In a remote rig, bd runs on the rig's machine
+	cmd := transport.For(b.workDir).Command(b.workDir, []string{"BEADS_DIR=" + beadsDir}, "bd", fullArgs...)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	write := mutates(args)
+	if write && dryrun.Command(cmd) {
+		return dryRunOutput(args), nil
+	}
+	if write {
+		defer invalidateCache(beadsDir)
+	} else if out, ok := cachedOutput(beadsDir, args); ok {
+		output.Tracef("bd %s (cached)", strings.Join(fullArgs, " "))
+		return out, nil
+	}
+
+	span := telemetry.Leaf("bd "+subcommand(args), attribute.String("bd.args", strings.Join(args, " ")))
+	start := time.Now()
 	err := cmd.Run()
+	metrics.BDCallDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
-		return nil, b.wrapError(err, stderr.String(), args)
+		metrics.BDCallErrors.Inc()
+		err = b.wrapError(err, stderr.String(), args)
+		span.End(err)
+		return nil, err
 	}
+	span.End(nil)
 
 	// Handle bd --no-daemon exit code 0 bug: when issue not found,
 	// --no-daemon exits 0 but writes error to stderr with empty stdout.
@@ -159,9 +206,36 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 		return nil, b.wrapError(fmt.Errorf("command produced no output"), stderr.String(), args)
 	}
 
+	if !write {
+		storeOutput(beadsDir, args, stdout.Bytes(), start)
+	}
 	return stdout.Bytes(), nil
 }
 
+// subcommand returns the first non-flag argument, used to name spans.
+func subcommand(args []string) string {
+	for _, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			return a
+		}
+	}
+	return ""
+}
+
+// currentIDs maps IDs under renamed prefixes to their current IDs (see
+// CurrentID), using the routes of the town b works in.
+func (b *Beads) currentIDs(ids ...string) []string {
+	townRoot, err := workspace.Find(b.workDir)
+	if err != nil || townRoot == "" {
+		return ids
+	}
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = CurrentID(townRoot, id)
+	}
+	return out
+}
+
 // Run executes a bd command and returns stdout.
 // This is a public wrapper around the internal run method for cases where
 // callers need to run arbitrary bd commands.
@@ -309,7 +383,7 @@ func (b *Beads) ReadyWithType(issueType string) ([]*Issue, error) {
 
 // Show returns detailed information about an issue.
 func (b *Beads) Show(id string) (*Issue, error) {
-	out, err := b.run("show", id, "--json")
+	out, err := b.run("show", b.currentIDs(id)[0], "--json")
 	if err != nil {
 		return nil, err
 	}
@@ -335,7 +409,8 @@ func (b *Beads) ShowMultiple(ids []string) (map[string]*Issue, error) {
 	}
 
 	// bd show supports multiple IDs
-	args := append([]string{"show", "--json"}, ids...)
+	current := b.currentIDs(ids...)
+	args := append([]string{"show", "--json"}, current...)
 	out, err := b.run(args...)
 	if err != nil {
 		// If bd fails, return empty map (some IDs might not exist)
@@ -351,6 +426,12 @@ func (b *Beads) ShowMultiple(ids []string) (map[string]*Issue, error) {
 	for _, issue := range issues {
 		result[issue.ID] = issue
 	}
+	// Callers look issues up by the IDs they asked for
+	for i, id := range ids {
+		if issue, ok := result[current[i]]; ok && id != current[i] {
+			result[id] = issue
+		}
+	}
 
 	return result, nil
 }
@@ -380,8 +461,15 @@ func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
 		args = append(args, "--title="+opts.Title)
 	}
 	// Type is deprecated: convert to gt:<type> label
+	labels := opts.Labels
 	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
+		labels = append([]string{"gt:" + opts.Type}, labels...)
+	}
+	if len(labels) > 0 {
+		args = append(args, "--labels="+strings.Join(labels, ","))
+	}
+	if opts.ExternalRef != "" {
+		args = append(args, "--external-ref="+opts.ExternalRef)
 	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
@@ -463,7 +551,7 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 
 // Update updates an existing issue.
 func (b *Beads) Update(id string, opts UpdateOptions) error {
-	args := []string{"update", id}
+	args := []string{"update", b.currentIDs(id)[0]}
 
 	if opts.Title != nil {
 		args = append(args, "--title="+*opts.Title)
@@ -506,7 +594,7 @@ func (b *Beads) Close(ids ...string) error {
 		return nil
 	}
 
-	args := append([]string{"close"}, ids...)
+	args := append([]string{"close"}, b.currentIDs(ids...)...)
 
 	// Pass session ID for work attribution if available
 	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
@@ -525,7 +613,7 @@ func (b *Beads) CloseWithReason(reason string, ids ...string) error {
 		return nil
 	}
 
-	args := append([]string{"close"}, ids...)
+	args := append([]string{"close"}, b.currentIDs(ids...)...)
 	args = append(args, "--reason="+reason)
 
 	// Pass session ID for work attribution if available
@@ -558,6 +646,32 @@ func (b *Beads) ReleaseWithReason(id, reason string) error {
 	return err
 }
 
+// Comment adds a comment to an issue.
+func (b *Beads) Comment(id, text string) error {
+	_, err := b.run("comment", id, text)
+	return err
+}
+
+// IssueComment is a comment on an issue.
+type IssueComment struct {
+	Author    string `json:"author"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Comments returns an issue's comments, oldest first.
+func (b *Beads) Comments(id string) ([]IssueComment, error) {
+	out, err := b.run("comments", id, "--json")
+	if err != nil {
+		return nil, err
+	}
+	var comments []IssueComment
+	if err := json.Unmarshal(out, &comments); err != nil {
+		return nil, fmt.Errorf("parsing bd comments output: %w", err)
+	}
+	return comments, nil
+}
+
 // AddDependency adds a dependency: issue depends on dependsOn.
 func (b *Beads) AddDependency(issue, dependsOn string) error {
 	_, err := b.run("dep", "add", issue, dependsOn)
@@ -680,12 +794,12 @@ func ProvisionPrimeMD(beadsDir string) error {
 	}
 
 	// Create .beads directory if it doesn't exist
-	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(beadsDir, 0755); err != nil {
 		return fmt.Errorf("creating beads dir: %w", err)
 	}
 
 	// Write PRIME.md
-	if err := os.WriteFile(primePath, []byte(primeContent), 0644); err != nil {
+	if err := dryrun.WriteFile(primePath, []byte(primeContent), 0644); err != nil {
 		return fmt.Errorf("writing PRIME.md: %w", err)
 	}
 
@@ -28,6 +28,7 @@ type EscalationFields struct {
 	ReescalationCount  int    // Number of times this has been re-escalated
 	LastReescalatedAt  string // When last re-escalated (empty if never)
 	LastReescalatedBy  string // Who last re-escalated (empty if never)
+	Context            string // Multi-line situation report (hooked work, step, what was tried)
 }
 
 // EscalationState constants for bead status tracking.
@@ -104,6 +105,14 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 		lines = append(lines, "last_reescalated_by: null")
 	}
 
+	// Context goes last, indented, so its lines are never read as fields
+	if fields.Context != "" {
+		lines = append(lines, "context:")
+		for _, l := range strings.Split(strings.TrimRight(fields.Context, "\n"), "\n") {
+			lines = append(lines, "  "+l)
+		}
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -111,7 +120,13 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 func ParseEscalationFields(description string) *EscalationFields {
 	fields := &EscalationFields{}
 
+	var context []string
+	inContext := false
 	for _, line := range strings.Split(description, "\n") {
+		if inContext {
+			context = append(context, strings.TrimPrefix(line, "  "))
+			continue
+		}
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
@@ -159,8 +174,11 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "context":
+			inContext = value == ""
 		}
 	}
+	fields.Context = strings.Join(context, "\n")
 
 	return fields
 }
@@ -0,0 +1,29 @@
+package beads
+
+import "testing"
+
+func TestEscalationFieldsRoundTrip(t *testing.T) {
+	in := &EscalationFields{
+		Severity:    "high",
+		Reason:      "Need API credentials",
+		EscalatedBy: "gastown/Toast",
+		EscalatedAt: "2026-01-02T03:04:05Z",
+		Context:     "Hooked: gt-abc12\nTried: reading .env.example\nseverity: not a field",
+	}
+	desc := FormatEscalationDescription("Blocked on credentials", in)
+	out := ParseEscalationFields(desc)
+
+	if out.Severity != "high" || out.Reason != in.Reason || out.EscalatedBy != in.EscalatedBy {
+		t.Errorf("fields not preserved: %+v", out)
+	}
+	if out.Context != in.Context {
+		t.Errorf("Context = %q, want %q", out.Context, in.Context)
+	}
+}
+
+func TestParseEscalationFields_NoContext(t *testing.T) {
+	desc := FormatEscalationDescription("Title", &EscalationFields{Severity: "low"})
+	if got := ParseEscalationFields(desc).Context; got != "" {
+		t.Errorf("Context = %q, want empty", got)
+	}
+}
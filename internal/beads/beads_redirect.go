@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/dryrun"
 )
 
 // ResolveBeadsDir returns the actual beads directory, following any redirect.
@@ -53,7 +55,7 @@ func ResolveBeadsDir(workDir string) string {
 	if resolved == beadsDir {
 		fmt.Fprintf(os.Stderr, "Warning: circular redirect detected in %s (points to itself), ignoring redirect\n", redirectPath)
 		// Remove the errant redirect file to prevent future warnings
-		if err := os.Remove(redirectPath); err != nil {
+		if err := dryrun.Remove(redirectPath); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not remove errant redirect file: %v\n", err)
 		}
 		return beadsDir
@@ -136,7 +138,7 @@ func cleanBeadsRuntimeFiles(beadsDir string) error {
 			continue
 		}
 		for _, match := range matches {
-			if err := os.RemoveAll(match); err != nil && firstErr == nil {
+			if err := dryrun.RemoveAll(match); err != nil && firstErr == nil {
 				firstErr = err
 			}
 		}
@@ -201,7 +203,7 @@ func SetupRedirect(townRoot, worktreePath string) error {
 	}
 
 	// Create .beads directory if it doesn't exist
-	if err := os.MkdirAll(worktreeBeadsDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(worktreeBeadsDir, 0755); err != nil {
 		return fmt.Errorf("creating .beads dir: %w", err)
 	}
 
@@ -234,7 +236,7 @@ func SetupRedirect(townRoot, worktreePath string) error {
 
 	// Create redirect file
 	redirectFile := filepath.Join(worktreeBeadsDir, "redirect")
-	if err := os.WriteFile(redirectFile, []byte(redirectPath+"\n"), 0644); err != nil {
+	if err := dryrun.WriteFile(redirectFile, []byte(redirectPath+"\n"), 0644); err != nil {
 		return fmt.Errorf("creating redirect file: %w", err)
 	}
 
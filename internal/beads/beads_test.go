@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestNew verifies the constructor.
@@ -902,6 +903,33 @@ func TestAttachmentFieldsRoundTrip(t *testing.T) {
 	}
 }
 
+// TestSetPRFields tests PR field replacement on a work bead.
+func TestSetPRFields(t *testing.T) {
+	issue := &Issue{Description: "Fix the login flow.\n\npr_url: https://github.com/o/r/pull/1\npr_state: open"}
+
+	updated := SetPRFields(issue, &PRFields{
+		PRURL:    "https://github.com/o/r/pull/1",
+		PRState:  "merged",
+		PRChecks: "passing",
+	})
+
+	if !strings.HasPrefix(updated, "Fix the login flow.") {
+		t.Errorf("prose not preserved at start: %q", updated)
+	}
+	if strings.Count(updated, "pr_url:") != 1 || strings.Contains(updated, "pr_state: open") {
+		t.Errorf("old PR fields not replaced: %q", updated)
+	}
+
+	parsed := ParsePRFields(&Issue{Description: updated})
+	if parsed == nil || parsed.PRState != "merged" || parsed.PRChecks != "passing" {
+		t.Errorf("ParsePRFields = %+v", parsed)
+	}
+
+	if ParsePRFields(&Issue{Description: "no pr here"}) != nil {
+		t.Error("expected nil for issue without pr_url")
+	}
+}
+
 // TestResolveBeadsDir tests the redirect following logic.
 func TestResolveBeadsDir(t *testing.T) {
 	// Create temp directory structure
@@ -1799,3 +1827,98 @@ func TestSetupRedirect(t *testing.T) {
 		}
 	})
 }
+
+func TestMutates(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"show", "gt-abc", "--json"}, false},
+		{[]string{"--no-daemon", "list", "--status=open", "--json"}, false},
+		{[]string{"mol", "show", "gt-m"}, false},
+		{[]string{"config", "get", "types.custom"}, false},
+		{[]string{"config", "set", "types.custom", "agent"}, true},
+		{[]string{"create", "--json", "--title=Fix it"}, true},
+		{[]string{"update", "gt-abc", "--status=hooked"}, true},
+		{[]string{"close", "gt-abc", "--dry-run"}, false},
+		{[]string{"sync"}, true},
+		{[]string{"comments", "gt-abc", "--json"}, false},
+		{[]string{"comments", "add", "gt-abc", "LGTM"}, true},
+	}
+	for _, tt := range tests {
+		if got := mutates(tt.args); got != tt.want {
+			t.Errorf("mutates(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestRunCache(t *testing.T) {
+	tmp := t.TempDir()
+	binDir := filepath.Join(tmp, "bin")
+	beadsDir := filepath.Join(tmp, ".beads")
+	calls := filepath.Join(tmp, "calls")
+	for _, d := range []string{binDir, beadsDir} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	script := "#!/bin/sh\necho \"$*\" >> " + calls + "\necho '[]'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(tmp, "cache"))
+	SetCacheTTL(time.Minute)
+	t.Cleanup(func() { SetCacheTTL(0) })
+
+	count := func() int {
+		data, _ := os.ReadFile(calls)
+		return strings.Count(string(data), "\n")
+	}
+	b := NewWithBeadsDir(tmp, beadsDir)
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := b.Run(args...); err != nil {
+			t.Fatalf("bd %v: %v", args, err)
+		}
+	}
+
+	// Repeated reads hit bd once
+	run("list", "--json")
+	run("list", "--json")
+	if n := count(); n != 1 {
+		t.Errorf("after two identical reads bd ran %d times, want 1", n)
+	}
+	// Different reads are cached separately
+	run("show", "gt-abc", "--json")
+	if n := count(); n != 2 {
+		t.Errorf("bd ran %d times, want 2", n)
+	}
+
+	// A write through the wrapper drops the cache
+	run("update", "gt-abc", "--status=open")
+	run("list", "--json")
+	if n := count(); n != 4 {
+		t.Errorf("after a write bd ran %d times, want 4", n)
+	}
+
+	// So does a change to the database made outside it
+	future := time.Now().Add(time.Hour)
+	if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Join(beadsDir, "issues.jsonl"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	run("list", "--json")
+	if n := count(); n != 5 {
+		t.Errorf("after an outside change bd ran %d times, want 5", n)
+	}
+
+	// And with caching off every read runs bd
+	SetCacheTTL(0)
+	run("show", "gt-abc", "--json")
+	if n := count(); n != 6 {
+		t.Errorf("with caching off bd ran %d times, want 6", n)
+	}
+}
@@ -0,0 +1,122 @@
+package beads
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultCacheTTL is how long a cached bd read stays fresh for commands
+// that turn caching on.
+const DefaultCacheTTL = 5 * time.Second
+
+// cacheTTL is how long read-only bd output is reused. Zero, the default,
+// turns caching off; commands that poll (gt status, gt dashboard) turn it
+// on with SetCacheTTL.
+var cacheTTL time.Duration
+
+// SetCacheTTL turns on caching of read-only bd output for ttl, across gt
+// processes, or turns it off with 0.
+//
+// A cached result is dropped when it is older than ttl, when a write goes
+// through this wrapper to the same database, or when anything in the
+// beads directory has changed since it was cached (bd run directly by an
+// agent, or sync). Only rigs on other machines rely on the TTL alone.
+func SetCacheTTL(ttl time.Duration) {
+	cacheTTL = ttl
+}
+
+// cacheEntry is a cached bd result on disk.
+type cacheEntry struct {
+	Args   []string  `json:"args"`
+	At     time.Time `json:"at"`
+	Output []byte    `json:"output"`
+}
+
+// cacheDir is where results for one beads database are cached, so a write
+// can drop them all.
+func cacheDir(beadsDir string) string {
+	abs, err := filepath.Abs(beadsDir)
+	if err != nil {
+		abs = beadsDir
+	}
+	return filepath.Join(state.CacheDir(), "bd", hashKey(abs))
+}
+
+func cachePath(beadsDir string, args []string) string {
+	return filepath.Join(cacheDir(beadsDir), hashKey(strings.Join(args, "\x00"))+".json")
+}
+
+func hashKey(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+// cachedOutput returns the cached output of a read, if it is fresh.
+func cachedOutput(beadsDir string, args []string) ([]byte, bool) {
+	if cacheTTL <= 0 {
+		return nil, false
+	}
+	data, err := os.ReadFile(cachePath(beadsDir, args)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if json.Unmarshal(data, &entry) != nil || strings.Join(entry.Args, "\x00") != strings.Join(args, "\x00") {
+		return nil, false
+	}
+	if time.Since(entry.At) > cacheTTL || changedSince(beadsDir, entry.At) {
+		return nil, false
+	}
+	return entry.Output, true
+}
+
+// storeOutput caches the output of a read that started at start, so a
+// write made while it ran still invalidates it. Caching is best effort.
+func storeOutput(beadsDir string, args []string, output []byte, start time.Time) {
+	if cacheTTL <= 0 {
+		return
+	}
+	path := cachePath(beadsDir, args)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = util.AtomicWriteJSON(path, cacheEntry{Args: args, At: start, Output: output})
+}
+
+// invalidateCache drops every cached read of a beads database. It runs on
+// each write through the wrapper, whether or not caching is on in this
+// process, so other processes don't serve stale reads.
+func invalidateCache(beadsDir string) {
+	_ = os.RemoveAll(cacheDir(beadsDir))
+}
+
+// changedSince reports whether the beads directory or any file directly in
+// it (the database, its journal, the JSONL export) was modified after t.
+// A directory that can't be read (a remote rig) counts as unchanged.
+func changedSince(beadsDir string, t time.Time) bool {
+	info, err := os.Stat(beadsDir)
+	if err != nil {
+		return false
+	}
+	if info.ModTime().After(t) {
+		return true
+	}
+	entries, err := os.ReadDir(beadsDir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && info.ModTime().After(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package beads
+
+import "strings"
+
+// readOnlyCommands never change the beads database and run even in dry-run
+// mode. Keys are "<subcommand>" or "<subcommand> <action>".
+var readOnlyCommands = map[string]bool{
+	"blocked":       true,
+	"count":         true,
+	"info":          true,
+	"list":          true,
+	"prime":         true,
+	"ready":         true,
+	"search":        true,
+	"show":          true,
+	"stats":         true,
+	"status":        true,
+	"version":       true,
+	"where":         true,
+	"config get":    true,
+	"config list":   true,
+	"daemon list":   true,
+	"daemon health": true,
+	"dep list":      true,
+	"dep tree":      true,
+	"label list":    true,
+	"mol current":   true,
+	"mol list":      true,
+	"mol progress":  true,
+	"mol show":      true,
+	"slot show":     true,
+	"formula list":  true,
+	"formula show":  true,
+}
+
+// mutates reports whether a bd invocation changes state, so that dry-run
+// mode records it instead of running it. Calls that pass bd's own
+// --dry-run are safe to run.
+func mutates(args []string) bool {
+	var words []string
+	for _, a := range args {
+		if a == "--dry-run" {
+			return false
+		}
+		if !strings.HasPrefix(a, "-") {
+			words = append(words, a)
+		}
+	}
+	if len(words) == 0 {
+		return false
+	}
+	if readOnlyCommands[words[0]] {
+		return false
+	}
+	if words[0] == "comments" {
+		// "bd comments <id>" lists; "bd comments add" writes
+		return len(words) > 1 && words[1] == "add"
+	}
+	return len(words) < 2 || !readOnlyCommands[words[0]+" "+words[1]]
+}
+
+// dryRunOutput stands in for the output of a skipped bd call. JSON callers
+// get an empty object, so a recorded "bd create --json" yields an issue
+// with no ID rather than a parse error.
+func dryRunOutput(args []string) []byte {
+	for _, a := range args {
+		if a == "--json" || a == "--format=json" {
+			return []byte("{}")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package beads
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SizePoints maps t-shirt sizes to story points.
+var SizePoints = map[string]float64{"XS": 1, "S": 2, "M": 3, "L": 5, "XL": 8}
+
+// EstimateFields holds a work bead's size and estimate, stored as "size:"
+// and "estimate:" description lines.
+type EstimateFields struct {
+	Size     string  // XS, S, M, L or XL
+	Estimate float64 // Points; overrides the size's points when set
+}
+
+// estimateFieldKeys are the description keys owned by EstimateFields.
+var estimateFieldKeys = map[string]bool{
+	"size":     true,
+	"estimate": true,
+}
+
+// Points returns the estimate, else the size's points, else 0.
+func (f *EstimateFields) Points() float64 {
+	if f == nil {
+		return 0
+	}
+	if f.Estimate > 0 {
+		return f.Estimate
+	}
+	return SizePoints[f.Size]
+}
+
+// ParseSize normalizes a t-shirt size ("m" → "M").
+func ParseSize(s string) (string, error) {
+	size := strings.ToUpper(strings.TrimSpace(s))
+	if _, ok := SizePoints[size]; !ok {
+		return "", fmt.Errorf("invalid size %q (want XS, S, M, L or XL)", s)
+	}
+	return size, nil
+}
+
+// ParseEstimateFields extracts size and estimate from an issue's
+// description. Returns nil if the issue has neither.
+func ParseEstimateFields(issue *Issue) *EstimateFields {
+	if issue == nil || issue.Description == "" {
+		return nil
+	}
+
+	fields := &EstimateFields{}
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "size":
+			if size, err := ParseSize(value); err == nil {
+				fields.Size = size
+			}
+		case "estimate":
+			if n, err := strconv.ParseFloat(value, 64); err == nil && n > 0 {
+				fields.Estimate = n
+			}
+		}
+	}
+
+	if fields.Size == "" && fields.Estimate == 0 {
+		return nil
+	}
+	return fields
+}
+
+// FormatEstimateFields formats EstimateFields as description lines. Only
+// non-empty fields are included.
+func FormatEstimateFields(fields *EstimateFields) string {
+	if fields == nil {
+		return ""
+	}
+
+	var lines []string
+	if fields.Size != "" {
+		lines = append(lines, "size: "+fields.Size)
+	}
+	if fields.Estimate > 0 {
+		lines = append(lines, "estimate: "+strconv.FormatFloat(fields.Estimate, 'f', -1, 64))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetEstimateFields updates an issue's description with the given size and
+// estimate. Existing lines for them are replaced and other content is
+// preserved, with the fields appended as for PR fields.
+// Returns the new description string.
+func SetEstimateFields(issue *Issue, fields *EstimateFields) string {
+	var otherLines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			key, _, ok := strings.Cut(strings.TrimSpace(line), ":")
+			if ok && estimateFieldKeys[strings.ToLower(strings.TrimSpace(key))] {
+				continue
+			}
+			otherLines = append(otherLines, line)
+		}
+	}
+
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+
+	formatted := FormatEstimateFields(fields)
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	if len(otherLines) == 0 {
+		return formatted
+	}
+	return strings.Join(otherLines, "\n") + "\n\n" + formatted
+}
+
+// SetEstimate records a bead's size and estimate, replacing any earlier
+// ones.
+func (b *Beads) SetEstimate(id string, fields *EstimateFields) error {
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	description := SetEstimateFields(issue, fields)
+	return b.Update(id, UpdateOptions{Description: &description})
+}
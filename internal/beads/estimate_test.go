@@ -0,0 +1,30 @@
+package beads
+
+import "testing"
+
+func TestEstimateFields(t *testing.T) {
+	issue := &Issue{Description: "Fix the login flow.\n\nsize: m\n"}
+	fields := ParseEstimateFields(issue)
+	if fields == nil || fields.Size != "M" || fields.Points() != 3 {
+		t.Fatalf("ParseEstimateFields = %+v", fields)
+	}
+
+	issue.Description = SetEstimateFields(issue, &EstimateFields{Size: "L", Estimate: 6.5})
+	want := "Fix the login flow.\n\nsize: L\nestimate: 6.5"
+	if issue.Description != want {
+		t.Errorf("SetEstimateFields = %q, want %q", issue.Description, want)
+	}
+	if got := ParseEstimateFields(issue).Points(); got != 6.5 {
+		t.Errorf("Points = %v, want the explicit estimate 6.5", got)
+	}
+
+	if ParseEstimateFields(&Issue{Description: "size: huge"}) != nil {
+		t.Error("invalid size parsed")
+	}
+	if got := (*EstimateFields)(nil).Points(); got != 0 {
+		t.Errorf("nil Points = %v", got)
+	}
+	if _, err := ParseSize("XXL"); err == nil {
+		t.Error("ParseSize(XXL) succeeded")
+	}
+}
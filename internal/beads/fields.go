@@ -421,6 +421,118 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 	return formatted + "\n\n" + strings.Join(otherLines, "\n")
 }
 
+// PRFields holds the GitHub pull request tracking fields for a work bead.
+// Set when a rig submits work as a pull request instead of through the
+// local merge queue (merge_queue.strategy = "github-pr").
+type PRFields struct {
+	PRURL    string // Pull request URL
+	PRBranch string // Head branch of the PR
+	PRState  string // open, merged, closed
+	PRReview string // GitHub review decision: approved, changes_requested, review_required
+	PRChecks string // Aggregate CI status: passing, failing, pending
+}
+
+// prFieldKeys are the description keys owned by PRFields.
+var prFieldKeys = map[string]bool{
+	"pr_url":    true,
+	"pr_branch": true,
+	"pr_state":  true,
+	"pr_review": true,
+	"pr_checks": true,
+}
+
+// ParsePRFields extracts pull request fields from an issue's description.
+// Returns nil if the issue isn't tracking a pull request.
+func ParsePRFields(issue *Issue) *PRFields {
+	if issue == nil || issue.Description == "" {
+		return nil
+	}
+
+	fields := &PRFields{}
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "pr_url":
+			fields.PRURL = value
+		case "pr_branch":
+			fields.PRBranch = value
+		case "pr_state":
+			fields.PRState = value
+		case "pr_review":
+			fields.PRReview = value
+		case "pr_checks":
+			fields.PRChecks = value
+		}
+	}
+
+	if fields.PRURL == "" {
+		return nil
+	}
+	return fields
+}
+
+// FormatPRFields formats PRFields as description lines. Only non-empty
+// fields are included.
+func FormatPRFields(fields *PRFields) string {
+	if fields == nil {
+		return ""
+	}
+
+	var lines []string
+	if fields.PRURL != "" {
+		lines = append(lines, "pr_url: "+fields.PRURL)
+	}
+	if fields.PRBranch != "" {
+		lines = append(lines, "pr_branch: "+fields.PRBranch)
+	}
+	if fields.PRState != "" {
+		lines = append(lines, "pr_state: "+fields.PRState)
+	}
+	if fields.PRReview != "" {
+		lines = append(lines, "pr_review: "+fields.PRReview)
+	}
+	if fields.PRChecks != "" {
+		lines = append(lines, "pr_checks: "+fields.PRChecks)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetPRFields updates an issue's description with the given PR fields.
+// Existing PR field lines are replaced; other content is preserved.
+// Unlike MR beads, work beads are mostly prose, so the fields are appended
+// after the existing content rather than placed first.
+// Returns the new description string.
+func SetPRFields(issue *Issue, fields *PRFields) string {
+	var otherLines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			key, _, ok := strings.Cut(strings.TrimSpace(line), ":")
+			if ok && prFieldKeys[strings.ToLower(strings.TrimSpace(key))] {
+				continue // Skip PR field lines - they'll be replaced
+			}
+			otherLines = append(otherLines, line)
+		}
+	}
+
+	// Trim trailing blank lines from other content
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+
+	formatted := FormatPRFields(fields)
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	if len(otherLines) == 0 {
+		return formatted
+	}
+	return strings.Join(otherLines, "\n") + "\n\n" + formatted
+}
+
 // SynthesisFields holds structured fields for synthesis beads.
 // These fields track the synthesis step in a convoy workflow.
 type SynthesisFields struct {
@@ -18,8 +18,22 @@ type MoleculeStep struct {
 	Tier         string         // Optional tier hint: haiku, sonnet, opus
 	Type         string         // Step type: "task" (default), "wait", etc.
 	Backoff      *BackoffConfig // Backoff configuration for wait-type steps
+	Gate         string         // Approval gate: GateHuman pauses until 'gt approve'
+	Lint         []string       // Linters to run before the step starts (LintRig: the rig's)
+	Inputs       []string       // Earlier steps' artifacts (step/name) attached when the step starts
+	Outputs      []string       // Artifacts the step must store before it closes
 }
 
+// GateHuman marks a step that waits for a human to approve it.
+const GateHuman = "human"
+
+// LintRig in a step's Lint line stands for the rig's configured review
+// linters (workflow.review_linters).
+const LintRig = "rig"
+
+// LabelGateAwaiting marks a gate step an agent is waiting on.
+const LabelGateAwaiting = "gate:awaiting"
+
 // BackoffConfig defines exponential backoff parameters for wait-type steps.
 // Used by patrol agents to implement cost-saving await-signal patterns.
 type BackoffConfig struct {
@@ -49,6 +63,27 @@ var typeLineRegex = regexp.MustCompile(`(?i)^Type:\s*(\w+)\s*$`)
 // Parses backoff configuration for wait-type steps.
 var backoffLineRegex = regexp.MustCompile(`(?i)^Backoff:\s*(.+)$`)
 
+// gateLineRegex matches "Gate: human" lines. It also matches the "gate:"
+// provenance line of instantiated steps, so StepGate can read either.
+var gateLineRegex = regexp.MustCompile(`(?i)^Gate:\s*(\w+)\s*$`)
+
+// lintLineRegex matches "Lint: go vet, staticcheck" lines. It also matches
+// the "lint:" provenance line of instantiated steps, so StepLint can read
+// either.
+var lintLineRegex = regexp.MustCompile(`(?i)^Lint:\s*(.+)$`)
+
+// inputsLineRegex matches "Inputs: design/design.md" lines, and the
+// "inputs:" provenance line of instantiated steps.
+var inputsLineRegex = regexp.MustCompile(`(?i)^Inputs:\s*(.+)$`)
+
+// outputsLineRegex matches "Outputs: design.md" lines, and the "outputs:"
+// provenance line of instantiated steps.
+var outputsLineRegex = regexp.MustCompile(`(?i)^Outputs:\s*(.+)$`)
+
+// stepRefLineRegex matches the "step: <ref>" provenance line of
+// instantiated steps.
+var stepRefLineRegex = regexp.MustCompile(`^step:\s*(\S+)\s*$`)
+
 // templateVarRegex matches {{variable}} placeholders.
 var templateVarRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
 
@@ -62,6 +97,10 @@ var templateVarRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
 //	Tier: haiku|sonnet|opus  # optional
 //	Type: task|wait  # optional, default is "task"
 //	Backoff: base=30s, multiplier=2, max=10m  # optional, for wait-type steps
+//	Gate: human  # optional, wait for 'gt approve' before the step closes
+//	Lint: rig | go vet, staticcheck  # optional, linter findings for the step
+//	Inputs: design/design.md  # optional, earlier steps' artifacts for the step
+//	Outputs: design.md  # optional, artifacts the step must store
 //
 // Returns an empty slice if no steps are found.
 func ParseMoleculeSteps(description string) ([]MoleculeStep, error) {
@@ -127,6 +166,28 @@ func ParseMoleculeSteps(description string) ([]MoleculeStep, error) {
 				continue
 			}
 
+			// Check for Gate: line
+			if matches := gateLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.Gate = strings.ToLower(matches[1])
+				continue
+			}
+
+			// Check for Lint: line
+			if matches := lintLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.Lint = splitLinters(matches[1])
+				continue
+			}
+
+			// Check for Inputs: and Outputs: lines
+			if matches := inputsLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.Inputs = splitLinters(matches[1])
+				continue
+			}
+			if matches := outputsLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.Outputs = splitLinters(matches[1])
+				continue
+			}
+
 			// Regular instruction line
 			instructionLines = append(instructionLines, line)
 		}
@@ -236,6 +297,78 @@ func ExpandTemplateVars(text string, ctx map[string]string) string {
 	})
 }
 
+// StepGate returns the approval gate declared in a step issue's description
+// ("Gate: human" in a formula step, or the "gate:" line instantiation adds),
+// or "" for an ordinary step.
+func StepGate(step *Issue) string {
+	for _, line := range strings.Split(step.Description, "\n") {
+		if matches := gateLineRegex.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			return strings.ToLower(matches[1])
+		}
+	}
+	return ""
+}
+
+// StepLint returns the linters a step issue asks to run before it starts
+// ("Lint:" in a formula step, or the "lint:" line instantiation adds), or
+// nil for an ordinary step.
+func StepLint(step *Issue) []string {
+	for _, line := range strings.Split(step.Description, "\n") {
+		if matches := lintLineRegex.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			return splitLinters(matches[1])
+		}
+	}
+	return nil
+}
+
+// StepInputs returns the artifacts of earlier steps, as step/name, that a
+// step issue asks to have attached when it starts ("Inputs:" in a formula
+// step, or the "inputs:" line instantiation adds).
+func StepInputs(step *Issue) []string {
+	return stepListLine(step, inputsLineRegex)
+}
+
+// StepOutputs returns the artifacts a step issue must store before it
+// closes ("Outputs:" in a formula step, or the "outputs:" line
+// instantiation adds).
+func StepOutputs(step *Issue) []string {
+	return stepListLine(step, outputsLineRegex)
+}
+
+// stepListLine returns the comma-separated list of the first line of a step
+// issue's description that re matches, or nil.
+func stepListLine(step *Issue, re *regexp.Regexp) []string {
+	for _, line := range strings.Split(step.Description, "\n") {
+		if matches := re.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			return splitLinters(matches[1])
+		}
+	}
+	return nil
+}
+
+// splitLinters splits a Lint (or Inputs/Outputs) line's comma-separated
+// entries.
+func splitLinters(list string) []string {
+	var linters []string
+	for _, l := range strings.Split(list, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			linters = append(linters, l)
+		}
+	}
+	return linters
+}
+
+// StepRef returns the step reference recorded when a step issue was
+// instantiated, or "" if it has none.
+func StepRef(step *Issue) string {
+	for _, line := range strings.Split(step.Description, "\n") {
+		if matches := stepRefLineRegex.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
 // InstantiateOptions configures molecule instantiation behavior.
 type InstantiateOptions struct {
 	// Context map for {{variable}} substitution
@@ -406,6 +539,18 @@ func (b *Beads) instantiateFromMarkdown(mol *Issue, parent *Issue, opts Instanti
 		if step.Tier != "" {
 			description += fmt.Sprintf("\ntier: %s", step.Tier)
 		}
+		if step.Gate != "" {
+			description += fmt.Sprintf("\ngate: %s", step.Gate)
+		}
+		if len(step.Lint) > 0 {
+			description += fmt.Sprintf("\nlint: %s", strings.Join(step.Lint, ", "))
+		}
+		if len(step.Inputs) > 0 {
+			description += fmt.Sprintf("\ninputs: %s", strings.Join(step.Inputs, ", "))
+		}
+		if len(step.Outputs) > 0 {
+			description += fmt.Sprintf("\noutputs: %s", strings.Join(step.Outputs, ", "))
+		}
 
 		// Create the child issue
 		childOpts := CreateOptions{
@@ -787,3 +787,126 @@ Needs: step1`
 		t.Errorf("step[1].Type = %q, want task", steps[1].Type)
 	}
 }
+
+func TestParseMoleculeSteps_WithGate(t *testing.T) {
+	desc := `## Step: implement
+Write the code.
+
+## Step: review
+A human reviews the diff.
+Needs: implement
+Gate: Human
+
+## Step: merge
+Submit to the merge queue.
+Needs: review`
+
+	steps, err := ParseMoleculeSteps(desc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(steps))
+	}
+	if steps[0].Gate != "" {
+		t.Errorf("step[0].Gate = %q, want empty", steps[0].Gate)
+	}
+	if steps[1].Gate != GateHuman {
+		t.Errorf("step[1].Gate = %q, want %q", steps[1].Gate, GateHuman)
+	}
+	if strings.Contains(steps[1].Instructions, "Gate:") {
+		t.Errorf("Gate line should be stripped from instructions, got %q", steps[1].Instructions)
+	}
+}
+
+func TestStepGateAndRef(t *testing.T) {
+	instantiated := &Issue{Description: "A human reviews the diff.\n\ninstantiated_from: gt-mol\nstep: review\ngate: human"}
+	if got := StepGate(instantiated); got != GateHuman {
+		t.Errorf("StepGate(instantiated) = %q, want %q", got, GateHuman)
+	}
+	if got := StepRef(instantiated); got != "review" {
+		t.Errorf("StepRef(instantiated) = %q, want review", got)
+	}
+
+	// Formula steps carry the attribute in their own description
+	formula := &Issue{Description: "Wait for sign-off.\nGate: human"}
+	if got := StepGate(formula); got != GateHuman {
+		t.Errorf("StepGate(formula) = %q, want %q", got, GateHuman)
+	}
+
+	plain := &Issue{Description: "Write the code.\n\nstep: implement"}
+	if got := StepGate(plain); got != "" {
+		t.Errorf("StepGate(plain) = %q, want empty", got)
+	}
+}
+
+func TestParseMoleculeSteps_WithLint(t *testing.T) {
+	desc := `## Step: implement
+Write the code.
+
+## Step: review
+Review your own diff.
+Needs: implement
+Lint: go vet,  staticcheck`
+
+	steps, err := ParseMoleculeSteps(desc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(steps))
+	}
+	if len(steps[0].Lint) != 0 {
+		t.Errorf("step[0].Lint = %q, want none", steps[0].Lint)
+	}
+	if want := []string{"go vet", "staticcheck"}; !reflect.DeepEqual(steps[1].Lint, want) {
+		t.Errorf("step[1].Lint = %q, want %q", steps[1].Lint, want)
+	}
+	if strings.Contains(steps[1].Instructions, "Lint:") {
+		t.Errorf("Lint line should be stripped from instructions, got %q", steps[1].Instructions)
+	}
+}
+
+func TestStepLint(t *testing.T) {
+	instantiated := &Issue{Description: "Review your own diff.\n\ninstantiated_from: gt-mol\nstep: review\nlint: rig"}
+	if got := StepLint(instantiated); !reflect.DeepEqual(got, []string{LintRig}) {
+		t.Errorf("StepLint(instantiated) = %q, want [rig]", got)
+	}
+
+	formula := &Issue{Description: "Self-review.\nLint: go vet, golangci-lint"}
+	if got := StepLint(formula); !reflect.DeepEqual(got, []string{"go vet", "golangci-lint"}) {
+		t.Errorf("StepLint(formula) = %q", got)
+	}
+
+	if got := StepLint(&Issue{Description: "Write the code."}); got != nil {
+		t.Errorf("StepLint(plain) = %q, want nil", got)
+	}
+}
+
+func TestStepInputsOutputs(t *testing.T) {
+	steps, err := ParseMoleculeSteps("## Step: design\nThink first.\nOutputs: design.md\n\n## Step: implement\nWrite it.\nInputs: design/design.md, design/api.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"design.md"}; !reflect.DeepEqual(steps[0].Outputs, want) {
+		t.Errorf("design Outputs = %q", steps[0].Outputs)
+	}
+	if want := []string{"design/design.md", "design/api.md"}; !reflect.DeepEqual(steps[1].Inputs, want) {
+		t.Errorf("implement Inputs = %q", steps[1].Inputs)
+	}
+	if strings.Contains(steps[1].Instructions, "Inputs:") {
+		t.Errorf("Inputs line should be stripped from instructions, got %q", steps[1].Instructions)
+	}
+
+	formula := &Issue{Description: "Write the code for auth.\nInputs: design/design.md"}
+	if got := StepInputs(formula); !reflect.DeepEqual(got, []string{"design/design.md"}) {
+		t.Errorf("StepInputs(formula) = %q", got)
+	}
+	instantiated := &Issue{Description: "Think first.\n\ninstantiated_from: gt-mol\nstep: design\noutputs: design.md"}
+	if got := StepOutputs(instantiated); !reflect.DeepEqual(got, []string{"design.md"}) {
+		t.Errorf("StepOutputs(instantiated) = %q", got)
+	}
+	if StepInputs(instantiated) != nil || StepOutputs(formula) != nil {
+		t.Error("steps without the line should have none")
+	}
+}
@@ -0,0 +1,96 @@
+package beads
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// bookkeepingKinds are bead types (and gt:<kind> labels) that track the
+// town itself rather than work: agents, mail, merge requests, molecules
+// and the like.
+var bookkeepingKinds = map[string]bool{
+	"agent": true, "role": true, "rig": true, "message": true, "merge-request": true,
+	"molecule": true, "convoy": true, "gate": true, "event": true, "escalation": true,
+}
+
+// IsBookkeeping reports whether an issue tracks the town itself (an agent,
+// a mail message, a merge request, a molecule, ...) rather than work.
+func IsBookkeeping(issue *Issue) bool {
+	if bookkeepingKinds[issue.Type] {
+		return true
+	}
+	for _, l := range issue.Labels {
+		if kind, ok := strings.CutPrefix(l, "gt:"); ok && bookkeepingKinds[kind] {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadyWork returns the issues that can be picked up now, most urgent
+// first. See ReadyIssues.
+func (b *Beads) ReadyWork() ([]*Issue, error) {
+	issues, err := b.List(ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	return ReadyIssues(issues), nil
+}
+
+// ReadyIssues picks the work in issues that can be picked up now: open,
+// unassigned, not an epic or bookkeeping, and with every dependency
+// closed. A dependency that isn't in issues (a bead in another database)
+// counts as unmet, since its state is unknown. Molecule steps are left to
+// their molecule.
+//
+// The result is sorted by priority (P0 first), then oldest first.
+func ReadyIssues(issues []*Issue) []*Issue {
+	byID := make(map[string]*Issue, len(issues))
+	for _, i := range issues {
+		byID[i.ID] = i
+	}
+
+	var ready []*Issue
+	for _, i := range issues {
+		if i.Status != "open" || i.Assignee != "" || i.Type == "epic" || IsBookkeeping(i) {
+			continue
+		}
+		if parent, ok := byID[i.Parent]; ok && IsBookkeeping(parent) {
+			continue
+		}
+		if depsClosed(i, byID) {
+			ready = append(ready, i)
+		}
+	}
+	SortByUrgency(ready)
+	return ready
+}
+
+func depsClosed(issue *Issue, byID map[string]*Issue) bool {
+	for _, deps := range [][]string{issue.DependsOn, issue.BlockedBy} {
+		for _, id := range deps {
+			dep, ok := byID[id]
+			if !ok || dep.Status != "closed" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SortByUrgency sorts issues by priority (P0 first), then by age, oldest
+// first.
+func SortByUrgency(issues []*Issue) {
+	sort.SliceStable(issues, func(a, b int) bool {
+		if issues[a].Priority != issues[b].Priority {
+			return issues[a].Priority < issues[b].Priority
+		}
+		ta, errA := time.Parse(time.RFC3339, issues[a].CreatedAt)
+		tb, errB := time.Parse(time.RFC3339, issues[b].CreatedAt)
+		if errA == nil && errB == nil && !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		return issues[a].ID < issues[b].ID
+	})
+}
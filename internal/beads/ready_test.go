@@ -0,0 +1,69 @@
+package beads
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadyIssues(t *testing.T) {
+	issues := []*Issue{
+		{ID: "gt-done", Status: "closed", Type: "task"},
+		{ID: "gt-old", Status: "open", Type: "task", Priority: 2, CreatedAt: "2026-01-01T00:00:00Z"},
+		{ID: "gt-new", Status: "open", Type: "bug", Priority: 2, CreatedAt: "2026-02-01T00:00:00Z"},
+		{ID: "gt-urgent", Status: "open", Type: "task", Priority: 0, CreatedAt: "2026-03-01T00:00:00Z"},
+		{ID: "gt-unblocked", Status: "open", Type: "task", Priority: 1, DependsOn: []string{"gt-done"}},
+		{ID: "gt-blocked", Status: "open", Type: "task", DependsOn: []string{"gt-old"}},
+		{ID: "gt-blocked-by", Status: "open", Type: "task", BlockedBy: []string{"gt-new"}},
+		{ID: "gt-foreign", Status: "open", Type: "task", DependsOn: []string{"hq-elsewhere"}},
+		{ID: "gt-taken", Status: "open", Type: "task", Assignee: "gastown/polecats/Toast"},
+		{ID: "gt-wip", Status: "in_progress", Type: "task"},
+		{ID: "gt-epic", Status: "open", Type: "epic"},
+		{ID: "gt-agent", Status: "open", Type: "agent"},
+		{ID: "gt-mail", Status: "open", Type: "task", Labels: []string{"gt:message"}},
+		{ID: "gt-mol", Status: "open", Type: "molecule"},
+		{ID: "gt-mol.1", Status: "open", Type: "task", Parent: "gt-mol"},
+	}
+
+	var got []string
+	for _, i := range ReadyIssues(issues) {
+		got = append(got, i.ID)
+	}
+	want := []string{"gt-urgent", "gt-unblocked", "gt-old", "gt-new"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadyIssues() = %v, want %v", got, want)
+	}
+}
+
+func TestIsBookkeeping(t *testing.T) {
+	tests := []struct {
+		issue Issue
+		want  bool
+	}{
+		{Issue{Type: "task"}, false},
+		{Issue{Type: "bug", Labels: []string{"gt:task", "frontend"}}, false},
+		{Issue{Type: "merge-request"}, true},
+		{Issue{Type: "task", Labels: []string{"gt:agent"}}, true},
+		{Issue{Type: "task", Labels: []string{"gt:convoy"}}, true},
+	}
+	for _, tt := range tests {
+		if got := IsBookkeeping(&tt.issue); got != tt.want {
+			t.Errorf("IsBookkeeping(%+v) = %v, want %v", tt.issue, got, tt.want)
+		}
+	}
+}
+
+func TestSortByUrgency_TiesByID(t *testing.T) {
+	issues := []*Issue{
+		{ID: "gt-b", Priority: 1},
+		{ID: "gt-a", Priority: 1},
+		{ID: "gt-c", Priority: 1, CreatedAt: "2026-01-01T00:00:00Z"},
+	}
+	SortByUrgency(issues)
+	var got []string
+	for _, i := range issues {
+		got = append(got, i.ID)
+	}
+	if want := []string{"gt-a", "gt-b", "gt-c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortByUrgency() = %v, want %v", got, want)
+	}
+}
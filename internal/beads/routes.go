@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -15,6 +16,11 @@ import (
 type Route struct {
 	Prefix string `json:"prefix"` // Issue ID prefix (e.g., "gt-")
 	Path   string `json:"path"`   // Relative path to .beads directory from town root
+
+	// RenamedTo is the prefix that replaced this one (gt prefix rename).
+	// The database at Path has no IDs under Prefix any more; they are read
+	// under RenamedTo instead (see CurrentID). bd ignores it.
+	RenamedTo string `json:"renamed_to,omitempty"`
 }
 
 // RoutesFileName is the name of the routes configuration file.
@@ -61,7 +67,7 @@ func AppendRoute(townRoot string, route Route) error {
 }
 
 // AppendRouteToDir appends a route to routes.jsonl in the given beads directory.
-// If the prefix already exists, it updates the path.
+// If the prefix already exists, it replaces that route.
 func AppendRouteToDir(beadsDir string, route Route) error {
 	// Load existing routes
 	routes, err := LoadRoutes(beadsDir)
@@ -73,7 +79,7 @@ func AppendRouteToDir(beadsDir string, route Route) error {
 	found := false
 	for i, r := range routes {
 		if r.Prefix == route.Prefix {
-			routes[i].Path = route.Path
+			routes[i] = route
 			found = true
 			break
 		}
@@ -191,6 +197,18 @@ func FindConflictingPrefixes(beadsDir string) (map[string][]string, error) {
 	return conflicts, nil
 }
 
+// validIDPattern matches a bead ID: a lowercase prefix, a hyphen, and the
+// rest (which may itself hold hyphens and dots, as in hq-cv-abc or
+// gt-abc.1).
+var validIDPattern = regexp.MustCompile(`^[a-z0-9]+-[a-z0-9][a-z0-9.-]*$`)
+
+// IsValidID reports whether id is shaped like a bead ID. IDs from outside
+// (HTTP, MCP) are checked with it before they reach bd's command line,
+// where one starting with "-" would be read as a flag.
+func IsValidID(id string) bool {
+	return validIDPattern.MatchString(id)
+}
+
 // ExtractPrefix extracts the prefix from a bead ID.
 // For example, "ap-qtsup.16" returns "ap-", "hq-cv-abc" returns "hq-".
 // Returns empty string if no valid prefix found (empty input, no hyphen,
@@ -208,6 +226,36 @@ func ExtractPrefix(beadID string) string {
 	return beadID[:idx+1]
 }
 
+// CurrentID maps an ID under a renamed prefix to its ID under the prefix
+// that replaced it, per the town's routes, and returns other IDs
+// unchanged. Old IDs stay in commit messages and mail, so they are read
+// through this wherever they are looked up.
+func CurrentID(townRoot, id string) string {
+	prefix := ExtractPrefix(id)
+	if prefix == "" {
+		return id
+	}
+	routes, err := LoadRoutes(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		return id
+	}
+	// Follow renames; each hop is a different route, so stop after as many
+	for range routes {
+		renamed := ""
+		for _, r := range routes {
+			if r.Prefix == prefix {
+				renamed = r.RenamedTo
+			}
+		}
+		if renamed == "" || renamed == prefix {
+			break
+		}
+		id = renamed + strings.TrimPrefix(id, prefix)
+		prefix = renamed
+	}
+	return id
+}
+
 // GetRigPathForPrefix returns the rig path for a given bead ID prefix.
 // The townRoot should be the Gas Town root directory (e.g., ~/gt).
 // Returns the full absolute path to the rig directory, or empty string if not found.
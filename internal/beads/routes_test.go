@@ -125,6 +125,43 @@ func TestGetRigPathForPrefix_NoRoutesFile(t *testing.T) {
 	}
 }
 
+func TestCurrentID(t *testing.T) {
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// gt- was renamed to gtn-, then gtn- to gtx-
+	routesContent := `{"prefix": "gt-", "path": "gastown/mayor/rig", "renamed_to": "gtx-"}
+{"prefix": "gtn-", "path": "gastown/mayor/rig", "renamed_to": "gtx-"}
+{"prefix": "gtx-", "path": "gastown/mayor/rig"}
+{"prefix": "hq-", "path": "."}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "routes.jsonl"), []byte(routesContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id       string
+		expected string
+	}{
+		{"gt-abc12", "gtx-abc12"},
+		{"gtn-abc12.3", "gtx-abc12.3"},
+		{"gtx-abc12", "gtx-abc12"},
+		{"hq-mayor", "hq-mayor"},
+		{"zz-abc12", "zz-abc12"}, // Unknown prefix is unchanged
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.id, func(t *testing.T) {
+			if got := CurrentID(tmpDir, tc.id); got != tc.expected {
+				t.Errorf("CurrentID(%q) = %q, want %q", tc.id, got, tc.expected)
+			}
+		})
+	}
+}
+
 func TestResolveHookDir(t *testing.T) {
 	// Create a temporary directory with routes.jsonl
 	tmpDir := t.TempDir()
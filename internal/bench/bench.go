@@ -0,0 +1,154 @@
+// Package bench measures gt's orchestration overhead: how long the
+// operations every workflow leans on (listing beads through the bd wrapper,
+// starting an agent session, adding a rig) take. Reports are JSON with
+// stable names, so runs from different versions can be compared.
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// Result is one benchmark's timings, in milliseconds.
+type Result struct {
+	Name       string  `json:"name"`
+	Iterations int     `json:"iterations"`
+	MinMs      float64 `json:"min_ms,omitempty"`
+	MedianMs   float64 `json:"median_ms,omitempty"`
+	MeanMs     float64 `json:"mean_ms,omitempty"`
+	P95Ms      float64 `json:"p95_ms,omitempty"`
+	MaxMs      float64 `json:"max_ms,omitempty"`
+	Skipped    string  `json:"skipped,omitempty"` // Why it didn't run
+	Error      string  `json:"error,omitempty"`   // Why it stopped
+}
+
+// Report is a whole gt bench run.
+type Report struct {
+	Version   string    `json:"version"`
+	Commit    string    `json:"commit,omitempty"`
+	Platform  string    `json:"platform"`
+	BdVersion string    `json:"bd_version,omitempty"`
+	Time      time.Time `json:"time"`
+	Params    Params    `json:"params"`
+	Results   []Result  `json:"results"`
+}
+
+// Params are the knobs a report was measured with. Reports with different
+// params don't compare.
+type Params struct {
+	Iterations int `json:"iterations"`
+	Issues     int `json:"issues"` // Beads in the bd-list fixture
+}
+
+// Measure runs fn n times and summarizes how long each run took. It stops
+// at the first error, keeping the runs that succeeded.
+func Measure(name string, n int, fn func(i int) error) Result {
+	return MeasureWithSetup(name, n, nil, fn)
+}
+
+// MeasureWithSetup is Measure with an untimed setup before each run.
+func MeasureWithSetup(name string, n int, setup, fn func(i int) error) Result {
+	var samples []time.Duration
+	for i := 0; i < n; i++ {
+		if setup != nil {
+			if err := setup(i); err != nil {
+				r := Summarize(name, samples)
+				r.Error = fmt.Sprintf("setup: %v", err)
+				return r
+			}
+		}
+		start := time.Now()
+		if err := fn(i); err != nil {
+			r := Summarize(name, samples)
+			r.Error = err.Error()
+			return r
+		}
+		samples = append(samples, time.Since(start))
+	}
+	return Summarize(name, samples)
+}
+
+// Skip is a result for a benchmark that couldn't run here.
+func Skip(name, reason string) Result {
+	return Result{Name: name, Skipped: reason}
+}
+
+// Summarize turns samples into a result.
+func Summarize(name string, samples []time.Duration) Result {
+	r := Result{Name: name, Iterations: len(samples)}
+	if len(samples) == 0 {
+		return r
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	r.MinMs = ms(sorted[0])
+	r.MaxMs = ms(sorted[len(sorted)-1])
+	r.MeanMs = ms(total / time.Duration(len(sorted)))
+	r.MedianMs = ms(percentile(sorted, 50))
+	r.P95Ms = ms(percentile(sorted, 95))
+	return r
+}
+
+// percentile is the nearest-rank percentile of sorted samples.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Delta is how a benchmark's median moved between two reports.
+type Delta struct {
+	Name     string  `json:"name"`
+	BaseMs   float64 `json:"base_median_ms"`
+	MedianMs float64 `json:"median_ms"`
+	Change   float64 `json:"change_pct"` // Positive is slower
+}
+
+// Compare matches the benchmarks both reports measured.
+func Compare(base, cur *Report) []Delta {
+	baseByName := make(map[string]Result, len(base.Results))
+	for _, r := range base.Results {
+		baseByName[r.Name] = r
+	}
+	var deltas []Delta
+	for _, r := range cur.Results {
+		b, ok := baseByName[r.Name]
+		if !ok || b.MedianMs == 0 || r.MedianMs == 0 {
+			continue
+		}
+		deltas = append(deltas, Delta{
+			Name:     r.Name,
+			BaseMs:   b.MedianMs,
+			MedianMs: r.MedianMs,
+			Change:   (r.MedianMs - b.MedianMs) / b.MedianMs * 100,
+		})
+	}
+	return deltas
+}
+
+// Load reads a report written by gt bench --json.
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the user's report
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing bench report %s: %w", path, err)
+	}
+	return &r, nil
+}
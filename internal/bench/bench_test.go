@@ -0,0 +1,55 @@
+package bench
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	var samples []time.Duration
+	for i := 10; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+	r := Summarize("x", samples)
+	if r.Iterations != 10 || r.MinMs != 1 || r.MaxMs != 10 || r.MedianMs != 5 || r.P95Ms != 10 || r.MeanMs != 5.5 {
+		t.Errorf("Summarize = %+v", r)
+	}
+	if r := Summarize("x", nil); r.Iterations != 0 || r.MedianMs != 0 {
+		t.Errorf("Summarize of nothing = %+v", r)
+	}
+}
+
+func TestMeasureStopsAtError(t *testing.T) {
+	r := Measure("x", 5, func(i int) error {
+		if i == 2 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	if r.Iterations != 2 || r.Error != "boom" {
+		t.Errorf("Measure = %+v", r)
+	}
+}
+
+func TestCompareAndLoad(t *testing.T) {
+	base := &Report{Results: []Result{{Name: "bd-list", MedianMs: 100}, {Name: "spawn", Skipped: "no tmux"}}}
+	cur := &Report{Results: []Result{{Name: "bd-list", MedianMs: 150}, {Name: "spawn", MedianMs: 900}, {Name: "rig-add", MedianMs: 10}}}
+
+	path := filepath.Join(t.TempDir(), "base.json")
+	data, _ := json.Marshal(base)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deltas := Compare(loaded, cur)
+	if len(deltas) != 1 || deltas[0].Name != "bd-list" || deltas[0].Change != 50 {
+		t.Errorf("Compare = %+v", deltas)
+	}
+}
@@ -0,0 +1,89 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// AlertsFile records, within the town's .runtime directory, which budget
+// levels have already been announced in each window.
+const AlertsFile = "budget-alerts.json"
+
+// Announce logs a budget_warning or budget_exceeded event for each cap
+// that reached a new level in its current window. Each level is announced
+// once per window, however many times Announce runs.
+func Announce(townRoot string, s *Status, now time.Time) error {
+	path := filepath.Join(townRoot, ".runtime", AlertsFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking budget alerts: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	announced := make(map[string]Level)
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is constructed internally
+		_ = json.Unmarshal(data, &announced)
+	}
+
+	changed := false
+	for i := range s.Caps {
+		c := &s.Caps[i]
+		key := windowKey(c, now)
+		if c.Level == OK || announced[key] >= c.Level {
+			continue
+		}
+		eventType := events.TypeBudgetWarning
+		if c.Level == Exceeded {
+			eventType = events.TypeBudgetExceeded
+		}
+		_ = events.LogFeed(eventType, "budget", events.BudgetPayload(c.Period, c.Unit, c.Used, c.Limit, c.Percent))
+		announced[key] = c.Level
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	// Drop windows that have passed
+	for key := range announced {
+		if !isCurrent(key, s, now) {
+			delete(announced, key)
+		}
+	}
+	data, err := json.MarshalIndent(announced, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(path, append(data, '\n'), 0644)
+}
+
+// windowKey names a cap's current window, e.g. "daily/usd/2026-01-07".
+// The weekly window is keyed by the ISO week, so a weekly level is
+// announced at most once a calendar week.
+func windowKey(c *Cap, now time.Time) string {
+	window := now.Format("2006-01-02")
+	if c.Period == Weekly {
+		year, week := now.ISOWeek()
+		window = fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return c.Period + "/" + c.Unit + "/" + window
+}
+
+func isCurrent(key string, s *Status, now time.Time) bool {
+	for i := range s.Caps {
+		if windowKey(&s.Caps[i], now) == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+// Package budget enforces the town's spend caps.
+//
+// Caps come from the "budget" section of settings/config.json and are
+// measured against the session_end events 'gt costs record' writes to the
+// town event log. At the warning threshold (80% by default) new spawns
+// pause: slings are queued, queued slings are held, and a budget_warning
+// event is sent to notification sinks. At 100% spawning stops outright
+// until the window rolls over or the cap is raised; --over-budget
+// overrides both for a single sling or drain.
+package budget
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/format"
+)
+
+// DefaultWarnPercent is the share of a cap at which spawns pause.
+const DefaultWarnPercent = 80
+
+// Periods and units of a cap.
+const (
+	Daily  = "daily"
+	Weekly = "weekly"
+
+	USD    = "usd"
+	Tokens = "tokens"
+)
+
+// Level is how close the town is to its caps.
+type Level int
+
+// Budget levels, in increasing severity.
+const (
+	OK       Level = iota
+	Warn           // at or over the warning threshold: new spawns pause
+	Exceeded       // at or over a cap: all dispatch stops
+)
+
+func (l Level) String() string {
+	switch l {
+	case Warn:
+		return "warning"
+	case Exceeded:
+		return "exceeded"
+	}
+	return "ok"
+}
+
+// MarshalText encodes a level by name.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes a level name written by MarshalText.
+func (l *Level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "ok":
+		*l = OK
+	case "warning":
+		*l = Warn
+	case "exceeded":
+		*l = Exceeded
+	default:
+		return fmt.Errorf("unknown budget level %q", text)
+	}
+	return nil
+}
+
+// Spend is the recorded agent spend over a period.
+type Spend struct {
+	USD    float64 `json:"usd"`
+	Tokens int64   `json:"tokens"`
+}
+
+// Cap is one configured limit and the spend against it.
+type Cap struct {
+	Period  string  `json:"period"` // Daily or Weekly
+	Unit    string  `json:"unit"`   // USD or Tokens
+	Limit   float64 `json:"limit"`
+	Used    float64 `json:"used"`
+	Percent float64 `json:"percent"`
+	Level   Level   `json:"level"`
+}
+
+// String describes the cap, e.g. "daily spend $41.20 of $50.00 (82%)".
+func (c *Cap) String() string {
+	return fmt.Sprintf("%s %s %s of %s (%.0f%%)", c.Period, c.noun(), c.amount(c.Used), c.amount(c.Limit), c.Percent)
+}
+
+func (c *Cap) noun() string {
+	if c.Unit == Tokens {
+		return "tokens"
+	}
+	return "spend"
+}
+
+func (c *Cap) amount(v float64) string {
+	if c.Unit == Tokens {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return format.Currency(v)
+}
+
+// Status is the town's standing against its caps.
+type Status struct {
+	WarnPercent int   `json:"warn_percent"`
+	Day         Spend `json:"day"`
+	Week        Spend `json:"week"`
+	Caps        []Cap `json:"caps"`
+	Level       Level `json:"level"`
+}
+
+// Worst returns the cap closest to (or furthest over) its limit, or nil
+// when no caps are configured.
+func (s *Status) Worst() *Cap {
+	var worst *Cap
+	for i := range s.Caps {
+		if worst == nil || s.Caps[i].Percent > worst.Percent {
+			worst = &s.Caps[i]
+		}
+	}
+	return worst
+}
+
+// Reason describes why spawning is paused or stopped.
+func (s *Status) Reason() string {
+	worst := s.Worst()
+	if worst == nil {
+		return ""
+	}
+	return "budget " + worst.Level.String() + ": " + worst.String()
+}
+
+// Validate rejects negative caps and out-of-range thresholds.
+func Validate(cfg *config.BudgetConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.DailyUSD < 0 || cfg.WeeklyUSD < 0 || cfg.DailyTokens < 0 || cfg.WeeklyTokens < 0 {
+		return fmt.Errorf("budget caps must not be negative")
+	}
+	if cfg.WarnPercent < 0 || cfg.WarnPercent > 100 {
+		return fmt.Errorf("budget warn_percent must be between 0 and 100, got %d", cfg.WarnPercent)
+	}
+	return nil
+}
+
+// Evaluate measures spend against cfg. A nil cfg has no caps.
+func Evaluate(cfg *config.BudgetConfig, day, week Spend) *Status {
+	s := &Status{WarnPercent: DefaultWarnPercent, Day: day, Week: week}
+	if cfg == nil {
+		return s
+	}
+	if cfg.WarnPercent > 0 {
+		s.WarnPercent = cfg.WarnPercent
+	}
+
+	add := func(period, unit string, limit, used float64) {
+		if limit <= 0 {
+			return
+		}
+		c := Cap{Period: period, Unit: unit, Limit: limit, Used: used, Percent: used / limit * 100}
+		switch {
+		case c.Percent >= 100:
+			c.Level = Exceeded
+		case c.Percent >= float64(s.WarnPercent):
+			c.Level = Warn
+		}
+		if c.Level > s.Level {
+			s.Level = c.Level
+		}
+		s.Caps = append(s.Caps, c)
+	}
+	add(Daily, USD, cfg.DailyUSD, day.USD)
+	add(Weekly, USD, cfg.WeeklyUSD, week.USD)
+	add(Daily, Tokens, float64(cfg.DailyTokens), float64(day.Tokens))
+	add(Weekly, Tokens, float64(cfg.WeeklyTokens), float64(week.Tokens))
+	return s
+}
+
+// Load reads the town's caps and its spend today and over the last week.
+func Load(townRoot string, now time.Time) (*Status, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if err := Validate(settings.Budget); err != nil {
+		return nil, err
+	}
+	if settings.Budget == nil {
+		return Evaluate(nil, Spend{}, Spend{}), nil
+	}
+	day, week, err := Usage(townRoot, now)
+	if err != nil {
+		return nil, err
+	}
+	return Evaluate(settings.Budget, day, week), nil
+}
+
+// Usage sums the session spend recorded in the town event log since local
+// midnight and over the seven days before now.
+func Usage(townRoot string, now time.Time) (day, week Spend, err error) {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return day, week, nil
+	}
+	if err != nil {
+		return day, week, fmt.Errorf("reading event log: %w", err)
+	}
+	defer f.Close()
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	weekStart := now.Add(-7 * 24 * time.Hour)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if json.Unmarshal(scanner.Bytes(), &e) != nil || e.Type != events.TypeSessionEnd {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || ts.Before(weekStart) || ts.After(now) {
+			continue
+		}
+		var s Spend
+		s.USD, _ = e.Payload["cost_usd"].(float64)
+		if tokens, ok := e.Payload["tokens"].(float64); ok {
+			s.Tokens = int64(tokens)
+		}
+		week.USD += s.USD
+		week.Tokens += s.Tokens
+		if !ts.Before(midnight) {
+			day.USD += s.USD
+			day.Tokens += s.Tokens
+		}
+	}
+	return day, week, scanner.Err()
+}
@@ -0,0 +1,154 @@
+package budget
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestEvaluate(t *testing.T) {
+	cfg := &config.BudgetConfig{DailyUSD: 50, WeeklyUSD: 200, DailyTokens: 1000}
+
+	tests := []struct {
+		name  string
+		day   Spend
+		week  Spend
+		level Level
+	}{
+		{"under", Spend{USD: 10, Tokens: 100}, Spend{USD: 60}, OK},
+		{"daily warning", Spend{USD: 40}, Spend{USD: 60}, Warn},
+		{"weekly exceeded", Spend{USD: 10}, Spend{USD: 200}, Exceeded},
+		{"tokens exceeded", Spend{Tokens: 1500}, Spend{}, Exceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Evaluate(cfg, tt.day, tt.week)
+			if s.Level != tt.level {
+				t.Errorf("Level = %v, want %v", s.Level, tt.level)
+			}
+			if len(s.Caps) != 3 {
+				t.Errorf("got %d caps, want 3 (weekly tokens is unset)", len(s.Caps))
+			}
+		})
+	}
+
+	if s := Evaluate(nil, Spend{USD: 1e6}, Spend{}); s.Level != OK || len(s.Caps) != 0 {
+		t.Errorf("no config: got level %v with %d caps", s.Level, len(s.Caps))
+	}
+
+	s := Evaluate(&config.BudgetConfig{DailyUSD: 50, WarnPercent: 90}, Spend{USD: 41.2}, Spend{})
+	if s.Level != OK {
+		t.Errorf("82%% with a 90%% threshold: Level = %v, want ok", s.Level)
+	}
+	if got := s.Worst().String(); got != "daily spend $41.20 of $50.00 (82%)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(&config.BudgetConfig{DailyUSD: -1}); err == nil {
+		t.Error("negative cap accepted")
+	}
+	if err := Validate(&config.BudgetConfig{WarnPercent: 120}); err == nil {
+		t.Error("warn_percent over 100 accepted")
+	}
+	if err := Validate(&config.BudgetConfig{DailyUSD: 10, WarnPercent: 75}); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+}
+
+func writeEvents(t *testing.T, townRoot string, evs ...events.Event) {
+	t.Helper()
+	var lines []string
+	for _, e := range evs {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, string(data))
+	}
+	lines = append(lines, "not json")
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 1, 7, 15, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+
+	writeEvents(t, townRoot,
+		events.Event{Timestamp: at(time.Hour), Type: events.TypeSessionEnd, Payload: map[string]interface{}{"cost_usd": 2.5, "tokens": 1000}},
+		events.Event{Timestamp: at(20 * time.Hour), Type: events.TypeSessionEnd, Payload: map[string]interface{}{"cost_usd": 4.0}},
+		events.Event{Timestamp: at(10 * 24 * time.Hour), Type: events.TypeSessionEnd, Payload: map[string]interface{}{"cost_usd": 100.0}},
+		events.Event{Timestamp: at(time.Hour), Type: events.TypeSling, Payload: map[string]interface{}{"cost_usd": 9.0}},
+	)
+
+	day, week, err := Usage(townRoot, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if day != (Spend{USD: 2.5, Tokens: 1000}) {
+		t.Errorf("day = %+v", day)
+	}
+	if week != (Spend{USD: 6.5, Tokens: 1000}) {
+		t.Errorf("week = %+v", week)
+	}
+
+	if _, _, err := Usage(t.TempDir(), now); err != nil {
+		t.Errorf("missing event log: %v", err)
+	}
+}
+
+func TestAnnounceOncePerWindow(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Chdir(t.TempDir()) // outside a workspace: announced events go nowhere
+	now := time.Date(2026, 1, 7, 15, 0, 0, 0, time.UTC)
+	cfg := &config.BudgetConfig{DailyUSD: 50}
+
+	read := func() map[string]Level {
+		data, err := os.ReadFile(filepath.Join(townRoot, ".runtime", AlertsFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]Level)
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	if err := Announce(townRoot, Evaluate(cfg, Spend{USD: 45}, Spend{}), now); err != nil {
+		t.Fatal(err)
+	}
+	if got := read()["daily/usd/2026-01-07"]; got != Warn {
+		t.Fatalf("announced %v, want warning", got)
+	}
+
+	// A lower reading in the same window doesn't lower the recorded level
+	if err := Announce(townRoot, Evaluate(cfg, Spend{USD: 10}, Spend{}), now); err != nil {
+		t.Fatal(err)
+	}
+	if err := Announce(townRoot, Evaluate(cfg, Spend{USD: 60}, Spend{}), now); err != nil {
+		t.Fatal(err)
+	}
+	if got := read()["daily/usd/2026-01-07"]; got != Exceeded {
+		t.Fatalf("announced %v, want exceeded", got)
+	}
+
+	// The next day starts a new window and forgets the old one
+	if err := Announce(townRoot, Evaluate(cfg, Spend{USD: 45}, Spend{}), now.Add(24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	got := read()
+	if len(got) != 1 || got["daily/usd/2026-01-08"] != Warn {
+		t.Errorf("alerts = %v", got)
+	}
+}
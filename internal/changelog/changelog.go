@@ -0,0 +1,159 @@
+// Package changelog builds release notes from the beads merged into a rig
+// since its last release.
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Section headings, in the order they are rendered.
+const (
+	SectionBreaking = "Breaking Changes"
+	SectionFeatures = "Features"
+	SectionFixes    = "Bug Fixes"
+	SectionPerf     = "Performance"
+	SectionDocs     = "Documentation"
+	SectionOther    = "Other Changes"
+)
+
+var sectionOrder = []string{SectionBreaking, SectionFeatures, SectionFixes, SectionPerf, SectionDocs, SectionOther}
+
+// Labels that steer a bead's section. "changelog:<heading>" puts it under
+// a heading of its own; "changelog:skip" leaves it out.
+const (
+	LabelPrefix = "changelog:"
+	LabelSkip   = "changelog:skip"
+)
+
+// labelSections maps conventional labels to sections.
+var labelSections = map[string]string{
+	"breaking":        SectionBreaking,
+	"breaking-change": SectionBreaking,
+	"perf":            SectionPerf,
+	"performance":     SectionPerf,
+	"docs":            SectionDocs,
+	"documentation":   SectionDocs,
+}
+
+// typeSections maps bead types to sections; other types are Other Changes.
+var typeSections = map[string]string{
+	"feature": SectionFeatures,
+	"epic":    SectionFeatures,
+	"bug":     SectionFixes,
+}
+
+// infraLabels mark Gas Town's own beads, which never appear in release
+// notes even when a commit mentions them.
+var infraLabels = map[string]bool{
+	"gt:agent":         true,
+	"gt:convoy":        true,
+	"gt:escalation":    true,
+	"gt:merge-request": true,
+	"gt:molecule":      true,
+	"gt:rig":           true,
+	"gt:wisp":          true,
+}
+
+// Entry is one merged bead.
+type Entry struct {
+	ID     string   `json:"id"`
+	Title  string   `json:"title"`
+	Type   string   `json:"type"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// Section is a heading and the beads under it.
+type Section struct {
+	Title   string  `json:"title"`
+	Entries []Entry `json:"entries"`
+}
+
+// BeadIDs returns the bead IDs with the given prefix mentioned in commit
+// messages, in order of first mention. Refinery merge commits name the
+// bead ("Merge polecat/nux into main (gt-abc12)"), as do most polecat
+// commits.
+func BeadIDs(messages []string, prefix string) []string {
+	re := regexp.MustCompile(`\b` + regexp.QuoteMeta(prefix) + `-[a-z0-9]+(?:\.[0-9]+)*\b`)
+	seen := make(map[string]bool)
+	var ids []string
+	for _, msg := range messages {
+		for _, id := range re.FindAllString(msg, -1) {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// sectionFor returns the heading an entry goes under, or "" to leave it
+// out.
+func sectionFor(e Entry) string {
+	section := ""
+	for _, l := range e.Labels {
+		switch {
+		case l == LabelSkip, infraLabels[l]:
+			return ""
+		case strings.HasPrefix(l, LabelPrefix):
+			section = strings.TrimPrefix(l, LabelPrefix)
+		case section == "" && labelSections[l] != "":
+			section = labelSections[l]
+		}
+	}
+	if section != "" {
+		return section
+	}
+	if s, ok := typeSections[e.Type]; ok {
+		return s
+	}
+	return SectionOther
+}
+
+// Group sorts entries into sections: the standard ones in their order,
+// then custom headings alphabetically. Entries keep their order within a
+// section.
+func Group(entries []Entry) []Section {
+	bySection := make(map[string][]Entry)
+	for _, e := range entries {
+		if s := sectionFor(e); s != "" {
+			bySection[s] = append(bySection[s], e)
+		}
+	}
+	var sections []Section
+	for _, title := range sectionOrder {
+		if es := bySection[title]; len(es) > 0 {
+			sections = append(sections, Section{Title: title, Entries: es})
+			delete(bySection, title)
+		}
+	}
+	var custom []string
+	for title := range bySection {
+		custom = append(custom, title)
+	}
+	sort.Strings(custom)
+	for _, title := range custom {
+		sections = append(sections, Section{Title: title, Entries: bySection[title]})
+	}
+	return sections
+}
+
+// Render formats sections as a markdown changelog section for version.
+func Render(version string, date time.Time, sections []Section) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s (%s)\n", version, date.Format("2006-01-02"))
+	if len(sections) == 0 {
+		sb.WriteString("\nNo user-facing changes.\n")
+	}
+	for _, s := range sections {
+		fmt.Fprintf(&sb, "\n### %s\n\n", s.Title)
+		for _, e := range s.Entries {
+			fmt.Fprintf(&sb, "- %s (%s)\n", e.Title, e.ID)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,78 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBeadIDs(t *testing.T) {
+	messages := []string{
+		"Merge polecat/nux into main (gt-abc12)",
+		"Fix the widget (gt-abc12, gt-def34.2)\n\nAlso see hq-zzz and xgt-nope.",
+		"Merge polecat/toast into main",
+		"gt-9x8y7: tidy",
+	}
+	got := BeadIDs(messages, "gt")
+	want := []string{"gt-abc12", "gt-def34.2", "gt-9x8y7"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BeadIDs = %v, want %v", got, want)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	entries := []Entry{
+		{ID: "gt-1", Title: "Add widgets", Type: "feature"},
+		{ID: "gt-2", Title: "Fix crash", Type: "bug"},
+		{ID: "gt-3", Title: "Tidy up", Type: "task"},
+		{ID: "gt-4", Title: "Drop v1 API", Type: "feature", Labels: []string{"breaking"}},
+		{ID: "gt-5", Title: "Faster sync", Type: "task", Labels: []string{"perf"}},
+		{ID: "gt-6", Title: "Internal", Type: "task", Labels: []string{LabelSkip}},
+		{ID: "gt-7", Title: "Harden auth", Type: "bug", Labels: []string{"changelog:Security"}},
+		{ID: "gt-8", Title: "Merge: polecat/nux", Type: "task", Labels: []string{"gt:merge-request"}},
+	}
+	var got []string
+	for _, s := range Group(entries) {
+		ids := ""
+		for _, e := range s.Entries {
+			ids += " " + e.ID
+		}
+		got = append(got, s.Title+":"+ids)
+	}
+	want := []string{
+		"Breaking Changes: gt-4",
+		"Features: gt-1",
+		"Bug Fixes: gt-2",
+		"Performance: gt-5",
+		"Other Changes: gt-3",
+		"Security: gt-7",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Group = %q, want %q", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	date := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	got := Render("v1.2.0", date, []Section{
+		{Title: SectionFeatures, Entries: []Entry{{ID: "gt-1", Title: "Add widgets"}}},
+		{Title: SectionFixes, Entries: []Entry{{ID: "gt-2", Title: "Fix crash"}, {ID: "gt-3", Title: "Fix leak"}}},
+	})
+	want := `## v1.2.0 (2026-10-15)
+
+### Features
+
+- Add widgets (gt-1)
+
+### Bug Fixes
+
+- Fix crash (gt-2)
+- Fix leak (gt-3)
+`
+	if got != want {
+		t.Errorf("Render =\n%s\nwant\n%s", got, want)
+	}
+	if got := Render("v1.2.1", date, nil); got != "## v1.2.1 (2026-10-15)\n\nNo user-facing changes.\n" {
+		t.Errorf("Render(empty) = %q", got)
+	}
+}
@@ -49,6 +49,9 @@ type Checkpoint struct {
 
 	// Notes contains optional context from the session.
 	Notes string `json:"notes,omitempty"`
+
+	// LastPrompt is the last prompt sent into the session (e.g. a nudge).
+	LastPrompt string `json:"last_prompt,omitempty"`
 }
 
 // Path returns the checkpoint file path for a given polecat directory.
@@ -114,6 +117,32 @@ func Remove(polecatDir string) error {
 	return nil
 }
 
+// Update refreshes the checkpoint in the polecat directory: git state is
+// captured anew, work context (molecule, step, hook, notes) carries over
+// from the existing checkpoint, then fn applies any changes. fn may be nil.
+func Update(polecatDir string, fn func(cp *Checkpoint)) error {
+	cp, err := Read(polecatDir)
+	if err != nil {
+		return err
+	}
+	fresh, err := Capture(polecatDir)
+	if err != nil {
+		return err
+	}
+	if cp == nil {
+		cp = fresh
+	} else {
+		cp.ModifiedFiles = fresh.ModifiedFiles
+		cp.LastCommit = fresh.LastCommit
+		cp.Branch = fresh.Branch
+		cp.Timestamp = fresh.Timestamp
+	}
+	if fn != nil {
+		fn(cp)
+	}
+	return Write(polecatDir, cp)
+}
+
 // Capture creates a checkpoint by capturing current git and work state.
 func Capture(polecatDir string) (*Checkpoint, error) {
 	cp := &Checkpoint{
@@ -206,6 +206,42 @@ func TestCapture(t *testing.T) {
 	}
 }
 
+func TestUpdateCarriesWorkContext(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// No checkpoint yet: Update starts a fresh one
+	if err := Update(tmpDir, func(cp *Checkpoint) { cp.WithHookedBead("gt-xyz") }); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	cp, err := Read(tmpDir)
+	if err != nil || cp == nil {
+		t.Fatalf("Read after Update = %v, %v", cp, err)
+	}
+	if cp.HookedBead != "gt-xyz" {
+		t.Errorf("HookedBead = %q, want gt-xyz", cp.HookedBead)
+	}
+
+	// Later updates keep the work context and refresh the timestamp
+	cp.WithMolecule("mol-123", "mol-123.2", "Write tests")
+	cp.Timestamp = time.Now().Add(-time.Hour)
+	if err := Write(tmpDir, cp); err != nil {
+		t.Fatal(err)
+	}
+	if err := Update(tmpDir, func(cp *Checkpoint) { cp.LastPrompt = "check your mail" }); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, _ := Read(tmpDir)
+	if got.MoleculeID != "mol-123" || got.CurrentStep != "mol-123.2" || got.HookedBead != "gt-xyz" {
+		t.Errorf("work context lost: %+v", got)
+	}
+	if got.LastPrompt != "check your mail" {
+		t.Errorf("LastPrompt = %q", got.LastPrompt)
+	}
+	if got.Age() > time.Minute {
+		t.Errorf("timestamp not refreshed: age %v", got.Age())
+	}
+}
+
 func TestWithMolecule(t *testing.T) {
 	cp := &Checkpoint{}
 	result := cp.WithMolecule("mol-abc", "step-1", "Do the thing")
@@ -0,0 +1,186 @@
+// Package chores schedules the town's periodic housekeeping: syncing and
+// repairing beads databases, verifying hooks, pruning stale branches and
+// seeding patrol molecules, and committing town metadata.
+//
+// The daemon decides what is due on each heartbeat and runs
+// 'gt deacon chores run --due'; gt does the work. This package holds the
+// schedules and the record of past runs, not the chores themselves.
+package chores
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Built-in chore names.
+const (
+	BdSync        = "bd-sync"
+	BdDoctor      = "bd-doctor"
+	HookVerify    = "hook-verify"
+	BranchPrune   = "branch-prune"
+	MoleculeSeed  = "molecule-seed"
+	HarnessCommit = "harness-commit"
+	Archive       = "archive"
+)
+
+// Off disables a chore in ChoresConfig.Schedule.
+const Off = "off"
+
+// Chore is a named housekeeping task with a default schedule.
+type Chore struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     string `json:"default"`
+}
+
+// Builtins are the chores gt knows how to run, in run order.
+var Builtins = []Chore{
+	{BdSync, "Sync every beads database (bd sync)", "0 3 * * *"},
+	{BdDoctor, "Repair every beads database (bd doctor --fix)", "15 3 * * *"},
+	{HookVerify, "Repair broken hook attachments and unhook stale hooked beads", "*/30 * * * *"},
+	{BranchPrune, "Delete stale polecat branches in every rig", "30 3 * * *"},
+	{MoleculeSeed, "Create missing patrol molecules in every rig", "45 3 * * *"},
+	{HarnessCommit, "Commit town metadata when harness_git.auto_commit is \"schedule\"", "@hourly"},
+	{Archive, "Offload old transcripts, artifacts and events when an archive is configured", "0 4 * * *"},
+}
+
+// Scheduled is a chore with its effective schedule.
+type Scheduled struct {
+	Chore
+	// Schedule is nil when the chore is off.
+	Schedule *Schedule
+}
+
+// Resolve applies configured schedule overrides to the built-in chores.
+// Unknown chore names and invalid expressions are errors.
+func Resolve(cfg *config.ChoresConfig) ([]Scheduled, error) {
+	overrides := map[string]string{}
+	if cfg != nil && cfg.Schedule != nil {
+		overrides = cfg.Schedule
+	}
+
+	known := make(map[string]bool, len(Builtins))
+	out := make([]Scheduled, 0, len(Builtins))
+	for _, c := range Builtins {
+		known[c.Name] = true
+		expr := c.Default
+		if o, ok := overrides[c.Name]; ok {
+			expr = o
+		}
+		s := Scheduled{Chore: c}
+		if !strings.EqualFold(strings.TrimSpace(expr), Off) {
+			sched, err := Parse(expr)
+			if err != nil {
+				return nil, fmt.Errorf("chore %s: %w", c.Name, err)
+			}
+			s.Schedule = sched
+		}
+		out = append(out, s)
+	}
+
+	var unknown []string
+	for name := range overrides {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown chore(s) %s", strings.Join(unknown, ", "))
+	}
+	return out, nil
+}
+
+// StateFile records chore runs within the town's .runtime directory.
+const StateFile = "chores.json"
+
+// Run is the outcome of a chore's last run.
+type Run struct {
+	At       time.Time     `json:"at"`
+	Duration time.Duration `json:"duration_ns"`
+	OK       bool          `json:"ok"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// State is the record of chore runs.
+type State struct {
+	// Since is when chores were first scheduled. A chore that has never
+	// run is first due at its next scheduled time after Since, so turning
+	// chores on doesn't run them all at once.
+	Since time.Time       `json:"since"`
+	Runs  map[string]*Run `json:"runs,omitempty"`
+}
+
+func statePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", StateFile)
+}
+
+// LoadState reads the chore state. A missing file is an empty state.
+func LoadState(townRoot string) (*State, error) {
+	s := &State{Runs: map[string]*Run{}}
+	data, err := os.ReadFile(statePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StateFile, err)
+	}
+	if s.Runs == nil {
+		s.Runs = map[string]*Run{}
+	}
+	return s, nil
+}
+
+// Update loads the chore state, applies fn and saves it, holding a lock
+// so the daemon and gt don't overwrite each other's records.
+func Update(townRoot string, fn func(*State)) error {
+	path := statePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking chore state: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	s, err := LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	fn(s)
+	return util.AtomicWriteJSON(path, s)
+}
+
+// Next returns when c is next due: its first scheduled time after the last
+// run, or after s.Since if it has never run. It is the zero time for a
+// chore that is off, or before chores have been scheduled.
+func (s *State) Next(c Scheduled) time.Time {
+	if c.Schedule == nil || s.Since.IsZero() {
+		return time.Time{}
+	}
+	from := s.Since
+	if r := s.Runs[c.Name]; r != nil && r.At.After(from) {
+		from = r.At
+	}
+	return c.Schedule.Next(from)
+}
+
+// Due reports whether c should run at now. A heartbeat that misses a
+// scheduled time (the daemon was down) runs the chore once when it wakes.
+func (s *State) Due(c Scheduled, now time.Time) bool {
+	next := s.Next(c)
+	return !next.IsZero() && !next.After(now)
+}
@@ -0,0 +1,141 @@
+package chores
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		expr  string
+		after string
+		want  string
+	}{
+		{"0 3 * * *", "2026-01-07 02:00", "2026-01-07 03:00"},
+		{"0 3 * * *", "2026-01-07 03:00", "2026-01-08 03:00"},
+		{"*/30 * * * *", "2026-01-07 10:07", "2026-01-07 10:30"},
+		{"15 9-17/4 * * *", "2026-01-07 14:00", "2026-01-07 17:15"},
+		{"0 0 * * 0", "2026-01-07 12:00", "2026-01-11 00:00"}, // next Sunday
+		{"0 0 * * 7", "2026-01-07 12:00", "2026-01-11 00:00"},
+		{"0 0 1 * 1", "2026-01-07 12:00", "2026-01-12 00:00"}, // Monday before the 1st
+		{"@weekly", "2026-01-07 12:00", "2026-01-11 00:00"},
+		{"0 12 29 2 *", "2026-03-01 00:00", "2028-02-29 12:00"},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		if got := s.Next(at(tt.after)); !got.Equal(at(tt.want)) {
+			t.Errorf("%q after %s = %s, want %s", tt.expr, tt.after, got.Format("2006-01-02 15:04"), tt.want)
+		}
+	}
+
+	never, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := never.Next(at("2026-01-01 00:00")); !got.IsZero() {
+		t.Errorf("30 February fired at %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	got, err := Resolve(&config.ChoresConfig{Schedule: map[string]string{BdSync: "@hourly", BranchPrune: "off"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(Builtins) {
+		t.Fatalf("resolved %d chores, want %d", len(got), len(Builtins))
+	}
+	for _, c := range got {
+		switch c.Name {
+		case BdSync:
+			if c.Schedule.String() != "@hourly" {
+				t.Errorf("bd-sync schedule = %s", c.Schedule)
+			}
+		case BranchPrune:
+			if c.Schedule != nil {
+				t.Errorf("branch-prune should be off, got %s", c.Schedule)
+			}
+		default:
+			if c.Schedule == nil || c.Schedule.String() != c.Default {
+				t.Errorf("%s schedule = %v, want default %s", c.Name, c.Schedule, c.Default)
+			}
+		}
+	}
+
+	if _, err := Resolve(&config.ChoresConfig{Schedule: map[string]string{"bd-synk": "@daily"}}); err == nil || !strings.Contains(err.Error(), "bd-synk") {
+		t.Errorf("unknown chore error = %v", err)
+	}
+}
+
+func TestStateDue(t *testing.T) {
+	sched, _ := Parse("0 3 * * *")
+	c := Scheduled{Chore: Chore{Name: BdSync}, Schedule: sched}
+
+	s := &State{Runs: map[string]*Run{}}
+	if s.Due(c, at("2026-01-08 04:00")) {
+		t.Error("due before chores were scheduled")
+	}
+
+	// Turned on mid-morning: not due until the next night
+	s.Since = at("2026-01-07 10:00")
+	if s.Due(c, at("2026-01-07 23:00")) {
+		t.Error("due before its first scheduled time")
+	}
+	if !s.Due(c, at("2026-01-08 03:02")) {
+		t.Error("not due after its scheduled time")
+	}
+
+	s.Runs[BdSync] = &Run{At: at("2026-01-08 03:02"), OK: true}
+	if s.Due(c, at("2026-01-08 12:00")) {
+		t.Error("due again after running")
+	}
+	// The daemon was down all of the next night: runs once on waking
+	if !s.Due(c, at("2026-01-10 09:00")) {
+		t.Error("missed run not due")
+	}
+
+	off := Scheduled{Chore: Chore{Name: BranchPrune}}
+	if s.Due(off, at("2027-01-01 00:00")) || !s.Next(off).IsZero() {
+		t.Error("chore that is off is due")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	townRoot := t.TempDir()
+	now := at("2026-01-07 10:00")
+	if err := Update(townRoot, func(s *State) { s.Since = now }); err != nil {
+		t.Fatal(err)
+	}
+	if err := Update(townRoot, func(s *State) { s.Runs[HookVerify] = &Run{At: now, OK: true} }); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Since.Equal(now) || s.Runs[HookVerify] == nil || !s.Runs[HookVerify].OK {
+		t.Errorf("state = %+v", s)
+	}
+}
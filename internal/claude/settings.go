@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/dryrun"
 )
 
 //go:embed config/*.json
@@ -54,7 +56,7 @@ func EnsureSettingsAt(workDir string, roleType RoleType, settingsDir, settingsFi
 	}
 
 	// Create settings directory if needed
-	if err := os.MkdirAll(claudeDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(claudeDir, 0755); err != nil {
 		return fmt.Errorf("creating settings directory: %w", err)
 	}
 
@@ -74,7 +76,7 @@ func EnsureSettingsAt(workDir string, roleType RoleType, settingsDir, settingsFi
 	}
 
 	// Write settings file
-	if err := os.WriteFile(settingsPath, content, 0600); err != nil {
+	if err := dryrun.WriteFile(settingsPath, content, 0600); err != nil {
 		return fmt.Errorf("writing settings: %w", err)
 	}
 
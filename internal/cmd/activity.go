@@ -27,14 +27,17 @@ var (
 
 var activityCmd = &cobra.Command{
 	Use:     "activity",
+	Aliases: []string{"events"},
 	GroupID: GroupDiag,
 	Short:   "Emit and view activity events",
 	Long: `Emit and view activity events for the Gas Town activity feed.
 
-Events are written to ~/gt/.events.jsonl and can be viewed with 'gt feed'.
+Events are written to ~/gt/.events.jsonl and can be viewed with 'gt feed'
+or, raw and filterable, with 'gt activity tail' (alias: 'gt events tail').
 
 Subcommands:
-  emit    Emit an activity event`,
+  emit    Emit an activity event
+  tail    Show and follow the raw event log`,
 }
 
 var activityEmitCmd = &cobra.Command{
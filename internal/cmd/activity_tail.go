@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/format"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tui/feed"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Activity tail command flags
+var (
+	activityTailFollow  bool
+	activityTailLines   int
+	activityTailSince   time.Duration
+	activityTailFilters []string
+	activityTailJSON    bool
+)
+
+var activityTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Show and follow the raw town event log",
+	Long: `Show recent events from ~/gt/.events.jsonl, optionally following new ones.
+
+Every subsystem publishes to this log: slings, spawns, molecule steps,
+refinery merges, escalations, patrols, session deaths. Use it to find out
+what the town did overnight.
+
+Filters are key=value conditions (repeat --filter or comma-separate):
+  type=<prefix>   Event type prefix (type=merge matches merged, merge_failed, ...)
+  actor=<glob>    Actor, e.g. actor=gastown/polecats/*
+  <field>=<glob>  Any payload field, e.g. rig=gastown, bead=gt-abc
+
+Examples:
+  gt events tail
+  gt events tail --follow --filter type=merge
+  gt events tail --since 12h --filter type=escalation,severity=high
+  gt events tail -n 0 -f --json | jq .`,
+	Args: cobra.NoArgs,
+	RunE: runActivityTail,
+}
+
+func init() {
+	activityTailCmd.Flags().BoolVarP(&activityTailFollow, "follow", "f", false, "Keep streaming new events")
+	activityTailCmd.Flags().IntVarP(&activityTailLines, "lines", "n", 20, "Number of recent events to show (-1 for all)")
+	activityTailCmd.Flags().DurationVar(&activityTailSince, "since", 0, "Only events newer than this (e.g., 30m, 12h)")
+	activityTailCmd.Flags().StringArrayVar(&activityTailFilters, "filter", nil, "Filter condition key=value (repeatable)")
+	activityTailCmd.Flags().BoolVar(&activityTailJSON, "json", false, "Output raw JSON lines")
+
+	activityCmd.AddCommand(activityTailCmd)
+}
+
+func runActivityTail(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	filter, err := events.ParseFilter(activityTailFilters)
+	if err != nil {
+		return err
+	}
+
+	opts := events.TailOptions{
+		Lines:  activityTailLines,
+		Follow: activityTailFollow,
+		Filter: filter,
+	}
+	if activityTailSince > 0 {
+		opts.Since = time.Now().Add(-activityTailSince)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	enc := json.NewEncoder(os.Stdout)
+	return events.Tail(ctx, townRoot, opts, func(e *events.Event) {
+		if activityTailJSON {
+			_ = enc.Encode(e)
+			return
+		}
+		fmt.Println(formatEventLine(e))
+	})
+}
+
+// formatEventLine renders an event as a single human-readable line.
+func formatEventLine(e *events.Event) string {
+	ts := e.Timestamp
+	if t, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+		ts = format.DateTimeSeconds(t)
+	}
+
+	symbol := feed.EventSymbols[e.Type]
+	if symbol == "" {
+		symbol = "·"
+	}
+
+	keys := make([]string, 0, len(e.Payload))
+	for k := range e.Payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := e.Payload[k]
+		if s, ok := v.(string); ok && strings.ContainsAny(s, " \t") {
+			v = fmt.Sprintf("%q", s)
+		}
+		fields = append(fields, fmt.Sprintf("%s=%v", k, v))
+	}
+
+	return fmt.Sprintf("%s %s %s %s %s",
+		style.Dim.Render(ts), symbol, style.Bold.Render(fmt.Sprintf("%-16s", e.Type)), e.Actor, style.Dim.Render(strings.Join(fields, " ")))
+}
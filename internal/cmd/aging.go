@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/aging"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// agingRunInterval is how often 'gt aging run' does anything without
+// --force. The patrol loops much faster than beads age.
+const agingRunInterval = 6 * time.Hour
+
+// agingLastRunFile records the last aging run within .runtime.
+const agingLastRunFile = "aging-last-run"
+
+var (
+	agingStatusJSON bool
+	agingRunForce   bool
+)
+
+var agingCmd = &cobra.Command{
+	Use:     "aging",
+	GroupID: GroupWork,
+	Short:   "Raise priority of neglected beads and flag stale blockers",
+	Long: `Apply the bead aging policy across the town and every rig.
+
+Two rules:
+  Escalation      A ready bead (open, unassigned, dependencies closed) with no
+                  update for bump_after_days has its priority raised by one,
+                  but not past max_priority. Each raise restarts the clock.
+  Stale blockers  An unblocked bead with no update for stale_after_days that
+                  holds up blocked_threshold or more open beads is labeled
+                  aging:stale-blocker and reported to the Mayor.
+
+Enable and tune it in ~/gt/settings/config.json:
+
+  "aging": {
+    "bump_after_days": 3,
+    "max_priority": 1,
+    "stale_after_days": 2,
+    "blocked_threshold": 3
+  }
+
+The Deacon runs 'gt aging run' on patrol; it acts at most every 6 hours.
+
+Examples:
+  gt aging status          # What the policy would change now
+  gt aging run             # Apply it (Deacon patrol)
+  gt aging run --force     # Apply it now, even if it ran recently`,
+	RunE: requireSubcommand,
+}
+
+var agingStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show what the aging policy would change",
+	Args:  cobra.NoArgs,
+	RunE:  runAgingStatus,
+}
+
+var agingRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Apply the aging policy",
+	Args:  cobra.NoArgs,
+	RunE:  runAgingRun,
+}
+
+func init() {
+	agingStatusCmd.Flags().BoolVar(&agingStatusJSON, "json", false, "Output as JSON")
+	agingRunCmd.Flags().BoolVar(&agingRunForce, "force", false, "Run even if the policy ran within the last 6 hours")
+	agingCmd.AddCommand(agingStatusCmd)
+	agingCmd.AddCommand(agingRunCmd)
+	rootCmd.AddCommand(agingCmd)
+}
+
+// agingResult is the policy's report for one beads database.
+type agingResult struct {
+	Location string        `json:"location"`
+	Report   *aging.Report `json:"report"`
+	b        *beads.Beads
+}
+
+// evaluateAging runs the policy over every beads database in the town.
+func evaluateAging(townRoot string, cfg *config.AgingConfig) []agingResult {
+	policy := aging.NewPolicy(cfg)
+	now := time.Now()
+	var results []agingResult
+	for _, location := range townBeadsLocations(townRoot) {
+		b := beads.New(location)
+		issues, err := b.List(beads.ListOptions{Status: "all", Priority: -1})
+		if err != nil {
+			style.PrintWarning("skipping %s: %v", location, err)
+			continue
+		}
+		rel, _ := filepath.Rel(townRoot, location)
+		if rel == "." {
+			rel = "town"
+		}
+		results = append(results, agingResult{Location: rel, Report: policy.Evaluate(issues, now), b: b})
+	}
+	return results
+}
+
+func loadAgingConfig(townRoot string) (*config.AgingConfig, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return settings.Aging, nil
+}
+
+func runAgingStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := loadAgingConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	results := evaluateAging(townRoot, cfg)
+
+	if agingStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if cfg == nil {
+		fmt.Printf("%s Aging is off (add \"aging\": {} to settings/config.json); showing the defaults\n\n", style.Dim.Render("○"))
+	}
+	printAgingResults(results)
+	return nil
+}
+
+func runAgingRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := loadAgingConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		fmt.Printf("%s Aging is off (add \"aging\": {} to settings/config.json)\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	stamp := filepath.Join(townRoot, ".runtime", agingLastRunFile)
+	if info, err := os.Stat(stamp); err == nil && !agingRunForce && time.Since(info.ModTime()) < agingRunInterval {
+		fmt.Printf("%s Aging ran %s ago; next run after %s (--force to run now)\n", style.Dim.Render("○"),
+			time.Since(info.ModTime()).Round(time.Minute), info.ModTime().Add(agingRunInterval).Format("15:04"))
+		return nil
+	}
+
+	results := evaluateAging(townRoot, cfg)
+	var newBlockers []aging.Blocker
+	for _, res := range results {
+		if res.Report.Empty() {
+			continue
+		}
+		if err := aging.Apply(res.b, res.Report); err != nil {
+			style.PrintWarning("%s: %v", res.Location, err)
+			continue
+		}
+		for _, bump := range res.Report.Bumps {
+			_ = events.LogFeed(events.TypePriorityBumped, "deacon", events.PriorityBumpPayload(bump.ID, bump.From, bump.To, agingDays(bump.Idle)))
+		}
+		for _, blocker := range res.Report.Blockers {
+			if blocker.New {
+				_ = events.LogFeed(events.TypeStaleBlocker, "deacon", events.StaleBlockerPayload(blocker.ID, blocker.Assignee, blocker.Downstream, agingDays(blocker.Idle)))
+				newBlockers = append(newBlockers, blocker)
+			}
+		}
+	}
+	printAgingResults(results)
+
+	if len(newBlockers) > 0 {
+		if err := mailStaleBlockers(townRoot, newBlockers); err != nil {
+			style.PrintWarning("could not mail the Mayor: %v", err)
+		}
+	}
+
+	_ = os.MkdirAll(filepath.Dir(stamp), 0755)
+	return os.WriteFile(stamp, []byte(time.Now().UTC().Format(time.RFC3339)+"\n"), 0644) //nolint:gosec // G306: timestamp only
+}
+
+// mailStaleBlockers tells the Mayor about newly flagged stale blockers,
+// since unblocking a chain usually needs someone to reassign or split work.
+func mailStaleBlockers(townRoot string, blockers []aging.Blocker) error {
+	var body strings.Builder
+	body.WriteString("These beads have gone stale while other work waits on them:\n\n")
+	for _, b := range blockers {
+		owner := b.Assignee
+		if owner == "" {
+			owner = "unassigned"
+		}
+		fmt.Fprintf(&body, "- %s %s (%s, idle %dd) blocks %d: %s\n", b.ID, b.Title, owner, agingDays(b.Idle), len(b.Downstream), strings.Join(b.Downstream, ", "))
+	}
+	body.WriteString("\nReassign, split or unblock them; 'gt aging status' lists them all.")
+
+	return mail.NewRouter(townRoot).Send(&mail.Message{
+		From:     "deacon/",
+		To:       "mayor/",
+		Subject:  fmt.Sprintf("⛓ %d stale blocker(s) holding up work", len(blockers)),
+		Body:     body.String(),
+		Type:     mail.TypeNotification,
+		Priority: mail.PriorityHigh,
+	})
+}
+
+func printAgingResults(results []agingResult) {
+	changed := false
+	for _, res := range results {
+		r := res.Report
+		if r.Empty() {
+			continue
+		}
+		changed = true
+		fmt.Printf("%s %s\n", style.Bold.Render("●"), res.Location)
+		for _, bump := range r.Bumps {
+			fmt.Printf("  ↑ %-14s P%d → P%d  %s  %s\n", bump.ID, bump.From, bump.To, bump.Title,
+				style.Dim.Render(fmt.Sprintf("(ready %dd)", agingDays(bump.Idle))))
+		}
+		for _, b := range r.Blockers {
+			fmt.Printf("  %s %-14s blocks %d  %s  %s\n", style.Warning.Render("⛓"), b.ID, len(b.Downstream), b.Title,
+				style.Dim.Render(fmt.Sprintf("(idle %dd)", agingDays(b.Idle))))
+		}
+		for _, id := range r.Cleared {
+			fmt.Printf("  %s %-14s no longer a stale blocker\n", style.Dim.Render("✓"), id)
+		}
+	}
+	if !changed {
+		fmt.Printf("%s Nothing to age: no neglected ready beads or stale blockers\n", style.Dim.Render("○"))
+	}
+}
+
+// agingDays rounds an idle duration down to whole days.
+func agingDays(d time.Duration) int {
+	return int(d.Hours() / 24)
+}
@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	approveNote    string
+	rejectFeedback string
+	reviewJSON     bool
+)
+
+var approveCmd = &cobra.Command{
+	Use:     "approve <run-id> <step>",
+	GroupID: GroupWork,
+	Short:   "Approve a molecule step that waits for a human",
+	Long: `Approve a human approval gate in a running molecule.
+
+A molecule step declared with "Gate: human" pauses the molecule: the agent
+that reaches it stops and waits. Approving closes the gate step, pins the
+next step to the waiting agent and wakes it with mail and a nudge.
+
+<run-id> is the molecule instance (the parent of its steps). <step> is the
+gate's step ID (gt-abc.3), its number (3) or its step ref (review).
+
+Examples:
+  gt review                           # Gates waiting for a decision
+  gt approve gt-abc review            # Approve the "review" gate
+  gt approve gt-abc 3 -m "LGTM"       # Approve with a note for the agent`,
+	Args: cobra.ExactArgs(2),
+	RunE: runApprove,
+}
+
+var rejectCmd = &cobra.Command{
+	Use:     "reject <run-id> <step>",
+	GroupID: GroupWork,
+	Short:   "Send a gated molecule step back to the agent with feedback",
+	Long: `Reject a human approval gate, feeding the reviewer's feedback to the agent.
+
+The steps the gate depends on are reopened with the feedback appended to
+their description, the first is pinned back to the waiting agent, and the
+agent is woken with the feedback. When it finishes the rework it reaches
+the gate again and waits for another review.
+
+Examples:
+  gt reject gt-abc review --feedback "Missing tests for the error path"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runReject,
+}
+
+var reviewCmd = &cobra.Command{
+	Use:     "review",
+	GroupID: GroupWork,
+	Short:   "List molecule steps waiting for human approval",
+	Long: `List human approval gates that an agent is waiting on, across the
+town and every rig.
+
+Decide each with 'gt approve <run-id> <step>' or
+'gt reject <run-id> <step> --feedback "..."'.`,
+	Args: cobra.NoArgs,
+	RunE: runReview,
+}
+
+func init() {
+	approveCmd.Flags().StringVarP(&approveNote, "message", "m", "", "Note passed to the agent with the approval")
+	rejectCmd.Flags().StringVarP(&rejectFeedback, "feedback", "f", "", "What the agent must change (required)")
+	_ = rejectCmd.MarkFlagRequired("feedback")
+	reviewCmd.Flags().BoolVar(&reviewJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(approveCmd)
+	rootCmd.AddCommand(rejectCmd)
+	rootCmd.AddCommand(reviewCmd)
+}
+
+// PendingGate is a human approval gate an agent is waiting on.
+type PendingGate struct {
+	RunID   string `json:"run_id"`
+	StepID  string `json:"step_id"`
+	StepRef string `json:"step_ref,omitempty"`
+	Title   string `json:"title"`
+	Agent   string `json:"agent"`
+	Since   string `json:"since,omitempty"`
+}
+
+// gateContext is a resolved gate and the beads database it lives in.
+type gateContext struct {
+	townRoot string
+	runID    string
+	b        *beads.Beads
+	gate     *beads.Issue
+	steps    []*beads.Issue
+}
+
+// resolveGate finds the human gate <step> of molecule runID.
+func resolveGate(runID, step string) (*gateContext, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cwd, _ := os.Getwd()
+	b := beads.New(beads.ResolveHookDir(townRoot, runID, cwd))
+
+	steps, err := b.List(beads.ListOptions{Parent: runID, Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing steps of %s: %w", runID, err)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("%s has no steps (expected a molecule instance)", runID)
+	}
+
+	var gate *beads.Issue
+	for _, s := range steps {
+		if s.ID == step || s.ID == runID+"."+step || beads.StepRef(s) == step {
+			gate = s
+			break
+		}
+	}
+	if gate == nil {
+		return nil, fmt.Errorf("no step %q in %s", step, runID)
+	}
+	if beads.StepGate(gate) != beads.GateHuman {
+		return nil, fmt.Errorf("step %s is not a human approval gate", gate.ID)
+	}
+	if gate.Status == "closed" {
+		return nil, fmt.Errorf("gate %s is already approved", gate.ID)
+	}
+	return &gateContext{townRoot: townRoot, runID: runID, b: b, gate: gate, steps: steps}, nil
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	gc, err := resolveGate(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	reviewer := reviewerName()
+	agentID := gc.gate.Assignee
+
+	reason := "approved by " + reviewer
+	if approveNote != "" {
+		reason += ": " + approveNote
+	}
+	if err := gc.b.CloseWithReason(reason, gc.gate.ID); err != nil {
+		return fmt.Errorf("closing gate: %w", err)
+	}
+	_ = events.LogFeed(events.TypeGateApproved, reviewer, events.GatePayload(gc.runID, gc.gate.ID, gc.gate.Title, agentID, approveNote))
+	fmt.Printf("%s Approved %s: %s\n", style.Bold.Render("✓"), gc.gate.ID, gc.gate.Title)
+
+	if agentID == "" {
+		fmt.Printf("  %s No agent is waiting on this gate; the molecule continues when its agent gets here\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	next, complete, err := findNextReadyStep(gc.b, gc.runID)
+	if err != nil {
+		return fmt.Errorf("finding next step: %w", err)
+	}
+
+	var instructions string
+	switch {
+	case complete:
+		instructions = "That was the last step of the molecule. Finish up with 'gt done'."
+	case next == nil:
+		instructions = fmt.Sprintf("The remaining steps are blocked; check 'gt mol progress %s'.", gc.runID)
+	case beads.StepGate(next) == beads.GateHuman:
+		if err := parkAtGate(gc.b, gc.runID, next, agentID); err != nil {
+			return err
+		}
+		fmt.Printf("  %s Next step %s is another approval gate\n", style.Dim.Render("○"), next.ID)
+		instructions = fmt.Sprintf("The next step, %s (%s), also needs approval. Keep waiting.", next.ID, next.Title)
+	default:
+		status := "pinned"
+		if err := gc.b.Update(next.ID, beads.UpdateOptions{Status: &status, Assignee: &agentID}); err != nil {
+			return fmt.Errorf("pinning next step: %w", err)
+		}
+		fmt.Printf("%s Next step pinned to %s: %s\n", style.Bold.Render("📌"), agentID, next.ID)
+		instructions = fmt.Sprintf("Continue with the next step, %s: %s (run 'gt prime' to load it).", next.ID, next.Title)
+	}
+
+	body := fmt.Sprintf("%s approved %s (%s).\n", reviewer, gc.gate.ID, gc.gate.Title)
+	if approveNote != "" {
+		body += "\nNote: " + approveNote + "\n"
+	}
+	body += "\n" + instructions
+	wakeGateAgent(gc.townRoot, agentID, "✅ APPROVED: "+gc.gate.Title, body,
+		fmt.Sprintf("Gate %s approved by %s. %s", gc.gate.ID, reviewer, instructions))
+	return nil
+}
+
+func runReject(cmd *cobra.Command, args []string) error {
+	feedback := strings.TrimSpace(rejectFeedback)
+	if feedback == "" {
+		return fmt.Errorf("--feedback is required: tell the agent what to change")
+	}
+	gc, err := resolveGate(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	reviewer := reviewerName()
+	agentID := gc.gate.Assignee
+
+	byID := make(map[string]*beads.Issue, len(gc.steps))
+	for _, s := range gc.steps {
+		byID[s.ID] = s
+	}
+
+	// Reopen the gated work with the feedback attached
+	note := fmt.Sprintf("\n\n## Review feedback (%s, %s)\n\n%s", reviewer, time.Now().Format("2006-01-02 15:04"), feedback)
+	var reopened []string
+	for _, depID := range gc.gate.DependsOn {
+		dep := byID[depID]
+		if dep == nil || dep.Status != "closed" {
+			continue
+		}
+		desc := dep.Description + note
+		status := "open"
+		opts := beads.UpdateOptions{Status: &status, Description: &desc}
+		if len(reopened) == 0 && agentID != "" {
+			status = "pinned"
+			opts.Assignee = &agentID
+		}
+		if err := gc.b.Update(dep.ID, opts); err != nil {
+			return fmt.Errorf("reopening %s: %w", dep.ID, err)
+		}
+		reopened = append(reopened, dep.ID)
+	}
+	if err := gc.b.Update(gc.gate.ID, beads.UpdateOptions{RemoveLabels: []string{beads.LabelGateAwaiting}}); err != nil {
+		return fmt.Errorf("updating gate: %w", err)
+	}
+	_ = events.LogFeed(events.TypeGateRejected, reviewer, events.GatePayload(gc.runID, gc.gate.ID, gc.gate.Title, agentID, feedback))
+
+	fmt.Printf("%s Rejected %s: %s\n", style.Bold.Render("✗"), gc.gate.ID, gc.gate.Title)
+	if len(reopened) > 0 {
+		fmt.Printf("  Reopened with feedback: %s\n", strings.Join(reopened, ", "))
+	}
+	if agentID == "" {
+		fmt.Printf("  %s No agent is waiting on this gate; the feedback is on the reopened steps\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	instructions := "Address the feedback, then reach the gate again for another review."
+	if len(reopened) > 0 {
+		instructions = fmt.Sprintf("%s is pinned to you again with the feedback appended. Address it, then run 'gt mol step done %s' to return to review.", reopened[0], reopened[0])
+	}
+	body := fmt.Sprintf("%s rejected %s (%s).\n\nFeedback:\n%s\n\n%s", reviewer, gc.gate.ID, gc.gate.Title, feedback, instructions)
+	wakeGateAgent(gc.townRoot, agentID, "↩️ CHANGES REQUESTED: "+gc.gate.Title, body,
+		fmt.Sprintf("Gate %s rejected by %s: %s. %s", gc.gate.ID, reviewer, feedback, instructions))
+	return nil
+}
+
+// parkAtGate marks a gate as awaiting approval for agentID and announces it.
+func parkAtGate(b *beads.Beads, moleculeID string, gate *beads.Issue, agentID string) error {
+	if err := b.Update(gate.ID, beads.UpdateOptions{
+		Assignee:  &agentID,
+		AddLabels: []string{beads.LabelGateAwaiting},
+	}); err != nil {
+		return fmt.Errorf("marking gate as awaiting approval: %w", err)
+	}
+	_ = events.LogFeed(events.TypeGateAwaiting, agentID, events.GatePayload(moleculeID, gate.ID, gate.Title, agentID, ""))
+	return nil
+}
+
+// wakeGateAgent tells the agent waiting on a gate about the decision: by
+// mail, which survives a restart, and by nudge if its session is running.
+func wakeGateAgent(townRoot, agentID, subject, body, nudge string) {
+	address := strings.TrimSuffix(agentID, "/")
+	msg := &mail.Message{
+		From:     "overseer",
+		To:       agentID,
+		Subject:  subject,
+		Body:     body,
+		Type:     mail.TypeNotification,
+		Priority: mail.PriorityHigh,
+	}
+	if err := mail.NewRouter(townRoot).Send(msg); err != nil {
+		style.PrintWarning("could not mail %s: %v", agentID, err)
+	} else {
+		fmt.Printf("  Mailed %s\n", agentID)
+	}
+
+	_, sessionName, err := agentAddressToIDs(address)
+	if err != nil {
+		return
+	}
+	t := tmux.NewTmux()
+	if ok, _ := t.HasSession(sessionName); !ok {
+		return
+	}
+	if err := t.NudgeSession(sessionName, nudge); err != nil {
+		style.PrintWarning("could not nudge %s: %v", sessionName, err)
+		return
+	}
+	fmt.Printf("  Nudged %s\n", sessionName)
+}
+
+// townBeadsLocations returns the town root and every rig with a beads
+// database, sorted by rig name.
+func townBeadsLocations(townRoot string) []string {
+	locations := []string{townRoot}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil {
+		return locations
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rigPath := filepath.Join(townRoot, name)
+		if _, err := os.Stat(filepath.Join(rigPath, constants.DirBeads)); err == nil {
+			locations = append(locations, rigPath)
+		}
+	}
+	return locations
+}
+
+// reviewerName identifies who decided a gate: the agent when run by one,
+// otherwise the local user.
+func reviewerName() string {
+	if os.Getenv("GT_ROLE") != "" {
+		return detectActor()
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "overseer"
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	pending := []PendingGate{}
+	seen := make(map[string]bool)
+	for _, location := range townBeadsLocations(townRoot) {
+		issues, err := beads.New(location).List(beads.ListOptions{Label: beads.LabelGateAwaiting, Status: "open", Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range issues {
+			if seen[issue.ID] {
+				continue
+			}
+			seen[issue.ID] = true
+			pending = append(pending, PendingGate{
+				RunID:   extractMoleculeIDFromStep(issue.ID),
+				StepID:  issue.ID,
+				StepRef: beads.StepRef(issue),
+				Title:   issue.Title,
+				Agent:   issue.Assignee,
+				Since:   issue.UpdatedAt,
+			})
+		}
+	}
+
+	if reviewJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pending)
+	}
+
+	if len(pending) == 0 {
+		fmt.Printf("%s No steps waiting for approval\n", style.Dim.Render("○"))
+		return nil
+	}
+	fmt.Printf("%s Waiting for approval: %d\n\n", style.Bold.Render("✋"), len(pending))
+	for _, g := range pending {
+		step := g.StepID
+		if g.StepRef != "" {
+			step = g.StepRef
+		}
+		fmt.Printf("  %s  %s\n", style.Bold.Render(g.StepID), g.Title)
+		fmt.Printf("    agent: %s\n", g.Agent)
+		fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("gt approve %s %s  |  gt reject %s %s --feedback \"...\"", g.RunID, step, g.RunID, step)))
+	}
+	return nil
+}
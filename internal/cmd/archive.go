@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/archive"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/format"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	archiveAfterDays int
+	archiveJSON      bool
+	archiveOutput    string
+)
+
+var archiveCmd = &cobra.Command{
+	Use:     "archive",
+	GroupID: GroupDiag,
+	Short:   "Offload old transcripts, artifacts and events to remote storage",
+	Long: `Move old polecat transcripts, step artifacts and event log entries out of
+the town into remote storage, keeping the harness small.
+
+The archive is configured in settings/config.json:
+
+  "archive": {"url": "s3://my-bucket/gastown", "after_days": 30}
+
+The URL may be s3://bucket/prefix, gs://bucket/prefix, az://container/prefix
+(storage account from AZURE_STORAGE_ACCOUNT) or a local directory such as a
+mounted share. The cloud stores use the aws, gcloud or az CLI and their
+credentials.
+
+Archived files are recorded in .logs/archive.jsonl. 'gt logs' and
+'gt artifacts get' fetch an archived transcript or artifact back
+transparently. The archive chore runs 'gt archive run' nightly.
+
+COMMANDS:
+  run     Archive items older than the configured age
+  list    List archived items
+  get     Print an archived file`,
+	RunE: requireSubcommand,
+}
+
+var archiveRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Archive items older than the configured age",
+	Long: `Archive the transcripts and artifacts last written more than after_days
+ago, and the events older than that, removing the local copies.
+
+Examples:
+  gt archive run
+  gt archive run --after-days 7`,
+	Args: cobra.NoArgs,
+	RunE: runArchiveRun,
+}
+
+var archiveListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List archived items",
+	Args:  cobra.NoArgs,
+	RunE:  runArchiveList,
+}
+
+var archiveGetCmd = &cobra.Command{
+	Use:   "get <path|key>",
+	Short: "Print an archived file",
+	Long: `Print the latest archived copy of a file, named by its path in the town
+(as 'gt archive list' shows it) or its archive key.
+
+Examples:
+  gt archive get .logs/transcripts/gt-abc.log
+  gt archive get .logs/events/events-20261001-030000.jsonl -o old-events.jsonl`,
+	Args: cobra.ExactArgs(1),
+	RunE: runArchiveGet,
+}
+
+func init() {
+	archiveRunCmd.Flags().IntVar(&archiveAfterDays, "after-days", 0, "Archive items older than this many days (default: archive.after_days)")
+	archiveListCmd.Flags().BoolVar(&archiveJSON, "json", false, "Output as JSON")
+	archiveGetCmd.Flags().StringVarP(&archiveOutput, "output", "o", "", "Write to this file instead of stdout")
+	archiveCmd.AddCommand(archiveRunCmd, archiveListCmd, archiveGetCmd)
+	rootCmd.AddCommand(archiveCmd)
+}
+
+// loadArchive returns the town's archive store and config, or a nil store
+// if no archive is configured.
+func loadArchive(townRoot string) (archive.Store, *config.ArchiveConfig, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Archive == nil || settings.Archive.URL == "" {
+		return nil, nil, nil
+	}
+	store, err := archive.Open(settings.Archive.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return store, settings.Archive, nil
+}
+
+// fetchArchived returns the archived contents of a town file whose local
+// copy is gone, wrapping archive.ErrNotArchived when there are none.
+func fetchArchived(townRoot, path string) ([]byte, error) {
+	item, err := archive.Find(townRoot, path)
+	if err != nil {
+		return nil, err
+	}
+	store, _, err := loadArchive(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, fmt.Errorf("%s is archived but no archive is configured", item.Path)
+	}
+	return archive.Fetch(store, item)
+}
+
+// archiveNow archives the town's old items, returning a summary.
+func archiveNow(townRoot string, afterDays int) (string, error) {
+	store, cfg, err := loadArchive(townRoot)
+	if err != nil {
+		return "", err
+	}
+	if store == nil {
+		return "skipped (no archive configured)", nil
+	}
+	if afterDays <= 0 {
+		afterDays = cfg.AfterDays
+	}
+	if afterDays <= 0 {
+		afterDays = archive.DefaultAfterDays
+	}
+	items, err := archive.Run(townRoot, store, time.Now().AddDate(0, 0, -afterDays))
+	var size int64
+	for _, item := range items {
+		size += item.Size
+	}
+	summary := fmt.Sprintf("archived %d file(s), %s", len(items), formatSize(size))
+	return summary, err
+}
+
+func choreArchive(townRoot string) (string, error) {
+	return archiveNow(townRoot, 0)
+}
+
+func runArchiveRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	summary, err := archiveNow(townRoot, archiveAfterDays)
+	if summary != "" {
+		fmt.Printf("%s %s\n", style.Success.Render("✓"), summary)
+	}
+	return err
+}
+
+func runArchiveList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	items, err := archive.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading archive index: %w", err)
+	}
+	if archiveJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+	if len(items) == 0 {
+		fmt.Printf("%s Nothing archived yet\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, item := range items {
+		fmt.Printf("%s  %-10s %8s  %s\n",
+			style.Dim.Render(format.DateTimeSeconds(item.Archived.Local())), item.Kind, formatSize(item.Size), item.Path)
+	}
+	return nil
+}
+
+func runArchiveGet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	data, err := fetchArchived(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if archiveOutput != "" {
+		return os.WriteFile(archiveOutput, data, 0644) //nolint:gosec // G306: user-chosen output file
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
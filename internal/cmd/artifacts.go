@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/archive"
+	"github.com/steveyegge/gastown/internal/artifacts"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	artifactsRun  string
+	artifactsStep string
+	artifactsJSON bool
+)
+
+var artifactsCmd = &cobra.Command{
+	Use:     "artifacts",
+	GroupID: GroupWork,
+	Short:   "Store and read the named outputs of molecule steps",
+	Long: `Keep the named outputs of molecule steps - a design doc, a test report,
+benchmark results - where later steps and humans can find them.
+
+Artifacts are files under <town>/.artifacts/<run>/<step>/<name>, where run
+is the molecule run (wisp root) ID and step the formula step ID. An agent
+working a molecule doesn't need to name either: put and get default to the
+molecule on its hook and the step it has in progress.
+
+COMMANDS:
+  put     Store an artifact for the current step
+  get     Print an artifact from a step of the current run
+  list    List a run's artifacts
+  show    Show a run's artifacts, or one artifact's contents`,
+	RunE: requireSubcommand,
+}
+
+var artifactsPutCmd = &cobra.Command{
+	Use:   "put <name> [file]",
+	Short: "Store an artifact for the current step",
+	Long: `Store an artifact from a file, or from stdin when no file (or -) is given.
+Storing a name again replaces the earlier artifact.
+
+Examples:
+  gt artifacts put design.md docs/design.md
+  go test ./... 2>&1 | gt artifacts put test-report.txt
+  gt artifacts put bench.json bench.json --run gt-wisp-abc --step bench`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runArtifactsPut,
+}
+
+var artifactsGetCmd = &cobra.Command{
+	Use:   "get <step>/<name>",
+	Short: "Print an artifact from a step of the current run",
+	Long: `Print an artifact stored by a step of the run - typically an earlier step
+whose output this one builds on.
+
+Examples:
+  gt artifacts get design/design.md
+  gt artifacts get test/test-report.txt --run gt-wisp-abc`,
+	Args: cobra.ExactArgs(1),
+	RunE: runArtifactsGet,
+}
+
+var artifactsListCmd = &cobra.Command{
+	Use:   "list [run]",
+	Short: "List a run's artifacts",
+	Long: `List the artifacts of a run (default: the molecule on your hook).
+
+Examples:
+  gt artifacts list
+  gt artifacts list gt-wisp-abc --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runArtifactsList,
+}
+
+var artifactsShowCmd = &cobra.Command{
+	Use:   "show <run> [<step>[/<name>]]",
+	Short: "Show a run's artifacts, or one artifact's contents",
+	Long: `Show a run's artifacts grouped by step, with the run's formula and status
+when it was recorded. Naming a step limits the list to it; naming an
+artifact prints its contents.
+
+Examples:
+  gt artifacts show gt-wisp-abc
+  gt artifacts show gt-wisp-abc design
+  gt artifacts show gt-wisp-abc design/design.md`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runArtifactsShow,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{artifactsPutCmd, artifactsGetCmd, artifactsListCmd} {
+		c.Flags().StringVar(&artifactsRun, "run", "", "Molecule run ID (default: the molecule on your hook)")
+	}
+	artifactsPutCmd.Flags().StringVar(&artifactsStep, "step", "", "Formula step ID (default: your in-progress step)")
+	artifactsListCmd.Flags().BoolVar(&artifactsJSON, "json", false, "Output as JSON")
+	artifactsShowCmd.Flags().BoolVar(&artifactsJSON, "json", false, "Output as JSON")
+
+	artifactsCmd.AddCommand(artifactsPutCmd)
+	artifactsCmd.AddCommand(artifactsGetCmd)
+	artifactsCmd.AddCommand(artifactsListCmd)
+	artifactsCmd.AddCommand(artifactsShowCmd)
+	rootCmd.AddCommand(artifactsCmd)
+}
+
+func runArtifactsPut(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	run, err := artifactRun(townRoot, artifactsRun)
+	if err != nil {
+		return err
+	}
+	step := artifactsStep
+	if step == "" {
+		if step, err = currentArtifactStep(run); err != nil {
+			return err
+		}
+	}
+
+	var data []byte
+	if len(args) < 2 || args[1] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[1])
+	}
+	if err != nil {
+		return fmt.Errorf("reading artifact: %w", err)
+	}
+
+	a, err := artifacts.Put(townRoot, run, step, args[0], data)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Stored %s/%s for run %s (%s)\n", style.Bold.Render("✓"), a.Step, a.Name, a.Run, formatSize(a.Size))
+	return nil
+}
+
+func runArtifactsGet(cmd *cobra.Command, args []string) error {
+	step, name, ok := strings.Cut(args[0], "/")
+	if !ok {
+		return fmt.Errorf("name the artifact as <step>/<name>")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	run, err := artifactRun(townRoot, artifactsRun)
+	if err != nil {
+		return err
+	}
+	data, err := artifacts.Read(townRoot, run, step, name)
+	if errors.Is(err, artifacts.ErrNotFound) {
+		path, _ := artifacts.Path(townRoot, run, step, name)
+		if archived, aerr := fetchArchived(townRoot, path); !errors.Is(aerr, archive.ErrNotArchived) {
+			data, err = archived, aerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func runArtifactsList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	runArg := artifactsRun
+	if len(args) > 0 {
+		runArg = args[0]
+	}
+	run, err := artifactRun(townRoot, runArg)
+	if err != nil {
+		return err
+	}
+	list, err := artifacts.List(townRoot, run)
+	if err != nil {
+		return err
+	}
+	if artifactsJSON {
+		if list == nil {
+			list = []artifacts.Artifact{}
+		}
+		return outputJSON(list)
+	}
+	if len(list) == 0 {
+		fmt.Printf("No artifacts for run %s\n", run)
+		return nil
+	}
+	for _, a := range list {
+		fmt.Printf("%s/%s  %s\n", a.Step, a.Name, style.Dim.Render(formatSize(a.Size)))
+	}
+	return nil
+}
+
+func runArtifactsShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	run := args[0]
+
+	var stepFilter string
+	if len(args) > 1 {
+		if step, name, ok := strings.Cut(args[1], "/"); ok {
+			data, err := artifacts.Read(townRoot, run, step, name)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		stepFilter = args[1]
+	}
+
+	list, err := artifacts.List(townRoot, run)
+	if err != nil {
+		return err
+	}
+	var shown []artifacts.Artifact
+	for _, a := range list {
+		if stepFilter == "" || a.Step == stepFilter {
+			shown = append(shown, a)
+		}
+	}
+	if artifactsJSON {
+		if shown == nil {
+			shown = []artifacts.Artifact{}
+		}
+		return outputJSON(shown)
+	}
+
+	header := run
+	if r, err := molruns.Load(townRoot, run); err == nil {
+		header = fmt.Sprintf("%s %s: %s (%s)", runStatusIcon(r.Status), r.ID, r.Formula, r.Status)
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render(header))
+	if len(shown) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no artifacts)"))
+		return nil
+	}
+	lastStep := ""
+	for _, a := range shown {
+		if a.Step != lastStep {
+			fmt.Printf("  %s\n", style.Bold.Render(a.Step))
+			lastStep = a.Step
+		}
+		fmt.Printf("    %-30s %8s  %s\n", a.Name, formatSize(a.Size), style.Dim.Render(a.Modified.Local().Format("2006-01-02 15:04")))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Print one with: gt artifacts show %s <step>/<name>", run)))
+	return nil
+}
+
+// artifactRun returns the run artifacts are for: the given ID, or the
+// molecule on the current agent's hook.
+func artifactRun(townRoot, run string) (string, error) {
+	if run != "" {
+		return run, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting current directory: %w", err)
+	}
+	roleInfo, err := GetRole()
+	if err != nil {
+		return "", fmt.Errorf("detecting role: %w", err)
+	}
+	hooked := detectHookedBead(cwd, roleInfo)
+	if hooked == "" {
+		return "", fmt.Errorf("nothing on your hook (use --run)")
+	}
+	if r, err := molruns.FindByHook(townRoot, hooked); err == nil && r != nil {
+		return r.ID, nil
+	}
+	// gt mol step done hooks each step in turn
+	if molecule := extractMoleculeIDFromStep(hooked); molecule != "" {
+		return molecule, nil
+	}
+	return hooked, nil
+}
+
+// currentArtifactStep returns the formula step ID of the step the current
+// agent has in progress in run.
+func currentArtifactStep(run string) (string, error) {
+	roleInfo, err := GetRole()
+	if err != nil {
+		return "", fmt.Errorf("detecting role: %w", err)
+	}
+	assignee := getAgentIdentity(RoleContext{Role: roleInfo.Role, Rig: roleInfo.Rig, Polecat: roleInfo.Polecat})
+	if assignee == "" {
+		return "", fmt.Errorf("cannot determine your in-progress step (use --step)")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting current directory: %w", err)
+	}
+	// A step is in progress, or pinned when gt mol step done moved to it
+	for _, status := range []string{"in_progress", beads.StatusPinned} {
+		issues, err := beads.New(cwd).List(beads.ListOptions{Status: status, Assignee: assignee, Priority: -1})
+		if err != nil {
+			return "", fmt.Errorf("listing in-progress steps: %w", err)
+		}
+		for _, issue := range issues {
+			if issue.Parent == run || strings.HasPrefix(issue.ID, run+".") {
+				return artifactStepName(issue), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no in-progress step of %s (use --step)", run)
+}
+
+// formatSize formats a byte count for display.
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/audit"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
@@ -39,6 +40,9 @@ Shows a unified timeline of work performed by an actor including:
   - Beads closed by the actor (via assignee)
   - Town log events (spawn, done, handoff, etc.)
   - Activity feed events
+  - State-changing gt commands (the command audit log)
+
+'gt audit tail' and 'gt audit export' read the command audit log alone.
 
 Examples:
   gt audit --actor=greenplace/crew/joe       # Show all work by joe
@@ -62,7 +66,7 @@ func init() {
 // AuditEntry represents a single entry in the audit log.
 type AuditEntry struct {
 	Timestamp time.Time `json:"timestamp"`
-	Source    string    `json:"source"` // "git", "beads", "townlog", "events"
+	Source    string    `json:"source"` // "git", "beads", "townlog", "events", "commands"
 	Type      string    `json:"type"`   // "commit", "bead_created", "bead_closed", "spawn", etc.
 	Actor     string    `json:"actor"`
 	Summary   string    `json:"summary"`
@@ -118,6 +122,13 @@ func runAudit(cmd *cobra.Command, args []string) error {
 	}
 	allEntries = append(allEntries, feedEntries...)
 
+	// 5. State-changing commands
+	commandEntries, err := collectCommandAudit(townRoot, auditActor, sinceTime)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read command audit log: %v\n", err)
+	}
+	allEntries = append(allEntries, commandEntries...)
+
 	// Sort by timestamp (newest first)
 	sort.Slice(allEntries, func(i, j int) bool {
 		return allEntries[i].Timestamp.After(allEntries[j].Timestamp)
@@ -445,6 +456,33 @@ func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, e
 	return entries, nil
 }
 
+// collectCommandAudit reads state-changing commands from the audit log.
+func collectCommandAudit(townRoot, actor string, since time.Time) ([]AuditEntry, error) {
+	records, err := audit.Read(townRoot, audit.Filter{Since: since})
+	if err != nil {
+		return nil, err
+	}
+	var entries []AuditEntry
+	for _, r := range records {
+		if actor != "" && !matchesActor(r.Actor, actor) {
+			continue
+		}
+		entry := AuditEntry{
+			Timestamp: r.Time,
+			Source:    "commands",
+			Type:      "command",
+			Actor:     r.Actor,
+			Summary:   "gt " + strings.Join(r.Args, " "),
+		}
+		if !r.OK() {
+			entry.Type = "command_failed"
+			entry.Details = r.Error
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
 // formatFeedSummary creates a readable summary from a feed event.
 func formatFeedSummary(e events.Event) string {
 	switch e.Type {
@@ -536,6 +574,8 @@ func formatSource(source string) string {
 		return style.Dim.Render("[log]")
 	case "events":
 		return style.Warning.Render("[events]")
+	case "commands":
+		return style.Bold.Render("[gt]")
 	default:
 		return fmt.Sprintf("[%s]", source)
 	}
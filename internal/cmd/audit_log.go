@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/audit"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/format"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Audit log command flags
+var (
+	auditLogLines   int
+	auditLogSince   string
+	auditLogActor   string
+	auditLogRole    string
+	auditLogCommand string
+	auditLogFailed  bool
+	auditLogJSON    bool
+	auditLogOutput  string
+)
+
+var auditTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Show recent state-changing commands",
+	Long: `Show the most recent entries of the command audit log (.audit.jsonl):
+every gt command that changed town state, who ran it and how it ended.
+
+Examples:
+  gt audit tail
+  gt audit tail -n 100 --role polecat
+  gt audit tail --command "rig remove" --since 7d
+  gt audit tail --failed --actor ana`,
+	Args: cobra.NoArgs,
+	RunE: runAuditTail,
+}
+
+var auditExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the command audit log as JSON",
+	Long: `Export command audit log entries as a JSON array, to stdout or a file.
+Takes the same filters as 'gt audit tail'.
+
+Examples:
+  gt audit export > audit.json
+  gt audit export --since 30d -o audit-march.json`,
+	Args: cobra.NoArgs,
+	RunE: runAuditExport,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{auditTailCmd, auditExportCmd} {
+		c.Flags().StringVar(&auditLogSince, "since", "", "Only entries newer than this (e.g., 1h, 24h, 7d)")
+		c.Flags().StringVar(&auditLogActor, "actor", "", "Filter by agent address or OS user (partial match)")
+		c.Flags().StringVar(&auditLogRole, "role", "", "Filter by role")
+		c.Flags().StringVar(&auditLogCommand, "command", "", "Filter by command, e.g. \"rig\" or \"rig remove\"")
+		c.Flags().BoolVar(&auditLogFailed, "failed", false, "Only commands that failed")
+	}
+	auditTailCmd.Flags().IntVarP(&auditLogLines, "lines", "n", 20, "Number of entries to show (-1 for all)")
+	auditTailCmd.Flags().BoolVar(&auditLogJSON, "json", false, "Output as JSON lines")
+	auditExportCmd.Flags().StringVarP(&auditLogOutput, "output", "o", "", "Write to this file instead of stdout")
+
+	auditCmd.AddCommand(auditTailCmd)
+	auditCmd.AddCommand(auditExportCmd)
+}
+
+// auditLogFilter builds the filter from the shared flags.
+func auditLogFilter() (audit.Filter, error) {
+	filter := audit.Filter{
+		Actor:      auditLogActor,
+		Role:       auditLogRole,
+		Command:    strings.Join(strings.Fields(auditLogCommand), " "),
+		FailedOnly: auditLogFailed,
+	}
+	if auditLogSince != "" {
+		d, err := parseDuration(auditLogSince)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --since duration: %w", err)
+		}
+		filter.Since = time.Now().Add(-d)
+	}
+	return filter, nil
+}
+
+func readAuditLog() ([]audit.Entry, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	filter, err := auditLogFilter()
+	if err != nil {
+		return nil, err
+	}
+	return audit.Read(townRoot, filter)
+}
+
+func runAuditTail(cmd *cobra.Command, args []string) error {
+	entries, err := readAuditLog()
+	if err != nil {
+		return err
+	}
+	if auditLogLines >= 0 && len(entries) > auditLogLines {
+		entries = entries[len(entries)-auditLogLines:]
+	}
+
+	if auditLogJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(entries) == 0 {
+		fmt.Printf("%s No commands recorded\n", style.Dim.Render("○"))
+		return nil
+	}
+	for i := range entries {
+		fmt.Println(formatAuditLine(&entries[i]))
+	}
+	return nil
+}
+
+// formatAuditLine renders an audit entry as one line.
+func formatAuditLine(e *audit.Entry) string {
+	mark := style.Success.Render("✓")
+	result := ""
+	if !e.OK() {
+		mark = style.Error.Render("✗")
+		result = fmt.Sprintf(" %s", style.Error.Render(fmt.Sprintf("exit %d", e.ExitCode)))
+		if e.Error != "" {
+			result += style.Dim.Render(": " + truncateWithEllipsis(e.Error, 80))
+		}
+	}
+	who := e.Actor
+	if e.User != "" && e.User != e.Actor {
+		who += "@" + e.User
+	}
+	return fmt.Sprintf("%s %s %-30s %-9s gt %s%s",
+		style.Dim.Render(format.DateTimeSeconds(e.Time.Local())), mark, who, e.Role, strings.Join(e.Args, " "), result)
+}
+
+func runAuditExport(cmd *cobra.Command, args []string) error {
+	entries, err := readAuditLog()
+	if err != nil {
+		return err
+	}
+	if entries == nil {
+		entries = []audit.Entry{}
+	}
+	if auditLogOutput == "" {
+		return outputJSON(entries)
+	}
+
+	if err := writeJSON(auditLogOutput, entries); err != nil {
+		return err
+	}
+	fmt.Printf("%s Exported %d entries to %s\n", style.Bold.Render("✓"), len(entries), auditLogOutput)
+	return nil
+}
+
+// recordAudit appends a finished command to the town's audit log if it
+// could have changed state. Best-effort: a town whose log can't be written
+// still runs its commands.
+func recordAudit(cmd *cobra.Command, err error, code int, start time.Time) {
+	if cmd == nil || dryrun.Enabled() {
+		return
+	}
+	args := os.Args[1:]
+	path := commandPath(cmd)
+	if !audit.Mutates(path, args) {
+		return
+	}
+	townRoot, ferr := workspace.FindFromCwd()
+	if ferr != nil || townRoot == "" {
+		return
+	}
+
+	role := "unverified"
+	if id, _, idErr := callerIdentity(townRoot); idErr == nil {
+		role = id.Role
+	}
+	entry := audit.Entry{
+		Time:       start.UTC(),
+		User:       auditUser(),
+		Actor:      detectSender(),
+		Role:       role,
+		Command:    path,
+		Args:       append([]string(nil), args...),
+		ExitCode:   code,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if _, silent := IsSilentExit(err); err != nil && !silent {
+		entry.Error = err.Error()
+	}
+	_ = audit.Append(townRoot, entry)
+}
+
+// auditUser returns the OS user running gt.
+func auditUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
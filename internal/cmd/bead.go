@@ -0,0 +1,454 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Bead command flags
+var (
+	beadDescription string
+	beadType        string
+	beadPriority    int
+	beadParent      string
+	beadRig         string
+	beadTown        bool
+	beadNoLink      bool
+	beadJSON        bool
+	beadCloseReason string
+	beadSize        string
+	beadEstimate    float64
+)
+
+var beadCmd = &cobra.Command{
+	Use:     "bead",
+	GroupID: GroupWork,
+	Short:   "Create, show, close and assign beads in the right database",
+	RunE:    requireSubcommand,
+	Long: `Shortcuts for everyday bd operations that know where you are.
+
+Every rig has its own beads database, and the town has another. These
+commands pick the right one so you don't have to cd or remember prefixes:
+
+  create   in your rig's database (the town's outside a rig), labeled with
+           your rig and agent, and linked to the molecule step you're on
+  show,    in whichever database the bead's prefix routes to
+  close,
+  assign
+  estimate size a bead for 'gt plan'
+
+Examples:
+  gt bead create "Flaky login test" -d "Fails 1 in 5 on CI"
+  gt bead create "Update runbook" --town
+  gt bead create "Login 500s on Safari" --template bug
+  gt bead show gt-abc12
+  gt bead close gt-abc12 -r "Fixed in 4f2e1c"
+  gt bead assign gt-abc12 gastown/polecats/Toast
+  gt bead estimate gt-abc12 L`,
+}
+
+var beadCreateCmd = &cobra.Command{
+	Use:   "create <title>",
+	Short: "Create a bead in the current rig's database",
+	Long: `Create a bead in the current rig's beads database, or the town's when you
+aren't in a rig (or with --town).
+
+The bead is labeled rig:<rig> and polecat:<name> or crew:<name> for the
+agent creating it, and its description records the molecule step you're on
+(discovered_from: <step>) unless --no-link is given.
+
+--template starts from a bead template (see 'gt bead templates'): its type,
+priority, description skeleton and labels, plus a molecule:<formula> label
+for the molecule gt sling applies. Flags override the template.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadCreate,
+}
+
+var beadShowCmd = &cobra.Command{
+	Use:   "show <bead-id>",
+	Short: "Show a bead from whichever database holds it",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBeadShow,
+}
+
+var beadCloseCmd = &cobra.Command{
+	Use:   "close <bead-id>...",
+	Short: "Close beads in whichever databases hold them",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runBeadClose,
+}
+
+var beadEstimateCmd = &cobra.Command{
+	Use:   "estimate <bead-id> <size-or-points>",
+	Short: "Record a bead's size (XS-XL) or estimate in points",
+	Long: `Record a bead's size or estimate for 'gt plan'. Sizes XS, S, M, L and
+XL are 1, 2, 3, 5 and 8 points; a number is an estimate in points and
+overrides the size. "none" clears both.
+
+Examples:
+  gt bead estimate gt-abc12 M
+  gt bead estimate gt-abc12 13
+  gt bead estimate gt-abc12 none`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBeadEstimate,
+}
+
+var beadAssignCmd = &cobra.Command{
+	Use:   "assign <bead-id> [agent]",
+	Short: "Assign a bead to an agent (default: yourself)",
+	Long: `Assign a bead to an agent, such as gastown/polecats/Toast or mayor.
+Without an agent, the bead is assigned to you.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runBeadAssign,
+}
+
+func init() {
+	beadCreateCmd.Flags().StringVarP(&beadDescription, "description", "d", "", "Bead description")
+	beadCreateCmd.Flags().StringVarP(&beadType, "type", "t", "", "Bead type (task, bug, feature, epic)")
+	beadCreateCmd.Flags().IntVarP(&beadPriority, "priority", "p", 2, "Priority (0-4)")
+	beadCreateCmd.Flags().StringVar(&beadParent, "parent", "", "Parent bead ID")
+	beadCreateCmd.Flags().StringVar(&beadRig, "rig", "", "Create in this rig's database")
+	beadCreateCmd.Flags().BoolVar(&beadTown, "town", false, "Create in the town's database")
+	beadCreateCmd.Flags().BoolVar(&beadNoLink, "no-link", false, "Don't link the bead to the current molecule step")
+	beadCreateCmd.Flags().BoolVar(&beadJSON, "json", false, "Output as JSON")
+	beadCreateCmd.Flags().StringVar(&beadSize, "size", "", "Size for planning: XS, S, M, L or XL")
+	beadCreateCmd.Flags().Float64Var(&beadEstimate, "estimate", 0, "Estimate in points (overrides --size)")
+
+	beadShowCmd.Flags().BoolVar(&beadJSON, "json", false, "Output as JSON")
+	beadCloseCmd.Flags().StringVarP(&beadCloseReason, "reason", "r", "", "Reason for closing")
+
+	beadCmd.AddCommand(beadCreateCmd)
+	beadCmd.AddCommand(beadShowCmd)
+	beadCmd.AddCommand(beadCloseCmd)
+	beadCmd.AddCommand(beadAssignCmd)
+	beadCmd.AddCommand(beadEstimateCmd)
+	rootCmd.AddCommand(beadCmd)
+}
+
+// beadLocation returns the directory to run bd in for an existing bead:
+// the database its prefix routes to, else the one the current directory
+// uses, else the town's.
+func beadLocation(townRoot, id string) string {
+	local, err := findLocalBeadsDir()
+	if err != nil {
+		local = townRoot
+	}
+	return beads.ResolveHookDir(townRoot, id, local)
+}
+
+// rigBeadsLocation returns the directory holding a rig's beads database.
+func rigBeadsLocation(townRoot, rigName string) string {
+	prefix := beads.GetPrefixForRig(townRoot, rigName)
+	if path := beads.GetRigPathForPrefix(townRoot, prefix+"-"); path != "" && path != townRoot {
+		return path
+	}
+	return filepath.Join(townRoot, rigName)
+}
+
+// beadCreateTarget picks the database a new bead goes in and the rig it
+// belongs to ("" for the town).
+func beadCreateTarget(townRoot string, info RoleInfo, rigFlag string, town bool) (string, string, error) {
+	if town {
+		return townRoot, "", nil
+	}
+	rigName := rigFlag
+	if rigName == "" {
+		rigName = info.Rig
+	}
+	if rigName == "" {
+		return townRoot, "", nil
+	}
+	if rigFlag != "" {
+		rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+		if err != nil {
+			return "", "", fmt.Errorf("loading rigs: %w", err)
+		}
+		if _, ok := rigsConfig.Rigs[rigName]; !ok {
+			return "", "", fmt.Errorf("rig %q not found", rigName)
+		}
+	}
+	return rigBeadsLocation(townRoot, rigName), rigName, nil
+}
+
+// beadContextLabels labels a bead with the rig and worker creating it.
+func beadContextLabels(info RoleInfo, rigName string) []string {
+	var labels []string
+	if rigName != "" {
+		labels = append(labels, "rig:"+rigName)
+	}
+	if info.Polecat != "" {
+		switch info.Role {
+		case RolePolecat:
+			labels = append(labels, "polecat:"+info.Polecat)
+		case RoleCrew:
+			labels = append(labels, "crew:"+info.Polecat)
+		}
+	}
+	return labels
+}
+
+// activeStep returns the molecule step the agent is on: the first
+// in-progress step of the molecule attached to its hooked bead, or the
+// hooked bead itself when no molecule is attached. Empty if nothing is
+// hooked.
+func activeStep(agentID string) string {
+	workDir, err := findLocalBeadsDir()
+	if err != nil {
+		return ""
+	}
+	b := beads.New(workDir)
+	hooked, err := b.List(beads.ListOptions{Status: beads.StatusHooked, Assignee: agentID, Priority: -1})
+	if err != nil || len(hooked) == 0 {
+		return ""
+	}
+	attachment := beads.ParseAttachmentFields(hooked[0])
+	if attachment == nil || attachment.AttachedMolecule == "" {
+		return hooked[0].ID
+	}
+	steps, err := b.List(beads.ListOptions{Parent: attachment.AttachedMolecule, Status: "in_progress", Priority: -1})
+	if err != nil || len(steps) == 0 {
+		return attachment.AttachedMolecule
+	}
+	return steps[0].ID
+}
+
+// withDiscoveredFrom records the step a bead was found on in its description.
+func withDiscoveredFrom(description, stepID string) string {
+	if stepID == "" {
+		return description
+	}
+	line := "discovered_from: " + stepID
+	if description == "" {
+		return line
+	}
+	return description + "\n\n" + line
+}
+
+func runBeadCreate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if beadTown && beadRig != "" {
+		return fmt.Errorf("--town and --rig are mutually exclusive")
+	}
+	info, err := GetRole()
+	if err != nil {
+		return err
+	}
+
+	location, rigName, err := beadCreateTarget(townRoot, info, beadRig, beadTown)
+	if err != nil {
+		return err
+	}
+
+	var templateLabels []string
+	if beadTemplateName != "" {
+		tmpl, err := findBeadTemplate(townRoot, beadTemplateName)
+		if err != nil {
+			return err
+		}
+		templateLabels = applyBeadTemplate(cmd, tmpl)
+	}
+
+	actor := info.ActorString()
+	description := beadDescription
+	if !beadNoLink && info.Role != RoleUnknown {
+		description = withDiscoveredFrom(description, activeStep(actor))
+	}
+	if beadSize != "" || beadEstimate != 0 {
+		fields, err := estimateFromFlags(beadSize, beadEstimate)
+		if err != nil {
+			return err
+		}
+		description = beads.SetEstimateFields(&beads.Issue{Description: description}, fields)
+	}
+
+	issue, err := beads.New(location).Create(beads.CreateOptions{
+		Title:       args[0],
+		Type:        beadType,
+		Priority:    beadPriority,
+		Description: description,
+		Parent:      beadParent,
+		Actor:       actor,
+		Labels:      append(beadContextLabels(info, rigName), templateLabels...),
+	})
+	if err != nil {
+		return fmt.Errorf("creating bead: %w", err)
+	}
+	if dryrun.Enabled() {
+		return nil
+	}
+
+	if beadJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(issue)
+	}
+	where := "town"
+	if rigName != "" {
+		where = rigName
+	}
+	fmt.Printf("%s Created %s: %s %s\n", style.Bold.Render("✓"), issue.ID, issue.Title, style.Dim.Render("("+where+")"))
+	return nil
+}
+
+func runBeadShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	issue, err := beads.New(beadLocation(townRoot, args[0])).Show(args[0])
+	if err != nil {
+		return fmt.Errorf("showing %s: %w", args[0], err)
+	}
+
+	if beadJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(issue)
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render(issue.ID), issue.Title)
+	fmt.Printf("  Status:   %s\n", issue.Status)
+	fmt.Printf("  Priority: P%d\n", issue.Priority)
+	if issue.Type != "" {
+		fmt.Printf("  Type:     %s\n", issue.Type)
+	}
+	if issue.Assignee != "" {
+		fmt.Printf("  Assignee: %s\n", issue.Assignee)
+	}
+	if issue.Parent != "" {
+		fmt.Printf("  Parent:   %s\n", issue.Parent)
+	}
+	if len(issue.Labels) > 0 {
+		fmt.Printf("  Labels:   %s\n", strings.Join(issue.Labels, ", "))
+	}
+	if len(issue.BlockedBy) > 0 {
+		fmt.Printf("  Blocked by: %s\n", strings.Join(issue.BlockedBy, ", "))
+	}
+	if issue.Description != "" {
+		fmt.Printf("\n%s\n", issue.Description)
+	}
+	return nil
+}
+
+func runBeadClose(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	// Close beads database by database
+	byLocation := make(map[string][]string)
+	var locations []string
+	for _, id := range args {
+		location := beadLocation(townRoot, id)
+		if byLocation[location] == nil {
+			locations = append(locations, location)
+		}
+		byLocation[location] = append(byLocation[location], id)
+	}
+	for _, location := range locations {
+		b := beads.New(location)
+		ids := byLocation[location]
+		if beadCloseReason != "" {
+			err = b.CloseWithReason(beadCloseReason, ids...)
+		} else {
+			err = b.Close(ids...)
+		}
+		if err != nil {
+			return fmt.Errorf("closing %s: %w", strings.Join(ids, ", "), err)
+		}
+		for _, id := range ids {
+			fmt.Printf("%s Closed %s\n", style.Bold.Render("✓"), id)
+		}
+	}
+	return nil
+}
+
+func runBeadAssign(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	assignee := ""
+	if len(args) > 1 {
+		assignee = args[1]
+	} else {
+		info, err := GetRole()
+		if err != nil {
+			return err
+		}
+		if info.Role == RoleUnknown {
+			return fmt.Errorf("cannot determine who you are; name the agent to assign to")
+		}
+		assignee = info.ActorString()
+	}
+
+	id := args[0]
+	if err := beads.New(beadLocation(townRoot, id)).Update(id, beads.UpdateOptions{Assignee: &assignee}); err != nil {
+		return fmt.Errorf("assigning %s: %w", id, err)
+	}
+	fmt.Printf("%s Assigned %s to %s\n", style.Bold.Render("✓"), id, assignee)
+	return nil
+}
+
+// estimateFromFlags builds estimate fields from a size and a points
+// estimate, either of which may be unset.
+func estimateFromFlags(size string, estimate float64) (*beads.EstimateFields, error) {
+	fields := &beads.EstimateFields{Estimate: estimate}
+	if estimate < 0 {
+		return nil, fmt.Errorf("estimate must be positive")
+	}
+	if size != "" {
+		s, err := beads.ParseSize(size)
+		if err != nil {
+			return nil, err
+		}
+		fields.Size = s
+	}
+	return fields, nil
+}
+
+func runBeadEstimate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	id, value := args[0], args[1]
+
+	var fields *beads.EstimateFields
+	if value != "none" {
+		if points, perr := strconv.ParseFloat(value, 64); perr == nil {
+			fields, err = estimateFromFlags("", points)
+		} else {
+			fields, err = estimateFromFlags(value, 0)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := beads.New(beadLocation(townRoot, id)).SetEstimate(id, fields); err != nil {
+		return fmt.Errorf("estimating %s: %w", id, err)
+	}
+	if fields == nil {
+		fmt.Printf("%s Cleared %s's estimate\n", style.Bold.Render("✓"), id)
+		return nil
+	}
+	fmt.Printf("%s %s: %s points\n", style.Bold.Render("✓"), id, formatPoints(fields.Points()))
+	return nil
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// moleculeLabelPrefix labels a bead with the formula its template names,
+// e.g. "molecule:shiny".
+const moleculeLabelPrefix = "molecule:"
+
+var (
+	beadTemplateName      string
+	beadTemplatesListJSON bool
+)
+
+// builtinBeadTemplates are the templates every town has. Town settings can
+// replace them by name.
+var builtinBeadTemplates = map[string]*config.BeadTemplate{
+	"bug": {
+		Type:     "bug",
+		Priority: intPtr(1),
+		Labels:   []string{"bug"},
+		Description: `## Steps to reproduce
+
+## Expected
+
+## Actual
+
+## Environment
+`,
+		Molecule: "shiny",
+	},
+	"feature": {
+		Type:   "feature",
+		Labels: []string{"feature"},
+		Description: `## Goal
+
+## Acceptance criteria
+- [ ]
+
+## Out of scope
+`,
+		Molecule: "shiny",
+	},
+	"chore": {
+		Type:     "chore",
+		Priority: intPtr(3),
+		Labels:   []string{"chore"},
+		Description: `## What
+
+## Done when
+`,
+		Molecule: "mol-polecat-work",
+	},
+}
+
+func intPtr(n int) *int { return &n }
+
+var beadTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List the bead templates 'gt bead create --template' offers",
+	Long: `List bead templates: bug, feature and chore are built in, and town
+settings can add more or replace them in settings/config.json:
+
+  "bead_templates": {
+    "incident": {"type": "bug", "priority": 0, "labels": ["incident"],
+                 "description": "## Impact\n\n## Timeline\n", "molecule": "shiny"}
+  }
+
+A template's molecule is recorded on the bead as a molecule:<formula>
+label; 'gt sling' and 'gt assign --auto' apply it unless given another.`,
+	Args: cobra.NoArgs,
+	RunE: runBeadTemplates,
+}
+
+func init() {
+	beadCreateCmd.Flags().StringVar(&beadTemplateName, "template", "", "Start from a bead template (bug, feature, chore, or one from town settings)")
+	beadTemplatesCmd.Flags().BoolVar(&beadTemplatesListJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadTemplatesCmd)
+}
+
+// beadTemplates returns the built-in templates merged with the town's.
+func beadTemplates(townRoot string) map[string]*config.BeadTemplate {
+	templates := make(map[string]*config.BeadTemplate, len(builtinBeadTemplates))
+	for name, t := range builtinBeadTemplates {
+		templates[name] = t
+	}
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		for name, t := range settings.BeadTemplates {
+			if t != nil {
+				templates[name] = t
+			}
+		}
+	}
+	return templates
+}
+
+// findBeadTemplate looks up a template by name.
+func findBeadTemplate(townRoot, name string) (*config.BeadTemplate, error) {
+	templates := beadTemplates(townRoot)
+	if t, ok := templates[name]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown bead template %q (known: %s)", name, strings.Join(sortedTemplateNames(templates), ", "))
+}
+
+func sortedTemplateNames(templates map[string]*config.BeadTemplate) []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyBeadTemplate fills in what the create flags left unset and returns
+// the labels the template adds.
+func applyBeadTemplate(cmd *cobra.Command, t *config.BeadTemplate) []string {
+	if t.Type != "" && !cmd.Flags().Changed("type") {
+		beadType = t.Type
+	}
+	if t.Priority != nil && !cmd.Flags().Changed("priority") {
+		beadPriority = *t.Priority
+	}
+	if t.Description != "" && !cmd.Flags().Changed("description") {
+		beadDescription = t.Description
+	}
+	labels := append([]string(nil), t.Labels...)
+	if t.Molecule != "" {
+		labels = append(labels, moleculeLabelPrefix+t.Molecule)
+	}
+	return labels
+}
+
+// beadMolecule returns the formula a bead's molecule label names, or "".
+func beadMolecule(labels []string) string {
+	for _, l := range labels {
+		if m, ok := strings.CutPrefix(l, moleculeLabelPrefix); ok && m != "" {
+			return m
+		}
+	}
+	return ""
+}
+
+func runBeadTemplates(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	templates := beadTemplates(townRoot)
+	if beadTemplatesListJSON {
+		return outputJSON(templates)
+	}
+
+	for _, name := range sortedTemplateNames(templates) {
+		t := templates[name]
+		priority := 2
+		if t.Priority != nil {
+			priority = *t.Priority
+		}
+		details := fmt.Sprintf("%s P%d", t.Type, priority)
+		if len(t.Labels) > 0 {
+			details += " [" + strings.Join(t.Labels, ", ") + "]"
+		}
+		if t.Molecule != "" {
+			details += " → " + t.Molecule
+		}
+		fmt.Printf("  %s %s\n", style.Bold.Render(fmt.Sprintf("%-12s", name)), style.Dim.Render(details))
+	}
+	return nil
+}
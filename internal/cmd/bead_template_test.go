@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestBeadTemplates(t *testing.T) {
+	town := t.TempDir()
+	settings := `{"type": "town-settings", "version": 1, "bead_templates": {
+		"incident": {"type": "bug", "priority": 0, "labels": ["incident"]},
+		"chore": {"type": "task", "molecule": "shiny"}
+	}}`
+	if err := os.MkdirAll(filepath.Join(town, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templates := beadTemplates(town)
+	if templates["bug"] == nil || templates["feature"] == nil {
+		t.Error("built-in templates missing")
+	}
+	if templates["incident"] == nil {
+		t.Error("town template missing")
+	}
+	if templates["chore"].Type != "task" {
+		t.Errorf("town chore template didn't replace the built-in: %+v", templates["chore"])
+	}
+	if _, err := findBeadTemplate(town, "nope"); err == nil {
+		t.Error("findBeadTemplate(nope) succeeded")
+	}
+}
+
+func TestApplyBeadTemplate(t *testing.T) {
+	oldType, oldPriority, oldDescription := beadType, beadPriority, beadDescription
+	t.Cleanup(func() { beadType, beadPriority, beadDescription = oldType, oldPriority, oldDescription })
+
+	newCmd := func() *cobra.Command {
+		c := &cobra.Command{}
+		c.Flags().StringVarP(&beadType, "type", "t", "", "")
+		c.Flags().IntVarP(&beadPriority, "priority", "p", 2, "")
+		c.Flags().StringVarP(&beadDescription, "description", "d", "", "")
+		return c
+	}
+
+	c := newCmd()
+	labels := applyBeadTemplate(c, builtinBeadTemplates["bug"])
+	if beadType != "bug" || beadPriority != 1 || beadDescription == "" {
+		t.Errorf("template not applied: type=%q priority=%d", beadType, beadPriority)
+	}
+	if !slices.Contains(labels, "bug") || beadMolecule(labels) != "shiny" {
+		t.Errorf("labels = %v", labels)
+	}
+
+	c = newCmd()
+	if err := c.Flags().Parse([]string{"-p", "0", "-d", "Crash on save"}); err != nil {
+		t.Fatal(err)
+	}
+	applyBeadTemplate(c, builtinBeadTemplates["bug"])
+	if beadPriority != 0 || beadDescription != "Crash on save" {
+		t.Errorf("flags didn't override the template: priority=%d description=%q", beadPriority, beadDescription)
+	}
+}
+
+func TestAssignMolecule(t *testing.T) {
+	molecules := map[string]string{"bug": "rule-of-five"}
+	tests := []struct {
+		issue *beads.Issue
+		want  string
+	}{
+		{&beads.Issue{Type: "bug"}, "rule-of-five"},
+		{&beads.Issue{Type: "bug", Labels: []string{"molecule:shiny"}}, "shiny"},
+		{&beads.Issue{Type: "task"}, defaultAssignMolecule},
+	}
+	for _, tt := range tests {
+		if got := assignMolecule(tt.issue, molecules); got != tt.want {
+			t.Errorf("assignMolecule(%+v) = %q, want %q", tt.issue, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBeadCreateTarget(t *testing.T) {
+	townRoot := setupTestTownForCrewList(t, map[string][]string{"gastown": nil})
+	beadsDir := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	routes := `{"prefix":"hq-","path":"."}` + "\n" + `{"prefix":"gt-","path":"gastown/mayor/rig"}` + "\n"
+	if err := os.WriteFile(filepath.Join(beadsDir, "routes.jsonl"), []byte(routes), 0644); err != nil {
+		t.Fatal(err)
+	}
+	polecat := RoleInfo{Role: RolePolecat, Rig: "gastown", Polecat: "Toast"}
+	rigDB := filepath.Join(townRoot, "gastown", "mayor", "rig")
+
+	tests := []struct {
+		name     string
+		info     RoleInfo
+		rigFlag  string
+		town     bool
+		wantDir  string
+		wantRig  string
+		wantFail bool
+	}{
+		{"polecat", polecat, "", false, rigDB, "gastown", false},
+		{"polecat --town", polecat, "", true, townRoot, "", false},
+		{"mayor", RoleInfo{Role: RoleMayor}, "", false, townRoot, "", false},
+		{"mayor --rig", RoleInfo{Role: RoleMayor}, "gastown", false, rigDB, "gastown", false},
+		{"unknown rig", RoleInfo{Role: RoleMayor}, "nope", false, "", "", true},
+	}
+	for _, tt := range tests {
+		dir, rigName, err := beadCreateTarget(townRoot, tt.info, tt.rigFlag, tt.town)
+		if (err != nil) != tt.wantFail {
+			t.Errorf("%s: err = %v", tt.name, err)
+			continue
+		}
+		if dir != tt.wantDir || rigName != tt.wantRig {
+			t.Errorf("%s: got %s, %q; want %s, %q", tt.name, dir, rigName, tt.wantDir, tt.wantRig)
+		}
+	}
+}
+
+func TestBeadContextLabels(t *testing.T) {
+	got := beadContextLabels(RoleInfo{Role: RolePolecat, Rig: "gastown", Polecat: "Toast"}, "gastown")
+	if want := []string{"rig:gastown", "polecat:Toast"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("polecat labels = %v, want %v", got, want)
+	}
+	got = beadContextLabels(RoleInfo{Role: RoleCrew, Rig: "gastown", Polecat: "max"}, "gastown")
+	if want := []string{"rig:gastown", "crew:max"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("crew labels = %v, want %v", got, want)
+	}
+	if got := beadContextLabels(RoleInfo{Role: RoleMayor}, ""); got != nil {
+		t.Errorf("mayor labels = %v", got)
+	}
+}
+
+func TestWithDiscoveredFrom(t *testing.T) {
+	if got := withDiscoveredFrom("", "gt-step1"); got != "discovered_from: gt-step1" {
+		t.Errorf("empty description = %q", got)
+	}
+	if got := withDiscoveredFrom("Fails on CI", "gt-step1"); got != "Fails on CI\n\ndiscovered_from: gt-step1" {
+		t.Errorf("with description = %q", got)
+	}
+	if got := withDiscoveredFrom("Fails on CI", ""); got != "Fails on CI" {
+		t.Errorf("no step = %q", got)
+	}
+}
+
+func TestEstimateFromFlags(t *testing.T) {
+	fields, err := estimateFromFlags("l", 0)
+	if err != nil || fields.Size != "L" || fields.Points() != 5 {
+		t.Errorf("estimateFromFlags(l) = %+v, %v", fields, err)
+	}
+	fields, err = estimateFromFlags("S", 4)
+	if err != nil || fields.Points() != 4 {
+		t.Errorf("estimate didn't override size: %+v, %v", fields, err)
+	}
+	if _, err := estimateFromFlags("huge", 0); err == nil {
+		t.Error("estimateFromFlags(huge) succeeded")
+	}
+	if _, err := estimateFromFlags("", -1); err == nil {
+		t.Error("negative estimate accepted")
+	}
+}
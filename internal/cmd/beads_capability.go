@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// beadsOptionalCommands work without bd installed, leaving out what they
+// would have shown from beads (hooks, mail, merge queues). Keys are command
+// paths below gt; an entry covers its subcommands.
+var beadsOptionalCommands = map[string]bool{
+	"status":     true, // Sessions and rig layout
+	"top":        true,
+	"peek":       true,
+	"nudge":      true,
+	"session":    true,
+	"rig list":   true,
+	"rig status": true,
+	"doctor":     true, // Reports bd as missing
+	"snapshot":   true,
+	"config":     true,
+	"shell":      true,
+	"theme":      true,
+	"account":    true,
+	"whoami":     true,
+	"info":       true,
+	"thanks":     true,
+	"mol lint":   true, // Reads formula files only
+}
+
+// beadsOptional reports whether cmd can run without bd.
+func beadsOptional(cmd *cobra.Command) bool {
+	for c := cmd; c != nil && c.HasParent(); c = c.Parent() {
+		path := strings.TrimPrefix(c.CommandPath(), c.Root().Name()+" ")
+		if beadsOptionalCommands[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBeadsCapability makes sure a command will find the bd it needs, so
+// a missing or outdated bd is reported once, up front, rather than as a
+// failed subprocess somewhere inside the command.
+func checkBeadsCapability(cmd *cobra.Command) error {
+	var err error
+	switch {
+	case !beads.Installed() && beadsOptional(cmd):
+		return nil
+	case !beads.Installed():
+		err = beadsMissingError(cmd)
+	default:
+		err = CheckBeadsVersion()
+	}
+	if err != nil {
+		cmd.SilenceUsage = true // The command was used correctly
+	}
+	return err
+}
+
+// beadsMissingError tells the user how to get bd, and what works without it.
+func beadsMissingError(cmd *cobra.Command) error {
+	var optional []string
+	for path := range beadsOptionalCommands {
+		optional = append(optional, "gt "+path)
+	}
+	sort.Strings(optional)
+	return fmt.Errorf("'%s' needs beads (bd), which is not on PATH\n\nInstall it: %s\n\nThese work without it: %s",
+		cmd.CommandPath(), beads.InstallCommand, strings.Join(optional, ", "))
+}
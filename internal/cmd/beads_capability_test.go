@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestBeadsOptional(t *testing.T) {
+	root := &cobra.Command{Use: "gt"}
+	rig := &cobra.Command{Use: "rig"}
+	rigList := &cobra.Command{Use: "list"}
+	rigAdd := &cobra.Command{Use: "add"}
+	session := &cobra.Command{Use: "session"}
+	sessionStart := &cobra.Command{Use: "start"}
+	mol := &cobra.Command{Use: "mol"}
+	molList := &cobra.Command{Use: "list"}
+	root.AddCommand(rig, session, mol)
+	rig.AddCommand(rigList, rigAdd)
+	session.AddCommand(sessionStart)
+	mol.AddCommand(molList)
+
+	for cmd, want := range map[*cobra.Command]bool{
+		rigList:      true,
+		rigAdd:       false, // Initializes the rig's beads
+		sessionStart: true,  // Covered by its parent
+		molList:      false, // "list" only counts under rig
+		mol:          false,
+	} {
+		if got := beadsOptional(cmd); got != want {
+			t.Errorf("beadsOptional(%s) = %v, want %v", cmd.CommandPath(), got, want)
+		}
+	}
+}
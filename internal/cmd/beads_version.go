@@ -130,3 +130,45 @@ func CheckBeadsVersion() error {
 
 	return nil
 }
+
+// beadsCompat is one row of the gt/bd compatibility matrix: the bd versions
+// a gt release line is known to work with.
+type beadsCompat struct {
+	GT     string // gt release line, "major.minor"
+	MinBD  string // Oldest bd that works
+	MaxBD  string // Newest bd known to work, "major.minor"; "" if no known limit
+	Reason string // What older bd versions lack
+}
+
+// beadsCompatibility is the compatibility matrix. Add a row when a gt
+// release line starts depending on a newer bd, or a bd release breaks one.
+var beadsCompatibility = []beadsCompat{
+	{GT: "0.2", MinBD: MinBeadsVersion, Reason: "custom type support (bd-i54l)"},
+}
+
+// beadsCompatWarning returns why bdVersion doesn't suit gtVersion according
+// to the matrix, or "" if it is fine or either version is unknown.
+func beadsCompatWarning(gtVersion, bdVersion string) string {
+	gt, err := parseBeadsVersion(gtVersion)
+	if err != nil {
+		return ""
+	}
+	bd, err := parseBeadsVersion(bdVersion)
+	if err != nil {
+		return ""
+	}
+	for _, row := range beadsCompatibility {
+		line, err := parseBeadsVersion(row.GT)
+		if err != nil || line.major != gt.major || line.minor != gt.minor {
+			continue
+		}
+		if min, err := parseBeadsVersion(row.MinBD); err == nil && bd.compare(min) < 0 {
+			return fmt.Sprintf("bd %s is older than %s, the minimum for gt %s: it lacks %s", bdVersion, row.MinBD, row.GT, row.Reason)
+		}
+		if max, err := parseBeadsVersion(row.MaxBD); err == nil && (bd.major > max.major || (bd.major == max.major && bd.minor > max.minor)) {
+			return fmt.Sprintf("bd %s is newer than %s.x, the latest known to work with gt %s", bdVersion, row.MaxBD, row.GT)
+		}
+		return ""
+	}
+	return ""
+}
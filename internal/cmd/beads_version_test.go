@@ -66,3 +66,30 @@ func TestBeadsVersionCompare(t *testing.T) {
 		})
 	}
 }
+
+func TestBeadsCompatWarning(t *testing.T) {
+	saved := beadsCompatibility
+	t.Cleanup(func() { beadsCompatibility = saved })
+	beadsCompatibility = []beadsCompat{
+		{GT: "0.2", MinBD: "0.44.0", Reason: "custom types"},
+		{GT: "0.3", MinBD: "0.47.0", MaxBD: "0.49", Reason: "batch create"},
+	}
+
+	tests := []struct {
+		gt, bd string
+		want   string
+	}{
+		{"0.2.6", "0.44.0", ""},
+		{"0.2.6", "0.43.9", "bd 0.43.9 is older than 0.44.0, the minimum for gt 0.2: it lacks custom types"},
+		{"0.3.0", "0.49.7", ""},
+		{"0.3.1", "0.50.0", "bd 0.50.0 is newer than 0.49.x, the latest known to work with gt 0.3"},
+		{"0.9.0", "0.1.0", ""}, // No row for this gt line
+		{"dev", "0.1.0", ""},   // Unknown gt version
+		{"0.2.6", "weird", ""}, // Unknown bd version
+	}
+	for _, tt := range tests {
+		if got := beadsCompatWarning(tt.gt, tt.bd); got != tt.want {
+			t.Errorf("beadsCompatWarning(%q, %q) = %q, want %q", tt.gt, tt.bd, got, tt.want)
+		}
+	}
+}
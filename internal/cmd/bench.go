@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/bench"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	benchIterations int
+	benchIssues     int
+	benchJSON       bool
+	benchCompare    string
+	benchRig        string
+)
+
+// benchSpawnTimeout is how long the spawn benchmark waits for a prompt.
+const benchSpawnTimeout = 2 * time.Minute
+
+// benchmarks are what gt bench can measure, in the order it runs them.
+var benchmarks = []struct {
+	name string
+	run  func(work string) bench.Result
+}{
+	{"bd-list", benchBdList},
+	{"spawn", benchSpawn},
+	{"rig-add", benchRigAdd},
+}
+
+var benchCmd = &cobra.Command{
+	Use:     "bench [benchmark...]",
+	GroupID: GroupDiag,
+	Short:   "Measure gt's orchestration overhead",
+	Long: `Time the operations every workflow leans on, so regressions in gt's own
+overhead show up:
+
+  bd-list   list --issues open beads through the bd wrapper
+  spawn     start the agent runtime in a tmux session and wait for its
+            prompt (the rig's agent with --rig, else the town's default)
+  rig-add   gt rig add of a one-commit local repo into a fresh town
+
+Each runs --iterations times in a scratch directory that is removed
+afterwards; fixtures are built untimed. Benchmarks that can't run here
+(no bd, tmux or agent on PATH) are reported as skipped.
+
+--json writes a report with gt's version and stable benchmark names. Keep
+it, and pass it to --compare on a later version to see how the medians
+moved.
+
+Examples:
+  gt bench
+  gt bench bd-list --issues 50000
+  gt bench --json > bench-0.2.6.json
+  gt bench --compare bench-0.2.6.json`,
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().IntVarP(&benchIterations, "iterations", "n", 5, "Runs of each benchmark")
+	benchCmd.Flags().IntVar(&benchIssues, "issues", 10000, "Beads in the bd-list fixture")
+	benchCmd.Flags().BoolVar(&benchJSON, "json", false, "Output the report as JSON")
+	benchCmd.Flags().StringVar(&benchCompare, "compare", "", "Compare against a report from an earlier --json run")
+	benchCmd.Flags().StringVar(&benchRig, "rig", "", "Rig whose agent the spawn benchmark starts")
+	rootCmd.AddCommand(benchCmd)
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if benchIterations < 1 {
+		return fmt.Errorf("--iterations must be at least 1")
+	}
+	known := make(map[string]bool, len(benchmarks))
+	for _, b := range benchmarks {
+		known[b.name] = true
+	}
+	for _, a := range args {
+		if !known[a] {
+			return fmt.Errorf("unknown benchmark %q (have: bd-list, spawn, rig-add)", a)
+		}
+	}
+
+	var base *bench.Report
+	if benchCompare != "" {
+		var err error
+		if base, err = bench.Load(benchCompare); err != nil {
+			return err
+		}
+	}
+
+	work, err := os.MkdirTemp("", "gt-bench-")
+	if err != nil {
+		return fmt.Errorf("creating scratch directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(work) }()
+
+	info := collectVersionInfo()
+	report := &bench.Report{
+		Version:   info.Version,
+		Commit:    info.Commit,
+		Platform:  info.Platform,
+		BdVersion: info.BeadsVersion,
+		Time:      time.Now().UTC(),
+		Params:    bench.Params{Iterations: benchIterations, Issues: benchIssues},
+	}
+	for _, b := range benchmarks {
+		if len(args) > 0 && !slices.Contains(args, b.name) {
+			continue
+		}
+		if !benchJSON {
+			fmt.Fprintf(os.Stderr, "%s %s...\n", style.Dim.Render("running"), b.name)
+		}
+		dir := filepath.Join(work, b.name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		report.Results = append(report.Results, b.run(dir))
+	}
+
+	if benchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printBenchReport(report, base)
+	return nil
+}
+
+func printBenchReport(report *bench.Report, base *bench.Report) {
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("gt %s bench (iterations: %d)", report.Version, report.Params.Iterations)))
+	fmt.Printf("  %-10s %10s %10s %10s\n", "", "median", "p95", "max")
+	for _, r := range report.Results {
+		switch {
+		case r.Skipped != "":
+			fmt.Printf("  %-10s %s\n", r.Name, style.Dim.Render("skipped: "+r.Skipped))
+			continue
+		case r.Iterations == 0:
+			fmt.Printf("  %-10s %s\n", r.Name, style.Error.Render("failed: "+r.Error))
+			continue
+		}
+		fmt.Printf("  %-10s %8.1fms %8.1fms %8.1fms\n", r.Name, r.MedianMs, r.P95Ms, r.MaxMs)
+		if r.Error != "" {
+			fmt.Printf("  %-10s %s\n", "", style.Warning.Render(fmt.Sprintf("stopped after %d: %s", r.Iterations, r.Error)))
+		}
+	}
+
+	if base == nil {
+		return
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render("Compared with gt "+base.Version))
+	if base.Params != report.Params {
+		style.PrintWarning("the base report used different parameters (%d runs, %d issues)", base.Params.Iterations, base.Params.Issues)
+	}
+	deltas := bench.Compare(base, report)
+	if len(deltas) == 0 {
+		fmt.Println("  (no benchmarks in common)")
+	}
+	for _, d := range deltas {
+		change := fmt.Sprintf("%+.1f%%", d.Change)
+		switch {
+		case d.Change >= 10:
+			change = style.Error.Render(change)
+		case d.Change <= -10:
+			change = style.Success.Render(change)
+		}
+		fmt.Printf("  %-10s %8.1fms → %8.1fms  %s\n", d.Name, d.BaseMs, d.MedianMs, change)
+	}
+}
+
+// benchBdList times listing a database of benchIssues open beads.
+func benchBdList(work string) bench.Result {
+	const name = "bd-list"
+	if _, err := exec.LookPath("bd"); err != nil {
+		return bench.Skip(name, "bd not on PATH")
+	}
+	if err := benchBeadsFixture(work, benchIssues); err != nil {
+		return bench.Result{Name: name, Error: fmt.Sprintf("building fixture: %v", err)}
+	}
+	b := beads.New(work)
+	return bench.Measure(name, benchIterations, func(int) error {
+		issues, err := b.List(beads.ListOptions{Status: "open", Priority: -1})
+		if err != nil {
+			return err
+		}
+		if len(issues) != benchIssues {
+			return fmt.Errorf("listed %d of %d beads", len(issues), benchIssues)
+		}
+		return nil
+	})
+}
+
+// benchBeadsFixture creates a beads database in dir holding n open beads,
+// imported in one go rather than created one by one.
+func benchBeadsFixture(dir string, n int) error {
+	if err := benchRun(dir, "git", "init", "-q"); err != nil {
+		return err
+	}
+	if err := benchRun(dir, "bd", "init", "--prefix", "bench"); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, "fixture.jsonl")
+	f, err := os.Create(path) //nolint:gosec // G304: path is in our scratch directory
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	now := time.Now().UTC().Format(time.RFC3339)
+	for i := 1; i <= n; i++ {
+		issue := beads.Issue{
+			ID:          fmt.Sprintf("bench-%d", i),
+			Title:       fmt.Sprintf("Benchmark bead %d", i),
+			Description: "Fixture for gt bench.",
+			Status:      "open",
+			Priority:    i % 5,
+			Type:        "task",
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := enc.Encode(issue); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return benchRun(dir, "bd", "import", "-i", path)
+}
+
+// benchSpawn times starting the agent runtime until it shows its prompt.
+func benchSpawn(work string) bench.Result {
+	const name = "spawn"
+	t := tmux.NewTmux()
+	if !t.IsAvailable() {
+		return bench.Skip(name, "tmux not available")
+	}
+	rc := config.DefaultRuntimeConfig()
+	dir, _ := os.Getwd()
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		rigPath := ""
+		if benchRig != "" {
+			rigPath = filepath.Join(townRoot, benchRig)
+		}
+		rc = config.ResolveAgentConfig(townRoot, rigPath)
+	}
+	if _, err := exec.LookPath(rc.Command); err != nil {
+		return bench.Skip(name, rc.Command+" not on PATH")
+	}
+
+	session := func(i int) string { return fmt.Sprintf("gt-bench-%d-%d", os.Getpid(), i) }
+	defer func() {
+		for i := 0; i < benchIterations; i++ {
+			_ = t.KillSession(session(i))
+		}
+	}()
+	return bench.MeasureWithSetup(name, benchIterations,
+		func(i int) error {
+			if i > 0 {
+				_ = t.KillSession(session(i - 1))
+			}
+			return nil
+		},
+		func(i int) error {
+			if err := t.NewSessionWithCommand(session(i), dir, rc.BuildCommand()); err != nil {
+				return err
+			}
+			return t.WaitForRuntimeReady(session(i), rc, benchSpawnTimeout)
+		})
+}
+
+// benchRigAdd times gt rig add into a fresh town, as a user would run it.
+func benchRigAdd(work string) bench.Result {
+	const name = "rig-add"
+	if _, err := exec.LookPath("bd"); err != nil {
+		return bench.Skip(name, "bd not on PATH")
+	}
+	gt, err := os.Executable()
+	if err != nil {
+		return bench.Skip(name, "can't find the gt binary")
+	}
+	repo := filepath.Join(work, "repo")
+	if err := benchRepoFixture(repo); err != nil {
+		return bench.Result{Name: name, Error: fmt.Sprintf("building fixture: %v", err)}
+	}
+
+	town := func(i int) string { return filepath.Join(work, fmt.Sprintf("town-%d", i)) }
+	return bench.MeasureWithSetup(name, benchIterations,
+		func(i int) error {
+			return benchRun(work, gt, "install", town(i), "--name", fmt.Sprintf("bench%d", i))
+		},
+		func(i int) error {
+			return benchRun(town(i), gt, "rig", "add", "bench", repo)
+		})
+}
+
+// benchRepoFixture creates a git repository with one commit on main.
+func benchRepoFixture(repo string) error {
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("# bench\n"), 0644); err != nil {
+		return err
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.name=gt bench", "-c", "user.email=bench@gastown.invalid", "commit", "-q", "-m", "Initial commit"},
+	} {
+		if err := benchRun(repo, "git", args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// benchRun runs a fixture or benchmark command in dir, isolated from the
+// town gt bench was started in.
+func benchRun(dir, name string, args ...string) error {
+	c := exec.Command(name, args...)
+	c.Dir = dir
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, "GT_") && !strings.HasPrefix(e, "BEADS_DIR=") {
+			c.Env = append(c.Env, e)
+		}
+	}
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s %s: %w: %s", filepath.Base(name), strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/format"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var budgetStatusJSON bool
+
+var budgetCmd = &cobra.Command{
+	Use:     "budget",
+	GroupID: GroupDiag,
+	Short:   "Spend caps that pause and stop polecat spawning",
+	Long: `Show the town's spend against its daily and weekly caps.
+
+Caps are set in ~/gt/settings/config.json, in dollars, tokens or both:
+
+  "budget": {
+    "daily_usd": 50,
+    "weekly_usd": 250,
+    "daily_tokens": 20000000,
+    "warn_percent": 80
+  }
+
+Spend is what 'gt costs record' logged for ended sessions. At the warning
+threshold new slings are queued instead of spawned, queued slings are held,
+and a "budget" notification is sent. At 100% slings into rigs are refused.
+Pass --over-budget to gt sling or gt scheduler drain to go past the caps.
+
+Examples:
+  gt budget status          # Spend against each cap
+  gt budget status --json   # Same, as JSON`,
+	RunE: requireSubcommand,
+}
+
+var budgetStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show spend against the daily and weekly caps",
+	Args:  cobra.NoArgs,
+	RunE:  runBudgetStatus,
+}
+
+func init() {
+	budgetStatusCmd.Flags().BoolVar(&budgetStatusJSON, "json", false, "Output as JSON")
+	budgetCmd.AddCommand(budgetStatusCmd)
+	rootCmd.AddCommand(budgetCmd)
+}
+
+func runBudgetStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	status, err := checkBudget(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if budgetStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+
+	if len(status.Caps) == 0 {
+		fmt.Printf("%s No budget caps configured (\"budget\" in settings/config.json)\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Budget (%s)\n", style.Bold.Render("💰"), time.Now().Format("2006-01-02"))
+	for _, c := range status.Caps {
+		icon := style.Bold.Render("✓")
+		switch c.Level {
+		case budget.Warn:
+			icon = style.Warning.Render("⚠")
+		case budget.Exceeded:
+			icon = style.Error.Render("✗")
+		}
+		fmt.Printf("  %s %s\n", icon, c.String())
+	}
+
+	fmt.Println()
+	switch status.Level {
+	case budget.Warn:
+		fmt.Printf("New spawns are paused at %d%% of a cap; slings are queued.\n", status.WarnPercent)
+	case budget.Exceeded:
+		fmt.Println("Spawning is stopped until the window rolls over or the cap is raised.")
+	default:
+		fmt.Printf("Spawning is open. Today: %s; last 7 days: %s.\n", format.Currency(status.Day.USD), format.Currency(status.Week.USD))
+		return nil
+	}
+	fmt.Println("Use --over-budget with gt sling or gt scheduler drain to override.")
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -104,13 +105,11 @@ Unknown message types are logged but left unprocessed.`,
 }
 
 var (
-	callbacksDryRun  bool
-	callbacksVerbose bool
+	callbacksDryRun bool
 )
 
 func init() {
 	callbacksProcessCmd.Flags().BoolVar(&callbacksDryRun, "dry-run", false, "Show what would be processed without taking action")
-	callbacksProcessCmd.Flags().BoolVarP(&callbacksVerbose, "verbose", "v", false, "Show detailed processing info")
 
 	callbacksCmd.AddCommand(callbacksProcessCmd)
 	rootCmd.AddCommand(callbacksCmd)
@@ -165,7 +164,7 @@ func runCallbacksProcess(cmd *cobra.Command, args []string) error {
 				result.Action)
 		}
 
-		if callbacksVerbose {
+		if output.IsVerbose() {
 			fmt.Printf("      From: %s\n", msg.From)
 			fmt.Printf("      Subject: %s\n", msg.Subject)
 		}
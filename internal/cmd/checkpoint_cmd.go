@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -26,8 +27,13 @@ Checkpoint data includes:
 - Hooked bead
 - Modified files list
 - Git branch and last commit
+- Last prompt sent into the session
 - Timestamp
 
+Checkpoints are written automatically as molecule steps advance and when the
+session is nudged or emergency-stopped. After a crash or reboot,
+'gt resume --all' restarts each polecat that has one at its last step.
+
 Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.`,
 }
 
@@ -168,7 +174,7 @@ func runCheckpointRead(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s\n\n", style.Bold.Render("Checkpoint"))
-	fmt.Printf("Timestamp: %s (%s ago)\n", cp.Timestamp.Format("2006-01-02 15:04:05"), cp.Age().Round(1))
+	fmt.Printf("Timestamp: %s (%s ago)\n", format.DateTimeSeconds(cp.Timestamp), cp.Age().Round(1))
 
 	if cp.MoleculeID != "" {
 		fmt.Printf("Molecule: %s\n", cp.MoleculeID)
@@ -197,6 +203,9 @@ func runCheckpointRead(cmd *cobra.Command, args []string) error {
 	if cp.Notes != "" {
 		fmt.Printf("Notes: %s\n", cp.Notes)
 	}
+	if cp.LastPrompt != "" {
+		fmt.Printf("Last Prompt: %s\n", cp.LastPrompt)
+	}
 	if cp.SessionID != "" {
 		fmt.Printf("Session ID: %s\n", cp.SessionID)
 	}
@@ -218,6 +227,28 @@ func runCheckpointClear(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// recordStepCheckpoint records the molecule step a polecat or crew worker
+// moves on to, so a session restarted after a crash or reboot picks up at
+// that step. A nil step means the molecule is complete and clears the
+// checkpoint. Best-effort: failures never block the step transition.
+func recordStepCheckpoint(cwd, townRoot, moleculeID string, step *beads.Issue) {
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil || (roleInfo.Role != RolePolecat && roleInfo.Role != RoleCrew) {
+		return
+	}
+	dir := cwd
+	if gitRoot, err := getGitRoot(); err == nil {
+		dir = gitRoot
+	}
+	if step == nil {
+		_ = checkpoint.Remove(dir)
+		return
+	}
+	_ = checkpoint.Update(dir, func(cp *checkpoint.Checkpoint) {
+		cp.WithMolecule(moleculeID, step.ID, step.Title)
+	})
+}
+
 // detectMoleculeContext tries to detect the current molecule and step from beads.
 func detectMoleculeContext(workDir string, ctx RoleInfo) (moleculeID, stepID, stepTitle string) {
 	b := beads.New(workDir)
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/vcs"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var claimForce bool
+
+var claimCmd = &cobra.Command{
+	Use:     "claim <bead>",
+	GroupID: GroupWork,
+	Short:   "Take a bead to work on by hand in your crew clone",
+	Long: `Claim a bead for yourself, working by hand in a crew clone, the way
+gt sling hands one to an agent.
+
+The bead is hooked and assigned to you (<rig>/crew/<name>), and a branch
+crew/<name>/<bead> is started from the latest default branch. Commit your
+work there, then run gt done: it runs the rig's tests and submits the
+branch to the refinery, so your work merges through the same queue as the
+polecats'.
+
+Your clone must have no uncommitted changes. A bead already assigned to
+someone else needs --force.
+
+Examples:
+  gt claim gt-abc
+  gt claim gt-abc --force      # Take it over from another assignee`,
+	Args: cobra.ExactArgs(1),
+	RunE: runClaim,
+}
+
+func init() {
+	claimCmd.Flags().BoolVarP(&claimForce, "force", "f", false, "Claim a bead assigned to someone else")
+	rootCmd.AddCommand(claimCmd)
+}
+
+func runClaim(cmd *cobra.Command, args []string) error {
+	beadID := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return fmt.Errorf("detecting role: %w", err)
+	}
+	if roleInfo.Role != RoleCrew || roleInfo.Polecat == "" {
+		return fmt.Errorf("gt claim works in a crew clone (agents get work with gt sling)")
+	}
+	ctx := RoleContext{Role: roleInfo.Role, Rig: roleInfo.Rig, Polecat: roleInfo.Polecat, TownRoot: townRoot, WorkDir: cwd}
+	me := buildAgentIdentity(ctx)
+	rigPath := filepath.Join(townRoot, roleInfo.Rig)
+	clonePath := filepath.Join(rigPath, "crew", roleInfo.Polecat)
+
+	bd := beads.New(beads.ResolveBeadsDir(cwd))
+	bead, err := bd.Show(beadID)
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", beadID, err)
+	}
+	switch {
+	case bead.Status == "closed":
+		return fmt.Errorf("%s is closed", beadID)
+	case bead.Assignee != "" && bead.Assignee != me && !claimForce:
+		return fmt.Errorf("%s is assigned to %s (use --force to take it over)", beadID, bead.Assignee)
+	}
+
+	rigVCS, defaultBranch := "", "main"
+	if rigCfg, err := rig.LoadRigConfig(rigPath); err == nil {
+		rigVCS = rigCfg.VCS
+		if rigCfg.DefaultBranch != "" {
+			defaultBranch = rigCfg.DefaultBranch
+		}
+	}
+	repo := vcs.Open(rigVCS, clonePath)
+	status, err := repo.CheckUncommittedWork()
+	if err != nil {
+		return fmt.Errorf("checking %s: %w", clonePath, err)
+	}
+	if status.HasUncommittedChanges {
+		return fmt.Errorf("%s has uncommitted changes; commit or stash them first", clonePath)
+	}
+	if err := repo.Fetch("origin"); err != nil {
+		return fmt.Errorf("fetching origin: %w", err)
+	}
+	branch := fmt.Sprintf("%s%s/%s", constants.BranchCrewPrefix, roleInfo.Polecat, beadID)
+	if exists, _ := repo.BranchExists(branch); exists {
+		err = repo.Checkout(branch) // Picking a claim back up
+	} else {
+		err = repo.CheckoutNewBranch(branch, "origin/"+defaultBranch)
+	}
+	if err != nil {
+		return fmt.Errorf("switching to %s: %w", branch, err)
+	}
+
+	hookCmd := exec.Command("bd", "--no-daemon", "update", beadID, "--status="+beads.StatusHooked, "--assignee="+me)
+	hookCmd.Dir = beads.ResolveHookDir(townRoot, beadID, clonePath)
+	hookCmd.Stderr = os.Stderr
+	if err := hookCmd.Run(); err != nil {
+		return fmt.Errorf("hooking %s: %w", beadID, err)
+	}
+	updateAgentHookBead(me, beadID, clonePath, "")
+	_ = events.LogFeed(events.TypeSling, me, events.SlingPayload(beadID, me))
+
+	fmt.Printf("%s Claimed %s: %s\n", style.Bold.Render("✓"), beadID, bead.Title)
+	fmt.Printf("  Branch: %s\n", branch)
+	if testCmd := getTestCommand(rigPath); testCmd != "" {
+		fmt.Printf("  Tests:  %s\n", testCmd)
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Commit your work on the branch, then run gt done to submit it to the refinery."))
+	return nil
+}
+
+// runCrewTests runs the rig's test command in a crew clone, with the rig's
+// env, as the refinery will when it merges the branch.
+func runCrewTests(r *rig.Rig, clonePath string) error {
+	testCmd := getTestCommand(r.Path)
+	if testCmd == "" {
+		return nil
+	}
+	fmt.Printf("%s Running tests: %s\n", style.Bold.Render("→"), testCmd)
+	cmd := exec.Command("sh", "-c", testCmd) //nolint:gosec // G204: test_command is from trusted rig settings
+	cmd.Dir = clonePath
+	if r.Subdir != "" {
+		cmd.Dir = filepath.Join(clonePath, r.Subdir)
+	}
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	rigEnv, err := config.ResolveRigEnv(r.Path)
+	if err != nil {
+		style.PrintWarning("rig env: %v", err)
+	}
+	if len(rigEnv) > 0 {
+		cmd.Env = os.Environ()
+		for name, value := range rigEnv {
+			cmd.Env = append(cmd.Env, name+"="+value)
+		}
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("tests failed (%s): %w; fix them or submit with --no-test", testCmd, err)
+	}
+	fmt.Printf("%s Tests passed\n", style.Success.Render("✓"))
+	return nil
+}
+
+// pushToRefinery pushes a crew branch into the rig's shared repo, where
+// the refinery picks up polecat branches: crew clones are separate clones,
+// so their branches aren't there otherwise.
+func pushToRefinery(repo vcs.Repo, rigPath, branch string) error {
+	shared := filepath.Join(rigPath, ".repo.git")
+	if _, err := os.Stat(shared); err != nil {
+		shared = filepath.Join(rigPath, "refinery", "rig")
+	}
+	if err := repo.Push(shared, branch, true); err != nil {
+		return fmt.Errorf("pushing %s to the refinery: %w", branch, err)
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Dynamic shell completion.
+//
+// 'gt completion bash|zsh|fish|powershell' (cobra's) generates scripts that
+// call back into gt for candidates. Arguments are completed from the
+// placeholders in each command's Use line, so "start <rig>..." completes
+// rig names for every argument and "peek <rig/polecat>" completes polecat
+// addresses, without each command wiring it up.
+
+// argCompleters complete the argument placeholders used in Use lines.
+var argCompleters = map[string]cobra.CompletionFunc{
+	"rig":            completeRigNames,
+	"rig/polecat":    completePolecatAddresses,
+	"bead-id":        completeBeadIDs,
+	"issue-id":       completeBeadIDs,
+	"root-issue-id":  completeBeadIDs,
+	"pinned-bead-id": completeBeadIDs,
+	"epic-id":        completeBeadIDs,
+	"convoy-id":      completeBeadIDs,
+	"molecule-id":    completeMoleculeIDs,
+	"harness":        completeHarnessNames,
+	"bead-or-formula": func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		beadIDs, _ := completeBeadIDs(cmd, args, toComplete)
+		formulas, _ := completeFormulaNames(cmd, args, toComplete)
+		return append(formulas, beadIDs...), cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+// flagCompleters complete flag values by flag name, on every command.
+var flagCompleters = map[string]cobra.CompletionFunc{
+	"rig":      completeRigNames,
+	"molecule": completeMoleculeIDs,
+	"harness":  completeHarnessNames,
+}
+
+// registerDynamicCompletions attaches completion functions to every
+// command under root whose arguments or flags it knows how to complete.
+func registerDynamicCompletions(root *cobra.Command) {
+	// Completion requests run through persistentPreRun like any command;
+	// they must print nothing but candidates.
+	for _, name := range []string{cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd} {
+		beadsExemptCommands[name] = true
+		branchCheckExemptCommands[name] = true
+	}
+
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if c.ValidArgsFunction == nil {
+			if fn := completionForUse(c.Use); fn != nil {
+				c.ValidArgsFunction = fn
+			}
+		}
+		c.LocalFlags().VisitAll(func(f *pflag.Flag) {
+			if fn, ok := flagCompleters[f.Name]; ok {
+				_ = c.RegisterFlagCompletionFunc(f.Name, fn)
+			}
+		})
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
+
+// completionForUse returns a completion function for the positional
+// arguments a Use line describes, or nil if none of them are known. A
+// trailing "..." placeholder completes every remaining argument.
+func completionForUse(use string) cobra.CompletionFunc {
+	fields := strings.Fields(use)
+	if len(fields) < 2 {
+		return nil
+	}
+	var positional []string
+	variadic := false
+	known := false
+	for _, field := range fields[1:] {
+		if field == "|" || strings.HasPrefix(field, "-") {
+			break
+		}
+		variadic = strings.HasSuffix(field, "...")
+		name := strings.TrimSuffix(field, "...")
+		name = strings.ReplaceAll(strings.Trim(name, "<>[]"), ">/<", "/")
+		positional = append(positional, name)
+		if argCompleters[name] != nil {
+			known = true
+		}
+	}
+	if !known {
+		return nil
+	}
+
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		i := len(args)
+		if i >= len(positional) {
+			if !variadic {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			i = len(positional) - 1
+		}
+		fn := argCompleters[positional[i]]
+		if fn == nil {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		return fn(cmd, args, toComplete)
+	}
+}
+
+// filterPrefix keeps the candidates that start with prefix. Candidates may
+// carry a tab-separated description.
+func filterPrefix(candidates []string, prefix string) []string {
+	out := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// completionRigNames returns the town's rig names, sorted.
+func completionRigNames(townRoot string) []string {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func completeRigNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterPrefix(completionRigNames(townRoot), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePolecatAddresses completes <rig>/<polecat>: rig names until a
+// rig is chosen, then that rig's polecats.
+func completePolecatAddresses(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	rigName, _, hasRig := strings.Cut(toComplete, "/")
+	if !hasRig {
+		var rigs []string
+		for _, name := range filterPrefix(completionRigNames(townRoot), toComplete) {
+			rigs = append(rigs, name+"/")
+		}
+		return rigs, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+
+	entries, err := os.ReadDir(filepath.Join(townRoot, rigName, constants.DirPolecats))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var addresses []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			addresses = append(addresses, rigName+"/"+e.Name())
+		}
+	}
+	return filterPrefix(addresses, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeFormulaNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, name := range townFormulaNames(townRoot) {
+		names = append(names, name+"\tformula")
+	}
+	return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeHarnessNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	h, err := workspace.LoadHarnesses()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(h.Harnesses))
+	for _, harness := range h.Harnesses {
+		names = append(names, harness.Name+"\t"+harness.Path)
+	}
+	return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionBeadCacheTTL is how long bead ID candidates are reused. A Tab
+// press shouldn't wait on bd in every beads database.
+const completionBeadCacheTTL = 30 * time.Second
+
+// completionBeadCache is the cached list of open beads, kept in the town's
+// .runtime directory.
+type completionBeadCache struct {
+	Updated time.Time        `json:"updated"`
+	Beads   []completionBead `json:"beads"`
+}
+
+type completionBead struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Molecule bool   `json:"molecule,omitempty"`
+}
+
+func completionBeadCachePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "completion-beads.json")
+}
+
+// completionBeads returns the town's open beads, from the cache while it is
+// fresh and from bd otherwise.
+func completionBeads(townRoot string) []completionBead {
+	path := completionBeadCachePath(townRoot)
+	var cache completionBeadCache
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is constructed internally
+		if json.Unmarshal(data, &cache) == nil && time.Since(cache.Updated) < completionBeadCacheTTL {
+			return cache.Beads
+		}
+	}
+
+	cache = completionBeadCache{Updated: time.Now()}
+	for _, location := range townBeadsLocations(townRoot) {
+		issues, err := beads.New(location).List(beads.ListOptions{Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range issues {
+			cache.Beads = append(cache.Beads, completionBead{
+				ID:       issue.ID,
+				Title:    issue.Title,
+				Molecule: isMoleculeIssue(issue),
+			})
+		}
+	}
+	sort.Slice(cache.Beads, func(i, j int) bool { return cache.Beads[i].ID < cache.Beads[j].ID })
+	_ = os.MkdirAll(filepath.Dir(path), 0755)
+	_ = util.AtomicWriteJSON(path, &cache)
+	return cache.Beads
+}
+
+func isMoleculeIssue(issue *beads.Issue) bool {
+	if issue.Type == "molecule" {
+		return true
+	}
+	for _, l := range issue.Labels {
+		if l == "gt:molecule" {
+			return true
+		}
+	}
+	return false
+}
+
+func completeBeads(toComplete string, moleculesOnly bool) ([]string, cobra.ShellCompDirective) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var candidates []string
+	for _, b := range completionBeads(townRoot) {
+		if moleculesOnly && !b.Molecule {
+			continue
+		}
+		if strings.HasPrefix(b.ID, toComplete) {
+			candidates = append(candidates, b.ID+"\t"+b.Title)
+		}
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeBeadIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeBeads(toComplete, false)
+}
+
+func completeMoleculeIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeBeads(toComplete, true)
+}
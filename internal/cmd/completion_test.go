@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+func TestCompletionForUse(t *testing.T) {
+	townRoot := setupTestTownForCrewList(t, map[string][]string{"gastown": nil, "beads": nil})
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "polecats", "toast"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	if completionForUse("set <name> <command>") != nil {
+		t.Error("completion registered for unknown placeholders")
+	}
+
+	start := completionForUse("start <rig>...")
+	if got, _ := start(nil, []string{"beads"}, ""); !reflect.DeepEqual(got, []string{"beads", "gastown"}) {
+		t.Errorf("start <rig>... = %v", got)
+	}
+
+	peek := completionForUse("peek <rig/polecat> [count]")
+	if got, _ := peek(nil, nil, "ga"); !reflect.DeepEqual(got, []string{"gastown/"}) {
+		t.Errorf("peek ga = %v", got)
+	}
+	if got, _ := peek(nil, nil, "gastown/"); !reflect.DeepEqual(got, []string{"gastown/toast"}) {
+		t.Errorf("peek gastown/ = %v", got)
+	}
+	if got, d := peek(nil, []string{"gastown/toast"}, ""); got != nil || d != cobra.ShellCompDirectiveDefault {
+		t.Errorf("peek count = %v, %v", got, d)
+	}
+
+	remove := completionForUse("remove <rig>/<polecat>... | <rig> --all")
+	if got, _ := remove(nil, []string{"gastown/toast"}, "gastown/t"); !reflect.DeepEqual(got, []string{"gastown/toast"}) {
+		t.Errorf("remove = %v", got)
+	}
+}
+
+func TestCompletionBeadsUsesCache(t *testing.T) {
+	townRoot := setupTestTownForCrewList(t, nil)
+	t.Chdir(townRoot)
+
+	// A fresh cache answers without asking bd
+	cache := completionBeadCache{
+		Updated: time.Now(),
+		Beads: []completionBead{
+			{ID: "gt-abc", Title: "Fix the thing"},
+			{ID: "gt-mol1", Title: "Patrol", Molecule: true},
+		},
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, ".runtime"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.AtomicWriteJSON(completionBeadCachePath(townRoot), &cache); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := completeBeadIDs(nil, nil, "gt-a"); !reflect.DeepEqual(got, []string{"gt-abc\tFix the thing"}) {
+		t.Errorf("bead IDs = %v", got)
+	}
+	if got, _ := completeMoleculeIDs(nil, nil, ""); !reflect.DeepEqual(got, []string{"gt-mol1\tPatrol"}) {
+		t.Errorf("molecule IDs = %v", got)
+	}
+}
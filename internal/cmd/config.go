@@ -7,9 +7,11 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -29,7 +31,10 @@ Commands:
   gt config agent get <name>         Show agent configuration
   gt config agent set <name> <cmd>   Set custom agent command
   gt config agent remove <name>      Remove custom agent
-  gt config default-agent [name]     Get or set default agent`,
+  gt config default-agent [name]     Get or set default agent
+  gt config locale [tag]             Get or set report locale
+  gt config doctor                   Check agent, tier and sandbox settings
+  gt config notifications            Show Slack/Discord notification routing`,
 }
 
 // Agent subcommands
@@ -119,6 +124,28 @@ Examples:
 	RunE: runConfigDefaultAgent,
 }
 
+// Locale subcommand
+
+var configLocaleCmd = &cobra.Command{
+	Use:   "locale [tag]",
+	Short: "Get or set the locale for report formatting",
+	Long: `Get or set the locale used to format dates and numbers in reports.
+
+Generated reports (cost digests, audit timelines, mail listings) format
+timestamps, thousands separators and currency for this locale. Use
+"system" to follow LC_ALL/LC_TIME/LANG, or "iso" (the default) for
+ISO 8601 dates and ungrouped numbers. GT_LOCALE overrides the setting.
+
+JSON output and bead fields are never localized.
+
+Examples:
+  gt config locale            # Show current locale
+  gt config locale de-DE      # German dates and separators
+  gt config locale system     # Follow the environment`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigLocale,
+}
+
 // Flags
 var (
 	configAgentListJSON bool
@@ -444,6 +471,49 @@ func runConfigDefaultAgent(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConfigLocale(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	settingsPath := config.TownSettingsPath(townRoot)
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	if len(args) == 0 {
+		configured := townSettings.Locale
+		if configured == "" {
+			configured = format.DefaultTag
+		}
+		fmt.Printf("Locale: %s\n", style.Bold.Render(configured))
+		if env := os.Getenv("GT_LOCALE"); env != "" {
+			fmt.Printf("%s\n", style.Dim.Render("Overridden by GT_LOCALE="+env))
+		}
+		fmt.Printf("Example: %s  %s\n", format.DateTime(time.Now()), format.Currency(1234.5))
+		return nil
+	}
+
+	tag := args[0]
+	if err := format.SetLocale(tag); err != nil {
+		return err
+	}
+	if !strings.EqualFold(tag, "system") {
+		tag = format.Current().Tag
+	}
+
+	townSettings.Locale = tag
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	fmt.Printf("Locale set to '%s'\n", style.Bold.Render(tag))
+	fmt.Printf("Example: %s  %s\n", format.DateTime(time.Now()), format.Currency(1234.5))
+	return nil
+}
+
 func init() {
 	// Add flags
 	configAgentListCmd.Flags().BoolVar(&configAgentListJSON, "json", false, "Output as JSON")
@@ -462,6 +532,7 @@ func init() {
 	// Add subcommands to config
 	configCmd.AddCommand(configAgentCmd)
 	configCmd.AddCommand(configDefaultAgentCmd)
+	configCmd.AddCommand(configLocaleCmd)
 
 	// Register with root
 	rootCmd.AddCommand(configCmd)
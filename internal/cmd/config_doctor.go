@@ -0,0 +1,454 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/models"
+	"github.com/steveyegge/gastown/internal/sandbox"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	configDoctorJSON   bool
+	configDoctorOnline bool
+)
+
+var configDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check agent, model tier and sandbox settings before a run",
+	Long: `Check the town's agent, model tier and sandbox settings, so a bad
+setting fails now rather than when work is dispatched overnight.
+
+Checks:
+  tiers      every tier in models.tiers has a known provider and a model
+             name that looks like one of the provider's models
+  keys       tiers on providers that need an API key have one
+  agents     agents named by default_agent, role_agents, rig settings and
+             spawn_profiles are built in or defined, and their CLIs are
+             installed; each tier has an agent CLI that can run it
+  refs       tiers named by spawn_profiles and by formula steps
+             (.beads/formulas in the town and rigs) are defined
+  sandbox    rig and spawn profile sandbox profiles name a known backend
+             whose tool is installed
+
+Model names are checked offline by their shape; --online also asks each
+provider whether it serves the tier's model (as 'gt models test' does).
+
+Exits 1 if any finding is an error.
+
+Examples:
+  gt config doctor
+  gt config doctor --online
+  gt config doctor --json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigDoctor,
+}
+
+func init() {
+	configDoctorCmd.Flags().BoolVar(&configDoctorJSON, "json", false, "Output as JSON")
+	configDoctorCmd.Flags().BoolVar(&configDoctorOnline, "online", false, "Also ask each provider whether it serves the tier's model")
+	configCmd.AddCommand(configDoctorCmd)
+}
+
+// configFinding is one problem gt config doctor found.
+type configFinding struct {
+	Severity string `json:"severity"` // formula.SeverityError or SeverityWarning
+	Where    string `json:"where"`    // The setting at fault
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// configDoctorInput is the configuration gt config doctor checks.
+type configDoctorInput struct {
+	Town     *config.TownSettings
+	Rigs     map[string]*config.RigSettings // By rig name; nil settings are skipped
+	Formulas map[string]*formula.Formula    // By file path
+}
+
+// configDoctorLookPath finds agent CLIs. It is a variable so tests don't
+// depend on what is installed.
+var configDoctorLookPath = exec.LookPath
+
+func runConfigDoctor(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	in, err := loadConfigDoctorInput(townRoot)
+	if err != nil {
+		return err
+	}
+	findings := diagnoseConfig(in)
+	if configDoctorOnline {
+		findings = append(findings, pingTiers(in.Town.Models)...)
+	}
+
+	failed := 0
+	for _, f := range findings {
+		if f.Severity == formula.SeverityError {
+			failed++
+		}
+	}
+	if configDoctorJSON {
+		if findings == nil {
+			findings = []configFinding{}
+		}
+		if err := outputJSON(findings); err != nil {
+			return err
+		}
+	} else if len(findings) == 0 {
+		fmt.Printf("%s Agent, model and sandbox settings look good\n", style.Success.Render("✓"))
+	} else {
+		for _, f := range findings {
+			mark := style.Warning.Render("⚠")
+			if f.Severity == formula.SeverityError {
+				mark = style.Error.Render("✗")
+			}
+			fmt.Printf("%s %s: %s\n", mark, style.Bold.Render(f.Where), f.Message)
+			if f.Fix != "" {
+				fmt.Printf("    %s\n", style.Dim.Render("→ "+f.Fix))
+			}
+		}
+		fmt.Printf("\n%d error(s), %d warning(s)\n", failed, len(findings)-failed)
+	}
+	if failed > 0 {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// loadConfigDoctorInput reads the town settings, each rig's settings and
+// the formulas in the town's and rigs' .beads/formulas directories.
+func loadConfigDoctorInput(townRoot string) (*configDoctorInput, error) {
+	town, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	_ = config.LoadAgentRegistry(config.DefaultAgentRegistryPath(townRoot))
+
+	in := &configDoctorInput{
+		Town:     town,
+		Rigs:     make(map[string]*config.RigSettings),
+		Formulas: make(map[string]*formula.Formula),
+	}
+	formulaDirs := []string{filepath.Join(townRoot, ".beads", "formulas")}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err == nil {
+		for name := range rigsConfig.Rigs {
+			rigPath := filepath.Join(townRoot, name)
+			settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+			if err != nil && !errors.Is(err, config.ErrNotFound) {
+				return nil, fmt.Errorf("loading %s settings: %w", name, err)
+			}
+			in.Rigs[name] = settings
+			_ = config.LoadRigAgentRegistry(config.RigAgentRegistryPath(rigPath))
+			formulaDirs = append(formulaDirs,
+				filepath.Join(rigPath, ".beads", "formulas"),
+				filepath.Join(rigPath, "mayor", "rig", ".beads", "formulas"))
+		}
+	}
+
+	for _, dir := range formulaDirs {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*.formula.toml"))
+		for _, path := range paths {
+			// Unparseable formulas are gt mol lint's business
+			if f, err := formula.ParseFile(path); err == nil {
+				in.Formulas[path] = f
+			}
+		}
+	}
+	return in, nil
+}
+
+// diagnoseConfig checks the agent, tier and sandbox settings in in.
+func diagnoseConfig(in *configDoctorInput) []configFinding {
+	var findings []configFinding
+	add := func(severity, where, fix, format string, args ...any) {
+		findings = append(findings, configFinding{Severity: severity, Where: where, Message: fmt.Sprintf(format, args...), Fix: fix})
+	}
+	town := in.Town
+	if town == nil {
+		town = config.NewTownSettings()
+	}
+	// Tiers. Broken tiers are reported and left out, so the rest resolve.
+	var usable *config.ModelsConfig
+	if town.Models != nil {
+		usable = &config.ModelsConfig{Tiers: make(map[string]*config.ModelSpec)}
+		for _, name := range sortedKeys(town.Models.Tiers) {
+			spec := town.Models.Tiers[name]
+			where := "models.tiers." + name
+			switch {
+			case spec == nil:
+				continue
+			case !slices.Contains(models.Providers, strings.ToLower(spec.Provider)):
+				add(formula.SeverityError, where, "set provider to one of "+strings.Join(models.Providers, ", "),
+					"unknown provider %q", spec.Provider)
+				continue
+			case spec.Model == "":
+				add(formula.SeverityError, where, `set "model" to the provider's model name`, "no model")
+				continue
+			}
+			usable.Tiers[name] = spec
+		}
+	}
+	tiers, _ := models.Resolve(usable)
+	tierNames := make([]string, len(tiers))
+	for i := range tiers {
+		tierNames[i] = tiers[i].Name
+	}
+	findTier := func(name string) *models.Tier {
+		for i := range tiers {
+			if strings.EqualFold(tiers[i].Name, name) {
+				return &tiers[i]
+			}
+		}
+		return nil
+	}
+	undefinedTier := func(where, name string) {
+		add(formula.SeverityError, where, "add it under models.tiers in settings/config.json, or use "+strings.Join(tierNames, ", "),
+			"tier %q is not defined", name)
+	}
+
+	for i := range tiers {
+		t := &tiers[i]
+		where := "models.tiers." + t.Name
+		if t.Default {
+			where = "tier " + t.Name
+		}
+		if !plausibleModel(t) {
+			add(formula.SeverityWarning, where, "check the name, or run 'gt config doctor --online'",
+				"%q doesn't look like one of %s's model names", t.Model, t.Provider)
+		}
+
+		key, err := models.LookupKey(t.ModelSpec)
+		env := t.KeyEnv
+		if env == "" {
+			env = models.KeyEnvVars[t.Provider]
+		}
+		switch {
+		case err != nil:
+			add(formula.SeverityError, where, "", "reading API key: %v", err)
+		case key == nil && models.NeedsKey(t.Provider) && t.Provider == models.Anthropic:
+			// claude can run on its own login instead
+			if !t.Default {
+				add(formula.SeverityWarning, where, fmt.Sprintf("set %s or run 'gt models key set %s', unless claude is logged in", env, t.Provider),
+					"no %s API key", t.Provider)
+			}
+		case key == nil && models.NeedsKey(t.Provider):
+			add(formula.SeverityError, where, fmt.Sprintf("set %s or run 'gt models key set %s'", env, t.Provider),
+				"no %s API key", t.Provider)
+		}
+
+		if !t.Default && !slices.ContainsFunc(models.AgentCommands[t.Provider], onPath) {
+			add(formula.SeverityError, where, "install "+strings.Join(models.AgentCommands[t.Provider], " or "),
+				"no agent that runs %s models is installed", t.Provider)
+		}
+	}
+
+	// Agents named anywhere must exist, and their CLIs must be installed.
+	checked := make(map[string]bool)
+	checkAgent := func(where, name string, rig *config.RigSettings) *config.RuntimeConfig {
+		if name == "" {
+			return nil
+		}
+		rc := lookupDoctorAgent(name, town, rig)
+		if rc == nil {
+			add(formula.SeverityError, where, "define it with 'gt config agent set', or see 'gt config agent list'",
+				"agent %q is not defined (sessions would fall back to claude)", name)
+			return nil
+		}
+		if bin := filepath.Base(rc.Command); rc.Command != "" && !checked[bin] {
+			checked[bin] = true
+			if !onPath(rc.Command) {
+				add(formula.SeverityError, where, "install "+bin+" or pick another agent",
+					"agent %q runs %s, which is not installed", name, bin)
+			}
+		}
+		return rc
+	}
+	defaultRC := checkAgent("default_agent", town.DefaultAgent, nil)
+	if town.DefaultAgent == "" {
+		defaultRC = checkAgent("default_agent", "claude", nil)
+	}
+	for _, role := range sortedKeys(town.RoleAgents) {
+		checkAgent("role_agents."+role, town.RoleAgents[role], nil)
+	}
+	for name, agent := range town.Agents {
+		if agent == nil || agent.Command == "" {
+			add(formula.SeverityError, "agents."+name, "set its command with 'gt config agent set "+name+" <command>'",
+				"agent has no command")
+		}
+	}
+	for _, name := range sortedKeys(in.Rigs) {
+		rs := in.Rigs[name]
+		if rs == nil {
+			continue
+		}
+		checkAgent("rig "+name+" agent", rs.Agent, rs)
+		for _, role := range sortedKeys(rs.RoleAgents) {
+			checkAgent("rig "+name+" role_agents."+role, rs.RoleAgents[role], rs)
+		}
+		if rs.Sandbox != nil {
+			checkSandboxProfile(add, "rig "+name+" sandbox.profile", rs.Sandbox.Profile)
+		}
+	}
+
+	// Spawn profiles.
+	for _, name := range sortedKeys(town.SpawnProfiles) {
+		p := town.SpawnProfiles[name]
+		if p == nil {
+			continue
+		}
+		where := "spawn_profiles." + name
+		var rig *config.RigSettings
+		if p.Rig != "" {
+			var ok bool
+			if rig, ok = in.Rigs[p.Rig]; !ok && len(in.Rigs) > 0 {
+				add(formula.SeverityError, where, "see 'gt rig list'", "rig %q does not exist", p.Rig)
+			}
+		}
+		rc := defaultRC
+		if p.Agent != "" {
+			rc = checkAgent(where+".agent", p.Agent, rig)
+		} else if rig != nil && rig.Agent != "" {
+			rc = lookupDoctorAgent(rig.Agent, town, rig)
+		}
+		if p.Tier != "" {
+			t := findTier(p.Tier)
+			switch {
+			case t == nil:
+				undefinedTier(where+".tier", p.Tier)
+			case rc != nil:
+				if _, ok := t.ModelArg(filepath.Base(rc.Command)); !ok {
+					add(formula.SeverityError, where, "set agent to one that runs "+strings.Join(models.AgentCommands[t.Provider], "/"),
+						"tier %s runs on %s, which the %s agent can't use", t.Name, t.Provider, filepath.Base(rc.Command))
+				}
+			}
+		}
+		if p.Sandbox != "" {
+			checkSandboxProfile(add, where+".sandbox", p.Sandbox)
+		}
+	}
+
+	// Tiers formula steps ask for.
+	for _, path := range sortedKeys(in.Formulas) {
+		f := in.Formulas[path]
+		for _, s := range f.Steps {
+			if s.Tier != "" && findTier(s.Tier) == nil {
+				undefinedTier(fmt.Sprintf("%s step %s", filepath.Base(path), s.ID), s.Tier)
+			}
+		}
+		for _, s := range f.Template {
+			if s.Tier != "" && findTier(s.Tier) == nil {
+				undefinedTier(fmt.Sprintf("%s template %s", filepath.Base(path), s.ID), s.Tier)
+			}
+		}
+	}
+	return findings
+}
+
+// lookupDoctorAgent finds a named agent the way sessions do: the rig's
+// agents, then the town's, then the built-in presets. It returns nil for
+// an unknown name, where sessions silently fall back to claude.
+func lookupDoctorAgent(name string, town *config.TownSettings, rig *config.RigSettings) *config.RuntimeConfig {
+	if rig != nil && rig.Agents[name] != nil {
+		return rig.Agents[name]
+	}
+	if town.Agents[name] != nil {
+		return town.Agents[name]
+	}
+	if config.GetAgentPresetByName(name) != nil {
+		return config.RuntimeConfigFromPreset(config.AgentPreset(name))
+	}
+	return nil
+}
+
+// checkSandboxProfile reports a sandbox profile naming an unknown backend
+// or one whose tool is missing.
+func checkSandboxProfile(add func(severity, where, fix, format string, args ...any), where, profile string) {
+	_, err := sandbox.Lookup(profile)
+	switch {
+	case errors.Is(err, sandbox.ErrUnknownBackend):
+		add(formula.SeverityError, where, `use "auto", "off" or a backend name`, "%v", err)
+	case errors.Is(err, sandbox.ErrUnavailable):
+		add(formula.SeverityError, where, `install it, or set the profile to "off"`, "%v", err)
+	case err != nil:
+		add(formula.SeverityError, where, "", "%v", err)
+	}
+}
+
+// plausibleModel reports whether a tier's model name looks like one of its
+// provider's. Gateways (a base_url) and Ollama serve whatever they name.
+func plausibleModel(t *models.Tier) bool {
+	if t.ModelSpec.BaseURL != "" {
+		return true
+	}
+	switch t.Provider {
+	case models.Anthropic:
+		return slices.Contains(models.DefaultTiers, t.Model) || t.Model == "opusplan" ||
+			strings.HasPrefix(t.Model, "claude-")
+	case models.OpenAI:
+		for _, prefix := range []string{"gpt-", "o1", "o3", "o4", "codex-"} {
+			if strings.HasPrefix(t.Model, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// pingTiers asks each tier's provider whether it serves the tier's model.
+func pingTiers(cfg *config.ModelsConfig) []configFinding {
+	tiers, err := models.Resolve(cfg)
+	if err != nil {
+		return nil // Already reported
+	}
+	client := &http.Client{Timeout: models.PingTimeout}
+	var findings []configFinding
+	for i := range tiers {
+		t := &tiers[i]
+		key, err := models.LookupKey(t.ModelSpec)
+		if err != nil || (key == nil && models.NeedsKey(t.Provider)) {
+			continue // Already reported
+		}
+		if err := models.Ping(context.Background(), client, t, key); err != nil {
+			findings = append(findings, configFinding{
+				Severity: formula.SeverityError,
+				Where:    "tier " + t.Name,
+				Message:  fmt.Sprintf("%s/%s: %v", t.Provider, t.Model, err),
+			})
+		}
+	}
+	return findings
+}
+
+func onPath(command string) bool {
+	_, err := configDoctorLookPath(command)
+	return err == nil
+}
+
+// sortedKeys returns a map's keys in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestDiagnoseConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("GT_TEST_KEY", "sk-test")
+	installed := map[string]bool{"claude": true}
+	orig := configDoctorLookPath
+	configDoctorLookPath = func(name string) (string, error) {
+		if installed[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+	t.Cleanup(func() { configDoctorLookPath = orig })
+
+	town := config.NewTownSettings()
+	town.Models = &config.ModelsConfig{Tiers: map[string]*config.ModelSpec{
+		"fast":   {Provider: "openai", Model: "gpt-5-mini"},
+		"typo":   {Provider: "anthropic", Model: "sonet", KeyEnv: "GT_TEST_KEY"},
+		"broken": {Provider: "acme", Model: "x"},
+	}}
+	town.RoleAgents = map[string]string{"witness": "claude-tiny"}
+	town.SpawnProfiles = map[string]*config.SpawnProfile{
+		"docs":  {Tier: "cheap"},
+		"quick": {Tier: "fast"},
+	}
+	in := &configDoctorInput{
+		Town: town,
+		Rigs: map[string]*config.RigSettings{
+			"gastown": {Sandbox: &config.SandboxConfig{Profile: "firejail"}},
+			"empty":   nil,
+		},
+		Formulas: map[string]*formula.Formula{
+			"/town/.beads/formulas/release.formula.toml": {Steps: []formula.Step{
+				{ID: "bump", Tier: "haiku"},
+				{ID: "review", Tier: "genius"},
+			}},
+		},
+	}
+
+	var got []string
+	for _, f := range diagnoseConfig(in) {
+		got = append(got, f.Severity+" "+f.Where+": "+f.Message)
+	}
+	all := strings.Join(got, "\n")
+	for _, want := range []string{
+		`error models.tiers.broken: unknown provider "acme"`,
+		`warning models.tiers.typo: "sonet" doesn't look like one of anthropic's model names`,
+		`error models.tiers.fast: no openai API key`,
+		`error models.tiers.fast: no agent that runs openai models is installed`,
+		`error role_agents.witness: agent "claude-tiny" is not defined`,
+		`error spawn_profiles.docs.tier: tier "cheap" is not defined`,
+		`error spawn_profiles.quick: tier fast runs on openai, which the claude agent can't use`,
+		`error rig gastown sandbox.profile: unknown sandbox backend "firejail"`,
+		`error release.formula.toml step review: tier "genius" is not defined`,
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	for _, unwanted := range []string{"step bump", "tier opus", "models.tiers.typo: no"} {
+		if strings.Contains(all, unwanted) {
+			t.Errorf("unexpected finding mentioning %q in:\n%s", unwanted, all)
+		}
+	}
+
+	if findings := diagnoseConfig(&configDoctorInput{Town: config.NewTownSettings()}); len(findings) != 0 {
+		t.Errorf("default settings: %+v, want no findings", findings)
+	}
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/notify"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	configNotifySinkURL    string
+	configNotifySinkURLEnv string
+)
+
+var configNotifyCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "Show Slack/Discord notification sinks and routes",
+	Long: `Show external notification sinks and event routing.
+
+The daemon posts selected town events to Slack or Discord incoming
+webhooks. Routes map each event to one or more named sinks:
+
+  polecat_done       A polecat ran 'gt done'
+  merge_failed       The refinery failed to merge a branch
+  escalation         An escalation was sent or re-escalated
+  molecule_complete  A molecule's last step finished
+  budget             Spend reached a budget's warning threshold or cap
+  approval           A molecule reached a Gate: human step
+  step_slow          A molecule step ran past 3x its usual duration
+  digest             The Deacon's nightly digest (gt digest --send)
+  *                  All of the above
+
+Configuration lives in the "notifications" section of settings/config.json.
+Restart the daemon after changes.
+
+Examples:
+  gt config notifications
+  gt config notifications sink ops slack --url-env GT_SLACK_WEBHOOK
+  gt config notifications route merge_failed ops
+  gt config notifications route '*' ops
+  gt config notifications test ops`,
+	Args: cobra.NoArgs,
+	RunE: runConfigNotify,
+}
+
+var configNotifySinkCmd = &cobra.Command{
+	Use:   "sink <name> <slack|discord>",
+	Short: "Add or replace a notification sink",
+	Long: `Add or replace a named notification sink.
+
+Prefer --url-env so the webhook URL (a secret) stays out of settings files.
+
+Examples:
+  gt config notifications sink ops slack --url-env GT_SLACK_WEBHOOK
+  gt config notifications sink dev discord --url https://discord.com/api/webhooks/...`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigNotifySink,
+}
+
+var configNotifyRouteCmd = &cobra.Command{
+	Use:   "route <event> [sink...]",
+	Short: "Route a notification event to sinks",
+	Long: `Set the sinks an event is delivered to. With no sinks, the route is removed.
+
+Examples:
+  gt config notifications route merge_failed ops dev
+  gt config notifications route polecat_done     # Stop routing`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runConfigNotifyRoute,
+}
+
+var configNotifyTestCmd = &cobra.Command{
+	Use:   "test <sink>",
+	Short: "Send a test message to a sink",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigNotifyTest,
+}
+
+func init() {
+	configNotifySinkCmd.Flags().StringVar(&configNotifySinkURL, "url", "", "Webhook URL")
+	configNotifySinkCmd.Flags().StringVar(&configNotifySinkURLEnv, "url-env", "", "Environment variable holding the webhook URL")
+
+	configNotifyCmd.AddCommand(configNotifySinkCmd)
+	configNotifyCmd.AddCommand(configNotifyRouteCmd)
+	configNotifyCmd.AddCommand(configNotifyTestCmd)
+	configCmd.AddCommand(configNotifyCmd)
+}
+
+// loadNotifySettings loads town settings for notification commands.
+func loadNotifySettings() (string, *config.TownSettings, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settingsPath := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Notifications == nil {
+		settings.Notifications = &config.NotificationsConfig{}
+	}
+	return settingsPath, settings, nil
+}
+
+func runConfigNotify(cmd *cobra.Command, args []string) error {
+	_, settings, err := loadNotifySettings()
+	if err != nil {
+		return err
+	}
+	cfg := settings.Notifications
+
+	if len(cfg.Sinks) == 0 {
+		fmt.Println("No notification sinks configured.")
+		fmt.Printf("%s\n", style.Dim.Render("Add one with: gt config notifications sink <name> slack --url-env GT_SLACK_WEBHOOK"))
+		return nil
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render("Sinks:"))
+	names := make([]string, 0, len(cfg.Sinks))
+	for name := range cfg.Sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sc := cfg.Sinks[name]
+		target := style.Dim.Render("(inline url)")
+		if sc.URLEnv != "" {
+			target = style.Dim.Render("$" + sc.URLEnv)
+		}
+		status := ""
+		if _, err := notify.NewSink(sc); err != nil {
+			status = "  " + style.Warning.Render(err.Error())
+		}
+		fmt.Printf("  %-12s %-8s %s%s\n", name, sc.Type, target, status)
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Routes:"))
+	if len(cfg.Routes) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none - nothing will be sent)"))
+		return nil
+	}
+	for _, event := range append(notify.Events(), notify.EventAll) {
+		if sinks, ok := cfg.Routes[event]; ok {
+			fmt.Printf("  %-18s → %s\n", event, strings.Join(sinks, ", "))
+		}
+	}
+	return nil
+}
+
+func runConfigNotifySink(cmd *cobra.Command, args []string) error {
+	name, sinkType := args[0], args[1]
+	if sinkType != notify.SinkSlack && sinkType != notify.SinkDiscord {
+		return fmt.Errorf("unknown sink type %q: use %s or %s", sinkType, notify.SinkSlack, notify.SinkDiscord)
+	}
+	if (configNotifySinkURL == "") == (configNotifySinkURLEnv == "") {
+		return fmt.Errorf("specify exactly one of --url or --url-env")
+	}
+
+	settingsPath, settings, err := loadNotifySettings()
+	if err != nil {
+		return err
+	}
+	if settings.Notifications.Sinks == nil {
+		settings.Notifications.Sinks = make(map[string]*config.NotificationSink)
+	}
+	settings.Notifications.Sinks[name] = &config.NotificationSink{
+		Type:   sinkType,
+		URL:    configNotifySinkURL,
+		URLEnv: configNotifySinkURLEnv,
+	}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	fmt.Printf("%s Sink '%s' (%s) saved\n", style.Bold.Render("✓"), name, sinkType)
+	if len(settings.Notifications.Routes) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("Route events to it: gt config notifications route '*' "+name))
+	}
+	return nil
+}
+
+func runConfigNotifyRoute(cmd *cobra.Command, args []string) error {
+	event, sinks := args[0], args[1:]
+
+	settingsPath, settings, err := loadNotifySettings()
+	if err != nil {
+		return err
+	}
+	if !notify.ValidEvent(event) {
+		return fmt.Errorf("unknown event %q (valid: %s, *)", event, strings.Join(notify.Events(), ", "))
+	}
+
+	cfg := settings.Notifications
+	if cfg.Routes == nil {
+		cfg.Routes = make(map[string][]string)
+	}
+
+	if len(sinks) == 0 {
+		delete(cfg.Routes, event)
+	} else {
+		for _, name := range sinks {
+			if _, ok := cfg.Sinks[name]; !ok {
+				return fmt.Errorf("unknown sink %q: add it with 'gt config notifications sink'", name)
+			}
+		}
+		cfg.Routes[event] = sinks
+	}
+
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	if len(sinks) == 0 {
+		fmt.Printf("%s Route for '%s' removed\n", style.Bold.Render("✓"), event)
+	} else {
+		fmt.Printf("%s %s → %s\n", style.Bold.Render("✓"), event, strings.Join(sinks, ", "))
+	}
+	fmt.Printf("  %s\n", style.Dim.Render("Restart the daemon to apply: gt daemon stop && gt daemon start"))
+	return nil
+}
+
+func runConfigNotifyTest(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	_, settings, err := loadNotifySettings()
+	if err != nil {
+		return err
+	}
+	sc, ok := settings.Notifications.Sinks[name]
+	if !ok {
+		return fmt.Errorf("unknown sink %q", name)
+	}
+	sink, err := notify.NewSink(sc)
+	if err != nil {
+		return fmt.Errorf("sink %q: %w", name, err)
+	}
+
+	msg := &notify.Message{
+		Title: "🔔 Gas Town test notification",
+		Text:  fmt.Sprintf("Sink '%s' is configured correctly.", name),
+	}
+	if err := sink.Send(context.Background(), msg); err != nil {
+		return fmt.Errorf("sending test message: %w", err)
+	}
+	fmt.Printf("%s Test message sent to '%s'\n", style.Bold.Render("✓"), name)
+	return nil
+}
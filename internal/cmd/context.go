@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/recycle"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	contextJSON       bool
+	contextTranscript string
+)
+
+var contextCmd = &cobra.Command{
+	Use:     "context",
+	GroupID: GroupAgents,
+	Short:   "Show how full agent sessions' context windows are",
+	Long: `Show the context window utilization of agent sessions, as last recorded
+after each of their turns, fullest first.
+
+Long-lived sessions (Mayor, Deacon, witnesses, refineries and crew by
+default) are recycled before quality degrades deep into the window. When
+a session passes context_recycle.threshold_percent (default 70%) of the
+window, its agent is asked to save a summary of where it is
+(gt summarize) and run 'gt handoff'; the fresh session picks up the hook
+and the summary. An agent that carries on to force_percent (default 85%)
+is handed off with the collected state (gt handoff -c).
+
+Configure it in settings/config.json:
+
+  "context_recycle": {"threshold_percent": 70, "force_percent": 85,
+                      "window_tokens": 200000, "roles": ["mayor", "crew"]}
+
+Examples:
+  gt context
+  gt context --json`,
+	Args: cobra.NoArgs,
+	RunE: runContext,
+}
+
+var contextCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Record this session's context usage and recycle it if due (Stop hook)",
+	Long: `Read how full this session's context window is from its transcript,
+record it, and apply the recycling policy: ask the agent to hand off past
+the threshold, or hand the session off past the force threshold.
+
+Claude sessions run this from their Stop hook, which passes the
+transcript path on stdin. Outside a Gas Town session it does nothing.
+
+Examples:
+  gt context check
+  gt context check --transcript ~/.claude/projects/x/session.jsonl`,
+	Args: cobra.NoArgs,
+	RunE: runContextCheck,
+}
+
+func init() {
+	contextCmd.Flags().BoolVar(&contextJSON, "json", false, "Output as JSON")
+	contextCheckCmd.Flags().StringVar(&contextTranscript, "transcript", "", "Session transcript to read (default: from the hook's stdin)")
+
+	contextCmd.AddCommand(contextCheckCmd)
+	rootCmd.AddCommand(contextCmd)
+}
+
+func runContext(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	readings, err := recycle.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading context usage: %w", err)
+	}
+	if contextJSON {
+		if readings == nil {
+			readings = []*recycle.Reading{}
+		}
+		return outputJSON(readings)
+	}
+	if len(readings) == 0 {
+		fmt.Println(style.Dim.Render("No context usage recorded yet"))
+		return nil
+	}
+
+	policy := contextPolicy(townRoot)
+	fmt.Printf("%s\n\n", style.Bold.Render("Context windows"))
+	for _, r := range readings {
+		icon := style.Success.Render("●")
+		if r.Percent >= policy.Threshold {
+			icon = style.Warning.Render("●")
+		}
+		detail := fmt.Sprintf("%dk/%dk tokens, %s", r.Tokens/1000, r.Window/1000, formatAge(r.Updated))
+		if !r.Asked.IsZero() {
+			detail += ", asked to hand off " + formatAge(r.Asked)
+		}
+		fmt.Printf("  %s %-24s %-9s %3d%%  %s\n", icon, r.Session, r.Role, r.Percent, style.Dim.Render(detail))
+	}
+	return nil
+}
+
+func runContextCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil // Hooks run everywhere
+	}
+	transcriptPath := contextTranscript
+	var input *hookInput
+	if transcriptPath == "" {
+		input = readStdinJSON()
+		if input == nil || input.TranscriptPath == "" {
+			return nil
+		}
+		transcriptPath = input.TranscriptPath
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	info, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return nil
+	}
+	session, err := getCurrentTmuxSession()
+	if err != nil || session == "" {
+		return nil // Only tmux sessions can be handed off
+	}
+
+	tokens, err := recycle.ReadUsage(transcriptPath)
+	if err != nil {
+		return nil // Never fail the agent's turn over a reading
+	}
+	policy := contextPolicy(townRoot)
+	reading := &recycle.Reading{
+		Session:    session,
+		Role:       string(info.Role),
+		Transcript: transcriptPath,
+		Tokens:     tokens,
+		Window:     policy.Window,
+		Percent:    policy.Percent(tokens),
+		Updated:    time.Now(),
+	}
+	// A handed-off session starts a new transcript; the ask was for the old one
+	if prev, _ := recycle.Read(townRoot, session); prev != nil && prev.Transcript == transcriptPath {
+		reading.Asked = prev.Asked
+	}
+
+	action := policy.Decide(reading.Role, reading.Percent, !reading.Asked.IsZero())
+	if action == recycle.ActionAsk && input != nil && input.StopHookActive {
+		action = recycle.ActionNone // Already continuing from a Stop hook; ask after this turn
+	}
+	if action == recycle.ActionAsk {
+		reading.Asked = time.Now()
+	}
+	_ = recycle.Write(townRoot, reading)
+
+	switch action {
+	case recycle.ActionAsk:
+		_ = events.LogFeed(events.TypeContextRecycle, session, events.ContextRecyclePayload(session, string(action), tokens, reading.Percent))
+		return askContextHandoff(reading, policy)
+	case recycle.ActionForce:
+		_ = events.LogFeed(events.TypeContextRecycle, session, events.ContextRecyclePayload(session, string(action), tokens, reading.Percent))
+		subject := fmt.Sprintf("♻️ Context recycle: %d%% of the window used", reading.Percent)
+		handoff := exec.Command("gt", "handoff", "--collect", "--subject", subject)
+		handoff.Stdout = os.Stderr
+		handoff.Stderr = os.Stderr
+		return handoff.Run()
+	}
+	return nil
+}
+
+// askContextHandoff tells the agent, through the Stop hook's decision, to
+// summarize its state and hand off before its next turn.
+func askContextHandoff(r *recycle.Reading, policy recycle.Policy) error {
+	reason := fmt.Sprintf(`Your context window is %d%% full (recycle threshold %d%%). Quality degrades from here, so recycle this session now:
+1. Save a summary of where your task stands: gt summarize --done "..." --remaining "..." --gotcha "..." (repeat a flag per item; run gt summarize alone for guidance).
+2. Run: gt handoff -s "Context recycle" -m "<anything else the next session needs>"
+Your hook, the summary and the handoff mail carry over to the fresh session. If you carry on instead, the session is handed off for you at %d%%.`,
+		r.Percent, policy.Threshold, policy.Force)
+	return json.NewEncoder(os.Stdout).Encode(map[string]string{"decision": "block", "reason": reason})
+}
+
+// contextPolicy loads the town's context recycling policy.
+func contextPolicy(townRoot string) recycle.Policy {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return recycle.NewPolicy(nil)
+	}
+	return recycle.NewPolicy(settings.ContextRecycle)
+}
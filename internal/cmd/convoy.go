@@ -16,7 +16,9 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -101,13 +103,16 @@ WHAT IS A SWARM:
 TRACKING SEMANTICS:
   - 'tracks' relation is non-blocking (tracked issues don't block convoy)
   - Cross-prefix capable (convoy in hq-* tracks issues in gt-*, bd-*)
+  - Cross-town capable (<peer>:<issue-id> tracks issues in a peer town,
+    see 'gt town peer'; 'gt convoy block' makes local work wait on them)
   - Landed: all tracked issues closed → notification sent to subscribers
 
 COMMANDS:
   create    Create a convoy tracking specified issues
   add       Add issues to an existing convoy (reopens if closed)
   status    Show convoy progress, tracked issues, and active workers
-  list      List convoys (the dashboard view)`,
+  list      List convoys (the dashboard view)
+  block     Hold a local issue until issues in peer towns close`,
 }
 
 var convoyCreateCmd = &cobra.Command{
@@ -122,7 +127,8 @@ Examples:
   gt convoy create "Deploy v2.0" gt-abc bd-xyz
   gt convoy create "Release prep" gt-abc --notify           # defaults to mayor/
   gt convoy create "Release prep" gt-abc --notify ops/      # notify ops/
-  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release`,
+  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
+  gt convoy create "Auth rollout" gt-abc team-b:be-xyz      # cross-town`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runConvoyCreate,
 }
@@ -133,7 +139,8 @@ var convoyStatusCmd = &cobra.Command{
 	Long: `Show detailed status for a convoy.
 
 Displays convoy metadata, tracked issues, and completion progress.
-Without an ID, shows status of all active convoys.`,
+Tracked issues held by an issue in a peer town (see 'gt convoy block')
+show what they wait on. Without an ID, shows status of all active convoys.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runConvoyStatus,
 }
@@ -161,7 +168,8 @@ If the convoy is closed, it will be automatically reopened.
 
 Examples:
   gt convoy add hq-cv-abc gt-new-issue
-  gt convoy add hq-cv-abc gt-issue1 gt-issue2 gt-issue3`,
+  gt convoy add hq-cv-abc gt-issue1 gt-issue2 gt-issue3
+  gt convoy add hq-cv-abc team-b:be-xyz     # issue in peer town team-b`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: runConvoyAdd,
 }
@@ -174,10 +182,34 @@ var convoyCheckCmd = &cobra.Command{
 This handles cross-rig convoy completion: convoys in town beads tracking issues
 in rig beads won't auto-close via bd close alone. This command bridges that gap.
 
+It also polls peer towns for the issues remote gates wait on (see 'gt convoy
+block') and closes each gate whose remote issue has closed, releasing the
+local work held behind it.
+
 Can be run manually or by deacon patrol to ensure convoys close promptly.`,
 	RunE: runConvoyCheck,
 }
 
+var convoyBlockCmd = &cobra.Command{
+	Use:   "block <issue-id> <peer:issue-id> [peer:issue-id...]",
+	Short: "Hold a local issue until issues in peer towns close",
+	Long: `Make a local issue wait on issues in peer towns.
+
+bd dependencies can't point across towns, so each remote issue gets a local
+gate bead ("Remote: <peer>:<issue-id>") in the issue's rig, and the issue
+depends on it. While the gate is open the issue is blocked: bd ready leaves
+it out and gt sling refuses it without --force.
+
+'gt convoy check' polls the peer towns and closes each gate once its remote
+issue is closed. The Deacon runs it on patrol.
+
+Examples:
+  gt convoy block gt-abc team-b:be-xyz
+  gt convoy block gt-abc team-b:be-xyz infra:in-42`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runConvoyBlock,
+}
+
 var convoyStrandedCmd = &cobra.Command{
 	Use:   "stranded",
 	Short: "Find stranded convoys with ready work but no workers",
@@ -227,6 +259,7 @@ func init() {
 	convoyCmd.AddCommand(convoyAddCmd)
 	convoyCmd.AddCommand(convoyCheckCmd)
 	convoyCmd.AddCommand(convoyStrandedCmd)
+	convoyCmd.AddCommand(convoyBlockCmd)
 
 	rootCmd.AddCommand(convoyCmd)
 }
@@ -261,6 +294,12 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Issues in peer towns (<peer>:<issue-id>) are tracked via the description
+	localIssues, remoteRefs, err := splitRemoteRefs(filepath.Dir(townBeads), trackedIssues)
+	if err != nil {
+		return err
+	}
+
 	// Create convoy issue in town beads
 	description := fmt.Sprintf("Convoy tracking %d issues", len(trackedIssues))
 	if convoyNotify != "" {
@@ -269,6 +308,7 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		description += fmt.Sprintf("\nMolecule: %s", convoyMolecule)
 	}
+	description += formatConvoyRemoteRefs(remoteRefs)
 
 	// Generate convoy ID with cv- prefix
 	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
@@ -296,8 +336,8 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	// Notify address is stored in description (line 166-168) and read from there
 
 	// Add 'tracks' relations for each tracked issue
-	trackedCount := 0
-	for _, issueID := range trackedIssues {
+	trackedCount := len(remoteRefs)
+	for _, issueID := range localIssues {
 		// Use --type=tracks for non-blocking tracking relation
 		depArgs := []string{"dep", "add", convoyID, issueID, "--type=tracks"}
 		depCmd := exec.Command("bd", depArgs...)
@@ -338,6 +378,11 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	localIssues, remoteRefs, err := splitRemoteRefs(filepath.Dir(townBeads), issuesToAdd)
+	if err != nil {
+		return err
+	}
+
 	// Validate convoy exists and get its status
 	showArgs := []string{"show", convoyID, "--json"}
 	showCmd := exec.Command("bd", showArgs...)
@@ -384,8 +429,8 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Add 'tracks' relations for each issue
-	addedCount := 0
-	for _, issueID := range issuesToAdd {
+	var added []string
+	for _, issueID := range localIssues {
 		depArgs := []string{"dep", "add", convoyID, issueID, "--type=tracks"}
 		depCmd := exec.Command("bd", depArgs...)
 		depCmd.Dir = townBeads
@@ -393,7 +438,18 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 		if err := depCmd.Run(); err != nil {
 			style.PrintWarning("couldn't add %s: %v", issueID, err)
 		} else {
-			addedCount++
+			added = append(added, issueID)
+		}
+	}
+
+	// Remote issues are recorded in the convoy description
+	if len(remoteRefs) > 0 {
+		if _, err := addConvoyRemoteRefs(townBeads, convoyID, remoteRefs); err != nil {
+			style.PrintWarning("couldn't add remote issues: %v", err)
+		} else {
+			for _, ref := range remoteRefs {
+				added = append(added, ref.String())
+			}
 		}
 	}
 
@@ -401,9 +457,9 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	if reopened {
 		fmt.Println()
 	}
-	fmt.Printf("%s Added %d issue(s) to convoy 🚚 %s\n", style.Bold.Render("✓"), addedCount, convoyID)
-	if addedCount > 0 {
-		fmt.Printf("  Issues: %s\n", strings.Join(issuesToAdd[:addedCount], ", "))
+	fmt.Printf("%s Added %d issue(s) to convoy 🚚 %s\n", style.Bold.Render("✓"), len(added), convoyID)
+	if len(added) > 0 {
+		fmt.Printf("  Issues: %s\n", strings.Join(added, ", "))
 	}
 
 	return nil
@@ -415,6 +471,14 @@ func runConvoyCheck(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	released, err := checkRemoteGates(filepath.Dir(townBeads))
+	if err != nil {
+		style.PrintWarning("checking remote gates: %v", err)
+	}
+	for _, g := range released {
+		fmt.Printf("%s %s closed in its peer town, released gate %s\n", style.Bold.Render("🚦"), g.Ref, g.ID)
+	}
+
 	closed, err := checkAndCloseCompletedConvoys(townBeads)
 	if err != nil {
 		return err
@@ -596,9 +660,8 @@ func isReadyIssue(t trackedIssueInfo, blockedIssues map[string]bool) bool {
 		return true // Can't determine session = treat as ready
 	}
 
-	// Check if tmux session exists
-	checkCmd := exec.Command("tmux", "has-session", "-t", sessionName)
-	if err := checkCmd.Run(); err != nil {
+	// Check if the worker's session exists
+	if has, _ := tmux.NewTmux().HasSession(sessionName); !has {
 		return true // Session doesn't exist = ready
 	}
 
@@ -622,8 +685,9 @@ func checkAndCloseCompletedConvoys(townBeads string) ([]struct{ ID, Title string
 	}
 
 	var convoys []struct {
-		ID    string `json:"id"`
-		Title string `json:"title"`
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
 		return nil, fmt.Errorf("parsing convoy list: %w", err)
@@ -632,6 +696,7 @@ func checkAndCloseCompletedConvoys(townBeads string) ([]struct{ ID, Title string
 	// Check each convoy
 	for _, convoy := range convoys {
 		tracked := getTrackedIssues(townBeads, convoy.ID)
+		tracked = append(tracked, getRemoteTrackedIssues(townBeads, convoy.Description)...)
 		if len(tracked) == 0 {
 			continue // No tracked issues, nothing to check
 		}
@@ -768,6 +833,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	tracked := getTrackedIssues(townBeads, convoyID)
+	tracked = append(tracked, getRemoteTrackedIssues(townBeads, convoy.Description)...)
 
 	// Count completed
 	completed := 0
@@ -837,6 +903,9 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 				}
 				line += fmt.Sprintf("  %s", style.Dim.Render(workerDisplay))
 			}
+			if len(t.WaitsOn) > 0 && t.Status != "closed" {
+				line += fmt.Sprintf("  %s", style.Warning.Render("⏸ waits on "+strings.Join(t.WaitsOn, ", ")))
+			}
 			fmt.Println(line)
 		}
 	}
@@ -1017,14 +1086,15 @@ func formatConvoyStatus(status string) string {
 
 // trackedIssueInfo holds info about an issue being tracked by a convoy.
 type trackedIssueInfo struct {
-	ID        string `json:"id"`
-	Title     string `json:"title"`
-	Status    string `json:"status"`
-	Type      string `json:"dependency_type"`
-	IssueType string `json:"issue_type"`
-	Assignee  string `json:"assignee,omitempty"`   // Assigned agent (e.g., gastown/polecats/goose)
-	Worker    string `json:"worker,omitempty"`     // Worker currently assigned (e.g., gastown/nux)
-	WorkerAge string `json:"worker_age,omitempty"` // How long worker has been on this issue
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Type      string   `json:"dependency_type"`
+	IssueType string   `json:"issue_type"`
+	Assignee  string   `json:"assignee,omitempty"`   // Assigned agent (e.g., gastown/polecats/goose)
+	Worker    string   `json:"worker,omitempty"`     // Worker currently assigned (e.g., gastown/nux)
+	WorkerAge string   `json:"worker_age,omitempty"` // How long worker has been on this issue
+	WaitsOn   []string `json:"waits_on,omitempty"`   // Peer-town issues it is held behind
 }
 
 // getTrackedIssues queries SQLite directly to get issues tracked by a convoy.
@@ -1096,6 +1166,9 @@ func getTrackedIssues(townBeads, convoyID string) []trackedIssueInfo {
 			info.Status = details.Status
 			info.IssueType = details.IssueType
 			info.Assignee = details.Assignee
+			for _, ref := range remoteWaits(details.Dependencies) {
+				info.WaitsOn = append(info.WaitsOn, ref.String())
+			}
 		} else {
 			info.Title = "(external)"
 			info.Status = "unknown"
@@ -1115,11 +1188,12 @@ func getTrackedIssues(townBeads, convoyID string) []trackedIssueInfo {
 
 // issueDetails holds basic issue info.
 type issueDetails struct {
-	ID        string
-	Title     string
-	Status    string
-	IssueType string
-	Assignee  string
+	ID           string
+	Title        string
+	Status       string
+	IssueType    string
+	Assignee     string
+	Dependencies []beads.IssueDep
 }
 
 // getIssueDetailsBatch fetches details for multiple issues in a single bd show call.
@@ -1151,11 +1225,12 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 	}
 
 	var issues []struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		IssueType string `json:"issue_type"`
-		Assignee  string `json:"assignee"`
+		ID           string           `json:"id"`
+		Title        string           `json:"title"`
+		Status       string           `json:"status"`
+		IssueType    string           `json:"issue_type"`
+		Assignee     string           `json:"assignee"`
+		Dependencies []beads.IssueDep `json:"dependencies"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
 		return result
@@ -1163,11 +1238,12 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 
 	for _, issue := range issues {
 		result[issue.ID] = &issueDetails{
-			ID:        issue.ID,
-			Title:     issue.Title,
-			Status:    issue.Status,
-			IssueType: issue.IssueType,
-			Assignee:  issue.Assignee,
+			ID:           issue.ID,
+			Title:        issue.Title,
+			Status:       issue.Status,
+			IssueType:    issue.IssueType,
+			Assignee:     issue.Assignee,
+			Dependencies: issue.Dependencies,
 		}
 	}
 
@@ -1192,22 +1268,24 @@ func getIssueDetails(issueID string) *issueDetails {
 	}
 
 	var issues []struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		IssueType string `json:"issue_type"`
-		Assignee  string `json:"assignee"`
+		ID           string           `json:"id"`
+		Title        string           `json:"title"`
+		Status       string           `json:"status"`
+		IssueType    string           `json:"issue_type"`
+		Assignee     string           `json:"assignee"`
+		Dependencies []beads.IssueDep `json:"dependencies"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil || len(issues) == 0 {
 		return nil
 	}
 
 	return &issueDetails{
-		ID:        issues[0].ID,
-		Title:     issues[0].Title,
-		Status:    issues[0].Status,
-		IssueType: issues[0].IssueType,
-		Assignee:  issues[0].Assignee,
+		ID:           issues[0].ID,
+		Title:        issues[0].Title,
+		Status:       issues[0].Status,
+		IssueType:    issues[0].IssueType,
+		Assignee:     issues[0].Assignee,
+		Dependencies: issues[0].Dependencies,
 	}
 }
 
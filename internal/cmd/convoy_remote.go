@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// convoyRemotePrefix marks a tracked issue in a peer town within the convoy
+// description. bd dependencies can't point across towns, so remote tracking
+// lives alongside the Notify:/Molecule: metadata lines.
+const convoyRemotePrefix = "Remote: "
+
+// splitRemoteRefs separates <peer>:<issue-id> references from local issue IDs.
+// Remote references must name a registered peer town.
+func splitRemoteRefs(townRoot string, ids []string) (local []string, remote []federation.RemoteRef, err error) {
+	var peers *federation.PeersConfig
+	for _, id := range ids {
+		ref, ok := federation.ParseRemoteRef(id)
+		if !ok {
+			local = append(local, id)
+			continue
+		}
+		if peers == nil {
+			if peers, err = federation.LoadPeers(townRoot); err != nil {
+				return nil, nil, err
+			}
+		}
+		if _, err := peers.Get(ref.Peer); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w (register with 'gt town peer add')", id, err)
+		}
+		remote = append(remote, ref)
+	}
+	return local, remote, nil
+}
+
+// parseConvoyRemoteRefs extracts remote tracked issues from a convoy description.
+func parseConvoyRemoteRefs(description string) []federation.RemoteRef {
+	var refs []federation.RemoteRef
+	for _, line := range strings.Split(description, "\n") {
+		if !strings.HasPrefix(line, convoyRemotePrefix) {
+			continue
+		}
+		if ref, ok := federation.ParseRemoteRef(strings.TrimSpace(strings.TrimPrefix(line, convoyRemotePrefix))); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// formatConvoyRemoteRefs renders remote references as description lines.
+func formatConvoyRemoteRefs(refs []federation.RemoteRef) string {
+	var sb strings.Builder
+	for _, ref := range refs {
+		sb.WriteString("\n" + convoyRemotePrefix + ref.String())
+	}
+	return sb.String()
+}
+
+// addConvoyRemoteRefs appends remote references to an existing convoy's
+// description, skipping any already tracked. Returns the number added.
+func addConvoyRemoteRefs(townBeads, convoyID string, refs []federation.RemoteRef) (int, error) {
+	desc, err := getConvoyDescription(townBeads, convoyID)
+	if err != nil {
+		return 0, err
+	}
+
+	existing := make(map[federation.RemoteRef]bool)
+	for _, ref := range parseConvoyRemoteRefs(desc) {
+		existing[ref] = true
+	}
+	var toAdd []federation.RemoteRef
+	for _, ref := range refs {
+		if !existing[ref] {
+			toAdd = append(toAdd, ref)
+			existing[ref] = true
+		}
+	}
+	if len(toAdd) == 0 {
+		return 0, nil
+	}
+
+	updateCmd := exec.Command("bd", "update", convoyID, "--description="+desc+formatConvoyRemoteRefs(toAdd))
+	updateCmd.Dir = townBeads
+	var stderr bytes.Buffer
+	updateCmd.Stderr = &stderr
+	if err := updateCmd.Run(); err != nil {
+		return 0, fmt.Errorf("updating convoy: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+	return len(toAdd), nil
+}
+
+// getConvoyDescription returns a convoy's description text.
+func getConvoyDescription(townBeads, convoyID string) (string, error) {
+	showCmd := exec.Command("bd", "show", convoyID, "--json")
+	showCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	showCmd.Stdout = &stdout
+	if err := showCmd.Run(); err != nil {
+		return "", fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+
+	var convoys []struct {
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
+		return "", fmt.Errorf("parsing convoy data: %w", err)
+	}
+	if len(convoys) == 0 {
+		return "", fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+	return convoys[0].Description, nil
+}
+
+// getRemoteTrackedIssues resolves a convoy's remote tracked issues against
+// their peer towns. Unreachable peers produce "unknown" entries and a warning
+// so one offline town doesn't hide the rest of the convoy.
+func getRemoteTrackedIssues(townBeads, description string) []trackedIssueInfo {
+	refs := parseConvoyRemoteRefs(description)
+	if len(refs) == 0 {
+		return nil
+	}
+
+	peers, err := federation.LoadPeers(filepath.Dir(townBeads))
+	if err != nil {
+		style.PrintWarning("couldn't load peer towns: %v", err)
+		peers = federation.NewPeersConfig()
+	}
+
+	statuses, errs := federation.FetchRemoteIssues(peers, refs)
+	for peer, err := range errs {
+		style.PrintWarning("peer town %s unavailable: %v", peer, err)
+	}
+
+	tracked := make([]trackedIssueInfo, 0, len(refs))
+	for _, ref := range refs {
+		info := trackedIssueInfo{
+			ID:   ref.String(),
+			Type: "tracks",
+		}
+		if s, ok := statuses[ref]; ok {
+			info.Title = s.Title
+			info.Status = s.Status
+			info.IssueType = s.IssueType
+			info.Assignee = s.Assignee
+		} else {
+			info.Title = "(remote)"
+			info.Status = "unknown"
+		}
+		tracked = append(tracked, info)
+	}
+	return tracked
+}
+
+// remoteGateLabel marks a gate bead standing in for an issue in a peer
+// town. The local issue depends on the gate, so bd ready and gt sling hold
+// it until gt convoy check sees the remote issue close and closes the gate.
+// The gate's title is the remote reference in convoy description form
+// ("Remote: <peer>:<id>"), so it can be read back from a dependency list.
+const remoteGateLabel = "remote-gate"
+
+// blockOnRemote makes a local issue wait on an issue in a peer town,
+// creating the gate in the issue's own rig. An open gate for the same
+// reference is reused. Returns the gate ID.
+func blockOnRemote(townRoot, issueID string, ref federation.RemoteRef) (string, error) {
+	b := beads.New(beads.ResolveHookDir(townRoot, issueID, ""))
+	issue, err := b.Show(issueID)
+	if err != nil {
+		return "", fmt.Errorf("issue %s: %w", issueID, err)
+	}
+	title := convoyRemotePrefix + ref.String()
+	for _, dep := range issue.Dependencies {
+		if dep.Title == title && dep.Status != "closed" {
+			return dep.ID, nil
+		}
+	}
+
+	gate, err := b.Create(beads.CreateOptions{
+		Title:       title,
+		Type:        "gate",
+		Priority:    issue.Priority,
+		Description: fmt.Sprintf("%s waits on %s in peer town %s.\n\nClosed by 'gt convoy check' once the remote issue closes.", issueID, ref.IssueID, ref.Peer),
+		Labels:      []string{remoteGateLabel},
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating gate: %w", err)
+	}
+	if err := b.AddDependency(issueID, gate.ID); err != nil {
+		return "", fmt.Errorf("adding dependency on %s: %w", gate.ID, err)
+	}
+	return gate.ID, nil
+}
+
+func runConvoyBlock(cmd *cobra.Command, args []string) error {
+	issueID := args[0]
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	townRoot := filepath.Dir(townBeads)
+
+	local, refs, err := splitRemoteRefs(townRoot, args[1:])
+	if err != nil {
+		return err
+	}
+	if len(local) > 0 {
+		return fmt.Errorf("%s: not a <peer>:<issue-id> reference (use 'bd dep add' for local issues)", strings.Join(local, ", "))
+	}
+
+	for _, ref := range refs {
+		gateID, err := blockOnRemote(townRoot, issueID, ref)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s waits on %s (gate %s)\n", style.Bold.Render("⏸"), issueID, ref, gateID)
+	}
+	return nil
+}
+
+// remoteWaits returns the peer-town issues an issue still waits on: its
+// open remote gate dependencies.
+func remoteWaits(deps []beads.IssueDep) []federation.RemoteRef {
+	var refs []federation.RemoteRef
+	for _, dep := range deps {
+		if dep.Status != "closed" {
+			refs = append(refs, parseConvoyRemoteRefs(dep.Title)...)
+		}
+	}
+	return refs
+}
+
+// remoteGate is an open remote gate and the rig database it lives in.
+type remoteGate struct {
+	ID  string
+	Ref federation.RemoteRef
+	dir string
+}
+
+// listRemoteGates returns the open remote gates in every routed database.
+func listRemoteGates(townRoot string) ([]remoteGate, error) {
+	routes, err := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		return nil, fmt.Errorf("loading routes: %w", err)
+	}
+	dirs := []string{townRoot}
+	seen := map[string]bool{townRoot: true}
+	for _, r := range routes {
+		dir := filepath.Join(townRoot, r.Path)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	var gates []remoteGate
+	for _, dir := range dirs {
+		issues, err := beads.New(dir).List(beads.ListOptions{Label: remoteGateLabel, Status: "open", Priority: -1})
+		if err != nil {
+			continue // A rig without a database has no gates
+		}
+		for _, issue := range issues {
+			if refs := parseConvoyRemoteRefs(issue.Title); len(refs) == 1 {
+				gates = append(gates, remoteGate{ID: issue.ID, Ref: refs[0], dir: dir})
+			}
+		}
+	}
+	return gates, nil
+}
+
+// releasableGates picks the gates whose remote issue is closed.
+func releasableGates(gates []remoteGate, statuses map[federation.RemoteRef]*federation.IssueStatus) []remoteGate {
+	var out []remoteGate
+	for _, g := range gates {
+		if s, ok := statuses[g.Ref]; ok && s.Status == "closed" {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// checkRemoteGates polls peer towns for the issues open remote gates wait
+// on and closes the gates whose issue has closed, releasing the local work.
+// Unreachable peers are warned about and their gates left open.
+func checkRemoteGates(townRoot string) ([]remoteGate, error) {
+	gates, err := listRemoteGates(townRoot)
+	if err != nil || len(gates) == 0 {
+		return nil, err
+	}
+	peers, err := federation.LoadPeers(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("loading peer towns: %w", err)
+	}
+
+	refs := make([]federation.RemoteRef, 0, len(gates))
+	for _, g := range gates {
+		refs = append(refs, g.Ref)
+	}
+	statuses, errs := federation.FetchRemoteIssues(peers, refs)
+	for peer, err := range errs {
+		style.PrintWarning("peer town %s unavailable: %v", peer, err)
+	}
+
+	var released []remoteGate
+	for _, g := range releasableGates(gates, statuses) {
+		reason := fmt.Sprintf("%s closed in peer town", g.Ref)
+		if err := beads.New(g.dir).CloseWithReason(reason, g.ID); err != nil {
+			style.PrintWarning("closing gate %s: %v", g.ID, err)
+			continue
+		}
+		released = append(released, g)
+	}
+	return released, nil
+}
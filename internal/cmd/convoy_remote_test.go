@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/federation"
+)
+
+func TestRemoteWaits(t *testing.T) {
+	deps := []beads.IssueDep{
+		{ID: "gt-gate1", Title: "Remote: team-b:be-xyz", Status: "open"},
+		{ID: "gt-gate2", Title: "Remote: infra:in-42", Status: "closed"}, // Released
+		{ID: "gt-def", Title: "Local prerequisite", Status: "open"},
+	}
+	waits := remoteWaits(deps)
+	if len(waits) != 1 || waits[0] != (federation.RemoteRef{Peer: "team-b", IssueID: "be-xyz"}) {
+		t.Errorf("remoteWaits = %v, want only team-b:be-xyz", waits)
+	}
+}
+
+func TestReleasableGates(t *testing.T) {
+	closed := federation.RemoteRef{Peer: "team-b", IssueID: "be-xyz"}
+	open := federation.RemoteRef{Peer: "team-b", IssueID: "be-abc"}
+	offline := federation.RemoteRef{Peer: "infra", IssueID: "in-42"}
+	gates := []remoteGate{{ID: "gt-g1", Ref: closed}, {ID: "gt-g2", Ref: open}, {ID: "gt-g3", Ref: offline}}
+	statuses := map[federation.RemoteRef]*federation.IssueStatus{
+		closed: {ID: "be-xyz", Status: "closed"},
+		open:   {ID: "be-abc", Status: "in_progress"},
+	}
+
+	got := releasableGates(gates, statuses)
+	if len(got) != 1 || got[0].ID != "gt-g1" {
+		t.Errorf("releasableGates = %+v, want only gt-g1 (open and unreachable issues hold their gates)", got)
+	}
+}
@@ -15,18 +15,20 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/format"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
-	costsJSON    bool
-	costsToday   bool
-	costsWeek    bool
-	costsByRole  bool
-	costsByRig   bool
-	costsVerbose bool
+	costsJSON   bool
+	costsToday  bool
+	costsWeek   bool
+	costsByRole bool
+	costsByRig  bool
 
 	// Record subcommand flags
 	recordSession  string
@@ -131,7 +133,6 @@ func init() {
 	costsCmd.Flags().BoolVar(&costsWeek, "week", false, "Show this week's total from session events")
 	costsCmd.Flags().BoolVar(&costsByRole, "by-role", false, "Show breakdown by role")
 	costsCmd.Flags().BoolVar(&costsByRig, "by-rig", false, "Show breakdown by rig")
-	costsCmd.Flags().BoolVarP(&costsVerbose, "verbose", "v", false, "Show debug output for failures")
 
 	// Add record subcommand
 	costsCmd.AddCommand(costsRecordCmd)
@@ -183,6 +184,9 @@ type CostsOutput struct {
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
+// tokenRegex matches token counts like "12,345 tokens" or "1.2k tokens"
+var tokenRegex = regexp.MustCompile(`(\d[\d,]*(?:\.\d+)?)([kKmM]?) tokens\b`)
+
 func runCosts(cmd *cobra.Command, args []string) error {
 	// If querying ledger, use ledger functions
 	if costsToday || costsWeek || costsByRole || costsByRig {
@@ -388,7 +392,7 @@ func querySessionEvents() []CostEntry {
 		entries, err := querySessionEventsFromLocation(location)
 		if err != nil {
 			// Log but continue with other locations
-			if costsVerbose {
+			if output.IsVerbose() {
 				fmt.Fprintf(os.Stderr, "[costs] query from %s failed: %v\n", location, err)
 			}
 			continue
@@ -635,6 +639,25 @@ func extractCost(content string) float64 {
 	return cost
 }
 
+// extractTokens returns the last token count shown in pane content, or 0.
+func extractTokens(content string) int64 {
+	matches := tokenRegex.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return 0
+	}
+	lastMatch := matches[len(matches)-1]
+
+	var n float64
+	_, _ = fmt.Sscanf(strings.ReplaceAll(lastMatch[1], ",", ""), "%f", &n)
+	switch strings.ToLower(lastMatch[2]) {
+	case "k":
+		n *= 1e3
+	case "m":
+		n *= 1e6
+	}
+	return int64(n)
+}
+
 func outputCostsJSON(output CostsOutput) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
@@ -674,13 +697,13 @@ func outputCostsHuman(costs []SessionCost, total float64) error {
 			c.Session,
 			c.Role,
 			rigWorker,
-			fmt.Sprintf("$%.2f", c.Cost),
+			format.Currency(c.Cost),
 			statusIcon)
 	}
 
 	// Print total
 	fmt.Println(strings.Repeat("─", 75))
-	fmt.Printf("%s %s\n", style.Bold.Render("Total:"), fmt.Sprintf("$%.2f", total))
+	fmt.Printf("%s %s\n", style.Bold.Render("Total:"), format.Currency(total))
 
 	return nil
 }
@@ -694,14 +717,14 @@ func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 	fmt.Printf("\n%s Cost Summary%s\n\n", style.Bold.Render("📊"), periodStr)
 
 	// Total
-	fmt.Printf("%s $%.2f\n", style.Bold.Render("Total:"), output.Total)
+	fmt.Printf("%s %s\n", style.Bold.Render("Total:"), format.Currency(output.Total))
 
 	// By role breakdown
 	if output.ByRole != nil && len(output.ByRole) > 0 {
 		fmt.Printf("\n%s\n", style.Bold.Render("By Role:"))
 		for role, cost := range output.ByRole {
 			icon := constants.RoleEmoji(role)
-			fmt.Printf("  %s %-12s $%.2f\n", icon, role, cost)
+			fmt.Printf("  %s %-12s %s\n", icon, role, format.Currency(cost))
 		}
 	}
 
@@ -709,7 +732,7 @@ func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 	if output.ByRig != nil && len(output.ByRig) > 0 {
 		fmt.Printf("\n%s\n", style.Bold.Render("By Rig:"))
 		for rig, cost := range output.ByRig {
-			fmt.Printf("  %-15s $%.2f\n", rig, cost)
+			fmt.Printf("  %-15s %s\n", rig, format.Currency(cost))
 		}
 	}
 
@@ -748,8 +771,9 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		content = ""
 	}
 
-	// Extract cost
+	// Extract cost and, when the agent shows it, token usage
 	cost := extractCost(content)
+	tokens := extractTokens(content)
 
 	// Parse session name
 	role, rig, worker := parseSessionName(session)
@@ -776,6 +800,9 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	if worker != "" {
 		payload["worker"] = worker
 	}
+	if tokens > 0 {
+		payload["tokens"] = tokens
+	}
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshaling payload: %w", err)
@@ -814,6 +841,7 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	}
 
 	wispID := strings.TrimSpace(string(output))
+	_ = events.LogAudit(events.TypeSessionEnd, agentPath, events.SessionCostPayload(session, rig, cost, tokens))
 
 	// Auto-close session cost wisps immediately after creation.
 	// These are informational records that don't need to stay open.
@@ -826,7 +854,7 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 
 	// Output confirmation (silent if cost is zero and no work item)
 	if cost > 0 || recordWorkItem != "" {
-		fmt.Printf("%s Recorded $%.2f for %s (wisp: %s)", style.Success.Render("✓"), cost, session, wispID)
+		fmt.Printf("%s Recorded %s for %s (wisp: %s)", style.Success.Render("✓"), format.Currency(cost), session, wispID)
 		if recordWorkItem != "" {
 			fmt.Printf(" (work: %s)", recordWorkItem)
 		}
@@ -1007,16 +1035,16 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 
 	if digestDryRun {
 		fmt.Printf("%s [DRY RUN] Would create Cost Report %s:\n", style.Bold.Render("📊"), dateStr)
-		fmt.Printf("  Total: $%.2f\n", digest.TotalUSD)
+		fmt.Printf("  Total: %s\n", format.Currency(digest.TotalUSD))
 		fmt.Printf("  Sessions: %d\n", digest.SessionCount)
 		fmt.Printf("  By Role:\n")
 		for role, cost := range digest.ByRole {
-			fmt.Printf("    %s: $%.2f\n", role, cost)
+			fmt.Printf("    %s: %s\n", role, format.Currency(cost))
 		}
 		if len(digest.ByRig) > 0 {
 			fmt.Printf("  By Rig:\n")
 			for rig, cost := range digest.ByRig {
-				fmt.Printf("    %s: $%.2f\n", rig, cost)
+				fmt.Printf("    %s: %s\n", rig, format.Currency(cost))
 			}
 		}
 		return nil
@@ -1035,7 +1063,7 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Created Cost Report %s (bead: %s)\n", style.Success.Render("✓"), dateStr, digestID)
-	fmt.Printf("  Total: $%.2f from %d sessions\n", digest.TotalUSD, digest.SessionCount)
+	fmt.Printf("  Total: %s from %s sessions\n", format.Currency(digest.TotalUSD), format.Int(int64(digest.SessionCount)))
 	if deletedCount > 0 {
 		fmt.Printf("  Deleted %d source wisps\n", deletedCount)
 	}
@@ -1050,7 +1078,7 @@ func querySessionCostWisps(targetDate time.Time) ([]CostEntry, error) {
 	listOutput, err := listCmd.Output()
 	if err != nil {
 		// No wisps database or command failed
-		if costsVerbose {
+		if output.IsVerbose() {
 			fmt.Fprintf(os.Stderr, "[costs] wisp list failed: %v\n", err)
 		}
 		return nil, nil
@@ -1095,7 +1123,7 @@ func querySessionCostWisps(targetDate time.Time) ([]CostEntry, error) {
 		var payload SessionPayload
 		if event.Payload != "" {
 			if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
-				if costsVerbose {
+				if output.IsVerbose() {
 					fmt.Fprintf(os.Stderr, "[costs] payload unmarshal failed for event %s: %v\n", event.ID, err)
 				}
 				continue
@@ -1129,12 +1157,22 @@ func querySessionCostWisps(targetDate time.Time) ([]CostEntry, error) {
 	return sessionCostWisps, nil
 }
 
+// localizedDigestDate renders a digest's YYYY-MM-DD date for the report body.
+// Titles keep the ISO date so digests stay findable by date.
+func localizedDigestDate(date string) string {
+	t, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return date
+	}
+	return format.Date(t)
+}
+
 // createCostDigestBead creates a permanent bead for the daily cost digest.
 func createCostDigestBead(digest CostDigest) (string, error) {
 	// Build description with aggregate data
 	var desc strings.Builder
-	desc.WriteString(fmt.Sprintf("Daily cost aggregate for %s.\n\n", digest.Date))
-	desc.WriteString(fmt.Sprintf("**Total:** $%.2f from %d sessions\n\n", digest.TotalUSD, digest.SessionCount))
+	desc.WriteString(fmt.Sprintf("Daily cost aggregate for %s.\n\n", localizedDigestDate(digest.Date)))
+	desc.WriteString(fmt.Sprintf("**Total:** %s from %s sessions\n\n", format.Currency(digest.TotalUSD), format.Int(int64(digest.SessionCount))))
 
 	if len(digest.ByRole) > 0 {
 		desc.WriteString("## By Role\n")
@@ -1145,7 +1183,7 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 		sort.Strings(roles)
 		for _, role := range roles {
 			icon := constants.RoleEmoji(role)
-			desc.WriteString(fmt.Sprintf("- %s %s: $%.2f\n", icon, role, digest.ByRole[role]))
+			desc.WriteString(fmt.Sprintf("- %s %s: %s\n", icon, role, format.Currency(digest.ByRole[role])))
 		}
 		desc.WriteString("\n")
 	}
@@ -1158,7 +1196,7 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 		}
 		sort.Strings(rigs)
 		for _, rig := range rigs {
-			desc.WriteString(fmt.Sprintf("- %s: $%.2f\n", rig, digest.ByRig[rig]))
+			desc.WriteString(fmt.Sprintf("- %s: %s\n", rig, format.Currency(digest.ByRig[rig])))
 		}
 		desc.WriteString("\n")
 	}
@@ -1202,7 +1240,7 @@ func deleteSessionCostWisps(targetDate time.Time) (int, error) {
 	listCmd := exec.Command("bd", "mol", "wisp", "list", "--all", "--json")
 	listOutput, err := listCmd.Output()
 	if err != nil {
-		if costsVerbose {
+		if output.IsVerbose() {
 			fmt.Fprintf(os.Stderr, "[costs] wisp list failed in deletion: %v\n", err)
 		}
 		return 0, nil
@@ -1223,7 +1261,7 @@ func deleteSessionCostWisps(targetDate time.Time) (int, error) {
 		showCmd := exec.Command("bd", "show", wisp.ID, "--json")
 		showOutput, err := showCmd.Output()
 		if err != nil {
-			if costsVerbose {
+			if output.IsVerbose() {
 				fmt.Fprintf(os.Stderr, "[costs] bd show failed for wisp %s: %v\n", wisp.ID, err)
 			}
 			continue
@@ -1231,7 +1269,7 @@ func deleteSessionCostWisps(targetDate time.Time) (int, error) {
 
 		var events []SessionEvent
 		if err := json.Unmarshal(showOutput, &events); err != nil {
-			if costsVerbose {
+			if output.IsVerbose() {
 				fmt.Fprintf(os.Stderr, "[costs] JSON unmarshal failed for wisp %s: %v\n", wisp.ID, err)
 			}
 			continue
@@ -1252,7 +1290,7 @@ func deleteSessionCostWisps(targetDate time.Time) (int, error) {
 		var payload SessionPayload
 		if event.Payload != "" {
 			if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
-				if costsVerbose {
+				if output.IsVerbose() {
 					fmt.Fprintf(os.Stderr, "[costs] payload unmarshal failed for wisp %s: %v\n", wisp.ID, err)
 				}
 				continue
@@ -22,6 +22,8 @@ var (
 	crewListAll       bool
 	crewDryRun        bool
 	crewDebug         bool
+	crewOwner         string
+	crewEmail         string
 )
 
 var crewCmd = &cobra.Command{
@@ -61,11 +63,17 @@ Each workspace is created at <rig>/crew/<name>/ with:
 - CLAUDE.md with crew worker prompting
 - Optional feature branch (crew/<name>)
 
+When several people share a town, give each a personal workspace with
+--owner and/or --email: the clone's git identity is set to that person, and
+'gt status' and 'gt crew status' show which beads and branches each crew
+member holds.
+
 Examples:
   gt crew add dave                       # Create single workspace
   gt crew add murgen croaker goblin      # Create multiple at once
   gt crew add emma --rig greenplace      # Create in specific rig
-  gt crew add fred --branch              # Create with feature branch`,
+  gt crew add fred --branch              # Create with feature branch
+  gt crew add alice --email alice@example.com --branch  # Alice's own clone`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runCrewAdd,
 }
@@ -324,6 +332,8 @@ func init() {
 	// Add flags
 	crewAddCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to create crew workspace in")
 	crewAddCmd.Flags().BoolVar(&crewBranch, "branch", false, "Create a feature branch (crew/<name>)")
+	crewAddCmd.Flags().StringVar(&crewOwner, "owner", "", "Person who owns the workspace (default: the workspace name, with --email)")
+	crewAddCmd.Flags().StringVar(&crewEmail, "email", "", "Owner's git email; commits from the clone use it")
 
 	crewListCmd.Flags().StringVar(&crewRig, "rig", "", "Filter by rig name")
 	crewListCmd.Flags().BoolVar(&crewListAll, "all", false, "List crew workspaces in all rigs")
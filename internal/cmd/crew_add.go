@@ -44,6 +44,11 @@ func runCrewAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	personal := crewOwner != "" || crewEmail != ""
+	if personal && len(args) > 1 {
+		return fmt.Errorf("--owner and --email apply to one workspace at a time")
+	}
+
 	// Get rig
 	g := git.NewGit(townRoot)
 	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
@@ -97,6 +102,23 @@ func runCrewAdd(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Path: %s\n", worker.ClonePath)
 		fmt.Printf("  Branch: %s\n", worker.Branch)
 
+		if personal {
+			owner := crewOwner
+			if owner == "" {
+				owner = name
+			}
+			if owned, err := crewMgr.SetOwner(name, owner, crewEmail); err != nil {
+				style.PrintWarning("could not record owner for %s: %v", name, err)
+			} else {
+				worker = owned
+				who := owned.Owner
+				if owned.Email != "" {
+					who += " <" + owned.Email + ">"
+				}
+				fmt.Printf("  Owner: %s\n", who)
+			}
+		}
+
 		// Create agent bead for the crew worker
 		prefix := beads.GetPrefixForRig(townRoot, rigName)
 		crewID := beads.CrewBeadIDWithPrefix(prefix, rigName, name)
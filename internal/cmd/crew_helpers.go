@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -216,6 +217,10 @@ func isInTmuxSession(targetSession string) bool {
 // attachToTmuxSession attaches to a tmux session.
 // Should only be called from outside tmux.
 func attachToTmuxSession(sessionID string) error {
+	if t := tmux.NewTmux(); t.IsProcessBackend() {
+		return t.AttachSession(sessionID)
+	}
+
 	tmuxPath, err := exec.LookPath("tmux")
 	if err != nil {
 		return fmt.Errorf("tmux not found: %w", err)
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// CrewHoldings is what a crew member has in flight: the beads assigned to
+// them and the branches in their clone. It answers "who has what" when a
+// team shares a town.
+type CrewHoldings struct {
+	Name     string     `json:"name"`
+	Owner    string     `json:"owner,omitempty"`
+	Branches []string   `json:"branches,omitempty"`
+	Beads    []CrewBead `json:"beads,omitempty"`
+}
+
+// CrewBead is a bead held by a crew member.
+type CrewBead struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// crewHeldStatuses are the bead statuses that count as held, most active
+// first.
+var crewHeldStatuses = []string{"in_progress", beads.StatusHooked, "open"}
+
+// crewHoldings collects each crew member's beads and branches. Beads are
+// looked up once per status for the whole rig, not per member.
+func crewHoldings(r *rig.Rig, mgr *crew.Manager, workers []*crew.CrewWorker, b *beads.Beads) []CrewHoldings {
+	byAssignee := make(map[string][]CrewBead)
+	prefix := r.Name + "/crew/"
+	for _, status := range crewHeldStatuses {
+		issues, err := b.List(beads.ListOptions{Status: status, Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range issues {
+			if strings.HasPrefix(issue.Assignee, prefix) {
+				byAssignee[issue.Assignee] = append(byAssignee[issue.Assignee], CrewBead{ID: issue.ID, Title: issue.Title, Status: issue.Status})
+			}
+		}
+	}
+
+	var holdings []CrewHoldings
+	for _, w := range workers {
+		h := CrewHoldings{Name: w.Name, Owner: w.Owner, Beads: byAssignee[prefix+w.Name]}
+		h.Branches, _ = mgr.Branches(w.Name)
+		holdings = append(holdings, h)
+	}
+	return holdings
+}
+
+// formatCrewHoldings renders a crew member's holdings on one line, or ""
+// if they hold nothing.
+func formatCrewHoldings(h CrewHoldings) string {
+	var parts []string
+	for _, bead := range h.Beads {
+		parts = append(parts, fmt.Sprintf("%s %s", bead.ID, style.Dim.Render(truncateWithEllipsis(bead.Title, 40))))
+	}
+	if len(h.Branches) > 0 {
+		parts = append(parts, style.Dim.Render("branches: "+strings.Join(h.Branches, ", ")))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	who := h.Name
+	if h.Owner != "" && h.Owner != h.Name {
+		who += " (" + h.Owner + ")"
+	}
+	return fmt.Sprintf("%s: %s", who, strings.Join(parts, "; "))
+}
+
+// renderCrewHoldings prints who holds what under a rig's crew in gt status.
+func renderCrewHoldings(holdings []CrewHoldings) {
+	var lines []string
+	for _, h := range holdings {
+		if line := formatCrewHoldings(h); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Printf("   %s\n", style.Dim.Render("Holding:"))
+	for _, line := range lines {
+		fmt.Printf("     %s\n", line)
+	}
+}
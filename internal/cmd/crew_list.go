@@ -19,6 +19,7 @@ type CrewListItem struct {
 	Rig        string `json:"rig"`
 	Branch     string `json:"branch"`
 	Path       string `json:"path"`
+	Owner      string `json:"owner,omitempty"`
 	HasSession bool   `json:"has_session"`
 	GitClean   bool   `json:"git_clean"`
 }
@@ -72,6 +73,7 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 				Rig:        r.Name,
 				Branch:     w.Branch,
 				Path:       w.ClonePath,
+				Owner:      w.Owner,
 				HasSession: hasSession,
 				GitClean:   gitClean,
 			})
@@ -102,7 +104,11 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 			gitStatus = style.Bold.Render("dirty")
 		}
 
-		fmt.Printf("  %s %s/%s\n", status, item.Rig, item.Name)
+		owner := ""
+		if item.Owner != "" {
+			owner = style.Dim.Render(" (" + item.Owner + ")")
+		}
+		fmt.Printf("  %s %s/%s%s\n", status, item.Rig, item.Name, owner)
 		fmt.Printf("    Branch: %s  Git: %s\n", item.Branch, gitStatus)
 		fmt.Printf("    %s\n", style.Dim.Render(item.Path))
 	}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
@@ -17,17 +18,21 @@ import (
 
 // CrewStatusItem represents detailed status for a crew worker.
 type CrewStatusItem struct {
-	Name         string   `json:"name"`
-	Rig          string   `json:"rig"`
-	Path         string   `json:"path"`
-	Branch       string   `json:"branch"`
-	HasSession   bool     `json:"has_session"`
-	SessionID    string   `json:"session_id,omitempty"`
-	GitClean     bool     `json:"git_clean"`
-	GitModified  []string `json:"git_modified,omitempty"`
-	GitUntracked []string `json:"git_untracked,omitempty"`
-	MailTotal    int      `json:"mail_total"`
-	MailUnread   int      `json:"mail_unread"`
+	Name         string     `json:"name"`
+	Rig          string     `json:"rig"`
+	Path         string     `json:"path"`
+	Branch       string     `json:"branch"`
+	Owner        string     `json:"owner,omitempty"`
+	Email        string     `json:"email,omitempty"`
+	HasSession   bool       `json:"has_session"`
+	SessionID    string     `json:"session_id,omitempty"`
+	GitClean     bool       `json:"git_clean"`
+	GitModified  []string   `json:"git_modified,omitempty"`
+	GitUntracked []string   `json:"git_untracked,omitempty"`
+	MailTotal    int        `json:"mail_total"`
+	MailUnread   int        `json:"mail_unread"`
+	Branches     []string   `json:"branches,omitempty"` // Branches other than the default
+	Beads        []CrewBead `json:"beads,omitempty"`    // Beads assigned to this crew member
 }
 
 func runCrewStatus(cmd *cobra.Command, args []string) error {
@@ -75,8 +80,9 @@ func runCrewStatus(cmd *cobra.Command, args []string) error {
 
 	t := tmux.NewTmux()
 	var items []CrewStatusItem
+	holdings := crewHoldings(r, crewMgr, workers, beads.New(filepath.Join(r.Path, "mayor", "rig")))
 
-	for _, w := range workers {
+	for i, w := range workers {
 		sessionID := crewSessionName(r.Name, w.Name)
 		hasSession, _ := t.HasSession(sessionID)
 
@@ -107,12 +113,16 @@ func runCrewStatus(cmd *cobra.Command, args []string) error {
 			Rig:          r.Name,
 			Path:         w.ClonePath,
 			Branch:       branch,
+			Owner:        w.Owner,
+			Email:        w.Email,
 			HasSession:   hasSession,
 			GitClean:     gitClean,
 			GitModified:  modified,
 			GitUntracked: untracked,
 			MailTotal:    mailTotal,
 			MailUnread:   mailUnread,
+			Branches:     holdings[i].Branches,
+			Beads:        holdings[i].Beads,
 		}
 		if hasSession {
 			item.SessionID = sessionID
@@ -140,7 +150,26 @@ func runCrewStatus(cmd *cobra.Command, args []string) error {
 
 		fmt.Printf("%s %s/%s\n", sessionStatus, item.Rig, item.Name)
 		fmt.Printf("  Path:   %s\n", item.Path)
+		if item.Owner != "" {
+			owner := item.Owner
+			if item.Email != "" {
+				owner += " <" + item.Email + ">"
+			}
+			fmt.Printf("  Owner:  %s\n", owner)
+		}
 		fmt.Printf("  Branch: %s\n", item.Branch)
+		var others []string
+		for _, b := range item.Branches {
+			if b != item.Branch {
+				others = append(others, b)
+			}
+		}
+		if len(others) > 0 {
+			fmt.Printf("          %s\n", style.Dim.Render("also: "+strings.Join(others, ", ")))
+		}
+		for _, bead := range item.Beads {
+			fmt.Printf("  Holds:  %s %s %s\n", bead.ID, bead.Title, style.Dim.Render("("+bead.Status+")"))
+		}
 
 		if item.GitClean {
 			fmt.Printf("  Git:    %s\n", style.Dim.Render("clean"))
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -33,7 +34,15 @@ var daemonStartCmd = &cobra.Command{
 	Short: "Start the daemon",
 	Long: `Start the Gas Town daemon in the background.
 
-The daemon will run until stopped with 'gt daemon stop'.`,
+The daemon will run until stopped with 'gt daemon stop'.
+
+With --metrics-addr (or GT_METRICS_ADDR), the daemon serves Prometheus
+metrics at /metrics: running polecats, merge queue depth, merge and
+escalation totals, recorded spend, and bd call latency.
+
+Examples:
+  gt daemon start
+  gt daemon start --metrics-addr :9464`,
 	RunE: runDaemonStart,
 }
 
@@ -66,8 +75,9 @@ var daemonRunCmd = &cobra.Command{
 }
 
 var (
-	daemonLogLines int
-	daemonLogFollow bool
+	daemonLogLines    int
+	daemonLogFollow   bool
+	daemonMetricsAddr string
 )
 
 func init() {
@@ -79,6 +89,8 @@ func init() {
 
 	daemonLogsCmd.Flags().IntVarP(&daemonLogLines, "lines", "n", 50, "Number of lines to show")
 	daemonLogsCmd.Flags().BoolVarP(&daemonLogFollow, "follow", "f", false, "Follow log output")
+	daemonStartCmd.Flags().StringVar(&daemonMetricsAddr, "metrics-addr", os.Getenv("GT_METRICS_ADDR"), "Serve Prometheus metrics on this address (e.g., :9464)")
+	daemonRunCmd.Flags().StringVar(&daemonMetricsAddr, "metrics-addr", os.Getenv("GT_METRICS_ADDR"), "Serve Prometheus metrics on this address")
 
 	rootCmd.AddCommand(daemonCmd)
 }
@@ -105,7 +117,11 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding executable: %w", err)
 	}
 
-	daemonCmd := exec.Command(gtPath, "daemon", "run")
+	runArgs := []string{"daemon", "run"}
+	if daemonMetricsAddr != "" {
+		runArgs = append(runArgs, "--metrics-addr", daemonMetricsAddr)
+	}
+	daemonCmd := exec.Command(gtPath, runArgs...)
 	daemonCmd.Dir = townRoot
 
 	// Detach from terminal
@@ -184,7 +200,7 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 		// Load state for more details
 		state, err := daemon.LoadState(townRoot)
 		if err == nil && !state.StartedAt.IsZero() {
-			fmt.Printf("  Started: %s\n", state.StartedAt.Format("2006-01-02 15:04:05"))
+			fmt.Printf("  Started: %s\n", format.DateTimeSeconds(state.StartedAt))
 			if !state.LastHeartbeat.IsZero() {
 				fmt.Printf("  Last heartbeat: %s (#%d)\n",
 					state.LastHeartbeat.Format("15:04:05"),
@@ -193,7 +209,7 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 
 			// Check if binary is newer than process
 			if binaryModTime, err := getBinaryModTime(); err == nil {
-				fmt.Printf("  Binary: %s\n", binaryModTime.Format("2006-01-02 15:04:05"))
+				fmt.Printf("  Binary: %s\n", format.DateTimeSeconds(binaryModTime))
 				if binaryModTime.After(state.StartedAt) {
 					fmt.Printf("  %s Binary is newer than process - consider '%s'\n",
 						style.Bold.Render("⚠"),
@@ -258,6 +274,7 @@ func runDaemonRun(cmd *cobra.Command, args []string) error {
 	}
 
 	config := daemon.DefaultConfig(townRoot)
+	config.MetricsAddr = daemonMetricsAddr
 	d, err := daemon.New(config)
 	if err != nil {
 		return fmt.Errorf("creating daemon: %w", err)
@@ -3,18 +3,24 @@ package cmd
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"os/exec"
 	"runtime"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/townmetrics"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
-	dashboardPort int
-	dashboardOpen bool
+	dashboardPort     int
+	dashboardOpen     bool
+	dashboardAPIToken string
+	dashboardNoCache  bool
 )
 
 var dashboardCmd = &cobra.Command{
@@ -29,6 +35,16 @@ The dashboard shows real-time convoy status with:
 - Last activity indicator (green/yellow/red)
 - Auto-refresh every 30 seconds via htmx
 
+The server also exposes a JSON API at /api/issues?ids=... that peer towns
+query to track this town's issues in their convoys (see 'gt town peer').
+The API is only served when --api-token (or GT_DASHBOARD_API_TOKEN) is
+set, and peers must send it as a bearer token.
+
+Prometheus metrics for the town are served at /metrics.
+
+Beads queries are cached for a few seconds, shared with 'gt status', so
+busy pages and peers don't each query bd. Use --no-cache to turn this off.
+
 Example:
   gt dashboard              # Start on default port 8080
   gt dashboard --port 3000  # Start on port 3000
@@ -39,14 +55,24 @@ Example:
 func init() {
 	dashboardCmd.Flags().IntVar(&dashboardPort, "port", 8080, "HTTP port to listen on")
 	dashboardCmd.Flags().BoolVar(&dashboardOpen, "open", false, "Open browser automatically")
+	dashboardCmd.Flags().StringVar(&dashboardAPIToken, "api-token", "", "Bearer token required for peer API requests (default: $GT_DASHBOARD_API_TOKEN)")
+	dashboardCmd.Flags().BoolVar(&dashboardNoCache, "no-cache", false, "Query bd directly instead of using cached results")
 	rootCmd.AddCommand(dashboardCmd)
 }
 
 func runDashboard(cmd *cobra.Command, args []string) error {
 	// Verify we're in a workspace
-	if _, err := workspace.FindFromCwdOrError(); err != nil {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	// Read here, not as the flag's default, so --help doesn't print it
+	if dashboardAPIToken == "" {
+		dashboardAPIToken = os.Getenv("GT_DASHBOARD_API_TOKEN")
+	}
+	if !dashboardNoCache {
+		beads.SetCacheTTL(beads.DefaultCacheTTL)
+	}
 
 	// Create the live convoy fetcher
 	fetcher, err := web.NewLiveConvoyFetcher()
@@ -60,6 +86,13 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("creating convoy handler: %w", err)
 	}
 
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	if dashboardAPIToken != "" {
+		mux.Handle(federation.IssuesAPIPath, web.NewIssueAPIHandler(fetcher, dashboardAPIToken))
+	}
+	mux.Handle("/metrics", townmetrics.NewRegistry(townRoot).Handler())
+
 	// Build the URL
 	url := fmt.Sprintf("http://localhost:%d", dashboardPort)
 
@@ -70,11 +103,14 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 
 	// Start the server with timeouts
 	fmt.Printf("🚚 Gas Town Dashboard starting at %s\n", url)
+	if dashboardAPIToken == "" {
+		fmt.Printf("   Peer API %s off (set --api-token to serve it)\n", federation.IssuesAPIPath)
+	}
 	fmt.Printf("   Press Ctrl+C to stop\n")
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", dashboardPort),
-		Handler:           handler,
+		Handler:           mux,
 		ReadHeaderTimeout: 10 * time.Second,
 		ReadTimeout:       30 * time.Second,
 		WriteTimeout:      60 * time.Second,
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
@@ -346,9 +347,15 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 		return fmt.Errorf("creating deacon directory: %w", err)
 	}
 
-	// Ensure Claude settings exist (autonomous role needs mail in SessionStart)
-	if err := claude.EnsureSettingsForRole(deaconDir, "deacon"); err != nil {
+	// Ensure Claude settings exist (autonomous role needs mail in SessionStart).
+	// gt install and gt doctor --fix write the same file under the town lock.
+	if release, err := acquireStateLock(townRoot, lock.ScopeTown); err != nil {
 		style.PrintWarning("Could not create deacon settings: %v", err)
+	} else {
+		if err := claude.EnsureSettingsForRole(deaconDir, "deacon"); err != nil {
+			style.PrintWarning("Could not create deacon settings: %v", err)
+		}
+		release()
 	}
 
 	// Create session in deacon directory
@@ -515,6 +522,10 @@ func runDeaconStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt deacon start"))
 	}
 
+	if townRoot != "" {
+		printChoresSummary(townRoot)
+	}
+
 	return nil
 }
 
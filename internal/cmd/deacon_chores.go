@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/chores"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var choresRunDue bool
+
+var deaconChoresCmd = &cobra.Command{
+	Use:   "chores",
+	Short: "Show the scheduled town chores",
+	Long: `Show the town chores the daemon runs on a schedule, and how they last went.
+
+Chores:
+  bd-sync         bd sync in every beads database            (0 3 * * *)
+  bd-doctor       bd doctor --fix in every beads database    (15 3 * * *)
+  hook-verify     Repair broken hook attachments and unhook
+                  stale hooked beads                         (*/30 * * * *)
+  branch-prune    Delete stale polecat branches in each rig  (30 3 * * *)
+  molecule-seed   Create missing patrol molecules            (45 3 * * *)
+  harness-commit  Commit town metadata, when harness_git
+                  auto_commit is "schedule"                  (@hourly)
+
+Chores are off until enabled in ~/gt/settings/config.json. Schedules are
+five-field cron expressions in local time (minute hour day month weekday),
+@hourly, @daily, @nightly (03:00), @weekly, or "off":
+
+  "chores": {
+    "schedule": { "bd-sync": "0 */6 * * *", "branch-prune": "off" }
+  }
+
+The daemon checks on each heartbeat and runs whatever is due; a chore missed
+while the daemon was down runs once when it next checks.
+
+Examples:
+  gt deacon chores                    # Schedule, last and next runs
+  gt deacon chores run bd-sync        # Run a chore now
+  gt deacon chores run --due          # Run whatever is due (daemon)`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconChores,
+}
+
+var deaconChoresRunCmd = &cobra.Command{
+	Use:   "run [chore...]",
+	Short: "Run chores now",
+	Long: `Run the named chores now, or with --due, the chores whose time has come.
+
+Each run is recorded, so a chore run by hand isn't run again by the daemon
+until its next scheduled time.`,
+	RunE: runDeaconChoresRun,
+}
+
+func init() {
+	deaconChoresRunCmd.Flags().BoolVar(&choresRunDue, "due", false, "Run the chores that are due")
+	deaconChoresCmd.AddCommand(deaconChoresRunCmd)
+	deaconCmd.AddCommand(deaconChoresCmd)
+}
+
+// choreFuncs do the work of each built-in chore, returning a one-line
+// summary of what changed.
+var choreFuncs = map[string]func(townRoot string) (string, error){
+	chores.BdSync:        choreBdSync,
+	chores.BdDoctor:      choreBdDoctor,
+	chores.HookVerify:    choreHookVerify,
+	chores.BranchPrune:   choreBranchPrune,
+	chores.MoleculeSeed:  choreMoleculeSeed,
+	chores.HarnessCommit: choreHarnessCommit,
+	chores.Archive:       choreArchive,
+}
+
+// loadChores returns the town's chore schedule, or nil if chores are off.
+func loadChores(townRoot string) ([]chores.Scheduled, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Chores == nil {
+		return nil, nil
+	}
+	return chores.Resolve(settings.Chores)
+}
+
+func runDeaconChores(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	scheduled, err := loadChores(townRoot)
+	if err != nil {
+		return err
+	}
+	if scheduled == nil {
+		fmt.Printf("%s Chores are off (add \"chores\": {} to settings/config.json)\n", style.Dim.Render("○"))
+		return nil
+	}
+	state, err := chores.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range scheduled {
+		if c.Schedule == nil {
+			fmt.Printf("%s %-14s %s\n", style.Dim.Render("○"), c.Name, style.Dim.Render("off"))
+			continue
+		}
+		icon := style.Dim.Render("○")
+		last := "never run"
+		if r := state.Runs[c.Name]; r != nil {
+			icon = style.Bold.Render("✓")
+			last = "last " + r.At.Format("Jan 2 15:04")
+			if !r.OK {
+				icon = style.Error.Render("✗")
+				last += ": " + r.Error
+			}
+		}
+		next := "scheduled from the daemon's next heartbeat"
+		if at := state.Next(c); !at.IsZero() {
+			next = "next " + at.Format("Jan 2 15:04")
+		}
+		fmt.Printf("%s %-14s %-14s %s, %s\n", icon, c.Name, c.Schedule, next, style.Dim.Render(last))
+	}
+	return nil
+}
+
+// printChoresSummary adds the chore schedule to gt deacon status: the
+// next chore due and any whose last run failed.
+func printChoresSummary(townRoot string) {
+	scheduled, err := loadChores(townRoot)
+	if err != nil || scheduled == nil {
+		return
+	}
+	state, err := chores.LoadState(townRoot)
+	if err != nil {
+		return
+	}
+
+	var next chores.Scheduled
+	var nextAt time.Time
+	var failed []string
+	for _, c := range scheduled {
+		if r := state.Runs[c.Name]; r != nil && !r.OK {
+			failed = append(failed, c.Name)
+		}
+		if at := state.Next(c); !at.IsZero() && (nextAt.IsZero() || at.Before(nextAt)) {
+			next, nextAt = c, at
+		}
+	}
+
+	fmt.Println()
+	switch {
+	case nextAt.IsZero() && state.Since.IsZero():
+		fmt.Printf("Chores: %s\n", style.Dim.Render("scheduled from the daemon's next heartbeat"))
+	case nextAt.IsZero():
+		fmt.Printf("Chores: %s\n", style.Dim.Render("all off"))
+	default:
+		fmt.Printf("Chores: next %s at %s\n", next.Name, nextAt.Format("Jan 2 15:04"))
+	}
+	if len(failed) > 0 {
+		fmt.Printf("  %s Last run failed: %s (see 'gt deacon chores')\n", style.Warning.Render("⚠"), strings.Join(failed, ", "))
+	}
+}
+
+func runDeaconChoresRun(cmd *cobra.Command, args []string) error {
+	if choresRunDue == (len(args) > 0) {
+		return fmt.Errorf("name the chores to run, or use --due")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	scheduled, err := loadChores(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var todo []string
+	if choresRunDue {
+		if scheduled == nil {
+			return nil
+		}
+		state, err := chores.LoadState(townRoot)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, c := range scheduled {
+			if state.Due(c, now) {
+				todo = append(todo, c.Name)
+			}
+		}
+	} else {
+		for _, name := range args {
+			if choreFuncs[name] == nil {
+				return fmt.Errorf("unknown chore %q (see 'gt deacon chores')", name)
+			}
+		}
+		todo = args
+	}
+
+	var failed []string
+	for _, name := range todo {
+		start := time.Now()
+		summary, err := choreFuncs[name](townRoot)
+		run := &chores.Run{At: start, Duration: time.Since(start), OK: err == nil}
+		if err != nil {
+			run.Error = err.Error()
+			failed = append(failed, name)
+			fmt.Printf("%s %s: %v\n", style.Error.Render("✗"), name, err)
+		} else {
+			fmt.Printf("%s %s: %s\n", style.Bold.Render("✓"), name, summary)
+		}
+		if err := chores.Update(townRoot, func(s *chores.State) { s.Runs[name] = run }); err != nil {
+			style.PrintWarning("could not record %s: %v", name, err)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d chore(s) failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// choreBd runs a bd command in every beads database in the town.
+func choreBd(townRoot string, args ...string) (int, error) {
+	var errs []error
+	n := 0
+	for _, location := range townBeadsLocations(townRoot) {
+		c := exec.Command("bd", args...) //nolint:gosec // G204: args are constructed internally
+		c.Dir = location
+		if out, err := c.CombinedOutput(); err != nil {
+			rel, _ := filepath.Rel(townRoot, location)
+			errs = append(errs, fmt.Errorf("%s: %v: %s", rel, err, strings.TrimSpace(string(out))))
+			continue
+		}
+		n++
+	}
+	return n, errors.Join(errs...)
+}
+
+func choreBdSync(townRoot string) (string, error) {
+	n, err := choreBd(townRoot, "sync")
+	return fmt.Sprintf("synced %d beads database(s)", n), err
+}
+
+func choreBdDoctor(townRoot string) (string, error) {
+	n, err := choreBd(townRoot, "doctor", "--fix")
+	return fmt.Sprintf("checked %d beads database(s)", n), err
+}
+
+func choreHookVerify(townRoot string) (string, error) {
+	ctx := &doctor.CheckContext{TownRoot: townRoot}
+	fixed := 0
+	for _, check := range []doctor.Check{doctor.NewHookAttachmentValidCheck(), doctor.NewHookSingletonCheck()} {
+		if result := check.Run(ctx); result.Status != doctor.StatusOK {
+			if err := check.Fix(ctx); err != nil {
+				return "", fmt.Errorf("%s: %w", check.Name(), err)
+			}
+			fixed++
+		}
+	}
+
+	stale, err := deacon.ScanStaleHooks(townRoot, deacon.DefaultStaleHookConfig())
+	if err != nil {
+		return "", fmt.Errorf("scanning stale hooks: %w", err)
+	}
+	return fmt.Sprintf("%d hook problem(s) repaired, %d stale hooked bead(s) unhooked", fixed, stale.Unhooked), nil
+}
+
+func choreBranchPrune(townRoot string) (string, error) {
+	rigs, _, err := getAllRigs()
+	if err != nil {
+		return "", err
+	}
+	var errs []error
+	deleted := 0
+	for _, r := range rigs {
+		n, err := polecat.NewManager(r, git.NewGit(r.Path)).CleanupStaleBranches()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Name, err))
+		}
+		deleted += n
+	}
+	return fmt.Sprintf("deleted %d stale branch(es) across %d rig(s)", deleted, len(rigs)), errors.Join(errs...)
+}
+
+func choreMoleculeSeed(townRoot string) (string, error) {
+	ctx := &doctor.CheckContext{TownRoot: townRoot}
+	check := doctor.NewPatrolMoleculesExistCheck()
+	result := check.Run(ctx)
+	if result.Status == doctor.StatusOK {
+		return result.Message, nil
+	}
+	if err := check.Fix(ctx); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("seeded patrol molecules (%s)", result.Message), nil
+}
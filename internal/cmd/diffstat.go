@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	diffstatRig     string
+	diffstatBranch  string
+	diffstatOnto    string
+	diffstatJSON    bool
+	diffstatComment bool
+)
+
+var diffstatCmd = &cobra.Command{
+	Use:     "diffstat [bead]",
+	GroupID: GroupWork,
+	Short:   "Summarize the code changes for a bead",
+	Long: `Summarize the changes on a bead's polecat branch: files changed, lines
+added and removed, the packages (directories) touched and the test delta.
+
+The branch is found from the bead (polecat/<name>/<bead>), or given with
+--branch. With no bead, the current branch is summarized. Changes are
+counted from where the branch forked from origin/<default branch>, so
+later work on main doesn't show up.
+
+'gt done' and 'gt mq submit' add this summary to the bead as a comment,
+for the reviewer. Use --comment to add it by hand.
+
+Examples:
+  gt diffstat                       # The current branch
+  gt diffstat gt-abc                # The branch for gt-abc
+  gt diffstat gt-abc --comment      # ...and comment it on the bead
+  gt diffstat --branch fix/typo --onto develop
+  gt diffstat gt-abc --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDiffstat,
+}
+
+func init() {
+	diffstatCmd.Flags().StringVar(&diffstatRig, "rig", "", "Rig to look in (default: from the bead's prefix or the current directory)")
+	diffstatCmd.Flags().StringVar(&diffstatBranch, "branch", "", "Branch to summarize (default: the bead's polecat branch)")
+	diffstatCmd.Flags().StringVar(&diffstatOnto, "onto", "", "Base branch (default: the rig's default branch)")
+	diffstatCmd.Flags().BoolVar(&diffstatJSON, "json", false, "Output as JSON")
+	diffstatCmd.Flags().BoolVar(&diffstatComment, "comment", false, "Add the summary to the bead as a comment")
+
+	rootCmd.AddCommand(diffstatCmd)
+}
+
+func runDiffstat(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	beadID := ""
+	if len(args) > 0 {
+		beadID = args[0]
+	}
+
+	r, err := diffstatRigFor(townRoot, beadID)
+	if err != nil {
+		return err
+	}
+
+	// Read from the current clone when in one, otherwise from the rig's
+	// shared repo, which has every polecat branch
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	g := git.NewGit(cwd)
+	if !g.IsRepo() {
+		g = rigRepoGit(r.Path)
+	}
+
+	branch := diffstatBranch
+	switch {
+	case branch != "":
+	case beadID != "":
+		if branch, err = findBeadBranch(g, beadID); err != nil {
+			return err
+		}
+	default:
+		if branch, err = g.CurrentBranch(); err != nil {
+			return fmt.Errorf("getting current branch: %w", err)
+		}
+	}
+	if beadID == "" {
+		beadID = parseBranchName(branch).Issue
+	}
+
+	target := diffstatOnto
+	if target == "" {
+		target = defaultBranchFor(r.Path)
+	}
+	stat, err := g.DiffStat("origin/"+target, branch)
+	if err != nil {
+		return fmt.Errorf("summarizing %s against origin/%s: %w", branch, target, err)
+	}
+
+	if diffstatComment {
+		if beadID == "" {
+			return fmt.Errorf("can't tell which bead %s is for; pass the bead", branch)
+		}
+		bd := beads.New(beads.ResolveBeadsDir(r.Path))
+		if err := bd.Comment(beadID, stat.Markdown()); err != nil {
+			return fmt.Errorf("commenting on %s: %w", beadID, err)
+		}
+	}
+
+	if diffstatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stat)
+	}
+	printDiffStat(stat)
+	if diffstatComment {
+		fmt.Printf("\n%s Added the summary to %s\n", style.Bold.Render("✓"), beadID)
+	}
+	return nil
+}
+
+// diffstatRigFor picks the rig: --rig, else the rig the bead's prefix
+// routes to, else the rig of the current directory.
+func diffstatRigFor(townRoot, beadID string) (*rig.Rig, error) {
+	if diffstatRig != "" {
+		_, r, err := getRig(diffstatRig)
+		return r, err
+	}
+	if beadID != "" {
+		if rigPath := beads.GetRigPathForPrefix(townRoot, beads.ExtractPrefix(beadID)); rigPath != "" && rigPath != townRoot {
+			rel, err := filepath.Rel(townRoot, rigPath)
+			if err == nil {
+				rigName, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+				if _, r, err := getRig(rigName); err == nil {
+					return r, nil
+				}
+			}
+		}
+	}
+	_, r, err := findCurrentRig(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("%w (use --rig)", err)
+	}
+	return r, nil
+}
+
+// findBeadBranch finds the polecat branch for a bead.
+func findBeadBranch(g *git.Git, beadID string) (string, error) {
+	branches, err := g.ListBranches(constants.BranchPolecatPrefix + "*")
+	if err != nil {
+		return "", fmt.Errorf("listing polecat branches: %w", err)
+	}
+	var matches []string
+	for _, b := range branches {
+		if parseBranchName(b).Issue == beadID || strings.HasSuffix(b, "/"+beadID) {
+			matches = append(matches, b)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no polecat branch for %s (use --branch)", beadID)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("several branches for %s: %s (use --branch)", beadID, strings.Join(matches, ", "))
+	}
+}
+
+// printDiffStat prints a change summary for people.
+func printDiffStat(stat *git.DiffStat) {
+	fmt.Printf("%s %s vs %s: %d commit(s), %d file(s), %s %s\n",
+		style.Bold.Render("Δ"), stat.Head, stat.Base, stat.Commits, len(stat.Files),
+		style.Success.Render(fmt.Sprintf("+%d", stat.Insertions)),
+		style.Error.Render(fmt.Sprintf("-%d", stat.Deletions)))
+	for _, f := range stat.Files {
+		counts := fmt.Sprintf("+%d -%d", f.Insertions, f.Deletions)
+		if f.Binary {
+			counts = "binary"
+		}
+		fmt.Printf("  %-50s %s\n", f.Path, style.Dim.Render(counts))
+	}
+	if len(stat.Packages) > 0 {
+		fmt.Printf("\n  Packages: %s\n", strings.Join(stat.Packages, ", "))
+	}
+	if stat.TestFiles > 0 {
+		fmt.Printf("  Tests:    %d file(s), +%d -%d\n", stat.TestFiles, stat.TestInsertions, stat.TestDeletions)
+	} else {
+		fmt.Printf("  Tests:    %s\n", style.Warning.Render("none changed"))
+	}
+}
+
+// commentDiffStat adds branch's change summary to the bead for the
+// reviewer. Submission doesn't depend on it, so failures only warn.
+func commentDiffStat(bd *beads.Beads, g *git.Git, issueID, branch, target string) {
+	stat, err := g.DiffStat("origin/"+target, branch)
+	if err != nil {
+		style.PrintWarning("could not summarize changes for %s: %v", issueID, err)
+		return
+	}
+	if err := bd.Comment(issueID, stat.Markdown()); err != nil {
+		style.PrintWarning("could not add change summary to %s: %v", issueID, err)
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestFindBeadBranch(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"branch", "polecat/Toast/gt-abc"},
+		{"branch", "polecat/Nux/gt-def"},
+		{"branch", "polecat/Rex/gt-def"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	g := git.NewGit(dir)
+
+	if branch, err := findBeadBranch(g, "gt-abc"); err != nil || branch != "polecat/Toast/gt-abc" {
+		t.Errorf("findBeadBranch(gt-abc) = %q, %v", branch, err)
+	}
+	if _, err := findBeadBranch(g, "gt-def"); err == nil || !strings.Contains(err.Error(), "several branches") {
+		t.Errorf("findBeadBranch(gt-def) err = %v, want several branches", err)
+	}
+	if _, err := findBeadBranch(g, "gt-zzz"); err == nil {
+		t.Error("findBeadBranch(gt-zzz) should fail")
+	}
+}
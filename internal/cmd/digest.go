@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/digest"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/notify"
+	"github.com/steveyegge/gastown/internal/stats"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// digestSendInterval is how often 'gt digest --send' delivers without
+// --force. A little under a day, so the send drifts with the patrol
+// rather than skipping a day.
+const digestSendInterval = 20 * time.Hour
+
+// digestLastSentFile records the last delivered digest within .runtime.
+const digestLastSentFile = "digest-last-sent"
+
+var (
+	digestSince string
+	digestJSON  bool
+	digestSend  bool
+	digestMail  bool
+	digestForce bool
+)
+
+var digestCmd = &cobra.Command{
+	Use:     "digest",
+	GroupID: GroupDiag,
+	Short:   "Summarize the last day of town activity",
+	Long: `Summarize what the town did over the last 24 hours (or --since): beads
+closed, refinery merges, failures (failed merges, failed molecule runs and
+sessions that died), escalations sent and session spend, as markdown.
+
+With --send the digest is delivered: to the notification sinks routed for
+the "digest" event (see 'gt config notifications'), and to the Mayor's
+inbox when no sink is routed or with --mail. The Deacon runs
+'gt digest --send' on patrol; it delivers at most once every 20 hours
+unless --force.
+
+Examples:
+  gt digest                    # Print the last 24 hours
+  gt digest --since 7d --json
+  gt digest --send             # Deliver it (Deacon patrol)
+  gt digest --send --mail      # Also to the Mayor's inbox`,
+	Args: cobra.NoArgs,
+	RunE: runDigest,
+}
+
+func init() {
+	digestCmd.Flags().StringVar(&digestSince, "since", "24h", "Summarize from this date or age (e.g. 24h, 7d, 2026-01-01)")
+	digestCmd.Flags().BoolVar(&digestJSON, "json", false, "Output as JSON")
+	digestCmd.Flags().BoolVar(&digestSend, "send", false, "Deliver to notification sinks or the Mayor's inbox")
+	digestCmd.Flags().BoolVar(&digestMail, "mail", false, "With --send, also mail the Mayor when sinks are routed")
+	digestCmd.Flags().BoolVar(&digestForce, "force", false, "With --send, deliver even if a digest went out recently")
+
+	rootCmd.AddCommand(digestCmd)
+}
+
+func runDigest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	since, err := parseSearchTime(digestSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	stamp := filepath.Join(townRoot, ".runtime", digestLastSentFile)
+	if digestSend && !digestForce {
+		if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < digestSendInterval {
+			fmt.Printf("%s Digest sent %s ago; next after %s (--force to send now)\n", style.Dim.Render("○"),
+				time.Since(info.ModTime()).Round(time.Minute), info.ModTime().Add(digestSendInterval).Format("15:04"))
+			return nil
+		}
+	}
+
+	d, err := buildDigest(townRoot, since, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if !digestSend {
+		if digestJSON {
+			return outputJSON(d)
+		}
+		fmt.Print(digest.Render(d))
+		return nil
+	}
+
+	sinks, err := sendDigest(townRoot, d)
+	if err != nil {
+		return err
+	}
+	_ = os.MkdirAll(filepath.Dir(stamp), 0755)
+	if err := os.WriteFile(stamp, []byte(time.Now().UTC().Format(time.RFC3339)+"\n"), 0644); err != nil { //nolint:gosec // G306: timestamp only
+		return err
+	}
+	if digestJSON {
+		return outputJSON(d)
+	}
+	fmt.Printf("%s %s\n", style.Success.Render("✓"), d.Headline())
+	for _, to := range sinks {
+		fmt.Printf("  sent to %s\n", to)
+	}
+	return nil
+}
+
+// buildDigest gathers the town's and every rig's activity since since.
+// Sources that can't be read only warn.
+func buildDigest(townRoot string, since, until time.Time) (*digest.Digest, error) {
+	var in digest.Input
+	in.Beads = closedWork("", beads.GetTownBeadsPath(townRoot))
+	rigs, err := discoverAllRigs(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing rigs: %w", err)
+	}
+	for _, r := range rigs {
+		in.Beads = append(in.Beads, closedWork(r.Name, r.BeadsPath())...)
+	}
+	if in.Runs, err = molruns.List(townRoot); err != nil {
+		style.PrintWarning("could not read molecule runs: %v", err)
+	}
+	if in.Merges, err = stats.LoadMerges(townRoot); err != nil {
+		style.PrintWarning("could not read merge events: %v", err)
+	}
+	if in.Events, err = digest.LoadEvents(townRoot, since); err != nil {
+		style.PrintWarning("could not read the event log: %v", err)
+	}
+	return digest.Build(in, since, until), nil
+}
+
+// sendDigest delivers d to the sinks routed for digests and, when there
+// are none or --mail is set, to the Mayor's inbox. It returns where the
+// digest went.
+func sendDigest(townRoot string, d *digest.Digest) ([]string, error) {
+	var sent []string
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	notifier, err := notify.New(settings.Notifications)
+	if err != nil {
+		return nil, fmt.Errorf("notifications: %w", err)
+	}
+	sinks := notifier.SinksFor(notify.EventDigest)
+	if len(sinks) > 0 {
+		msg := &notify.Message{Event: notify.EventDigest, Title: d.Headline(), Text: digest.Summary(d) + "\nFull digest: gt digest"}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		errs := notifier.Notify(ctx, msg)
+		cancel()
+		failed := make(map[string]bool)
+		for _, err := range errs {
+			style.PrintWarning("digest not delivered to %v", err)
+			name, _, _ := strings.Cut(err.Error(), ":")
+			failed[name] = true
+		}
+		for _, name := range sinks {
+			if !failed[name] {
+				sent = append(sent, name)
+			}
+		}
+	}
+
+	if len(sent) == 0 || digestMail {
+		err := mail.NewRouter(townRoot).Send(&mail.Message{
+			From:     "deacon/",
+			To:       "mayor/",
+			Subject:  d.Headline(),
+			Body:     digest.Render(d),
+			Type:     mail.TypeNotification,
+			Priority: mail.PriorityLow,
+		})
+		if err != nil {
+			if len(sent) == 0 {
+				return nil, fmt.Errorf("mailing the Mayor: %w", err)
+			}
+			style.PrintWarning("could not mail the Mayor: %v", err)
+		} else {
+			sent = append(sent, "mayor/")
+		}
+	}
+	return sent, nil
+}
@@ -6,14 +6,17 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
 	doctorFix             bool
-	doctorVerbose         bool
 	doctorRig             string
 	doctorRestartSessions bool
+	doctorDeep            bool
+	doctorStaleDays       int
 )
 
 var doctorCmd = &cobra.Command{
@@ -80,6 +83,13 @@ Patrol checks:
   - patrol-plugins-accessible Verify plugin directories
   - patrol-roles-have-prompts Verify role prompts exist
 
+Deep checks (with --deep, slow on large repositories; all fixable):
+  - git-fsck                 Verify objects in each rig's .repo.git and mayor/rig
+  - beads-integrity          Run bd doctor on the town and rig beads databases
+  - orphan-worktrees         Detect worktrees whose directory is gone
+  - sync-branch-divergence   Detect beads-sync branches diverged from origin
+  - dangling-polecat-branches Detect polecat branches with no worktree, idle --stale-days
+
 Use --fix to attempt automatic fixes for issues that support it.
 Use --rig to check a specific rig instead of the entire workspace.`,
 	RunE: runDoctor,
@@ -87,8 +97,9 @@ Use --rig to check a specific rig instead of the entire workspace.`,
 
 func init() {
 	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Attempt to automatically fix issues")
-	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
 	doctorCmd.Flags().StringVar(&doctorRig, "rig", "", "Check specific rig only")
+	doctorCmd.Flags().BoolVar(&doctorDeep, "deep", false, "Also run repository integrity checks (git fsck, bd doctor, stale branches)")
+	doctorCmd.Flags().IntVar(&doctorStaleDays, "stale-days", doctor.DefaultStaleBranchDays, "With --deep, age in days at which an unused polecat branch is dangling")
 	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
 	rootCmd.AddCommand(doctorCmd)
 }
@@ -100,15 +111,50 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// Fixes reinstall settings and hooks the town's other writers share
+	if doctorFix {
+		release, err := acquireStateLock(townRoot, lock.ScopeTown)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
 	// Create check context
 	ctx := &doctor.CheckContext{
 		TownRoot:        townRoot,
 		RigName:         doctorRig,
-		Verbose:         doctorVerbose,
+		Verbose:         output.IsVerbose(),
 		RestartSessions: doctorRestartSessions,
 	}
 
-	// Create doctor and register checks
+	d := newTownDoctor(doctorRig)
+	if doctorDeep {
+		d.RegisterAll(doctor.DeepChecks(doctorStaleDays)...)
+	}
+
+	// Run checks
+	var report *doctor.Report
+	if doctorFix {
+		report = d.Fix(ctx)
+	} else {
+		report = d.Run(ctx)
+	}
+
+	// Print report
+	report.Print(os.Stdout, output.IsVerbose())
+
+	// Exit with error code if there are errors
+	if report.HasErrors() {
+		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
+	}
+
+	return nil
+}
+
+// newTownDoctor returns a doctor with every check registered, and the rig
+// checks when rig is set.
+func newTownDoctor(rig string) *doctor.Doctor {
 	d := doctor.NewDoctor()
 
 	// Register workspace-level checks first (fundamental)
@@ -118,6 +164,8 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	// Register built-in checks
 	d.Register(doctor.NewStaleBinaryCheck())
+	d.Register(doctor.NewGtInstallCheck())
+	d.Register(doctor.NewBdInstallCheck())
 	d.Register(doctor.NewTownGitCheck())
 	d.Register(doctor.NewTownRootBranchCheck())
 	d.Register(doctor.NewPreCheckoutHookCheck())
@@ -181,25 +229,8 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewOrphanedAttachmentsCheck())
 
 	// Rig-specific checks (only when --rig is specified)
-	if doctorRig != "" {
+	if rig != "" {
 		d.RegisterAll(doctor.RigChecks()...)
 	}
-
-	// Run checks
-	var report *doctor.Report
-	if doctorFix {
-		report = d.Fix(ctx)
-	} else {
-		report = d.Run(ctx)
-	}
-
-	// Print report
-	report.Print(os.Stdout, doctorVerbose)
-
-	// Exit with error code if there are errors
-	if report.HasErrors() {
-		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
-	}
-
-	return nil
+	return d
 }
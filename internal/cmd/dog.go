@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/dog"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -466,7 +467,7 @@ func showDogStatus(mgr *dog.Manager, name string) error {
 	}
 	fmt.Printf("  Path:        %s\n", d.Path)
 	fmt.Printf("  Last Active: %s\n", dogFormatTimeAgo(d.LastActive))
-	fmt.Printf("  Created:     %s\n", d.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Printf("  Created:     %s\n", format.DateTime(d.CreatedAt))
 
 	if len(d.Worktrees) > 0 {
 		fmt.Println("\nWorktrees:")
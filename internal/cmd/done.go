@@ -8,12 +8,15 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/github"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/vcs"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -24,17 +27,49 @@ var doneCmd = &cobra.Command{
 	Long: `Signal that your work is complete and ready for the merge queue.
 
 This is a convenience command for polecats that:
-1. Submits the current branch to the merge queue
+1. Checks the branch rebases cleanly onto the target (gt premerge) and
+   submits it to the merge queue, once the work passes the rig's quality
+   gate (gt quality) if it has one
 2. Auto-detects issue ID from branch name
 3. Notifies the Witness with the exit outcome
 4. Exits the Claude session (polecats don't stay alive after completion)
 
+Auto pull:
+  Rigs listed in scheduler.auto_pull in ~/gt/settings/config.json keep
+  their polecats working: after submitting, gt done hooks the rig's next
+  ready bead (spawn-queued beads first; beads labeled tier:<name> only for
+  polecats of that tier) and moves the worktree to a fresh branch for it,
+  instead of exiting. After the configured number of beads the session
+  exits as usual, so the next one starts with a fresh context:
+
+    "scheduler": {"auto_pull": {"gastown": 5}}
+
 Exit statuses:
   COMPLETED      - Work done, MR submitted (default)
   ESCALATED      - Hit blocker, needs human intervention
   DEFERRED       - Work paused, issue still open
   PHASE_COMPLETE - Phase done, awaiting gate (use --phase-complete)
 
+GitHub pull requests:
+  Rigs with merge_queue.strategy = "github-pr" in settings/config.json open
+  a pull request from the polecat branch instead of submitting to the local
+  merge queue. The PR is linked on the bead (pr_url) and its review and CI
+  status are tracked back into the bead by 'gt github sync'. The bead stays
+  in progress until the PR merges, which closes it.
+
+Quality gate:
+  Rigs with quality_gate enabled in settings/config.json score the work
+  (build, test pass rate, lint findings, diff size) before submitting. Work
+  below min_score isn't submitted: the score report is added to the issue,
+  and the molecule's implement step is pinned back to you with the report
+  appended. Rework it, finish the step, and run gt done again.
+
+Crew:
+  From a crew clone (see gt claim), gt done first runs the rig's
+  merge_queue.test_command, then pushes the branch to the rig's shared
+  repo for the refinery and submits it like a polecat's. The witness isn't
+  notified and your session stays open.
+
 Phase handoff workflow:
   When a molecule has gate steps (async waits), use --phase-complete to signal
   that the current phase is complete but work continues after the gate closes.
@@ -57,6 +92,10 @@ var (
 	donePhaseComplete bool
 	doneGate          string
 	doneCleanupStatus string
+	doneNoPremerge    bool
+	doneNoQualityGate bool
+	doneSummary       string
+	doneNoTest        bool
 )
 
 // Valid exit types for gt done
@@ -73,6 +112,10 @@ func init() {
 	doneCmd.Flags().StringVar(&doneStatus, "status", ExitCompleted, "Exit status: COMPLETED, ESCALATED, or DEFERRED")
 	doneCmd.Flags().BoolVar(&donePhaseComplete, "phase-complete", false, "Signal phase complete - await gate before continuing")
 	doneCmd.Flags().StringVar(&doneGate, "gate", "", "Gate bead ID to wait on (with --phase-complete)")
+	doneCmd.Flags().BoolVar(&doneNoPremerge, "no-premerge", false, "Submit without checking that the branch rebases cleanly (see gt premerge)")
+	doneCmd.Flags().BoolVar(&doneNoQualityGate, "no-quality-gate", false, "Submit without scoring the work against the rig's quality gate; not for polecats (see gt quality)")
+	doneCmd.Flags().BoolVar(&doneNoTest, "no-test", false, "From a crew clone, submit without running the rig's tests first")
+	doneCmd.Flags().StringVar(&doneSummary, "summary", "", "Short summary of the work, for the molecule run's retro (see gt retro)")
 	doneCmd.Flags().StringVar(&doneCleanupStatus, "cleanup-status", "", "Git cleanup status: clean, uncommitted, unpushed, stash, unknown (ZFC: agent-observed)")
 
 	rootCmd.AddCommand(doneCmd)
@@ -100,18 +143,29 @@ func runDone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if doneNoQualityGate {
+		if err := checkQualityGateSkip(townRoot); err != nil {
+			return err
+		}
+	}
+
 	// Find current rig
-	rigName, _, err := findCurrentRig(townRoot)
+	rigName, r, err := findCurrentRig(townRoot)
 	if err != nil {
 		return err
 	}
 
-	// Initialize git for the current directory
+	// Initialize git (or the rig's jj) for the current directory
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("getting current directory: %w", err)
 	}
-	g := git.NewGit(cwd)
+	rigVCS := ""
+	if rigCfg, err := rig.LoadRigConfig(filepath.Join(townRoot, rigName)); err == nil {
+		rigVCS = rigCfg.VCS
+	}
+	g := vcs.Open(rigVCS, cwd)
+	gitRepo, isGit := g.(*git.Git) // Premerge, diff stats and PRs are git-only
 
 	// Get current branch
 	branch, err := g.CurrentBranch()
@@ -165,9 +219,13 @@ func runDone(cmd *cobra.Command, args []string) error {
 		polecatName = parts[len(parts)-1]
 	}
 
-	// Get agent bead ID for cross-referencing
+	// Get agent bead ID for cross-referencing. Crew are people working by
+	// hand (see gt claim): they are tested here and stay in their session.
 	var agentBeadID string
+	isCrew, isPolecat := false, false
 	if roleInfo, err := GetRoleWithContext(cwd, townRoot); err == nil {
+		isCrew = roleInfo.Role == RoleCrew
+		isPolecat = roleInfo.Role == RolePolecat
 		ctx := RoleContext{
 			Role:     roleInfo.Role,
 			Rig:      roleInfo.Rig,
@@ -184,8 +242,15 @@ func runDone(cmd *cobra.Command, args []string) error {
 		defaultBranch = rigCfg.DefaultBranch
 	}
 
+	// Rigs hosted on GitHub can opt into pull requests instead of the local
+	// refinery merge (merge_queue.strategy = "github-pr")
+	useGitHubPR := false
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName))); err == nil {
+		useGitHubPR = settings.MergeQueue.UsesGitHubPR()
+	}
+
 	// For COMPLETED, we need an issue ID and branch must not be the default branch
-	var mrID string
+	var mrID, prURL string
 	if exitType == ExitCompleted {
 		if branch == defaultBranch || branch == "master" {
 			return fmt.Errorf("cannot submit %s/master branch to merge queue", defaultBranch)
@@ -208,9 +273,42 @@ func runDone(cmd *cobra.Command, args []string) error {
 
 		// Determine target branch (auto-detect integration branch if applicable)
 		target := defaultBranch
-		autoTarget, err := detectIntegrationBranch(bd, g, issueID)
-		if err == nil && autoTarget != "" {
-			target = autoTarget
+		if isGit {
+			autoTarget, err := detectIntegrationBranch(bd, gitRepo, issueID)
+			if err == nil && autoTarget != "" {
+				target = autoTarget
+			}
+		}
+
+		// Surface conflicts now, while the polecat still has the context
+		if !doneNoPremerge && isGit {
+			if err := requireCleanRebase(gitRepo, filepath.Join(townRoot, rigName), branch, target, "gt done"); err != nil {
+				return err
+			}
+		}
+
+		// Score the work before it reaches the refinery; work below the
+		// rig's bar goes back to the implement step
+		if !doneNoQualityGate && isGit {
+			if err := requireQuality(r, gitRepo, branch, target, townRoot, issueID, sender, "gt done", true); err != nil {
+				return err
+			}
+		}
+
+		if isCrew {
+			if !doneNoTest {
+				if err := runCrewTests(r, g.WorkDir()); err != nil {
+					return err
+				}
+			}
+			if !useGitHubPR {
+				if !isGit {
+					return fmt.Errorf("gt done from a crew clone needs a git rig")
+				}
+				if err := pushToRefinery(g, r.Path, branch); err != nil {
+					return err
+				}
+			}
 		}
 
 		// Get source issue for priority inheritance
@@ -227,58 +325,75 @@ func runDone(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		// Check if MR bead already exists for this branch (idempotency)
-		existingMR, err := bd.FindMRForBranch(branch)
-		if err != nil {
-			style.PrintWarning("could not check for existing MR: %v", err)
-			// Continue with creation attempt - Create will fail if duplicate
-		}
-
-		if existingMR != nil {
-			// MR already exists - use it instead of creating a new one
-			mrID = existingMR.ID
-			fmt.Printf("%s MR already exists (idempotent)\n", style.Bold.Render("✓"))
-			fmt.Printf("  MR ID: %s\n", style.Bold.Render(mrID))
-		} else {
-			// Build MR bead title and description
-			title := fmt.Sprintf("Merge: %s", issueID)
-			description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s",
-				branch, target, issueID, rigName)
-			if worker != "" {
-				description += fmt.Sprintf("\nworker: %s", worker)
+		if useGitHubPR {
+			if !isGit {
+				return fmt.Errorf("the github-pr merge strategy needs a git rig")
 			}
-			if agentBeadID != "" {
-				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
+			pr, err := submitGitHubPR(gitRepo, bd, cwd, issueID, branch, target, worker)
+			if err != nil {
+				return fmt.Errorf("opening pull request: %w", err)
 			}
-
-			// Add conflict resolution tracking fields (initialized, updated by Refinery)
-			description += "\nretry_count: 0"
-			description += "\nlast_conflict_sha: null"
-			description += "\nconflict_task_id: null"
-
-			// Create MR bead (ephemeral wisp - will be cleaned up after merge)
-			mrIssue, err := bd.Create(beads.CreateOptions{
-				Title:       title,
-				Type:        "merge-request",
-				Priority:    priority,
-				Description: description,
-				Ephemeral:   true,
-			})
+			prURL = pr.URL
+			fmt.Printf("%s Work submitted as GitHub pull request\n", style.Bold.Render("✓"))
+			fmt.Printf("  PR: %s\n", style.Bold.Render(prURL))
+			commentDiffStat(bd, gitRepo, issueID, branch, target)
+		} else {
+			// Check if MR bead already exists for this branch (idempotency)
+			existingMR, err := bd.FindMRForBranch(branch)
 			if err != nil {
-				return fmt.Errorf("creating merge request bead: %w", err)
+				style.PrintWarning("could not check for existing MR: %v", err)
+				// Continue with creation attempt - Create will fail if duplicate
 			}
-			mrID = mrIssue.ID
 
-			// Update agent bead with active_mr reference (for traceability)
-			if agentBeadID != "" {
-				if err := bd.UpdateAgentActiveMR(agentBeadID, mrID); err != nil {
-					style.PrintWarning("could not update agent bead with active_mr: %v", err)
+			if existingMR != nil {
+				// MR already exists - use it instead of creating a new one
+				mrID = existingMR.ID
+				fmt.Printf("%s MR already exists (idempotent)\n", style.Bold.Render("✓"))
+				fmt.Printf("  MR ID: %s\n", style.Bold.Render(mrID))
+			} else {
+				// Build MR bead title and description
+				title := fmt.Sprintf("Merge: %s", issueID)
+				description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s",
+					branch, target, issueID, rigName)
+				if worker != "" {
+					description += fmt.Sprintf("\nworker: %s", worker)
+				}
+				if agentBeadID != "" {
+					description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 				}
-			}
 
-			// Success output
-			fmt.Printf("%s Work submitted to merge queue\n", style.Bold.Render("✓"))
-			fmt.Printf("  MR ID: %s\n", style.Bold.Render(mrID))
+				// Add conflict resolution tracking fields (initialized, updated by Refinery)
+				description += "\nretry_count: 0"
+				description += "\nlast_conflict_sha: null"
+				description += "\nconflict_task_id: null"
+
+				// Create MR bead (ephemeral wisp - will be cleaned up after merge)
+				mrIssue, err := bd.Create(beads.CreateOptions{
+					Title:       title,
+					Type:        "merge-request",
+					Priority:    priority,
+					Description: description,
+					Ephemeral:   true,
+				})
+				if err != nil {
+					return fmt.Errorf("creating merge request bead: %w", err)
+				}
+				mrID = mrIssue.ID
+
+				// Update agent bead with active_mr reference (for traceability)
+				if agentBeadID != "" {
+					if err := bd.UpdateAgentActiveMR(agentBeadID, mrID); err != nil {
+						style.PrintWarning("could not update agent bead with active_mr: %v", err)
+					}
+				}
+
+				// Success output
+				fmt.Printf("%s Work submitted to merge queue\n", style.Bold.Render("✓"))
+				fmt.Printf("  MR ID: %s\n", style.Bold.Render(mrID))
+				if isGit {
+					commentDiffStat(bd, gitRepo, issueID, branch, target)
+				}
+			}
 		}
 		fmt.Printf("  Source: %s\n", branch)
 		fmt.Printf("  Target: %s\n", target)
@@ -288,7 +403,11 @@ func runDone(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Printf("  Priority: P%d\n", priority)
 		fmt.Println()
-		fmt.Printf("%s\n", style.Dim.Render("The Refinery will process your merge request."))
+		if useGitHubPR {
+			fmt.Printf("%s\n", style.Dim.Render("Review and CI status will be tracked on the bead (gt github prs)."))
+		} else {
+			fmt.Printf("%s\n", style.Dim.Render("The Refinery will process your merge request."))
+		}
 	} else if exitType == ExitPhaseComplete {
 		// Phase complete - register as waiter on gate, then recycle
 		fmt.Printf("%s Phase complete, awaiting gate\n", style.Bold.Render("→"))
@@ -316,6 +435,15 @@ func runDone(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Branch: %s\n", branch)
 	}
 
+	// With scheduler.auto_pull, a polecat whose work is all committed takes
+	// the rig's next ready bead instead of exiting. Its submitted branch
+	// stays for the refinery.
+	var next *pulledBead
+	if exitType == ExitCompleted && isPolecat && polecatName != "" &&
+		(doneCleanupStatus == "clean" || doneCleanupStatus == "unpushed") {
+		next = pullNextBead(townRoot, rigName, polecatName, issueID)
+	}
+
 	// Notify Witness about completion
 	// Use town-level beads for cross-agent mail
 	townRouter := mail.NewRouter(townRoot)
@@ -330,10 +458,16 @@ func runDone(cmd *cobra.Command, args []string) error {
 	if mrID != "" {
 		bodyLines = append(bodyLines, fmt.Sprintf("MR: %s", mrID))
 	}
+	if prURL != "" {
+		bodyLines = append(bodyLines, fmt.Sprintf("PR: %s", prURL))
+	}
 	if doneGate != "" {
 		bodyLines = append(bodyLines, fmt.Sprintf("Gate: %s", doneGate))
 	}
 	bodyLines = append(bodyLines, fmt.Sprintf("Branch: %s", branch))
+	if next != nil {
+		bodyLines = append(bodyLines, fmt.Sprintf("Next: %s", next.Issue.ID))
+	}
 
 	doneNotification := &mail.Message{
 		To:      witnessAddr,
@@ -342,11 +476,13 @@ func runDone(cmd *cobra.Command, args []string) error {
 		Body:    strings.Join(bodyLines, "\n"),
 	}
 
-	fmt.Printf("\nNotifying Witness...\n")
-	if err := townRouter.Send(doneNotification); err != nil {
-		style.PrintWarning("could not notify witness: %v", err)
-	} else {
-		fmt.Printf("%s Witness notified of %s\n", style.Bold.Render("✓"), exitType)
+	if !isCrew { // The witness watches polecats only
+		fmt.Printf("\nNotifying Witness...\n")
+		if err := townRouter.Send(doneNotification); err != nil {
+			style.PrintWarning("could not notify witness: %v", err)
+		} else {
+			fmt.Printf("%s Witness notified of %s\n", style.Bold.Render("✓"), exitType)
+		}
 	}
 
 	// Notify dispatcher if work was dispatched by another agent
@@ -371,7 +507,16 @@ func runDone(cmd *cobra.Command, args []string) error {
 	_ = events.LogFeed(events.TypeDone, sender, events.DonePayload(issueID, branch))
 
 	// Update agent bead state (ZFC: self-report completion)
-	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
+	updateAgentStateOnDone(cwd, townRoot, exitType, issueID, mrID)
+
+	if isCrew {
+		fmt.Printf("\n%s\n", style.Dim.Render("Claim your next bead with gt claim <bead>."))
+		return nil
+	}
+	if next != nil {
+		startPulledBead(cwd, next)
+		return nil
+	}
 
 	// Self-cleaning: Nuke our own sandbox before exiting (if we're a polecat)
 	// This is the self-cleaning model - polecats clean up after themselves
@@ -406,7 +551,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 // intentional agent decisions that can't be observed from tmux.
 //
 // Also self-reports cleanup_status for ZFC compliance (#10).
-func updateAgentStateOnDone(cwd, townRoot, exitType, _ string) { // issueID unused but kept for future audit logging
+func updateAgentStateOnDone(cwd, townRoot, exitType, _, mrID string) { // issueID unused but kept for future audit logging
 	// Get role context
 	roleInfo, err := GetRoleWithContext(cwd, townRoot)
 	if err != nil {
@@ -452,9 +597,17 @@ func updateAgentStateOnDone(cwd, townRoot, exitType, _ string) { // issueID unus
 
 	if agentBead.HookBead != "" {
 		hookedBeadID := agentBead.HookBead
+		finishMoleculeRunOnDone(townRoot, hookedBeadID, exitType, doneSummary, mrID)
 		// Only close if the hooked bead exists and is still in "hooked" status
 		if hookedBead, err := bd.Show(hookedBeadID); err == nil && hookedBead.Status == beads.StatusHooked {
-			if err := bd.Close(hookedBeadID); err != nil {
+			if github.SubmittedAsPR(hookedBead) {
+				// The PR is still in review: leave the bead in progress for
+				// gt github sync to close when the PR merges.
+				inProgress := "in_progress"
+				if err := bd.Update(hookedBeadID, beads.UpdateOptions{Status: &inProgress}); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: couldn't update hooked bead %s: %v\n", hookedBeadID, err)
+				}
+			} else if err := bd.Close(hookedBeadID); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: couldn't close hooked bead %s: %v\n", hookedBeadID, err)
 			}
 		}
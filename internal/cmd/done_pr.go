@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/github"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// submitGitHubPR pushes the polecat branch, opens (or reuses) a pull request
+// against target, and links it on the source bead so 'gt github sync' can
+// track review and CI status.
+func submitGitHubPR(g *git.Git, bd *beads.Beads, cwd, issueID, branch, target, worker string) (*github.PullRequest, error) {
+	if err := g.Push("origin", branch, false); err != nil {
+		return nil, fmt.Errorf("pushing %s: %w", branch, err)
+	}
+
+	sourceIssue, err := bd.Show(issueID)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", issueID, err)
+	}
+
+	pr, err := github.CreatePR(cwd, github.PROptions{
+		Branch: branch,
+		Base:   target,
+		Title:  fmt.Sprintf("%s (%s)", sourceIssue.Title, issueID),
+		Body:   prBody(sourceIssue, worker),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	desc := beads.SetPRFields(sourceIssue, &beads.PRFields{
+		PRURL:    pr.URL,
+		PRBranch: branch,
+		PRState:  pr.State,
+		PRReview: pr.Review,
+		PRChecks: pr.Checks,
+	})
+	if err := bd.Update(issueID, beads.UpdateOptions{
+		Description: &desc,
+		AddLabels:   []string{github.LabelPR},
+	}); err != nil {
+		// The PR exists; losing the link only costs tracking, so don't fail.
+		style.PrintWarning("could not link PR on %s: %v", issueID, err)
+	}
+	return pr, nil
+}
+
+// prBody builds the pull request description from the source bead.
+func prBody(issue *beads.Issue, worker string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Bead: `%s`\n", issue.ID)
+
+	// Drop any pr_* lines left from an earlier submission
+	if desc := beads.SetPRFields(issue, nil); strings.TrimSpace(desc) != "" {
+		sb.WriteString("\n" + strings.TrimSpace(desc) + "\n")
+	}
+	if worker != "" {
+		fmt.Fprintf(&sb, "\n---\nSubmitted by Gas Town polecat %s via `gt done`.\n", worker)
+	}
+	return sb.String()
+}
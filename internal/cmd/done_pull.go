@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// tierLabelPrefix marks a bead for polecats of one model tier
+// ("tier:opus"). Unlabeled beads suit any tier.
+const tierLabelPrefix = "tier:"
+
+// pulledBead is the work a polecat took on after gt done.
+type pulledBead struct {
+	Agent  string // The polecat, as rig/polecats/name
+	Issue  *beads.Issue
+	Branch string
+	Count  int // Beads this session, the pulled one included
+	Max    int
+}
+
+// pullNextBead hands a polecat that just finished a bead the rig's next
+// ready bead for its tier, when the town's scheduler.auto_pull allows the
+// rig another bead this session and spend is under the budget's warning
+// threshold. The bead is hooked to the polecat and its worktree moved to a
+// fresh branch for it. Returns nil when the polecat should exit as usual.
+func pullNextBead(townRoot, rigName, polecatName, finished string) *pulledBead {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Scheduler == nil {
+		return nil
+	}
+	limit := settings.Scheduler.AutoPull[rigName]
+	if limit < 2 {
+		return nil
+	}
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return nil
+	}
+	done, err := mgr.SessionBeads(polecatName)
+	if err != nil {
+		style.PrintWarning("auto-pull: reading session beads: %v", err)
+		return nil
+	}
+	if len(done)+1 >= limit {
+		fmt.Printf("%s Finished %d beads this session; recycling for a fresh context\n", style.Dim.Render("○"), len(done)+1)
+		return nil
+	}
+	if status, err := budget.Load(townRoot, time.Now()); err == nil && status.Level >= budget.Warn {
+		fmt.Printf("%s Not pulling more work: %s\n", style.Dim.Render("○"), status.Reason())
+		return nil
+	}
+
+	// Polecats finishing together must not pull the same bead
+	lock := flock.New(filepath.Join(r.Path, "polecats", ".pull.lock"))
+	if err := lock.Lock(); err != nil {
+		style.PrintWarning("auto-pull: %v", err)
+		return nil
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	bd := beads.New(r.BeadsPath())
+	ready, err := bd.ReadyWork()
+	if err != nil {
+		style.PrintWarning("auto-pull: listing ready beads: %v", err)
+		return nil
+	}
+	queue := scheduler.NewQueue(townRoot)
+	queued := make(map[string]string)
+	if reqs, err := queue.List(); err == nil {
+		for _, req := range reqs {
+			if req.Rig == rigName {
+				queued[req.Bead] = requestTier(req.Args)
+			}
+		}
+	}
+	next := selectNextBead(ready, queued, os.Getenv(polecat.EnvTier))
+	if next == nil {
+		fmt.Printf("%s No ready bead for this polecat\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	agentID := fmt.Sprintf("%s/polecats/%s", rigName, polecatName)
+	hooked, assignee := beads.StatusHooked, agentID
+	if err := bd.Update(next.ID, beads.UpdateOptions{Status: &hooked, Assignee: &assignee}); err != nil {
+		style.PrintWarning("auto-pull: hooking %s: %v", next.ID, err)
+		return nil
+	}
+	p, err := mgr.MoveToBead(polecatName, next.ID)
+	if err != nil {
+		style.PrintWarning("auto-pull: %v", err)
+		open, nobody := "open", ""
+		_ = bd.Update(next.ID, beads.UpdateOptions{Status: &open, Assignee: &nobody})
+		return nil
+	}
+	if _, ok := queued[next.ID]; ok {
+		_, _ = queue.Remove(next.ID)
+	}
+	if finished != "" {
+		if err := mgr.AddSessionBead(polecatName, finished); err != nil {
+			style.PrintWarning("auto-pull: recording %s: %v", finished, err)
+		}
+	}
+	return &pulledBead{Agent: agentID, Issue: next, Branch: p.Branch, Count: len(done) + 2, Max: limit}
+}
+
+// selectNextBead picks a polecat's next bead from ready, which is sorted
+// most urgent first. Beads slung to the rig and waiting in the spawn queue
+// (queued, bead to the tier it was slung with) go before the rest. Beads
+// for another tier are skipped.
+func selectNextBead(ready []*beads.Issue, queued map[string]string, tier string) *beads.Issue {
+	suits := func(i *beads.Issue) bool {
+		want, ok := queued[i.ID]
+		if !ok || want == "" {
+			want = beadTier(i)
+		}
+		return want == "" || want == tier
+	}
+	var fallback *beads.Issue
+	for _, i := range ready {
+		if !suits(i) {
+			continue
+		}
+		if _, ok := queued[i.ID]; ok {
+			return i
+		}
+		if fallback == nil {
+			fallback = i
+		}
+	}
+	return fallback
+}
+
+// beadTier returns the tier a bead's tier: label asks for, or "".
+func beadTier(issue *beads.Issue) string {
+	for _, l := range issue.Labels {
+		if t, ok := strings.CutPrefix(l, tierLabelPrefix); ok {
+			return t
+		}
+	}
+	return ""
+}
+
+// requestTier returns the --tier a queued sling was made with, or "".
+func requestTier(args []string) string {
+	for i, a := range args {
+		if t, ok := strings.CutPrefix(a, "--tier="); ok {
+			return t
+		}
+		if a == "--tier" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// startPulledBead points the polecat's agent bead at the pulled bead and
+// tells the agent, whose gt done this is, to start on it.
+func startPulledBead(cwd string, next *pulledBead) {
+	updateAgentHookBead(next.Agent, next.Issue.ID, cwd, "")
+	_ = events.LogFeed(events.TypeHook, next.Agent, events.HookPayload(next.Issue.ID))
+
+	fmt.Println()
+	fmt.Printf("%s Pulled next bead %s: %s (%d of %d this session)\n", style.Bold.Render("→"),
+		next.Issue.ID, next.Issue.Title, next.Count, next.Max)
+	fmt.Printf("  Branch: %s\n", next.Branch)
+	fmt.Printf("  Start working on it now - run `gt hook` to see the hook, then begin.\n")
+}
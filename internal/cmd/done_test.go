@@ -246,3 +246,36 @@ func TestDoneCircularRedirectProtection(t *testing.T) {
 		t.Errorf("circular redirect should return original: got %s, want %s", resolved, beadsDir)
 	}
 }
+
+func TestSelectNextBead(t *testing.T) {
+	ready := []*beads.Issue{
+		{ID: "gt-p0", Labels: []string{"tier:opus"}},
+		{ID: "gt-p1"},
+		{ID: "gt-p2"},
+		{ID: "gt-p3", Labels: []string{"tier:haiku"}},
+	}
+	tests := []struct {
+		name   string
+		queued map[string]string
+		tier   string
+		want   string
+	}{
+		{"most urgent for any tier", nil, "", "gt-p1"},
+		{"tier label matches", nil, "opus", "gt-p0"},
+		{"queued first", map[string]string{"gt-p2": ""}, "", "gt-p2"},
+		{"queued for another tier", map[string]string{"gt-p2": "opus"}, "", "gt-p1"},
+		{"queued tier overrides label", map[string]string{"gt-p3": "sonnet"}, "sonnet", "gt-p3"},
+	}
+	for _, tt := range tests {
+		got := selectNextBead(ready, tt.queued, tt.tier)
+		if got == nil || got.ID != tt.want {
+			t.Errorf("%s: selectNextBead = %v, want %s", tt.name, got, tt.want)
+		}
+	}
+	if got := selectNextBead(ready[3:], nil, "opus"); got != nil {
+		t.Errorf("selectNextBead with no match = %s, want nil", got.ID)
+	}
+	if got := requestTier([]string{"sling", "gt-p2", "gastown", "--tier", "opus"}); got != "opus" {
+		t.Errorf("requestTier = %q, want opus", got)
+	}
+}
@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	epicDescription string
+	epicJSON        bool
+	epicAll         bool
+)
+
+var epicCmd = &cobra.Command{
+	Use:     "epic",
+	GroupID: GroupWork,
+	Short:   "Group beads from several rigs under one epic",
+	RunE:    requireSubcommand,
+	Long: `Epics are milestones that span rigs.
+
+A bd epic only sees its own database's children. A gt epic lives in the
+town's beads (hq-ep-*) and tracks beads in any rig, like a convoy, so one
+epic can hold the backend, frontend and docs work for a release. Unlike a
+convoy it doesn't close itself: it stands for the milestone, not a batch.
+
+'gt epic status' rolls the epic up across rigs: how much is done, per rig,
+and which beads are blocked and by what. 'gt plan --milestone <epic>'
+projects its completion date.
+
+Examples:
+  gt epic create "Billing v2" gt-abc12 fe-9xk2 docs-3mm
+  gt epic add hq-ep-x7k2m gt-def34
+  gt epic status hq-ep-x7k2m
+  gt epic list`,
+}
+
+var epicCreateCmd = &cobra.Command{
+	Use:   "create <title> [bead-id...]",
+	Short: "Create an epic, optionally with beads",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runEpicCreate,
+}
+
+var epicAddCmd = &cobra.Command{
+	Use:   "add <epic-id> <bead-id>...",
+	Short: "Attach beads from any rig to an epic",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runEpicAdd,
+}
+
+var epicStatusCmd = &cobra.Command{
+	Use:   "status <epic-id>",
+	Short: "Show an epic's completion and blocked beads across rigs",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEpicStatus,
+}
+
+var epicListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List epics with their completion",
+	Args:  cobra.NoArgs,
+	RunE:  runEpicList,
+}
+
+func init() {
+	epicCreateCmd.Flags().StringVarP(&epicDescription, "description", "d", "", "Epic description")
+	epicStatusCmd.Flags().BoolVar(&epicJSON, "json", false, "Output as JSON")
+	epicListCmd.Flags().BoolVar(&epicJSON, "json", false, "Output as JSON")
+	epicListCmd.Flags().BoolVar(&epicAll, "all", false, "Include closed epics")
+
+	epicCmd.AddCommand(epicCreateCmd)
+	epicCmd.AddCommand(epicAddCmd)
+	epicCmd.AddCommand(epicStatusCmd)
+	epicCmd.AddCommand(epicListCmd)
+	rootCmd.AddCommand(epicCmd)
+}
+
+// EpicMember is a bead an epic tracks.
+type EpicMember struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Rig       string   `json:"rig"`
+	Assignee  string   `json:"assignee,omitempty"`
+	BlockedBy []string `json:"blocked_by,omitempty"` // Open blockers
+}
+
+// EpicRig is one rig's share of an epic.
+type EpicRig struct {
+	Name  string `json:"name"`
+	Total int    `json:"total"`
+	Done  int    `json:"done"`
+}
+
+// EpicStatus is an epic rolled up across rigs.
+type EpicStatus struct {
+	ID         string       `json:"id"`
+	Title      string       `json:"title"`
+	Status     string       `json:"status"`
+	Total      int          `json:"total"`
+	Done       int          `json:"done"`
+	InProgress int          `json:"in_progress"`
+	Percent    int          `json:"percent"`
+	Rigs       []EpicRig    `json:"rigs"`
+	Blocked    []EpicMember `json:"blocked"`
+	Members    []EpicMember `json:"members"`
+}
+
+// rollupEpic computes an epic's completion from its members.
+func rollupEpic(id, title, status string, members []EpicMember) *EpicStatus {
+	s := &EpicStatus{ID: id, Title: title, Status: status, Rigs: []EpicRig{}, Blocked: []EpicMember{}, Members: members}
+	if s.Members == nil {
+		s.Members = []EpicMember{}
+	}
+	rigs := map[string]*EpicRig{}
+	for _, m := range members {
+		r, ok := rigs[m.Rig]
+		if !ok {
+			r = &EpicRig{Name: m.Rig}
+			rigs[m.Rig] = r
+		}
+		s.Total++
+		r.Total++
+		switch {
+		case m.Status == "closed":
+			s.Done++
+			r.Done++
+		case len(m.BlockedBy) > 0:
+			s.Blocked = append(s.Blocked, m)
+		case m.Status == "in_progress" || m.Status == beads.StatusHooked:
+			s.InProgress++
+		}
+	}
+	if s.Total > 0 {
+		s.Percent = s.Done * 100 / s.Total
+	}
+	for _, r := range rigs {
+		s.Rigs = append(s.Rigs, *r)
+	}
+	sort.Slice(s.Rigs, func(i, j int) bool { return s.Rigs[i].Name < s.Rigs[j].Name })
+	return s
+}
+
+// beadRigName names the rig a bead's prefix routes to, or "town".
+func beadRigName(townRoot, id string) string {
+	path := beads.GetRigPathForPrefix(townRoot, beads.ExtractPrefix(id))
+	if path == "" || path == townRoot {
+		return "town"
+	}
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil {
+		return "town"
+	}
+	return strings.Split(filepath.ToSlash(rel), "/")[0]
+}
+
+// epicMembers loads the beads an epic tracks, with their open blockers.
+func epicMembers(townBeads, epicID string) []EpicMember {
+	townRoot := filepath.Dir(townBeads)
+	var members []EpicMember
+	for _, t := range getTrackedIssues(townBeads, epicID) {
+		m := EpicMember{ID: t.ID, Title: t.Title, Status: t.Status, Rig: beadRigName(townRoot, t.ID), Assignee: t.Assignee}
+		if m.Status != "closed" {
+			if issue, err := beads.New(beadLocation(townRoot, t.ID)).Show(t.ID); err == nil {
+				for _, dep := range issue.Dependencies {
+					if (dep.DependencyType == "" || dep.DependencyType == "blocks") && dep.Status != "closed" {
+						m.BlockedBy = append(m.BlockedBy, dep.ID)
+					}
+				}
+			}
+		}
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Rig != members[j].Rig {
+			return members[i].Rig < members[j].Rig
+		}
+		return members[i].ID < members[j].ID
+	})
+	return members
+}
+
+// showEpic loads an epic from the town's beads and checks it is one.
+func showEpic(townBeads, epicID string) (*beads.Issue, error) {
+	issue, err := beads.New(townBeads).Show(epicID)
+	if err != nil {
+		return nil, fmt.Errorf("epic '%s' not found", epicID)
+	}
+	if issue.Type != "epic" {
+		return nil, fmt.Errorf("'%s' is not an epic (type: %s)", epicID, issue.Type)
+	}
+	return issue, nil
+}
+
+// trackInEpic adds 'tracks' relations from the epic to each bead and
+// returns the ones added.
+func trackInEpic(townBeads, epicID string, ids []string) []string {
+	var added []string
+	for _, id := range ids {
+		depCmd := exec.Command("bd", "dep", "add", epicID, id, "--type=tracks")
+		depCmd.Dir = townBeads
+		if out, err := depCmd.CombinedOutput(); err != nil {
+			style.PrintWarning("couldn't add %s: %s", id, strings.TrimSpace(string(out)))
+			continue
+		}
+		added = append(added, id)
+	}
+	return added
+}
+
+func runEpicCreate(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	title, ids := args[0], args[1:]
+	epicID := fmt.Sprintf("hq-ep-%s", generateShortID())
+
+	createArgs := []string{"create", "--type=epic", "--id=" + epicID, "--title=" + title, "--json"}
+	if epicDescription != "" {
+		createArgs = append(createArgs, "--description="+epicDescription)
+	}
+	createCmd := exec.Command("bd", createArgs...)
+	createCmd.Dir = townBeads
+	var stderr bytes.Buffer
+	createCmd.Stderr = &stderr
+	if err := createCmd.Run(); err != nil {
+		return fmt.Errorf("creating epic: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+
+	added := trackInEpic(townBeads, epicID, ids)
+	fmt.Printf("%s Created epic %s: %s\n", style.Bold.Render("✓"), epicID, title)
+	if len(added) > 0 {
+		fmt.Printf("  Tracking: %s\n", strings.Join(added, ", "))
+	}
+	return nil
+}
+
+func runEpicAdd(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	epicID := args[0]
+	if _, err := showEpic(townBeads, epicID); err != nil {
+		return err
+	}
+	added := trackInEpic(townBeads, epicID, args[1:])
+	fmt.Printf("%s Added %d bead(s) to epic %s\n", style.Bold.Render("✓"), len(added), epicID)
+	return nil
+}
+
+func runEpicStatus(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	epic, err := showEpic(townBeads, args[0])
+	if err != nil {
+		return err
+	}
+	status := rollupEpic(epic.ID, epic.Title, epic.Status, epicMembers(townBeads, epic.ID))
+	if epicJSON {
+		return outputJSON(status)
+	}
+
+	fmt.Printf("%s %s %s\n", style.Bold.Render("🏔 "+status.ID), status.Title, style.Dim.Render("("+status.Status+")"))
+	fmt.Printf("  %s %d%% (%d of %d done, %d in progress, %d blocked)\n\n",
+		epicProgressBar(status.Percent), status.Percent, status.Done, status.Total, status.InProgress, len(status.Blocked))
+	if status.Total == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No beads yet: gt epic add "+status.ID+" <bead-id>..."))
+		return nil
+	}
+
+	fmt.Printf("  %s\n", style.Bold.Render("By rig"))
+	for _, r := range status.Rigs {
+		fmt.Printf("    %-16s %d/%d\n", r.Name, r.Done, r.Total)
+	}
+
+	if len(status.Blocked) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Blocked"))
+		for _, m := range status.Blocked {
+			fmt.Printf("    %s %s %s\n", style.Warning.Render("⚠"), m.ID, truncateWithEllipsis(m.Title, 40))
+			fmt.Printf("      %s\n", style.Dim.Render("waiting on "+strings.Join(m.BlockedBy, ", ")))
+		}
+	}
+
+	fmt.Printf("\n  %s\n", style.Bold.Render("Beads"))
+	for _, m := range status.Members {
+		mark := style.Dim.Render("○")
+		switch {
+		case m.Status == "closed":
+			mark = style.Success.Render("✓")
+		case len(m.BlockedBy) > 0:
+			mark = style.Warning.Render("⚠")
+		case m.Status == "in_progress" || m.Status == beads.StatusHooked:
+			mark = style.Bold.Render("●")
+		}
+		line := fmt.Sprintf("    %s %-14s %-10s %s", mark, m.ID, m.Rig, truncateWithEllipsis(m.Title, 40))
+		if m.Assignee != "" && m.Status != "closed" {
+			line += " " + style.Dim.Render(m.Assignee)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// epicProgressBar draws a 20-cell completion bar.
+func epicProgressBar(percent int) string {
+	filled := percent / 5
+	return style.Success.Render(strings.Repeat("█", filled)) + style.Dim.Render(strings.Repeat("░", 20-filled))
+}
+
+func runEpicList(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	listArgs := []string{"list", "--type=epic", "--json"}
+	if epicAll {
+		listArgs = append(listArgs, "--all")
+	}
+	listCmd := exec.Command("bd", listArgs...)
+	listCmd.Dir = townBeads
+	out, err := listCmd.Output()
+	if err != nil {
+		return fmt.Errorf("listing epics: %w", err)
+	}
+	var epics []struct {
+		ID     string `json:"id"`
+		Title  string `json:"title"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(out, &epics); err != nil {
+		return fmt.Errorf("parsing epic list: %w", err)
+	}
+
+	var statuses []*EpicStatus
+	for _, e := range epics {
+		if !strings.HasPrefix(e.ID, "hq-ep-") {
+			continue // A town-level bd epic, not a cross-rig one
+		}
+		statuses = append(statuses, rollupEpic(e.ID, e.Title, e.Status, epicMembers(townBeads, e.ID)))
+	}
+	if epicJSON {
+		if statuses == nil {
+			statuses = []*EpicStatus{}
+		}
+		return outputJSON(statuses)
+	}
+	if len(statuses) == 0 {
+		fmt.Printf("%s No epics\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, s := range statuses {
+		fmt.Printf("  %-14s %s %3d%%  %s %s\n", s.ID, epicProgressBar(s.Percent), s.Percent, s.Title,
+			style.Dim.Render(fmt.Sprintf("(%d/%d, %d blocked)", s.Done, s.Total, len(s.Blocked))))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import "testing"
+
+func TestRollupEpic(t *testing.T) {
+	members := []EpicMember{
+		{ID: "gt-1", Rig: "gastown", Status: "closed"},
+		{ID: "gt-2", Rig: "gastown", Status: "in_progress"},
+		{ID: "fe-1", Rig: "frontend", Status: "open", BlockedBy: []string{"gt-2"}},
+		{ID: "fe-2", Rig: "frontend", Status: "closed"},
+		{ID: "hq-1", Rig: "town", Status: "open"},
+	}
+	s := rollupEpic("hq-ep-abc", "Billing v2", "open", members)
+	if s.Total != 5 || s.Done != 2 || s.InProgress != 1 || s.Percent != 40 {
+		t.Errorf("rollup = %d total, %d done, %d in progress, %d%%", s.Total, s.Done, s.InProgress, s.Percent)
+	}
+	if len(s.Blocked) != 1 || s.Blocked[0].ID != "fe-1" {
+		t.Errorf("Blocked = %+v", s.Blocked)
+	}
+	want := []EpicRig{{"frontend", 2, 1}, {"gastown", 2, 1}, {"town", 1, 0}}
+	if len(s.Rigs) != len(want) {
+		t.Fatalf("Rigs = %+v", s.Rigs)
+	}
+	for i, r := range want {
+		if s.Rigs[i] != r {
+			t.Errorf("Rigs[%d] = %+v, want %+v", i, s.Rigs[i], r)
+		}
+	}
+
+	empty := rollupEpic("hq-ep-new", "Empty", "open", nil)
+	if empty.Percent != 0 || len(empty.Members) != 0 {
+		t.Errorf("empty epic = %+v", empty)
+	}
+}
@@ -16,6 +16,7 @@ var (
 	escalateStaleJSON   bool
 	escalateDryRun      bool
 	escalateCloseReason string
+	escalateContext     string
 )
 
 var escalateCmd = &cobra.Command{
@@ -48,9 +49,15 @@ CONFIGURATION:
   - stale_threshold: When unacked escalations are re-escalated (default: 4h)
   - max_reescalations: How many times to bump severity (default: 2)
 
+CONTEXT:
+  Each escalation records the escalating agent's rig, hooked bead and current
+  molecule step, plus anything passed with --context, so whoever picks it up
+  from 'gt escalations' doesn't have to go digging.
+
 Examples:
   gt escalate "Build failing" --severity critical --reason "CI blocked"
   gt escalate "Need API credentials" --severity high --source "plugin:rebuild-gt"
+  gt escalate "Spec is ambiguous" -s high -c "README says X, issue says Y; which wins?"
   gt escalate "Code review requested" --reason "PR #123 ready"
   gt escalate list                          # Show open escalations
   gt escalate ack hq-abc123                 # Acknowledge
@@ -141,6 +148,7 @@ func init() {
 	escalateCmd.Flags().StringVarP(&escalateReason, "reason", "r", "", "Detailed reason for escalation")
 	escalateCmd.Flags().StringVar(&escalateSource, "source", "", "Source identifier (e.g., plugin:rebuild-gt, patrol:deacon)")
 	escalateCmd.Flags().StringVar(&escalateRelatedBead, "related", "", "Related bead ID (task, bug, etc.)")
+	escalateCmd.Flags().StringVarP(&escalateContext, "context", "c", "", "What the human needs to know: what you tried, what you need")
 	escalateCmd.Flags().BoolVar(&escalateJSON, "json", false, "Output as JSON")
 	escalateCmd.Flags().BoolVarP(&escalateDryRun, "dry-run", "n", false, "Show what would be done without executing")
 
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -75,6 +77,7 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		EscalatedBy: agentID,
 		EscalatedAt: time.Now().Format(time.RFC3339),
 		RelatedBead: escalateRelatedBead,
+		Context:     escalationContext(townRoot, escalateContext),
 	}
 
 	issue, err := bd.CreateEscalationBead(description, fields)
@@ -93,7 +96,7 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 			From:    agentID,
 			To:      target,
 			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
-			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead, fields.Context),
 			Type:    mail.TypeTask,
 		}
 
@@ -490,6 +493,7 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 			"closedBy":    fields.ClosedBy,
 			"closedReason": fields.ClosedReason,
 			"relatedBead": fields.RelatedBead,
+			"context":     fields.Context,
 		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
@@ -516,10 +520,43 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if fields.Context != "" {
+		fmt.Printf("  Context:\n")
+		for _, line := range strings.Split(fields.Context, "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
 
 	return nil
 }
 
+// escalationContext describes where the escalating agent is: its rig,
+// hooked bead and molecule step, followed by the agent's own notes.
+// Whatever can't be detected is left out.
+func escalationContext(townRoot, notes string) string {
+	var lines []string
+	if cwd, err := os.Getwd(); err == nil {
+		if roleInfo, err := GetRoleWithContext(cwd, townRoot); err == nil {
+			if roleInfo.Rig != "" {
+				lines = append(lines, "Rig: "+roleInfo.Rig)
+			}
+			if hooked := detectHookedBead(cwd, roleInfo); hooked != "" {
+				lines = append(lines, "Hooked: "+hooked)
+			}
+			if moleculeID, stepID, stepTitle := detectMoleculeContext(cwd, roleInfo); stepID != "" {
+				lines = append(lines, fmt.Sprintf("Step: %s %s (molecule %s)", stepID, stepTitle, moleculeID))
+			}
+		}
+	}
+	if notes = strings.TrimSpace(notes); notes != "" {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, notes)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // Helper functions
 
 // extractMailTargetsFromActions extracts mail targets from action strings.
@@ -539,8 +576,8 @@ func extractMailTargetsFromActions(actions []string) []string {
 }
 
 // executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
+// Email and SMS only log warnings if contacts aren't configured - actual sending is future work.
+func executeExternalActions(actions []string, cfg *config.EscalationConfig, beadID, severity, description string) {
 	for _, action := range actions {
 		switch {
 		case strings.HasPrefix(action, "email:"):
@@ -563,8 +600,16 @@ func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _
 			if cfg.Contacts.SlackWebhook == "" {
 				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
 			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
+				msg := &notify.Message{
+					Event: notify.EventEscalation,
+					Title: fmt.Sprintf("%s Escalation %s (%s)", severityEmoji(severity), beadID, severity),
+					Text:  description,
+				}
+				if err := notify.NewSlackSink(cfg.Contacts.SlackWebhook).Send(context.Background(), msg); err != nil {
+					style.PrintWarning("slack action failed: %v", err)
+				} else {
+					fmt.Printf("  💬 Posted to Slack\n")
+				}
 			}
 
 		case action == "log":
@@ -575,7 +620,7 @@ func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _
 	}
 }
 
-func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
+func formatEscalationMailBody(beadID, severity, reason, from, related, context string) string {
 	var lines []string
 	lines = append(lines, fmt.Sprintf("Escalation ID: %s", beadID))
 	lines = append(lines, fmt.Sprintf("Severity: %s", severity))
@@ -589,6 +634,11 @@ func formatEscalationMailBody(beadID, severity, reason, from, related string) st
 		lines = append(lines, "")
 		lines = append(lines, fmt.Sprintf("Related: %s", related))
 	}
+	if context != "" {
+		lines = append(lines, "")
+		lines = append(lines, "Context:")
+		lines = append(lines, context)
+	}
 	lines = append(lines, "")
 	lines = append(lines, "---")
 	lines = append(lines, "To acknowledge: gt escalate ack "+beadID)
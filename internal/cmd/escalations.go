@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var escalationsCmd = &cobra.Command{
+	Use:     "escalations",
+	GroupID: GroupComm,
+	Short:   "Escalation mailbox: what needs a human",
+	Long: `Work through the escalations agents have raised for a human.
+
+Polecats escalate with 'gt escalate' when they hit something they can't
+settle themselves: ambiguous requirements, missing credentials, failures
+that keep coming back. The witness escalates help requests it can't handle.
+Each escalation records its severity and the agent's context (rig, hooked
+bead, molecule step, notes), is mailed per settings/escalation.json, and
+goes to the notification sinks routed for the escalation event.
+
+With no subcommand, lists the open escalations.
+
+Examples:
+  gt escalations                                  # Open escalations
+  gt escalations list --all                       # Include resolved
+  gt escalations ack hq-abc123                    # I'm on it
+  gt escalations resolve hq-abc123 --reason "Added the API key to .env"`,
+	Args: cobra.NoArgs,
+	RunE: runEscalateList,
+}
+
+var escalationsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List open escalations",
+	Long: `List open escalations with their severity, who raised them and who has
+acknowledged them. Use --all to include resolved ones.
+
+Examples:
+  gt escalations list
+  gt escalations list --all --json`,
+	Args: cobra.NoArgs,
+	RunE: runEscalateList,
+}
+
+var escalationsAckCmd = &cobra.Command{
+	Use:   "ack <escalation-id>",
+	Short: "Acknowledge an escalation",
+	Long: `Acknowledge an escalation to show someone is handling it. Acknowledged
+escalations are no longer re-escalated by 'gt escalate stale'.
+
+Examples:
+  gt escalations ack hq-abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEscalateAck,
+}
+
+var escalationsResolveCmd = &cobra.Command{
+	Use:   "resolve <escalation-id>",
+	Short: "Resolve and close an escalation",
+	Long: `Close an escalation once the agent is unblocked, recording the resolution.
+
+Examples:
+  gt escalations resolve hq-abc123 --reason "Requirements clarified in gt-xyz"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEscalationsResolve,
+}
+
+var escalationsShowCmd = &cobra.Command{
+	Use:   "show <escalation-id>",
+	Short: "Show an escalation and its context",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEscalateShow,
+}
+
+func init() {
+	escalationsCmd.Flags().BoolVar(&escalateListJSON, "json", false, "Output as JSON")
+	escalationsCmd.Flags().BoolVar(&escalateListAll, "all", false, "Include resolved escalations")
+	escalationsListCmd.Flags().BoolVar(&escalateListJSON, "json", false, "Output as JSON")
+	escalationsListCmd.Flags().BoolVar(&escalateListAll, "all", false, "Include resolved escalations")
+	escalationsResolveCmd.Flags().StringVarP(&escalateCloseReason, "reason", "r", "", "Resolution (default: \"resolved\")")
+	escalationsShowCmd.Flags().BoolVar(&escalateJSON, "json", false, "Output as JSON")
+
+	escalationsCmd.AddCommand(escalationsListCmd)
+	escalationsCmd.AddCommand(escalationsAckCmd)
+	escalationsCmd.AddCommand(escalationsResolveCmd)
+	escalationsCmd.AddCommand(escalationsShowCmd)
+
+	rootCmd.AddCommand(escalationsCmd)
+}
+
+func runEscalationsResolve(cmd *cobra.Command, args []string) error {
+	if escalateCloseReason == "" {
+		escalateCloseReason = "resolved"
+	}
+	return runEscalateClose(cmd, args)
+}
@@ -0,0 +1,459 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/estop"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	estopReason string
+	estopDryRun bool
+)
+
+var estopCmd = &cobra.Command{
+	Use:     "estop",
+	Aliases: []string{"emergency-stop"},
+	GroupID: GroupServices,
+	Short:   "Emergency stop: halt every agent and snapshot the town",
+	Long: `Stop the whole town now, when it has gone haywire and is burning tokens.
+
+gt estop first snapshots which agents are running (with each polecat's
+hooked bead) to .runtime/estop.json, then, in order:
+
+  1. Stops the daemon, so nothing gets restarted
+  2. Pauses the Deacon and every rig's Refinery
+  3. Stops Boot, the Deacon and the Witnesses
+  4. Stops polecats and crew (Ctrl-C first, then kills the session)
+  5. Stops the Mayor
+
+Worktrees, hooks and the merge queue are left untouched. While the town is
+stopped 'gt up' and 'gt start' refuse to run; bring back exactly what was
+running with:
+
+  gt resume --all
+
+Unlike 'gt down --nuke', only Gas Town sessions are killed.
+
+Examples:
+  gt estop -r "polecats looping on gp-abc"
+  gt estop --dry-run
+  gt resume --all --status           # Show the snapshot
+  gt resume --all`,
+	Args: cobra.NoArgs,
+	RunE: runEstop,
+}
+
+func init() {
+	estopCmd.Flags().StringVarP(&estopReason, "reason", "r", "", "Why the town was stopped")
+	estopCmd.Flags().BoolVar(&estopDryRun, "dry-run", false, "Show what would be stopped without stopping it")
+	rootCmd.AddCommand(estopCmd)
+}
+
+func runEstop(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	t := tmux.NewTmux()
+	if !t.IsAvailable() {
+		return fmt.Errorf("tmux not available (is tmux installed and on PATH?)")
+	}
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	rigs := discoverRigs(townRoot)
+
+	// Snapshot before touching anything. If the town is already stopped,
+	// keep the original snapshot and add anything that came back since.
+	snap, err := estop.Load(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+	if snap == nil {
+		daemonRunning, _, _ := daemon.IsRunning(townRoot)
+		deaconPaused, _, _ := deacon.IsPaused(townRoot)
+		snap = &estop.Snapshot{
+			StoppedAt:    time.Now().UTC(),
+			StoppedBy:    detectSender(),
+			Reason:       estopReason,
+			Daemon:       daemonRunning,
+			DeaconPaused: deaconPaused,
+		}
+	} else {
+		fmt.Printf("%s Town already stopped at %s; stopping anything that restarted\n\n",
+			style.Dim.Render("○"), snap.StoppedAt.Local().Format(time.RFC3339))
+	}
+	known := map[string]bool{}
+	for _, a := range snap.Agents {
+		known[a.Session] = true
+	}
+	running := estop.FromSessions(sessions)
+	for _, a := range running {
+		if known[a.Session] {
+			continue
+		}
+		if a.Role == session.RolePolecat {
+			if mgr, _, err := getPolecatManager(a.Rig); err == nil {
+				if p, err := mgr.Get(a.Name); err == nil {
+					a.Hook = p.Issue
+				}
+			}
+		}
+		snap.Agents = append(snap.Agents, a)
+	}
+
+	if estopDryRun {
+		fmt.Println("═══ DRY RUN: Emergency stop ═══")
+		fmt.Println()
+		if running, pid, _ := daemon.IsRunning(townRoot); running {
+			printDownStatus("Daemon", true, fmt.Sprintf("would stop (PID %d)", pid))
+		}
+		printDownStatus("Deacon", true, "would pause")
+		for _, rigName := range rigs {
+			printDownStatus(fmt.Sprintf("Refinery (%s)", rigName), true, "would pause")
+		}
+		for _, a := range running {
+			printDownStatus(a.Address(), true, "would stop")
+		}
+		fmt.Println()
+		fmt.Println("═══ DRY RUN COMPLETE (no changes made) ═══")
+		return nil
+	}
+
+	lock, err := acquireShutdownLock(townRoot)
+	if err != nil {
+		return fmt.Errorf("cannot proceed: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	if err := estop.Save(townRoot, snap); err != nil {
+		return fmt.Errorf("saving snapshot: %w", err)
+	}
+	allOK := true
+	fail := func(name string, err error) {
+		printDownStatus(name, false, err.Error())
+		allOK = false
+	}
+
+	// 1. Daemon
+	if running, pid, _ := daemon.IsRunning(townRoot); running {
+		if err := daemon.StopDaemon(townRoot); err != nil {
+			fail("Daemon", err)
+		} else {
+			printDownStatus("Daemon", true, fmt.Sprintf("stopped (was PID %d)", pid))
+		}
+	}
+
+	// 2. Pause the Deacon and Refineries
+	if paused, _, _ := deacon.IsPaused(townRoot); !paused {
+		reason := "emergency stop"
+		if snap.Reason != "" {
+			reason += ": " + snap.Reason
+		}
+		if err := deacon.Pause(townRoot, reason, snap.StoppedBy); err != nil {
+			fail("Deacon", err)
+		} else {
+			printDownStatus("Deacon", true, "paused")
+		}
+	}
+	pausedRigs := map[string]bool{}
+	for _, rigName := range snap.PausedRefineries {
+		pausedRigs[rigName] = true
+	}
+	for _, rigName := range rigs {
+		name := fmt.Sprintf("Refinery (%s)", rigName)
+		_, r, err := getRig(rigName)
+		if err != nil {
+			fail(name, err)
+			continue
+		}
+		mgr := refinery.NewManager(r)
+		if ref, err := mgr.Status(); err == nil && ref.State == refinery.StatePaused {
+			continue // Paused before the stop (or by an earlier one)
+		}
+		if err := mgr.Pause(); err != nil {
+			fail(name, err)
+			continue
+		}
+		printDownStatus(name, true, "paused")
+		if !pausedRigs[rigName] {
+			snap.PausedRefineries = append(snap.PausedRefineries, rigName)
+		}
+	}
+	if err := estop.Save(townRoot, snap); err != nil {
+		fail("Snapshot", err)
+	}
+
+	// 3-5. Sessions, watchers before workers, the caller's own session last
+	self, _ := getCurrentTmuxSession()
+	order := []session.Role{session.RoleDeacon, session.RoleWitness, session.RolePolecat, session.RoleCrew, session.RoleMayor}
+	if wasRunning, err := stopSession(t, boot.SessionName); err != nil {
+		fail("Boot", err)
+	} else if wasRunning {
+		printDownStatus("Boot", true, "stopped")
+	}
+	var selfAgent *estop.Agent
+	for _, role := range order {
+		for _, a := range running {
+			if a.Role != role {
+				continue
+			}
+			if a.Session == self {
+				selfAgent = &a
+				continue
+			}
+			estopStopAgent(t, a, fail)
+		}
+	}
+
+	fmt.Println()
+	if allOK {
+		fmt.Printf("%s Town stopped (%d agents)\n", style.Bold.Render("✓"), len(running))
+	} else {
+		fmt.Printf("%s Town stopped with errors\n", style.Bold.Render("✗"))
+	}
+	fmt.Printf("  Snapshot: %s\n", style.Dim.Render(estop.Path(townRoot)))
+	fmt.Printf("  Bring it back with: %s\n", style.Bold.Render("gt resume --all"))
+	_ = events.LogFeed(events.TypeHalt, snap.StoppedBy, events.HaltPayload([]string{"estop"}))
+
+	if selfAgent != nil {
+		estopStopAgent(t, *selfAgent, fail)
+	}
+	if !allOK {
+		return fmt.Errorf("not all agents stopped")
+	}
+	return nil
+}
+
+// estopStopAgent stops one agent's session, interrupting it first.
+// Polecats are checkpointed before they go.
+func estopStopAgent(t *tmux.Tmux, a estop.Agent, fail func(string, error)) {
+	var err error
+	if a.Role == session.RolePolecat {
+		_, r, rigErr := getRig(a.Rig)
+		if rigErr != nil {
+			fail(a.Address(), rigErr)
+			return
+		}
+		// Checkpoint first so the restarted session knows where it was
+		if mgr, _, err := getPolecatManager(a.Rig); err == nil {
+			if p, err := mgr.Get(a.Name); err == nil && p.ClonePath != "" {
+				_ = checkpoint.Update(p.ClonePath, func(cp *checkpoint.Checkpoint) {
+					if cp.HookedBead == "" {
+						cp.HookedBead = a.Hook
+					}
+				})
+			}
+		}
+		err = polecat.NewSessionManager(t, r).Stop(a.Name, false)
+	} else {
+		_, err = stopSession(t, a.Session)
+	}
+	if err != nil {
+		// Agents that exit on the interrupt take their session with them
+		if running, _ := t.HasSession(a.Session); !running {
+			printDownStatus(a.Address(), true, "stopped")
+			return
+		}
+		fail(a.Address(), err)
+		return
+	}
+	printDownStatus(a.Address(), true, "stopped")
+}
+
+// runResumeAll brings the town back from 'gt estop': it lifts the pauses the
+// stop set and restarts the daemon and every agent in the snapshot. Without
+// a snapshot it restarts the polecats that were at work (see
+// runResumeCheckpoints).
+func runResumeAll() error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	snap, err := estop.Load(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+	if snap == nil {
+		// No emergency stop: the machine rebooted or sessions died
+		return runResumeCheckpoints(townRoot)
+	}
+	if resumeStatusOnly {
+		if resumeJSON {
+			return outputJSON(snap)
+		}
+		return displayEstopSnapshot(townRoot, snap)
+	}
+
+	allOK := true
+	result := func(name string, err error, detail string) {
+		if err != nil {
+			printStatus(name, false, err.Error())
+			allOK = false
+			return
+		}
+		printStatus(name, true, detail)
+	}
+
+	// Lift the pauses
+	if !snap.DeaconPaused {
+		err := deacon.Resume(townRoot)
+		result("Deacon", err, "unpaused")
+	}
+	for _, rigName := range snap.PausedRefineries {
+		name := fmt.Sprintf("Refinery (%s)", rigName)
+		_, r, err := getRig(rigName)
+		if err == nil {
+			err = refinery.NewManager(r).Resume()
+		}
+		result(name, err, "unpaused")
+	}
+
+	// Daemon
+	if snap.Daemon {
+		err := ensureDaemon(townRoot)
+		detail := ""
+		if err == nil {
+			_, pid, _ := daemon.IsRunning(townRoot)
+			detail = fmt.Sprintf("PID %d", pid)
+		}
+		result("Daemon", err, detail)
+	}
+
+	// Agents, coordinators before workers
+	t := tmux.NewTmux()
+	order := []session.Role{session.RoleMayor, session.RoleDeacon, session.RoleWitness, session.RoleRefinery, session.RoleCrew, session.RolePolecat}
+	for _, role := range order {
+		for _, a := range snap.AgentsWithRole(role) {
+			err := estopStartAgent(t, townRoot, a)
+			if errors.Is(err, polecat.ErrPolecatNotFound) {
+				printStatus(a.Address(), true, "gone (removed since the stop)")
+				continue
+			}
+			result(a.Address(), err, a.Session)
+		}
+	}
+
+	fmt.Println()
+	if !allOK {
+		fmt.Printf("%s Some agents failed to start; the snapshot is kept, rerun 'gt resume --all' to retry\n", style.Bold.Render("✗"))
+		return fmt.Errorf("not all agents restarted")
+	}
+	if err := estop.Clear(townRoot); err != nil {
+		return fmt.Errorf("clearing snapshot: %w", err)
+	}
+	fmt.Printf("%s Town resumed (%d agents)\n", style.Bold.Render("✓"), len(snap.Agents))
+	_ = events.LogFeed(events.TypeBoot, detectSender(), events.BootPayload("town", []string{"estop-resume"}))
+	return nil
+}
+
+// estopStartAgent restarts one agent from the snapshot. Agents already
+// running count as started.
+func estopStartAgent(t *tmux.Tmux, townRoot string, a estop.Agent) error {
+	switch a.Role {
+	case session.RoleMayor:
+		if err := mayor.NewManager(townRoot).Start(""); err != nil && err != mayor.ErrAlreadyRunning {
+			return err
+		}
+		return nil
+	case session.RoleDeacon:
+		if err := deacon.NewManager(townRoot).Start(""); err != nil && err != deacon.ErrAlreadyRunning {
+			return err
+		}
+		return nil
+	}
+
+	_, r, err := getRig(a.Rig)
+	if err != nil {
+		return err
+	}
+	switch a.Role {
+	case session.RoleWitness:
+		err = witness.NewManager(r).Start(false, "", nil)
+		if err == witness.ErrAlreadyRunning {
+			err = nil
+		}
+	case session.RoleRefinery:
+		err = refinery.NewManager(r).Start(false)
+		if err == refinery.ErrAlreadyRunning {
+			err = nil
+		}
+	case session.RoleCrew:
+		crewMgr, _, mgrErr := getCrewManager(a.Rig)
+		if mgrErr != nil {
+			return mgrErr
+		}
+		err = crewMgr.Start(a.Name, crew.StartOptions{})
+		if err == crew.ErrSessionRunning {
+			err = nil
+		}
+	case session.RolePolecat:
+		err = polecat.NewSessionManager(t, r).Start(a.Name, polecat.SessionStartOptions{})
+		if err == polecat.ErrSessionRunning {
+			err = nil
+		}
+	}
+	return err
+}
+
+// displayEstopSnapshot prints what an emergency stop recorded.
+func displayEstopSnapshot(townRoot string, snap *estop.Snapshot) error {
+	fmt.Printf("%s Town emergency-stopped at %s", style.Bold.Render("⏹"), snap.StoppedAt.Local().Format(time.RFC3339))
+	if snap.StoppedBy != "" {
+		fmt.Printf(" by %s", snap.StoppedBy)
+	}
+	fmt.Println()
+	if snap.Reason != "" {
+		fmt.Printf("  Reason: %s\n", snap.Reason)
+	}
+	fmt.Printf("  Daemon was running: %v\n", snap.Daemon)
+	if len(snap.PausedRefineries) > 0 {
+		fmt.Printf("  Paused refineries: %v\n", snap.PausedRefineries)
+	}
+	fmt.Printf("\n  Agents to restart (%d):\n", len(snap.Agents))
+	for _, a := range snap.Agents {
+		line := a.Address()
+		if a.Hook != "" {
+			line += style.Dim.Render("  hook: " + a.Hook)
+		}
+		fmt.Printf("    %s\n", line)
+	}
+	fmt.Printf("\n  %s\n", style.Dim.Render(estop.Path(townRoot)))
+	return nil
+}
+
+// checkNotEstopped refuses to bring services up while the town is
+// emergency-stopped.
+func checkNotEstopped(townRoot string) error {
+	snap, err := estop.Load(townRoot)
+	if err != nil || snap == nil {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Town was emergency-stopped at %s", snap.StoppedAt.Local().Format(time.RFC3339))
+	if snap.Reason != "" {
+		fmt.Fprintf(os.Stderr, " (%s)", snap.Reason)
+	}
+	fmt.Fprintln(os.Stderr)
+	return fmt.Errorf("town is emergency-stopped; run 'gt resume --all' to bring it back")
+}
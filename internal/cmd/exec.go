@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Clone roles gt exec can target.
+const (
+	execRoleMayor    = "mayor"
+	execRoleRefinery = "refinery"
+	execRoleCrew     = "crew"
+	execRolePolecat  = "polecat"
+)
+
+var execRoles = []string{execRoleMayor, execRoleRefinery, execRoleCrew, execRolePolecat}
+
+var (
+	execRoleFlags []string
+	execRigs      string
+	execJobs      int
+	execTimeout   time.Duration
+	execJSON      bool
+)
+
+var execCmd = &cobra.Command{
+	Use:     "exec [flags] -- <command> [args...]",
+	GroupID: GroupWorkspace,
+	Short:   "Run a command in every rig clone",
+	Long: `Run an ad hoc command in the clones of every rig (or the named ones),
+a few at a time, and summarize what failed. For maintenance that doesn't
+warrant a molecule.
+
+Each clone's output is printed, prefixed with the clone, as it finishes.
+The command runs directly, not through a shell; use sh -c for pipes.
+
+Roles pick the clones:
+  mayor     <rig>/mayor/rig (default)
+  refinery  <rig>/refinery/rig
+  crew      every <rig>/crew/<name>
+  polecat   every polecat worktree
+  all       all of the above
+
+Remote rigs are skipped. Exits 1 if the command failed anywhere.
+
+Examples:
+  gt exec -- git fetch --prune
+  gt exec --role mayor --rigs all -- git fetch --prune
+  gt exec --role crew,refinery --rigs gastown,beads -- git status --short
+  gt exec --role all --jobs 8 --timeout 2m -- sh -c 'git gc --auto'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
+}
+
+func init() {
+	execCmd.Flags().StringSliceVar(&execRoleFlags, "role", []string{execRoleMayor}, "Clones to run in: mayor, refinery, crew, polecat or all")
+	execCmd.Flags().StringVar(&execRigs, "rigs", "all", "Comma-separated rigs, or all")
+	execCmd.Flags().IntVarP(&execJobs, "jobs", "j", 4, "Clones to run in at once")
+	execCmd.Flags().DurationVar(&execTimeout, "timeout", 0, "Kill the command in a clone after this long (0 = no limit)")
+	execCmd.Flags().BoolVar(&execJSON, "json", false, "Output results as JSON")
+	rootCmd.AddCommand(execCmd)
+}
+
+// execTarget is one clone gt exec runs in.
+type execTarget struct {
+	Rig  string `json:"rig"`
+	Role string `json:"role"`
+	Name string `json:"name,omitempty"` // Crew or polecat name
+	Dir  string `json:"dir"`
+}
+
+// Label returns the clone as rig/role[/name].
+func (t execTarget) Label() string {
+	if t.Name != "" {
+		return t.Rig + "/" + t.Role + "/" + t.Name
+	}
+	return t.Rig + "/" + t.Role
+}
+
+// execResult is the outcome of the command in one clone.
+type execResult struct {
+	execTarget
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+	Output   string `json:"output"`
+	Duration string `json:"duration"`
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	roles, err := parseExecRoles(execRoleFlags)
+	if err != nil {
+		return err
+	}
+	rigs, err := selectExecRigs(execRigs)
+	if err != nil {
+		return err
+	}
+
+	var targets []execTarget
+	for _, r := range rigs {
+		if r.Remote != "" {
+			if !execJSON {
+				fmt.Printf("%s %s: remote rig, skipped\n", style.Dim.Render("○"), r.Name)
+			}
+			continue
+		}
+		targets = append(targets, execTargets(r, roles)...)
+	}
+	if len(targets) == 0 {
+		if execJSON {
+			return outputJSON([]execResult{})
+		}
+		fmt.Printf("%s No clones to run in\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	var printMu sync.Mutex
+	results, _ := gatherParallel(len(targets), execJobs, 0, func(i int) execResult {
+		res := runInClone(targets[i], args)
+		if !execJSON {
+			printMu.Lock()
+			printExecResult(res)
+			printMu.Unlock()
+		}
+		return res
+	})
+
+	if execJSON {
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	}
+	var failed []execResult
+	for _, res := range results {
+		if res.Error != "" {
+			failed = append(failed, res)
+		}
+	}
+	if !execJSON {
+		fmt.Println()
+		if len(failed) == 0 {
+			fmt.Printf("%s Succeeded in %d clone(s)\n", style.Success.Render("✓"), len(results))
+			return nil
+		}
+		fmt.Printf("%s Failed in %d of %d clone(s):\n", style.Error.Render("✗"), len(failed), len(results))
+		for _, res := range failed {
+			fmt.Printf("  %-36s %s\n", res.Label(), res.Error)
+		}
+	}
+	if len(failed) > 0 {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// parseExecRoles expands and checks --role values.
+func parseExecRoles(values []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var roles []string
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "all" {
+			return execRoles, nil
+		}
+		v = strings.TrimSuffix(v, "s") // polecats, crews
+		switch {
+		case !containsString(execRoles, v):
+			return nil, fmt.Errorf("unknown role %q (use %s or all)", v, strings.Join(execRoles, ", "))
+		case !seen[v]:
+			seen[v] = true
+			roles = append(roles, v)
+		}
+	}
+	return roles, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// selectExecRigs returns the rigs --rigs names, sorted by name.
+func selectExecRigs(spec string) ([]*rig.Rig, error) {
+	all, _, err := getAllRigs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	if spec == "" || spec == "all" {
+		return all, nil
+	}
+	byName := make(map[string]*rig.Rig, len(all))
+	for _, r := range all {
+		byName[r.Name] = r
+	}
+	var rigs []*rig.Rig
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		r, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("rig '%s' not found", name)
+		}
+		rigs = append(rigs, r)
+	}
+	return rigs, nil
+}
+
+// execTargets lists a rig's clones for the roles, skipping any that don't
+// exist.
+func execTargets(r *rig.Rig, roles []string) []execTarget {
+	var targets []execTarget
+	isDir := func(path string) bool {
+		info, err := os.Stat(path)
+		return err == nil && info.IsDir()
+	}
+	for _, role := range roles {
+		switch role {
+		case execRoleMayor, execRoleRefinery:
+			if dir := filepath.Join(r.Path, role, "rig"); isDir(dir) {
+				targets = append(targets, execTarget{Rig: r.Name, Role: role, Dir: dir})
+			}
+		case execRoleCrew:
+			for _, name := range listDirs(filepath.Join(r.Path, "crew")) {
+				targets = append(targets, execTarget{Rig: r.Name, Role: role, Name: name, Dir: filepath.Join(r.Path, "crew", name)})
+			}
+		case execRolePolecat:
+			for _, name := range listDirs(filepath.Join(r.Path, "polecats")) {
+				// polecats/<name>/<rig>/, or polecats/<name>/ for older polecats
+				dir := filepath.Join(r.Path, "polecats", name, r.Name)
+				if !isDir(dir) {
+					dir = filepath.Join(r.Path, "polecats", name)
+					if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+						continue
+					}
+				}
+				targets = append(targets, execTarget{Rig: r.Name, Role: role, Name: name, Dir: dir})
+			}
+		}
+	}
+	return targets
+}
+
+// listDirs returns the visible subdirectories of dir, sorted.
+func listDirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// runInClone runs the command in one clone, capturing its output.
+func runInClone(t execTarget, args []string) execResult {
+	ctx := context.Background()
+	if execTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, execTimeout)
+		defer cancel()
+	}
+	c := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec // G204: the user's own command
+	c.Dir = t.Dir
+	var out bytes.Buffer
+	c.Stdout = &out
+	c.Stderr = &out
+
+	start := time.Now()
+	err := c.Run()
+	res := execResult{
+		execTarget: t,
+		Output:     out.String(),
+		Duration:   time.Since(start).Round(time.Millisecond).String(),
+	}
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		res.ExitCode = -1
+		res.Error = fmt.Sprintf("timed out after %s", execTimeout)
+	case err != nil:
+		res.ExitCode = -1
+		if c.ProcessState != nil {
+			res.ExitCode = c.ProcessState.ExitCode()
+		}
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// printExecResult prints a clone's output, each line prefixed with the
+// clone.
+func printExecResult(res execResult) {
+	mark := style.Success.Render("✓")
+	if res.Error != "" {
+		mark = style.Error.Render("✗")
+	}
+	label := res.Label()
+	fmt.Printf("%s %s %s\n", mark, style.Bold.Render(label), style.Dim.Render(res.Duration))
+	for _, line := range strings.Split(strings.TrimRight(res.Output, "\n"), "\n") {
+		if line != "" {
+			fmt.Printf("  %s %s\n", style.Dim.Render(label+" |"), line)
+		}
+	}
+	if res.Error != "" {
+		fmt.Printf("  %s %s\n", style.Dim.Render(label+" |"), style.Error.Render(res.Error))
+	}
+}
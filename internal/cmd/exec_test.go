@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestExecTargets(t *testing.T) {
+	rigPath := filepath.Join(t.TempDir(), "gastown")
+	for _, dir := range []string{
+		"mayor/rig",
+		"crew/max",
+		"crew/.hidden",
+		"polecats/toast/gastown",
+		"polecats/nux/.git", // older layout
+		"polecats/empty",
+	} {
+		if err := os.MkdirAll(filepath.Join(rigPath, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &rig.Rig{Name: "gastown", Path: rigPath}
+
+	var got []string
+	for _, target := range execTargets(r, execRoles) {
+		rel, _ := filepath.Rel(rigPath, target.Dir)
+		got = append(got, target.Label()+"="+rel)
+	}
+	want := []string{
+		"gastown/mayor=mayor/rig",
+		"gastown/crew/max=crew/max",
+		"gastown/polecat/nux=polecats/nux",
+		"gastown/polecat/toast=polecats/toast/gastown",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("execTargets = %v, want %v", got, want)
+	}
+}
+
+func TestParseExecRoles(t *testing.T) {
+	roles, err := parseExecRoles([]string{"Polecats", "crew", "polecat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"polecat", "crew"}; !reflect.DeepEqual(roles, want) {
+		t.Errorf("roles = %v, want %v", roles, want)
+	}
+	if roles, _ := parseExecRoles([]string{"mayor", "all"}); !reflect.DeepEqual(roles, execRoles) {
+		t.Errorf("all = %v, want %v", roles, execRoles)
+	}
+	if _, err := parseExecRoles([]string{"witness"}); err == nil {
+		t.Error("witness accepted, want an error")
+	}
+}
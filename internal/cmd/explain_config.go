@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/ui"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	explainConfigRig     string
+	explainConfigAgent   string
+	explainConfigAccount string
+	explainConfigSource  string
+	explainConfigJSON    bool
+)
+
+var explainConfigCmd = &cobra.Command{
+	Use:     "explain-config [key-prefix]",
+	GroupID: GroupConfig,
+	Short:   "Show effective configuration and where each value comes from",
+	Long: `Print every effective configuration value together with its source.
+
+Sources, lowest precedence first:
+  default  Built-in default
+  town     <town>/settings/config.json (accounts: mayor/accounts.json)
+  rig      <rig>/settings/config.json
+  env      Environment variable (GT_LOCALE, GT_ACCOUNT, GT_THEME, GT_GLYPHS)
+  flag     Command-line flag (--agent, --account)
+
+There is no organization-wide base layer: towns don't share settings, so
+values common to several towns must be set in each town's settings file.
+
+Inside a rig directory the rig's settings are included automatically;
+use --rig to pick one explicitly. Pass --agent/--account to see how a
+command-line override would resolve. Secret-looking values (webhook URLs,
+tokens) are redacted.
+
+Examples:
+  gt explain-config
+  gt explain-config --rig gastown
+  gt explain-config merge_queue        # Only merge_queue.* keys
+  gt explain-config --source rig       # Only values set in the rig file
+  gt explain-config --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExplainConfig,
+}
+
+func init() {
+	explainConfigCmd.Flags().StringVar(&explainConfigRig, "rig", "", "Rig to include (default: inferred from cwd)")
+	explainConfigCmd.Flags().StringVar(&explainConfigAgent, "agent", "", "Resolve as if --agent were passed")
+	explainConfigCmd.Flags().StringVar(&explainConfigAccount, "account", "", "Resolve as if --account were passed")
+	explainConfigCmd.Flags().StringVar(&explainConfigSource, "source", "", "Only show values from this source (default, town, rig, env, flag)")
+	explainConfigCmd.Flags().BoolVar(&explainConfigJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(explainConfigCmd)
+}
+
+func runExplainConfig(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigName := explainConfigRig
+	if rigName == "" {
+		if cwd, err := os.Getwd(); err == nil {
+			rigName = detectRigFromPath(townRoot, cwd)
+		}
+	}
+	rigPath := ""
+	if rigName != "" {
+		rigPath = filepath.Join(townRoot, rigName)
+		if _, err := os.Stat(rigPath); err != nil {
+			return fmt.Errorf("rig '%s' not found", rigName)
+		}
+	}
+
+	settings, err := config.ExplainConfig(config.ExplainOptions{
+		TownRoot:    townRoot,
+		RigPath:     rigPath,
+		AgentFlag:   explainConfigAgent,
+		AccountFlag: explainConfigAccount,
+	})
+	if err != nil {
+		return err
+	}
+	settings = append(settings, displaySettings()...)
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+
+	prefix := ""
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+	var filtered []config.ExplainedSetting
+	for _, s := range settings {
+		if prefix != "" && s.Key != prefix && !strings.HasPrefix(s.Key, prefix+".") {
+			continue
+		}
+		if explainConfigSource != "" && s.Source != explainConfigSource {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+
+	if explainConfigJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(filtered)
+	}
+
+	scope := "town " + townRoot
+	if rigName != "" {
+		scope += ", rig " + rigName
+	}
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Effective configuration:"), style.Dim.Render("("+scope+")"))
+	if len(filtered) == 0 {
+		fmt.Println("  (no matching settings)")
+		return nil
+	}
+
+	width := 0
+	for _, s := range filtered {
+		if len(s.Key) > width {
+			width = len(s.Key)
+		}
+	}
+	for _, s := range filtered {
+		origin := s.Source
+		if s.Origin != "" {
+			origin += ": " + shortenOrigin(townRoot, s.Origin)
+		}
+		fmt.Printf("  %-*s  %s  %s\n", width, s.Key, explainValue(s.Value), style.Dim.Render("["+origin+"]"))
+	}
+	return nil
+}
+
+// displaySettings reports terminal presentation settings, which are
+// environment-only.
+func displaySettings() []config.ExplainedSetting {
+	explain := func(key, env, value string) config.ExplainedSetting {
+		if os.Getenv(env) != "" {
+			return config.ExplainedSetting{Key: key, Value: value, Source: config.SourceEnv, Origin: env}
+		}
+		return config.ExplainedSetting{Key: key, Value: value, Source: config.SourceDefault}
+	}
+	return []config.ExplainedSetting{
+		explain("display.glyphs", "GT_GLYPHS", ui.GlyphSetName()),
+		explain("display.theme", "GT_THEME", ui.ThemeName()),
+	}
+}
+
+// explainValue renders a setting value as compact JSON.
+func explainValue(v interface{}) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// shortenOrigin shows file origins relative to the town root.
+func shortenOrigin(townRoot, origin string) string {
+	if rel, err := filepath.Rel(townRoot, origin); err == nil && !strings.HasPrefix(rel, "..") && filepath.IsAbs(origin) {
+		return rel
+	}
+	return origin
+}
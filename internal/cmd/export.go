@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/export"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/stats"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	exportFormat  string
+	exportSince   string
+	exportInclude []string
+	exportRig     string
+	exportOutput  string
+)
+
+// exportSets maps --include names to record kinds.
+var exportSets = map[string]string{
+	"beads":  export.KindBead,
+	"runs":   export.KindMoleculeRun,
+	"merges": export.KindMerge,
+	"costs":  export.KindCost,
+}
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	GroupID: GroupDiag,
+	Short:   "Export beads, molecule runs, merges and costs as JSONL or CSV",
+	Long: `Export town data for spreadsheets, BI tools and warehouses.
+
+Four record sets are exported, each with fixed columns:
+  beads    Work beads touched in the window, across the town and rigs
+  runs     Molecule runs started or finished in the window
+  merges   Refinery merge attempts, from the event log
+  costs    Session cost records from the cost ledger
+
+JSONL writes one object per line, tagged with a "kind" field, to stdout
+or -o. CSV writes one file per set, so with more than one set -o names a
+directory (beads.csv, runs.csv, ...); with a single --include it can go
+to stdout. Times are RFC 3339 in UTC.
+
+Examples:
+  gt export --since 30d > town.jsonl
+  gt export --format csv --since 90d -o export/
+  gt export --format csv --include beads --rig greenplace > beads.csv`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "jsonl", "Output format: jsonl or csv")
+	exportCmd.Flags().StringVar(&exportSince, "since", "30d", "Only records from this window (e.g., 7d, 30d)")
+	exportCmd.Flags().StringSliceVar(&exportInclude, "include", []string{"beads", "runs", "merges", "costs"}, "Record sets to export")
+	exportCmd.Flags().StringVar(&exportRig, "rig", "", "Only this rig's records")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file (jsonl) or directory (csv)")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportFormat != "jsonl" && exportFormat != "csv" {
+		return fmt.Errorf("invalid --format %q (want jsonl or csv)", exportFormat)
+	}
+	window, err := parseDuration(exportSince)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --since %q", exportSince)
+	}
+	var sets []string
+	for _, name := range exportInclude {
+		if _, ok := exportSets[name]; !ok {
+			return fmt.Errorf("unknown record set %q (want beads, runs, merges or costs)", name)
+		}
+		sets = append(sets, name)
+	}
+	if exportFormat == "csv" && len(sets) > 1 && exportOutput == "" {
+		return fmt.Errorf("csv export of several record sets needs -o <directory> (or a single --include)")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	since := time.Now().Add(-window)
+
+	var tables []export.Table
+	for _, name := range sets {
+		table, err := exportTable(townRoot, exportSets[name], since)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, table)
+	}
+
+	if exportFormat == "jsonl" {
+		return writeExport(exportOutput, func(w io.Writer) error { return export.WriteJSONL(w, tables...) })
+	}
+	if len(tables) == 1 && !isExportDir(exportOutput) {
+		return writeExport(exportOutput, func(w io.Writer) error { return export.WriteCSV(w, tables[0]) })
+	}
+	if err := os.MkdirAll(exportOutput, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", exportOutput, err)
+	}
+	for i, table := range tables {
+		path := filepath.Join(exportOutput, sets[i]+".csv")
+		if err := writeExport(path, func(w io.Writer) error { return export.WriteCSV(w, table) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isExportDir reports whether -o names a directory: an existing one, or a
+// path ending in a separator.
+func isExportDir(path string) bool {
+	if path == "" {
+		return false
+	}
+	if strings.HasSuffix(path, string(os.PathSeparator)) {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// writeExport writes to path, or stdout if path is empty.
+func writeExport(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path) //nolint:gosec // G304: path is the user's -o
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	fmt.Fprintf(os.Stderr, "%s Exported %s\n", style.Success.Render("✓"), path)
+	return nil
+}
+
+// exportTable gathers one record set.
+func exportTable(townRoot, kind string, since time.Time) (export.Table, error) {
+	switch kind {
+	case export.KindBead:
+		table := export.Table{Kind: kind, Columns: export.BeadColumns}
+		var sources []planSource
+		if exportRig == "" {
+			sources = append(sources, planSource{path: beads.GetTownBeadsPath(townRoot)})
+		}
+		rigs, err := discoverAllRigs(townRoot)
+		if err != nil {
+			return table, fmt.Errorf("listing rigs: %w", err)
+		}
+		for _, r := range rigs {
+			if exportRig == "" || r.Name == exportRig {
+				sources = append(sources, planSource{rig: r.Name, path: r.BeadsPath()})
+			}
+		}
+		if exportRig != "" && len(sources) == 0 {
+			return table, fmt.Errorf("rig '%s' not found", exportRig)
+		}
+		for _, src := range sources {
+			issues, err := beads.New(src.path).List(beads.ListOptions{Status: "all", Priority: -1})
+			if err != nil {
+				style.PrintWarning("could not list %s beads: %v", planSourceName(src), err)
+				continue
+			}
+			table.Rows = append(table.Rows, export.Beads(src.rig, issues, since).Rows...)
+		}
+		return table, nil
+
+	case export.KindMoleculeRun:
+		runs, err := molruns.List(townRoot)
+		if err != nil {
+			style.PrintWarning("could not read molecule runs: %v", err)
+		}
+		var kept []*molruns.Run
+		for _, r := range runs {
+			if exportRig == "" || r.Rig == exportRig {
+				kept = append(kept, r)
+			}
+		}
+		return export.Runs(kept, since), nil
+
+	case export.KindMerge:
+		merges, err := stats.LoadMerges(townRoot)
+		if err != nil {
+			style.PrintWarning("could not read merge events: %v", err)
+		}
+		var kept []stats.Merge
+		for _, m := range merges {
+			if exportRig == "" || m.Rig == exportRig {
+				kept = append(kept, m)
+			}
+		}
+		return export.Merges(kept, since), nil
+
+	default:
+		table := export.Table{Kind: export.KindCost, Columns: export.CostColumns}
+		entries := querySessionEvents()
+		today, _ := querySessionCostWisps(time.Now())
+		for _, e := range append(entries, today...) {
+			if e.EndedAt.Before(since) || (exportRig != "" && e.Rig != exportRig) {
+				continue
+			}
+			table.Rows = append(table.Rows, []any{e.SessionID, e.Role, e.Rig, e.Worker, e.CostUSD, e.StartedAt, e.EndedAt, e.WorkItem})
+		}
+		return table, nil
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/flakes"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	flakesListJSON bool
+	flakesListAll  bool
+
+	flakesCheckMain   bool
+	flakesCheckBranch string
+	flakesCheckFile   string
+)
+
+var flakesCmd = &cobra.Command{
+	Use:     "flakes",
+	GroupID: GroupWork,
+	Short:   "Track tests that fail on main as well as on branches",
+	RunE:    requireSubcommand,
+	Long: `The refinery records each test's pass/fail history per rig: runs of the
+target branch (main) and runs of branches being merged.
+
+A test that fails on a branch is flaky when it also failed in main's
+latest run, or fails on main at least merge_queue.flaky_threshold of the
+time (default 0.2, over at least merge_queue.flaky_min_runs runs, default
+5). A branch whose only failures are flaky tests merges anyway instead of
+going back to its polecat. Set flaky_threshold to 0 in the rig's
+config.json to turn this off.
+
+History is kept in <rig>/.runtime/flakes.json.`,
+}
+
+var flakesListCmd = &cobra.Command{
+	Use:   "list [rig]",
+	Short: "List tests with failures, flaky ones first",
+	Long: `List the tests that have failed on main or on a branch, with their
+failure counts. Flaky tests come first, then the rest by main failure rate.
+
+Examples:
+  gt flakes list
+  gt flakes list greenplace --all
+  gt flakes list --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFlakesList,
+}
+
+var flakesCheckCmd = &cobra.Command{
+	Use:   "check [rig]",
+	Short: "Record a test run and report which failures are flaky",
+	Long: `Read go test output (plain, -v or -json) from stdin or --file and add it
+to the rig's test history, as a run of main (--main) or of a branch being
+merged (--branch).
+
+For a branch run, each failed test is reported as flaky or real, and the
+command exits 1 if any failure is real. Use -v or -json so passing tests
+are recorded too; plain output only lists failures.
+
+Examples:
+  go test -json ./... > /tmp/main.out; gt flakes check --main --file /tmp/main.out
+  go test -json ./... | gt flakes check --branch polecat/Toast/gt-abc`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFlakesCheck,
+}
+
+func init() {
+	flakesListCmd.Flags().BoolVar(&flakesListJSON, "json", false, "Output as JSON")
+	flakesListCmd.Flags().BoolVar(&flakesListAll, "all", false, "Include tests that have only passed")
+
+	flakesCheckCmd.Flags().BoolVar(&flakesCheckMain, "main", false, "The output is a run of the target branch")
+	flakesCheckCmd.Flags().StringVar(&flakesCheckBranch, "branch", "", "The output is a run of this branch")
+	flakesCheckCmd.Flags().StringVar(&flakesCheckFile, "file", "", "Read test output from this file (default: stdin)")
+
+	flakesCmd.AddCommand(flakesListCmd)
+	flakesCmd.AddCommand(flakesCheckCmd)
+
+	rootCmd.AddCommand(flakesCmd)
+}
+
+// flakesRig returns the rig from args, or the current one, and its flake
+// policy.
+func flakesRig(args []string) (*rig.Rig, flakes.Policy, error) {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return nil, flakes.Policy{}, err
+	}
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return nil, flakes.Policy{}, fmt.Errorf("loading merge queue config: %w", err)
+	}
+	return r, eng.Config().FlakePolicy(), nil
+}
+
+func runFlakesList(cmd *cobra.Command, args []string) error {
+	r, policy, err := flakesRig(args)
+	if err != nil {
+		return err
+	}
+	h, err := flakes.Load(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading test history: %w", err)
+	}
+	entries := h.List(policy, flakesListAll)
+
+	if flakesListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("%s No test failures recorded for %s\n", style.Bold.Render("✓"), r.Name)
+		return nil
+	}
+	if !policy.Enabled() {
+		fmt.Printf("%s\n\n", style.Dim.Render("Flake detection is off (merge_queue.flaky_threshold is 0)"))
+	}
+	fmt.Printf("%s Test history for %s:\n\n", style.Bold.Render("🧪"), r.Name)
+	for _, e := range entries {
+		marker := style.Dim.Render("○")
+		if e.IsFlaky {
+			marker = style.Warning.Render("⚠")
+		} else if e.Branch.Failures > 0 {
+			marker = style.Error.Render("✗")
+		}
+		fmt.Printf("  %s %s\n", marker, e.Test)
+		fmt.Printf("      main %d/%d failed (%.0f%%), branches %d/%d failed",
+			e.Main.Failures, e.Main.Runs, e.FailureRate*100, e.Branch.Failures, e.Branch.Runs)
+		if !e.LastFailure.IsZero() {
+			fmt.Printf(", last %s", e.LastFailure.Format("2006-01-02 15:04"))
+			if e.LastBranch != "" {
+				fmt.Printf(" on %s", e.LastBranch)
+			}
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runFlakesCheck(cmd *cobra.Command, args []string) error {
+	if flakesCheckMain == (flakesCheckBranch != "") {
+		return fmt.Errorf("pass one of --main or --branch")
+	}
+	r, policy, err := flakesRig(args)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if flakesCheckFile != "" {
+		data, err = os.ReadFile(flakesCheckFile)
+	} else {
+		data, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return fmt.Errorf("reading test output: %w", err)
+	}
+	results := flakes.ParseGoTest(string(data))
+	failed := flakes.Failed(results)
+
+	var flaky, real []string
+	if err := flakes.Update(r.Path, func(h *flakes.History) {
+		if flakesCheckMain {
+			h.RecordMain(results, time.Now())
+			return
+		}
+		h.RecordBranch(results, flakesCheckBranch, time.Now())
+		flaky, real = h.Classify(failed, policy)
+	}); err != nil {
+		return fmt.Errorf("recording test history: %w", err)
+	}
+
+	fmt.Printf("%s Recorded %d test result(s), %d failed\n", style.Bold.Render("✓"), len(results), len(failed))
+	if flakesCheckMain {
+		return nil
+	}
+	if len(failed) == 0 && goTestFailed(string(data)) {
+		// A build failure or panic outside any test: nothing is flaky
+		fmt.Printf("\n%s The run failed, but not in a test (build failure?)\n", style.Error.Render("✗"))
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return NewSilentExit(1)
+	}
+	for _, test := range flaky {
+		fmt.Printf("  %s %s %s\n", style.Warning.Render("⚠"), test, style.Dim.Render("(flaky on main)"))
+	}
+	for _, test := range real {
+		fmt.Printf("  %s %s\n", style.Error.Render("✗"), test)
+	}
+	if len(real) > 0 {
+		fmt.Printf("\n%s %d failure(s) not explained by flaky tests\n", style.Error.Render("✗"), len(real))
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return NewSilentExit(1)
+	}
+	if len(flaky) > 0 {
+		fmt.Printf("\n%s Only flaky tests failed; safe to merge\n", style.Bold.Render("✓"))
+	}
+	return nil
+}
+
+// goTestFailed reports whether go test output shows a failed package.
+func goTestFailed(output string) bool {
+	return strings.HasPrefix(output, "FAIL") || strings.Contains(output, "\nFAIL") ||
+		strings.Contains(output, `"Action":"fail"`)
+}
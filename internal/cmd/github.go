@@ -0,0 +1,483 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/github"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townmetrics"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// GitHub command flags
+var (
+	githubServePort     int
+	githubServeSecret   string
+	githubServeBind     string
+	githubServeInsecure bool
+	githubSyncSince     time.Duration
+	githubSyncDryRun    bool
+	githubSyncJSON      bool
+	githubSyncNoBack    bool
+	githubPRsJSON       bool
+)
+
+var githubCmd = &cobra.Command{
+	Use:     "github",
+	GroupID: GroupWork,
+	Short:   "Mirror GitHub issues and PR review feedback into beads",
+	Long: `Mirror GitHub issues and pull request review feedback into beads.
+
+Each rig whose git URL points at GitHub receives the repository's issues
+and review comments as beads, labeled 'github' plus a kind label
+(gh:issue, gh:review-comment, gh:review). GitHub labels are copied as
+gh-label:<name>; P0-P4 and priority:<level> labels set bead priority.
+
+Mirrored beads carry an external ref back to their source, so repeated
+deliveries and syncs never create duplicates. Closing an issue on GitHub
+closes its bead. New beads show up in 'gt ready' for the mayor to assign.
+
+Rigs with merge_queue.strategy = "github-pr" submit finished work as pull
+requests (see 'gt done'); sync also refreshes their review and CI status.
+
+COMMANDS:
+  serve     Receive GitHub webhooks
+  sync      Poll the GitHub API (via gh) instead of webhooks
+  prs       Refresh and show pull requests tracked on beads`,
+	RunE: requireSubcommand,
+}
+
+var githubServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Receive GitHub webhooks and ingest them as beads",
+	Long: `Start an HTTP server that receives GitHub webhook deliveries.
+
+Configure a repository webhook pointing at this server with content type
+application/json and these events:
+  - Issues
+  - Pull request review comments
+  - Pull request reviews (only "changes requested" reviews are mirrored)
+
+Set --secret (or GT_GITHUB_WEBHOOK_SECRET) to the webhook secret so
+deliveries are verified via X-Hub-Signature-256. The server refuses to
+start without one unless --insecure is given, since anyone who can reach
+it could otherwise create beads.
+
+The server listens on localhost by default, for use behind a reverse
+proxy or tunnel. Use --bind 0.0.0.0 to accept deliveries directly.
+
+Prometheus metrics for the town are served at /metrics.
+
+Examples:
+  gt github serve --secret=s3cret
+  gt github serve --port 9000 --bind 0.0.0.0 --secret=s3cret
+  gt github serve --insecure       # Local testing without signatures`,
+	RunE: runGitHubServe,
+}
+
+var githubSyncCmd = &cobra.Command{
+	Use:   "sync [owner/repo...]",
+	Short: "Poll GitHub and ingest new issues and review comments",
+	Long: `Poll GitHub through the gh CLI and ingest new items as beads.
+
+Without arguments, every rig with a GitHub git URL is synced. By default
+open issues and review comments from the last 24h are fetched. With
+--since, issues updated in that window are fetched in any state so that
+closures propagate to their beads.
+
+Pull requests opened by 'gt done' in github-pr rigs are refreshed too:
+review and CI status are written back to the bead, and a merged PR closes
+its bead.
+
+Sync also mirrors bead progress back to the issues it came from, for
+stakeholders who follow GitHub rather than beads. Each issue bead posts a
+comment when it is claimed by an agent, when it is submitted (to the merge
+queue or as a PR) and when it merges; when the bead closes, so does the
+issue. Each transition is posted once. --no-sync-back skips this.
+
+Examples:
+  gt github sync
+  gt github sync steveyegge/gastown
+  gt github sync --since 72h --dry-run
+  gt github sync --no-sync-back`,
+	RunE: runGitHubSync,
+}
+
+var githubPRsCmd = &cobra.Command{
+	Use:   "prs",
+	Short: "Refresh and show pull requests tracked on beads",
+	Long: `Refresh the status of pull requests opened by 'gt done' in rigs using
+merge_queue.strategy = "github-pr", and show them.
+
+Each unclosed bead labeled gh:pr (gt done leaves them in progress) has
+its pr_state, pr_review and pr_checks fields updated from GitHub. Merged
+PRs close their bead; PRs closed without merging drop the gh:pr label and
+reopen the bead so the work can be re-slung.
+
+Examples:
+  gt github prs
+  gt github prs --json`,
+	RunE: runGitHubPRs,
+}
+
+func init() {
+	githubServeCmd.Flags().IntVar(&githubServePort, "port", 8090, "HTTP port to listen on")
+	githubServeCmd.Flags().StringVar(&githubServeSecret, "secret", "", "Webhook secret for signature verification (default: $GT_GITHUB_WEBHOOK_SECRET)")
+	githubServeCmd.Flags().StringVar(&githubServeBind, "bind", "127.0.0.1", "Address to listen on")
+	githubServeCmd.Flags().BoolVar(&githubServeInsecure, "insecure", false, "Accept unsigned deliveries when no secret is set")
+
+	githubSyncCmd.Flags().DurationVar(&githubSyncSince, "since", 0, "Fetch items updated within this window (e.g., 72h)")
+	githubSyncCmd.Flags().BoolVarP(&githubSyncDryRun, "dry-run", "n", false, "Show what would be ingested without creating beads")
+	githubSyncCmd.Flags().BoolVar(&githubSyncJSON, "json", false, "Output results as JSON")
+	githubSyncCmd.Flags().BoolVar(&githubSyncNoBack, "no-sync-back", false, "Don't post bead progress to GitHub issues")
+
+	githubPRsCmd.Flags().BoolVar(&githubPRsJSON, "json", false, "Output as JSON")
+
+	githubCmd.AddCommand(githubServeCmd)
+	githubCmd.AddCommand(githubSyncCmd)
+	githubCmd.AddCommand(githubPRsCmd)
+	rootCmd.AddCommand(githubCmd)
+}
+
+// loadGitHubIngester builds an ingester for the town's GitHub-backed rigs.
+func loadGitHubIngester() (*github.Ingester, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigsConfigPath := filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON)
+	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	ingester := github.NewIngester(townRoot, rigsConfig)
+	if len(ingester.Repos()) == 0 {
+		return nil, fmt.Errorf("no rigs have a GitHub git URL")
+	}
+	return ingester, nil
+}
+
+// logIngest records a newly created or closed mirror bead in the feed.
+func logIngest(item *github.Item, result *github.Result) {
+	if result.Action != github.ActionCreated && result.Action != github.ActionClosed {
+		return
+	}
+	_ = events.LogFeed(events.TypeIngest, "github",
+		events.IngestPayload("github", result.Rig, result.BeadID, result.Ref, item.Title))
+}
+
+func runGitHubServe(cmd *cobra.Command, args []string) error {
+	// Read here, not as the flag's default, so --help doesn't print it
+	if githubServeSecret == "" {
+		githubServeSecret = os.Getenv("GT_GITHUB_WEBHOOK_SECRET")
+	}
+	if githubServeSecret == "" && !githubServeInsecure {
+		return fmt.Errorf("no webhook secret set: use --secret or GT_GITHUB_WEBHOOK_SECRET (or --insecure to accept unsigned deliveries)")
+	}
+	ingester, err := loadGitHubIngester()
+	if err != nil {
+		return err
+	}
+
+	handler := github.NewWebhookHandler(ingester, githubServeSecret)
+	handler.OnIngest = func(item *github.Item, result *github.Result) {
+		logIngest(item, result)
+		fmt.Printf("%s %s %s → %s/%s\n", style.Dim.Render(time.Now().Format("15:04:05")),
+			result.Action, result.Ref, result.Rig, result.BeadID)
+	}
+
+	if githubServeSecret == "" {
+		style.PrintWarning("--insecure: deliveries are not verified (use --secret or GT_GITHUB_WEBHOOK_SECRET)")
+	}
+
+	repos := make([]string, 0, len(ingester.Repos()))
+	for repo, rig := range ingester.Repos() {
+		repos = append(repos, fmt.Sprintf("%s → %s", repo, rig))
+	}
+	sort.Strings(repos)
+
+	addr := net.JoinHostPort(githubServeBind, strconv.Itoa(githubServePort))
+	fmt.Printf("%s GitHub webhook receiver listening on %s\n", style.Bold.Render("✓"), addr)
+	for _, r := range repos {
+		fmt.Printf("  %s\n", style.Dim.Render(r))
+	}
+	fmt.Printf("   Press Ctrl+C to stop\n")
+
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		mux.Handle("/metrics", townmetrics.NewRegistry(townRoot).Handler())
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+func runGitHubSync(cmd *cobra.Command, args []string) error {
+	ingester, err := loadGitHubIngester()
+	if err != nil {
+		return err
+	}
+
+	repos := args
+	if len(repos) == 0 {
+		for repo := range ingester.Repos() {
+			repos = append(repos, repo)
+		}
+		sort.Strings(repos)
+	}
+
+	var since time.Time
+	if githubSyncSince > 0 {
+		since = time.Now().Add(-githubSyncSince)
+	}
+
+	var results []*github.Result
+	for _, repo := range repos {
+		if _, ok := ingester.RigFor(repo); !ok {
+			return fmt.Errorf("no rig tracks GitHub repo %s", repo)
+		}
+
+		items, err := github.Poll(repo, since)
+		if err != nil {
+			return err
+		}
+
+		if githubSyncDryRun {
+			rig, _ := ingester.RigFor(repo)
+			for _, item := range items {
+				if !githubSyncJSON {
+					fmt.Printf("  would ingest %s → %s: %s\n", item.ExternalRef(), rig, item.Title)
+				}
+			}
+			continue
+		}
+		ingested, errs := ingester.IngestAll(items)
+		for i, item := range items {
+			if errs[i] != nil {
+				style.PrintWarning("%s: %v", item.ExternalRef(), errs[i])
+				continue
+			}
+			logIngest(item, ingested[i])
+			results = append(results, ingested[i])
+		}
+	}
+
+	if githubSyncDryRun {
+		if townRoot, err := workspace.FindFromCwdOrError(); err == nil && !githubSyncNoBack && !githubSyncJSON {
+			for _, t := range pendingSyncBack(townRoot) {
+				fmt.Printf("  would post %s to %s (%s/%s)\n", t.Stage, t.Ref, t.Rig, t.BeadID)
+			}
+		}
+		return nil
+	}
+
+	if githubSyncJSON {
+		if townRoot, err := workspace.FindFromCwdOrError(); err == nil && !githubSyncNoBack {
+			syncBack(townRoot)
+		}
+		if results == nil {
+			results = []*github.Result{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Action]++
+		if r.Action == github.ActionCreated || r.Action == github.ActionClosed {
+			fmt.Printf("  %s %s → %s/%s\n", r.Action, r.Ref, r.Rig, r.BeadID)
+		}
+	}
+
+	summary := []string{fmt.Sprintf("%d created", counts[github.ActionCreated])}
+	if counts[github.ActionClosed] > 0 {
+		summary = append(summary, fmt.Sprintf("%d closed", counts[github.ActionClosed]))
+	}
+	summary = append(summary, fmt.Sprintf("%d already mirrored", counts[github.ActionExists]))
+	fmt.Printf("%s Synced %d repo(s): %s\n", style.Bold.Render("✓"), len(repos), strings.Join(summary, ", "))
+
+	if townRoot, err := workspace.FindFromCwdOrError(); err == nil {
+		prs := refreshTrackedPRs(townRoot)
+		changed := 0
+		for _, p := range prs {
+			if p.Changed {
+				changed++
+			}
+		}
+		if len(prs) > 0 {
+			fmt.Printf("%s Refreshed %d tracked PR(s), %d updated\n", style.Bold.Render("✓"), len(prs), changed)
+		}
+
+		// After the PR refresh, so PRs it found merged are posted as merges
+		if !githubSyncNoBack {
+			posted := syncBack(townRoot)
+			for _, t := range posted {
+				fmt.Printf("  posted %s to %s\n", t.Stage, t.Ref)
+			}
+			if len(posted) > 0 {
+				fmt.Printf("%s Mirrored %d transition(s) back to GitHub\n", style.Bold.Render("✓"), len(posted))
+			}
+		}
+	}
+	return nil
+}
+
+// pendingSyncBack returns the bead transitions of every rig that haven't
+// been posted to their GitHub issues yet.
+func pendingSyncBack(townRoot string) []*github.Transition {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil {
+		style.PrintWarning("loading rigs config: %v", err)
+		return nil
+	}
+	rigNames := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		rigNames = append(rigNames, name)
+	}
+	sort.Strings(rigNames)
+
+	var pending []*github.Transition
+	for _, rigName := range rigNames {
+		transitions, err := github.PendingTransitions(rigName, beads.New(filepath.Join(townRoot, rigName)))
+		if err != nil {
+			style.PrintWarning("%s: %v", rigName, err)
+			continue
+		}
+		pending = append(pending, transitions...)
+	}
+	return pending
+}
+
+// syncBack posts pending transitions to GitHub and returns those posted.
+// Failures are warned about and retried on the next sync.
+func syncBack(townRoot string) []*github.Transition {
+	var posted []*github.Transition
+	for _, t := range pendingSyncBack(townRoot) {
+		if err := github.PostTransition(beads.New(filepath.Join(townRoot, t.Rig)), t); err != nil {
+			style.PrintWarning("%s → %s: %v", t.BeadID, t.Ref, err)
+			continue
+		}
+		posted = append(posted, t)
+	}
+	return posted
+}
+
+// trackedPR is a work bead's pull request after a refresh.
+type trackedPR struct {
+	Rig     string              `json:"rig"`
+	BeadID  string              `json:"bead_id"`
+	Title   string              `json:"title"`
+	PR      *github.PullRequest `json:"pr"`
+	Changed bool                `json:"changed"`
+}
+
+// refreshTrackedPRs refreshes every gh:pr bead in rigs using the github-pr
+// merge strategy. Per-bead failures are warned about and skipped.
+func refreshTrackedPRs(townRoot string) []trackedPR {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil {
+		style.PrintWarning("loading rigs config: %v", err)
+		return nil
+	}
+
+	rigNames := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		rigNames = append(rigNames, name)
+	}
+	sort.Strings(rigNames)
+
+	var result []trackedPR
+	for _, rigName := range rigNames {
+		rigPath := filepath.Join(townRoot, rigName)
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+		if err != nil || !settings.MergeQueue.UsesGitHubPR() {
+			continue
+		}
+
+		bd := beads.New(rigPath)
+		issues, err := github.TrackedPRs(bd)
+		if err != nil {
+			style.PrintWarning("%s: listing tracked PRs: %v", rigName, err)
+			continue
+		}
+		for _, issue := range issues {
+			pr, changed, err := github.RefreshPR(bd, issue)
+			if err != nil {
+				style.PrintWarning("%s: %v", issue.ID, err)
+				continue
+			}
+			result = append(result, trackedPR{
+				Rig:     rigName,
+				BeadID:  issue.ID,
+				Title:   issue.Title,
+				PR:      pr,
+				Changed: changed,
+			})
+		}
+	}
+	return result
+}
+
+func runGitHubPRs(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	prs := refreshTrackedPRs(townRoot)
+
+	if githubPRsJSON {
+		if prs == nil {
+			prs = []trackedPR{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(prs)
+	}
+
+	if len(prs) == 0 {
+		fmt.Println("No tracked pull requests.")
+		fmt.Println(style.Dim.Render("Set merge_queue.strategy = \"github-pr\" in a rig's settings/config.json to submit work as PRs."))
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Tracked Pull Requests"))
+	for _, p := range prs {
+		status := p.PR.State
+		if p.PR.Review != "" {
+			status += ", " + strings.ReplaceAll(p.PR.Review, "_", " ")
+		}
+		if p.PR.Checks != "" {
+			status += ", checks " + p.PR.Checks
+		}
+		fmt.Printf("  %s %s  #%d  %s\n", p.BeadID, p.Title, p.PR.Number, style.Dim.Render(status))
+		fmt.Printf("    %s\n", style.Dim.Render(p.PR.URL))
+	}
+	return nil
+}
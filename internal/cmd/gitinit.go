@@ -38,6 +38,9 @@ And tracks:
   - .beads/ configuration and issues
   - Rig configs and hop/ directory
 
+Commit town metadata afterwards with 'gt harness commit', or have gt do it
+automatically (see 'gt harness commit --help').
+
 Examples:
   gt git-init                             # Init git with .gitignore
   gt git-init --github=user/repo          # Create private GitHub repo (default)
@@ -83,6 +86,11 @@ const HQGitignore = `# Gas Town HQ .gitignore
 # Runtime state directories (gitignored ephemeral data)
 # =============================================================================
 **/.runtime/
+.logs/
+.artifacts/
+.events.jsonl*
+.feed.jsonl
+.audit.jsonl
 
 # =============================================================================
 # Rig .beads symlinks (point to ignored mayor/rig/.beads, recreated on setup)
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/graph"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	graphMolecule string
+	graphBead     string
+	graphDepth    int
+	graphFormat   string
+	graphOutput   string
+)
+
+var graphCmd = &cobra.Command{
+	Use:     "graph",
+	GroupID: GroupWork,
+	Short:   "Draw a bead's dependencies or a molecule's step DAG",
+	Long: `Render a dependency graph as Graphviz DOT, Mermaid, or SVG.
+
+--molecule takes a formula name and draws its steps (or a convoy formula's
+legs and synthesis), or a molecule bead ID and draws its steps with their
+current status. --bead walks blocking dependencies out from a bead, in both
+directions, up to --depth hops.
+
+Arrows point from a prerequisite to the work that needs it. Nodes and
+edges on a dependency cycle are drawn red and listed on stderr: nothing on
+a cycle can ever become ready.
+
+SVG output runs Graphviz's dot, which must be on PATH.
+
+Examples:
+  gt graph --molecule mol-polecat-work
+  gt graph --molecule gt-mol-abc --format mermaid
+  gt graph --bead gt-42 --depth 3
+  gt graph --bead gt-42 --format svg -o deps.svg`,
+	Args: cobra.NoArgs,
+	RunE: runGraph,
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphMolecule, "molecule", "", "Formula name or molecule bead ID to draw")
+	graphCmd.Flags().StringVar(&graphBead, "bead", "", "Bead whose dependencies to draw")
+	graphCmd.Flags().IntVar(&graphDepth, "depth", 2, "Dependency hops to follow from --bead")
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "Output format: dot, mermaid, or svg")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "Write to this file instead of stdout")
+	rootCmd.AddCommand(graphCmd)
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	if (graphMolecule == "") == (graphBead == "") {
+		return fmt.Errorf("give one of --molecule or --bead")
+	}
+	if graphFormat != "dot" && graphFormat != "mermaid" && graphFormat != "svg" {
+		return fmt.Errorf("invalid --format %q (want dot, mermaid, or svg)", graphFormat)
+	}
+
+	var g *graph.Graph
+	var err error
+	if graphMolecule != "" {
+		g, err = moleculeGraph(graphMolecule)
+	} else {
+		if graphDepth < 1 {
+			return fmt.Errorf("--depth must be at least 1")
+		}
+		g, err = beadGraph(graphBead, graphDepth)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, cycle := range g.Cycles() {
+		fmt.Fprintf(os.Stderr, "%s Dependency cycle: %s\n", style.Warning.Render("⚠"), strings.Join(append(cycle, cycle[0]), " → "))
+	}
+
+	out, err := renderGraph(g, graphFormat)
+	if err != nil {
+		return err
+	}
+	if graphOutput != "" {
+		if err := os.WriteFile(graphOutput, out, 0644); err != nil { //nolint:gosec // G306: graph output is not sensitive
+			return fmt.Errorf("writing %s: %w", graphOutput, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s Wrote %s\n", style.Success.Render("✓"), graphOutput)
+		return nil
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
+
+// renderGraph renders g in the given format.
+func renderGraph(g *graph.Graph, format string) ([]byte, error) {
+	switch format {
+	case "mermaid":
+		return []byte(g.Mermaid()), nil
+	case "svg":
+		if _, err := exec.LookPath("dot"); err != nil {
+			return nil, fmt.Errorf("svg output needs Graphviz's dot on PATH (or use --format dot)")
+		}
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command("dot", "-Tsvg")
+		cmd.Stdin = strings.NewReader(g.DOT())
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("dot: %s", strings.TrimSpace(stderr.String()))
+		}
+		return stdout.Bytes(), nil
+	default:
+		return []byte(g.DOT()), nil
+	}
+}
+
+// moleculeGraph draws a formula's steps, or a molecule instance's if the
+// name isn't a formula but looks like a bead ID.
+func moleculeGraph(name string) (*graph.Graph, error) {
+	path, err := findFormulaFile(name)
+	if err != nil {
+		if looksLikeBeadID(name) {
+			return moleculeInstanceGraph(name)
+		}
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the formula search paths
+	if err != nil {
+		return nil, fmt.Errorf("reading formula: %w", err)
+	}
+	// Decode rather than Parse: Parse rejects cycles, which are what we want to show.
+	f, err := formula.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return formulaGraph(f), nil
+}
+
+// formulaGraph builds the step DAG of a formula.
+func formulaGraph(f *formula.Formula) *graph.Graph {
+	g := graph.New(f.Name)
+	switch f.Type {
+	case formula.TypeConvoy:
+		for _, leg := range f.Legs {
+			g.AddNode(leg.ID, leg.Title, "")
+		}
+		if f.Synthesis != nil {
+			g.AddNode("synthesis", f.Synthesis.Title, "")
+			needs := f.Synthesis.DependsOn
+			if len(needs) == 0 {
+				for _, leg := range f.Legs {
+					needs = append(needs, leg.ID)
+				}
+			}
+			for _, need := range needs {
+				g.AddEdge(need, "synthesis")
+			}
+		}
+	case formula.TypeAspect:
+		for _, aspect := range f.Aspects {
+			g.AddNode(aspect.ID, aspect.Title, "")
+		}
+	default:
+		for _, step := range f.Steps {
+			g.AddNode(step.ID, step.Title, "")
+		}
+		for _, tmpl := range f.Template {
+			g.AddNode(tmpl.ID, tmpl.Title, "")
+		}
+		for _, step := range f.Steps {
+			for _, need := range step.Needs {
+				g.AddEdge(need, step.ID)
+			}
+		}
+		for _, tmpl := range f.Template {
+			for _, need := range tmpl.Needs {
+				g.AddEdge(need, tmpl.ID)
+			}
+		}
+	}
+	return g
+}
+
+// moleculeInstanceGraph draws the steps of a poured molecule with their
+// status.
+func moleculeInstanceGraph(rootID string) (*graph.Graph, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	b := beads.New(beadLocation(townRoot, rootID))
+	root, err := b.Show(rootID)
+	if err != nil {
+		return nil, fmt.Errorf("molecule '%s' not found: %w", rootID, err)
+	}
+	steps, err := b.List(beads.ListOptions{Parent: rootID, Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing %s's steps: %w", rootID, err)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("%s has no steps", rootID)
+	}
+
+	g := graph.New(rootID + ": " + root.Title)
+	inMolecule := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		g.AddNode(step.ID, step.Title, step.Status)
+		inMolecule[step.ID] = true
+	}
+	for _, step := range steps {
+		for _, dep := range step.DependsOn {
+			if inMolecule[dep] {
+				g.AddEdge(dep, step.ID)
+			}
+		}
+	}
+	return g, nil
+}
+
+// isBlockingDep reports whether a dependency holds up its dependent.
+func isBlockingDep(dep beads.IssueDep) bool {
+	return dep.DependencyType == "" || dep.DependencyType == "blocks"
+}
+
+// beadGraph walks blocking dependencies both ways from a bead, up to depth
+// hops.
+func beadGraph(id string, depth int) (*graph.Graph, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	root, err := beads.New(beadLocation(townRoot, id)).Show(id)
+	if err != nil {
+		return nil, fmt.Errorf("bead '%s' not found: %w", id, err)
+	}
+
+	g := graph.New("")
+	g.AddNode(root.ID, root.Title, root.Status)
+	seen := map[string]bool{root.ID: true}
+	frontier := []*beads.Issue{root}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []*beads.Issue
+		visit := func(dep beads.IssueDep) {
+			g.AddNode(dep.ID, dep.Title, dep.Status)
+			if seen[dep.ID] {
+				return
+			}
+			seen[dep.ID] = true
+			issue, err := beads.New(beadLocation(townRoot, dep.ID)).Show(dep.ID)
+			if err != nil {
+				style.PrintWarning("could not read %s: %v", dep.ID, err)
+				return
+			}
+			next = append(next, issue)
+		}
+		for _, issue := range frontier {
+			for _, dep := range issue.Dependencies {
+				if isBlockingDep(dep) {
+					g.AddEdge(dep.ID, issue.ID)
+					visit(dep)
+				}
+			}
+			for _, dep := range issue.Dependents {
+				if isBlockingDep(dep) {
+					g.AddEdge(issue.ID, dep.ID)
+					visit(dep)
+				}
+			}
+		}
+		frontier = next
+	}
+	return g, nil
+}
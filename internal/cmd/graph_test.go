@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/graph"
+)
+
+func TestFormulaGraph_Workflow(t *testing.T) {
+	f, err := formula.Decode([]byte(`
+formula = "mol-x"
+[[steps]]
+id = "load"
+title = "Load"
+[[steps]]
+id = "build"
+needs = ["load", "test"]
+[[steps]]
+id = "test"
+needs = ["build"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := formulaGraph(f)
+	if g.Title != "mol-x" || len(g.Nodes) != 3 {
+		t.Fatalf("graph = %q with %d nodes", g.Title, len(g.Nodes))
+	}
+	want := []graph.Edge{{From: "load", To: "build"}, {From: "test", To: "build"}, {From: "build", To: "test"}}
+	if got := g.Edges; !reflect.DeepEqual(got, want) {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+	if cycles := g.Cycles(); !reflect.DeepEqual(cycles, [][]string{{"build", "test"}}) {
+		t.Errorf("cycles = %v", cycles)
+	}
+}
+
+func TestFormulaGraph_Convoy(t *testing.T) {
+	f, err := formula.Decode([]byte(`
+formula = "review"
+type = "convoy"
+[[legs]]
+id = "security"
+[[legs]]
+id = "perf"
+[synthesis]
+title = "Combine"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []graph.Edge{{From: "security", To: "synthesis"}, {From: "perf", To: "synthesis"}}
+	if got := formulaGraph(f).Edges; !reflect.DeepEqual(got, want) {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+}
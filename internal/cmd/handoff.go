@@ -40,11 +40,20 @@ Examples:
   gt handoff -c                       # Collect state into handoff message
   gt handoff crew                     # Hand off crew session
   gt handoff mayor                    # Hand off mayor session
+  gt handoff gt-abc --to greenplace/Nux -m "Tests pass, docs left"
+  gt handoff gt-abc --to greenplace/crew/max   # Hand work to a human
 
-The --collect (-c) flag gathers current state (hooked work, inbox, ready beads,
-in-progress items) and includes it in the handoff mail. This provides context
+The --collect (-c) flag gathers current state (hooked work and its last
+'gt summarize' summary, inbox, ready beads, in-progress items) and includes
+it in the handoff mail. This provides context
 for the next session without manual summarization.
 
+The --to flag transfers a bead to another polecat or a crew workspace
+instead of cycling a session. The receiver gets the bead on their hook and
+a handoff mail with its branch (pushed to origin), the molecule steps still
+open, the tail of the previous holder's transcript and your -m notes. The
+previous holder is told the work has moved.
+
 Any molecule on the hook will be auto-continued by the new session.
 The SessionStart hook runs 'gt prime' to restore context.`,
 	RunE: runHandoff,
@@ -56,6 +65,7 @@ var (
 	handoffSubject string
 	handoffMessage string
 	handoffCollect bool
+	handoffTo      string
 )
 
 func init() {
@@ -64,10 +74,15 @@ func init() {
 	handoffCmd.Flags().StringVarP(&handoffSubject, "subject", "s", "", "Subject for handoff mail (optional)")
 	handoffCmd.Flags().StringVarP(&handoffMessage, "message", "m", "", "Message body for handoff mail (optional)")
 	handoffCmd.Flags().BoolVarP(&handoffCollect, "collect", "c", false, "Auto-collect state (status, inbox, beads) into handoff message")
+	handoffCmd.Flags().StringVar(&handoffTo, "to", "", "Transfer the bead to another polecat or crew workspace (<rig>/<polecat> or <rig>/crew/<name>)")
 	rootCmd.AddCommand(handoffCmd)
 }
 
 func runHandoff(cmd *cobra.Command, args []string) error {
+	if handoffTo != "" {
+		return runHandoffTransfer(args)
+	}
+
 	// Check if we're a polecat - polecats use gt done instead
 	// GT_POLECAT is set by the session manager when starting polecat sessions
 	if polecatName := os.Getenv("GT_POLECAT"); polecatName != "" {
@@ -729,6 +744,14 @@ func collectHandoffState() string {
 		}
 	}
 
+	// Get the hooked bead's last state summary (gt summarize)
+	summaryOutput, err := exec.Command("gt", "summarize", "--show").Output()
+	if err == nil {
+		if summaryStr := strings.TrimSpace(string(summaryOutput)); summaryStr != "" {
+			parts = append(parts, summaryStr)
+		}
+	}
+
 	// Get inbox summary (first few messages)
 	inboxOutput, err := exec.Command("gt", "mail", "inbox").Output()
 	if err == nil {
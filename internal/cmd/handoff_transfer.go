@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// handoffTranscriptLines is how much of the previous holder's transcript
+// travels with a handoff.
+const handoffTranscriptLines = 20
+
+// handoffTarget is a worker that can receive a bead: a polecat or a crew
+// workspace.
+type handoffTarget struct {
+	Rig  string
+	Kind string // "polecats" or "crew"
+	Name string
+}
+
+// Address returns the target's agent address, the form beads are assigned
+// to.
+func (t handoffTarget) Address() string {
+	return t.Rig + "/" + t.Kind + "/" + t.Name
+}
+
+// parseHandoffTarget parses <rig>/<polecat>, <rig>/polecats/<name> or
+// <rig>/crew/<name>.
+func parseHandoffTarget(s string) (handoffTarget, error) {
+	parts := strings.Split(strings.Trim(s, "/"), "/")
+	switch {
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "" && parts[1] != "crew" && parts[1] != "polecats":
+		return handoffTarget{Rig: parts[0], Kind: "polecats", Name: parts[1]}, nil
+	case len(parts) == 3 && (parts[1] == "polecats" || parts[1] == "crew") && parts[0] != "" && parts[2] != "":
+		return handoffTarget{Rig: parts[0], Kind: parts[1], Name: parts[2]}, nil
+	}
+	return handoffTarget{}, fmt.Errorf("invalid handoff target %q (want <rig>/<polecat>, <rig>/polecats/<name> or <rig>/crew/<name>)", s)
+}
+
+// HandoffPackage is what the receiver of a transferred bead gets: enough
+// to continue without asking the previous holder.
+type HandoffPackage struct {
+	Bead       string   `json:"bead"`
+	Title      string   `json:"title"`
+	From       string   `json:"from,omitempty"`
+	To         string   `json:"to"`
+	Branch     string   `json:"branch,omitempty"`
+	Pushed     bool     `json:"pushed,omitempty"`
+	Dirty      bool     `json:"dirty,omitempty"` // Holder's clone has uncommitted changes
+	Steps      []string `json:"remaining_steps,omitempty"`
+	Transcript string   `json:"transcript,omitempty"`
+	Notes      string   `json:"notes,omitempty"`
+}
+
+// Body renders the package as the handoff mail body.
+func (p *HandoffPackage) Body() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s) is now on your hook", p.Bead, p.Title)
+	if p.From != "" {
+		fmt.Fprintf(&b, ", handed off by %s", p.From)
+	}
+	b.WriteString(".\n")
+
+	if p.Notes != "" {
+		fmt.Fprintf(&b, "\n## Notes\n%s\n", p.Notes)
+	}
+
+	b.WriteString("\n## Branch\n")
+	switch {
+	case p.Branch == "":
+		b.WriteString("No branch yet; start one from the default branch.\n")
+	case p.Pushed:
+		fmt.Fprintf(&b, "%s (pushed to origin)\n  git fetch origin && git checkout %s\n", p.Branch, p.Branch)
+	default:
+		fmt.Fprintf(&b, "%s (not pushed; ask %s to push it)\n", p.Branch, p.From)
+	}
+	if p.Dirty {
+		b.WriteString("The previous clone had uncommitted changes; they were not handed off.\n")
+	}
+
+	if len(p.Steps) > 0 {
+		b.WriteString("\n## Remaining steps\n")
+		for _, step := range p.Steps {
+			fmt.Fprintf(&b, "- %s\n", step)
+		}
+	}
+
+	if p.Transcript != "" {
+		fmt.Fprintf(&b, "\n## Where it left off\n%s", p.Transcript)
+	}
+	return b.String()
+}
+
+// runHandoffTransfer reassigns a bead to another polecat or crew workspace,
+// packaging what the previous holder knew.
+func runHandoffTransfer(args []string) error {
+	if len(args) != 1 || !looksLikeBeadID(args[0]) {
+		return fmt.Errorf("--to needs the bead to hand off: gt handoff <bead> --to <agent>")
+	}
+	beadID := args[0]
+	target, err := parseHandoffTarget(handoffTo)
+	if err != nil {
+		return err
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	b := beads.New(beadLocation(townRoot, beadID))
+	issue, err := b.Show(beadID)
+	if err != nil {
+		return fmt.Errorf("bead %s not found: %w", beadID, err)
+	}
+	if issue.Status == "closed" {
+		return fmt.Errorf("bead %s is closed", beadID)
+	}
+	to := target.Address()
+	if issue.Assignee == to {
+		return fmt.Errorf("%s is already assigned to %s", beadID, to)
+	}
+	if err := checkHandoffTarget(target, beadID); err != nil {
+		return err
+	}
+
+	pkg := buildHandoffPackage(townRoot, b, issue, to)
+	if handoffDryRun {
+		fmt.Printf("Would assign %s to %s and mail them:\n\n%s", beadID, to, pkg.Body())
+		return nil
+	}
+	if pkg.Branch != "" {
+		pkg.Pushed = pushHandoffBranch(issue.Assignee, pkg.Branch)
+	}
+
+	hooked := beads.StatusHooked
+	if err := b.Update(beadID, beads.UpdateOptions{Status: &hooked, Assignee: &to}); err != nil {
+		return fmt.Errorf("reassigning %s: %w", beadID, err)
+	}
+	fmt.Printf("%s %s assigned to %s\n", style.Bold.Render("✓"), beadID, to)
+	if workDir := handoffWorkDir(target); workDir != "" {
+		updateAgentHookBead(to, beadID, workDir, "")
+	}
+
+	sender := detectSender()
+	subject := handoffSubject
+	if subject == "" {
+		subject = fmt.Sprintf("%s %s", beadID, issue.Title)
+	}
+	router := mail.NewRouter(townRoot)
+	msg := mail.NewMessage(sender, to, "🤝 HANDOFF: "+subject, pkg.Body())
+	msg.Type = mail.TypeTask
+	msg.Priority = mail.PriorityHigh
+	if err := router.Send(msg); err != nil {
+		return fmt.Errorf("%s reassigned but mailing %s failed: %w", beadID, to, err)
+	}
+	fmt.Printf("%s Handoff mailed to %s\n", style.Bold.Render("✓"), to)
+
+	if pkg.From != "" && pkg.From != sender {
+		notice := mail.NewMessage(sender, pkg.From, "Handed off: "+beadID,
+			fmt.Sprintf("%s (%s) was handed off to %s. It is no longer on your hook.", beadID, issue.Title, to))
+		if err := router.Send(notice); err != nil {
+			style.PrintWarning("couldn't notify %s: %v", pkg.From, err)
+		}
+	}
+
+	_ = LogHandoff(townRoot, sender, fmt.Sprintf("%s → %s", beadID, to))
+	_ = events.LogFeed(events.TypeHandoff, sender, events.HandoffPayload(subject, false))
+
+	nudgeHandoffTarget(target, beadID)
+	return nil
+}
+
+// checkHandoffTarget verifies the receiving worker exists.
+func checkHandoffTarget(target handoffTarget, beadID string) error {
+	_, r, err := getRig(target.Rig)
+	if err != nil {
+		return err
+	}
+	if target.Kind == "crew" {
+		if _, err := crew.NewManager(r, git.NewGit(r.Path)).Get(target.Name); err != nil {
+			return fmt.Errorf("crew workspace %s: %w", target.Address(), err)
+		}
+		return nil
+	}
+	if _, err := polecat.NewManager(r, git.NewGit(r.Path)).Get(target.Name); err != nil {
+		return fmt.Errorf("polecat %s: %w (use 'gt sling %s %s' for a fresh polecat)", target.Address(), err, beadID, target.Rig)
+	}
+	return nil
+}
+
+// buildHandoffPackage gathers the bead's branch, remaining molecule steps
+// and the tail of its transcript.
+func buildHandoffPackage(townRoot string, b *beads.Beads, issue *beads.Issue, to string) *HandoffPackage {
+	pkg := &HandoffPackage{
+		Bead:  issue.ID,
+		Title: issue.Title,
+		From:  issue.Assignee,
+		To:    to,
+		Notes: handoffMessage,
+	}
+
+	if from, err := parseHandoffTarget(issue.Assignee); err == nil {
+		if dir := handoffWorkDir(from); dir != "" {
+			g := git.NewGit(dir)
+			if branch, err := g.CurrentBranch(); err == nil && branch != "HEAD" {
+				if _, r, err := getRig(from.Rig); err != nil || branch != r.DefaultBranch() {
+					pkg.Branch = branch
+				}
+			}
+			pkg.Dirty, _ = g.HasUncommittedChanges()
+		}
+	}
+
+	molID := issue.ID
+	if attachment := beads.ParseAttachmentFields(issue); attachment != nil && attachment.AttachedMolecule != "" {
+		molID = attachment.AttachedMolecule
+	}
+	if steps, err := b.List(beads.ListOptions{Parent: molID, Status: "all", Priority: -1}); err == nil {
+		for _, step := range steps {
+			if step.Status != "closed" {
+				pkg.Steps = append(pkg.Steps, fmt.Sprintf("%s %s (%s)", step.ID, step.Title, step.Status))
+			}
+		}
+	}
+
+	if entry, err := transcript.Find(townRoot, issue.ID); err == nil {
+		if data, err := os.ReadFile(entry.Path); err == nil {
+			pkg.Transcript = transcript.Summary(string(data), handoffTranscriptLines)
+		}
+	}
+	return pkg
+}
+
+// handoffWorkDir returns a worker's clone, or "" if it can't be found.
+func handoffWorkDir(t handoffTarget) string {
+	_, r, err := getRig(t.Rig)
+	if err != nil {
+		return ""
+	}
+	if t.Kind == "crew" {
+		if w, err := crew.NewManager(r, git.NewGit(r.Path)).Get(t.Name); err == nil {
+			return w.ClonePath
+		}
+		return ""
+	}
+	if p, err := polecat.NewManager(r, git.NewGit(r.Path)).Get(t.Name); err == nil {
+		return p.ClonePath
+	}
+	return ""
+}
+
+// pushHandoffBranch pushes the previous holder's branch so a receiver in
+// another clone can fetch it. Failure is reported, not fatal.
+func pushHandoffBranch(holder, branch string) bool {
+	from, err := parseHandoffTarget(holder)
+	if err != nil {
+		return false
+	}
+	dir := handoffWorkDir(from)
+	if dir == "" {
+		return false
+	}
+	if err := git.NewGit(dir).Push("origin", branch, false); err != nil {
+		style.PrintWarning("couldn't push %s: %v", branch, err)
+		return false
+	}
+	fmt.Printf("%s Pushed %s\n", style.Bold.Render("✓"), branch)
+	return true
+}
+
+// nudgeHandoffTarget tells a running receiver to check its hook.
+func nudgeHandoffTarget(target handoffTarget, beadID string) {
+	name := session.PolecatSessionName(target.Rig, target.Name)
+	if target.Kind == "crew" {
+		name = session.CrewSessionName(target.Rig, target.Name)
+	}
+	t := tmux.NewTmux()
+	if running, _ := t.HasSession(name); !running {
+		fmt.Printf("  %s isn't running; the handoff waits on its hook and in its inbox\n", target.Address())
+		return
+	}
+	if err := t.NudgeSession(name, fmt.Sprintf("%s was handed off to you. Read the handoff mail (gt mail inbox), then gt hook.", beadID)); err != nil {
+		style.PrintWarning("couldn't nudge %s: %v", name, err)
+	}
+}
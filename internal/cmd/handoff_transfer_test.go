@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHandoffTarget(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"greenplace/Nux", "greenplace/polecats/Nux", false},
+		{"greenplace/polecats/Nux", "greenplace/polecats/Nux", false},
+		{"greenplace/crew/max", "greenplace/crew/max", false},
+		{"greenplace/crew/max/", "greenplace/crew/max", false},
+		{"greenplace", "", true},
+		{"greenplace/witness/x", "", true},
+		{"greenplace/crew/", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseHandoffTarget(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseHandoffTarget(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.Address() != tt.want {
+			t.Errorf("parseHandoffTarget(%q) = %s, want %s", tt.in, got.Address(), tt.want)
+		}
+	}
+}
+
+func TestHandoffPackageBody(t *testing.T) {
+	pkg := &HandoffPackage{
+		Bead:       "gt-abc",
+		Title:      "Fix login",
+		From:       "greenplace/polecats/Toast",
+		To:         "greenplace/crew/max",
+		Branch:     "polecat/Toast/gt-abc",
+		Pushed:     true,
+		Steps:      []string{"gt-abc.3 Write tests (open)"},
+		Transcript: "in progress: gt-abc.2: Implement\n",
+		Notes:      "Tests are flaky on CI",
+	}
+	body := pkg.Body()
+	for _, want := range []string{
+		"handed off by greenplace/polecats/Toast",
+		"Tests are flaky on CI",
+		"git checkout polecat/Toast/gt-abc",
+		"- gt-abc.3 Write tests (open)",
+		"in progress: gt-abc.2",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	pkg.Pushed = false
+	if body := pkg.Body(); !strings.Contains(body, "not pushed") {
+		t.Errorf("unpushed branch not flagged:\n%s", body)
+	}
+}
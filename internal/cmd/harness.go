@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var harnessListJSON bool
+
+var harnessCmd = &cobra.Command{
+	Use:     "harness",
+	GroupID: GroupWorkspace,
+	Short:   "Switch between registered harnesses (towns)",
+	RunE:    requireSubcommand,
+	Long: `Register several harnesses on one machine and switch between them.
+
+gt finds the town from the current directory as usual. Outside any town it
+uses the current harness, so 'gt status' or 'gt mail inbox' from your home
+directory goes to the town you last selected with 'gt harness use'. The
+global --harness flag runs a single command in a named harness, from its
+town root, wherever you are.
+
+'gt install' registers the towns it creates (named after the town).
+
+Examples:
+  gt harness add work ~/gt
+  gt harness add personal ~/gt-personal
+  gt harness use personal
+  gt harness list
+  gt --harness work status`,
+}
+
+var harnessListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered harnesses",
+	Args:  cobra.NoArgs,
+	RunE:  runHarnessList,
+}
+
+var harnessUseCmd = &cobra.Command{
+	Use:   "use <harness>",
+	Short: "Make a harness current",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHarnessUse,
+}
+
+var harnessAddCmd = &cobra.Command{
+	Use:   "add <name> [path]",
+	Short: "Register a town as a harness",
+	Long: `Register a town as a harness. The path defaults to the town the current
+directory is in. Re-adding a name points it at the new path.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runHarnessAdd,
+}
+
+var harnessRemoveCmd = &cobra.Command{
+	Use:   "remove <harness>",
+	Short: "Unregister a harness (the town is left alone)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHarnessRemove,
+}
+
+func init() {
+	harnessListCmd.Flags().BoolVar(&harnessListJSON, "json", false, "Output as JSON")
+
+	harnessCmd.AddCommand(harnessListCmd)
+	harnessCmd.AddCommand(harnessUseCmd)
+	harnessCmd.AddCommand(harnessAddCmd)
+	harnessCmd.AddCommand(harnessRemoveCmd)
+	rootCmd.AddCommand(harnessCmd)
+}
+
+// enterHarness moves into the harness named by --harness, so the command
+// runs as if started from its town root.
+func enterHarness() error {
+	if harnessFlag == "" {
+		return nil
+	}
+	townRoot, err := workspace.ResolveHarness(harnessFlag)
+	if err != nil {
+		return fmt.Errorf("--harness: %w", err)
+	}
+	if err := os.Chdir(townRoot); err != nil {
+		return fmt.Errorf("--harness: %w", err)
+	}
+	return nil
+}
+
+func runHarnessList(cmd *cobra.Command, args []string) error {
+	h, err := workspace.LoadHarnesses()
+	if err != nil {
+		return err
+	}
+	if harnessListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(h)
+	}
+	if len(h.Harnesses) == 0 {
+		fmt.Println("No harnesses registered. Add one with: gt harness add <name> <path>")
+		return nil
+	}
+	for _, harness := range h.Harnesses {
+		marker := " "
+		if harness.Name == h.Current {
+			marker = style.Bold.Render("*")
+		}
+		note := ""
+		if ok, _ := workspace.IsWorkspace(harness.Path); !ok {
+			note = " " + style.Warning.Render("(missing)")
+		}
+		fmt.Printf("%s %-16s %s%s\n", marker, harness.Name, style.Dim.Render(harness.Path), note)
+	}
+	return nil
+}
+
+func runHarnessUse(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	h, err := workspace.LoadHarnesses()
+	if err != nil {
+		return err
+	}
+	if h.Get(name) == nil {
+		return fmt.Errorf("unknown harness %q (see 'gt harness list')", name)
+	}
+	h.Current = name
+	if err := workspace.SaveHarnesses(h); err != nil {
+		return err
+	}
+	fmt.Printf("%s Current harness: %s\n", style.Bold.Render("✓"), name)
+	return nil
+}
+
+func runHarnessAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	var townRoot string
+	if len(args) > 1 {
+		absPath, err := filepath.Abs(args[1])
+		if err != nil {
+			return err
+		}
+		if ok, _ := workspace.IsWorkspace(absPath); !ok {
+			return fmt.Errorf("%s is not a Gas Town workspace", absPath)
+		}
+		townRoot = absPath
+	} else {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if townRoot, err = workspace.FindOrError(cwd); err != nil {
+			return fmt.Errorf("%w (give the town's path)", err)
+		}
+	}
+
+	h, err := workspace.LoadHarnesses()
+	if err != nil {
+		return err
+	}
+	h.Add(name, townRoot)
+	if err := workspace.SaveHarnesses(h); err != nil {
+		return err
+	}
+	fmt.Printf("%s Registered harness %s → %s\n", style.Bold.Render("✓"), name, townRoot)
+	if h.Current == name {
+		fmt.Printf("  Current harness: %s\n", name)
+	}
+	return nil
+}
+
+func runHarnessRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	h, err := workspace.LoadHarnesses()
+	if err != nil {
+		return err
+	}
+	if !h.Remove(name) {
+		return fmt.Errorf("unknown harness %q (see 'gt harness list')", name)
+	}
+	if err := workspace.SaveHarnesses(h); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed harness %s\n", style.Bold.Render("✓"), name)
+	return nil
+}
+
+// registerInstalledHarness registers a newly installed town as a harness
+// named after it, unless it is already registered or the name is taken.
+func registerInstalledHarness(name, townRoot string) {
+	h, err := workspace.LoadHarnesses()
+	if err != nil {
+		fmt.Printf("   %s Could not register harness: %v\n", style.Dim.Render("⚠"), err)
+		return
+	}
+	if h.ByPath(townRoot) != nil {
+		return
+	}
+	if existing := h.Get(name); existing != nil {
+		fmt.Printf("   %s Harness name %s is taken by %s; register with: gt harness add <name> %s\n",
+			style.Dim.Render("⚠"), name, existing.Path, townRoot)
+		return
+	}
+	h.Add(name, townRoot)
+	if err := workspace.SaveHarnesses(h); err != nil {
+		fmt.Printf("   %s Could not register harness: %v\n", style.Dim.Render("⚠"), err)
+		return
+	}
+	_ = state.RecordInstall(state.KindHarness, townRoot, name)
+	fmt.Printf("   ✓ Registered harness %s\n", name)
+}
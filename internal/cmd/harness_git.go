@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/harnessgit"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var harnessCommitMessage string
+
+var harnessCommitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Commit town metadata to the harness git repo",
+	Long: `Commit the town's metadata to the git repository at the town root
+(created with 'gt git-init').
+
+Only harness metadata is staged: CLAUDE.md, .claude/, mayor/ (town.json,
+rigs.json), settings/, deacon/, plugins/, prompts/, the town and rig
+.beads (mail, convoys, molecule runs), and each rig's config.json and
+settings. Rig clones, polecat worktrees and runtime state are never
+committed, and anything else you have staged is left out of the commit.
+The .gitignore is brought up to date first.
+
+To commit automatically, set harness_git in settings/config.json:
+
+  "harness_git": { "auto_commit": "command" }    # after every gt command
+  "harness_git": { "auto_commit": "schedule", "push": true }
+                                                 # harness-commit chore (hourly)
+
+Examples:
+  gt harness commit
+  gt harness commit -m "Add greenplace rig"`,
+	Args: cobra.NoArgs,
+	RunE: runHarnessCommit,
+}
+
+var harnessPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Commit town metadata and push it",
+	Long: `Commit any town metadata changes, then push the town root's branch.
+A branch with no upstream is pushed to origin and set to track it.`,
+	Args: cobra.NoArgs,
+	RunE: runHarnessPush,
+}
+
+var harnessPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Pull town metadata from the remote",
+	Long: `Commit any local town metadata changes, then pull the town root's
+branch with rebase, so another machine's harness changes land on top of
+local ones.`,
+	Args: cobra.NoArgs,
+	RunE: runHarnessPull,
+}
+
+func init() {
+	harnessCommitCmd.Flags().StringVarP(&harnessCommitMessage, "message", "m", "", "Commit message")
+
+	harnessCmd.AddCommand(harnessCommitCmd)
+	harnessCmd.AddCommand(harnessPushCmd)
+	harnessCmd.AddCommand(harnessPullCmd)
+}
+
+// findHarnessRepo returns the town root, which must be a git repository.
+func findHarnessRepo() (string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if !harnessgit.IsRepo(townRoot) {
+		return "", fmt.Errorf("town root is not a git repository (run 'gt git-init' first)")
+	}
+	return townRoot, nil
+}
+
+// commitHarness updates the town .gitignore and commits any metadata
+// changes, returning the files committed.
+func commitHarness(townRoot, message string) ([]string, error) {
+	rigs := discoverRigs(townRoot)
+	if _, err := harnessgit.EnsureGitignore(townRoot, HQGitignore, rigs); err != nil {
+		return nil, fmt.Errorf("updating .gitignore: %w", err)
+	}
+	return harnessgit.Commit(townRoot, rigs, message)
+}
+
+func runHarnessCommit(cmd *cobra.Command, args []string) error {
+	townRoot, err := findHarnessRepo()
+	if err != nil {
+		return err
+	}
+	message := harnessCommitMessage
+	if message == "" {
+		message = "harness: update town metadata"
+	}
+	files, err := commitHarness(townRoot, message)
+	if err != nil {
+		return err
+	}
+	printHarnessCommit(files)
+	return nil
+}
+
+func printHarnessCommit(files []string) {
+	if len(files) == 0 {
+		fmt.Printf("%s Town metadata is up to date\n", style.Dim.Render("○"))
+		return
+	}
+	fmt.Printf("%s Committed %d file(s)\n", style.Bold.Render("✓"), len(files))
+	for _, f := range files {
+		fmt.Printf("  %s\n", style.Dim.Render(f))
+	}
+}
+
+func runHarnessPush(cmd *cobra.Command, args []string) error {
+	townRoot, err := findHarnessRepo()
+	if err != nil {
+		return err
+	}
+	files, err := commitHarness(townRoot, "harness: update town metadata")
+	if err != nil {
+		return err
+	}
+	printHarnessCommit(files)
+	if err := pushHarness(townRoot, os.Stdout); err != nil {
+		return err
+	}
+	fmt.Printf("%s Pushed town metadata\n", style.Bold.Render("✓"))
+	return nil
+}
+
+// pushHarness pushes the town root's branch, setting an upstream on
+// origin the first time.
+func pushHarness(townRoot string, out io.Writer) error {
+	args := []string{"push"}
+	if !harnessgit.HasUpstream(townRoot) {
+		args = append(args, "-u", "origin", "HEAD")
+	}
+	return runHarnessGit(townRoot, out, args...)
+}
+
+func runHarnessPull(cmd *cobra.Command, args []string) error {
+	townRoot, err := findHarnessRepo()
+	if err != nil {
+		return err
+	}
+	if !harnessgit.HasUpstream(townRoot) {
+		return fmt.Errorf("town branch has no upstream (run 'gt harness push' first)")
+	}
+	files, err := commitHarness(townRoot, "harness: update town metadata")
+	if err != nil {
+		return err
+	}
+	printHarnessCommit(files)
+	if err := runHarnessGit(townRoot, os.Stdout, "pull", "--rebase", "--autostash"); err != nil {
+		return err
+	}
+	fmt.Printf("%s Pulled town metadata\n", style.Bold.Render("✓"))
+	return nil
+}
+
+func runHarnessGit(townRoot string, out io.Writer, args ...string) error {
+	gitCmd := exec.Command("git", args...)
+	gitCmd.Dir = townRoot
+	gitCmd.Stdout = out
+	gitCmd.Stderr = os.Stderr
+	if err := gitCmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+// harnessAutoCommitExempt are commands that don't auto-commit afterwards:
+// they commit themselves, or run for the life of a process.
+var harnessAutoCommitExempt = map[string]bool{
+	"commit":       true,
+	"push":         true,
+	"pull":         true,
+	"git-init":     true,
+	"install":      true,
+	"uninstall":    true,
+	"session-host": true,
+	"heartbeat":    true,
+	"help":         true,
+	"completion":   true,
+	"version":      true,
+}
+
+// autoCommitHarness commits town metadata after a command when the town
+// asks for it (harness_git.auto_commit = "command"). Best-effort and
+// silent: another gt holding the git index just means the next command
+// commits the change.
+func autoCommitHarness(cmd *cobra.Command) {
+	if cmd == nil || harnessAutoCommitExempt[cmd.Name()] || dryrun.Enabled() {
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" || !harnessgit.IsRepo(townRoot) {
+		return
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.HarnessGit == nil || settings.HarnessGit.AutoCommit != config.HarnessCommitCommand {
+		return
+	}
+	_, _ = commitHarness(townRoot, "harness: after "+strings.TrimSpace(cmd.CommandPath()))
+}
+
+func choreHarnessCommit(townRoot string) (string, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return "", fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.HarnessGit == nil || settings.HarnessGit.AutoCommit != config.HarnessCommitSchedule {
+		return "skipped (harness_git.auto_commit is not \"schedule\")", nil
+	}
+	if !harnessgit.IsRepo(townRoot) {
+		return "skipped (town root is not a git repository)", nil
+	}
+	files, err := commitHarness(townRoot, "harness: scheduled commit")
+	if err != nil {
+		return "", err
+	}
+	summary := fmt.Sprintf("committed %d file(s)", len(files))
+	if settings.HarnessGit.Push && harnessgit.HasUpstream(townRoot) {
+		if err := runHarnessGit(townRoot, io.Discard, "push"); err != nil {
+			return summary, err
+		}
+		summary += ", pushed"
+	}
+	return summary, nil
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/heartbeat"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	heartbeatEvery time.Duration
+	heartbeatWatch string
+)
+
+var heartbeatCmd = &cobra.Command{
+	Use:     "heartbeat",
+	GroupID: GroupAgents,
+	Short:   "Tell the witness this agent is active",
+	Long: `Record a heartbeat for the current polecat or crew worker: the bead it is
+working and the time, in <town>/.runtime/heartbeats/<rig>/<name>.json.
+
+Agents don't run this themselves; their wrapper does, while the agent is
+doing something:
+
+  - Claude sessions beat from a PostToolUse hook, so each tool call counts.
+    Beats closer together than 30s are skipped.
+  - Other runtimes run 'gt heartbeat --every 30s' alongside the agent. It
+    beats each interval in which the watched file (the session transcript,
+    $GT_TRANSCRIPT, by default) grew, and exits with its parent process.
+
+A working polecat whose beats stop is hung: see 'gt witness heartbeats'.
+Outside a polecat or crew worktree this does nothing.
+
+Examples:
+  gt heartbeat
+  gt heartbeat --every 30s &
+  gt heartbeat --every 1m --watch /tmp/agent.log &`,
+	Args: cobra.NoArgs,
+	RunE: runHeartbeat,
+}
+
+func init() {
+	heartbeatCmd.Flags().DurationVar(&heartbeatEvery, "every", 0, "Keep running, beating each interval the agent's output grew")
+	heartbeatCmd.Flags().StringVar(&heartbeatWatch, "watch", "", "File whose growth counts as activity, with --every (default: $GT_TRANSCRIPT)")
+	rootCmd.AddCommand(heartbeatCmd)
+}
+
+func runHeartbeat(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil // Hooks run everywhere; only rig workers beat
+	}
+	info, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil || (info.Role != RolePolecat && info.Role != RoleCrew) || info.Rig == "" || info.Polecat == "" {
+		return nil
+	}
+
+	if heartbeatEvery <= 0 {
+		// From a hook: never fail the agent's tool call over a heartbeat
+		_ = beat(townRoot, cwd, info, heartbeat.SourceHook, heartbeat.DefaultInterval)
+		return nil
+	}
+
+	watch := heartbeatWatch
+	if watch == "" {
+		watch = os.Getenv(transcript.EnvVar)
+	}
+	if watch == "" {
+		return fmt.Errorf("nothing to watch for activity: pass --watch or set %s", transcript.EnvVar)
+	}
+	return runHeartbeatWrapper(townRoot, cwd, info, watch)
+}
+
+// runHeartbeatWrapper beats each interval in which watch changed, until
+// the process that started it exits.
+func runHeartbeatWrapper(townRoot, cwd string, info RoleInfo, watch string) error {
+	parent := os.Getppid()
+	var lastSize int64 = -1
+	var lastMod time.Time
+	ticker := time.NewTicker(heartbeatEvery)
+	defer ticker.Stop()
+	for {
+		if fi, err := os.Stat(watch); err == nil {
+			if fi.Size() != lastSize || !fi.ModTime().Equal(lastMod) {
+				if lastSize >= 0 {
+					if err := beat(townRoot, cwd, info, heartbeat.SourceWrapper, heartbeatEvery); err != nil {
+						fmt.Fprintf(os.Stderr, "heartbeat: %v\n", err)
+					}
+				}
+				lastSize, lastMod = fi.Size(), fi.ModTime()
+			}
+		}
+		<-ticker.C
+		if !util.ProcessAlive(parent) {
+			return nil
+		}
+	}
+}
+
+// beat records a heartbeat for a rig worker, unless it beat within
+// interval.
+func beat(townRoot, cwd string, info RoleInfo, source string, interval time.Duration) error {
+	now := time.Now()
+	prev, _ := heartbeat.Read(townRoot, info.Rig, info.Polecat)
+	if !heartbeat.Due(prev, now, interval) {
+		return nil
+	}
+	return heartbeat.Write(townRoot, &heartbeat.Heartbeat{
+		Rig:    info.Rig,
+		Agent:  info.Polecat,
+		Step:   heartbeatStep(cwd, info),
+		Source: source,
+		Beat:   now,
+	}, prev)
+}
+
+// heartbeatStep is the bead a worker is on: the one on its hook, or the
+// molecule step gt mol step done pinned for it.
+func heartbeatStep(cwd string, info RoleInfo) string {
+	if hooked := detectHookedBead(cwd, info); hooked != "" {
+		return hooked
+	}
+	assignee := getAgentIdentity(RoleContext{Role: info.Role, Rig: info.Rig, Polecat: info.Polecat})
+	if assignee == "" {
+		return ""
+	}
+	pinned, err := beads.New(cwd).List(beads.ListOptions{Status: beads.StatusPinned, Assignee: assignee, Priority: -1})
+	if err != nil || len(pinned) == 0 {
+		return ""
+	}
+	return pinned[0].ID
+}
@@ -8,13 +8,13 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
 	hooksJSON    bool
-	hooksVerbose bool
 )
 
 var hooksCmd = &cobra.Command{
@@ -43,7 +43,6 @@ Examples:
 func init() {
 	rootCmd.AddCommand(hooksCmd)
 	hooksCmd.Flags().BoolVar(&hooksJSON, "json", false, "Output as JSON")
-	hooksCmd.Flags().BoolVarP(&hooksVerbose, "verbose", "v", false, "Show hook commands")
 }
 
 // ClaudeSettings represents the Claude Code settings.json structure.
@@ -300,7 +299,7 @@ func outputHooksHuman(townRoot string, hooks []HookInfo) error {
 
 			fmt.Printf("  %s %-25s%s\n", statusIcon, h.Agent, style.Dim.Render(matcherStr))
 
-			if hooksVerbose {
+			if output.IsVerbose() {
 				for _, cmd := range h.Commands {
 					fmt.Printf("    %s %s\n", style.Dim.Render("→"), cmd)
 				}
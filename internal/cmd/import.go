@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/github"
+	"github.com/steveyegge/gastown/internal/jira"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	importRepo   string
+	importLabels []string
+	importRig    string
+	importCSV    string
+	importJQL    string
+	importURL    string
+	importJSON   bool
+)
+
+var importCmd = &cobra.Command{
+	Use:     "import",
+	GroupID: GroupWork,
+	Short:   "Import an existing GitHub or Jira backlog into beads",
+	Long: `Import an existing backlog into a rig's beads, once, so the town can start
+on work that's already been filed.
+
+Imported beads keep a back-link to their source: an external ref
+(gh-owner/name#42, jira-PROJ-123) and a link in the description. Running
+an import again skips what's already there, so it's safe to repeat as the
+backlog grows. Priorities and types are mapped from the source; labels are
+copied as gh-label:<name> or jira-label:<name>.
+
+For ongoing mirroring of a GitHub repo, see 'gt github sync'.
+
+COMMANDS:
+  github    Import open GitHub issues
+  jira      Import Jira issues from a CSV export or a JQL search`,
+	RunE: requireSubcommand,
+}
+
+var importGitHubCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Import a repository's open GitHub issues",
+	Long: `Import a repository's open issues (via the gh CLI) into beads.
+
+Issues go to the rig whose git URL is the repo, or to --rig. --label
+limits the import to issues carrying every given label, so a team can
+mark what agents may take.
+
+P0-P4 and priority:<level> labels set the bead priority, and a "bug" label
+makes it a bug.
+
+Examples:
+  gt import github --repo acme/api --label agent-ok
+  gt import github --repo acme/legacy --rig api --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runImportGitHub,
+}
+
+var importJiraCmd = &cobra.Command{
+	Use:   "jira",
+	Short: "Import Jira issues into a rig's beads",
+	Long: `Import Jira issues from a CSV export (Issues → Export → CSV, all fields)
+or from a JQL search through the REST API.
+
+The API needs the site URL (--url or JIRA_URL) and credentials:
+JIRA_EMAIL with JIRA_API_TOKEN for Jira Cloud, or JIRA_API_TOKEN alone as
+a personal access token for Jira Server/Data Center. --url also turns CSV
+keys into browse links.
+
+Resolved issues are skipped. Priorities map Highest/Blocker/Critical to
+P0, High/Major to P1, Medium to P2, Low/Minor to P3 and Lowest/Trivial to
+P4; Bugs become bugs and Stories and Improvements become features.
+
+Examples:
+  gt import jira --csv backlog.csv --rig api
+  gt import jira --csv backlog.csv --rig api --label agent-ok
+  gt import jira --jql 'project = API AND labels = agent-ok' --rig api --url https://acme.atlassian.net`,
+	Args: cobra.NoArgs,
+	RunE: runImportJira,
+}
+
+func init() {
+	importGitHubCmd.Flags().StringVar(&importRepo, "repo", "", "GitHub repository (owner/name)")
+	importGitHubCmd.Flags().StringArrayVar(&importLabels, "label", nil, "Only import issues with this label (repeatable)")
+	importGitHubCmd.Flags().StringVar(&importRig, "rig", "", "Rig to import into (default: the rig whose git URL is the repo)")
+	importGitHubCmd.Flags().BoolVar(&importJSON, "json", false, "Output results as JSON")
+	_ = importGitHubCmd.MarkFlagRequired("repo")
+
+	importJiraCmd.Flags().StringVar(&importCSV, "csv", "", "Jira CSV export to import")
+	importJiraCmd.Flags().StringVar(&importJQL, "jql", "", "JQL query to import through the REST API")
+	importJiraCmd.Flags().StringVar(&importURL, "url", os.Getenv("JIRA_URL"), "Jira site URL (e.g. https://acme.atlassian.net)")
+	importJiraCmd.Flags().StringArrayVar(&importLabels, "label", nil, "Only import issues with this label (repeatable)")
+	importJiraCmd.Flags().StringVar(&importRig, "rig", "", "Rig to import into")
+	importJiraCmd.Flags().BoolVar(&importJSON, "json", false, "Output results as JSON")
+	_ = importJiraCmd.MarkFlagRequired("rig")
+
+	importCmd.AddCommand(importGitHubCmd)
+	importCmd.AddCommand(importJiraCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImportGitHub(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	ingester := github.NewIngester(townRoot, rigsConfig)
+	if importRig != "" {
+		if _, ok := rigsConfig.Rigs[importRig]; !ok {
+			return fmt.Errorf("rig '%s' not found", importRig)
+		}
+		ingester.Track(importRepo, importRig)
+	}
+	rig, ok := ingester.RigFor(importRepo)
+	if !ok {
+		return fmt.Errorf("no rig tracks GitHub repo %s (use --rig)", importRepo)
+	}
+
+	items, err := github.ListIssues(importRepo, importLabels)
+	if err != nil {
+		return err
+	}
+
+	if dryrun.Enabled() {
+		for _, item := range items {
+			dryrun.Record("import %s → %s: %s", item.ExternalRef(), rig, item.Title)
+		}
+		return nil
+	}
+
+	results := []*github.Result{}
+	for _, item := range items {
+		result, err := ingester.Ingest(item)
+		if err != nil {
+			style.PrintWarning("%s: %v", item.ExternalRef(), err)
+			continue
+		}
+		logIngest(item, result)
+		results = append(results, result)
+	}
+
+	if importJSON {
+		return outputJSON(results)
+	}
+	created, existing := 0, 0
+	for _, r := range results {
+		switch r.Action {
+		case github.ActionCreated:
+			created++
+			fmt.Printf("  created %s → %s/%s\n", r.Ref, r.Rig, r.BeadID)
+		case github.ActionExists:
+			existing++
+		}
+	}
+	fmt.Printf("%s Imported %s into %s: %d created, %d already imported\n",
+		style.Bold.Render("✓"), importRepo, rig, created, existing)
+	return nil
+}
+
+func runImportJira(cmd *cobra.Command, args []string) error {
+	if (importCSV == "") == (importJQL == "") {
+		return fmt.Errorf("give one of --csv or --jql")
+	}
+	_, r, err := getRig(importRig)
+	if err != nil {
+		return err
+	}
+
+	var issues []*jira.Issue
+	if importCSV != "" {
+		f, err := os.Open(importCSV)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", importCSV, err)
+		}
+		issues, err = jira.ParseCSV(f, importURL)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", importCSV, err)
+		}
+	} else {
+		if importURL == "" {
+			return fmt.Errorf("--jql needs the Jira site URL (--url or JIRA_URL)")
+		}
+		client := &jira.Client{BaseURL: importURL, Email: os.Getenv("JIRA_EMAIL"), Token: os.Getenv("JIRA_API_TOKEN")}
+		if client.Token == "" {
+			return fmt.Errorf("--jql needs JIRA_API_TOKEN (and JIRA_EMAIL for Jira Cloud)")
+		}
+		if issues, err = client.Search(importJQL); err != nil {
+			return err
+		}
+	}
+
+	var selected []*jira.Issue
+	for _, issue := range issues {
+		if issue.HasLabels(importLabels) {
+			selected = append(selected, issue)
+		}
+	}
+
+	if dryrun.Enabled() {
+		for _, issue := range selected {
+			if !issue.Resolved() {
+				dryrun.Record("import %s → %s: %s", issue.ExternalRef(), r.Name, issue.Summary)
+			}
+		}
+		return nil
+	}
+
+	results, err := jira.Import(beads.New(r.BeadsPath()), selected)
+	for _, res := range results {
+		if res.Action == jira.ActionCreated {
+			_ = events.LogFeed(events.TypeIngest, "jira",
+				events.IngestPayload("jira", r.Name, res.BeadID, res.Ref, res.Title))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if importJSON {
+		return outputJSON(results)
+	}
+	counts := map[string]int{}
+	for _, res := range results {
+		counts[res.Action]++
+		if res.Action == jira.ActionCreated {
+			fmt.Printf("  created %s → %s/%s\n", res.Ref, r.Name, res.BeadID)
+		}
+	}
+	summary := []string{
+		fmt.Sprintf("%d created", counts[jira.ActionCreated]),
+		fmt.Sprintf("%d already imported", counts[jira.ActionExists]),
+	}
+	if counts[jira.ActionSkipped] > 0 {
+		summary = append(summary, fmt.Sprintf("%d resolved, skipped", counts[jira.ActionSkipped]))
+	}
+	fmt.Printf("%s Imported %d Jira issue(s) into %s: %s\n",
+		style.Bold.Render("✓"), len(results), r.Name, strings.Join(summary, ", "))
+	return nil
+}
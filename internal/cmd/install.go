@@ -16,10 +16,12 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/deps"
 	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/shell"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"github.com/steveyegge/gastown/internal/wrappers"
 )
@@ -35,6 +37,7 @@ var (
 	installPublic     bool
 	installShell      bool
 	installWrappers   bool
+	installUninstall  bool
 )
 
 var installCmd = &cobra.Command{
@@ -61,7 +64,9 @@ Examples:
   gt install ~/gt --git                        # Also init git with .gitignore
   gt install ~/gt --github=user/repo           # Create private GitHub repo (default)
   gt install ~/gt --github=user/repo --public  # Create public GitHub repo
-  gt install ~/gt --shell                      # Install shell integration (sets GT_TOWN_ROOT/GT_RIG)`,
+  gt install ~/gt --shell                      # Install shell integration (sets GT_TOWN_ROOT/GT_RIG)
+  gt install --uninstall                       # Undo what gt install did (see gt uninstall)
+  gt install --uninstall ~/gt                  # ...and remove the town at ~/gt too`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInstall,
 }
@@ -77,10 +82,16 @@ func init() {
 	installCmd.Flags().BoolVar(&installPublic, "public", false, "Make GitHub repo public (use with --github)")
 	installCmd.Flags().BoolVar(&installShell, "shell", false, "Install shell integration (sets GT_TOWN_ROOT/GT_RIG env vars)")
 	installCmd.Flags().BoolVar(&installWrappers, "wrappers", false, "Install gt-codex/gt-opencode wrapper scripts to ~/bin/")
+	installCmd.Flags().BoolVar(&installUninstall, "uninstall", false, "Undo a previous install (same as gt uninstall; with a path, also removes that town)")
 	rootCmd.AddCommand(installCmd)
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
+	if installUninstall {
+		uninstallForce = installForce
+		return runUninstall(cmd, args)
+	}
+
 	// Determine target path
 	targetPath := "."
 	if len(args) > 0 {
@@ -88,13 +99,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 
 	// Expand ~ and resolve to absolute path
-	if targetPath[0] == '~' {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("getting home directory: %w", err)
-		}
-		targetPath = filepath.Join(home, targetPath[1:])
-	}
+	targetPath = util.ExpandHome(targetPath)
 
 	absPath, err := filepath.Abs(targetPath)
 	if err != nil {
@@ -139,6 +144,12 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	if err := os.MkdirAll(absPath, 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
+	// Re-running install (--force) rewrites settings and hooks in place
+	release, err := acquireStateLock(absPath, lock.ScopeTown)
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	// Create mayor directory (holds config, state, and mail)
 	mayorDir := filepath.Join(absPath, "mayor")
@@ -284,6 +295,11 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   ✓ Created .claude/commands/ (slash commands for all agents)\n")
 	}
 
+	if err := state.RecordInstall(state.KindTown, absPath, townName); err != nil {
+		fmt.Printf("   %s Could not record install manifest: %v\n", style.Dim.Render("⚠"), err)
+	}
+	registerInstalledHarness(townName, absPath)
+
 	if installShell {
 		fmt.Println()
 		if err := shell.Install(); err != nil {
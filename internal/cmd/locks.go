@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	locksJSON  bool
+	locksClean bool
+)
+
+var locksCmd = &cobra.Command{
+	Use:     "locks",
+	GroupID: GroupDiag,
+	Short:   "Show the state locks mutating commands hold",
+	Long: `Show the town's state locks and who holds them.
+
+Commands that change shared state take an advisory lock first, so a human,
+the Deacon and agents running gt at the same time can't interleave their
+writes:
+
+  town        gt install, gt rig add, gt rig remove, gt prefix rename,
+              gt doctor --fix, the Deacon's settings install at startup
+  rig-<name>  gt rig add and remove and gt prefix rename for that rig,
+              refinery merges and merge trains
+
+A command that finds a lock busy waits up to two minutes for it. Locks are
+released when their holder exits, however it exits; a holder that crashed
+leaves a stale record behind, which --clean removes.
+
+Examples:
+  gt locks
+  gt locks --clean
+  gt locks --json`,
+	Args: cobra.NoArgs,
+	RunE: runLocks,
+}
+
+func init() {
+	locksCmd.Flags().BoolVar(&locksJSON, "json", false, "Output as JSON")
+	locksCmd.Flags().BoolVar(&locksClean, "clean", false, "Remove stale lock records")
+	rootCmd.AddCommand(locksCmd)
+}
+
+func runLocks(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if locksClean {
+		n, err := lock.CleanStaleState(townRoot)
+		if err != nil {
+			return fmt.Errorf("cleaning stale locks: %w", err)
+		}
+		if !locksJSON {
+			fmt.Printf("%s Removed %d stale lock record(s)\n", style.Bold.Render("✓"), n)
+		}
+	}
+
+	locks, err := lock.ListState(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading locks: %w", err)
+	}
+	if locksJSON {
+		if locks == nil {
+			locks = []lock.StateStatus{}
+		}
+		return outputJSON(locks)
+	}
+	if len(locks) == 0 {
+		if !locksClean {
+			fmt.Println(style.Dim.Render("No state locks held"))
+		}
+		return nil
+	}
+	for _, l := range locks {
+		icon, state := style.Warning.Render("●"), "held"
+		if l.Stale {
+			icon, state = style.Dim.Render("○"), "stale"
+		}
+		detail := "holder unknown"
+		if h := l.Holder; h != nil {
+			detail = fmt.Sprintf("PID %d, %s, %s", h.PID, h.Command, formatAge(h.Acquired))
+		}
+		fmt.Printf("  %s %-20s %-6s %s\n", icon, l.Scope, state, style.Dim.Render(detail))
+	}
+	return nil
+}
+
+// acquireStateLock takes a state lock for a mutating command, saying so
+// when it has to wait. Callers defer the returned release.
+func acquireStateLock(townRoot, scope string) (release func(), err error) {
+	l, err := lock.AcquireState(townRoot, scope, 0)
+	if errors.Is(err, lock.ErrBusy) {
+		holder := scope
+		if h := lock.ReadHolder(townRoot, scope); h != nil {
+			holder = h.String()
+		}
+		fmt.Fprintf(os.Stderr, "%s Waiting for lock: %s\n", style.Dim.Render("○"), holder)
+		l, err = lock.AcquireState(townRoot, scope, lock.DefaultStateWait)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w (see 'gt locks')", err)
+	}
+	return func() { _ = l.Release() }, nil
+}
+
+// acquireRigStateLocks takes the town lock and then the rig's lock, for
+// commands that change the rig registry.
+func acquireRigStateLocks(townRoot, rigName string) (release func(), err error) {
+	releaseTown, err := acquireStateLock(townRoot, lock.ScopeTown)
+	if err != nil {
+		return nil, err
+	}
+	releaseRig, err := acquireStateLock(townRoot, lock.RigScope(rigName))
+	if err != nil {
+		releaseTown()
+		return nil, err
+	}
+	return func() {
+		releaseRig()
+		releaseTown()
+	}, nil
+}
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -180,7 +182,7 @@ func followLog(logPath string) error {
 
 // printEvent prints a single event with styling.
 func printEvent(e townlog.Event) {
-	ts := e.Timestamp.Format("2006-01-02 15:04:05")
+	ts := format.DateTimeSeconds(e.Timestamp)
 
 	// Color-code event types
 	var typeStr string
@@ -308,9 +310,9 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 	if err != nil || townRoot == "" {
 		// Try to find town root from conventional location
 		// This is called from tmux hook which may not have proper cwd
-		home := os.Getenv("HOME")
-		defaultRoot := home + "/gt"
-		if _, statErr := os.Stat(defaultRoot + "/mayor"); statErr == nil {
+		home, _ := os.UserHomeDir()
+		defaultRoot := filepath.Join(home, "gt")
+		if _, statErr := os.Stat(filepath.Join(defaultRoot, "mayor")); statErr == nil {
 			townRoot = defaultRoot
 		}
 		if townRoot == "" {
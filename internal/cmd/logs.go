@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/archive"
+	"github.com/steveyegge/gastown/internal/format"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Logs command flags
+var (
+	logsStep   string
+	logsFollow bool
+	logsRaw    bool
+	logsTail   int
+)
+
+var logsCmd = &cobra.Command{
+	Use:     "logs [polecat|bead]",
+	GroupID: GroupDiag,
+	Short:   "View polecat session transcripts",
+	Long: `View the recorded transcript of a polecat session.
+
+Every polecat session's pane output (agent output, prompts, tool calls) is
+recorded to ~/gt/.logs/transcripts/<bead>.log, and kept after the polecat
+is nuked. With no argument, lists recorded transcripts. A transcript moved
+out by 'gt archive' is fetched back from the archive.
+
+The target is a bead ID or a polecat (<name>, <rig>/<name>); a polecat
+shows its most recent session. Molecule steps are marked in the transcript
+as they complete, so --step can show just one of them.
+
+Examples:
+  gt logs                          # List transcripts
+  gt logs gt-abc                   # Transcript for a bead
+  gt logs gastown/Toast            # Latest transcript for a polecat
+  gt logs gt-abc --step review     # Just the review step
+  gt logs Toast -f                 # Follow a running session
+  gt logs gt-abc -n 100 --raw      # Last 100 lines with terminal escapes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogs,
+}
+
+func init() {
+	logsCmd.Flags().StringVar(&logsStep, "step", "", "Show only steps whose ID or title contains this")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Follow the transcript as it grows")
+	logsCmd.Flags().BoolVar(&logsRaw, "raw", false, "Keep terminal escape sequences")
+	logsCmd.Flags().IntVarP(&logsTail, "tail", "n", 0, "Show only the last N lines (0 = all)")
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if len(args) == 0 {
+		return listTranscripts(townRoot)
+	}
+	if logsFollow && logsStep != "" {
+		return fmt.Errorf("--follow and --step cannot be combined")
+	}
+
+	entry, err := transcript.Find(townRoot, args[0])
+	if err != nil {
+		if errors.Is(err, transcript.ErrNotFound) {
+			return fmt.Errorf("%w (run 'gt logs' to list transcripts)", err)
+		}
+		return fmt.Errorf("reading transcript index: %w", err)
+	}
+
+	data, err := os.ReadFile(entry.Path)
+	archived := false
+	if os.IsNotExist(err) {
+		// Old transcripts may have been moved out by gt archive
+		if data, err = fetchArchived(townRoot, entry.Path); err == nil {
+			archived = true
+		} else if errors.Is(err, archive.ErrNotArchived) {
+			err = fmt.Errorf("%s is gone", entry.Path)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("reading transcript: %w", err)
+	}
+	content := string(data)
+	if !logsRaw {
+		content = transcript.Clean(content)
+	}
+	if logsStep != "" {
+		section, ok := transcript.Step(content, logsStep)
+		if !ok {
+			return fmt.Errorf("no step matching %q in %s", logsStep, entry.Path)
+		}
+		content = section
+	}
+	if logsTail > 0 {
+		lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+		if len(lines) > logsTail {
+			content = strings.Join(lines[len(lines)-logsTail:], "\n") + "\n"
+		}
+	}
+	fmt.Print(content)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		fmt.Println()
+	}
+
+	if logsFollow && !archived {
+		return followTranscript(entry.Path, int64(len(data)))
+	}
+	return nil
+}
+
+// listTranscripts prints recorded transcripts, newest first.
+func listTranscripts(townRoot string) error {
+	entries, err := transcript.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading transcript index: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Printf("%s No transcripts recorded yet\n", style.Dim.Render("○"))
+		return nil
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		bead := e.Bead
+		if bead == "" {
+			bead = "-"
+		}
+		where := e.Path
+		if _, err := os.Stat(e.Path); os.IsNotExist(err) {
+			where += " (archived)"
+		}
+		fmt.Printf("%s  %-14s %-24s %s\n",
+			style.Dim.Render(format.DateTimeSeconds(e.Started.Local())), bead, e.Agent(), style.Dim.Render(where))
+	}
+	return nil
+}
+
+// followTranscript prints lines appended to path after offset until
+// interrupted.
+func followTranscript(path string, offset int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening transcript: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(f)
+	var partial string
+	for {
+		chunk, err := reader.ReadString('\n')
+		partial += chunk
+		if err == io.EOF {
+			time.Sleep(500 * time.Millisecond)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		line := partial
+		partial = ""
+		if !logsRaw {
+			line = transcript.Clean(line)
+		}
+		fmt.Print(line)
+	}
+}
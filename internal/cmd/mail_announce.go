@@ -13,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -149,7 +150,7 @@ func readAnnounceChannel(townRoot string, cfg *config.MessagingConfig, channelNa
 			style.Dim.Render(msg.ID),
 			msg.From)
 		fmt.Printf("    %s\n",
-			style.Dim.Render(msg.Created.Format("2006-01-02 15:04")))
+			style.Dim.Render(format.DateTime(msg.Created)))
 		if msg.Description != "" {
 			// Show first line of description as preview
 			lines := strings.SplitN(msg.Description, "\n", 2)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -83,7 +84,7 @@ func runMailClaim(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Preview: %s\n", style.Dim.Render(preview))
 	}
 	fmt.Printf("  From: %s\n", oldest.From)
-	fmt.Printf("  Created: %s\n", oldest.Created.Format("2006-01-02 15:04"))
+	fmt.Printf("  Created: %s\n", format.DateTime(oldest.Created))
 
 	return nil
 }
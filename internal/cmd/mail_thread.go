@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 )
@@ -69,7 +70,7 @@ func runMailThread(cmd *cobra.Command, args []string) error {
 			style.Dim.Render(msg.ID),
 			msg.From, msg.To)
 		fmt.Printf("    %s\n",
-			style.Dim.Render(msg.Timestamp.Format("2006-01-02 15:04")))
+			style.Dim.Render(format.DateTime(msg.Timestamp)))
 
 		if msg.Body != "" {
 			fmt.Printf("    %s\n", msg.Body)
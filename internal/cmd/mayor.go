@@ -18,7 +18,8 @@ var mayorCmd = &cobra.Command{
 	Long: `Manage the Mayor tmux session.
 
 The Mayor is the global coordinator for Gas Town, running as a persistent
-tmux session. Use the subcommands to start, stop, attach, and check status.`,
+tmux session. Use the subcommands to start, stop, attach, and check status,
+or 'gt mayor chat' to attach with the Mayor briefed on the town.`,
 }
 
 var mayorAgentOverride string
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// mayorBriefFile is the chat briefing within .runtime.
+const mayorBriefFile = "mayor-brief.md"
+
+var (
+	mayorChatPrint    bool
+	mayorChatNoAttach bool
+)
+
+var mayorChatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Talk to the Mayor, briefed on the town's state",
+	Long: `Start (or reuse) the Mayor session, brief it, and attach.
+
+The briefing covers the town's status (rigs, polecats at work, agents that
+are down, merge queues), open escalations and the Mayor's unread mail. It is
+written to .runtime/mayor-brief.md, and the Mayor is asked to read it and
+open with what needs your attention, so the conversation starts where the
+town is instead of with pasted context.
+
+Examples:
+  gt mayor chat
+  gt mayor chat --print       # Show the briefing only
+  gt mayor chat --no-attach   # Brief the Mayor, attach later`,
+	Args: cobra.NoArgs,
+	RunE: runMayorChat,
+}
+
+func init() {
+	mayorChatCmd.Flags().StringVar(&mayorAgentOverride, "agent", "", "Agent alias to run the Mayor with if it isn't running (overrides town default)")
+	mayorChatCmd.Flags().BoolVar(&mayorChatPrint, "print", false, "Print the briefing without touching the Mayor session")
+	mayorChatCmd.Flags().BoolVar(&mayorChatNoAttach, "no-attach", false, "Brief the Mayor without attaching")
+	mayorCmd.AddCommand(mayorChatCmd)
+}
+
+// mayorBrief is what the Mayor is told before a chat.
+type mayorBrief struct {
+	Generated   time.Time
+	Status      TownStatus
+	Escalations []*beads.Issue
+	Inbox       []*mail.Message // Unread
+	Missing     []string        // What couldn't be read, and why
+}
+
+func runMayorChat(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	brief := collectMayorBrief(townRoot, time.Now())
+	text := renderMayorBrief(brief)
+	if mayorChatPrint {
+		fmt.Print(text)
+		return nil
+	}
+	briefPath := filepath.Join(townRoot, constants.DirRuntime, mayorBriefFile)
+	if err := os.MkdirAll(filepath.Dir(briefPath), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	if err := os.WriteFile(briefPath, []byte(text), 0644); err != nil {
+		return fmt.Errorf("writing briefing: %w", err)
+	}
+
+	mgr := mayor.NewManager(townRoot)
+	if err := mgr.Start(mayorAgentOverride); err == nil {
+		fmt.Printf("%s Started the Mayor session\n", style.Bold.Render("✓"))
+	} else if !errors.Is(err, mayor.ErrAlreadyRunning) {
+		return fmt.Errorf("starting the Mayor: %w", err)
+	}
+
+	if err := tmux.NewTmux().NudgeSession(mgr.SessionName(), mayorChatNudge(briefPath, brief)); err != nil {
+		return fmt.Errorf("briefing the Mayor: %w", err)
+	}
+	fmt.Printf("%s Briefed the Mayor: %d open escalation(s), %d unread message(s)\n",
+		style.Bold.Render("✓"), len(brief.Escalations), len(brief.Inbox))
+	if mayorChatNoAttach {
+		fmt.Printf("Attach with: %s\n", style.Dim.Render("gt mayor attach"))
+		return nil
+	}
+	return attachToTmuxSession(mgr.SessionName())
+}
+
+// collectMayorBrief gathers the briefing. What can't be read is noted in
+// it rather than failing the chat.
+func collectMayorBrief(townRoot string, now time.Time) *mayorBrief {
+	b := &mayorBrief{Generated: now, Status: collectTownStatus(townRoot)}
+
+	escalations, err := beads.New(beads.ResolveBeadsDir(townRoot)).ListEscalations()
+	if err != nil {
+		b.Missing = append(b.Missing, fmt.Sprintf("escalations: %v", err))
+	}
+	b.Escalations = escalations
+
+	mailbox, err := mail.NewRouter(townRoot).GetMailbox("mayor/")
+	if err == nil {
+		b.Inbox, err = mailbox.ListUnread()
+	}
+	if err != nil {
+		b.Missing = append(b.Missing, fmt.Sprintf("inbox: %v", err))
+	}
+	return b
+}
+
+// mayorChatNudge asks the Mayor to read the briefing and open the chat.
+func mayorChatNudge(briefPath string, b *mayorBrief) string {
+	return fmt.Sprintf("The overseer is here to chat. Read the briefing in %s (town status, %d open escalation(s), %d unread message(s)), then open with a short summary of what needs their attention, most urgent first, and wait for their questions.",
+		briefPath, len(b.Escalations), len(b.Inbox))
+}
+
+// renderMayorBrief renders the briefing as markdown.
+func renderMayorBrief(b *mayorBrief) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Briefing for a chat with the overseer\n\nGenerated %s.\n\n", b.Generated.Format(time.RFC3339))
+
+	s := b.Status
+	sb.WriteString("## Town\n\n")
+	fmt.Fprintf(&sb, "%d rig(s), %d polecat(s), %d crew, %d active hook(s).\n\n",
+		s.Summary.RigCount, s.Summary.PolecatCount, s.Summary.CrewCount, s.Summary.ActiveHooks)
+	var down []string
+	for _, a := range s.Agents {
+		if !a.Running && a.Role != "mayor" {
+			down = append(down, a.Name)
+		}
+	}
+	for _, r := range s.Rigs {
+		if r.Error != "" {
+			fmt.Fprintf(&sb, "- %s: status unavailable (%s)\n", r.Name, r.Error)
+			continue
+		}
+		working := 0
+		for _, h := range r.Hooks {
+			if h.Role == "polecat" && h.HasWork {
+				working++
+			}
+		}
+		line := fmt.Sprintf("- %s: %d polecat(s), %d working", r.Name, r.PolecatCount, working)
+		if r.MQ != nil && r.MQ.Pending+r.MQ.InFlight+r.MQ.Blocked > 0 {
+			line += fmt.Sprintf("; merge queue %d pending, %d in flight, %d blocked (%s)", r.MQ.Pending, r.MQ.InFlight, r.MQ.Blocked, r.MQ.Health)
+		}
+		sb.WriteString(line + "\n")
+		for _, a := range r.Agents {
+			if !a.Running && (a.Role == "witness" || a.Role == "refinery") {
+				down = append(down, r.Name+"/"+a.Name)
+			}
+		}
+	}
+	if len(down) > 0 {
+		fmt.Fprintf(&sb, "\nNot running: %s\n", strings.Join(down, ", "))
+	}
+
+	fmt.Fprintf(&sb, "\n## Open escalations (%d)\n\n", len(b.Escalations))
+	if len(b.Escalations) == 0 {
+		sb.WriteString("None.\n")
+	}
+	for _, issue := range b.Escalations {
+		fields := beads.ParseEscalationFields(issue.Description)
+		acked := ""
+		if beads.HasLabel(issue, "acked") {
+			acked = ", acked"
+		}
+		fmt.Fprintf(&sb, "- [%s] %s: %s (from %s, %s%s)\n",
+			fields.Severity, issue.ID, issue.Title, fields.EscalatedBy, formatRelativeTime(issue.CreatedAt), acked)
+	}
+
+	fmt.Fprintf(&sb, "\n## Unread mail (%d)\n\n", len(b.Inbox))
+	if len(b.Inbox) == 0 {
+		sb.WriteString("None.\n")
+	}
+	for _, m := range b.Inbox {
+		fmt.Fprintf(&sb, "- %s from %s: %s (%s)\n", m.ID, m.From, m.Subject, formatAge(m.Timestamp))
+	}
+
+	if len(b.Missing) > 0 {
+		sb.WriteString("\n## Could not read\n\n")
+		for _, m := range b.Missing {
+			fmt.Fprintf(&sb, "- %s\n", m)
+		}
+	}
+	return sb.String()
+}
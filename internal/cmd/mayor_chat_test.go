@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestRenderMayorBrief(t *testing.T) {
+	now := time.Now()
+	b := &mayorBrief{
+		Generated: now,
+		Status: TownStatus{
+			Agents: []AgentRuntime{
+				{Name: "mayor", Role: "mayor"},
+				{Name: "deacon", Role: "deacon"},
+			},
+			Rigs: []RigStatus{
+				{
+					Name:         "gastown",
+					PolecatCount: 2,
+					Hooks: []AgentHookInfo{
+						{Agent: "gastown/toast", Role: "polecat", HasWork: true},
+						{Agent: "gastown/nux", Role: "polecat"},
+					},
+					Agents: []AgentRuntime{
+						{Name: "witness", Role: "witness", Running: true},
+						{Name: "refinery", Role: "refinery"},
+					},
+					MQ: &MQSummary{Pending: 2, Blocked: 1, Health: "healthy"},
+				},
+				{Name: "far", Error: "ssh: connection refused"},
+			},
+			Summary: StatusSum{RigCount: 2, PolecatCount: 2},
+		},
+		Escalations: []*beads.Issue{{
+			ID:          "hq-esc1",
+			Title:       "Refinery stuck on conflict",
+			Description: "severity: high\nescalated_by: gastown/witness\n",
+			CreatedAt:   now.Add(-2 * time.Hour).Format(time.RFC3339),
+		}},
+		Inbox:   []*mail.Message{{ID: "hq-m1", From: "gastown/witness", Subject: "Polecat nux idle", Timestamp: now.Add(-time.Hour)}},
+		Missing: []string{"inbox: boom"},
+	}
+
+	text := renderMayorBrief(b)
+	for _, want := range []string{
+		"2 rig(s), 2 polecat(s)",
+		"- gastown: 2 polecat(s), 1 working; merge queue 2 pending, 0 in flight, 1 blocked (healthy)",
+		"- far: status unavailable (ssh: connection refused)",
+		"Not running: deacon, gastown/refinery",
+		"## Open escalations (1)",
+		"- [high] hq-esc1: Refinery stuck on conflict (from gastown/witness, 2 hours ago)",
+		"## Unread mail (1)",
+		"- hq-m1 from gastown/witness: Polecat nux idle",
+		"## Could not read\n\n- inbox: boom",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("briefing missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Not running: mayor") {
+		t.Errorf("briefing lists the mayor as down:\n%s", text)
+	}
+
+	nudge := mayorChatNudge("/town/.runtime/mayor-brief.md", b)
+	if !strings.Contains(nudge, "/town/.runtime/mayor-brief.md") || !strings.Contains(nudge, "1 open escalation(s), 1 unread message(s)") {
+		t.Errorf("nudge = %q", nudge)
+	}
+}
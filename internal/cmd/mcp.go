@@ -0,0 +1,597 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/authz"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/delegation"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mcp"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	mcpRole      string
+	mcpToolsJSON bool
+)
+
+var mcpCmd = &cobra.Command{
+	Use:     "mcp",
+	GroupID: GroupServices,
+	Short:   "Serve town operations to agents over MCP",
+	RunE:    requireSubcommand,
+	Long: `Serve town operations to agents as MCP (Model Context Protocol) tools,
+so an agent can query beads, send mail, check the refinery or request a
+sub-spawn without shelling out to gt.
+
+Tools are scoped by role. Every role can read beads, use its mail and see
+the refinery queue. Workers (polecats, crew, witness, refinery) request
+a spawn from the Mayor with request_spawn; the Mayor, Deacon, Witness and
+crew can update beads. Override a role's tools in settings/config.json:
+
+  "mcp": {"roles": {"polecat": ["beads_show", "beads_list", "mail_send"]}}
+
+The Mayor delegates without a human relaying each step: bead_create files
+the pieces of a larger request, and spawn slings a polecat onto one,
+optionally with a formula as its molecule. Spawns are capped per hour (6
+by default) and to the allowed model tiers, the first being the default:
+
+  "mcp": {"delegation": {"max_spawns_per_hour": 4, "tiers": ["haiku", "sonnet"]}}
+
+Register the server with an agent by adding 'gt mcp config' output to its
+.mcp.json. The server takes its role from the agent's signed role token,
+and only the overseer may pick another with --role (see 'gt permissions').
+
+Examples:
+  gt mcp tools                 # Tools this agent may call
+  gt mcp tools --role polecat  # ...or another role
+  gt mcp config                # .mcp.json entry for the server
+  gt mcp serve                 # Run the server on stdio (agents do this)`,
+}
+
+var mcpServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the MCP server on stdin/stdout",
+	Args:  cobra.NoArgs,
+	RunE:  runMCPServe,
+}
+
+var mcpToolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "List the tools a role may call",
+	Args:  cobra.NoArgs,
+	RunE:  runMCPTools,
+}
+
+var mcpConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Print the .mcp.json entry that registers the server",
+	Args:  cobra.NoArgs,
+	RunE:  runMCPConfig,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{mcpServeCmd, mcpToolsCmd} {
+		c.Flags().StringVar(&mcpRole, "role", "", "Serve as this role instead of the detected one")
+	}
+	mcpToolsCmd.Flags().BoolVar(&mcpToolsJSON, "json", false, "Output as JSON")
+
+	mcpCmd.AddCommand(mcpServeCmd)
+	mcpCmd.AddCommand(mcpToolsCmd)
+	mcpCmd.AddCommand(mcpConfigCmd)
+	rootCmd.AddCommand(mcpCmd)
+}
+
+// mcpSession is who the server is serving.
+type mcpSession struct {
+	townRoot string
+	info     RoleInfo
+	role     string
+	sender   string
+}
+
+// newMCPServer builds the server for the current agent.
+func newMCPServer() (*mcp.Server, *mcpSession, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	info, err := GetRole()
+	if err != nil {
+		return nil, nil, err
+	}
+	// The server's role is the caller's verified one; only the overseer
+	// may serve as another role
+	id, _, err := callerIdentity(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := &mcpSession{townRoot: townRoot, info: info, role: id.Role, sender: detectSender()}
+	if mcpRole != "" && mcpRole != id.Role {
+		if id.Role != authz.Overseer {
+			return nil, nil, fmt.Errorf("a %s may not serve tools as %s", id.Role, mcpRole)
+		}
+		s.role = mcpRole
+	}
+
+	var allow map[string][]string
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.MCP != nil {
+		allow = settings.MCP.Roles
+	}
+	return mcp.NewServer("gastown", Version, s.role, s.tools(), allow), s, nil
+}
+
+func runMCPServe(cmd *cobra.Command, args []string) error {
+	server, _, err := newMCPServer()
+	if err != nil {
+		return err
+	}
+	return server.Serve(context.Background(), os.Stdin, os.Stdout)
+}
+
+func runMCPTools(cmd *cobra.Command, args []string) error {
+	server, s, err := newMCPServer()
+	if err != nil {
+		return err
+	}
+	tools := server.Tools()
+	if mcpToolsJSON {
+		var names []string
+		for _, t := range tools {
+			names = append(names, t.Name)
+		}
+		return outputJSON(map[string]any{"role": s.role, "tools": names})
+	}
+	fmt.Printf("%s Tools for %s:\n", style.Bold.Render("●"), s.role)
+	for _, t := range tools {
+		fmt.Printf("  %-16s %s\n", t.Name, style.Dim.Render(t.Description))
+	}
+	return nil
+}
+
+func runMCPConfig(cmd *cobra.Command, args []string) error {
+	return outputJSON(map[string]any{
+		"mcpServers": map[string]any{
+			"gastown": map[string]any{"command": "gt", "args": []string{"mcp", "serve"}},
+		},
+	})
+}
+
+// Role lists for tools that not every role may call.
+var (
+	mcpWorkerRoles  = []string{string(RolePolecat), string(RoleCrew), string(RoleWitness), string(RoleRefinery), authz.Overseer}
+	mcpManagerRoles = []string{string(RoleMayor), string(RoleDeacon), string(RoleWitness), string(RoleCrew), authz.Overseer}
+	mcpMayorRoles   = []string{string(RoleMayor), authz.Overseer}
+)
+
+// mcpNamePattern matches the formula and tier names a tool passes on to gt.
+var mcpNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// mcpRunGT runs a gt command for a tool, returning its combined output.
+var mcpRunGT = func(args ...string) ([]byte, error) {
+	return exec.Command("gt", args...).CombinedOutput() //nolint:gosec // G204: args are tool arguments passed to gt, not a shell
+}
+
+func mcpSchema(required []string, props map[string]string) map[string]any {
+	properties := map[string]any{}
+	for name, desc := range props {
+		typ := "string"
+		if name == "unread_only" {
+			typ = "boolean"
+		}
+		properties[name] = map[string]any{"type": typ, "description": desc}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// tools are the town operations the server offers.
+func (s *mcpSession) tools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "beads_show",
+			Description: "Show a bead (issue) by ID",
+			InputSchema: mcpSchema([]string{"id"}, map[string]string{"id": "Bead ID, e.g. gt-abc12"}),
+			Call:        s.beadsShow,
+		},
+		{
+			Name:        "beads_list",
+			Description: "List beads in a rig (default: yours) or the town",
+			InputSchema: mcpSchema(nil, map[string]string{
+				"rig":      "Rig name, or \"town\" for town beads",
+				"status":   "open, in_progress, closed or all",
+				"assignee": "Assignee address, e.g. greenplace/Toast",
+				"label":    "Label filter",
+				"parent":   "Parent bead ID",
+			}),
+			Call: s.beadsList,
+		},
+		{
+			Name:        "beads_ready",
+			Description: "List beads ready to work on (no open blockers)",
+			InputSchema: mcpSchema(nil, map[string]string{"rig": "Rig name, or \"town\""}),
+			Call:        s.beadsReady,
+		},
+		{
+			Name:        "bead_update",
+			Description: "Update a bead's status, assignee or priority",
+			InputSchema: mcpSchema([]string{"id"}, map[string]string{
+				"id":       "Bead ID",
+				"status":   "New status: open, in_progress, blocked or closed",
+				"assignee": "New assignee address (empty string unassigns)",
+				"priority": "New priority, 0 (highest) to 4",
+			}),
+			Roles: mcpManagerRoles,
+			Call:  s.beadUpdate,
+		},
+		{
+			Name:        "bead_create",
+			Description: "File a bead (issue) in a rig or the town, e.g. one piece of a larger request",
+			InputSchema: mcpSchema([]string{"title"}, map[string]string{
+				"title":       "What needs doing",
+				"description": "Details and acceptance criteria",
+				"type":        "task, bug, feature or epic (default task)",
+				"priority":    "0 (highest) to 4 (default 2)",
+				"parent":      "Parent bead ID, e.g. the epic this breaks down",
+				"rig":         "Rig to file it in, or \"town\" (default: yours)",
+			}),
+			Roles: mcpMayorRoles,
+			Call:  s.beadCreate,
+		},
+		{
+			Name:        "spawn",
+			Description: "Spawn a polecat on a bead, optionally running a formula as its molecule; capped per hour and to the allowed model tiers",
+			InputSchema: mcpSchema([]string{"bead", "rig"}, map[string]string{
+				"bead":    "Bead for the polecat to work on",
+				"rig":     "Rig to spawn the polecat in",
+				"formula": "Formula to attach to the bead as its molecule",
+				"tier":    "Model tier (default: the first allowed tier, or the rig's)",
+			}),
+			Roles: mcpMayorRoles,
+			Call:  s.spawn,
+		},
+		{
+			Name:        "delegation_status",
+			Description: "Show the limits on delegated spawns and the spawns of the last hour",
+			Roles:       mcpMayorRoles,
+			Call:        s.delegationStatus,
+		},
+		{
+			Name:        "mail_send",
+			Description: "Send mail to an agent, e.g. mayor/ or greenplace/witness",
+			InputSchema: mcpSchema([]string{"to", "subject"}, map[string]string{
+				"to":       "Recipient address",
+				"subject":  "Subject line",
+				"body":     "Message body",
+				"priority": "low, normal, high or urgent",
+			}),
+			Call: s.mailSend,
+		},
+		{
+			Name:        "mail_inbox",
+			Description: "List your mail",
+			InputSchema: mcpSchema(nil, map[string]string{"unread_only": "Only unread messages"}),
+			Call:        s.mailInbox,
+		},
+		{
+			Name:        "refinery_status",
+			Description: "Show a rig's refinery state and merge queue",
+			InputSchema: mcpSchema(nil, map[string]string{"rig": "Rig name (default: yours)"}),
+			Call:        s.refineryStatus,
+		},
+		{
+			Name:        "request_spawn",
+			Description: "Ask the Mayor for a polecat to take on a piece of work; files it as a bead",
+			InputSchema: mcpSchema([]string{"title"}, map[string]string{
+				"title":       "What the new polecat should do",
+				"description": "Details and acceptance criteria",
+				"rig":         "Rig to spawn in (default: yours)",
+			}),
+			Roles: mcpWorkerRoles,
+			Call:  s.requestSpawn,
+		},
+	}
+}
+
+// rigOrDefault returns the rig argument, or the agent's own rig.
+func (s *mcpSession) rigOrDefault(args map[string]any) string {
+	if r := mcp.StringArg(args, "rig"); r != "" {
+		return r
+	}
+	return s.info.Rig
+}
+
+// beadsFor returns the beads database for a rig argument ("town" or no
+// rig means the town's).
+func (s *mcpSession) beadsFor(args map[string]any) *beads.Beads {
+	rigName := s.rigOrDefault(args)
+	if rigName == "" || rigName == "town" {
+		return beads.New(s.townRoot)
+	}
+	return beads.New(rigBeadsLocation(s.townRoot, rigName))
+}
+
+func (s *mcpSession) beadsShow(_ context.Context, args map[string]any) (any, error) {
+	id, err := mcp.RequireString(args, "id")
+	if err != nil {
+		return nil, err
+	}
+	return beads.New(beadLocation(s.townRoot, id)).Show(id)
+}
+
+func (s *mcpSession) beadsList(_ context.Context, args map[string]any) (any, error) {
+	return s.beadsFor(args).List(beads.ListOptions{
+		Status:   mcp.StringArg(args, "status"),
+		Assignee: mcp.StringArg(args, "assignee"),
+		Label:    mcp.StringArg(args, "label"),
+		Parent:   mcp.StringArg(args, "parent"),
+		Priority: -1,
+	})
+}
+
+func (s *mcpSession) beadsReady(_ context.Context, args map[string]any) (any, error) {
+	return s.beadsFor(args).Ready()
+}
+
+func (s *mcpSession) beadUpdate(_ context.Context, args map[string]any) (any, error) {
+	id, err := mcp.RequireString(args, "id")
+	if err != nil {
+		return nil, err
+	}
+	var opts beads.UpdateOptions
+	if v, ok := args["status"].(string); ok && v != "" {
+		opts.Status = &v
+	}
+	if v, ok := args["assignee"].(string); ok {
+		opts.Assignee = &v
+	}
+	if v := mcp.StringArg(args, "priority"); v != "" {
+		p, err := mcpPriority(v)
+		if err != nil {
+			return nil, err
+		}
+		opts.Priority = &p
+	}
+	if opts.Status == nil && opts.Assignee == nil && opts.Priority == nil {
+		return nil, fmt.Errorf("nothing to update (give status, assignee or priority)")
+	}
+	if err := beads.New(beadLocation(s.townRoot, id)).Update(id, opts); err != nil {
+		return nil, err
+	}
+	return fmt.Sprintf("updated %s", id), nil
+}
+
+// mcpPriority parses a priority argument.
+func mcpPriority(v string) (int, error) {
+	var p int
+	if _, err := fmt.Sscanf(v, "%d", &p); err != nil || p < 0 || p > 4 {
+		return 0, fmt.Errorf("priority must be 0-4, got %q", v)
+	}
+	return p, nil
+}
+
+func (s *mcpSession) beadCreate(_ context.Context, args map[string]any) (any, error) {
+	title, err := mcp.RequireString(args, "title")
+	if err != nil {
+		return nil, err
+	}
+	priority := 2
+	if v := mcp.StringArg(args, "priority"); v != "" {
+		if priority, err = mcpPriority(v); err != nil {
+			return nil, err
+		}
+	}
+	issueType := mcp.StringArg(args, "type")
+	if issueType == "" {
+		issueType = "task"
+	}
+	rigArg := mcp.StringArg(args, "rig")
+	location, rigName, err := beadCreateTarget(s.townRoot, s.info, rigArg, rigArg == "town")
+	if err != nil {
+		return nil, err
+	}
+	issue, err := beads.New(location).Create(beads.CreateOptions{
+		Title:       title,
+		Type:        issueType,
+		Priority:    priority,
+		Description: mcp.StringArg(args, "description"),
+		Parent:      mcp.StringArg(args, "parent"),
+		Actor:       s.sender,
+		Labels:      beadContextLabels(s.info, rigName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// delegationLimits returns the town's current limits on delegated spawns.
+func (s *mcpSession) delegationLimits() delegation.Limits {
+	var cfg *config.DelegationConfig
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(s.townRoot)); err == nil && settings.MCP != nil {
+		cfg = settings.MCP.Delegation
+	}
+	return delegation.LimitsFrom(cfg)
+}
+
+func (s *mcpSession) spawn(_ context.Context, args map[string]any) (any, error) {
+	bead, err := mcp.RequireString(args, "bead")
+	if err != nil {
+		return nil, err
+	}
+	rigName, err := mcp.RequireString(args, "rig")
+	if err != nil {
+		return nil, err
+	}
+	if !beads.IsValidID(bead) {
+		return nil, fmt.Errorf("invalid bead ID %q", bead)
+	}
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(s.townRoot)); err != nil {
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	} else if _, ok := rigsConfig.Rigs[rigName]; !ok {
+		return nil, fmt.Errorf("rig %q not found", rigName)
+	}
+	limits := s.delegationLimits()
+	tier, err := limits.Tier(mcp.StringArg(args, "tier"))
+	if err != nil {
+		return nil, err
+	}
+	formula := mcp.StringArg(args, "formula")
+	for name, v := range map[string]string{"formula": formula, "tier": tier} {
+		if v != "" && !mcpNamePattern.MatchString(v) {
+			return nil, fmt.Errorf("invalid %s %q", name, v)
+		}
+	}
+
+	// gt sling applies the town's other limits: spend caps, admission. The
+	// arguments come from the client, so they go after "--", where none
+	// can be read as a flag.
+	gtArgs := []string{"sling"}
+	if formula != "" {
+		gtArgs = append(gtArgs, "--on="+bead)
+	}
+	if tier != "" {
+		gtArgs = append(gtArgs, "--tier="+tier)
+	}
+	if formula != "" {
+		gtArgs = append(gtArgs, "--", formula, rigName)
+	} else {
+		gtArgs = append(gtArgs, "--", bead, rigName)
+	}
+	var out []byte
+	record := delegation.Spawn{Bead: bead, Rig: rigName, Formula: formula, Tier: tier, By: s.sender}
+	err = delegation.NewLedger(s.townRoot).Spawn(limits, record, time.Now(), func() error {
+		var runErr error
+		if out, runErr = mcpRunGT(gtArgs...); runErr != nil {
+			return fmt.Errorf("gt %s: %w\n%s", strings.Join(gtArgs, " "), runErr, strings.TrimSpace(string(out)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"bead":    bead,
+		"rig":     rigName,
+		"formula": formula,
+		"tier":    tier,
+		"output":  strings.TrimSpace(string(out)),
+	}, nil
+}
+
+func (s *mcpSession) delegationStatus(_ context.Context, _ map[string]any) (any, error) {
+	limits := s.delegationLimits()
+	recent, err := delegation.NewLedger(s.townRoot).Recent(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"limits":    limits,
+		"recent":    recent,
+		"remaining": max(limits.MaxSpawnsPerHour-len(recent), 0),
+	}, nil
+}
+
+func (s *mcpSession) mailSend(_ context.Context, args map[string]any) (any, error) {
+	to, err := mcp.RequireString(args, "to")
+	if err != nil {
+		return nil, err
+	}
+	subject, err := mcp.RequireString(args, "subject")
+	if err != nil {
+		return nil, err
+	}
+	msg := mail.NewMessage(s.sender, to, subject, mcp.StringArg(args, "body"))
+	if p := mcp.StringArg(args, "priority"); p != "" {
+		msg.Priority = mail.ParsePriority(p)
+	}
+	if err := mail.NewRouter(s.townRoot).Send(msg); err != nil {
+		return nil, err
+	}
+	return fmt.Sprintf("sent to %s", to), nil
+}
+
+func (s *mcpSession) mailInbox(_ context.Context, args map[string]any) (any, error) {
+	mailbox, err := mail.NewRouter(s.townRoot).GetMailbox(s.sender)
+	if err != nil {
+		return nil, err
+	}
+	if mcp.BoolArg(args, "unread_only") {
+		return mailbox.ListUnread()
+	}
+	return mailbox.List()
+}
+
+func (s *mcpSession) refineryStatus(_ context.Context, args map[string]any) (any, error) {
+	rigName := s.rigOrDefault(args)
+	if rigName == "" {
+		return nil, fmt.Errorf("no rig given and you are not in one")
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return nil, err
+	}
+	mgr := refinery.NewManager(r)
+	status, err := mgr.Status()
+	if err != nil {
+		return nil, err
+	}
+	queue, err := mgr.Queue()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"refinery": status, "queue": queue}, nil
+}
+
+func (s *mcpSession) requestSpawn(_ context.Context, args map[string]any) (any, error) {
+	title, err := mcp.RequireString(args, "title")
+	if err != nil {
+		return nil, err
+	}
+	location, rigName, err := beadCreateTarget(s.townRoot, s.info, mcp.StringArg(args, "rig"), false)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := beads.New(location).Create(beads.CreateOptions{
+		Title:       title,
+		Type:        "task",
+		Priority:    2,
+		Description: mcp.StringArg(args, "description"),
+		Actor:       s.sender,
+		Labels:      beadContextLabels(s.info, rigName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	target := issue.ID
+	if rigName != "" {
+		target += " " + rigName
+	}
+	body := fmt.Sprintf("%s asks for a polecat to take %s: %s\n\nTo spawn it: gt sling %s", s.sender, issue.ID, title, target)
+	msg := mail.NewMessage(s.sender, "mayor/", "Spawn request: "+title, body)
+	msg.Type = mail.TypeTask
+	if err := mail.NewRouter(s.townRoot).Send(msg); err != nil {
+		return nil, fmt.Errorf("filed %s but could not mail the Mayor: %w", issue.ID, err)
+	}
+	return map[string]string{
+		"bead":   issue.ID,
+		"status": "requested; the Mayor decides whether to spawn it",
+		"rig":    strings.TrimSpace(rigName),
+	}, nil
+}
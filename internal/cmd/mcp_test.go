@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/authz"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/delegation"
+	"github.com/steveyegge/gastown/internal/mcp"
+)
+
+func TestMCPToolRoles(t *testing.T) {
+	s := &mcpSession{}
+	offers := func(role, tool string) bool {
+		for _, t := range mcp.NewServer("gastown", "test", role, s.tools(), nil).Tools() {
+			if t.Name == tool {
+				return true
+			}
+		}
+		return false
+	}
+
+	cases := []struct {
+		role, tool string
+		want       bool
+	}{
+		{"polecat", "beads_show", true},
+		{"polecat", "request_spawn", true},
+		{"polecat", "bead_update", false},
+		{"mayor", "bead_update", true},
+		{"mayor", "request_spawn", false},
+		{"crew", "request_spawn", true},
+		{authz.Overseer, "bead_update", true},
+		{authz.Overseer, "request_spawn", true},
+		{"mayor", "spawn", true},
+		{"mayor", "bead_create", true},
+		{"deacon", "spawn", false},
+		{"polecat", "delegation_status", false},
+		{authz.Overseer, "spawn", true},
+	}
+	for _, tc := range cases {
+		if got := offers(tc.role, tc.tool); got != tc.want {
+			t.Errorf("%s offered %s = %v, want %v", tc.role, tc.tool, got, tc.want)
+		}
+	}
+}
+
+func TestMCPSpawnGuardrails(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.MCP = &config.MCPConfig{Delegation: &config.DelegationConfig{MaxSpawnsPerHour: 1, Tiers: []string{"haiku", "sonnet"}}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	rigsConfig := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{"gastown": {}}}
+	if err := config.SaveRigsConfig(constants.MayorRigsPath(townRoot), rigsConfig); err != nil {
+		t.Fatal(err)
+	}
+	var calls [][]string
+	orig := mcpRunGT
+	mcpRunGT = func(args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		return []byte("slung"), nil
+	}
+	t.Cleanup(func() { mcpRunGT = orig })
+	s := &mcpSession{townRoot: townRoot, sender: "mayor/"}
+
+	if _, err := s.spawn(context.Background(), map[string]any{"bead": "gt-a", "rig": "gastown", "tier": "opus"}); !errors.Is(err, delegation.ErrTierNotAllowed) {
+		t.Errorf("spawn on opus = %v, want ErrTierNotAllowed", err)
+	}
+	// Client arguments that gt would read as flags, or that name no rig
+	for _, args := range []map[string]any{
+		{"bead": "--force", "rig": "gastown"},
+		{"bead": "-x", "rig": "gastown"},
+		{"bead": "gt-a", "rig": "--all"},
+		{"bead": "gt-a", "rig": "nowhere"},
+		{"bead": "gt-a", "rig": "gastown", "formula": "--force"},
+	} {
+		if _, err := s.spawn(context.Background(), args); err == nil {
+			t.Errorf("spawn(%v) was allowed", args)
+		}
+	}
+	if _, err := s.spawn(context.Background(), map[string]any{"bead": "gt-a", "rig": "gastown", "formula": "shiny"}); err != nil {
+		t.Fatalf("spawn: %v", err)
+	}
+	want := []string{"sling", "--on=gt-a", "--tier=haiku", "--", "shiny", "gastown"}
+	if len(calls) != 1 || !slices.Equal(calls[0], want) {
+		t.Errorf("gt calls = %v, want [%v]", calls, want)
+	}
+	if _, err := s.spawn(context.Background(), map[string]any{"bead": "gt-b", "rig": "gastown"}); !errors.Is(err, delegation.ErrRateLimited) {
+		t.Errorf("second spawn in the hour = %v, want ErrRateLimited", err)
+	}
+
+	status, err := s.delegationStatus(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := status.(map[string]any)["remaining"]; got != 0 {
+		t.Errorf("remaining = %v, want 0", got)
+	}
+	if len(calls) != 1 || !strings.Contains(strings.Join(calls[0], " "), "gt-a") {
+		t.Errorf("refused spawns reached gt: %v", calls)
+	}
+}
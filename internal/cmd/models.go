@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/models"
+	"github.com/steveyegge/gastown/internal/ratelimit"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+var modelsListJSON bool
+
+var modelsCmd = &cobra.Command{
+	Use:     "models",
+	GroupID: GroupConfig,
+	Short:   "Show model tiers and check their providers",
+	RunE:    requireSubcommand,
+	Long: `Show the model tiers polecats can run (gt sling --tier) and check that
+each tier's provider answers before a big convoy run.
+
+haiku, sonnet and opus run Anthropic's models by default. Override them
+or add tiers on OpenAI or a local Ollama server in settings/config.json:
+
+  "models": {"tiers": {
+    "opus":  {"provider": "anthropic", "model": "claude-opus-4-1"},
+    "fast":  {"provider": "openai", "model": "gpt-5-mini"},
+    "local": {"provider": "ollama", "model": "qwen2.5-coder"}
+  }}
+
+A tier runs on an agent that speaks its provider: claude for Anthropic,
+codex for OpenAI, opencode for Ollama.
+
+API keys are read from, in order:
+  1. The tier's key_env, or ANTHROPIC_API_KEY / OPENAI_API_KEY
+  2. The system keychain (service "gastown", account = provider)
+  3. ~/.config/gastown/secrets.json, which must be mode 0600
+     (written by 'gt models key set')
+
+Examples:
+  gt models list
+  gt models test
+  gt models test opus local
+  gt models key set openai`,
+}
+
+var modelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List model tiers and where their keys come from",
+	Args:  cobra.NoArgs,
+	RunE:  runModelsList,
+}
+
+var modelsTestCmd = &cobra.Command{
+	Use:   "test [tier...]",
+	Short: "Check that each tier's provider answers",
+	Long: `Check each tier (or the named ones) by listing the provider's models
+with the tier's key. No tokens are spent. Exits 1 if any tier fails.`,
+	RunE: runModelsTest,
+}
+
+var modelsKeyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Manage API keys in the secrets file",
+	RunE:  requireSubcommand,
+}
+
+var modelsKeySetCmd = &cobra.Command{
+	Use:   "set <provider>",
+	Short: "Store a provider's API key in the secrets file",
+	Long: `Store a provider's API key in ~/.config/gastown/secrets.json (mode 0600).
+The key is read from stdin, without echo when stdin is a terminal.
+
+Examples:
+  gt models key set anthropic
+  echo "$KEY" | gt models key set openai`,
+	Args: cobra.ExactArgs(1),
+	RunE: runModelsKeySet,
+}
+
+var modelsKeyRemoveCmd = &cobra.Command{
+	Use:   "remove <provider>",
+	Short: "Remove a provider's API key from the secrets file",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runModelsKeyRemove,
+}
+
+func init() {
+	modelsListCmd.Flags().BoolVar(&modelsListJSON, "json", false, "Output as JSON")
+
+	modelsKeyCmd.AddCommand(modelsKeySetCmd)
+	modelsKeyCmd.AddCommand(modelsKeyRemoveCmd)
+	modelsCmd.AddCommand(modelsListCmd)
+	modelsCmd.AddCommand(modelsTestCmd)
+	modelsCmd.AddCommand(modelsKeyCmd)
+	rootCmd.AddCommand(modelsCmd)
+}
+
+// loadModelsConfig returns the town's models settings, or nil outside a
+// town (the default tiers still apply).
+func loadModelsConfig() (*config.ModelsConfig, error) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil, nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return settings.Models, nil
+}
+
+// ModelTierInfo is a tier as shown by gt models list --json.
+type ModelTierInfo struct {
+	Tier      string `json:"tier"`
+	Provider  string `json:"provider"`
+	Model     string `json:"model"`
+	BaseURL   string `json:"base_url"`
+	KeySource string `json:"key_source,omitempty"`
+	KeyError  string `json:"key_error,omitempty"`
+	Default   bool   `json:"default"`
+}
+
+func runModelsList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadModelsConfig()
+	if err != nil {
+		return err
+	}
+	tiers, err := models.Resolve(cfg)
+	if err != nil {
+		return err
+	}
+
+	var infos []ModelTierInfo
+	for i := range tiers {
+		t := &tiers[i]
+		info := ModelTierInfo{Tier: t.Name, Provider: t.Provider, Model: t.Model, BaseURL: t.BaseURL(), Default: t.Default}
+		key, err := models.LookupKey(t.ModelSpec)
+		switch {
+		case err != nil:
+			info.KeyError = err.Error()
+		case key != nil:
+			info.KeySource = key.Source
+		}
+		infos = append(infos, info)
+	}
+	if modelsListJSON {
+		return outputJSON(infos)
+	}
+
+	for _, info := range infos {
+		keyNote := style.Dim.Render("no key needed")
+		switch {
+		case info.KeyError != "":
+			keyNote = style.Error.Render("key: " + info.KeyError)
+		case info.KeySource != "":
+			keyNote = style.Dim.Render("key: " + info.KeySource)
+		case models.NeedsKey(info.Provider):
+			keyNote = style.Warning.Render("no key")
+		}
+		fmt.Printf("  %-10s %-10s %-24s %s\n", info.Tier, info.Provider, info.Model, keyNote)
+	}
+	return nil
+}
+
+func runModelsTest(cmd *cobra.Command, args []string) error {
+	cfg, err := loadModelsConfig()
+	if err != nil {
+		return err
+	}
+	tiers, err := models.Resolve(cfg)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		var picked []models.Tier
+		for _, name := range args {
+			t, err := models.Find(cfg, name)
+			if err != nil {
+				return err
+			}
+			picked = append(picked, *t)
+		}
+		tiers = picked
+	}
+
+	client := &http.Client{Timeout: models.PingTimeout}
+	// In a town, pings take their turn under the town's rate limits
+	var limiter *ratelimit.Limiter
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if limiter, err = ratelimit.Load(townRoot); err != nil {
+			return err
+		}
+	}
+	failed := 0
+	for i := range tiers {
+		t := &tiers[i]
+		key, err := models.LookupKey(t.ModelSpec)
+		if err == nil && limiter != nil {
+			err = limiter.Wait(context.Background(), t.Provider)
+		}
+		if err == nil {
+			start := time.Now()
+			err = models.Ping(context.Background(), client, t, key)
+			var limited *models.RateLimitedError
+			if errors.As(err, &limited) && limiter != nil {
+				_, _ = limiter.Hit(t.Provider, limited.RetryAfter)
+			}
+			if err == nil {
+				if limiter != nil {
+					_ = limiter.Succeeded(t.Provider)
+				}
+				fmt.Printf("%s %-10s %s/%s %s\n", style.Success.Render("✓"), t.Name, t.Provider, t.Model,
+					style.Dim.Render(time.Since(start).Round(time.Millisecond).String()))
+				continue
+			}
+		}
+		failed++
+		fmt.Printf("%s %-10s %s/%s: %v\n", style.Error.Render("✗"), t.Name, t.Provider, t.Model, err)
+	}
+
+	if failed > 0 {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func checkProvider(provider string) (string, error) {
+	provider = strings.ToLower(provider)
+	if !slices.Contains(models.Providers, provider) {
+		return "", fmt.Errorf("unknown provider %q (use %s)", provider, strings.Join(models.Providers, ", "))
+	}
+	if !models.NeedsKey(provider) {
+		return "", fmt.Errorf("%s needs no API key", provider)
+	}
+	return provider, nil
+}
+
+func runModelsKeySet(cmd *cobra.Command, args []string) error {
+	provider, err := checkProvider(args[0])
+	if err != nil {
+		return err
+	}
+
+	var key string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Printf("%s API key: ", provider)
+		b, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			return fmt.Errorf("reading key: %w", err)
+		}
+		key = string(b)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("reading key from stdin: %w", err)
+		}
+		key = line
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return fmt.Errorf("no key given")
+	}
+
+	if err := models.SetSecret(provider, key); err != nil {
+		return err
+	}
+	fmt.Printf("%s Stored %s key in %s\n", style.Bold.Render("✓"), provider, models.SecretsPath())
+	if env := models.KeyEnvVars[provider]; os.Getenv(env) != "" {
+		fmt.Printf("  %s %s is set and takes precedence\n", style.Dim.Render("Note:"), env)
+	}
+	return nil
+}
+
+func runModelsKeyRemove(cmd *cobra.Command, args []string) error {
+	provider, err := checkProvider(args[0])
+	if err != nil {
+		return err
+	}
+	if err := models.SetSecret(provider, ""); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed %s key from %s\n", style.Bold.Render("✓"), provider, models.SecretsPath())
+	return nil
+}
@@ -34,6 +34,15 @@ LIFECYCLE:
   gt mol burn          Discard attached molecule (no record)
   gt mol squash        Compress to digest (permanent record)
 
+RUN HISTORY:
+  gt mol runs          List recorded molecule runs
+  gt mol show-run      Show a run's variables and step timings
+  gt mol replay        Re-run a molecule, optionally from a step
+  gt mol migrate-runs  Move running molecules to a new formula version
+
+AUTHORING:
+  gt mol lint          Check formulas against best-practice rules
+
 TO DISPATCH WORK (with molecules):
   gt sling mol-xxx target   # Pour formula + sling to agent
   gt formulas               # List available formulas`,
@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -19,7 +20,6 @@ var (
 	awaitSignalBackoffBase string
 	awaitSignalBackoffMult int
 	awaitSignalBackoffMax  string
-	awaitSignalQuiet       bool
 	awaitSignalAgentBead   string
 )
 
@@ -85,8 +85,6 @@ func init() {
 		"Maximum interval cap for backoff (e.g., 10m)")
 	moleculeAwaitSignalCmd.Flags().StringVar(&awaitSignalAgentBead, "agent-bead", "",
 		"Agent bead ID for tracking idle cycles (reads/writes idle:N label)")
-	moleculeAwaitSignalCmd.Flags().BoolVar(&awaitSignalQuiet, "quiet", false,
-		"Suppress output (for scripting)")
 	moleculeAwaitSignalCmd.Flags().BoolVar(&moleculeJSON, "json", false,
 		"Output as JSON")
 
@@ -108,7 +106,7 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 		labels, err := getAgentLabels(awaitSignalAgentBead, beadsDir)
 		if err != nil {
 			// Agent bead might not exist yet - that's OK, start at 0
-			if !awaitSignalQuiet {
+			if !output.IsQuiet() {
 				fmt.Printf("%s Could not read agent bead (starting at idle=0): %v\n",
 					style.Dim.Render("⚠"), err)
 			}
@@ -125,7 +123,7 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid timeout configuration: %w", err)
 	}
 
-	if !awaitSignalQuiet && !moleculeJSON {
+	if !output.IsQuiet() && !moleculeJSON {
 		if awaitSignalAgentBead != "" {
 			fmt.Printf("%s Awaiting signal (timeout: %v, idle: %d)...\n",
 				style.Dim.Render("⏳"), timeout, idleCycles)
@@ -152,7 +150,7 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 	if result.Reason == "timeout" && awaitSignalAgentBead != "" {
 		newIdleCycles := idleCycles + 1
 		if err := setAgentIdleCycles(awaitSignalAgentBead, beadsDir, newIdleCycles); err != nil {
-			if !awaitSignalQuiet {
+			if !output.IsQuiet() {
 				fmt.Printf("%s Failed to update agent bead idle count: %v\n",
 					style.Dim.Render("⚠"), err)
 			}
@@ -171,7 +169,7 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 		return enc.Encode(result)
 	}
 
-	if !awaitSignalQuiet {
+	if !output.IsQuiet() {
 		switch result.Reason {
 		case "signal":
 			fmt.Printf("%s Signal received after %v\n",
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/molruns"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -96,6 +97,8 @@ func runMoleculeBurn(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("detaching molecule: %w", err)
 	}
 
+	updateMoleculeRun(townRoot, moleculeID, func(r *molruns.Run) { r.Finish(molruns.StatusBurned, time.Now()) })
+
 	if moleculeJSON {
 		result := map[string]interface{}{
 			"burned":          moleculeID,
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var moleculeLintMaxTokens int
+
+var moleculeLintCmd = &cobra.Command{
+	Use:   "lint <file|formula>...",
+	Short: "Check formulas against best-practice rules",
+	Long: `Check formula files for problems that parse but make poor molecules.
+
+Rules:
+  invalid        the formula fails validation (error)
+  orphan-step    a step that needs nothing and nothing needs (warning)
+  missing-tier   a mechanical step (commit, push, tag, bump, ...) with no
+                 tier, so it runs on the default model (warning)
+  long-prompt    a step's title and description over --max-step-tokens
+                 (warning)
+  undefined-var  a {{var}} in a step that [vars] doesn't define (warning)
+
+Steps whose text uses block helpers ({{#each}}, {{if}}, ...) are report
+templates the agent fills in, and aren't checked for variables. Arguments
+are paths, or formula names looked up like gt formula run does.
+
+Exits 1 if any finding is an error.
+
+Examples:
+  gt mol lint .beads/formulas/release.formula.toml
+  gt mol lint release --json
+  gt mol lint *.formula.toml --max-step-tokens 2000`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMoleculeLint,
+}
+
+func init() {
+	moleculeLintCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+	moleculeLintCmd.Flags().IntVar(&moleculeLintMaxTokens, "max-step-tokens", formula.DefaultMaxStepTokens, "Step prompt size to warn above")
+	moleculeCmd.AddCommand(moleculeLintCmd)
+}
+
+// moleculeLintResult is the findings for one file.
+type moleculeLintResult struct {
+	File     string            `json:"file"`
+	Findings []formula.Finding `json:"findings"`
+}
+
+func runMoleculeLint(cmd *cobra.Command, args []string) error {
+	var results []moleculeLintResult
+	failed := 0
+	for _, arg := range args {
+		path := arg
+		if _, err := os.Stat(path); err != nil {
+			if path, err = findFormulaFile(arg); err != nil {
+				return err
+			}
+		}
+		findings, err := lintFormulaFile(path)
+		if err != nil {
+			return err
+		}
+		for _, f := range findings {
+			if f.Severity == formula.SeverityError {
+				failed++
+			}
+		}
+		if findings == nil {
+			findings = []formula.Finding{}
+		}
+		results = append(results, moleculeLintResult{File: path, Findings: findings})
+	}
+
+	if moleculeJSON {
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			if len(r.Findings) == 0 {
+				fmt.Printf("%s %s\n", style.Success.Render("✓"), r.File)
+				continue
+			}
+			for _, f := range r.Findings {
+				mark := style.Warning.Render("⚠")
+				if f.Severity == formula.SeverityError {
+					mark = style.Error.Render("✗")
+				}
+				fmt.Printf("%s %s: %s\n", mark, r.File, f)
+			}
+		}
+	}
+	if failed > 0 {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// lintFormulaFile lints one formula file. A file that isn't TOML is an
+// error finding, not a failure, so the other files are still linted.
+func lintFormulaFile(path string) ([]formula.Finding, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the user's argument
+	if err != nil {
+		return nil, err
+	}
+	f, err := formula.Decode(data)
+	if err != nil {
+		return []formula.Finding{{Rule: formula.RuleInvalid, Severity: formula.SeverityError, Message: err.Error()}}, nil
+	}
+	return f.Lint(moleculeLintMaxTokens), nil
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	molMigrateYes    bool
+	molMigrateDryRun bool
+)
+
+var moleculeMigrateRunsCmd = &cobra.Command{
+	Use:   "migrate-runs [formula]",
+	Short: "Move in-flight runs to their formula's new version",
+	Long: `Offer to move running molecules onto the current version of their
+formula, after the formula (builtin or your own) has changed.
+
+Each run records the formula version it was poured from. A run can move
+when its progress carries over: every step done or in progress is still in
+the new version, and nothing new comes before a done step. Moving it adds
+the new steps, closes the dropped ones and rewrites the open ones with the
+new text and dependencies, in place; the agent carries on from where it
+was.
+
+A run that can't move is pinned: it finishes on the version it started
+with, which gt keeps a copy of in .runtime/molecule-runs/formulas/.
+
+Bump the formula's version field when you change it; runs recorded
+without a version are left alone.
+
+Examples:
+  gt mol migrate-runs                      # Every formula, asking per run
+  gt mol migrate-runs mol-polecat-work --dry-run
+  gt mol migrate-runs --yes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMoleculeMigrateRuns,
+}
+
+func init() {
+	moleculeMigrateRunsCmd.Flags().BoolVarP(&molMigrateYes, "yes", "y", false, "Move every compatible run without asking")
+	moleculeMigrateRunsCmd.Flags().BoolVarP(&molMigrateDryRun, "dry-run", "n", false, "Show what would move or be pinned")
+	moleculeCmd.AddCommand(moleculeMigrateRunsCmd)
+}
+
+func runMoleculeMigrateRuns(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	runs, err := molruns.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing molecule runs: %w", err)
+	}
+
+	current := make(map[string]*formula.Formula) // nil when not found
+	considered := 0
+	for _, r := range runs {
+		if r.Status != molruns.StatusRunning || r.Version == 0 || r.Pinned {
+			continue
+		}
+		if len(args) == 1 && r.Formula != args[0] {
+			continue
+		}
+		f, seen := current[r.Formula]
+		if !seen {
+			if path, err := findFormulaFile(r.Formula); err == nil {
+				if f, err = formula.ParseFile(path); err != nil {
+					style.PrintWarning("%s: %v", r.Formula, err)
+				}
+			}
+			current[r.Formula] = f
+		}
+		if f == nil || f.Version <= r.Version {
+			continue
+		}
+		considered++
+		if err := migrateRun(townRoot, r, f); err != nil {
+			style.PrintWarning("%s: %v", r.ID, err)
+		}
+	}
+	if considered == 0 {
+		fmt.Printf("%s No running molecules are behind their formula\n", style.Dim.Render("○"))
+	}
+	return nil
+}
+
+// migrateRun moves one run to version f of its formula if its progress
+// carries over and the user agrees, and pins it otherwise.
+func migrateRun(townRoot string, r *molruns.Run, f *formula.Formula) error {
+	b := beads.New(beads.ResolveHookDir(townRoot, r.ID, ""))
+	steps, err := b.List(beads.ListOptions{Parent: r.ID, Status: "all", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("listing molecule steps: %w", err)
+	}
+	var poured []formula.PouredStep
+	for _, s := range steps {
+		if ref := beads.StepRef(s); ref != "" {
+			poured = append(poured, formula.PouredStep{
+				Ref:     ref,
+				Done:    s.Status == "closed",
+				Started: s.Status == "in_progress" || s.Status == "hooked",
+			})
+		}
+	}
+
+	name := fmt.Sprintf("%s (%s v%d → v%d)", r.ID, r.Formula, r.Version, f.Version)
+	plan, err := f.PlanMigration(poured)
+	if err != nil {
+		fmt.Printf("%s %s: can't move: %s; pinned to v%d\n", style.Bold.Render("📌"), name, err, r.Version)
+		if molMigrateDryRun {
+			return nil
+		}
+		return molruns.Update(townRoot, r.ID, func(r *molruns.Run) { r.Pinned = true })
+	}
+
+	fmt.Printf("%s %s: %s\n", style.Bold.Render("↑"), name, describeMigration(plan))
+	if molMigrateDryRun {
+		return nil
+	}
+	if !molMigrateYes && !promptYesNo("  Move it?") {
+		return nil
+	}
+	if err := applyMigration(b, r, f, plan, steps); err != nil {
+		return err
+	}
+	if err := molruns.Update(townRoot, r.ID, func(run *molruns.Run) {
+		run.MigratedFrom, run.Version = run.Version, f.Version
+	}); err != nil {
+		return err
+	}
+	recordFormulaVersion(townRoot, &molruns.Run{Formula: r.Formula}) // Snapshot the new version
+	fmt.Printf("  %s Moved to v%d\n", style.Success.Render("✓"), f.Version)
+	return nil
+}
+
+// describeMigration summarizes a plan as "+a -b ~c".
+func describeMigration(m *formula.Migration) string {
+	if m.Empty() {
+		return "no remaining steps change"
+	}
+	var parts []string
+	for _, s := range m.Add {
+		parts = append(parts, "+"+s.ID)
+	}
+	for _, ref := range m.Remove {
+		parts = append(parts, "-"+ref)
+	}
+	for _, s := range m.Update {
+		parts = append(parts, "~"+s.ID)
+	}
+	return strings.Join(parts, " ")
+}
+
+// applyMigration rewrites a molecule's step beads to match the plan:
+// dropped steps are closed, open steps get the new text, new steps are
+// created, and the dependencies among them are set to the new needs.
+func applyMigration(b *beads.Beads, r *molruns.Run, f *formula.Formula, plan *formula.Migration, steps []*beads.Issue) error {
+	vars := make(map[string]string, len(r.Vars))
+	for _, v := range r.Vars {
+		if k, val, ok := strings.Cut(v, "="); ok {
+			vars[k] = val
+		}
+	}
+	byRef := make(map[string]*beads.Issue)
+	proto := ""
+	for _, s := range steps {
+		if ref := beads.StepRef(s); ref != "" {
+			byRef[ref] = s
+		}
+		if proto == "" {
+			proto = provenanceField(s.Description, "instantiated_from")
+		}
+	}
+	describe := func(step formula.Step) string {
+		desc := beads.ExpandTemplateVars(step.Description, vars)
+		if desc != "" {
+			desc += "\n\n"
+		}
+		desc += fmt.Sprintf("instantiated_from: %s\nstep: %s", proto, step.ID)
+		if step.Tier != "" {
+			desc += "\ntier: " + step.Tier
+		}
+		return desc
+	}
+
+	var removed []string
+	for _, ref := range plan.Remove {
+		removed = append(removed, byRef[ref].ID)
+	}
+	if err := b.CloseWithReason(fmt.Sprintf("removed in %s v%d", f.Name, f.Version), removed...); err != nil {
+		return fmt.Errorf("closing dropped steps: %w", err)
+	}
+	for _, step := range plan.Update {
+		title, desc := beads.ExpandTemplateVars(step.Title, vars), describe(step)
+		if err := b.Update(byRef[step.ID].ID, beads.UpdateOptions{Title: &title, Description: &desc}); err != nil {
+			return fmt.Errorf("updating step %s: %w", step.ID, err)
+		}
+	}
+	for _, step := range plan.Add {
+		issue, err := b.Create(beads.CreateOptions{
+			Title:       beads.ExpandTemplateVars(step.Title, vars),
+			Type:        "task",
+			Description: describe(step),
+			Parent:      r.ID,
+			Ephemeral:   true,
+		})
+		if err != nil {
+			return fmt.Errorf("creating step %s: %w", step.ID, err)
+		}
+		byRef[step.ID] = issue
+	}
+
+	siblings := make(map[string]bool, len(byRef))
+	for _, s := range byRef {
+		siblings[s.ID] = true
+	}
+	for _, step := range append(plan.Update, plan.Add...) {
+		issue := byRef[step.ID]
+		want := make(map[string]bool)
+		for _, need := range step.Needs {
+			want[byRef[need].ID] = true
+		}
+		for _, dep := range issue.DependsOn {
+			if siblings[dep] && !want[dep] {
+				if err := b.RemoveDependency(issue.ID, dep); err != nil {
+					return fmt.Errorf("updating step %s: %w", step.ID, err)
+				}
+			}
+			delete(want, dep)
+		}
+		for dep := range want {
+			if err := b.AddDependency(issue.ID, dep); err != nil {
+				return fmt.Errorf("updating step %s: %w", step.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// provenanceField returns a "key: value" line's value from a step's
+// description, or "".
+func provenanceField(description, key string) string {
+	for _, line := range strings.Split(description, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), key+":"); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
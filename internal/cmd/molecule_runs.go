@@ -0,0 +1,480 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	molRunsJSON   bool
+	molRunsAll    bool
+	molRunsStatus string
+	molRunsLimit  int
+
+	molShowRunJSON bool
+
+	molReplayFromStep string
+	molReplayTo       string
+	molReplayDryRun   bool
+)
+
+var moleculeRunsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "List recorded molecule runs",
+	Long: `List molecule runs, newest first: the formula, who ran it, how far it got
+and how it ended.
+
+Every molecule slung with 'gt sling' is recorded with its variables, its
+assigned agent and the timing and outcome of each step, in
+<town>/.runtime/molecule-runs/.
+
+Examples:
+  gt mol runs
+  gt mol runs --status failed
+  gt mol runs --all --json`,
+	Args: cobra.NoArgs,
+	RunE: runMoleculeRuns,
+}
+
+var moleculeShowRunCmd = &cobra.Command{
+	Use:   "show-run <id>",
+	Short: "Show a molecule run's variables and step timings",
+	Long: `Show one recorded molecule run: the formula and variables it was slung
+with, the agent it ran on, and each step's agent, duration and outcome.
+
+Examples:
+  gt mol show-run gt-wisp-abc
+  gt mol show-run gt-wisp-abc --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMoleculeShowRun,
+}
+
+var moleculeReplayCmd = &cobra.Command{
+	Use:   "replay <id>",
+	Short: "Re-run a molecule with the same formula and variables",
+	Long: `Sling a recorded run's formula again with the same variables (or to the
+same bead, for a formula applied with --on).
+
+With --from-step, every step the chosen one depends on is closed as
+skipped before the new molecule is hooked, so the agent starts at that
+step. A step is chosen by its formula step ID (test), its position (4),
+its title, or its bead ID in the original run.
+
+The new run goes to the original rig (a fresh polecat) unless --to names
+another target.
+
+Examples:
+  gt mol replay gt-wisp-abc
+  gt mol replay gt-wisp-abc --from-step run-tests
+  gt mol replay gt-wisp-abc --from-step 4 --to greenplace/Toast`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMoleculeReplay,
+}
+
+func init() {
+	moleculeRunsCmd.Flags().BoolVar(&molRunsJSON, "json", false, "Output as JSON")
+	moleculeRunsCmd.Flags().BoolVar(&molRunsAll, "all", false, "Show all runs (default: last 20)")
+	moleculeRunsCmd.Flags().StringVar(&molRunsStatus, "status", "", "Only runs with this status (running, complete, failed, burned)")
+	moleculeRunsCmd.Flags().IntVarP(&molRunsLimit, "limit", "n", 20, "Maximum number of runs to show")
+
+	moleculeShowRunCmd.Flags().BoolVar(&molShowRunJSON, "json", false, "Output as JSON")
+
+	moleculeReplayCmd.Flags().StringVar(&molReplayFromStep, "from-step", "", "Start at this step, skipping the steps it depends on")
+	moleculeReplayCmd.Flags().StringVar(&molReplayTo, "to", "", "Sling to this target instead of the original rig")
+	moleculeReplayCmd.Flags().BoolVarP(&molReplayDryRun, "dry-run", "n", false, "Show what would be slung")
+
+	moleculeCmd.AddCommand(moleculeRunsCmd)
+	moleculeCmd.AddCommand(moleculeShowRunCmd)
+	moleculeCmd.AddCommand(moleculeReplayCmd)
+}
+
+func runMoleculeRuns(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	runs, err := molruns.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing molecule runs: %w", err)
+	}
+	if molRunsStatus != "" {
+		var filtered []*molruns.Run
+		for _, r := range runs {
+			if r.Status == molRunsStatus {
+				filtered = append(filtered, r)
+			}
+		}
+		runs = filtered
+	}
+	if !molRunsAll && molRunsLimit > 0 && len(runs) > molRunsLimit {
+		runs = runs[:molRunsLimit]
+	}
+
+	if molRunsJSON {
+		if runs == nil {
+			runs = []*molruns.Run{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(runs)
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("No molecule runs recorded")
+		return nil
+	}
+	for _, r := range runs {
+		done := 0
+		for _, s := range r.Steps {
+			if s.Outcome == molruns.OutcomeDone || s.Outcome == molruns.OutcomeSkipped {
+				done++
+			}
+		}
+		fmt.Printf("%s %s  %s  %s\n", runStatusIcon(r.Status), r.ID, r.Formula, style.Dim.Render(r.Target))
+		detail := fmt.Sprintf("%s, %d step(s) done, %s, started %s", r.Status, done,
+			r.Duration().Round(time.Second), r.Started.Local().Format("2006-01-02 15:04"))
+		if s := r.FailedStep(); s != nil {
+			detail += ", failed at " + runStepName(*s)
+		}
+		if r.ReplayOf != "" {
+			detail += ", replay of " + r.ReplayOf
+		}
+		fmt.Printf("    %s\n", style.Dim.Render(detail))
+	}
+	return nil
+}
+
+func runMoleculeShowRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	r, err := molruns.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+
+	if molShowRunJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+
+	fmt.Printf("%s %s: %s\n\n", runStatusIcon(r.Status), r.ID, r.Formula)
+	fmt.Printf("  Status:  %s\n", r.Status)
+	if r.Version > 0 {
+		version := fmt.Sprintf("v%d", r.Version)
+		switch {
+		case r.Pinned:
+			version += " (pinned)"
+		case r.MigratedFrom > 0:
+			version += fmt.Sprintf(" (moved from v%d)", r.MigratedFrom)
+		}
+		fmt.Printf("  Version: %s\n", version)
+	}
+	fmt.Printf("  Target:  %s\n", r.Target)
+	if r.Bead != "" {
+		fmt.Printf("  Bead:    %s\n", r.Bead)
+	}
+	if r.Actor != "" {
+		fmt.Printf("  Slung by: %s\n", r.Actor)
+	}
+	fmt.Printf("  Started: %s\n", r.Started.Local().Format("2006-01-02 15:04:05"))
+	if !r.Finished.IsZero() {
+		fmt.Printf("  Ended:   %s (%s)\n", r.Finished.Local().Format("2006-01-02 15:04:05"), r.Duration().Round(time.Second))
+	}
+	if r.ReplayOf != "" {
+		from := ""
+		if r.FromStep != "" {
+			from = " from step " + r.FromStep
+		}
+		fmt.Printf("  Replay:  of %s%s\n", r.ReplayOf, from)
+	}
+	if len(r.Vars) > 0 {
+		fmt.Printf("\n  Variables:\n")
+		for _, v := range r.Vars {
+			fmt.Printf("    %s\n", v)
+		}
+	}
+
+	fmt.Printf("\n  Steps:\n")
+	if len(r.Steps) == 0 {
+		fmt.Printf("    %s\n", style.Dim.Render("(none started)"))
+	}
+	for _, s := range r.Steps {
+		icon := style.Dim.Render("○")
+		switch s.Outcome {
+		case molruns.OutcomeDone:
+			icon = style.Bold.Render("✓")
+		case molruns.OutcomeFailed:
+			icon = style.Error.Render("✗")
+		case "":
+			icon = style.Warning.Render("▶")
+		}
+		line := fmt.Sprintf("    %s %s  %s", icon, s.ID, runStepName(s))
+		var detail []string
+		if s.Outcome == molruns.OutcomeSkipped {
+			detail = append(detail, "skipped")
+		} else if !s.Started.IsZero() {
+			detail = append(detail, s.Duration().Round(time.Second).String())
+		}
+		if s.Agent != "" {
+			detail = append(detail, s.Agent)
+		}
+		if len(detail) > 0 {
+			line += "  " + style.Dim.Render("("+strings.Join(detail, ", ")+")")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runMoleculeReplay(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	r, err := molruns.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if r.Status == molruns.StatusRunning && !molReplayDryRun {
+		return fmt.Errorf("run %s is still running: burn it or wait for it to end before replaying", r.ID)
+	}
+
+	target := molReplayTo
+	if target == "" {
+		target = r.Rig
+	}
+	if target == "" {
+		target = r.Target
+	}
+
+	// A step named by its bead in the original run is looked up in the new
+	// molecule by its formula step ID, which instantiation keeps
+	fromStep := molReplayFromStep
+	for _, s := range r.Steps {
+		if s.ID == fromStep {
+			fromStep = s.Ref
+			if fromStep == "" {
+				fromStep = s.Title
+			}
+			break
+		}
+	}
+
+	slingArgs := []string{"sling", r.Formula}
+	if r.Bead != "" {
+		slingArgs = append(slingArgs, "--on", r.Bead)
+	} else {
+		for _, v := range r.Vars {
+			slingArgs = append(slingArgs, "--var", v)
+		}
+	}
+	slingArgs = append(slingArgs, target, "--replay-of", r.ID)
+	if fromStep != "" {
+		slingArgs = append(slingArgs, "--from-step", fromStep)
+	}
+	if molReplayDryRun {
+		slingArgs = append(slingArgs, "--dry-run")
+	}
+
+	fmt.Printf("%s Replaying %s (%s) on %s\n", style.Bold.Render("🔁"), r.ID, r.Formula, target)
+	replayCmd := exec.Command("gt", slingArgs...)
+	replayCmd.Stdin = os.Stdin
+	replayCmd.Stdout = os.Stdout
+	replayCmd.Stderr = os.Stderr
+	return replayCmd.Run()
+}
+
+func runStatusIcon(status string) string {
+	switch status {
+	case molruns.StatusComplete:
+		return style.Bold.Render("✓")
+	case molruns.StatusFailed:
+		return style.Error.Render("✗")
+	case molruns.StatusBurned:
+		return style.Dim.Render("🔥")
+	default:
+		return style.Warning.Render("▶")
+	}
+}
+
+// runStepName is how a step is shown: its formula step ID and title.
+func runStepName(s molruns.Step) string {
+	switch {
+	case s.Ref != "" && s.Title != "":
+		return s.Ref + ": " + s.Title
+	case s.Ref != "":
+		return s.Ref
+	case s.Title != "":
+		return s.Title
+	}
+	return s.ID
+}
+
+// startMoleculeRun records a molecule that sling just instantiated. For a
+// replay (--replay-of), the steps before --from-step are closed first, in
+// workDir's beads, so the agent starts at the chosen step.
+func startMoleculeRun(townRoot, workDir string, run *molruns.Run) error {
+	run.ReplayOf, run.FromStep = slingReplayOf, slingFromStep
+	run.Actor = detectActor()
+	run.Started = time.Now()
+	if slingFromStep != "" {
+		b := beads.New(workDir)
+		skipped, err := skipStepsBefore(b, run.ID, slingFromStep)
+		if err != nil {
+			return err
+		}
+		if len(skipped) > 0 {
+			ids := make([]string, 0, len(skipped))
+			for _, s := range skipped {
+				ids = append(ids, s.ID)
+			}
+			reason := fmt.Sprintf("skipped: replay of %s from step %s", slingReplayOf, slingFromStep)
+			if err := b.CloseWithReason(reason, ids...); err != nil {
+				return fmt.Errorf("closing skipped steps: %w", err)
+			}
+			for _, s := range skipped {
+				run.Steps = append(run.Steps, molruns.Step{ID: s.ID, Ref: beads.StepRef(s), Title: s.Title,
+					Started: run.Started, Finished: run.Started, Outcome: molruns.OutcomeSkipped})
+			}
+			fmt.Printf("%s Skipped %d step(s) before %s\n", style.Bold.Render("✓"), len(skipped), slingFromStep)
+		}
+	}
+	if agent := strings.SplitN(run.Target, "/", 2); len(agent) == 2 {
+		if rigName, ok := IsRigName(agent[0]); ok {
+			run.Rig = rigName
+		}
+	}
+	recordFormulaVersion(townRoot, run)
+	if err := molruns.Start(townRoot, run); err != nil {
+		style.PrintWarning("could not record molecule run: %v", err)
+	}
+	return nil
+}
+
+// recordFormulaVersion sets the run's formula version and keeps a snapshot
+// of that version's source for gt mol migrate-runs. A formula gt can't
+// find (one bd resolves elsewhere) leaves the version unknown.
+func recordFormulaVersion(townRoot string, run *molruns.Run) {
+	path, err := findFormulaFile(run.Formula)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the formula search paths
+	if err != nil {
+		return
+	}
+	f, err := formula.Decode(data)
+	if err != nil || f.Version == 0 {
+		return
+	}
+	run.Version = f.Version
+	if err := molruns.SnapshotFormula(townRoot, run.Formula, f.Version, data); err != nil {
+		style.PrintWarning("could not snapshot formula %s v%d: %v", run.Formula, f.Version, err)
+	}
+}
+
+// skipStepsBefore returns the steps of a molecule that the step chosen by
+// sel depends on.
+func skipStepsBefore(b *beads.Beads, moleculeID, sel string) ([]*beads.Issue, error) {
+	steps, err := b.List(beads.ListOptions{Parent: moleculeID, Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing molecule steps: %w", err)
+	}
+	from := matchStep(steps, sel)
+	if from == nil {
+		return nil, fmt.Errorf("molecule %s has no step %q", moleculeID, sel)
+	}
+	return stepAncestors(steps, from), nil
+}
+
+// stepAncestors returns the steps that from depends on, directly or not,
+// in step order.
+func stepAncestors(steps []*beads.Issue, from *beads.Issue) []*beads.Issue {
+	byID := make(map[string]*beads.Issue, len(steps))
+	for _, s := range steps {
+		byID[s.ID] = s
+	}
+	seen := map[string]bool{}
+	var ancestors []*beads.Issue
+	var visit func(*beads.Issue)
+	visit = func(s *beads.Issue) {
+		for _, dep := range s.DependsOn {
+			if d, ok := byID[dep]; ok && !seen[dep] {
+				seen[dep] = true
+				ancestors = append(ancestors, d)
+				visit(d)
+			}
+		}
+	}
+	visit(from)
+	sort.Slice(ancestors, func(i, j int) bool { return stepNumber(ancestors[i].ID) < stepNumber(ancestors[j].ID) })
+	return ancestors
+}
+
+// matchStep finds the step sel names: a bead ID, a position, a formula
+// step ID or a title.
+func matchStep(steps []*beads.Issue, sel string) *beads.Issue {
+	for _, match := range []func(*beads.Issue) bool{
+		func(s *beads.Issue) bool { return s.ID == sel },
+		func(s *beads.Issue) bool { return strconv.Itoa(stepNumber(s.ID)) == sel },
+		func(s *beads.Issue) bool { return beads.StepRef(s) == sel },
+		func(s *beads.Issue) bool { return strings.EqualFold(s.Title, sel) },
+	} {
+		for _, s := range steps {
+			if match(s) {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// stepNumber is a step's position from its ID (gt-abc.3 is 3), or 0.
+func stepNumber(stepID string) int {
+	n, _ := strconv.Atoi(stepID[strings.LastIndex(stepID, ".")+1:])
+	return n
+}
+
+// updateMoleculeRun applies fn to a molecule's recorded run, if it has one.
+func updateMoleculeRun(townRoot, moleculeID string, fn func(*molruns.Run)) {
+	if err := molruns.Update(townRoot, moleculeID, fn); err != nil && !errors.Is(err, molruns.ErrNotFound) {
+		style.PrintWarning("could not update molecule run %s: %v", moleculeID, err)
+	}
+}
+
+// finishMoleculeRunOnDone ends the run of the molecule on an agent's hook
+// when the agent runs gt done: escalating fails it at the current step.
+// The run's retro records the agent's summary and its merge request.
+func finishMoleculeRunOnDone(townRoot, hookedBeadID, exitType, summary, mrID string) {
+	status := molruns.StatusComplete
+	switch exitType {
+	case ExitEscalated:
+		status = molruns.StatusFailed
+	case ExitCompleted:
+	default:
+		return
+	}
+	run, err := molruns.FindByHook(townRoot, hookedBeadID)
+	if err != nil || run == nil {
+		return
+	}
+	updateMoleculeRun(townRoot, run.ID, func(r *molruns.Run) { r.Finish(status, time.Now()) })
+	recordRunRetro(townRoot, run.ID, summary, mrID)
+}
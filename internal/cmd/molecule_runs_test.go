@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestStepAncestors(t *testing.T) {
+	steps := []*beads.Issue{
+		{ID: "gt-wisp.1", Title: "Load context", Description: "step: load-context"},
+		{ID: "gt-wisp.2", Title: "Set up branch", Description: "step: branch-setup", DependsOn: []string{"gt-wisp.1"}},
+		{ID: "gt-wisp.3", Title: "Write docs", Description: "step: docs", DependsOn: []string{"gt-wisp.1"}},
+		{ID: "gt-wisp.4", Title: "Implement", Description: "step: implement", DependsOn: []string{"gt-wisp.2", "gt-wisp"}},
+		{ID: "gt-wisp.10", Title: "Run tests", Description: "step: run-tests", DependsOn: []string{"gt-wisp.4"}},
+	}
+
+	for sel, want := range map[string]string{
+		"gt-wisp.4": "gt-wisp.4",
+		"10":        "gt-wisp.10",
+		"run-tests": "gt-wisp.10",
+		"run tests": "gt-wisp.10",
+		"deploy":    "",
+	} {
+		got := ""
+		if s := matchStep(steps, sel); s != nil {
+			got = s.ID
+		}
+		if got != want {
+			t.Errorf("matchStep(%q) = %q, want %q", sel, got, want)
+		}
+	}
+
+	var ids []string
+	for _, s := range stepAncestors(steps, steps[4]) {
+		ids = append(ids, s.ID)
+	}
+	// Only what run-tests depends on is skipped: not the docs step, and
+	// not the molecule root
+	if want := []string{"gt-wisp.1", "gt-wisp.2", "gt-wisp.4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ancestors = %v, want %v", ids, want)
+	}
+}
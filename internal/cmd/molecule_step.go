@@ -5,13 +5,21 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/molruns"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // moleculeStepDoneCmd is the "gt mol step done" command.
@@ -28,6 +36,8 @@ This command handles the step-to-step transition for polecats:
 4. If next step exists:
    - Updates the hook to point to the next step
    - Respawns the pane for a fresh session
+   - If it is a "Gate: human" step, stops instead and waits for
+     'gt approve' or 'gt reject' (see gt review)
 5. If molecule complete:
    - Clears the hook
    - Sends POLECAT_DONE to witness
@@ -43,12 +53,14 @@ Example:
 }
 
 var (
-	moleculeStepDryRun bool
+	moleculeStepDryRun  bool
+	moleculeStepSummary string
 )
 
 func init() {
 	moleculeStepDoneCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
 	moleculeStepDoneCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+	moleculeStepDoneCmd.Flags().StringVar(&moleculeStepSummary, "summary", "", "Short summary of the work, for the run's retro when this is the last step")
 }
 
 // StepDoneResult is the result of a step done operation.
@@ -59,7 +71,7 @@ type StepDoneResult struct {
 	NextStepID   string `json:"next_step_id,omitempty"`
 	NextStepTitle string `json:"next_step_title,omitempty"`
 	Complete     bool   `json:"complete"`
-	Action       string `json:"action"` // "continue", "done", "no_more_ready"
+	Action       string `json:"action"` // "continue", "awaiting_approval", "done", "no_more_ready"
 }
 
 func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
@@ -99,6 +111,17 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot extract molecule ID from step %s (expected format: gt-xxx.N)", stepID)
 	}
 
+	// Approval gates are closed by a human, never by the agent
+	if beads.StepGate(step) == beads.GateHuman {
+		return fmt.Errorf("step %s is a human approval gate: it closes when a human runs 'gt approve %s %s'", stepID, moleculeID, stepID)
+	}
+
+	// Declared outputs are how later steps, in fresh sessions, get this
+	// step's results: don't close without them
+	if missing := missingStepOutputs(townRoot, moleculeID, step); len(missing) > 0 {
+		return fmt.Errorf("step %s must store %s before it closes: gt artifacts put <name> <file>", stepID, strings.Join(missing, ", "))
+	}
+
 	result := StepDoneResult{
 		StepID:     stepID,
 		MoleculeID: moleculeID,
@@ -109,11 +132,19 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		fmt.Printf("[dry-run] Would close step: %s\n", stepID)
 		result.StepClosed = true
 	} else {
+		span := startStepSpan(moleculeID, step)
 		if err := b.Close(stepID); err != nil {
+			span.End(err)
 			return fmt.Errorf("closing step: %w", err)
 		}
+		span.End(nil)
+		_ = transcript.MarkCurrent(transcript.StepEnd, stepID, step.Title)
 		result.StepClosed = true
 		fmt.Printf("%s Closed step %s: %s\n", style.Bold.Render("✓"), stepID, step.Title)
+		_ = events.LogFeed(events.TypeStepComplete, detectActor(), events.StepPayload(moleculeID, stepID, step.Title))
+		updateMoleculeRun(townRoot, moleculeID, func(r *molruns.Run) {
+			r.FinishStep(stepID, beads.StepRef(step), step.Title, detectActor(), molruns.OutcomeDone, time.Now())
+		})
 	}
 
 	// Step 4: Find the next ready step
@@ -129,6 +160,9 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		result.NextStepID = nextStep.ID
 		result.NextStepTitle = nextStep.Title
 		result.Action = "continue"
+		if beads.StepGate(nextStep) == beads.GateHuman {
+			result.Action = "awaiting_approval"
+		}
 	} else {
 		// There are more steps but none are ready (blocked on dependencies)
 		result.Action = "no_more_ready"
@@ -144,10 +178,30 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	// Step 5: Handle next action
 	switch result.Action {
 	case "continue":
+		if !moleculeStepDryRun {
+			_ = transcript.MarkCurrent(transcript.StepBegin, nextStep.ID, nextStep.Title)
+			beginRunStep(townRoot, moleculeID, nextStep)
+			recordStepCheckpoint(cwd, townRoot, moleculeID, nextStep)
+		}
 		return handleStepContinue(cwd, townRoot, workDir, nextStep, moleculeStepDryRun)
 
+	case "awaiting_approval":
+		if !moleculeStepDryRun {
+			beginRunStep(townRoot, moleculeID, nextStep)
+			recordStepCheckpoint(cwd, townRoot, moleculeID, nextStep)
+		}
+		return handleAwaitApproval(b, cwd, townRoot, moleculeID, nextStep, moleculeStepDryRun)
+
 	case "done":
-		return handleMoleculeComplete(cwd, townRoot, moleculeID, moleculeStepDryRun)
+		if !moleculeStepDryRun {
+			updateMoleculeRun(townRoot, moleculeID, func(r *molruns.Run) { r.Finish(molruns.StatusComplete, time.Now()) })
+			recordRunRetro(townRoot, moleculeID, moleculeStepSummary, "")
+			recordStepCheckpoint(cwd, townRoot, moleculeID, nil)
+		}
+		span := telemetry.Start("molecule.complete", attribute.String("molecule.id", moleculeID))
+		err := handleMoleculeComplete(cwd, townRoot, moleculeID, moleculeStepDryRun)
+		span.End(err)
+		return err
 
 	case "no_more_ready":
 		fmt.Printf("\n%s All remaining steps are blocked - waiting on dependencies\n",
@@ -159,6 +213,30 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// startStepSpan opens the trace span for a molecule step that is being
+// closed. Steps are worked on across many processes, so the span is
+// backdated to the step's last update (when it was claimed) to cover the
+// work rather than just the close.
+func startStepSpan(moleculeID string, step *beads.Issue) *telemetry.Span {
+	opts := []trace.SpanStartOption{trace.WithAttributes(
+		attribute.String("molecule.id", moleculeID),
+		attribute.String("step.id", step.ID),
+		attribute.String("step.title", step.Title),
+	)}
+	if started, err := time.Parse(time.RFC3339, step.UpdatedAt); err == nil {
+		opts = append(opts, trace.WithTimestamp(started))
+	}
+	return telemetry.StartWith("molecule.step "+step.Title, opts...)
+}
+
+// beginRunStep records in the molecule's run history that a step is
+// starting.
+func beginRunStep(townRoot, moleculeID string, step *beads.Issue) {
+	updateMoleculeRun(townRoot, moleculeID, func(r *molruns.Run) {
+		r.BeginStep(step.ID, beads.StepRef(step), step.Title, detectActor(), time.Now())
+	})
+}
+
 // extractMoleculeIDFromStep extracts the molecule ID from a step ID.
 // Step IDs have format: mol-id.N where N is the step number.
 // Examples:
@@ -249,15 +327,12 @@ func findNextReadyStep(b *beads.Beads, moleculeID string) (*beads.Issue, bool, e
 	return nil, false, nil
 }
 
-// handleStepContinue handles continuing to the next step.
-func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun bool) error { // workDir unused but kept for signature consistency
-	fmt.Printf("\n%s Next step: %s\n", style.Bold.Render("→"), nextStep.ID)
-	fmt.Printf("  %s\n", nextStep.Title)
-
-	// Detect agent identity
+// currentAgentID returns the identity of the agent running in cwd, in the
+// form used as a bead assignee.
+func currentAgentID(cwd, townRoot string) (string, error) {
 	roleInfo, err := GetRoleWithContext(cwd, townRoot)
 	if err != nil {
-		return fmt.Errorf("detecting role: %w", err)
+		return "", fmt.Errorf("detecting role: %w", err)
 	}
 
 	roleCtx := RoleContext{
@@ -269,7 +344,47 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 	}
 	agentID := buildAgentIdentity(roleCtx)
 	if agentID == "" {
-		return fmt.Errorf("cannot determine agent identity (role: %s)", roleCtx.Role)
+		return "", fmt.Errorf("cannot determine agent identity (role: %s)", roleCtx.Role)
+	}
+	return agentID, nil
+}
+
+// handleAwaitApproval parks the agent at a human approval gate. The gate is
+// assigned to the agent and labeled as awaiting, so 'gt approve' knows whom
+// to wake; the session then idles until the approval or rejection arrives.
+func handleAwaitApproval(b *beads.Beads, cwd, townRoot, moleculeID string, gate *beads.Issue, dryRun bool) error {
+	fmt.Printf("\n%s Next step needs human approval: %s\n", style.Bold.Render("✋"), gate.ID)
+	fmt.Printf("  %s\n", gate.Title)
+
+	agentID, err := currentAgentID(cwd, townRoot)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("\n[dry-run] Would mark %s as awaiting approval for %s\n", gate.ID, agentID)
+		return nil
+	}
+
+	if err := parkAtGate(b, moleculeID, gate, agentID); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s Waiting for a human to review. They will run one of:\n", style.Bold.Render("⏸"))
+	fmt.Printf("  gt approve %s %s\n", moleculeID, gate.ID)
+	fmt.Printf("  gt reject %s %s --feedback \"...\"\n", moleculeID, gate.ID)
+	fmt.Printf("\nStop here. You will get mail and a nudge with the decision.\n")
+	return nil
+}
+
+// handleStepContinue handles continuing to the next step.
+func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun bool) error { // workDir unused but kept for signature consistency
+	fmt.Printf("\n%s Next step: %s\n", style.Bold.Render("→"), nextStep.ID)
+	fmt.Printf("  %s\n", nextStep.Title)
+
+	agentID, err := currentAgentID(cwd, townRoot)
+	if err != nil {
+		return err
 	}
 
 	// Get git root for hook files
@@ -278,12 +393,34 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 		return fmt.Errorf("finding git root: %w", err)
 	}
 
+	// Review steps can ask for static analysis: run it now and attach the
+	// findings, so the fresh session starts the step with them
+	rigPath := ""
+	if rigName, _, err := findCurrentRig(townRoot); err == nil {
+		rigPath = filepath.Join(townRoot, rigName)
+	}
+	linters := stepLinters(nextStep, rigPath)
+	inputs := beads.StepInputs(nextStep)
+
 	if dryRun {
+		if len(linters) > 0 {
+			fmt.Printf("\n[dry-run] Would run linters: %s\n", strings.Join(linters, ", "))
+		}
+		if len(inputs) > 0 {
+			fmt.Printf("\n[dry-run] Would attach inputs: %s\n", strings.Join(inputs, ", "))
+		}
 		fmt.Printf("\n[dry-run] Would pin next step: %s\n", nextStep.ID)
 		fmt.Printf("[dry-run] Would respawn pane\n")
 		return nil
 	}
 
+	if len(linters) > 0 {
+		attachLintFindings(gitRoot, nextStep, linters)
+	}
+	if len(inputs) > 0 {
+		attachStepInputs(gitRoot, townRoot, extractMoleculeIDFromStep(nextStep.ID), nextStep, inputs)
+	}
+
 	// Pin the next step bead
 	pinCmd := exec.Command("bd", "update", nextStep.ID, "--status=pinned", "--assignee="+agentID)
 	pinCmd.Dir = gitRoot
@@ -362,6 +499,8 @@ func handleMoleculeComplete(cwd, townRoot, moleculeID string, dryRun bool) error
 		return nil
 	}
 
+	_ = events.LogFeed(events.TypeMoleculeComplete, agentID, events.MoleculePayload(moleculeID, roleCtx.Rig))
+
 	// Unpin the molecule bead (set status to open, will be closed by gt done or manually)
 	workDir, err := findLocalBeadsDir()
 	if err == nil {
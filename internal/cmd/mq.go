@@ -17,11 +17,13 @@ import (
 // MQ command flags
 var (
 	// Submit flags
-	mqSubmitBranch    string
-	mqSubmitIssue     string
-	mqSubmitEpic      string
-	mqSubmitPriority  int
-	mqSubmitNoCleanup bool
+	mqSubmitBranch     string
+	mqSubmitIssue      string
+	mqSubmitEpic       string
+	mqSubmitPriority   int
+	mqSubmitNoCleanup  bool
+	mqSubmitNoPremerge bool
+	mqSubmitNoQuality  bool
 
 	// Retry flags
 	mqRetryNow bool
@@ -88,6 +90,10 @@ Target branch auto-detection:
 
 This ensures batch work on epics automatically flows to integration branches.
 
+Quality gate:
+  In rigs with quality_gate enabled, the work is scored first (see gt
+  quality) and not submitted if it scores below min_score.
+
 Polecat auto-cleanup:
   When run from a polecat work branch (polecat/<worker>/<issue>), this command
   automatically triggers polecat shutdown after submitting the MR. The polecat
@@ -276,6 +282,8 @@ func init() {
 	mqSubmitCmd.Flags().StringVar(&mqSubmitEpic, "epic", "", "Target epic's integration branch instead of main")
 	mqSubmitCmd.Flags().IntVarP(&mqSubmitPriority, "priority", "p", -1, "Override priority (0-4, default: inherit from issue)")
 	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoCleanup, "no-cleanup", false, "Don't auto-cleanup after submit (for polecats)")
+	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoPremerge, "no-premerge", false, "Submit without checking that the branch rebases cleanly (see gt premerge)")
+	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoQuality, "no-quality-gate", false, "Submit without scoring the work against the rig's quality gate; not for polecats (see gt quality)")
 
 	// Retry flags
 	mqRetryCmd.Flags().BoolVar(&mqRetryNow, "now", false, "Immediately process instead of waiting for refinery loop")
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -14,7 +15,6 @@ import (
 var (
 	mqNextStrategy string // "priority" (default) or "fifo"
 	mqNextJSON     bool
-	mqNextQuiet    bool
 )
 
 var mqNextCmd = &cobra.Command{
@@ -42,7 +42,6 @@ Examples:
 func init() {
 	mqNextCmd.Flags().StringVar(&mqNextStrategy, "strategy", "priority", "Ordering strategy: 'priority' or 'fifo'")
 	mqNextCmd.Flags().BoolVar(&mqNextJSON, "json", false, "Output as JSON")
-	mqNextCmd.Flags().BoolVarP(&mqNextQuiet, "quiet", "q", false, "Just print the MR ID")
 
 	mqCmd.AddCommand(mqNextCmd)
 }
@@ -79,7 +78,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(ready) == 0 {
-		if mqNextQuiet {
+		if output.IsQuiet() {
 			return nil // Silent exit
 		}
 		fmt.Printf("%s No ready merge requests in queue\n", style.Dim.Render("ℹ"))
@@ -124,7 +123,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	fields := beads.ParseMRFields(next)
 
 	// Output based on format flags
-	if mqNextQuiet {
+	if output.IsQuiet() {
 		fmt.Println(next.ID)
 		return nil
 	}
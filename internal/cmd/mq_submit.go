@@ -32,8 +32,8 @@ type branchInfo struct {
 func parseBranchName(branch string) branchInfo {
 	info := branchInfo{Branch: branch}
 
-	// Try polecat/<worker>/<issue> format
-	if strings.HasPrefix(branch, constants.BranchPolecatPrefix) {
+	// Try polecat/<worker>/<issue> and crew/<worker>/<issue> formats
+	if strings.HasPrefix(branch, constants.BranchPolecatPrefix) || strings.HasPrefix(branch, constants.BranchCrewPrefix) {
 		parts := strings.SplitN(branch, "/", 3)
 		if len(parts) == 3 {
 			info.Worker = parts[1]
@@ -58,9 +58,14 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	if mqSubmitNoQuality {
+		if err := checkQualityGateSkip(townRoot); err != nil {
+			return err
+		}
+	}
 
 	// Find current rig
-	rigName, _, err := findCurrentRig(townRoot)
+	rigName, r, err := findCurrentRig(townRoot)
 	if err != nil {
 		return err
 	}
@@ -124,6 +129,18 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Surface conflicts now, while the submitter still has the context
+	if !mqSubmitNoPremerge {
+		if err := requireCleanRebase(g, filepath.Join(townRoot, rigName), branch, target, "gt mq submit"); err != nil {
+			return err
+		}
+	}
+	if !mqSubmitNoQuality {
+		if err := requireQuality(r, g, branch, target, townRoot, issueID, worker, "gt mq submit", false); err != nil {
+			return err
+		}
+	}
+
 	// Get source issue for priority inheritance
 	var priority int
 	if mqSubmitPriority >= 0 {
@@ -168,6 +185,7 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Worker: %s\n", worker)
 	}
 	fmt.Printf("  Priority: P%d\n", priority)
+	commentDiffStat(bd, g, issueID, branch, target)
 
 	// Auto-cleanup for polecats: if this is a polecat branch and cleanup not disabled,
 	// send lifecycle request and wait for termination
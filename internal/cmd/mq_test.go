@@ -68,6 +68,12 @@ func TestParseBranchName(t *testing.T) {
 			wantIssue:  "gt-abc.1",
 			wantWorker: "Worker",
 		},
+		{
+			name:       "crew branch format",
+			branch:     "crew/max/gt-xyz",
+			wantIssue:  "gt-xyz",
+			wantWorker: "max",
+		},
 		{
 			name:       "simple issue branch",
 			branch:     "gt-xyz",
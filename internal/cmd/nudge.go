@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/session"
@@ -199,6 +200,9 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Printf("%s Nudged %s/%s\n", style.Bold.Render("✓"), rigName, polecatName)
+		if !strings.HasPrefix(polecatName, "crew/") {
+			recordLastPrompt(rigName, polecatName, message)
+		}
 
 		// Log nudge event
 		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
@@ -231,6 +235,22 @@ func runNudge(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// recordLastPrompt notes a nudge in the polecat's checkpoint, so a session
+// restarted after a crash or reboot sees what it was last told.
+func recordLastPrompt(rigName, polecatName, message string) {
+	mgr, _, err := getPolecatManager(rigName)
+	if err != nil {
+		return
+	}
+	p, err := mgr.Get(polecatName)
+	if err != nil || p.ClonePath == "" {
+		return
+	}
+	_ = checkpoint.Update(p.ClonePath, func(cp *checkpoint.Checkpoint) {
+		cp.LastPrompt = message
+	})
+}
+
 // runNudgeChannel nudges all members of a named channel.
 func runNudgeChannel(channelName, message string) error {
 	// Find town root
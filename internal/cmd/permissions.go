@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/authz"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	permissionsRole string
+	permissionsJSON bool
+)
+
+var permissionsCmd = &cobra.Command{
+	Use:     "permissions",
+	GroupID: GroupConfig,
+	Short:   "Show which roles may run which commands",
+	Args:    cobra.NoArgs,
+	RunE:    runPermissions,
+	Long: `Show the command policy and who gt thinks you are.
+
+Town-shaping commands are restricted by role: a polecat can't run
+'gt rig remove' or change town config, the Mayor can. Commands that
+aren't listed are open to every role, and the overseer (you, outside any
+agent session) may run everything.
+
+Agents are spawned with a signed role token (GT_ROLE_TOKEN), which gt
+trusts over GT_ROLE. Without a token, anything that looks like an agent
+session (GT_ROLE or another agent variable, or a working directory under
+a rig's polecats/, witness/ or refinery/) gets the least privileged role,
+polecat. Only with none of these are you the overseer.
+Override rules in settings/config.json:
+
+  "permissions": {"commands": {"rig add": ["mayor", "crew"], "config": ["*"]}}
+
+The same roles scope the tools 'gt mcp serve' offers.
+
+Examples:
+  gt permissions
+  gt permissions --role polecat
+  gt permissions --json`,
+}
+
+func init() {
+	permissionsCmd.Flags().StringVar(&permissionsRole, "role", "", "Show what this role may run")
+	permissionsCmd.Flags().BoolVar(&permissionsJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(permissionsCmd)
+}
+
+// loadCommandPolicy returns the town's command policy.
+func loadCommandPolicy(townRoot string) authz.Policy {
+	var overrides map[string][]string
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.Permissions != nil {
+		overrides = settings.Permissions.Commands
+	}
+	return authz.NewPolicy(overrides)
+}
+
+// agentSessionEnv are environment variables agent sessions carry. Any of
+// them set means gt may be running inside one. TMUX isn't one: people use
+// tmux too.
+var agentSessionEnv = []string{EnvGTRole, "GT_SESSION", "GT_SESSION_ID", "GT_RIG", "GT_POLECAT", "GT_CREW"}
+
+// agentHomeDirs are the rig directories only agents work in. crew/ isn't
+// one: crew clones are people's workspaces too.
+var agentHomeDirs = []string{"polecats", "witness", "refinery"}
+
+// agentSessionSignal returns what suggests the caller is an agent: an
+// agent session variable, or a working directory in an agent's home under
+// a rig. It returns "" when there is no such sign.
+func agentSessionSignal(townRoot, cwd string) string {
+	for _, key := range agentSessionEnv {
+		if os.Getenv(key) != "" {
+			return key
+		}
+	}
+	if rig, home := rigHomeDir(townRoot, cwd); slices.Contains(agentHomeDirs, home) {
+		return "working directory " + rig + "/" + home + "/"
+	}
+	return ""
+}
+
+// rigHomeDir returns the rig and the directory under it (polecats, crew,
+// witness, ...) that cwd is in, or "" for both when cwd isn't that deep in
+// the town.
+func rigHomeDir(townRoot, cwd string) (rig, home string) {
+	if cwd == "" {
+		return "", ""
+	}
+	rel, err := filepath.Rel(townRoot, cwd)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", ""
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// callerIdentity returns who is running gt: the identity in a valid role
+// token, else the overseer when nothing suggests an agent session. A
+// caller that looks like an agent but has no token (an unsigned GT_ROLE,
+// an agent's working directory) gets the least privileged role, whatever
+// GT_ROLE claims. Every role's startup command carries its token.
+func callerIdentity(townRoot string) (authz.Identity, string, error) {
+	if token := os.Getenv(authz.EnvToken); token != "" {
+		id, err := authz.Verify(townRoot, token)
+		if err != nil {
+			return authz.Identity{}, "", fmt.Errorf("%s: %w", authz.EnvToken, err)
+		}
+		return *id, "role token", nil
+	}
+	cwd, _ := os.Getwd()
+	if signal := agentSessionSignal(townRoot, cwd); signal != "" {
+		id := authz.Identity{Role: authz.Unverified}
+		if info, err := GetRoleWithContext(cwd, townRoot); err == nil && info.Role != RoleUnknown {
+			id.Rig, id.Agent = info.Rig, info.Polecat
+		}
+		return id, signal + " without a role token", nil
+	}
+	return authz.Identity{Role: authz.Overseer}, "no agent session", nil
+}
+
+// commandPath returns a command's path without the leading "gt".
+func commandPath(cmd *cobra.Command) string {
+	return strings.TrimPrefix(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()), " ")
+}
+
+// describeRoles renders a rule's roles for messages.
+func describeRoles(roles []string) string {
+	if len(roles) == 0 {
+		return "the overseer"
+	}
+	return strings.Join(roles, ", ")
+}
+
+// authorizeCommand refuses a restricted command to a role the policy
+// doesn't allow. Outside a town there is nothing to protect.
+func authorizeCommand(cmd *cobra.Command) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	path := commandPath(cmd)
+	policy := loadCommandPolicy(townRoot)
+	_, roles, restricted := policy.Rule(path)
+	if !restricted {
+		return nil
+	}
+
+	cmd.SilenceUsage = true
+	id, _, err := callerIdentity(townRoot)
+	if err != nil {
+		return fmt.Errorf("'gt %s' is restricted to %s and your role can't be verified: %w", path, describeRoles(roles), err)
+	}
+	if policy.Allows(id.Role, path) {
+		cmd.SilenceUsage = false
+		return nil
+	}
+	return fmt.Errorf("'gt %s' is restricted to %s; a %s may not run it (see 'gt permissions')", path, describeRoles(roles), id.Role)
+}
+
+// PermissionRule is a policy entry as shown by gt permissions --json.
+type PermissionRule struct {
+	Command string   `json:"command"`
+	Roles   []string `json:"roles"`
+	Allowed bool     `json:"allowed"`
+}
+
+func runPermissions(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	policy := loadCommandPolicy(townRoot)
+
+	id, source, idErr := callerIdentity(townRoot)
+	role := id.Role
+	if permissionsRole != "" {
+		role, source, idErr = permissionsRole, "--role", nil
+	}
+
+	var rules []PermissionRule
+	for path, roles := range policy {
+		rules = append(rules, PermissionRule{Command: path, Roles: roles, Allowed: idErr == nil && policy.Allows(role, path)})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Command < rules[j].Command })
+
+	if permissionsJSON {
+		out := map[string]any{"role": role, "source": source, "rules": rules}
+		if idErr != nil {
+			out["error"] = idErr.Error()
+		}
+		return outputJSON(out)
+	}
+
+	if idErr != nil {
+		fmt.Printf("%s %v\n  Restricted commands are refused until the token is fixed (respawn the agent).\n\n", style.Error.Render("✗"), idErr)
+	} else {
+		fmt.Printf("%s You are %s %s\n\n", style.Bold.Render("●"), role, style.Dim.Render("("+source+")"))
+	}
+	for _, r := range rules {
+		mark := style.Success.Render("✓")
+		if !r.Allowed {
+			mark = style.Error.Render("✗")
+		}
+		fmt.Printf("  %s gt %-16s %s\n", mark, r.Command, style.Dim.Render(describeRoles(r.Roles)))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Commands not listed are open to every role."))
+	return nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/authz"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestCallerIdentity(t *testing.T) {
+	town := t.TempDir()
+	t.Chdir(town)
+	t.Setenv(authz.EnvToken, "")
+	for _, key := range agentSessionEnv {
+		t.Setenv(key, "")
+	}
+
+	if id, _, err := callerIdentity(town); err != nil || id.Role != authz.Overseer {
+		t.Errorf("no agent env = %+v, %v; want overseer", id, err)
+	}
+
+	// An unsigned GT_ROLE proves nothing: least privilege, not the role claimed
+	t.Setenv(EnvGTRole, "mayor")
+	if id, _, _ := callerIdentity(town); id.Role != authz.Unverified {
+		t.Errorf("unsigned GT_ROLE=mayor = %+v, want %s", id, authz.Unverified)
+	}
+
+	// The token wins over a GT_ROLE the agent changed
+	token, err := authz.Sign(town, authz.Identity{Role: "polecat", Rig: "greenplace", Agent: "Toast"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(authz.EnvToken, token)
+	if id, source, _ := callerIdentity(town); id.Role != "polecat" || source != "role token" {
+		t.Errorf("signed polecat with GT_ROLE=mayor = %+v (%s)", id, source)
+	}
+
+	t.Setenv(authz.EnvToken, "forged.token")
+	if _, _, err := callerIdentity(town); err == nil {
+		t.Error("a bad token should be an error, not a fallback to GT_ROLE")
+	}
+}
+
+func TestCallerIdentityAgentSignals(t *testing.T) {
+	town := t.TempDir()
+	t.Chdir(town)
+	t.Setenv(authz.EnvToken, "")
+	for _, key := range agentSessionEnv {
+		t.Setenv(key, "")
+	}
+
+	// People use tmux and work in crew clones too
+	t.Setenv("TMUX", "/tmp/tmux-0/default,1,0")
+	crew := filepath.Join(town, "greenplace", "crew", "max")
+	if err := os.MkdirAll(crew, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(crew)
+	if id, source, _ := callerIdentity(town); id.Role != authz.Overseer {
+		t.Errorf("TMUX in a crew clone = %+v (%s), want overseer", id, source)
+	}
+
+	// Running from a polecat's worktree with a clean environment doesn't
+	// make an agent the overseer
+	worktree := filepath.Join(town, "greenplace", "polecats", "Toast")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(worktree)
+	if id, source, _ := callerIdentity(town); id.Role != authz.Unverified {
+		t.Errorf("cwd in polecats/ = %+v (%s), want %s", id, source, authz.Unverified)
+	}
+
+	// The rig's own directory is not an agent's home
+	t.Chdir(filepath.Join(town, "greenplace"))
+	if id, _, _ := callerIdentity(town); id.Role != authz.Overseer {
+		t.Errorf("cwd in the rig = %+v, want overseer", id)
+	}
+}
+
+// TestCallerIdentityStartupEnv runs callerIdentity with the environment
+// the startup commands of town and rig agents export, which is all their
+// first pane gets.
+func TestCallerIdentityStartupEnv(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"type":"town","version":2,"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rigPath := filepath.Join(town, "greenplace")
+	t.Chdir(town)
+
+	tests := []struct {
+		name    string
+		command string
+		want    authz.Identity
+	}{
+		{"mayor", config.BuildAgentStartupCommand("mayor", "mayor", "", ""), authz.Identity{Role: "mayor"}},
+		{"witness", config.BuildAgentStartupCommand("witness", "greenplace/witness", rigPath, ""), authz.Identity{Role: "witness", Rig: "greenplace"}},
+		{"crew", config.BuildCrewStartupCommand("greenplace", "max", rigPath, ""), authz.Identity{Role: "crew", Rig: "greenplace", Agent: "max"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(authz.EnvToken, "")
+			for _, key := range agentSessionEnv {
+				t.Setenv(key, "")
+			}
+			exports, _, ok := strings.Cut(tt.command, " && ")
+			if !ok || !strings.HasPrefix(exports, "export ") {
+				t.Fatalf("startup command exports nothing: %s", tt.command)
+			}
+			for _, kv := range strings.Fields(strings.TrimPrefix(exports, "export ")) {
+				if k, v, ok := strings.Cut(kv, "="); ok {
+					t.Setenv(k, v)
+				}
+			}
+			id, source, err := callerIdentity(town)
+			if err != nil || id != tt.want || source != "role token" {
+				t.Errorf("callerIdentity = %+v (%s), %v; want %+v from the role token", id, source, err, tt.want)
+			}
+		})
+	}
+}
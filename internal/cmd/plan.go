@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/stats"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	planLabel     string
+	planMilestone string
+	planRig       string
+	planWindow    string
+	planJSON      bool
+)
+
+var planCmd = &cobra.Command{
+	Use:     "plan",
+	GroupID: GroupWork,
+	Short:   "Project when a label's or milestone's beads will be done",
+	Long: `Project a completion date for a set of beads from their estimates and
+the town's recent throughput.
+
+The set is every bead with --label, or a --milestone: the beads a gt epic
+tracks (hq-ep-*, see 'gt epic'), or any other bead's children. Estimates
+come from 'gt bead estimate' (or create --size and --estimate): sizes XS,
+S, M, L and XL are 1, 2, 3, 5 and 8 points, and an explicit estimate
+overrides the size. Beads without one count as the
+average of the set's estimated beads.
+
+Throughput is the points closed over the last --window (default 14d) in
+the rigs the plan covers, with unestimated closed beads counted as 3. The
+projection assumes the same pace continues.
+
+Examples:
+  gt plan --label auth
+  gt plan --milestone hq-ep-abc12 --window 30d
+  gt plan --label v2 --rig greenplace --json`,
+	Args: cobra.NoArgs,
+	RunE: runPlan,
+}
+
+func init() {
+	planCmd.Flags().StringVar(&planLabel, "label", "", "Plan the beads with this label")
+	planCmd.Flags().StringVar(&planMilestone, "milestone", "", "Plan the beads of this epic, or the children of this bead")
+	planCmd.Flags().StringVar(&planRig, "rig", "", "Only this rig's beads and throughput")
+	planCmd.Flags().StringVar(&planWindow, "window", "14d", "Throughput window (e.g., 7d, 30d)")
+	planCmd.Flags().BoolVar(&planJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(planCmd)
+}
+
+// planSource is a beads database the plan reads.
+type planSource struct {
+	rig  string // "" for the town
+	path string
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	if (planLabel == "") == (planMilestone == "") {
+		return fmt.Errorf("give one of --label or --milestone")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	window, err := parseDuration(planWindow)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --window %q", planWindow)
+	}
+
+	var sources []planSource
+	if planRig == "" {
+		sources = append(sources, planSource{path: beads.GetTownBeadsPath(townRoot)})
+	}
+	rigs, err := discoverAllRigs(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing rigs: %w", err)
+	}
+	for _, r := range rigs {
+		if planRig == "" || r.Name == planRig {
+			sources = append(sources, planSource{rig: r.Name, path: r.BeadsPath()})
+		}
+	}
+	if planRig != "" && len(sources) == 0 {
+		return fmt.Errorf("rig '%s' not found", planRig)
+	}
+
+	var set []*beads.Issue
+	rigOf := map[string]string{}
+	if strings.HasPrefix(planMilestone, "hq-ep-") {
+		// A gt epic tracks beads across rigs rather than parenting them
+		for _, t := range getTrackedIssues(beads.GetTownBeadsPath(townRoot), planMilestone) {
+			issue, err := beads.New(beadLocation(townRoot, t.ID)).Show(t.ID)
+			if err != nil {
+				style.PrintWarning("could not read %s: %v", t.ID, err)
+				continue
+			}
+			rigOf[issue.ID] = beadRigName(townRoot, issue.ID)
+			set = append(set, issue)
+		}
+	} else if planMilestone != "" {
+		children, err := beads.New(beadLocation(townRoot, planMilestone)).List(beads.ListOptions{Parent: planMilestone, Status: "all", Priority: -1})
+		if err != nil {
+			return fmt.Errorf("listing %s's beads: %w", planMilestone, err)
+		}
+		set = children
+	} else {
+		for _, src := range sources {
+			issues, err := beads.New(src.path).List(beads.ListOptions{Label: planLabel, Status: "all", Priority: -1})
+			if err != nil {
+				style.PrintWarning("could not list %s beads: %v", planSourceName(src), err)
+				continue
+			}
+			for _, i := range issues {
+				rigOf[i.ID] = src.rig
+			}
+			set = append(set, issues...)
+		}
+	}
+
+	var remaining []stats.PlanItem
+	done := 0
+	for _, i := range set {
+		if beads.IsBookkeeping(i) {
+			continue
+		}
+		if i.Status == "closed" {
+			done++
+			continue
+		}
+		item := stats.PlanItem{Rig: rigOf[i.ID], ID: i.ID, Title: i.Title, Status: i.Status}
+		if points := beads.ParseEstimateFields(i).Points(); points > 0 {
+			item.Points, item.Estimated = points, true
+		}
+		remaining = append(remaining, item)
+	}
+	sort.SliceStable(remaining, func(a, b int) bool { return remaining[a].ID < remaining[b].ID })
+
+	var closed []stats.Bead
+	for _, src := range sources {
+		closed = append(closed, closedWork(src.rig, src.path)...)
+	}
+	now := time.Now()
+	plan := stats.Project(remaining, done, closed, now.Add(-window), now)
+
+	if planJSON {
+		return outputJSON(plan)
+	}
+	printPlan(plan)
+	return nil
+}
+
+func planSourceName(src planSource) string {
+	if src.rig == "" {
+		return "town"
+	}
+	return src.rig
+}
+
+func printPlan(p *stats.Plan) {
+	what := "label " + planLabel
+	if planMilestone != "" {
+		what = planMilestone
+	}
+	fmt.Printf("%s Plan for %s\n\n", style.Bold.Render("📅"), what)
+
+	fmt.Printf("  Done: %d   Remaining: %d (%s points", p.Done, len(p.Remaining), formatPoints(p.RemainingPoints))
+	if p.Unestimated > 0 {
+		fmt.Printf(", %d unestimated", p.Unestimated)
+	}
+	fmt.Println(")")
+	fmt.Printf("  Throughput: %d beads, %s points in the last %s (%.1f/day)\n",
+		p.Closed, formatPoints(p.Completed), planWindow, p.PerDay)
+
+	switch {
+	case len(p.Remaining) == 0:
+		fmt.Printf("  %s Nothing left to do\n", style.Success.Render("✓"))
+	case p.ETA.IsZero():
+		fmt.Printf("  %s No beads closed in the window; can't project a date\n", style.Warning.Render("⚠"))
+	default:
+		fmt.Printf("  Projected: %s (%.1f days)\n", style.Bold.Render(p.ETA.Local().Format("2006-01-02")), p.Days)
+	}
+
+	if len(p.Remaining) == 0 {
+		return
+	}
+	fmt.Println()
+	for _, item := range p.Remaining {
+		points := fmt.Sprintf("%5s", formatPoints(item.Points))
+		if !item.Estimated {
+			points = style.Dim.Render(fmt.Sprintf("%5s", "~"+formatPoints(item.Points)))
+		}
+		fmt.Printf("    %-14s %s  %-12s %s\n", item.ID, points, item.Status, truncateWithEllipsis(item.Title, 50))
+	}
+}
+
+// formatPoints shows points without a trailing ".0".
+func formatPoints(n float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", n), ".0")
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -50,6 +51,7 @@ all currently active polecats with their states:
   - working: Actively working on an issue
   - done: Completed work, waiting for cleanup
   - stuck: Needs assistance
+  - warm: Spare primed with rig context, waiting for a sling (gt polecat warm)
 
 Examples:
   gt polecat list greenplace
@@ -414,6 +416,8 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 			stateStr = style.Info.Render(stateStr)
 		case polecat.StateStuck:
 			stateStr = style.Warning.Render(stateStr)
+		case polecat.StateWarm:
+			stateStr = style.Info.Render(stateStr)
 		case polecat.StateDone:
 			stateStr = style.Success.Render(stateStr)
 		default:
@@ -720,7 +724,7 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 		}
 
 		if !sessInfo.Created.IsZero() {
-			fmt.Printf("  Created:       %s\n", sessInfo.Created.Format("2006-01-02 15:04:05"))
+			fmt.Printf("  Created:       %s\n", format.DateTimeSeconds(sessInfo.Created))
 		}
 
 		if !sessInfo.LastActivity.IsZero() {
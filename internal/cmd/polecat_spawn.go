@@ -2,6 +2,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -11,10 +12,13 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/primer"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // SpawnedPolecatInfo contains info about a spawned polecat session.
@@ -38,12 +42,29 @@ type SlingSpawnOptions struct {
 	Create   bool   // Create polecat if it doesn't exist (currently always true for sling)
 	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	Agent    string // Agent override for this spawn (e.g., "gemini", "codex", "claude-haiku")
+	Runtime  string // "local" or "docker" (default: the rig's container settings decide)
+	Tier     string // Model tier: haiku, sonnet, opus or a configured tier
+	Sandbox  string // Sandbox profile override (default: the rig's sandbox settings)
+	Branch   string // Branch name template (see polecat.AddOptions)
+	Warm     bool   // Start as a warm spare, with no work, for a later sling to claim
 }
 
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
 // This is used by gt sling when the target is a rig name.
 // The caller (sling) handles hook attachment and nudging.
 func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolecatInfo, error) {
+	// The agent session inherits this span's trace context, so everything
+	// the polecat does is part of the sling's trace.
+	span := telemetry.Start("polecat.spawn", attribute.String("gt.rig", rigName), attribute.String("gt.bead", opts.HookBead))
+	info, err := spawnPolecatForSling(rigName, opts)
+	if info != nil {
+		span.SetAttributes(attribute.String("gt.polecat", info.PolecatName))
+	}
+	span.End(err)
+	return info, err
+}
+
+func spawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolecatInfo, error) {
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -81,6 +102,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	// Build add options with hook_bead set atomically at spawn time
 	addOpts := polecat.AddOptions{
 		HookBead: opts.HookBead,
+		Branch:   opts.Branch,
 	}
 
 	if err == nil {
@@ -114,6 +136,14 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("getting polecat after creation: %w", err)
 	}
 
+	// Mark a warm spare before its session starts, so gt prime tells the
+	// agent to wait for work instead of escalating its empty hook
+	if opts.Warm {
+		if err := polecatMgr.MarkWarm(polecatName); err != nil {
+			return nil, fmt.Errorf("marking %s warm: %w", polecatName, err)
+		}
+	}
+
 	// Resolve account for runtime config
 	accountsPath := constants.MayorAccountsPath(townRoot)
 	claudeConfigDir, accountHandle, err := config.ResolveAccountConfigDir(accountsPath, opts.Account)
@@ -124,6 +154,12 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		fmt.Printf("Using account: %s\n", accountHandle)
 	}
 
+	// Bring the rig's repo primer up to date before the session primes
+	// with it; the worktree was just created from a fresh fetch
+	if _, err := primer.Ensure(townRoot, rigName, r.Path, r.DefaultBranch(), getTestCommand(r.Path)); err != nil && !errors.Is(err, primer.ErrNoRepo) {
+		style.PrintWarning("could not build repo primer: %v", err)
+	}
+
 	// Start session
 	t := tmux.NewTmux()
 	polecatSessMgr := polecat.NewSessionManager(t, r)
@@ -133,13 +169,20 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	if !running {
 		fmt.Printf("Starting session for %s/%s...\n", rigName, polecatName)
 		startOpts := polecat.SessionStartOptions{
+			Bead:             opts.HookBead,
 			RuntimeConfigDir: claudeConfigDir,
+			Runtime:          opts.Runtime,
+			Sandbox:          opts.Sandbox,
+			Tier:             opts.Tier,
 		}
-		if opts.Agent != "" {
+		if opts.Agent != "" || opts.Tier != "" {
 			cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(rigName, polecatName, r.Path, "", opts.Agent)
 			if err != nil {
 				return nil, err
 			}
+			if opts.Tier != "" {
+				cmd = withModelTier(townRoot, r.Path, opts.Agent, cmd, opts.Tier)
+			}
 			startOpts.Command = cmd
 		}
 		if err := polecatSessMgr.Start(polecatName, startOpts); err != nil {
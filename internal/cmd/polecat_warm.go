@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	polecatWarmAll   bool
+	polecatWarmCount int
+)
+
+var polecatWarmCmd = &cobra.Command{
+	Use:   "warm [rig]",
+	Short: "Keep warm spare polecats ready for slings",
+	Long: `Top up a rig's pool of warm spare polecats.
+
+A warm spare is a polecat whose session is already started and primed
+with the rig's context, waiting for work. gt sling to the rig claims the
+longest-waiting spare, moves its worktree to a fresh branch for the bead
+and sends it only the work, instead of spawning a polecat and waiting for
+it to load its context.
+
+Pool sizes are set per rig in ~/gt/settings/config.json, and the daemon
+tops the pools up on every heartbeat:
+
+  "scheduler": {"warm_polecats": {"gastown": 2}}
+
+Spares count against max_polecats, so the pool never outgrows the limits,
+and none are started while spend is past the budget's warning threshold
+or a model provider is backing off. Slings with --agent, --tier,
+--runtime, --sandbox, --account or --branch always spawn a new polecat.
+
+Examples:
+  gt polecat warm gastown              # Top up to the configured size
+  gt polecat warm gastown --count 3    # Top up to 3
+  gt polecat warm --all                # Every rig with a configured pool`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPolecatWarm,
+}
+
+func init() {
+	polecatWarmCmd.Flags().BoolVar(&polecatWarmAll, "all", false, "Top up every rig with a configured pool")
+	polecatWarmCmd.Flags().IntVar(&polecatWarmCount, "count", -1, "Pool size (default: the rig's warm_polecats setting)")
+	polecatCmd.AddCommand(polecatWarmCmd)
+}
+
+func runPolecatWarm(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	var sizes map[string]int
+	if settings.Scheduler != nil {
+		sizes = settings.Scheduler.WarmPolecats
+	}
+
+	var rigs []*rig.Rig
+	switch {
+	case polecatWarmAll:
+		all, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		for _, r := range all {
+			if sizes[r.Name] > 0 || polecatWarmCount >= 0 {
+				rigs = append(rigs, r)
+			}
+		}
+	case len(args) == 1:
+		_, r, err := getRig(args[0])
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	default:
+		return fmt.Errorf("rig name required (or use --all)")
+	}
+	if len(rigs) == 0 {
+		fmt.Printf("%s No warm pools configured\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	status, err := checkBudget(townRoot)
+	if err != nil {
+		return err
+	}
+	held := ""
+	if status.Level != budget.OK {
+		held = status.Reason()
+	} else if backingOff, reason, err := providerBackoff(townRoot); err != nil {
+		return err
+	} else if backingOff {
+		held = reason
+	}
+
+	state, err := scheduler.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	// Spares count against the limits here, so a claimed spare never takes
+	// a rig past them
+	occupied := make(scheduler.Running)
+	for name, n := range state.Running {
+		occupied[name] = n + state.Warm[name]
+	}
+
+	for _, r := range rigs {
+		size := sizes[r.Name]
+		if polecatWarmCount >= 0 {
+			size = polecatWarmCount
+		}
+		if err := topUpWarm(r, size, held, state, occupied); err != nil {
+			style.PrintWarning("%s: %v", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// topUpWarm starts spares in a rig until it has size live ones, counting
+// each against occupied. Spares whose session died are unmarked, leaving
+// ordinary idle polecats for cleanup.
+func topUpWarm(r *rig.Rig, size int, held string, state *scheduler.State, occupied scheduler.Running) error {
+	mgr, _, err := getPolecatManager(r.Name)
+	if err != nil {
+		return err
+	}
+	sessMgr := polecat.NewSessionManager(tmux.NewTmux(), r)
+	warm, err := mgr.ListWarm()
+	if err != nil {
+		return err
+	}
+	live := 0
+	for _, name := range warm {
+		if running, _ := sessMgr.IsRunning(name); running {
+			live++
+			continue
+		}
+		if err := mgr.UnmarkWarm(name); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s/%s: warm session gone, no longer a spare\n", style.Dim.Render("○"), r.Name, name)
+	}
+
+	need := size - live
+	if need <= 0 {
+		fmt.Printf("%s %s: %d warm\n", style.Success.Render("✓"), r.Name, live)
+		return nil
+	}
+	if held != "" {
+		fmt.Printf("%s %s: %d/%d warm, not starting more: %s\n", style.Bold.Render("⏸"), r.Name, live, size, held)
+		return nil
+	}
+	for ; need > 0; need-- {
+		if ok, reason := state.Limits.Check(occupied, r.Name); !ok {
+			fmt.Printf("%s %s: %d/%d warm, not starting more: %s\n", style.Bold.Render("⏸"), r.Name, live, size, reason)
+			return nil
+		}
+		if _, err := SpawnPolecatForSling(r.Name, SlingSpawnOptions{Warm: true}); err != nil {
+			return fmt.Errorf("starting warm polecat: %w", err)
+		}
+		occupied[r.Name]++
+		live++
+	}
+	fmt.Printf("%s %s: %d warm\n", style.Success.Render("✓"), r.Name, live)
+	return nil
+}
+
+// warmClaimable reports whether this sling may go to a warm spare, which
+// was started with the rig's defaults.
+func warmClaimable() bool {
+	return slingAgent == "" && slingTier == "" && slingRuntime == "" &&
+		slingSandbox == "" && slingAccount == "" && slingBranch == ""
+}
+
+// claimWarmPolecat hands a bead to one of a rig's warm spares, returning
+// nil if the rig has none.
+func claimWarmPolecat(rigName, beadID string) (*SpawnedPolecatInfo, error) {
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return nil, err
+	}
+	sessMgr := polecat.NewSessionManager(tmux.NewTmux(), r)
+	p, err := mgr.ClaimWarm(beadID, func(name string) bool {
+		running, _ := sessMgr.IsRunning(name)
+		return running
+	})
+	if errors.Is(err, polecat.ErrNoWarm) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sessionName := sessMgr.SessionName(p.Name)
+	pane, err := getSessionPane(sessionName)
+	if err != nil {
+		return nil, fmt.Errorf("getting pane for %s: %w", sessionName, err)
+	}
+	fmt.Printf("%s Claimed warm polecat %s on %s\n", style.Bold.Render("✓"), p.Name, p.Branch)
+	return &SpawnedPolecatInfo{
+		RigName:     rigName,
+		PolecatName: p.Name,
+		ClonePath:   p.ClonePath,
+		SessionName: sessionName,
+		Pane:        pane,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var prefixListJSON bool
+
+var prefixCmd = &cobra.Command{
+	Use:     "prefix",
+	GroupID: GroupWorkspace,
+	Short:   "Manage the beads prefixes of rigs",
+	Long: `Manage the beads prefixes of the town's rigs.
+
+Every rig's beads have their own ID prefix (gt-abc12), which routes.jsonl
+uses to find the database a bead lives in. Two rigs with one prefix break
+that routing, so gt rig add refuses a prefix another rig, or the town's own
+hq, already uses, and gt doctor reports collisions.
+
+Examples:
+  gt prefix list
+  gt prefix rename gastown gtn`,
+	RunE: requireSubcommand,
+}
+
+var prefixListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the prefixes in use and who uses them",
+	Long: `List every beads prefix in the town and the rigs that use it.
+
+Prefixes are gathered from rigs.json, each rig's config.json (including
+prefixes a rig had before a rename, which stay reserved) and routes.jsonl.
+A prefix with more than one owner is a collision.
+
+Examples:
+  gt prefix list
+  gt prefix list --json`,
+	Args: cobra.NoArgs,
+	RunE: runPrefixList,
+}
+
+var prefixRenameCmd = &cobra.Command{
+	Use:   "rename <rig> <new-prefix>",
+	Short: "Move a rig to a new beads prefix",
+	Long: `Move a rig to a new beads prefix, rewriting its bead IDs.
+
+rigs.json, the rig's config.json and routes.jsonl are updated first, then
+bd rename-prefix rewrites the IDs of the rig's beads and the references
+between them. If bd fails, run the same rename again to retry it. The old
+prefix is recorded in config.json as a former prefix and stays reserved,
+and its route stays, marked as renamed: bead IDs in existing commit
+messages and mail still resolve to their new IDs in gt bead, gt sling and
+gt rig release.
+
+Agents of the rig keep the IDs they started with; restart them afterwards.
+
+Examples:
+  gt prefix rename gastown gtn`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPrefixRename,
+}
+
+func init() {
+	prefixListCmd.Flags().BoolVar(&prefixListJSON, "json", false, "Output as JSON")
+	prefixCmd.AddCommand(prefixListCmd)
+	prefixCmd.AddCommand(prefixRenameCmd)
+	rootCmd.AddCommand(prefixCmd)
+}
+
+func runPrefixList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, _ := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	reg, err := rig.LoadPrefixRegistry(townRoot, rigsConfig)
+	if err != nil {
+		return err
+	}
+	if prefixListJSON {
+		return outputJSON(reg)
+	}
+	prefixes := make([]string, 0, len(reg))
+	for p := range reg {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	for _, p := range prefixes {
+		owners := strings.Join(reg[p], ", ")
+		if len(reg[p]) > 1 {
+			fmt.Printf("  %s %-12s %s\n", style.Error.Render("✗"), p, style.Error.Render(owners+" (collision)"))
+			continue
+		}
+		fmt.Printf("  %s %-12s %s\n", style.Success.Render("✓"), p, owners)
+	}
+	return nil
+}
+
+func runPrefixRename(cmd *cobra.Command, args []string) error {
+	name, newPrefix := args[0], args[1]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	release, err := acquireRigStateLocks(townRoot, name)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	oldPrefix, err := mgr.RenamePrefix(name, newPrefix)
+	if err != nil {
+		return fmt.Errorf("renaming prefix: %w", err)
+	}
+
+	fmt.Printf("%s Rig %s moved from prefix %s to %s\n", style.Success.Render("✓"), name, oldPrefix, newPrefix)
+	fmt.Printf("  %s stays reserved for %s's existing commit references\n", oldPrefix, name)
+	fmt.Printf("  Restart the rig's agents: %s\n", style.Dim.Render("gt rig restart "+name))
+	return nil
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	premergeRig  string
+	premergeOnto string
+	premergeJSON bool
+)
+
+var premergeCmd = &cobra.Command{
+	Use:     "premerge [branch]",
+	GroupID: GroupWork,
+	Short:   "Check that a branch rebases cleanly onto main before submitting",
+	Long: `Rebase a branch onto the rig's canonical branch in a scratch worktree and
+report any conflicts, without touching your checkout.
+
+The branch defaults to the current one and the target to origin/<default
+branch>, fetched first. Conflicts are listed with the commit that failed
+to apply, so they can be fixed while the work is fresh, rather than by the
+refinery later. Files changed on both sides that merge cleanly are listed
+too: they are worth a look for conflicts git can't see.
+
+'gt done' and 'gt mq submit' run this check before submitting and refuse
+to submit a conflicting branch (skip it with --no-premerge).
+
+Exits 1 when the branch conflicts.
+
+Examples:
+  gt premerge                          # Current branch onto origin/main
+  gt premerge polecat/Toast/gt-abc     # A specific branch
+  gt premerge --onto integration/gt-ep # Onto another branch
+  gt premerge --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPremerge,
+}
+
+func init() {
+	premergeCmd.Flags().StringVar(&premergeRig, "rig", "", "Rig to check in (default: the rig of the current directory)")
+	premergeCmd.Flags().StringVar(&premergeOnto, "onto", "", "Branch to rebase onto (default: the rig's default branch)")
+	premergeCmd.Flags().BoolVar(&premergeJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(premergeCmd)
+}
+
+// premergeReport is gt premerge's JSON output.
+type premergeReport struct {
+	Branch    string   `json:"branch"`
+	Onto      string   `json:"onto"`
+	Clean     bool     `json:"clean"`
+	Commit    string   `json:"commit,omitempty"`
+	Conflicts []string `json:"conflicts,omitempty"`
+	Overlap   []string `json:"overlap,omitempty"`
+}
+
+func runPremerge(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var r *rig.Rig
+	if premergeRig != "" {
+		if _, r, err = getRig(premergeRig); err != nil {
+			return err
+		}
+	} else if _, r, err = findCurrentRig(townRoot); err != nil {
+		return fmt.Errorf("%w (use --rig)", err)
+	}
+
+	// Check from the current clone when in one (crew clones have their own
+	// refs), otherwise from the rig's shared repo
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	g := git.NewGit(cwd)
+	if !g.IsRepo() {
+		g = rigRepoGit(r.Path)
+	}
+
+	branch := ""
+	if len(args) > 0 {
+		branch = args[0]
+	} else if branch, err = g.CurrentBranch(); err != nil {
+		return fmt.Errorf("getting current branch: %w", err)
+	}
+
+	target := premergeOnto
+	if target == "" {
+		target = defaultBranchFor(r.Path)
+	}
+
+	result, onto, err := premergeCheck(g, r.Path, branch, target)
+	if err != nil {
+		return err
+	}
+
+	if premergeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(premergeReport{
+			Branch:    branch,
+			Onto:      onto,
+			Clean:     len(result.Conflicts) == 0,
+			Commit:    result.Commit,
+			Conflicts: result.Conflicts,
+			Overlap:   result.Overlap,
+		}); err != nil {
+			return err
+		}
+	} else {
+		printPremerge(branch, onto, result)
+	}
+	if len(result.Conflicts) > 0 {
+		// The report says it all; exit 1 without cobra's error and usage
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// premergeCheck fetches target from origin and tries rebasing branch onto
+// it in a scratch worktree under the rig. It returns the result and the
+// ref rebased onto (origin/<target>).
+func premergeCheck(g *git.Git, rigPath, branch, target string) (*git.RebaseCheckResult, string, error) {
+	if err := g.FetchBranch("origin", target); err != nil {
+		style.PrintWarning("could not fetch %s: %v (checking against the last fetched copy)", target, err)
+	}
+	onto := "origin/" + target
+	scratch := filepath.Join(rigPath, ".premerge", fmt.Sprintf("%s-%d", strings.ReplaceAll(branch, "/", "-"), os.Getpid()))
+	result, err := g.RebaseCheck(branch, onto, scratch)
+	if err != nil {
+		return nil, onto, fmt.Errorf("checking %s against %s: %w", branch, onto, err)
+	}
+	_ = os.Remove(filepath.Dir(scratch)) // Only removes .premerge if empty
+	return result, onto, nil
+}
+
+// requireCleanRebase runs the premerge check for a submit step and fails
+// if the branch conflicts. A check that can't run only warns: the refinery
+// still catches conflicts.
+func requireCleanRebase(g *git.Git, rigPath, branch, target, retry string) error {
+	result, onto, err := premergeCheck(g, rigPath, branch, target)
+	if err != nil {
+		style.PrintWarning("premerge check skipped: %v", err)
+		return nil
+	}
+	if len(result.Conflicts) == 0 {
+		return nil
+	}
+	printPremerge(branch, onto, result)
+	return fmt.Errorf("%s conflicts with %s; resolve and run %s again (or pass --no-premerge)", branch, onto, retry)
+}
+
+// printPremerge prints a premerge result for people and agents.
+func printPremerge(branch, onto string, result *git.RebaseCheckResult) {
+	if len(result.Conflicts) == 0 {
+		fmt.Printf("%s %s rebases cleanly onto %s\n", style.Bold.Render("✓"), branch, onto)
+		if len(result.Overlap) > 0 {
+			fmt.Printf("  %s\n", style.Dim.Render("Also changed on "+onto+": "+strings.Join(result.Overlap, ", ")))
+		}
+		return
+	}
+	fmt.Printf("%s %s conflicts with %s", style.Error.Render("✗"), branch, onto)
+	if result.Commit != "" {
+		fmt.Printf(" at %s", result.Commit)
+	}
+	fmt.Println(":")
+	for _, f := range result.Conflicts {
+		fmt.Printf("    %s\n", f)
+	}
+	fmt.Printf("\n  Rebase and resolve before submitting:\n")
+	fmt.Printf("    git fetch origin && git rebase %s\n", onto)
+}
+
+// rigRepoGit returns git for a rig's shared repo: the bare .repo.git, or
+// mayor/rig in rigs that predate it.
+func rigRepoGit(rigPath string) *git.Git {
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if info, err := os.Stat(bareRepoPath); err == nil && info.IsDir() {
+		return git.NewGitWithDir(bareRepoPath, "")
+	}
+	return git.NewGit(filepath.Join(rigPath, "mayor", "rig"))
+}
+
+// defaultBranchFor returns a rig's configured default branch.
+func defaultBranchFor(rigPath string) string {
+	if rigCfg, err := rig.LoadRigConfig(rigPath); err == nil && rigCfg.DefaultBranch != "" {
+		return rigCfg.DefaultBranch
+	}
+	return "main"
+}
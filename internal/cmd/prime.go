@@ -12,7 +12,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/promptctx"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -196,55 +198,90 @@ func runPrime(cmd *cobra.Command, args []string) error {
 	explain(true, "Session metadata: always included for seance discovery")
 	outputSessionMetadata(ctx)
 
+	// Sections are collected and printed together so they can be fitted to
+	// the town's context budget; lower-priority ones are trimmed first.
+	var sections primeAssembly
+
 	// Output context
-	explain(true, fmt.Sprintf("Role context: detected role is %s", ctx.Role))
-	if err := outputPrimeContext(ctx); err != nil {
-		return err
+	var roleErr error
+	sections.add("role-pack", promptctx.Required, func() {
+		explain(true, fmt.Sprintf("Role context: detected role is %s", ctx.Role))
+		roleErr = outputPrimeContext(ctx)
+	})
+	if roleErr != nil {
+		return roleErr
+	}
+
+	// A path-scoped rig's agents work in one directory of a monorepo, and a
+	// jj rig's agents use jj rather than git
+	sections.add("rig-scope", promptctx.Required, func() { outputRigScope(ctx) })
+	sections.add("rig-vcs", promptctx.Required, func() { outputRigVCS(ctx) })
+
+	// Polecats start with the rig's repo primer instead of exploring
+	if ctx.Role == RolePolecat {
+		sections.add("repo-primer", promptctx.Normal, func() { outputRepoPrimer(ctx) })
 	}
 
 	// Output handoff content if present
-	outputHandoffContent(ctx)
+	sections.add("handoff", promptctx.High, func() { outputHandoffContent(ctx) })
 
 	// Output attachment status (for autonomous work detection)
-	outputAttachmentStatus(ctx)
+	sections.add("attachment", promptctx.Required, func() { outputAttachmentStatus(ctx) })
 
 	// Check for slung work on hook (from gt sling)
 	// If found, we're in autonomous mode - skip normal startup directive
-	hasSlungWork := checkSlungWork(ctx)
-	explain(hasSlungWork, "Autonomous mode: hooked/in-progress work detected")
+	var hooked string
+	sections.add("hooked-work", promptctx.Required, func() {
+		hooked = checkSlungWork(ctx)
+		explain(hooked != "", "Autonomous mode: hooked/in-progress work detected")
+	})
+	hasSlungWork := hooked != ""
+
+	// Output the last session's state summary of the hooked bead
+	if hasSlungWork {
+		sections.add("state-summary", promptctx.High, func() { outputStateSummary(ctx, hooked) })
+	}
 
 	// Output molecule context if working on a molecule step
-	outputMoleculeContext(ctx)
+	sections.add("molecule-step", promptctx.Required, func() { outputMoleculeContext(ctx) })
 
 	// Output previous session checkpoint for crash recovery
-	outputCheckpointContext(ctx)
+	sections.add("checkpoint", promptctx.Normal, func() { outputCheckpointContext(ctx) })
 
 	// Run bd prime to output beads workflow context
-	if !primeDryRun {
-		runBdPrime(cwd)
-	} else {
-		explain(true, "bd prime: skipped in dry-run mode")
-	}
+	sections.add("bd-prime", promptctx.Low, func() {
+		if !primeDryRun {
+			runBdPrime(cwd)
+		} else {
+			explain(true, "bd prime: skipped in dry-run mode")
+		}
+	})
 
 	// Run gt mail check --inject to inject any pending mail
-	if !primeDryRun {
-		runMailCheckInject(cwd)
-	} else {
-		explain(true, "gt mail check --inject: skipped in dry-run mode")
-	}
+	sections.add("mail", promptctx.High, func() {
+		if !primeDryRun {
+			runMailCheckInject(cwd)
+		} else {
+			explain(true, "gt mail check --inject: skipped in dry-run mode")
+		}
+	})
 
 	// For Mayor, check for pending escalations
 	if ctx.Role == RoleMayor {
-		checkPendingEscalations(ctx)
+		sections.add("escalations", promptctx.High, func() { checkPendingEscalations(ctx) })
 	}
 
 	// Output startup directive for roles that should announce themselves
 	// Skip if in autonomous mode (slung work provides its own directive)
 	if !hasSlungWork {
-		explain(true, "Startup directive: normal mode (no hooked work)")
-		outputStartupDirective(ctx)
+		sections.add("startup-directive", promptctx.Required, func() {
+			explain(true, "Startup directive: normal mode (no hooked work)")
+			outputStartupDirective(ctx)
+		})
 	}
 
+	sections.flush(ctx)
+
 	return nil
 }
 
@@ -386,12 +423,13 @@ func runMailCheckInject(workDir string) {
 
 // checkSlungWork checks for hooked work on the agent's hook.
 // If found, displays AUTONOMOUS WORK MODE and tells the agent to execute immediately.
-// Returns true if hooked work was found (caller should skip normal startup directive).
-func checkSlungWork(ctx RoleContext) bool {
+// Returns the hooked bead's ID if work was found (caller should skip normal
+// startup directive), else "".
+func checkSlungWork(ctx RoleContext) string {
 	// Determine agent identity
 	agentID := getAgentIdentity(ctx)
 	if agentID == "" {
-		return false
+		return ""
 	}
 
 	// Check for hooked beads (work on the agent's hook)
@@ -402,7 +440,7 @@ func checkSlungWork(ctx RoleContext) bool {
 		Priority: -1,
 	})
 	if err != nil {
-		return false
+		return ""
 	}
 
 	// If no hooked beads found, also check in_progress beads assigned to this agent.
@@ -415,14 +453,20 @@ func checkSlungWork(ctx RoleContext) bool {
 			Priority: -1,
 		})
 		if err != nil || len(inProgressBeads) == 0 {
-			return false
+			return ""
 		}
 		hookedBeads = inProgressBeads
 	}
 
 	// Use the first hooked bead (agents typically have one)
-	hookedBead := hookedBeads[0]
+	outputHookedWork(ctx, hookedBeads[0])
+	outputBeadDetails(hookedBeads[0].ID)
+	return hookedBeads[0].ID
+}
 
+// outputHookedWork prints the autonomous-mode directive and the details of
+// the bead on the agent's hook.
+func outputHookedWork(ctx RoleContext, hookedBead *beads.Issue) {
 	// Build the role announcement string
 	roleAnnounce := buildRoleAnnouncement(ctx)
 
@@ -468,18 +512,20 @@ func checkSlungWork(ctx RoleContext) bool {
 		}
 	}
 	fmt.Println()
+}
 
-	// Show bead preview using bd show
+// outputBeadDetails prints the first lines of bd show for a bead.
+func outputBeadDetails(id string) {
 	fmt.Println("**Bead details:**")
-	cmd := exec.Command("bd", "show", hookedBead.ID)
+	cmd := exec.Command("bd", "show", id)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
 		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
-			fmt.Fprintf(os.Stderr, "  bd show %s: %s\n", hookedBead.ID, errMsg)
+			fmt.Fprintf(os.Stderr, "  bd show %s: %s\n", id, errMsg)
 		} else {
-			fmt.Fprintf(os.Stderr, "  bd show %s: %v\n", hookedBead.ID, err)
+			fmt.Fprintf(os.Stderr, "  bd show %s: %v\n", id, err)
 		}
 	} else {
 		lines := strings.Split(stdout.String(), "\n")
@@ -493,8 +539,6 @@ func checkSlungWork(ctx RoleContext) bool {
 		}
 	}
 	fmt.Println()
-
-	return true
 }
 
 // buildRoleAnnouncement creates the role announcement string for autonomous mode.
@@ -584,7 +628,7 @@ func acquireIdentityLock(ctx RoleContext) error {
 				fmt.Printf("Lock holder:\n")
 				fmt.Printf("  PID: %d\n", info.PID)
 				fmt.Printf("  Session: %s\n", info.SessionID)
-				fmt.Printf("  Acquired: %s\n", info.AcquiredAt.Format("2006-01-02 15:04:05"))
+				fmt.Printf("  Acquired: %s\n", format.DateTimeSeconds(info.AcquiredAt))
 				fmt.Println()
 			}
 
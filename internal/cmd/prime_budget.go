@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/promptctx"
+)
+
+// defaultContextBudget is the token budget for gt prime output when the
+// town doesn't set prompts.context_budget. It leaves most of the context
+// window for the work itself.
+const defaultContextBudget = 24000
+
+// primeAssembly collects gt prime's sections for budgeting.
+type primeAssembly struct {
+	components []promptctx.Component
+}
+
+// add runs fn and records what it printed as one component.
+func (p *primeAssembly) add(name string, priority promptctx.Priority, fn func()) {
+	p.components = append(p.components, promptctx.Component{
+		Name:     name,
+		Priority: priority,
+		Text:     captureOutput(fn),
+	})
+}
+
+// flush prints the sections that fit the town's budget, followed by a
+// notice of anything cut, and logs the cuts.
+func (p *primeAssembly) flush(ctx RoleContext) {
+	a := promptctx.Assemble(primeContextBudget(ctx.TownRoot), p.components)
+	fmt.Print(a.Text())
+
+	cut := a.Cut()
+	if len(cut) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println(a.Notice())
+
+	log := logging.For("prime").With(logging.KeyRig, ctx.Rig, logging.KeyPolecat, ctx.Polecat)
+	for _, part := range cut {
+		log.Warn("context "+string(part.Outcome), "section", part.Name, "priority", part.Priority.String(),
+			"tokens", part.Tokens, "kept", part.Kept, "budget", a.Budget)
+	}
+}
+
+// primeContextBudget returns the town's prompt token budget; 0 means
+// unlimited.
+func primeContextBudget(townRoot string) int {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Prompts == nil || settings.Prompts.ContextBudget == 0 {
+		return defaultContextBudget
+	}
+	if settings.Prompts.ContextBudget < 0 {
+		return 0
+	}
+	return settings.Prompts.ContextBudget
+}
+
+// captureOutput runs fn with os.Stdout redirected and returns what it
+// wrote. If stdout can't be redirected fn prints as usual.
+func captureOutput(fn func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		fn()
+		return ""
+	}
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		_ = r.Close()
+		done <- buf.String()
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = w
+	func() {
+		defer func() {
+			os.Stdout = stdout
+			_ = w.Close()
+		}()
+		fn()
+	}()
+	return <-done
+}
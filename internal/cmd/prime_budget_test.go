@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPrimeContextBudget(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := primeContextBudget(townRoot); got != defaultContextBudget {
+		t.Errorf("no settings: %d, want %d", got, defaultContextBudget)
+	}
+
+	for budget, want := range map[int]int{5000: 5000, -1: 0} {
+		path := config.TownSettingsPath(townRoot)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		data := fmt.Sprintf(`{"type":"town-settings","version":1,"prompts":{"context_budget":%d}}`, budget)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := primeContextBudget(townRoot); got != want {
+			t.Errorf("context_budget %d: %d, want %d", budget, got, want)
+		}
+	}
+}
+
+func TestCaptureOutput(t *testing.T) {
+	got := captureOutput(func() { fmt.Println("hello") })
+	if got != "hello\n" {
+		t.Errorf("got %q", got)
+	}
+}
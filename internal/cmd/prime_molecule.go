@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/artifacts"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
@@ -66,31 +67,8 @@ func showMoleculeExecutionPrompt(workDir, moleculeID string) {
 		output.Completed, output.Total)
 
 	// Show current step if available
-	if output.NextStep != nil {
-		step := output.NextStep
-		fmt.Printf("%s\n\n", style.Bold.Render("## 🎬 CURRENT STEP: "+step.Title))
-		fmt.Printf("**Step ID:** %s\n", step.ID)
-		fmt.Printf("**Status:** %s (ready to execute)\n\n", step.Status)
-
-		// Show step description if available
-		if step.Description != "" {
-			fmt.Println("### Instructions")
-			fmt.Println()
-			// Indent the description for readability
-			lines := strings.Split(step.Description, "\n")
-			for _, line := range lines {
-				fmt.Printf("%s\n", line)
-			}
-			fmt.Println()
-		}
-
-		// The propulsion directive
-		fmt.Println(style.Bold.Render("→ EXECUTE THIS STEP NOW."))
-		fmt.Println()
-		fmt.Println("When complete:")
-		fmt.Printf("  1. Close the step: bd close %s\n", step.ID)
-		fmt.Println("  2. Check for next step: bd ready")
-		fmt.Println("  3. Continue until molecule complete")
+	if step := output.NextStep; step != nil {
+		outputCurrentStep(step.ID, step.Title, step.Description, step.Status)
 	} else {
 		// No next step - molecule may be complete
 		fmt.Println(style.Bold.Render("✓ MOLECULE COMPLETE"))
@@ -101,6 +79,34 @@ func showMoleculeExecutionPrompt(workDir, moleculeID string) {
 	}
 }
 
+// outputCurrentStep prints a molecule's ready step with the directive to
+// execute it.
+func outputCurrentStep(id, title, description, status string) {
+	fmt.Printf("%s\n\n", style.Bold.Render("## 🎬 CURRENT STEP: "+title))
+	fmt.Printf("**Step ID:** %s\n", id)
+	fmt.Printf("**Status:** %s (ready to execute)\n\n", status)
+
+	// Show step description if available
+	if description != "" {
+		fmt.Println("### Instructions")
+		fmt.Println()
+		// Indent the description for readability
+		lines := strings.Split(description, "\n")
+		for _, line := range lines {
+			fmt.Printf("%s\n", line)
+		}
+		fmt.Println()
+	}
+
+	// The propulsion directive
+	fmt.Println(style.Bold.Render("→ EXECUTE THIS STEP NOW."))
+	fmt.Println()
+	fmt.Println("When complete:")
+	fmt.Printf("  1. Close the step: bd close %s\n", id)
+	fmt.Println("  2. Check for next step: bd ready")
+	fmt.Println("  3. Continue until molecule complete")
+}
+
 // outputMoleculeContext checks if the agent is working on a molecule step and shows progress.
 func outputMoleculeContext(ctx RoleContext) {
 	// Applies to polecats, crew workers, deacon, witness, and refinery
@@ -167,10 +173,30 @@ func outputMoleculeContext(ctx RoleContext) {
 		fmt.Println("2. Check for next steps: `bd ready --parent " + rootID + "`")
 		fmt.Println("3. Work on next ready step(s)")
 		fmt.Println("4. When all steps done, run `gt done`")
+
+		outputStepArtifacts(ctx.TownRoot, rootID)
 		break // Only show context for first molecule step found
 	}
 }
 
+// outputStepArtifacts lists the artifacts earlier steps of a molecule run
+// stored, and how to store this step's.
+func outputStepArtifacts(townRoot, runID string) {
+	list, err := artifacts.List(townRoot, runID)
+	if err != nil {
+		return
+	}
+	fmt.Println()
+	fmt.Println("**Step artifacts:** save outputs later steps need with `gt artifacts put <name> [file]`.")
+	if len(list) == 0 {
+		return
+	}
+	fmt.Println("Stored so far (read with `gt artifacts get <step>/<name>`):")
+	for _, a := range list {
+		fmt.Printf("  - %s/%s (%s)\n", a.Step, a.Name, formatSize(a.Size))
+	}
+}
+
 // parseMoleculeMetadata extracts molecule info from a step's description.
 // Looks for lines like:
 //
@@ -7,23 +7,22 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/primer"
+	"github.com/steveyegge/gastown/internal/prompts"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/vcs"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // outputPrimeContext outputs the role-specific context using templates or fallback.
 func outputPrimeContext(ctx RoleContext) error {
-	// Try to use templates first
-	tmpl, err := templates.New()
-	if err != nil {
-		// Fall back to hardcoded output if templates fail
-		return outputPrimeContextFallback(ctx)
-	}
-
 	// Map role to template name
 	var roleName string
 	switch ctx.Role {
@@ -70,7 +69,7 @@ func outputPrimeContext(ctx RoleContext) error {
 	}
 
 	// Render and output
-	output, err := tmpl.RenderRole(roleName, data)
+	output, err := renderRolePrompt(ctx.TownRoot, roleName, data)
 	if err != nil {
 		return fmt.Errorf("rendering template: %w", err)
 	}
@@ -79,6 +78,20 @@ func outputPrimeContext(ctx RoleContext) error {
 	return nil
 }
 
+// renderRolePrompt renders a role's prompt from the town's active prompt
+// pack, or the built-in pack if the active one can't be loaded or rendered.
+func renderRolePrompt(townRoot, roleName string, data templates.RoleData) (string, error) {
+	pack, err := prompts.Active(townRoot)
+	if err == nil {
+		var output string
+		if output, err = pack.Render(roleName, data); err == nil {
+			return output, nil
+		}
+	}
+	style.PrintWarning("prompt pack %s: %v (using builtin)", prompts.ActiveVersion(townRoot), err)
+	return prompts.BuiltinPack().Render(roleName, data)
+}
+
 func outputPrimeContextFallback(ctx RoleContext) error {
 	switch ctx.Role {
 	case RoleMayor:
@@ -162,6 +175,67 @@ func outputRefineryContext(ctx RoleContext) {
 	fmt.Printf("Rig: %s\n", style.Dim.Render(ctx.Rig))
 }
 
+// outputRigScope tells agents of a path-scoped rig which part of the
+// monorepo is theirs.
+func outputRigScope(ctx RoleContext) {
+	if ctx.Rig == "" {
+		return
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(ctx.TownRoot))
+	if err != nil {
+		return
+	}
+	subdir := rigsConfig.Rigs[ctx.Rig].Subdir
+	if subdir == "" {
+		return
+	}
+	fmt.Println("## Monorepo Scope")
+	fmt.Printf("Rig %s is `%s/` of a larger repository. Only that directory and the\n", ctx.Rig, subdir)
+	fmt.Println("repository's top-level files are checked out: keep your changes inside it,")
+	fmt.Printf("and run builds and tests from `%s/`.\n", subdir)
+	fmt.Println()
+}
+
+// outputRigVCS tells agents of a jj rig to use jj instead of git.
+func outputRigVCS(ctx RoleContext) {
+	if ctx.Rig == "" {
+		return
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(ctx.TownRoot))
+	if err != nil || rigsConfig.Rigs[ctx.Rig].VCS != vcs.KindJJ {
+		return
+	}
+	fmt.Println("## Jujutsu")
+	fmt.Printf("Rig %s uses jj, not git: your workspace has no .git, so git commands fail.\n", ctx.Rig)
+	fmt.Println("Your branch is a jj bookmark. Where instructions say git, use:")
+	fmt.Println("  jj status / jj diff              see your changes")
+	fmt.Println("  jj commit -m \"...\"               commit them")
+	fmt.Println("  jj bookmark set <branch> -r @-   move your bookmark to the new commit")
+	fmt.Println("  jj git push --bookmark <branch>  push it")
+	fmt.Println()
+}
+
+// outputRepoPrimer prints the rig's repo primer: its layout, key files,
+// conventions and test commands. It is rebuilt when the default branch
+// has moved since it was cached.
+func outputRepoPrimer(ctx RoleContext) {
+	if ctx.Rig == "" {
+		return
+	}
+	rigPath := filepath.Join(ctx.TownRoot, ctx.Rig)
+	branch := ""
+	if rigCfg, err := rig.LoadRigConfig(rigPath); err == nil {
+		branch = rigCfg.DefaultBranch
+	}
+	p, err := primer.Ensure(ctx.TownRoot, ctx.Rig, rigPath, branch, getTestCommand(rigPath))
+	if p == nil {
+		explain(true, fmt.Sprintf("Repo primer: unavailable (%v)", err))
+		return
+	}
+	fmt.Print(p.Text)
+	fmt.Println()
+}
+
 func outputPolecatContext(ctx RoleContext) {
 	fmt.Printf("%s\n\n", style.Bold.Render("# Polecat Context"))
 	fmt.Printf("You are polecat **%s** in rig: %s\n\n",
@@ -281,6 +355,13 @@ func outputStartupDirective(ctx RoleContext) {
 		fmt.Println()
 		fmt.Println("---")
 		fmt.Println()
+		if polecat.IsWarm(filepath.Join(ctx.TownRoot, ctx.Rig), ctx.Polecat) {
+			fmt.Println("**STARTUP PROTOCOL**: You are a warm spare polecat. Please:")
+			fmt.Printf("1. Announce: \"%s Polecat %s, warm and waiting.\"\n", ctx.Rig, ctx.Polecat)
+			fmt.Println("2. Wait. Work will be slung to you with a start prompt;")
+			fmt.Println("   an empty hook until then is expected - do NOT escalate it")
+			break
+		}
 		fmt.Println("**STARTUP PROTOCOL**: You are a polecat. Please:")
 		fmt.Printf("1. Announce: \"%s Polecat %s, checking in.\"\n", ctx.Rig, ctx.Polecat)
 		fmt.Println("2. Check mail: `gt mail inbox`")
@@ -487,6 +568,9 @@ func outputCheckpointContext(ctx RoleContext) {
 	if cp.Notes != "" {
 		fmt.Printf("  **Notes:** %s\n", cp.Notes)
 	}
+	if cp.LastPrompt != "" {
+		fmt.Printf("  **Last prompt:** %s\n", cp.LastPrompt)
+	}
 	fmt.Println()
 
 	fmt.Println("Use this context to resume work. The checkpoint will be updated as you progress.")
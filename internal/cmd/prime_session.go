@@ -20,7 +20,8 @@ import (
 type hookInput struct {
 	SessionID      string `json:"session_id"`
 	TranscriptPath string `json:"transcript_path"`
-	Source         string `json:"source"` // startup, resume, clear, compact
+	Source         string `json:"source"`           // startup, resume, clear, compact
+	StopHookActive bool   `json:"stop_hook_active"` // Stop hooks: already continuing from one
 }
 
 // readHookSessionID reads session ID from available sources in hook mode.
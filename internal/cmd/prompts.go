@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/prompts"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	promptsFrom        string
+	promptsDescription string
+	promptsPack        string
+	promptsRole        string
+)
+
+var promptsCmd = &cobra.Command{
+	Use:     "prompts",
+	GroupID: GroupConfig,
+	Short:   "Manage versioned role prompt packs",
+	RunE:    requireSubcommand,
+	Long: `Manage the prompt packs that give each role its instructions.
+
+'gt prime' primes the mayor, witness, polecats and the other roles with a
+prompt from the town's active pack. Packs live in ~/gt/prompts/<version>/,
+each with a pack.json manifest naming the template parts that make up each
+role's prompt:
+
+  {
+    "schema": 1,
+    "version": "v2",
+    "description": "Polecats run the linter before gt done",
+    "roles": {"polecat": ["polecat.md.tmpl", "house-rules.md"]}
+  }
+
+Parts are Go templates over the same fields as the built-in prompts
+({{ .RigName }}, {{ .Polecat }}, ...), joined in order. Roles a pack leaves
+out use the built-in prompts compiled into gt ("builtin").
+
+Keep the prompts directory in the town's git repo and every change to agent
+behavior is reviewed and versioned like code.
+
+Examples:
+  gt prompts new v2                  # Copy the active pack to start editing
+  gt prompts validate v2
+  gt prompts diff builtin v2
+  gt prompts use v2`,
+}
+
+var promptsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List prompt packs",
+	Args:  cobra.NoArgs,
+	RunE:  runPromptsList,
+}
+
+var promptsShowCmd = &cobra.Command{
+	Use:   "show <role>",
+	Short: "Show a role's composed prompt template",
+	Long: `Show a role's prompt template as the active pack (or --pack) composes it,
+before rendering.
+
+Examples:
+  gt prompts show polecat
+  gt prompts show witness --pack builtin`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPromptsShow,
+}
+
+var promptsNewCmd = &cobra.Command{
+	Use:   "new <version>",
+	Short: "Create a prompt pack from an existing one",
+	Long: `Create ~/gt/prompts/<version>/ with every role's prompt copied from the
+active pack, or --from another version, one part per role. Edit the parts,
+validate, then switch to it with 'gt prompts use'.
+
+Examples:
+  gt prompts new v2
+  gt prompts new v3 --from builtin -d "Start over from upstream"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPromptsNew,
+}
+
+var promptsValidateCmd = &cobra.Command{
+	Use:   "validate [version...]",
+	Short: "Check prompt packs against the schema",
+	Long: `Check each pack's manifest against the schema and that every role's parts
+exist, parse and render. With no versions, checks all of the town's packs.
+Exits non-zero if any pack is invalid.
+
+Examples:
+  gt prompts validate
+  gt prompts validate v2`,
+	RunE: runPromptsValidate,
+}
+
+var promptsUseCmd = &cobra.Command{
+	Use:   "use <version>",
+	Short: "Switch the town to a prompt pack",
+	Long: `Make a pack the town's active one. It must validate. Agents pick it up the
+next time they are primed (new spawns, handoffs, 'gt prime').
+
+Examples:
+  gt prompts use v2
+  gt prompts use builtin`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPromptsUse,
+}
+
+var promptsDiffCmd = &cobra.Command{
+	Use:   "diff <from> [to]",
+	Short: "Show what changed between prompt pack versions",
+	Long: `Show a unified diff of each role's composed prompt between two packs.
+[to] defaults to the active pack.
+
+Examples:
+  gt prompts diff builtin            # Local changes to the active pack
+  gt prompts diff v1 v2 --role polecat`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runPromptsDiff,
+}
+
+func init() {
+	promptsShowCmd.Flags().StringVar(&promptsPack, "pack", "", "Pack version (default: active)")
+	promptsNewCmd.Flags().StringVar(&promptsFrom, "from", "", "Version to copy (default: active)")
+	promptsNewCmd.Flags().StringVarP(&promptsDescription, "description", "d", "", "What this version changes")
+	promptsDiffCmd.Flags().StringVar(&promptsRole, "role", "", "Only this role's prompt")
+
+	promptsCmd.AddCommand(promptsListCmd)
+	promptsCmd.AddCommand(promptsShowCmd)
+	promptsCmd.AddCommand(promptsNewCmd)
+	promptsCmd.AddCommand(promptsValidateCmd)
+	promptsCmd.AddCommand(promptsUseCmd)
+	promptsCmd.AddCommand(promptsDiffCmd)
+
+	rootCmd.AddCommand(promptsCmd)
+}
+
+func runPromptsList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	versions, err := prompts.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing prompt packs: %w", err)
+	}
+	active := prompts.ActiveVersion(townRoot)
+
+	for _, version := range append([]string{prompts.Builtin}, versions...) {
+		marker := "  "
+		if version == active {
+			marker = style.Bold.Render("* ")
+		}
+		p, err := prompts.Load(townRoot, version)
+		if err != nil {
+			fmt.Printf("%s%s  %s\n", marker, version, style.Error.Render(err.Error()))
+			continue
+		}
+		var roles []string
+		for _, role := range prompts.Roles {
+			if p.Overrides(role) {
+				roles = append(roles, role)
+			}
+		}
+		detail := p.Description
+		if version != prompts.Builtin {
+			detail = strings.TrimSpace(fmt.Sprintf("%s [%s]", detail, strings.Join(roles, ", ")))
+		}
+		fmt.Printf("%s%-12s %s\n", marker, version, style.Dim.Render(detail))
+	}
+	return nil
+}
+
+func runPromptsShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	version := promptsPack
+	if version == "" {
+		version = prompts.ActiveVersion(townRoot)
+	}
+	p, err := prompts.Load(townRoot, version)
+	if err != nil {
+		return err
+	}
+	src, err := p.Source(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(src)
+	return nil
+}
+
+func runPromptsNew(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	from := promptsFrom
+	if from == "" {
+		from = prompts.ActiveVersion(townRoot)
+	}
+	base, err := prompts.Load(townRoot, from)
+	if err != nil {
+		return err
+	}
+	p, err := prompts.Create(townRoot, args[0], promptsDescription, base)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Created prompt pack %s from %s\n", style.Bold.Render("✓"), p.Version, from)
+	fmt.Printf("  %s\n", style.Dim.Render(p.Dir))
+	fmt.Printf("  Edit the parts, then: gt prompts validate %s && gt prompts use %s\n", p.Version, p.Version)
+	return nil
+}
+
+func runPromptsValidate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	versions := args
+	if len(versions) == 0 {
+		if versions, err = prompts.List(townRoot); err != nil {
+			return fmt.Errorf("listing prompt packs: %w", err)
+		}
+		if len(versions) == 0 {
+			fmt.Println("No prompt packs in this town (using builtin)")
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, version := range versions {
+		p, err := prompts.Load(townRoot, version)
+		if err == nil {
+			err = p.Validate()
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s\n", style.Error.Render("✗"), version)
+			for _, line := range strings.Split(err.Error(), "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+			continue
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("✓"), version)
+	}
+	if failed > 0 {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runPromptsUse(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	version := args[0]
+	p, err := prompts.Load(townRoot, version)
+	if err != nil {
+		return err
+	}
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("prompt pack %s is invalid:\n%w", version, err)
+	}
+
+	settingsPath := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if version == prompts.Builtin {
+		settings.Prompts = nil
+	} else {
+		settings.Prompts = &config.PromptsConfig{Pack: version}
+	}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Town now uses prompt pack %s\n", style.Bold.Render("✓"), version)
+	fmt.Println("  Running agents pick it up when next primed.")
+	return nil
+}
+
+func runPromptsDiff(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	toVersion := prompts.ActiveVersion(townRoot)
+	if len(args) > 1 {
+		toVersion = args[1]
+	}
+	from, err := prompts.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	to, err := prompts.Load(townRoot, toVersion)
+	if err != nil {
+		return err
+	}
+
+	roles := prompts.Roles
+	if promptsRole != "" {
+		roles = []string{promptsRole}
+	}
+	changed := 0
+	for _, role := range roles {
+		a, err := from.Source(role)
+		if err != nil {
+			return err
+		}
+		b, err := to.Source(role)
+		if err != nil {
+			return err
+		}
+		if d := prompts.Diff(from.Version+"/"+role, to.Version+"/"+role, a, b); d != "" {
+			changed++
+			fmt.Print(d)
+		}
+	}
+	if changed == 0 {
+		fmt.Printf("No differences between %s and %s\n", from.Version, to.Version)
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/authz"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/quality"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	qualityOnto string
+	qualityJSON bool
+)
+
+var qualityCmd = &cobra.Command{
+	Use:     "quality",
+	GroupID: GroupWork,
+	Short:   "Score the work in this worktree against the rig's quality gate",
+	Long: `Run the rig's quality gate on the current worktree and print the score.
+
+The gate is configured in the rig's settings/config.json. Each configured
+check scores 0-100%, and the score is their weighted mean:
+
+  build   build_command exits cleanly (a failed build always bounces)
+  tests   share of tests passing in test_command's output
+          (default: merge_queue.test_command)
+  lint    lint_command's output lines, against max_lint_findings
+  diff    insertions plus deletions against the target, against
+          max_diff_lines
+
+    "quality_gate": {
+      "enabled": true,
+      "min_score": 70,
+      "build_command": "go build ./...",
+      "lint_command": "golangci-lint run --out-format line-number",
+      "max_diff_lines": 800,
+      "weights": {"build": 3, "tests": 4, "lint": 2, "diff": 1}
+    }
+
+'gt done' and 'gt mq submit' score the work before submitting and refuse
+to submit work below min_score. Anyone but a polecat can skip it with
+--no-quality-gate; the gate is there for a polecat's own work. From gt
+done, the molecule's implement step is reopened and pinned back to the
+polecat with the score report appended, and the report is added to the
+issue, so the work is reworked instead of reaching the refinery.
+
+Exits 1 when the work is below the bar.
+
+Examples:
+  gt quality                          # Score the current branch
+  gt quality --onto integration/gt-ep # Size the diff against another branch
+  gt quality --json`,
+	Args: cobra.NoArgs,
+	RunE: runQuality,
+}
+
+func init() {
+	qualityCmd.Flags().StringVar(&qualityOnto, "onto", "", "Branch the diff is measured against (default: the rig's default branch)")
+	qualityCmd.Flags().BoolVar(&qualityJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(qualityCmd)
+}
+
+func runQuality(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, r, err := findCurrentRig(townRoot)
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	g := git.NewGit(cwd)
+	branch, err := g.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("getting current branch: %w", err)
+	}
+	target := qualityOnto
+	if target == "" {
+		target = defaultBranchFor(r.Path)
+	}
+
+	report, err := scoreWork(r, g, branch, target)
+	if err != nil {
+		return err
+	}
+	if report == nil {
+		fmt.Printf("%s No quality gate configured for %s (see gt quality --help)\n", style.Dim.Render("○"), r.Name)
+		return nil
+	}
+	if qualityJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Print(report.Markdown())
+	}
+	if !report.Passed {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// scoreWork runs a rig's quality gate on the worktree g is in, measuring
+// the diff from origin/<target>. It returns nil when the rig has no gate.
+func scoreWork(r *rig.Rig, g *git.Git, branch, target string) (*quality.Report, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil {
+		return nil, nil
+	}
+	cfg, ok := quality.ConfigFrom(settings)
+	if !ok {
+		return nil, nil
+	}
+	diff, err := g.DiffStat("origin/"+target, branch)
+	if err != nil {
+		style.PrintWarning("could not measure the diff against %s: %v", target, err)
+	}
+	dir := g.WorkDir()
+	if r.Subdir != "" {
+		dir = filepath.Join(dir, r.Subdir)
+	}
+	fmt.Printf("%s Scoring work against the quality gate (needs %d)\n", style.Bold.Render("→"), cfg.MinScore)
+	return quality.Score(cfg, dir, diff, qualityRunner(r.Path)), nil
+}
+
+// qualityRunner runs gate commands with the rig's environment.
+func qualityRunner(rigPath string) quality.Runner {
+	rigEnv, err := config.ResolveRigEnv(rigPath)
+	if err != nil {
+		style.PrintWarning("rig env: %v", err)
+	}
+	return func(dir, command string) (string, error) {
+		cmd := exec.Command("sh", "-c", command) //nolint:gosec // G204: gate commands are from trusted rig settings
+		cmd.Dir = dir
+		if len(rigEnv) > 0 {
+			cmd.Env = os.Environ()
+			for name, value := range rigEnv {
+				cmd.Env = append(cmd.Env, name+"="+value)
+			}
+		}
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+}
+
+// requireQuality runs the quality gate for a submit step and fails if the
+// work scores below the bar. The report goes on the issue; with bounce, the
+// molecule's implement step is also reopened with it (see
+// bounceToImplement).
+func requireQuality(r *rig.Rig, g *git.Git, branch, target, townRoot, issueID, agent, retry string, bounce bool) error {
+	report, err := scoreWork(r, g, branch, target)
+	if err != nil || report == nil {
+		return err
+	}
+	if report.Passed {
+		fmt.Printf("%s Quality gate passed: %d/100\n", style.Bold.Render("✓"), report.Score)
+		return nil
+	}
+	fmt.Print(report.Markdown())
+
+	bd := beads.New(beads.ResolveBeadsDir(g.WorkDir()))
+	if err := bd.Comment(issueID, report.Markdown()); err != nil {
+		style.PrintWarning("could not add the score report to %s: %v", issueID, err)
+	}
+	if bounce {
+		stepID, err := bounceToImplement(townRoot, g.WorkDir(), issueID, agent, report)
+		if err != nil {
+			style.PrintWarning("could not reopen the implement step: %v", err)
+		} else if stepID != "" {
+			fmt.Printf("\n%s %s is pinned to you again with the score report appended.\n", style.Bold.Render("↩"), stepID)
+			fmt.Printf("  Rework it, run 'gt mol step done %s', then %s again.\n", stepID, retry)
+			return fmt.Errorf("quality score %d is below %d; back to the implement step (%s)", report.Score, report.MinScore, stepID)
+		}
+	}
+	if checkQualityGateSkip(townRoot) != nil {
+		return fmt.Errorf("quality score %d is below %d; improve the work and run %s again", report.Score, report.MinScore, retry)
+	}
+	return fmt.Errorf("quality score %d is below %d; improve the work and run %s again (or pass --no-quality-gate)", report.Score, report.MinScore, retry)
+}
+
+// checkQualityGateSkip refuses --no-quality-gate to polecats, who would
+// otherwise waive the gate on their own work: callers with a polecat role
+// token, or working in a polecat's worktree. Crew and the overseer, who
+// often have no token, may skip it.
+func checkQualityGateSkip(townRoot string) error {
+	refused := fmt.Errorf("--no-quality-gate is not available to polecats; improve the work until it passes (see gt quality)")
+	if token := os.Getenv(authz.EnvToken); token != "" {
+		id, err := authz.Verify(townRoot, token)
+		if err != nil {
+			return fmt.Errorf("%s: %w", authz.EnvToken, err)
+		}
+		if id.Role == constants.RolePolecat {
+			return refused
+		}
+	}
+	cwd, _ := os.Getwd()
+	if _, home := rigHomeDir(townRoot, cwd); home == "polecats" {
+		return refused
+	}
+	return nil
+}
+
+// bounceToImplement sends an issue's molecule back to its implement step:
+// the step is reopened and pinned with the score report appended, and the
+// step in progress (the submit step) goes back to open, so it comes up
+// again once the rework is done. It returns the implement step's ID, or ""
+// when the issue has no molecule with an implement step.
+func bounceToImplement(townRoot, cwd, issueID, agent string, report *quality.Report) (string, error) {
+	issue, err := beads.New(beads.ResolveBeadsDir(cwd)).Show(issueID)
+	if err != nil {
+		return "", fmt.Errorf("showing %s: %w", issueID, err)
+	}
+	attachment := beads.ParseAttachmentFields(issue)
+	if attachment == nil || attachment.AttachedMolecule == "" {
+		return "", nil
+	}
+	molID := attachment.AttachedMolecule
+	b := beads.New(beads.ResolveHookDir(townRoot, molID, cwd))
+	steps, err := b.List(beads.ListOptions{Parent: molID, Status: "all", Priority: -1})
+	if err != nil {
+		return "", fmt.Errorf("listing steps of %s: %w", molID, err)
+	}
+	var implement *beads.Issue
+	for _, s := range steps {
+		if beads.StepRef(s) == "implement" {
+			implement = s
+			break
+		}
+	}
+	if implement == nil {
+		return "", nil
+	}
+
+	assignee := agent
+	for _, s := range steps {
+		if s.ID == implement.ID || (s.Status != "pinned" && s.Status != "in_progress") {
+			continue
+		}
+		if s.Assignee != "" {
+			assignee = s.Assignee
+		}
+		open := "open"
+		if err := b.Update(s.ID, beads.UpdateOptions{Status: &open}); err != nil {
+			return "", fmt.Errorf("reopening %s: %w", s.ID, err)
+		}
+	}
+	desc := fmt.Sprintf("%s\n\n%s\nBounced back here at %s: rework until the gate passes, then submit again.\n",
+		implement.Description, report.Markdown(), time.Now().Format("2006-01-02 15:04"))
+	status := "pinned"
+	opts := beads.UpdateOptions{Status: &status, Description: &desc}
+	if assignee != "" {
+		opts.Assignee = &assignee
+	}
+	if err := b.Update(implement.ID, opts); err != nil {
+		return "", fmt.Errorf("reopening %s: %w", implement.ID, err)
+	}
+	return implement.ID, nil
+}
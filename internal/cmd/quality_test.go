@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/authz"
+)
+
+func TestCheckQualityGateSkip(t *testing.T) {
+	town := t.TempDir()
+	t.Chdir(town)
+	t.Setenv(authz.EnvToken, "")
+	for _, key := range agentSessionEnv {
+		t.Setenv(key, "")
+	}
+
+	if err := checkQualityGateSkip(town); err != nil {
+		t.Errorf("overseer skipping the gate = %v", err)
+	}
+
+	sign := func(id authz.Identity) {
+		token, err := authz.Sign(town, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(authz.EnvToken, token)
+	}
+	sign(authz.Identity{Role: "polecat", Rig: "greenplace", Agent: "Toast"})
+	if err := checkQualityGateSkip(town); err == nil {
+		t.Error("a polecat skipped its own quality gate")
+	}
+	sign(authz.Identity{Role: "crew", Rig: "greenplace", Agent: "max"})
+	if err := checkQualityGateSkip(town); err != nil {
+		t.Errorf("crew skipping the gate = %v", err)
+	}
+
+	// Crew members are people, and often run without a token
+	t.Setenv(authz.EnvToken, "")
+	crew := filepath.Join(town, "greenplace", "crew", "max")
+	if err := os.MkdirAll(crew, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(crew)
+	t.Setenv("TMUX", "/tmp/tmux-0/default,1,0")
+	if err := checkQualityGateSkip(town); err != nil {
+		t.Errorf("crew clone without a token skipping the gate = %v", err)
+	}
+
+	// Dropping the token doesn't get a polecat past the gate
+	worktree := filepath.Join(town, "greenplace", "polecats", "Toast")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(worktree)
+	if err := checkQualityGateSkip(town); err == nil {
+		t.Error("a polecat worktree without a token skipped the quality gate")
+	}
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/models"
+	"github.com/steveyegge/gastown/internal/ratelimit"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	ratelimitJSON       bool
+	ratelimitRetryAfter time.Duration
+	ratelimitTimeout    time.Duration
+)
+
+var ratelimitCmd = &cobra.Command{
+	Use:     "ratelimit",
+	GroupID: GroupServices,
+	Short:   "Town-wide pacing of model API requests",
+	Long: `Pace model API requests across the town so a convoy of polecats doesn't
+trip a provider's rate limits and fail together.
+
+Limits are set per provider in ~/gt/settings/config.json:
+
+  "rate_limits": {"anthropic": {"requests_per_minute": 50, "burst": 10}}
+
+Agent runners call 'gt ratelimit wait <provider>' before a request, and
+'gt ratelimit hit <provider>' when one is answered with 429. A 429 makes
+every caller of the provider back off, twice as long for each 429 in a
+row (5s up to max_backoff_seconds, default 300), until 'gt ratelimit ok'
+records a success. While a provider backs off, new slings are queued and
+the spawn queue is held.
+
+COMMANDS:
+  status  Show each provider's budget and backoff
+  wait    Block until a request may go
+  hit     Record a 429
+  ok      Record a successful request`,
+	RunE: requireSubcommand,
+}
+
+var ratelimitStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show each provider's budget and backoff",
+	Args:  cobra.NoArgs,
+	RunE:  runRatelimitStatus,
+}
+
+var ratelimitWaitCmd = &cobra.Command{
+	Use:   "wait <provider>",
+	Short: "Block until a request to the provider may go",
+	Long: `Block until the provider's shared budget has room and any backoff is over,
+then spend one request. Exits non-zero if --timeout passes first.
+
+Examples:
+  gt ratelimit wait anthropic
+  gt ratelimit wait openai --timeout 2m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRatelimitWait,
+}
+
+var ratelimitHitCmd = &cobra.Command{
+	Use:   "hit <provider>",
+	Short: "Record a 429 from the provider",
+	Long: `Record that the provider answered 429, backing off every caller.
+
+Examples:
+  gt ratelimit hit anthropic
+  gt ratelimit hit anthropic --retry-after 30s`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRatelimitHit,
+}
+
+var ratelimitOKCmd = &cobra.Command{
+	Use:   "ok <provider>",
+	Short: "Record a successful request, resetting the backoff",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRatelimitOK,
+}
+
+func init() {
+	ratelimitStatusCmd.Flags().BoolVar(&ratelimitJSON, "json", false, "Output as JSON")
+	ratelimitWaitCmd.Flags().DurationVar(&ratelimitTimeout, "timeout", 0, "Give up after this long (0 = wait as long as it takes)")
+	ratelimitHitCmd.Flags().DurationVar(&ratelimitRetryAfter, "retry-after", 0, "The provider's Retry-After, if it sent one")
+	ratelimitCmd.AddCommand(ratelimitStatusCmd, ratelimitWaitCmd, ratelimitHitCmd, ratelimitOKCmd)
+	rootCmd.AddCommand(ratelimitCmd)
+}
+
+// loadLimiter returns the town's rate limiter.
+func loadLimiter() (*ratelimit.Limiter, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return ratelimit.Load(townRoot)
+}
+
+// spawnProvider returns the provider a sling's polecat will call: its
+// tier's, or Anthropic's without a tier.
+func spawnProvider(townRoot string) string {
+	if slingTier == "" {
+		return models.Anthropic
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return models.Anthropic
+	}
+	tier, err := models.Find(settings.Models, slingTier)
+	if err != nil {
+		return models.Anthropic
+	}
+	return tier.Provider
+}
+
+// providerBackoff reports whether any provider is backing off, and why.
+func providerBackoff(townRoot string) (bool, string, error) {
+	limiter, err := ratelimit.Load(townRoot)
+	if err != nil {
+		return false, "", err
+	}
+	status, err := limiter.Status()
+	if err != nil {
+		return false, "", fmt.Errorf("reading rate limit state: %w", err)
+	}
+	names := make([]string, 0, len(status))
+	for name := range status {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if until := status[name].BackoffUntil; time.Now().Before(until) {
+			return true, fmt.Sprintf("%s rate limited until %s", name, until.Local().Format("15:04:05")), nil
+		}
+	}
+	return false, "", nil
+}
+
+func runRatelimitStatus(cmd *cobra.Command, args []string) error {
+	limiter, err := loadLimiter()
+	if err != nil {
+		return err
+	}
+	status, err := limiter.Status()
+	if err != nil {
+		return err
+	}
+	if ratelimitJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+	if len(status) == 0 {
+		fmt.Printf("%s No rate limits configured and no 429s recorded\n", style.Dim.Render("○"))
+		return nil
+	}
+	names := make([]string, 0, len(status))
+	for name := range status {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	now := time.Now()
+	for _, name := range names {
+		p := status[name]
+		state := style.Success.Render("ok")
+		if now.Before(p.BackoffUntil) {
+			state = style.Warning.Render(fmt.Sprintf("backing off %s (%d 429s in a row)", p.BackoffUntil.Sub(now).Round(time.Second), p.Strikes))
+		}
+		fmt.Printf("%-10s %s  %s\n", name, state, style.Dim.Render(fmt.Sprintf("%.1f request(s) available", p.Tokens)))
+	}
+	return nil
+}
+
+func runRatelimitWait(cmd *cobra.Command, args []string) error {
+	limiter, err := loadLimiter()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if ratelimitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ratelimitTimeout)
+		defer cancel()
+	}
+	if err := limiter.Wait(ctx, args[0]); err != nil {
+		return fmt.Errorf("waiting for %s: %w", args[0], err)
+	}
+	return nil
+}
+
+func runRatelimitHit(cmd *cobra.Command, args []string) error {
+	limiter, err := loadLimiter()
+	if err != nil {
+		return err
+	}
+	backoff, err := limiter.Hit(args[0], ratelimitRetryAfter)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s backing off for %s\n", style.Bold.Render("⏸"), args[0], backoff)
+	return nil
+}
+
+func runRatelimitOK(cmd *cobra.Command, args []string) error {
+	limiter, err := loadLimiter()
+	if err != nil {
+		return err
+	}
+	return limiter.Succeeded(args[0])
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// defaultAssignMolecule is the formula auto-assignment applies when town
+// settings name none for a bead's type.
+const defaultAssignMolecule = "mol-polecat-work"
+
+var (
+	readyRig   string
+	readyLimit int
+	readyJSON  bool
+
+	assignAuto    int
+	assignRig     string
+	assignFormula string
+	assignDryRun  bool
+)
+
+var readyCmd = &cobra.Command{
+	Use:     "ready",
+	GroupID: GroupWork,
+	Short:   "List work that is ready to start, most urgent first",
+	Long: `List every rig's beads that can be picked up now: open, unassigned, and
+with all their dependencies closed. Epics, molecule steps and bookkeeping
+beads (agents, mail, merge requests) are left out.
+
+Beads are sorted by priority (P0 first), then oldest first. This is the
+order 'gt assign --auto' dispatches them in.
+
+Examples:
+  gt ready
+  gt ready --rig greenplace -n 5
+  gt ready --json`,
+	Args: cobra.NoArgs,
+	RunE: runReady,
+}
+
+var assignCmd = &cobra.Command{
+	Use:     "assign [bead-id] [agent]",
+	GroupID: GroupWork,
+	Short:   "Assign a bead, or dispatch the most urgent ready work",
+	Long: `With a bead, assign it to an agent (default: yourself), as 'gt bead assign'.
+
+With --auto N, sling the top N beads from 'gt ready' to fresh polecats in
+their rigs, each with a molecule for its type: the formula named in
+"scheduler": {"molecules": {"bug": "shiny", "*": "mol-polecat-work"}} in
+settings/config.json, or mol-polecat-work. --formula overrides it.
+
+Beads are only dispatched while their rig and the town have polecat slots
+free (max_polecats); the rest are left for the next run rather than
+queued.
+
+Examples:
+  gt assign gt-abc12 gastown/polecats/Toast
+  gt assign --auto 3
+  gt assign --auto 5 --rig greenplace --dry-run`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runAssign,
+}
+
+func init() {
+	readyCmd.Flags().StringVar(&readyRig, "rig", "", "Only this rig's beads")
+	readyCmd.Flags().IntVarP(&readyLimit, "limit", "n", 0, "Maximum number of beads to show (0 = all)")
+	readyCmd.Flags().BoolVar(&readyJSON, "json", false, "Output as JSON")
+
+	assignCmd.Flags().IntVar(&assignAuto, "auto", 0, "Dispatch the N most urgent ready beads to new polecats")
+	assignCmd.Flags().StringVar(&assignRig, "rig", "", "With --auto, only this rig's beads")
+	assignCmd.Flags().StringVar(&assignFormula, "formula", "", "With --auto, apply this formula to every bead")
+	assignCmd.Flags().BoolVarP(&assignDryRun, "dry-run", "n", false, "With --auto, show what would be dispatched")
+
+	rootCmd.AddCommand(readyCmd)
+	rootCmd.AddCommand(assignCmd)
+}
+
+// readyBead is a ready bead and the rig it belongs to.
+type readyBead struct {
+	Rig string `json:"rig"`
+	*beads.Issue
+}
+
+// collectReady returns the ready beads of every rig, or only rigName's,
+// most urgent first. A rig whose beads can't be read only warns.
+func collectReady(townRoot, rigName string) ([]readyBead, error) {
+	rigs, err := discoverAllRigs(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing rigs: %w", err)
+	}
+	rigOf := map[*beads.Issue]string{}
+	var all []*beads.Issue
+	found := rigName == ""
+	for _, r := range rigs {
+		if rigName != "" && r.Name != rigName {
+			continue
+		}
+		found = true
+		issues, err := beads.New(r.BeadsPath()).ReadyWork()
+		if err != nil {
+			style.PrintWarning("could not list %s beads: %v", r.Name, err)
+			continue
+		}
+		for _, i := range issues {
+			rigOf[i] = r.Name
+		}
+		all = append(all, issues...)
+	}
+	if !found {
+		return nil, fmt.Errorf("rig '%s' not found", rigName)
+	}
+
+	beads.SortByUrgency(all)
+	ready := make([]readyBead, 0, len(all))
+	for _, i := range all {
+		ready = append(ready, readyBead{Rig: rigOf[i], Issue: i})
+	}
+	return ready, nil
+}
+
+func runReady(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	ready, err := collectReady(townRoot, readyRig)
+	if err != nil {
+		return err
+	}
+	if readyLimit > 0 && len(ready) > readyLimit {
+		ready = ready[:readyLimit]
+	}
+
+	if readyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ready)
+	}
+
+	if len(ready) == 0 {
+		fmt.Println("No ready work")
+		return nil
+	}
+	fmt.Printf("%s %d bead(s) ready:\n\n", style.Bold.Render("📋"), len(ready))
+	for _, b := range ready {
+		age := ""
+		if created, err := time.Parse(time.RFC3339, b.CreatedAt); err == nil {
+			age = formatAge(created)
+		}
+		fmt.Printf("  P%d %-12s %s  %s\n", b.Priority, b.ID, b.Title,
+			style.Dim.Render(fmt.Sprintf("[%s · %s · %s]", b.Rig, b.Type, age)))
+	}
+	return nil
+}
+
+func runAssign(cmd *cobra.Command, args []string) error {
+	if assignAuto == 0 {
+		if len(args) == 0 {
+			return fmt.Errorf("name a bead to assign, or use --auto N")
+		}
+		return runBeadAssign(cmd, args)
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("--auto picks the beads itself; don't name any")
+	}
+	if assignAuto < 0 {
+		return fmt.Errorf("--auto must be positive")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	ready, err := collectReady(townRoot, assignRig)
+	if err != nil {
+		return err
+	}
+	if len(ready) == 0 {
+		fmt.Println("No ready work to assign")
+		return nil
+	}
+	state, err := scheduler.Load(townRoot)
+	if err != nil {
+		return fmt.Errorf("checking spawn limits: %w", err)
+	}
+	var molecules map[string]string
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.Scheduler != nil {
+		molecules = settings.Scheduler.Molecules
+	}
+
+	dispatched := 0
+	for _, b := range ready {
+		if dispatched == assignAuto {
+			break
+		}
+		if ok, reason := state.Limits.Check(state.Running, b.Rig); !ok {
+			fmt.Printf("  %s %s: %s\n", style.Dim.Render("○"), b.ID, reason)
+			continue
+		}
+		formula := assignMolecule(b.Issue, molecules)
+		slingArgs := []string{"sling", formula, "--on", b.ID, b.Rig}
+		if assignDryRun {
+			fmt.Printf("Would run: gt %s\n", strings.Join(slingArgs, " "))
+		} else {
+			fmt.Printf("%s P%d %s → %s (%s)\n", style.Bold.Render("→"), b.Priority, b.ID, b.Rig, formula)
+			c := exec.Command("gt", slingArgs...)
+			c.Stdout = os.Stdout
+			c.Stderr = os.Stderr
+			if err := c.Run(); err != nil {
+				style.PrintWarning("could not dispatch %s: %v", b.ID, err)
+				continue
+			}
+		}
+		state.Running[b.Rig]++
+		dispatched++
+	}
+
+	if dispatched < assignAuto {
+		fmt.Printf("\n%s Dispatched %d of %d: no more ready work fits under the polecat limits\n",
+			style.Warning.Render("⚠"), dispatched, assignAuto)
+	} else {
+		fmt.Printf("\n%s Dispatched %d bead(s)\n", style.Bold.Render("✓"), dispatched)
+	}
+	return nil
+}
+
+// assignMolecule returns the formula auto-assignment applies to a bead:
+// --formula, else the molecule its template named, else the one for its
+// type.
+func assignMolecule(issue *beads.Issue, molecules map[string]string) string {
+	if assignFormula != "" {
+		return assignFormula
+	}
+	if f := beadMolecule(issue.Labels); f != "" {
+		return f
+	}
+	if f := molecules[issue.Type]; f != "" {
+		return f
+	}
+	if f := molecules["*"]; f != "" {
+		return f
+	}
+	return defaultAssignMolecule
+}
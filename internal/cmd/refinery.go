@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/mrqueue"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -205,6 +209,100 @@ Examples:
 
 var refineryBlockedJSON bool
 
+var refineryConflictCmd = &cobra.Command{
+	Use:   "conflict <mr-id> [rig]",
+	Short: "Hand a conflicting MR to a polecat for resolution",
+	Long: `Hand a merge request that failed to rebase or merge to conflict resolution.
+
+Re-checks the MR against its target and, if it conflicts:
+- Notifies the witness (MERGE_FAILED)
+- Creates a "Resolve merge conflicts" task with the conflicted files and hunks
+- Blocks the MR on that task, so the queue moves on
+- Slings mol-polecat-conflict-resolve on the task, to the original polecat
+  if it is still running, otherwise to a fresh one
+
+The MR returns to 'ready' when the task closes. Set
+merge_queue.auto_resolve_conflicts to false in the rig's config.json to
+create the task without dispatching it.
+
+Examples:
+  gt refinery conflict gt-abc123
+  gt refinery conflict gt-abc123 greenplace`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRefineryConflict,
+}
+
+var refineryTrainCmd = &cobra.Command{
+	Use:   "train [rig]",
+	Short: "Merge the head of a deep queue as a merge train",
+	Long: `Merge several ready MRs at once when the queue is deep.
+
+Takes the top ready MR and the ones behind it with the same target, up to
+merge_queue.max_train_length, merges them onto the target together and
+runs the tests once. If the tests pass, the whole train is pushed. If they
+fail, the train is bisected to find the MR that broke them; that MR fails
+back to its polecat and the rest are tested again. MRs that conflict with
+the train are handed to conflict resolution and left out.
+
+Trains are off until max_train_length is 2 or more in the rig's
+config.json, and only run once train_queue_depth MRs (default 3) are
+ready. When there is no train to run, this prints so and exits 0: merge
+the top MR on its own.
+
+  "merge_queue": {
+    "max_train_length": 4,
+    "train_queue_depth": 3
+  }
+
+The latest train's progress is kept in .runtime/merge-train.json; --status
+shows it.
+
+Examples:
+  gt refinery train
+  gt refinery train greenplace --json
+  gt refinery train --status`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryTrain,
+}
+
+var (
+	refineryTrainJSON   bool
+	refineryTrainStatus bool
+)
+
+var refineryPostMergeCmd = &cobra.Command{
+	Use:   "post-merge <bead> [rig]",
+	Short: "Run the rig's post-merge command for a merged bead",
+	Long: `Run the rig's post-merge command (a deploy script, container build and
+the like) after merging a bead's work by hand.
+
+The command is merge_queue.post_merge_command in the rig's config.json. It
+runs in the refinery's clone with the rig's env plus GT_MERGE_COMMIT,
+GT_TARGET_BRANCH and GT_MERGED_BEADS, for up to post_merge_timeout
+(default 30m):
+
+  "merge_queue": {
+    "post_merge_command": "./scripts/deploy.sh staging",
+    "post_merge_timeout": "15m"
+  }
+
+The output is commented on the bead and kept in
+.runtime/post-merge/<commit>.log. A failure files an escalation and exits
+1; the merge itself stands. The refinery runs the command on its own
+after merges it pushes (gt refinery train included).
+
+Examples:
+  gt refinery post-merge gt-abc12
+  gt refinery post-merge gt-abc12 greenplace --commit 1a2b3c4`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRefineryPostMerge,
+}
+
+var (
+	refineryPostMergeCommit string
+	refineryPostMergeJSON   bool
+)
+
 func init() {
 	// Start flags
 	refineryStartCmd.Flags().BoolVar(&refineryForeground, "foreground", false, "Run in foreground (default: background)")
@@ -224,6 +322,14 @@ func init() {
 	// Blocked flags
 	refineryBlockedCmd.Flags().BoolVar(&refineryBlockedJSON, "json", false, "Output as JSON")
 
+	// Train flags
+	refineryTrainCmd.Flags().BoolVar(&refineryTrainJSON, "json", false, "Output as JSON")
+	refineryTrainCmd.Flags().BoolVar(&refineryTrainStatus, "status", false, "Show the latest train instead of running one")
+
+	// Post-merge flags
+	refineryPostMergeCmd.Flags().StringVar(&refineryPostMergeCommit, "commit", "", "Merge commit (default: the refinery clone's HEAD)")
+	refineryPostMergeCmd.Flags().BoolVar(&refineryPostMergeJSON, "json", false, "Output as JSON")
+
 	// Add subcommands
 	refineryCmd.AddCommand(refineryStartCmd)
 	refineryCmd.AddCommand(refineryStopCmd)
@@ -236,6 +342,9 @@ func init() {
 	refineryCmd.AddCommand(refineryUnclaimedCmd)
 	refineryCmd.AddCommand(refineryReadyCmd)
 	refineryCmd.AddCommand(refineryBlockedCmd)
+	refineryCmd.AddCommand(refineryConflictCmd)
+	refineryCmd.AddCommand(refineryTrainCmd)
+	refineryCmd.AddCommand(refineryPostMergeCmd)
 
 	rootCmd.AddCommand(refineryCmd)
 }
@@ -356,7 +465,7 @@ func runRefineryStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  State: %s\n", stateStr)
 
 	if ref.StartedAt != nil {
-		fmt.Printf("  Started: %s\n", ref.StartedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("  Started: %s\n", format.DateTimeSeconds(*ref.StartedAt))
 	}
 
 	if ref.CurrentMR != nil {
@@ -379,7 +488,7 @@ func runRefineryStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("\n  Queue: %d pending\n", pendingCount)
 
 	if ref.LastMergeAt != nil {
-		fmt.Printf("  Last merge: %s\n", ref.LastMergeAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("  Last merge: %s\n", format.DateTimeSeconds(*ref.LastMergeAt))
 	}
 
 	return nil
@@ -707,3 +816,178 @@ func runRefineryBlocked(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runRefineryConflict(cmd *cobra.Command, args []string) error {
+	mrID := args[0]
+	rigName := ""
+	if len(args) > 1 {
+		rigName = args[1]
+	}
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	mr, err := mrqueue.New(r.Path).Get(mrID)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("MR %s not found in queue", mrID)
+		}
+		return fmt.Errorf("loading MR %s: %w", mrID, err)
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+
+	taskID, err := eng.HandleConflict(mr)
+	if err != nil {
+		return err
+	}
+	if taskID == "" {
+		fmt.Printf("%s No conflict task created for %s (it merges cleanly, or the merge slot is busy)\n", style.Dim.Render("○"), mrID)
+		return nil
+	}
+	fmt.Printf("%s %s blocked on conflict task %s\n", style.Bold.Render("✓"), mrID, taskID)
+	return nil
+}
+
+func runRefineryTrain(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	if refineryTrainStatus {
+		state, err := refinery.LoadTrainState(r.Path)
+		if err != nil {
+			return err
+		}
+		if refineryTrainJSON {
+			return outputJSON(state)
+		}
+		if state == nil {
+			fmt.Printf("%s No merge train has run in %s\n", style.Dim.Render("○"), rigName)
+			return nil
+		}
+		printTrainState(state)
+		return nil
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	mrs, err := eng.NextTrain()
+	if err != nil {
+		return fmt.Errorf("listing ready MRs: %w", err)
+	}
+	if len(mrs) == 0 {
+		if refineryTrainJSON {
+			return outputJSON(nil)
+		}
+		reason := "queue isn't deep enough"
+		if !eng.Config().TrainsEnabled() {
+			reason = "trains are off (merge_queue.max_train_length < 2)"
+		}
+		fmt.Printf("%s No merge train for %s: %s\n", style.Dim.Render("○"), rigName, reason)
+		return nil
+	}
+
+	if refineryTrainJSON {
+		eng.SetOutput(os.Stderr)
+	}
+	// Interrupting resets the target to where the train started
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	state, trainErr := eng.ProcessTrain(ctx, getWorkerID(), mrs)
+	if refineryTrainJSON && state != nil {
+		if err := outputJSON(state); err != nil {
+			return err
+		}
+	} else if state != nil {
+		fmt.Println()
+		printTrainState(state)
+	}
+	return trainErr
+}
+
+// printTrainState prints a merge train and what became of each MR.
+func printTrainState(state *refinery.TrainState) {
+	mark := style.Success.Render("✓")
+	if state.Status != refinery.TrainMerged {
+		mark = style.Error.Render("✗")
+		if state.FinishedAt == nil {
+			mark = style.Warning.Render("●")
+		}
+	}
+	fmt.Printf("%s Merge train %s → %s: %s %s\n", mark, state.ID, state.Target, state.Status,
+		style.Dim.Render(fmt.Sprintf("(%d test run(s))", state.TestRuns)))
+	for _, c := range state.Cars {
+		line := fmt.Sprintf("  %-10s %-14s %s", c.Status, c.MR, c.Branch)
+		if c.Error != "" {
+			line += "  " + style.Dim.Render(c.Error)
+		}
+		fmt.Println(line)
+	}
+	if state.Error != "" {
+		fmt.Printf("  %s\n", style.Error.Render(state.Error))
+	}
+}
+
+func runRefineryPostMerge(cmd *cobra.Command, args []string) error {
+	beadID := args[0]
+	rigName := ""
+	if len(args) > 1 {
+		rigName = args[1]
+	}
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if refineryPostMergeJSON {
+		eng.SetOutput(os.Stderr)
+	}
+
+	result := eng.RunPostMerge(context.Background(), refineryPostMergeCommit, eng.Config().TargetBranch, []string{beadID})
+	if result == nil {
+		if refineryPostMergeJSON {
+			return outputJSON(nil)
+		}
+		fmt.Printf("%s No post_merge_command set for %s\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+	if refineryPostMergeJSON {
+		if err := outputJSON(result); err != nil {
+			return err
+		}
+	} else if result.Success {
+		fmt.Printf("%s Post-merge command passed for %s (%s)\n", style.Bold.Render("✓"), beadID, result.Duration)
+	} else {
+		fmt.Printf("%s Post-merge command failed for %s: %s\n", style.Error.Render("✗"), beadID, result.Error)
+		if result.Escalation != "" {
+			fmt.Printf("  Escalated: %s\n", result.Escalation)
+		}
+		if result.Log != "" {
+			fmt.Printf("  Log: %s\n", result.Log)
+		}
+	}
+	if !result.Success {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return NewSilentExit(1)
+	}
+	return nil
+}
@@ -2,13 +2,20 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/format"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // Resume command checks for cleared gates and resumes parked work.
@@ -25,6 +32,13 @@ its gate has cleared. If the gate is closed, it restores your work context.
 With --handoff, it checks the inbox for handoff messages (messages with
 "HANDOFF" in the subject) and displays them formatted for easy continuation.
 
+With --all, it brings the town back after 'gt estop': lifts the Deacon and
+Refinery pauses and restarts the daemon and every agent that was running.
+Without an emergency stop (e.g. after a reboot), --all restarts each polecat
+that has a checkpoint or hooked work but no session; the new session primes
+with the molecule step, branch and last prompt from its checkpoint, so it
+continues at that step instead of starting over.
+
 The resume command:
   1. Checks for parked work state (default) or handoff messages (--handoff)
   2. For parked work: verifies gate has closed
@@ -34,7 +48,9 @@ The resume command:
 Examples:
   gt resume              # Check for and resume parked work
   gt resume --status     # Just show parked work status without resuming
-  gt resume --handoff    # Check inbox for handoff messages`,
+  gt resume --handoff    # Check inbox for handoff messages
+  gt resume --all        # Restart the town after 'gt estop' or a reboot
+  gt resume --all --status  # Show what would be restarted`,
 	RunE: runResume,
 }
 
@@ -42,12 +58,14 @@ var (
 	resumeStatusOnly bool
 	resumeJSON       bool
 	resumeHandoff    bool
+	resumeAll        bool
 )
 
 func init() {
 	resumeCmd.Flags().BoolVar(&resumeStatusOnly, "status", false, "Just show parked work status")
 	resumeCmd.Flags().BoolVar(&resumeJSON, "json", false, "Output as JSON")
 	resumeCmd.Flags().BoolVar(&resumeHandoff, "handoff", false, "Check for handoff messages instead of parked work")
+	resumeCmd.Flags().BoolVar(&resumeAll, "all", false, "Bring the whole town back after 'gt estop'")
 	rootCmd.AddCommand(resumeCmd)
 }
 
@@ -61,6 +79,11 @@ type ResumeStatus struct {
 }
 
 func runResume(cmd *cobra.Command, args []string) error {
+	// If --all, restart the town from its emergency-stop snapshot
+	if resumeAll {
+		return runResumeAll()
+	}
+
 	// If --handoff flag, check for handoff messages instead
 	if resumeHandoff {
 		return checkHandoffMessages()
@@ -136,7 +159,7 @@ func runResume(cmd *cobra.Command, args []string) error {
 		if parked.BeadID != "" {
 			fmt.Printf("  Working on: %s\n", parked.BeadID)
 		}
-		fmt.Printf("  Parked at: %s\n", parked.ParkedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("  Parked at: %s\n", format.DateTimeSeconds(parked.ParkedAt))
 		fmt.Printf("\n%s Gate still open. Check back later or run 'bd gate show %s'\n",
 			style.Dim.Render("⏳"), parked.GateID)
 		return nil
@@ -211,7 +234,7 @@ func displayResumeStatus(status ResumeStatus, parked *ParkedWork) error {
 	if parked.Formula != "" {
 		fmt.Printf("  Formula: %s\n", parked.Formula)
 	}
-	fmt.Printf("  Parked: %s\n", parked.ParkedAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("  Parked: %s\n", format.DateTimeSeconds(parked.ParkedAt))
 
 	if status.GateClosed {
 		fmt.Printf("\n%s Gate cleared! Run 'gt resume' (without --status) to restore work.\n",
@@ -326,3 +349,129 @@ func containsHandoff(s string) bool {
 	upper := strings.ToUpper(s)
 	return strings.Contains(upper, "HANDOFF")
 }
+
+// ResumablePolecat is a polecat with work in flight and no running session.
+type ResumablePolecat struct {
+	Rig        string                 `json:"rig"`
+	Polecat    string                 `json:"polecat"`
+	Hook       string                 `json:"hook,omitempty"`
+	Checkpoint *checkpoint.Checkpoint `json:"checkpoint,omitempty"`
+}
+
+// findResumablePolecats returns the town's polecats that have a checkpoint
+// or hooked work but whose sessions aren't running, as after a reboot.
+func findResumablePolecats(townRoot string) ([]ResumablePolecat, error) {
+	rigs, err := discoverAllRigs(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	t := tmux.NewTmux()
+	var out []ResumablePolecat
+	for _, r := range rigs {
+		mgr := polecat.NewManager(r, git.NewGit(r.Path))
+		polecats, err := mgr.List()
+		if err != nil {
+			continue
+		}
+		sessions := polecat.NewSessionManager(t, r)
+		for _, p := range polecats {
+			if running, _ := sessions.IsRunning(p.Name); running {
+				continue
+			}
+			cp, _ := checkpoint.Read(p.ClonePath)
+			hook := p.Issue
+			if hook == "" && cp != nil {
+				hook = cp.HookedBead
+			}
+			if cp == nil && hook == "" {
+				continue // Idle: nothing to resume
+			}
+			out = append(out, ResumablePolecat{Rig: r.Name, Polecat: p.Name, Hook: hook, Checkpoint: cp})
+		}
+	}
+	return out, nil
+}
+
+// runResumeCheckpoints restarts every polecat that was at work when its
+// session died. Each carries its checkpoint into the new session, which
+// primes with the molecule step, branch and last prompt it left off at.
+func runResumeCheckpoints(townRoot string) error {
+	polecats, err := findResumablePolecats(townRoot)
+	if err != nil {
+		return fmt.Errorf("finding polecats: %w", err)
+	}
+	if resumeJSON {
+		if polecats == nil {
+			polecats = []ResumablePolecat{}
+		}
+		if resumeStatusOnly {
+			return outputJSON(polecats)
+		}
+	}
+	if len(polecats) == 0 {
+		if !resumeJSON {
+			fmt.Printf("%s No polecats to resume\n", style.Dim.Render("○"))
+		}
+		return nil
+	}
+
+	if resumeStatusOnly && !resumeJSON {
+		fmt.Printf("%s Polecats to resume (%d):\n", style.Bold.Render("▶"), len(polecats))
+	}
+	t := tmux.NewTmux()
+	failed := 0
+	for _, rp := range polecats {
+		name := fmt.Sprintf("%s/polecats/%s", rp.Rig, rp.Polecat)
+		where := "hooked: " + rp.Hook
+		if cp := rp.Checkpoint; cp != nil && cp.CurrentStep != "" {
+			where = fmt.Sprintf("step %s", cp.CurrentStep)
+			if cp.StepTitle != "" {
+				where += ": " + cp.StepTitle
+			}
+		}
+		if resumeStatusOnly {
+			age := ""
+			if rp.Checkpoint != nil {
+				age = fmt.Sprintf(" (checkpoint %s old)", formatDuration(rp.Checkpoint.Age()))
+			}
+			fmt.Printf("  %s %s%s\n", name, style.Dim.Render(where), style.Dim.Render(age))
+			continue
+		}
+
+		_, r, err := getRig(rp.Rig)
+		if err == nil {
+			if rp.Checkpoint != nil {
+				// Refresh so the new session doesn't discard it as stale
+				mgr := polecat.NewManager(r, git.NewGit(r.Path))
+				if p, err := mgr.Get(rp.Polecat); err == nil {
+					_ = checkpoint.Update(p.ClonePath, nil)
+				}
+			}
+			err = polecat.NewSessionManager(t, r).Start(rp.Polecat, polecat.SessionStartOptions{Bead: rp.Hook})
+		}
+		if err != nil && !errors.Is(err, polecat.ErrSessionRunning) {
+			failed++
+			if !resumeJSON {
+				printStatus(name, false, err.Error())
+			}
+			continue
+		}
+		if !resumeJSON {
+			printStatus(name, true, "resumed at "+where)
+		}
+	}
+	if resumeJSON {
+		return outputJSON(polecats)
+	}
+	if resumeStatusOnly {
+		return nil
+	}
+
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		fmt.Printf("\n%s The daemon isn't running; 'gt up' brings back the witnesses and refineries\n", style.Dim.Render("ℹ"))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d polecat(s) failed to resume", failed)
+	}
+	return nil
+}
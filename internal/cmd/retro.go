@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/stats"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// retroSessionGrace is how long after a run ends a session of its agent
+// still counts toward the run's cost: the session that ran gt done ends
+// after the run does.
+const retroSessionGrace = 15 * time.Minute
+
+var retroJSON bool
+
+var retroCmd = &cobra.Command{
+	Use:     "retro <run-id>",
+	GroupID: GroupWork,
+	Short:   "Show a molecule run's retrospective",
+	Long: `Show the retrospective of a molecule run: how long each step took, which
+steps were retried, what the run's agent sessions cost, what became of its
+merge request, and the agent's own summary of the work.
+
+A retro is recorded when a run ends (gt done, or gt mol step done on the
+last step), stored in the run history and added as a comment on the
+run's bead. The agent writes its summary with --summary:
+
+  gt done --summary "Added token refresh; the retry path needed a mutex"
+
+Merge outcome and cost are brought up to date each time the retro is
+shown, since the refinery merges after the run ends.
+
+Examples:
+  gt retro gt-wisp-abc
+  gt retro gt-wisp-abc --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRetro,
+}
+
+func init() {
+	retroCmd.Flags().BoolVar(&retroJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(retroCmd)
+}
+
+func runRetro(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	r, err := molruns.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+
+	// A running run gets a provisional retro that isn't stored
+	r.Retro = gatherRetro(townRoot, r, r.Retro)
+	if r.Status != molruns.StatusRunning {
+		retro := r.Retro
+		updateMoleculeRun(townRoot, r.ID, func(stored *molruns.Run) { stored.Retro = retro })
+	}
+
+	if retroJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	fmt.Print(retroText(r))
+	return nil
+}
+
+// recordRunRetro records the retrospective of a run that just ended, in
+// its history and as a comment on its bead. Failing to comment only warns.
+func recordRunRetro(townRoot, runID, summary, mrID string) {
+	r, err := molruns.Load(townRoot, runID)
+	if err != nil {
+		return
+	}
+	retro := gatherRetro(townRoot, r, &molruns.Retro{Summary: strings.TrimSpace(summary), MR: mrID})
+	updateMoleculeRun(townRoot, runID, func(stored *molruns.Run) { stored.Retro = retro })
+	r.Retro = retro
+
+	bead := retroBead(r)
+	if err := beads.New(beadLocation(townRoot, bead)).Comment(bead, retroText(r)); err != nil {
+		style.PrintWarning("could not add retro to %s: %v", bead, err)
+		return
+	}
+	fmt.Printf("%s Retro recorded on %s (gt retro %s)\n", style.Bold.Render("✓"), bead, r.ID)
+}
+
+// gatherRetro builds a run's retro from its cost and merge records,
+// keeping the summary and merge request of prev.
+func gatherRetro(townRoot string, r *molruns.Run, prev *molruns.Retro) *molruns.Retro {
+	retro := &molruns.Retro{Generated: time.Now()}
+	if prev != nil {
+		retro.Summary, retro.MR = prev.Summary, prev.MR
+	}
+	retro.CostUSD, retro.Sessions = runCost(r)
+
+	if retro.MR != "" {
+		retro.Merge = molruns.MergePending
+		merges, _ := stats.LoadMerges(townRoot)
+		for _, m := range merges {
+			if m.MR != retro.MR {
+				continue
+			}
+			retro.Merge, retro.MergeReason = molruns.MergeFailed, m.Reason
+			if m.OK {
+				retro.Merge, retro.MergeReason = molruns.MergeMerged, ""
+			}
+		}
+	}
+	return retro
+}
+
+// runCost totals the cost of the run's agent's sessions that ended while
+// the run was going (or shortly after it ended).
+func runCost(r *molruns.Run) (float64, int) {
+	agent := r.Target[strings.LastIndex(r.Target, "/")+1:]
+	if agent == "" {
+		return 0, 0
+	}
+	entries := querySessionEvents()
+	if today, err := querySessionCostWisps(time.Now()); err == nil {
+		entries = append(entries, today...)
+	}
+
+	var total float64
+	sessions := 0
+	for _, e := range entries {
+		if e.Worker != agent || (r.Rig != "" && e.Rig != r.Rig) || e.EndedAt.Before(r.Started) {
+			continue
+		}
+		if !r.Finished.IsZero() && e.EndedAt.After(r.Finished.Add(retroSessionGrace)) {
+			continue
+		}
+		total += e.CostUSD
+		sessions++
+	}
+	return total, sessions
+}
+
+// retroBead is the bead a run's retro is attached to: the bead the formula
+// was applied to, the bead on the hook, or the molecule itself.
+func retroBead(r *molruns.Run) string {
+	switch {
+	case r.Bead != "":
+		return r.Bead
+	case r.Hook != "":
+		return r.Hook
+	}
+	return r.ID
+}
+
+// retroText renders a run's retro as plain text, for the terminal and for
+// the bead comment.
+func retroText(r *molruns.Run) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Retro: %s (%s, %s)\n\n", r.ID, r.Formula, r.Status)
+
+	retries := r.Retries()
+	fmt.Fprintf(&b, "Duration: %s over %d step(s)", r.Duration().Round(time.Second), len(r.Steps))
+	if retries == 1 {
+		b.WriteString(", 1 retry")
+	} else if retries > 1 {
+		fmt.Fprintf(&b, ", %d retries", retries)
+	}
+	b.WriteString("\n")
+	if r.ReplayOf != "" {
+		fmt.Fprintf(&b, "Replay:   of %s\n", r.ReplayOf)
+	}
+
+	retro := r.Retro
+	if retro == nil {
+		retro = &molruns.Retro{}
+	}
+	if retro.Sessions > 0 {
+		fmt.Fprintf(&b, "Cost:     $%.2f over %d session(s)\n", retro.CostUSD, retro.Sessions)
+	} else {
+		b.WriteString("Cost:     no session costs recorded\n")
+	}
+	switch {
+	case retro.MR == "":
+		b.WriteString("Merge:    no merge request\n")
+	case retro.MergeReason != "":
+		fmt.Fprintf(&b, "Merge:    %s %s (%s)\n", retro.MR, retro.Merge, retro.MergeReason)
+	default:
+		fmt.Fprintf(&b, "Merge:    %s %s\n", retro.MR, retro.Merge)
+	}
+
+	if len(r.Steps) > 0 {
+		b.WriteString("\nSteps:\n")
+		for _, s := range r.Steps {
+			detail := s.Outcome
+			if detail == "" {
+				detail = "in progress"
+			}
+			if s.Outcome != molruns.OutcomeSkipped && !s.Started.IsZero() {
+				detail = s.Duration().Round(time.Second).String() + ", " + detail
+			}
+			if n := s.Retries(); n > 0 {
+				detail += fmt.Sprintf(", retried %d×", n)
+			}
+			fmt.Fprintf(&b, "  %-40s %s\n", runStepName(s), detail)
+		}
+	}
+
+	b.WriteString("\nSummary:\n")
+	if retro.Summary == "" {
+		b.WriteString("  (none written; agents add one with gt done --summary)\n")
+	} else {
+		for _, line := range strings.Split(retro.Summary, "\n") {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	return b.String()
+}
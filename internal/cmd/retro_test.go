@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/molruns"
+)
+
+func TestRetroText(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	r := &molruns.Run{ID: "gt-wisp-a", Formula: "shiny", Status: molruns.StatusComplete, Started: t0, Finished: t0.Add(time.Hour)}
+	r.FinishStep("gt-wisp-a.1", "design", "Design auth", "", molruns.OutcomeDone, t0.Add(10*time.Minute))
+	r.BeginStep("gt-wisp-a.2", "implement", "Implement auth", "", t0.Add(10*time.Minute))
+	r.BeginStep("gt-wisp-a.2", "implement", "Implement auth", "", t0.Add(20*time.Minute))
+	r.FinishStep("gt-wisp-a.2", "", "", "", molruns.OutcomeDone, t0.Add(50*time.Minute))
+	r.Retro = &molruns.Retro{Summary: "Added token refresh.\nThe retry path needed a mutex.", CostUSD: 3.5, Sessions: 2,
+		MR: "gt-mr-1", Merge: molruns.MergeFailed, MergeReason: "tests failed"}
+
+	text := retroText(r)
+	for _, want := range []string{
+		"Retro: gt-wisp-a (shiny, complete)",
+		"Duration: 1h0m0s over 2 step(s), 1 retry",
+		"Cost:     $3.50 over 2 session(s)",
+		"Merge:    gt-mr-1 failed (tests failed)",
+		"design: Design auth",
+		"30m0s, done, retried 1×",
+		"  Added token refresh.\n  The retry path needed a mutex.\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("retro missing %q:\n%s", want, text)
+		}
+	}
+
+	r.Retro = nil
+	text = retroText(r)
+	if !strings.Contains(text, "no merge request") || !strings.Contains(text, "none written") {
+		t.Errorf("retro without a record:\n%s", text)
+	}
+}
+
+func TestRetroBead(t *testing.T) {
+	for _, tc := range []struct {
+		run  molruns.Run
+		want string
+	}{
+		{molruns.Run{ID: "gt-wisp-a", Bead: "gt-abc", Hook: "gt-def"}, "gt-abc"},
+		{molruns.Run{ID: "gt-wisp-a", Hook: "gt-def"}, "gt-def"},
+		{molruns.Run{ID: "gt-wisp-a"}, "gt-wisp-a"},
+	} {
+		if got := retroBead(&tc.run); got != tc.want {
+			t.Errorf("retroBead(%+v) = %s, want %s", tc.run, got, tc.want)
+		}
+	}
+}
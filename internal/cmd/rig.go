@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/vcs"
 	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -41,7 +42,7 @@ A rig is a container for managing a project and its agents:
 }
 
 var rigAddCmd = &cobra.Command{
-	Use:   "add <name> <git-url>",
+	Use:   "add <name> [git-url]",
 	Short: "Add a new rig to the workspace",
 	Long: `Add a new rig by cloning a repository.
 
@@ -60,10 +61,35 @@ The command also:
   - Creates ~/gt/plugins/ (town-level) if it doesn't exist
   - Creates <rig>/plugins/ (rig-level)
 
+With --remote, the clones, beads database and agent directories are created
+on another machine over SSH, and the town keeps only the rig's config.json.
+git, bd and agent sessions for the rig run on that machine (which needs git,
+bd, gt and the agent CLI installed, and key-based SSH access). Sessions stay
+in the local tmux server, so the mayor, status, nudges and peeks work as for
+local rigs.
+
+With --path, the rig is one directory of a monorepo: its clones check out
+only that directory and the repo's top-level files (sparse checkout), the
+refinery runs the rig's test command there, and the rig keeps beads of its
+own prefix rather than any the monorepo tracks. Add a rig per service.
+
+With --vcs jj, the rig runs on Jujutsu, colocated with git so it still
+fetches from and pushes to the git remote: polecats and the refinery are
+jj workspaces of mayor/rig, and polecat branches are bookmarks. Premerge
+checks, diff stats and GitHub pull requests need a git rig.
+
+If a step fails, everything the add created is removed. With --no-rollback
+the partial rig is kept instead, and --resume continues it from the failed
+step (say after fixing a bd install), with the options it was started with.
+
 Example:
   gt rig add gastown https://github.com/steveyegge/gastown
-  gt rig add my-project git@github.com:user/repo.git --prefix mp`,
-	Args: cobra.ExactArgs(2),
+  gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig add svc_auth git@github.com:acme/mono.git --path services/auth
+  gt rig add docs_site git@github.com:acme/docs.git --vcs jj
+  gt rig add big_model git@github.com:user/model.git --remote me@gpubox:/srv/gt/big_model
+  gt rig add gastown --resume`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: runRigAdd,
 }
 
@@ -256,6 +282,11 @@ var (
 	rigAddPrefix       string
 	rigAddLocalRepo    string
 	rigAddBranch       string
+	rigAddRemote       string
+	rigAddPath         string
+	rigAddVCS          string
+	rigAddResume       bool
+	rigAddNoRollback   bool
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -286,6 +317,11 @@ func init() {
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
 	rigAddCmd.Flags().StringVar(&rigAddLocalRepo, "local-repo", "", "Local repo path to share git objects (optional)")
 	rigAddCmd.Flags().StringVar(&rigAddBranch, "branch", "", "Default branch name (default: auto-detected from remote)")
+	rigAddCmd.Flags().StringVar(&rigAddRemote, "remote", "", "Keep the rig's clones on another machine: [user@]host:/path (run over SSH)")
+	rigAddCmd.Flags().StringVar(&rigAddPath, "path", "", "Scope the rig to this directory of a monorepo")
+	rigAddCmd.Flags().StringVar(&rigAddVCS, "vcs", "", "Version control backend: git (default) or jj")
+	rigAddCmd.Flags().BoolVar(&rigAddResume, "resume", false, "Continue an add that failed part way")
+	rigAddCmd.Flags().BoolVar(&rigAddNoRollback, "no-rollback", false, "Keep a partial rig on failure, so it can be resumed")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -307,7 +343,12 @@ func init() {
 
 func runRigAdd(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	gitURL := args[1]
+	var gitURL string
+	if len(args) > 1 {
+		gitURL = args[1]
+	} else if !rigAddResume {
+		return fmt.Errorf("a git URL is required (only --resume can do without)")
+	}
 
 	// Ensure beads (bd) is available before proceeding
 	if err := deps.EnsureBeads(true); err != nil {
@@ -319,6 +360,11 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	release, err := acquireRigStateLocks(townRoot, name)
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	// Load rigs config
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
@@ -336,10 +382,21 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
 
 	fmt.Printf("Creating rig %s...\n", style.Bold.Render(name))
-	fmt.Printf("  Repository: %s\n", gitURL)
+	if gitURL != "" {
+		fmt.Printf("  Repository: %s\n", gitURL)
+	}
 	if rigAddLocalRepo != "" {
 		fmt.Printf("  Local repo: %s\n", rigAddLocalRepo)
 	}
+	if rigAddRemote != "" {
+		fmt.Printf("  Remote: %s\n", rigAddRemote)
+	}
+	if rigAddPath != "" {
+		fmt.Printf("  Path: %s\n", rigAddPath)
+	}
+	if rigAddVCS != "" {
+		fmt.Printf("  VCS: %s\n", rigAddVCS)
+	}
 
 	startTime := time.Now()
 
@@ -350,15 +407,28 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		BeadsPrefix:   rigAddPrefix,
 		LocalRepo:     rigAddLocalRepo,
 		DefaultBranch: rigAddBranch,
+		Remote:        rigAddRemote,
+		Subdir:        rigAddPath,
+		VCS:           rigAddVCS,
+		Resume:        rigAddResume,
+		NoRollback:    rigAddNoRollback,
 	})
 	if err != nil {
 		return fmt.Errorf("adding rig: %w", err)
 	}
+	gitURL = newRig.GitURL
 
 	// Save updated rigs config
 	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		if !rigAddResume && !rigAddNoRollback && rigAddRemote == "" {
+			fmt.Printf("  Rolling back: removing %s\n", newRig.Path)
+			_ = os.RemoveAll(newRig.Path)
+		}
 		return fmt.Errorf("saving rigs config: %w", err)
 	}
+	if err := rig.FinishAdd(newRig.Path); err != nil {
+		fmt.Printf("  %s Could not remove the add journal: %v\n", style.Warning.Render("!"), err)
+	}
 
 	// Add route to town-level routes.jsonl for prefix-based routing.
 	// Route points to the canonical beads location:
@@ -415,12 +485,22 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	fmt.Printf("\n%s Rig created in %.1fs\n", style.Success.Render("✓"), elapsed.Seconds())
 	fmt.Printf("\nStructure:\n")
 	fmt.Printf("  %s/\n", name)
+	if rigAddRemote != "" {
+		fmt.Printf("  (on %s; only config.json is kept here)\n", rigAddRemote)
+	}
 	fmt.Printf("  ├── config.json\n")
-	fmt.Printf("  ├── .repo.git/        (shared bare repo for refinery+polecats)\n")
-	fmt.Printf("  ├── .beads/           (prefix: %s)\n", newRig.Config.Prefix)
-	fmt.Printf("  ├── plugins/          (rig-level plugins)\n")
-	fmt.Printf("  ├── mayor/rig/        (clone: %s)\n", defaultBranch)
-	fmt.Printf("  ├── refinery/rig/     (worktree: %s, sees polecat branches)\n", defaultBranch)
+	if newRig.VCS == vcs.KindJJ {
+		fmt.Printf("  ├── .beads/           (prefix: %s)\n", newRig.Config.Prefix)
+		fmt.Printf("  ├── plugins/          (rig-level plugins)\n")
+		fmt.Printf("  ├── mayor/rig/        (jj repo colocated with git: %s)\n", defaultBranch)
+		fmt.Printf("  ├── refinery/rig/     (jj workspace: %s, sees polecat bookmarks)\n", defaultBranch)
+	} else {
+		fmt.Printf("  ├── .repo.git/        (shared bare repo for refinery+polecats)\n")
+		fmt.Printf("  ├── .beads/           (prefix: %s)\n", newRig.Config.Prefix)
+		fmt.Printf("  ├── plugins/          (rig-level plugins)\n")
+		fmt.Printf("  ├── mayor/rig/        (clone: %s)\n", defaultBranch)
+		fmt.Printf("  ├── refinery/rig/     (worktree: %s, sees polecat branches)\n", defaultBranch)
+	}
 	fmt.Printf("  ├── crew/             (empty - add crew with 'gt crew add')\n")
 	fmt.Printf("  ├── witness/\n")
 	fmt.Printf("  └── polecats/\n")
@@ -468,6 +548,15 @@ func runRigList(cmd *cobra.Command, args []string) error {
 
 		summary := r.Summary()
 		fmt.Printf("  %s\n", style.Bold.Render(name))
+		if r.Remote != "" {
+			fmt.Printf("    Remote: %s\n", r.Remote)
+		}
+		if r.Subdir != "" {
+			fmt.Printf("    Path: %s\n", r.Subdir)
+		}
+		if r.VCS != "" {
+			fmt.Printf("    VCS: %s\n", r.VCS)
+		}
 		fmt.Printf("    Polecats: %d  Crew: %d\n", summary.PolecatCount, summary.CrewCount)
 
 		agents := []string{}
@@ -497,6 +586,11 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	release, err := acquireRigStateLocks(townRoot, name)
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	// Load rigs config
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
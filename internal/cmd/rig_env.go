@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var rigEnvJSON bool
+
+var rigEnvCmd = &cobra.Command{
+	Use:   "env <rig>",
+	Short: "Show the environment a rig's agents and tests get",
+	Long: `Show the environment variables set in a rig's agent sessions and the
+refinery's test runs, and check that each secret resolves. Secret values
+are never printed.
+
+Variables live in the rig's settings/config.json. A value is used as is,
+or read at session start from a secret reference:
+
+  "env": {
+    "DATABASE_URL": "postgres://localhost/app_test",
+    "STRIPE_KEY":   "keychain:stripe-test",
+    "API_TOKEN":    "sops:secrets.enc.yaml#api_token",
+    "GH_TOKEN":     "env:GH_TOKEN",
+    "LICENSE":      "file:/etc/app/license"
+  }
+
+  env:VAR           gt's own environment (the daemon's, for restarts)
+  keychain:NAME     macOS keychain or Secret Service, service "gastown"
+  sops:FILE#KEY     sops --decrypt, one key or the whole file
+  file:PATH         a file's contents
+
+Relative paths are relative to the rig. Variables gt sets itself
+(GT_ROLE, BD_ACTOR and the like) can't be overridden. Sessions pick up
+changes when they next start.
+
+Examples:
+  gt rig env gastown
+  gt rig env set gastown DATABASE_URL postgres://localhost/app_test
+  gt rig env set gastown STRIPE_KEY keychain:stripe-test
+  gt rig env unset gastown STRIPE_KEY`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigEnv,
+}
+
+var rigEnvSetCmd = &cobra.Command{
+	Use:   "set <rig> <name> <value>",
+	Short: "Set a variable or secret reference",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runRigEnvSet,
+}
+
+var rigEnvUnsetCmd = &cobra.Command{
+	Use:   "unset <rig> <name>",
+	Short: "Remove a variable",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runRigEnvUnset,
+}
+
+func init() {
+	rigEnvCmd.Flags().BoolVar(&rigEnvJSON, "json", false, "Output as JSON")
+	rigEnvCmd.AddCommand(rigEnvSetCmd, rigEnvUnsetCmd)
+	rigCmd.AddCommand(rigEnvCmd)
+}
+
+func runRigEnv(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	vars, err := config.RigEnvVars(r.Path)
+	if err != nil {
+		return err
+	}
+	if rigEnvJSON {
+		if vars == nil {
+			vars = []config.RigEnvVar{}
+		}
+		return outputJSON(vars)
+	}
+	if len(vars) == 0 {
+		fmt.Printf("%s No env set for %s\n", style.Dim.Render("○"), r.Name)
+		return nil
+	}
+
+	failed := 0
+	for _, v := range vars {
+		switch {
+		case v.Error != "":
+			failed++
+			fmt.Printf("%s %-24s %s\n", style.Error.Render("✗"), v.Name, style.Error.Render(v.Source+": "+v.Error))
+		case v.Secret():
+			fmt.Printf("%s %-24s %s\n", style.Success.Render("✓"), v.Name, style.Dim.Render(v.Source))
+		default:
+			fmt.Printf("%s %-24s %s\n", style.Success.Render("✓"), v.Name, v.Value)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d variable(s) don't resolve; sessions start without them", failed)
+	}
+	return nil
+}
+
+// updateRigEnv applies fn to a rig's env settings and saves them.
+func updateRigEnv(rigName string, fn func(env map[string]string)) error {
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	path := config.RigSettingsPath(r.Path)
+	settings, err := config.LoadRigSettings(path)
+	if errors.Is(err, config.ErrNotFound) {
+		settings, err = config.NewRigSettings(), nil
+	}
+	if err != nil {
+		return err
+	}
+	if settings.Env == nil {
+		settings.Env = make(map[string]string)
+	}
+	fn(settings.Env)
+	return config.SaveRigSettings(path, settings)
+}
+
+func runRigEnvSet(cmd *cobra.Command, args []string) error {
+	rigName, name, value := args[0], args[1], args[2]
+	if !config.ValidEnvName(name) {
+		return fmt.Errorf("%q is not a valid variable name", name)
+	}
+	if err := updateRigEnv(rigName, func(env map[string]string) { env[name] = value }); err != nil {
+		return err
+	}
+	fmt.Printf("%s Set %s for %s\n", style.Bold.Render("✓"), name, rigName)
+	return nil
+}
+
+func runRigEnvUnset(cmd *cobra.Command, args []string) error {
+	rigName, name := args[0], args[1]
+	found := false
+	if err := updateRigEnv(rigName, func(env map[string]string) {
+		_, found = env[name]
+		delete(env, name)
+	}); err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s sets no %s", rigName, name)
+	}
+	fmt.Printf("%s Removed %s from %s\n", style.Bold.Render("✓"), name, rigName)
+	return nil
+}
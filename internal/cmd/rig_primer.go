@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/primer"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	rigPrimerRefresh bool
+	rigPrimerJSON    bool
+)
+
+var rigPrimerCmd = &cobra.Command{
+	Use:   "primer <rig>",
+	Short: "Show the repo primer polecats are spawned with",
+	Long: `Show a rig's repo primer: an overview of its repository's layout, the
+files worth reading first, the conventions its manifests reveal and how
+to build and test it. Polecats get it when they prime, so they don't
+spend tokens rediscovering the repository.
+
+The primer is built from the rig's default branch and cached in
+.runtime/primers/<rig>.json. It is rebuilt whenever the branch has moved;
+--refresh rebuilds it now.
+
+Examples:
+  gt rig primer gastown
+  gt rig primer gastown --refresh`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigPrimer,
+}
+
+func init() {
+	rigPrimerCmd.Flags().BoolVar(&rigPrimerRefresh, "refresh", false, "Rebuild the primer even if the cached one is current")
+	rigPrimerCmd.Flags().BoolVar(&rigPrimerJSON, "json", false, "Output as JSON")
+	rigCmd.AddCommand(rigPrimerCmd)
+}
+
+func runRigPrimer(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	if rigPrimerRefresh {
+		if err := os.Remove(primer.Path(townRoot, r.Name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	p, err := primer.Ensure(townRoot, r.Name, r.Path, r.DefaultBranch(), getTestCommand(r.Path))
+	if p == nil {
+		return fmt.Errorf("building primer for %s: %w", r.Name, err)
+	}
+	if err != nil {
+		style.PrintWarning("could not cache primer: %v", err)
+	}
+	if rigPrimerJSON {
+		return outputJSON(p)
+	}
+	fmt.Print(p.Text)
+	fmt.Println(style.Dim.Render(fmt.Sprintf("\n(built %s, %d bytes)", formatAge(p.Built), len(p.Text))))
+	return nil
+}
@@ -11,14 +11,14 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
-	quickAddUser  string
-	quickAddYes   bool
-	quickAddQuiet bool
+	quickAddUser string
+	quickAddYes  bool
 )
 
 var rigQuickAddCmd = &cobra.Command{
@@ -42,7 +42,6 @@ func init() {
 	rigCmd.AddCommand(rigQuickAddCmd)
 	rigQuickAddCmd.Flags().StringVar(&quickAddUser, "user", "", "Crew workspace name (default: $USER)")
 	rigQuickAddCmd.Flags().BoolVar(&quickAddYes, "yes", false, "Non-interactive, assume yes")
-	rigQuickAddCmd.Flags().BoolVar(&quickAddQuiet, "quiet", false, "Minimal output")
 }
 
 func runRigQuickAdd(cmd *cobra.Command, args []string) error {
@@ -83,11 +82,11 @@ func runRigQuickAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	originalName := filepath.Base(gitRoot)
-	if rigName != originalName && !quickAddQuiet {
+	if rigName != originalName && !output.IsQuiet() {
 		fmt.Printf("Note: Using %q as rig name (sanitized from %q)\n", rigName, originalName)
 	}
 
-	if !quickAddQuiet {
+	if !output.IsQuiet() {
 		fmt.Printf("Adding %s to Gas Town...\n", style.Bold.Render(rigName))
 		fmt.Printf("  Repository: %s\n", gitURL)
 		fmt.Printf("  Town: %s\n", townRoot)
@@ -112,7 +111,7 @@ func runRigQuickAdd(cmd *cobra.Command, args []string) error {
 		user = "default"
 	}
 
-	if !quickAddQuiet {
+	if !output.IsQuiet() {
 		fmt.Printf("\nCreating crew workspace for %s...\n", user)
 	}
 
@@ -127,7 +126,7 @@ func runRigQuickAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	crewPath := filepath.Join(townRoot, rigName, "crew", user)
-	if !quickAddQuiet {
+	if !output.IsQuiet() {
 		fmt.Printf("\n%s Added to Gas Town!\n", style.Success.Render("✓"))
 		fmt.Printf("\nYour workspace: %s\n", style.Bold.Render(crewPath))
 	}
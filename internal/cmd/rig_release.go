@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/changelog"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/github"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	rigReleaseSince  string
+	rigReleasePush   bool
+	rigReleaseGitHub bool
+	rigReleaseDryRun bool
+	rigReleaseJSON   bool
+)
+
+var rigReleaseCmd = &cobra.Command{
+	Use:   "release <rig> <version>",
+	Short: "Tag a release with a changelog of the beads merged since the last one",
+	Long: `Cut a release of a rig: collect the beads merged since the last tag,
+write a changelog section for them and tag the default branch.
+
+Beads are found in the commit messages on origin/<default branch> since the
+last tag (or --since): the refinery's merge commits name the bead they
+merge. Closed beads are grouped by type (features, bug fixes, other) and by
+label:
+
+  breaking, perf, docs     Breaking Changes, Performance, Documentation
+  changelog:<heading>      a heading of its own
+  changelog:skip           left out
+
+The tag is annotated with the changelog and created in the rig's mayor
+clone, at origin/<default branch>. --push pushes it; --github also drafts
+a GitHub release from it (needs the gh CLI). --dry-run prints the changelog
+without tagging.
+
+Examples:
+  gt rig release gastown v1.4.0 --dry-run
+  gt rig release gastown v1.4.0 --push
+  gt rig release gastown v1.4.0 --github
+  gt rig release gastown v1.4.0 --since v1.2.0 --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRigRelease,
+}
+
+func init() {
+	rigReleaseCmd.Flags().StringVar(&rigReleaseSince, "since", "", "Collect beads merged after this ref (default: the latest tag)")
+	rigReleaseCmd.Flags().BoolVar(&rigReleasePush, "push", false, "Push the tag to origin")
+	rigReleaseCmd.Flags().BoolVar(&rigReleaseGitHub, "github", false, "Push the tag and draft a GitHub release")
+	rigReleaseCmd.Flags().BoolVar(&rigReleaseDryRun, "dry-run", false, "Print the changelog without tagging")
+	rigReleaseCmd.Flags().BoolVar(&rigReleaseJSON, "json", false, "Output as JSON")
+	rigCmd.AddCommand(rigReleaseCmd)
+}
+
+// rigRelease is what gt rig release made.
+type rigRelease struct {
+	Rig       string              `json:"rig"`
+	Version   string              `json:"version"`
+	Since     string              `json:"since,omitempty"`
+	Ref       string              `json:"ref"`
+	Sections  []changelog.Section `json:"sections"`
+	Changelog string              `json:"changelog"`
+	Tagged    bool                `json:"tagged"`
+	Pushed    bool                `json:"pushed"`
+	GitHubURL string              `json:"github_url,omitempty"`
+	Skipped   []string            `json:"skipped,omitempty"` // mentioned beads not closed or not found
+}
+
+func runRigRelease(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	version := args[1]
+	clone := filepath.Join(r.Path, "mayor", "rig")
+	if _, err := os.Stat(clone); err != nil {
+		return fmt.Errorf("rig %s has no mayor clone to tag in: %w", r.Name, err)
+	}
+	g := git.NewGit(clone)
+	if err := g.Fetch("origin"); err != nil {
+		return fmt.Errorf("fetching origin: %w", err)
+	}
+
+	rel := &rigRelease{Rig: r.Name, Version: version, Ref: "origin/" + r.DefaultBranch(), Since: rigReleaseSince}
+	if !rigReleaseDryRun {
+		exists, err := g.TagExists(version)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("tag %s already exists in %s", version, r.Name)
+		}
+	}
+	if rel.Since == "" {
+		if rel.Since, err = g.LatestTag(rel.Ref); err != nil {
+			return fmt.Errorf("finding the latest tag: %w", err)
+		}
+	}
+	messages, err := g.CommitMessages(rel.Since, rel.Ref)
+	if err != nil {
+		return fmt.Errorf("reading commits since %s: %w", rel.Since, err)
+	}
+
+	prefix := "gt"
+	if r.Config != nil && r.Config.Prefix != "" {
+		prefix = r.Config.Prefix
+	}
+	// Commits from before a gt prefix rename name beads by a former prefix
+	ids := changelog.BeadIDs(messages, prefix)
+	if rigCfg, err := rig.LoadRigConfig(r.Path); err == nil && rigCfg.Beads != nil {
+		for _, former := range rigCfg.Beads.FormerPrefixes {
+			for _, id := range changelog.BeadIDs(messages, former) {
+				if id = rigCfg.Beads.CurrentBeadID(id); !slices.Contains(ids, id) {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	bd := beads.New(r.Path)
+	var entries []changelog.Entry
+	for _, id := range ids {
+		issue, err := bd.Show(id)
+		if err != nil || issue.Status != "closed" {
+			rel.Skipped = append(rel.Skipped, id)
+			continue
+		}
+		entries = append(entries, changelog.Entry{ID: issue.ID, Title: issue.Title, Type: issue.Type, Labels: issue.Labels})
+	}
+	rel.Sections = changelog.Group(entries)
+	rel.Changelog = changelog.Render(version, time.Now(), rel.Sections)
+
+	if !rigReleaseDryRun {
+		if err := g.CreateTag(version, rel.Ref, version+"\n\n"+rel.Changelog); err != nil {
+			return fmt.Errorf("tagging %s: %w", version, err)
+		}
+		rel.Tagged = true
+		if rigReleasePush || rigReleaseGitHub {
+			if err := g.PushTag("origin", version); err != nil {
+				return fmt.Errorf("pushing tag %s: %w", version, err)
+			}
+			rel.Pushed = true
+		}
+		if rigReleaseGitHub {
+			url, err := github.DraftRelease(clone, version, rel.Changelog)
+			if err != nil {
+				err = fmt.Errorf("drafting GitHub release: %w", err)
+				if rigReleaseJSON {
+					_ = outputJSON(rel)
+				}
+				return err
+			}
+			rel.GitHubURL = url
+		}
+	}
+
+	if rigReleaseJSON {
+		return outputJSON(rel)
+	}
+	fmt.Print(rel.Changelog)
+	fmt.Println()
+	if len(rel.Skipped) > 0 {
+		fmt.Printf("%s Left out %d bead(s) that aren't closed or weren't found: %v\n",
+			style.Dim.Render("○"), len(rel.Skipped), rel.Skipped)
+	}
+	since := rel.Since
+	if since == "" {
+		since = "the first commit"
+	}
+	switch {
+	case rigReleaseDryRun:
+		fmt.Printf("%s Dry run: %s of %s since %s, not tagged\n", style.Dim.Render("○"), version, rel.Ref, since)
+	case rel.GitHubURL != "":
+		fmt.Printf("%s Tagged and pushed %s; draft release: %s\n", style.Success.Render("✓"), version, rel.GitHubURL)
+	case rel.Pushed:
+		fmt.Printf("%s Tagged and pushed %s at %s\n", style.Success.Render("✓"), version, rel.Ref)
+	default:
+		fmt.Printf("%s Tagged %s at %s in %s (not pushed; use --push)\n", style.Success.Render("✓"), version, rel.Ref, clone)
+	}
+	return nil
+}
@@ -2,15 +2,24 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/format"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/version"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var rootCmd = &cobra.Command{
@@ -27,20 +36,43 @@ across distributed teams of AI agents working on shared codebases.`,
 // Commands that don't require beads to be installed/checked.
 // These are basic utility commands that should work without beads.
 var beadsExemptCommands = map[string]bool{
-	"version":    true,
-	"help":       true,
-	"completion": true,
+	"version":        true,
+	"help":           true,
+	"completion":     true,
+	"explain-config": true, // Config diagnostics must work when bd is broken
+	"tutorial":       true, // Runs in a sandbox without beads
+	"bench":          true, // Runs in a scratch directory, skipping what it lacks
+	"session-host":   true, // Output goes to the session log
+	"heartbeat":      true, // Runs after every agent tool call
 }
 
+// Global output level flags (see internal/output).
+var (
+	quietFlag     bool
+	verbosityFlag int
+)
+
+// logFlag holds per-component log levels (see internal/logging).
+var logFlag string
+
+// dryRunFlag records commands and file changes instead of making them
+// (see internal/dryrun).
+var dryRunFlag bool
+
+// harnessFlag runs the command in a registered harness instead of the town
+// the current directory is in (see 'gt harness').
+var harnessFlag string
+
 // Commands exempt from the town root branch warning.
 // These are commands that help fix the problem or are diagnostic.
 var branchCheckExemptCommands = map[string]bool{
-	"version":    true,
-	"help":       true,
-	"completion": true,
-	"doctor":     true, // Used to fix the problem
-	"install":    true, // Initial setup
-	"git-init":   true, // Git setup
+	"version":      true,
+	"help":         true,
+	"completion":   true,
+	"doctor":       true, // Used to fix the problem
+	"install":      true, // Initial setup
+	"git-init":     true, // Git setup
+	"session-host": true,
 }
 
 // persistentPreRun runs before every command.
@@ -48,18 +80,93 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Get the root command name being run
 	cmdName := cmd.Name()
 
+	output.SetLevel(output.FromFlags(quietFlag, verbosityFlag))
+
+	// Commands with their own --dry-run (sling, hook, ...) shadow the
+	// global flag; either one turns on dry-run mode.
+	if on, err := cmd.Flags().GetBool("dry-run"); err == nil && on {
+		dryrun.Set(true)
+	}
+	output.Tracef("gt %s", strings.Join(os.Args[1:], " "))
+
+	if err := enterHarness(); err != nil {
+		return err
+	}
+
+	if err := authorizeCommand(cmd); err != nil {
+		return err
+	}
+
+	if err := initLogging(); err != nil {
+		return err
+	}
+	startCommandSpan(cmd)
+
 	// Check town root branch (warning only, non-blocking)
 	if !branchCheckExemptCommands[cmdName] {
 		warnIfTownRootOffMain()
 	}
 
+	// Apply the town's report locale (non-blocking)
+	initLocale()
+
 	// Skip beads check for exempt commands
 	if beadsExemptCommands[cmdName] {
 		return nil
 	}
 
-	// Check beads version
-	return CheckBeadsVersion()
+	// Check beads is installed (where needed) and new enough
+	return checkBeadsCapability(cmd)
+}
+
+// initLocale selects the report formatting locale from GT_LOCALE or the
+// town settings. An invalid locale is warned about and the default kept.
+func initLocale() {
+	configured := ""
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+			configured = settings.Locale
+		}
+	}
+	if err := format.Init(configured); err != nil {
+		style.PrintWarning("%v", err)
+	}
+}
+
+// initLogging directs structured logs to the town's .logs/ directory at the
+// levels given by --log (or GT_LOG). The flag is exported to GT_LOG so that
+// processes gt spawns (daemon, agents running gt) log at the same levels.
+func initLogging() error {
+	spec := os.Getenv(logging.EnvVar)
+	if logFlag != "" {
+		spec = logFlag
+		_ = os.Setenv(logging.EnvVar, spec)
+	}
+	if _, err := logging.ParseLevels(spec); err != nil {
+		return fmt.Errorf("--log: %w", err)
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	// Non-blocking: commands still run if the log file can't be opened
+	_ = logging.Setup(townRoot, spec)
+	return nil
+}
+
+// commandSpan is the root span for this invocation, ended by Execute.
+var commandSpan *telemetry.Span
+
+// startCommandSpan opens the root trace span named after the command path
+// ("gt mol step done"), tagged with the calling agent's identity.
+func startCommandSpan(cmd *cobra.Command) {
+	attrs := []attribute.KeyValue{attribute.String("gt.args", strings.Join(os.Args[1:], " "))}
+	for key, env := range map[string]string{"gt.role": "GT_ROLE", "gt.rig": "GT_RIG", "gt.polecat": "GT_POLECAT"} {
+		if v := os.Getenv(env); v != "" {
+			attrs = append(attrs, attribute.String(key, v))
+		}
+	}
+	commandSpan = telemetry.Start(cmd.CommandPath(), attrs...)
 }
 
 // warnIfTownRootOffMain prints a warning if the town root is not on main branch.
@@ -158,15 +265,35 @@ func checkStaleBinaryWarning() {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
-	if err := rootCmd.Execute(); err != nil {
-		// Check for silent exit (scripting commands that signal status via exit code)
-		if code, ok := IsSilentExit(err); ok {
-			return code
+	shutdown, err := telemetry.Init(context.Background(), "gt")
+	if err != nil {
+		style.PrintWarning("tracing disabled: %v", err)
+	} else {
+		defer func() { _ = shutdown(context.Background()) }()
+	}
+
+	registerDynamicCompletions(rootCmd)
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	if commandSpan != nil {
+		commandSpan.End(err)
+	}
+	autoCommitHarness(cmd)
+	if dryrun.Enabled() {
+		dryrun.Record("no changes were made")
+	}
+
+	code := 0
+	if err != nil {
+		// Silent exits (scripting commands) signal status via exit code;
+		// other errors were already printed by cobra
+		code = 1
+		if c, ok := IsSilentExit(err); ok {
+			code = c
 		}
-		// Other errors already printed by cobra
-		return 1
 	}
-	return 0
+	recordAudit(cmd, err, code, start)
+	return code
 }
 
 // Command group IDs - used by subcommands to organize help output
@@ -199,8 +326,12 @@ func init() {
 	rootCmd.SetHelpCommandGroupID(GroupDiag)
 	rootCmd.SetCompletionCommandGroupID(GroupConfig)
 
-	// Global flags can be added here
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
+	// Global output level: -q for scripts, -v for detail, -vv for step traces
+	rootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "Only print results and errors")
+	rootCmd.PersistentFlags().CountVarP(&verbosityFlag, "verbose", "v", "Show more detail (-vv for step traces)")
+	rootCmd.PersistentFlags().BoolVar(&dryRunFlag, "dry-run", false, "Print the commands and file changes that would be made, without making them")
+	rootCmd.PersistentFlags().StringVar(&harnessFlag, "harness", "", "Run in the named harness instead of the current directory's town")
+	rootCmd.PersistentFlags().StringVar(&logFlag, "log", "", "Per-component log levels, e.g. refinery=debug,warn (logs in <town>/.logs/)")
 }
 
 // buildCommandPath walks the command hierarchy to build the full command path.
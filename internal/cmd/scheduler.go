@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	schedulerStatusJSON      bool
+	schedulerDrainOverBudget bool
+)
+
+var schedulerCmd = &cobra.Command{
+	Use:     "scheduler",
+	GroupID: GroupWork,
+	Short:   "Polecat spawn limits and the spawn queue",
+	Long: `Show and manage polecat admission control.
+
+Spawning into a rig (gt sling <bead> <rig>) is refused while the town or
+the rig already runs its maximum number of polecats. The sling is queued
+instead, and the daemon dispatches queued slings, highest bead priority
+first, as polecats finish. While spend is past the budget's warning
+threshold (gt budget status), or a model provider is backing off after a
+429 (gt ratelimit status), new slings are queued and the queue is held.
+
+Limits:
+  Town:  "scheduler": {"max_polecats": 8} in ~/gt/settings/config.json
+  Rig:   gt rig config set <rig> max_polecats 4   (0 = no limit)
+
+Examples:
+  gt scheduler status           # Limits, live polecats and queue
+  gt scheduler drain            # Dispatch what fits now
+  gt scheduler cancel gt-abc    # Drop a queued sling`,
+	RunE: requireSubcommand,
+}
+
+var schedulerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show limits, live polecats and queued spawns",
+	Args:  cobra.NoArgs,
+	RunE:  runSchedulerStatus,
+}
+
+var schedulerDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Dispatch queued spawns that fit under the limits",
+	Long: `Dispatch queued spawns that fit under the limits, most urgent first.
+
+The daemon runs this on every heartbeat; run it by hand after raising a
+limit to dispatch without waiting. Nothing is dispatched while spend is
+past the budget's warning threshold, unless --over-budget is given, or
+while a model provider is backing off after a 429.`,
+	Args: cobra.NoArgs,
+	RunE: runSchedulerDrain,
+}
+
+var schedulerCancelCmd = &cobra.Command{
+	Use:   "cancel <id|bead>",
+	Short: "Remove a queued spawn",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSchedulerCancel,
+}
+
+func init() {
+	schedulerStatusCmd.Flags().BoolVar(&schedulerStatusJSON, "json", false, "Output as JSON")
+	schedulerDrainCmd.Flags().BoolVar(&schedulerDrainOverBudget, "over-budget", false, "Dispatch even when spend caps pause or stop spawning")
+	schedulerCmd.AddCommand(schedulerStatusCmd)
+	schedulerCmd.AddCommand(schedulerDrainCmd)
+	schedulerCmd.AddCommand(schedulerCancelCmd)
+	rootCmd.AddCommand(schedulerCmd)
+}
+
+func runSchedulerStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := scheduler.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	queued, err := scheduler.NewQueue(townRoot).List()
+	if err != nil {
+		return err
+	}
+
+	if schedulerStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{
+			"limits":  state.Limits,
+			"running": state.Running,
+			"queued":  queued,
+		})
+	}
+
+	fmt.Printf("%s Polecats: %d running (town limit: %s)\n",
+		style.Bold.Render("●"), state.Running.Total(), limitString(state.Limits.Town))
+	rigs := make([]string, 0, len(state.Running))
+	for name := range state.Running {
+		rigs = append(rigs, name)
+	}
+	sort.Strings(rigs)
+	for _, name := range rigs {
+		fmt.Printf("  %-20s %d running (limit: %s)\n", name, state.Running[name], limitString(state.Limits.PerRig[name]))
+	}
+
+	fmt.Println()
+	if len(queued) == 0 {
+		fmt.Printf("%s No queued spawns\n", style.Dim.Render("○"))
+		return nil
+	}
+	fmt.Printf("%s Queued spawns: %d\n", style.Bold.Render("⏳"), len(queued))
+	for i, req := range queued {
+		fmt.Printf("  %2d. P%d  %-14s → %-16s %s  %s\n", i+1, req.Priority, req.Bead, req.Rig,
+			style.Dim.Render(req.ID), style.Dim.Render("waiting "+time.Since(req.Enqueued).Round(time.Second).String()))
+	}
+	return nil
+}
+
+func limitString(n int) string {
+	if n <= 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func runSchedulerDrain(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	status, err := checkBudget(townRoot)
+	if err != nil {
+		return err
+	}
+	if status.Level != budget.OK && !schedulerDrainOverBudget {
+		fmt.Printf("%s Spawn queue held: %s\n", style.Bold.Render("⏸"), status.Reason())
+		return nil
+	}
+	if held, reason, err := providerBackoff(townRoot); err != nil {
+		return err
+	} else if held {
+		fmt.Printf("%s Spawn queue held: %s\n", style.Bold.Render("⏸"), reason)
+		return nil
+	}
+	dispatched, errs, err := scheduler.Drain(townRoot, func(req scheduler.Request) error {
+		fmt.Printf("%s Dispatching %s to %s...\n", style.Bold.Render("▶"), req.Bead, req.Rig)
+		return dispatchQueuedSpawn(townRoot, req)
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range errs {
+		style.PrintWarning("%v", e)
+	}
+	if len(dispatched) == 0 {
+		fmt.Printf("%s Nothing to dispatch\n", style.Dim.Render("○"))
+		return nil
+	}
+	fmt.Printf("%s Dispatched %d queued spawn(s)\n", style.Bold.Render("✓"), len(dispatched)-len(errs))
+	return nil
+}
+
+// dispatchQueuedSpawn re-runs a queued sling, marked as already admitted.
+func dispatchQueuedSpawn(townRoot string, req scheduler.Request) error {
+	gt, err := os.Executable()
+	if err != nil {
+		gt = "gt"
+	}
+	c := exec.Command(gt, req.Args...) //nolint:gosec // G204: args were recorded by gt sling
+	c.Dir = townRoot
+	c.Env = append(os.Environ(), scheduler.EnvAdmitted+"=1")
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+func runSchedulerCancel(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	removed, err := scheduler.NewQueue(townRoot).Remove(args[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("no queued spawn matches %s", args[0])
+	}
+	fmt.Printf("%s Removed %s from the spawn queue\n", style.Bold.Render("✓"), args[0])
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -285,5 +286,5 @@ func formatEventTime(ts string) string {
 	if err != nil {
 		return ts
 	}
-	return t.Local().Format("2006-01-02 15:04")
+	return format.DateTime(t)
 }
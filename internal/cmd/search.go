@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/search"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	searchRig       string
+	searchType      string
+	searchSince     string
+	searchUntil     string
+	searchLimit     int
+	searchJSON      bool
+	searchReindex   bool
+	searchNoRefresh bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:     "search <query>",
+	GroupID: GroupWork,
+	Short:   "Search beads, mail and transcripts across the town",
+	Long: `Search every rig's beads (titles, descriptions and comments), mail and
+polecat session transcripts, best matches first.
+
+Results come from a local index in <town>/.runtime/search-index.json,
+brought up to date on each search: only beads that changed and transcripts
+that grew are re-read. Use --reindex to rebuild it from scratch.
+
+--type takes a kind (bead, mail, transcript) or a bead type (bug, task,
+epic, ...). --since and --until take a date (2026-01-07) or an age (24h,
+7d). --rig town limits results to town-level beads and mail.
+
+Examples:
+  gt search "flaky websocket test"
+  gt search websocket --rig greenplace --type bug
+  gt search "merge conflict" --type transcript --since 7d
+  gt search deploy --type mail --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchRig, "rig", "", "Only results from this rig (\"town\" for town-level)")
+	searchCmd.Flags().StringVar(&searchType, "type", "", "Only this kind (bead, mail, transcript) or bead type")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "Only results from this date or age on (e.g. 2026-01-07, 7d)")
+	searchCmd.Flags().StringVar(&searchUntil, "until", "", "Only results before this date or age")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 20, "Maximum number of results")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "Output as JSON")
+	searchCmd.Flags().BoolVar(&searchReindex, "reindex", false, "Rebuild the index before searching")
+	searchCmd.Flags().BoolVar(&searchNoRefresh, "no-refresh", false, "Search the index as it is, without updating it")
+
+	rootCmd.AddCommand(searchCmd)
+}
+
+// searchResult is one result in gt search's JSON output.
+type searchResult struct {
+	Kind    string    `json:"kind"`
+	Rig     string    `json:"rig,omitempty"`
+	Type    string    `json:"type,omitempty"`
+	ID      string    `json:"id,omitempty"`
+	Title   string    `json:"title"`
+	Date    time.Time `json:"date"`
+	Path    string    `json:"path,omitempty"`
+	Line    int       `json:"line,omitempty"`
+	Snippet string    `json:"snippet"`
+	Score   float64   `json:"score"`
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	q := search.Query{Text: strings.Join(args, " "), Rig: searchRig, Limit: searchLimit}
+	switch strings.ToLower(searchType) {
+	case search.KindBead, search.KindMail, search.KindTranscript:
+		q.Kind = strings.ToLower(searchType)
+	default:
+		q.Type = searchType
+	}
+	if q.Since, err = parseSearchTime(searchSince); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if q.Until, err = parseSearchTime(searchUntil); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+	if len(search.Tokenize(q.Text)) == 0 {
+		return fmt.Errorf("nothing to search for in %q", q.Text)
+	}
+
+	var ix *search.Index
+	if searchNoRefresh {
+		ix, err = search.Load(townRoot)
+	} else {
+		ix, err = search.Update(townRoot, func(ix *search.Index) error {
+			if searchReindex {
+				*ix = *search.New()
+			}
+			refreshSearchIndex(townRoot, ix)
+			return nil
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("updating search index: %w", err)
+	}
+
+	hits := ix.Search(q)
+	if searchJSON {
+		results := make([]searchResult, 0, len(hits))
+		for _, h := range hits {
+			results = append(results, searchResult{
+				Kind:    h.Doc.Kind,
+				Rig:     h.Doc.Rig,
+				Type:    h.Doc.Type,
+				ID:      h.Doc.ID,
+				Title:   h.Doc.Title,
+				Date:    h.Doc.Date,
+				Path:    h.Doc.Path,
+				Line:    h.Doc.Line,
+				Snippet: h.Snippet(q.Text, 200),
+				Score:   h.Score,
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(hits) == 0 {
+		fmt.Printf("No results for %q\n", q.Text)
+		return nil
+	}
+	for _, h := range hits {
+		printSearchHit(h, q.Text)
+	}
+	return nil
+}
+
+// refreshSearchIndex brings the index up to date with the town's and each
+// rig's beads and the transcripts. A source that can't be read only warns;
+// its last indexed docs stay searchable.
+func refreshSearchIndex(townRoot string, ix *search.Index) {
+	if err := ix.IndexBeads("", beads.New(beads.GetTownBeadsPath(townRoot))); err != nil {
+		style.PrintWarning("could not index town beads: %v", err)
+	}
+	rigs, err := discoverAllRigs(townRoot)
+	if err != nil {
+		style.PrintWarning("could not list rigs: %v", err)
+	}
+	for _, r := range rigs {
+		if err := ix.IndexBeads(r.Name, beads.New(r.BeadsPath())); err != nil {
+			style.PrintWarning("could not index %s beads: %v", r.Name, err)
+		}
+	}
+	if err := ix.IndexTranscripts(townRoot); err != nil {
+		style.PrintWarning("could not index transcripts: %v", err)
+	}
+}
+
+// printSearchHit prints one result for people.
+func printSearchHit(h search.Hit, query string) {
+	d := h.Doc
+	where := d.Rig
+	if where == "" {
+		where = "town"
+	}
+	ref := d.ID
+	switch d.Kind {
+	case search.KindTranscript:
+		ref = fmt.Sprintf("%s:%d", d.Path, d.Line)
+	case search.KindBead:
+		if d.Type != "" {
+			where += " " + d.Type
+		}
+	}
+	fmt.Printf("%s %s  %s\n", style.Bold.Render(d.Kind), d.Title, style.Dim.Render(fmt.Sprintf("[%s · %s]", where, d.Date.Local().Format("2006-01-02"))))
+	if ref != "" {
+		fmt.Printf("  %s\n", style.Dim.Render(ref))
+	}
+	if snippet := h.Snippet(query, 120); snippet != "" && snippet != d.Title {
+		fmt.Printf("  %s\n", snippet)
+	}
+	fmt.Println()
+}
+
+// parseSearchTime parses a date (2006-01-02) or an age before now (24h,
+// 7d). Empty is the zero time.
+func parseSearchTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a date nor an age", s)
+	}
+	return time.Now().Add(-d), nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -568,7 +569,7 @@ func runSessionStatus(cmd *cobra.Command, args []string) error {
 
 	if !info.Created.IsZero() {
 		uptime := time.Since(info.Created)
-		fmt.Printf("  Created: %s\n", info.Created.Format("2006-01-02 15:04:05"))
+		fmt.Printf("  Created: %s\n", format.DateTimeSeconds(info.Created))
 		fmt.Printf("  Uptime: %s\n", formatDuration(uptime))
 	}
 
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/procsession"
+)
+
+var sessionHostCmd = &cobra.Command{
+	Use:    procsession.HostCommand + " <name>",
+	Short:  "Host a process-backend session (internal)",
+	Hidden: true, // Started by the process session backend
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return procsession.HostManager().Host(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sessionHostCmd)
+}
@@ -10,12 +10,14 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/molruns"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var slingCmd = &cobra.Command{
 	Use:     "sling <bead-or-formula> [target]",
+	Aliases: []string{"spawn"},
 	GroupID: GroupWork,
 	Short:   "Assign work to an agent (THE unified work dispatch command)",
 	Long: `Sling work onto an agent's hook and start working immediately.
@@ -48,6 +50,25 @@ Spawning Options (when target is a rig):
   gt sling gp-abc greenplace --create               # Create polecat if missing
   gt sling gp-abc greenplace --force                # Ignore unread mail
   gt sling gp-abc greenplace --account work         # Use specific Claude account
+  gt sling gp-abc greenplace --runtime docker       # Run the polecat in a container
+  gt sling gp-abc greenplace --over-budget          # Spawn past the spend caps (gt budget)
+  gt sling gp-abc greenplace --tier opus            # Run Claude at a model tier
+  gt sling gp-abc greenplace --sandbox off          # Override the rig's sandbox profile
+  gt sling gp-abc greenplace --branch 'fix/{bead}'  # Name the branch polecat/fix/<bead>
+
+Spawn Profiles:
+  Named sets of spawn settings for recurring kinds of work, kept under
+  spawn_profiles in settings/config.json (rig, agent, tier, molecule,
+  sandbox, runtime, account, branch). Flags given alongside override them.
+
+  gt spawn gp-abc --profile writer-fixes            # Rig, agent, molecule... from the profile
+
+Previewing the Prompt:
+  gt spawn gp-abc greenplace --preview      # Print what the new polecat would be told
+
+  --preview prints the composed prompt - project instructions, role pack,
+  hooked bead, first molecule step and start nudge - without spawning or
+  hooking anything, so context assembly can be reviewed and tuned.
 
 Natural Language Args:
   gt sling gt-abc --args "patch release"
@@ -84,6 +105,7 @@ var (
 	slingSubject  string
 	slingMessage  string
 	slingDryRun   bool
+	slingPreview  bool
 	slingOnTarget string   // --on flag: target bead when slinging a formula
 	slingVars     []string // --var flag: formula variables (key=value)
 	slingArgs     string   // --args flag: natural language instructions for executor
@@ -93,13 +115,25 @@ var (
 	slingForce    bool   // --force: force spawn even if polecat has unread mail
 	slingAccount  string // --account: Claude Code account handle to use
 	slingAgent    string // --agent: override runtime agent for this sling/spawn
+	slingRuntime  string // --runtime: run the polecat locally or in a container
 	slingNoConvoy bool   // --no-convoy: skip auto-convoy creation
+
+	slingOverBudget bool // --over-budget: spawn even when spend caps pause or stop spawning
+
+	slingProfile string // --profile: named spawn profile from town settings
+	slingTier    string // --tier: model tier (see gt models)
+	slingSandbox string // --sandbox: sandbox profile override for the polecat
+	slingBranch  string // --branch: polecat branch name template
+
+	slingReplayOf string // --replay-of: molecule run being replayed (gt mol replay)
+	slingFromStep string // --from-step: step a replay starts at
 )
 
 func init() {
 	slingCmd.Flags().StringVarP(&slingSubject, "subject", "s", "", "Context subject for the work")
 	slingCmd.Flags().StringVarP(&slingMessage, "message", "m", "", "Context message for the work")
 	slingCmd.Flags().BoolVarP(&slingDryRun, "dry-run", "n", false, "Show what would be done")
+	slingCmd.Flags().BoolVar(&slingPreview, "preview", false, "Print the composed prompt the agent would receive, without slinging")
 	slingCmd.Flags().StringVar(&slingOnTarget, "on", "", "Apply formula to existing bead (implies wisp scaffolding)")
 	slingCmd.Flags().StringArrayVar(&slingVars, "var", nil, "Formula variable (key=value), can be repeated")
 	slingCmd.Flags().StringVarP(&slingArgs, "args", "a", "", "Natural language instructions for the executor (e.g., 'patch release')")
@@ -109,7 +143,19 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingForce, "force", false, "Force spawn even if polecat has unread mail")
 	slingCmd.Flags().StringVar(&slingAccount, "account", "", "Claude Code account handle to use")
 	slingCmd.Flags().StringVar(&slingAgent, "agent", "", "Override agent/runtime for this sling (e.g., claude, gemini, codex, or custom alias)")
+	slingCmd.Flags().StringVar(&slingRuntime, "runtime", "", "Polecat runtime: local or docker (default: rig's container settings)")
 	slingCmd.Flags().BoolVar(&slingNoConvoy, "no-convoy", false, "Skip auto-convoy creation for single-issue sling")
+	slingCmd.Flags().BoolVar(&slingOverBudget, "over-budget", false, "Spawn even when the town's spend caps pause or stop spawning")
+	slingCmd.Flags().StringVar(&slingProfile, "profile", "", "Apply a named spawn profile from town settings")
+	slingCmd.Flags().StringVar(&slingTier, "tier", "", "Model tier for the polecat: haiku, sonnet, opus or a tier from town settings (see gt models)")
+	slingCmd.Flags().StringVar(&slingSandbox, "sandbox", "", "Sandbox profile for the polecat (default: rig's sandbox settings; off to disable)")
+	slingCmd.Flags().StringVar(&slingBranch, "branch", "", "Polecat branch name after polecat/ ({name}, {bead}, {rig}, {ts}; default {name}/{bead})")
+
+	// Set by 'gt mol replay'
+	slingCmd.Flags().StringVar(&slingReplayOf, "replay-of", "", "Molecule run this sling replays")
+	slingCmd.Flags().StringVar(&slingFromStep, "from-step", "", "Close the steps before this one in the new molecule")
+	_ = slingCmd.Flags().MarkHidden("replay-of")
+	_ = slingCmd.Flags().MarkHidden("from-step")
 
 	rootCmd.AddCommand(slingCmd)
 }
@@ -128,11 +174,19 @@ func runSling(cmd *cobra.Command, args []string) error {
 	}
 	townBeadsDir := filepath.Join(townRoot, ".beads")
 
+	if args, err = applySpawnProfile(cmd, townRoot, args); err != nil {
+		return err
+	}
+
 	// --var is only for standalone formula mode, not formula-on-bead mode
 	if slingOnTarget != "" && len(slingVars) > 0 {
 		return fmt.Errorf("--var cannot be used with --on (formula-on-bead mode doesn't support variables)")
 	}
 
+	if slingPreview && len(args) > 2 {
+		return fmt.Errorf("--preview takes one bead or formula")
+	}
+
 	// Batch mode detection: multiple beads with rig target
 	// Pattern: gt sling gt-abc gt-def gt-ghi gastown
 	// When len(args) > 2 and last arg is a rig, sling each bead to its own polecat
@@ -170,6 +224,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 			// Not a verified bead - try as standalone formula
 			if err := verifyFormulaExists(firstArg); err == nil {
 				// Standalone formula mode: gt sling <formula> [target]
+				if slingPreview {
+					return runSlingPreview(townRoot, "", firstArg, previewTarget(args))
+				}
 				return runSlingFormula(args)
 			}
 			// Not a formula either - check if it looks like a bead ID (routing issue workaround).
@@ -184,6 +241,26 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// An ID from before a prefix rename is slung under its current ID
+	beadID = beads.CurrentID(townRoot, beadID)
+
+	// A bead made from a template names its default molecule
+	if formulaName == "" {
+		if info, err := getBeadInfo(beadID); err == nil {
+			if m := beadMolecule(info.Labels); m != "" {
+				if err := verifyFormulaExists(m); err != nil {
+					style.PrintWarning("%s names molecule %s, which isn't available; slinging without it", beadID, m)
+				} else {
+					formulaName = m
+				}
+			}
+		}
+	}
+
+	if slingPreview {
+		return runSlingPreview(townRoot, beadID, formulaName, previewTarget(args))
+	}
+
 	// Determine target agent (self or specified)
 	var targetAgent string
 	var targetPane string
@@ -228,18 +305,41 @@ func runSling(cmd *cobra.Command, args []string) error {
 				targetAgent = fmt.Sprintf("%s/polecats/<new>", rigName)
 				targetPane = "<new-pane>"
 			} else {
-				// Spawn a fresh polecat in the rig
-				fmt.Printf("Target is rig '%s', spawning fresh polecat...\n", rigName)
-				spawnOpts := SlingSpawnOptions{
-					Force:    slingForce,
-					Account:  slingAccount,
-					Create:   slingCreate,
-					HookBead: beadID, // Set atomically at spawn time
-					Agent:    slingAgent,
+				admitted, err := admitSpawn(rigName, beadID, true)
+				if err != nil {
+					return err
 				}
-				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
-				if spawnErr != nil {
-					return fmt.Errorf("spawning polecat: %w", spawnErr)
+				if !admitted {
+					return nil
+				}
+
+				// A warm spare is already primed and only needs the work
+				var spawnInfo *SpawnedPolecatInfo
+				if warmClaimable() {
+					spawnInfo, err = claimWarmPolecat(rigName, beadID)
+					if err != nil {
+						fmt.Printf("%s Could not claim warm polecat: %v\n", style.Dim.Render("Warning:"), err)
+					}
+				}
+				if spawnInfo == nil {
+					// Spawn a fresh polecat in the rig
+					fmt.Printf("Target is rig '%s', spawning fresh polecat...\n", rigName)
+					spawnOpts := SlingSpawnOptions{
+						Force:    slingForce,
+						Account:  slingAccount,
+						Create:   slingCreate,
+						HookBead: beadID, // Set atomically at spawn time
+						Agent:    slingAgent,
+						Runtime:  slingRuntime,
+						Tier:     slingTier,
+						Sandbox:  slingSandbox,
+						Branch:   slingBranch,
+					}
+					var spawnErr error
+					spawnInfo, spawnErr = SpawnPolecatForSling(rigName, spawnOpts)
+					if spawnErr != nil {
+						return fmt.Errorf("spawning polecat: %w", spawnErr)
+					}
 				}
 				targetAgent = spawnInfo.AgentID()
 				targetPane = spawnInfo.Pane
@@ -292,6 +392,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 		return fmt.Errorf("bead %s is already pinned to %s\nUse --force to re-sling", beadID, assignee)
 	}
+	if waits := remoteWaits(info.Dependencies); len(waits) > 0 && !slingForce {
+		return fmt.Errorf("bead %s waits on %s in a peer town (see 'gt convoy block')\nUse --force to sling anyway", beadID, waits[0])
+	}
 
 	// Auto-convoy: check if issue is already tracked by a convoy
 	// If not, create one for dashboard visibility (unless --no-convoy is set)
@@ -374,6 +477,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("parsing wisp output: %w", err)
 		}
 		fmt.Printf("%s Formula wisp created: %s\n", style.Bold.Render("✓"), wispRootID)
+		moleculeRootID := wispRootID
 
 		// Step 3: Bond wisp to original bead (creates compound)
 		// Use --no-daemon for mol bond (requires direct database access)
@@ -400,6 +504,17 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 		fmt.Printf("%s Formula bonded to %s\n", style.Bold.Render("✓"), beadID)
 
+		if err := startMoleculeRun(townRoot, formulaWorkDir, &molruns.Run{
+			ID:      moleculeRootID,
+			Hook:    wispRootID,
+			Formula: formulaName,
+			Vars:    []string{featureVar, issueVar},
+			Bead:    beadID,
+			Target:  targetAgent,
+		}); err != nil {
+			return err
+		}
+
 		// Update beadID to hook the compound root instead of bare bead
 		beadID = wispRootID
 	}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/ratelimit"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// defaultQueuePriority is used for slings without a bead (formulas) and
+// beads whose priority can't be read.
+const defaultQueuePriority = 2
+
+// slingFlags are the flags slingReplayArgs carries over. Set in init, as
+// referring to slingCmd here would make its initialization cyclic.
+var slingFlags *pflag.FlagSet
+
+func init() {
+	slingFlags = slingCmd.Flags()
+}
+
+// admitSpawn asks the scheduler whether a polecat may start in rigName for
+// subject (a bead or formula). When the town or rig is at its limit, or
+// spend has reached the budget's warning threshold, the sling is queued for
+// the daemon to dispatch later, and admitSpawn returns false: the caller
+// should stop without spawning. Slings are also queued while the polecat's
+// model provider is rate limited (see gt ratelimit). Once a budget cap is exhausted the sling is
+// refused instead, unless --over-budget is set.
+func admitSpawn(rigName, subject string, isBead bool) (bool, error) {
+	if os.Getenv(scheduler.EnvAdmitted) != "" {
+		return true, nil
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return false, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	status, err := checkBudget(townRoot)
+	if err != nil {
+		return false, err
+	}
+	if !slingOverBudget {
+		switch status.Level {
+		case budget.Exceeded:
+			return false, fmt.Errorf("spawning stopped: %s (raise the cap or use --over-budget)", status.Reason())
+		case budget.Warn:
+			return false, queueSpawn(townRoot, rigName, subject, isBead, status.Reason())
+		}
+	}
+
+	state, err := scheduler.Load(townRoot)
+	if err != nil {
+		return false, fmt.Errorf("checking spawn limits: %w", err)
+	}
+	if ok, reason := state.Limits.Check(state.Running, rigName); !ok {
+		return false, queueSpawn(townRoot, rigName, subject, isBead, reason)
+	}
+
+	// A new session opens with a burst of requests; hold it while the
+	// provider is backing off or the town has spent its request budget
+	provider := spawnProvider(townRoot)
+	limiter, err := ratelimit.Load(townRoot)
+	if err != nil {
+		return false, err
+	}
+	wait, err := limiter.Take(provider)
+	if err != nil {
+		return false, fmt.Errorf("checking rate limits: %w", err)
+	}
+	if wait > 0 {
+		return false, queueSpawn(townRoot, rigName, subject, isBead, fmt.Sprintf("%s rate limited for %s", provider, wait.Round(time.Second)))
+	}
+	return true, nil
+}
+
+// checkBudget measures the town's spend against its caps, announcing any
+// newly reached threshold.
+func checkBudget(townRoot string) (*budget.Status, error) {
+	now := time.Now()
+	status, err := budget.Load(townRoot, now)
+	if err != nil {
+		return nil, fmt.Errorf("checking budget: %w", err)
+	}
+	if err := budget.Announce(townRoot, status, now); err != nil {
+		style.PrintWarning("could not record budget alert: %v", err)
+	}
+	return status, nil
+}
+
+// queueSpawn adds the sling to the spawn queue, explaining why it waits.
+func queueSpawn(townRoot, rigName, subject string, isBead bool, reason string) error {
+	priority := defaultQueuePriority
+	if isBead {
+		if info, err := getBeadInfo(subject); err == nil {
+			priority = info.Priority
+		}
+	}
+	q := scheduler.NewQueue(townRoot)
+	req, err := q.Add(scheduler.Request{
+		Rig:      rigName,
+		Bead:     subject,
+		Priority: priority,
+		Args:     slingReplayArgs(subject, rigName),
+	})
+	if err != nil {
+		return fmt.Errorf("queueing spawn: %w", err)
+	}
+	fmt.Printf("%s Queued %s for %s (%s); position %d, priority P%d\n",
+		style.Bold.Render("⏳"), subject, rigName, reason, q.Position(req.ID), req.Priority)
+	fmt.Printf("  The daemon dispatches it once it fits under the limits (gt scheduler status)\n")
+	return nil
+}
+
+// slingReplayArgs returns the gt arguments that re-run this sling for one
+// subject, carrying over the flags the user set.
+func slingReplayArgs(subject, rigName string) []string {
+	args := []string{"sling", subject, rigName}
+	slingFlags.Visit(func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				args = append(args, "--"+f.Name+"="+v)
+			}
+			return
+		}
+		args = append(args, "--"+f.Name+"="+f.Value.String())
+	})
+	return args
+}
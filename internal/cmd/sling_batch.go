@@ -36,6 +36,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		beadID  string
 		polecat string
 		success bool
+		queued  bool
 		errMsg  string
 	}
 	results := make([]slingResult, 0, len(beadIDs))
@@ -57,6 +58,22 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			fmt.Printf("  %s Already pinned (use --force to re-sling)\n", style.Dim.Render("✗"))
 			continue
 		}
+		if waits := remoteWaits(info.Dependencies); len(waits) > 0 && !slingForce {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "waits on " + waits[0].String()})
+			fmt.Printf("  %s Waits on %s in a peer town (use --force to sling anyway)\n", style.Dim.Render("✗"), waits[0])
+			continue
+		}
+
+		admitted, err := admitSpawn(rigName, beadID, true)
+		if err != nil {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: err.Error()})
+			fmt.Printf("  %s %v\n", style.Dim.Render("✗"), err)
+			continue
+		}
+		if !admitted {
+			results = append(results, slingResult{beadID: beadID, queued: true})
+			continue
+		}
 
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
@@ -65,6 +82,10 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Create:   slingCreate,
 			HookBead: beadID, // Set atomically at spawn time
 			Agent:    slingAgent,
+			Runtime:  slingRuntime,
+			Tier:     slingTier,
+			Sandbox:  slingSandbox,
+			Branch:   slingBranch,
 		}
 		spawnInfo, err := SpawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -134,17 +155,23 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 	wakeRigAgents(rigName)
 
 	// Print summary
-	successCount := 0
+	successCount, queuedCount := 0, 0
 	for _, r := range results {
 		if r.success {
 			successCount++
 		}
+		if r.queued {
+			queuedCount++
+		}
 	}
 
 	fmt.Printf("\n%s Batch sling complete: %d/%d succeeded\n", style.Bold.Render("📊"), successCount, len(beadIDs))
-	if successCount < len(beadIDs) {
+	if queuedCount > 0 {
+		fmt.Printf("  %s %d queued until polecats free up (gt scheduler status)\n", style.Bold.Render("⏳"), queuedCount)
+	}
+	if successCount+queuedCount < len(beadIDs) {
 		for _, r := range results {
-			if !r.success {
+			if !r.success && !r.queued {
 				fmt.Printf("  %s %s: %s\n", style.Dim.Render("✗"), r.beadID, r.errMsg)
 			}
 		}
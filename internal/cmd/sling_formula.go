@@ -10,6 +10,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/molruns"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -128,6 +129,14 @@ func runSlingFormula(args []string) error {
 				targetAgent = fmt.Sprintf("%s/polecats/<new>", rigName)
 				targetPane = "<new-pane>"
 			} else {
+				admitted, err := admitSpawn(rigName, formulaName, false)
+				if err != nil {
+					return err
+				}
+				if !admitted {
+					return nil
+				}
+
 				// Spawn a fresh polecat in the rig
 				fmt.Printf("Target is rig '%s', spawning fresh polecat...\n", rigName)
 				spawnOpts := SlingSpawnOptions{
@@ -135,6 +144,10 @@ func runSlingFormula(args []string) error {
 					Account: slingAccount,
 					Create:  slingCreate,
 					Agent:   slingAgent,
+					Runtime: slingRuntime,
+					Tier:    slingTier,
+					Sandbox: slingSandbox,
+					Branch:  slingBranch,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -210,6 +223,15 @@ func runSlingFormula(args []string) error {
 
 	fmt.Printf("%s Wisp created: %s\n", style.Bold.Render("✓"), wispRootID)
 
+	if err := startMoleculeRun(townRoot, beads.ResolveHookDir(townRoot, wispRootID, ""), &molruns.Run{
+		ID:      wispRootID,
+		Formula: formulaName,
+		Vars:    slingVars,
+		Target:  targetAgent,
+	}); err != nil {
+		return err
+	}
+
 	// Step 3: Hook the wisp bead using bd update.
 	// See: https://github.com/steveyegge/gastown/issues/148
 	hookCmd := exec.Command("bd", "--no-daemon", "update", wispRootID, "--status=hooked", "--assignee="+targetAgent)
@@ -251,14 +273,8 @@ func runSlingFormula(args []string) error {
 		return nil
 	}
 
-	var prompt string
-	if slingArgs != "" {
-		prompt = fmt.Sprintf("Formula %s slung. Args: %s. Run `gt hook` to see your hook, then execute using these args.", formulaName, slingArgs)
-	} else {
-		prompt = fmt.Sprintf("Formula %s slung. Run `gt hook` to see your hook, then execute the steps.", formulaName)
-	}
 	t := tmux.NewTmux()
-	if err := t.NudgePane(targetPane, prompt); err != nil {
+	if err := t.NudgePane(targetPane, formulaStartPrompt(formulaName, slingArgs)); err != nil {
 		// Graceful fallback for no-tmux mode
 		fmt.Printf("%s Could not nudge (no tmux?): %v\n", style.Dim.Render("○"), err)
 		fmt.Printf("  Agent will discover work via gt prime / bd show\n")
@@ -268,3 +284,12 @@ func runSlingFormula(args []string) error {
 
 	return nil
 }
+
+// formulaStartPrompt builds the "start now" prompt injected after slinging
+// a standalone formula.
+func formulaStartPrompt(formulaName, args string) string {
+	if args != "" {
+		return fmt.Sprintf("Formula %s slung. Args: %s. Run `gt hook` to see your hook, then execute using these args.", formulaName, args)
+	}
+	return fmt.Sprintf("Formula %s slung. Run `gt hook` to see your hook, then execute the steps.", formulaName)
+}
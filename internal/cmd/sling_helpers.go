@@ -16,9 +16,13 @@ import (
 
 // beadInfo holds status and assignee for a bead.
 type beadInfo struct {
-	Title    string `json:"title"`
-	Status   string `json:"status"`
-	Assignee string `json:"assignee"`
+	Title    string   `json:"title"`
+	Status   string   `json:"status"`
+	Assignee string   `json:"assignee"`
+	Priority int      `json:"priority"`
+	Labels   []string `json:"labels"`
+
+	Dependencies []beads.IssueDep `json:"dependencies"`
 }
 
 // verifyBeadExists checks that the bead exists using bd show.
@@ -29,12 +33,14 @@ type beadInfo struct {
 // while still finding beads when database is out of sync with JSONL.
 // For existence checks, stale data is acceptable - we just need to know it exists.
 func verifyBeadExists(beadID string) error {
-	cmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
 	// Run from town root so bd can find routes.jsonl for prefix-based routing.
 	// Do NOT set BEADS_DIR - that overrides routing and breaks rig bead resolution.
-	if townRoot, err := workspace.FindFromCwd(); err == nil {
-		cmd.Dir = townRoot
+	townRoot, err := workspace.FindFromCwd()
+	if err == nil && townRoot != "" {
+		beadID = beads.CurrentID(townRoot, beadID) // An ID from before a prefix rename
 	}
+	cmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
+	cmd.Dir = townRoot
 	// Use Output() instead of Run() to detect bd --no-daemon exit 0 bug:
 	// when issue not found, --no-daemon exits 0 but produces empty stdout.
 	out, err := cmd.Output()
@@ -51,11 +57,13 @@ func verifyBeadExists(beadID string) error {
 // Uses bd's native prefix-based routing via routes.jsonl.
 // Uses --no-daemon with --allow-stale for consistency with verifyBeadExists.
 func getBeadInfo(beadID string) (*beadInfo, error) {
-	cmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
 	// Run from town root so bd can find routes.jsonl for prefix-based routing.
-	if townRoot, err := workspace.FindFromCwd(); err == nil {
-		cmd.Dir = townRoot
+	townRoot, err := workspace.FindFromCwd()
+	if err == nil && townRoot != "" {
+		beadID = beads.CurrentID(townRoot, beadID) // An ID from before a prefix rename
 	}
+	cmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
+	cmd.Dir = townRoot
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("bead '%s' not found", beadID)
@@ -175,7 +183,13 @@ func injectStartPrompt(pane, beadID, subject, args string) error {
 		return fmt.Errorf("no target pane")
 	}
 
-	// Build the prompt to inject
+	// Use the reliable nudge pattern (same as gt nudge / tmux.NudgeSession)
+	t := tmux.NewTmux()
+	return t.NudgePane(pane, startPrompt(beadID, subject, args))
+}
+
+// startPrompt builds the "start now" prompt injected after slinging.
+func startPrompt(beadID, subject, args string) string {
 	var prompt string
 	if args != "" {
 		// Args provided - include them prominently in the prompt
@@ -189,10 +203,7 @@ func injectStartPrompt(pane, beadID, subject, args string) error {
 	} else {
 		prompt = fmt.Sprintf("Work slung: %s. Start working on it now - run `gt hook` to see the hook, then begin.", beadID)
 	}
-
-	// Use the reliable nudge pattern (same as gt nudge / tmux.NudgeSession)
-	t := tmux.NewTmux()
-	return t.NudgePane(pane, prompt)
+	return prompt
 }
 
 // getSessionFromPane extracts session name from a pane target.
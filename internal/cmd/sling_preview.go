@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/prompts"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// previewPolecat stands in for the name of a polecat not yet spawned.
+const previewPolecat = "<new>"
+
+// runSlingPreview prints the prompt an agent would receive for this sling,
+// in the order it receives it: project instructions from the worktree, the
+// gt prime output (role pack, hooked work, molecule step, bd prime), then
+// the start nudge. Nothing is spawned, hooked or created.
+//
+// beadID is empty for a standalone formula.
+func runSlingPreview(townRoot, beadID, formulaName, target string) error {
+	workDir, err := previewWorkDir(townRoot, target)
+	if err != nil {
+		return err
+	}
+	info := detectRole(workDir, townRoot)
+	if info.Role == RoleUnknown {
+		return fmt.Errorf("cannot preview for target %q: not an agent home", target)
+	}
+	ctx := RoleContext{
+		Role:     info.Role,
+		Rig:      info.Rig,
+		Polecat:  info.Polecat,
+		TownRoot: townRoot,
+		WorkDir:  workDir,
+	}
+
+	var bead *beads.Issue
+	if beadID != "" {
+		if bead, err = beads.New(beadLocation(townRoot, beadID)).Show(beadID); err != nil {
+			return fmt.Errorf("loading %s: %w", beadID, err)
+		}
+	}
+	var f *formula.Formula
+	if formulaName != "" {
+		path, err := findFormulaFile(formulaName)
+		if err != nil {
+			return err
+		}
+		if f, err = formula.ParseFile(path); err != nil {
+			return fmt.Errorf("parsing formula %s: %w", formulaName, err)
+		}
+	}
+
+	if ctx.Rig != "" {
+		for _, name := range []string{"CLAUDE.md", "AGENTS.md"} {
+			path := filepath.Join(townRoot, ctx.Rig, "mayor", "rig", name)
+			data, err := os.ReadFile(path) //nolint:gosec // G304: path is inside the town
+			if err != nil {
+				continue
+			}
+			previewSection("Project instructions: " + name)
+			fmt.Println(strings.TrimRight(string(data), "\n"))
+		}
+	}
+
+	previewSection(fmt.Sprintf("Role pack: %s (%s)", ctx.Role, prompts.ActiveVersion(townRoot)))
+	if err := outputPrimeContext(ctx); err != nil {
+		return err
+	}
+
+	if bead != nil {
+		previewSection("Hooked work")
+		outputHookedWork(ctx, bead)
+		outputBeadDetails(bead.ID)
+	}
+
+	if f != nil {
+		previewSection("Molecule step: " + f.Name)
+		if steps := f.ReadySteps(nil); len(steps) > 0 {
+			title, description := previewStep(f, steps[0])
+			vars := previewVars(f, bead)
+			outputCurrentStep(steps[0], vars.Replace(title), vars.Replace(description), "open")
+		} else {
+			fmt.Println(style.Dim.Render("(formula has no steps)"))
+		}
+	}
+
+	previewSection("bd prime")
+	runBdPrime(previewBeadsDir(townRoot, ctx))
+
+	previewSection("Start nudge")
+	if bead == nil {
+		fmt.Println(formulaStartPrompt(formulaName, slingArgs))
+	} else {
+		hookID := beadID
+		if f != nil {
+			hookID = "<molecule>" // The compound root, made when slinging
+		}
+		fmt.Println(startPrompt(hookID, slingSubject, slingArgs))
+	}
+	fmt.Println()
+	fmt.Println(style.Dim.Render("Pending mail (gt mail check --inject) is added at session start and isn't shown."))
+	return nil
+}
+
+// previewWorkDir maps a sling target to the home directory of the agent,
+// which is what gt prime detects the role from. A rig target becomes a new
+// polecat in that rig; no target is the current agent.
+func previewWorkDir(townRoot, target string) (string, error) {
+	if target == "" || target == "." {
+		return os.Getwd()
+	}
+	if rigName, isRig := IsRigName(target); isRig {
+		return filepath.Join(townRoot, rigName, "polecats", previewPolecat), nil
+	}
+	switch strings.TrimSuffix(target, "/") {
+	case "mayor", "may":
+		return filepath.Join(townRoot, "mayor"), nil
+	case "deacon", "dea":
+		return filepath.Join(townRoot, "deacon"), nil
+	}
+	parts := strings.Split(strings.TrimSuffix(target, "/"), "/")
+	switch {
+	case len(parts) == 2 && (parts[1] == "witness" || parts[1] == "refinery"):
+		return filepath.Join(townRoot, parts[0], parts[1], "rig"), nil
+	case len(parts) == 2:
+		return filepath.Join(townRoot, parts[0], "polecats", parts[1]), nil
+	case len(parts) == 3 && (parts[1] == "polecats" || parts[1] == "crew"):
+		return filepath.Join(townRoot, parts[0], parts[1], parts[2]), nil
+	}
+	return "", fmt.Errorf("cannot preview for target %q (use a rig, mayor, deacon, <rig>/witness, <rig>/refinery, <rig>/crew/<name> or <rig>/<polecat>)", target)
+}
+
+// previewBeadsDir is where the agent's bd prime would run: its home if it
+// exists yet (a polecat's worktree doesn't), else its rig or the town.
+func previewBeadsDir(townRoot string, ctx RoleContext) string {
+	if _, err := os.Stat(ctx.WorkDir); err == nil {
+		return ctx.WorkDir
+	}
+	if ctx.Rig != "" {
+		return filepath.Join(townRoot, ctx.Rig)
+	}
+	return townRoot
+}
+
+// previewStep returns the title and description of a formula step, leg or
+// aspect.
+func previewStep(f *formula.Formula, id string) (title, description string) {
+	if s := f.GetStep(id); s != nil {
+		return s.Title, s.Description
+	}
+	if t := f.GetTemplate(id); t != nil {
+		return t.Title, t.Description
+	}
+	if l := f.GetLeg(id); l != nil {
+		return l.Title, l.Description
+	}
+	if a := f.GetAspect(id); a != nil {
+		return a.Title, a.Description
+	}
+	return id, ""
+}
+
+// previewVars fills {{var}} placeholders the way sling instantiates the
+// formula: defaults, then the bead's feature and issue, then --var.
+func previewVars(f *formula.Formula, bead *beads.Issue) *strings.Replacer {
+	values := map[string]string{}
+	for name, v := range f.Vars {
+		if v.Default != "" {
+			values[name] = v.Default
+		}
+	}
+	if bead != nil {
+		values["feature"] = bead.Title
+		values["issue"] = bead.ID
+	}
+	for _, kv := range slingVars {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			values[k] = v
+		}
+	}
+	var pairs []string
+	for k, v := range values {
+		pairs = append(pairs, "{{"+k+"}}", v)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+func previewSection(title string) {
+	fmt.Println()
+	fmt.Println(style.Dim.Render("──── " + title + " ────"))
+	fmt.Println()
+}
+
+// previewTarget returns the sling target from the arguments, if any.
+func previewTarget(args []string) string {
+	if len(args) > 1 {
+		return args[1]
+	}
+	return ""
+}
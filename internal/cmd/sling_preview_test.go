@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestPreviewWorkDir(t *testing.T) {
+	townRoot := t.TempDir()
+	tests := []struct {
+		target  string
+		role    Role
+		polecat string
+	}{
+		{"mayor", RoleMayor, ""},
+		{"deacon/", RoleDeacon, ""},
+		{"gastown/witness", RoleWitness, ""},
+		{"gastown/refinery", RoleRefinery, ""},
+		{"gastown/crew/max", RoleCrew, "max"},
+		{"gastown/polecats/Toast", RolePolecat, "Toast"},
+		{"gastown/Toast", RolePolecat, "Toast"},
+	}
+	for _, tt := range tests {
+		dir, err := previewWorkDir(townRoot, tt.target)
+		if err != nil {
+			t.Errorf("%s: %v", tt.target, err)
+			continue
+		}
+		info := detectRole(dir, townRoot)
+		if info.Role != tt.role || info.Polecat != tt.polecat {
+			t.Errorf("%s: got %s/%q, want %s/%q", tt.target, info.Role, info.Polecat, tt.role, tt.polecat)
+		}
+	}
+
+	if _, err := previewWorkDir(townRoot, "deacon/dogs/alpha"); err == nil {
+		t.Error("expected an error for a dog target")
+	}
+	if dir, _ := previewWorkDir(townRoot, "gastown/Toast"); dir != filepath.Join(townRoot, "gastown", "polecats", "Toast") {
+		t.Errorf("dir = %s", dir)
+	}
+}
+
+func TestPreviewVars(t *testing.T) {
+	old := slingVars
+	defer func() { slingVars = old }()
+	slingVars = []string{"level=high"}
+
+	f := &formula.Formula{Vars: map[string]formula.Var{
+		"level": {Default: "low"},
+		"depth": {Default: "3"},
+	}}
+	vars := previewVars(f, &beads.Issue{ID: "gt-abc", Title: "Login page"})
+	got := vars.Replace("Design {{feature}} ({{issue}}) at {{level}}, depth {{depth}}, {{unset}}")
+	want := "Design Login page (gt-abc) at high, depth 3, {{unset}}"
+	if got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestStartPrompt(t *testing.T) {
+	if got := startPrompt("gt-abc", "", ""); got != "Work slung: gt-abc. Start working on it now - run `gt hook` to see the hook, then begin." {
+		t.Errorf("got %q", got)
+	}
+	if got := startPrompt("gt-abc", "login", "patch release"); got != "Work slung: gt-abc (login). Args: patch release. Start working now - use these args to guide your execution." {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/models"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// applySpawnProfile fills the sling flags from the --profile named in town
+// settings and returns the args to sling with. Flags given on the command
+// line win over the profile. The profile's rig becomes the target when
+// none is given, and its molecule is applied to the bead as with --on.
+func applySpawnProfile(cmd *cobra.Command, townRoot string, args []string) ([]string, error) {
+	if slingProfile != "" {
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if err != nil {
+			return nil, fmt.Errorf("loading town settings: %w", err)
+		}
+		profile, ok := settings.SpawnProfiles[slingProfile]
+		if !ok || profile == nil {
+			names := make([]string, 0, len(settings.SpawnProfiles))
+			for name := range settings.SpawnProfiles {
+				names = append(names, name)
+			}
+			if len(names) == 0 {
+				return nil, fmt.Errorf("unknown spawn profile %q (no spawn_profiles in town settings)", slingProfile)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown spawn profile %q (known: %s)", slingProfile, strings.Join(names, ", "))
+		}
+
+		for _, f := range []struct {
+			flag  string
+			dst   *string
+			value string
+		}{
+			{"agent", &slingAgent, profile.Agent},
+			{"tier", &slingTier, profile.Tier},
+			{"sandbox", &slingSandbox, profile.Sandbox},
+			{"runtime", &slingRuntime, profile.Runtime},
+			{"account", &slingAccount, profile.Account},
+			{"branch", &slingBranch, profile.Branch},
+		} {
+			if f.value != "" && !cmd.Flags().Changed(f.flag) {
+				*f.dst = f.value
+			}
+		}
+
+		if profile.Rig != "" && len(args) == 1 {
+			args = append(args, profile.Rig)
+		}
+		if profile.Molecule != "" && slingOnTarget == "" {
+			if len(args) > 2 {
+				return nil, fmt.Errorf("spawn profile %q applies molecule %s, which batch slinging doesn't support; sling the beads one at a time", slingProfile, profile.Molecule)
+			}
+			// gt sling <bead> --profile p  =>  gt sling <molecule> --on <bead>
+			slingOnTarget = args[0]
+			args = append([]string{profile.Molecule}, args[1:]...)
+		}
+	}
+
+	if slingTier != "" {
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if err != nil {
+			return nil, fmt.Errorf("loading town settings: %w", err)
+		}
+		tier, err := models.Find(settings.Models, slingTier)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tier: %w", err)
+		}
+		slingTier = tier.Name
+	}
+	return args, nil
+}
+
+// withModelTier adds the tier's model to a polecat's startup command. The
+// agent must speak the tier's provider (claude for Anthropic, and so on);
+// otherwise it warns and leaves the command.
+func withModelTier(townRoot, rigPath, agent, command, tierName string) string {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		style.PrintWarning("--tier %s: loading town settings: %v; ignoring it", tierName, err)
+		return command
+	}
+	tier, err := models.Find(settings.Models, tierName)
+	if err != nil {
+		style.PrintWarning("--tier %s: %v; ignoring it", tierName, err)
+		return command
+	}
+	rc, _, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, agent)
+	if err != nil || rc == nil {
+		style.PrintWarning("--tier %s: could not resolve the agent; ignoring it", tierName)
+		return command
+	}
+	model, ok := tier.ModelArg(filepath.Base(rc.Command))
+	if !ok {
+		style.PrintWarning("--tier %s runs on %s, which the %s agent can't use (try --agent %s); ignoring it",
+			tierName, tier.Provider, filepath.Base(rc.Command), strings.Join(models.AgentCommands[tier.Provider], "/"))
+		return command
+	}
+	return command + " --model " + model
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestApplySpawnProfile(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.SpawnProfiles = map[string]*config.SpawnProfile{
+		"writer-fixes": {
+			Rig:      "docs",
+			Agent:    "claude",
+			Tier:     "Haiku",
+			Molecule: "mol-polish",
+			Sandbox:  "off",
+			Branch:   "fix/{bead}",
+		},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func() *cobra.Command {
+		c := &cobra.Command{}
+		c.Flags().StringVar(&slingAgent, "agent", "", "")
+		c.Flags().StringVar(&slingTier, "tier", "", "")
+		c.Flags().StringVar(&slingSandbox, "sandbox", "", "")
+		c.Flags().StringVar(&slingRuntime, "runtime", "", "")
+		c.Flags().StringVar(&slingAccount, "account", "", "")
+		c.Flags().StringVar(&slingBranch, "branch", "", "")
+		return c
+	}
+	t.Cleanup(func() {
+		slingProfile, slingOnTarget = "", ""
+		slingAgent, slingTier, slingSandbox, slingRuntime, slingAccount, slingBranch = "", "", "", "", "", ""
+	})
+
+	// The profile fills unset flags, supplies the rig and applies its molecule
+	c := newCmd()
+	if err := c.Flags().Set("agent", "gemini"); err != nil {
+		t.Fatal(err)
+	}
+	slingProfile = "writer-fixes"
+	args, err := applySpawnProfile(c, townRoot, []string{"gt-abc"})
+	if err != nil {
+		t.Fatalf("applySpawnProfile: %v", err)
+	}
+	if want := []string{"mol-polish", "docs"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+	if slingOnTarget != "gt-abc" {
+		t.Errorf("slingOnTarget = %q, want the bead", slingOnTarget)
+	}
+	if slingAgent != "gemini" {
+		t.Errorf("slingAgent = %q, the --agent flag should win", slingAgent)
+	}
+	if slingTier != "haiku" || slingSandbox != "off" || slingBranch != "fix/{bead}" {
+		t.Errorf("tier, sandbox, branch = %q, %q, %q", slingTier, slingSandbox, slingBranch)
+	}
+
+	// Unknown profiles list the known ones
+	slingProfile, slingOnTarget = "nope", ""
+	_, err = applySpawnProfile(newCmd(), townRoot, []string{"gt-abc"})
+	if err == nil || !strings.Contains(err.Error(), "writer-fixes") {
+		t.Errorf("unknown profile err = %v", err)
+	}
+
+	// Tiers are checked with or without a profile
+	c = newCmd()
+	slingProfile, slingTier = "", "gpt"
+	if _, err := applySpawnProfile(c, townRoot, []string{"gt-abc"}); err == nil {
+		t.Error("expected an error for an invalid tier")
+	}
+}
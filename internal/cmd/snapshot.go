@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/snapshot"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	snapshotOutput string
+	snapshotLines  int
+	snapshotEvents int
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:     "snapshot",
+	GroupID: GroupDiag,
+	Short:   "Capture the town's state into a tarball for a bug report",
+	Long: `Capture what's needed to debug the town, as one gzipped tarball to
+attach to a bug report:
+
+  manifest.json     When and where it was taken, and anything not captured
+  version.json      gt and bd versions (gt version --json)
+  status.json       The town's status (gt status --json)
+  doctor.txt        gt doctor results
+  events.jsonl      The last --events lines of the event log
+  config/           Town and rig config: mayor/*.json, settings/*.json,
+                    <rig>/config.json, <rig>/settings/*.json
+  transcripts/      The last --lines lines of each polecat's transcript
+
+Everything is read first and written once, so the files describe the
+same moment. Secrets are redacted from config (tokens, passwords,
+webhook URLs, credentials in git URLs); transcripts and events are
+captured as they are, so look over the tarball before posting it
+publicly.
+
+Examples:
+  gt snapshot
+  gt snapshot -o /tmp/town.tar.gz --lines 500`,
+	Args: cobra.NoArgs,
+	RunE: runSnapshot,
+}
+
+func init() {
+	snapshotCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "", "Tarball to write (default gt-snapshot-<time>.tar.gz)")
+	snapshotCmd.Flags().IntVar(&snapshotLines, "lines", 200, "Transcript lines per polecat")
+	snapshotCmd.Flags().IntVar(&snapshotEvents, "events", 1000, "Event log lines")
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+// SnapshotManifest describes a snapshot.
+type SnapshotManifest struct {
+	Created  time.Time `json:"created"`
+	TownRoot string    `json:"town_root"`
+	Version  string    `json:"gt_version"`
+	Files    []string  `json:"files"`
+	Missing  []string  `json:"missing,omitempty"` // What couldn't be captured, and why
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	if snapshotLines < 0 || snapshotEvents < 0 {
+		return fmt.Errorf("--lines and --events can't be negative")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	now := time.Now()
+	name := "gt-snapshot-" + now.Format("20060102-150405")
+	out := snapshotOutput
+	if out == "" {
+		out = name + ".tar.gz"
+	}
+
+	bundle, manifest := captureSnapshot(townRoot, now)
+	for _, f := range bundle.Files {
+		manifest.Files = append(manifest.Files, f.Name)
+	}
+	if err := bundle.AddJSON("manifest.json", manifest); err != nil {
+		return err
+	}
+
+	f, err := os.Create(out) //nolint:gosec // G304: path is the user's -o
+	if err != nil {
+		return fmt.Errorf("creating snapshot: %w", err)
+	}
+	if err := bundle.Write(f, name); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+
+	for _, m := range manifest.Missing {
+		style.PrintWarning("not captured: %s", m)
+	}
+	size := ""
+	if info, err := os.Stat(out); err == nil {
+		size = fmt.Sprintf(", %.1f KB", float64(info.Size())/1024)
+	}
+	fmt.Printf("%s Wrote %s %s\n", style.Bold.Render("✓"), out,
+		style.Dim.Render(fmt.Sprintf("(%d files%s)", len(bundle.Files), size)))
+	fmt.Println("  Transcripts and events are not redacted; review before sharing publicly.")
+	return nil
+}
+
+// captureSnapshot reads everything a snapshot holds into memory. What
+// can't be read is noted in the manifest rather than failing the snapshot:
+// a broken town is when one is needed most.
+func captureSnapshot(townRoot string, now time.Time) (*snapshot.Bundle, *SnapshotManifest) {
+	bundle := snapshot.New(now)
+	manifest := &SnapshotManifest{Created: now.UTC(), TownRoot: townRoot, Version: Version}
+	missing := func(what string, err error) {
+		manifest.Missing = append(manifest.Missing, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	if err := bundle.AddJSON("version.json", collectVersionInfo()); err != nil {
+		missing("version.json", err)
+	}
+
+	status := collectTownStatus(townRoot)
+	if err := bundle.AddJSON("status.json", status); err != nil {
+		missing("status.json", err)
+	}
+
+	var report bytes.Buffer
+	newTownDoctor("").Run(&doctor.CheckContext{TownRoot: townRoot, Verbose: true}).Print(&report, true)
+	bundle.Add("doctor.txt", []byte(transcript.Clean(report.String())))
+
+	if data, err := os.ReadFile(filepath.Join(townRoot, events.EventsFile)); err == nil {
+		bundle.Add("events.jsonl", snapshot.Tail(data, snapshotEvents))
+	} else if !os.IsNotExist(err) {
+		missing("events.jsonl", err)
+	}
+
+	for _, rel := range snapshotConfigFiles(townRoot) {
+		data, err := os.ReadFile(filepath.Join(townRoot, rel)) //nolint:gosec // G304: path is within the town
+		if err == nil {
+			data, err = snapshot.RedactJSON(data)
+		}
+		if err != nil {
+			missing(rel, err)
+			continue
+		}
+		bundle.Add("config/"+filepath.ToSlash(rel), data)
+	}
+
+	for _, r := range status.Rigs {
+		for _, a := range r.Agents {
+			if a.Role != "polecat" {
+				continue
+			}
+			e, err := transcript.Find(townRoot, r.Name+"/"+a.Name)
+			if err != nil {
+				continue // Never recorded one
+			}
+			data, err := os.ReadFile(e.Path)
+			if err != nil {
+				missing("transcript of "+e.Agent(), err)
+				continue
+			}
+			bundle.Add("transcripts/"+r.Name+"/"+a.Name+".log", []byte(transcript.Clean(string(snapshot.Tail(data, snapshotLines)))))
+		}
+	}
+	return bundle, manifest
+}
+
+// snapshotConfigFiles returns the town's and rigs' config files, relative
+// to the town root.
+func snapshotConfigFiles(townRoot string) []string {
+	patterns := []string{
+		filepath.Join(constants.DirMayor, "*.json"),
+		filepath.Join(constants.DirSettings, "*.json"),
+	}
+	if rigs, err := discoverAllRigs(townRoot); err == nil {
+		for _, r := range rigs {
+			rel, err := filepath.Rel(townRoot, r.Path)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			patterns = append(patterns, filepath.Join(rel, "config.json"), filepath.Join(rel, constants.DirSettings, "*.json"))
+		}
+	}
+	var files []string
+	for _, p := range patterns {
+		matches, _ := filepath.Glob(filepath.Join(townRoot, p))
+		for _, m := range matches {
+			if rel, err := filepath.Rel(townRoot, m); err == nil {
+				files = append(files, rel)
+			}
+		}
+	}
+	return files
+}
@@ -6,12 +6,12 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/version"
 )
 
 var staleJSON bool
-var staleQuiet bool
 
 var staleCmd = &cobra.Command{
 	Use:     "stale",
@@ -36,7 +36,6 @@ Exit codes:
 
 func init() {
 	staleCmd.Flags().BoolVar(&staleJSON, "json", false, "Output as JSON")
-	staleCmd.Flags().BoolVarP(&staleQuiet, "quiet", "q", false, "Exit code only (0=stale, 1=fresh)")
 	rootCmd.AddCommand(staleCmd)
 }
 
@@ -53,7 +52,7 @@ func runStale(cmd *cobra.Command, args []string) error {
 	// Find the gastown repo
 	repoRoot, err := version.GetRepoRoot()
 	if err != nil {
-		if staleQuiet {
+		if output.IsQuiet() {
 			os.Exit(2)
 		}
 		if staleJSON {
@@ -67,7 +66,7 @@ func runStale(cmd *cobra.Command, args []string) error {
 
 	// Handle errors
 	if info.Error != nil {
-		if staleQuiet {
+		if output.IsQuiet() {
 			os.Exit(2)
 		}
 		if staleJSON {
@@ -77,7 +76,7 @@ func runStale(cmd *cobra.Command, args []string) error {
 	}
 
 	// Quiet mode: just exit with appropriate code
-	if staleQuiet {
+	if output.IsQuiet() {
 		if info.IsStale {
 			os.Exit(0)
 		}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/stats"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	statsWeeks int
+	statsSince string
+	statsRig   string
+	statsJSON  bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:     "stats",
+	GroupID: GroupDiag,
+	Short:   "Show throughput and cycle-time analytics",
+	Long: `Show what the town has produced: beads closed per week, median cycle
+time (bead created to closed, and per molecule formula from its run
+history), molecule run outcomes, and the refineries' merge success rate,
+with a breakdown per rig.
+
+Bookkeeping beads (agents, mail, merge requests, molecules and their steps)
+don't count as work. Merge attempts come from the merged and merge_failed
+events in ~/gt/.events.jsonl; molecule runs from 'gt mol runs'.
+
+The window is the last --weeks calendar weeks (Monday to Sunday), or
+everything from --since (a date or an age such as 30d) until now.
+
+Examples:
+  gt stats
+  gt stats --weeks 12 --rig greenplace
+  gt stats --since 2026-01-01 --json`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().IntVar(&statsWeeks, "weeks", 4, "Number of calendar weeks to report, this one included")
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "Report from this date or age instead (e.g. 2026-01-01, 30d)")
+	statsCmd.Flags().StringVar(&statsRig, "rig", "", "Only this rig (\"town\" for town-level beads)")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	now := time.Now()
+	opts := stats.Options{Until: now, Rig: statsRig}
+	if statsSince != "" {
+		if opts.Since, err = parseSearchTime(statsSince); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	} else {
+		if statsWeeks < 1 {
+			return fmt.Errorf("--weeks must be at least 1")
+		}
+		opts.Since = stats.WeekStart(now).AddDate(0, 0, -7*(statsWeeks-1))
+	}
+
+	var in stats.Input
+	if statsRig == "" || statsRig == "town" {
+		in.Beads = append(in.Beads, closedWork("", beads.GetTownBeadsPath(townRoot))...)
+	}
+	if statsRig != "town" {
+		rigs, err := discoverAllRigs(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing rigs: %w", err)
+		}
+		found := statsRig == ""
+		for _, r := range rigs {
+			if statsRig == "" || r.Name == statsRig {
+				found = true
+				in.Beads = append(in.Beads, closedWork(r.Name, r.BeadsPath())...)
+			}
+		}
+		if !found {
+			return fmt.Errorf("rig '%s' not found", statsRig)
+		}
+	}
+	if in.Runs, err = molruns.List(townRoot); err != nil {
+		style.PrintWarning("could not read molecule runs: %v", err)
+	}
+	if in.Merges, err = stats.LoadMerges(townRoot); err != nil {
+		style.PrintWarning("could not read merge events: %v", err)
+	}
+
+	rep := stats.Compute(in, opts)
+	if statsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	}
+	printStats(rep)
+	return nil
+}
+
+// closedWork lists the closed work beads of one beads database. A database
+// that can't be read only warns.
+func closedWork(rigName, beadsPath string) []stats.Bead {
+	issues, err := beads.New(beadsPath).List(beads.ListOptions{Status: "closed", Priority: -1})
+	if err != nil {
+		where := rigName
+		if where == "" {
+			where = "town"
+		}
+		style.PrintWarning("could not list %s beads: %v", where, err)
+		return nil
+	}
+	return stats.ClosedWork(rigName, issues)
+}
+
+func printStats(rep *stats.Report) {
+	fmt.Printf("%s Stats %s to %s\n\n", style.Bold.Render("📊"),
+		rep.Since.Local().Format("2006-01-02"), rep.Until.Local().Format("2006-01-02"))
+
+	fmt.Printf("  Beads closed: %d", rep.Closed)
+	if rep.MedianCycle > 0 {
+		fmt.Printf("   median cycle time: %s", formatDuration(time.Duration(rep.MedianCycle)))
+	}
+	fmt.Println()
+	fmt.Printf("  Merges: %s\n\n", formatMerges(rep.Merges))
+
+	if len(rep.Weeks) > 1 {
+		peak := 1
+		for _, w := range rep.Weeks {
+			peak = max(peak, w.Closed)
+		}
+		fmt.Printf("  %s\n", style.Bold.Render("Closed per week"))
+		for _, w := range rep.Weeks {
+			bar := strings.Repeat("█", (w.Closed*30+peak-1)/peak)
+			fmt.Println(strings.TrimRight(fmt.Sprintf("    %s  %4d  %s", w.Start.Format("2006-01-02"), w.Closed, bar), " "))
+		}
+		fmt.Println()
+	}
+
+	if len(rep.Molecules) > 0 {
+		fmt.Printf("  %s\n", style.Bold.Render("Molecules"))
+		fmt.Printf("    %-28s %6s %9s %7s %7s %14s\n", "FORMULA", "RUNS", "COMPLETE", "FAILED", "BURNED", "MEDIAN CYCLE")
+		for _, m := range rep.Molecules {
+			cycle := "-"
+			if m.MedianCycle > 0 {
+				cycle = formatDuration(time.Duration(m.MedianCycle))
+			}
+			fmt.Printf("    %-28s %6d %9d %7d %7d %14s\n", m.Formula, m.Runs, m.Complete, m.Failed, m.Burned, cycle)
+		}
+		fmt.Println()
+	}
+
+	if len(rep.Rigs) > 0 {
+		fmt.Printf("  %s\n", style.Bold.Render("Rigs"))
+		fmt.Printf("    %-20s %7s %14s %6s  %s\n", "RIG", "CLOSED", "MEDIAN CYCLE", "RUNS", "MERGES")
+		for _, r := range rep.Rigs {
+			cycle := "-"
+			if r.MedianCycle > 0 {
+				cycle = formatDuration(time.Duration(r.MedianCycle))
+			}
+			fmt.Printf("    %-20s %7d %14s %6d  %s\n", r.Name, r.Closed, cycle, r.Runs, formatMerges(r.Merges))
+		}
+	}
+}
+
+// formatMerges shows merge attempts and the success rate.
+func formatMerges(m stats.Merges) string {
+	if m.Merged+m.Failed == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%d merged, %d failed (%.0f%% success)", m.Merged, m.Failed, m.SuccessRate*100)
+}
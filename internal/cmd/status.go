@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
@@ -15,12 +16,13 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
-	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/ui"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"golang.org/x/term"
 )
@@ -29,7 +31,9 @@ var statusJSON bool
 var statusFast bool
 var statusWatch bool
 var statusInterval int
-var statusVerbose bool
+var statusNoCache bool
+var statusJobs int
+var statusRigTimeout time.Duration
 
 var statusCmd = &cobra.Command{
 	Use:     "status",
@@ -41,7 +45,15 @@ var statusCmd = &cobra.Command{
 Shows town name, registered rigs, active polecats, and witness status.
 
 Use --fast to skip mail lookups for faster execution.
-Use --watch to continuously refresh status at regular intervals.`,
+Use --watch to continuously refresh status at regular intervals.
+
+Beads queries are cached for a few seconds, so repeated calls don't each
+query every rig's database; any change to a database drops its cached
+results. Use --no-cache to query bd directly.
+
+Rigs are gathered in parallel, --jobs at a time. A rig that doesn't answer
+within --rig-timeout (an unreachable remote, a hung bd) is shown as timed
+out rather than holding up the rest.`,
 	RunE: runStatus,
 }
 
@@ -50,7 +62,9 @@ func init() {
 	statusCmd.Flags().BoolVar(&statusFast, "fast", false, "Skip mail lookups for faster execution")
 	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Watch mode: refresh status continuously")
 	statusCmd.Flags().IntVarP(&statusInterval, "interval", "n", 2, "Refresh interval in seconds")
-	statusCmd.Flags().BoolVarP(&statusVerbose, "verbose", "v", false, "Show detailed multi-line output per agent")
+	statusCmd.Flags().BoolVar(&statusNoCache, "no-cache", false, "Query bd directly instead of using cached results")
+	statusCmd.Flags().IntVar(&statusJobs, "jobs", 8, "Rigs to gather at once")
+	statusCmd.Flags().DurationVar(&statusRigTimeout, "rig-timeout", 10*time.Second, "Give up on a rig that takes longer than this (0: wait)")
 	rootCmd.AddCommand(statusCmd)
 }
 
@@ -91,6 +105,7 @@ type AgentRuntime struct {
 // RigStatus represents status of a single rig.
 type RigStatus struct {
 	Name         string          `json:"name"`
+	Remote       string          `json:"remote,omitempty"` // [user@]host:/path of a remote rig
 	Polecats     []string        `json:"polecats"`
 	PolecatCount int             `json:"polecat_count"`
 	Crews        []string        `json:"crews"`
@@ -99,7 +114,9 @@ type RigStatus struct {
 	HasRefinery  bool            `json:"has_refinery"`
 	Hooks        []AgentHookInfo `json:"hooks,omitempty"`
 	Agents       []AgentRuntime  `json:"agents,omitempty"` // Runtime state of all agents in rig
+	CrewHoldings []CrewHoldings  `json:"crew_holdings,omitempty"` // Beads and branches per crew member
 	MQ           *MQSummary      `json:"mq,omitempty"`     // Merge queue summary
+	Error        string          `json:"error,omitempty"`  // Why the rig's status is missing
 }
 
 // MQSummary represents the merge queue status for a rig.
@@ -131,6 +148,9 @@ type StatusSum struct {
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
+	if !statusNoCache {
+		beads.SetCacheTTL(beads.DefaultCacheTTL)
+	}
 	if statusWatch {
 		return runStatusWatch(cmd, args)
 	}
@@ -193,6 +213,33 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	// This is non-blocking - if daemons can't be started, we show a warning but continue
 	bdWarning := beads.EnsureBdDaemonHealth(townRoot)
 
+	status := collectTownStatus(townRoot)
+
+	// Output
+	if statusJSON {
+		return outputStatusJSON(status)
+	}
+	if err := outputStatusText(status); err != nil {
+		return err
+	}
+
+	if !beads.Installed() {
+		fmt.Printf("%s %s\n", style.Warning.Render("⚠"), "bd is not installed: hooks, mail and merge queues aren't shown")
+		fmt.Printf("  Install it: %s\n", beads.InstallCommand)
+	}
+
+	// Show bd daemon warning at the end if there were issues
+	if bdWarning != "" {
+		fmt.Printf("%s %s\n", style.Warning.Render("⚠"), bdWarning)
+		fmt.Printf("  Run 'bd daemon killall && bd daemon --start' to restart daemons\n")
+	}
+
+	return nil
+}
+
+// collectTownStatus gathers the status of the town's agents and rigs. It is
+// the aggregation behind gt status, and what gt top refreshes from.
+func collectTownStatus(townRoot string) TownStatus {
 	// Load town config
 	townConfigPath := constants.MayorTownPath(townRoot)
 	townConfig, err := config.LoadTownConfig(townConfigPath)
@@ -224,13 +271,15 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 		}
 	}
 
-	// Discover rigs
-	rigs, err := mgr.DiscoverRigs()
-	if err != nil {
-		return fmt.Errorf("discovering rigs: %w", err)
+	// Rigs are loaded in the parallel pass below: loading a remote rig
+	// reaches its machine, which may not answer.
+	rigNames := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		rigNames = append(rigNames, name)
 	}
+	sort.Strings(rigNames)
 
-	// Pre-fetch agent beads across all rig-specific beads DBs.
+	// Pre-fetch town agent beads; each rig's are fetched with the rig.
 	allAgentBeads := make(map[string]*beads.Issue)
 	allHookBeads := make(map[string]*beads.Issue)
 
@@ -243,62 +292,13 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	}
 
 	// Fetch hook beads from town beads
-	var townHookIDs []string
-	for _, issue := range townAgentBeads {
-		hookID := issue.HookBead
-		if hookID == "" {
-			fields := beads.ParseAgentFields(issue.Description)
-			if fields != nil {
-				hookID = fields.HookBead
-			}
-		}
-		if hookID != "" {
-			townHookIDs = append(townHookIDs, hookID)
-		}
-	}
-	if len(townHookIDs) > 0 {
+	if townHookIDs := agentHookIDs(townAgentBeads); len(townHookIDs) > 0 {
 		townHookBeads, _ := townBeadsClient.ShowMultiple(townHookIDs)
 		for id, issue := range townHookBeads {
 			allHookBeads[id] = issue
 		}
 	}
 
-	// Fetch rig-level agent beads
-	for _, r := range rigs {
-		rigBeadsPath := filepath.Join(r.Path, "mayor", "rig")
-		rigBeads := beads.New(rigBeadsPath)
-		rigAgentBeads, _ := rigBeads.ListAgentBeads()
-		if rigAgentBeads == nil {
-			continue
-		}
-		for id, issue := range rigAgentBeads {
-			allAgentBeads[id] = issue
-		}
-
-		var hookIDs []string
-		for _, issue := range rigAgentBeads {
-			// Use the HookBead field from the database column; fall back for legacy beads.
-			hookID := issue.HookBead
-			if hookID == "" {
-				fields := beads.ParseAgentFields(issue.Description)
-				if fields != nil {
-					hookID = fields.HookBead
-				}
-			}
-			if hookID != "" {
-				hookIDs = append(hookIDs, hookID)
-			}
-		}
-
-		if len(hookIDs) == 0 {
-			continue
-		}
-		hookBeads, _ := rigBeads.ShowMultiple(hookIDs)
-		for id, issue := range hookBeads {
-			allHookBeads[id] = issue
-		}
-	}
-
 	// Create mail router for inbox lookups
 	mailRouter := mail.NewRouter(townRoot)
 
@@ -323,7 +323,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 		Name:     townConfig.Name,
 		Location: townRoot,
 		Overseer: overseerInfo,
-		Rigs:     make([]RigStatus, len(rigs)),
+		Rigs:     []RigStatus{},
 	}
 
 	var wg sync.WaitGroup
@@ -335,58 +335,37 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 		status.Agents = discoverGlobalAgents(allSessions, allAgentBeads, allHookBeads, mailRouter, statusFast)
 	}()
 
-	// Process all rigs in parallel
-	rigActiveHooks := make([]int, len(rigs)) // Track hooks per rig for thread safety
-	for i, r := range rigs {
-		wg.Add(1)
-		go func(idx int, r *rig.Rig) {
-			defer wg.Done()
-
-			rs := RigStatus{
-				Name:         r.Name,
-				Polecats:     r.Polecats,
-				PolecatCount: len(r.Polecats),
-				HasWitness:   r.HasWitness,
-				HasRefinery:  r.HasRefinery,
-			}
-
-			// Count crew workers
-			crewGit := git.NewGit(r.Path)
-			crewMgr := crew.NewManager(r, crewGit)
-			if workers, err := crewMgr.List(); err == nil {
-				for _, w := range workers {
-					rs.Crews = append(rs.Crews, w.Name)
-				}
-				rs.CrewCount = len(workers)
-			}
-
-			// Discover hooks for all agents in this rig
-			rs.Hooks = discoverRigHooks(r, rs.Crews)
-			activeHooks := 0
-			for _, hook := range rs.Hooks {
-				if hook.HasWork {
-					activeHooks++
-				}
-			}
-			rigActiveHooks[idx] = activeHooks
-
-			// Discover runtime state for all agents in this rig
-			rs.Agents = discoverRigAgents(allSessions, r, rs.Crews, allAgentBeads, allHookBeads, mailRouter, statusFast)
-
-			// Get MQ summary if rig has a refinery
-			rs.MQ = getMQSummary(r)
-
-			status.Rigs[idx] = rs
-		}(i, r)
-	}
+	// Process rigs in parallel, at most --jobs at a time. A rig that takes
+	// longer than --rig-timeout is shown as timed out instead of holding up
+	// the rest.
+	results, finished := gatherParallel(len(rigNames), statusJobs, statusRigTimeout, func(idx int) rigStatusResult {
+		r, err := mgr.GetRig(rigNames[idx])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load rig %q: %v\n", rigNames[idx], err)
+			return rigStatusResult{}
+		}
+		return gatherRigStatus(r, allSessions, allAgentBeads, allHookBeads, mailRouter)
+	})
 
 	wg.Wait()
 
 	// Aggregate summary (after parallel work completes)
-	for i, rs := range status.Rigs {
+	for i, res := range results {
+		if !finished[i] {
+			res = rigStatusResult{loaded: true, status: RigStatus{
+				Name:   rigNames[i],
+				Remote: rigsConfig.Rigs[rigNames[i]].Remote,
+				Error:  fmt.Sprintf("timed out after %s", statusRigTimeout),
+			}}
+		}
+		if !res.loaded {
+			continue
+		}
+		rs := res.status
+		status.Rigs = append(status.Rigs, rs)
 		status.Summary.PolecatCount += rs.PolecatCount
 		status.Summary.CrewCount += rs.CrewCount
-		status.Summary.ActiveHooks += rigActiveHooks[i]
+		status.Summary.ActiveHooks += res.activeHooks
 		if rs.HasWitness {
 			status.Summary.WitnessCount++
 		}
@@ -394,23 +373,8 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 			status.Summary.RefineryCount++
 		}
 	}
-	status.Summary.RigCount = len(rigs)
-
-	// Output
-	if statusJSON {
-		return outputStatusJSON(status)
-	}
-	if err := outputStatusText(status); err != nil {
-		return err
-	}
-
-	// Show bd daemon warning at the end if there were issues
-	if bdWarning != "" {
-		fmt.Printf("%s %s\n", style.Warning.Render("⚠"), bdWarning)
-		fmt.Printf("  Run 'bd daemon killall && bd daemon --start' to restart daemons\n")
-	}
-
-	return nil
+	status.Summary.RigCount = len(status.Rigs)
+	return status
 }
 
 func outputStatusJSON(status TownStatus) error {
@@ -432,24 +396,25 @@ func outputStatusText(status TownStatus) error {
 		} else if status.Overseer.Username != "" && status.Overseer.Username != status.Overseer.Name {
 			overseerDisplay = fmt.Sprintf("%s (@%s)", status.Overseer.Name, status.Overseer.Username)
 		}
-		fmt.Printf("👤 %s %s\n", style.Bold.Render("Overseer:"), overseerDisplay)
+		fmt.Printf("%s %s %s\n", ui.Emoji("👤", "*"), style.Bold.Render("Overseer:"), overseerDisplay)
 		if status.Overseer.UnreadMail > 0 {
-			fmt.Printf("   📬 %d unread\n", status.Overseer.UnreadMail)
+			fmt.Printf("   %s %d unread\n", mailGlyph(), status.Overseer.UnreadMail)
 		}
 		fmt.Println()
 	}
 
-	// Role icons - uses centralized emojis from constants package
+	// Role icons - uses centralized emojis from constants package,
+	// replaced with short text tags when emoji are disabled (GT_GLYPHS)
 	roleIcons := map[string]string{
-		constants.RoleMayor:    constants.EmojiMayor,
-		constants.RoleDeacon:   constants.EmojiDeacon,
-		constants.RoleWitness:  constants.EmojiWitness,
-		constants.RoleRefinery: constants.EmojiRefinery,
-		constants.RoleCrew:     constants.EmojiCrew,
-		constants.RolePolecat:  constants.EmojiPolecat,
+		constants.RoleMayor:    ui.Emoji(constants.EmojiMayor, "[M]"),
+		constants.RoleDeacon:   ui.Emoji(constants.EmojiDeacon, "[D]"),
+		constants.RoleWitness:  ui.Emoji(constants.EmojiWitness, "[W]"),
+		constants.RoleRefinery: ui.Emoji(constants.EmojiRefinery, "[R]"),
+		constants.RoleCrew:     ui.Emoji(constants.EmojiCrew, "[C]"),
+		constants.RolePolecat:  ui.Emoji(constants.EmojiPolecat, "[P]"),
 		// Legacy names for backwards compatibility
-		"coordinator":  constants.EmojiMayor,
-		"health-check": constants.EmojiDeacon,
+		"coordinator":  ui.Emoji(constants.EmojiMayor, "[M]"),
+		"health-check": ui.Emoji(constants.EmojiDeacon, "[D]"),
 	}
 
 	// Global Agents (Mayor, Deacon)
@@ -458,7 +423,7 @@ func outputStatusText(status TownStatus) error {
 		if icon == "" {
 			icon = roleIcons[agent.Name]
 		}
-		if statusVerbose {
+		if output.IsVerbose() {
 			fmt.Printf("%s %s\n", icon, style.Bold.Render(capitalizeFirst(agent.Name)))
 			renderAgentDetails(agent, "   ", nil, status.Location)
 			fmt.Println()
@@ -467,7 +432,7 @@ func outputStatusText(status TownStatus) error {
 			renderAgentCompact(agent, icon+" ", nil, status.Location)
 		}
 	}
-	if !statusVerbose && len(status.Agents) > 0 {
+	if !output.IsVerbose() && len(status.Agents) > 0 {
 		fmt.Println()
 	}
 
@@ -479,7 +444,15 @@ func outputStatusText(status TownStatus) error {
 	// Rigs
 	for _, r := range status.Rigs {
 		// Rig header with separator
-		fmt.Printf("─── %s ───────────────────────────────────────────\n\n", style.Bold.Render(r.Name+"/"))
+		rule := ui.Glyph("───", "---")
+		fmt.Printf("%s %s %s\n\n", rule, style.Bold.Render(r.Name+"/"), ui.Glyph("───────────────────────────────────────────", "-------------------------------------------"))
+		if r.Remote != "" {
+			fmt.Printf("  %s\n\n", style.Dim.Render("remote: "+r.Remote))
+		}
+		if r.Error != "" {
+			fmt.Printf("   %s %s\n\n", style.Warning.Render("⚠"), r.Error)
+			continue
+		}
 
 		// Group agents by role
 		var witnesses, refineries, crews, polecats []AgentRuntime
@@ -498,7 +471,7 @@ func outputStatusText(status TownStatus) error {
 
 		// Witness
 		if len(witnesses) > 0 {
-			if statusVerbose {
+			if output.IsVerbose() {
 				fmt.Printf("%s %s\n", roleIcons["witness"], style.Bold.Render("Witness"))
 				for _, agent := range witnesses {
 					renderAgentDetails(agent, "   ", r.Hooks, status.Location)
@@ -513,7 +486,7 @@ func outputStatusText(status TownStatus) error {
 
 		// Refinery
 		if len(refineries) > 0 {
-			if statusVerbose {
+			if output.IsVerbose() {
 				fmt.Printf("%s %s\n", roleIcons["refinery"], style.Bold.Render("Refinery"))
 				for _, agent := range refineries {
 					renderAgentDetails(agent, "   ", r.Hooks, status.Location)
@@ -543,7 +516,7 @@ func outputStatusText(status TownStatus) error {
 
 		// Crew
 		if len(crews) > 0 {
-			if statusVerbose {
+			if output.IsVerbose() {
 				fmt.Printf("%s %s (%d)\n", roleIcons["crew"], style.Bold.Render("Crew"), len(crews))
 				for _, agent := range crews {
 					renderAgentDetails(agent, "   ", r.Hooks, status.Location)
@@ -556,10 +529,11 @@ func outputStatusText(status TownStatus) error {
 				}
 			}
 		}
+		renderCrewHoldings(r.CrewHoldings)
 
 		// Polecats
 		if len(polecats) > 0 {
-			if statusVerbose {
+			if output.IsVerbose() {
 				fmt.Printf("%s %s (%d)\n", roleIcons["polecat"], style.Bold.Render("Polecats"), len(polecats))
 				for _, agent := range polecats {
 					renderAgentDetails(agent, "   ", r.Hooks, status.Location)
@@ -674,9 +648,9 @@ func renderAgentDetails(agent AgentRuntime, indent string, hooks []AgentHookInfo
 
 	// Line 3: Mail (if any unread)
 	if agent.UnreadMail > 0 {
-		mailStr := fmt.Sprintf("📬 %d unread", agent.UnreadMail)
+		mailStr := fmt.Sprintf("%s %d unread", mailGlyph(), agent.UnreadMail)
 		if agent.FirstSubject != "" {
-			mailStr = fmt.Sprintf("📬 %d unread → %s", agent.UnreadMail, truncateWithEllipsis(agent.FirstSubject, 35))
+			mailStr = fmt.Sprintf("%s %d unread → %s", mailGlyph(), agent.UnreadMail, truncateWithEllipsis(agent.FirstSubject, 35))
 		}
 		fmt.Printf("%s  mail: %s\n", indent, mailStr)
 	}
@@ -701,12 +675,12 @@ func formatMQSummary(mq *MQSummary) string {
 		return ""
 	}
 	// Add state indicator
-	stateIcon := "○" // idle
+	stateIcon := ui.Glyph("○", "o") // idle
 	switch mq.State {
 	case "processing":
-		stateIcon = style.Success.Render("●")
+		stateIcon = style.Success.Render(ui.Glyph("●", "*"))
 	case "blocked":
-		stateIcon = style.Error.Render("○")
+		stateIcon = style.Error.Render(ui.Glyph("○", "o"))
 	}
 	// Add health warning if stale
 	healthSuffix := ""
@@ -766,7 +740,7 @@ func renderAgentCompactWithSuffix(agent AgentRuntime, indent string, hooks []Age
 	// Mail indicator
 	mailSuffix := ""
 	if agent.UnreadMail > 0 {
-		mailSuffix = fmt.Sprintf(" 📬%d", agent.UnreadMail)
+		mailSuffix = fmt.Sprintf(" %s%d", mailGlyph(), agent.UnreadMail)
 	}
 
 	// Print single line: name + status + hook + mail + suffix
@@ -806,7 +780,7 @@ func renderAgentCompact(agent AgentRuntime, indent string, hooks []AgentHookInfo
 	// Mail indicator
 	mailSuffix := ""
 	if agent.UnreadMail > 0 {
-		mailSuffix = fmt.Sprintf(" 📬%d", agent.UnreadMail)
+		mailSuffix = fmt.Sprintf(" %s%d", mailGlyph(), agent.UnreadMail)
 	}
 
 	// Print single line: name + status + hook + mail
@@ -822,9 +796,9 @@ func buildStatusIndicator(agent AgentRuntime) string {
 	// Base indicator from tmux state
 	var indicator string
 	if sessionExists {
-		indicator = style.Success.Render("●")
+		indicator = style.Success.Render(ui.Glyph("●", "+"))
 	} else {
-		indicator = style.Error.Render("○")
+		indicator = style.Error.Render(ui.Glyph("○", "-"))
 	}
 
 	// Add non-observable state suffix if present
@@ -1232,3 +1206,8 @@ func getAgentHook(b *beads.Beads, role, agentAddress, roleType string) AgentHook
 
 	return hook
 }
+
+// mailGlyph returns the unread-mail marker for the active glyph set.
+func mailGlyph() string {
+	return ui.Glyph(ui.Emoji("📬", "✉"), "mail:")
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// gatherParallel calls fn for each of n items, at most jobs at a time, and
+// gives each call timeout to return (0: no limit). It returns the results
+// and which calls finished in time. A call that times out is left running,
+// and its slot freed, so one stuck item can't hold up the others; its
+// result is dropped.
+func gatherParallel[T any](n, jobs int, timeout time.Duration, fn func(i int) T) ([]T, []bool) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	results := make([]T, n)
+	finished := make([]bool, n)
+	slots := make(chan struct{}, jobs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			done := make(chan T, 1)
+			go func() { done <- fn(i) }()
+
+			var expired <-chan time.Time
+			if timeout > 0 {
+				timer := time.NewTimer(timeout)
+				defer timer.Stop()
+				expired = timer.C
+			}
+			select {
+			case results[i] = <-done:
+				finished[i] = true
+			case <-expired:
+			}
+		}(i)
+	}
+	wg.Wait()
+	return results, finished
+}
+
+// rigStatusResult is one rig's part of gt status.
+type rigStatusResult struct {
+	loaded      bool // False when the rig couldn't be loaded
+	status      RigStatus
+	activeHooks int
+}
+
+// gatherRigStatus collects a rig's git, beads and session state.
+// townAgentBeads and townHookBeads are the town-level beads, shared by
+// every rig; they are only read.
+func gatherRigStatus(r *rig.Rig, allSessions map[string]bool, townAgentBeads, townHookBeads map[string]*beads.Issue, mailRouter *mail.Router) rigStatusResult {
+	rs := RigStatus{
+		Name:         r.Name,
+		Polecats:     r.Polecats,
+		PolecatCount: len(r.Polecats),
+		HasWitness:   r.HasWitness,
+		HasRefinery:  r.HasRefinery,
+		Remote:       r.Remote,
+	}
+	rigBeads := beads.New(filepath.Join(r.Path, "mayor", "rig"))
+
+	// Count crew workers
+	crewMgr := crew.NewManager(r, git.NewGit(r.Path))
+	if r.Remote != "" {
+		// The remote's crew directories, as listed by the rig
+		rs.Crews = r.Crew
+		rs.CrewCount = len(r.Crew)
+	} else if workers, err := crewMgr.List(); err == nil {
+		for _, w := range workers {
+			rs.Crews = append(rs.Crews, w.Name)
+		}
+		rs.CrewCount = len(workers)
+		if !statusFast && len(workers) > 0 {
+			rs.CrewHoldings = crewHoldings(r, crewMgr, workers, rigBeads)
+		}
+	}
+
+	// Discover hooks for all agents in this rig
+	rs.Hooks = discoverRigHooks(r, rs.Crews)
+	activeHooks := 0
+	for _, hook := range rs.Hooks {
+		if hook.HasWork {
+			activeHooks++
+		}
+	}
+
+	// The rig's agent beads and what they have hooked, alongside the town's
+	agentBeads := make(map[string]*beads.Issue, len(townAgentBeads))
+	hookBeads := make(map[string]*beads.Issue, len(townHookBeads))
+	for id, issue := range townAgentBeads {
+		agentBeads[id] = issue
+	}
+	for id, issue := range townHookBeads {
+		hookBeads[id] = issue
+	}
+	rigAgentBeads, _ := rigBeads.ListAgentBeads()
+	for id, issue := range rigAgentBeads {
+		agentBeads[id] = issue
+	}
+	if hookIDs := agentHookIDs(rigAgentBeads); len(hookIDs) > 0 {
+		rigHookBeads, _ := rigBeads.ShowMultiple(hookIDs)
+		for id, issue := range rigHookBeads {
+			hookBeads[id] = issue
+		}
+	}
+
+	// Discover runtime state for all agents in this rig
+	rs.Agents = discoverRigAgents(allSessions, r, rs.Crews, agentBeads, hookBeads, mailRouter, statusFast)
+
+	// Get MQ summary if rig has a refinery
+	rs.MQ = getMQSummary(r)
+
+	return rigStatusResult{loaded: true, status: rs, activeHooks: activeHooks}
+}
+
+// agentHookIDs returns the beads hooked by agent beads.
+func agentHookIDs(agentBeads map[string]*beads.Issue) []string {
+	var hookIDs []string
+	for _, issue := range agentBeads {
+		// Use the HookBead field from the database column; fall back for legacy beads.
+		hookID := issue.HookBead
+		if hookID == "" {
+			if fields := beads.ParseAgentFields(issue.Description); fields != nil {
+				hookID = fields.HookBead
+			}
+		}
+		if hookID != "" {
+			hookIDs = append(hookIDs, hookID)
+		}
+	}
+	return hookIDs
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGatherParallel(t *testing.T) {
+	var running, peak atomic.Int32
+	block := make(chan struct{})
+	defer close(block)
+
+	results, finished := gatherParallel(6, 2, 200*time.Millisecond, func(i int) int {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		if i == 3 {
+			<-block // A rig that never answers
+		}
+		time.Sleep(10 * time.Millisecond)
+		return i * 10
+	})
+
+	for i := range results {
+		if i == 3 {
+			if finished[i] {
+				t.Error("the stuck call should have timed out")
+			}
+			continue
+		}
+		if !finished[i] || results[i] != i*10 {
+			t.Errorf("call %d: finished=%v result=%d", i, finished[i], results[i])
+		}
+	}
+	// The stuck call keeps running after its slot is freed, so at most
+	// one extra is in flight
+	if p := peak.Load(); p > 3 {
+		t.Errorf("peak concurrency %d, want at most 3", p)
+	}
+}
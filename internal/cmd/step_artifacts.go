@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/artifacts"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// stepInputMax caps how much of one artifact is attached to a step.
+const stepInputMax = 16 * 1024
+
+// missingStepOutputs returns the artifacts a step declares as outputs that
+// it hasn't stored in run yet.
+func missingStepOutputs(townRoot, run string, step *beads.Issue) []string {
+	outputs := beads.StepOutputs(step)
+	if len(outputs) == 0 {
+		return nil
+	}
+	stepName := artifactStepName(step)
+	var missing []string
+	for _, name := range outputs {
+		if _, err := artifacts.Read(townRoot, run, stepName, name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// artifactStepName is the step part of a step's artifact paths: its formula
+// step ID, or its bead ID when it has none.
+func artifactStepName(step *beads.Issue) string {
+	if ref := beads.StepRef(step); ref != "" {
+		return ref
+	}
+	return step.ID
+}
+
+// stepInputsReport renders a step's input artifacts as a section for its
+// description. An input that was never stored is listed as missing.
+func stepInputsReport(townRoot, run string, inputs []string) string {
+	var b strings.Builder
+	b.WriteString("\n\n## Inputs from earlier steps\n")
+	for _, input := range inputs {
+		stepName, name, ok := strings.Cut(input, "/")
+		if !ok {
+			fmt.Fprintf(&b, "\n### %s: not a <step>/<name> artifact\n", input)
+			continue
+		}
+		data, err := artifacts.Read(townRoot, run, stepName, name)
+		switch {
+		case errors.Is(err, artifacts.ErrNotFound):
+			fmt.Fprintf(&b, "\n### %s: not stored\n", input)
+			continue
+		case err != nil:
+			fmt.Fprintf(&b, "\n### %s: unreadable (%v)\n", input, err)
+			continue
+		}
+		text := strings.TrimSpace(string(data))
+		if len(text) > stepInputMax {
+			text = text[:stepInputMax] + "\n... (truncated; read it all with: gt artifacts get " + input + ")"
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n%s\n", input, text)
+	}
+	return b.String()
+}
+
+// attachStepInputs appends a step's input artifacts to its description, so
+// the fresh session working the step starts with them. Failing to attach
+// only warns: the agent can still read them with gt artifacts get.
+func attachStepInputs(worktree, townRoot, run string, step *beads.Issue, inputs []string) {
+	fmt.Printf("\n%s Attaching inputs to %s: %s\n", style.Bold.Render("📎"), step.ID, strings.Join(inputs, ", "))
+	desc := step.Description + stepInputsReport(townRoot, run, inputs)
+	if err := beads.New(worktree).Update(step.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		style.PrintWarning("could not attach inputs to %s: %v", step.ID, err)
+		return
+	}
+	step.Description = desc
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/artifacts"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestMissingStepOutputs(t *testing.T) {
+	town := t.TempDir()
+	design := &beads.Issue{ID: "gt-wisp-abc.1", Description: "Think first.\nOutputs: design.md, risks.md\n\nstep: design"}
+
+	if got := missingStepOutputs(town, "gt-wisp-abc", design); !reflect.DeepEqual(got, []string{"design.md", "risks.md"}) {
+		t.Errorf("missing = %q", got)
+	}
+	if _, err := artifacts.Put(town, "gt-wisp-abc", "design", "design.md", []byte("# Plan")); err != nil {
+		t.Fatal(err)
+	}
+	if got := missingStepOutputs(town, "gt-wisp-abc", design); !reflect.DeepEqual(got, []string{"risks.md"}) {
+		t.Errorf("missing = %q", got)
+	}
+	if got := missingStepOutputs(town, "gt-wisp-abc", &beads.Issue{ID: "gt-wisp-abc.2"}); got != nil {
+		t.Errorf("step without outputs: missing = %q", got)
+	}
+}
+
+func TestStepInputsReport(t *testing.T) {
+	town := t.TempDir()
+	if _, err := artifacts.Put(town, "gt-wisp-abc", "design", "design.md", []byte("Use a queue.\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	report := stepInputsReport(town, "gt-wisp-abc", []string{"design/design.md", "bench/results.json", "oops"})
+	for _, want := range []string{
+		"## Inputs from earlier steps",
+		"### design/design.md\n\nUse a queue.\n",
+		"### bench/results.json: not stored",
+		"### oops: not a <step>/<name> artifact",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// lintTimeout bounds each review linter run.
+const lintTimeout = 5 * time.Minute
+
+// lintOutputMax caps the findings kept from one linter.
+const lintOutputMax = 8 * 1024
+
+// knownLinters are the linters a Lint line can name, with the commands that
+// run them over the whole worktree. Anything else is run as a shell command.
+var knownLinters = map[string]string{
+	"go vet":        "go vet ./...",
+	"staticcheck":   "staticcheck ./...",
+	"golangci-lint": "golangci-lint run ./...",
+}
+
+// lintResult is one linter's run over a worktree.
+type lintResult struct {
+	Linter   string
+	Command  string
+	Output   string
+	Findings bool   // The linter reported problems
+	Skipped  string // Why it didn't run, if it didn't
+}
+
+// stepLinters returns the linters a step asks for, with "rig" replaced by
+// the rig's configured review linters.
+func stepLinters(step *beads.Issue, rigPath string) []string {
+	var linters []string
+	for _, l := range beads.StepLint(step) {
+		if strings.EqualFold(l, beads.LintRig) {
+			if rigPath != "" {
+				linters = append(linters, config.GetReviewLinters(rigPath)...)
+			}
+			continue
+		}
+		linters = append(linters, l)
+	}
+	return linters
+}
+
+// runLinters runs each linter in dir. A linter that isn't installed, or a
+// Go linter outside a Go module, is skipped with the reason.
+func runLinters(dir string, linters []string) []lintResult {
+	results := make([]lintResult, 0, len(linters))
+	for _, linter := range linters {
+		r := lintResult{Linter: linter, Command: linter}
+		if known, ok := knownLinters[linter]; ok {
+			r.Command = known
+			tool := strings.Fields(known)[0]
+			if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+				r.Skipped = "no go.mod in the worktree"
+			} else if _, err := exec.LookPath(tool); err != nil {
+				r.Skipped = tool + " is not installed"
+			}
+			if r.Skipped != "" {
+				results = append(results, r)
+				continue
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), lintTimeout)
+		cmd := exec.CommandContext(ctx, "sh", "-c", r.Command) //nolint:gosec // G204: linters come from the formula or rig settings
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		cancel()
+
+		r.Output = strings.TrimSpace(string(out))
+		if len(r.Output) > lintOutputMax {
+			r.Output = r.Output[:lintOutputMax] + "\n... (truncated)"
+		}
+		var exitErr *exec.ExitError
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			r.Skipped = fmt.Sprintf("timed out after %s", lintTimeout)
+		case errors.As(err, &exitErr):
+			r.Findings = true
+		case err != nil:
+			r.Skipped = err.Error()
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// lintReport renders linter results as a section for the step's
+// description, where the agent reads them when the step starts.
+func lintReport(results []lintResult, at time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\n## Static analysis (%s)\n", at.Format("2006-01-02 15:04"))
+	for _, r := range results {
+		switch {
+		case r.Skipped != "":
+			fmt.Fprintf(&b, "\n### %s: skipped (%s)\n", r.Linter, r.Skipped)
+		case !r.Findings:
+			fmt.Fprintf(&b, "\n### %s: clean\n", r.Linter)
+		default:
+			fmt.Fprintf(&b, "\n### %s: findings\n\n`%s`\n\n```\n%s\n```\n", r.Linter, r.Command, r.Output)
+		}
+	}
+	for _, r := range results {
+		if r.Findings {
+			b.WriteString("\nAddress these findings (or note why they don't apply) before finishing this step.\n")
+			break
+		}
+	}
+	return b.String()
+}
+
+// attachLintFindings runs a step's linters in the worktree and appends the
+// report to the step's description. Failing to attach only warns: the step
+// goes ahead without the findings.
+func attachLintFindings(worktree string, step *beads.Issue, linters []string) {
+	fmt.Printf("\n%s Running linters for %s: %s\n", style.Bold.Render("🔍"), step.ID, strings.Join(linters, ", "))
+	results := runLinters(worktree, linters)
+	for _, r := range results {
+		switch {
+		case r.Skipped != "":
+			fmt.Printf("  %s %s skipped: %s\n", style.Dim.Render("○"), r.Linter, r.Skipped)
+		case r.Findings:
+			fmt.Printf("  %s %s reported findings\n", style.Warning.Render("⚠"), r.Linter)
+		default:
+			fmt.Printf("  %s %s clean\n", style.Bold.Render("✓"), r.Linter)
+		}
+	}
+
+	desc := step.Description + lintReport(results, time.Now())
+	if err := beads.New(worktree).Update(step.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		style.PrintWarning("could not attach lint findings to %s: %v", step.ID, err)
+		return
+	}
+	step.Description = desc
+}
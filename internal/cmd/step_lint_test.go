@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestStepLinters(t *testing.T) {
+	rigPath := t.TempDir()
+	settings := config.NewRigSettings()
+	settings.Workflow = &config.WorkflowConfig{ReviewLinters: []string{"go vet", "staticcheck"}}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	step := &beads.Issue{Description: "Review the diff.\nLint: rig, make lint"}
+	if got, want := stepLinters(step, rigPath), []string{"go vet", "staticcheck", "make lint"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stepLinters = %q, want %q", got, want)
+	}
+	if got := stepLinters(&beads.Issue{Description: "Write the code."}, rigPath); len(got) != 0 {
+		t.Errorf("stepLinters(plain step) = %q, want none", got)
+	}
+}
+
+func TestRunLinters(t *testing.T) {
+	dir := t.TempDir()
+	results := runLinters(dir, []string{"go vet", "true", "echo 'main.go:3: unused x' && exit 1"})
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if !strings.Contains(results[0].Skipped, "go.mod") {
+		t.Errorf("go vet outside a module: skipped = %q", results[0].Skipped)
+	}
+	if results[1].Findings || results[1].Skipped != "" {
+		t.Errorf("true: %+v, want clean", results[1])
+	}
+	if !results[2].Findings || results[2].Output != "main.go:3: unused x" {
+		t.Errorf("failing linter: %+v", results[2])
+	}
+
+	report := lintReport(results, time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC))
+	for _, want := range []string{
+		"## Static analysis (2026-01-02 03:04)",
+		"### go vet: skipped (no go.mod in the worktree)",
+		"### true: clean",
+		"main.go:3: unused x",
+		"Address these findings",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
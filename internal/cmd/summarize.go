@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/summary"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	summarizeDone      []string
+	summarizeRemaining []string
+	summarizeGotchas   []string
+	summarizeStdin     bool
+	summarizeShow      bool
+	summarizeAsk       string
+	summarizeJSON      bool
+)
+
+var summarizeCmd = &cobra.Command{
+	Use:     "summarize [bead]",
+	GroupID: GroupWork,
+	Short:   "Save a summary of where your task stands to its bead",
+	Long: `Save a structured summary of your current task to its bead (the one on
+your hook unless given): what is done, what remains, and the gotchas the
+next session must not trip over.
+
+The summary is stored as a comment on the bead. Whichever session picks
+the bead up next - after gt handoff, gt resume, a context recycle or a
+crash - is shown the newest summary when it primes.
+
+Run without items to print what to write. --stdin reads the summary as
+markdown with Done, Remaining and Gotchas headings over lists. --ask asks
+another agent to write one, by nudge.
+
+Examples:
+  gt summarize --done "Token refresh added" --remaining "Tests for expiry" \
+      --gotcha "The mock clock ignores time zones"
+  gt summarize gt-abc --stdin < notes.md
+  gt summarize --show
+  gt summarize --ask gastown/crew/max`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSummarize,
+}
+
+func init() {
+	summarizeCmd.Flags().StringArrayVar(&summarizeDone, "done", nil, "Something finished (repeatable)")
+	summarizeCmd.Flags().StringArrayVar(&summarizeRemaining, "remaining", nil, "Something left to do, in order (repeatable)")
+	summarizeCmd.Flags().StringArrayVar(&summarizeGotchas, "gotcha", nil, "A trap or dead end for the next session (repeatable)")
+	summarizeCmd.Flags().BoolVar(&summarizeStdin, "stdin", false, "Read the summary as markdown from stdin")
+	summarizeCmd.Flags().BoolVar(&summarizeShow, "show", false, "Show the bead's newest summary instead")
+	summarizeCmd.Flags().StringVar(&summarizeAsk, "ask", "", "Nudge an agent to write a summary of its task")
+	summarizeCmd.Flags().BoolVar(&summarizeJSON, "json", false, "With --show, output as JSON")
+	rootCmd.AddCommand(summarizeCmd)
+}
+
+func runSummarize(cmd *cobra.Command, args []string) error {
+	if summarizeAsk != "" {
+		nudge := exec.Command("gt", "nudge", summarizeAsk, summary.Prompt(""))
+		nudge.Stdout = os.Stdout
+		nudge.Stderr = os.Stderr
+		return nudge.Run()
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bead := ""
+	if len(args) > 0 {
+		bead = args[0]
+	} else {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+		if info, err := GetRoleWithContext(cwd, townRoot); err == nil {
+			bead = detectHookedBead(cwd, info)
+		}
+		if bead == "" {
+			return fmt.Errorf("nothing on your hook: name the bead to summarize")
+		}
+	}
+	bd := beads.New(beadLocation(townRoot, bead))
+
+	if summarizeShow {
+		s, err := latestSummary(bd, bead)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("%s has no state summary", bead)
+		}
+		if summarizeJSON {
+			return outputJSON(s)
+		}
+		fmt.Print(summary.Format(s))
+		return nil
+	}
+
+	s := &summary.Summary{Done: summarizeDone, Remaining: summarizeRemaining, Gotchas: summarizeGotchas}
+	if summarizeStdin {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+		parsed := summary.ParseSections(string(data))
+		s.Done = append(s.Done, parsed.Done...)
+		s.Remaining = append(s.Remaining, parsed.Remaining...)
+		s.Gotchas = append(s.Gotchas, parsed.Gotchas...)
+	}
+	if s.Empty() {
+		fmt.Println(summary.Prompt(bead))
+		return nil
+	}
+	s.Bead, s.Agent, s.Written = bead, detectSender(), time.Now()
+
+	if err := bd.Comment(bead, summary.Format(s)); err != nil {
+		return fmt.Errorf("saving summary to %s: %w", bead, err)
+	}
+	fmt.Printf("%s State summary saved to %s (%d done, %d remaining, %d gotcha(s))\n", style.Success.Render("✓"),
+		bead, len(s.Done), len(s.Remaining), len(s.Gotchas))
+	return nil
+}
+
+// latestSummary returns the newest state summary on a bead, or nil.
+func latestSummary(bd *beads.Beads, bead string) (*summary.Summary, error) {
+	comments, err := bd.Comments(bead)
+	if err != nil {
+		return nil, fmt.Errorf("reading comments of %s: %w", bead, err)
+	}
+	texts := make([]string, len(comments))
+	for i, c := range comments {
+		texts[i] = c.Text
+	}
+	s := summary.Latest(texts)
+	if s != nil {
+		s.Bead = bead
+	}
+	return s, nil
+}
+
+// outputStateSummary prints the newest state summary on the agent's
+// hooked bead, for gt prime.
+func outputStateSummary(ctx RoleContext, bead string) {
+	s, err := latestSummary(beads.New(beadLocation(ctx.TownRoot, bead)), bead)
+	if err != nil || s == nil {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("%s\n\n", style.Bold.Render("## 📝 Where the Last Session Left Off"))
+	fmt.Println(strings.TrimSpace(strings.TrimPrefix(summary.Format(s), summary.Header)))
+	fmt.Println()
+	fmt.Println(style.Dim.Render("(Update it with: gt summarize --done ... --remaining ... --gotcha ...)"))
+}
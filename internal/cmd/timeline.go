@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/digest"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timeline"
+	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/tui/feed"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	timelineSince    string
+	timelineUntil    string
+	timelineRig      string
+	timelineFilters  []string
+	timelineProblems bool
+	timelineAll      bool
+	timelineJSON     bool
+)
+
+var timelineCmd = &cobra.Command{
+	Use:     "timeline",
+	GroupID: GroupDiag,
+	Short:   "Reconstruct what happened across the town, in order",
+	Long: `Reconstruct an ordered timeline of a window of town activity, for looking
+back at a convoy or overnight run that ended badly.
+
+Spawns, hooked work, molecule steps, refinery merges, escalations, kills,
+session deaths and crashes are read from the event log (and crashes from
+the town log) and put in one list, each with the time since the window's
+first event. Events that end something show how long it took: a session
+from its spawn, a merge from its start, a bead's done from its sling, a
+step from the previous step, an escalation's ack from when it was sent.
+Things that went wrong are marked, and listed again at the end.
+
+Patrol chatter, mail and nudges are left out unless --all.
+
+Examples:
+  gt timeline                         # The last 8 hours
+  gt timeline --since 2026-03-01 --until 2026-03-02
+  gt timeline --rig gastown --problems
+  gt timeline --filter bead=gt-abc    # One bead's story
+  gt timeline --json`,
+	Args: cobra.NoArgs,
+	RunE: runTimeline,
+}
+
+func init() {
+	timelineCmd.Flags().StringVar(&timelineSince, "since", "8h", "Start of the window, as a date or age (e.g. 8h, 2d, 2026-01-01)")
+	timelineCmd.Flags().StringVar(&timelineUntil, "until", "", "End of the window, as a date or age (default: now)")
+	timelineCmd.Flags().StringVar(&timelineRig, "rig", "", "Only events for this rig")
+	timelineCmd.Flags().StringArrayVar(&timelineFilters, "filter", nil, "Filter condition key=value, as in gt events tail (repeatable)")
+	timelineCmd.Flags().BoolVar(&timelineProblems, "problems", false, "Only list what went wrong")
+	timelineCmd.Flags().BoolVar(&timelineAll, "all", false, "Include patrol, mail and nudge events")
+	timelineCmd.Flags().BoolVar(&timelineJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(timelineCmd)
+}
+
+func runTimeline(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	since, err := parseSearchTime(timelineSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until := time.Now()
+	if timelineUntil != "" {
+		if until, err = parseSearchTime(timelineUntil); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	filter, err := events.ParseFilter(timelineFilters)
+	if err != nil {
+		return err
+	}
+
+	evs, err := digest.LoadEvents(townRoot, since)
+	if err != nil {
+		return fmt.Errorf("reading the event log: %w", err)
+	}
+	if logged, err := townlog.ReadEvents(townRoot); err != nil {
+		style.PrintWarning("could not read the town log: %v", err)
+	} else {
+		evs = append(evs, timeline.FromTownLog(logged)...)
+	}
+	var selected []*events.Event
+	for _, e := range evs {
+		if filter.Match(e) && (timelineRig == "" || eventInRig(e, timelineRig)) {
+			selected = append(selected, e)
+		}
+	}
+
+	t := timeline.Build(selected, since, until, timelineAll)
+	if timelineProblems {
+		t.Entries = t.Problems()
+		t.Counts = map[string]int{}
+		for _, e := range t.Entries {
+			t.Counts[e.Kind]++
+		}
+	}
+	if timelineJSON {
+		return outputJSON(t)
+	}
+	printTimeline(t)
+	return nil
+}
+
+// eventInRig reports whether an event concerns a rig, by its payload's rig
+// or its actor's address.
+func eventInRig(e *events.Event, rig string) bool {
+	if r, _ := e.Payload["rig"].(string); r != "" {
+		return r == rig
+	}
+	if a, _ := e.Payload["agent"].(string); strings.HasPrefix(a, rig+"/") {
+		return true
+	}
+	return strings.HasPrefix(e.Actor, rig+"/")
+}
+
+// printTimeline prints the timeline, then what went wrong.
+func printTimeline(t *timeline.Timeline) {
+	fmt.Printf("%s %s to %s\n\n", style.Bold.Render("Timeline"),
+		t.Since.Local().Format("2006-01-02 15:04"), t.Until.Local().Format("2006-01-02 15:04"))
+	if len(t.Entries) == 0 {
+		fmt.Printf("%s Nothing happened in this window\n", style.Dim.Render("○"))
+		return
+	}
+
+	start := t.Entries[0].At
+	for _, e := range t.Entries {
+		symbol := feed.EventSymbols[e.Type]
+		if symbol == "" {
+			symbol = "·"
+		}
+		summary := e.Summary
+		if e.Since != "" {
+			summary += style.Dim.Render(fmt.Sprintf(" [%s after %s]", formatDuration(e.Duration), e.Since))
+		}
+		line := fmt.Sprintf("%s %s %s %-10s %s %s",
+			style.Dim.Render(e.At.Local().Format("15:04:05")),
+			style.Dim.Render(fmt.Sprintf("%9s", "+"+formatDuration(e.At.Sub(start)))),
+			symbol, e.Kind, e.Actor, summary)
+		if e.Problem {
+			line = style.Error.Render("!") + " " + line
+		} else {
+			line = "  " + line
+		}
+		fmt.Println(line)
+	}
+
+	problems := t.Problems()
+	fmt.Printf("\n%d event(s) over %s: %d spawn, %d step, %d merge, %d escalation, %d kill, %d error\n",
+		len(t.Entries), formatDuration(t.Entries[len(t.Entries)-1].At.Sub(start)),
+		t.Counts[timeline.KindSpawn], t.Counts[timeline.KindStep], t.Counts[timeline.KindMerge],
+		t.Counts[timeline.KindEscalation], t.Counts[timeline.KindKill], t.Counts[timeline.KindError])
+	if len(problems) == 0 {
+		fmt.Printf("%s Nothing went wrong\n", style.Success.Render("✓"))
+		return
+	}
+	if len(problems) == len(t.Entries) {
+		return // Already listed
+	}
+	fmt.Printf("\n%s (%d):\n", style.Bold.Render("What went wrong"), len(problems))
+	for _, p := range problems {
+		fmt.Printf("  %s +%s %s: %s\n", p.At.Local().Format("15:04:05"), formatDuration(p.At.Sub(start)), p.Actor, p.Summary)
+	}
+}
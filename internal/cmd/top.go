@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/heartbeat"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/top"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+var (
+	topInterval time.Duration
+	topRig      string
+)
+
+// topPaneLines is how much of each polecat's pane a refresh reads for its
+// token count and last line.
+const topPaneLines = 30
+
+var topCmd = &cobra.Command{
+	Use:     "top",
+	GroupID: GroupAgents,
+	Short:   "Live, keyboard-driven view of every polecat",
+	Long: `Show the town's polecats in a live table, one row each:
+
+  POLECAT      rig/name, red when its session is gone
+  STATE        agent state from its agent bead
+  BEAD, STEP   hooked bead and molecule step (from its heartbeat)
+  ELAPSED      how long its session has been up
+  TOKENS       the last token count shown in its pane
+  LAST OUTPUT  the last line in its pane
+
+Rows refresh every --interval from the same aggregation as gt status.
+
+Keys:
+  j/k        select
+  enter, p   peek: follow the selected polecat's pane below the table
+  x          kill its session (gt session stop)
+  r          restart its session (gt session restart)
+  e          escalate it (gt escalate, severity high)
+  ctrl+r     refresh now
+  q          quit
+
+Kill, restart and escalate ask for y before acting.
+
+Examples:
+  gt top
+  gt top --rig greenplace --interval 5s`,
+	Args: cobra.NoArgs,
+	RunE: runTop,
+}
+
+func init() {
+	topCmd.Flags().DurationVar(&topInterval, "interval", 2*time.Second, "Refresh interval")
+	topCmd.Flags().StringVar(&topRig, "rig", "", "Only this rig's polecats")
+	rootCmd.AddCommand(topCmd)
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("gt top needs a terminal; use 'gt status --watch' for plain output")
+	}
+	if topInterval < 500*time.Millisecond {
+		return fmt.Errorf("--interval must be at least 500ms")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	statusFast = true // Mail counts aren't shown
+	source := &topSource{townRoot: townRoot, rig: topRig, tmux: tmux.NewTmux()}
+	_, err = tea.NewProgram(top.New(source, topInterval), tea.WithAltScreen()).Run()
+	return err
+}
+
+// topSource feeds gt top from the town's status and tmux.
+type topSource struct {
+	townRoot string
+	rig      string
+	tmux     *tmux.Tmux
+}
+
+// Rows returns a row per polecat, their panes read in parallel.
+func (s *topSource) Rows() ([]top.Row, error) {
+	status := collectTownStatus(s.townRoot)
+	var rows []top.Row
+	for _, rs := range status.Rigs {
+		if s.rig != "" && rs.Name != s.rig {
+			continue
+		}
+		for _, a := range rs.Agents {
+			if a.Role != "polecat" {
+				continue
+			}
+			rows = append(rows, top.Row{
+				Rig:     rs.Name,
+				Name:    a.Name,
+				Address: a.Address,
+				Running: a.Running,
+				State:   a.State,
+				Bead:    a.HookBead,
+				Title:   a.WorkTitle,
+				Session: a.Session,
+			})
+		}
+	}
+
+	details, finished := gatherParallel(len(rows), statusJobs, statusRigTimeout, func(i int) top.Row {
+		return s.detail(rows[i])
+	})
+	for i := range rows {
+		if finished[i] {
+			rows[i] = details[i]
+		}
+	}
+	return rows, nil
+}
+
+// detail fills in what a row needs beyond gt status: the heartbeat's step
+// and what the polecat's pane shows.
+func (s *topSource) detail(row top.Row) top.Row {
+	if hb, err := heartbeat.Read(s.townRoot, row.Rig, row.Name); err == nil && hb != nil {
+		row.Step = hb.Step
+	}
+	if !row.Running {
+		return row
+	}
+	if info, err := s.tmux.GetSessionInfo(row.Session); err == nil {
+		if created, err := time.ParseInLocation(time.ANSIC, info.Created, time.Local); err == nil {
+			row.Elapsed = time.Since(created)
+		}
+	}
+	if lines, err := s.tmux.CapturePaneLines(row.Session, topPaneLines); err == nil {
+		row.Tokens = extractTokens(strings.Join(lines, "\n"))
+		for i := len(lines) - 1; i >= 0; i-- {
+			if l := strings.TrimSpace(lines[i]); l != "" {
+				row.LastLine = l
+				break
+			}
+		}
+	}
+	return row
+}
+
+// Peek returns the tail of a polecat's pane.
+func (s *topSource) Peek(row top.Row) (string, error) {
+	return s.tmux.CapturePane(row.Session, 200)
+}
+
+// Act runs the gt command behind an action.
+func (s *topSource) Act(action top.Action, row top.Row) error {
+	var args []string
+	switch action {
+	case top.ActionKill:
+		args = []string{"session", "stop", row.Address}
+	case top.ActionRestart:
+		args = []string{"session", "restart", row.Address}
+	case top.ActionEscalate:
+		context := "Escalated from gt top."
+		if row.Step != "" {
+			context += " Step: " + row.Step + "."
+		}
+		if row.LastLine != "" {
+			context += " Last output: " + row.LastLine
+		}
+		args = []string{"escalate", row.Address + " needs attention", "--severity", "high", "--source", "gt-top", "--context", context}
+		if row.Bead != "" {
+			args = append(args, "--related", row.Bead)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+	out, err := exec.Command("gt", args...).CombinedOutput() //nolint:gosec // G204: fixed gt subcommands
+	if err != nil {
+		return fmt.Errorf("%s", lastLine(string(out), err.Error()))
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of out, or fallback.
+func lastLine(out, fallback string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if l := strings.TrimSpace(lines[len(lines)-1]); l != "" {
+		return l
+	}
+	return fallback
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Town peer command flags
+var (
+	townPeerToken    string
+	townPeerListJSON bool
+)
+
+var townPeerCmd = &cobra.Command{
+	Use:   "peer",
+	Short: "Manage peer towns for inter-town convoys",
+	Long: `Manage peer towns whose issues can be tracked by local convoys.
+
+Organizations running one town per team can register each other's towns
+as peers. A convoy can then track issues in a peer town using the
+<peer>:<issue-id> reference form:
+
+  gt town peer add team-b http://team-b.internal:8080
+  gt convoy create "Auth rollout" gt-abc team-b:be-xyz
+
+Peer towns serve issue status from 'gt dashboard --api-token' (/api/issues).
+
+COMMANDS:
+  add       Register a peer town
+  remove    Unregister a peer town
+  list      List registered peer towns`,
+	RunE: requireSubcommand,
+}
+
+var townPeerAddCmd = &cobra.Command{
+	Use:   "add <name> <url>",
+	Short: "Register a peer town",
+	Long: `Register a peer town by name and dashboard URL.
+
+The URL is the base address of the peer's 'gt dashboard' server. The peer
+only serves its API with a token (gt dashboard --api-token); pass that
+token with --token.
+
+Examples:
+  gt town peer add team-b http://team-b.internal:8080
+  gt town peer add infra https://infra-town.example.com --token=s3cret`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTownPeerAdd,
+}
+
+var townPeerRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Unregister a peer town",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTownPeerRemove,
+}
+
+var townPeerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered peer towns",
+	RunE:  runTownPeerList,
+}
+
+func init() {
+	townPeerAddCmd.Flags().StringVar(&townPeerToken, "token", "", "Bearer token for the peer's API")
+	townPeerListCmd.Flags().BoolVar(&townPeerListJSON, "json", false, "Output as JSON")
+
+	townPeerCmd.AddCommand(townPeerAddCmd)
+	townPeerCmd.AddCommand(townPeerRemoveCmd)
+	townPeerCmd.AddCommand(townPeerListCmd)
+	townCmd.AddCommand(townPeerCmd)
+}
+
+func runTownPeerAdd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	peers, err := federation.LoadPeers(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := peers.Add(args[0], args[1], townPeerToken); err != nil {
+		return err
+	}
+	if err := federation.SavePeers(townRoot, peers); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Registered peer town %s → %s\n", style.Bold.Render("✓"), args[0], peers.Peers[args[0]].URL)
+	fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("Track its issues with: gt convoy add <convoy> %s:<issue-id>", args[0])))
+	return nil
+}
+
+func runTownPeerRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	peers, err := federation.LoadPeers(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := peers.Remove(args[0]); err != nil {
+		return err
+	}
+	if err := federation.SavePeers(townRoot, peers); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Removed peer town %s\n", style.Bold.Render("✓"), args[0])
+	return nil
+}
+
+func runTownPeerList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	peers, err := federation.LoadPeers(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if townPeerListJSON {
+		type jsonPeer struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		}
+		out := make([]jsonPeer, 0, len(peers.Peers))
+		for _, name := range peers.Names() {
+			out = append(out, jsonPeer{Name: name, URL: peers.Peers[name].URL})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(peers.Peers) == 0 {
+		fmt.Println("No peer towns registered.")
+		fmt.Println("Register one with: gt town peer add <name> <url>")
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Peer Towns"))
+	for _, name := range peers.Names() {
+		fmt.Printf("  %s  %s\n", name, style.Dim.Render(peers.Peers[name].URL))
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tui/triage"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+// Labels gt triage adds: every triaged bead is labeled, so it leaves the
+// inbox; dismissed ones are iceboxed as well.
+const (
+	labelTriaged = "triaged"
+	labelIcebox  = "icebox"
+)
+
+// triageTypes are the bead types that arrive as work to triage.
+var triageTypes = map[string]bool{"task": true, "bug": true, "feature": true, "chore": true, "epic": true}
+
+// triageSourceLabels are labels a bead arrives with - where it came from and
+// who filed it - which don't mean anyone has triaged it.
+var triageSourceLabels = []string{"rig:", "polecat:", "crew:", "github", "gh:", "gh-label:", "jira"}
+
+var triageRig string
+
+var triageCmd = &cobra.Command{
+	Use:     "triage",
+	GroupID: GroupWork,
+	Short:   "Triage new beads interactively",
+	Long: `Step through new beads and triage each with single keystrokes.
+
+A bead is new while it is open, unassigned, and carries no labels besides
+those it arrived with (rig:, polecat:, crew:, and the GitHub and Jira
+import labels). The town's beads and every rig's are triaged together;
+--rig narrows it to one rig.
+
+Keys:
+  0-4       set priority
+  l         add a label (type it, enter)
+  m         pick the molecule to work it with (molecule:<formula>)
+  r         pick the rig to work it in (rig:<name>)
+  i         dismiss to the icebox (status deferred, label icebox)
+  u         undo this bead's decisions
+  j/enter   next bead, k previous
+  q         apply the decisions and quit
+  ctrl+c    quit without changing anything
+
+Nothing is written until q; then each decided bead gets one update, and
+is labeled triaged so it leaves the inbox. 'gt ready' and 'gt assign
+--auto' pick up the molecule chosen here.
+
+Examples:
+  gt triage
+  gt triage --rig greenplace`,
+	Args: cobra.NoArgs,
+	RunE: runTriage,
+}
+
+func init() {
+	triageCmd.Flags().StringVar(&triageRig, "rig", "", "Only this rig's beads")
+	rootCmd.AddCommand(triageCmd)
+}
+
+func runTriage(cmd *cobra.Command, args []string) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("gt triage is interactive and needs a terminal")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	items, dirs, rigNames, err := collectTriage(townRoot, triageRig)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println("Nothing to triage")
+		return nil
+	}
+
+	final, err := tea.NewProgram(triage.New(items, townFormulaNames(townRoot), rigNames), tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	m := final.(triage.Model)
+	if !m.Finished() {
+		fmt.Println("Triage abandoned; nothing changed")
+		return nil
+	}
+
+	decided := m.Decisions()
+	applied := 0
+	for _, t := range decided {
+		if err := beads.New(dirs[t.Item.ID]).Update(t.Item.ID, triageUpdate(t)); err != nil {
+			style.PrintWarning("%s: %v", t.Item.ID, err)
+			continue
+		}
+		applied++
+		fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), t.Item.ID, style.Dim.Render(triageSummary(t.Decision)))
+	}
+	fmt.Printf("%s Triaged %d of %d bead(s)\n", style.Bold.Render("✓"), applied, len(items))
+	return nil
+}
+
+// collectTriage returns the new beads of the town and its rigs (only
+// rigName's, if set), the beads directory each lives in, and the rig names.
+func collectTriage(townRoot, rigName string) ([]triage.Item, map[string]string, []string, error) {
+	rigs, err := discoverAllRigs(townRoot)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("listing rigs: %w", err)
+	}
+	type source struct{ rig, dir string }
+	var sources []source
+	if rigName == "" {
+		sources = append(sources, source{"", townRoot})
+	}
+	var rigNames []string
+	for _, r := range rigs {
+		rigNames = append(rigNames, r.Name)
+		if rigName == "" || r.Name == rigName {
+			sources = append(sources, source{r.Name, r.BeadsPath()})
+		}
+	}
+	sort.Strings(rigNames)
+	if rigName != "" && len(sources) == 0 {
+		return nil, nil, nil, fmt.Errorf("rig '%s' not found", rigName)
+	}
+
+	var items []triage.Item
+	dirs := make(map[string]string)
+	for _, s := range sources {
+		issues, err := beads.New(s.dir).List(beads.ListOptions{Status: "open", Priority: -1})
+		if err != nil {
+			style.PrintWarning("could not list %s beads: %v", triageWhere(s.rig), err)
+			continue
+		}
+		for _, i := range issues {
+			if !needsTriage(i) || dirs[i.ID] != "" {
+				continue
+			}
+			dirs[i.ID] = s.dir
+			items = append(items, triage.Item{
+				ID:          i.ID,
+				Title:       i.Title,
+				Type:        i.Type,
+				Description: i.Description,
+				Priority:    i.Priority,
+				Labels:      i.Labels,
+				Rig:         s.rig,
+			})
+		}
+	}
+	return items, dirs, rigNames, nil
+}
+
+// needsTriage reports whether a bead is new work nobody has looked at.
+func needsTriage(issue *beads.Issue) bool {
+	if issue.Status != "open" || issue.Assignee != "" || !triageTypes[issue.Type] {
+		return false
+	}
+	for _, l := range issue.Labels {
+		if !isTriageSourceLabel(l) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTriageSourceLabel(label string) bool {
+	for _, p := range triageSourceLabels {
+		if strings.HasPrefix(label, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// triageUpdate is the single update that applies a decision. A new
+// molecule or rig replaces the bead's existing one.
+func triageUpdate(t triage.Triaged) beads.UpdateOptions {
+	d := t.Decision
+	opts := beads.UpdateOptions{Priority: d.Priority}
+	opts.AddLabels = append(append(opts.AddLabels, d.Labels...), labelTriaged)
+	if d.Molecule != "" {
+		opts.AddLabels = append(opts.AddLabels, moleculeLabelPrefix+d.Molecule)
+	}
+	if d.Rig != "" {
+		opts.AddLabels = append(opts.AddLabels, "rig:"+d.Rig)
+	}
+	for _, l := range t.Item.Labels {
+		if (d.Molecule != "" && strings.HasPrefix(l, moleculeLabelPrefix) && l != moleculeLabelPrefix+d.Molecule) ||
+			(d.Rig != "" && strings.HasPrefix(l, "rig:") && l != "rig:"+d.Rig) {
+			opts.RemoveLabels = append(opts.RemoveLabels, l)
+		}
+	}
+	if d.Icebox {
+		status := "deferred"
+		opts.Status = &status
+		opts.AddLabels = append(opts.AddLabels, labelIcebox)
+	}
+	return opts
+}
+
+// triageSummary describes a decision in one line.
+func triageSummary(d triage.Decision) string {
+	var parts []string
+	if d.Icebox {
+		parts = append(parts, "iceboxed")
+	}
+	if d.Priority != nil {
+		parts = append(parts, fmt.Sprintf("P%d", *d.Priority))
+	}
+	if d.Molecule != "" {
+		parts = append(parts, "molecule "+d.Molecule)
+	}
+	if d.Rig != "" {
+		parts = append(parts, "rig "+d.Rig)
+	}
+	for _, l := range d.Labels {
+		parts = append(parts, "+"+l)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func triageWhere(rig string) string {
+	if rig == "" {
+		return "town"
+	}
+	return rig
+}
+
+// townFormulaNames returns the names of the town's formulas.
+func townFormulaNames(townRoot string) []string {
+	paths, _ := filepath.Glob(filepath.Join(townRoot, constants.DirBeads, "formulas", "*.formula.toml"))
+	names := make([]string, 0, len(paths))
+	for _, p := range paths {
+		names = append(names, strings.TrimSuffix(filepath.Base(p), ".formula.toml"))
+	}
+	return names
+}
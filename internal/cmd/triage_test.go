@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/tui/triage"
+)
+
+func TestNeedsTriage(t *testing.T) {
+	tests := []struct {
+		issue beads.Issue
+		want  bool
+	}{
+		{beads.Issue{Status: "open", Type: "task"}, true},
+		{beads.Issue{Status: "open", Type: "bug", Labels: []string{"rig:gastown", "github", "gh:issue", "gh-label:help"}}, true},
+		{beads.Issue{Status: "open", Type: "task", Labels: []string{"triaged"}}, false},
+		{beads.Issue{Status: "open", Type: "task", Assignee: "gastown/polecats/Toast"}, false},
+		{beads.Issue{Status: "hooked", Type: "task"}, false},
+		{beads.Issue{Status: "open", Type: "merge-request"}, false},
+	}
+	for _, tt := range tests {
+		if got := needsTriage(&tt.issue); got != tt.want {
+			t.Errorf("needsTriage(%+v) = %v, want %v", tt.issue, got, tt.want)
+		}
+	}
+}
+
+func TestTriageUpdate(t *testing.T) {
+	p := 1
+	opts := triageUpdate(triage.Triaged{
+		Item:     triage.Item{ID: "gt-1", Labels: []string{"rig:town", "molecule:shiny", "polecat:Toast"}},
+		Decision: triage.Decision{Priority: &p, Labels: []string{"ui"}, Molecule: "mol-polecat-work", Rig: "gastown", Icebox: true},
+	})
+	if opts.Priority == nil || *opts.Priority != 1 || opts.Status == nil || *opts.Status != "deferred" {
+		t.Errorf("priority/status = %v/%v", opts.Priority, opts.Status)
+	}
+	for _, l := range []string{"ui", "triaged", "molecule:mol-polecat-work", "rig:gastown", "icebox"} {
+		if !slices.Contains(opts.AddLabels, l) {
+			t.Errorf("AddLabels %v missing %s", opts.AddLabels, l)
+		}
+	}
+	if !slices.Equal(opts.RemoveLabels, []string{"rig:town", "molecule:shiny"}) {
+		t.Errorf("RemoveLabels = %v", opts.RemoveLabels)
+	}
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tutorial"
+)
+
+var (
+	tutorialDir  string
+	tutorialYes  bool
+	tutorialKeep bool
+)
+
+var tutorialCmd = &cobra.Command{
+	Use:     "tutorial",
+	GroupID: GroupWorkspace,
+	Short:   "Guided walkthrough of Gas Town in a throwaway sandbox",
+	Long: `Walk through the life of a piece of work in a sandbox town.
+
+The tutorial creates a fixture town with one rig, then:
+  1. Slings a work item to a polecat
+  2. Lets you watch the polecat work
+  3. Reviews what it submitted
+  4. Lands it on main, the way the refinery does
+
+The polecat is a scripted mock agent: no model calls, no beads database,
+no tmux. It costs nothing and leaves nothing behind unless --keep is set.
+
+Examples:
+  gt tutorial
+  gt tutorial --keep --dir ~/gt-tutorial   # Keep the sandbox to poke around
+  gt tutorial --yes                        # Run straight through`,
+	Args: cobra.NoArgs,
+	RunE: runTutorial,
+}
+
+func init() {
+	tutorialCmd.Flags().StringVar(&tutorialDir, "dir", "", "Sandbox directory (default: a new temp directory)")
+	tutorialCmd.Flags().BoolVarP(&tutorialYes, "yes", "y", false, "Don't pause between lessons")
+	tutorialCmd.Flags().BoolVar(&tutorialKeep, "keep", false, "Keep the sandbox afterwards")
+	rootCmd.AddCommand(tutorialCmd)
+}
+
+// tutorialPolecat is the mock polecat's name.
+const tutorialPolecat = "toast"
+
+func runTutorial(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dir := tutorialDir
+	if dir == "" {
+		tmp, err := os.MkdirTemp("", "gt-tutorial-")
+		if err != nil {
+			return fmt.Errorf("creating sandbox directory: %w", err)
+		}
+		dir = tmp
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	pause := func() {
+		if tutorialYes {
+			fmt.Println()
+			return
+		}
+		fmt.Printf("\n%s", style.Dim.Render("Press Enter to continue..."))
+		_, _ = reader.ReadString('\n')
+		fmt.Println()
+	}
+	lesson := func(n int, title string) {
+		fmt.Printf("%s %s\n\n", style.Bold.Render(fmt.Sprintf("Lesson %d:", n)), style.Bold.Render(title))
+	}
+	inRealTown := func(cmds ...string) {
+		fmt.Printf("  %s\n", style.Dim.Render("In a real town: "+strings.Join(cmds, ", ")))
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("🏭 Welcome to Gas Town"))
+	fmt.Println("Gas Town runs many coding agents at once. You hand out work, agents")
+	fmt.Println("do it in their own git worktrees, and a merge queue lands the results.")
+	fmt.Println("This walkthrough uses a sandbox town and a scripted agent, so it's free.")
+	pause()
+
+	// Lesson 1: the town
+	lesson(1, "Your town")
+	sandbox, err := tutorial.Create(dir)
+	if err != nil {
+		return fmt.Errorf("creating sandbox: %w", err)
+	}
+	if !tutorialKeep {
+		defer func() { _ = sandbox.Remove() }()
+	}
+	fmt.Printf("Created a sandbox town at %s\n\n", sandbox.Root)
+	fmt.Printf("  %-28s %s\n", "mayor/town.json", style.Dim.Render("town identity"))
+	fmt.Printf("  %-28s %s\n", "mayor/rigs.json", style.Dim.Render("registered rigs (projects)"))
+	fmt.Printf("  %-28s %s\n", tutorial.RigName+"/mayor/rig/", style.Dim.Render("the rig's main clone"))
+	fmt.Printf("  %-28s %s\n\n", tutorial.RigName+"/polecats/", style.Dim.Render("worker worktrees, one per polecat"))
+	fmt.Println("A rig wraps one git repository. Polecats are short-lived workers that")
+	fmt.Println("each get their own branch and worktree in the rig.")
+	inRealTown("gt install ~/gt", "gt rig add "+tutorial.RigName+" <git-url>")
+	pause()
+
+	// Lesson 2: sling work
+	lesson(2, "Sling work to a polecat")
+	fmt.Printf("Work items are beads. Ours is %s: %q.\n", tutorial.DemoBead, tutorial.DemoTitle)
+	fmt.Println("Slinging it to the rig spawns a polecat with the bead on its hook.")
+	fmt.Println()
+	agent, err := sandbox.Spawn(tutorialPolecat)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Spawned polecat %s on branch %s\n", style.Bold.Render("✓"), tutorialPolecat, tutorial.PolecatBranch(tutorialPolecat))
+	fmt.Printf("  %s\n", style.Dim.Render(agent.WorkDir))
+	inRealTown("gt sling "+tutorial.DemoBead+" "+tutorial.RigName, "gt polecat list "+tutorial.RigName)
+	pause()
+
+	// Lesson 3: watch it work
+	lesson(3, "Watch it work")
+	fmt.Println("The polecat primes itself, finds the bead on its hook, and gets to work.")
+	fmt.Println()
+	agent.Out = os.Stdout
+	if !tutorialYes {
+		agent.Delay = 700 * time.Millisecond
+	}
+	if err := agent.Run(ctx, tutorial.DemoScript()); err != nil {
+		return fmt.Errorf("mock agent: %w", err)
+	}
+	fmt.Println()
+	inRealTown("gt peek "+tutorial.RigName+"/"+tutorialPolecat, "gt feed", "gt events tail -f")
+	pause()
+
+	// Lesson 4: review
+	lesson(4, "Review the submission")
+	stat, diff, err := sandbox.Review(tutorialPolecat)
+	if err != nil {
+		return fmt.Errorf("reviewing: %w", err)
+	}
+	fmt.Println("'gt done' put the branch on the merge queue. Here is what it changes:")
+	fmt.Println()
+	fmt.Print(stat)
+	fmt.Println()
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++"):
+			fmt.Println(style.Success.Render(line))
+		case strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---"):
+			fmt.Println(style.Error.Render(line))
+		default:
+			fmt.Println(style.Dim.Render(line))
+		}
+	}
+	fmt.Println()
+	inRealTown("gt mq list "+tutorial.RigName, "gt mq next "+tutorial.RigName)
+	pause()
+
+	// Lesson 5: land it
+	lesson(5, "Land it")
+	fmt.Println("The refinery merges queued branches into main one at a time, then the")
+	fmt.Println("polecat's worktree is cleaned up.")
+	fmt.Println()
+	commit, err := sandbox.Land(tutorialPolecat, fmt.Sprintf("Merge %s (%s)", tutorial.PolecatBranch(tutorialPolecat), tutorial.DemoBead))
+	if err != nil {
+		return fmt.Errorf("landing: %w", err)
+	}
+	fmt.Printf("%s Merged to %s (commit %s)\n\n", style.Bold.Render("✓"), tutorial.Branch, commit[:8])
+	if log, err := sandbox.Log(); err == nil {
+		fmt.Print(log)
+	}
+	fmt.Println()
+	inRealTown("gt refinery start "+tutorial.RigName, "gt mq status <mr-id>")
+	pause()
+
+	fmt.Printf("%s\n\n", style.Bold.Render("🎉 That's the whole loop."))
+	fmt.Println("Next steps:")
+	fmt.Println("  gt install ~/gt          Create your town")
+	fmt.Println("  gt rig add <name> <url>  Add a project")
+	fmt.Println("  gt sling <bead> <rig>    Put a polecat to work")
+	if tutorialKeep {
+		fmt.Printf("\nThe sandbox is kept at %s\n", sandbox.Root)
+	}
+	return nil
+}
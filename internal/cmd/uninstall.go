@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -14,35 +15,50 @@ import (
 	"github.com/steveyegge/gastown/internal/shell"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/wrappers"
 )
 
 var (
 	uninstallWorkspace bool
 	uninstallForce     bool
+	uninstallDryRun    bool
 )
 
 var uninstallCmd = &cobra.Command{
-	Use:     "uninstall",
+	Use:     "uninstall [town]",
 	GroupID: GroupConfig,
 	Short:   "Remove Gas Town from the system",
 	Long: `Completely remove Gas Town from the system.
 
-By default, removes:
-  - Shell integration (~/.zshrc or ~/.bashrc)
+gt install records what it touches in an install manifest
+(~/.local/state/gastown/install-manifest.json). Uninstall undoes
+everything in the manifest, plus anything it can find that an older
+install left behind:
+  - Daemon units (systemd user units, launchd agents), disabled first
+  - Shell integration: the Gas Town block in ~/.zshrc, ~/.bashrc or the
+    PowerShell profile, which is also where PATH changes live
+  - Shell hook scripts (~/.config/gastown/shell-hook.*)
   - Wrapper scripts (~/bin/gt-codex, ~/bin/gt-opencode)
-  - State directory (~/.local/state/gastown/)
+  - State directory (~/.local/state/gastown/), including the harness registry
   - Config directory (~/.config/gastown/)
   - Cache directory (~/.cache/gastown/)
 
-The workspace (e.g., ~/gt) is NOT removed unless --workspace is specified.
+The workspace (e.g., ~/gt) is NOT removed unless --workspace is specified
+or a town path is given. With --workspace, every town gt install created
+is removed.
 
-Use --force to skip confirmation prompts.
+When it finishes, uninstall prints a manifest of everything it touched.
+Use --dry-run to see the plan without changing anything, and --force to
+skip confirmation prompts. 'gt install --uninstall' is the same command.
 
 Examples:
   gt uninstall                    # Remove Gas Town, keep workspace
+  gt uninstall --dry-run          # Show what would be removed
   gt uninstall --workspace        # Also remove workspace directory
+  gt uninstall ~/gt               # Also remove the town at ~/gt
   gt uninstall --force            # Skip confirmation`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runUninstall,
 }
 
@@ -51,21 +67,42 @@ func init() {
 		"Also remove the workspace directory (DESTRUCTIVE)")
 	uninstallCmd.Flags().BoolVarP(&uninstallForce, "force", "f", false,
 		"Skip confirmation prompts")
+	uninstallCmd.Flags().BoolVarP(&uninstallDryRun, "dry-run", "n", false,
+		"Show what would be removed without removing anything")
 	rootCmd.AddCommand(uninstallCmd)
 }
 
+// uninstallItem is one thing uninstall will undo.
+type uninstallItem struct {
+	Kind   string // state.Kind*, or "dir" for the XDG directories
+	Path   string
+	Detail string
+	Status string // Filled in as the item is processed
+}
+
 func runUninstall(cmd *cobra.Command, args []string) error {
+	var towns []string
+	if len(args) > 0 {
+		towns = append(towns, util.ExpandHome(args[0]))
+	}
+	plan, err := buildUninstallPlan(uninstallWorkspace, towns)
+	if err != nil {
+		return err
+	}
+
+	if uninstallDryRun {
+		fmt.Println("Would remove:")
+		printUninstallPlan(plan, false)
+		return nil
+	}
+
 	if !uninstallForce {
 		fmt.Println("This will remove Gas Town from your system.")
 		fmt.Println()
 		fmt.Println("The following will be removed:")
-		fmt.Printf("  • Shell integration (%s)\n", shell.RCFilePath(shell.DetectShell()))
-		fmt.Printf("  • Wrapper scripts (%s)\n", wrappers.BinDir())
-		fmt.Printf("  • State directory (%s)\n", state.StateDir())
-		fmt.Printf("  • Config directory (%s)\n", state.ConfigDir())
-		fmt.Printf("  • Cache directory (%s)\n", state.CacheDir())
+		printUninstallPlan(plan, false)
 
-		if uninstallWorkspace {
+		if hasUninstallKind(plan, state.KindTown) {
 			fmt.Println()
 			fmt.Printf("  %s WORKSPACE WILL BE DELETED\n", style.Warning.Render("⚠"))
 			fmt.Println("     This cannot be undone!")
@@ -89,47 +126,18 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	fmt.Println("Removing Gas Town...")
 
-	if err := shell.Remove(); err != nil {
-		errors = append(errors, fmt.Sprintf("shell integration: %v", err))
-	} else {
-		fmt.Printf("  %s Removed shell integration\n", style.Success.Render("✓"))
-	}
-
-	if err := wrappers.Remove(); err != nil {
-		errors = append(errors, fmt.Sprintf("wrapper scripts: %v", err))
-	} else {
-		fmt.Printf("  %s Removed wrapper scripts\n", style.Success.Render("✓"))
-	}
-
-	if err := os.RemoveAll(state.StateDir()); err != nil && !os.IsNotExist(err) {
-		errors = append(errors, fmt.Sprintf("state directory: %v", err))
-	} else {
-		fmt.Printf("  %s Removed state directory\n", style.Success.Render("✓"))
-	}
-
-	if err := os.RemoveAll(state.ConfigDir()); err != nil && !os.IsNotExist(err) {
-		errors = append(errors, fmt.Sprintf("config directory: %v", err))
-	} else {
-		fmt.Printf("  %s Removed config directory\n", style.Success.Render("✓"))
-	}
-
-	if err := os.RemoveAll(state.CacheDir()); err != nil && !os.IsNotExist(err) {
-		errors = append(errors, fmt.Sprintf("cache directory: %v", err))
-	} else {
-		fmt.Printf("  %s Removed cache directory\n", style.Success.Render("✓"))
-	}
-
-	if uninstallWorkspace {
-		workspaceDir := findWorkspaceForUninstall()
-		if workspaceDir != "" {
-			if err := os.RemoveAll(workspaceDir); err != nil {
-				errors = append(errors, fmt.Sprintf("workspace: %v", err))
-			} else {
-				fmt.Printf("  %s Removed workspace: %s\n", style.Success.Render("✓"), workspaceDir)
-			}
+	for i := range plan {
+		item := &plan[i]
+		if err := undoInstallItem(item); err != nil {
+			item.Status = "failed"
+			errors = append(errors, fmt.Sprintf("%s: %v", item.Path, err))
 		}
 	}
 
+	fmt.Println()
+	fmt.Println("Touched:")
+	printUninstallPlan(plan, true)
+
 	if len(errors) > 0 {
 		fmt.Println()
 		fmt.Printf("%s Some components could not be removed:\n", style.Warning.Render("⚠"))
@@ -149,6 +157,184 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// buildUninstallPlan lists what to undo, in the order to undo it: daemon
+// units first so nothing restarts mid-uninstall, the state directory (which
+// holds the manifest) last. Entries come from the install manifest plus
+// whatever an unrecorded install is known to leave behind.
+func buildUninstallPlan(withWorkspace bool, towns []string) ([]uninstallItem, error) {
+	manifest, err := state.LoadManifest()
+	if err != nil {
+		return nil, fmt.Errorf("reading install manifest: %w", err)
+	}
+
+	var plan []uninstallItem
+	seen := make(map[string]bool)
+	add := func(kind, path, detail string) {
+		if path == "" || seen[path] {
+			return
+		}
+		seen[path] = true
+		plan = append(plan, uninstallItem{Kind: kind, Path: path, Detail: detail})
+	}
+
+	for _, e := range manifest.OfKind(state.KindUnit) {
+		add(e.Kind, e.Path, e.Detail)
+	}
+	for _, unit := range findDaemonUnits() {
+		add(state.KindUnit, unit, "")
+	}
+
+	for _, e := range manifest.OfKind(state.KindRCBlock) {
+		add(e.Kind, e.Path, e.Detail)
+	}
+	for _, rc := range shell.RCFiles() {
+		if shell.HasBlock(rc) {
+			add(state.KindRCBlock, rc, "")
+		}
+	}
+
+	for _, e := range manifest.OfKind(state.KindFile) {
+		add(e.Kind, e.Path, e.Detail)
+	}
+	candidates := []string{shell.HookScriptPath("zsh"), shell.HookScriptPath(shell.PowerShell)}
+	candidates = append(candidates, wrappers.Paths()...)
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			add(state.KindFile, path, "")
+		}
+	}
+
+	if withWorkspace && len(towns) == 0 {
+		for _, e := range manifest.OfKind(state.KindTown) {
+			towns = append(towns, e.Path)
+		}
+		if len(towns) == 0 {
+			towns = append(towns, findWorkspaceForUninstall())
+		}
+	}
+	for _, town := range towns {
+		if town == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(town); err == nil {
+			town = abs
+		}
+		add(state.KindTown, town, "")
+	}
+
+	add("dir", state.ConfigDir(), "config")
+	add("dir", state.CacheDir(), "cache")
+	add("dir", state.StateDir(), "state, harness registry, install manifest")
+
+	return plan, nil
+}
+
+// findDaemonUnits returns systemd user units and launchd agents that look
+// like they run a Gas Town daemon.
+func findDaemonUnits() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var units []string
+	for _, pattern := range []string{
+		filepath.Join(home, ".config", "systemd", "user", "gastown*.service"),
+		filepath.Join(home, ".config", "systemd", "user", "gt-*.service"),
+		filepath.Join(home, "Library", "LaunchAgents", "*gastown*.plist"),
+	} {
+		matches, _ := filepath.Glob(pattern)
+		units = append(units, matches...)
+	}
+	return units
+}
+
+// undoInstallItem undoes one plan item and records what happened in its
+// Status. A thing that is already gone is not an error.
+func undoInstallItem(item *uninstallItem) error {
+	switch item.Kind {
+	case state.KindUnit:
+		if _, err := os.Stat(item.Path); os.IsNotExist(err) {
+			item.Status = "not present"
+			return nil
+		}
+		disableDaemonUnit(item.Path)
+		if err := os.Remove(item.Path); err != nil {
+			return err
+		}
+		item.Status = "disabled and removed"
+
+	case state.KindRCBlock:
+		if !shell.HasBlock(item.Path) {
+			item.Status = "not present"
+			return nil
+		}
+		if err := shell.RemoveBlock(item.Path); err != nil {
+			return err
+		}
+		item.Status = "removed Gas Town block"
+
+	default:
+		if _, err := os.Stat(item.Path); os.IsNotExist(err) {
+			item.Status = "not present"
+			return nil
+		}
+		if err := os.RemoveAll(item.Path); err != nil {
+			return err
+		}
+		item.Status = "removed"
+	}
+	return nil
+}
+
+// disableDaemonUnit stops a daemon unit before its file is removed.
+// Best-effort: a unit that was never loaded is fine to delete anyway.
+func disableDaemonUnit(path string) {
+	if strings.HasSuffix(path, ".plist") {
+		_ = exec.Command("launchctl", "unload", "-w", path).Run() //nolint:gosec // G204: path is a discovered unit file
+		return
+	}
+	_ = exec.Command("systemctl", "--user", "disable", "--now", filepath.Base(path)).Run() //nolint:gosec // G204: unit name from a discovered file
+}
+
+// printUninstallPlan prints one line per plan item; once items have been
+// processed, done shows each item's outcome.
+func printUninstallPlan(plan []uninstallItem, done bool) {
+	labels := map[string]string{
+		state.KindUnit:    "Daemon unit",
+		state.KindRCBlock: "Shell integration",
+		state.KindFile:    "File",
+		state.KindTown:    "Workspace",
+		"dir":             "Directory",
+	}
+	for _, item := range plan {
+		line := fmt.Sprintf("%s (%s)", labels[item.Kind], item.Path)
+		if item.Detail != "" {
+			line += style.Dim.Render(" - " + item.Detail)
+		}
+		if !done {
+			fmt.Printf("  • %s\n", line)
+			continue
+		}
+		mark := style.Success.Render("✓")
+		switch item.Status {
+		case "failed":
+			mark = style.Error.Render("✗")
+		case "not present":
+			mark = style.Dim.Render("-")
+		}
+		fmt.Printf("  %s %s: %s\n", mark, line, item.Status)
+	}
+}
+
+func hasUninstallKind(plan []uninstallItem, kind string) bool {
+	for _, item := range plan {
+		if item.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
 func findWorkspaceForUninstall() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
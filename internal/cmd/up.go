@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/output"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
@@ -52,12 +53,10 @@ aren't already running.`,
 }
 
 var (
-	upQuiet   bool
 	upRestore bool
 )
 
 func init() {
-	upCmd.Flags().BoolVarP(&upQuiet, "quiet", "q", false, "Only show errors")
 	upCmd.Flags().BoolVar(&upRestore, "restore", false, "Also restore crew (from settings) and polecats (from hooks)")
 	rootCmd.AddCommand(upCmd)
 }
@@ -67,6 +66,9 @@ func runUp(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	if err := checkNotEstopped(townRoot); err != nil {
+		return err
+	}
 
 	allOK := true
 
@@ -197,7 +199,7 @@ func runUp(cmd *cobra.Command, args []string) error {
 }
 
 func printStatus(name string, ok bool, detail string) {
-	if upQuiet && ok {
+	if output.IsQuiet() && ok {
 		return
 	}
 	if ok {
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os/exec"
+	"runtime"
 	"runtime/debug"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/version"
 )
 
@@ -18,27 +20,32 @@ var (
 	// Commit and Branch - the git revision the binary was built from (optional ldflag)
 	Commit = ""
 	Branch = ""
+	// BuildTime is when the binary was built, RFC 3339 (optional ldflag)
+	BuildTime = ""
 )
 
+var versionJSON bool
+
 var versionCmd = &cobra.Command{
 	Use:     "version",
 	GroupID: GroupDiag,
 	Short:   "Print version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		commit := resolveCommitHash()
-		branch := resolveBranch()
-
-		if commit != "" && branch != "" {
-			fmt.Printf("gt version %s (%s: %s@%s)\n", Version, Build, branch, version.ShortCommit(commit))
-		} else if commit != "" {
-			fmt.Printf("gt version %s (%s: %s)\n", Version, Build, version.ShortCommit(commit))
-		} else {
-			fmt.Printf("gt version %s (%s)\n", Version, Build)
-		}
-	},
+	Long: `Print gt's version and build metadata (commit, build date, Go version)
+and the installed bd version.
+
+A warning is printed when the installed bd is outside the range known to
+work with this gt release; 'gt doctor' and every command that needs bd
+enforce the minimum.
+
+Examples:
+  gt version
+  gt version --json`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
 }
 
 func init() {
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "Output as JSON")
 	rootCmd.AddCommand(versionCmd)
 
 	// Pass the build-time commit to the version package for stale binary checks
@@ -47,6 +54,104 @@ func init() {
 	}
 }
 
+// VersionInfo is gt's build metadata and the bd it found.
+type VersionInfo struct {
+	Version    string `json:"version"`
+	Build      string `json:"build"`
+	Commit     string `json:"commit,omitempty"`
+	Branch     string `json:"branch,omitempty"`
+	BuildTime  string `json:"build_time,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	Modified   bool   `json:"modified,omitempty"` // Built from a dirty tree
+	GoVersion  string `json:"go_version"`
+	Platform   string `json:"platform"`
+
+	BeadsVersion string `json:"bd_version,omitempty"`
+	BeadsPath    string `json:"bd_path,omitempty"`
+	BeadsError   string `json:"bd_error,omitempty"`
+	BeadsMinimum string `json:"bd_minimum"`
+	Warning      string `json:"warning,omitempty"`
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	info := collectVersionInfo()
+	if versionJSON {
+		return outputJSON(info)
+	}
+
+	if info.Commit != "" && info.Branch != "" {
+		fmt.Printf("gt version %s (%s: %s@%s)\n", info.Version, info.Build, info.Branch, version.ShortCommit(info.Commit))
+	} else if info.Commit != "" {
+		fmt.Printf("gt version %s (%s: %s)\n", info.Version, info.Build, version.ShortCommit(info.Commit))
+	} else {
+		fmt.Printf("gt version %s (%s)\n", info.Version, info.Build)
+	}
+
+	if info.Commit != "" {
+		commit := info.Commit
+		if info.Modified {
+			commit += " (modified)"
+		}
+		fmt.Printf("  Commit:  %s\n", commit)
+	}
+	switch {
+	case info.BuildTime != "":
+		fmt.Printf("  Built:   %s\n", info.BuildTime)
+	case info.CommitTime != "":
+		fmt.Printf("  Built:   %s\n", style.Dim.Render("unknown (commit from "+info.CommitTime+")"))
+	}
+	fmt.Printf("  Go:      %s %s\n", info.GoVersion, info.Platform)
+	if info.BeadsVersion != "" {
+		fmt.Printf("  bd:      %s %s\n", info.BeadsVersion, style.Dim.Render("("+info.BeadsPath+")"))
+	} else {
+		fmt.Printf("  bd:      %s\n", style.Warning.Render(info.BeadsError))
+	}
+	if info.Warning != "" {
+		fmt.Printf("\n%s %s\n", style.Warning.Render("⚠"), info.Warning)
+		fmt.Println("  Upgrade beads: go install github.com/steveyegge/beads/cmd/bd@latest")
+	}
+	return nil
+}
+
+// collectVersionInfo gathers build metadata and detects bd.
+func collectVersionInfo() *VersionInfo {
+	info := &VersionInfo{
+		Version:      Version,
+		Build:        Build,
+		Commit:       resolveCommitHash(),
+		Branch:       resolveBranch(),
+		BuildTime:    BuildTime,
+		GoVersion:    runtime.Version(),
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		BeadsMinimum: MinBeadsVersion,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.time":
+				info.CommitTime = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	path, err := exec.LookPath("bd")
+	if err != nil {
+		info.BeadsError = "not found in PATH"
+		return info
+	}
+	info.BeadsPath = path
+	bdVersion, err := getBeadsVersion()
+	if err != nil {
+		info.BeadsError = err.Error()
+		return info
+	}
+	info.BeadsVersion = bdVersion
+	info.Warning = beadsCompatWarning(Version, bdVersion)
+	return info
+}
+
 func resolveCommitHash() string {
 	if Commit != "" {
 		return Commit
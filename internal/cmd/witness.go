@@ -4,9 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/format"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -261,7 +261,7 @@ func runWitnessStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	if w.StartedAt != nil {
-		fmt.Printf("  Started: %s\n", w.StartedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("  Started: %s\n", format.DateTimeSeconds(*w.StartedAt))
 	}
 
 	// Show monitored polecats
@@ -316,16 +316,7 @@ func runWitnessAttach(cmd *cobra.Command, args []string) error {
 	}
 
 	// Attach to the session
-	tmuxPath, err := exec.LookPath("tmux")
-	if err != nil {
-		return fmt.Errorf("tmux not found: %w", err)
-	}
-
-	attachCmd := exec.Command(tmuxPath, "attach-session", "-t", sessionName)
-	attachCmd.Stdin = os.Stdin
-	attachCmd.Stdout = os.Stdout
-	attachCmd.Stderr = os.Stderr
-	return attachCmd.Run()
+	return attachToTmuxSession(sessionName)
 }
 
 func runWitnessRestart(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/heartbeat"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	witnessHeartbeatsJSON      bool
+	witnessHeartbeatsHungAfter time.Duration
+)
+
+var witnessHeartbeatsCmd = &cobra.Command{
+	Use:   "heartbeats <rig>",
+	Short: "Show which polecats are beating and which are hung",
+	Long: `Show each polecat's last heartbeat (see 'gt heartbeat') and what it means:
+
+  alive       beat within --hung-after
+  hung        working, with a session, but no beat within --hung-after
+  none        never beat - its runtime has no heartbeat wrapper; check its pane
+  idle        not working, so a missing beat means nothing
+  no session  the polecat's session is gone
+
+The witness patrol uses this instead of reading tmux panes to decide a
+polecat needs a nudge.
+
+Examples:
+  gt witness heartbeats greenplace
+  gt witness heartbeats greenplace --hung-after 20m --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessHeartbeats,
+}
+
+func init() {
+	witnessHeartbeatsCmd.Flags().BoolVar(&witnessHeartbeatsJSON, "json", false, "Output as JSON")
+	witnessHeartbeatsCmd.Flags().DurationVar(&witnessHeartbeatsHungAfter, "hung-after", heartbeat.DefaultHungAfter, "How long a working polecat can go without a beat")
+	witnessCmd.AddCommand(witnessHeartbeatsCmd)
+}
+
+// PolecatHeartbeat is a polecat's liveness as the witness sees it.
+type PolecatHeartbeat struct {
+	Name    string        `json:"name"`
+	State   polecat.State `json:"polecat_state"`
+	Session bool          `json:"session"`
+	Health  string        `json:"health"` // A heartbeat.State, "idle" or "no session"
+	Step    string        `json:"step,omitempty"`
+	Beat    *time.Time    `json:"last_beat,omitempty"`
+	Source  string        `json:"source,omitempty"`
+}
+
+func runWitnessHeartbeats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	polecats, err := polecat.NewManager(r, git.NewGit(r.Path)).List()
+	if err != nil {
+		return fmt.Errorf("listing polecats: %w", err)
+	}
+	sessions := polecat.NewSessionManager(tmux.NewTmux(), r)
+
+	now := time.Now()
+	results := make([]PolecatHeartbeat, 0, len(polecats))
+	for _, p := range polecats {
+		running, _ := sessions.IsRunning(p.Name)
+		hb, err := heartbeat.Read(townRoot, r.Name, p.Name)
+		if err != nil {
+			style.PrintWarning("%v", err)
+		}
+		result := PolecatHeartbeat{Name: p.Name, State: p.State, Session: running}
+		if hb != nil {
+			beat := hb.Beat
+			result.Step, result.Beat, result.Source = hb.Step, &beat, hb.Source
+		}
+		result.Health = polecatHealth(p.State, running, heartbeat.Assess(hb, now, witnessHeartbeatsHungAfter))
+		results = append(results, result)
+	}
+
+	if witnessHeartbeatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No polecats in %s\n", r.Name)
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("Polecat heartbeats: "+r.Name))
+	for _, p := range results {
+		icon := style.Dim.Render("○")
+		switch p.Health {
+		case string(heartbeat.StateAlive):
+			icon = style.Success.Render("●")
+		case string(heartbeat.StateHung):
+			icon = style.Error.Render("✗")
+		case string(heartbeat.StateNone):
+			icon = style.Warning.Render("?")
+		}
+		detail := "never beat"
+		if p.Beat != nil {
+			detail = fmt.Sprintf("beat %s ago", now.Sub(*p.Beat).Round(time.Second))
+			if p.Step != "" {
+				detail += " on " + p.Step
+			}
+		}
+		fmt.Printf("  %s %-16s %-10s %s\n", icon, p.Name, p.Health, style.Dim.Render(detail))
+	}
+	return nil
+}
+
+// polecatHealth is what a polecat's heartbeat means given what it is
+// doing: only a working polecat with a live session can be hung.
+func polecatHealth(state polecat.State, session bool, beat heartbeat.State) string {
+	switch {
+	case !session:
+		return "no session"
+	case state != polecat.StateWorking:
+		return "idle"
+	}
+	return string(beat)
+}
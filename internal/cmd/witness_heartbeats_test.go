@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/heartbeat"
+	"github.com/steveyegge/gastown/internal/polecat"
+)
+
+func TestPolecatHealth(t *testing.T) {
+	for _, tc := range []struct {
+		state   polecat.State
+		session bool
+		beat    heartbeat.State
+		want    string
+	}{
+		{polecat.StateWorking, true, heartbeat.StateAlive, "alive"},
+		{polecat.StateWorking, true, heartbeat.StateHung, "hung"},
+		{polecat.StateWorking, true, heartbeat.StateNone, "none"},
+		{polecat.StateDone, true, heartbeat.StateHung, "idle"},
+		{polecat.StateWorking, false, heartbeat.StateHung, "no session"},
+	} {
+		if got := polecatHealth(tc.state, tc.session, tc.beat); got != tc.want {
+			t.Errorf("polecatHealth(%s, %v, %s) = %s, want %s", tc.state, tc.session, tc.beat, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	witnessSlowStepsJSON       bool
+	witnessSlowStepsFactor     float64
+	witnessSlowStepsMinSamples int
+)
+
+var witnessSlowStepsCmd = &cobra.Command{
+	Use:   "slow-steps <rig>",
+	Short: "Flag molecule steps running far past their usual duration",
+	Long: `Compare each running molecule step in the rig against how long the same
+formula step took in past runs (see 'gt molecule runs'), and flag those
+that have taken more than --factor times the median.
+
+A step needs --min-samples finished runs before it is judged. Each slow
+step attempt is announced once with a step_slow event, which the daemon
+can send on as a notification (see 'gt config notify'); later checks
+still list it.
+
+The witness patrol runs this alongside 'gt witness heartbeats': a hung
+polecat stops beating, but a slow one can beat happily while going in
+circles.
+
+Examples:
+  gt witness slow-steps greenplace
+  gt witness slow-steps greenplace --factor 5 --min-samples 10 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessSlowSteps,
+}
+
+func init() {
+	witnessSlowStepsCmd.Flags().BoolVar(&witnessSlowStepsJSON, "json", false, "Output as JSON")
+	witnessSlowStepsCmd.Flags().Float64Var(&witnessSlowStepsFactor, "factor", molruns.DefaultSlowFactor, "Flag steps past this multiple of their median")
+	witnessSlowStepsCmd.Flags().IntVar(&witnessSlowStepsMinSamples, "min-samples", molruns.DefaultMinSamples, "Finished runs of a step needed to judge it")
+	witnessCmd.AddCommand(witnessSlowStepsCmd)
+}
+
+// SlowStep is a running molecule step the witness flagged.
+type SlowStep struct {
+	Molecule string  `json:"molecule"`
+	Formula  string  `json:"formula"`
+	Step     string  `json:"step"`
+	Ref      string  `json:"step_ref"`
+	Title    string  `json:"title,omitempty"`
+	Agent    string  `json:"agent,omitempty"`
+	Elapsed  int64   `json:"elapsed_secs"`
+	Median   int64   `json:"median_secs"`
+	Samples  int     `json:"median_samples"`
+	Factor   float64 `json:"factor"`
+	Notified bool    `json:"notified"` // Announced by this check
+}
+
+func runWitnessSlowSteps(cmd *cobra.Command, args []string) error {
+	if witnessSlowStepsFactor <= 1 {
+		return fmt.Errorf("--factor must be greater than 1")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	runs, err := molruns.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading molecule runs: %w", err)
+	}
+
+	// Baselines come from every rig's runs of a formula; only this rig's
+	// steps are judged.
+	now := time.Now()
+	results := []SlowStep{}
+	for _, slow := range molruns.SlowSteps(runs, witnessSlowStepsFactor, witnessSlowStepsMinSamples, now) {
+		if !runInRig(slow.Run, r.Name) {
+			continue
+		}
+		result := SlowStep{
+			Molecule: slow.Run.ID,
+			Formula:  slow.Run.Formula,
+			Step:     slow.Step.ID,
+			Ref:      slow.Step.Ref,
+			Title:    slow.Step.Title,
+			Agent:    slow.Step.Agent,
+			Elapsed:  int64(slow.Elapsed.Seconds()),
+			Median:   int64(slow.Baseline.Median.Seconds()),
+			Samples:  slow.Baseline.Samples,
+			Factor:   slow.Factor(),
+		}
+		if result.Agent == "" {
+			result.Agent = slow.Run.Target
+		}
+		if slow.Step.Slow.IsZero() && !dryrun.Enabled() {
+			announceSlowStep(townRoot, r.Name, slow, result.Agent, now)
+			result.Notified = true
+		}
+		results = append(results, result)
+	}
+
+	if witnessSlowStepsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No slow steps in %s\n", r.Name)
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("Slow molecule steps: "+r.Name))
+	for _, s := range results {
+		name := s.Ref
+		if s.Title != "" {
+			name = s.Title
+		}
+		fmt.Printf("  %s %s %s  %s\n", style.Warning.Render("⚠"), s.Molecule, name, style.Dim.Render(s.Agent))
+		detail := fmt.Sprintf("running %s, %.1fx its median of %s over %d run(s)",
+			formatDuration(time.Duration(s.Elapsed)*time.Second), s.Factor,
+			formatDuration(time.Duration(s.Median)*time.Second), s.Samples)
+		if s.Notified {
+			detail += ", announced"
+		}
+		fmt.Printf("    %s\n", style.Dim.Render(detail))
+	}
+	return nil
+}
+
+// announceSlowStep logs the step_slow event for a slow step attempt and
+// marks the attempt, so the next patrol doesn't announce it again.
+func announceSlowStep(townRoot, rigName string, slow molruns.Slow, agent string, now time.Time) {
+	_ = events.LogFeed(events.TypeStepSlow, rigName+"/witness", events.StepSlowPayload(
+		slow.Run.ID, slow.Step.ID, slow.Step.Ref, slow.Run.Formula, agent, slow.Elapsed, slow.Baseline.Median, slow.Baseline.Samples))
+	started := slow.Step.Started
+	updateMoleculeRun(townRoot, slow.Run.ID, func(r *molruns.Run) {
+		for i := range r.Steps {
+			if r.Steps[i].ID == slow.Step.ID && r.Steps[i].Started.Equal(started) {
+				r.Steps[i].Slow = now
+			}
+		}
+	})
+}
+
+// runInRig reports whether a molecule run is working in rigName.
+func runInRig(r *molruns.Run, rigName string) bool {
+	return r.Rig == rigName || strings.HasPrefix(r.Target, rigName+"/")
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,6 +11,8 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -83,6 +86,37 @@ Examples:
 	RunE: runWorktreeRemove,
 }
 
+// Worktree gc command flags
+var (
+	worktreeGCUnmerged bool
+)
+
+var worktreeGCCmd = &cobra.Command{
+	Use:   "gc [rig]",
+	Short: "Clean up polecat worktrees and branches left behind",
+	Long: `Clean up after polecats in one rig, or in every rig.
+
+Polecats work in git worktrees off the rig's shared bare repo, each on a
+branch named polecat/<name>/<bead-id>. Removing a polecat deletes its
+branch once the branch is merged; this command catches what is left:
+
+  - worktree records whose directories are gone are pruned
+  - polecat branches merged into the default branch that no worktree
+    has checked out are deleted
+
+Unmerged branches are kept and listed unless --unmerged is given (the
+refinery merges from the copies polecats push, so local ones are
+expendable once their work is merged or abandoned).
+
+Examples:
+  gt worktree gc                  # Every rig
+  gt worktree gc gastown          # One rig
+  gt worktree gc --unmerged       # Also delete unmerged orphaned branches
+  gt --dry-run worktree gc        # Show what would be deleted`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorktreeGC,
+}
+
 func init() {
 	worktreeCmd.Flags().BoolVar(&worktreeNoCD, "no-cd", false, "Just print path (don't print cd command)")
 	worktreeCmd.AddCommand(worktreeListCmd)
@@ -90,6 +124,9 @@ func init() {
 	worktreeRemoveCmd.Flags().BoolVarP(&worktreeRemoveForce, "force", "f", false, "Force remove even with uncommitted changes")
 	worktreeCmd.AddCommand(worktreeRemoveCmd)
 
+	worktreeGCCmd.Flags().BoolVar(&worktreeGCUnmerged, "unmerged", false, "Also delete unmerged branches no worktree has checked out")
+	worktreeCmd.AddCommand(worktreeGCCmd)
+
 	rootCmd.AddCommand(worktreeCmd)
 }
 
@@ -338,3 +375,46 @@ func runWorktreeRemove(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runWorktreeGC(cmd *cobra.Command, args []string) error {
+	var rigs []*rig.Rig
+	if len(args) == 1 {
+		_, r, err := getRig(args[0])
+		if err != nil {
+			return err
+		}
+		rigs = append(rigs, r)
+	} else {
+		all, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		rigs = all
+	}
+
+	var errs []error
+	deleted, kept := 0, 0
+	for _, r := range rigs {
+		result, err := polecat.NewManager(r, git.NewGit(r.Path)).GarbageCollectWorktrees(worktreeGCUnmerged)
+		if err != nil {
+			fmt.Printf("  %s %s: %v\n", style.Warning.Render("⚠"), r.Name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", r.Name, err))
+			continue
+		}
+		for _, branch := range result.Deleted {
+			fmt.Printf("  %s %s: deleted %s\n", style.Bold.Render("✓"), r.Name, branch)
+		}
+		for _, branch := range result.Kept {
+			fmt.Printf("  %s %s: kept %s %s\n", style.Dim.Render("○"), r.Name, branch, style.Dim.Render("(unmerged)"))
+		}
+		deleted += len(result.Deleted)
+		kept += len(result.Kept)
+	}
+
+	fmt.Printf("\nDeleted %d branch(es) across %d rig(s)", deleted, len(rigs))
+	if kept > 0 {
+		fmt.Printf("; kept %d unmerged (use --unmerged to delete them)", kept)
+	}
+	fmt.Println()
+	return errors.Join(errs...)
+}
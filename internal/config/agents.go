@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+
+	"github.com/steveyegge/gastown/internal/dryrun"
 )
 
 // AgentPreset identifies a supported LLM agent runtime.
@@ -432,7 +434,7 @@ func IsKnownPreset(name string) bool {
 
 // SaveAgentRegistry writes the agent registry to a file.
 func SaveAgentRegistry(path string, registry *AgentRegistry) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
 
@@ -441,7 +443,7 @@ func SaveAgentRegistry(path string, registry *AgentRegistry) error {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: config file
+	return dryrun.WriteFile(path, data, 0644) //nolint:gosec // G306: config file
 }
 
 // NewExampleAgentRegistry creates an example registry with comments.
@@ -6,6 +6,8 @@ import (
 	"os"
 	"sort"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/authz"
 )
 
 // AgentEnvConfig specifies the configuration for generating agent environment variables.
@@ -90,6 +92,14 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		env["BEADS_DIR"] = cfg.BeadsDir
 	}
 
+	// Sign the role so gt can trust it over GT_ROLE (see internal/authz).
+	// Without a readable town there is no key; the agent runs unsigned.
+	if cfg.TownRoot != "" && cfg.Role != "" {
+		if token, err := authz.Sign(cfg.TownRoot, authz.Identity{Role: cfg.Role, Rig: cfg.Rig, Agent: cfg.AgentName}); err == nil {
+			env[authz.EnvToken] = token
+		}
+	}
+
 	// Set BEADS_AGENT_NAME for polecat/crew (uses same format as BD_ACTOR)
 	if cfg.Role == "polecat" || cfg.Role == "crew" {
 		env["BEADS_AGENT_NAME"] = fmt.Sprintf("%s/%s", cfg.Rig, cfg.AgentName)
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/format"
+)
+
+// Provenance sources for effective settings, lowest precedence first.
+// Settings have no layer shared across towns (an org base), so none is
+// reported.
+const (
+	SourceDefault = "default" // built-in default
+	SourceTown    = "town"    // <town>/settings/config.json
+	SourceRig     = "rig"     // <rig>/settings/config.json
+	SourceEnv     = "env"     // environment variable
+	SourceFlag    = "flag"    // command-line flag
+)
+
+// ExplainedSetting is one effective configuration value and where it came from.
+type ExplainedSetting struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+	Origin string      `json:"origin,omitempty"` // file path, env var, or flag name
+}
+
+// ExplainOptions selects the scope to explain.
+type ExplainOptions struct {
+	TownRoot    string
+	RigPath     string // optional; adds rig settings and rig-level overrides
+	AgentFlag   string // value of --agent, if any
+	AccountFlag string // value of --account, if any
+}
+
+// explainRoles are the roles whose agent assignments are resolved.
+var explainRoles = []string{"mayor", "deacon", "witness", "refinery", "polecat", "crew"}
+
+// ExplainConfig returns every effective setting for a town (and optionally
+// a rig) with its provenance, following the same precedence as the
+// resolvers used at runtime: flag > env > rig file > town file > default.
+// Values whose key suggests a secret are redacted.
+func ExplainConfig(opts ExplainOptions) ([]ExplainedSetting, error) {
+	settings := make(map[string]ExplainedSetting)
+	set := func(key string, value interface{}, source, origin string) {
+		settings[key] = ExplainedSetting{Key: key, Value: redact(key, value), Source: source, Origin: origin}
+	}
+
+	// Town settings: defaults, then the file
+	townPath := TownSettingsPath(opts.TownRoot)
+	townSettings, err := LoadOrCreateTownSettings(townPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	townRaw, err := fileLayer(townPath, NewTownSettings(), townSettings)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range townRaw.defaults {
+		set(k, v, SourceDefault, "")
+	}
+	for k, v := range townRaw.file {
+		set(k, v, SourceTown, townPath)
+	}
+
+	// Rig settings: defaults, then the file (rig keys shadow town keys)
+	var rigSettings *RigSettings
+	rigPath := ""
+	if opts.RigPath != "" {
+		rigPath = RigSettingsPath(opts.RigPath)
+		rigSettings, err = LoadRigSettings(rigPath)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				return nil, fmt.Errorf("loading rig settings: %w", err)
+			}
+			rigSettings = nil
+		}
+		var loaded interface{} = rigSettings
+		if rigSettings == nil {
+			loaded = &RigSettings{}
+		}
+		rigRaw, err := fileLayer(rigPath, NewRigSettings(), loaded)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range rigRaw.defaults {
+			set(k, v, SourceDefault, "")
+		}
+		for k, v := range rigRaw.file {
+			set(k, v, SourceRig, rigPath)
+		}
+	}
+
+	// Effective agent: --agent > rig agent > town default_agent > claude
+	switch {
+	case opts.AgentFlag != "":
+		set("agent", opts.AgentFlag, SourceFlag, "--agent")
+	case rigSettings != nil && rigSettings.Agent != "":
+		set("agent", rigSettings.Agent, SourceRig, rigPath)
+	case townSettings.DefaultAgent != "" && townRaw.file["default_agent"] != nil:
+		set("agent", townSettings.DefaultAgent, SourceTown, townPath)
+	default:
+		set("agent", "claude", SourceDefault, "")
+	}
+
+	// Effective per-role agents: rig role_agents > town role_agents > agent
+	agent := settings["agent"]
+	for _, role := range explainRoles {
+		key := "role_agents." + role
+		switch {
+		case rigSettings != nil && rigSettings.RoleAgents[role] != "":
+			set(key, rigSettings.RoleAgents[role], SourceRig, rigPath)
+		case townSettings.RoleAgents[role] != "":
+			set(key, townSettings.RoleAgents[role], SourceTown, townPath)
+		default:
+			set(key, agent.Value, agent.Source, agent.Origin)
+		}
+	}
+
+	// Locale: GT_LOCALE > town locale > default
+	switch {
+	case os.Getenv("GT_LOCALE") != "":
+		set("locale", os.Getenv("GT_LOCALE"), SourceEnv, "GT_LOCALE")
+	case townSettings.Locale != "":
+		set("locale", townSettings.Locale, SourceTown, townPath)
+	default:
+		set("locale", format.DefaultTag, SourceDefault, "")
+	}
+
+	// Account: GT_ACCOUNT > --account > accounts.json default
+	accountsPath := constants.MayorAccountsPath(opts.TownRoot)
+	switch {
+	case os.Getenv("GT_ACCOUNT") != "":
+		set("account", os.Getenv("GT_ACCOUNT"), SourceEnv, "GT_ACCOUNT")
+	case opts.AccountFlag != "":
+		set("account", opts.AccountFlag, SourceFlag, "--account")
+	default:
+		if accounts, err := LoadAccountsConfig(accountsPath); err == nil && accounts.Default != "" {
+			set("account", accounts.Default, SourceTown, accountsPath)
+		}
+	}
+
+	result := make([]ExplainedSetting, 0, len(settings))
+	for _, s := range settings {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+// layerValues are the flattened keys of one settings file, split by
+// whether the file sets them.
+type layerValues struct {
+	defaults map[string]interface{} // keys the file leaves to built-in defaults
+	file     map[string]interface{} // keys whose value comes from the file
+}
+
+// fileLayer attributes each effective key of a settings file. A top-level
+// section present in the file replaces its defaults wholesale (JSON decoding
+// leaves omitted fields zero), so such sections report the loaded values;
+// absent sections report the built-in defaults.
+func fileLayer(path string, defaults, loaded interface{}) (*layerValues, error) {
+	present := make(map[string]bool)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		for k := range raw {
+			present[k] = true
+		}
+	}
+
+	defaultKeys, err := flattenJSON(defaults)
+	if err != nil {
+		return nil, err
+	}
+	loadedKeys, err := flattenJSON(loaded)
+	if err != nil {
+		return nil, err
+	}
+
+	lv := &layerValues{defaults: make(map[string]interface{}), file: make(map[string]interface{})}
+	for k, v := range defaultKeys {
+		if !present[topLevel(k)] {
+			lv.defaults[k] = v
+		}
+	}
+	for k, v := range loadedKeys {
+		if present[topLevel(k)] {
+			lv.file[k] = v
+		}
+	}
+	return lv, nil
+}
+
+func topLevel(key string) string {
+	if i := strings.Index(key, "."); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// flattenJSON flattens a struct through its JSON encoding.
+func flattenJSON(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{})
+	flatten("", raw, out)
+	return out, nil
+}
+
+// flatten writes nested objects as dotted keys. Arrays are leaves; empty
+// objects and the type/version header are skipped.
+func flatten(prefix string, m map[string]interface{}, out map[string]interface{}) {
+	for k, v := range m {
+		if prefix == "" && (k == "type" || k == "version") {
+			continue
+		}
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			flatten(key, nested, out)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+// redact hides values of keys that typically hold secrets (webhook URLs,
+// tokens), so explain output is safe to paste into bug reports.
+func redact(key string, value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return value
+	}
+	lower := strings.ToLower(key)
+	last := lower[strings.LastIndex(lower, ".")+1:]
+	if last == "url" || strings.Contains(last, "token") || strings.Contains(last, "secret") || strings.Contains(last, "webhook") {
+		return "<redacted>"
+	}
+	return value
+}
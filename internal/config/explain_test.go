@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExplainConfigProvenance(t *testing.T) {
+	t.Setenv("GT_LOCALE", "")
+	t.Setenv("GT_ACCOUNT", "")
+
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "myrig")
+	writeSettings := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSettings(TownSettingsPath(townRoot), `{"type":"town-settings","version":1,"default_agent":"gemini","role_agents":{"witness":"claude-haiku"},"locale":"de-DE"}`)
+	writeSettings(RigSettingsPath(rigPath), `{"type":"rig-settings","version":1,"role_agents":{"witness":"codex"},"merge_queue":{"enabled":true,"strategy":"github-pr"},"theme":{"name":"ocean"}}`)
+
+	get := func(settings []ExplainedSetting, key string) ExplainedSetting {
+		t.Helper()
+		for _, s := range settings {
+			if s.Key == key {
+				return s
+			}
+		}
+		t.Fatalf("key %q not explained", key)
+		return ExplainedSetting{}
+	}
+
+	settings, err := ExplainConfig(ExplainOptions{TownRoot: townRoot, RigPath: rigPath})
+	if err != nil {
+		t.Fatalf("ExplainConfig: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		value  interface{}
+		source string
+	}{
+		{"agent", "gemini", SourceTown},
+		{"role_agents.witness", "codex", SourceRig},
+		{"role_agents.polecat", "gemini", SourceTown},
+		{"locale", "de-DE", SourceTown},
+		{"merge_queue.strategy", "github-pr", SourceRig},
+		{"merge_queue.run_tests", false, SourceRig}, // section present: omitted fields are zero
+		{"namepool.style", "mad-max", SourceDefault},
+	}
+	for _, tt := range tests {
+		got := get(settings, tt.key)
+		if got.Value != tt.value || got.Source != tt.source {
+			t.Errorf("%s = %v [%s], want %v [%s]", tt.key, got.Value, got.Source, tt.value, tt.source)
+		}
+	}
+
+	// Flags and environment take precedence over files
+	t.Setenv("GT_LOCALE", "fr-FR")
+	settings, err = ExplainConfig(ExplainOptions{TownRoot: townRoot, RigPath: rigPath, AgentFlag: "amp"})
+	if err != nil {
+		t.Fatalf("ExplainConfig: %v", err)
+	}
+	if got := get(settings, "agent"); got.Value != "amp" || got.Source != SourceFlag {
+		t.Errorf("agent = %v [%s], want amp [flag]", got.Value, got.Source)
+	}
+	if got := get(settings, "role_agents.mayor"); got.Value != "amp" {
+		t.Errorf("role_agents.mayor = %v, want inherited amp", got.Value)
+	}
+	if got := get(settings, "locale"); got.Value != "fr-FR" || got.Origin != "GT_LOCALE" {
+		t.Errorf("locale = %v [%s], want fr-FR from GT_LOCALE", got.Value, got.Origin)
+	}
+}
+
+func TestExplainConfigRedactsSecrets(t *testing.T) {
+	townRoot := t.TempDir()
+	path := TownSettingsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"type":"town-settings","version":1,"notifications":{"sinks":{"ops":{"type":"slack","url":"https://hooks.slack.com/secret"}}}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := ExplainConfig(ExplainOptions{TownRoot: townRoot})
+	if err != nil {
+		t.Fatalf("ExplainConfig: %v", err)
+	}
+	for _, s := range settings {
+		if s.Key == "notifications.sinks.ops.url" {
+			if s.Value != "<redacted>" {
+				t.Errorf("webhook url not redacted: %v", s.Value)
+			}
+			return
+		}
+	}
+	t.Error("notifications.sinks.ops.url not explained")
+}
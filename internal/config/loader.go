@@ -10,7 +10,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/authz"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 var (
@@ -55,7 +58,7 @@ func SaveTownConfig(path string, config *TownConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
@@ -64,7 +67,7 @@ func SaveTownConfig(path string, config *TownConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := dryrun.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -99,7 +102,7 @@ func SaveRigsConfig(path string, config *RigsConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
@@ -108,7 +111,7 @@ func SaveRigsConfig(path string, config *RigsConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := dryrun.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -168,7 +171,7 @@ func SaveRigConfig(path string, config *RigConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
@@ -177,7 +180,7 @@ func SaveRigConfig(path string, config *RigConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+	if err := dryrun.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -211,12 +214,58 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Sandbox != nil {
+		if err := validateSandboxConfig(c.Sandbox); err != nil {
+			return err
+		}
+	}
+	if c.Container != nil {
+		if err := validateContainerConfig(c.Container); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrInvalidSandbox indicates an invalid sandbox profile.
+var ErrInvalidSandbox = errors.New("invalid sandbox config")
+
+// validateSandboxConfig validates sandbox limits. The profile name is
+// checked when a polecat starts, against the backends built into gt.
+func validateSandboxConfig(c *SandboxConfig) error {
+	if c.MemoryMB < 0 || c.CPUs < 0 || c.MaxProcs < 0 {
+		return fmt.Errorf("%w: limits must not be negative", ErrInvalidSandbox)
+	}
+	return nil
+}
+
+// ErrInvalidContainer indicates an invalid container runtime config.
+var ErrInvalidContainer = errors.New("invalid container config")
+
+// validateContainerConfig validates the container runtime config.
+func validateContainerConfig(c *ContainerConfig) error {
+	if c.Image == "" {
+		return fmt.Errorf("%w: image is required", ErrInvalidContainer)
+	}
+	switch c.Engine {
+	case "", "docker", "podman":
+	default:
+		return fmt.Errorf("%w: unknown engine %q (use docker or podman)", ErrInvalidContainer, c.Engine)
+	}
+	for _, m := range c.Mounts {
+		if parts := strings.Split(m, ":"); len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("%w: mount %q is not host:container[:ro]", ErrInvalidContainer, m)
+		}
+	}
 	return nil
 }
 
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
+// ErrInvalidMergeStrategy indicates an invalid merge_queue strategy.
+var ErrInvalidMergeStrategy = errors.New("invalid merge strategy")
+
 // validateMergeQueueConfig validates a MergeQueueConfig.
 func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	// Validate on_conflict strategy
@@ -225,6 +274,12 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 			ErrInvalidOnConflict, c.OnConflict, OnConflictAssignBack, OnConflictAutoRebase)
 	}
 
+	// Validate merge strategy
+	if c.Strategy != "" && c.Strategy != MergeStrategyLocal && c.Strategy != MergeStrategyGitHubPR {
+		return fmt.Errorf("%w: got '%s', want '%s' or '%s'",
+			ErrInvalidMergeStrategy, c.Strategy, MergeStrategyLocal, MergeStrategyGitHubPR)
+	}
+
 	// Validate poll_interval if specified
 	if c.PollInterval != "" {
 		if _, err := time.ParseDuration(c.PollInterval); err != nil {
@@ -291,7 +346,7 @@ func SaveRigSettings(path string, settings *RigSettings) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
@@ -300,7 +355,7 @@ func SaveRigSettings(path string, settings *RigSettings) error {
 		return fmt.Errorf("encoding settings: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+	if err := dryrun.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
 		return fmt.Errorf("writing settings: %w", err)
 	}
 
@@ -335,7 +390,7 @@ func SaveMayorConfig(path string, config *MayorConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
@@ -344,7 +399,7 @@ func SaveMayorConfig(path string, config *MayorConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+	if err := dryrun.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -403,7 +458,7 @@ func SaveDaemonPatrolConfig(path string, config *DaemonPatrolConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
@@ -412,7 +467,7 @@ func SaveDaemonPatrolConfig(path string, config *DaemonPatrolConfig) error {
 		return fmt.Errorf("encoding daemon patrol config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+	if err := dryrun.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
 		return fmt.Errorf("writing daemon patrol config: %w", err)
 	}
 
@@ -469,7 +524,7 @@ func SaveAccountsConfig(path string, config *AccountsConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
@@ -478,7 +533,7 @@ func SaveAccountsConfig(path string, config *AccountsConfig) error {
 		return fmt.Errorf("encoding accounts config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: accounts config doesn't contain sensitive credentials
+	if err := dryrun.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: accounts config doesn't contain sensitive credentials
 		return fmt.Errorf("writing accounts config: %w", err)
 	}
 
@@ -579,13 +634,7 @@ func ResolveAccountConfigDir(accountsPath, accountFlag string) (configDir, handl
 
 // expandPath expands ~ to home directory.
 func expandPath(path string) string {
-	if strings.HasPrefix(path, "~/") {
-		home, err := os.UserHomeDir()
-		if err == nil {
-			return filepath.Join(home, path[2:])
-		}
-	}
-	return path
+	return util.ExpandHome(path)
 }
 
 // LoadMessagingConfig loads and validates a messaging configuration file.
@@ -616,7 +665,7 @@ func SaveMessagingConfig(path string, config *MessagingConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
@@ -625,7 +674,7 @@ func SaveMessagingConfig(path string, config *MessagingConfig) error {
 		return fmt.Errorf("encoding messaging config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: messaging config doesn't contain secrets
+	if err := dryrun.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: messaging config doesn't contain secrets
 		return fmt.Errorf("writing messaging config: %w", err)
 	}
 
@@ -765,7 +814,7 @@ func SaveTownSettings(path string, settings *TownSettings) error {
 		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, settings.Version, CurrentTownSettingsVersion)
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
@@ -774,7 +823,7 @@ func SaveTownSettings(path string, settings *TownSettings) error {
 		return fmt.Errorf("encoding settings: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+	if err := dryrun.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
 		return fmt.Errorf("writing settings: %w", err)
 	}
 
@@ -1180,12 +1229,14 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
 		resolvedEnv["GT_SESSION_ID_ENV"] = rc.Session.SessionIDEnv
 	}
+	addRoleToken(resolvedEnv, townRoot, rigPath)
 
 	// Build environment export prefix
 	var exports []string
 	for k, v := range resolvedEnv {
 		exports = append(exports, fmt.Sprintf("%s=%s", k, v))
 	}
+	exports = withRigEnv(exports, resolvedEnv, rigPath)
 
 	// Sort for deterministic output
 	sort.Strings(exports)
@@ -1205,6 +1256,33 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	return cmd
 }
 
+// addRoleToken adds a signed token for env's GT_ROLE (see internal/authz),
+// unless env already has one. tmux SetEnvironment doesn't reach a
+// session's first pane, so the startup command has to export the token,
+// as it does GT_ROLE. The rig comes from GT_RIG or rigPath, the agent from
+// GT_POLECAT or GT_CREW. Outside a town (no mayor/town.json) nothing is
+// signed, so no key is created there.
+func addRoleToken(env map[string]string, townRoot, rigPath string) {
+	role := env["GT_ROLE"]
+	if role == "" || townRoot == "" || env[authz.EnvToken] != "" {
+		return
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, "mayor", "town.json")); err != nil {
+		return
+	}
+	rig := env["GT_RIG"]
+	if rig == "" && rigPath != "" {
+		rig = filepath.Base(rigPath)
+	}
+	agent := env["GT_POLECAT"]
+	if agent == "" {
+		agent = env["GT_CREW"]
+	}
+	if token, err := authz.Sign(townRoot, authz.Identity{Role: role, Rig: rig, Agent: agent}); err == nil {
+		env[authz.EnvToken] = token
+	}
+}
+
 // PrependEnv prepends export statements to a command string.
 func PrependEnv(command string, envVars map[string]string) string {
 	if len(envVars) == 0 {
@@ -1224,16 +1302,18 @@ func PrependEnv(command string, envVars map[string]string) string {
 // but uses agentOverride if non-empty.
 func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, prompt, agentOverride string) (string, error) {
 	var rc *RuntimeConfig
+	var townRoot string
 
 	if rigPath != "" {
-		townRoot := filepath.Dir(rigPath)
+		townRoot = filepath.Dir(rigPath)
 		var err error
 		rc, _, err = ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride)
 		if err != nil {
 			return "", err
 		}
 	} else {
-		townRoot, err := findTownRootFromCwd()
+		var err error
+		townRoot, err = findTownRootFromCwd()
 		if err != nil {
 			rc = DefaultRuntimeConfig()
 		} else {
@@ -1245,11 +1325,19 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 		}
 	}
 
+	// Copy env vars to avoid mutating caller map
+	resolvedEnv := make(map[string]string, len(envVars)+1)
+	for k, v := range envVars {
+		resolvedEnv[k] = v
+	}
+	addRoleToken(resolvedEnv, townRoot, rigPath)
+
 	// Build environment export prefix
 	var exports []string
-	for k, v := range envVars {
+	for k, v := range resolvedEnv {
 		exports = append(exports, fmt.Sprintf("%s=%s", k, v))
 	}
+	exports = withRigEnv(exports, resolvedEnv, rigPath)
 	sort.Strings(exports)
 
 	var cmd string
@@ -1338,6 +1426,16 @@ func GetDefaultFormula(rigPath string) string {
 	return settings.Workflow.DefaultFormula
 }
 
+// GetReviewLinters returns the review linters for a rig from settings/config.json.
+// Returns nil if none are configured.
+func GetReviewLinters(rigPath string) []string {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil || settings.Workflow == nil {
+		return nil
+	}
+	return settings.Workflow.ReviewLinters
+}
+
 // GetRigPrefix returns the beads prefix for a rig from rigs.json.
 // Falls back to "gt" if the rig isn't found or has no prefix configured.
 // townRoot is the path to the town directory (e.g., ~/gt).
@@ -1407,7 +1505,7 @@ func SaveEscalationConfig(path string, config *EscalationConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
@@ -1416,7 +1514,7 @@ func SaveEscalationConfig(path string, config *EscalationConfig) error {
 		return fmt.Errorf("encoding escalation config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: escalation config doesn't contain secrets
+	if err := dryrun.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: escalation config doesn't contain secrets
 		return fmt.Errorf("writing escalation config: %w", err)
 	}
 
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -220,6 +221,28 @@ func TestRigSettingsWithCustomMergeQueue(t *testing.T) {
 	}
 }
 
+func TestMergeQueueStrategyValidation(t *testing.T) {
+	t.Parallel()
+	for _, strategy := range []string{"", MergeStrategyLocal, MergeStrategyGitHubPR} {
+		if err := validateMergeQueueConfig(&MergeQueueConfig{Strategy: strategy}); err != nil {
+			t.Errorf("strategy %q: unexpected error %v", strategy, err)
+		}
+	}
+
+	err := validateMergeQueueConfig(&MergeQueueConfig{Strategy: "gitlab-mr"})
+	if !errors.Is(err, ErrInvalidMergeStrategy) {
+		t.Errorf("strategy gitlab-mr: err = %v, want ErrInvalidMergeStrategy", err)
+	}
+
+	if !(&MergeQueueConfig{Strategy: MergeStrategyGitHubPR}).UsesGitHubPR() {
+		t.Error("UsesGitHubPR() = false for github-pr strategy")
+	}
+	var nilCfg *MergeQueueConfig
+	if nilCfg.UsesGitHubPR() {
+		t.Error("UsesGitHubPR() = true for nil config")
+	}
+}
+
 func TestRigConfigValidation(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
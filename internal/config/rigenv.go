@@ -0,0 +1,216 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Secret reference schemes for rig env values.
+const (
+	SecretEnv      = "env"
+	SecretKeychain = "keychain"
+	SecretSops     = "sops"
+	SecretFile     = "file"
+)
+
+// SecretSchemes lists the reference schemes, in the order they are
+// documented.
+var SecretSchemes = []string{SecretEnv, SecretKeychain, SecretSops, SecretFile}
+
+// RigEnvVar is one resolved rig env variable.
+type RigEnvVar struct {
+	Name   string `json:"name"`
+	Value  string `json:"-"`
+	Source string `json:"source"` // "literal", or the secret reference
+	Error  string `json:"error,omitempty"`
+}
+
+// Secret reports whether the value came from a secret reference.
+func (v RigEnvVar) Secret() bool {
+	return v.Source != "literal"
+}
+
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidEnvName reports whether name can be an environment variable.
+func ValidEnvName(name string) bool {
+	return envNameRe.MatchString(name)
+}
+
+// ResolveRigEnv returns the rig's env settings with secret references
+// read, or nil when the rig sets none. Every variable is resolved; the
+// error joins those that couldn't be.
+func ResolveRigEnv(rigPath string) (map[string]string, error) {
+	vars, err := RigEnvVars(rigPath)
+	if err != nil || len(vars) == 0 {
+		return nil, err
+	}
+	env := make(map[string]string, len(vars))
+	var errs []error
+	for _, v := range vars {
+		if v.Error != "" {
+			errs = append(errs, fmt.Errorf("%s: %s", v.Name, v.Error))
+			continue
+		}
+		env[v.Name] = v.Value
+	}
+	return env, errors.Join(errs...)
+}
+
+// RigEnvVars resolves the rig's env settings one by one, sorted by name,
+// recording each variable's failure rather than stopping at it.
+func RigEnvVars(rigPath string) ([]RigEnvVar, error) {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(settings.Env))
+	for name := range settings.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	vars := make([]RigEnvVar, 0, len(names))
+	for _, name := range names {
+		ref := settings.Env[name]
+		v := RigEnvVar{Name: name, Source: "literal", Value: ref}
+		if scheme, _, ok := strings.Cut(ref, ":"); ok && isSecretScheme(scheme) {
+			v.Source = ref
+			v.Value, err = resolveSecret(rigPath, ref)
+		} else {
+			err = nil
+		}
+		if err == nil && !ValidEnvName(name) {
+			err = fmt.Errorf("not a valid variable name")
+		}
+		if err != nil {
+			v.Value, v.Error = "", err.Error()
+		}
+		vars = append(vars, v)
+	}
+	return vars, nil
+}
+
+func isSecretScheme(scheme string) bool {
+	for _, s := range SecretSchemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSecret reads a secret reference.
+func resolveSecret(rigPath, ref string) (string, error) {
+	scheme, rest, _ := strings.Cut(ref, ":")
+	if rest == "" {
+		return "", fmt.Errorf("%s reference names nothing", scheme)
+	}
+	rigFile := func(path string) string {
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(rigPath, path)
+	}
+
+	switch scheme {
+	case SecretEnv:
+		v, ok := os.LookupEnv(rest)
+		if !ok {
+			return "", fmt.Errorf("%s is not set in gt's environment", rest)
+		}
+		return v, nil
+	case SecretKeychain:
+		return keychainSecret(rest)
+	case SecretSops:
+		file, key, _ := strings.Cut(rest, "#")
+		return sopsSecret(rigFile(file), key)
+	case SecretFile:
+		data, err := os.ReadFile(rigFile(rest)) //nolint:gosec // G304: path is from trusted rig settings
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default:
+		return "", fmt.Errorf("unknown secret scheme %q", scheme)
+	}
+}
+
+// keychainSecret reads a named secret from the system keychain, stored
+// under service "gastown". It is a variable so tests don't touch the
+// real keychain.
+var keychainSecret = func(name string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", "gastown", "-a", name, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", "gastown", "account", name)
+	default:
+		return "", fmt.Errorf("no keychain support on %s", runtime.GOOS)
+	}
+	return secretCommand(cmd)
+}
+
+// sopsSecret decrypts a sops file, or one key of it. It is a variable so
+// tests don't need sops.
+var sopsSecret = func(file, key string) (string, error) {
+	args := []string{"--decrypt"}
+	if key != "" {
+		args = append(args, "--extract", fmt.Sprintf("[%q]", key))
+	}
+	return secretCommand(exec.Command("sops", append(args, file)...)) //nolint:gosec // G204: file is from trusted rig settings
+}
+
+// secretCommand runs a secret store's CLI and returns its output, without
+// the trailing newline.
+func secretCommand(cmd *exec.Cmd) (string, error) {
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %s", filepath.Base(cmd.Path), msg)
+		}
+		return "", fmt.Errorf("%s: %w", filepath.Base(cmd.Path), err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// withRigEnv adds the rig's env to exports ("NAME=value" words), leaving
+// variables gt sets itself alone. A rig env that can't be fully resolved
+// is reported and the rest is used: the session still starts, and the
+// missing variables show up in gt rig env.
+func withRigEnv(exports []string, env map[string]string, rigPath string) []string {
+	if rigPath == "" {
+		return exports
+	}
+	rigEnv, err := ResolveRigEnv(rigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: rig env for %s: %v\n", filepath.Base(rigPath), err)
+	}
+	for name, value := range rigEnv {
+		if _, set := env[name]; !set {
+			exports = append(exports, name+"="+shellWord(value))
+		}
+	}
+	return exports
+}
+
+// shellWord quotes s for the shell unless it is safe bare.
+func shellWord(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-./:@%+=,") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
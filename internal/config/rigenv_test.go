@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRigEnvVars(t *testing.T) {
+	rigPath := filepath.Join(t.TempDir(), "testrig")
+	settings := NewRigSettings()
+	settings.Env = map[string]string{
+		"DATABASE_URL": "postgres://localhost/app test",
+		"FROM_ENV":     "env:GT_RIGENV_TEST",
+		"MISSING":      "env:GT_RIGENV_UNSET",
+		"LICENSE":      "file:license.txt",
+		"API_TOKEN":    "sops:secrets.enc.yaml#api_token",
+		"STRIPE_KEY":   "keychain:stripe-test",
+		"URL":          "https://example.com", // not a secret scheme
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigPath, "license.txt"), []byte("lic-123\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GT_RIGENV_TEST", "from-env")
+
+	origSops, origKeychain := sopsSecret, keychainSecret
+	defer func() { sopsSecret, keychainSecret = origSops, origKeychain }()
+	sopsSecret = func(file, key string) (string, error) {
+		if file != filepath.Join(rigPath, "secrets.enc.yaml") || key != "api_token" {
+			t.Errorf("sops(%s, %s): wrong file or key", file, key)
+		}
+		return "tok-456", nil
+	}
+	keychainSecret = func(name string) (string, error) { return "", errors.New("not found") }
+
+	vars, err := RigEnvVars(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]RigEnvVar)
+	for _, v := range vars {
+		got[v.Name] = v
+	}
+	want := map[string]string{
+		"DATABASE_URL": "postgres://localhost/app test",
+		"FROM_ENV":     "from-env",
+		"LICENSE":      "lic-123",
+		"API_TOKEN":    "tok-456",
+		"URL":          "https://example.com",
+	}
+	for name, value := range want {
+		if v := got[name]; v.Value != value || v.Error != "" {
+			t.Errorf("%s = %q (%s), want %q", name, v.Value, v.Error, value)
+		}
+	}
+	if got["URL"].Secret() || !got["LICENSE"].Secret() {
+		t.Error("only scheme references are secrets")
+	}
+	for _, name := range []string{"MISSING", "STRIPE_KEY"} {
+		if got[name].Error == "" {
+			t.Errorf("%s resolved, want an error", name)
+		}
+	}
+
+	env, err := ResolveRigEnv(rigPath)
+	if err == nil || !strings.Contains(err.Error(), "STRIPE_KEY") {
+		t.Errorf("ResolveRigEnv error = %v, want one naming STRIPE_KEY", err)
+	}
+	if env["API_TOKEN"] != "tok-456" {
+		t.Errorf("ResolveRigEnv dropped resolvable variables: %v", env)
+	}
+}
+
+func TestBuildStartupCommand_RigEnv(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+	settings := NewRigSettings()
+	settings.Env = map[string]string{
+		"DATABASE_URL": "postgres://u:it's@db/app",
+		"GT_ROLE":      "mayor",
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": "polecat"}, rigPath, "")
+	if !strings.Contains(cmd, `DATABASE_URL='postgres://u:it'\''s@db/app'`) {
+		t.Errorf("rig env missing or unquoted: %q", cmd)
+	}
+	if !strings.Contains(cmd, "GT_ROLE=polecat") || strings.Contains(cmd, "GT_ROLE=mayor") {
+		t.Errorf("rig env overrode GT_ROLE: %q", cmd)
+	}
+}
@@ -56,6 +56,345 @@ type TownSettings struct {
 	// This allows cost optimization by using different models for different roles.
 	// Example: {"mayor": "claude-opus", "witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Locale controls date and number formatting in human-facing reports
+	// (e.g., "de-DE", "en-GB", or "system" to follow LANG). GT_LOCALE
+	// overrides it. Default: ISO 8601 dates and ungrouped numbers.
+	Locale string `json:"locale,omitempty"`
+
+	// Notifications routes selected town events to external chat sinks
+	// (Slack, Discord). Delivered by the daemon; nil disables notifications.
+	Notifications *NotificationsConfig `json:"notifications,omitempty"`
+
+	// Scheduler caps concurrent polecats across the town. Spawns beyond
+	// the cap are queued and dispatched by priority as polecats finish.
+	Scheduler *SchedulerConfig `json:"scheduler,omitempty"`
+
+	// Budget caps daily and weekly agent spend. New spawns pause at the
+	// warning threshold and all dispatch stops at the cap.
+	Budget *BudgetConfig `json:"budget,omitempty"`
+
+	// Aging raises the priority of ready beads nobody claims and flags
+	// stale beads blocking many others. Run by the Deacon (gt aging run);
+	// nil disables it.
+	Aging *AgingConfig `json:"aging,omitempty"`
+
+	// Chores turns on the scheduled town chores the daemon runs (nightly
+	// bd sync, branch pruning and the like); nil disables them.
+	Chores *ChoresConfig `json:"chores,omitempty"`
+
+	// SpawnProfiles are named sets of spawn settings for recurring kinds of
+	// work, applied with gt sling --profile <name>.
+	SpawnProfiles map[string]*SpawnProfile `json:"spawn_profiles,omitempty"`
+
+	// Prompts selects the role prompt pack agents are primed with and the
+	// token budget for priming.
+	Prompts *PromptsConfig `json:"prompts,omitempty"`
+
+	// HarnessGit turns on automatic commits of town metadata when the
+	// town root is a git repository (see gt harness commit).
+	HarnessGit *HarnessGitConfig `json:"harness_git,omitempty"`
+
+	// Models maps model tiers (--tier) to a provider and model. The
+	// haiku, sonnet and opus tiers default to Anthropic's models.
+	Models *ModelsConfig `json:"models,omitempty"`
+
+	// MCP scopes the tools 'gt mcp serve' offers each role.
+	MCP *MCPConfig `json:"mcp,omitempty"`
+
+	// Permissions overrides which roles may run which gt commands.
+	Permissions *PermissionsConfig `json:"permissions,omitempty"`
+
+	// BeadTemplates adds or replaces the templates 'gt bead create
+	// --template' offers; bug, feature and chore are built in.
+	BeadTemplates map[string]*BeadTemplate `json:"bead_templates,omitempty"`
+
+	// Archive offloads old transcripts, artifacts and events to remote
+	// storage (gt archive run, or the archive chore); nil keeps
+	// everything in the town.
+	Archive *ArchiveConfig `json:"archive,omitempty"`
+
+	// RateLimits paces model API requests per provider ("anthropic",
+	// "openai"), shared by every polecat in the town. Providers without
+	// an entry are unpaced but still back off after a 429.
+	RateLimits map[string]*RateLimitConfig `json:"rate_limits,omitempty"`
+
+	// ContextRecycle hands long-lived agent sessions off to fresh ones as
+	// their context windows fill (see gt context). nil uses the defaults.
+	ContextRecycle *ContextRecycleConfig `json:"context_recycle,omitempty"`
+}
+
+// ContextRecycleConfig configures context recycling. Zero values use the
+// defaults noted on each field.
+type ContextRecycleConfig struct {
+	// Disabled turns context recycling off.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// ThresholdPercent is how full a session's context window may get
+	// before the agent is asked to summarize and hand off (default 70).
+	ThresholdPercent int `json:"threshold_percent,omitempty"`
+
+	// ForcePercent is how full it may get after that before the session
+	// is handed off regardless (default 85).
+	ForcePercent int `json:"force_percent,omitempty"`
+
+	// WindowTokens is the size of the agents' context window (default
+	// 200000).
+	WindowTokens int64 `json:"window_tokens,omitempty"`
+
+	// Roles are the roles whose sessions are recycled (default mayor,
+	// deacon, witness, refinery and crew).
+	Roles []string `json:"roles,omitempty"`
+}
+
+// RateLimitConfig is a provider's town-wide request limit.
+type RateLimitConfig struct {
+	// RequestsPerMinute is the sustained request rate (0 = unlimited).
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+
+	// Burst is how many requests may go at once after a quiet spell.
+	// Default: RequestsPerMinute.
+	Burst int `json:"burst,omitempty"`
+
+	// MaxBackoffSeconds caps the wait after repeated 429s. Default: 300.
+	MaxBackoffSeconds int `json:"max_backoff_seconds,omitempty"`
+}
+
+// ArchiveConfig names where old logs and artifacts are archived.
+type ArchiveConfig struct {
+	// URL is the archive location: s3://bucket/prefix, gs://bucket/prefix,
+	// az://container/prefix (account from AZURE_STORAGE_ACCOUNT), or a
+	// local directory such as a mounted share.
+	URL string `json:"url"`
+
+	// AfterDays is how old an item must be before it is archived.
+	// Default: 30.
+	AfterDays int `json:"after_days,omitempty"`
+}
+
+// BeadTemplate pre-fills a new bead. Flags given to 'gt bead create'
+// override the template's type, priority and description.
+type BeadTemplate struct {
+	// Type is the bead type (task, bug, feature, chore, epic).
+	Type string `json:"type,omitempty"`
+
+	// Priority is the default priority (0-4); nil keeps gt's default, P2.
+	Priority *int `json:"priority,omitempty"`
+
+	// Labels are added to every bead made from the template.
+	Labels []string `json:"labels,omitempty"`
+
+	// Description is the skeleton the bead's description starts from.
+	Description string `json:"description,omitempty"`
+
+	// Molecule is the formula 'gt sling' and 'gt assign --auto' apply to
+	// the bead unless told otherwise.
+	Molecule string `json:"molecule,omitempty"`
+}
+
+// PermissionsConfig overrides the default command policy (see internal/authz).
+type PermissionsConfig struct {
+	// Commands maps a command path to the roles allowed to run it,
+	// replacing the default rule for that path. "*" allows every role.
+	// Example: {"rig add": ["mayor", "crew"], "config": ["*"]}
+	Commands map[string][]string `json:"commands,omitempty"`
+}
+
+// MCPConfig configures the town's MCP server.
+type MCPConfig struct {
+	// Roles replaces the default tool list for the roles it names, by
+	// tool name; "*" allows every tool.
+	// Example: {"polecat": ["beads_show", "beads_list", "mail_send"]}
+	Roles map[string][]string `json:"roles,omitempty"`
+
+	// Delegation limits the polecats the Mayor spawns through the spawn
+	// tool.
+	Delegation *DelegationConfig `json:"delegation,omitempty"`
+}
+
+// DelegationConfig puts guardrails on the Mayor's delegated spawns.
+type DelegationConfig struct {
+	// MaxSpawnsPerHour caps spawns in any hour; 0 means the default (6),
+	// and a negative value turns the spawn tool off.
+	MaxSpawnsPerHour int `json:"max_spawns_per_hour,omitempty"`
+
+	// Tiers are the model tiers delegated polecats may run on; the first
+	// is used when the Mayor names none. Empty allows any tier.
+	// Example: ["haiku", "sonnet"]
+	Tiers []string `json:"tiers,omitempty"`
+}
+
+// ModelsConfig maps model tiers to providers and models.
+type ModelsConfig struct {
+	// Tiers maps a tier name to its model. Listing haiku, sonnet or opus
+	// overrides the default; other names add tiers.
+	// Example: {"local": {"provider": "ollama", "model": "qwen2.5-coder"}}
+	Tiers map[string]*ModelSpec `json:"tiers,omitempty"`
+}
+
+// ModelSpec is the model a tier runs.
+type ModelSpec struct {
+	// Provider is "anthropic", "openai" or "ollama".
+	Provider string `json:"provider"`
+
+	// Model is the provider's model name (e.g., "claude-sonnet-4-5",
+	// "gpt-5", "llama3.1").
+	Model string `json:"model"`
+
+	// BaseURL overrides the provider's API endpoint (an OpenAI-compatible
+	// gateway, or an Ollama server other than localhost:11434).
+	BaseURL string `json:"base_url,omitempty"`
+
+	// KeyEnv names the environment variable holding the API key, instead
+	// of the provider's usual one (ANTHROPIC_API_KEY, OPENAI_API_KEY).
+	KeyEnv string `json:"key_env,omitempty"`
+}
+
+// Harness auto-commit modes.
+const (
+	HarnessCommitOff      = "off"
+	HarnessCommitCommand  = "command"
+	HarnessCommitSchedule = "schedule"
+)
+
+// HarnessGitConfig configures automatic commits of harness metadata.
+type HarnessGitConfig struct {
+	// AutoCommit is "command" to commit after every gt command that
+	// changes metadata, "schedule" to commit from the harness-commit
+	// chore, or "off" (the default).
+	AutoCommit string `json:"auto_commit,omitempty"`
+
+	// Push pushes each scheduled commit to the upstream branch.
+	Push bool `json:"push,omitempty"`
+}
+
+// PromptsConfig selects a versioned prompt pack from <town>/prompts/.
+type PromptsConfig struct {
+	// Pack is the pack version to use; empty or "builtin" uses the
+	// prompts compiled into gt.
+	Pack string `json:"pack,omitempty"`
+
+	// ContextBudget caps the tokens gt prime gives an agent; lower-priority
+	// context beyond it is trimmed or dropped. 0 uses the default and a
+	// negative value turns the budget off.
+	ContextBudget int `json:"context_budget,omitempty"`
+}
+
+// SpawnProfile bundles the settings for one kind of polecat spawn. Empty
+// fields leave the usual defaults; flags given with --profile win.
+type SpawnProfile struct {
+	// Rig is the rig to spawn in when the sling names no target.
+	Rig string `json:"rig,omitempty"`
+
+	// Agent is the agent alias to run (as with --agent).
+	Agent string `json:"agent,omitempty"`
+
+	// Tier picks the model tier: haiku, sonnet, opus or one from Models.
+	Tier string `json:"tier,omitempty"`
+
+	// Molecule is a formula applied to the bead (as with --on).
+	Molecule string `json:"molecule,omitempty"`
+
+	// Sandbox overrides the rig's sandbox profile ("off" runs unconfined).
+	Sandbox string `json:"sandbox,omitempty"`
+
+	// Runtime is "local" or "docker" (as with --runtime).
+	Runtime string `json:"runtime,omitempty"`
+
+	// Account is the Claude Code account handle (as with --account).
+	Account string `json:"account,omitempty"`
+
+	// Branch names the polecat's branch after "polecat/", with {name},
+	// {bead}, {rig} and {ts} placeholders. Default: "{name}/{bead}".
+	Branch string `json:"branch,omitempty"`
+}
+
+// ChoresConfig configures scheduled town chores.
+type ChoresConfig struct {
+	// Schedule overrides chore schedules by chore name, as five-field cron
+	// expressions ("0 3 * * *"), @hourly/@daily/@weekly, or "off".
+	// Chores not listed run on their default schedule.
+	Schedule map[string]string `json:"schedule,omitempty"`
+}
+
+// SchedulerConfig configures spawn admission control.
+type SchedulerConfig struct {
+	// MaxPolecats caps live polecats across all rigs (0 = no limit).
+	// Per-rig caps come from the rig's max_polecats property.
+	MaxPolecats int `json:"max_polecats,omitempty"`
+
+	// Molecules picks the formula 'gt assign --auto' applies to a bead, by
+	// bead type ("bug": "shiny"); "*" matches any type. Unlisted types get
+	// mol-polecat-work.
+	Molecules map[string]string `json:"molecules,omitempty"`
+
+	// WarmPolecats keeps this many idle polecat sessions per rig started
+	// and primed with rig context, so a sling to the rig only has to send
+	// the work ("gastown": 2). The daemon tops the pools up.
+	WarmPolecats map[string]int `json:"warm_polecats,omitempty"`
+
+	// AutoPull lets a rig's polecats take the next ready bead for their
+	// tier after 'gt done' instead of exiting, up to this many beads per
+	// session before the session is recycled ("gastown": 5). Unlisted
+	// rigs, and values below 2, keep one bead per session.
+	AutoPull map[string]int `json:"auto_pull,omitempty"`
+}
+
+// BudgetConfig caps agent spend, as recorded by 'gt costs record'.
+// Zero disables a cap. Days are local calendar days; the week is the
+// last seven days, matching 'gt costs --week'.
+type BudgetConfig struct {
+	DailyUSD     float64 `json:"daily_usd,omitempty"`
+	WeeklyUSD    float64 `json:"weekly_usd,omitempty"`
+	DailyTokens  int64   `json:"daily_tokens,omitempty"`
+	WeeklyTokens int64   `json:"weekly_tokens,omitempty"`
+
+	// WarnPercent is the share of a cap at which new spawns pause and a
+	// budget notification is sent (default 80).
+	WarnPercent int `json:"warn_percent,omitempty"`
+}
+
+// AgingConfig configures bead priority aging. Zero values use the defaults
+// noted on each field.
+type AgingConfig struct {
+	// BumpAfterDays is how long a bead may sit ready and unclaimed before
+	// its priority is raised by one (default 3). The clock restarts on
+	// every update, so an ignored bead keeps climbing.
+	BumpAfterDays int `json:"bump_after_days,omitempty"`
+
+	// MaxPriority is the most urgent priority aging raises a bead to
+	// (default 1; set 0 to let aging reach P0).
+	MaxPriority *int `json:"max_priority,omitempty"`
+
+	// StaleAfterDays is how long an open bead goes without updates before
+	// it counts as stale when it blocks others (default 2).
+	StaleAfterDays int `json:"stale_after_days,omitempty"`
+
+	// BlockedThreshold is how many open beads, directly or through a
+	// chain, a stale bead must hold up to be flagged (default 3).
+	BlockedThreshold int `json:"blocked_threshold,omitempty"`
+}
+
+// NotificationsConfig configures external notification delivery.
+type NotificationsConfig struct {
+	// Sinks are named delivery targets referenced by Routes.
+	// Example: {"ops": {"type": "slack", "url_env": "GT_SLACK_WEBHOOK"}}
+	Sinks map[string]*NotificationSink `json:"sinks,omitempty"`
+
+	// Routes maps notification events to sink names. Events:
+	// "polecat_done", "merge_failed", "escalation", "molecule_complete",
+	// or "*" for all of them.
+	Routes map[string][]string `json:"routes,omitempty"`
+}
+
+// NotificationSink is a single notification target.
+type NotificationSink struct {
+	Type string `json:"type"` // "slack" or "discord"
+
+	// URL is the incoming webhook URL. URLEnv names an environment variable
+	// holding it instead, which keeps secrets out of settings files.
+	URL    string `json:"url,omitempty"`
+	URLEnv string `json:"url_env,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -153,6 +492,9 @@ type RigsConfig struct {
 type RigEntry struct {
 	GitURL      string       `json:"git_url"`
 	LocalRepo   string       `json:"local_repo,omitempty"`
+	Remote      string       `json:"remote,omitempty"` // [user@]host:/path of a rig on another machine
+	Subdir      string       `json:"path,omitempty"`   // Monorepo subdirectory the rig works in
+	VCS         string       `json:"vcs,omitempty"`    // Version control backend, "jj" or "" for git
 	AddedAt     time.Time    `json:"added_at"`
 	BeadsConfig *BeadsConfig `json:"beads,omitempty"`
 }
@@ -193,6 +535,11 @@ type WorkflowConfig struct {
 	// DefaultFormula is the formula to use when `gt formula run` is called without arguments.
 	// If empty, no default is set and a formula name must be provided.
 	DefaultFormula string `json:"default_formula,omitempty"`
+
+	// ReviewLinters are the static analysis tools run for molecule steps
+	// that declare "Lint: rig" (the polecat self-review step does). Entries
+	// are "go vet", "staticcheck", "golangci-lint" or a shell command.
+	ReviewLinters []string `json:"review_linters,omitempty"`
 }
 
 // RigSettings represents per-rig behavioral configuration (settings/config.json).
@@ -205,6 +552,12 @@ type RigSettings struct {
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
+	Sandbox    *SandboxConfig    `json:"sandbox,omitempty"`     // polecat sandbox profile
+	Container  *ContainerConfig  `json:"container,omitempty"`   // polecat container runtime
+
+	// QualityGate scores work before gt done submits it (see
+	// QualityGateConfig).
+	QualityGate *QualityGateConfig `json:"quality_gate,omitempty"`
 
 	// Agent selects which agent preset to use for this rig.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp")
@@ -224,6 +577,12 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Env sets environment variables in the rig's agent sessions and the
+	// refinery's test runs. A value is used as is, or read from a secret
+	// reference: "env:VAR", "keychain:<name>", "sops:<file>#<key>" or
+	// "file:<path>", with paths relative to the rig (see ResolveRigEnv).
+	Env map[string]string `json:"env,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -656,6 +1015,11 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of concurrent merges.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// Strategy selects how completed work lands: "local" (default) submits
+	// to the refinery merge queue; "github-pr" opens a GitHub pull request
+	// from the polecat branch and tracks review/CI status on the bead.
+	Strategy string `json:"strategy,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -664,6 +1028,18 @@ const (
 	OnConflictAutoRebase = "auto_rebase"
 )
 
+// Merge strategy constants.
+const (
+	MergeStrategyLocal    = "local"
+	MergeStrategyGitHubPR = "github-pr"
+)
+
+// UsesGitHubPR returns true if completed work should be submitted as a
+// GitHub pull request instead of through the local merge queue.
+func (c *MergeQueueConfig) UsesGitHubPR() bool {
+	return c != nil && c.Strategy == MergeStrategyGitHubPR
+}
+
 // DefaultMergeQueueConfig returns a MergeQueueConfig with sensible defaults.
 func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
@@ -695,6 +1071,92 @@ type NamepoolConfig struct {
 	MaxBeforeNumbering int `json:"max_before_numbering,omitempty"`
 }
 
+// SandboxConfig represents the sandbox profile polecats run under.
+type SandboxConfig struct {
+	// Profile selects the backend: "nsjail" (Linux), "sandbox-exec" (macOS),
+	// "auto" for whichever is installed, or "off" (the default).
+	Profile string `json:"profile,omitempty"`
+
+	// DenyNetwork cuts polecats off from the network.
+	DenyNetwork bool `json:"deny_network,omitempty"`
+
+	// MemoryMB caps each polecat's memory (0 = no limit).
+	MemoryMB int `json:"memory_mb,omitempty"`
+
+	// CPUs caps each polecat's CPU use in cores (0 = no limit).
+	CPUs float64 `json:"cpus,omitempty"`
+
+	// MaxProcs caps the processes in each polecat's sandbox (0 = no limit).
+	MaxProcs int `json:"max_procs,omitempty"`
+
+	// Writable lists extra paths polecats may modify, beyond their clone.
+	// Relative paths are relative to the rig; "~/" is the home directory.
+	Writable []string `json:"writable,omitempty"`
+}
+
+// ContainerConfig represents the container polecats run in with the
+// docker runtime.
+type ContainerConfig struct {
+	// Default runs every polecat in a container unless spawned with
+	// --runtime local.
+	Default bool `json:"default,omitempty"`
+
+	// Engine is the container CLI: "docker" (default) or "podman".
+	Engine string `json:"engine,omitempty"`
+
+	// Image is the image to run. It must provide the agent runtime.
+	Image string `json:"image"`
+
+	// Build is a directory with a Dockerfile, relative to the rig. When
+	// set, Image is built from it before each spawn instead of pulled.
+	Build string `json:"build,omitempty"`
+
+	// Env sets extra environment variables in the container.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Mounts adds bind mounts as "host:container[:ro]". Relative host
+	// paths are relative to the rig; "~/" is the home directory.
+	Mounts []string `json:"mounts,omitempty"`
+
+	// Network is the container network mode (e.g. "none", "host").
+	// If empty, the engine's default is used.
+	Network string `json:"network,omitempty"`
+}
+
+// QualityGateConfig configures the score polecat work must reach before
+// gt done submits it. Work that scores below MinScore goes back to the
+// molecule's implement step with the score report instead of to the
+// refinery.
+type QualityGateConfig struct {
+	// Enabled turns the gate on.
+	Enabled bool `json:"enabled"`
+
+	// MinScore is the passing score, 0-100. Default 70.
+	MinScore int `json:"min_score,omitempty"`
+
+	// BuildCommand checks the work compiles (e.g. "go build ./...").
+	// A failed build bounces the work whatever the score.
+	BuildCommand string `json:"build_command,omitempty"`
+
+	// TestCommand runs the tests; the pass rate is read from its output.
+	// Default: merge_queue.test_command.
+	TestCommand string `json:"test_command,omitempty"`
+
+	// LintCommand runs the linter; each line of output counts as a finding.
+	LintCommand string `json:"lint_command,omitempty"`
+
+	// MaxLintFindings is the finding count that scores zero. Default 20.
+	MaxLintFindings int `json:"max_lint_findings,omitempty"`
+
+	// MaxDiffLines is the diff size (insertions plus deletions) above
+	// which the diff check starts losing points. Default 1000.
+	MaxDiffLines int `json:"max_diff_lines,omitempty"`
+
+	// Weights overrides how much each check counts, by check name:
+	// "build", "tests", "lint" and "diff".
+	Weights map[string]float64 `json:"weights,omitempty"`
+}
+
 // DefaultNamepoolConfig returns a NamepoolConfig with sensible defaults.
 func DefaultNamepoolConfig() *NamepoolConfig {
 	return &NamepoolConfig{
@@ -102,6 +102,10 @@ const (
 	// BranchPolecatPrefix is the prefix for polecat work branches.
 	BranchPolecatPrefix = "polecat/"
 
+	// BranchCrewPrefix is the prefix for crew work branches
+	// (crew/<name>/<issue>, made by gt claim).
+	BranchCrewPrefix = "crew/"
+
 	// BranchIntegrationPrefix is the prefix for integration branches.
 	BranchIntegrationPrefix = "integration/"
 )
@@ -185,6 +189,15 @@ func RoleEmoji(role string) string {
 // Used to identify if a tmux pane is at a shell prompt vs running a command.
 var SupportedShells = []string{"bash", "zsh", "sh", "fish", "tcsh", "ksh"}
 
+// ContainerEngines lists container CLIs that host containerized agents.
+// An agent in a container is not a child of the pane, so a pane running
+// one of these is taken to be running its agent.
+var ContainerEngines = []string{"docker", "podman"}
+
+// RemoteClients lists the clients that host agents of remote rigs. Like a
+// container engine, a pane running one is taken to be running its agent.
+var RemoteClients = []string{"ssh"}
+
 // Path helpers construct common paths.
 
 // MayorRigsPath returns the path to rigs.json within a town root.
@@ -0,0 +1,171 @@
+// Package container runs polecat agents inside containers.
+//
+// The container is started in the polecat's tmux session, so the session,
+// its pane and the witness's health checks work exactly as they do for a
+// local agent; only the process in the pane differs. Host paths are
+// mounted at the same paths inside the container, so the clone, beads and
+// git metadata the agent sees match what gt records on the host.
+package container
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/dryrun"
+)
+
+// Polecat runtimes.
+const (
+	// RuntimeLocal runs the agent directly in the tmux session.
+	RuntimeLocal = "local"
+	// RuntimeDocker runs the agent in a container.
+	RuntimeDocker = "docker"
+)
+
+// DefaultEngine is the container CLI used when a rig names none.
+const DefaultEngine = "docker"
+
+// Mount is a bind mount, or an empty tmpfs when Source is "".
+type Mount struct {
+	Source   string
+	Target   string
+	ReadOnly bool
+}
+
+// Spec describes a polecat container.
+type Spec struct {
+	Engine   string // container CLI ("docker", "podman")
+	Name     string // container name (the tmux session name)
+	Image    string
+	WorkDir  string
+	User     string // "uid:gid", or "" for the image's user
+	Network  string // network mode, or "" for the engine's default
+	Mounts   []Mount
+	Env      map[string]string
+	MemoryMB int
+	CPUs     float64
+	MaxProcs int
+}
+
+// RunArgs returns the engine invocation that runs command in the container.
+func (s *Spec) RunArgs(command string) []string {
+	args := []string{s.engine(), "run", "--rm", "-it", "--init", "--name", s.Name}
+	if s.User != "" {
+		args = append(args, "--user", s.User)
+	}
+	if s.Network != "" {
+		args = append(args, "--network", s.Network)
+	}
+	if s.MemoryMB > 0 {
+		args = append(args, "--memory", fmt.Sprintf("%dm", s.MemoryMB))
+	}
+	if s.CPUs > 0 {
+		args = append(args, "--cpus", fmt.Sprintf("%g", s.CPUs))
+	}
+	if s.MaxProcs > 0 {
+		args = append(args, "--pids-limit", fmt.Sprintf("%d", s.MaxProcs))
+	}
+	for _, m := range s.Mounts {
+		args = append(args, "--mount", m.flag())
+	}
+	keys := make([]string, 0, len(s.Env))
+	for k := range s.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--env", k+"="+s.Env[k])
+	}
+	if s.WorkDir != "" {
+		args = append(args, "--workdir", s.WorkDir)
+	}
+	return append(args, s.Image, "/bin/sh", "-c", command)
+}
+
+// SessionCommand returns the shell command a tmux session runs to host the
+// container. A leftover container from a crashed session is removed first,
+// and the container is removed when the session is killed: killing the
+// pane only hangs up the engine client, which leaves the container running.
+func (s *Spec) SessionCommand(command string) string {
+	rm := shellJoin([]string{s.engine(), "rm", "-f", s.Name}) + " >/dev/null 2>&1"
+	return fmt.Sprintf("%s; trap %s EXIT HUP TERM; %s", rm, shellQuote(rm), shellJoin(s.RunArgs(command)))
+}
+
+func (s *Spec) engine() string {
+	if s.Engine == "" {
+		return DefaultEngine
+	}
+	return s.Engine
+}
+
+func (m Mount) flag() string {
+	if m.Source == "" {
+		return "type=tmpfs,destination=" + m.Target
+	}
+	flag := "type=bind,source=" + m.Source + ",target=" + m.Target
+	if m.ReadOnly {
+		flag += ",readonly"
+	}
+	return flag
+}
+
+// EnsureImage makes image available to engine: it is built from
+// buildContext when one is given, otherwise pulled if not present.
+func EnsureImage(engine, image, buildContext string) error {
+	if engine == "" {
+		engine = DefaultEngine
+	}
+	if _, err := exec.LookPath(engine); err != nil {
+		return fmt.Errorf("%s not installed: %w", engine, err)
+	}
+
+	if buildContext != "" {
+		// Builds are cached, so rebuilding picks up Dockerfile changes cheaply
+		return run(exec.Command(engine, "build", "-t", image, buildContext), "building image "+image)
+	}
+	if exec.Command(engine, "image", "inspect", image).Run() == nil { //nolint:gosec // G204: engine and image come from rig settings
+		return nil
+	}
+	return run(exec.Command(engine, "pull", image), "pulling image "+image) //nolint:gosec // G204: engine and image come from rig settings
+}
+
+// Remove force-removes a container, if it exists.
+func Remove(engine, name string) error {
+	if engine == "" {
+		engine = DefaultEngine
+	}
+	cmd := exec.Command(engine, "rm", "-f", name) //nolint:gosec // G204: engine comes from rig settings
+	if dryrun.Command(cmd) {
+		return nil
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil && !strings.Contains(string(out), "No such container") {
+		return fmt.Errorf("removing container %s: %s", name, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func run(cmd *exec.Cmd, action string) error {
+	if dryrun.Command(cmd) {
+		return nil
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w\n%s", action, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// shellQuote quotes s as a single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return strings.Join(quoted, " ")
+}
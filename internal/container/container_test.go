@@ -0,0 +1,48 @@
+package container
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunArgs(t *testing.T) {
+	spec := &Spec{
+		Name:     "gt-gastown-Toast",
+		Image:    "ghcr.io/example/agent:latest",
+		WorkDir:  "/gt/gastown/polecats/Toast/gastown",
+		User:     "1000:1000",
+		Network:  "none",
+		MemoryMB: 2048,
+		Mounts: []Mount{
+			{Source: "/gt", Target: "/gt", ReadOnly: true},
+			{Target: "/gt/gastown/polecats"},
+			{Source: "/gt/gastown/polecats/Toast", Target: "/gt/gastown/polecats/Toast"},
+		},
+		Env: map[string]string{"GT_ROLE": "polecat", "BD_ACTOR": "gastown/polecats/Toast"},
+	}
+	got := strings.Join(spec.RunArgs("claude"), " ")
+	want := "docker run --rm -it --init --name gt-gastown-Toast --user 1000:1000 --network none --memory 2048m" +
+		" --mount type=bind,source=/gt,target=/gt,readonly" +
+		" --mount type=tmpfs,destination=/gt/gastown/polecats" +
+		" --mount type=bind,source=/gt/gastown/polecats/Toast,target=/gt/gastown/polecats/Toast" +
+		" --env BD_ACTOR=gastown/polecats/Toast --env GT_ROLE=polecat" +
+		" --workdir /gt/gastown/polecats/Toast/gastown ghcr.io/example/agent:latest /bin/sh -c claude"
+	if got != want {
+		t.Errorf("RunArgs =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSessionCommand(t *testing.T) {
+	spec := &Spec{Engine: "podman", Name: "gt-gastown-Toast", Image: "agent"}
+	got := spec.SessionCommand("export A=1 && claude")
+
+	rm := `'podman' 'rm' '-f' 'gt-gastown-Toast' >/dev/null 2>&1`
+	if !strings.HasPrefix(got, rm+"; trap ") {
+		t.Errorf("SessionCommand does not remove a leftover container first:\n%s", got)
+	}
+	for _, want := range []string{"EXIT HUP TERM", `'podman' 'run'`, `'/bin/sh' '-c' 'export A=1 && claude'`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SessionCommand missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -144,6 +144,13 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 		}
 	}
 
+	if m.rig.Subdir != "" {
+		if err := git.SetSparseScope(crewPath, m.rig.Subdir); err != nil {
+			_ = os.RemoveAll(crewPath) // best-effort cleanup
+			return nil, fmt.Errorf("scoping clone to %s: %w", m.rig.Subdir, err)
+		}
+	}
+
 	crewGit := git.NewGit(crewPath)
 	branchName := m.rig.DefaultBranch()
 
@@ -216,6 +223,55 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 	return crew, nil
 }
 
+// SetOwner records the human who owns a crew workspace and sets the
+// clone's git identity to theirs, so their commits and branches are
+// attributed to them rather than to whoever set up the town.
+func (m *Manager) SetOwner(name, owner, email string) (*CrewWorker, error) {
+	worker, err := m.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	crewGit := git.NewGit(worker.ClonePath)
+	if owner != "" {
+		if err := crewGit.SetConfig("user.name", owner); err != nil {
+			return nil, fmt.Errorf("setting git user.name: %w", err)
+		}
+	}
+	if email != "" {
+		if err := crewGit.SetConfig("user.email", email); err != nil {
+			return nil, fmt.Errorf("setting git user.email: %w", err)
+		}
+	}
+
+	worker.Owner = owner
+	worker.Email = email
+	worker.UpdatedAt = time.Now()
+	if err := m.saveState(worker); err != nil {
+		return nil, err
+	}
+	return worker, nil
+}
+
+// Branches returns the local branches in a crew workspace other than the
+// rig's default branch: the work its owner has in flight.
+func (m *Manager) Branches(name string) ([]string, error) {
+	worker, err := m.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	all, err := git.NewGit(worker.ClonePath).ListBranches("")
+	if err != nil {
+		return nil, err
+	}
+	var branches []string
+	for _, b := range all {
+		if b != "" && b != m.rig.DefaultBranch() {
+			branches = append(branches, b)
+		}
+	}
+	return branches, nil
+}
+
 // Remove deletes a crew worker.
 func (m *Manager) Remove(name string, force bool) error {
 	if err := validateCrewName(name); err != nil {
@@ -347,3 +347,59 @@ func runCmd(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	return cmd.Run()
 }
+
+func TestManagerSetOwner(t *testing.T) {
+	tmpDir := t.TempDir()
+	rigPath := filepath.Join(tmpDir, "test-rig")
+	sourceRepoPath := filepath.Join(tmpDir, "source-repo")
+	for _, dir := range []string{rigPath, sourceRepoPath} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(sourceRepoPath, "README.md"), []byte("# Test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, cmd := range [][]string{
+		{"git", "-C", sourceRepoPath, "init", "-b", "main"},
+		{"git", "-C", sourceRepoPath, "add", "."},
+		{"git", "-C", sourceRepoPath, "-c", "user.email=test@test.com", "-c", "user.name=Test", "commit", "-m", "Initial commit"},
+	} {
+		if err := runCmd(cmd[0], cmd[1:]...); err != nil {
+			t.Fatalf("failed to run %v: %v", cmd, err)
+		}
+	}
+
+	r := &rig.Rig{Name: "test-rig", Path: rigPath, GitURL: sourceRepoPath}
+	mgr := NewManager(r, git.NewGit(rigPath))
+	if _, err := mgr.Add("alice", true); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	if _, err := mgr.SetOwner("alice", "Alice Liddell", "alice@example.com"); err != nil {
+		t.Fatalf("SetOwner failed: %v", err)
+	}
+	worker, err := mgr.Get("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if worker.Owner != "Alice Liddell" || worker.Email != "alice@example.com" {
+		t.Errorf("owner = %q <%s>", worker.Owner, worker.Email)
+	}
+	out, err := exec.Command("git", "-C", worker.ClonePath, "config", "user.email").Output()
+	if err != nil || string(out) != "alice@example.com\n" {
+		t.Errorf("clone user.email = %q, %v", out, err)
+	}
+
+	branches, err := mgr.Branches("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(branches) != 1 || branches[0] != "crew/alice" {
+		t.Errorf("Branches = %v, want [crew/alice]", branches)
+	}
+
+	if _, err := mgr.SetOwner("nobody", "x", ""); err != ErrCrewNotFound {
+		t.Errorf("SetOwner on missing crew = %v", err)
+	}
+}
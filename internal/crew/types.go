@@ -17,6 +17,13 @@ type CrewWorker struct {
 	// Branch is the current git branch.
 	Branch string `json:"branch"`
 
+	// Owner is the human who works in this workspace, when the town is
+	// shared by a team. Commits from the clone carry their identity.
+	Owner string `json:"owner,omitempty"`
+
+	// Email is the owner's git email.
+	Email string `json:"email,omitempty"`
+
 	// CreatedAt is when the crew worker was created.
 	CreatedAt time.Time `json:"created_at"`
 
@@ -28,6 +35,7 @@ type CrewWorker struct {
 type Summary struct {
 	Name   string `json:"name"`
 	Branch string `json:"branch"`
+	Owner  string `json:"owner,omitempty"`
 }
 
 // Summary returns a Summary for this crew worker.
@@ -35,5 +43,6 @@ func (c *CrewWorker) Summary() Summary {
 	return Summary{
 		Name:   c.Name,
 		Branch: c.Branch,
+		Owner:  c.Owner,
 	}
 }
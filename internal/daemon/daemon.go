@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -12,22 +13,27 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/chores"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/townmetrics"
+	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/witness"
 )
@@ -43,6 +49,8 @@ type Daemon struct {
 	ctx     context.Context
 	cancel  context.CancelFunc
 	curator *feed.Curator
+	watcher *notify.Watcher
+	metrics *http.Server
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -126,7 +134,7 @@ func (d *Daemon) Run() error {
 
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
+	signal.Notify(sigChan, daemonSignals...)
 
 	// Fixed recovery-focused heartbeat (no activity-based backoff)
 	// Normal wake is handled by feed subscription (bd activity --follow)
@@ -143,6 +151,10 @@ func (d *Daemon) Run() error {
 		d.logger.Println("Feed curator started")
 	}
 
+	// Start notification watcher if any sinks are routed
+	d.startNotifications()
+	d.startMetrics()
+
 	// Initial heartbeat
 	d.heartbeat(state)
 
@@ -153,7 +165,7 @@ func (d *Daemon) Run() error {
 			return d.shutdown(state)
 
 		case sig := <-sigChan:
-			if sig == syscall.SIGUSR1 {
+			if isLifecycleSignal(sig) {
 				// SIGUSR1: immediate lifecycle processing (from gt handoff)
 				d.logger.Println("Received SIGUSR1, processing lifecycle requests immediately")
 				d.processLifecycleRequests()
@@ -224,6 +236,18 @@ func (d *Daemon) heartbeat(state *State) {
 	// This validates tmux sessions are still alive for polecats with work-on-hook
 	d.checkPolecatSessionHealth()
 
+	// 12. Announce spend reaching the budget's thresholds
+	d.checkBudget()
+
+	// 13. Dispatch queued spawns that fit under the polecat limits
+	d.drainSpawnQueue()
+
+	// 14. Top up the rigs' warm spare polecats
+	d.topUpWarmPolecats()
+
+	// 15. Run scheduled town chores that are due
+	d.runDueChores()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -564,11 +588,165 @@ func (d *Daemon) triggerPendingSpawns() {
 	}
 }
 
+// checkBudget sends budget notifications even when nothing is being slung.
+// Enforcement happens where spawns are admitted (gt sling, gt scheduler drain).
+func (d *Daemon) checkBudget() {
+	now := time.Now()
+	status, err := budget.Load(d.config.TownRoot, now)
+	if err != nil {
+		d.logger.Printf("Error checking budget: %v", err)
+		return
+	}
+	if status.Level != budget.OK {
+		d.logger.Printf("Spawning held: %s", status.Reason())
+	}
+	if err := budget.Announce(d.config.TownRoot, status, now); err != nil {
+		d.logger.Printf("Error recording budget alert: %v", err)
+	}
+}
+
+// drainSpawnQueue dispatches queued slings once polecats have finished.
+// gt does the dispatching, since a dispatch is a full gt sling.
+func (d *Daemon) drainSpawnQueue() {
+	queued, err := scheduler.NewQueue(d.config.TownRoot).List()
+	if err != nil {
+		d.logger.Printf("Error reading spawn queue: %v", err)
+		return
+	}
+	if len(queued) == 0 {
+		return
+	}
+
+	cmd := exec.Command("gt", "scheduler", "drain")
+	cmd.Dir = d.config.TownRoot
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("Error draining spawn queue: %v: %s", err, strings.TrimSpace(string(out)))
+		return
+	}
+	d.logger.Printf("Spawn queue (%d queued): %s", len(queued), strings.TrimSpace(string(out)))
+}
+
+// topUpWarmPolecats keeps the configured warm spares running, after the
+// spawn queue has had its pick of them. gt does the spawning.
+func (d *Daemon) topUpWarmPolecats() {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil || settings.Scheduler == nil {
+		return
+	}
+	configured := false
+	for _, n := range settings.Scheduler.WarmPolecats {
+		configured = configured || n > 0
+	}
+	if !configured {
+		return
+	}
+
+	cmd := exec.Command("gt", "polecat", "warm", "--all")
+	cmd.Dir = d.config.TownRoot
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("Error topping up warm polecats: %v: %s", err, strings.TrimSpace(string(out)))
+		return
+	}
+	d.logger.Printf("Warm polecats: %s", strings.TrimSpace(string(out)))
+}
+
+// runDueChores runs the scheduled town chores whose time has come.
+// The daemon only keeps time; gt does the work.
+func (d *Daemon) runDueChores() {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil || settings.Chores == nil {
+		return
+	}
+	scheduled, err := chores.Resolve(settings.Chores)
+	if err != nil {
+		d.logger.Printf("Error in chore schedule: %v", err)
+		return
+	}
+	state, err := chores.LoadState(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Error reading chore state: %v", err)
+		return
+	}
+
+	now := time.Now()
+	if state.Since.IsZero() {
+		// First check since chores were turned on: start the clocks
+		if err := chores.Update(d.config.TownRoot, func(s *chores.State) { s.Since = now }); err != nil {
+			d.logger.Printf("Error recording chore state: %v", err)
+		}
+		return
+	}
+	var due []string
+	for _, c := range scheduled {
+		if state.Due(c, now) {
+			due = append(due, c.Name)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	cmd := exec.Command("gt", "deacon", "chores", "run", "--due")
+	cmd.Dir = d.config.TownRoot
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("Chores (%s) failed: %v: %s", strings.Join(due, ", "), err, strings.TrimSpace(string(out)))
+		return
+	}
+	d.logger.Printf("Chores (%s): %s", strings.Join(due, ", "), strings.TrimSpace(string(out)))
+}
+
 // processLifecycleRequests checks for and processes lifecycle requests.
 func (d *Daemon) processLifecycleRequests() {
 	d.ProcessLifecycleRequests()
 }
 
+// startNotifications starts delivering town events to the sinks configured
+// in settings/config.json. Configuration errors are logged, not fatal.
+func (d *Daemon) startNotifications() {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil || settings.Notifications == nil {
+		return
+	}
+	notifier, err := notify.New(settings.Notifications)
+	if err != nil {
+		d.logger.Printf("Warning: notifications disabled: %v", err)
+		return
+	}
+	if !notifier.Enabled() {
+		return
+	}
+	d.watcher = notify.NewWatcher(d.config.TownRoot, notifier, d.logger.Printf)
+	if err := d.watcher.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start notification watcher: %v", err)
+		d.watcher = nil
+		return
+	}
+	d.logger.Printf("Notification watcher started (sinks: %s)", strings.Join(notifier.SinkNames(), ", "))
+}
+
+// startMetrics serves Prometheus metrics when a metrics address is configured.
+func (d *Daemon) startMetrics() {
+	if d.config.MetricsAddr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", townmetrics.NewRegistry(d.config.TownRoot).Handler())
+	d.metrics = &http.Server{
+		Addr:              d.config.MetricsAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := d.metrics.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			d.logger.Printf("Warning: metrics endpoint stopped: %v", err)
+		}
+	}()
+	d.logger.Printf("Serving metrics on %s/metrics", d.config.MetricsAddr)
+}
+
 // shutdown performs graceful shutdown.
 func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return kept for future use
 	d.logger.Println("Daemon shutting down")
@@ -579,6 +757,19 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Feed curator stopped")
 	}
 
+	// Stop notification watcher
+	if d.watcher != nil {
+		d.watcher.Stop()
+		d.logger.Println("Notification watcher stopped")
+	}
+
+	// Stop metrics endpoint
+	if d.metrics != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		_ = d.metrics.Shutdown(ctx)
+		cancel()
+	}
+
 	state.Running = false
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save final state: %v", err)
@@ -613,14 +804,7 @@ func IsRunning(townRoot string) (bool, int, error) {
 	}
 
 	// Check if process is running
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false, 0, nil
-	}
-
-	// On Unix, FindProcess always succeeds. Send signal 0 to check if alive.
-	err = process.Signal(syscall.Signal(0))
-	if err != nil {
+	if !util.ProcessAlive(pid) {
 		// Process not running, clean up stale PID file
 		_ = os.Remove(pidFile)
 		return false, 0, nil
@@ -647,7 +831,7 @@ func StopDaemon(townRoot string) error {
 	}
 
 	// Send SIGTERM for graceful shutdown
-	if err := process.Signal(syscall.SIGTERM); err != nil {
+	if err := util.TerminateProcess(process); err != nil {
 		return fmt.Errorf("sending SIGTERM: %w", err)
 	}
 
@@ -655,9 +839,9 @@ func StopDaemon(townRoot string) error {
 	time.Sleep(constants.ShutdownNotifyDelay)
 
 	// Check if still running
-	if err := process.Signal(syscall.Signal(0)); err == nil {
+	if util.ProcessAlive(pid) {
 		// Still running, force kill
-		_ = process.Signal(syscall.SIGKILL)
+		_ = process.Kill()
 	}
 
 	// Clean up PID file
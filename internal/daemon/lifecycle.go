@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/authz"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
@@ -479,12 +480,21 @@ func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIde
 	if runtimeConfig.Session != nil && runtimeConfig.Session.SessionIDEnv != "" {
 		defaultCmd = config.PrependEnv(defaultCmd, map[string]string{"GT_SESSION_ID_ENV": runtimeConfig.Session.SessionIDEnv})
 	}
+	// The agent process gets its role token in the command: tmux
+	// SetEnvironment doesn't reach the session's first pane
+	token, _ := authz.Sign(d.config.TownRoot, authz.Identity{Role: parsed.RoleType, Rig: parsed.RigName, Agent: parsed.AgentName})
+	if token != "" {
+		defaultCmd = config.PrependEnv(defaultCmd, map[string]string{authz.EnvToken: token})
+	}
 
 	// Polecats and crew need environment variables set in the command
 	if parsed.RoleType == "polecat" {
 		envVars := config.AgentEnvSimple("polecat", parsed.RigName, parsed.AgentName)
 		// Add GT_ROOT and session ID env if available
 		envVars["GT_ROOT"] = d.config.TownRoot
+		if token != "" {
+			envVars[authz.EnvToken] = token
+		}
 		if runtimeConfig.Session != nil && runtimeConfig.Session.SessionIDEnv != "" {
 			envVars["GT_SESSION_ID_ENV"] = runtimeConfig.Session.SessionIDEnv
 		}
@@ -494,6 +504,9 @@ func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIde
 	if parsed.RoleType == "crew" {
 		envVars := config.AgentEnvSimple("crew", parsed.RigName, parsed.AgentName)
 		envVars["GT_ROOT"] = d.config.TownRoot
+		if token != "" {
+			envVars[authz.EnvToken] = token
+		}
 		if runtimeConfig.Session != nil && runtimeConfig.Session.SessionIDEnv != "" {
 			envVars["GT_SESSION_ID_ENV"] = runtimeConfig.Session.SessionIDEnv
 		}
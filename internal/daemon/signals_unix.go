@@ -0,0 +1,16 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// daemonSignals are the signals the daemon handles: SIGINT and SIGTERM
+// shut it down, SIGUSR1 processes lifecycle requests immediately.
+var daemonSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1}
+
+func isLifecycleSignal(sig os.Signal) bool {
+	return sig == syscall.SIGUSR1
+}
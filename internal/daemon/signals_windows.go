@@ -0,0 +1,16 @@
+//go:build windows
+
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// daemonSignals are the signals the daemon handles. Windows has no
+// SIGUSR1, so lifecycle requests wait for the next heartbeat.
+var daemonSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+func isLifecycleSignal(sig os.Signal) bool {
+	return false
+}
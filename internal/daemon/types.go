@@ -30,6 +30,10 @@ type Config struct {
 
 	// PidFile is the path to the PID file.
 	PidFile string `json:"pid_file"`
+
+	// MetricsAddr is the listen address for the Prometheus /metrics
+	// endpoint (e.g., ":9464"). Empty disables it.
+	MetricsAddr string `json:"metrics_addr,omitempty"`
 }
 
 // DefaultConfig returns the default daemon configuration.
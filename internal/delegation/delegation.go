@@ -0,0 +1,171 @@
+// Package delegation puts guardrails on the work the Mayor hands out
+// through MCP tools, so it can decompose a request into polecat work
+// without a human relaying each spawn: a cap on spawns per hour, and the
+// model tiers delegated polecats may run on.
+package delegation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// LedgerFile records recent delegated spawns within the town's .runtime
+// directory.
+const LedgerFile = "delegations.json"
+
+// DefaultMaxSpawnsPerHour is the spawn cap when settings name none.
+const DefaultMaxSpawnsPerHour = 6
+
+// Window is the span the spawn cap counts over.
+const Window = time.Hour
+
+var (
+	// ErrRateLimited is returned when the hour's spawns are used up.
+	ErrRateLimited = errors.New("delegated spawn limit reached")
+
+	// ErrTierNotAllowed is returned for a tier outside the allowed list.
+	ErrTierNotAllowed = errors.New("model tier not allowed for delegated spawns")
+
+	// ErrDisabled is returned when delegated spawning is turned off.
+	ErrDisabled = errors.New("delegated spawning is turned off")
+)
+
+// Limits are the guardrails on delegated spawns.
+type Limits struct {
+	MaxSpawnsPerHour int      `json:"max_spawns_per_hour"` // Negative: no spawns
+	Tiers            []string `json:"tiers,omitempty"`     // Allowed tiers; empty allows any
+}
+
+// LimitsFrom returns the limits a town's delegation settings set.
+func LimitsFrom(cfg *config.DelegationConfig) Limits {
+	l := Limits{MaxSpawnsPerHour: DefaultMaxSpawnsPerHour}
+	if cfg == nil {
+		return l
+	}
+	if cfg.MaxSpawnsPerHour != 0 {
+		l.MaxSpawnsPerHour = cfg.MaxSpawnsPerHour
+	}
+	l.Tiers = cfg.Tiers
+	return l
+}
+
+// Tier returns the tier a delegated spawn runs on: the requested one if
+// it is allowed, or the first allowed tier when none is requested.
+func (l Limits) Tier(requested string) (string, error) {
+	if len(l.Tiers) == 0 {
+		return requested, nil
+	}
+	if requested == "" {
+		return l.Tiers[0], nil
+	}
+	if !slices.Contains(l.Tiers, requested) {
+		return "", fmt.Errorf("%w: %s (allowed: %s)", ErrTierNotAllowed, requested, strings.Join(l.Tiers, ", "))
+	}
+	return requested, nil
+}
+
+// Spawn is a delegated spawn.
+type Spawn struct {
+	Bead    string    `json:"bead"`
+	Rig     string    `json:"rig"`
+	Formula string    `json:"formula,omitempty"`
+	Tier    string    `json:"tier,omitempty"`
+	By      string    `json:"by"`
+	At      time.Time `json:"at"`
+}
+
+// Ledger is the town's record of recent delegated spawns.
+type Ledger struct {
+	path string
+}
+
+// NewLedger returns the delegation ledger for a town.
+func NewLedger(townRoot string) *Ledger {
+	return &Ledger{path: filepath.Join(townRoot, ".runtime", LedgerFile)}
+}
+
+// Spawn runs a delegated spawn if the limits leave room for it, and
+// records it when run succeeds. The ledger stays locked while run runs,
+// so concurrent delegations can't overshoot the cap.
+func (l *Ledger) Spawn(limits Limits, s Spawn, now time.Time, run func() error) error {
+	if limits.MaxSpawnsPerHour < 0 {
+		return ErrDisabled
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	lock := flock.New(l.path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking delegation ledger: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	spawns, err := l.load()
+	if err != nil {
+		return err
+	}
+	spawns = recent(spawns, now)
+	if len(spawns) >= limits.MaxSpawnsPerHour {
+		next := spawns[0].At.Add(Window)
+		return fmt.Errorf("%w: %d in the last hour; the next is allowed at %s", ErrRateLimited, len(spawns), next.Format(time.Kitchen))
+	}
+	if err := run(); err != nil {
+		return err
+	}
+	if s.At.IsZero() {
+		s.At = now
+	}
+	return l.save(append(spawns, s))
+}
+
+// Recent returns the spawns within the window before now, oldest first.
+func (l *Ledger) Recent(now time.Time) ([]Spawn, error) {
+	spawns, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	return recent(spawns, now), nil
+}
+
+func recent(spawns []Spawn, now time.Time) []Spawn {
+	var out []Spawn
+	for _, s := range spawns {
+		if now.Sub(s.At) < Window {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (l *Ledger) load() ([]Spawn, error) {
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading delegation ledger: %w", err)
+	}
+	var spawns []Spawn
+	if err := json.Unmarshal(data, &spawns); err != nil {
+		return nil, fmt.Errorf("parsing delegation ledger: %w", err)
+	}
+	return spawns, nil
+}
+
+func (l *Ledger) save(spawns []Spawn) error {
+	data, err := json.MarshalIndent(spawns, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(l.path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,66 @@
+package delegation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestLimitsTier(t *testing.T) {
+	open := LimitsFrom(nil)
+	if open.MaxSpawnsPerHour != DefaultMaxSpawnsPerHour {
+		t.Errorf("default cap = %d", open.MaxSpawnsPerHour)
+	}
+	if tier, err := open.Tier("opus"); err != nil || tier != "opus" {
+		t.Errorf("Tier(opus) without a list = %q, %v", tier, err)
+	}
+
+	capped := LimitsFrom(&config.DelegationConfig{MaxSpawnsPerHour: 2, Tiers: []string{"haiku", "sonnet"}})
+	if tier, err := capped.Tier(""); err != nil || tier != "haiku" {
+		t.Errorf("Tier(\"\") = %q, %v; want the first allowed tier", tier, err)
+	}
+	if tier, err := capped.Tier("sonnet"); err != nil || tier != "sonnet" {
+		t.Errorf("Tier(sonnet) = %q, %v", tier, err)
+	}
+	if _, err := capped.Tier("opus"); !errors.Is(err, ErrTierNotAllowed) {
+		t.Errorf("Tier(opus) = %v, want ErrTierNotAllowed", err)
+	}
+}
+
+func TestLedgerSpawn(t *testing.T) {
+	ledger := NewLedger(t.TempDir())
+	limits := Limits{MaxSpawnsPerHour: 2}
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	ran := 0
+	run := func() error { ran++; return nil }
+
+	for i, at := range []time.Time{start, start.Add(10 * time.Minute)} {
+		if err := ledger.Spawn(limits, Spawn{Bead: "gt-a", Rig: "gastown"}, at, run); err != nil {
+			t.Fatalf("spawn %d: %v", i, err)
+		}
+	}
+	if err := ledger.Spawn(limits, Spawn{Bead: "gt-b"}, start.Add(30*time.Minute), run); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("third spawn in the hour = %v, want ErrRateLimited", err)
+	}
+	if ran != 2 {
+		t.Errorf("ran %d spawns, want 2", ran)
+	}
+
+	// A failed spawn doesn't use up the allowance
+	later := start.Add(61 * time.Minute)
+	if err := ledger.Spawn(limits, Spawn{Bead: "gt-c"}, later, func() error { return errors.New("sling failed") }); err == nil {
+		t.Error("failing spawn returned no error")
+	}
+	if recent, _ := ledger.Recent(later); len(recent) != 1 {
+		t.Errorf("recent spawns after the first aged out = %d, want 1", len(recent))
+	}
+	if err := ledger.Spawn(limits, Spawn{Bead: "gt-c"}, later, run); err != nil {
+		t.Errorf("spawn once the oldest aged out: %v", err)
+	}
+
+	if err := ledger.Spawn(Limits{MaxSpawnsPerHour: -1}, Spawn{}, later, run); !errors.Is(err, ErrDisabled) {
+		t.Errorf("spawn with spawning off = %v, want ErrDisabled", err)
+	}
+}
@@ -0,0 +1,282 @@
+// Package digest summarizes a window of town activity, usually the last
+// 24 hours, for the Deacon's nightly report: beads closed, merges,
+// failures, escalations and spend.
+package digest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/stats"
+)
+
+// Failure kinds.
+const (
+	FailureMerge    = "merge"
+	FailureMolecule = "molecule"
+	FailureSession  = "session"
+)
+
+// Failure is something that went wrong in the window.
+type Failure struct {
+	At      time.Time `json:"at"`
+	Kind    string    `json:"kind"`
+	Rig     string    `json:"rig,omitempty"`
+	Subject string    `json:"subject"` // Branch, molecule or session
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// Escalation is one escalation sent in the window.
+type Escalation struct {
+	At       time.Time `json:"at"`
+	Actor    string    `json:"actor"`
+	Severity string    `json:"severity,omitempty"`
+	Source   string    `json:"source,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// Rig is one rig's share of the digest.
+type Rig struct {
+	Name   string       `json:"name"`
+	Closed int          `json:"closed"`
+	Merges stats.Merges `json:"merges"`
+}
+
+// Digest is the activity of one window.
+type Digest struct {
+	Since       time.Time    `json:"since"`
+	Until       time.Time    `json:"until"`
+	Closed      []stats.Bead `json:"closed"`
+	Merges      stats.Merges `json:"merges"`
+	Rigs        []Rig        `json:"rigs"`
+	Failures    []Failure    `json:"failures"`
+	Escalations []Escalation `json:"escalations"`
+	CostUSD     float64      `json:"cost_usd"`
+	Tokens      int64        `json:"tokens"`
+	Sessions    int          `json:"sessions"` // Sessions whose spend was recorded
+}
+
+// Input is what a digest is built from.
+type Input struct {
+	Beads  []stats.Bead
+	Runs   []*molruns.Run
+	Merges []stats.Merge
+	Events []*events.Event // Escalations, session deaths and spend
+}
+
+// Build summarizes the activity between since and until.
+func Build(in Input, since, until time.Time) *Digest {
+	d := &Digest{Since: since, Until: until, Closed: []stats.Bead{}, Rigs: []Rig{}, Failures: []Failure{}, Escalations: []Escalation{}}
+	within := func(t time.Time) bool { return !t.Before(since) && t.Before(until) }
+	rigs := map[string]*Rig{}
+	rigFor := func(name string) *Rig {
+		if name == "" {
+			name = "town"
+		}
+		r, ok := rigs[name]
+		if !ok {
+			r = &Rig{Name: name}
+			rigs[name] = r
+		}
+		return r
+	}
+
+	rep := stats.Compute(stats.Input{Beads: in.Beads, Merges: in.Merges}, stats.Options{Since: since, Until: until})
+	d.Merges = rep.Merges
+	for _, r := range rep.Rigs {
+		rig := rigFor(r.Name)
+		rig.Closed = r.Closed
+		rig.Merges = r.Merges
+	}
+	for _, b := range in.Beads {
+		if within(b.Closed) {
+			d.Closed = append(d.Closed, b)
+		}
+	}
+	sort.SliceStable(d.Closed, func(i, j int) bool { return d.Closed[i].Closed.Before(d.Closed[j].Closed) })
+
+	for _, m := range in.Merges {
+		if m.OK || !within(m.At) {
+			continue
+		}
+		subject := m.Branch
+		if subject == "" {
+			subject = m.MR
+		}
+		d.Failures = append(d.Failures, Failure{At: m.At, Kind: FailureMerge, Rig: m.Rig, Subject: subject, Reason: m.Reason})
+	}
+	for _, run := range in.Runs {
+		at := run.Finished
+		if at.IsZero() {
+			at = run.Started
+		}
+		if run.Status != molruns.StatusFailed || !within(at) {
+			continue
+		}
+		d.Failures = append(d.Failures, Failure{At: at, Kind: FailureMolecule, Rig: run.Rig, Subject: run.Formula + " " + run.ID})
+	}
+
+	for _, e := range in.Events {
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || !within(at) {
+			continue
+		}
+		str := func(key string) string {
+			v, _ := e.Payload[key].(string)
+			return v
+		}
+		switch e.Type {
+		case events.TypeEscalationSent:
+			severity := str("severity")
+			if severity == "" {
+				severity = str("new_severity")
+			}
+			d.Escalations = append(d.Escalations, Escalation{At: at, Actor: e.Actor, Severity: severity, Source: str("source"), Reason: str("reason")})
+		case events.TypeSessionDeath:
+			if str("caller") == "gt down" {
+				continue // Deliberate shutdown
+			}
+			subject := str("agent")
+			if subject == "" || subject == "unknown" {
+				subject = str("session")
+			}
+			d.Failures = append(d.Failures, Failure{At: at, Kind: FailureSession, Subject: subject, Reason: str("reason")})
+		case events.TypeSessionEnd:
+			cost, _ := e.Payload["cost_usd"].(float64)
+			tokens, _ := e.Payload["tokens"].(float64)
+			d.CostUSD += cost
+			d.Tokens += int64(tokens)
+			d.Sessions++
+		}
+	}
+	sort.SliceStable(d.Failures, func(i, j int) bool { return d.Failures[i].At.Before(d.Failures[j].At) })
+
+	for _, r := range rigs {
+		d.Rigs = append(d.Rigs, *r)
+	}
+	sort.Slice(d.Rigs, func(i, j int) bool { return d.Rigs[i].Name < d.Rigs[j].Name })
+	return d
+}
+
+// LoadEvents reads the town event log from since on. A missing log has
+// no events.
+func LoadEvents(townRoot string, since time.Time) ([]*events.Event, error) {
+	var evs []*events.Event
+	err := events.Tail(context.Background(), townRoot, events.TailOptions{Lines: -1, Since: since}, func(e *events.Event) {
+		evs = append(evs, e)
+	})
+	return evs, err
+}
+
+// Empty reports whether nothing happened in the window.
+func (d *Digest) Empty() bool {
+	return len(d.Closed) == 0 && d.Merges.Merged+d.Merges.Failed == 0 &&
+		len(d.Failures) == 0 && len(d.Escalations) == 0 && d.Sessions == 0
+}
+
+// Headline is a one-line summary, used as the notification title and
+// mail subject.
+func (d *Digest) Headline() string {
+	return fmt.Sprintf("📰 Gas Town digest: %d closed, %d merged, %d failure(s), %d escalation(s), $%.2f",
+		len(d.Closed), d.Merges.Merged, len(d.Failures), len(d.Escalations), d.CostUSD)
+}
+
+// Summary is the digest's totals as a short markdown list, small enough
+// for a chat notification.
+func Summary(d *Digest) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "- Beads closed: %d\n", len(d.Closed))
+	fmt.Fprintf(&sb, "- Merges: %d merged, %d failed", d.Merges.Merged, d.Merges.Failed)
+	if d.Merges.Merged+d.Merges.Failed > 0 {
+		fmt.Fprintf(&sb, " (%.0f%% success)", d.Merges.SuccessRate*100)
+	}
+	fmt.Fprintf(&sb, "\n- Failures: %d\n", len(d.Failures))
+	fmt.Fprintf(&sb, "- Escalations: %d\n", len(d.Escalations))
+	fmt.Fprintf(&sb, "- Cost: $%.2f over %d session(s)", d.CostUSD, d.Sessions)
+	if d.Tokens > 0 {
+		fmt.Fprintf(&sb, ", %d tokens", d.Tokens)
+	}
+	return sb.String()
+}
+
+// maxListed caps each list in the rendered digest; the JSON has them all.
+const maxListed = 20
+
+// Render formats the digest as markdown.
+func Render(d *Digest) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Gas Town digest\n\n%s to %s\n\n", d.Since.Local().Format("2006-01-02 15:04"), d.Until.Local().Format("2006-01-02 15:04"))
+	if d.Empty() {
+		sb.WriteString("A quiet day: nothing closed, merged, failed or escalated.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(Summary(d) + "\n")
+
+	if len(d.Rigs) > 0 {
+		sb.WriteString("\n## By rig\n\n| Rig | Closed | Merged | Failed |\n|---|---|---|---|\n")
+		for _, r := range d.Rigs {
+			fmt.Fprintf(&sb, "| %s | %d | %d | %d |\n", r.Name, r.Closed, r.Merges.Merged, r.Merges.Failed)
+		}
+	}
+
+	if len(d.Escalations) > 0 {
+		sb.WriteString("\n## Escalations\n\n")
+		for i, e := range d.Escalations {
+			if i == maxListed {
+				fmt.Fprintf(&sb, "- ...and %d more\n", len(d.Escalations)-maxListed)
+				break
+			}
+			line := fmt.Sprintf("- %s %s", e.At.Local().Format("15:04"), e.Actor)
+			if e.Severity != "" {
+				line += " (" + e.Severity + ")"
+			}
+			if e.Reason != "" {
+				line += ": " + e.Reason
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	if len(d.Failures) > 0 {
+		sb.WriteString("\n## Failures\n\n")
+		for i, f := range d.Failures {
+			if i == maxListed {
+				fmt.Fprintf(&sb, "- ...and %d more\n", len(d.Failures)-maxListed)
+				break
+			}
+			line := fmt.Sprintf("- %s %s %s", f.At.Local().Format("15:04"), f.Kind, f.Subject)
+			if f.Rig != "" {
+				line += " [" + f.Rig + "]"
+			}
+			if f.Reason != "" {
+				line += ": " + f.Reason
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	if len(d.Closed) > 0 {
+		sb.WriteString("\n## Closed\n\n")
+		for i, b := range d.Closed {
+			if i == maxListed {
+				fmt.Fprintf(&sb, "- ...and %d more\n", len(d.Closed)-maxListed)
+				break
+			}
+			line := "- " + b.ID
+			if b.Title != "" {
+				line += " " + b.Title
+			}
+			if b.Rig != "" {
+				line += " [" + b.Rig + "]"
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/stats"
+)
+
+func TestBuild(t *testing.T) {
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	at := func(h int) time.Time { return since.Add(time.Duration(h) * time.Hour) }
+	ts := func(h int) string { return at(h).Format(time.RFC3339) }
+
+	in := Input{
+		Beads: []stats.Bead{
+			{Rig: "gastown", ID: "gt-1", Title: "Add widgets", Closed: at(3)},
+			{Rig: "gastown", ID: "gt-2", Closed: at(1)},
+			{Rig: "gastown", ID: "gt-old", Closed: at(-2)}, // Before the window
+			{ID: "hq-1", Closed: at(5)},
+		},
+		Runs: []*molruns.Run{
+			{ID: "gt-wisp-1", Formula: "mol-polecat-work", Rig: "gastown", Status: molruns.StatusFailed, Started: at(1), Finished: at(2)},
+			{ID: "gt-wisp-2", Formula: "mol-polecat-work", Rig: "gastown", Status: molruns.StatusComplete, Started: at(1), Finished: at(2)},
+		},
+		Merges: []stats.Merge{
+			{Rig: "gastown", At: at(4), OK: true},
+			{Rig: "gastown", At: at(6), OK: false, Branch: "polecat/nux", Reason: "conflict"},
+		},
+		Events: []*events.Event{
+			{Timestamp: ts(7), Type: events.TypeEscalationSent, Actor: "gastown/witness",
+				Payload: map[string]interface{}{"severity": "high", "reason": "polecat stuck"}},
+			{Timestamp: ts(8), Type: events.TypeSessionDeath, Actor: "gt-gastown-nux",
+				Payload: map[string]interface{}{"session": "gt-gastown-nux", "agent": "unknown", "reason": "orphan cleanup"}},
+			{Timestamp: ts(9), Type: events.TypeSessionDeath, Actor: "hq-mayor",
+				Payload: map[string]interface{}{"session": "hq-mayor", "caller": "gt down"}},
+			{Timestamp: ts(10), Type: events.TypeSessionEnd, Payload: map[string]interface{}{"cost_usd": 1.5, "tokens": 1000.0}},
+			{Timestamp: ts(11), Type: events.TypeSessionEnd, Payload: map[string]interface{}{"cost_usd": 0.25}},
+			{Timestamp: ts(30), Type: events.TypeSessionEnd, Payload: map[string]interface{}{"cost_usd": 9.0}}, // After the window
+		},
+	}
+	d := Build(in, since, until)
+
+	var closed []string
+	for _, b := range d.Closed {
+		closed = append(closed, b.ID)
+	}
+	if got := strings.Join(closed, " "); got != "gt-2 gt-1 hq-1" {
+		t.Errorf("closed = %q, want gt-2 gt-1 hq-1", got)
+	}
+	if d.Merges.Merged != 1 || d.Merges.Failed != 1 {
+		t.Errorf("merges = %+v, want 1 merged, 1 failed", d.Merges)
+	}
+	var failures []string
+	for _, f := range d.Failures {
+		failures = append(failures, f.Kind+":"+f.Subject)
+	}
+	if got, want := strings.Join(failures, ", "), "molecule:mol-polecat-work gt-wisp-1, merge:polecat/nux, session:gt-gastown-nux"; got != want {
+		t.Errorf("failures = %q, want %q", got, want)
+	}
+	if len(d.Escalations) != 1 || d.Escalations[0].Severity != "high" {
+		t.Errorf("escalations = %+v, want one high", d.Escalations)
+	}
+	if d.CostUSD != 1.75 || d.Tokens != 1000 || d.Sessions != 2 {
+		t.Errorf("cost = $%.2f, %d tokens, %d sessions; want $1.75, 1000, 2", d.CostUSD, d.Tokens, d.Sessions)
+	}
+	if len(d.Rigs) != 2 || d.Rigs[0].Name != "gastown" || d.Rigs[0].Closed != 2 || d.Rigs[1].Name != "town" {
+		t.Errorf("rigs = %+v", d.Rigs)
+	}
+
+	out := Render(d)
+	for _, want := range []string{"- Beads closed: 3", "50% success", "| gastown | 2 | 1 | 1 |", "## Escalations", "polecat stuck", "- gt-1 Add widgets [gastown]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	d := Build(Input{}, time.Now().Add(-24*time.Hour), time.Now())
+	if !d.Empty() {
+		t.Fatalf("Empty() = false for %+v", d)
+	}
+	if out := Render(d); !strings.Contains(out, "A quiet day") {
+		t.Errorf("Render(empty) = %q", out)
+	}
+}
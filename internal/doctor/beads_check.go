@@ -7,9 +7,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
 )
 
 // BeadsDatabaseCheck verifies that the beads database is properly initialized.
@@ -165,8 +168,9 @@ func (c *BeadsDatabaseCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
-// PrefixConflictCheck detects duplicate prefixes across rigs in routes.jsonl.
-// Duplicate prefixes break prefix-based routing.
+// PrefixConflictCheck detects beads prefixes used by more than one rig (or
+// by a rig and the town), in rigs.json, rig config.json files or
+// routes.jsonl. Duplicate prefixes break prefix-based routing.
 type PrefixConflictCheck struct {
 	BaseCheck
 }
@@ -182,22 +186,22 @@ func NewPrefixConflictCheck() *PrefixConflictCheck {
 	}
 }
 
-// Run checks for duplicate prefixes in routes.jsonl.
+// Run checks the town's prefix registry and routes.jsonl for duplicates.
 func (c *PrefixConflictCheck) Run(ctx *CheckContext) *CheckResult {
 	beadsDir := filepath.Join(ctx.TownRoot, ".beads")
-
-	// Check if routes.jsonl exists
-	routesPath := filepath.Join(beadsDir, beads.RoutesFileName)
-	if _, err := os.Stat(routesPath); os.IsNotExist(err) {
+	rigsConfig, _ := config.LoadRigsConfig(filepath.Join(ctx.TownRoot, "mayor", "rigs.json"))
+	reg, err := rig.LoadPrefixRegistry(ctx.TownRoot, rigsConfig)
+	if err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
-			Status:  StatusOK,
-			Message: "No routes.jsonl file (prefix routing not configured)",
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Could not check prefixes: %v", err),
 		}
 	}
+	collisions := reg.Collisions()
 
-	// Find conflicts
-	conflicts, err := beads.FindConflictingPrefixes(beadsDir)
+	// One rig routing a prefix to two places is a conflict too
+	routeConflicts, err := beads.FindConflictingPrefixes(beadsDir)
 	if err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -206,26 +210,31 @@ func (c *PrefixConflictCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	if len(conflicts) == 0 {
+	var details []string
+	for prefix, owners := range collisions {
+		details = append(details, fmt.Sprintf("Prefix %q used by: %s", prefix, strings.Join(owners, ", ")))
+	}
+	for prefix, paths := range routeConflicts {
+		if _, ok := collisions[strings.TrimSuffix(prefix, "-")]; ok {
+			continue
+		}
+		details = append(details, fmt.Sprintf("Prefix %q routed to: %s", prefix, strings.Join(paths, ", ")))
+	}
+	if len(details) == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusOK,
 			Message: "No prefix conflicts found",
 		}
 	}
-
-	// Build details
-	var details []string
-	for prefix, paths := range conflicts {
-		details = append(details, fmt.Sprintf("Prefix %q used by: %s", prefix, strings.Join(paths, ", ")))
-	}
+	sort.Strings(details)
 
 	return &CheckResult{
 		Name:    c.Name(),
 		Status:  StatusError,
-		Message: fmt.Sprintf("%d prefix conflict(s) found in routes.jsonl", len(conflicts)),
+		Message: fmt.Sprintf("%d prefix conflict(s) found", len(details)),
 		Details: details,
-		FixHint: "Use 'bd rename-prefix <new-prefix>' in one of the conflicting rigs to resolve",
+		FixHint: "Use 'gt prefix rename <rig> <new-prefix>' on one of the conflicting rigs to resolve",
 	}
 }
 
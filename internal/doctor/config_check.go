@@ -371,7 +371,7 @@ func (c *SessionHookCheck) usesSessionStartScript(content, hookType string) bool
 
 	// Find the end of this hook section (next top-level key at same depth)
 	// Simple approach: look until we find another "Session" or "User" or end of hooks
-	endMarkers := []string{`"SessionStart"`, `"PreCompact"`, `"UserPromptSubmit"`, `"Stop"`, `"Notification"`}
+	endMarkers := []string{`"SessionStart"`, `"PreCompact"`, `"UserPromptSubmit"`, `"PostToolUse"`, `"Stop"`, `"Notification"`}
 	sectionEnd := len(section)
 	for _, marker := range endMarkers {
 		if marker == `"`+hookType+`"` {
@@ -0,0 +1,492 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// DefaultStaleBranchDays is how old an unused polecat branch must be
+// before DanglingBranchCheck reports it.
+const DefaultStaleBranchDays = 14
+
+// syncBranch is the branch bd sync commits beads data to.
+const syncBranch = "beads-sync"
+
+// DeepChecks returns the slow repository integrity checks run by
+// 'gt doctor --deep'. Polecat branches idle for staleDays are dangling.
+func DeepChecks(staleDays int) []Check {
+	return []Check{
+		NewGitFsckCheck(),
+		NewBeadsIntegrityCheck(),
+		NewOrphanWorktreeCheck(),
+		NewSyncBranchDivergenceCheck(),
+		NewDanglingBranchCheck(staleDays),
+	}
+}
+
+// rigRepo is a rig's canonical repository: the shared bare repo
+// (.repo.git) polecat worktrees hang off, or mayor/rig without one.
+type rigRepo struct {
+	rig  string
+	path string
+}
+
+// canonicalRepos returns every rig's canonical repositories. With all
+// set, a rig with both a bare repo and a mayor clone gives both.
+func canonicalRepos(townRoot string, all bool) []rigRepo {
+	rigs, _ := discoverRigs(townRoot)
+	sort.Strings(rigs)
+	var repos []rigRepo
+	for _, rig := range rigs {
+		for _, loc := range []string{".repo.git", filepath.Join(constants.DirMayor, "rig")} {
+			path := filepath.Join(townRoot, rig, loc)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			repos = append(repos, rigRepo{rig: rig, path: path})
+			if !all {
+				break
+			}
+		}
+	}
+	return repos
+}
+
+// gitIn runs git in dir, returning trimmed stdout or an error carrying
+// git's stderr.
+func gitIn(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// GitFsckCheck runs git fsck on every rig's canonical repositories.
+type GitFsckCheck struct {
+	FixableCheck
+	corrupt []string // Cached during Run for use in Fix
+}
+
+// NewGitFsckCheck creates a new git fsck check.
+func NewGitFsckCheck() *GitFsckCheck {
+	return &GitFsckCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "git-fsck",
+				CheckDescription: "Verify object integrity of canonical rig repositories",
+				CheckCategory:    CategoryRig,
+			},
+		},
+	}
+}
+
+// Run fscks each canonical repository.
+func (c *GitFsckCheck) Run(ctx *CheckContext) *CheckResult {
+	repos := canonicalRepos(ctx.TownRoot, true)
+	c.corrupt = nil
+	var details []string
+	for _, r := range repos {
+		if _, err := gitIn(r.path, "fsck", "--no-progress", "--no-dangling"); err != nil {
+			c.corrupt = append(c.corrupt, r.path)
+			rel, _ := filepath.Rel(ctx.TownRoot, r.path)
+			details = append(details, fmt.Sprintf("%s: %s", rel, firstLine(err.Error())))
+		}
+	}
+
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d repository(ies) failed git fsck", len(details)),
+			Details: details,
+			FixHint: "Run 'gt doctor --deep --fix' to refetch all objects from origin",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d canonical repository(ies) pass git fsck", len(repos)),
+	}
+}
+
+// Fix refetches every object of a corrupt repository from origin.
+func (c *GitFsckCheck) Fix(ctx *CheckContext) error {
+	var errs []error
+	for _, path := range c.corrupt {
+		if _, err := gitIn(path, "fetch", "--refetch", "origin"); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// BeadsIntegrityCheck runs bd doctor on the town and rig beads databases.
+type BeadsIntegrityCheck struct {
+	FixableCheck
+	failing []string // Cached during Run for use in Fix
+}
+
+// NewBeadsIntegrityCheck creates a new beads integrity check.
+func NewBeadsIntegrityCheck() *BeadsIntegrityCheck {
+	return &BeadsIntegrityCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "beads-integrity",
+				CheckDescription: "Verify beads database integrity (bd doctor)",
+				CheckCategory:    CategoryCore,
+			},
+		},
+	}
+}
+
+// beadsLocations returns the town root and every rig with a beads
+// database.
+func beadsLocations(townRoot string) []string {
+	locations := []string{townRoot}
+	rigs, _ := discoverRigs(townRoot)
+	sort.Strings(rigs)
+	for _, rig := range rigs {
+		if _, err := os.Stat(filepath.Join(townRoot, rig, constants.DirBeads)); err == nil {
+			locations = append(locations, filepath.Join(townRoot, rig))
+		}
+	}
+	return locations
+}
+
+// Run runs bd doctor in each beads location.
+func (c *BeadsIntegrityCheck) Run(ctx *CheckContext) *CheckResult {
+	if _, err := exec.LookPath("bd"); err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "bd not installed; beads databases not checked",
+		}
+	}
+
+	locations := beadsLocations(ctx.TownRoot)
+	c.failing = nil
+	var details []string
+	for _, dir := range locations {
+		cmd := exec.Command("bd", "doctor")
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			c.failing = append(c.failing, dir)
+			rel, _ := filepath.Rel(ctx.TownRoot, dir)
+			details = append(details, fmt.Sprintf("%s: %s", rel, firstLine(strings.TrimSpace(string(out)))))
+		}
+	}
+
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d beads database(s) failed bd doctor", len(details)),
+			Details: details,
+			FixHint: "Run 'gt doctor --deep --fix' to run bd doctor --fix",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d beads database(s) healthy", len(locations)),
+	}
+}
+
+// Fix runs bd doctor --fix where bd doctor failed.
+func (c *BeadsIntegrityCheck) Fix(ctx *CheckContext) error {
+	var errs []error
+	for _, dir := range c.failing {
+		cmd := exec.Command("bd", "doctor", "--fix")
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v: %s", dir, err, firstLine(strings.TrimSpace(string(out)))))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OrphanWorktreeCheck finds worktrees registered in a canonical repository
+// whose directory is gone.
+type OrphanWorktreeCheck struct {
+	FixableCheck
+	repos []string // Cached during Run for use in Fix
+}
+
+// NewOrphanWorktreeCheck creates a new orphaned worktree check.
+func NewOrphanWorktreeCheck() *OrphanWorktreeCheck {
+	return &OrphanWorktreeCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "orphan-worktrees",
+				CheckDescription: "Detect worktrees whose directory no longer exists",
+				CheckCategory:    CategoryCleanup,
+			},
+		},
+	}
+}
+
+// Run lists each canonical repository's worktrees.
+func (c *OrphanWorktreeCheck) Run(ctx *CheckContext) *CheckResult {
+	c.repos = nil
+	var details []string
+	for _, r := range canonicalRepos(ctx.TownRoot, false) {
+		out, err := gitIn(r.path, "worktree", "list", "--porcelain")
+		if err != nil {
+			continue
+		}
+		orphans := 0
+		for _, line := range strings.Split(out, "\n") {
+			path, ok := strings.CutPrefix(line, "worktree ")
+			if !ok {
+				continue
+			}
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				details = append(details, fmt.Sprintf("%s: %s", r.rig, path))
+				orphans++
+			}
+		}
+		if orphans > 0 {
+			c.repos = append(c.repos, r.path)
+		}
+	}
+
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%d orphaned worktree(s)", len(details)),
+			Details: details,
+			FixHint: "Run 'gt doctor --deep --fix' to prune them (git worktree prune)",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "No orphaned worktrees",
+	}
+}
+
+// Fix prunes the worktree records of missing directories.
+func (c *OrphanWorktreeCheck) Fix(ctx *CheckContext) error {
+	var errs []error
+	for _, path := range c.repos {
+		if _, err := gitIn(path, "worktree", "prune"); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SyncBranchDivergenceCheck finds clones whose beads-sync branch has
+// diverged from origin's: each has commits the other lacks, so bd sync
+// can no longer fast-forward.
+type SyncBranchDivergenceCheck struct {
+	FixableCheck
+	diverged []string // Cached during Run for use in Fix
+}
+
+// NewSyncBranchDivergenceCheck creates a new sync branch divergence check.
+func NewSyncBranchDivergenceCheck() *SyncBranchDivergenceCheck {
+	return &SyncBranchDivergenceCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "sync-branch-divergence",
+				CheckDescription: "Detect beads-sync branches diverged from origin",
+				CheckCategory:    CategoryRig,
+			},
+		},
+	}
+}
+
+// Run compares each clone's beads-sync with origin/beads-sync.
+func (c *SyncBranchDivergenceCheck) Run(ctx *CheckContext) *CheckResult {
+	c.diverged = nil
+	var details []string
+	for _, clone := range NewCloneDivergenceCheck().findAllClones(ctx.TownRoot) {
+		out, err := gitIn(clone, "rev-list", "--left-right", "--count", syncBranch+"...origin/"+syncBranch)
+		if err != nil {
+			continue // No sync branch here
+		}
+		ahead, behind, ok := parseLeftRight(out)
+		if !ok || ahead == 0 || behind == 0 {
+			continue
+		}
+		c.diverged = append(c.diverged, clone)
+		rel, _ := filepath.Rel(ctx.TownRoot, clone)
+		details = append(details, fmt.Sprintf("%s: %d local, %d remote commit(s)", rel, ahead, behind))
+	}
+
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%d clone(s) with diverged %s", len(details), syncBranch),
+			Details: details,
+			FixHint: "Run 'gt doctor --deep --fix' to reset them to origin (the local tip is kept as a backup branch), then bd sync",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("No diverged %s branches", syncBranch),
+	}
+}
+
+// Fix keeps each diverged branch's tip as beads-sync-diverged-<date> and
+// resets the branch to origin's. The beads data itself lives in the
+// database, so the next bd sync exports anything the reset dropped.
+func (c *SyncBranchDivergenceCheck) Fix(ctx *CheckContext) error {
+	backup := syncBranch + "-diverged-" + time.Now().Format("20060102-150405")
+	var errs []error
+	for _, clone := range c.diverged {
+		if _, err := gitIn(clone, "branch", backup, syncBranch); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", clone, err))
+			continue
+		}
+		current, _ := gitIn(clone, "branch", "--show-current")
+		var err error
+		if current == syncBranch {
+			_, err = gitIn(clone, "reset", "--hard", "origin/"+syncBranch)
+		} else {
+			_, err = gitIn(clone, "branch", "-f", syncBranch, "origin/"+syncBranch)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", clone, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseLeftRight parses the "<left>\t<right>" output of
+// git rev-list --left-right --count.
+func parseLeftRight(out string) (left, right int, ok bool) {
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	l, err1 := strconv.Atoi(fields[0])
+	r, err2 := strconv.Atoi(fields[1])
+	return l, r, err1 == nil && err2 == nil
+}
+
+// DanglingBranchCheck finds polecat branches no worktree has checked out
+// and nobody has committed to for a while: leftovers of nuked polecats.
+type DanglingBranchCheck struct {
+	FixableCheck
+	staleDays int
+	dangling  map[string][]string // Repo path to branches, cached for Fix
+}
+
+// NewDanglingBranchCheck creates a new dangling branch check for branches
+// idle for staleDays (DefaultStaleBranchDays if not positive).
+func NewDanglingBranchCheck(staleDays int) *DanglingBranchCheck {
+	if staleDays <= 0 {
+		staleDays = DefaultStaleBranchDays
+	}
+	return &DanglingBranchCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "dangling-polecat-branches",
+				CheckDescription: "Detect old polecat branches with no worktree",
+				CheckCategory:    CategoryCleanup,
+			},
+		},
+		staleDays: staleDays,
+	}
+}
+
+// Run lists each canonical repository's idle polecat branches.
+func (c *DanglingBranchCheck) Run(ctx *CheckContext) *CheckResult {
+	cutoff := time.Now().AddDate(0, 0, -c.staleDays)
+	c.dangling = make(map[string][]string)
+	var details []string
+	for _, r := range canonicalRepos(ctx.TownRoot, false) {
+		out, err := gitIn(r.path, "for-each-ref", "--format=%(refname:short) %(committerdate:unix)", "refs/heads/polecat/")
+		if err != nil || out == "" {
+			continue
+		}
+		checkedOut := worktreeBranches(r.path)
+		for _, line := range strings.Split(out, "\n") {
+			branch, unix, ok := strings.Cut(line, " ")
+			if !ok || checkedOut[branch] {
+				continue
+			}
+			secs, err := strconv.ParseInt(unix, 10, 64)
+			if err != nil || !time.Unix(secs, 0).Before(cutoff) {
+				continue
+			}
+			c.dangling[r.path] = append(c.dangling[r.path], branch)
+			days := int(time.Since(time.Unix(secs, 0)).Hours() / 24)
+			details = append(details, fmt.Sprintf("%s: %s (%d days)", r.rig, branch, days))
+		}
+	}
+
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%d polecat branch(es) idle over %d days with no worktree", len(details), c.staleDays),
+			Details: details,
+			FixHint: "Run 'gt doctor --deep --fix' to delete them",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "No dangling polecat branches",
+	}
+}
+
+// Fix deletes the dangling branches.
+func (c *DanglingBranchCheck) Fix(ctx *CheckContext) error {
+	var errs []error
+	for repo, branches := range c.dangling {
+		for _, branch := range branches {
+			if _, err := gitIn(repo, "branch", "-D", branch); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", branch, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// worktreeBranches returns the branches checked out in a repository's
+// worktrees.
+func worktreeBranches(repo string) map[string]bool {
+	branches := make(map[string]bool)
+	out, err := gitIn(repo, "worktree", "list", "--porcelain")
+	if err != nil {
+		return branches
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if ref, ok := strings.CutPrefix(line, "branch refs/heads/"); ok {
+			branches[ref] = true
+		}
+	}
+	return branches
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,104 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// deepTestTown creates a town with one rig, "gastown", whose mayor/rig is
+// a git repository with one commit on main.
+func deepTestTown(t *testing.T) (town, repo string) {
+	t.Helper()
+	town = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigs := `{"version":1,"rigs":{"gastown":{"git_url":"https://example.com/gastown.git"}}}`
+	if err := os.WriteFile(filepath.Join(town, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo = filepath.Join(town, "gastown", "mayor", "rig")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "init", "-q", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "init")
+	return town, repo
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestDanglingBranchCheck(t *testing.T) {
+	town, repo := deepTestTown(t)
+	cmd := exec.Command("git", "commit", "-q", "--allow-empty", "-m", "old work")
+	cmd.Dir = repo
+	cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2020-01-01T00:00:00Z")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("old commit: %v\n%s", err, out)
+	}
+	runGit(t, repo, "branch", "polecat/old")
+	runGit(t, repo, "reset", "-q", "--hard", "HEAD~1")
+	runGit(t, repo, "branch", "polecat/fresh")
+	runGit(t, repo, "worktree", "add", "-q", filepath.Join(t.TempDir(), "busy"), "-b", "polecat/busy", "polecat/old")
+
+	ctx := &CheckContext{TownRoot: town}
+	check := NewDanglingBranchCheck(0)
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || len(result.Details) != 1 {
+		t.Fatalf("Run = %v %q %v, want one dangling branch", result.Status, result.Message, result.Details)
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after Fix: %v %v", result.Status, result.Details)
+	}
+}
+
+func TestOrphanWorktreeCheck(t *testing.T) {
+	town, repo := deepTestTown(t)
+	wt := filepath.Join(t.TempDir(), "gone")
+	runGit(t, repo, "worktree", "add", "-q", wt, "-b", "polecat/gone")
+	if err := os.RemoveAll(wt); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := &CheckContext{TownRoot: town}
+	check := NewOrphanWorktreeCheck()
+	if result := check.Run(ctx); result.Status != StatusWarning {
+		t.Fatalf("Run = %v %q, want warning", result.Status, result.Message)
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after Fix: %v %v", result.Status, result.Details)
+	}
+}
+
+func TestGitFsckCheck_Healthy(t *testing.T) {
+	town, _ := deepTestTown(t)
+	if result := NewGitFsckCheck().Run(&CheckContext{TownRoot: town}); result.Status != StatusOK {
+		t.Errorf("Run = %v %v, want OK", result.Status, result.Details)
+	}
+}
+
+func TestParseLeftRight(t *testing.T) {
+	if l, r, ok := parseLeftRight("3\t2"); !ok || l != 3 || r != 2 {
+		t.Errorf("parseLeftRight = %d, %d, %v", l, r, ok)
+	}
+	if _, _, ok := parseLeftRight("fatal"); ok {
+		t.Error("parseLeftRight accepted garbage")
+	}
+}
@@ -0,0 +1,211 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// shadowedSuffix is appended to a stale copy that Fix moves out of the way.
+const shadowedSuffix = ".shadowed"
+
+// installedBinary is one copy of a binary found on PATH.
+type installedBinary struct {
+	Path    string // Where PATH finds it
+	Real    string // With symlinks resolved
+	Brew    bool   // Installed by Homebrew
+	Version string // First line of "<binary> version", if it ran
+}
+
+// InstallCheck detects mixed installation states for a binary: several
+// different copies on PATH, and in particular a Homebrew install shadowed
+// by an older copy earlier on PATH (a ~/bin or ~/go/bin left over from a
+// source install, say).
+type InstallCheck struct {
+	FixableCheck
+	binary    string
+	pathEnv   func() string
+	home      func() (string, error)
+	versionOf func(path string) string
+
+	shadowing []string // Copies in the home directory shadowing a brew install
+}
+
+// NewGtInstallCheck creates the check for gt binaries.
+func NewGtInstallCheck() *InstallCheck {
+	return newInstallCheck("gt-install", "gt")
+}
+
+// NewBdInstallCheck creates the check for bd binaries.
+func NewBdInstallCheck() *InstallCheck {
+	return newInstallCheck("bd-install", "bd")
+}
+
+func newInstallCheck(name, binary string) *InstallCheck {
+	return &InstallCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        name,
+				CheckDescription: fmt.Sprintf("Check for conflicting %s installs on PATH", binary),
+				CheckCategory:    CategoryInfrastructure,
+			},
+		},
+		binary:    binary,
+		pathEnv:   func() string { return os.Getenv("PATH") },
+		home:      os.UserHomeDir,
+		versionOf: binaryVersion,
+	}
+}
+
+// Run finds every copy of the binary on PATH.
+func (c *InstallCheck) Run(ctx *CheckContext) *CheckResult {
+	c.shadowing = nil
+	found := c.find()
+
+	switch len(found) {
+	case 0:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%s not found on PATH", c.binary),
+			FixHint: fmt.Sprintf("Install %s (brew install %s, or go install)", c.binary, c.binary),
+		}
+	case 1:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("One %s on PATH: %s", c.binary, describeBinary(found[0])),
+		}
+	}
+
+	active := found[0]
+	var details []string
+	for i, b := range found {
+		line := describeBinary(b)
+		if i == 0 {
+			line += "  ← used"
+		}
+		details = append(details, line)
+	}
+
+	var brew *installedBinary
+	for i := range found {
+		if found[i].Brew {
+			brew = &found[i]
+			break
+		}
+	}
+	if brew != nil && !active.Brew {
+		home, _ := c.home()
+		for _, b := range found {
+			if b.Path == brew.Path {
+				break
+			}
+			if home != "" && strings.HasPrefix(b.Path, home+string(filepath.Separator)) {
+				c.shadowing = append(c.shadowing, b.Path)
+			}
+		}
+		hint := fmt.Sprintf("Remove the copy shadowing Homebrew's, or put %s first on PATH", filepath.Dir(brew.Path))
+		if len(c.shadowing) > 0 {
+			hint = fmt.Sprintf("Run 'gt doctor --fix' to move the shadowing copy aside (renamed *%s)", shadowedSuffix)
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Homebrew %s at %s is shadowed by %s", c.binary, brew.Path, active.Path),
+			Details: details,
+			FixHint: hint,
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d different %s binaries on PATH; %s is used", len(found), c.binary, active.Path),
+		Details: details,
+		FixHint: fmt.Sprintf("Remove all but one %s so every shell and agent runs the same version", c.binary),
+	}
+}
+
+// Fix moves home-directory copies that shadow a Homebrew install aside, so
+// PATH falls through to the brew copy. Other conflicts need a person to
+// choose which copy to keep.
+func (c *InstallCheck) Fix(ctx *CheckContext) error {
+	if len(c.shadowing) == 0 {
+		return fmt.Errorf("no copy to move aside; remove the extra %s binaries manually", c.binary)
+	}
+	for _, path := range c.shadowing {
+		if err := os.Rename(path, path+shadowedSuffix); err != nil {
+			return fmt.Errorf("moving %s aside: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// find returns the distinct copies on PATH, in PATH order. Entries that
+// resolve to the same file (a brew symlink and the Cellar, say) count once.
+func (c *InstallCheck) find() []installedBinary {
+	var found []installedBinary
+	seen := map[string]bool{}
+	for _, dir := range filepath.SplitList(c.pathEnv()) {
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, c.binary)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+			continue
+		}
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			real = path
+		}
+		if seen[real] {
+			continue
+		}
+		seen[real] = true
+		found = append(found, installedBinary{
+			Path:    path,
+			Real:    real,
+			Brew:    isBrewPath(real),
+			Version: c.versionOf(path),
+		})
+	}
+	return found
+}
+
+// isBrewPath reports whether a resolved binary path is inside a Homebrew
+// Cellar.
+func isBrewPath(real string) bool {
+	if cellar := os.Getenv("HOMEBREW_CELLAR"); cellar != "" && strings.HasPrefix(real, cellar+string(filepath.Separator)) {
+		return true
+	}
+	return strings.Contains(real, string(filepath.Separator)+"Cellar"+string(filepath.Separator))
+}
+
+func describeBinary(b installedBinary) string {
+	s := b.Path
+	if b.Version != "" {
+		s += " (" + b.Version + ")"
+	}
+	if b.Brew {
+		s += " [brew]"
+	}
+	return s
+}
+
+// binaryVersion runs "<path> version" and returns its first line.
+func binaryVersion(path string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "version").Output() //nolint:gosec // G204: path is a binary found on PATH
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return line
+}
@@ -0,0 +1,113 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeBinary creates an executable file at dir/name.
+func writeBinary(t *testing.T, dir, name string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// testInstallCheck returns a gt install check that searches dirs and
+// treats home as the home directory.
+func testInstallCheck(home string, dirs ...string) *InstallCheck {
+	c := NewGtInstallCheck()
+	c.pathEnv = func() string { return strings.Join(dirs, string(os.PathListSeparator)) }
+	c.home = func() (string, error) { return home, nil }
+	c.versionOf = func(path string) string { return "gt v-" + filepath.Base(filepath.Dir(path)) }
+	return c
+}
+
+func TestInstallCheck_Single(t *testing.T) {
+	tmp := t.TempDir()
+	bin := filepath.Join(tmp, "bin")
+	writeBinary(t, bin, "gt")
+
+	result := testInstallCheck(tmp, bin, filepath.Join(tmp, "missing")).Run(&CheckContext{})
+	if result.Status != StatusOK {
+		t.Errorf("status = %v, want OK: %s", result.Status, result.Message)
+	}
+}
+
+func TestInstallCheck_NotFound(t *testing.T) {
+	result := testInstallCheck(t.TempDir()).Run(&CheckContext{})
+	if result.Status != StatusWarning {
+		t.Errorf("status = %v, want warning", result.Status)
+	}
+}
+
+func TestInstallCheck_SymlinkCountsOnce(t *testing.T) {
+	tmp := t.TempDir()
+	cellar := writeBinary(t, filepath.Join(tmp, "Cellar", "gt", "1.0", "bin"), "gt")
+	brewBin := filepath.Join(tmp, "brew", "bin")
+	if err := os.MkdirAll(brewBin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(cellar, filepath.Join(brewBin, "gt")); err != nil {
+		t.Fatal(err)
+	}
+
+	result := testInstallCheck(tmp, brewBin, filepath.Dir(cellar)).Run(&CheckContext{})
+	if result.Status != StatusOK || !strings.Contains(result.Message, "[brew]") {
+		t.Errorf("got %v %q, want OK brew install", result.Status, result.Message)
+	}
+}
+
+func TestInstallCheck_ShadowedBrewFix(t *testing.T) {
+	tmp := t.TempDir()
+	home := filepath.Join(tmp, "home")
+	stale := writeBinary(t, filepath.Join(home, "bin"), "gt")
+	cellar := writeBinary(t, filepath.Join(tmp, "Cellar", "gt", "1.0", "bin"), "gt")
+
+	c := testInstallCheck(home, filepath.Dir(stale), filepath.Dir(cellar))
+	result := c.Run(&CheckContext{})
+	if result.Status != StatusWarning {
+		t.Fatalf("status = %v, want warning", result.Status)
+	}
+	if !strings.Contains(result.Message, "shadowed by "+stale) {
+		t.Errorf("message = %q", result.Message)
+	}
+	if len(result.Details) != 2 || !strings.HasSuffix(result.Details[0], "← used") {
+		t.Errorf("details = %v", result.Details)
+	}
+
+	if err := c.Fix(&CheckContext{}); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if _, err := os.Stat(stale + shadowedSuffix); err != nil {
+		t.Errorf("stale copy not moved aside: %v", err)
+	}
+	if result := c.Run(&CheckContext{}); result.Status != StatusOK {
+		t.Errorf("after fix: %v %q", result.Status, result.Message)
+	}
+}
+
+func TestInstallCheck_DuplicatesNeedManualFix(t *testing.T) {
+	tmp := t.TempDir()
+	a := writeBinary(t, filepath.Join(tmp, "a"), "gt")
+	b := writeBinary(t, filepath.Join(tmp, "b"), "gt")
+
+	c := testInstallCheck(tmp, filepath.Dir(a), filepath.Dir(b))
+	result := c.Run(&CheckContext{})
+	if result.Status != StatusWarning || !strings.Contains(result.Message, "2 different gt binaries") {
+		t.Errorf("got %v %q", result.Status, result.Message)
+	}
+	if err := c.Fix(&CheckContext{}); err == nil {
+		t.Error("Fix should refuse when there is no brew install to fall back to")
+	}
+	if _, err := os.Stat(a); err != nil {
+		t.Errorf("Fix touched %s: %v", a, err)
+	}
+}
@@ -0,0 +1,150 @@
+// Package dryrun implements gt's global --dry-run mode.
+//
+// Side effects funnel through a few choke points: the git, bd and tmux
+// wrappers, config writers, and the helpers here. When dry-run is enabled
+// those record what they would do ("[dry-run] $ git worktree add ...",
+// "[dry-run] write mayor/rigs.json") instead of doing it. Read-only
+// commands still run, so a dry run follows the same path as a real one
+// for as long as it can: a step that reads back something an earlier
+// recorded step would have created sees it missing.
+package dryrun
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	enabled atomic.Bool
+
+	mu  sync.Mutex
+	out io.Writer = os.Stdout
+)
+
+// Set turns dry-run mode on or off for this process.
+func Set(on bool) {
+	enabled.Store(on)
+}
+
+// Enabled reports whether dry-run mode is on.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// SetOutput directs records to w (default os.Stdout).
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	out = w
+	mu.Unlock()
+}
+
+// Record prints an operation that was skipped.
+func Record(format string, args ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	_, _ = fmt.Fprintf(out, "[dry-run] "+format+"\n", args...)
+}
+
+// Command records cmd instead of running it and reports true when dry-run
+// is enabled. Callers that mutate state do:
+//
+//	if dryrun.Command(cmd) {
+//		return nil
+//	}
+func Command(cmd *exec.Cmd) bool {
+	if !Enabled() {
+		return false
+	}
+	Record("%s", FormatCommand(cmd.Dir, cmd.Args))
+	return true
+}
+
+// FormatCommand renders a command line as it would be typed in dir.
+func FormatCommand(dir string, args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = quote(a)
+	}
+	line := "$ " + strings.Join(quoted, " ")
+	if dir != "" {
+		line = "(" + dir + ") " + line
+	}
+	return line
+}
+
+func quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !strings.ContainsAny(s, " \t\n'\"$`\\|&;<>()*?[]#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// MkdirAll is os.MkdirAll, recorded in dry-run mode when path is missing.
+func MkdirAll(path string, perm os.FileMode) error {
+	if !Enabled() {
+		return os.MkdirAll(path, perm)
+	}
+	if _, err := os.Stat(path); err != nil {
+		Record("mkdir -p %s", path)
+	}
+	return nil
+}
+
+// WriteFile is os.WriteFile, recorded in dry-run mode.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if !Enabled() {
+		return os.WriteFile(path, data, perm)
+	}
+	verb := "create"
+	if _, err := os.Stat(path); err == nil {
+		verb = "overwrite"
+	}
+	Record("%s %s (%d bytes)", verb, path, len(data))
+	return nil
+}
+
+// Remove is os.Remove, recorded in dry-run mode.
+func Remove(path string) error {
+	if !Enabled() {
+		return os.Remove(path)
+	}
+	Record("rm %s", path)
+	return nil
+}
+
+// RemoveAll is os.RemoveAll, recorded in dry-run mode when path exists.
+func RemoveAll(path string) error {
+	if !Enabled() {
+		return os.RemoveAll(path)
+	}
+	if _, err := os.Lstat(path); err == nil {
+		Record("rm -rf %s", path)
+	}
+	return nil
+}
+
+// Rename is os.Rename, recorded in dry-run mode.
+func Rename(oldpath, newpath string) error {
+	if !Enabled() {
+		return os.Rename(oldpath, newpath)
+	}
+	Record("mv %s %s", oldpath, newpath)
+	return nil
+}
+
+// Symlink is os.Symlink, recorded in dry-run mode.
+func Symlink(target, link string) error {
+	if !Enabled() {
+		return os.Symlink(target, link)
+	}
+	Record("ln -s %s %s", target, link)
+	return nil
+}
@@ -0,0 +1,84 @@
+package dryrun
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func withDryRun(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	Set(true)
+	SetOutput(&buf)
+	t.Cleanup(func() {
+		Set(false)
+		SetOutput(os.Stdout)
+	})
+	return &buf
+}
+
+func TestCommand(t *testing.T) {
+	cmd := exec.Command("git", "commit", "-m", "it's done")
+	cmd.Dir = "/tmp/rig"
+	if Command(cmd) {
+		t.Fatal("Command reported a dry run while disabled")
+	}
+
+	buf := withDryRun(t)
+	if !Command(cmd) {
+		t.Fatal("Command did not report a dry run while enabled")
+	}
+	want := `[dry-run] (/tmp/rig) $ git commit -m 'it'\''s done'` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("recorded %q, want %q", got, want)
+	}
+}
+
+func TestFileHelpers(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing")
+	if err := os.WriteFile(existing, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	buf := withDryRun(t)
+	steps := []error{
+		MkdirAll(dir, 0755), // exists: not recorded
+		MkdirAll(missing, 0755),
+		WriteFile(existing, []byte("replaced"), 0644),
+		WriteFile(missing, []byte("new"), 0644),
+		Rename(existing, missing),
+		Symlink(existing, missing),
+		Remove(existing),
+		RemoveAll(missing), // missing: not recorded
+	}
+	for i, err := range steps {
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	want := []string{
+		"[dry-run] mkdir -p " + missing,
+		"[dry-run] overwrite " + existing + " (8 bytes)",
+		"[dry-run] create " + missing + " (3 bytes)",
+		"[dry-run] mv " + existing + " " + missing,
+		"[dry-run] ln -s " + existing + " " + missing,
+		"[dry-run] rm " + existing,
+	}
+	if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("recorded:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if data, err := os.ReadFile(existing); err != nil || string(data) != "keep" {
+		t.Errorf("existing file = %q, %v; want untouched", data, err)
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Errorf("missing path was created: %v", err)
+	}
+}
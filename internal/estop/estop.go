@@ -0,0 +1,134 @@
+// Package estop records an emergency stop of the town.
+//
+// 'gt estop' snapshots which agents were running before it stops them all,
+// so 'gt resume --all' can bring the same set back. The snapshot lives in
+// <town>/.runtime/estop.json; while it exists the town is stopped and
+// 'gt up' / 'gt start' refuse to run.
+package estop
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Agent is an agent session that was running when the town was stopped.
+type Agent struct {
+	Session string       `json:"session"`
+	Role    session.Role `json:"role"`
+	Rig     string       `json:"rig,omitempty"`
+	Name    string       `json:"name,omitempty"`
+	Hook    string       `json:"hook,omitempty"` // Bead on the agent's hook, if known
+}
+
+// Address returns the agent's mail-style address (e.g. "greenplace/polecats/Toast").
+func (a Agent) Address() string {
+	id := session.AgentIdentity{Role: a.Role, Rig: a.Rig, Name: a.Name}
+	return id.Address()
+}
+
+// Snapshot is the town's state at the moment of an emergency stop.
+type Snapshot struct {
+	StoppedAt time.Time `json:"stopped_at"`
+	StoppedBy string    `json:"stopped_by,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+
+	// Daemon is true if the daemon was running.
+	Daemon bool `json:"daemon"`
+
+	// DeaconPaused is true if the Deacon was already paused before the
+	// stop, so resuming leaves it paused.
+	DeaconPaused bool `json:"deacon_paused,omitempty"`
+
+	// PausedRefineries lists the rigs whose refineries the stop paused.
+	PausedRefineries []string `json:"paused_refineries,omitempty"`
+
+	// Agents are the sessions that were running, in the order found.
+	Agents []Agent `json:"agents"`
+}
+
+// Path returns the snapshot file for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "estop.json")
+}
+
+// Load reads the town's snapshot. Returns (nil, nil) when the town is not
+// emergency-stopped.
+func Load(townRoot string) (*Snapshot, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes the snapshot, marking the town as emergency-stopped.
+func Save(townRoot string, s *Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(Path(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(Path(townRoot), s)
+}
+
+// Clear removes the snapshot once the town is back up.
+func Clear(townRoot string) error {
+	err := os.Remove(Path(townRoot))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Active reports whether the town is emergency-stopped.
+func Active(townRoot string) bool {
+	s, err := Load(townRoot)
+	return err == nil && s != nil
+}
+
+// AgentsWithRole returns the snapshot's agents with the given role.
+func (s *Snapshot) AgentsWithRole(role session.Role) []Agent {
+	var out []Agent
+	for _, a := range s.Agents {
+		if a.Role == role {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// FromSessions builds the agent list from tmux session names, skipping
+// sessions that aren't Gas Town agents. Town agents come first, then rig
+// agents sorted by rig and name.
+func FromSessions(sessions []string) []Agent {
+	var agents []Agent
+	for _, name := range sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		agents = append(agents, Agent{Session: name, Role: id.Role, Rig: id.Rig, Name: id.Name})
+	}
+	sort.SliceStable(agents, func(i, j int) bool {
+		a, b := agents[i], agents[j]
+		if (a.Rig == "") != (b.Rig == "") {
+			return a.Rig == ""
+		}
+		if a.Rig != b.Rig {
+			return a.Rig < b.Rig
+		}
+		return a.Session < b.Session
+	})
+	return agents
+}
@@ -0,0 +1,87 @@
+package estop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestSaveLoadClear(t *testing.T) {
+	town := t.TempDir()
+
+	if s, err := Load(town); err != nil || s != nil {
+		t.Fatalf("Load with no snapshot = %v, %v", s, err)
+	}
+	if Active(town) {
+		t.Error("town should not be stopped yet")
+	}
+
+	want := &Snapshot{
+		StoppedAt:        time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		StoppedBy:        "human",
+		Reason:           "runaway polecats",
+		Daemon:           true,
+		PausedRefineries: []string{"greenplace"},
+		Agents:           []Agent{{Session: "gt-greenplace-Toast", Role: session.RolePolecat, Rig: "greenplace", Name: "Toast", Hook: "gp-abc"}},
+	}
+	if err := Save(town, want); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if !Active(town) {
+		t.Error("town should be stopped after Save")
+	}
+
+	got, err := Load(town)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !got.StoppedAt.Equal(want.StoppedAt) || got.Reason != want.Reason || !got.Daemon ||
+		len(got.Agents) != 1 || got.Agents[0] != want.Agents[0] || len(got.PausedRefineries) != 1 {
+		t.Errorf("Load = %+v, want %+v", got, want)
+	}
+	if addr := got.Agents[0].Address(); addr != "greenplace/polecats/Toast" {
+		t.Errorf("Address = %q", addr)
+	}
+
+	if err := Clear(town); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if Active(town) {
+		t.Error("town should not be stopped after Clear")
+	}
+	if err := Clear(town); err != nil {
+		t.Errorf("Clear with no snapshot: %v", err)
+	}
+}
+
+func TestFromSessions(t *testing.T) {
+	agents := FromSessions([]string{
+		"gt-greenplace-Toast",
+		"scratch",
+		"gt-greenplace-witness",
+		"hq-mayor",
+		"gt-boot",
+		"gt-alpha-crew-max",
+		"hq-deacon",
+	})
+
+	var got []string
+	for _, a := range agents {
+		got = append(got, a.Session)
+	}
+	want := []string{"hq-deacon", "hq-mayor", "gt-alpha-crew-max", "gt-greenplace-Toast", "gt-greenplace-witness"}
+	if len(got) != len(want) {
+		t.Fatalf("FromSessions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("FromSessions = %v, want %v", got, want)
+		}
+	}
+
+	s := &Snapshot{Agents: agents}
+	if crew := s.AgentsWithRole(session.RoleCrew); len(crew) != 1 || crew[0].Name != "max" || crew[0].Rig != "alpha" {
+		t.Errorf("AgentsWithRole(crew) = %+v", crew)
+	}
+}
@@ -0,0 +1,37 @@
+package events
+
+import "sync"
+
+// Subscribers receive every event logged by this process, after it has been
+// appended to the events file. They run synchronously on the logging
+// goroutine and must not block.
+var (
+	subMu       sync.RWMutex
+	subscribers = map[int]func(Event){}
+	nextSubID   int
+)
+
+// Subscribe registers fn to receive events logged in this process.
+// The returned function removes the subscription.
+func Subscribe(fn func(Event)) (unsubscribe func()) {
+	subMu.Lock()
+	id := nextSubID
+	nextSubID++
+	subscribers[id] = fn
+	subMu.Unlock()
+
+	return func() {
+		subMu.Lock()
+		delete(subscribers, id)
+		subMu.Unlock()
+	}
+}
+
+// publish delivers an event to in-process subscribers.
+func publish(event Event) {
+	subMu.RLock()
+	defer subMu.RUnlock()
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
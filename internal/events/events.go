@@ -1,7 +1,10 @@
-// Package events provides event logging for the gt activity feed.
+// Package events is the town event bus and persistent event log.
 //
-// Events are written to ~/gt/.events.jsonl (raw audit log) and later
-// curated by the feed daemon into ~/.feed.jsonl (user-facing).
+// Every subsystem (sling, spawn, molecule steps, refinery merges,
+// escalations, ...) publishes events here. Events are appended to
+// ~/gt/.events.jsonl (raw, append-only audit log), delivered to in-process
+// subscribers, and later curated by the feed daemon into ~/.feed.jsonl
+// (user-facing). 'gt activity tail' (alias 'gt events tail') reads the log.
 package events
 
 import (
@@ -12,6 +15,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/dryrun"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -62,12 +67,36 @@ const (
 	TypeEscalationAcked  = "escalation_acked"
 	TypeEscalationClosed = "escalation_closed"
 	TypePatrolComplete   = "patrol_complete"
+	TypeStepSlow         = "step_slow" // A molecule step far past its usual duration
 
 	// Merge queue events (emitted by refinery)
 	TypeMergeStarted = "merge_started"
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// External tracker ingestion (gt github)
+	TypeIngest = "ingest"
+
+	// Molecule lifecycle events
+	TypeStepComplete     = "step_complete"
+	TypeMoleculeComplete = "molecule_complete"
+
+	// Human approval gates in molecules (Gate: human)
+	TypeGateAwaiting = "gate_awaiting"
+	TypeGateApproved = "gate_approved"
+	TypeGateRejected = "gate_rejected"
+
+	// Bead aging policy (gt aging run)
+	TypePriorityBumped = "priority_bumped"
+	TypeStaleBlocker   = "stale_blocker"
+
+	// Budget events (spend caps in settings/config.json)
+	TypeBudgetWarning  = "budget_warning"
+	TypeBudgetExceeded = "budget_exceeded"
+
+	// Context recycling of long-lived sessions (gt context check)
+	TypeContextRecycle = "context_recycle"
 )
 
 // EventsFile is the name of the raw events log.
@@ -88,7 +117,11 @@ func Log(eventType, actor string, payload map[string]interface{}, visibility str
 		Payload:    payload,
 		Visibility: visibility,
 	}
-	return write(event)
+	if err := write(event); err != nil {
+		return err
+	}
+	publish(event)
+	return nil
 }
 
 // LogFeed is a convenience wrapper for feed-visible events.
@@ -103,6 +136,11 @@ func LogAudit(eventType, actor string, payload map[string]interface{}) error {
 
 // write appends an event to the events file.
 func write(event Event) error {
+	// A dry run didn't do what the event would report
+	if dryrun.Enabled() {
+		return nil
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil || townRoot == "" {
@@ -119,9 +157,15 @@ func write(event Event) error {
 	}
 	data = append(data, '\n')
 
-	// Append to file with proper locking
+	// Append to file with proper locking; the file lock keeps the event
+	// out of a log Trim is replacing
 	mutex.Lock()
 	defer mutex.Unlock()
+	lock := flock.New(filepath.Join(townRoot, LockFile))
+	if err := lock.RLock(); err != nil {
+		return fmt.Errorf("locking events file: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
 
 	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
 	if err != nil {
@@ -273,6 +317,38 @@ func KillPayload(rig, target, reason string) map[string]interface{} {
 	}
 }
 
+// IngestPayload creates a payload for ingest events.
+// source: external tracker ("github"); ref: external ref of the source item
+func IngestPayload(source, rig, beadID, ref, title string) map[string]interface{} {
+	return map[string]interface{}{
+		"source": source,
+		"rig":    rig,
+		"bead":   beadID,
+		"ref":    ref,
+		"title":  title,
+	}
+}
+
+// MoleculePayload creates a payload for molecule lifecycle events.
+func MoleculePayload(moleculeID, rig string) map[string]interface{} {
+	p := map[string]interface{}{
+		"molecule": moleculeID,
+	}
+	if rig != "" {
+		p["rig"] = rig
+	}
+	return p
+}
+
+// StepPayload creates a payload for molecule step events.
+func StepPayload(moleculeID, stepID, stepTitle string) map[string]interface{} {
+	return map[string]interface{}{
+		"molecule": moleculeID,
+		"step":     stepID,
+		"title":    stepTitle,
+	}
+}
+
 // HaltPayload creates a payload for halt events.
 func HaltPayload(services []string) map[string]interface{} {
 	return map[string]interface{}{
@@ -330,3 +406,99 @@ func SessionPayload(sessionID, role, topic, cwd string) map[string]interface{} {
 	}
 	return p
 }
+
+// SessionCostPayload creates a payload for a session_end event recorded by
+// 'gt costs record'. tokens is omitted when the session didn't report it.
+func SessionCostPayload(session, rig string, costUSD float64, tokens int64) map[string]interface{} {
+	p := map[string]interface{}{
+		"session":  session,
+		"cost_usd": costUSD,
+	}
+	if rig != "" {
+		p["rig"] = rig
+	}
+	if tokens > 0 {
+		p["tokens"] = tokens
+	}
+	return p
+}
+
+// GatePayload creates a payload for approval gate events. feedback is the
+// reviewer's note, omitted when empty.
+func GatePayload(moleculeID, stepID, stepTitle, agent, feedback string) map[string]interface{} {
+	p := map[string]interface{}{
+		"molecule":   moleculeID,
+		"step":       stepID,
+		"step_title": stepTitle,
+		"agent":      agent,
+	}
+	if feedback != "" {
+		p["feedback"] = feedback
+	}
+	return p
+}
+
+// PriorityBumpPayload creates a payload for a priority raised by aging.
+func PriorityBumpPayload(beadID string, from, to int, idleDays int) map[string]interface{} {
+	return map[string]interface{}{
+		"bead":      beadID,
+		"from":      from,
+		"to":        to,
+		"idle_days": idleDays,
+	}
+}
+
+// StaleBlockerPayload creates a payload for a stale bead flagged as holding
+// up others.
+func StaleBlockerPayload(beadID, assignee string, downstream []string, idleDays int) map[string]interface{} {
+	p := map[string]interface{}{
+		"bead":       beadID,
+		"downstream": downstream,
+		"blocked":    len(downstream),
+		"idle_days":  idleDays,
+	}
+	if assignee != "" {
+		p["assignee"] = assignee
+	}
+	return p
+}
+
+// StepSlowPayload creates a payload for a molecule step that has run far
+// longer than its median. Durations are in seconds.
+func StepSlowPayload(moleculeID, stepID, stepRef, formula, agent string, elapsed, median time.Duration, samples int) map[string]interface{} {
+	p := map[string]interface{}{
+		"molecule":       moleculeID,
+		"step":           stepID,
+		"step_ref":       stepRef,
+		"formula":        formula,
+		"elapsed_secs":   int64(elapsed.Seconds()),
+		"median_secs":    int64(median.Seconds()),
+		"median_samples": samples,
+	}
+	if agent != "" {
+		p["agent"] = agent
+	}
+	return p
+}
+
+// BudgetPayload creates a payload for budget warning/exceeded events.
+func BudgetPayload(period, unit string, used, limit, percent float64) map[string]interface{} {
+	return map[string]interface{}{
+		"period":  period,
+		"unit":    unit,
+		"used":    used,
+		"limit":   limit,
+		"percent": percent,
+	}
+}
+
+// ContextRecyclePayload creates a payload for context recycle events.
+// action is "ask" or "force".
+func ContextRecyclePayload(session, action string, tokens int64, percent int) map[string]interface{} {
+	return map[string]interface{}{
+		"session": session,
+		"action":  action,
+		"tokens":  tokens,
+		"percent": percent,
+	}
+}
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFilter(t *testing.T) {
+	merged := &Event{Type: TypeMerged, Actor: "gastown/refinery", Payload: MergePayload("gt-mr1", "nux", "polecat/nux", "")}
+	failed := &Event{Type: TypeMergeFailed, Actor: "gastown/refinery", Payload: MergePayload("gt-mr2", "toast", "polecat/toast", "conflict")}
+	sling := &Event{Type: TypeSling, Actor: "mayor", Payload: SlingPayload("gt-abc", "gastown/polecats/nux")}
+
+	tests := []struct {
+		exprs []string
+		want  []bool // merged, failed, sling
+	}{
+		{nil, []bool{true, true, true}},
+		{[]string{"type=merge"}, []bool{true, true, false}},
+		{[]string{"type=merge_failed"}, []bool{false, true, false}},
+		{[]string{"type=merge", "worker=nux"}, []bool{true, false, false}},
+		{[]string{"type=merge,worker=nux"}, []bool{true, false, false}},
+		{[]string{"actor=gastown/*"}, []bool{true, true, false}},
+		{[]string{"reason=conflict"}, []bool{false, true, false}},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.exprs)
+		if err != nil {
+			t.Fatalf("ParseFilter(%v): %v", tt.exprs, err)
+		}
+		for i, e := range []*Event{merged, failed, sling} {
+			if got := f.Match(e); got != tt.want[i] {
+				t.Errorf("filter %v on %s = %v, want %v", tt.exprs, e.Type, got, tt.want[i])
+			}
+		}
+	}
+
+	if _, err := ParseFilter([]string{"type"}); err == nil {
+		t.Error("expected error for filter without '='")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Chdir(t.TempDir()) // outside a workspace: nothing is written, subscribers still run
+
+	var got []string
+	unsubscribe := Subscribe(func(e Event) { got = append(got, e.Type) })
+	_ = LogFeed(TypeSpawn, "test", SpawnPayload("gastown", "nux"))
+	unsubscribe()
+	_ = LogFeed(TypeKill, "test", nil)
+
+	if len(got) != 1 || got[0] != TypeSpawn {
+		t.Errorf("subscriber got %v, want [spawn]", got)
+	}
+}
+
+func TestTail(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, EventsFile)
+	writeEvents := func(evs ...Event) {
+		t.Helper()
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		for _, e := range evs {
+			data, _ := json.Marshal(e)
+			_, _ = f.Write(append(data, '\n'))
+		}
+	}
+	now := time.Now().UTC()
+	old := now.Add(-2 * time.Hour).Format(time.RFC3339)
+	recent := now.Format(time.RFC3339)
+	writeEvents(
+		Event{Timestamp: old, Type: TypeMerged},
+		Event{Timestamp: recent, Type: TypeSling},
+		Event{Timestamp: recent, Type: TypeMergeFailed},
+		Event{Timestamp: recent, Type: TypeMerged},
+	)
+
+	collect := func(opts TailOptions) []string {
+		var types []string
+		if err := Tail(context.Background(), townRoot, opts, func(e *Event) { types = append(types, e.Type) }); err != nil {
+			t.Fatalf("Tail: %v", err)
+		}
+		return types
+	}
+
+	filter, _ := ParseFilter([]string{"type=merge"})
+	if got := collect(TailOptions{Lines: -1, Filter: filter}); len(got) != 3 {
+		t.Errorf("all merges = %v, want 3 events", got)
+	}
+	if got := collect(TailOptions{Lines: 1, Filter: filter}); len(got) != 1 || got[0] != TypeMerged {
+		t.Errorf("last merge = %v, want [merged]", got)
+	}
+	if got := collect(TailOptions{Lines: -1, Since: now.Add(-time.Hour)}); len(got) != 3 {
+		t.Errorf("since 1h = %v, want 3 events", got)
+	}
+
+	// Follow picks up appended events
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan []string)
+	go func() {
+		var types []string
+		_ = Tail(ctx, townRoot, TailOptions{Lines: 0, Follow: true, Filter: filter}, func(e *Event) {
+			types = append(types, e.Type)
+			if len(types) == 1 {
+				cancel()
+			}
+		})
+		done <- types
+	}()
+	time.Sleep(100 * time.Millisecond)
+	writeEvents(Event{Timestamp: recent, Type: TypeSling}, Event{Timestamp: recent, Type: TypeMergeSkipped})
+
+	select {
+	case got := <-done:
+		if len(got) != 1 || got[0] != TypeMergeSkipped {
+			t.Errorf("followed = %v, want [merge_skipped]", got)
+		}
+	case <-time.After(5 * time.Second):
+		cancel()
+		t.Fatal("follow did not deliver appended event")
+	}
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Filter selects events by field. All conditions must match.
+//
+// Conditions are "key=value". Keys are "type", "actor", "source",
+// "visibility", or any payload field. Values may use shell-style globs
+// (for example actor=gastown/*). The type key also matches by prefix, so
+// type=merge selects merge_started, merged, merge_failed and merge_skipped.
+type Filter []condition
+
+type condition struct {
+	key, value string
+}
+
+// ParseFilter parses "key=value" expressions. Comma-separated conditions
+// within one expression are allowed ("type=merge,rig=gastown").
+func ParseFilter(exprs []string) (Filter, error) {
+	var f Filter
+	for _, expr := range exprs {
+		for _, part := range strings.Split(expr, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(part, "=")
+			if !ok || key == "" {
+				return nil, fmt.Errorf("invalid filter %q: expected key=value", part)
+			}
+			if _, err := path.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %w", part, err)
+			}
+			f = append(f, condition{key: strings.TrimSpace(key), value: strings.TrimSpace(value)})
+		}
+	}
+	return f, nil
+}
+
+// Match reports whether an event satisfies every condition.
+func (f Filter) Match(e *Event) bool {
+	for _, c := range f {
+		if !c.match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c condition) match(e *Event) bool {
+	var actual string
+	switch c.key {
+	case "type":
+		if strings.HasPrefix(e.Type, c.value) {
+			return true
+		}
+		actual = e.Type
+	case "actor":
+		actual = e.Actor
+	case "source":
+		actual = e.Source
+	case "visibility":
+		actual = e.Visibility
+	default:
+		v, ok := e.Payload[c.key]
+		if !ok {
+			return false
+		}
+		actual = fmt.Sprint(v)
+	}
+	ok, _ := path.Match(c.value, actual)
+	return ok
+}
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// LockFile is held shared while an event is appended and exclusively while
+// Trim replaces the log, so no event lands in the old file.
+const LockFile = EventsFile + ".lock"
+
+// RotatedFile records how many bytes of the log Trim carried over from
+// the file it replaced, so readers following the old file can go on in
+// the new one where they left off.
+const RotatedFile = EventsFile + ".rotated"
+
+// Trim moves the events before cutoff out of the town's log, returning
+// their lines. Events are appended in time order, so it is the run of
+// events at the start of the log that are older; a line that doesn't
+// parse goes with them.
+//
+// The kept events are written to a new file that replaces the log. Readers
+// that keep the log open follow the replacement with Reopen.
+func Trim(townRoot string, cutoff time.Time) ([]byte, error) {
+	path := filepath.Join(townRoot, EventsFile)
+	lock := flock.New(filepath.Join(townRoot, LockFile))
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking events file: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	n := trimOffset(data, cutoff)
+	if n == 0 {
+		return nil, nil
+	}
+	kept := data[n:]
+
+	if err := os.WriteFile(filepath.Join(townRoot, RotatedFile), []byte(strconv.Itoa(len(kept))+"\n"), 0644); err != nil { //nolint:gosec // G306: non-sensitive
+		return nil, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, kept, 0644); err != nil { //nolint:gosec // G306: events file is non-sensitive operational data
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+// trimOffset returns where the first event at or after cutoff starts in
+// data.
+func trimOffset(data []byte, cutoff time.Time) int {
+	offset := 0
+	for offset < len(data) {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end < 0 {
+			break // An event being written; keep it
+		}
+		var e Event
+		if json.Unmarshal(data[offset:offset+end], &e) == nil {
+			if ts, err := time.Parse(time.RFC3339, e.Timestamp); err == nil && !ts.Before(cutoff) {
+				break
+			}
+		}
+		offset += end + 1
+	}
+	return offset
+}
+
+// Reopen returns the town's events log when Trim has replaced the file f,
+// positioned just after the events f held. The caller reads f to its end
+// first. It returns nil when f is still the log. A log replaced some other
+// way is opened at its end.
+func Reopen(f *os.File, townRoot string) (*os.File, error) {
+	path := filepath.Join(townRoot, EventsFile)
+	current, err := os.Stat(path)
+	if err != nil {
+		return nil, nil // Keep reading f until a new log appears
+	}
+	open, err := f.Stat()
+	if err != nil || os.SameFile(current, open) {
+		return nil, err
+	}
+
+	nf, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	offset, whence := int64(0), io.SeekEnd
+	if data, err := os.ReadFile(filepath.Join(townRoot, RotatedFile)); err == nil { //nolint:gosec // G304: path is constructed internally
+		if kept, err := strconv.ParseInt(string(bytes.TrimSpace(data)), 10, 64); err == nil {
+			offset, whence = kept, io.SeekStart
+		}
+	}
+	if _, err := nf.Seek(offset, whence); err != nil {
+		_ = nf.Close()
+		return nil, err
+	}
+	return nf, nil
+}
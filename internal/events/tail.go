@@ -0,0 +1,170 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TailOptions controls Tail.
+type TailOptions struct {
+	Lines  int       // Most recent matching events to replay; negative replays all
+	Since  time.Time // Skip events older than this (zero = no limit)
+	Follow bool      // Keep streaming new events until ctx is canceled
+	Filter Filter
+}
+
+// followInterval is how often Tail polls the log for new events.
+const followInterval = 500 * time.Millisecond
+
+// Tail replays recent events from a town's events log and optionally
+// follows it, calling fn for each event that matches the filter.
+func Tail(ctx context.Context, townRoot string, opts TailOptions, fn func(*Event)) error {
+	eventsPath := filepath.Join(townRoot, EventsFile)
+
+	f, err := os.Open(eventsPath) //nolint:gosec // G304: path is constructed internally
+	switch {
+	case os.IsNotExist(err) && opts.Follow:
+		// Wait for the first event to create the file
+		if f, err = waitForFile(ctx, eventsPath); err != nil || f == nil {
+			return err
+		}
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return fmt.Errorf("opening events file: %w", err)
+	}
+	defer f.Close()
+
+	// Replay: keep the last N matches
+	var recent []*Event
+	reader := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break // partial trailing line is picked up by follow
+		}
+		offset += int64(len(line))
+		if e := parseMatching(line, opts); e != nil {
+			recent = append(recent, e)
+			if opts.Lines >= 0 && len(recent) > opts.Lines {
+				recent = recent[1:]
+			}
+		}
+	}
+	for _, e := range recent {
+		fn(e)
+	}
+
+	if !opts.Follow {
+		return nil
+	}
+	return follow(ctx, townRoot, f, offset, opts, fn)
+}
+
+// follow streams events appended after offset.
+func follow(ctx context.Context, townRoot string, f *os.File, offset int64, opts TailOptions, fn func(*Event)) error {
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+	opened := f
+	defer func() {
+		if f != opened {
+			_ = f.Close()
+		}
+	}()
+
+	var partial []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("reading events file: %w", err)
+		}
+		if info.Size() < offset {
+			// Log was truncated or rotated; start over
+			offset, partial = 0, nil
+		}
+		if info.Size() == offset {
+			// gt archive moved old events out of the log
+			if nf, err := Reopen(f, townRoot); err == nil && nf != nil {
+				if f != opened {
+					_ = f.Close()
+				}
+				f, partial = nf, nil
+				if offset, err = f.Seek(0, io.SeekCurrent); err != nil {
+					return fmt.Errorf("seeking events file: %w", err)
+				}
+			}
+			continue
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("seeking events file: %w", err)
+		}
+
+		reader := bufio.NewReader(f)
+		for {
+			line, err := reader.ReadBytes('\n')
+			offset += int64(len(line))
+			if err != nil {
+				partial = append(partial, line...)
+				break
+			}
+			if len(partial) > 0 {
+				line = append(partial, line...)
+				partial = nil
+			}
+			if e := parseMatching(line, opts); e != nil {
+				fn(e)
+			}
+		}
+	}
+}
+
+func waitForFile(ctx context.Context, path string) (*os.File, error) {
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-ticker.C:
+		}
+		f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+		if err == nil {
+			return f, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("opening events file: %w", err)
+		}
+	}
+}
+
+// parseMatching decodes a log line, returning nil if it is malformed or
+// excluded by the options.
+func parseMatching(line []byte, opts TailOptions) *Event {
+	var e Event
+	if err := json.Unmarshal(line, &e); err != nil {
+		return nil
+	}
+	if !opts.Since.IsZero() {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || ts.Before(opts.Since) {
+			return nil
+		}
+	}
+	if !opts.Filter.Match(&e) {
+		return nil
+	}
+	return &e
+}
@@ -0,0 +1,203 @@
+// Package export flattens town data (beads, molecule runs, merges and
+// costs) into tables for spreadsheets and warehouses.
+//
+// Each Table is one kind of record with fixed columns. WriteJSONL writes
+// any number of tables as one stream, each line tagged with its kind;
+// WriteCSV writes a single table with a header row.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/stats"
+)
+
+// Record kinds.
+const (
+	KindBead        = "bead"
+	KindMoleculeRun = "molecule_run"
+	KindMerge       = "merge"
+	KindCost        = "cost"
+)
+
+// Kinds lists every record kind, in export order.
+var Kinds = []string{KindBead, KindMoleculeRun, KindMerge, KindCost}
+
+// Table is one kind of record. Row values are strings, ints, float64s,
+// bools, []string, time.Time or nil; nil and zero times are empty.
+type Table struct {
+	Kind    string
+	Columns []string
+	Rows    [][]any
+}
+
+// BeadColumns are the columns of a bead table.
+var BeadColumns = []string{"rig", "id", "title", "type", "status", "priority", "assignee", "labels", "parent", "points", "created", "updated", "closed"}
+
+// Beads tabulates the beads of one database touched since since. Mail,
+// agent and other bookkeeping beads are left out.
+func Beads(rig string, issues []*beads.Issue, since time.Time) Table {
+	t := Table{Kind: KindBead, Columns: BeadColumns}
+	for _, i := range issues {
+		if beads.IsBookkeeping(i) {
+			continue
+		}
+		updated := parseTime(i.UpdatedAt)
+		if updated.IsZero() {
+			updated = parseTime(i.CreatedAt)
+		}
+		if updated.Before(since) {
+			continue
+		}
+		t.Rows = append(t.Rows, []any{
+			rig, i.ID, i.Title, i.Type, i.Status, i.Priority, i.Assignee, i.Labels, i.Parent,
+			beads.ParseEstimateFields(i).Points(),
+			parseTime(i.CreatedAt), parseTime(i.UpdatedAt), parseTime(i.ClosedAt),
+		})
+	}
+	return t
+}
+
+// RunColumns are the columns of a molecule run table.
+var RunColumns = []string{"id", "formula", "rig", "bead", "target", "status", "started", "finished", "duration_seconds", "steps", "failed_step"}
+
+// Runs tabulates the molecule runs started or finished since since.
+func Runs(runs []*molruns.Run, since time.Time) Table {
+	t := Table{Kind: KindMoleculeRun, Columns: RunColumns}
+	for _, r := range runs {
+		if r.Started.Before(since) && !r.Finished.IsZero() && r.Finished.Before(since) {
+			continue
+		}
+		var duration any // Still running
+		if !r.Finished.IsZero() {
+			duration = int(r.Duration().Seconds())
+		}
+		failed := ""
+		if s := r.FailedStep(); s != nil {
+			failed = s.ID
+		}
+		t.Rows = append(t.Rows, []any{
+			r.ID, r.Formula, r.Rig, r.Bead, r.Target, r.Status, r.Started, r.Finished, duration, len(r.Steps), failed,
+		})
+	}
+	return t
+}
+
+// MergeColumns are the columns of a merge table.
+var MergeColumns = []string{"rig", "at", "ok", "mr", "worker", "branch", "reason"}
+
+// Merges tabulates the refinery merge attempts since since.
+func Merges(merges []stats.Merge, since time.Time) Table {
+	t := Table{Kind: KindMerge, Columns: MergeColumns}
+	for _, m := range merges {
+		if m.At.Before(since) {
+			continue
+		}
+		t.Rows = append(t.Rows, []any{m.Rig, m.At, m.OK, m.MR, m.Worker, m.Branch, m.Reason})
+	}
+	return t
+}
+
+// CostColumns are the columns of a cost table.
+var CostColumns = []string{"session_id", "role", "rig", "worker", "cost_usd", "started", "ended", "work_item"}
+
+// WriteJSONL writes each row of each table as a JSON object on its own
+// line, with a "kind" field first and the columns in table order.
+func WriteJSONL(w io.Writer, tables ...Table) error {
+	for _, t := range tables {
+		for _, row := range t.Rows {
+			var line bytes.Buffer
+			line.WriteString(`{"kind":`)
+			kind, _ := json.Marshal(t.Kind)
+			line.Write(kind)
+			for i, col := range t.Columns {
+				value, err := json.Marshal(jsonValue(row[i]))
+				if err != nil {
+					return fmt.Errorf("%s %s: %w", t.Kind, col, err)
+				}
+				name, _ := json.Marshal(col)
+				line.WriteByte(',')
+				line.Write(name)
+				line.WriteByte(':')
+				line.Write(value)
+			}
+			line.WriteString("}\n")
+			if _, err := w.Write(line.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes a table with a header row.
+func WriteCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+	record := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i := range t.Columns {
+			record[i] = csvValue(row[i])
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonValue makes zero times null and others RFC 3339.
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		return v.UTC().Format(time.RFC3339)
+	case []string:
+		if v == nil {
+			return []string{}
+		}
+	}
+	return v
+}
+
+// csvValue formats a value for a CSV cell. Lists are joined with ";".
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ";")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func parseTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/molruns"
+	"github.com/steveyegge/gastown/internal/stats"
+)
+
+var since = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func TestBeads(t *testing.T) {
+	issues := []*beads.Issue{
+		{ID: "gt-1", Title: "Fix, \"quoted\"", Type: "bug", Status: "closed", Priority: 1,
+			Labels: []string{"auth", "p1"}, Description: "size: M",
+			CreatedAt: "2026-02-20T00:00:00Z", UpdatedAt: "2026-03-02T00:00:00Z", ClosedAt: "2026-03-02T00:00:00Z"},
+		{ID: "gt-old", Status: "open", CreatedAt: "2026-01-01T00:00:00Z", UpdatedAt: "2026-01-02T00:00:00Z"},
+		{ID: "gt-new", Status: "open", CreatedAt: "2026-03-05T00:00:00Z"},
+		{ID: "gt-mail", Type: "message", Status: "open", UpdatedAt: "2026-03-05T00:00:00Z"},
+	}
+	table := Beads("gastown", issues, since)
+	if len(table.Rows) != 2 || table.Rows[0][1] != "gt-1" || table.Rows[1][1] != "gt-new" {
+		t.Fatalf("rows = %v", table.Rows)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != strings.Join(BeadColumns, ",") {
+		t.Errorf("header = %q", lines[0])
+	}
+	want := `gastown,gt-1,"Fix, ""quoted""",bug,closed,1,,auth;p1,,3,2026-02-20T00:00:00Z,2026-03-02T00:00:00Z,2026-03-02T00:00:00Z`
+	if lines[1] != want {
+		t.Errorf("row = %q\nwant  %q", lines[1], want)
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	finished := time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)
+	runs := Runs([]*molruns.Run{
+		{ID: "wisp-1", Formula: "shiny", Status: "complete", Started: finished.Add(-90 * time.Second), Finished: finished,
+			Steps: []molruns.Step{{ID: "s1"}, {ID: "s2"}}},
+		{ID: "wisp-2", Formula: "shiny", Status: "running", Started: since.Add(-time.Hour)},
+		{ID: "wisp-old", Started: since.Add(-2 * time.Hour), Finished: since.Add(-time.Hour)},
+	}, since)
+	merges := Merges([]stats.Merge{
+		{Rig: "gastown", At: finished, OK: false, Branch: "polecat/nux", Reason: "conflict"},
+		{Rig: "gastown", At: since.Add(-time.Minute), OK: true},
+	}, since)
+
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, runs, merges); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], `{"kind":"molecule_run","id":"wisp-1","formula":"shiny"`) {
+		t.Errorf("line 0 = %s", lines[0])
+	}
+
+	var run map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &run); err != nil {
+		t.Fatal(err)
+	}
+	if run["duration_seconds"] != float64(90) || run["steps"] != float64(2) || run["finished"] != "2026-03-02T01:00:00Z" {
+		t.Errorf("run = %v", run)
+	}
+	var running, merge map[string]any
+	_ = json.Unmarshal([]byte(lines[1]), &running)
+	_ = json.Unmarshal([]byte(lines[2]), &merge)
+	if running["finished"] != nil || running["duration_seconds"] != nil {
+		t.Errorf("running run = %v", running)
+	}
+	if merge["kind"] != "merge" || merge["ok"] != false || merge["reason"] != "conflict" {
+		t.Errorf("merge = %v", merge)
+	}
+}
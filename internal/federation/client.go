@@ -0,0 +1,135 @@
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// IssuesAPIPath is the dashboard endpoint that serves issue status to peers.
+const IssuesAPIPath = "/api/issues"
+
+// DefaultTimeout bounds each request to a peer town so an unreachable peer
+// cannot stall convoy status output.
+const DefaultTimeout = 5 * time.Second
+
+// IssueStatus is the wire format for an issue served to peer towns.
+type IssueStatus struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	IssueType string `json:"issue_type,omitempty"`
+	Assignee  string `json:"assignee,omitempty"`
+}
+
+// RemoteRef identifies an issue in a peer town.
+// Format: <peer>:<issue-id> (e.g., "team-b:gt-abc").
+type RemoteRef struct {
+	Peer    string
+	IssueID string
+}
+
+// String returns the reference in canonical form.
+func (r RemoteRef) String() string {
+	return r.Peer + ":" + r.IssueID
+}
+
+// ParseRemoteRef parses a <peer>:<issue-id> reference.
+// Returns ok=false for plain issue IDs and for beads' own "external:" refs.
+func ParseRemoteRef(s string) (RemoteRef, bool) {
+	idx := strings.Index(s, ":")
+	if idx <= 0 || idx == len(s)-1 {
+		return RemoteRef{}, false
+	}
+	peer, issueID := s[:idx], s[idx+1:]
+	if peer == "external" || strings.Contains(issueID, ":") {
+		return RemoteRef{}, false
+	}
+	return RemoteRef{Peer: peer, IssueID: issueID}, true
+}
+
+// Client fetches issue status from a peer town's API.
+type Client struct {
+	peer *Peer
+	http *http.Client
+}
+
+// NewClient creates a client for the given peer.
+func NewClient(peer *Peer) *Client {
+	return &Client{
+		peer: peer,
+		http: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// FetchIssues returns status for the given issue IDs in the peer town.
+// Issues unknown to the peer are omitted from the result.
+func (c *Client) FetchIssues(ids []string) (map[string]*IssueStatus, error) {
+	result := make(map[string]*IssueStatus)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	reqURL := c.peer.URL + IssuesAPIPath + "?ids=" + url.QueryEscape(strings.Join(ids, ","))
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.peer.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.peer.Token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying peer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("peer returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var issues []IssueStatus
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("parsing peer response: %w", err)
+	}
+	for i := range issues {
+		result[issues[i].ID] = &issues[i]
+	}
+	return result, nil
+}
+
+// FetchRemoteIssues resolves a set of remote references against the registry,
+// grouping requests per peer. Per-peer failures are returned in errs keyed by
+// peer name rather than failing the whole lookup.
+func FetchRemoteIssues(cfg *PeersConfig, refs []RemoteRef) (map[RemoteRef]*IssueStatus, map[string]error) {
+	byPeer := make(map[string][]string)
+	for _, ref := range refs {
+		byPeer[ref.Peer] = append(byPeer[ref.Peer], ref.IssueID)
+	}
+
+	results := make(map[RemoteRef]*IssueStatus)
+	errs := make(map[string]error)
+	for peerName, ids := range byPeer {
+		peer, err := cfg.Get(peerName)
+		if err != nil {
+			errs[peerName] = err
+			continue
+		}
+		issues, err := NewClient(peer).FetchIssues(ids)
+		if err != nil {
+			errs[peerName] = err
+			continue
+		}
+		for id, issue := range issues {
+			results[RemoteRef{Peer: peerName, IssueID: id}] = issue
+		}
+	}
+	return results, errs
+}
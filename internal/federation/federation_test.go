@@ -0,0 +1,144 @@
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestParseRemoteRef(t *testing.T) {
+	tests := []struct {
+		input  string
+		wantOK bool
+		peer   string
+		issue  string
+	}{
+		{"team-b:gt-abc", true, "team-b", "gt-abc"},
+		{"gt-abc", false, "", ""},
+		{"external:gastown:gt-abc", false, "", ""},
+		{":gt-abc", false, "", ""},
+		{"team-b:", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ref, ok := ParseRemoteRef(tt.input)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRemoteRef(%q) ok = %v, want %v", tt.input, ok, tt.wantOK)
+			}
+			if ok && (ref.Peer != tt.peer || ref.IssueID != tt.issue) {
+				t.Errorf("ParseRemoteRef(%q) = %+v", tt.input, ref)
+			}
+			if ok && ref.String() != tt.input {
+				t.Errorf("String() = %q, want %q", ref.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestPeersRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	peers, err := LoadPeers(townRoot)
+	if err != nil {
+		t.Fatalf("LoadPeers on empty town: %v", err)
+	}
+	if len(peers.Peers) != 0 {
+		t.Fatalf("expected no peers, got %d", len(peers.Peers))
+	}
+
+	if err := peers.Add("team-b", "http://team-b:8080/", "tok"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := SavePeers(townRoot, peers); err != nil {
+		t.Fatalf("SavePeers: %v", err)
+	}
+
+	info, err := os.Stat(PeersPath(townRoot))
+	if err != nil {
+		t.Fatalf("stat peers file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("peers file mode = %o, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := LoadPeers(townRoot)
+	if err != nil {
+		t.Fatalf("LoadPeers: %v", err)
+	}
+	p, err := loaded.Get("team-b")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if p.URL != "http://team-b:8080" {
+		t.Errorf("URL = %q, want trailing slash trimmed", p.URL)
+	}
+	if p.Token != "tok" {
+		t.Errorf("Token = %q", p.Token)
+	}
+
+	if err := loaded.Remove("team-b"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := loaded.Get("team-b"); !errors.Is(err, ErrPeerNotFound) {
+		t.Errorf("Get after remove: err = %v, want ErrPeerNotFound", err)
+	}
+}
+
+func TestPeersAddValidation(t *testing.T) {
+	peers := NewPeersConfig()
+	if err := peers.Add("bad:name", "http://x", ""); err == nil {
+		t.Error("expected error for name containing ':'")
+	}
+	if err := peers.Add("ok", "team-b:8080", ""); err == nil {
+		t.Error("expected error for URL without scheme")
+	}
+}
+
+func TestFetchRemoteIssues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != IssuesAPIPath {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if got := r.URL.Query().Get("ids"); got != "be-1,be-2" {
+			t.Errorf("ids = %q", got)
+		}
+		_ = json.NewEncoder(w).Encode([]IssueStatus{
+			{ID: "be-1", Title: "Backend change", Status: "closed"},
+		})
+	}))
+	defer srv.Close()
+
+	peers := NewPeersConfig()
+	if err := peers.Add("team-b", srv.URL, "tok"); err != nil {
+		t.Fatal(err)
+	}
+
+	refs := []RemoteRef{
+		{Peer: "team-b", IssueID: "be-1"},
+		{Peer: "team-b", IssueID: "be-2"},
+		{Peer: "missing", IssueID: "x-1"},
+	}
+	results, errs := FetchRemoteIssues(peers, refs)
+
+	if s := results[refs[0]]; s == nil || s.Status != "closed" {
+		t.Errorf("be-1 status = %+v, want closed", s)
+	}
+	if _, ok := results[refs[1]]; ok {
+		t.Error("be-2 should be absent (unknown to peer)")
+	}
+	if !errors.Is(errs["missing"], ErrPeerNotFound) {
+		t.Errorf("errs[missing] = %v, want ErrPeerNotFound", errs["missing"])
+	}
+	if errs["team-b"] != nil {
+		t.Errorf("unexpected error for team-b: %v", errs["team-b"])
+	}
+}
@@ -0,0 +1,155 @@
+// Package federation provides cross-town coordination for Gas Town.
+//
+// Organizations that run one town per team can register peer towns and
+// reference their issues from local convoys. Peer towns expose issue status
+// through the dashboard's JSON API (gt dashboard serves /api/issues), so a
+// convoy in team A's town can track and wait on work landing in team B's.
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// PeersFileName is the peer registry file in mayor/.
+const PeersFileName = "peers.json"
+
+// CurrentPeersVersion is the current schema version for PeersConfig.
+const CurrentPeersVersion = 1
+
+// ErrPeerNotFound indicates the named peer town is not registered.
+var ErrPeerNotFound = errors.New("peer town not found")
+
+// Peer is a remote town whose issues can be tracked by local convoys.
+type Peer struct {
+	// URL is the base URL of the peer's dashboard API (e.g., "http://team-b:8080").
+	URL string `json:"url"`
+
+	// Token is an optional bearer token sent with API requests.
+	Token string `json:"token,omitempty"`
+
+	// AddedAt records when the peer was registered.
+	AddedAt time.Time `json:"added_at"`
+}
+
+// PeersConfig is the peer town registry (mayor/peers.json).
+type PeersConfig struct {
+	Type    string           `json:"type"`    // "peers"
+	Version int              `json:"version"` // schema version
+	Peers   map[string]*Peer `json:"peers"`   // peer name -> peer
+}
+
+// NewPeersConfig creates an empty peer registry.
+func NewPeersConfig() *PeersConfig {
+	return &PeersConfig{
+		Type:    "peers",
+		Version: CurrentPeersVersion,
+		Peers:   make(map[string]*Peer),
+	}
+}
+
+// PeersPath returns the path to the peer registry within a town root.
+func PeersPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirMayor, PeersFileName)
+}
+
+// LoadPeers loads the peer registry, returning an empty registry if none exists.
+func LoadPeers(townRoot string) (*PeersConfig, error) {
+	data, err := os.ReadFile(PeersPath(townRoot)) //nolint:gosec // G304: path is constructed from town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewPeersConfig(), nil
+		}
+		return nil, fmt.Errorf("reading peers: %w", err)
+	}
+
+	var cfg PeersConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing peers: %w", err)
+	}
+	if cfg.Type != "peers" && cfg.Type != "" {
+		return nil, fmt.Errorf("invalid peers config type %q", cfg.Type)
+	}
+	if cfg.Version > CurrentPeersVersion {
+		return nil, fmt.Errorf("unsupported peers version %d (max %d)", cfg.Version, CurrentPeersVersion)
+	}
+	if cfg.Peers == nil {
+		cfg.Peers = make(map[string]*Peer)
+	}
+	return &cfg, nil
+}
+
+// SavePeers writes the peer registry. The file may contain API tokens, so it
+// is written with owner-only permissions.
+func SavePeers(townRoot string, cfg *PeersConfig) error {
+	path := PeersPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding peers: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing peers: %w", err)
+	}
+	return nil
+}
+
+// Get returns the named peer.
+func (c *PeersConfig) Get(name string) (*Peer, error) {
+	p, ok := c.Peers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPeerNotFound, name)
+	}
+	return p, nil
+}
+
+// Add registers a peer town. Names must not contain ':' since they are used
+// as the prefix in remote issue references.
+func (c *PeersConfig) Add(name, url, token string) error {
+	if name == "" {
+		return fmt.Errorf("peer name is required")
+	}
+	if strings.ContainsAny(name, ":/ ") {
+		return fmt.Errorf("invalid peer name %q: must not contain ':', '/', or spaces", name)
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return fmt.Errorf("invalid peer URL %q: must start with http:// or https://", url)
+	}
+	c.Peers[name] = &Peer{
+		URL:     strings.TrimSuffix(url, "/"),
+		Token:   token,
+		AddedAt: time.Now().UTC(),
+	}
+	return nil
+}
+
+// Remove unregisters a peer town.
+func (c *PeersConfig) Remove(name string) error {
+	if _, ok := c.Peers[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, name)
+	}
+	delete(c.Peers, name)
+	return nil
+}
+
+// Names returns the registered peer names in sorted order.
+func (c *PeersConfig) Names() []string {
+	names := make([]string, 0, len(c.Peers))
+	for name := range c.Peers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -103,7 +103,7 @@ func (c *Curator) Stop() {
 // ZFC: No in-memory state to clean up - state is derived from the events file.
 func (c *Curator) run(file *os.File) {
 	defer c.wg.Done()
-	defer file.Close()
+	defer func() { _ = file.Close() }()
 
 	reader := bufio.NewReader(file)
 	ticker := time.NewTicker(100 * time.Millisecond)
@@ -123,6 +123,12 @@ func (c *Curator) run(file *os.File) {
 				}
 				c.processLine(line)
 			}
+			// gt archive moved old events out of the log
+			if nf, err := events.Reopen(file, c.townRoot); err == nil && nf != nil {
+				_ = file.Close()
+				file = nf
+				reader.Reset(nf)
+			}
 		}
 	}
 }
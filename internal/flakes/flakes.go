@@ -0,0 +1,236 @@
+// Package flakes keeps per-test pass/fail history for a rig's merge queue,
+// so the refinery can tell a branch's regression from a test that fails on
+// main too.
+//
+// Each test run in the refinery is recorded against either the target
+// branch (main) or the branch being merged. A test that fails on a branch
+// is flaky when it also failed on main's latest run, or when its failure
+// rate on main is at or above the rig's threshold; such failures don't
+// bounce the branch.
+package flakes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// StateFile records test history within the rig's .runtime directory.
+const StateFile = "flakes.json"
+
+// Result is one test's outcome in a run.
+type Result struct {
+	Test   string
+	Passed bool
+}
+
+// Counts tallies a test's runs and failures.
+type Counts struct {
+	Runs     int `json:"runs"`
+	Failures int `json:"failures"`
+}
+
+// FailureRate is the fraction of runs that failed.
+func (c Counts) FailureRate() float64 {
+	if c.Runs == 0 {
+		return 0
+	}
+	return float64(c.Failures) / float64(c.Runs)
+}
+
+// Record is a test's history.
+type Record struct {
+	Main   Counts `json:"main"`
+	Branch Counts `json:"branch"`
+
+	// LastMainFailed is whether the test failed in main's latest run.
+	LastMainFailed bool `json:"last_main_failed,omitempty"`
+
+	LastFailure time.Time `json:"last_failure,omitempty"`
+	LastBranch  string    `json:"last_branch,omitempty"` // Branch of the last branch failure
+
+	// Flaky is set once a branch failure was let through as flaky.
+	Flaky bool `json:"flaky,omitempty"`
+}
+
+// History is the test record for a rig, keyed by test name.
+type History struct {
+	Tests map[string]*Record `json:"tests,omitempty"`
+}
+
+// Policy decides when a test counts as flaky on main.
+type Policy struct {
+	// Threshold is the main failure rate at or above which a test is
+	// flaky. Zero turns flake detection off.
+	Threshold float64
+
+	// MinRuns is how many main runs a test needs before its rate counts.
+	MinRuns int
+}
+
+// Enabled reports whether the policy detects flakes at all.
+func (p Policy) Enabled() bool {
+	return p.Threshold > 0
+}
+
+func statePath(rigPath string) string {
+	return filepath.Join(constants.RigRuntimePath(rigPath), StateFile)
+}
+
+// Load reads a rig's test history. A missing file is an empty history.
+func Load(rigPath string) (*History, error) {
+	h := &History{Tests: map[string]*Record{}}
+	data, err := os.ReadFile(statePath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StateFile, err)
+	}
+	if h.Tests == nil {
+		h.Tests = map[string]*Record{}
+	}
+	return h, nil
+}
+
+// Update loads the test history, applies fn and saves it, holding a lock
+// so concurrent refinery runs don't lose each other's results.
+func Update(rigPath string, fn func(*History)) error {
+	path := statePath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking test history: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	h, err := Load(rigPath)
+	if err != nil {
+		return err
+	}
+	fn(h)
+	return util.AtomicWriteJSON(path, h)
+}
+
+// RecordMain adds a run of the target branch to the history.
+func (h *History) RecordMain(results []Result, at time.Time) {
+	for _, r := range results {
+		rec := h.record(r.Test)
+		rec.Main.Runs++
+		rec.LastMainFailed = !r.Passed
+		if !r.Passed {
+			rec.Main.Failures++
+			rec.LastFailure = at
+		}
+	}
+}
+
+// RecordBranch adds a run of a branch being merged to the history.
+func (h *History) RecordBranch(results []Result, branch string, at time.Time) {
+	for _, r := range results {
+		rec := h.record(r.Test)
+		rec.Branch.Runs++
+		if !r.Passed {
+			rec.Branch.Failures++
+			rec.LastFailure = at
+			rec.LastBranch = branch
+		}
+	}
+}
+
+func (h *History) record(test string) *Record {
+	if h.Tests == nil {
+		h.Tests = map[string]*Record{}
+	}
+	rec := h.Tests[test]
+	if rec == nil {
+		rec = &Record{}
+		h.Tests[test] = rec
+	}
+	return rec
+}
+
+// IsFlaky reports whether test fails on main often enough, or recently
+// enough, that a branch failure says nothing about the branch.
+func (h *History) IsFlaky(test string, p Policy) bool {
+	rec := h.Tests[test]
+	if rec == nil || !p.Enabled() {
+		return false
+	}
+	if rec.LastMainFailed {
+		return true
+	}
+	return rec.Main.Runs >= p.MinRuns && rec.Main.FailureRate() >= p.Threshold
+}
+
+// Classify splits a branch's failed tests into flaky ones and real
+// failures, marking the flaky ones in the history.
+func (h *History) Classify(failed []string, p Policy) (flaky, real []string) {
+	for _, test := range failed {
+		if h.IsFlaky(test, p) {
+			h.record(test).Flaky = true
+			flaky = append(flaky, test)
+		} else {
+			real = append(real, test)
+		}
+	}
+	return flaky, real
+}
+
+// Entry is a test in a List.
+type Entry struct {
+	Test string `json:"test"`
+	Record
+	FailureRate float64 `json:"main_failure_rate"`
+	IsFlaky     bool    `json:"is_flaky"`
+}
+
+// List returns the tests with failures, flaky ones first and then by main
+// failure rate. With all set, tests that have only passed are included.
+func (h *History) List(p Policy, all bool) []Entry {
+	var out []Entry
+	for test, rec := range h.Tests {
+		if !all && rec.Main.Failures == 0 && rec.Branch.Failures == 0 {
+			continue
+		}
+		out = append(out, Entry{
+			Test:        test,
+			Record:      *rec,
+			FailureRate: rec.Main.FailureRate(),
+			IsFlaky:     rec.Flaky || h.IsFlaky(test, p),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].IsFlaky != out[j].IsFlaky {
+			return out[i].IsFlaky
+		}
+		if out[i].FailureRate != out[j].FailureRate {
+			return out[i].FailureRate > out[j].FailureRate
+		}
+		return out[i].Test < out[j].Test
+	})
+	return out
+}
+
+// Failed returns the names of the failed tests in results.
+func Failed(results []Result) []string {
+	var failed []string
+	for _, r := range results {
+		if !r.Passed {
+			failed = append(failed, r.Test)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,110 @@
+package flakes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseGoTest(t *testing.T) {
+	plain := `=== RUN   TestA
+--- PASS: TestA (0.00s)
+=== RUN   TestB
+    b_test.go:10: boom
+--- FAIL: TestB (0.01s)
+    --- FAIL: TestB/sub (0.00s)
+--- SKIP: TestC (0.00s)
+FAIL
+FAIL	example.com/m/a	0.02s
+--- FAIL: TestD (0.00s)
+ok  	example.com/m/b	0.01s
+`
+	want := []Result{
+		{"example.com/m/a.TestA", true},
+		{"example.com/m/a.TestB", false},
+		{"example.com/m/a.TestB/sub", false},
+		{"example.com/m/b.TestD", false},
+	}
+	if got := ParseGoTest(plain); !reflect.DeepEqual(got, want) {
+		t.Errorf("plain output:\n got %v\nwant %v", got, want)
+	}
+
+	events := `{"Action":"run","Package":"example.com/m/a","Test":"TestA"}
+{"Action":"output","Package":"example.com/m/a","Test":"TestA","Output":"--- PASS: TestA (0.00s)\n"}
+{"Action":"pass","Package":"example.com/m/a","Test":"TestA","Elapsed":0}
+{"Action":"fail","Package":"example.com/m/a","Test":"TestB","Elapsed":0}
+{"Action":"skip","Package":"example.com/m/a","Test":"TestC","Elapsed":0}
+{"Action":"fail","Package":"example.com/m/a","Elapsed":0.1}
+`
+	want = []Result{
+		{"example.com/m/a.TestA", true},
+		{"example.com/m/a.TestB", false},
+	}
+	if got := ParseGoTest(events); !reflect.DeepEqual(got, want) {
+		t.Errorf("-json output:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	now := time.Now()
+	p := Policy{Threshold: 0.25, MinRuns: 4}
+	h := &History{}
+
+	// TestFlaky fails 1 in 4 on main; TestSolid always passes
+	for i := 0; i < 4; i++ {
+		h.RecordMain([]Result{{"TestFlaky", i != 1}, {"TestSolid", true}}, now)
+	}
+	// TestBroken just failed on main's latest run
+	h.RecordMain([]Result{{"TestBroken", false}}, now)
+
+	h.RecordBranch([]Result{{"TestFlaky", false}, {"TestSolid", false}, {"TestBroken", false}, {"TestNew", false}}, "polecat/Toast/gt-abc", now)
+	flaky, real := h.Classify([]string{"TestFlaky", "TestSolid", "TestBroken", "TestNew"}, p)
+	if want := []string{"TestFlaky", "TestBroken"}; !reflect.DeepEqual(flaky, want) {
+		t.Errorf("flaky = %v, want %v", flaky, want)
+	}
+	if want := []string{"TestSolid", "TestNew"}; !reflect.DeepEqual(real, want) {
+		t.Errorf("real = %v, want %v", real, want)
+	}
+	if rec := h.Tests["TestFlaky"]; !rec.Flaky || rec.LastBranch != "polecat/Toast/gt-abc" || rec.Branch.Failures != 1 {
+		t.Errorf("TestFlaky record = %+v", rec)
+	}
+
+	// Too few main runs don't count, and a zero threshold turns detection off
+	if (&History{Tests: map[string]*Record{"T": {Main: Counts{Runs: 1, Failures: 1}}}}).IsFlaky("T", p) {
+		t.Error("one run should not be enough to call a test flaky")
+	}
+	if h.IsFlaky("TestBroken", Policy{}) {
+		t.Error("a zero threshold should disable flake detection")
+	}
+
+	list := h.List(p, false)
+	var names []string
+	for _, e := range list {
+		names = append(names, e.Test)
+	}
+	if want := []string{"TestBroken", "TestFlaky", "TestNew", "TestSolid"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("List = %v, want %v", names, want)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	rigPath := t.TempDir()
+	h, err := Load(rigPath)
+	if err != nil || len(h.Tests) != 0 {
+		t.Fatalf("Load of a new rig = %v, %v", h, err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := Update(rigPath, func(h *History) {
+			h.RecordMain([]Result{{"TestA", i == 0}}, time.Now())
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h, err = Load(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := h.Tests["TestA"]; rec == nil || rec.Main.Runs != 2 || rec.Main.Failures != 1 || !rec.LastMainFailed {
+		t.Errorf("TestA record = %+v", rec)
+	}
+}
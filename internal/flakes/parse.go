@@ -0,0 +1,71 @@
+package flakes
+
+import (
+	"bufio"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var (
+	// --- PASS: TestFoo (0.01s), indented for subtests
+	testLineRegex = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+)`)
+
+	// ok  	github.com/x/y	0.01s, or FAIL	github.com/x/y	0.01s
+	packageLineRegex = regexp.MustCompile(`^(ok|FAIL)\s+(\S+)\s`)
+)
+
+// goTestEvent is the part of a 'go test -json' event we read.
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+}
+
+// ParseGoTest reads test results from go test output, either plain (-v
+// lists passing tests; without it only failures show) or -json. Tests are
+// named package.Test when the package is known. Skipped tests aren't
+// results.
+func ParseGoTest(output string) []Result {
+	var results []Result
+	var pending []Result // Plain-output tests awaiting their package line
+	flush := func(pkg string) {
+		for _, r := range pending {
+			if pkg != "" {
+				r.Test = pkg + "." + r.Test
+			}
+			results = append(results, r)
+		}
+		pending = pending[:0]
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "{") {
+			var ev goTestEvent
+			if json.Unmarshal([]byte(line), &ev) == nil && ev.Test != "" {
+				if ev.Action == "pass" || ev.Action == "fail" {
+					name := ev.Test
+					if ev.Package != "" {
+						name = ev.Package + "." + name
+					}
+					results = append(results, Result{Test: name, Passed: ev.Action == "pass"})
+				}
+				continue
+			}
+		}
+		if m := testLineRegex.FindStringSubmatch(line); m != nil {
+			if m[1] != "SKIP" {
+				pending = append(pending, Result{Test: m[2], Passed: m[1] == "PASS"})
+			}
+			continue
+		}
+		if m := packageLineRegex.FindStringSubmatch(line + " "); m != nil {
+			flush(m[2])
+		}
+	}
+	flush("")
+	return results
+}
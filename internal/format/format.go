@@ -0,0 +1,235 @@
+// Package format renders timestamps and numbers in human-facing reports
+// according to the town's locale, so generated Markdown and terminal output
+// read naturally for non-US stakeholders.
+//
+// The locale comes from GT_LOCALE, then the "locale" field of the town
+// settings (settings/config.json). "system" reads LC_ALL/LC_TIME/LANG.
+// Without a configured locale the historical format is kept: ISO 8601
+// dates and plain decimals with no grouping.
+//
+// Machine-readable output (JSON, bead fields, RFC 3339 timestamps) must not
+// go through this package.
+package format
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Locale describes how dates and numbers are written.
+type Locale struct {
+	Tag       string // BCP 47 style tag, e.g. "de-DE"
+	Date      string // Go time layout for dates
+	DateTime  string // Go time layout for date and time (minute precision)
+	Decimal   string // Decimal separator
+	Group     string // Thousands separator ("" disables grouping)
+	Lakh      bool   // Indian grouping: the last three digits, then pairs (12,34,567)
+	CurrencyL string // Currency symbol placed before the amount ("$1.00")
+	CurrencyR string // Currency symbol placed after the amount ("1,00 $")
+}
+
+// DefaultTag is the locale used when none is configured.
+const DefaultTag = "iso"
+
+// locales are the built-in locales, keyed by lowercase tag.
+// French groups with a narrow no-break space, per Imprimerie nationale.
+// Amounts are always USD (model costs); only placement and separators vary.
+var locales = map[string]*Locale{
+	"iso":   {Tag: "iso", Date: "2006-01-02", DateTime: "2006-01-02 15:04", Decimal: ".", CurrencyL: "$"},
+	"en-us": {Tag: "en-US", Date: "01/02/2006", DateTime: "01/02/2006 3:04 PM", Decimal: ".", Group: ",", CurrencyL: "$"},
+	"en-gb": {Tag: "en-GB", Date: "02/01/2006", DateTime: "02/01/2006 15:04", Decimal: ".", Group: ",", CurrencyL: "US$"},
+	"en-ca": {Tag: "en-CA", Date: "2006-01-02", DateTime: "2006-01-02 15:04", Decimal: ".", Group: ",", CurrencyL: "US$"},
+	"en-au": {Tag: "en-AU", Date: "02/01/2006", DateTime: "02/01/2006 15:04", Decimal: ".", Group: ",", CurrencyL: "US$"},
+	"de-de": {Tag: "de-DE", Date: "02.01.2006", DateTime: "02.01.2006 15:04", Decimal: ",", Group: ".", CurrencyR: " $"},
+	"fr-fr": {Tag: "fr-FR", Date: "02/01/2006", DateTime: "02/01/2006 15:04", Decimal: ",", Group: "\u202f", CurrencyR: "\u00a0$US"},
+	"es-es": {Tag: "es-ES", Date: "02/01/2006", DateTime: "02/01/2006 15:04", Decimal: ",", Group: ".", CurrencyR: " US$"},
+	"it-it": {Tag: "it-IT", Date: "02/01/2006", DateTime: "02/01/2006 15:04", Decimal: ",", Group: ".", CurrencyR: " USD"},
+	"nl-nl": {Tag: "nl-NL", Date: "02-01-2006", DateTime: "02-01-2006 15:04", Decimal: ",", Group: ".", CurrencyL: "US$ "},
+	"pt-br": {Tag: "pt-BR", Date: "02/01/2006", DateTime: "02/01/2006 15:04", Decimal: ",", Group: ".", CurrencyL: "US$ "},
+	"sv-se": {Tag: "sv-SE", Date: "2006-01-02", DateTime: "2006-01-02 15:04", Decimal: ",", Group: " ", CurrencyR: " US$"},
+	"pl-pl": {Tag: "pl-PL", Date: "02.01.2006", DateTime: "02.01.2006 15:04", Decimal: ",", Group: " ", CurrencyR: " USD"},
+	"ja-jp": {Tag: "ja-JP", Date: "2006/01/02", DateTime: "2006/01/02 15:04", Decimal: ".", Group: ",", CurrencyL: "$"},
+	"zh-cn": {Tag: "zh-CN", Date: "2006-01-02", DateTime: "2006-01-02 15:04", Decimal: ".", Group: ",", CurrencyL: "US$"},
+	"en-in": {Tag: "en-IN", Date: "02/01/2006", DateTime: "02/01/2006 15:04", Decimal: ".", Group: ",", Lakh: true, CurrencyL: "$"},
+}
+
+var (
+	mu      sync.RWMutex
+	current = locales[DefaultTag]
+)
+
+// Tags returns the supported locale tags, sorted.
+func Tags() []string {
+	tags := make([]string, 0, len(locales))
+	for _, l := range locales {
+		tags = append(tags, l.Tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Lookup returns the built-in locale for a tag. Matching is case-insensitive,
+// accepts "_" for "-", ignores POSIX suffixes (".UTF-8", "@euro"), and falls
+// back to a language-only match ("de" → de-DE).
+func Lookup(tag string) (*Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ReplaceAll(tag, "_", "-")
+	if tag == "" || tag == "c" || tag == "posix" {
+		return locales[DefaultTag], true
+	}
+	if l, ok := locales[tag]; ok {
+		return l, true
+	}
+
+	// Language-only match: prefer the language's home region (de-de, fr-fr),
+	// then the alphabetically first region for determinism.
+	lang, _, _ := strings.Cut(tag, "-")
+	if l, ok := locales[lang+"-"+lang]; ok {
+		return l, true
+	}
+	best := ""
+	for key := range locales {
+		if strings.HasPrefix(key, lang+"-") && (best == "" || key < best) {
+			best = key
+		}
+	}
+	if best != "" {
+		return locales[best], true
+	}
+	return nil, false
+}
+
+// SetLocale selects the active locale. "system" resolves from the process
+// environment; an empty tag restores the default.
+func SetLocale(tag string) error {
+	if strings.EqualFold(tag, "system") {
+		tag = systemLocale()
+	}
+	l, ok := Lookup(tag)
+	if !ok {
+		return fmt.Errorf("unsupported locale %q (supported: %s, system)", tag, strings.Join(Tags(), ", "))
+	}
+	mu.Lock()
+	current = l
+	mu.Unlock()
+	return nil
+}
+
+// Init selects the locale from GT_LOCALE, falling back to the configured
+// tag (usually the town settings "locale" field).
+func Init(configured string) error {
+	if env := os.Getenv("GT_LOCALE"); env != "" {
+		return SetLocale(env)
+	}
+	return SetLocale(configured)
+}
+
+// Current returns the active locale.
+func Current() *Locale {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// systemLocale returns the POSIX locale from the environment, using the
+// same precedence as setlocale(LC_TIME).
+func systemLocale() string {
+	for _, key := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Date formats a date (no time of day) in local time.
+func Date(t time.Time) string {
+	return t.Local().Format(Current().Date)
+}
+
+// DateTime formats a timestamp to minute precision in local time.
+func DateTime(t time.Time) string {
+	return t.Local().Format(Current().DateTime)
+}
+
+// DateTimeSeconds formats a timestamp to second precision in local time.
+func DateTimeSeconds(t time.Time) string {
+	layout := Current().DateTime
+	// Every built-in layout has exactly one minute field to extend
+	layout = strings.Replace(layout, ":04", ":04:05", 1)
+	return t.Local().Format(layout)
+}
+
+// Int formats an integer with the locale's thousands separator.
+func Int(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	s = group(s, Current())
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+// Decimal formats a number with a fixed number of decimal places.
+func Decimal(f float64, places int) string {
+	l := Current()
+	s := strconv.FormatFloat(math.Abs(f), 'f', places, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+	out := group(whole, l)
+	if frac != "" {
+		out += l.Decimal + frac
+	}
+	if f < 0 && strings.Trim(out, "0.,") != "" {
+		return "-" + out
+	}
+	return out
+}
+
+// Currency formats a USD amount with two decimal places.
+func Currency(usd float64) string {
+	l := Current()
+	amount := Decimal(usd, 2)
+	sign := ""
+	if strings.HasPrefix(amount, "-") {
+		sign, amount = "-", amount[1:]
+	}
+	return sign + l.CurrencyL + amount + l.CurrencyR
+}
+
+// group inserts the locale's separator every three digits from the right,
+// or for lakh grouping after the last three digits and then every two.
+func group(digits string, l *Locale) string {
+	if l.Group == "" || len(digits) <= 3 {
+		return digits
+	}
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	size := 3
+	if l.Lakh {
+		size = 2
+	}
+	var sb strings.Builder
+	lead := len(head) % size
+	if lead > 0 {
+		sb.WriteString(head[:lead])
+	}
+	for i := lead; i < len(head); i += size {
+		if sb.Len() > 0 {
+			sb.WriteString(l.Group)
+		}
+		sb.WriteString(head[i : i+size])
+	}
+	return sb.String() + l.Group + tail
+}
@@ -0,0 +1,131 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func withLocale(t *testing.T, tag string) {
+	t.Helper()
+	if err := SetLocale(tag); err != nil {
+		t.Fatalf("SetLocale(%q): %v", tag, err)
+	}
+	t.Cleanup(func() { _ = SetLocale("") })
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+		ok   bool
+	}{
+		{"", "iso", true},
+		{"C", "iso", true},
+		{"de_DE.UTF-8", "de-DE", true},
+		{"fr_FR@euro", "fr-FR", true},
+		{"EN-gb", "en-GB", true},
+		{"de", "de-DE", true},
+		{"en", "en-AU", true},
+		{"xx-YY", "", false},
+	}
+	for _, tt := range tests {
+		l, ok := Lookup(tt.tag)
+		if ok != tt.ok || (ok && l.Tag != tt.want) {
+			t.Errorf("Lookup(%q) = %v, %v; want %q, %v", tt.tag, l, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	tests := []struct {
+		tag      string
+		integer  string
+		decimal  string
+		currency string
+	}{
+		{"iso", "1234567", "1234.50", "$1234.50"},
+		{"en-US", "1,234,567", "1,234.50", "$1,234.50"},
+		{"de-DE", "1.234.567", "1.234,50", "1.234,50 $"},
+		{"fr-FR", "1\u202f234\u202f567", "1\u202f234,50", "1\u202f234,50\u00a0$US"},
+		{"en-IN", "12,34,567", "1,234.50", "$1,234.50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			withLocale(t, tt.tag)
+			if got := Int(1234567); got != tt.integer {
+				t.Errorf("Int = %q, want %q", got, tt.integer)
+			}
+			if got := Decimal(1234.5, 2); got != tt.decimal {
+				t.Errorf("Decimal = %q, want %q", got, tt.decimal)
+			}
+			if got := Currency(1234.5); got != tt.currency {
+				t.Errorf("Currency = %q, want %q", got, tt.currency)
+			}
+		})
+	}
+
+	withLocale(t, "en-US")
+	if got := Int(-1000); got != "-1,000" {
+		t.Errorf("Int(-1000) = %q", got)
+	}
+	if got := Currency(-0.5); got != "-$0.50" {
+		t.Errorf("Currency(-0.5) = %q", got)
+	}
+	if got := Int(999); got != "999" {
+		t.Errorf("Int(999) = %q", got)
+	}
+
+	withLocale(t, "en-IN")
+	for n, want := range map[int64]string{1000: "1,000", 100000: "1,00,000", 123456789: "12,34,56,789", -1234567: "-12,34,567"} {
+		if got := Int(n); got != want {
+			t.Errorf("en-IN Int(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestDates(t *testing.T) {
+	ts := time.Date(2026, 3, 7, 14, 5, 9, 0, time.Local)
+
+	withLocale(t, "iso")
+	if got := DateTimeSeconds(ts); got != "2026-03-07 14:05:09" {
+		t.Errorf("iso DateTimeSeconds = %q", got)
+	}
+
+	withLocale(t, "de-DE")
+	if got := Date(ts); got != "07.03.2026" {
+		t.Errorf("de-DE Date = %q", got)
+	}
+
+	withLocale(t, "en-US")
+	if got := DateTime(ts); got != "03/07/2026 2:05 PM" {
+		t.Errorf("en-US DateTime = %q", got)
+	}
+	if got := DateTimeSeconds(ts); got != "03/07/2026 2:05:09 PM" {
+		t.Errorf("en-US DateTimeSeconds = %q", got)
+	}
+}
+
+func TestInitPrefersEnv(t *testing.T) {
+	t.Cleanup(func() { _ = SetLocale("") })
+
+	t.Setenv("GT_LOCALE", "de-DE")
+	if err := Init("en-US"); err != nil {
+		t.Fatal(err)
+	}
+	if Current().Tag != "de-DE" {
+		t.Errorf("Current() = %s, want de-DE from GT_LOCALE", Current().Tag)
+	}
+
+	t.Setenv("GT_LOCALE", "")
+	t.Setenv("LC_ALL", "sv_SE.UTF-8")
+	if err := Init("system"); err != nil {
+		t.Fatal(err)
+	}
+	if Current().Tag != "sv-SE" {
+		t.Errorf("Current() = %s, want sv-SE from system locale", Current().Tag)
+	}
+
+	if err := Init("klingon"); err == nil {
+		t.Error("expected error for unsupported locale")
+	}
+}
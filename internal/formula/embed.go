@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/dryrun"
 )
 
 // Generate formulas directory from canonical source at .beads/formulas/
@@ -97,7 +99,7 @@ func saveInstalledRecord(formulasDir string, record *InstalledRecord) error {
 	if err != nil {
 		return fmt.Errorf("encoding installed record: %w", err)
 	}
-	return os.WriteFile(path, data, 0644)
+	return dryrun.WriteFile(path, data, 0644)
 }
 
 // computeFileHash computes SHA256 hash of a file.
@@ -126,7 +128,7 @@ func ProvisionFormulas(beadsPath string) (int, error) {
 
 	// Create .beads/formulas/ directory
 	formulasDir := filepath.Join(beadsPath, ".beads", "formulas")
-	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(formulasDir, 0755); err != nil {
 		return 0, fmt.Errorf("creating formulas directory: %w", err)
 	}
 
@@ -157,7 +159,7 @@ func ProvisionFormulas(beadsPath string) (int, error) {
 			return count, fmt.Errorf("reading %s: %w", entry.Name(), err)
 		}
 
-		if err := os.WriteFile(destPath, content, 0644); err != nil {
+		if err := dryrun.WriteFile(destPath, content, 0644); err != nil {
 			return count, fmt.Errorf("writing %s: %w", entry.Name(), err)
 		}
 
@@ -258,7 +260,7 @@ func UpdateFormulas(beadsPath string) (updated, skipped, reinstalled int, err er
 	}
 
 	formulasDir := filepath.Join(beadsPath, ".beads", "formulas")
-	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(formulasDir, 0755); err != nil {
 		return 0, 0, 0, fmt.Errorf("creating formulas directory: %w", err)
 	}
 
@@ -310,7 +312,7 @@ func UpdateFormulas(beadsPath string) (updated, skipped, reinstalled int, err er
 				return updated, skipped, reinstalled, fmt.Errorf("reading %s: %w", filename, err)
 			}
 
-			if err := os.WriteFile(destPath, content, 0644); err != nil {
+			if err := dryrun.WriteFile(destPath, content, 0644); err != nil {
 				return updated, skipped, reinstalled, fmt.Errorf("writing %s: %w", filename, err)
 			}
 
@@ -0,0 +1,148 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/promptctx"
+)
+
+// Lint severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Lint rules.
+const (
+	RuleInvalid     = "invalid"      // Fails Validate
+	RuleOrphan      = "orphan-step"  // Neither needs nor is needed by another step
+	RuleMissingTier = "missing-tier" // Mechanical step with no tier
+	RuleLongPrompt  = "long-prompt"  // Step prompt over the token budget
+	RuleUndefined   = "undefined-var"
+)
+
+// DefaultMaxStepTokens is the step prompt size Lint flags by default: a
+// sixth of gt prime's default context budget, since a step shares the
+// prompt with the role pack and the hooked bead.
+const DefaultMaxStepTokens = 4000
+
+// Finding is one problem Lint found.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Step     string `json:"step,omitempty"`
+	Message  string `json:"message"`
+}
+
+// String formats the finding as "step: severity rule: message".
+func (f Finding) String() string {
+	prefix := ""
+	if f.Step != "" {
+		prefix = f.Step + ": "
+	}
+	return fmt.Sprintf("%s%s %s: %s", prefix, f.Severity, f.Rule, f.Message)
+}
+
+// mechanicalVerbs start the titles of steps that follow a script rather
+// than need judgment, which a cheaper tier can do.
+var mechanicalVerbs = []string{
+	"archive", "bump", "commit", "fetch", "format", "install", "move",
+	"push", "sync", "tag",
+}
+
+// varRe matches a cook-time variable ({{name}} or {{name.field}}).
+var varRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)(?:\.[A-Za-z0-9_.]+)?\s*\}\}`)
+
+// blockRe matches template block helpers, which mark text as a report
+// template the agent fills in rather than one bd fills at cook time.
+var blockRe = regexp.MustCompile(`\{\{-?\s*(#|/|if\s|range\s|else|end)`)
+
+// Lint checks the formula against best practices, beyond what Validate
+// requires. A formula that fails Validate gets a single error finding.
+// maxStepTokens of 0 uses DefaultMaxStepTokens.
+func (f *Formula) Lint(maxStepTokens int) []Finding {
+	if maxStepTokens <= 0 {
+		maxStepTokens = DefaultMaxStepTokens
+	}
+	if err := f.Validate(); err != nil {
+		return []Finding{{Rule: RuleInvalid, Severity: SeverityError, Message: err.Error()}}
+	}
+
+	var steps []Template // Steps and templates lint alike
+	switch f.Type {
+	case TypeWorkflow:
+		for _, s := range f.Steps {
+			steps = append(steps, Template(s))
+		}
+	case TypeExpansion:
+		steps = f.Template
+	default:
+		return nil // Legs and aspects are independent by design
+	}
+
+	needed := make(map[string]bool)
+	for _, s := range steps {
+		for _, need := range s.Needs {
+			needed[need] = true
+		}
+	}
+
+	var findings []Finding
+	add := func(rule, severity, step, format string, args ...interface{}) {
+		findings = append(findings, Finding{Rule: rule, Severity: severity, Step: step, Message: fmt.Sprintf(format, args...)})
+	}
+	for _, s := range steps {
+		if len(steps) > 1 && len(s.Needs) == 0 && !needed[s.ID] {
+			add(RuleOrphan, SeverityWarning, s.ID, "no step needs it and it needs none; it runs in parallel with everything")
+		}
+		if s.Tier == "" && isMechanical(s.Title) {
+			add(RuleMissingTier, SeverityWarning, s.ID, "%q looks mechanical; set tier (e.g. haiku) so it doesn't run on the default model", s.Title)
+		}
+		if tokens := promptctx.EstimateTokens(s.Title + "\n" + s.Description); tokens > maxStepTokens {
+			add(RuleLongPrompt, SeverityWarning, s.ID, "prompt is ~%d tokens, over the %d-token budget; split the step or move detail to a file", tokens, maxStepTokens)
+		}
+		for _, name := range undefinedVars(f.Vars, s.Title, s.Description) {
+			add(RuleUndefined, SeverityWarning, s.ID, "{{%s}} is not in [vars]; bd leaves it unfilled", name)
+		}
+	}
+	return findings
+}
+
+// isMechanical reports whether a step title starts with a mechanical verb
+// or says it is mechanical.
+func isMechanical(title string) bool {
+	lower := strings.ToLower(title)
+	if strings.Contains(lower, "mechanical") {
+		return true
+	}
+	first, _, _ := strings.Cut(lower, " ")
+	for _, verb := range mechanicalVerbs {
+		if first == verb {
+			return true
+		}
+	}
+	return false
+}
+
+// undefinedVars returns the variables texts use that vars doesn't define,
+// each once. Texts with block helpers are report templates and skipped.
+func undefinedVars(vars map[string]Var, texts ...string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, text := range texts {
+		if blockRe.MatchString(text) {
+			continue
+		}
+		for _, m := range varRe.FindAllStringSubmatch(text, -1) {
+			name := m[1]
+			if _, ok := vars[name]; ok || seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,75 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	data := []byte(`
+formula = "release"
+type = "workflow"
+
+[[steps]]
+id = "build"
+title = "Build {{version}}"
+description = "Build it for {{target.os}}."
+
+[[steps]]
+id = "tag"
+title = "Tag the release"
+needs = ["build"]
+
+[[steps]]
+id = "push"
+title = "Push the tag"
+tier = "haiku"
+needs = ["tag"]
+
+[[steps]]
+id = "notes"
+title = "Write release notes"
+description = "{{#each change}}- {{summary}}{{/each}}"
+
+[vars]
+[vars.version]
+description = "Version to release"
+required = true
+`)
+	f, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]bool)
+	for _, finding := range f.Lint(0) {
+		got[finding.Step+" "+finding.Rule] = true
+	}
+	want := []string{
+		"build undefined-var", // {{target.os}}
+		"tag missing-tier",
+		"notes orphan-step",
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("missing finding %q in %v", w, got)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("findings = %v, want only %v", got, want)
+	}
+
+	f.Steps[3].Description = strings.Repeat("word ", 100)
+	var long bool
+	for _, finding := range f.Lint(50) {
+		long = long || (finding.Step == "notes" && finding.Rule == RuleLongPrompt)
+	}
+	if !long {
+		t.Error("long step prompt not flagged")
+	}
+
+	f.Steps[1].Needs = []string{"missing"}
+	if findings := f.Lint(0); len(findings) != 1 || findings[0].Rule != RuleInvalid {
+		t.Errorf("invalid formula findings = %v, want one invalid", findings)
+	}
+}
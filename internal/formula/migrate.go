@@ -0,0 +1,98 @@
+package formula
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PouredStep is a step of a molecule already poured from some version of
+// a formula.
+type PouredStep struct {
+	Ref     string // Formula step ID
+	Done    bool   // Closed
+	Started bool   // In progress
+}
+
+// Migration moves a poured molecule onto another version of its formula.
+type Migration struct {
+	Add    []Step   // Steps the version adds
+	Remove []string // Open steps the version drops
+	Update []Step   // Open steps to rewrite with the version's text and needs
+}
+
+// Empty reports whether the migration changes nothing.
+func (m *Migration) Empty() bool {
+	return len(m.Add) == 0 && len(m.Remove) == 0 && len(m.Update) == 0
+}
+
+// PlanMigration plans moving a molecule poured from another version of
+// the formula onto this one. The remaining steps are compatible, and the
+// plan is returned, only if the progress made carries over: every done or
+// started step is still in this version, and every step this version puts
+// before a done step is done too. Otherwise the error says why not.
+func (f *Formula) PlanMigration(poured []PouredStep) (*Migration, error) {
+	if f.Type != TypeWorkflow {
+		return nil, fmt.Errorf("only workflow formulas can migrate runs (%s is %s)", f.Name, f.Type)
+	}
+	have := make(map[string]PouredStep, len(poured))
+	for _, p := range poured {
+		have[p.Ref] = p
+	}
+
+	m := &Migration{}
+	var refs []string
+	for ref := range have {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		p := have[ref]
+		if f.GetStep(ref) != nil {
+			continue
+		}
+		switch {
+		case p.Done:
+			return nil, fmt.Errorf("done step %s was removed", ref)
+		case p.Started:
+			return nil, fmt.Errorf("step %s is in progress and was removed", ref)
+		}
+		m.Remove = append(m.Remove, ref)
+	}
+
+	for _, step := range f.Steps {
+		p, ok := have[step.ID]
+		if p.Done {
+			for _, before := range f.ancestors(step.ID) {
+				if !have[before].Done {
+					return nil, fmt.Errorf("step %s now comes before done step %s", before, step.ID)
+				}
+			}
+			continue
+		}
+		if !ok {
+			m.Add = append(m.Add, step)
+		} else {
+			m.Update = append(m.Update, step)
+		}
+	}
+	return m, nil
+}
+
+// ancestors returns the steps id needs, directly or not.
+func (f *Formula) ancestors(id string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	var visit func(string)
+	visit = func(id string) {
+		for _, need := range f.GetDependencies(id) {
+			if !seen[need] {
+				seen[need] = true
+				out = append(out, need)
+				visit(need)
+			}
+		}
+	}
+	visit(id)
+	return out
+}
+
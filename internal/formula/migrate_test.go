@@ -0,0 +1,56 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlanMigration(t *testing.T) {
+	f := &Formula{Name: "work", Type: TypeWorkflow, Version: 2, Steps: []Step{
+		{ID: "load"},
+		{ID: "implement", Needs: []string{"load"}},
+		{ID: "lint", Needs: []string{"implement"}}, // New in v2
+		{ID: "test", Needs: []string{"lint"}},
+	}}
+
+	// v1 was load, implement, test, submit
+	plan, err := f.PlanMigration([]PouredStep{
+		{Ref: "load", Done: true},
+		{Ref: "implement", Started: true},
+		{Ref: "test"},
+		{Ref: "submit"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := describe(plan); got != "+lint -submit ~implement ~test" {
+		t.Errorf("plan = %s", got)
+	}
+
+	for _, tc := range []struct {
+		poured []PouredStep
+		reason string
+	}{
+		{[]PouredStep{{Ref: "load", Done: true}, {Ref: "submit", Done: true}}, "done step submit was removed"},
+		{[]PouredStep{{Ref: "load", Done: true}, {Ref: "submit", Started: true}}, "submit is in progress"},
+		{[]PouredStep{{Ref: "load", Done: true}, {Ref: "implement", Done: true}, {Ref: "test", Done: true}}, "lint now comes before done step test"},
+	} {
+		if _, err := f.PlanMigration(tc.poured); err == nil || !strings.Contains(err.Error(), tc.reason) {
+			t.Errorf("PlanMigration(%v) error = %v, want %q", tc.poured, err, tc.reason)
+		}
+	}
+}
+
+func describe(m *Migration) string {
+	var parts []string
+	for _, s := range m.Add {
+		parts = append(parts, "+"+s.ID)
+	}
+	for _, ref := range m.Remove {
+		parts = append(parts, "-"+ref)
+	}
+	for _, s := range m.Update {
+		parts = append(parts, "~"+s.ID)
+	}
+	return strings.Join(parts, " ")
+}
@@ -33,6 +33,17 @@ func Parse(data []byte) (*Formula, error) {
 	return &f, nil
 }
 
+// Decode parses formula.toml content without validating it, so tools can
+// inspect a broken formula (one with a dependency cycle, say).
+func Decode(data []byte) (*Formula, error) {
+	var f Formula
+	if _, err := toml.Decode(string(data), &f); err != nil {
+		return nil, fmt.Errorf("parsing TOML: %w", err)
+	}
+	f.inferType()
+	return &f, nil
+}
+
 // inferType sets the formula type based on content when not explicitly set.
 func (f *Formula) inferType() {
 	if f.Type != "" {
@@ -222,6 +222,29 @@ needs = ["step1"]
 	}
 }
 
+func TestDecode_SkipsValidation(t *testing.T) {
+	data := []byte(`
+formula = "test"
+[[steps]]
+id = "step1"
+needs = ["step2"]
+[[steps]]
+id = "step2"
+needs = ["step1"]
+`)
+
+	f, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if f.Type != TypeWorkflow {
+		t.Errorf("Type = %q, want %q", f.Type, TypeWorkflow)
+	}
+	if len(f.Steps) != 2 {
+		t.Errorf("len(Steps) = %d, want 2", len(f.Steps))
+	}
+}
+
 func TestTopologicalSort(t *testing.T) {
 	data := []byte(`
 formula = "test"
@@ -93,6 +93,7 @@ type Step struct {
 	Title       string   `toml:"title"`
 	Description string   `toml:"description"`
 	Needs       []string `toml:"needs"`
+	Tier        string   `toml:"tier"` // Model tier the step's work needs (haiku, sonnet, opus)
 }
 
 // Template represents a template step in an expansion formula.
@@ -101,6 +102,7 @@ type Template struct {
 	Title       string   `toml:"title"`
 	Description string   `toml:"description"`
 	Needs       []string `toml:"needs"`
+	Tier        string   `toml:"tier"`
 }
 
 // Var represents a variable definition for formulas.
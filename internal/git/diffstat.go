@@ -0,0 +1,121 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileStat is one file's line counts in a DiffStat.
+type FileStat struct {
+	Path       string `json:"path"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+	Binary     bool   `json:"binary,omitempty"`
+	Test       bool   `json:"test,omitempty"`
+}
+
+// DiffStat summarizes what a branch changes relative to its base.
+type DiffStat struct {
+	Base       string     `json:"base"`
+	Head       string     `json:"head"`
+	Commits    int        `json:"commits"`
+	Files      []FileStat `json:"files"`
+	Insertions int        `json:"insertions"`
+	Deletions  int        `json:"deletions"`
+
+	// Packages are the directories with changed files, sorted.
+	Packages []string `json:"packages"`
+
+	// The test delta: changed test files and their line counts.
+	TestFiles      int `json:"test_files"`
+	TestInsertions int `json:"test_insertions"`
+	TestDeletions  int `json:"test_deletions"`
+}
+
+// DiffStat summarizes the changes on head since it forked from base (the
+// three-dot diff base...head), so later commits on base don't show up.
+func (g *Git) DiffStat(base, head string) (*DiffStat, error) {
+	out, err := g.run("diff", "--numstat", "--no-renames", base+"..."+head)
+	if err != nil {
+		return nil, err
+	}
+	stat := &DiffStat{Base: base, Head: head}
+	if count, err := g.run("rev-list", "--count", base+".."+head); err == nil {
+		stat.Commits, _ = strconv.Atoi(count)
+	}
+
+	packages := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		f := FileStat{Path: fields[2], Test: IsTestFile(fields[2])}
+		if fields[0] == "-" {
+			f.Binary = true
+		} else {
+			f.Insertions, _ = strconv.Atoi(fields[0])
+			f.Deletions, _ = strconv.Atoi(fields[1])
+		}
+		stat.Files = append(stat.Files, f)
+		stat.Insertions += f.Insertions
+		stat.Deletions += f.Deletions
+		if f.Test {
+			stat.TestFiles++
+			stat.TestInsertions += f.Insertions
+			stat.TestDeletions += f.Deletions
+		}
+		packages[path.Dir(f.Path)] = true
+	}
+	for p := range packages {
+		stat.Packages = append(stat.Packages, p)
+	}
+	sort.Strings(stat.Packages)
+	return stat, nil
+}
+
+// IsTestFile reports whether a repo path looks like a test, by the usual
+// naming conventions (foo_test.go, test_foo.py, foo.spec.ts, tests/...).
+func IsTestFile(p string) bool {
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		switch dir {
+		case "test", "tests", "__tests__", "spec", "testdata":
+			return true
+		}
+	}
+	name := path.Base(p)
+	stem := strings.TrimSuffix(name, path.Ext(name))
+	return strings.HasSuffix(stem, "_test") || strings.HasSuffix(stem, "_spec") ||
+		strings.HasPrefix(stem, "test_") || strings.HasSuffix(stem, ".test") ||
+		strings.HasSuffix(stem, ".spec") || strings.HasSuffix(stem, "Test")
+}
+
+// Markdown renders the summary for a bead comment or a reviewer.
+func (d *DiffStat) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Change summary** (%s vs %s)\n\n", d.Head, d.Base)
+	fmt.Fprintf(&b, "- %d commit(s), %d file(s) changed, +%d -%d\n", d.Commits, len(d.Files), d.Insertions, d.Deletions)
+	if len(d.Packages) > 0 {
+		fmt.Fprintf(&b, "- Packages: %s\n", strings.Join(d.Packages, ", "))
+	}
+	if d.TestFiles > 0 {
+		fmt.Fprintf(&b, "- Tests: %d file(s), +%d -%d\n", d.TestFiles, d.TestInsertions, d.TestDeletions)
+	} else {
+		b.WriteString("- Tests: none changed\n")
+	}
+	if len(d.Files) > 0 {
+		b.WriteString("\n```\n")
+		for _, f := range d.Files {
+			if f.Binary {
+				fmt.Fprintf(&b, "%s (binary)\n", f.Path)
+			} else {
+				fmt.Fprintf(&b, "%s +%d -%d\n", f.Path, f.Insertions, f.Deletions)
+			}
+		}
+		b.WriteString("```\n")
+	}
+	return b.String()
+}
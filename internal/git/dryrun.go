@@ -0,0 +1,98 @@
+package git
+
+import "strings"
+
+// readOnlyCommands never change a repository and run even in dry-run mode.
+var readOnlyCommands = map[string]bool{
+	"blame":                true,
+	"cat-file":             true,
+	"check-ignore":         true,
+	"check-ref-format":     true,
+	"describe":             true,
+	"diff":                 true,
+	"fetch":                true, // only updates remote-tracking refs
+	"for-each-ref":         true,
+	"grep":                 true,
+	"log":                  true,
+	"ls-files":             true,
+	"ls-remote":            true,
+	"ls-tree":              true,
+	"merge-base":           true,
+	"name-rev":             true,
+	"rev-list":             true,
+	"rev-parse":            true,
+	"shortlog":             true,
+	"show":                 true,
+	"show-ref":             true,
+	"status":               true,
+	"symbolic-ref":         true,
+	"var":                  true,
+	"version":              true,
+	"worktree list":        true,
+	"stash list":           true,
+	"stash show":           true,
+	"remote get-url":       true,
+	"remote show":          true,
+	"notes list":           true,
+	"notes show":           true,
+	"sparse-checkout list": true,
+}
+
+// listFlags make otherwise mutating subcommands read-only.
+var listFlags = map[string]map[string]bool{
+	"branch": {"--list": true, "-l": true, "-a": true, "-r": true, "-v": true, "-vv": true,
+		"--show-current": true, "--merged": true, "--no-merged": true, "--contains": true},
+	"tag":    {"--list": true, "-l": true, "--merged": true, "--no-merged": true, "--contains": true},
+	"config": {"--get": true, "--get-all": true, "--get-regexp": true, "--list": true, "-l": true},
+}
+
+// mutates reports whether a git invocation changes repository state, so
+// that dry-run mode records it instead of running it.
+func mutates(args []string) bool {
+	var words, flags []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "-C":
+			i++ // skip the directory
+		case strings.HasPrefix(a, "-"):
+			flags = append(flags, a)
+		default:
+			words = append(words, a)
+		}
+	}
+	if len(words) == 0 {
+		return false
+	}
+	sub := words[0]
+	if readOnlyCommands[sub] || (len(words) > 1 && readOnlyCommands[sub+" "+words[1]]) {
+		return false
+	}
+	for _, f := range flags {
+		if name, _, _ := strings.Cut(f, "="); listFlags[sub][name] {
+			return false
+		}
+	}
+	switch sub {
+	case "branch", "tag":
+		// Bare "git branch" lists; a name argument creates or deletes
+		return len(words) > 1 || hasFlagPrefix(flags, "--set-upstream", "--unset-upstream", "-d", "-D", "-m", "-M")
+	case "config":
+		// One key reads it; key and value set it
+		return len(words) > 2 || hasFlagPrefix(flags, "--unset", "--add", "--replace-all", "--remove-section", "--rename-section")
+	case "remote":
+		return len(words) > 1
+	}
+	return true
+}
+
+func hasFlagPrefix(flags []string, prefixes ...string) bool {
+	for _, f := range flags {
+		for _, p := range prefixes {
+			if strings.HasPrefix(f, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -8,6 +8,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/output"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"github.com/steveyegge/gastown/internal/transport"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // GitError contains raw output from a git command for agent observation.
@@ -69,23 +75,54 @@ func (g *Git) run(args ...string) (string, error) {
 		args = append([]string{"--git-dir=" + g.gitDir}, args...)
 	}
 
-	cmd := exec.Command("git", args...)
-	if g.workDir != "" {
-		cmd.Dir = g.workDir
-	}
+	output.Tracef("git %s", strings.Join(args, " "))
+	cmd := g.transportFor().Command(g.workDir, nil, "git", args...)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	if mutates(args) && dryrun.Command(cmd) {
+		return "", nil
+	}
+
+	span := telemetry.Leaf("git "+subcommand(args), attribute.String("git.args", strings.Join(args, " ")))
 	err := cmd.Run()
 	if err != nil {
-		return "", g.wrapError(err, stdout.String(), stderr.String(), args)
+		err = g.wrapError(err, stdout.String(), stderr.String(), args)
+		span.End(err)
+		return "", err
 	}
+	span.End(nil)
 
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// command returns a git command run where dir is: over SSH in a remote
+// rig, locally otherwise.
+func command(dir string, args ...string) *exec.Cmd {
+	return transport.For(dir).Command("", nil, "git", args...)
+}
+
+// transportFor returns where g runs git: over SSH in a remote rig, locally
+// otherwise.
+func (g *Git) transportFor() transport.Transport {
+	if g.workDir != "" {
+		return transport.For(g.workDir)
+	}
+	return transport.For(g.gitDir)
+}
+
+// subcommand returns the first non-flag argument, used to name spans.
+func subcommand(args []string) string {
+	for _, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			return a
+		}
+	}
+	return ""
+}
+
 // wrapError wraps git errors with context.
 // ZFC: Returns GitError with raw output for agent observation.
 // Does not detect or interpret error types - agents should observe and decide.
@@ -116,10 +153,13 @@ func (g *Git) wrapError(err error, stdout, stderr string, args []string) error {
 
 // Clone clones a repository to the destination.
 func (g *Git) Clone(url, dest string) error {
-	cmd := exec.Command("git", "clone", url, dest)
+	cmd := command(dest, "clone", url, dest)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	if dryrun.Command(cmd) {
+		return nil
+	}
 	if err := cmd.Run(); err != nil {
 		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", url})
 	}
@@ -134,10 +174,13 @@ func (g *Git) Clone(url, dest string) error {
 // CloneWithReference clones a repository using a local repo as an object reference.
 // This saves disk by sharing objects without changing remotes.
 func (g *Git) CloneWithReference(url, dest, reference string) error {
-	cmd := exec.Command("git", "clone", "--reference-if-able", reference, url, dest)
+	cmd := command(dest, "clone", "--reference-if-able", reference, url, dest)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	if dryrun.Command(cmd) {
+		return nil
+	}
 	if err := cmd.Run(); err != nil {
 		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", "--reference-if-able", url})
 	}
@@ -152,10 +195,13 @@ func (g *Git) CloneWithReference(url, dest, reference string) error {
 // CloneBare clones a repository as a bare repo (no working directory).
 // This is used for the shared repo architecture where all worktrees share a single git database.
 func (g *Git) CloneBare(url, dest string) error {
-	cmd := exec.Command("git", "clone", "--bare", url, dest)
+	cmd := command(dest, "clone", "--bare", url, dest)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	if dryrun.Command(cmd) {
+		return nil
+	}
 	if err := cmd.Run(); err != nil {
 		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", "--bare", url})
 	}
@@ -173,7 +219,7 @@ func configureHooksPath(repoPath string) error {
 		return nil
 	}
 
-	cmd := exec.Command("git", "-C", repoPath, "config", "core.hooksPath", ".githooks")
+	cmd := command(repoPath, "-C", repoPath, "config", "core.hooksPath", ".githooks")
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
@@ -188,14 +234,14 @@ func configureHooksPath(repoPath string) error {
 // and origin/main never appears in refs/remotes/origin/main.
 // See: https://github.com/anthropics/gastown/issues/286
 func configureRefspec(repoPath string) error {
-	cmd := exec.Command("git", "-C", repoPath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	cmd := command(repoPath, "-C", repoPath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("configuring refspec: %s", strings.TrimSpace(stderr.String()))
 	}
 	// Fetch to populate refs/remotes/origin/* so worktrees can use origin/main
-	fetchCmd := exec.Command("git", "-C", repoPath, "fetch", "origin")
+	fetchCmd := command(repoPath, "-C", repoPath, "fetch", "origin")
 	fetchCmd.Stderr = &stderr
 	if err := fetchCmd.Run(); err != nil {
 		return fmt.Errorf("fetching origin: %s", strings.TrimSpace(stderr.String()))
@@ -205,10 +251,13 @@ func configureRefspec(repoPath string) error {
 
 // CloneBareWithReference clones a bare repository using a local repo as an object reference.
 func (g *Git) CloneBareWithReference(url, dest, reference string) error {
-	cmd := exec.Command("git", "clone", "--bare", "--reference-if-able", reference, url, dest)
+	cmd := command(dest, "clone", "--bare", "--reference-if-able", reference, url, dest)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	if dryrun.Command(cmd) {
+		return nil
+	}
 	if err := cmd.Run(); err != nil {
 		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", "--bare", "--reference-if-able", url})
 	}
@@ -222,6 +271,13 @@ func (g *Git) Checkout(ref string) error {
 	return err
 }
 
+// CheckoutNewBranch switches to a new branch at startPoint, discarding
+// changes in the working tree. An existing branch of the name is reset.
+func (g *Git) CheckoutNewBranch(name, startPoint string) error {
+	_, err := g.run("checkout", "--force", "-B", name, startPoint)
+	return err
+}
+
 // Fetch fetches from the remote.
 func (g *Git) Fetch(remote string) error {
 	_, err := g.run("fetch", remote)
@@ -424,9 +480,18 @@ func (g *Git) AbortMerge() error {
 // The caller must ensure the working directory is clean before calling this.
 // After return, the working directory is restored to the target branch.
 func (g *Git) CheckConflicts(source, target string) ([]string, error) {
+	conflicts, _, err := g.CheckConflictHunks(source, target, 0)
+	return conflicts, err
+}
+
+// CheckConflictHunks is CheckConflicts that also returns the conflicted
+// hunks, as `git diff` shows them mid-merge (with conflict markers), so
+// whoever resolves the conflict can see it without reproducing the merge.
+// The hunks are cut at maxBytes when maxBytes > 0.
+func (g *Git) CheckConflictHunks(source, target string, maxBytes int) ([]string, string, error) {
 	// Checkout the target branch
 	if err := g.Checkout(target); err != nil {
-		return nil, fmt.Errorf("checkout target %s: %w", target, err)
+		return nil, "", fmt.Errorf("checkout target %s: %w", target, err)
 	}
 
 	// Attempt test merge with --no-commit --no-ff
@@ -438,27 +503,33 @@ func (g *Git) CheckConflicts(source, target string) ([]string, error) {
 		// GetConflictingFiles() uses `git diff --diff-filter=U` which is the proper way.
 		conflicts, err := g.GetConflictingFiles()
 		if err == nil && len(conflicts) > 0 {
+			hunks, _ := g.run(append([]string{"diff", "--"}, conflicts...)...)
+			if maxBytes > 0 && len(hunks) > maxBytes {
+				hunks = hunks[:maxBytes] + "\n... (truncated)"
+			}
 			// Abort the test merge (best-effort cleanup)
 			_ = g.AbortMerge()
-			return conflicts, nil
+			return conflicts, hunks, nil
 		}
 
 		// No unmerged files detected - this is some other merge error
 		_ = g.AbortMerge()
-		return nil, mergeErr
+		return nil, "", mergeErr
 	}
 
 	// Merge succeeded (no conflicts) - abort the test merge
 	// Use reset since --abort won't work on successful merge (best-effort cleanup)
 	_, _ = g.run("reset", "--hard", "HEAD")
-	return nil, nil
+	return nil, "", nil
 }
 
 // runMergeCheck runs a git merge command and returns error info from both stdout and stderr.
 // ZFC: Returns GitError with raw output for agent observation.
 func (g *Git) runMergeCheck(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = g.workDir
+	cmd := g.transportFor().Command(g.workDir, nil, "git", args...)
+	if dryrun.Command(cmd) {
+		return "", nil
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -498,6 +569,74 @@ func (g *Git) GetConflictingFiles() ([]string, error) {
 	return result, nil
 }
 
+// RebaseCheckResult is the outcome of RebaseCheck.
+type RebaseCheckResult struct {
+	// Conflicts lists the files that conflict at the first commit that
+	// fails to apply; empty when the branch rebases cleanly.
+	Conflicts []string
+
+	// Commit is the branch commit that failed to apply.
+	Commit string
+
+	// Overlap lists files changed both on the branch and on onto since
+	// they diverged, conflicting or not.
+	Overlap []string
+}
+
+// RebaseCheck tries rebasing branch onto onto in a scratch worktree at
+// scratchPath, which is removed afterwards, so no checkout is disturbed.
+// It doesn't fetch; callers fetch onto first.
+func (g *Git) RebaseCheck(branch, onto, scratchPath string) (*RebaseCheckResult, error) {
+	result := &RebaseCheckResult{}
+	base, err := g.run("merge-base", onto, branch)
+	if err != nil {
+		return nil, fmt.Errorf("finding merge base of %s and %s: %w", branch, onto, err)
+	}
+	ours, err := g.run("diff", "--name-only", base, branch)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := g.run("diff", "--name-only", base, onto)
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[string]bool)
+	for _, f := range strings.Split(theirs, "\n") {
+		changed[f] = f != ""
+	}
+	for _, f := range strings.Split(ours, "\n") {
+		if changed[f] {
+			result.Overlap = append(result.Overlap, f)
+		}
+	}
+	if len(result.Overlap) == 0 {
+		// Nothing both sides touched can't conflict
+		return result, nil
+	}
+
+	if _, err := g.run("worktree", "add", "--detach", scratchPath, branch); err != nil {
+		return nil, fmt.Errorf("creating scratch worktree: %w", err)
+	}
+	defer func() {
+		_ = g.WorktreeRemove(scratchPath, true)
+		_ = g.WorktreePrune()
+	}()
+
+	// The scratch commits are thrown away, so any identity will do
+	scratch := NewGit(scratchPath)
+	if _, err := scratch.runMergeCheck("-c", "user.name=Gas Town", "-c", "user.email=gastown@localhost", "rebase", onto); err != nil {
+		conflicts, cerr := scratch.GetConflictingFiles()
+		if cerr != nil || len(conflicts) == 0 {
+			_ = scratch.AbortRebase()
+			return nil, err
+		}
+		result.Conflicts = conflicts
+		result.Commit, _ = scratch.run("rev-parse", "--short", "REBASE_HEAD")
+		_ = scratch.AbortRebase()
+	}
+	return result, nil
+}
+
 // AbortRebase aborts a rebase in progress.
 func (g *Git) AbortRebase() error {
 	_, err := g.run("rebase", "--abort")
@@ -529,6 +668,80 @@ func (g *Git) BranchExists(name string) (bool, error) {
 	return true, nil
 }
 
+// LatestTag returns the most recent tag reachable from ref, or "" if
+// there is none.
+func (g *Git) LatestTag(ref string) (string, error) {
+	tag, err := g.run("describe", "--tags", "--abbrev=0", ref)
+	if err != nil {
+		if strings.Contains(err.Error(), "No names found") || strings.Contains(err.Error(), "No tags can describe") {
+			return "", nil
+		}
+		return "", err
+	}
+	return tag, nil
+}
+
+// TagExists checks if a tag exists.
+func (g *Git) TagExists(name string) (bool, error) {
+	_, err := g.run("show-ref", "--verify", "--quiet", "refs/tags/"+name)
+	if err != nil {
+		if strings.Contains(err.Error(), "exit status 1") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateTag creates an annotated tag at ref.
+func (g *Git) CreateTag(name, ref, message string) error {
+	_, err := g.run("tag", "--annotate", "--message", message, name, ref)
+	return err
+}
+
+// PushTag pushes a tag to the remote.
+func (g *Git) PushTag(remote, name string) error {
+	_, err := g.run("push", remote, "refs/tags/"+name)
+	return err
+}
+
+// CommitMessages returns the full messages of the commits in from..to,
+// newest first. An empty from means all of to's history.
+func (g *Git) CommitMessages(from, to string) ([]string, error) {
+	rangeSpec := to
+	if from != "" {
+		rangeSpec = from + ".." + to
+	}
+	out, err := g.run("log", "--format=%B%x00", rangeSpec)
+	if err != nil {
+		return nil, err
+	}
+	var msgs []string
+	for _, m := range strings.Split(out, "\x00") {
+		if m = strings.TrimSpace(m); m != "" {
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs, nil
+}
+
+// ListTree returns the paths of every file in ref's tree.
+func (g *Git) ListTree(ref string) ([]string, error) {
+	out, err := g.run("ls-tree", "-r", "--name-only", ref)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// ShowFile returns a file's content at ref, without checking it out.
+func (g *Git) ShowFile(ref, path string) (string, error) {
+	return g.run("show", ref+":"+path)
+}
+
 // RemoteBranchExists checks if a branch exists on the remote.
 func (g *Git) RemoteBranchExists(remote, branch string) (bool, error) {
 	_, err := g.run("ls-remote", "--heads", remote, branch)
@@ -571,6 +784,12 @@ func (g *Git) ListBranches(pattern string) ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// SetConfig sets a config value in the repository's local config.
+func (g *Git) SetConfig(key, value string) error {
+	_, err := g.run("config", key, value)
+	return err
+}
+
 // ResetBranch force-updates a branch to point to a ref.
 // This is useful for resetting stale polecat branches to main.
 func (g *Git) ResetBranch(name, ref string) error {
@@ -578,6 +797,13 @@ func (g *Git) ResetBranch(name, ref string) error {
 	return err
 }
 
+// ResetHard moves the current branch to ref, discarding changes in the
+// working tree.
+func (g *Git) ResetHard(ref string) error {
+	_, err := g.run("reset", "--hard", ref)
+	return err
+}
+
 // Rev returns the commit hash for the given ref.
 func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
@@ -644,12 +870,66 @@ func (g *Git) WorktreeAddExistingForce(path, branch string) error {
 	return ConfigureSparseCheckout(path)
 }
 
+// SparseScopeKey is the git config key holding the subdirectory a
+// path-scoped rig checks out (a service in a monorepo). Worktrees of a bare
+// repo share its config, so setting it there scopes every worktree.
+const SparseScopeKey = "gastown.path"
+
+// SparsePatterns returns the sparse-checkout patterns for a clone: the whole
+// tree, or with a scope only the top-level files and the scope directory.
+// Claude context files at the top level are excluded either way.
+func SparsePatterns(scope string) string {
+	var b strings.Builder
+	b.WriteString("/*\n")
+	if scope = strings.Trim(filepath.ToSlash(scope), "/"); scope != "" {
+		// Each ancestor's files but not its other directories, as cone mode does
+		b.WriteString("!/*/\n")
+		parts := strings.Split(scope, "/")
+		for i := range parts {
+			dir := "/" + strings.Join(parts[:i+1], "/") + "/"
+			b.WriteString(dir + "\n")
+			if i < len(parts)-1 {
+				b.WriteString("!" + dir + "*/\n")
+			}
+		}
+	}
+	b.WriteString("!/.claude/\n!/CLAUDE.md\n!/CLAUDE.local.md\n!/.mcp.json\n")
+	return b.String()
+}
+
+// SparseScope returns the subdirectory a clone is scoped to, or "".
+func SparseScope(repoPath string) string {
+	out, err := command(repoPath, "-C", repoPath, "config", "--get", SparseScopeKey).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// SetSparseScope scopes a clone or worktree to a subdirectory and reapplies
+// its sparse checkout.
+func SetSparseScope(repoPath, scope string) error {
+	if !dryrun.Enabled() {
+		cmd := command(repoPath, "-C", repoPath, "config", SparseScopeKey, scope)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("setting sparse scope: %s", strings.TrimSpace(string(out)))
+		}
+	}
+	return ConfigureSparseCheckout(repoPath)
+}
+
 // ConfigureSparseCheckout sets up sparse checkout for a clone or worktree to exclude .claude/.
 // This ensures source repo settings don't override Gas Town agent settings.
+// A clone scoped with SetSparseScope checks out only its subdirectory.
 // Exported for use by doctor checks.
 func ConfigureSparseCheckout(repoPath string) error {
+	if dryrun.Enabled() {
+		dryrun.Record("configure sparse checkout in %s (exclude .claude/, CLAUDE.md, CLAUDE.local.md, .mcp.json)", repoPath)
+		return nil
+	}
+
 	// Enable sparse checkout
-	cmd := exec.Command("git", "-C", repoPath, "config", "core.sparseCheckout", "true")
+	cmd := command(repoPath, "-C", repoPath, "config", "core.sparseCheckout", "true")
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
@@ -657,7 +937,7 @@ func ConfigureSparseCheckout(repoPath string) error {
 	}
 
 	// Get git dir for this repo/worktree
-	cmd = exec.Command("git", "-C", repoPath, "rev-parse", "--git-dir")
+	cmd = command(repoPath, "-C", repoPath, "rev-parse", "--git-dir")
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 	stderr.Reset()
@@ -679,25 +959,33 @@ func ConfigureSparseCheckout(repoPath string) error {
 	// - CLAUDE.local.md : personal context file
 	// - .mcp.json     : MCP server configuration
 	infoDir := filepath.Join(gitDir, "info")
-	if err := os.MkdirAll(infoDir, 0755); err != nil {
-		return fmt.Errorf("creating info dir: %w", err)
-	}
-	sparseFile := filepath.Join(infoDir, "sparse-checkout")
-	sparsePatterns := "/*\n!/.claude/\n!/CLAUDE.md\n!/CLAUDE.local.md\n!/.mcp.json\n"
-	if err := os.WriteFile(sparseFile, []byte(sparsePatterns), 0644); err != nil {
-		return fmt.Errorf("writing sparse-checkout: %w", err)
+	sparsePatterns := SparsePatterns(SparseScope(repoPath))
+	if tr := transport.For(repoPath); tr.Remote() {
+		// The worktree is in a remote rig: write the file there
+		write := tr.Command("", nil, "sh", "-c", `mkdir -p "$1" && printf '%s' "$2" > "$1/sparse-checkout"`, "sh", infoDir, sparsePatterns)
+		if out, err := write.CombinedOutput(); err != nil {
+			return fmt.Errorf("writing sparse-checkout: %v (%s)", err, strings.TrimSpace(string(out)))
+		}
+	} else {
+		if err := os.MkdirAll(infoDir, 0755); err != nil {
+			return fmt.Errorf("creating info dir: %w", err)
+		}
+		sparseFile := filepath.Join(infoDir, "sparse-checkout")
+		if err := os.WriteFile(sparseFile, []byte(sparsePatterns), 0644); err != nil {
+			return fmt.Errorf("writing sparse-checkout: %w", err)
+		}
 	}
 
 	// Check if HEAD exists (repo has commits) before running read-tree
 	// Empty repos (no commits) don't need read-tree and it would fail
-	checkHead := exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "HEAD")
+	checkHead := command(repoPath, "-C", repoPath, "rev-parse", "--verify", "HEAD")
 	if err := checkHead.Run(); err != nil {
 		// No commits yet, sparse checkout config is set up for future use
 		return nil
 	}
 
 	// Reapply to remove excluded files
-	cmd = exec.Command("git", "-C", repoPath, "read-tree", "-mu", "HEAD")
+	cmd = command(repoPath, "-C", repoPath, "read-tree", "-mu", "HEAD")
 	stderr.Reset()
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
@@ -735,14 +1023,14 @@ func CheckExcludedFilesExist(repoPath string) []string {
 // file contains all required exclusion patterns.
 func IsSparseCheckoutConfigured(repoPath string) bool {
 	// Check if core.sparseCheckout is true
-	cmd := exec.Command("git", "-C", repoPath, "config", "core.sparseCheckout")
+	cmd := command(repoPath, "-C", repoPath, "config", "core.sparseCheckout")
 	output, err := cmd.Output()
 	if err != nil || strings.TrimSpace(string(output)) != "true" {
 		return false
 	}
 
 	// Get git dir for this repo/worktree
-	cmd = exec.Command("git", "-C", repoPath, "rev-parse", "--git-dir")
+	cmd = command(repoPath, "-C", repoPath, "rev-parse", "--git-dir")
 	output, err = cmd.Output()
 	if err != nil {
 		return false
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -385,6 +386,18 @@ func TestCheckConflicts_WithConflict(t *testing.T) {
 		t.Errorf("expected README.md in conflicts, got %v", conflicts)
 	}
 
+	// The hunks show both sides of the conflict
+	_, hunks, err := g.CheckConflictHunks("feature", mainBranch, 0)
+	if err != nil {
+		t.Fatalf("CheckConflictHunks: %v", err)
+	}
+	if !strings.Contains(hunks, "<<<<<<<") || !strings.Contains(hunks, "Feature changes") || !strings.Contains(hunks, "Main changes") {
+		t.Errorf("hunks missing conflict markers or sides:\n%s", hunks)
+	}
+	if _, hunks, _ := g.CheckConflictHunks("feature", mainBranch, 10); !strings.HasSuffix(hunks, "(truncated)") {
+		t.Errorf("expected truncated hunks, got:\n%s", hunks)
+	}
+
 	// Verify we're still on main and clean
 	branch, _ := g.CurrentBranch()
 	if branch != mainBranch {
@@ -396,6 +409,80 @@ func TestCheckConflicts_WithConflict(t *testing.T) {
 	}
 }
 
+func TestRebaseCheck(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+
+	commit := func(file, content, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+		if err := g.Add(file); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if err := g.Commit(msg); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+
+	// feature edits README.md and adds notes.txt; main edits README.md too
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout feature: %v", err)
+	}
+	commit("notes.txt", "notes\n", "add notes")
+	commit("README.md", "# Feature\n", "feature readme")
+	if err := g.Checkout(mainBranch); err != nil {
+		t.Fatalf("Checkout main: %v", err)
+	}
+	commit("README.md", "# Main\n", "main readme")
+
+	scratch := filepath.Join(t.TempDir(), "scratch")
+	result, err := g.RebaseCheck("feature", mainBranch, scratch)
+	if err != nil {
+		t.Fatalf("RebaseCheck: %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "README.md" {
+		t.Errorf("Conflicts = %v, want [README.md]", result.Conflicts)
+	}
+	if result.Commit == "" {
+		t.Error("expected the failing commit")
+	}
+	if len(result.Overlap) != 1 || result.Overlap[0] != "README.md" {
+		t.Errorf("Overlap = %v, want [README.md]", result.Overlap)
+	}
+	if _, err := os.Stat(scratch); !os.IsNotExist(err) {
+		t.Error("scratch worktree should be removed")
+	}
+	if branch, _ := g.CurrentBranch(); branch != mainBranch {
+		t.Errorf("branch = %q, want %q", branch, mainBranch)
+	}
+
+	// A branch touching only its own files rebases cleanly
+	if err := g.CreateBranch("other"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("other"); err != nil {
+		t.Fatalf("Checkout other: %v", err)
+	}
+	commit("other.txt", "other\n", "add other")
+	if err := g.Checkout(mainBranch); err != nil {
+		t.Fatalf("Checkout main: %v", err)
+	}
+	commit("main.txt", "main\n", "add main")
+	result, err = g.RebaseCheck("other", mainBranch, scratch)
+	if err != nil {
+		t.Fatalf("RebaseCheck clean: %v", err)
+	}
+	if len(result.Conflicts) != 0 {
+		t.Errorf("clean rebase Conflicts = %v", result.Conflicts)
+	}
+}
+
 // TestCloneBareHasOriginRefs verifies that after CloneBare, origin/* refs
 // are available for worktree creation. This was broken before the fix:
 // bare clones had refspec configured but no fetch was run, so origin/main
@@ -488,3 +575,160 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestMutates(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"status", "--porcelain"}, false},
+		{[]string{"-C", "/tmp/rig", "log", "--oneline"}, false},
+		{[]string{"worktree", "list", "--porcelain"}, false},
+		{[]string{"worktree", "add", "-b", "polecat/Toast", "/tmp/wt"}, true},
+		{[]string{"branch"}, false},
+		{[]string{"branch", "--list", "polecat/*"}, false},
+		{[]string{"branch", "polecat/Toast"}, true},
+		{[]string{"branch", "-D", "polecat/Toast"}, true},
+		{[]string{"tag", "-l"}, false},
+		{[]string{"tag", "-a", "v1.0", "-m", "release"}, true},
+		{[]string{"config", "user.name"}, false},
+		{[]string{"config", "--get", "remote.origin.url"}, false},
+		{[]string{"config", "user.name", "Gas Town"}, true},
+		{[]string{"config", "--unset", "branch.main.merge"}, true},
+		{[]string{"remote"}, false},
+		{[]string{"remote", "get-url", "origin"}, false},
+		{[]string{"remote", "add", "upstream", "/tmp/repo"}, true},
+		{[]string{"push", "origin", "main"}, true},
+		{[]string{"merge", "--no-ff", "polecat/Toast"}, true},
+	}
+	for _, tt := range tests {
+		if got := mutates(tt.args); got != tt.want {
+			t.Errorf("mutates(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestDiffStat(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	files := map[string]string{
+		"README.md":           "# Test\nmore\n",
+		"pkg/foo/foo.go":      "package foo\n\nfunc Foo() {}\n",
+		"pkg/foo/foo_test.go": "package foo\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Add(name); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if err := g.Commit("feature work"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	// A later commit on main isn't part of the branch's changes
+	if err := g.Checkout(mainBranch); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.txt"), []byte("main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("main.txt"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("main work"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	stat, err := g.DiffStat(mainBranch, "feature")
+	if err != nil {
+		t.Fatalf("DiffStat: %v", err)
+	}
+	if stat.Commits != 1 || len(stat.Files) != 3 {
+		t.Errorf("commits, files = %d, %d, want 1, 3", stat.Commits, len(stat.Files))
+	}
+	if stat.Insertions != 5 || stat.Deletions != 0 {
+		t.Errorf("insertions, deletions = %d, %d, want 5, 0", stat.Insertions, stat.Deletions)
+	}
+	if want := []string{".", "pkg/foo"}; strings.Join(stat.Packages, ",") != strings.Join(want, ",") {
+		t.Errorf("packages = %v, want %v", stat.Packages, want)
+	}
+	if stat.TestFiles != 1 || stat.TestInsertions != 1 {
+		t.Errorf("test files, insertions = %d, %d, want 1, 1", stat.TestFiles, stat.TestInsertions)
+	}
+	if md := stat.Markdown(); !strings.Contains(md, "pkg/foo/foo.go +3 -0") || !strings.Contains(md, "Tests: 1 file(s)") {
+		t.Errorf("Markdown missing details:\n%s", md)
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	for p, want := range map[string]bool{
+		"internal/git/git_test.go": true,
+		"tests/test_api.py":        true,
+		"src/app.spec.ts":          true,
+		"src/FooTest.java":         true,
+		"lib/test_helpers.py":      true,
+		"internal/git/git.go":      false,
+		"docs/testing.md":          false,
+		"latest.go":                false,
+	} {
+		if got := IsTestFile(p); got != want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestSparseScope(t *testing.T) {
+	src := initTestRepo(t)
+	for _, f := range []string{"go.mod", "services/auth/main.go", "services/auth/internal/x.go", "services/billing/main.go", "web/index.html"} {
+		path := filepath.Join(src, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = exec.Command("git", "-C", src, "add", ".").Run()
+	_ = exec.Command("git", "-C", src, "commit", "-m", "monorepo").Run()
+
+	dst := filepath.Join(t.TempDir(), "clone")
+	if err := NewGit(t.TempDir()).Clone(src, dst); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if err := SetSparseScope(dst, "services/auth"); err != nil {
+		t.Fatalf("SetSparseScope: %v", err)
+	}
+	if got := SparseScope(dst); got != "services/auth" {
+		t.Errorf("SparseScope = %q", got)
+	}
+	for f, want := range map[string]bool{
+		"README.md":                   true,
+		"go.mod":                      true,
+		"services/auth/main.go":       true,
+		"services/auth/internal/x.go": true,
+		"services/billing/main.go":    false,
+		"web/index.html":              false,
+	} {
+		if _, err := os.Stat(filepath.Join(dst, f)); (err == nil) != want {
+			t.Errorf("%s checked out = %v, want %v", f, err == nil, want)
+		}
+	}
+	if !IsSparseCheckoutConfigured(dst) {
+		t.Error("a scoped clone should still exclude Claude context files")
+	}
+}
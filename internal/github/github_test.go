@@ -0,0 +1,571 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// fakeStore is an in-memory Store.
+type fakeStore struct {
+	issues []*beads.Issue
+	nextID int
+	lists  int
+}
+
+func (s *fakeStore) List(opts beads.ListOptions) ([]*beads.Issue, error) {
+	s.lists++
+	return s.issues, nil
+}
+
+func (s *fakeStore) Create(opts beads.CreateOptions) (*beads.Issue, error) {
+	s.nextID++
+	is := &beads.Issue{
+		ID:          "gt-" + string(rune('a'+s.nextID-1)),
+		Title:       opts.Title,
+		Description: opts.Description,
+		Status:      "open",
+		Priority:    opts.Priority,
+		Labels:      opts.Labels,
+		ExternalRef: opts.ExternalRef,
+	}
+	s.issues = append(s.issues, is)
+	return is, nil
+}
+
+func (s *fakeStore) CloseWithReason(reason string, ids ...string) error {
+	for _, is := range s.issues {
+		for _, id := range ids {
+			if is.ID == id {
+				is.Status = "closed"
+			}
+		}
+	}
+	return nil
+}
+
+func newTestIngester(store *fakeStore) *Ingester {
+	in := NewIngester("/town", &config.RigsConfig{Rigs: map[string]config.RigEntry{
+		"gastown": {GitURL: "git@github.com:steveyegge/gastown.git"},
+		"local":   {GitURL: "/srv/git/local.git"},
+	}})
+	in.storeFor = func(string) Store { return store }
+	return in
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+const issueOpened = `{
+  "action": "opened",
+  "repository": {"full_name": "steveyegge/gastown"},
+  "issue": {
+    "number": 42, "title": "Crash on startup", "body": "Steps...",
+    "html_url": "https://github.com/steveyegge/gastown/issues/42",
+    "state": "open", "user": {"login": "alice"},
+    "labels": [{"name": "bug"}, {"name": "P1"}]
+  }
+}`
+
+func TestRepoFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+		ok   bool
+	}{
+		{"https://github.com/steveyegge/gastown.git", "steveyegge/gastown", true},
+		{"https://github.com/steveyegge/gastown", "steveyegge/gastown", true},
+		{"git@github.com:steveyegge/gastown.git", "steveyegge/gastown", true},
+		{"git@gitlab.com:foo/bar.git", "", false},
+		{"/srv/git/local.git", "", false},
+		{"https://github.com/steveyegge", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := RepoFromURL(tt.url)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("RepoFromURL(%q) = %q, %v; want %q, %v", tt.url, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"x":1}`)
+	if !VerifySignature("s3cret", body, sign("s3cret", body)) {
+		t.Error("valid signature rejected")
+	}
+	if VerifySignature("other", body, sign("s3cret", body)) {
+		t.Error("signature with wrong secret accepted")
+	}
+	if VerifySignature("s3cret", body, "sha1=abc") {
+		t.Error("non-sha256 signature accepted")
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	item, err := ParseEvent("issues", []byte(issueOpened))
+	if err != nil {
+		t.Fatalf("ParseEvent: %v", err)
+	}
+	if item.Kind != KindIssue || item.Number != 42 || item.Author != "alice" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	if got := item.ExternalRef(); got != "gh-steveyegge/gastown#42" {
+		t.Errorf("ExternalRef() = %q", got)
+	}
+
+	if _, err := ParseEvent("ping", []byte(`{"zen":"hi"}`)); !errors.Is(err, ErrIgnored) {
+		t.Errorf("ping: err = %v, want ErrIgnored", err)
+	}
+
+	labeled := strings.Replace(issueOpened, `"opened"`, `"labeled"`, 1)
+	if _, err := ParseEvent("issues", []byte(labeled)); !errors.Is(err, ErrIgnored) {
+		t.Errorf("labeled: err = %v, want ErrIgnored", err)
+	}
+
+	comment := `{"action":"created","repository":{"full_name":"steveyegge/gastown"},
+	  "pull_request":{"number":7,"title":"Add feed"},
+	  "comment":{"id":99,"body":"nit: rename","path":"feed.go","line":12,"user":{"login":"bob"}}}`
+	item, err = ParseEvent("pull_request_review_comment", []byte(comment))
+	if err != nil {
+		t.Fatalf("review comment: %v", err)
+	}
+	if got := item.ExternalRef(); got != "gh-steveyegge/gastown#7/review-comment/99" {
+		t.Errorf("review comment ExternalRef() = %q", got)
+	}
+
+	approved := `{"action":"submitted","repository":{"full_name":"steveyegge/gastown"},
+	  "pull_request":{"number":7},"review":{"id":5,"state":"approved","body":"LGTM"}}`
+	if _, err := ParseEvent("pull_request_review", []byte(approved)); !errors.Is(err, ErrIgnored) {
+		t.Errorf("approved review: err = %v, want ErrIgnored", err)
+	}
+}
+
+func TestIngestIdempotentAndClose(t *testing.T) {
+	store := &fakeStore{}
+	in := newTestIngester(store)
+
+	item, _ := ParseEvent("issues", []byte(issueOpened))
+	res, err := in.Ingest(item)
+	if err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	if res.Action != ActionCreated || res.Rig != "gastown" {
+		t.Fatalf("first ingest = %+v, want created in gastown", res)
+	}
+	created := store.issues[0]
+	if created.Priority != 1 {
+		t.Errorf("priority = %d, want 1 from P1 label", created.Priority)
+	}
+	if !strings.Contains(created.Description, "https://github.com/steveyegge/gastown/issues/42") {
+		t.Errorf("description missing source URL: %q", created.Description)
+	}
+
+	res, _ = in.Ingest(item)
+	if res.Action != ActionExists || len(store.issues) != 1 {
+		t.Errorf("second ingest = %+v with %d beads, want exists with 1", res, len(store.issues))
+	}
+
+	item.Closed = true
+	res, _ = in.Ingest(item)
+	if res.Action != ActionClosed || store.issues[0].Status != "closed" {
+		t.Errorf("close ingest = %+v, status %s", res, store.issues[0].Status)
+	}
+
+	if _, err := in.Ingest(&Item{Kind: KindIssue, Repo: "other/repo", Number: 1}); err == nil {
+		t.Error("expected error for untracked repo")
+	}
+}
+
+func TestIngestAllListsOncePerBatch(t *testing.T) {
+	store := &fakeStore{}
+	in := newTestIngester(store)
+
+	items := []*Item{
+		{Kind: KindIssue, Repo: "steveyegge/gastown", Number: 1, Title: "one"},
+		{Kind: KindIssue, Repo: "steveyegge/gastown", Number: 2, Title: "two"},
+		{Kind: KindIssue, Repo: "steveyegge/gastown", Number: 1, Title: "one", Closed: true},
+		{Kind: KindIssue, Repo: "other/repo", Number: 3},
+	}
+	results, errs := in.IngestAll(items)
+	if store.lists != 1 {
+		t.Errorf("listed mirrored beads %d times, want once for the batch", store.lists)
+	}
+	if results[0].Action != ActionCreated || results[1].Action != ActionCreated {
+		t.Errorf("results = %+v %+v, want both created", results[0], results[1])
+	}
+	if results[2].Action != ActionClosed || results[2].BeadID != results[0].BeadID {
+		t.Errorf("close in the same batch = %+v, want %s closed", results[2], results[0].BeadID)
+	}
+	if errs[3] == nil {
+		t.Error("expected error for untracked repo")
+	}
+}
+
+func TestPriorityFromLabel(t *testing.T) {
+	tests := []struct {
+		label string
+		want  int
+		ok    bool
+	}{
+		{"P0", 0, true},
+		{"priority: high", 1, true},
+		{"priority/3", 3, true},
+		{"high", 0, false},
+		{"1", 0, false},
+		{"enhancement", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := priorityFromLabel(tt.label)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("priorityFromLabel(%q) = %d, %v; want %d, %v", tt.label, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	store := &fakeStore{}
+	h := NewWebhookHandler(newTestIngester(store), "s3cret")
+
+	post := func(event, body, sig string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", sig)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("issues", issueOpened, "sha256=00"); w.Code != http.StatusUnauthorized {
+		t.Errorf("bad signature: status %d, want 401", w.Code)
+	}
+	if w := post("issues", issueOpened, sign("s3cret", []byte(issueOpened))); w.Code != http.StatusOK {
+		t.Errorf("valid delivery: status %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if len(store.issues) != 1 {
+		t.Errorf("expected 1 bead, got %d", len(store.issues))
+	}
+	ping := `{"zen":"hi"}`
+	if w := post("ping", ping, sign("s3cret", []byte(ping))); w.Code != http.StatusNoContent {
+		t.Errorf("ping: status %d, want 204", w.Code)
+	}
+}
+
+func TestPoll(t *testing.T) {
+	saved := ghAPI
+	defer func() { ghAPI = saved }()
+
+	ghAPI = func(path string) ([]byte, error) {
+		switch {
+		case strings.HasPrefix(path, "repos/steveyegge/gastown/issues?"):
+			// Two pages, one of which includes a pull request to skip.
+			return []byte(`[{"number":1,"title":"A","state":"open"}]
+[{"number":2,"title":"PR","state":"open","pull_request":{}}]`), nil
+		case strings.HasPrefix(path, "repos/steveyegge/gastown/pulls/comments?"):
+			return []byte(`[{"id":9,"body":"fix","path":"a.go","line":3,
+  "pull_request_url":"https://api.github.com/repos/steveyegge/gastown/pulls/2"}]`), nil
+		}
+		t.Fatalf("unexpected path %s", path)
+		return nil, nil
+	}
+
+	items, err := Poll("steveyegge/gastown", time.Time{})
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	if items[0].Kind != KindIssue || items[0].Number != 1 {
+		t.Errorf("items[0] = %+v", items[0])
+	}
+	if items[1].Kind != KindReviewComment || items[1].Number != 2 || items[1].ID != 9 {
+		t.Errorf("items[1] = %+v", items[1])
+	}
+}
+
+func TestListIssues(t *testing.T) {
+	saved := ghAPI
+	defer func() { ghAPI = saved }()
+
+	var gotPath string
+	ghAPI = func(path string) ([]byte, error) {
+		gotPath = path
+		return []byte(`[{"number":4,"title":"Backlog","state":"open","labels":[{"name":"agent-ok"}]},
+  {"number":5,"title":"PR","state":"open","pull_request":{}}]`), nil
+	}
+
+	items, err := ListIssues("steveyegge/gastown", []string{"agent-ok", "p1"})
+	if err != nil {
+		t.Fatalf("ListIssues: %v", err)
+	}
+	if !strings.Contains(gotPath, "labels=agent-ok%2Cp1") || !strings.Contains(gotPath, "state=open") {
+		t.Errorf("path = %s", gotPath)
+	}
+	if len(items) != 1 || items[0].Number != 4 || items[0].Labels[0] != "agent-ok" {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestIngesterTrack(t *testing.T) {
+	in := newTestIngester(&fakeStore{})
+	in.Track("Acme/Backlog", "local")
+	if rig, ok := in.RigFor("acme/backlog"); !ok || rig != "local" {
+		t.Errorf("RigFor = %q, %v; want local", rig, ok)
+	}
+}
+
+func TestParsePRView(t *testing.T) {
+	tests := []struct {
+		name   string
+		rollup string
+		want   string
+	}{
+		{"no checks", `[]`, ""},
+		{"all passing", `[{"status":"COMPLETED","conclusion":"SUCCESS"},{"state":"SUCCESS"}]`, ChecksPassing},
+		{"pending", `[{"status":"COMPLETED","conclusion":"SUCCESS"},{"status":"IN_PROGRESS"}]`, ChecksPending},
+		{"failure wins", `[{"status":"IN_PROGRESS"},{"status":"COMPLETED","conclusion":"FAILURE"},{"state":"SUCCESS"}]`, ChecksFailing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := `{"number":5,"url":"https://github.com/o/r/pull/5","state":"OPEN",
+			  "reviewDecision":"CHANGES_REQUESTED","headRefName":"polecat/nux/gt-1",
+			  "statusCheckRollup":` + tt.rollup + `}`
+			pr, err := parsePRView([]byte(data))
+			if err != nil {
+				t.Fatalf("parsePRView: %v", err)
+			}
+			if pr.State != PRStateOpen || pr.Review != "changes_requested" || pr.Branch != "polecat/nux/gt-1" {
+				t.Errorf("unexpected PR: %+v", pr)
+			}
+			if pr.Checks != tt.want {
+				t.Errorf("Checks = %q, want %q", pr.Checks, tt.want)
+			}
+		})
+	}
+}
+
+// fakePRStore serves issues and records updates for RefreshPR.
+type fakePRStore struct {
+	issues   []*beads.Issue
+	updated  map[string]beads.UpdateOptions
+	closed   []string
+	closeErr error
+}
+
+func (s *fakePRStore) List(opts beads.ListOptions) ([]*beads.Issue, error) { return s.issues, nil }
+
+func (s *fakePRStore) Update(id string, opts beads.UpdateOptions) error {
+	s.updated[id] = opts
+	return nil
+}
+
+func (s *fakePRStore) CloseWithReason(reason string, ids ...string) error {
+	if s.closeErr != nil {
+		return s.closeErr
+	}
+	s.closed = append(s.closed, ids...)
+	return nil
+}
+
+func TestRefreshPRMergedClosesBead(t *testing.T) {
+	saved := ghRun
+	defer func() { ghRun = saved }()
+	ghRun = func(dir string, args ...string) ([]byte, error) {
+		return []byte(`{"number":5,"url":"https://github.com/o/r/pull/5","state":"MERGED",
+		  "reviewDecision":"APPROVED","headRefName":"b","statusCheckRollup":[]}`), nil
+	}
+
+	store := &fakePRStore{updated: map[string]beads.UpdateOptions{}}
+	issue := &beads.Issue{ID: "gt-1", Description: "Work.\n\npr_url: https://github.com/o/r/pull/5\npr_state: open"}
+
+	pr, changed, err := RefreshPR(store, issue)
+	if err != nil {
+		t.Fatalf("RefreshPR: %v", err)
+	}
+	if !changed || pr.State != PRStateMerged {
+		t.Errorf("changed=%v state=%s, want changed merged", changed, pr.State)
+	}
+	opts := store.updated["gt-1"]
+	if opts.Description == nil || !strings.Contains(*opts.Description, "pr_state: merged") {
+		t.Errorf("description not updated: %v", opts.Description)
+	}
+	if len(opts.RemoveLabels) != 1 || opts.RemoveLabels[0] != LabelPR {
+		t.Errorf("RemoveLabels = %v, want [%s]", opts.RemoveLabels, LabelPR)
+	}
+	if len(store.closed) != 1 || store.closed[0] != "gt-1" {
+		t.Errorf("closed = %v, want [gt-1]", store.closed)
+	}
+}
+
+func TestRefreshPRMergedCloseFailsKeepsLabel(t *testing.T) {
+	saved := ghRun
+	defer func() { ghRun = saved }()
+	ghRun = func(dir string, args ...string) ([]byte, error) {
+		return []byte(`{"number":5,"url":"https://github.com/o/r/pull/5","state":"MERGED",
+		  "reviewDecision":"APPROVED","headRefName":"b","statusCheckRollup":[]}`), nil
+	}
+
+	store := &fakePRStore{updated: map[string]beads.UpdateOptions{}, closeErr: errors.New("bd unavailable")}
+	issue := &beads.Issue{ID: "gt-1", Status: "in_progress", Labels: []string{LabelPR},
+		Description: "Work.\n\npr_url: https://github.com/o/r/pull/5\npr_state: open"}
+	store.issues = []*beads.Issue{issue}
+
+	if _, _, err := RefreshPR(store, issue); err == nil {
+		t.Fatal("RefreshPR succeeded though the close failed")
+	}
+	if opts, ok := store.updated["gt-1"]; ok {
+		t.Errorf("bead updated (%+v) though it could not be closed; it would drop out of tracking", opts)
+	}
+	if tracked, _ := TrackedPRs(store); len(tracked) != 1 {
+		t.Errorf("TrackedPRs = %v, want gt-1 still tracked for the next refresh", tracked)
+	}
+}
+
+func TestPRBeadTrackedFromDoneToMerge(t *testing.T) {
+	saved := ghRun
+	defer func() { ghRun = saved }()
+	state := "OPEN"
+	ghRun = func(dir string, args ...string) ([]byte, error) {
+		return []byte(`{"number":5,"url":"https://github.com/o/r/pull/5","state":"` + state + `",
+		  "reviewDecision":"","headRefName":"b","statusCheckRollup":[]}`), nil
+	}
+
+	// gt done: the hooked bead was submitted as a PR, so it is left in
+	// progress instead of closed
+	issue := &beads.Issue{
+		ID:          "gt-1",
+		Status:      beads.StatusHooked,
+		Labels:      []string{LabelPR},
+		Description: "Work.\n\npr_url: https://github.com/o/r/pull/5\npr_state: open",
+	}
+	if !SubmittedAsPR(issue) {
+		t.Fatal("SubmittedAsPR = false for a gh:pr bead with a pr_url")
+	}
+	if SubmittedAsPR(&beads.Issue{ID: "gt-2", Status: beads.StatusHooked}) {
+		t.Error("SubmittedAsPR = true for a plain hooked bead")
+	}
+	issue.Status = "in_progress"
+	store := &fakePRStore{
+		issues:  []*beads.Issue{issue, {ID: "gt-0", Status: "closed", Labels: []string{LabelPR}}},
+		updated: map[string]beads.UpdateOptions{},
+	}
+
+	// gt github sync while the PR is open: tracked, not closed
+	tracked, err := TrackedPRs(store)
+	if err != nil || len(tracked) != 1 || tracked[0].ID != "gt-1" {
+		t.Fatalf("TrackedPRs = %v, %v; want the in-progress gt-1 only", tracked, err)
+	}
+	if _, _, err := RefreshPR(store, tracked[0]); err != nil {
+		t.Fatalf("RefreshPR: %v", err)
+	}
+	if len(store.closed) != 0 {
+		t.Fatalf("closed %v while the PR is open", store.closed)
+	}
+
+	// gt github sync after the merge: the bead closes
+	state = "MERGED"
+	if _, _, err := RefreshPR(store, tracked[0]); err != nil {
+		t.Fatalf("RefreshPR: %v", err)
+	}
+	if len(store.closed) != 1 || store.closed[0] != "gt-1" {
+		t.Errorf("closed = %v, want [gt-1] once merged", store.closed)
+	}
+}
+
+// fakeMirrorStore serves issue and merge request beads by label.
+type fakeMirrorStore struct {
+	byLabel map[string][]*beads.Issue
+	updated map[string]beads.UpdateOptions
+}
+
+func (s *fakeMirrorStore) List(opts beads.ListOptions) ([]*beads.Issue, error) {
+	return s.byLabel[opts.Label], nil
+}
+
+func (s *fakeMirrorStore) Update(id string, opts beads.UpdateOptions) error {
+	s.updated[id] = opts
+	return nil
+}
+
+func TestMirrorTransitions(t *testing.T) {
+	store := &fakeMirrorStore{
+		byLabel: map[string][]*beads.Issue{
+			LabelIssue: {
+				{ID: "gt-1", Status: "open", ExternalRef: "gh-o/r#1"},
+				{ID: "gt-2", Status: beads.StatusHooked, Assignee: "gastown/polecats/Toast", ExternalRef: "gh-o/r#2"},
+				{ID: "gt-3", Status: "open", ExternalRef: "gh-o/r#3", Description: "Fix it.\n\ngh_mirror: claimed"},
+				{ID: "gt-4", Status: "closed", ExternalRef: "gh-o/r#4", Description: "gh_mirror: submitted"},
+				{ID: "gt-5", Status: "closed", ExternalRef: "gh-o/r#5", Description: "gh_mirror: merged"},
+			},
+			"gt:merge-request": {
+				{ID: "gt-mr3", Status: "open", Description: "branch: b3\ntarget: main\nsource_issue: gt-3"},
+				{ID: "gt-mr4a", Status: "closed", Description: "branch: b4\nsource_issue: gt-4\nclose_reason: rejected"},
+				{ID: "gt-mr4b", Status: "closed", Description: "branch: b4\ntarget: main\nsource_issue: gt-4\nmerge_commit: 0123456789abcdef\nclose_reason: merged"},
+			},
+		},
+		updated: map[string]beads.UpdateOptions{},
+	}
+
+	pending, err := PendingTransitions("gastown", store)
+	if err != nil {
+		t.Fatalf("PendingTransitions: %v", err)
+	}
+	got := map[string]string{}
+	for _, tr := range pending {
+		got[tr.BeadID] = tr.Stage
+	}
+	want := map[string]string{"gt-2": StageClaimed, "gt-3": StageSubmitted, "gt-4": StageMerged}
+	if len(got) != len(want) {
+		t.Fatalf("transitions = %v, want %v", got, want)
+	}
+	for id, stage := range want {
+		if got[id] != stage {
+			t.Errorf("%s stage = %q, want %q", id, got[id], stage)
+		}
+	}
+
+	var calls []string
+	saved := ghRun
+	defer func() { ghRun = saved }()
+	ghRun = func(dir string, args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args[:2], " "))
+		return []byte(`{"state":"OPEN"}`), nil
+	}
+	for _, tr := range pending {
+		if err := PostTransition(store, tr); err != nil {
+			t.Fatalf("PostTransition(%s): %v", tr.BeadID, err)
+		}
+		if tr.Stage == StageMerged && !strings.Contains(tr.Comment, "0123456789ab") {
+			t.Errorf("merged comment = %q, want the merge commit", tr.Comment)
+		}
+	}
+	if strings.Join(calls, ",") != "issue comment,issue comment,issue view,issue close" {
+		t.Errorf("gh calls = %v", calls)
+	}
+	if d := store.updated["gt-3"].Description; d == nil || *d != "Fix it.\n\ngh_mirror: submitted" {
+		t.Errorf("gt-3 description = %v", d)
+	}
+}
+
+func TestParseIssueRef(t *testing.T) {
+	if repo, n, ok := ParseIssueRef("gh-o/r#42"); !ok || repo != "o/r" || n != 42 {
+		t.Errorf("ParseIssueRef = %q %d %v", repo, n, ok)
+	}
+	for _, ref := range []string{"gh-o/r#42/review-comment/7", "gh-42", "jira-X-1"} {
+		if _, _, ok := ParseIssueRef(ref); ok {
+			t.Errorf("ParseIssueRef(%q) parsed", ref)
+		}
+	}
+}
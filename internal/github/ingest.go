@@ -0,0 +1,256 @@
+package github
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// LabelGitHub marks every bead mirrored from GitHub. Kind-specific labels
+// (gh:issue, gh:review-comment, gh:review) are added alongside it.
+const LabelGitHub = "github"
+
+// DefaultPriority is used when an issue carries no priority label.
+const DefaultPriority = 2
+
+// Ingestion outcomes.
+const (
+	ActionCreated = "created" // New bead created
+	ActionExists  = "exists"  // Already mirrored, nothing to do
+	ActionClosed  = "closed"  // Mirrored bead closed because the source closed
+	ActionSkipped = "skipped" // Nothing to mirror (e.g., closed before first sight)
+)
+
+// Store is the subset of the beads API used for ingestion.
+type Store interface {
+	List(opts beads.ListOptions) ([]*beads.Issue, error)
+	Create(opts beads.CreateOptions) (*beads.Issue, error)
+	CloseWithReason(reason string, ids ...string) error
+}
+
+// Result describes what ingesting one item did.
+type Result struct {
+	Action string `json:"action"`
+	Rig    string `json:"rig"`
+	BeadID string `json:"bead_id,omitempty"`
+	Ref    string `json:"external_ref"`
+}
+
+// Ingester mirrors GitHub items into the beads of the rig whose git URL
+// matches the item's repository.
+type Ingester struct {
+	rigs     map[string]string // lowercase "owner/name" -> rig name
+	storeFor func(rig string) Store
+}
+
+// NewIngester creates an ingester for the rigs registered in the town.
+// Rigs whose git URL isn't on GitHub are ignored.
+func NewIngester(townRoot string, rigsConfig *config.RigsConfig) *Ingester {
+	in := &Ingester{
+		rigs: make(map[string]string),
+		storeFor: func(rig string) Store {
+			return beads.New(filepath.Join(townRoot, rig))
+		},
+	}
+	for name, entry := range rigsConfig.Rigs {
+		if repo, ok := RepoFromURL(entry.GitURL); ok {
+			in.rigs[strings.ToLower(repo)] = name
+		}
+	}
+	return in
+}
+
+// RigFor returns the rig tracking the given repository.
+func (in *Ingester) RigFor(repo string) (string, bool) {
+	rig, ok := in.rigs[strings.ToLower(repo)]
+	return rig, ok
+}
+
+// Track routes a repository's items to a rig, overriding its git URL.
+func (in *Ingester) Track(repo, rig string) {
+	in.rigs[strings.ToLower(repo)] = rig
+}
+
+// Repos returns the GitHub repositories tracked by rigs.
+func (in *Ingester) Repos() map[string]string {
+	return in.rigs
+}
+
+// Ingest mirrors one item. It is idempotent: an item whose external ref is
+// already present in the rig's beads is not created again.
+func (in *Ingester) Ingest(item *Item) (*Result, error) {
+	results, errs := in.IngestAll([]*Item{item})
+	return results[0], errs[0]
+}
+
+// IngestAll mirrors a batch of items, listing each rig's mirrored beads
+// once for the whole batch instead of once per item. results[i] and
+// errs[i] describe items[i].
+func (in *Ingester) IngestAll(items []*Item) ([]*Result, []error) {
+	results := make([]*Result, len(items))
+	errs := make([]error, len(items))
+	mirrors := make(map[string]*mirrorIndex)
+	for i, item := range items {
+		rig, ok := in.RigFor(item.Repo)
+		if !ok {
+			errs[i] = fmt.Errorf("no rig tracks GitHub repo %s", item.Repo)
+			continue
+		}
+		m, ok := mirrors[rig]
+		if !ok {
+			var err error
+			if m, err = loadMirrorIndex(in.storeFor(rig)); err != nil {
+				errs[i] = err
+				continue
+			}
+			mirrors[rig] = m
+		}
+		results[i], errs[i] = m.ingest(rig, item)
+	}
+	return results, errs
+}
+
+// mirrorIndex is a rig's mirrored beads by external ref.
+type mirrorIndex struct {
+	store Store
+	byRef map[string]*beads.Issue
+}
+
+// loadMirrorIndex lists a rig's mirrored beads and indexes them by
+// external ref.
+func loadMirrorIndex(store Store) (*mirrorIndex, error) {
+	issues, err := store.List(beads.ListOptions{
+		Status:   "all",
+		Label:    LabelGitHub,
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing mirrored beads: %w", err)
+	}
+	m := &mirrorIndex{store: store, byRef: make(map[string]*beads.Issue, len(issues))}
+	for _, is := range issues {
+		if is.ExternalRef != "" {
+			m.byRef[is.ExternalRef] = is
+		}
+	}
+	return m, nil
+}
+
+// ingest mirrors one item into the rig, keeping the index current so later
+// items in the batch see what this one created or closed.
+func (m *mirrorIndex) ingest(rig string, item *Item) (*Result, error) {
+	ref := item.ExternalRef()
+	result := &Result{Rig: rig, Ref: ref}
+
+	if existing := m.byRef[ref]; existing != nil {
+		result.BeadID = existing.ID
+		if item.Closed && existing.Status != "closed" {
+			if err := m.store.CloseWithReason("closed on GitHub", existing.ID); err != nil {
+				return nil, fmt.Errorf("closing %s: %w", existing.ID, err)
+			}
+			existing.Status = "closed"
+			result.Action = ActionClosed
+			return result, nil
+		}
+		result.Action = ActionExists
+		return result, nil
+	}
+
+	if item.Closed {
+		result.Action = ActionSkipped
+		return result, nil
+	}
+
+	issue, err := m.store.Create(createOptions(item))
+	if err != nil {
+		return nil, fmt.Errorf("creating bead for %s: %w", ref, err)
+	}
+	m.byRef[ref] = issue
+	result.Action = ActionCreated
+	result.BeadID = issue.ID
+	return result, nil
+}
+
+// createOptions builds the bead for an item.
+func createOptions(item *Item) beads.CreateOptions {
+	opts := beads.CreateOptions{
+		Type:        "task",
+		Priority:    DefaultPriority,
+		Labels:      []string{LabelGitHub, "gh:" + item.Kind},
+		ExternalRef: item.ExternalRef(),
+		Actor:       "github",
+	}
+
+	var desc strings.Builder
+	switch item.Kind {
+	case KindIssue:
+		opts.Title = item.Title
+		for _, l := range item.Labels {
+			if p, ok := priorityFromLabel(l); ok {
+				opts.Priority = p
+				continue
+			}
+			if strings.EqualFold(l, "bug") {
+				opts.Type = "bug"
+			}
+			opts.Labels = append(opts.Labels, "gh-label:"+l)
+		}
+		desc.WriteString(strings.TrimSpace(item.Body))
+
+	case KindReviewComment:
+		location := item.Path
+		if item.Line > 0 {
+			location = fmt.Sprintf("%s:%d", item.Path, item.Line)
+		}
+		opts.Title = fmt.Sprintf("Address review comment on PR #%d: %s", item.Number, location)
+		desc.WriteString(strings.TrimSpace(item.Body))
+		fmt.Fprintf(&desc, "\n\nPR: #%d", item.Number)
+		if item.Title != "" {
+			desc.WriteString(" " + item.Title)
+		}
+
+	case KindReview:
+		opts.Title = fmt.Sprintf("Address review on PR #%d: %s", item.Number, item.Title)
+		desc.WriteString(strings.TrimSpace(item.Body))
+	}
+
+	fmt.Fprintf(&desc, "\n\nGitHub: %s", item.URL)
+	if item.Author != "" {
+		fmt.Fprintf(&desc, "\nAuthor: @%s", item.Author)
+	}
+	opts.Description = strings.TrimSpace(desc.String())
+	return opts
+}
+
+// priorityFromLabel maps common priority label conventions to bead priority:
+// "P0".."P4", and "priority:" or "priority/" prefixed levels ("priority:1",
+// "priority: high").
+func priorityFromLabel(l string) (int, bool) {
+	l = strings.ToLower(strings.TrimSpace(l))
+	level := l
+	for _, prefix := range []string{"priority:", "priority/"} {
+		if rest, ok := strings.CutPrefix(l, prefix); ok {
+			level = strings.TrimSpace(rest)
+		}
+	}
+	prefixed := level != l
+
+	levels := []struct {
+		short, digit, name string
+	}{
+		{"p0", "0", "critical"},
+		{"p1", "1", "high"},
+		{"p2", "2", "medium"},
+		{"p3", "3", "low"},
+		{"p4", "4", "backlog"},
+	}
+	for i, lv := range levels {
+		if level == lv.short || (prefixed && (level == lv.digit || level == lv.name)) {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,267 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// LabelIssue marks beads mirrored from GitHub issues. Only these are
+// mirrored back: review feedback beads have no issue of their own.
+const LabelIssue = "gh:" + KindIssue
+
+// Mirror stages are the bead transitions posted back to its GitHub issue.
+const (
+	StageClaimed   = "claimed"   // Hooked by an agent
+	StageSubmitted = "submitted" // In the merge queue, or an open PR
+	StageMerged    = "merged"    // Closed by a merge
+	StageClosed    = "closed"    // Closed without a merge
+)
+
+// mirrorField is the description key recording the last stage posted, so a
+// transition is posted once however often sync runs.
+const mirrorField = "gh_mirror"
+
+// MirrorStore is the subset of the beads API used for mirroring back.
+type MirrorStore interface {
+	List(opts beads.ListOptions) ([]*beads.Issue, error)
+	Update(id string, opts beads.UpdateOptions) error
+}
+
+// Transition is a stage a mirrored bead reached that its GitHub issue
+// hasn't heard about yet.
+type Transition struct {
+	Rig     string `json:"rig"`
+	BeadID  string `json:"bead_id"`
+	Ref     string `json:"external_ref"`
+	Stage   string `json:"stage"`
+	Comment string `json:"comment"`
+	Close   bool   `json:"close,omitempty"` // Close the issue with the comment
+
+	issue  *beads.Issue
+	repo   string
+	number int
+}
+
+// ParseIssueRef splits an issue external ref ("gh-owner/name#42") into its
+// repository and number. Review feedback refs don't parse.
+func ParseIssueRef(ref string) (string, int, bool) {
+	rest, ok := strings.CutPrefix(ref, "gh-")
+	if !ok {
+		return "", 0, false
+	}
+	repo, num, ok := strings.Cut(rest, "#")
+	if !ok || strings.Count(repo, "/") != 1 {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n <= 0 {
+		return "", 0, false
+	}
+	return repo, n, true
+}
+
+// MirrorStage returns the stage a work bead has reached, or "" while it
+// waits to be claimed. mr is the bead's merge request, or nil.
+func MirrorStage(issue, mr *beads.Issue) string {
+	pr := beads.ParsePRFields(issue)
+	if issue.Status == "closed" {
+		if pr != nil && pr.PRState == PRStateMerged {
+			return StageMerged
+		}
+		if mr != nil && mr.Status == "closed" {
+			if f := beads.ParseMRFields(mr); f != nil && f.CloseReason == "merged" {
+				return StageMerged
+			}
+		}
+		return StageClosed
+	}
+	if (pr != nil && pr.PRState == PRStateOpen) || (mr != nil && mr.Status != "closed") {
+		return StageSubmitted
+	}
+	if issue.Status == beads.StatusHooked || issue.Status == "in_progress" || issue.Assignee != "" {
+		return StageClaimed
+	}
+	return ""
+}
+
+// mirroredStage returns the last stage posted for a bead.
+func mirroredStage(issue *beads.Issue) string {
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && strings.TrimSpace(key) == mirrorField {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// setMirroredStage returns the bead's description recording stage, in the
+// way SetPRFields appends its fields after the prose.
+func setMirroredStage(issue *beads.Issue, stage string) string {
+	var lines []string
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, _, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && strings.TrimSpace(key) == mirrorField {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	desc := strings.TrimRight(strings.Join(lines, "\n"), "\n ")
+	if desc == "" {
+		return mirrorField + ": " + stage
+	}
+	return desc + "\n\n" + mirrorField + ": " + stage
+}
+
+// PendingTransitions returns the transitions of a rig's GitHub issue beads
+// that haven't been posted yet.
+func PendingTransitions(rig string, store MirrorStore) ([]*Transition, error) {
+	issues, err := store.List(beads.ListOptions{Status: "all", Label: LabelIssue, Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing mirrored beads: %w", err)
+	}
+	mrs, err := store.List(beads.ListOptions{Status: "all", Label: "gt:merge-request", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing merge requests: %w", err)
+	}
+	mrFor := make(map[string]*beads.Issue)
+	for _, mr := range mrs {
+		f := beads.ParseMRFields(mr)
+		if f == nil || f.SourceIssue == "" {
+			continue
+		}
+		if prev := mrFor[f.SourceIssue]; prev == nil || mrRank(mr) > mrRank(prev) {
+			mrFor[f.SourceIssue] = mr
+		}
+	}
+
+	var pending []*Transition
+	for _, issue := range issues {
+		repo, number, ok := ParseIssueRef(issue.ExternalRef)
+		if !ok {
+			continue
+		}
+		stage, posted := MirrorStage(issue, mrFor[issue.ID]), mirroredStage(issue)
+		if stage == "" || stage == posted || (isFinalStage(stage) && isFinalStage(posted)) {
+			continue
+		}
+		t := &Transition{
+			Rig:    rig,
+			BeadID: issue.ID,
+			Ref:    issue.ExternalRef,
+			Stage:  stage,
+			Close:  isFinalStage(stage),
+			issue:  issue,
+			repo:   repo,
+			number: number,
+		}
+		t.Comment = transitionComment(t, mrFor[issue.ID])
+		pending = append(pending, t)
+	}
+	return pending, nil
+}
+
+// mrRank orders a bead's merge requests: an open one is the current
+// submission, otherwise a merged one tells how the work landed.
+func mrRank(mr *beads.Issue) int {
+	switch {
+	case mr.Status != "closed":
+		return 2
+	case beads.ParseMRFields(mr).CloseReason == "merged":
+		return 1
+	}
+	return 0
+}
+
+func isFinalStage(stage string) bool {
+	return stage == StageMerged || stage == StageClosed
+}
+
+// transitionComment is what the issue's followers are told.
+func transitionComment(t *Transition, mr *beads.Issue) string {
+	pr := beads.ParsePRFields(t.issue)
+	var mrFields *beads.MRFields
+	if mr != nil {
+		mrFields = beads.ParseMRFields(mr)
+	}
+
+	var s string
+	switch t.Stage {
+	case StageClaimed:
+		who := "an agent"
+		if t.issue.Assignee != "" {
+			who = "`" + t.issue.Assignee + "`"
+		}
+		s = fmt.Sprintf("Work started: claimed by %s.", who)
+	case StageSubmitted:
+		if pr != nil && pr.PRState == PRStateOpen {
+			s = fmt.Sprintf("Submitted for review in %s.", pr.PRURL)
+		} else {
+			s = fmt.Sprintf("Submitted to the merge queue as %s.", mr.ID)
+		}
+	case StageMerged:
+		switch {
+		case pr != nil && pr.PRState == PRStateMerged:
+			s = fmt.Sprintf("Merged via %s.", pr.PRURL)
+		case mrFields != nil && mrFields.MergeCommit != "":
+			commit := mrFields.MergeCommit
+			if len(commit) > 12 {
+				commit = commit[:12]
+			}
+			s = fmt.Sprintf("Merged in %s.", commit)
+			if mrFields.Target != "" {
+				s = fmt.Sprintf("Merged to `%s` in %s.", mrFields.Target, commit)
+			}
+		default:
+			s = "Merged."
+		}
+	case StageClosed:
+		s = "Closed without a merge."
+	}
+	return s + fmt.Sprintf("\n\n<sub>Gas Town bead %s</sub>", t.BeadID)
+}
+
+// PostTransition tells the GitHub issue about a transition, closing it when
+// the bead closed, and records it on the bead. An issue that is already
+// closed on GitHub is left alone.
+func PostTransition(store MirrorStore, t *Transition) error {
+	num := strconv.Itoa(t.number)
+	if t.Close {
+		open, err := issueOpen(t.repo, num)
+		if err != nil {
+			return err
+		}
+		if open {
+			if _, err := ghRun("", "issue", "close", num, "-R", t.repo, "--comment", t.Comment); err != nil {
+				return err
+			}
+		}
+	} else if _, err := ghRun("", "issue", "comment", num, "-R", t.repo, "--body", t.Comment); err != nil {
+		return err
+	}
+
+	desc := setMirroredStage(t.issue, t.Stage)
+	if err := store.Update(t.BeadID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("updating %s: %w", t.BeadID, err)
+	}
+	return nil
+}
+
+// issueOpen reports whether a GitHub issue is still open.
+func issueOpen(repo, num string) (bool, error) {
+	out, err := ghRun("", "issue", "view", num, "-R", repo, "--json", "state")
+	if err != nil {
+		return false, err
+	}
+	var view struct {
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(out, &view); err != nil {
+		return false, fmt.Errorf("parsing gh issue view output: %w", err)
+	}
+	return view.State == "OPEN", nil
+}
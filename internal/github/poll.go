@@ -0,0 +1,156 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCommentWindow bounds how far back review comments are fetched when
+// no explicit --since is given. Review comments on long-merged PRs are noise.
+const DefaultCommentWindow = 24 * time.Hour
+
+// ghRun runs the gh CLI, which handles GitHub auth. A non-empty dir sets
+// the working directory so gh can infer the repository from git remotes.
+// It is a variable so tests can substitute canned responses.
+var ghRun = func(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("gh CLI not found: install from https://cli.github.com")
+		}
+		return nil, fmt.Errorf("gh %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// ghAPI runs a paginated GET through the gh CLI.
+var ghAPI = func(path string) ([]byte, error) {
+	return ghRun("", "api", "--paginate", path)
+}
+
+// Poll fetches mirrorable items for a repository from the GitHub API.
+// With a zero since, only open issues and review comments from the last
+// DefaultCommentWindow are returned. With since set, issues updated since
+// then in any state are included so closures propagate.
+func Poll(repo string, since time.Time) ([]*Item, error) {
+	issues, err := pollIssues(repo, since)
+	if err != nil {
+		return nil, err
+	}
+
+	commentsSince := since
+	if commentsSince.IsZero() {
+		commentsSince = time.Now().Add(-DefaultCommentWindow)
+	}
+	comments, err := pollReviewComments(repo, commentsSince)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, comments...), nil
+}
+
+// ListIssues fetches a repository's open issues carrying all the given
+// labels, for a one-time import of an existing backlog.
+func ListIssues(repo string, labels []string) ([]*Item, error) {
+	q := url.Values{"per_page": {"100"}, "state": {"open"}}
+	if len(labels) > 0 {
+		q.Set("labels", strings.Join(labels, ","))
+	}
+	return fetchIssues(repo, q)
+}
+
+func pollIssues(repo string, since time.Time) ([]*Item, error) {
+	q := url.Values{"per_page": {"100"}, "state": {"open"}}
+	if !since.IsZero() {
+		q.Set("state", "all")
+		q.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	return fetchIssues(repo, q)
+}
+
+func fetchIssues(repo string, q url.Values) ([]*Item, error) {
+	out, err := ghAPI(fmt.Sprintf("repos/%s/issues?%s", repo, q.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []issue
+	if err := decodePages(out, &pages); err != nil {
+		return nil, fmt.Errorf("parsing issues for %s: %w", repo, err)
+	}
+
+	var items []*Item
+	for i := range pages {
+		// The issues API includes pull requests; those are handled through
+		// their review feedback instead.
+		if pages[i].PullRequest != nil {
+			continue
+		}
+		items = append(items, issueItem(repo, &pages[i]))
+	}
+	return items, nil
+}
+
+func pollReviewComments(repo string, since time.Time) ([]*Item, error) {
+	q := url.Values{"per_page": {"100"}, "since": {since.UTC().Format(time.RFC3339)}}
+	out, err := ghAPI(fmt.Sprintf("repos/%s/pulls/comments?%s", repo, q.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []struct {
+		reviewComment
+		PullRequestURL string `json:"pull_request_url"`
+	}
+	if err := decodePages(out, &comments); err != nil {
+		return nil, fmt.Errorf("parsing review comments for %s: %w", repo, err)
+	}
+
+	var items []*Item
+	for _, c := range comments {
+		number, err := strconv.Atoi(c.PullRequestURL[strings.LastIndex(c.PullRequestURL, "/")+1:])
+		if err != nil {
+			continue
+		}
+		items = append(items, &Item{
+			Kind:   KindReviewComment,
+			Repo:   repo,
+			Number: number,
+			Body:   c.Body,
+			URL:    c.HTMLURL,
+			Author: c.User.Login,
+			Path:   c.Path,
+			Line:   c.Line,
+			ID:     c.ID,
+		})
+	}
+	return items, nil
+}
+
+// decodePages decodes gh --paginate output, which is one JSON array per
+// page written back to back, into a single slice.
+func decodePages[T any](data []byte, out *[]T) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var page []T
+		if err := dec.Decode(&page); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		*out = append(*out, page...)
+	}
+}
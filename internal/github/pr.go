@@ -0,0 +1,212 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// PR states as recorded in bead pr_state fields.
+const (
+	PRStateOpen   = "open"
+	PRStateMerged = "merged"
+	PRStateClosed = "closed"
+)
+
+// Aggregate CI check states as recorded in bead pr_checks fields.
+const (
+	ChecksPassing = "passing"
+	ChecksFailing = "failing"
+	ChecksPending = "pending"
+)
+
+// prViewFields are the gh pr view --json fields we read.
+const prViewFields = "number,url,state,reviewDecision,headRefName,statusCheckRollup"
+
+// PullRequest is the tracked status of a GitHub pull request.
+type PullRequest struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	Branch string `json:"branch"`
+	State  string `json:"state"`  // PRStateOpen, PRStateMerged, PRStateClosed
+	Review string `json:"review"` // approved, changes_requested, review_required, or ""
+	Checks string `json:"checks"` // ChecksPassing, ChecksFailing, ChecksPending, or ""
+}
+
+// PROptions describes a pull request to open.
+type PROptions struct {
+	Branch string // Head branch (must already be pushed)
+	Base   string // Base branch to merge into
+	Title  string
+	Body   string
+}
+
+// CreatePR opens a pull request from the repository checked out in dir.
+// If a pull request already exists for the branch, it is returned instead,
+// which keeps repeated submissions idempotent.
+func CreatePR(dir string, opts PROptions) (*PullRequest, error) {
+	if existing, err := ViewPR(dir, opts.Branch); err == nil && existing.State == PRStateOpen {
+		return existing, nil
+	}
+
+	if _, err := ghRun(dir, "pr", "create",
+		"--head", opts.Branch,
+		"--base", opts.Base,
+		"--title", opts.Title,
+		"--body", opts.Body,
+	); err != nil {
+		return nil, err
+	}
+	return ViewPR(dir, opts.Branch)
+}
+
+// ViewPR fetches pull request status. ref is a PR number, URL, or branch;
+// for branches, dir must be inside the repository checkout.
+func ViewPR(dir, ref string) (*PullRequest, error) {
+	out, err := ghRun(dir, "pr", "view", ref, "--json", prViewFields)
+	if err != nil {
+		return nil, err
+	}
+	return parsePRView(out)
+}
+
+// parsePRView normalizes gh pr view JSON output.
+func parsePRView(data []byte) (*PullRequest, error) {
+	var raw struct {
+		Number            int    `json:"number"`
+		URL               string `json:"url"`
+		State             string `json:"state"`
+		ReviewDecision    string `json:"reviewDecision"`
+		HeadRefName       string `json:"headRefName"`
+		StatusCheckRollup []struct {
+			Status     string `json:"status"`     // CheckRun: QUEUED, IN_PROGRESS, COMPLETED
+			Conclusion string `json:"conclusion"` // CheckRun: SUCCESS, FAILURE, ...
+			State      string `json:"state"`      // StatusContext: SUCCESS, PENDING, FAILURE, ERROR
+		} `json:"statusCheckRollup"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing gh pr view output: %w", err)
+	}
+
+	pr := &PullRequest{
+		Number: raw.Number,
+		URL:    raw.URL,
+		Branch: raw.HeadRefName,
+		State:  strings.ToLower(raw.State),
+		Review: strings.ToLower(raw.ReviewDecision),
+	}
+
+	for _, c := range raw.StatusCheckRollup {
+		switch {
+		case c.State == "FAILURE" || c.State == "ERROR" ||
+			c.Conclusion == "FAILURE" || c.Conclusion == "TIMED_OUT" ||
+			c.Conclusion == "CANCELLED" || c.Conclusion == "ACTION_REQUIRED":
+			pr.Checks = ChecksFailing
+		case pr.Checks == ChecksFailing:
+			// Failure wins over everything else
+		case c.State == "PENDING" || c.State == "EXPECTED" ||
+			(c.Status != "" && c.Status != "COMPLETED"):
+			pr.Checks = ChecksPending
+		case pr.Checks == "":
+			pr.Checks = ChecksPassing
+		}
+	}
+	return pr, nil
+}
+
+// LabelPR marks work beads whose submission is an open GitHub pull request.
+// Tracking (gt github sync / gt github prs) refreshes beads with this label.
+const LabelPR = "gh:pr"
+
+// PRStore is the subset of the beads API used for PR tracking.
+type PRStore interface {
+	List(opts beads.ListOptions) ([]*beads.Issue, error)
+	Update(id string, opts beads.UpdateOptions) error
+	CloseWithReason(reason string, ids ...string) error
+}
+
+// SubmittedAsPR reports whether a work bead was submitted as a pull request
+// that is still tracked. gt done leaves such a bead in progress instead of
+// closing it; RefreshPR closes it when the PR merges.
+func SubmittedAsPR(issue *beads.Issue) bool {
+	if beads.ParsePRFields(issue) == nil {
+		return false
+	}
+	for _, l := range issue.Labels {
+		if l == LabelPR {
+			return true
+		}
+	}
+	return false
+}
+
+// TrackedPRs returns the work beads still tracking a pull request: any not
+// yet closed, whether open, in progress (as gt done leaves them) or hooked.
+func TrackedPRs(store PRStore) ([]*beads.Issue, error) {
+	issues, err := store.List(beads.ListOptions{
+		Status:   "all",
+		Label:    LabelPR,
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var tracked []*beads.Issue
+	for _, issue := range issues {
+		if issue.Status != "closed" {
+			tracked = append(tracked, issue)
+		}
+	}
+	return tracked, nil
+}
+
+// RefreshPR fetches the current status of a bead's pull request and writes
+// it back into the bead's pr_* fields. A merged PR closes the bead; a PR
+// closed without merging drops the tracking label and reopens the bead for
+// rework. Returns the PR status and whether the bead changed.
+func RefreshPR(store PRStore, issue *beads.Issue) (*PullRequest, bool, error) {
+	fields := beads.ParsePRFields(issue)
+	if fields == nil {
+		return nil, false, fmt.Errorf("%s has no pr_url", issue.ID)
+	}
+
+	pr, err := ViewPR("", fields.PRURL)
+	if err != nil {
+		return nil, false, err
+	}
+
+	updated := &beads.PRFields{
+		PRURL:    pr.URL,
+		PRBranch: pr.Branch,
+		PRState:  pr.State,
+		PRReview: pr.Review,
+		PRChecks: pr.Checks,
+	}
+	if *updated == *fields {
+		return pr, false, nil
+	}
+
+	// Close a merged bead before dropping its label: if the close fails,
+	// the bead stays tracked and the next refresh tries again
+	if pr.State == PRStateMerged {
+		if err := store.CloseWithReason("merged via "+pr.URL, issue.ID); err != nil {
+			return nil, false, fmt.Errorf("closing %s: %w", issue.ID, err)
+		}
+	}
+
+	desc := beads.SetPRFields(issue, updated)
+	opts := beads.UpdateOptions{Description: &desc}
+	if pr.State != PRStateOpen {
+		opts.RemoveLabels = []string{LabelPR}
+	}
+	if pr.State == PRStateClosed {
+		reopened := "open"
+		opts.Status = &reopened
+	}
+	if err := store.Update(issue.ID, opts); err != nil {
+		return nil, false, fmt.Errorf("updating %s: %w", issue.ID, err)
+	}
+	return pr, true, nil
+}
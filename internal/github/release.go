@@ -0,0 +1,19 @@
+package github
+
+import "strings"
+
+// DraftRelease drafts a GitHub release for an already pushed tag in the
+// repository checked out in dir, and returns its URL. It stays a draft
+// until someone publishes it on GitHub.
+func DraftRelease(dir, tag, notes string) (string, error) {
+	out, err := ghRun(dir, "release", "create", tag,
+		"--draft",
+		"--verify-tag",
+		"--title", tag,
+		"--notes", notes,
+	)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,74 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// maxPayloadBytes caps webhook bodies. GitHub payloads are capped at 25MB,
+// but issue and review events are far smaller.
+const maxPayloadBytes = 5 << 20
+
+// WebhookHandler receives GitHub webhook deliveries and ingests them.
+type WebhookHandler struct {
+	ingester *Ingester
+	secret   string
+
+	// OnIngest, if set, is called after each successful ingestion.
+	OnIngest func(item *Item, result *Result)
+}
+
+// NewWebhookHandler creates a webhook handler. If secret is non-empty,
+// deliveries must carry a valid X-Hub-Signature-256 header.
+func NewWebhookHandler(ingester *Ingester, secret string) *WebhookHandler {
+	return &WebhookHandler{ingester: ingester, secret: secret}
+}
+
+// ServeHTTP handles a single webhook delivery.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadBytes))
+	if err != nil {
+		http.Error(w, "reading body", http.StatusBadRequest)
+		return
+	}
+
+	if h.secret != "" && !VerifySignature(h.secret, body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	item, err := ParseEvent(r.Header.Get("X-GitHub-Event"), body)
+	if errors.Is(err, ErrIgnored) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.ingester.RigFor(item.Repo); !ok {
+		// Not an error from GitHub's point of view; don't trigger redelivery.
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	result, err := h.ingester.Ingest(item)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if h.OnIngest != nil {
+		h.OnIngest(item, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
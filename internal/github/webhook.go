@@ -0,0 +1,228 @@
+// Package github mirrors GitHub issues and pull request review feedback into
+// beads, so incoming work lands in a rig's issue queue without manual triage.
+//
+// Items arrive either as webhook deliveries (gt github serve) or by polling
+// the GitHub API through the gh CLI (gt github sync). Each mirrored bead
+// carries an external ref pointing back at its GitHub source, which makes
+// ingestion idempotent across redeliveries and repeated syncs. Sync also
+// mirrors back: issue beads post their progress as issue comments, and
+// close their issue when they close.
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Item kinds mirrored into beads.
+const (
+	KindIssue         = "issue"
+	KindReviewComment = "review-comment"
+	KindReview        = "review"
+)
+
+// ErrIgnored is returned for deliveries that are valid but not mirrored
+// (ping events, label edits, approvals, etc.).
+var ErrIgnored = errors.New("event ignored")
+
+// Item is a GitHub object normalized for ingestion.
+type Item struct {
+	Kind   string   // KindIssue, KindReviewComment, KindReview
+	Repo   string   // "owner/name"
+	Number int      // Issue or PR number
+	Title  string   // Issue title, or PR title for review feedback
+	Body   string   // Issue body or comment text
+	URL    string   // html_url of the source object
+	Author string   // GitHub login of the author
+	Labels []string // GitHub label names (issues only)
+	Path   string   // File path (review comments only)
+	Line   int      // Line number (review comments only)
+	ID     int64    // Comment/review ID (review feedback only)
+	Closed bool     // True when the source issue was closed
+}
+
+// ExternalRef returns the bead external ref identifying this item.
+// Issues:          gh-owner/name#42
+// Review feedback: gh-owner/name#42/review-comment/123456
+func (i *Item) ExternalRef() string {
+	ref := fmt.Sprintf("gh-%s#%d", i.Repo, i.Number)
+	if i.Kind != KindIssue {
+		ref += fmt.Sprintf("/%s/%d", i.Kind, i.ID)
+	}
+	return ref
+}
+
+// VerifySignature checks a webhook body against its X-Hub-Signature-256
+// header ("sha256=<hex>") using the shared secret.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// Webhook payload shapes (only the fields we use).
+type (
+	user struct {
+		Login string `json:"login"`
+	}
+	label struct {
+		Name string `json:"name"`
+	}
+	repository struct {
+		FullName string `json:"full_name"`
+	}
+	issue struct {
+		Number      int       `json:"number"`
+		Title       string    `json:"title"`
+		Body        string    `json:"body"`
+		HTMLURL     string    `json:"html_url"`
+		State       string    `json:"state"`
+		User        user      `json:"user"`
+		Labels      []label   `json:"labels"`
+		PullRequest *struct{} `json:"pull_request,omitempty"`
+	}
+	pullRequest struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	}
+	reviewComment struct {
+		ID      int64  `json:"id"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+		Path    string `json:"path"`
+		Line    int    `json:"line"`
+		User    user   `json:"user"`
+	}
+	review struct {
+		ID      int64  `json:"id"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+		State   string `json:"state"`
+		User    user   `json:"user"`
+	}
+	webhookPayload struct {
+		Action      string         `json:"action"`
+		Repository  repository     `json:"repository"`
+		Issue       *issue         `json:"issue"`
+		PullRequest *pullRequest   `json:"pull_request"`
+		Comment     *reviewComment `json:"comment"`
+		Review      *review        `json:"review"`
+	}
+)
+
+// ParseEvent converts a webhook delivery into an Item.
+// eventType is the X-GitHub-Event header value. Returns ErrIgnored for
+// deliveries that don't produce work.
+func ParseEvent(eventType string, body []byte) (*Item, error) {
+	var p webhookPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("parsing %s payload: %w", eventType, err)
+	}
+
+	switch eventType {
+	case "issues":
+		if p.Issue == nil {
+			return nil, fmt.Errorf("issues event missing issue")
+		}
+		switch p.Action {
+		case "opened", "reopened", "closed":
+		default:
+			return nil, ErrIgnored
+		}
+		item := issueItem(p.Repository.FullName, p.Issue)
+		item.Closed = p.Action == "closed"
+		return item, nil
+
+	case "pull_request_review_comment":
+		if p.Action != "created" || p.Comment == nil || p.PullRequest == nil {
+			return nil, ErrIgnored
+		}
+		return &Item{
+			Kind:   KindReviewComment,
+			Repo:   p.Repository.FullName,
+			Number: p.PullRequest.Number,
+			Title:  p.PullRequest.Title,
+			Body:   p.Comment.Body,
+			URL:    p.Comment.HTMLURL,
+			Author: p.Comment.User.Login,
+			Path:   p.Comment.Path,
+			Line:   p.Comment.Line,
+			ID:     p.Comment.ID,
+		}, nil
+
+	case "pull_request_review":
+		// Only reviews requesting changes carry actionable work; inline
+		// comments arrive separately as pull_request_review_comment events.
+		if p.Action != "submitted" || p.Review == nil || p.PullRequest == nil {
+			return nil, ErrIgnored
+		}
+		if p.Review.State != "changes_requested" || strings.TrimSpace(p.Review.Body) == "" {
+			return nil, ErrIgnored
+		}
+		return &Item{
+			Kind:   KindReview,
+			Repo:   p.Repository.FullName,
+			Number: p.PullRequest.Number,
+			Title:  p.PullRequest.Title,
+			Body:   p.Review.Body,
+			URL:    p.Review.HTMLURL,
+			Author: p.Review.User.Login,
+			ID:     p.Review.ID,
+		}, nil
+
+	default:
+		return nil, ErrIgnored
+	}
+}
+
+// issueItem normalizes an issue payload. Pull requests also appear in the
+// issues API; callers filter those out.
+func issueItem(repo string, is *issue) *Item {
+	item := &Item{
+		Kind:   KindIssue,
+		Repo:   repo,
+		Number: is.Number,
+		Title:  is.Title,
+		Body:   is.Body,
+		URL:    is.HTMLURL,
+		Author: is.User.Login,
+		Closed: is.State == "closed",
+	}
+	for _, l := range is.Labels {
+		item.Labels = append(item.Labels, l.Name)
+	}
+	return item
+}
+
+// RepoFromURL extracts "owner/name" from a GitHub remote URL.
+// Handles https://github.com/owner/name(.git) and git@github.com:owner/name(.git).
+func RepoFromURL(gitURL string) (string, bool) {
+	var path string
+	switch {
+	case strings.HasPrefix(gitURL, "git@github.com:"):
+		path = strings.TrimPrefix(gitURL, "git@github.com:")
+	case strings.Contains(gitURL, "github.com/"):
+		path = gitURL[strings.Index(gitURL, "github.com/")+len("github.com/"):]
+	default:
+		return "", false
+	}
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return parts[0] + "/" + parts[1], true
+}
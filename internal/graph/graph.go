@@ -0,0 +1,277 @@
+// Package graph renders dependency graphs of beads and molecule steps.
+//
+// A Graph holds nodes in insertion order and edges from a prerequisite to
+// the node that needs it, so arrows point the way work flows. Cycles finds
+// the strongly connected groups that keep their members from ever being
+// ready; DOT and Mermaid draw them in red.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Node is a bead or step.
+type Node struct {
+	ID     string
+	Label  string
+	Status string // Bead status; "" for formula steps
+}
+
+// Edge says To can't start until From is done.
+type Edge struct {
+	From string
+	To   string
+}
+
+// Graph is a directed dependency graph.
+type Graph struct {
+	Title string
+	Nodes []*Node
+	Edges []Edge
+
+	index map[string]*Node
+	edges map[Edge]bool
+}
+
+// New returns an empty graph.
+func New(title string) *Graph {
+	return &Graph{Title: title, index: map[string]*Node{}, edges: map[Edge]bool{}}
+}
+
+// AddNode adds a node, or fills in the label and status of one an edge
+// already created.
+func (g *Graph) AddNode(id, label, status string) *Node {
+	if n, ok := g.index[id]; ok {
+		if label != "" {
+			n.Label = label
+		}
+		if status != "" {
+			n.Status = status
+		}
+		return n
+	}
+	n := &Node{ID: id, Label: label, Status: status}
+	g.index[id] = n
+	g.Nodes = append(g.Nodes, n)
+	return n
+}
+
+// AddEdge records that to needs from, adding either node if missing.
+func (g *Graph) AddEdge(from, to string) {
+	e := Edge{From: from, To: to}
+	if g.edges[e] {
+		return
+	}
+	g.AddNode(from, "", "")
+	g.AddNode(to, "", "")
+	g.edges[e] = true
+	g.Edges = append(g.Edges, e)
+}
+
+// Node returns the node with the given ID, or nil.
+func (g *Graph) Node(id string) *Node {
+	return g.index[id]
+}
+
+// Cycles returns each group of nodes that depend on each other, in node
+// order. A node that needs itself is a cycle of one.
+func (g *Graph) Cycles() [][]string {
+	out := map[string][]string{}
+	for _, e := range g.Edges {
+		out[e.From] = append(out[e.From], e.To)
+	}
+
+	// Tarjan's strongly connected components.
+	order := map[string]int{}
+	low := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		order[id] = len(order)
+		low[id] = order[id]
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, next := range out[id] {
+			if _, seen := order[next]; !seen {
+				visit(next)
+				low[id] = min(low[id], low[next])
+			} else if onStack[next] {
+				low[id] = min(low[id], order[next])
+			}
+		}
+
+		if low[id] != order[id] {
+			return
+		}
+		var group []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			group = append(group, top)
+			if top == id {
+				break
+			}
+		}
+		if len(group) > 1 || g.edges[Edge{From: id, To: id}] {
+			cycles = append(cycles, group)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if _, seen := order[n.ID]; !seen {
+			visit(n.ID)
+		}
+	}
+
+	position := map[string]int{}
+	for i, n := range g.Nodes {
+		position[n.ID] = i
+	}
+	for _, group := range cycles {
+		sort.Slice(group, func(a, b int) bool { return position[group[a]] < position[group[b]] })
+	}
+	sort.Slice(cycles, func(a, b int) bool { return position[cycles[a][0]] < position[cycles[b][0]] })
+	return cycles
+}
+
+// inCycle returns the set of nodes and edges that lie on a cycle.
+func (g *Graph) inCycle() (map[string]bool, map[Edge]bool) {
+	group := map[string]int{}
+	for i, c := range g.Cycles() {
+		for _, id := range c {
+			group[id] = i + 1
+		}
+	}
+	nodes := map[string]bool{}
+	for id := range group {
+		nodes[id] = true
+	}
+	edges := map[Edge]bool{}
+	for _, e := range g.Edges {
+		if group[e.From] != 0 && group[e.From] == group[e.To] {
+			edges[e] = true
+		}
+	}
+	return nodes, edges
+}
+
+// statusColors are the DOT fill colors for bead statuses.
+var statusColors = map[string]string{
+	"closed":      "#d4edda",
+	"in_progress": "#fff3cd",
+	"hooked":      "#fff3cd",
+	"blocked":     "#f8d7da",
+}
+
+// DOT renders the graph for Graphviz.
+func (g *Graph) DOT() string {
+	cycleNodes, cycleEdges := g.inCycle()
+
+	var sb strings.Builder
+	sb.WriteString("digraph G {\n")
+	if g.Title != "" {
+		fmt.Fprintf(&sb, "  label=%s;\n  labelloc=t;\n", dotQuote(g.Title))
+	}
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=white, fontname=Helvetica];\n")
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(nodeLabel(n, "\n"))}
+		if color, ok := statusColors[n.Status]; ok {
+			attrs = append(attrs, "fillcolor="+dotQuote(color))
+		}
+		if cycleNodes[n.ID] {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		fmt.Fprintf(&sb, "  %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		attrs := ""
+		if cycleEdges[e] {
+			attrs = " [color=red, penwidth=2]"
+		}
+		fmt.Fprintf(&sb, "  %s -> %s%s;\n", dotQuote(e.From), dotQuote(e.To), attrs)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	cycleNodes, cycleEdges := g.inCycle()
+
+	// Mermaid IDs can't hold the dots and dashes bead and step IDs do.
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	var sb strings.Builder
+	if g.Title != "" {
+		fmt.Fprintf(&sb, "---\ntitle: %s\n---\n", mermaidEscape(g.Title))
+	}
+	sb.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[n.ID], mermaidEscape(nodeLabel(n, "<br/>")))
+	}
+	var cycleLinks []string
+	for i, e := range g.Edges {
+		fmt.Fprintf(&sb, "  %s --> %s\n", ids[e.From], ids[e.To])
+		if cycleEdges[e] {
+			cycleLinks = append(cycleLinks, fmt.Sprint(i))
+		}
+	}
+
+	classes := map[string][]string{}
+	for _, n := range g.Nodes {
+		switch {
+		case cycleNodes[n.ID]:
+			classes["cycle"] = append(classes["cycle"], ids[n.ID])
+		case n.Status != "":
+			if _, ok := statusColors[n.Status]; ok {
+				classes[n.Status] = append(classes[n.Status], ids[n.ID])
+			}
+		}
+	}
+	for _, status := range []string{"closed", "in_progress", "hooked", "blocked"} {
+		if len(classes[status]) > 0 {
+			fmt.Fprintf(&sb, "  classDef %s fill:%s\n  class %s %s\n", status, statusColors[status], strings.Join(classes[status], ","), status)
+		}
+	}
+	if len(classes["cycle"]) > 0 {
+		fmt.Fprintf(&sb, "  classDef cycle stroke:red,stroke-width:2px\n  class %s cycle\n", strings.Join(classes["cycle"], ","))
+	}
+	if len(cycleLinks) > 0 {
+		fmt.Fprintf(&sb, "  linkStyle %s stroke:red,stroke-width:2px\n", strings.Join(cycleLinks, ","))
+	}
+	return sb.String()
+}
+
+// nodeLabel is the ID, then the title and status when known.
+func nodeLabel(n *Node, sep string) string {
+	label := n.ID
+	if n.Label != "" && n.Label != n.ID {
+		label += sep + n.Label
+	}
+	if n.Status != "" {
+		label += sep + "(" + n.Status + ")"
+	}
+	return label
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
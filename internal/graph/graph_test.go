@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCycles(t *testing.T) {
+	g := New("")
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "b")
+	g.AddEdge("c", "d")
+	g.AddEdge("e", "e")
+
+	want := [][]string{{"b", "c"}, {"e"}}
+	if got := g.Cycles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles() = %v, want %v", got, want)
+	}
+}
+
+func TestCycles_DAG(t *testing.T) {
+	g := New("")
+	g.AddEdge("a", "b")
+	g.AddEdge("a", "c")
+	g.AddEdge("b", "d")
+	g.AddEdge("c", "d")
+	if got := g.Cycles(); len(got) != 0 {
+		t.Errorf("Cycles() = %v, want none", got)
+	}
+}
+
+func TestAddNodeFillsIn(t *testing.T) {
+	g := New("")
+	g.AddEdge("gt-1", "gt-2")
+	g.AddNode("gt-1", "Fix login", "open")
+	g.AddEdge("gt-1", "gt-2")
+
+	if len(g.Nodes) != 2 || len(g.Edges) != 1 {
+		t.Fatalf("got %d nodes, %d edges; want 2, 1", len(g.Nodes), len(g.Edges))
+	}
+	if n := g.Node("gt-1"); n.Label != "Fix login" || n.Status != "open" {
+		t.Errorf("gt-1 = %+v", n)
+	}
+}
+
+func TestDOT(t *testing.T) {
+	g := New("mol-x")
+	g.AddNode("load", `Load "config"`, "")
+	g.AddNode("gt-1", "Done thing", "closed")
+	g.AddEdge("load", "gt-1")
+	g.AddEdge("gt-1", "load")
+	g.AddEdge("gt-1", "ship")
+
+	dot := g.DOT()
+	for _, want := range []string{
+		`label="mol-x"`,
+		`"load" [label="load\nLoad \"config\"", color=red, penwidth=2];`,
+		`fillcolor="#d4edda"`,
+		`"load" -> "gt-1" [color=red, penwidth=2];`,
+		`"gt-1" -> "ship";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	g := New("")
+	g.AddNode("gt-1", `Say "hi"`, "in_progress")
+	g.AddEdge("gt-1", "gt-2")
+	g.AddEdge("gt-2", "gt-3")
+	g.AddEdge("gt-3", "gt-2")
+
+	out := g.Mermaid()
+	for _, want := range []string{
+		"flowchart LR\n",
+		`n0["gt-1<br/>Say #quot;hi#quot;<br/>(in_progress)"]`,
+		"n0 --> n1\n",
+		"class n0 in_progress\n",
+		"class n1,n2 cycle\n",
+		"linkStyle 1,2 stroke:red",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Package harnessgit keeps a town's metadata under version control.
+//
+// A town root tracked with 'gt git-init' holds both harness metadata
+// (town and rig config, settings, role context, beads with their mail and
+// molecule runs) and things that must never be committed there: rig
+// clones, polecat worktrees and runtime state. Commit stages only the
+// metadata paths, so an automatic commit can't sweep rig code or a
+// half-written runtime file into the harness history.
+package harnessgit
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// townPaths are the harness metadata paths at the town root.
+var townPaths = []string{
+	"CLAUDE.md",
+	"AGENTS.md",
+	".gitignore",
+	".claude",  // Town slash commands
+	".beads",   // Town beads: mail, convoys, molecule runs
+	"mayor",    // town.json, rigs.json, mayor context (mayor/rig is ignored)
+	"deacon",   // Deacon context and settings
+	"settings", // Town settings
+	"plugins",
+	"prompts", // Role prompt packs
+}
+
+// rigPaths are the harness metadata paths inside each rig.
+var rigPaths = []string{
+	"config.json",
+	".gitignore",
+	".beads", // Rig beads, unless it is a symlink into the rig clone
+	"settings",
+	"witness/.claude",
+	"refinery/.claude",
+}
+
+// IsRepo reports whether the town root is a git repository.
+func IsRepo(townRoot string) bool {
+	_, err := os.Stat(filepath.Join(townRoot, ".git"))
+	return err == nil
+}
+
+// TrackedPaths returns the harness metadata paths that exist in the town,
+// relative to townRoot. Symlinks are skipped: they point into rig clones.
+func TrackedPaths(townRoot string, rigs []string) []string {
+	var candidates []string
+	candidates = append(candidates, townPaths...)
+	sorted := append([]string(nil), rigs...)
+	sort.Strings(sorted)
+	for _, r := range sorted {
+		for _, p := range rigPaths {
+			candidates = append(candidates, filepath.Join(r, p))
+		}
+	}
+
+	var paths []string
+	for _, p := range candidates {
+		info, err := os.Lstat(filepath.Join(townRoot, p))
+		if err != nil || info.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// Commit stages changes to the harness metadata paths and commits them,
+// leaving anything else in the index alone. It returns the files
+// committed; none means there was nothing to commit.
+func Commit(townRoot string, rigs []string, message string) ([]string, error) {
+	paths := TrackedPaths(townRoot, rigs)
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	if _, err := git(townRoot, append([]string{"add", "-A", "--"}, paths...)...); err != nil {
+		return nil, err
+	}
+	out, err := git(townRoot, append([]string{"diff", "--cached", "--name-only", "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	files := strings.Split(out, "\n")
+	// Committing by path commits only these paths, even if the user has
+	// other changes staged.
+	if _, err := git(townRoot, append([]string{"commit", "--no-verify", "-m", message, "--"}, paths...)...); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// HasUpstream reports whether the town's current branch tracks a remote branch.
+func HasUpstream(townRoot string) bool {
+	_, err := git(townRoot, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	return err == nil
+}
+
+// Gitignore block for rig entries, regenerated by EnsureGitignore.
+const (
+	gitignoreStart = "# --- gt harness: rig entries (managed) ---"
+	gitignoreEnd   = "# --- end gt harness ---"
+)
+
+// EnsureGitignore makes sure the town's .gitignore contains base (the HQ
+// patterns, identified by its first line) and an up-to-date block of
+// per-rig entries: the rig .beads symlinks, which point into ignored rig
+// clones. It reports whether the file changed.
+func EnsureGitignore(townRoot, base string, rigs []string) (bool, error) {
+	path := filepath.Join(townRoot, ".gitignore")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	content := string(data)
+
+	firstLine, _, _ := strings.Cut(base, "\n")
+	if !strings.Contains(content, firstLine) {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		content += base
+	}
+
+	var entries []string
+	sorted := append([]string(nil), rigs...)
+	sort.Strings(sorted)
+	for _, r := range sorted {
+		info, err := os.Lstat(filepath.Join(townRoot, r, ".beads"))
+		if err == nil && info.Mode()&os.ModeSymlink != 0 {
+			entries = append(entries, "/"+r+"/.beads")
+		}
+	}
+	content = replaceBlock(content, entries)
+
+	if content == string(data) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil { //nolint:gosec // G306: .gitignore is not secret
+		return false, err
+	}
+	return true, nil
+}
+
+// replaceBlock swaps the managed block in content for one listing entries,
+// dropping it when there are none.
+func replaceBlock(content string, entries []string) string {
+	if start := strings.Index(content, gitignoreStart); start != -1 {
+		if end := strings.Index(content[start:], gitignoreEnd); end != -1 {
+			end += start + len(gitignoreEnd)
+			if end < len(content) && content[end] == '\n' {
+				end++
+			}
+			content = content[:start] + content[end:]
+		}
+	}
+	if len(entries) == 0 {
+		return content
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + gitignoreStart + "\n" + strings.Join(entries, "\n") + "\n" + gitignoreEnd + "\n"
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,104 @@
+package harnessgit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func write(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func initRepo(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestCommitOnlyMetadata(t *testing.T) {
+	town := t.TempDir()
+	initRepo(t, town)
+	write(t, filepath.Join(town, "mayor", "rigs.json"), "{}")
+	write(t, filepath.Join(town, "settings", "config.json"), "{}")
+	write(t, filepath.Join(town, "greenplace", "config.json"), "{}")
+	write(t, filepath.Join(town, "greenplace", "polecats", "Toast", "main.go"), "package main")
+	write(t, filepath.Join(town, "scratch.txt"), "not metadata")
+
+	files, err := Commit(town, []string{"greenplace"}, "harness: test")
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	got := strings.Join(files, ",")
+	want := "greenplace/config.json,mayor/rigs.json,settings/config.json"
+	if got != want {
+		t.Errorf("committed %s, want %s", got, want)
+	}
+
+	out, _ := exec.Command("git", "-C", town, "status", "--porcelain").Output()
+	if !strings.Contains(string(out), "scratch.txt") || !strings.Contains(string(out), "polecats") {
+		t.Errorf("non-metadata files should be left uncommitted, status:\n%s", out)
+	}
+
+	files, err = Commit(town, []string{"greenplace"}, "harness: again")
+	if err != nil || len(files) != 0 {
+		t.Errorf("second Commit = %v, %v; want nothing to commit", files, err)
+	}
+}
+
+func TestEnsureGitignore(t *testing.T) {
+	town := t.TempDir()
+	base := "# Gas Town HQ .gitignore\n**/polecats/\n"
+	write(t, filepath.Join(town, ".gitignore"), "node_modules/")
+	if err := os.MkdirAll(filepath.Join(town, "greenplace", "mayor", "rig", ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("mayor/rig/.beads", filepath.Join(town, "greenplace", ".beads")); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := EnsureGitignore(town, base, []string{"greenplace", "other"})
+	if err != nil || !changed {
+		t.Fatalf("EnsureGitignore = %v, %v", changed, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(town, ".gitignore"))
+	content := string(data)
+	for _, want := range []string{"node_modules/\n", base, gitignoreStart + "\n/greenplace/.beads\n" + gitignoreEnd} {
+		if !strings.Contains(content, want) {
+			t.Errorf(".gitignore missing %q:\n%s", want, content)
+		}
+	}
+
+	if changed, err := EnsureGitignore(town, base, []string{"greenplace", "other"}); err != nil || changed {
+		t.Errorf("second EnsureGitignore = %v, %v; want unchanged", changed, err)
+	}
+
+	// Rig gone: the managed block goes with it
+	if err := os.Remove(filepath.Join(town, "greenplace", ".beads")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := EnsureGitignore(town, base, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(filepath.Join(town, ".gitignore"))
+	if strings.Contains(string(data), gitignoreStart) {
+		t.Errorf("managed block should be dropped with no entries:\n%s", data)
+	}
+}
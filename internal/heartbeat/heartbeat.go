@@ -0,0 +1,153 @@
+// Package heartbeat is the liveness protocol between a rig's agents and its
+// witness. An agent's wrapper beats while the agent is doing something (a
+// Claude hook after each tool call, or 'gt heartbeat --every' watching the
+// session's output), so a working agent whose beats stop is hung - without
+// the witness having to read its tmux pane.
+//
+// Each agent's last beat is a JSON file at
+// <town>/.runtime/heartbeats/<rig>/<agent>.json.
+package heartbeat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DirName is the heartbeat directory within the town's .runtime directory.
+const DirName = "heartbeats"
+
+// DefaultInterval is how often an active agent beats. Beats closer
+// together than this are skipped.
+const DefaultInterval = 30 * time.Second
+
+// DefaultHungAfter is how long a working agent can go without a beat
+// before the witness treats it as hung.
+const DefaultHungAfter = 10 * time.Minute
+
+// Beat sources.
+const (
+	SourceHook    = "hook"    // The agent runtime's tool-use hook
+	SourceWrapper = "wrapper" // 'gt heartbeat --every' watching session output
+)
+
+// Heartbeat is an agent's last beat.
+type Heartbeat struct {
+	Rig    string    `json:"rig"`
+	Agent  string    `json:"agent"`          // Polecat or crew name
+	Step   string    `json:"step,omitempty"` // Bead the agent is working
+	Source string    `json:"source"`
+	Beat   time.Time `json:"beat"` // When the agent was last seen active
+	Beats  int64     `json:"beats"`
+}
+
+// States the witness sees an agent in.
+type State string
+
+const (
+	StateAlive State = "alive" // Beat recently
+	StateHung  State = "hung"  // Beat before, but not for hungAfter
+	StateNone  State = "none"  // Never beat: its runtime has no wrapper
+)
+
+// Dir returns the heartbeat directory for a rig.
+func Dir(townRoot, rig string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, DirName, rig)
+}
+
+// Path returns an agent's heartbeat file.
+func Path(townRoot, rig, agent string) string {
+	return filepath.Join(Dir(townRoot, rig), agent+".json")
+}
+
+// Read returns an agent's last beat, or nil if it has never beat.
+func Read(townRoot, rig, agent string) (*Heartbeat, error) {
+	data, err := os.ReadFile(Path(townRoot, rig, agent)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var hb Heartbeat
+	if err := json.Unmarshal(data, &hb); err != nil {
+		return nil, fmt.Errorf("parsing heartbeat of %s/%s: %w", rig, agent, err)
+	}
+	return &hb, nil
+}
+
+// Write records a beat, counting it after prev (the agent's last beat, or
+// nil).
+func Write(townRoot string, hb *Heartbeat, prev *Heartbeat) error {
+	if hb.Rig == "" || hb.Agent == "" || strings.ContainsAny(hb.Rig+hb.Agent, `/\`) {
+		return fmt.Errorf("invalid heartbeat agent %q/%q", hb.Rig, hb.Agent)
+	}
+	if hb.Beat.IsZero() {
+		hb.Beat = time.Now()
+	}
+	hb.Beats = 1
+	if prev != nil {
+		hb.Beats = prev.Beats + 1
+	}
+	if err := os.MkdirAll(Dir(townRoot, hb.Rig), 0755); err != nil {
+		return fmt.Errorf("creating heartbeat directory: %w", err)
+	}
+	data, err := json.Marshal(hb)
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(Path(townRoot, hb.Rig, hb.Agent), data, 0644)
+}
+
+// List returns the last beats of a rig's agents, by agent name.
+func List(townRoot, rig string) ([]*Heartbeat, error) {
+	entries, err := os.ReadDir(Dir(townRoot, rig))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var beats []*Heartbeat
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if hb, err := Read(townRoot, rig, name); err == nil && hb != nil {
+			beats = append(beats, hb)
+		}
+	}
+	sort.Slice(beats, func(i, j int) bool { return beats[i].Agent < beats[j].Agent })
+	return beats, nil
+}
+
+// Due reports whether an agent whose last beat was prev should beat again
+// at now.
+func Due(prev *Heartbeat, now time.Time, interval time.Duration) bool {
+	return prev == nil || now.Sub(prev.Beat) >= interval
+}
+
+// Age is how long ago the beat was.
+func (hb *Heartbeat) Age(now time.Time) time.Duration {
+	return now.Sub(hb.Beat)
+}
+
+// Assess returns the state an agent whose last beat was hb is in at now.
+func Assess(hb *Heartbeat, now time.Time, hungAfter time.Duration) State {
+	switch {
+	case hb == nil:
+		return StateNone
+	case hb.Age(now) >= hungAfter:
+		return StateHung
+	default:
+		return StateAlive
+	}
+}
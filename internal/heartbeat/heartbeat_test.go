@@ -0,0 +1,64 @@
+package heartbeat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWriteReadList(t *testing.T) {
+	town := t.TempDir()
+
+	if hb, err := Read(town, "gastown", "Toast"); hb != nil || err != nil {
+		t.Fatalf("Read before any beat = %v, %v", hb, err)
+	}
+
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	if err := Write(town, &Heartbeat{Rig: "gastown", Agent: "Toast", Step: "gt-abc", Source: SourceHook, Beat: t0}, nil); err != nil {
+		t.Fatal(err)
+	}
+	prev, _ := Read(town, "gastown", "Toast")
+	if err := Write(town, &Heartbeat{Rig: "gastown", Agent: "Toast", Step: "gt-abc.2", Source: SourceHook, Beat: t0.Add(time.Minute)}, prev); err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(town, &Heartbeat{Rig: "gastown", Agent: "Nux", Source: SourceWrapper, Beat: t0}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	hb, err := Read(town, "gastown", "Toast")
+	if err != nil || hb.Step != "gt-abc.2" || hb.Beats != 2 || !hb.Beat.Equal(t0.Add(time.Minute)) {
+		t.Errorf("Read = %+v, %v", hb, err)
+	}
+	beats, err := List(town, "gastown")
+	if err != nil || len(beats) != 2 || beats[0].Agent != "Nux" || beats[1].Agent != "Toast" {
+		t.Errorf("List = %v, %v", beats, err)
+	}
+	if beats, _ := List(town, "other"); beats != nil {
+		t.Errorf("List of a rig with no beats = %v", beats)
+	}
+
+	if err := Write(town, &Heartbeat{Rig: "gastown", Agent: "../x"}, nil); err == nil {
+		t.Error("agent names with separators should be refused")
+	}
+}
+
+func TestDueAndAssess(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	recent := &Heartbeat{Beat: now.Add(-10 * time.Second)}
+	old := &Heartbeat{Beat: now.Add(-20 * time.Minute)}
+
+	if !Due(nil, now, DefaultInterval) || Due(recent, now, DefaultInterval) || !Due(old, now, DefaultInterval) {
+		t.Error("Due: want a beat with none yet or after the interval only")
+	}
+	for _, tc := range []struct {
+		hb   *Heartbeat
+		want State
+	}{
+		{nil, StateNone},
+		{recent, StateAlive},
+		{old, StateHung},
+	} {
+		if got := Assess(tc.hb, now, DefaultHungAfter); got != tc.want {
+			t.Errorf("Assess(%v) = %s, want %s", tc.hb, got, tc.want)
+		}
+	}
+}
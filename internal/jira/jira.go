@@ -0,0 +1,376 @@
+// Package jira imports Jira issues into beads, so a town can start from a
+// team's existing backlog.
+//
+// Issues come from a CSV export (Jira's "Export Excel CSV (all fields)")
+// or from the REST search API with a JQL query. Each bead carries an
+// external ref back to its Jira key, which makes re-importing idempotent.
+package jira
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// LabelJira marks every bead imported from Jira. Jira labels are copied
+// alongside it as jira-label:<name>.
+const LabelJira = "jira"
+
+// DefaultPriority is used for issues with no priority or an unknown one.
+const DefaultPriority = 2
+
+// Import outcomes.
+const (
+	ActionCreated = "created" // New bead created
+	ActionExists  = "exists"  // Already imported, nothing to do
+	ActionSkipped = "skipped" // Resolved in Jira, not imported
+)
+
+// Issue is a Jira issue normalized for import.
+type Issue struct {
+	Key            string   // "PROJ-123"
+	Summary        string   // Issue title
+	Description    string   // Issue body
+	Type           string   // Jira issue type ("Bug", "Story", ...)
+	Priority       string   // Jira priority name ("High", "Blocker", ...)
+	Status         string   // Jira status name
+	StatusCategory string   // "To Do", "In Progress" or "Done" (API only)
+	Labels         []string // Jira labels
+	Reporter       string   // Reporter display name
+	URL            string   // Browse URL, when the Jira base URL is known
+}
+
+// ExternalRef returns the bead external ref identifying this issue.
+func (i *Issue) ExternalRef() string {
+	return "jira-" + i.Key
+}
+
+// Resolved reports whether the issue is finished in Jira.
+func (i *Issue) Resolved() bool {
+	if i.StatusCategory != "" {
+		return strings.EqualFold(i.StatusCategory, "Done")
+	}
+	switch strings.ToLower(i.Status) {
+	case "done", "closed", "resolved", "won't do", "won't fix":
+		return true
+	}
+	return false
+}
+
+// priorities maps Jira's default priority schemes to bead priority.
+var priorities = map[string]int{
+	"highest":  0,
+	"blocker":  0,
+	"critical": 0,
+	"high":     1,
+	"major":    1,
+	"medium":   2,
+	"low":      3,
+	"minor":    3,
+	"lowest":   4,
+	"trivial":  4,
+}
+
+// types maps Jira issue types to bead types. Anything else is a task.
+var types = map[string]string{
+	"bug":         "bug",
+	"story":       "feature",
+	"feature":     "feature",
+	"new feature": "feature",
+	"improvement": "feature",
+	"chore":       "chore",
+}
+
+// CreateOptions builds the bead for an issue.
+func CreateOptions(issue *Issue) beads.CreateOptions {
+	opts := beads.CreateOptions{
+		Title:       issue.Summary,
+		Type:        "task",
+		Priority:    DefaultPriority,
+		Labels:      []string{LabelJira},
+		ExternalRef: issue.ExternalRef(),
+		Actor:       "jira",
+	}
+	if p, ok := priorities[strings.ToLower(strings.TrimSpace(issue.Priority))]; ok {
+		opts.Priority = p
+	}
+	if t, ok := types[strings.ToLower(strings.TrimSpace(issue.Type))]; ok {
+		opts.Type = t
+	}
+	for _, l := range issue.Labels {
+		opts.Labels = append(opts.Labels, "jira-label:"+l)
+	}
+
+	var desc strings.Builder
+	desc.WriteString(strings.TrimSpace(issue.Description))
+	if issue.URL != "" {
+		fmt.Fprintf(&desc, "\n\nJira: %s", issue.URL)
+	} else {
+		fmt.Fprintf(&desc, "\n\nJira: %s", issue.Key)
+	}
+	if issue.Reporter != "" {
+		fmt.Fprintf(&desc, "\nReporter: %s", issue.Reporter)
+	}
+	opts.Description = strings.TrimSpace(desc.String())
+	return opts
+}
+
+// Store is the subset of the beads API used for import.
+type Store interface {
+	List(opts beads.ListOptions) ([]*beads.Issue, error)
+	Create(opts beads.CreateOptions) (*beads.Issue, error)
+}
+
+// Result describes what importing one issue did.
+type Result struct {
+	Action string `json:"action"`
+	BeadID string `json:"bead_id,omitempty"`
+	Ref    string `json:"external_ref"`
+	Title  string `json:"title"`
+}
+
+// Import creates beads for the issues not already imported into store.
+// Resolved issues are skipped. It stops at the first failed create,
+// returning the results so far.
+func Import(store Store, issues []*Issue) ([]*Result, error) {
+	existing, err := store.List(beads.ListOptions{Status: "all", Label: LabelJira, Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing imported beads: %w", err)
+	}
+	imported := make(map[string]string, len(existing))
+	for _, is := range existing {
+		if is.ExternalRef != "" {
+			imported[is.ExternalRef] = is.ID
+		}
+	}
+
+	results := make([]*Result, 0, len(issues))
+	for _, issue := range issues {
+		r := &Result{Ref: issue.ExternalRef(), Title: issue.Summary}
+		switch id, ok := imported[r.Ref]; {
+		case ok:
+			r.Action, r.BeadID = ActionExists, id
+		case issue.Resolved():
+			r.Action = ActionSkipped
+		default:
+			created, err := store.Create(CreateOptions(issue))
+			if err != nil {
+				return results, fmt.Errorf("creating bead for %s: %w", issue.Key, err)
+			}
+			r.Action, r.BeadID = ActionCreated, created.ID
+			imported[r.Ref] = created.ID
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// ParseCSV reads a Jira CSV export. Jira repeats the Labels column once per
+// label; all of them are collected. baseURL, if set, builds browse links.
+func ParseCSV(r io.Reader, baseURL string) ([]*Issue, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	columns := map[string]int{}
+	var labelColumns []int
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if name == "labels" {
+			labelColumns = append(labelColumns, i)
+			continue
+		}
+		if _, dup := columns[name]; !dup {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["issue key"]; !ok {
+		return nil, fmt.Errorf("CSV has no \"Issue key\" column; is it a Jira export?")
+	}
+	if _, ok := columns["summary"]; !ok {
+		return nil, fmt.Errorf("CSV has no \"Summary\" column; is it a Jira export?")
+	}
+
+	var issues []*Issue
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV line %d: %w", line, err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		issue := &Issue{
+			Key:         field("issue key"),
+			Summary:     field("summary"),
+			Description: field("description"),
+			Type:        field("issue type"),
+			Priority:    field("priority"),
+			Status:      field("status"),
+			Reporter:    field("reporter"),
+		}
+		if issue.Key == "" {
+			continue
+		}
+		for _, i := range labelColumns {
+			if i < len(record) && strings.TrimSpace(record[i]) != "" {
+				issue.Labels = append(issue.Labels, strings.TrimSpace(record[i]))
+			}
+		}
+		issue.URL = browseURL(baseURL, issue.Key)
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// HasLabels reports whether the issue carries every one of labels.
+func (i *Issue) HasLabels(labels []string) bool {
+	for _, want := range labels {
+		found := false
+		for _, l := range i.Labels {
+			if strings.EqualFold(l, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func browseURL(baseURL, key string) string {
+	if baseURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/browse/" + key
+}
+
+// Client searches a Jira site through the REST API.
+type Client struct {
+	BaseURL string // e.g. https://acme.atlassian.net
+	Email   string // Account email, for Jira Cloud API tokens
+	Token   string // API token (Cloud) or personal access token (Server)
+	HTTP    *http.Client
+}
+
+// searchPageSize is how many issues each search request asks for.
+const searchPageSize = 100
+
+// searchResponse is the subset of /rest/api/2/search used here.
+type searchResponse struct {
+	StartAt    int `json:"startAt"`
+	MaxResults int `json:"maxResults"`
+	Total      int `json:"total"`
+	Issues     []struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary     string   `json:"summary"`
+			Description string   `json:"description"`
+			Labels      []string `json:"labels"`
+			IssueType   struct {
+				Name string `json:"name"`
+			} `json:"issuetype"`
+			Priority *struct {
+				Name string `json:"name"`
+			} `json:"priority"`
+			Status struct {
+				Name           string `json:"name"`
+				StatusCategory struct {
+					Name string `json:"name"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+			Reporter *struct {
+				DisplayName string `json:"displayName"`
+			} `json:"reporter"`
+		} `json:"fields"`
+	} `json:"issues"`
+}
+
+// Search returns every issue matching jql, following pagination.
+func (c *Client) Search(jql string) ([]*Issue, error) {
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	var issues []*Issue
+	for startAt := 0; ; {
+		q := url.Values{
+			"jql":        {jql},
+			"startAt":    {fmt.Sprint(startAt)},
+			"maxResults": {fmt.Sprint(searchPageSize)},
+			"fields":     {"summary,description,labels,issuetype,priority,status,reporter"},
+		}
+		req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(c.BaseURL, "/")+"/rest/api/2/search?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		switch {
+		case c.Email != "":
+			req.SetBasicAuth(c.Email, c.Token)
+		case c.Token != "":
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("searching Jira: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading Jira response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("searching Jira: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		}
+
+		var page searchResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("parsing Jira response: %w", err)
+		}
+		for _, is := range page.Issues {
+			issue := &Issue{
+				Key:            is.Key,
+				Summary:        is.Fields.Summary,
+				Description:    is.Fields.Description,
+				Type:           is.Fields.IssueType.Name,
+				Status:         is.Fields.Status.Name,
+				StatusCategory: is.Fields.Status.StatusCategory.Name,
+				Labels:         is.Fields.Labels,
+				URL:            browseURL(c.BaseURL, is.Key),
+			}
+			if is.Fields.Priority != nil {
+				issue.Priority = is.Fields.Priority.Name
+			}
+			if is.Fields.Reporter != nil {
+				issue.Reporter = is.Fields.Reporter.DisplayName
+			}
+			issues = append(issues, issue)
+		}
+
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			return issues, nil
+		}
+	}
+}
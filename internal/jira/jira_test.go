@@ -0,0 +1,168 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// fakeStore is an in-memory Store.
+type fakeStore struct {
+	issues []*beads.Issue
+}
+
+func (s *fakeStore) List(opts beads.ListOptions) ([]*beads.Issue, error) {
+	return s.issues, nil
+}
+
+func (s *fakeStore) Create(opts beads.CreateOptions) (*beads.Issue, error) {
+	is := &beads.Issue{
+		ID:          fmt.Sprintf("gt-%d", len(s.issues)+1),
+		Title:       opts.Title,
+		Priority:    opts.Priority,
+		Labels:      opts.Labels,
+		ExternalRef: opts.ExternalRef,
+	}
+	s.issues = append(s.issues, is)
+	return is, nil
+}
+
+const exportCSV = "\ufeffSummary,Issue key,Issue Type,Status,Priority,Reporter,Labels,Labels,Description\n" +
+	"Login fails,AUTH-1,Bug,To Do,Blocker,Ada,agent-ok,auth,\"Steps:\n1. log in\"\n" +
+	"Dark mode,UI-7,Story,Done,Low,Bob,,,\n" +
+	"Rotate keys,OPS-3,Task,In Progress,Whatever,,agent-ok,,\n"
+
+func TestParseCSV(t *testing.T) {
+	issues, err := ParseCSV(strings.NewReader(exportCSV), "https://acme.atlassian.net/")
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("got %d issues, want 3", len(issues))
+	}
+	first := issues[0]
+	if first.Key != "AUTH-1" || first.Summary != "Login fails" || first.Type != "Bug" || first.Priority != "Blocker" {
+		t.Errorf("issues[0] = %+v", first)
+	}
+	if len(first.Labels) != 2 || first.Labels[1] != "auth" {
+		t.Errorf("labels = %v, want [agent-ok auth]", first.Labels)
+	}
+	if first.Description != "Steps:\n1. log in" {
+		t.Errorf("description = %q", first.Description)
+	}
+	if first.URL != "https://acme.atlassian.net/browse/AUTH-1" {
+		t.Errorf("URL = %q", first.URL)
+	}
+	if !issues[1].Resolved() || issues[0].Resolved() {
+		t.Error("only UI-7 should be resolved")
+	}
+	if !issues[2].HasLabels([]string{"AGENT-OK"}) || issues[1].HasLabels([]string{"agent-ok"}) {
+		t.Error("HasLabels mismatch")
+	}
+}
+
+func TestParseCSV_NotJira(t *testing.T) {
+	if _, err := ParseCSV(strings.NewReader("name,age\nada,36\n"), ""); err == nil {
+		t.Error("expected error for a CSV without Jira columns")
+	}
+}
+
+func TestCreateOptions(t *testing.T) {
+	opts := CreateOptions(&Issue{
+		Key: "AUTH-1", Summary: "Login fails", Type: "Bug", Priority: "Blocker",
+		Labels: []string{"auth"}, Reporter: "Ada", URL: "https://acme.atlassian.net/browse/AUTH-1",
+		Description: "Broken",
+	})
+	if opts.Title != "Login fails" || opts.Type != "bug" || opts.Priority != 0 {
+		t.Errorf("opts = %+v", opts)
+	}
+	if opts.ExternalRef != "jira-AUTH-1" {
+		t.Errorf("ExternalRef = %q", opts.ExternalRef)
+	}
+	if strings.Join(opts.Labels, ",") != "jira,jira-label:auth" {
+		t.Errorf("Labels = %v", opts.Labels)
+	}
+	want := "Broken\n\nJira: https://acme.atlassian.net/browse/AUTH-1\nReporter: Ada"
+	if opts.Description != want {
+		t.Errorf("Description = %q, want %q", opts.Description, want)
+	}
+
+	defaults := CreateOptions(&Issue{Key: "OPS-3", Summary: "Rotate", Type: "Sub-task", Priority: "Whatever"})
+	if defaults.Type != "task" || defaults.Priority != DefaultPriority || defaults.Description != "Jira: OPS-3" {
+		t.Errorf("defaults = %+v", defaults)
+	}
+}
+
+func TestImport(t *testing.T) {
+	store := &fakeStore{issues: []*beads.Issue{{ID: "gt-old", ExternalRef: "jira-AUTH-1"}}}
+	issues := []*Issue{
+		{Key: "AUTH-1", Summary: "Login fails"},
+		{Key: "UI-7", Summary: "Dark mode", Status: "Done"},
+		{Key: "OPS-3", Summary: "Rotate keys"},
+		{Key: "OPS-3", Summary: "Rotate keys"},
+	}
+
+	results, err := Import(store, issues)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Action+":"+r.BeadID)
+	}
+	want := "exists:gt-old skipped: created:gt-2 exists:gt-2"
+	if strings.Join(got, " ") != want {
+		t.Errorf("results = %v, want %s", got, want)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@acme.com" || pass != "tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("jql") != "labels = agent-ok" {
+			t.Errorf("jql = %q", r.URL.Query().Get("jql"))
+		}
+		// Two pages of one issue each.
+		switch r.URL.Query().Get("startAt") {
+		case "0":
+			fmt.Fprint(w, `{"startAt":0,"total":2,"issues":[{"key":"AUTH-1","fields":{
+  "summary":"Login fails","labels":["agent-ok"],"issuetype":{"name":"Bug"},
+  "priority":{"name":"High"},"status":{"name":"Open","statusCategory":{"name":"To Do"}},
+  "reporter":{"displayName":"Ada"}}}]}`)
+		case "1":
+			fmt.Fprint(w, `{"startAt":1,"total":2,"issues":[{"key":"AUTH-2","fields":{
+  "summary":"Logout","issuetype":{"name":"Task"},
+  "status":{"name":"Shipped","statusCategory":{"name":"Done"}}}}]}`)
+		default:
+			t.Errorf("unexpected startAt %q", r.URL.Query().Get("startAt"))
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Email: "me@acme.com", Token: "tok"}
+	issues, err := c.Search("labels = agent-ok")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	if issues[0].Priority != "High" || issues[0].Reporter != "Ada" || issues[0].URL != server.URL+"/browse/AUTH-1" {
+		t.Errorf("issues[0] = %+v", issues[0])
+	}
+	if issues[0].Resolved() || !issues[1].Resolved() {
+		t.Error("only AUTH-2 should be resolved")
+	}
+
+	bad := &Client{BaseURL: server.URL}
+	if _, err := bad.Search("x"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("unauthenticated Search error = %v", err)
+	}
+}
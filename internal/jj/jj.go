@@ -0,0 +1,626 @@
+// Package jj provides a wrapper for Jujutsu (jj) operations via subprocess,
+// for rigs backed by jj rather than git.
+//
+// Its methods mirror package git's, so a jj rig's polecat workspaces and
+// refinery work the same way (see package vcs): bookmarks stand in for
+// branches, "origin/main" names the main@origin remote bookmark, and
+// worktrees are jj workspaces. A jj rig's repository is colocated with
+// git, so it still fetches from and pushes to an ordinary git remote.
+//
+// jj has no checked-out branch: the working copy is a commit (@) on top of
+// the work, and HEAD here means its parent (@-). A JJ remembers the
+// bookmark last checked out, and a merge or reset moves that bookmark, as
+// git moves the branch HEAD is on.
+package jj
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/output"
+)
+
+// WorkspacesFile records, inside a repository's .jj directory, which
+// directory each workspace gt created lives in; jj itself only knows
+// their names.
+const WorkspacesFile = "gt-workspaces.json"
+
+// bookmarkNames prints the local bookmark names in a bookmark list.
+const bookmarkNames = `if(remote, "", name ++ "\n")`
+
+// JJError contains raw output from a jj command, like git.GitError.
+type JJError struct {
+	Command string
+	Args    []string
+	Stdout  string
+	Stderr  string
+	Err     error
+}
+
+func (e *JJError) Error() string {
+	if e.Stderr != "" {
+		return fmt.Sprintf("jj %s: %s", e.Command, e.Stderr)
+	}
+	return fmt.Sprintf("jj %s: %v", e.Command, e.Err)
+}
+
+func (e *JJError) Unwrap() error {
+	return e.Err
+}
+
+// ErrConflict is returned by MergeNoFF when the merge has conflicts. jj
+// records them in the merge rather than stopping; GetConflictingFiles
+// lists them and AbortMerge drops the merge.
+var ErrConflict = errors.New("merge has conflicts")
+
+// JJ wraps jj operations for a workspace directory.
+type JJ struct {
+	dir    string
+	branch string // Bookmark last checked out
+}
+
+// NewJJ creates a JJ wrapper for the given workspace directory.
+func NewJJ(dir string) *JJ {
+	return &JJ{dir: dir}
+}
+
+// WorkDir returns the workspace directory.
+func (j *JJ) WorkDir() string {
+	return j.dir
+}
+
+// IsRepo returns true if dir is in a jj repository.
+func IsRepo(dir string) bool {
+	_, err := NewJJ(dir).run("root")
+	return err == nil
+}
+
+// Colocate makes the git clone at dir a colocated jj repository, sharing
+// its .git. Remote branches become remote bookmarks.
+func Colocate(dir string) error {
+	_, err := NewJJ(dir).run("git", "init", "--colocate")
+	return err
+}
+
+// readOnlyCommands never change a repository and run in dry-run mode.
+var readOnlyCommands = map[string]bool{
+	"root": true, "log": true, "diff": true, "show": true, "status": true,
+	"bookmark list": true, "workspace list": true, "op log": true, "file show": true,
+	"git fetch": true, // Only updates remote bookmarks
+}
+
+func mutates(args []string) bool {
+	var words []string
+	for _, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			words = append(words, a)
+		}
+	}
+	if len(words) == 0 {
+		return false
+	}
+	if readOnlyCommands[words[0]] {
+		return false
+	}
+	if len(words) > 1 && readOnlyCommands[words[0]+" "+words[1]] {
+		return false
+	}
+	if words[0] == "resolve" {
+		for _, a := range args {
+			if a == "--list" || a == "-l" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// run executes a jj command in the workspace and returns stdout.
+func (j *JJ) run(args ...string) (string, error) {
+	output.Tracef("jj %s", strings.Join(args, " "))
+	cmd := exec.Command("jj", append([]string{"--no-pager", "--color=never"}, args...)...) //nolint:gosec // G204: jj subcommands built internally
+	cmd.Dir = j.dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if mutates(args) && dryrun.Command(cmd) {
+		return "", nil
+	}
+	if err := cmd.Run(); err != nil {
+		name := ""
+		for _, a := range args {
+			if !strings.HasPrefix(a, "-") {
+				name = a
+				break
+			}
+		}
+		return "", &JJError{Command: name, Args: args, Stdout: strings.TrimSpace(stdout.String()),
+			Stderr: strings.TrimSpace(stderr.String()), Err: err}
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Rev translates a git-style ref into a jj revision: HEAD is the working
+// copy's parent, and <remote>/<branch> a remote bookmark, <branch>@<remote>.
+func Rev(ref string) string {
+	if ref == "HEAD" {
+		return "@-"
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(ref, "HEAD~")); err == nil && n >= 0 && strings.HasPrefix(ref, "HEAD~") {
+		return "@-" + strings.Repeat("-", n)
+	}
+	if remote, branch, ok := strings.Cut(ref, "/"); ok && (remote == "origin" || remote == "upstream") {
+		return quote(branch) + "@" + remote
+	}
+	return quote(ref)
+}
+
+// quote quotes a bookmark name for a revset when it has characters a bare
+// symbol can't (polecat/nux/gt-abc).
+func quote(name string) string {
+	if strings.ContainsAny(name, "/-.+") && !strings.ContainsAny(name, `"()|&~:`) {
+		return `"` + name + `"`
+	}
+	return name
+}
+
+// count returns how many non-empty commits revset matches.
+func (j *JJ) count(revset string) (int, error) {
+	out, err := j.run("log", "--no-graph", "-r", "("+revset+") ~ empty()", "-T", `commit_id ++ "\n"`)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Fetch fetches from the remote.
+func (j *JJ) Fetch(remote string) error {
+	_, err := j.run("git", "fetch", "--remote", remote)
+	return err
+}
+
+// Pull fetches the remote and starts a new change on its branch, which
+// becomes the checked-out bookmark.
+func (j *JJ) Pull(remote, branch string) error {
+	if err := j.Fetch(remote); err != nil {
+		return err
+	}
+	if _, err := j.run("bookmark", "set", branch, "-r", quote(branch)+"@"+remote); err != nil {
+		return err
+	}
+	return j.Checkout(branch)
+}
+
+// Push pushes a bookmark to the remote. jj checks that the remote hasn't
+// moved since the last fetch, so force needs no flag.
+func (j *JJ) Push(remote, branch string, force bool) error {
+	_, err := j.run("git", "push", "--remote", remote, "--bookmark", branch, "--allow-new")
+	return err
+}
+
+// DeleteRemoteBranch deletes a bookmark on the remote.
+func (j *JJ) DeleteRemoteBranch(remote, branch string) error {
+	if _, err := j.run("bookmark", "delete", branch); err != nil {
+		return err
+	}
+	_, err := j.run("git", "push", "--remote", remote, "--bookmark", branch)
+	return err
+}
+
+// Checkout starts a new change on ref. A bookmark becomes the checked-out
+// one.
+func (j *JJ) Checkout(ref string) error {
+	if _, err := j.run("new", Rev(ref)); err != nil {
+		return err
+	}
+	j.branch = ""
+	if exists, _ := j.BranchExists(ref); exists {
+		j.branch = ref
+	}
+	return nil
+}
+
+// CheckoutNewBranch starts a new change on startPoint with a bookmark of
+// the name, which becomes the checked-out one.
+func (j *JJ) CheckoutNewBranch(name, startPoint string) error {
+	if _, err := j.run("new", Rev(startPoint)); err != nil {
+		return err
+	}
+	if _, err := j.run("bookmark", "set", name, "-r", "@", "--allow-backwards"); err != nil {
+		return err
+	}
+	j.branch = name
+	return nil
+}
+
+// CurrentBranch returns the bookmark nearest the working copy: the one a
+// workspace was created with, however many commits it has since.
+func (j *JJ) CurrentBranch() (string, error) {
+	out, err := j.run("log", "--no-graph", "-r", "latest(::@ & bookmarks())", "-T", `local_bookmarks.map(|b| b.name()).join("\n")`)
+	if err != nil {
+		return "", err
+	}
+	if out == "" {
+		return "", fmt.Errorf("no bookmark in %s", j.dir)
+	}
+	return strings.Split(out, "\n")[0], nil
+}
+
+// Rev returns the commit ID of a ref.
+func (j *JJ) Rev(ref string) (string, error) {
+	return j.run("log", "--no-graph", "-r", Rev(ref), "-T", "commit_id")
+}
+
+// IsAncestor reports whether ancestor is an ancestor of descendant.
+func (j *JJ) IsAncestor(ancestor, descendant string) (bool, error) {
+	out, err := j.run("log", "--no-graph", "-r", Rev(ancestor)+" & ::"+Rev(descendant), "-T", "commit_id")
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// BranchExists reports whether a local bookmark exists.
+func (j *JJ) BranchExists(name string) (bool, error) {
+	out, err := j.run("bookmark", "list", "-T", bookmarkNames, "exact:"+name)
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// ListBranches returns the local bookmarks matching a glob pattern.
+func (j *JJ) ListBranches(pattern string) ([]string, error) {
+	args := []string{"bookmark", "list", "-T", bookmarkNames}
+	if pattern != "" {
+		args = append(args, "glob:"+pattern)
+	}
+	out, err := j.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			names = append(names, line)
+		}
+	}
+	return names, nil
+}
+
+// DeleteBranch forgets a local bookmark. Forgetting isn't pushed, so the
+// remote's copy is left alone, as with git.
+func (j *JJ) DeleteBranch(name string, force bool) error {
+	_, err := j.run("bookmark", "forget", name)
+	return err
+}
+
+// MergeNoFF merges branch into HEAD with a merge commit, which the
+// checked-out bookmark moves to. Conflicts return ErrConflict, leaving the
+// conflicted merge in the working copy for GetConflictingFiles.
+func (j *JJ) MergeNoFF(branch, message string) error {
+	if _, err := j.run("new", "@-", Rev(branch), "-m", message); err != nil {
+		return err
+	}
+	if conflicted, err := j.conflicted("@"); err != nil || conflicted {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("merging %s: %w", branch, ErrConflict)
+	}
+	if _, err := j.run("new"); err != nil {
+		return err
+	}
+	if j.branch != "" {
+		_, err := j.run("bookmark", "set", j.branch, "-r", "@-")
+		return err
+	}
+	return nil
+}
+
+// AbortMerge drops a conflicted merge left by MergeNoFF.
+func (j *JJ) AbortMerge() error {
+	_, err := j.run("undo")
+	return err
+}
+
+// ResetHard starts a new change on ref, moving the checked-out bookmark
+// back to it.
+func (j *JJ) ResetHard(ref string) error {
+	rev := Rev(ref)
+	if _, err := j.run("new", rev); err != nil {
+		return err
+	}
+	if j.branch != "" {
+		// @- is now ref; Rev(ref) could be relative to the old @
+		_, err := j.run("bookmark", "set", j.branch, "-r", "@-", "--allow-backwards")
+		return err
+	}
+	return nil
+}
+
+func (j *JJ) conflicted(rev string) (bool, error) {
+	out, err := j.run("log", "--no-graph", "-r", rev, "-T", `if(conflict, "conflict")`)
+	return out == "conflict", err
+}
+
+// GetConflictingFiles returns the files with conflicts in the working
+// copy.
+func (j *JJ) GetConflictingFiles() ([]string, error) {
+	if conflicted, err := j.conflicted("@"); err != nil || !conflicted {
+		return nil, err
+	}
+	out, err := j.run("resolve", "--list")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			files = append(files, fields[0])
+		}
+	}
+	return files, nil
+}
+
+// CheckConflictHunks reports the files that conflict when source is merged
+// into target, and their conflict markers (cut at maxBytes when
+// maxBytes > 0). The trial merge is undone; the repository is left as it
+// was.
+func (j *JJ) CheckConflictHunks(source, target string, maxBytes int) ([]string, string, error) {
+	op, err := j.run("op", "log", "--no-graph", "-n", "1", "-T", "id")
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _, _ = j.run("op", "restore", op) }()
+
+	if _, err := j.run("new", Rev(target), Rev(source), "-m", "gt conflict check"); err != nil {
+		return nil, "", err
+	}
+	files, err := j.GetConflictingFiles()
+	if err != nil || len(files) == 0 {
+		return nil, "", err
+	}
+	var hunks strings.Builder
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(j.dir, f)) //nolint:gosec // G304: path from jj's conflict list
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&hunks, "--- %s\n%s\n", f, data)
+	}
+	out := hunks.String()
+	if maxBytes > 0 && len(out) > maxBytes {
+		out = out[:maxBytes] + "\n... (truncated)"
+	}
+	return files, out, nil
+}
+
+// CheckUncommittedWork reports work not yet pushed: changes in the working
+// copy, and commits on the workspace's line that no remote bookmark has.
+// jj has no stash.
+func (j *JJ) CheckUncommittedWork() (*git.UncommittedWorkStatus, error) {
+	status := &git.UncommittedWorkStatus{}
+	files, err := j.run("diff", "-r", "@", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range strings.Split(files, "\n") {
+		if f = strings.TrimSpace(f); f != "" {
+			status.ModifiedFiles = append(status.ModifiedFiles, f)
+		}
+	}
+	status.HasUncommittedChanges = len(status.ModifiedFiles) > 0
+	if status.UnpushedCommits, err = j.count("::@- ~ ::remote_bookmarks()"); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// CountCommitsBehind returns how many commits on ref HEAD doesn't have.
+func (j *JJ) CountCommitsBehind(ref string) (int, error) {
+	return j.count("::" + Rev(ref) + " ~ ::@")
+}
+
+// CommitsAhead returns how many commits branch has that base doesn't.
+func (j *JJ) CommitsAhead(base, branch string) (int, error) {
+	return j.count("::" + Rev(branch) + " ~ ::" + Rev(base))
+}
+
+// BranchPushedToRemote reports whether the workspace's work is on the
+// remote's copy of branch, and how many commits aren't.
+func (j *JJ) BranchPushedToRemote(localBranch, remote string) (bool, int, error) {
+	remoteRev := quote(localBranch) + "@" + remote
+	if _, err := j.run("log", "--no-graph", "-r", remoteRev, "-T", "commit_id"); err != nil {
+		n, err := j.count("::@- ~ ::remote_bookmarks()")
+		if err != nil {
+			return false, 0, fmt.Errorf("counting commits: %w", err)
+		}
+		return n == 0, n, nil
+	}
+	n, err := j.count("::@- ~ ::" + remoteRev)
+	if err != nil {
+		return false, 0, fmt.Errorf("counting unpushed commits: %w", err)
+	}
+	return n == 0, n, nil
+}
+
+// workspace is a workspace gt created.
+type workspace struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+func (j *JJ) workspacesPath() string {
+	return filepath.Join(j.dir, ".jj", WorkspacesFile)
+}
+
+func (j *JJ) loadWorkspaces() ([]workspace, error) {
+	data, err := os.ReadFile(j.workspacesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ws []workspace
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", WorkspacesFile, err)
+	}
+	return ws, nil
+}
+
+func (j *JJ) saveWorkspaces(ws []workspace) error {
+	sort.Slice(ws, func(a, b int) bool { return ws[a].Path < ws[b].Path })
+	data, err := json.MarshalIndent(ws, "", "  ")
+	if err != nil {
+		return err
+	}
+	return dryrun.WriteFile(j.workspacesPath(), append(data, '\n'), 0644)
+}
+
+// workspaceName names the workspace at path after its last two
+// directories (polecats/nux/greenplace is nux-greenplace), unique among
+// ws.
+func workspaceName(path string, ws []workspace) string {
+	base := filepath.Base(filepath.Dir(path)) + "-" + filepath.Base(path)
+	base = strings.Map(func(r rune) rune {
+		if r == '/' || r == ' ' {
+			return '-'
+		}
+		return r
+	}, base)
+	name := base
+	for n := 2; ; n++ {
+		taken := name == "default"
+		for _, w := range ws {
+			taken = taken || w.Name == name
+		}
+		if !taken {
+			return name
+		}
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// addWorkspace creates a workspace at path on rev.
+func (j *JJ) addWorkspace(path, rev string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	ws, err := j.loadWorkspaces()
+	if err != nil {
+		return err
+	}
+	name := workspaceName(abs, ws)
+	if _, err := j.run("workspace", "add", "--name", name, "-r", rev, abs); err != nil {
+		return err
+	}
+	return j.saveWorkspaces(append(ws, workspace{Name: name, Path: abs}))
+}
+
+// WorktreeAddFromRef creates a workspace at path with a new bookmark,
+// branch, on startPoint.
+func (j *JJ) WorktreeAddFromRef(path, branch, startPoint string) error {
+	if err := j.addWorkspace(path, Rev(startPoint)); err != nil {
+		return err
+	}
+	_, err := NewJJ(path).run("bookmark", "create", branch, "-r", "@")
+	return err
+}
+
+// WorktreeAddDetached creates a workspace at path on ref, with no
+// bookmark.
+func (j *JJ) WorktreeAddDetached(path, ref string) error {
+	return j.addWorkspace(path, Rev(ref))
+}
+
+// WorktreeRemove forgets the workspace at path and deletes its directory.
+// Unless force, one with changes in its working copy is refused.
+func (j *JJ) WorktreeRemove(path string, force bool) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	ws, err := j.loadWorkspaces()
+	if err != nil {
+		return err
+	}
+	for i, w := range ws {
+		if w.Path != abs {
+			continue
+		}
+		if !force {
+			if status, err := NewJJ(abs).CheckUncommittedWork(); err == nil && status.HasUncommittedChanges {
+				return fmt.Errorf("workspace %s has changes (%s)", w.Name, status.String())
+			}
+		}
+		if _, err := j.run("workspace", "forget", w.Name); err != nil {
+			return err
+		}
+		if err := dryrun.RemoveAll(abs); err != nil {
+			return err
+		}
+		return j.saveWorkspaces(append(ws[:i], ws[i+1:]...))
+	}
+	return fmt.Errorf("%s is not a workspace of %s", path, j.dir)
+}
+
+// WorktreePrune forgets workspaces whose directories are gone.
+func (j *JJ) WorktreePrune() error {
+	ws, err := j.loadWorkspaces()
+	if err != nil {
+		return err
+	}
+	kept := ws[:0]
+	for _, w := range ws {
+		if _, err := os.Stat(w.Path); os.IsNotExist(err) {
+			_, _ = j.run("workspace", "forget", w.Name)
+			continue
+		}
+		kept = append(kept, w)
+	}
+	if len(kept) == len(ws) {
+		return nil
+	}
+	return j.saveWorkspaces(kept)
+}
+
+// WorktreeList returns the repository's own workspace and those gt
+// created, each with its bookmark.
+func (j *JJ) WorktreeList() ([]git.Worktree, error) {
+	ws, err := j.loadWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{j.dir}
+	for _, w := range ws {
+		paths = append(paths, w.Path)
+	}
+	var list []git.Worktree
+	for _, p := range paths {
+		wt := NewJJ(p)
+		branch, _ := wt.CurrentBranch()
+		commit, _ := wt.Rev("HEAD")
+		list = append(list, git.Worktree{Path: p, Branch: branch, Commit: commit})
+	}
+	return list, nil
+}
@@ -0,0 +1,90 @@
+package jj
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRev(t *testing.T) {
+	tests := []struct {
+		ref, want string
+	}{
+		{"HEAD", "@-"},
+		{"HEAD~2", "@---"},
+		{"main", "main"},
+		{"origin/main", "main@origin"},
+		{"origin/polecat/nux/gt-abc", `"polecat/nux/gt-abc"@origin`},
+		{"polecat/nux-lx3k", `"polecat/nux-lx3k"`},
+		{"0123abcd", "0123abcd"},
+	}
+	for _, tt := range tests {
+		if got := Rev(tt.ref); got != tt.want {
+			t.Errorf("Rev(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestMutates(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"log", "--no-graph", "-r", "@-"}, false},
+		{[]string{"bookmark", "list", "-T", bookmarkNames}, false},
+		{[]string{"git", "fetch", "--remote", "origin"}, false},
+		{[]string{"resolve", "--list"}, false},
+		{[]string{"bookmark", "set", "main", "-r", "@-"}, true},
+		{[]string{"git", "push", "--remote", "origin"}, true},
+		{[]string{"new", "@-", "feature"}, true},
+		{[]string{"resolve"}, true},
+	}
+	for _, tt := range tests {
+		if got := mutates(tt.args); got != tt.want {
+			t.Errorf("mutates(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestWorkspaceName(t *testing.T) {
+	path := filepath.Join("rig", "polecats", "nux", "greenplace")
+	if got := workspaceName(path, nil); got != "nux-greenplace" {
+		t.Errorf("workspaceName = %q, want nux-greenplace", got)
+	}
+	taken := []workspace{{Name: "nux-greenplace"}, {Name: "nux-greenplace-2"}}
+	if got := workspaceName(path, taken); got != "nux-greenplace-3" {
+		t.Errorf("workspaceName with taken names = %q, want nux-greenplace-3", got)
+	}
+}
+
+func TestWorkspacesFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".jj"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	j := NewJJ(dir)
+	ws, err := j.loadWorkspaces()
+	if err != nil || len(ws) != 0 {
+		t.Fatalf("loadWorkspaces on a new repo = %v, %v", ws, err)
+	}
+
+	gone := filepath.Join(dir, "gone")
+	kept := filepath.Join(dir, "kept")
+	if err := os.Mkdir(kept, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.saveWorkspaces([]workspace{{Name: "kept", Path: kept}, {Name: "gone", Path: gone}}); err != nil {
+		t.Fatal(err)
+	}
+	// Forgetting "gone" fails without jj installed; the record goes anyway
+	if err := j.WorktreePrune(); err != nil {
+		t.Fatalf("WorktreePrune: %v", err)
+	}
+	ws, err = j.loadWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws) != 1 || ws[0].Path != kept {
+		t.Errorf("after prune, workspaces = %+v, want only %s", ws, kept)
+	}
+}
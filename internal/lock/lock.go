@@ -7,6 +7,9 @@
 // - Session ID (tmux session name)
 //
 // Stale locks (where the PID is dead) are automatically cleaned up.
+//
+// The package also provides state locks (see AcquireState), which
+// serialize gt commands that mutate shared town or rig state.
 package lock
 
 import (
@@ -16,8 +19,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"syscall"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 // Common errors
@@ -199,15 +203,7 @@ func processExists(pid int) bool {
 		return false
 	}
 
-	// On Unix, sending signal 0 checks if process exists without affecting it
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-
-	// Try to send signal 0 - this will fail if process doesn't exist
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
+	return util.ProcessAlive(pid)
 }
 
 // FindAllLocks scans a directory tree for agent.lock files.
@@ -0,0 +1,336 @@
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// State locks serialize gt commands that mutate shared town state (rig
+// add/remove, refinery merges, town setup), so a human, the Deacon and
+// agents calling gt through MCP can't interleave their writes.
+//
+// A state lock is an advisory flock on <town>/.runtime/locks/<scope>.lock,
+// which the kernel drops when its holder exits however it exits. Next to
+// it, <scope>.json records who holds it; a record whose lock is free was
+// left by a holder that crashed, and is stale.
+//
+// Scopes are "town" for the whole harness and "rig-<name>" for one rig.
+// Take the town lock before a rig lock to avoid deadlocks.
+
+// ScopeTown is the scope of the town-wide state lock.
+const ScopeTown = "town"
+
+// LocksDir is the directory, under the town's runtime directory, that
+// holds state locks.
+const LocksDir = "locks"
+
+// DefaultStateWait is how long a command waits for a busy state lock.
+const DefaultStateWait = 2 * time.Minute
+
+// HeldEnv lists the state locks this process holds ("path=pid,..."), so gt
+// commands it runs don't wait on their parent's locks.
+const HeldEnv = "GT_LOCKS_HELD"
+
+// ErrBusy is returned when a state lock is still held when the wait ends.
+var ErrBusy = errors.New("state lock is held by another gt command")
+
+// Holder records who holds a state lock.
+type Holder struct {
+	Scope    string    `json:"scope"`
+	PID      int       `json:"pid"`
+	Command  string    `json:"command"`
+	Acquired time.Time `json:"acquired"`
+	Hostname string    `json:"hostname,omitempty"`
+}
+
+// StateLock is a held state lock.
+type StateLock struct {
+	path string
+}
+
+// RigScope returns the state lock scope of a rig.
+func RigScope(rig string) string {
+	return "rig-" + rig
+}
+
+// StatePath returns the lock file of a scope.
+func StatePath(townRoot, scope string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, LocksDir, scope+".lock")
+}
+
+func holderPath(lockPath string) string {
+	return strings.TrimSuffix(lockPath, ".lock") + ".json"
+}
+
+// held counts this process's acquisitions of each lock, so code holding a
+// lock can call code that takes it again.
+var (
+	heldMu sync.Mutex
+	held   = make(map[string]*heldLock)
+)
+
+type heldLock struct {
+	fl    *flock.Flock // nil when a parent process holds the lock
+	count int
+}
+
+// AcquireState takes the scope's state lock, waiting up to wait for its
+// holder to finish. It returns ErrBusy, wrapped with the holder, if the
+// lock is still held then. The lock is reentrant within a process and
+// across the gt commands a holder runs.
+func AcquireState(townRoot, scope string, wait time.Duration) (*StateLock, error) {
+	path := StatePath(townRoot, scope)
+	heldMu.Lock()
+	defer heldMu.Unlock()
+	if h := held[path]; h != nil {
+		h.count++
+		return &StateLock{path: path}, nil
+	}
+	if heldByParent(path) {
+		held[path] = &heldLock{count: 1}
+		return &StateLock{path: path}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating locks directory: %w", err)
+	}
+	fl := flock.New(path)
+	locked, err := fl.TryLock()
+	if err == nil && !locked && wait > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), wait)
+		locked, err = fl.TryLockContext(ctx, 200*time.Millisecond)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("locking %s: %w", scope, err)
+	}
+	if !locked {
+		if h, _ := readHolder(path); h != nil {
+			return nil, fmt.Errorf("%w: %s", ErrBusy, h)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrBusy, scope)
+	}
+
+	hostname, _ := os.Hostname()
+	h := &Holder{Scope: scope, PID: os.Getpid(), Command: commandLine(), Acquired: time.Now(), Hostname: hostname}
+	if err := util.AtomicWriteJSON(holderPath(path), h); err != nil {
+		_ = fl.Unlock()
+		return nil, fmt.Errorf("recording %s lock holder: %w", scope, err)
+	}
+	held[path] = &heldLock{fl: fl, count: 1}
+	setHeldEnv()
+	return &StateLock{path: path}, nil
+}
+
+// Release drops the lock once every acquisition in this process has been
+// released.
+func (l *StateLock) Release() error {
+	heldMu.Lock()
+	defer heldMu.Unlock()
+	h := held[l.path]
+	if h == nil {
+		return nil
+	}
+	if h.count--; h.count > 0 {
+		return nil
+	}
+	delete(held, l.path)
+	setHeldEnv()
+	if h.fl == nil {
+		return nil
+	}
+	_ = os.Remove(holderPath(l.path))
+	return h.fl.Unlock()
+}
+
+// StateStatus is a state lock as shown by gt locks.
+type StateStatus struct {
+	Scope  string  `json:"scope"`
+	Held   bool    `json:"held"`
+	Stale  bool    `json:"stale"` // A holder record with nobody holding the lock
+	Holder *Holder `json:"holder,omitempty"`
+}
+
+// ListState returns the town's state locks that are held or have a holder
+// record, by scope.
+func ListState(townRoot string) ([]StateStatus, error) {
+	dir := filepath.Join(townRoot, constants.DirRuntime, LocksDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []StateStatus
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".lock") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		s := StateStatus{Scope: strings.TrimSuffix(e.Name(), ".lock")}
+		s.Holder, _ = readHolder(path)
+		s.Held = isHeld(path)
+		s.Stale = !s.Held && s.Holder != nil
+		if s.Held || s.Holder != nil {
+			out = append(out, s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Scope < out[j].Scope })
+	return out, nil
+}
+
+// CleanStaleState removes holder records left by holders that died, and
+// returns how many it removed.
+func CleanStaleState(townRoot string) (int, error) {
+	locks, err := ListState(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	cleaned := 0
+	for _, s := range locks {
+		if !s.Stale {
+			continue
+		}
+		path := StatePath(townRoot, s.Scope)
+		fl := flock.New(path)
+		// Hold the lock while removing, so a new holder's record survives
+		if locked, err := fl.TryLock(); err != nil || !locked {
+			continue
+		}
+		if err := os.Remove(holderPath(path)); err == nil {
+			cleaned++
+		}
+		_ = fl.Unlock()
+	}
+	return cleaned, nil
+}
+
+// String describes the holder for messages.
+func (h *Holder) String() string {
+	desc := fmt.Sprintf("%s held by PID %d", h.Scope, h.PID)
+	if h.Command != "" {
+		desc += " (" + h.Command + ")"
+	}
+	return desc + " since " + h.Acquired.Format(time.RFC3339)
+}
+
+// ReadHolder returns the recorded holder of a scope's lock, or nil.
+func ReadHolder(townRoot, scope string) *Holder {
+	h, _ := readHolder(StatePath(townRoot, scope))
+	return h
+}
+
+func readHolder(lockPath string) (*Holder, error) {
+	data, err := os.ReadFile(holderPath(lockPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var h Holder
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLock, err)
+	}
+	return &h, nil
+}
+
+// isHeld reports whether this or another process holds the lock at path.
+func isHeld(path string) bool {
+	heldMu.Lock()
+	_, ours := held[path]
+	heldMu.Unlock()
+	if ours {
+		return true
+	}
+	fl := flock.New(path)
+	locked, err := fl.TryLock()
+	if err != nil {
+		return false
+	}
+	if locked {
+		_ = fl.Unlock()
+		return false
+	}
+	return true
+}
+
+// heldByParent reports whether HeldEnv says an ancestor gt holds the lock
+// at path, and it still does. The check guards against the variable
+// leaking into long-lived processes such as a tmux server.
+func heldByParent(path string) bool {
+	for _, entry := range strings.Split(os.Getenv(HeldEnv), ",") {
+		p, pidStr, ok := strings.Cut(entry, "=")
+		if !ok || p != path {
+			continue
+		}
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil || !processExists(pid) {
+			return false
+		}
+		h, err := readHolder(path)
+		if err != nil || h.PID != pid {
+			return false
+		}
+		fl := flock.New(path)
+		locked, err := fl.TryLock()
+		if err != nil {
+			return false
+		}
+		if locked {
+			_ = fl.Unlock()
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// setHeldEnv exports the locks this process holds to HeldEnv. Locks a
+// parent holds are passed on as they came. Callers hold heldMu.
+func setHeldEnv() {
+	var entries []string
+	for _, entry := range strings.Split(os.Getenv(HeldEnv), ",") {
+		if p, _, ok := strings.Cut(entry, "="); ok && held[p] != nil && held[p].fl == nil {
+			entries = append(entries, entry)
+		}
+	}
+	for p, h := range held {
+		if h.fl != nil {
+			entries = append(entries, p+"="+strconv.Itoa(os.Getpid()))
+		}
+	}
+	sort.Strings(entries)
+	if len(entries) == 0 {
+		_ = os.Unsetenv(HeldEnv)
+		return
+	}
+	_ = os.Setenv(HeldEnv, strings.Join(entries, ","))
+}
+
+// commandLine is this process's command line, with the binary's base name.
+func commandLine() string {
+	if len(os.Args) == 0 {
+		return ""
+	}
+	args := append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...)
+	line := strings.Join(args, " ")
+	if len(line) > 200 {
+		line = line[:200] + "..."
+	}
+	return line
+}
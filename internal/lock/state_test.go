@@ -0,0 +1,141 @@
+package lock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+func TestAcquireState(t *testing.T) {
+	town := t.TempDir()
+	l, err := AcquireState(town, ScopeTown, 0)
+	if err != nil {
+		t.Fatalf("AcquireState: %v", err)
+	}
+	// Reentrant within the process
+	again, err := AcquireState(town, ScopeTown, 0)
+	if err != nil {
+		t.Fatalf("AcquireState again: %v", err)
+	}
+	if err := again.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if h := ReadHolder(town, ScopeTown); h == nil || h.PID != os.Getpid() {
+		t.Fatalf("holder after inner release = %+v", h)
+	}
+	locks, err := ListState(town)
+	if err != nil || len(locks) != 1 || !locks[0].Held || locks[0].Stale {
+		t.Fatalf("ListState = %+v, %v", locks, err)
+	}
+	if err := l.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if h := ReadHolder(town, ScopeTown); h != nil {
+		t.Errorf("holder after release = %+v", h)
+	}
+	if locks, _ := ListState(town); len(locks) != 0 {
+		t.Errorf("ListState after release = %+v", locks)
+	}
+}
+
+func TestAcquireStateBusy(t *testing.T) {
+	town := t.TempDir()
+	scope := RigScope("gastown")
+	path := StatePath(town, scope)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Another holder: flock locks on separate opens conflict even in one process
+	other := flock.New(path)
+	if ok, err := other.TryLock(); err != nil || !ok {
+		t.Fatalf("TryLock = %v, %v", ok, err)
+	}
+	defer func() { _ = other.Unlock() }()
+
+	start := time.Now()
+	_, err := AcquireState(town, scope, 300*time.Millisecond)
+	if !errors.Is(err, ErrBusy) {
+		t.Fatalf("AcquireState on a held lock = %v, want ErrBusy", err)
+	}
+	if time.Since(start) < 300*time.Millisecond {
+		t.Errorf("AcquireState gave up after %s, before the wait", time.Since(start))
+	}
+}
+
+func TestStaleState(t *testing.T) {
+	town := t.TempDir()
+	path := StatePath(town, ScopeTown)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A holder that crashed leaves its record behind; the kernel freed the lock
+	record := `{"scope":"town","pid":999999999,"command":"gt rig add x","acquired":"2026-01-01T00:00:00Z"}`
+	if err := os.WriteFile(holderPath(path), []byte(record), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	locks, err := ListState(town)
+	if err != nil || len(locks) != 1 || !locks[0].Stale || locks[0].Held {
+		t.Fatalf("ListState = %+v, %v", locks, err)
+	}
+	if n, err := CleanStaleState(town); err != nil || n != 1 {
+		t.Errorf("CleanStaleState = %d, %v; want 1", n, err)
+	}
+	if locks, _ := ListState(town); len(locks) != 0 {
+		t.Errorf("ListState after clean = %+v", locks)
+	}
+
+	// A new holder replaces a stale record
+	l, err := AcquireState(town, ScopeTown, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Release() }()
+	if h := ReadHolder(town, ScopeTown); h == nil || h.PID != os.Getpid() {
+		t.Errorf("holder = %+v", h)
+	}
+}
+
+func TestHeldByParent(t *testing.T) {
+	town := t.TempDir()
+	path := StatePath(town, ScopeTown)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	parent := flock.New(path)
+	if ok, err := parent.TryLock(); err != nil || !ok {
+		t.Fatalf("TryLock = %v, %v", ok, err)
+	}
+	defer func() { _ = parent.Unlock() }()
+	record := `{"scope":"town","pid":` + strconv.Itoa(os.Getpid()) + `,"acquired":"2026-01-01T00:00:00Z"}`
+	if err := os.WriteFile(holderPath(path), []byte(record), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(HeldEnv, path+"=999999999")
+	if heldByParent(path) {
+		t.Error("heldByParent trusted a dead PID")
+	}
+	t.Setenv(HeldEnv, path+"="+strconv.Itoa(os.Getpid()))
+	if !heldByParent(path) {
+		t.Fatal("heldByParent = false for a live holder named in the environment")
+	}
+	l, err := AcquireState(town, ScopeTown, 0)
+	if err != nil {
+		t.Fatalf("AcquireState under a parent's lock: %v", err)
+	}
+	if err := l.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if h := ReadHolder(town, ScopeTown); h == nil {
+		t.Error("releasing a parent's lock removed its holder record")
+	}
+}
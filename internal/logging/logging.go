@@ -0,0 +1,182 @@
+// Package logging provides structured, per-component logging for gt.
+//
+// Components (refinery, witness, polecat, daemon, ...) obtain a logger with
+// For and attach context with the standard keys (rig, polecat, bead). Records
+// are written as JSON lines to ~/gt/.logs/gt.log, which is rotated by size.
+//
+// Levels are set per component with a spec such as "refinery=debug,warn":
+// bare levels set the default, component=level pairs override it. The spec
+// comes from the --log flag or the GT_LOG environment variable. Until Setup
+// is called (or outside a town) all records are discarded.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Standard attribute keys.
+const (
+	KeyComponent = "component"
+	KeyRig       = "rig"
+	KeyPolecat   = "polecat"
+	KeyBead      = "bead"
+)
+
+// EnvVar is the environment variable holding the level spec.
+const EnvVar = "GT_LOG"
+
+// DirName is the log directory under the town root.
+const DirName = ".logs"
+
+// FileName is the log file within DirName.
+const FileName = "gt.log"
+
+// DefaultLevel applies to components without an explicit level.
+const DefaultLevel = slog.LevelInfo
+
+// Dir returns the log directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, DirName)
+}
+
+// Path returns the log file path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(Dir(townRoot), FileName)
+}
+
+// Levels maps components to minimum levels.
+type Levels struct {
+	Default    slog.Level
+	Components map[string]slog.Level
+}
+
+// For returns the minimum level for a component.
+func (l Levels) For(component string) slog.Level {
+	if lvl, ok := l.Components[component]; ok {
+		return lvl
+	}
+	return l.Default
+}
+
+// ParseLevels parses a level spec like "refinery=debug,witness=warn,info".
+// An empty spec yields DefaultLevel for all components.
+func ParseLevels(spec string) (Levels, error) {
+	levels := Levels{Default: DefaultLevel, Components: make(map[string]slog.Level)}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		component, name, found := strings.Cut(part, "=")
+		if !found {
+			name, component = component, ""
+		}
+		var lvl slog.Level
+		if err := lvl.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+			return Levels{}, fmt.Errorf("invalid log level %q (use debug, info, warn, error)", name)
+		}
+		if component == "" {
+			levels.Default = lvl
+			continue
+		}
+		levels.Components[strings.TrimSpace(component)] = lvl
+	}
+	return levels, nil
+}
+
+// state is the process-wide logging configuration.
+var state struct {
+	mu      sync.RWMutex
+	levels  Levels
+	handler slog.Handler // nil until Setup
+	closer  io.Closer
+}
+
+// Setup directs logs for the given town to its rotated log file, with
+// per-component levels from spec. Calling Setup again replaces the
+// previous configuration.
+func Setup(townRoot, spec string) error {
+	levels, err := ParseLevels(spec)
+	if err != nil {
+		return err
+	}
+	w, err := OpenRotatingFile(Path(townRoot), DefaultMaxSize, DefaultMaxBackups)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	setHandler(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}), levels, w)
+	return nil
+}
+
+// SetWriter directs logs to w (used by tests and foreground tools).
+func SetWriter(w io.Writer, levels Levels) {
+	setHandler(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}), levels, nil)
+}
+
+func setHandler(h slog.Handler, levels Levels, closer io.Closer) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.closer != nil {
+		_ = state.closer.Close()
+	}
+	state.handler, state.levels, state.closer = h, levels, closer
+}
+
+// Close flushes and closes the log file, discarding further records.
+func Close() {
+	setHandler(nil, Levels{Default: DefaultLevel}, nil)
+}
+
+// For returns a logger for a component. Loggers may be created before
+// Setup; they follow the configuration current when each record is logged.
+func For(component string) *slog.Logger {
+	return slog.New(&componentHandler{component: component})
+}
+
+// componentHandler applies the component's level and forwards to the
+// process-wide handler. Attributes and groups added via With/WithGroup
+// are replayed onto the current handler.
+type componentHandler struct {
+	component string
+	ops       []func(slog.Handler) slog.Handler
+}
+
+func (h *componentHandler) Enabled(_ context.Context, level slog.Level) bool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return state.handler != nil && level >= state.levels.For(h.component)
+}
+
+func (h *componentHandler) Handle(ctx context.Context, r slog.Record) error {
+	state.mu.RLock()
+	inner := state.handler
+	state.mu.RUnlock()
+	if inner == nil {
+		return nil
+	}
+	inner = inner.WithAttrs([]slog.Attr{slog.String(KeyComponent, h.component)})
+	for _, op := range h.ops {
+		inner = op(inner)
+	}
+	return inner.Handle(ctx, r)
+}
+
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(inner slog.Handler) slog.Handler { return inner.WithAttrs(attrs) })
+}
+
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(inner slog.Handler) slog.Handler { return inner.WithGroup(name) })
+}
+
+func (h *componentHandler) with(op func(slog.Handler) slog.Handler) *componentHandler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &componentHandler{component: h.component, ops: append(ops, op)}
+}
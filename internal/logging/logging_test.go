@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels("refinery=debug, witness=warn,error")
+	if err != nil {
+		t.Fatalf("ParseLevels: %v", err)
+	}
+	if got := levels.For("refinery"); got != slog.LevelDebug {
+		t.Errorf("refinery = %v, want debug", got)
+	}
+	if got := levels.For("witness"); got != slog.LevelWarn {
+		t.Errorf("witness = %v, want warn", got)
+	}
+	if got := levels.For("daemon"); got != slog.LevelError {
+		t.Errorf("default = %v, want error", got)
+	}
+
+	empty, err := ParseLevels("")
+	if err != nil {
+		t.Fatalf("ParseLevels(\"\"): %v", err)
+	}
+	if got := empty.For("refinery"); got != DefaultLevel {
+		t.Errorf("empty spec = %v, want %v", got, DefaultLevel)
+	}
+
+	if _, err := ParseLevels("refinery=loud"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+}
+
+func TestComponentLevelsAndFields(t *testing.T) {
+	var buf bytes.Buffer
+	levels, _ := ParseLevels("refinery=debug")
+	SetWriter(&buf, levels)
+	defer Close()
+
+	refinery := For("refinery").With(KeyRig, "gastown")
+	witness := For("witness")
+
+	refinery.Debug("checking branch", KeyBead, "gt-abc")
+	witness.Debug("hidden")
+	witness.Info("patrol")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	for key, want := range map[string]string{KeyComponent: "refinery", KeyRig: "gastown", KeyBead: "gt-abc", "msg": "checking branch"} {
+		if rec[key] != want {
+			t.Errorf("%s = %v, want %s", key, rec[key], want)
+		}
+	}
+	if !strings.Contains(lines[1], `"component":"witness"`) {
+		t.Errorf("witness line missing component: %s", lines[1])
+	}
+}
+
+func TestDiscardBeforeSetup(t *testing.T) {
+	Close()
+	if For("refinery").Enabled(context.Background(), slog.LevelError) {
+		t.Error("expected logging disabled before Setup")
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "gt.log")
+	r, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile: %v", err)
+	}
+	defer r.Close()
+
+	for _, line := range []string{"first-1\n", "second\n", "third-3\n", "fourth\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	read := func(p string) string {
+		data, _ := os.ReadFile(p)
+		return string(data)
+	}
+	if got := read(path); got != "fourth\n" {
+		t.Errorf("current = %q", got)
+	}
+	if got := read(path + ".1"); got != "third-3\n" {
+		t.Errorf("backup 1 = %q", got)
+	}
+	if got := read(path + ".2"); got != "second\n" {
+		t.Errorf("backup 2 = %q", got)
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("expected oldest backup to be dropped")
+	}
+}
@@ -0,0 +1,228 @@
+// Package mcp serves town operations to agents over the Model Context
+// Protocol, so an agent can call them as tools instead of shelling out to
+// gt and parsing its output.
+//
+// The server speaks JSON-RPC 2.0 over stdio, one message per line, which
+// is how agent CLIs launch MCP servers (.mcp.json). It implements the
+// tools capability only. Which tools an agent sees depends on its role: a
+// server started for a polecat lists and accepts only the tools polecats
+// may call.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ProtocolVersion is the MCP revision the server implements. A client
+// asking for another revision is answered with this one, per the spec.
+const ProtocolVersion = "2024-11-05"
+
+// Tool is a town operation exposed to agents.
+type Tool struct {
+	Name        string
+	Description string
+
+	// InputSchema is the JSON Schema of the tool's arguments.
+	InputSchema map[string]any
+
+	// Roles may call the tool; empty means every role.
+	Roles []string
+
+	// Call runs the tool. The result is returned to the agent as JSON
+	// text, an error as a tool error the agent can read and act on.
+	Call func(ctx context.Context, args map[string]any) (any, error)
+}
+
+// Server serves a set of tools to one agent.
+type Server struct {
+	name    string
+	version string
+	role    string
+	tools   []Tool
+	allow   map[string][]string // Role → tool names, overriding Tool.Roles
+}
+
+// NewServer creates a server for an agent with the given role. allow, if
+// non-nil, replaces the tools' default role lists for the roles it names.
+func NewServer(name, version, role string, tools []Tool, allow map[string][]string) *Server {
+	return &Server{name: name, version: version, role: role, tools: tools, allow: allow}
+}
+
+// Allowed reports whether the server's role may call a tool.
+func (s *Server) Allowed(t Tool) bool {
+	if names, ok := s.allow[s.role]; ok {
+		return slices.Contains(names, t.Name) || slices.Contains(names, "*")
+	}
+	return len(t.Roles) == 0 || slices.Contains(t.Roles, s.role)
+}
+
+// Tools returns the tools the server's role may call.
+func (s *Server) Tools() []Tool {
+	var out []Tool
+	for _, t := range s.tools {
+		if s.Allowed(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// closed or ctx is done. Requests are handled one at a time.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	enc := json.NewEncoder(out)
+	write := func(r response) error { return enc.Encode(r) }
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := write(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParseError, err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(req.ID) == 0 {
+			continue // A notification: nothing to answer
+		}
+		result, rerr := s.handle(ctx, &req)
+		if err := write(response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rerr}); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *Server) handle(ctx context.Context, req *request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": s.name, "version": s.version},
+		}, nil
+
+	case "ping":
+		return map[string]any{}, nil
+
+	case "tools/list":
+		tools := []map[string]any{}
+		for _, t := range s.Tools() {
+			schema := t.InputSchema
+			if schema == nil {
+				schema = map[string]any{"type": "object"}
+			}
+			tools = append(tools, map[string]any{
+				"name":        t.Name,
+				"description": t.Description,
+				"inputSchema": schema,
+			})
+		}
+		return map[string]any{"tools": tools}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{codeInvalidParams, err.Error()}
+		}
+		for _, t := range s.tools {
+			if t.Name != params.Name {
+				continue
+			}
+			if !s.Allowed(t) {
+				return toolResult(nil, fmt.Errorf("the %s role may not call %s", s.role, t.Name)), nil
+			}
+			if params.Arguments == nil {
+				params.Arguments = map[string]any{}
+			}
+			return toolResult(t.Call(ctx, params.Arguments)), nil
+		}
+		return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown tool %q", params.Name)}
+
+	case "":
+		return nil, &rpcError{codeInvalidRequest, "missing method"}
+	}
+	return nil, &rpcError{codeMethodNotFound, fmt.Sprintf("method %q not supported", req.Method)}
+}
+
+// toolResult wraps a tool's outcome as MCP tool-call content.
+func toolResult(v any, err error) map[string]any {
+	if err != nil {
+		return map[string]any{
+			"content": []map[string]any{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}
+	}
+	text, ok := v.(string)
+	if !ok {
+		data, merr := json.MarshalIndent(v, "", "  ")
+		if merr != nil {
+			return toolResult(nil, merr)
+		}
+		text = string(data)
+	}
+	return map[string]any{"content": []map[string]any{{"type": "text", "text": text}}}
+}
+
+// StringArg returns a string argument, or "" when it's missing.
+func StringArg(args map[string]any, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
+
+// RequireString returns a string argument that must be present.
+func RequireString(args map[string]any, name string) (string, error) {
+	if s := StringArg(args, name); s != "" {
+		return s, nil
+	}
+	return "", fmt.Errorf("missing required argument %q", name)
+}
+
+// BoolArg returns a boolean argument, false when it's missing.
+func BoolArg(args map[string]any, name string) bool {
+	b, _ := args[name].(bool)
+	return b
+}
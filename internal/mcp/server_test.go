@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testTools() []Tool {
+	return []Tool{
+		{
+			Name:        "echo",
+			Description: "Echo the text argument",
+			Call: func(_ context.Context, args map[string]any) (any, error) {
+				return RequireString(args, "text")
+			},
+		},
+		{
+			Name:  "reassign",
+			Roles: []string{"mayor"},
+			Call: func(context.Context, map[string]any) (any, error) {
+				return map[string]string{"ok": "yes"}, nil
+			},
+		},
+		{
+			Name: "fail",
+			Call: func(context.Context, map[string]any) (any, error) {
+				return nil, errors.New("bead not found")
+			},
+		},
+	}
+}
+
+// roundTrip sends each request line to a server and returns the decoded
+// responses in order.
+func roundTrip(t *testing.T, s *Server, lines ...string) []map[string]any {
+	t.Helper()
+	var out strings.Builder
+	if err := s.Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	var resps []map[string]any
+	sc := bufio.NewScanner(strings.NewReader(out.String()))
+	for sc.Scan() {
+		var r map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("bad response %q: %v", sc.Text(), err)
+		}
+		resps = append(resps, r)
+	}
+	return resps
+}
+
+func toolNames(resp map[string]any) []string {
+	var names []string
+	for _, tool := range resp["result"].(map[string]any)["tools"].([]any) {
+		names = append(names, tool.(map[string]any)["name"].(string))
+	}
+	return names
+}
+
+func callText(resp map[string]any) (string, bool) {
+	result := resp["result"].(map[string]any)
+	text := result["content"].([]any)[0].(map[string]any)["text"].(string)
+	isErr, _ := result["isError"].(bool)
+	return text, isErr
+}
+
+func TestServeProtocol(t *testing.T) {
+	s := NewServer("gastown", "test", "polecat", testTools(), nil)
+	resps := roundTrip(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"reassign"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"fail"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"resources/list"}`,
+		`not json`,
+	)
+	if len(resps) != 7 {
+		t.Fatalf("got %d responses, want 7 (the notification gets none): %v", len(resps), resps)
+	}
+
+	initResult := resps[0]["result"].(map[string]any)
+	if initResult["protocolVersion"] != ProtocolVersion {
+		t.Errorf("protocolVersion = %v", initResult["protocolVersion"])
+	}
+
+	if got := strings.Join(toolNames(resps[1]), ","); got != "echo,fail" {
+		t.Errorf("polecat tools = %s, want echo,fail", got)
+	}
+	if text, isErr := callText(resps[2]); text != "hi" || isErr {
+		t.Errorf("echo = %q, %v", text, isErr)
+	}
+	if text, isErr := callText(resps[3]); !isErr || !strings.Contains(text, "may not call") {
+		t.Errorf("polecat calling a mayor tool = %q, %v", text, isErr)
+	}
+	if text, isErr := callText(resps[4]); !isErr || text != "bead not found" {
+		t.Errorf("failing tool = %q, %v", text, isErr)
+	}
+	if code := resps[5]["error"].(map[string]any)["code"].(float64); code != codeMethodNotFound {
+		t.Errorf("unsupported method code = %v", code)
+	}
+	if code := resps[6]["error"].(map[string]any)["code"].(float64); code != codeParseError {
+		t.Errorf("bad JSON code = %v", code)
+	}
+}
+
+func TestAllowOverride(t *testing.T) {
+	mayor := NewServer("gastown", "test", "mayor", testTools(), nil)
+	if n := len(mayor.Tools()); n != 3 {
+		t.Errorf("mayor sees %d tools, want 3", n)
+	}
+
+	// Town settings can narrow or widen a role's tools
+	polecat := NewServer("gastown", "test", "polecat", testTools(), map[string][]string{"polecat": {"reassign"}})
+	var names []string
+	for _, tool := range polecat.Tools() {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "reassign" {
+		t.Errorf("overridden polecat tools = %v", names)
+	}
+	all := NewServer("gastown", "test", "crew", testTools(), map[string][]string{"crew": {"*"}})
+	if n := len(all.Tools()); n != 3 {
+		t.Errorf(`"*" should allow every tool, got %d`, n)
+	}
+}
@@ -0,0 +1,239 @@
+// Package metrics implements a minimal Prometheus text-format registry.
+//
+// Long-running gt processes (the daemon, 'gt dashboard', 'gt github serve')
+// serve /metrics. Town state is reported by internal/townmetrics, derived
+// from durable sources at scrape time so every server reports the same
+// numbers. This package also holds in-process instrumentation (bd call
+// latency) that covers the serving process only.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metric types.
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// Sample is one value of a metric family.
+type Sample struct {
+	Suffix string            // appended to the family name (_bucket, _sum, _count)
+	Labels map[string]string // label name → value
+	Value  float64
+}
+
+// Family is a named metric with its samples.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Collector produces metric families when scraped.
+type Collector interface {
+	Collect() []Family
+}
+
+// CollectorFunc adapts a function to Collector.
+type CollectorFunc func() []Family
+
+// Collect implements Collector.
+func (f CollectorFunc) Collect() []Family { return f() }
+
+// Registry holds collectors.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default is the process-wide registry with built-in instrumentation.
+var Default = NewRegistry()
+
+// Register adds a collector.
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// Gather collects all families, sorted by name.
+func (r *Registry) Gather() []Family {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	var families []Family
+	for _, c := range collectors {
+		families = append(families, c.Collect()...)
+	}
+	sort.SliceStable(families, func(i, j int) bool { return families[i].Name < families[j].Name })
+	return families
+}
+
+// Handler serves the registry in the Prometheus text exposition format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = WriteText(w, r.Gather())
+	})
+}
+
+// WriteText writes families in the Prometheus text exposition format.
+func WriteText(w io.Writer, families []Family) error {
+	for _, f := range families {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.Name, escapeHelp(f.Help), f.Name, f.Type); err != nil {
+			return err
+		}
+		for _, s := range f.Samples {
+			if _, err := fmt.Fprintf(w, "%s%s%s %s\n", f.Name, s.Suffix, formatLabels(s.Labels), formatValue(s.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+// Histogram tracks the distribution of observed values.
+type Histogram struct {
+	name    string
+	help    string
+	buckets []float64 // upper bounds, ascending
+
+	mu     sync.Mutex
+	counts []uint64 // per bucket, non-cumulative
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	return &Histogram{name: name, help: help, buckets: b, counts: make([]uint64, len(b))}
+}
+
+// Observe records a value.
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, bound := range h.buckets {
+		if v <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// Collect implements Collector.
+func (h *Histogram) Collect() []Family {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f := Family{Name: h.name, Help: h.help, Type: TypeHistogram}
+	var cumulative uint64
+	for i, bound := range h.buckets {
+		cumulative += h.counts[i]
+		f.Samples = append(f.Samples, Sample{Suffix: "_bucket", Labels: map[string]string{"le": formatValue(bound)}, Value: float64(cumulative)})
+	}
+	f.Samples = append(f.Samples,
+		Sample{Suffix: "_bucket", Labels: map[string]string{"le": "+Inf"}, Value: float64(h.count)},
+		Sample{Suffix: "_sum", Value: h.sum},
+		Sample{Suffix: "_count", Value: float64(h.count)},
+	)
+	return []Family{f}
+}
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	name string
+	help string
+
+	mu    sync.Mutex
+	value float64
+}
+
+// NewCounter creates a counter.
+func NewCounter(name, help string) *Counter {
+	return &Counter{name: name, help: help}
+}
+
+// Inc adds one.
+func (c *Counter) Inc() {
+	c.mu.Lock()
+	c.value++
+	c.mu.Unlock()
+}
+
+// Collect implements Collector.
+func (c *Counter) Collect() []Family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return []Family{{Name: c.name, Help: c.help, Type: TypeCounter, Samples: []Sample{{Value: c.value}}}}
+}
+
+// Built-in instrumentation, updated by the packages that do the work.
+var (
+	// BDCallDuration observes the latency of each bd invocation.
+	BDCallDuration = NewHistogram("gt_bd_call_duration_seconds",
+		"Latency of bd (beads) CLI calls made by this process.",
+		[]float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10})
+
+	// BDCallErrors counts failed bd invocations.
+	BDCallErrors = NewCounter("gt_bd_call_errors_total",
+		"bd (beads) CLI calls made by this process that failed.")
+)
+
+func init() {
+	Default.Register(BDCallDuration)
+	Default.Register(BDCallErrors)
+}
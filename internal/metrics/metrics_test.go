@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	families := []Family{{
+		Name: "gt_merge_queue_depth",
+		Help: "Merge requests waiting.",
+		Type: TypeGauge,
+		Samples: []Sample{
+			{Labels: map[string]string{"rig": "gastown"}, Value: 3},
+			{Labels: map[string]string{"rig": `we"ird`}, Value: 0.5},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, families); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	want := `# HELP gt_merge_queue_depth Merge requests waiting.
+# TYPE gt_merge_queue_depth gauge
+gt_merge_queue_depth{rig="gastown"} 3
+gt_merge_queue_depth{rig="we\"ird"} 0.5
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram("gt_test_seconds", "Test.", []float64{1, 0.1})
+	h.Observe(0.05)
+	h.Observe(0.5)
+	h.Observe(30)
+
+	var buf bytes.Buffer
+	_ = WriteText(&buf, h.Collect())
+	out := buf.String()
+	for _, line := range []string{
+		`gt_test_seconds_bucket{le="0.1"} 1`,
+		`gt_test_seconds_bucket{le="1"} 2`,
+		`gt_test_seconds_bucket{le="+Inf"} 3`,
+		`gt_test_seconds_sum 30.55`,
+		`gt_test_seconds_count 3`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, out)
+		}
+	}
+}
+
+func TestRegistryHandler(t *testing.T) {
+	r := NewRegistry()
+	c := NewCounter("gt_things_total", "Things.")
+	c.Inc()
+	c.Inc()
+	r.Register(c)
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "gt_things_total 2\n") {
+		t.Errorf("body missing counter:\n%s", rec.Body.String())
+	}
+}
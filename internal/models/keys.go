@@ -0,0 +1,142 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/state"
+)
+
+// KeyEnvVars are the environment variables each provider's key is read
+// from by default. Ollama needs no key.
+var KeyEnvVars = map[string]string{
+	Anthropic: "ANTHROPIC_API_KEY",
+	OpenAI:    "OPENAI_API_KEY",
+}
+
+// keychainService is the service name keys are stored under in the macOS
+// keychain or the Secret Service (libsecret) keyring.
+const keychainService = "gastown"
+
+// Key is an API key and where it was found.
+type Key struct {
+	Value  string
+	Source string // "env:ANTHROPIC_API_KEY", "keychain", "secrets file"
+}
+
+// NeedsKey reports whether a provider needs an API key.
+func NeedsKey(provider string) bool {
+	return provider != Ollama
+}
+
+// LookupKey finds the API key for a tier's provider: the tier's key_env
+// or the provider's usual environment variable, then the system keychain,
+// then the secrets file. It returns nil with no error when there is no
+// key; a secrets file others can read is an error.
+func LookupKey(spec config.ModelSpec) (*Key, error) {
+	if !NeedsKey(spec.Provider) {
+		return nil, nil
+	}
+	env := spec.KeyEnv
+	if env == "" {
+		env = KeyEnvVars[spec.Provider]
+	}
+	if v := os.Getenv(env); v != "" {
+		return &Key{Value: v, Source: "env:" + env}, nil
+	}
+	if v, err := keychainLookup(spec.Provider); err == nil && v != "" {
+		return &Key{Value: v, Source: "keychain"}, nil
+	}
+	secrets, err := LoadSecrets()
+	if err != nil {
+		return nil, err
+	}
+	if v := secrets[spec.Provider]; v != "" {
+		return &Key{Value: v, Source: "secrets file"}, nil
+	}
+	return nil, nil
+}
+
+// keychainLookup reads a provider's key from the system keychain. It is a
+// variable so tests don't touch the real keychain.
+var keychainLookup = func(provider string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", provider, "-w")
+	case "linux":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return "", err
+		}
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "provider", provider)
+	default:
+		return "", fmt.Errorf("no keychain support on %s", runtime.GOOS)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SecretsPath returns the secrets file, a JSON object of provider to key.
+func SecretsPath() string {
+	return filepath.Join(state.ConfigDir(), "secrets.json")
+}
+
+// LoadSecrets reads the secrets file. A missing file has no keys. The file
+// must not be readable by group or others.
+func LoadSecrets() (map[string]string, error) {
+	path := SecretsPath()
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("%s has permissions %04o; keys must be private (chmod 600 %s)", path, info.Mode().Perm(), path)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the fixed secrets file
+	if err != nil {
+		return nil, err
+	}
+	secrets := map[string]string{}
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return secrets, nil
+}
+
+// SetSecret stores a provider's key in the secrets file (mode 0600). An
+// empty key removes it.
+func SetSecret(provider, key string) error {
+	secrets, err := LoadSecrets()
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		delete(secrets, provider)
+	} else {
+		secrets[provider] = key
+	}
+	if err := os.MkdirAll(state.ConfigDir(), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(secrets, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := SecretsPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, SecretsPath())
+}
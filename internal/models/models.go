@@ -0,0 +1,123 @@
+// Package models resolves model tiers to providers and models, finds the
+// API keys to reach them, and checks that they answer.
+//
+// A tier is what 'gt sling --tier' names: haiku, sonnet and opus run
+// Anthropic's models unless the town's settings say otherwise, and a town
+// can add tiers of its own on OpenAI or a local Ollama server:
+//
+//	"models": {"tiers": {
+//	  "opus":  {"provider": "anthropic", "model": "claude-opus-4-1"},
+//	  "local": {"provider": "ollama", "model": "qwen2.5-coder"}
+//	}}
+package models
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Providers.
+const (
+	Anthropic = "anthropic"
+	OpenAI    = "openai"
+	Ollama    = "ollama"
+)
+
+// Providers lists the supported providers.
+var Providers = []string{Anthropic, OpenAI, Ollama}
+
+// DefaultTiers are the built-in tiers, in order of capability. Their
+// models are Claude Code's model aliases.
+var DefaultTiers = []string{"haiku", "sonnet", "opus"}
+
+// Tier is a named tier with its effective model.
+type Tier struct {
+	Name string
+	config.ModelSpec
+	Default bool // Not overridden in town settings
+}
+
+// Resolve returns the town's tiers: the defaults, with any overrides
+// applied, followed by the town's own tiers sorted by name. Unknown
+// providers and tiers without a model are errors.
+func Resolve(cfg *config.ModelsConfig) ([]Tier, error) {
+	var configured map[string]*config.ModelSpec
+	if cfg != nil {
+		configured = cfg.Tiers
+	}
+
+	var tiers []Tier
+	for _, name := range DefaultTiers {
+		if spec := configured[name]; spec != nil {
+			tiers = append(tiers, Tier{Name: name, ModelSpec: *spec})
+			continue
+		}
+		tiers = append(tiers, Tier{Name: name, ModelSpec: config.ModelSpec{Provider: Anthropic, Model: name}, Default: true})
+	}
+
+	var extra []string
+	for name, spec := range configured {
+		if spec != nil && !slices.Contains(DefaultTiers, name) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		tiers = append(tiers, Tier{Name: name, ModelSpec: *configured[name]})
+	}
+
+	for i := range tiers {
+		t := &tiers[i]
+		t.Provider = strings.ToLower(t.Provider)
+		if !slices.Contains(Providers, t.Provider) {
+			return nil, fmt.Errorf("tier %s: unknown provider %q (use %s)", t.Name, t.Provider, strings.Join(Providers, ", "))
+		}
+		if t.Model == "" {
+			return nil, fmt.Errorf("tier %s: no model", t.Name)
+		}
+	}
+	return tiers, nil
+}
+
+// Find returns the named tier, or an error listing the known tiers.
+func Find(cfg *config.ModelsConfig, name string) (*Tier, error) {
+	tiers, err := Resolve(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for i := range tiers {
+		if strings.EqualFold(tiers[i].Name, name) {
+			return &tiers[i], nil
+		}
+		names = append(names, tiers[i].Name)
+	}
+	return nil, fmt.Errorf("unknown tier %q (use %s)", name, strings.Join(names, ", "))
+}
+
+// AgentCommands are the agent CLIs that can run each provider's models
+// with --model.
+var AgentCommands = map[string][]string{
+	Anthropic: {"claude"},
+	OpenAI:    {"codex"},
+	Ollama:    {"opencode"},
+}
+
+// ModelArg returns the --model value an agent CLI takes for the tier.
+// ok is false when the agent can't run the tier's provider.
+func (t *Tier) ModelArg(agentCommand string) (arg string, ok bool) {
+	for _, c := range AgentCommands[t.Provider] {
+		if c == agentCommand {
+			if t.Provider == Ollama {
+				// opencode names models provider/model
+				return Ollama + "/" + t.Model, true
+			}
+			return t.Model, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestResolve(t *testing.T) {
+	tiers, err := Resolve(&config.ModelsConfig{Tiers: map[string]*config.ModelSpec{
+		"opus":  {Provider: "Anthropic", Model: "claude-opus-4-1"},
+		"local": {Provider: "ollama", Model: "qwen2.5-coder"},
+		"fast":  {Provider: "openai", Model: "gpt-5-mini"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, tier := range tiers {
+		got = append(got, tier.Name+"="+tier.Provider+"/"+tier.Model)
+	}
+	want := "haiku=anthropic/haiku sonnet=anthropic/sonnet opus=anthropic/claude-opus-4-1 fast=openai/gpt-5-mini local=ollama/qwen2.5-coder"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Resolve = %v, want %s", got, want)
+	}
+
+	if _, err := Resolve(&config.ModelsConfig{Tiers: map[string]*config.ModelSpec{"x": {Provider: "acme", Model: "m"}}}); err == nil {
+		t.Error("unknown provider should be an error")
+	}
+	if _, err := Find(nil, "grande"); err == nil || !strings.Contains(err.Error(), "haiku, sonnet, opus") {
+		t.Errorf("Find unknown tier = %v", err)
+	}
+
+	local := Tier{Name: "local", ModelSpec: config.ModelSpec{Provider: Ollama, Model: "llama3.1"}}
+	if arg, ok := local.ModelArg("opencode"); !ok || arg != "ollama/llama3.1" {
+		t.Errorf("ModelArg(opencode) = %q, %v", arg, ok)
+	}
+	if _, ok := local.ModelArg("claude"); ok {
+		t.Error("claude can't run an Ollama tier")
+	}
+}
+
+func TestLookupKey(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("GT_TEST_KEY", "")
+	orig := keychainLookup
+	keychainLookup = func(string) (string, error) { return "", errors.New("no keychain") }
+	defer func() { keychainLookup = orig }()
+
+	spec := config.ModelSpec{Provider: Anthropic, Model: "sonnet"}
+	if k, err := LookupKey(spec); err != nil || k != nil {
+		t.Fatalf("no key anywhere = %+v, %v", k, err)
+	}
+
+	if err := SetSecret(Anthropic, "sk-file"); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(SecretsPath())
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("secrets file mode = %04o, want 0600", info.Mode().Perm())
+	}
+	if k, _ := LookupKey(spec); k == nil || k.Value != "sk-file" || k.Source != "secrets file" {
+		t.Errorf("from secrets file = %+v", k)
+	}
+
+	keychainLookup = func(string) (string, error) { return "sk-keychain", nil }
+	if k, _ := LookupKey(spec); k == nil || k.Source != "keychain" {
+		t.Errorf("keychain should win over the secrets file, got %+v", k)
+	}
+
+	t.Setenv("GT_TEST_KEY", "sk-env")
+	spec.KeyEnv = "GT_TEST_KEY"
+	if k, _ := LookupKey(spec); k == nil || k.Value != "sk-env" || k.Source != "env:GT_TEST_KEY" {
+		t.Errorf("env should win, got %+v", k)
+	}
+
+	if err := os.Chmod(SecretsPath(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSecrets(); err == nil {
+		t.Error("a world-readable secrets file should be refused")
+	}
+
+	if k, err := LookupKey(config.ModelSpec{Provider: Ollama, Model: "llama3.1"}); err != nil || k != nil {
+		t.Errorf("ollama needs no key, got %+v, %v", k, err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			if r.Header.Get("x-api-key") != "good" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		case "/api/tags":
+			_, _ = w.Write([]byte(`{"models":[{"name":"llama3.1:latest"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	claude := &Tier{Name: "sonnet", ModelSpec: config.ModelSpec{Provider: Anthropic, Model: "sonnet", BaseURL: srv.URL}}
+	if err := Ping(ctx, srv.Client(), claude, &Key{Value: "good", Source: "env:X"}); err != nil {
+		t.Errorf("good key: %v", err)
+	}
+	if err := Ping(ctx, srv.Client(), claude, &Key{Value: "bad", Source: "env:X"}); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("bad key = %v", err)
+	}
+	if err := Ping(ctx, srv.Client(), claude, nil); err == nil || !strings.Contains(err.Error(), "ANTHROPIC_API_KEY") {
+		t.Errorf("missing key = %v", err)
+	}
+
+	gpt := &Tier{Name: "fast", ModelSpec: config.ModelSpec{Provider: OpenAI, Model: "gpt-nope", BaseURL: srv.URL}}
+	if err := Ping(ctx, srv.Client(), gpt, &Key{Value: "k"}); err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("unknown OpenAI model = %v", err)
+	}
+
+	local := &Tier{Name: "local", ModelSpec: config.ModelSpec{Provider: Ollama, Model: "llama3.1", BaseURL: srv.URL}}
+	if err := Ping(ctx, srv.Client(), local, nil); err != nil {
+		t.Errorf("pulled Ollama model: %v", err)
+	}
+	local.Model = "mistral"
+	if err := Ping(ctx, srv.Client(), local, nil); err == nil || !strings.Contains(err.Error(), "ollama pull") {
+		t.Errorf("missing Ollama model = %v", err)
+	}
+}
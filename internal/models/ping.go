@@ -0,0 +1,114 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURLs are the providers' API endpoints.
+var DefaultBaseURLs = map[string]string{
+	Anthropic: "https://api.anthropic.com",
+	OpenAI:    "https://api.openai.com",
+	Ollama:    "http://localhost:11434",
+}
+
+// PingTimeout bounds a single connectivity check.
+const PingTimeout = 15 * time.Second
+
+// RateLimitedError is returned when a provider answers 429.
+type RateLimitedError struct {
+	// RetryAfter is the provider's Retry-After, if it sent one.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited (retry after %s)", e.RetryAfter)
+	}
+	return "rate limited"
+}
+
+// BaseURL returns the tier's API endpoint.
+func (t *Tier) BaseURL() string {
+	if t.ModelSpec.BaseURL != "" {
+		return strings.TrimRight(t.ModelSpec.BaseURL, "/")
+	}
+	return DefaultBaseURLs[t.Provider]
+}
+
+// Ping checks that the tier's provider answers with the key, and where the
+// provider can say, that it serves the tier's model. It lists models
+// rather than running one, so it costs no tokens.
+func Ping(ctx context.Context, client *http.Client, t *Tier, key *Key) error {
+	if NeedsKey(t.Provider) && key == nil {
+		env := t.KeyEnv
+		if env == "" {
+			env = KeyEnvVars[t.Provider]
+		}
+		return fmt.Errorf("no API key (set %s, or run 'gt models key set %s')", env, t.Provider)
+	}
+
+	var url string
+	switch t.Provider {
+	case Anthropic:
+		url = t.BaseURL() + "/v1/models"
+	case OpenAI:
+		url = t.BaseURL() + "/v1/models/" + t.Model
+	case Ollama:
+		url = t.BaseURL() + "/api/tags"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	switch t.Provider {
+	case Anthropic:
+		req.Header.Set("x-api-key", key.Value)
+		req.Header.Set("anthropic-version", "2023-06-01")
+	case OpenAI:
+		req.Header.Set("Authorization", "Bearer "+key.Value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach %s: %w", t.BaseURL(), err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("key rejected (%s, from %s)", resp.Status, key.Source)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return &RateLimitedError{RetryAfter: time.Duration(secs) * time.Second}
+	case resp.StatusCode == http.StatusNotFound && t.Provider == OpenAI:
+		return fmt.Errorf("model %s not available to this key", t.Model)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%s answered %s", t.BaseURL(), resp.Status)
+	}
+
+	if t.Provider == Ollama {
+		var tags struct {
+			Models []struct {
+				Name string `json:"name"`
+			} `json:"models"`
+		}
+		if err := json.Unmarshal(body, &tags); err != nil {
+			return fmt.Errorf("parsing Ollama model list: %w", err)
+		}
+		for _, m := range tags.Models {
+			if m.Name == t.Model || strings.TrimSuffix(m.Name, ":latest") == t.Model {
+				return nil
+			}
+		}
+		return fmt.Errorf("model %s not pulled (run: ollama pull %s)", t.Model, t.Model)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package molruns
+
+import (
+	"sort"
+	"time"
+)
+
+// Defaults for flagging slow steps.
+const (
+	DefaultSlowFactor = 3.0 // A step is slow past this multiple of its median
+	DefaultMinSamples = 5   // Finished runs of a step needed before judging it
+)
+
+// Baseline is how long a formula step usually takes.
+type Baseline struct {
+	Formula string
+	Ref     string
+	Median  time.Duration
+	Samples int
+}
+
+// Slow is a running step that has taken well past its baseline.
+type Slow struct {
+	Run      *Run
+	Step     *Step
+	Elapsed  time.Duration
+	Baseline Baseline
+}
+
+// Factor is how many medians the step has taken so far.
+func (s Slow) Factor() float64 {
+	if s.Baseline.Median <= 0 {
+		return 0
+	}
+	return float64(s.Elapsed) / float64(s.Baseline.Median)
+}
+
+// Baselines returns the median duration of each formula step, keyed by
+// "<formula>/<step ref>", from the steps that finished done in runs. A
+// step that was retried counts with its last attempt only.
+func Baselines(runs []*Run) map[string]Baseline {
+	durations := make(map[string][]time.Duration)
+	baselines := make(map[string]Baseline)
+	for _, r := range runs {
+		for _, s := range r.Steps {
+			if s.Outcome != OutcomeDone || s.Ref == "" || s.Started.IsZero() || s.Finished.IsZero() {
+				continue
+			}
+			key := baselineKey(r.Formula, s.Ref)
+			durations[key] = append(durations[key], s.Finished.Sub(s.Started))
+			baselines[key] = Baseline{Formula: r.Formula, Ref: s.Ref}
+		}
+	}
+	for key, ds := range durations {
+		b := baselines[key]
+		b.Median, b.Samples = median(ds), len(ds)
+		baselines[key] = b
+	}
+	return baselines
+}
+
+// SlowSteps returns the steps of running runs that have taken more than
+// factor times their baseline's median by now. Steps with fewer than
+// minSamples finished runs to compare against are never slow.
+func SlowSteps(runs []*Run, factor float64, minSamples int, now time.Time) []Slow {
+	baselines := Baselines(runs)
+	var slow []Slow
+	for _, r := range runs {
+		if r.Status != StatusRunning {
+			continue
+		}
+		for i := range r.Steps {
+			s := &r.Steps[i]
+			if s.Outcome != "" || s.Started.IsZero() {
+				continue
+			}
+			b, ok := baselines[baselineKey(r.Formula, s.Ref)]
+			if !ok || b.Samples < minSamples || b.Median <= 0 {
+				continue
+			}
+			elapsed := now.Sub(s.Started)
+			if float64(elapsed) > factor*float64(b.Median) {
+				slow = append(slow, Slow{Run: r, Step: s, Elapsed: elapsed, Baseline: b})
+			}
+		}
+	}
+	sort.Slice(slow, func(i, j int) bool { return slow[i].Factor() > slow[j].Factor() })
+	return slow
+}
+
+func baselineKey(formula, ref string) string {
+	return formula + "/" + ref
+}
+
+func median(ds []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
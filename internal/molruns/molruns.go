@@ -0,0 +1,354 @@
+// Package molruns keeps the history of molecule runs: the formula and
+// variables each molecule was slung with, who ran it, and when each step
+// started and finished, so a failed run can be inspected and replayed.
+//
+// Each run is a JSON file in <town>/.runtime/molecule-runs/, named after
+// the molecule (wisp root) ID.
+package molruns
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// RunsDir is the run history directory within the town's .runtime
+// directory.
+const RunsDir = "molecule-runs"
+
+// Run statuses.
+const (
+	StatusRunning  = "running"
+	StatusComplete = "complete"
+	StatusFailed   = "failed"
+	StatusBurned   = "burned"
+)
+
+// Step outcomes.
+const (
+	OutcomeDone    = "done"
+	OutcomeFailed  = "failed"
+	OutcomeSkipped = "skipped" // Closed before a replay started
+)
+
+// ErrNotFound is returned for a run with no recorded history.
+var ErrNotFound = errors.New("no such molecule run")
+
+// Step is one step of a run.
+type Step struct {
+	ID       string    `json:"id"`
+	Ref      string    `json:"ref,omitempty"` // Formula step ID
+	Title    string    `json:"title,omitempty"`
+	Agent    string    `json:"agent,omitempty"`
+	Started  time.Time `json:"started,omitempty"`
+	Finished time.Time `json:"finished,omitempty"`
+	Outcome  string    `json:"outcome,omitempty"`
+	Attempts int       `json:"attempts,omitempty"` // Times the step was started
+	Slow     time.Time `json:"slow,omitempty"`     // When this attempt was flagged slow
+}
+
+// Retries is how many times the step was started again after the first.
+func (s Step) Retries() int {
+	if s.Attempts <= 1 {
+		return 0
+	}
+	return s.Attempts - 1
+}
+
+// Duration is how long the step took, or has taken so far.
+func (s Step) Duration() time.Duration {
+	if s.Started.IsZero() {
+		return 0
+	}
+	if s.Finished.IsZero() {
+		return time.Since(s.Started)
+	}
+	return s.Finished.Sub(s.Started)
+}
+
+// Run is one molecule run.
+type Run struct {
+	ID      string   `json:"id"`             // Wisp root; the steps' parent
+	Hook    string   `json:"hook,omitempty"` // Bead on the hook, when bonded to another
+	Formula string   `json:"formula"`
+	Version int      `json:"version,omitempty"` // Formula version poured; 0 if unknown
+	Vars    []string `json:"vars,omitempty"` // key=value, as passed to --var
+	Bead    string   `json:"bead,omitempty"` // Bead the formula was applied to (--on)
+	Rig     string   `json:"rig,omitempty"`
+	Target  string   `json:"target"` // Agent the molecule was slung to
+	Actor   string   `json:"actor,omitempty"`
+
+	Status   string    `json:"status"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished,omitempty"`
+	Steps    []Step    `json:"steps,omitempty"`
+
+	ReplayOf string `json:"replay_of,omitempty"`
+	FromStep string `json:"from_step,omitempty"`
+
+	// Pinned is set when a newer formula version couldn't take over the
+	// run; it finishes on Version, whose formula is kept as a snapshot.
+	Pinned bool `json:"pinned,omitempty"`
+	// MigratedFrom is the version the run was poured from, when it was
+	// moved to Version.
+	MigratedFrom int `json:"migrated_from,omitempty"`
+
+	Retro *Retro `json:"retro,omitempty"`
+}
+
+// Merge outcomes of a run's work.
+const (
+	MergePending = "pending" // Submitted, not yet processed by the refinery
+	MergeMerged  = "merged"
+	MergeFailed  = "failed"
+)
+
+// Retro is the retrospective recorded when a run ends: what it cost, what
+// became of its merge request, and the agent's summary of the work.
+type Retro struct {
+	Generated   time.Time `json:"generated"`
+	Summary     string    `json:"summary,omitempty"` // Written by the agent
+	CostUSD     float64   `json:"cost_usd,omitempty"`
+	Sessions    int       `json:"sessions,omitempty"` // Agent sessions the cost is from
+	MR          string    `json:"mr,omitempty"`
+	Merge       string    `json:"merge,omitempty"` // A Merge outcome; "" with no MR
+	MergeReason string    `json:"merge_reason,omitempty"`
+}
+
+// Retries is how many step restarts the run had.
+func (r *Run) Retries() int {
+	n := 0
+	for _, s := range r.Steps {
+		n += s.Retries()
+	}
+	return n
+}
+
+// Dir returns the run history directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, RunsDir)
+}
+
+// Path returns the history file of a run.
+func Path(townRoot, id string) string {
+	return filepath.Join(Dir(townRoot), id+".json")
+}
+
+// Start records a new run. It is running from now unless r says otherwise.
+func Start(townRoot string, r *Run) error {
+	if r.ID == "" {
+		return fmt.Errorf("molecule run has no ID")
+	}
+	if r.Status == "" {
+		r.Status = StatusRunning
+	}
+	if r.Started.IsZero() {
+		r.Started = time.Now()
+	}
+	return write(townRoot, r.ID, func(*Run) (*Run, error) { return r, nil })
+}
+
+// Load reads a run's history.
+func Load(townRoot, id string) (*Run, error) {
+	data, err := os.ReadFile(Path(townRoot, id)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r Run
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing run %s: %w", id, err)
+	}
+	return &r, nil
+}
+
+// Update applies fn to a recorded run and saves it, holding the run's
+// lock. It returns ErrNotFound for a molecule that wasn't recorded (one
+// slung before history was kept, or by hand with bd).
+func Update(townRoot, id string, fn func(*Run)) error {
+	return write(townRoot, id, func(r *Run) (*Run, error) {
+		if r == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		fn(r)
+		return r, nil
+	})
+}
+
+func write(townRoot, id string, fn func(*Run) (*Run, error)) error {
+	path := Path(townRoot, id)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runs directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking run %s: %w", id, err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	r, err := Load(townRoot, id)
+	if errors.Is(err, ErrNotFound) {
+		r, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+	if r, err = fn(r); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, r)
+}
+
+// List returns every recorded run, newest first.
+func List(townRoot string) ([]*Run, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var runs []*Run
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		r, err := Load(townRoot, id)
+		if err != nil {
+			continue // A damaged file shouldn't hide the rest
+		}
+		runs = append(runs, r)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Started.After(runs[j].Started) })
+	return runs, nil
+}
+
+// FormulaSnapshotPath returns where a formula version's source is kept.
+func FormulaSnapshotPath(townRoot, formula string, version int) string {
+	return filepath.Join(Dir(townRoot), "formulas", fmt.Sprintf("%s.v%d.formula.toml", formula, version))
+}
+
+// SnapshotFormula keeps a formula version's source, so a run can finish on
+// the version it was poured from after the formula changes. An existing
+// snapshot of the version is left alone.
+func SnapshotFormula(townRoot, formula string, version int, data []byte) error {
+	path := FormulaSnapshotPath(townRoot, formula, version)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating formula snapshots directory: %w", err)
+	}
+	return util.AtomicWriteFile(path, data, 0644)
+}
+
+// FindByHook returns the running run whose molecule or hooked bead is id,
+// or nil.
+func FindByHook(townRoot, id string) (*Run, error) {
+	runs, err := List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range runs {
+		if r.Status == StatusRunning && (r.ID == id || r.Hook == id) {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// step returns the run's entry for a step, adding one if needed.
+func (r *Run) step(id string) *Step {
+	for i := range r.Steps {
+		if r.Steps[i].ID == id {
+			return &r.Steps[i]
+		}
+	}
+	r.Steps = append(r.Steps, Step{ID: id})
+	return &r.Steps[len(r.Steps)-1]
+}
+
+// BeginStep records that agent started a step at t.
+func (r *Run) BeginStep(id, ref, title, agent string, t time.Time) {
+	s := r.step(id)
+	s.Ref, s.Title, s.Agent = ref, title, agent
+	s.Started, s.Finished, s.Outcome = t, time.Time{}, ""
+	s.Slow = time.Time{}
+	s.Attempts++
+}
+
+// FinishStep records a step's outcome at t. A step whose start wasn't seen
+// (the first one, claimed by the agent directly) is taken to have started
+// when the previous step finished, or with the run.
+func (r *Run) FinishStep(id, ref, title, agent, outcome string, t time.Time) {
+	last := r.Started
+	for _, s := range r.Steps {
+		if s.ID != id && s.Finished.After(last) && !s.Finished.After(t) {
+			last = s.Finished
+		}
+	}
+	s := r.step(id)
+	if ref != "" {
+		s.Ref = ref
+	}
+	if title != "" {
+		s.Title = title
+	}
+	if agent != "" {
+		s.Agent = agent
+	}
+	if s.Started.IsZero() {
+		s.Started = last
+	}
+	if s.Attempts == 0 {
+		s.Attempts = 1
+	}
+	s.Finished, s.Outcome = t, outcome
+}
+
+// Finish ends the run with status at t. A failed run's unfinished steps
+// are marked failed.
+func (r *Run) Finish(status string, t time.Time) {
+	r.Status, r.Finished = status, t
+	if status != StatusFailed {
+		return
+	}
+	for i := range r.Steps {
+		if s := &r.Steps[i]; s.Outcome == "" {
+			if s.Started.IsZero() {
+				s.Started = t
+			}
+			s.Finished, s.Outcome = t, OutcomeFailed
+		}
+	}
+}
+
+// Duration is how long the run took, or has taken so far.
+func (r *Run) Duration() time.Duration {
+	if r.Finished.IsZero() {
+		return time.Since(r.Started)
+	}
+	return r.Finished.Sub(r.Started)
+}
+
+// FailedStep returns the step the run failed on, or nil.
+func (r *Run) FailedStep() *Step {
+	for i := range r.Steps {
+		if r.Steps[i].Outcome == OutcomeFailed {
+			return &r.Steps[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package molruns
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunHistory(t *testing.T) {
+	townRoot := t.TempDir()
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	if err := Start(townRoot, &Run{ID: "gt-wisp-a", Hook: "gt-abc", Formula: "mol-polecat-work",
+		Vars: []string{"issue=gt-abc"}, Target: "gastown/polecats/Toast", Started: t0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Start(townRoot, &Run{ID: "gt-wisp-b", Formula: "mol-review", Started: t0.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first step's start wasn't seen: it began with the run
+	if err := Update(townRoot, "gt-wisp-a", func(r *Run) {
+		r.FinishStep("gt-wisp-a.1", "load-context", "Load context", "gastown/polecats/Toast", OutcomeDone, t0.Add(2*time.Minute))
+		r.FinishStep("gt-wisp-a.2", "branch-setup", "Set up branch", "", OutcomeDone, t0.Add(5*time.Minute))
+		r.BeginStep("gt-wisp-a.3", "implement", "Implement", "gastown/polecats/Toast", t0.Add(6*time.Minute))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := FindByHook(townRoot, "gt-abc")
+	if err != nil || r == nil || r.ID != "gt-wisp-a" {
+		t.Fatalf("FindByHook = %v, %v", r, err)
+	}
+	if got := r.Steps[0].Duration(); got != 2*time.Minute {
+		t.Errorf("step 1 took %v, want 2m", got)
+	}
+	if got := r.Steps[1].Duration(); got != 3*time.Minute {
+		t.Errorf("step 2 took %v, want 3m (from step 1's end)", got)
+	}
+
+	if err := Update(townRoot, "gt-wisp-a", func(r *Run) { r.Finish(StatusFailed, t0.Add(time.Hour)) }); err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := FindByHook(townRoot, "gt-abc"); r != nil {
+		t.Errorf("finished run still found by hook: %+v", r)
+	}
+
+	runs, err := List(townRoot)
+	if err != nil || len(runs) != 2 || runs[0].ID != "gt-wisp-b" {
+		t.Fatalf("List = %v, %v (want newest first)", runs, err)
+	}
+	failed := runs[1]
+	if failed.Status != StatusFailed || failed.Duration() != time.Hour {
+		t.Errorf("run = %+v", failed)
+	}
+	if s := failed.FailedStep(); s == nil || s.Ref != "implement" || s.Duration() != 54*time.Minute {
+		t.Errorf("failed step = %+v", s)
+	}
+
+	if err := Update(townRoot, "gt-nope", func(*Run) {}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update of unknown run = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStepRetries(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	r := &Run{ID: "gt-wisp-a", Started: t0}
+	r.FinishStep("gt-wisp-a.1", "design", "Design", "", OutcomeDone, t0.Add(time.Minute))
+	r.BeginStep("gt-wisp-a.2", "implement", "Implement", "", t0.Add(2*time.Minute))
+	r.BeginStep("gt-wisp-a.2", "implement", "Implement", "", t0.Add(5*time.Minute))
+	r.FinishStep("gt-wisp-a.2", "", "", "", OutcomeDone, t0.Add(9*time.Minute))
+
+	if got := r.Steps[0].Retries(); got != 0 {
+		t.Errorf("design retries = %d, want 0", got)
+	}
+	if got := r.Steps[1].Retries(); got != 1 {
+		t.Errorf("implement retries = %d, want 1", got)
+	}
+	if got := r.Steps[1].Duration(); got != 4*time.Minute {
+		t.Errorf("implement took %v, want 4m (the last attempt)", got)
+	}
+	if got := r.Retries(); got != 1 {
+		t.Errorf("run retries = %d, want 1", got)
+	}
+}
+
+func TestSlowSteps(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	var runs []*Run
+	for i, mins := range []int{10, 12, 8, 11, 9} {
+		start := t0.Add(time.Duration(i) * time.Hour)
+		r := &Run{ID: "gt-wisp-done", Formula: "mol-polecat-work", Status: StatusComplete, Started: start}
+		r.BeginStep("s1", "implement", "Implement", "", start)
+		r.FinishStep("s1", "", "", "", OutcomeDone, start.Add(time.Duration(mins)*time.Minute))
+		runs = append(runs, r)
+	}
+	now := t0.Add(10 * time.Hour)
+	slow := &Run{ID: "gt-wisp-slow", Formula: "mol-polecat-work", Status: StatusRunning, Started: now.Add(-time.Hour)}
+	slow.BeginStep("s1", "implement", "Implement", "nux", now.Add(-40*time.Minute))
+	fine := &Run{ID: "gt-wisp-fine", Formula: "mol-polecat-work", Status: StatusRunning, Started: now.Add(-time.Hour)}
+	fine.BeginStep("s1", "implement", "Implement", "", now.Add(-20*time.Minute))
+	other := &Run{ID: "gt-wisp-other", Formula: "mol-review", Status: StatusRunning, Started: now.Add(-time.Hour)}
+	other.BeginStep("s1", "implement", "Implement", "", now.Add(-50*time.Minute))
+	runs = append(runs, slow, fine, other)
+
+	b := Baselines(runs)["mol-polecat-work/implement"]
+	if b.Median != 10*time.Minute || b.Samples != 5 {
+		t.Fatalf("baseline = %+v, want 10m over 5 runs", b)
+	}
+
+	got := SlowSteps(runs, DefaultSlowFactor, DefaultMinSamples, now)
+	if len(got) != 1 || got[0].Run.ID != "gt-wisp-slow" || got[0].Factor() != 4 {
+		t.Fatalf("SlowSteps = %+v, want only gt-wisp-slow at 4x", got)
+	}
+	if got := SlowSteps(runs, DefaultSlowFactor, 6, now); len(got) != 0 {
+		t.Errorf("with too few samples, SlowSteps = %+v, want none", got)
+	}
+
+	// A retry is a new attempt, to be flagged afresh
+	slow.Steps[0].Slow = now
+	slow.BeginStep("s1", "implement", "Implement", "nux", now)
+	if !slow.Steps[0].Slow.IsZero() {
+		t.Error("BeginStep kept the previous attempt's slow mark")
+	}
+}
@@ -0,0 +1,269 @@
+// Package notify delivers selected town events to external chat sinks.
+//
+// Events are the same ones written to ~/gt/.events.jsonl; a Watcher tails
+// that file (inside the daemon) and hands matching events to a Notifier,
+// which routes them to sinks according to the "notifications" section of
+// settings/config.json. Slack and Discord incoming webhooks are supported.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Notification events that can be routed to sinks.
+const (
+	EventPolecatDone      = "polecat_done"
+	EventMergeFailed      = "merge_failed"
+	EventEscalation       = "escalation"
+	EventMoleculeComplete = "molecule_complete"
+	EventBudget           = "budget"
+	EventApproval         = "approval"
+	EventStepSlow         = "step_slow"
+	EventDigest           = "digest" // The Deacon's nightly digest (gt digest --send)
+
+	// EventAll routes every notification event to a sink.
+	EventAll = "*"
+)
+
+// Sink types.
+const (
+	SinkSlack   = "slack"
+	SinkDiscord = "discord"
+)
+
+// Events returns the routable notification events.
+func Events() []string {
+	return []string{EventPolecatDone, EventMergeFailed, EventEscalation, EventMoleculeComplete, EventBudget, EventApproval, EventStepSlow, EventDigest}
+}
+
+// Message is a rendered notification.
+type Message struct {
+	Event string // Notification event (EventPolecatDone, ...)
+	Title string // One-line headline
+	Text  string // Optional detail
+}
+
+// Sink delivers messages to an external service.
+type Sink interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Notifier routes messages to configured sinks.
+type Notifier struct {
+	sinks  map[string]Sink
+	routes map[string][]string
+}
+
+// New builds a Notifier from town notification settings.
+// Sinks whose webhook URL cannot be resolved are rejected so that
+// misconfiguration surfaces at startup rather than as silent drops.
+func New(cfg *config.NotificationsConfig) (*Notifier, error) {
+	n := &Notifier{sinks: make(map[string]Sink), routes: make(map[string][]string)}
+	if cfg == nil {
+		return n, nil
+	}
+
+	for name, sc := range cfg.Sinks {
+		if sc == nil {
+			continue
+		}
+		sink, err := NewSink(sc)
+		if err != nil {
+			return nil, fmt.Errorf("sink %q: %w", name, err)
+		}
+		n.sinks[name] = sink
+	}
+
+	for event, names := range cfg.Routes {
+		if !ValidEvent(event) {
+			return nil, fmt.Errorf("unknown notification event %q (valid: %s, *)", event, strings.Join(Events(), ", "))
+		}
+		for _, name := range names {
+			if _, ok := n.sinks[name]; !ok {
+				return nil, fmt.Errorf("route %q references unknown sink %q", event, name)
+			}
+		}
+		n.routes[event] = names
+	}
+	return n, nil
+}
+
+// NewSink creates a sink from its configuration.
+func NewSink(sc *config.NotificationSink) (Sink, error) {
+	url := sc.URL
+	if sc.URLEnv != "" {
+		url = os.Getenv(sc.URLEnv)
+		if url == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", sc.URLEnv)
+		}
+	}
+	if url == "" {
+		return nil, fmt.Errorf("no webhook url configured")
+	}
+
+	switch sc.Type {
+	case SinkSlack:
+		return NewSlackSink(url), nil
+	case SinkDiscord:
+		return NewDiscordSink(url), nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q (valid: %s, %s)", sc.Type, SinkSlack, SinkDiscord)
+	}
+}
+
+// Enabled reports whether any route is configured.
+func (n *Notifier) Enabled() bool {
+	return len(n.routes) > 0
+}
+
+// SinkNames returns the configured sink names, sorted.
+func (n *Notifier) SinkNames() []string {
+	names := make([]string, 0, len(n.sinks))
+	for name := range n.sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SinksFor returns the sink names a notification event is routed to,
+// including wildcard routes, without duplicates.
+func (n *Notifier) SinksFor(event string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, key := range []string{event, EventAll} {
+		for _, name := range n.routes[key] {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// Notify sends a message to every sink its event is routed to.
+// Delivery continues past failing sinks; all errors are returned.
+func (n *Notifier) Notify(ctx context.Context, msg *Message) []error {
+	var errs []error
+	for _, name := range n.SinksFor(msg.Event) {
+		if err := n.sinks[name].Send(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errs
+}
+
+// FromEvent renders a town event as a notification.
+// Returns false for events that are not notifiable.
+func FromEvent(e *events.Event) (*Message, bool) {
+	str := func(key string) string {
+		if v, ok := e.Payload[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+
+	switch e.Type {
+	case events.TypeDone:
+		msg := &Message{Event: EventPolecatDone, Title: fmt.Sprintf("✅ %s finished %s", e.Actor, str("bead"))}
+		if branch := str("branch"); branch != "" {
+			msg.Text = "Branch: " + branch
+		}
+		return msg, true
+
+	case events.TypeMergeFailed:
+		msg := &Message{Event: EventMergeFailed, Title: fmt.Sprintf("❌ Merge failed: %s", str("branch"))}
+		var lines []string
+		if worker := str("worker"); worker != "" {
+			lines = append(lines, "Worker: "+worker)
+		}
+		if mr := str("mr"); mr != "" {
+			lines = append(lines, "MR: "+mr)
+		}
+		if reason := str("reason"); reason != "" {
+			lines = append(lines, "Reason: "+reason)
+		}
+		msg.Text = strings.Join(lines, "\n")
+		return msg, true
+
+	case events.TypeEscalationSent:
+		severity := str("severity")
+		if severity == "" {
+			severity = str("new_severity")
+		}
+		title := fmt.Sprintf("🚨 Escalation from %s", e.Actor)
+		if severity != "" {
+			title = fmt.Sprintf("🚨 Escalation (%s) from %s", severity, e.Actor)
+		}
+		text := str("reason")
+		if id := str("escalation_id"); id != "" {
+			text = "Re-escalated: " + id
+		}
+		return &Message{Event: EventEscalation, Title: title, Text: text}, true
+
+	case events.TypeMoleculeComplete:
+		msg := &Message{Event: EventMoleculeComplete, Title: fmt.Sprintf("🎉 Molecule %s complete", str("molecule"))}
+		if e.Actor != "" {
+			msg.Text = "Agent: " + e.Actor
+		}
+		return msg, true
+
+	case events.TypeGateAwaiting:
+		msg := &Message{Event: EventApproval, Title: fmt.Sprintf("✋ Approval needed: %s", str("step_title"))}
+		msg.Text = fmt.Sprintf("Agent: %s\nApprove: gt approve %s %s", str("agent"), str("molecule"), str("step"))
+		return msg, true
+
+	case events.TypeBudgetWarning, events.TypeBudgetExceeded:
+		num := func(key string) float64 {
+			v, _ := e.Payload[key].(float64)
+			return v
+		}
+		amount := func(v float64) string {
+			if str("unit") == "tokens" {
+				return fmt.Sprintf("%.0f tokens", v)
+			}
+			return fmt.Sprintf("$%.2f", v)
+		}
+		title := fmt.Sprintf("⚠️ %s budget at %.0f%%: new spawns paused", str("period"), num("percent"))
+		if e.Type == events.TypeBudgetExceeded {
+			title = fmt.Sprintf("🛑 %s budget exhausted: spawning stopped", str("period"))
+		}
+		text := fmt.Sprintf("Used %s of %s", amount(num("used")), amount(num("limit")))
+		return &Message{Event: EventBudget, Title: title, Text: text}, true
+
+	case events.TypeStepSlow:
+		secs := func(key string) time.Duration {
+			v, _ := e.Payload[key].(float64)
+			return time.Duration(v) * time.Second
+		}
+		msg := &Message{Event: EventStepSlow, Title: fmt.Sprintf("🐢 Step %s of %s is running long", str("step_ref"), str("molecule"))}
+		msg.Text = fmt.Sprintf("Running %s; usually %s", secs("elapsed_secs"), secs("median_secs"))
+		if agent := str("agent"); agent != "" {
+			msg.Text += "\nAgent: " + agent
+		}
+		return msg, true
+	}
+	return nil, false
+}
+
+// ValidEvent reports whether event can be used as a route key.
+func ValidEvent(event string) bool {
+	if event == EventAll {
+		return true
+	}
+	for _, e := range Events() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
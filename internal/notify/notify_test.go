@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestFromEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		event events.Event
+		want  string
+		title string
+	}{
+		{
+			name:  "done",
+			event: events.Event{Type: events.TypeDone, Actor: "gastown/polecats/nux", Payload: events.DonePayload("gt-abc", "polecat/nux")},
+			want:  EventPolecatDone,
+			title: "gastown/polecats/nux finished gt-abc",
+		},
+		{
+			name:  "merge failed",
+			event: events.Event{Type: events.TypeMergeFailed, Payload: events.MergePayload("gt-mr1", "nux", "polecat/nux", "conflict")},
+			want:  EventMergeFailed,
+			title: "Merge failed: polecat/nux",
+		},
+		{
+			name:  "escalation",
+			event: events.Event{Type: events.TypeEscalationSent, Actor: "mayor", Payload: map[string]interface{}{"severity": "high", "reason": "stuck"}},
+			want:  EventEscalation,
+			title: "Escalation (high) from mayor",
+		},
+		{
+			name:  "molecule",
+			event: events.Event{Type: events.TypeMoleculeComplete, Payload: events.MoleculePayload("gt-mol1", "gastown")},
+			want:  EventMoleculeComplete,
+			title: "Molecule gt-mol1 complete",
+		},
+		{
+			name:  "budget",
+			event: events.Event{Type: events.TypeBudgetWarning, Payload: events.BudgetPayload("daily", "usd", 41, 50, 82)},
+			want:  EventBudget,
+			title: "daily budget at 82%",
+		},
+		{
+			name:  "approval",
+			event: events.Event{Type: events.TypeGateAwaiting, Payload: events.GatePayload("gt-mol", "gt-mol.2", "Review the diff", "gastown/polecats/nux", "")},
+			want:  EventApproval,
+			title: "Approval needed: Review the diff",
+		},
+		{
+			name:  "slow step",
+			event: events.Event{Type: events.TypeStepSlow, Payload: events.StepSlowPayload("gt-mol", "gt-mol.3", "implement", "mol-polecat-work", "gastown/polecats/nux", time.Hour, 10*time.Minute, 6)},
+			want:  EventStepSlow,
+			title: "Step implement of gt-mol is running long",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, ok := FromEvent(&tt.event)
+			if !ok {
+				t.Fatal("expected notifiable event")
+			}
+			if msg.Event != tt.want {
+				t.Errorf("Event = %q, want %q", msg.Event, tt.want)
+			}
+			if !strings.Contains(msg.Title, tt.title) {
+				t.Errorf("Title = %q, want it to contain %q", msg.Title, tt.title)
+			}
+		})
+	}
+
+	if _, ok := FromEvent(&events.Event{Type: events.TypeSling}); ok {
+		t.Error("sling events should not notify")
+	}
+}
+
+func TestNotifierRoutes(t *testing.T) {
+	var mu sync.Mutex
+	got := map[string][]map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		got[r.URL.Path] = append(got[r.URL.Path], body)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	t.Setenv("TEST_DISCORD_HOOK", srv.URL+"/discord")
+	n, err := New(&config.NotificationsConfig{
+		Sinks: map[string]*config.NotificationSink{
+			"ops": {Type: SinkSlack, URL: srv.URL + "/slack"},
+			"dev": {Type: SinkDiscord, URLEnv: "TEST_DISCORD_HOOK"},
+		},
+		Routes: map[string][]string{
+			EventMergeFailed: {"ops", "dev"},
+			EventAll:         {"ops"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if errs := n.Notify(t.Context(), &Message{Event: EventMergeFailed, Title: "boom", Text: "details"}); len(errs) != 0 {
+		t.Fatalf("Notify errors: %v", errs)
+	}
+	if errs := n.Notify(t.Context(), &Message{Event: EventPolecatDone, Title: "done"}); len(errs) != 0 {
+		t.Fatalf("Notify errors: %v", errs)
+	}
+
+	if len(got["/slack"]) != 2 {
+		t.Errorf("slack got %d messages, want 2 (no duplicate from wildcard)", len(got["/slack"]))
+	}
+	if len(got["/discord"]) != 1 {
+		t.Fatalf("discord got %d messages, want 1", len(got["/discord"]))
+	}
+	if got["/slack"][0]["text"] != "*boom*\ndetails" {
+		t.Errorf("slack text = %q", got["/slack"][0]["text"])
+	}
+	if got["/discord"][0]["content"] != "**boom**\ndetails" {
+		t.Errorf("discord content = %q", got["/discord"][0]["content"])
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.NotificationsConfig
+	}{
+		{"unknown type", &config.NotificationsConfig{Sinks: map[string]*config.NotificationSink{"x": {Type: "teams", URL: "http://x"}}}},
+		{"missing env", &config.NotificationsConfig{Sinks: map[string]*config.NotificationSink{"x": {Type: SinkSlack, URLEnv: "GT_TEST_UNSET_HOOK"}}}},
+		{"unknown event", &config.NotificationsConfig{Routes: map[string][]string{"deploy": nil}}},
+		{"unknown sink", &config.NotificationsConfig{Routes: map[string][]string{EventEscalation: {"nope"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSinkReportsHTTPErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	err := NewSlackSink(srv.URL).Send(t.Context(), &Message{Title: "x"})
+	if err == nil || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("Send error = %v, want HTTP failure detail", err)
+	}
+}
@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sendTimeout bounds a single webhook delivery.
+const sendTimeout = 10 * time.Second
+
+// webhookSink posts JSON to an incoming webhook URL.
+type webhookSink struct {
+	url    string
+	client *http.Client
+	body   func(msg *Message) any
+}
+
+// NewSlackSink creates a sink for a Slack incoming webhook.
+func NewSlackSink(url string) Sink {
+	return &webhookSink{
+		url:    url,
+		client: &http.Client{Timeout: sendTimeout},
+		body: func(msg *Message) any {
+			// Slack mrkdwn uses single asterisks for bold
+			return map[string]string{"text": render(msg, "*")}
+		},
+	}
+}
+
+// NewDiscordSink creates a sink for a Discord webhook.
+func NewDiscordSink(url string) Sink {
+	return &webhookSink{
+		url:    url,
+		client: &http.Client{Timeout: sendTimeout},
+		body: func(msg *Message) any {
+			return map[string]string{"content": render(msg, "**")}
+		},
+	}
+}
+
+// Send posts the message to the webhook.
+func (s *webhookSink) Send(ctx context.Context, msg *Message) error {
+	data, err := json.Marshal(s.body(msg))
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// render formats a message as chat markdown with a bold title.
+func render(msg *Message, bold string) string {
+	out := bold + msg.Title + bold
+	if msg.Text != "" {
+		out += "\n" + msg.Text
+	}
+	return out
+}
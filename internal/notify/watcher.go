@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Watcher tails the town events log and forwards notifiable events.
+type Watcher struct {
+	townRoot string
+	notifier *Notifier
+	logf     func(format string, args ...interface{})
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWatcher creates a watcher for a town. logf receives delivery errors.
+func NewWatcher(townRoot string, notifier *Notifier, logf func(format string, args ...interface{})) *Watcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Watcher{
+		townRoot: townRoot,
+		notifier: notifier,
+		logf:     logf,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start begins tailing from the current end of the events log, so a
+// daemon restart does not replay old notifications.
+func (w *Watcher) Start() error {
+	eventsPath := filepath.Join(w.townRoot, events.EventsFile)
+
+	file, err := os.OpenFile(eventsPath, os.O_RDONLY|os.O_CREATE, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening events file: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("seeking to end: %w", err)
+	}
+
+	w.wg.Add(1)
+	go w.run(file)
+	return nil
+}
+
+// Stop stops the watcher and waits for in-flight deliveries.
+func (w *Watcher) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *Watcher) run(file *os.File) {
+	defer w.wg.Done()
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var partial string
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					// Keep an incomplete trailing line until the writer finishes it
+					partial += line
+					break
+				}
+				w.handleLine(partial + line)
+				partial = ""
+			}
+			// gt archive moved old events out of the log
+			if nf, err := events.Reopen(file, w.townRoot); err == nil && nf != nil {
+				_ = file.Close()
+				file, partial = nf, ""
+				reader.Reset(nf)
+			}
+		}
+	}
+}
+
+func (w *Watcher) handleLine(line string) {
+	var event events.Event
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return
+	}
+	msg, ok := FromEvent(&event)
+	if !ok {
+		return
+	}
+	for _, err := range w.notifier.Notify(w.ctx, msg) {
+		if w.logf != nil {
+			w.logf("notify %s: %v", msg.Event, err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Package output implements the shared -q/-v/-vv verbosity levels for gt
+// commands.
+//
+//   - Quiet (-q): only results and errors, for scripts
+//   - Normal: human summaries (the default)
+//   - Verbose (-v): extra detail per item
+//   - Trace (-vv): per-step traces, including external commands run
+//
+// Summaries go to stdout. Verbose and trace lines go to stderr so that
+// machine-readable stdout (--json, IDs for scripts) is never polluted.
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Level is an output verbosity level.
+type Level int
+
+// Verbosity levels, least to most output.
+const (
+	Quiet   Level = -1
+	Normal  Level = 0
+	Verbose Level = 1
+	Trace   Level = 2
+)
+
+var (
+	mu      sync.RWMutex
+	level   = Normal
+	started = time.Now()
+
+	// stdout and stderr are variables so tests can capture output.
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// FromFlags converts the root -q flag and -v count into a level.
+// -q wins over -v so scripts stay quiet even with inherited aliases.
+func FromFlags(quiet bool, verbosity int) Level {
+	switch {
+	case quiet:
+		return Quiet
+	case verbosity >= 2:
+		return Trace
+	case verbosity == 1:
+		return Verbose
+	default:
+		return Normal
+	}
+}
+
+// SetLevel sets the process-wide verbosity level.
+func SetLevel(l Level) {
+	mu.Lock()
+	level = l
+	started = time.Now()
+	mu.Unlock()
+}
+
+// GetLevel returns the current verbosity level.
+func GetLevel() Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	return level
+}
+
+// IsQuiet reports whether -q was given.
+func IsQuiet() bool { return GetLevel() <= Quiet }
+
+// IsVerbose reports whether -v (or more) was given.
+func IsVerbose() bool { return GetLevel() >= Verbose }
+
+// IsTrace reports whether -vv was given.
+func IsTrace() bool { return GetLevel() >= Trace }
+
+// Printf writes a human summary line to stdout unless quiet.
+func Printf(format string, args ...interface{}) {
+	if !IsQuiet() {
+		_, _ = fmt.Fprintf(stdout, format, args...)
+	}
+}
+
+// Println writes a human summary line to stdout unless quiet.
+func Println(args ...interface{}) {
+	if !IsQuiet() {
+		_, _ = fmt.Fprintln(stdout, args...)
+	}
+}
+
+// Verbosef writes a detail line to stderr at -v and above.
+func Verbosef(format string, args ...interface{}) {
+	if IsVerbose() {
+		_, _ = fmt.Fprintf(stderr, format+"\n", args...)
+	}
+}
+
+// Tracef writes a step trace to stderr at -vv, prefixed with the time
+// elapsed since the command started.
+func Tracef(format string, args ...interface{}) {
+	if !IsTrace() {
+		return
+	}
+	mu.RLock()
+	elapsed := time.Since(started)
+	mu.RUnlock()
+	_, _ = fmt.Fprintf(stderr, "[%7.3fs] %s\n", elapsed.Seconds(), fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func capture(t *testing.T, l Level) (*bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	oldOut, oldErr := stdout, stderr
+	stdout, stderr = &out, &errOut
+	SetLevel(l)
+	t.Cleanup(func() {
+		stdout, stderr = oldOut, oldErr
+		SetLevel(Normal)
+	})
+	return &out, &errOut
+}
+
+func TestFromFlags(t *testing.T) {
+	tests := []struct {
+		quiet     bool
+		verbosity int
+		want      Level
+	}{
+		{false, 0, Normal},
+		{false, 1, Verbose},
+		{false, 2, Trace},
+		{false, 5, Trace},
+		{true, 0, Quiet},
+		{true, 2, Quiet},
+	}
+	for _, tt := range tests {
+		if got := FromFlags(tt.quiet, tt.verbosity); got != tt.want {
+			t.Errorf("FromFlags(%v, %d) = %d, want %d", tt.quiet, tt.verbosity, got, tt.want)
+		}
+	}
+}
+
+func TestLevels(t *testing.T) {
+	emit := func() {
+		Printf("summary\n")
+		Verbosef("detail")
+		Tracef("step")
+	}
+
+	tests := []struct {
+		level            Level
+		summary, verbose bool
+		trace            bool
+	}{
+		{Quiet, false, false, false},
+		{Normal, true, false, false},
+		{Verbose, true, true, false},
+		{Trace, true, true, true},
+	}
+	for _, tt := range tests {
+		out, errOut := capture(t, tt.level)
+		emit()
+		if got := strings.Contains(out.String(), "summary"); got != tt.summary {
+			t.Errorf("level %d: summary shown = %v, want %v", tt.level, got, tt.summary)
+		}
+		if got := strings.Contains(errOut.String(), "detail"); got != tt.verbose {
+			t.Errorf("level %d: verbose shown = %v, want %v", tt.level, got, tt.verbose)
+		}
+		if got := strings.Contains(errOut.String(), "step"); got != tt.trace {
+			t.Errorf("level %d: trace shown = %v, want %v", tt.level, got, tt.trace)
+		}
+	}
+}
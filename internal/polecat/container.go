@@ -0,0 +1,189 @@
+package polecat
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/container"
+	"github.com/steveyegge/gastown/internal/sandbox"
+)
+
+// ErrNoContainerConfig is returned for the docker runtime on a rig without
+// container settings.
+var ErrNoContainerConfig = errors.New("rig has no container settings (add \"container\" to settings/config.json)")
+
+// ResolveRuntime returns the runtime a polecat runs under: the requested
+// one, or the rig's default when none is requested.
+func ResolveRuntime(settings *config.RigSettings, requested string) (string, error) {
+	switch requested {
+	case "":
+		if settings != nil && settings.Container != nil && settings.Container.Default {
+			return container.RuntimeDocker, nil
+		}
+		return container.RuntimeLocal, nil
+	case container.RuntimeLocal:
+		return requested, nil
+	case container.RuntimeDocker:
+		if settings == nil || settings.Container == nil {
+			return "", ErrNoContainerConfig
+		}
+		return requested, nil
+	}
+	return "", fmt.Errorf("unknown runtime %q (use %s or %s)", requested, container.RuntimeLocal, container.RuntimeDocker)
+}
+
+// rigSettings loads the rig's settings; a rig without a settings file has
+// the defaults.
+func (m *SessionManager) rigSettings() (*config.RigSettings, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path))
+	if errors.Is(err, config.ErrNotFound) {
+		return &config.RigSettings{}, nil
+	}
+	return settings, err
+}
+
+// runtimeCommand wraps a polecat's startup command for its runtime: in a
+// container for the docker runtime, otherwise in the rig's sandbox
+// profile, if any. A non-empty sandboxProfile replaces the rig's profile.
+func (m *SessionManager) runtimeCommand(polecat, workDir, command, requested, sandboxProfile string, env map[string]string) (string, error) {
+	settings, err := m.rigSettings()
+	if err != nil {
+		return "", err
+	}
+	runtimeName, err := ResolveRuntime(settings, requested)
+	if err != nil {
+		return "", err
+	}
+	if runtimeName == container.RuntimeDocker {
+		return m.containerCommand(polecat, workDir, command, env, settings)
+	}
+	cfg := settings.Sandbox
+	if sandboxProfile != "" {
+		override := config.SandboxConfig{}
+		if cfg != nil {
+			override = *cfg
+		}
+		override.Profile = sandboxProfile
+		cfg = &override
+	}
+	if cfg == nil {
+		return command, nil
+	}
+	return sandbox.Wrap(cfg.Profile, command, m.sandboxPolicy(polecat, workDir, cfg))
+}
+
+// containerCommand readies the rig's image and returns the session command
+// that runs the agent in a container. The container gets the view of the
+// host a sandbox would: the town read-only, other agents' clones hidden,
+// and the polecat's own paths writable, all at their host paths.
+func (m *SessionManager) containerCommand(polecat, workDir, command string, env map[string]string, settings *config.RigSettings) (string, error) {
+	cfg := settings.Container
+	buildDir := ""
+	if cfg.Build != "" {
+		buildDir = m.settingsPath(cfg.Build)
+	}
+	if err := container.EnsureImage(cfg.Engine, cfg.Image, buildDir); err != nil {
+		return "", err
+	}
+
+	limits := settings.Sandbox
+	if limits == nil {
+		limits = &config.SandboxConfig{}
+	}
+	p := m.sandboxPolicy(polecat, workDir, limits)
+	townRoot := filepath.Dir(m.rig.Path)
+
+	spec := &container.Spec{
+		Engine:   cfg.Engine,
+		Name:     m.SessionName(polecat),
+		Image:    cfg.Image,
+		WorkDir:  workDir,
+		Network:  cfg.Network,
+		Env:      make(map[string]string),
+		MemoryMB: p.MemoryMB,
+		CPUs:     p.CPUs,
+		MaxProcs: p.MaxProcs,
+	}
+	if p.DenyNetwork && spec.Network == "" {
+		spec.Network = "none"
+	}
+
+	spec.Mounts = append(spec.Mounts, container.Mount{Source: townRoot, Target: townRoot, ReadOnly: true})
+	for _, dir := range p.Hidden {
+		spec.Mounts = append(spec.Mounts, container.Mount{Target: dir})
+	}
+	for _, path := range p.ReadOnly {
+		spec.Mounts = append(spec.Mounts, container.Mount{Source: path, Target: path, ReadOnly: true})
+	}
+	for _, path := range append([]string{workDir}, p.Writable...) {
+		spec.Mounts = append(spec.Mounts, container.Mount{Source: path, Target: path})
+	}
+	for _, mount := range cfg.Mounts {
+		parts := strings.Split(mount, ":")
+		spec.Mounts = append(spec.Mounts, container.Mount{
+			Source:   m.settingsPath(parts[0]),
+			Target:   parts[1],
+			ReadOnly: len(parts) == 3 && parts[2] == "ro",
+		})
+	}
+
+	// Linux hosts share gt, bd and the tmux server with the container, so
+	// the agent's gt commands (mail, nudges, done) work without baking gt
+	// into the image. Files the agent writes stay owned by the host user.
+	if goruntime.GOOS == "linux" {
+		spec.Mounts = append(spec.Mounts, hostTools()...)
+		spec.User = fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+	}
+
+	for k, v := range env {
+		spec.Env[k] = v
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		spec.Env["HOME"] = home // where the agent state mounts are
+	}
+	for k, v := range cfg.Env {
+		spec.Env[k] = v
+	}
+
+	return spec.SessionCommand(command), nil
+}
+
+// hostTools mounts gt, bd and the tmux socket directory into a container.
+func hostTools() []container.Mount {
+	var mounts []container.Mount
+	if gt, err := os.Executable(); err == nil {
+		mounts = append(mounts, container.Mount{Source: gt, Target: "/usr/local/bin/gt", ReadOnly: true})
+	}
+	if bd, err := exec.LookPath("bd"); err == nil {
+		if resolved, err := filepath.EvalSymlinks(bd); err == nil {
+			bd = resolved
+		}
+		mounts = append(mounts, container.Mount{Source: bd, Target: "/usr/local/bin/bd", ReadOnly: true})
+	}
+	tmpDir := os.Getenv("TMUX_TMPDIR")
+	if tmpDir == "" {
+		tmpDir = "/tmp"
+	}
+	socketDir := filepath.Join(tmpDir, fmt.Sprintf("tmux-%d", os.Getuid()))
+	if _, err := os.Stat(socketDir); err == nil {
+		mounts = append(mounts, container.Mount{Source: socketDir, Target: socketDir})
+	}
+	return mounts
+}
+
+// removeContainer removes a stopped polecat's container. The session's
+// own cleanup normally does this; this covers sessions killed before it
+// ran.
+func (m *SessionManager) removeContainer(sessionID string) {
+	settings, err := m.rigSettings()
+	if err != nil || settings.Container == nil {
+		return
+	}
+	debugSession("container.Remove", container.Remove(settings.Container.Engine, sessionID))
+}
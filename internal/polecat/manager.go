@@ -5,8 +5,8 @@ package polecat
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -14,8 +14,13 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dryrun"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/vcs"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -47,6 +52,7 @@ type Manager struct {
 	git      *git.Git
 	beads    *beads.Beads
 	namePool *NamePool
+	log      *slog.Logger
 }
 
 // NewManager creates a new polecat manager.
@@ -82,6 +88,7 @@ func NewManager(r *rig.Rig, g *git.Git) *Manager {
 		git:      g,
 		beads:    beads.NewWithBeadsDir(beadsPath, resolvedBeads),
 		namePool: pool,
+		log:      logging.For("polecat").With(logging.KeyRig, r.Name),
 	}
 }
 
@@ -161,10 +168,19 @@ func (m *Manager) checkCleanupStatus(name string, status CleanupStatus, force bo
 	}
 }
 
-// repoBase returns the git directory and Git object to use for worktree operations.
+// repoBase returns the repo to use for worktree operations.
 // Prefers the shared bare repo (.repo.git) if it exists, otherwise falls back to mayor/rig.
 // The bare repo architecture allows all worktrees (refinery, polecats) to share branch visibility.
-func (m *Manager) repoBase() (*git.Git, error) {
+// A jj rig has no bare repo: its polecats are workspaces of the colocated mayor/rig.
+func (m *Manager) repoBase() (vcs.Repo, error) {
+	if m.rig.VCS == vcs.KindJJ {
+		mayorPath := filepath.Join(m.rig.Path, "mayor", "rig")
+		if _, err := os.Stat(filepath.Join(mayorPath, ".jj")); err != nil {
+			return nil, fmt.Errorf("no jj repo at %s", mayorPath)
+		}
+		return vcs.Open(vcs.KindJJ, mayorPath), nil
+	}
+
 	// First check for shared bare repo (new architecture)
 	bareRepoPath := filepath.Join(m.rig.Path, ".repo.git")
 	if info, err := os.Stat(bareRepoPath); err == nil && info.IsDir() {
@@ -180,6 +196,11 @@ func (m *Manager) repoBase() (*git.Git, error) {
 	return git.NewGit(mayorPath), nil
 }
 
+// clone returns the repo of a polecat's worktree.
+func (m *Manager) clone(clonePath string) vcs.Repo {
+	return vcs.Open(m.rig.VCS, clonePath)
+}
+
 // polecatDir returns the parent directory for a polecat.
 // This is polecats/<name>/ - the polecat's home directory.
 func (m *Manager) polecatDir(name string) string {
@@ -219,6 +240,83 @@ func (m *Manager) exists(name string) bool {
 // AddOptions configures polecat creation.
 type AddOptions struct {
 	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
+	Branch   string // Branch name template after "polecat/" (see branchName)
+}
+
+// branchName returns the branch for a polecat run. Branches always start
+// with "polecat/", which branch pruning and the refinery look for; the
+// template sets the rest, with {name}, {bead}, {rig} and {ts} placeholders.
+// The default is polecat/<name>/<bead-id>, which gt mq submit parses, or
+// polecat/<name>-<ts> for a polecat spawned without a bead.
+func (m *Manager) branchName(name string, opts AddOptions) string {
+	template := opts.Branch
+	if template == "" {
+		template = "{name}-{ts}"
+		if opts.HookBead != "" {
+			template = "{name}/{bead}"
+		}
+	}
+	branch := strings.NewReplacer(
+		"{name}", name,
+		"{bead}", opts.HookBead,
+		"{rig}", m.rig.Name,
+		"{ts}", branchTimestamp(),
+	).Replace(strings.TrimPrefix(template, constants.BranchPolecatPrefix))
+	// An empty {bead} leaves doubled or dangling separators behind
+	for strings.Contains(branch, "--") || strings.Contains(branch, "//") {
+		branch = strings.ReplaceAll(strings.ReplaceAll(branch, "--", "-"), "//", "/")
+	}
+	return constants.BranchPolecatPrefix + strings.Trim(branch, "-/")
+}
+
+// branchTimestamp returns a short, per-run unique branch suffix.
+// Use base36 encoding for shorter branch names (8 chars vs 13 digits)
+func branchTimestamp() string {
+	return strconv.FormatInt(time.Now().UnixMilli(), 36)
+}
+
+// freeBranch makes way for a new polecat branch. A stale branch of the same
+// name left by an earlier run, or one in its way (polecat/<name> blocks
+// polecat/<name>/<bead>), is deleted: polecats push their branches for
+// merge, so the local copies are expendable. A branch still checked out in
+// a worktree is left alone and the new branch gets a timestamp suffix.
+func (m *Manager) freeBranch(repoGit vcs.Repo, branch string) string {
+	inUse := make(map[string]bool)
+	if worktrees, err := repoGit.WorktreeList(); err == nil {
+		for _, wt := range worktrees {
+			inUse[wt.Branch] = true
+		}
+	}
+	candidates := []string{branch}
+	parts := strings.Split(strings.TrimPrefix(branch, constants.BranchPolecatPrefix), "/")
+	for i := 1; i < len(parts); i++ {
+		candidates = append(candidates, constants.BranchPolecatPrefix+strings.Join(parts[:i], "/"))
+	}
+	for _, c := range candidates {
+		if exists, _ := repoGit.BranchExists(c); !exists {
+			continue
+		}
+		if inUse[c] {
+			return branch + "-" + branchTimestamp()
+		}
+		_ = repoGit.DeleteBranch(c, true)
+	}
+	return branch
+}
+
+// defaultBranch returns the rig's default branch.
+func (m *Manager) defaultBranch() string {
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
+		return rigCfg.DefaultBranch
+	}
+	return "main"
+}
+
+// branchMerged reports whether a polecat branch has been merged into the
+// rig's default branch on origin.
+func (m *Manager) branchMerged(repoGit vcs.Repo, branch string) bool {
+	merged, err := repoGit.IsAncestor(branch, "origin/"+m.defaultBranch())
+	return err == nil && merged
 }
 
 // Add creates a new polecat as a git worktree from the repo base.
@@ -246,12 +344,8 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	polecatDir := m.polecatDir(name)
 	clonePath := filepath.Join(polecatDir, m.rig.Name)
 
-	// Unique branch per run - prevents drift from stale branches
-	// Use base36 encoding for shorter branch names (8 chars vs 13 digits)
-	branchName := fmt.Sprintf("polecat/%s-%s", name, strconv.FormatInt(time.Now().UnixMilli(), 36))
-
 	// Create polecat directory (polecats/<name>/)
-	if err := os.MkdirAll(polecatDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(polecatDir, 0755); err != nil {
 		return nil, fmt.Errorf("creating polecat dir: %w", err)
 	}
 
@@ -261,6 +355,9 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		return nil, fmt.Errorf("finding repo base: %w", err)
 	}
 
+	// Fresh branch per run - prevents drift from stale branches
+	branchName := m.freeBranch(repoGit, m.branchName(name, opts))
+
 	// Fetch latest from origin to ensure worktree starts from up-to-date code
 	if err := repoGit.Fetch("origin"); err != nil {
 		// Non-fatal - proceed with potentially stale code
@@ -275,8 +372,8 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	}
 	startPoint := fmt.Sprintf("origin/%s", defaultBranch)
 
-	// Always create fresh branch - unique name guarantees no collision
-	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
+	// Always create fresh branch - freeBranch cleared any stale one
+	// git worktree add -b polecat/<name>/<bead> <path> <startpoint>
 	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
 	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
 		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
@@ -288,7 +385,7 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	if _, err := os.Stat(agentsMDPath); os.IsNotExist(err) {
 		srcPath := filepath.Join(m.rig.Path, "mayor", "rig", "AGENTS.md")
 		if srcData, readErr := os.ReadFile(srcPath); readErr == nil {
-			if writeErr := os.WriteFile(agentsMDPath, srcData, 0644); writeErr != nil {
+			if writeErr := dryrun.WriteFile(agentsMDPath, srcData, 0644); writeErr != nil {
 				fmt.Printf("Warning: could not copy AGENTS.md: %v\n", writeErr)
 			}
 		}
@@ -340,6 +437,7 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	if err != nil {
 		// Non-fatal - log warning but continue
 		fmt.Printf("Warning: could not create agent bead: %v\n", err)
+		m.log.Warn("agent bead not created", logging.KeyPolecat, name, "error", err)
 	}
 
 	// Return polecat with working state (transient model: polecats are spawned with work)
@@ -354,6 +452,7 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	m.log.Info("polecat created", logging.KeyPolecat, name, logging.KeyBead, opts.HookBead, "branch", branchName)
 
 	return polecat, nil
 }
@@ -394,7 +493,7 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear bool) error {
 			}
 		} else {
 			// Fallback path: Check git directly (for polecats that haven't reported yet)
-			polecatGit := git.NewGit(clonePath)
+			polecatGit := m.clone(clonePath)
 			status, err := polecatGit.CheckUncommittedWork()
 			if err == nil && !status.Clean() {
 				// For backward compatibility: force only bypasses uncommitted changes, not stashes/unpushed
@@ -414,14 +513,15 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear bool) error {
 	repoGit, err := m.repoBase()
 	if err != nil {
 		// Fall back to direct removal if repo base not found
-		return os.RemoveAll(polecatDir)
+		return dryrun.RemoveAll(polecatDir)
 	}
+	branch, _ := m.clone(clonePath).CurrentBranch()
 
 	// Try to remove as a worktree first (use force flag for worktree removal too)
 	if err := repoGit.WorktreeRemove(clonePath, force); err != nil {
 		// Fall back to direct removal if worktree removal fails
 		// (e.g., if this is an old-style clone, not a worktree)
-		if removeErr := os.RemoveAll(clonePath); removeErr != nil {
+		if removeErr := dryrun.RemoveAll(clonePath); removeErr != nil {
 			return fmt.Errorf("removing clone path: %w", removeErr)
 		}
 	}
@@ -429,12 +529,17 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear bool) error {
 	// Also remove the parent polecat directory if it's now empty
 	// (for new structure: polecats/<name>/ contains only polecats/<name>/<rigname>/)
 	if polecatDir != clonePath {
-		_ = os.Remove(polecatDir) // Non-fatal: only removes if empty
+		_ = dryrun.Remove(polecatDir) // Non-fatal: only removes if empty
 	}
 
 	// Prune any stale worktree entries (non-fatal: cleanup only)
 	_ = repoGit.WorktreePrune()
 
+	// A merged branch has nothing left to keep; unmerged ones wait for gc
+	if strings.HasPrefix(branch, constants.BranchPolecatPrefix) && m.branchMerged(repoGit, branch) {
+		_ = repoGit.DeleteBranch(branch, true)
+	}
+
 	// Release name back to pool if it's a pooled name (non-fatal: state file update)
 	m.namePool.Release(name)
 	_ = m.namePool.Save()
@@ -447,6 +552,7 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear bool) error {
 			fmt.Printf("Warning: could not delete agent bead %s: %v\n", agentID, err)
 		}
 	}
+	m.log.Info("polecat removed", logging.KeyPolecat, name, "force", force, "nuclear", nuclear)
 
 	return nil
 }
@@ -502,7 +608,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 
 	// Get the old clone path (may be old or new structure)
 	oldClonePath := m.clonePath(name)
-	polecatGit := git.NewGit(oldClonePath)
+	polecatGit := m.clone(oldClonePath)
 
 	// New clone path uses new structure
 	polecatDir := m.polecatDir(name)
@@ -533,7 +639,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	// Remove the old worktree (use force for git worktree removal)
 	if err := repoGit.WorktreeRemove(oldClonePath, true); err != nil {
 		// Fall back to direct removal
-		if removeErr := os.RemoveAll(oldClonePath); removeErr != nil {
+		if removeErr := dryrun.RemoveAll(oldClonePath); removeErr != nil {
 			return nil, fmt.Errorf("removing old clone path: %w", removeErr)
 		}
 	}
@@ -545,7 +651,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	_ = repoGit.Fetch("origin")
 
 	// Ensure polecat directory exists for new structure
-	if err := os.MkdirAll(polecatDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(polecatDir, 0755); err != nil {
 		return nil, fmt.Errorf("creating polecat dir: %w", err)
 	}
 
@@ -560,8 +666,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	// Create fresh worktree with unique branch name, starting from origin's default branch
 	// Old branches are left behind - they're ephemeral (never pushed to origin)
 	// and will be cleaned up by garbage collection
-	// Use base36 encoding for shorter branch names (8 chars vs 13 digits)
-	branchName := fmt.Sprintf("polecat/%s-%s", name, strconv.FormatInt(time.Now().UnixMilli(), 36))
+	branchName := m.freeBranch(repoGit, m.branchName(name, opts))
 	if err := repoGit.WorktreeAddFromRef(newClonePath, branchName, startPoint); err != nil {
 		return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 	}
@@ -572,7 +677,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	if _, err := os.Stat(agentsMDPath); os.IsNotExist(err) {
 		srcPath := filepath.Join(m.rig.Path, "mayor", "rig", "AGENTS.md")
 		if srcData, readErr := os.ReadFile(srcPath); readErr == nil {
-			if writeErr := os.WriteFile(agentsMDPath, srcData, 0644); writeErr != nil {
+			if writeErr := dryrun.WriteFile(agentsMDPath, srcData, 0644); writeErr != nil {
 				fmt.Printf("Warning: could not copy AGENTS.md: %v\n", writeErr)
 			}
 		}
@@ -795,7 +900,7 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 	clonePath := m.clonePath(name)
 
 	// Get actual branch from worktree (branches are now timestamped)
-	polecatGit := git.NewGit(clonePath)
+	polecatGit := m.clone(clonePath)
 	branchName, err := polecatGit.CurrentBranch()
 	if err != nil {
 		// Fall back to old format if we can't read the branch
@@ -824,6 +929,8 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 	if issue != nil {
 		issueID = issue.ID
 		state = StateWorking
+	} else if IsWarm(m.rig.Path, name) {
+		state = StateWarm
 	}
 
 	return &Polecat{
@@ -894,6 +1001,55 @@ func (m *Manager) CleanupStaleBranches() (int, error) {
 	return deleted, nil
 }
 
+// WorktreeGCResult reports what GarbageCollectWorktrees cleaned up.
+type WorktreeGCResult struct {
+	Deleted []string // Polecat branches deleted
+	Kept    []string // Unmerged branches left alone (no worktree has them)
+}
+
+// GarbageCollectWorktrees prunes worktree records whose directories are
+// gone and deletes the polecat branches no worktree has checked out.
+// Merged branches are always deleted; unmerged ones only with unmerged.
+func (m *Manager) GarbageCollectWorktrees(unmerged bool) (*WorktreeGCResult, error) {
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return nil, fmt.Errorf("finding repo base: %w", err)
+	}
+	if err := repoGit.WorktreePrune(); err != nil {
+		return nil, fmt.Errorf("pruning worktrees: %w", err)
+	}
+
+	worktrees, err := repoGit.WorktreeList()
+	if err != nil {
+		return nil, fmt.Errorf("listing worktrees: %w", err)
+	}
+	inUse := make(map[string]bool)
+	for _, wt := range worktrees {
+		inUse[wt.Branch] = true
+	}
+
+	branches, err := repoGit.ListBranches(constants.BranchPolecatPrefix + "*")
+	if err != nil {
+		return nil, fmt.Errorf("listing branches: %w", err)
+	}
+	result := &WorktreeGCResult{}
+	for _, branch := range branches {
+		if inUse[branch] {
+			continue
+		}
+		if !unmerged && !m.branchMerged(repoGit, branch) {
+			result.Kept = append(result.Kept, branch)
+			continue
+		}
+		if err := repoGit.DeleteBranch(branch, true); err != nil {
+			fmt.Printf("Warning: could not delete branch %s: %v\n", branch, err)
+			continue
+		}
+		result.Deleted = append(result.Deleted, branch)
+	}
+	return result, nil
+}
+
 // StalenessInfo contains details about a polecat's staleness.
 type StalenessInfo struct {
 	Name            string
@@ -940,7 +1096,7 @@ func (m *Manager) DetectStalePolecats(threshold int) ([]*StalenessInfo, error) {
 		info.HasActiveSession = checkTmuxSession(sessionName)
 
 		// Check how far behind main
-		polecatGit := git.NewGit(p.ClonePath)
+		polecatGit := m.clone(p.ClonePath)
 		info.CommitsBehind = countCommitsBehind(polecatGit, defaultBranch)
 
 		// Check for uncommitted work
@@ -966,13 +1122,12 @@ func (m *Manager) DetectStalePolecats(threshold int) ([]*StalenessInfo, error) {
 
 // checkTmuxSession checks if a tmux session exists.
 func checkTmuxSession(sessionName string) bool {
-	// Use has-session command which returns 0 if session exists
-	cmd := exec.Command("tmux", "has-session", "-t", sessionName) //nolint:gosec // G204: sessionName is constructed internally
-	return cmd.Run() == nil
+	has, _ := tmux.NewTmux().HasSession(sessionName)
+	return has
 }
 
 // countCommitsBehind counts how many commits a worktree is behind origin/<defaultBranch>.
-func countCommitsBehind(g *git.Git, defaultBranch string) int {
+func countCommitsBehind(g vcs.Repo, defaultBranch string) int {
 	// Use rev-list to count commits: origin/main..HEAD shows commits ahead,
 	// HEAD..origin/main shows commits behind
 	remoteBranch := "origin/" + defaultBranch
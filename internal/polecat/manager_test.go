@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/git"
@@ -125,6 +126,98 @@ func TestPolecatDir(t *testing.T) {
 	}
 }
 
+func TestBranchName(t *testing.T) {
+	r := &rig.Rig{
+		Name: "test-rig",
+		Path: "/home/user/ai/test-rig",
+	}
+	m := NewManager(r, git.NewGit(r.Path))
+
+	tests := []struct {
+		opts AddOptions
+		want string
+	}{
+		{AddOptions{HookBead: "gt-abc"}, "polecat/Toast/gt-abc"},
+		{AddOptions{Branch: "fix/{bead}", HookBead: "gt-abc"}, "polecat/fix/gt-abc"},
+		{AddOptions{Branch: "polecat/{rig}-{name}"}, "polecat/test-rig-Toast"},
+		{AddOptions{Branch: "{bead}-{name}"}, "polecat/Toast"},
+	}
+	for _, tt := range tests {
+		if got := m.branchName("Toast", tt.opts); got != tt.want {
+			t.Errorf("branchName(%+v) = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+
+	// Without a bead each run gets a timestamped branch
+	got := m.branchName("Toast", AddOptions{})
+	if ts := strings.TrimPrefix(got, "polecat/Toast-"); ts == got || ts == "" {
+		t.Errorf("branchName without bead = %q, want polecat/Toast-<ts>", got)
+	}
+}
+
+func TestGarbageCollectWorktrees(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	root := t.TempDir()
+	mayorRig := filepath.Join(root, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = mayorRig
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-b", "main")
+	run("commit", "--allow-empty", "-m", "initial")
+	run("branch", "polecat/Toast/gt-1") // merged
+	run("checkout", "-b", "polecat/Nux/gt-2")
+	run("commit", "--allow-empty", "-m", "work in progress") // unmerged
+	run("checkout", "main")
+	run("branch", "polecat/Slit") // legacy name in the way of polecat/Slit/<bead>
+	run("remote", "add", "origin", mayorRig)
+	run("fetch", "origin")
+
+	r := &rig.Rig{Name: "rig", Path: root}
+	m := NewManager(r, git.NewGit(root))
+	repoGit := git.NewGit(mayorRig)
+
+	if got := m.freeBranch(repoGit, "polecat/Slit/gt-3"); got != "polecat/Slit/gt-3" {
+		t.Errorf("freeBranch = %q", got)
+	}
+	if exists, _ := repoGit.BranchExists("polecat/Slit"); exists {
+		t.Error("freeBranch should delete the stale polecat/Slit branch")
+	}
+	if got := m.freeBranch(repoGit, "main"); got == "main" {
+		t.Error("freeBranch should not reuse a checked-out branch")
+	}
+
+	result, err := m.GarbageCollectWorktrees(false)
+	if err != nil {
+		t.Fatalf("GarbageCollectWorktrees: %v", err)
+	}
+	if len(result.Deleted) != 1 || result.Deleted[0] != "polecat/Toast/gt-1" {
+		t.Errorf("Deleted = %v, want the merged branch", result.Deleted)
+	}
+	if len(result.Kept) != 1 || result.Kept[0] != "polecat/Nux/gt-2" {
+		t.Errorf("Kept = %v, want the unmerged branch", result.Kept)
+	}
+
+	result, err = m.GarbageCollectWorktrees(true)
+	if err != nil {
+		t.Fatalf("GarbageCollectWorktrees(unmerged): %v", err)
+	}
+	if len(result.Deleted) != 1 || result.Deleted[0] != "polecat/Nux/gt-2" {
+		t.Errorf("Deleted with unmerged = %v", result.Deleted)
+	}
+}
+
 func TestAssigneeID(t *testing.T) {
 	r := &rig.Rig{
 		Name: "test-rig",
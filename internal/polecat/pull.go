@@ -0,0 +1,58 @@
+package polecat
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvTier names the model tier a polecat's session runs (gt sling --tier),
+// so that a polecat pulling its next bead picks work for its tier.
+const EnvTier = "GT_TIER"
+
+// SessionBeadsFile lists the beads a polecat has finished in its current
+// session, one per line, when it pulls more work instead of exiting (see
+// scheduler.auto_pull). It goes with the polecat when it is nuked.
+const SessionBeadsFile = ".session-beads"
+
+func (m *Manager) sessionBeadsPath(name string) string {
+	return filepath.Join(m.polecatDir(name), SessionBeadsFile)
+}
+
+// SessionBeads returns the beads the polecat has finished this session.
+func (m *Manager) SessionBeads(name string) ([]string, error) {
+	data, err := os.ReadFile(m.sessionBeadsPath(name)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// AddSessionBead records a bead the polecat finished this session.
+func (m *Manager) AddSessionBead(name, beadID string) error {
+	if !m.exists(name) {
+		return ErrPolecatNotFound
+	}
+	f, err := os.OpenFile(m.sessionBeadsPath(name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: bead IDs only
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(beadID + "\n"); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// MoveToBead puts a polecat that finished its bead onto a fresh branch for
+// the next one. Unlike a claimed warm spare's, its old branch is kept:
+// the refinery still has to merge it.
+func (m *Manager) MoveToBead(name, beadID string) (*Polecat, error) {
+	if !m.exists(name) {
+		return nil, ErrPolecatNotFound
+	}
+	return m.moveToBead(name, beadID, false)
+}
@@ -0,0 +1,47 @@
+package polecat
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestMoveToBead(t *testing.T) {
+	m := newWarmTestRig(t)
+	if _, err := m.AddWithOptions("Toast", AddOptions{HookBead: "gt-abc"}); err != nil {
+		t.Fatalf("AddWithOptions: %v", err)
+	}
+	clone := git.NewGit(m.clonePath("Toast"))
+	first, _ := clone.CurrentBranch()
+
+	p, err := m.MoveToBead("Toast", "gt-def")
+	if err != nil {
+		t.Fatalf("MoveToBead: %v", err)
+	}
+	if branch, _ := clone.CurrentBranch(); branch != p.Branch || p.Branch == first {
+		t.Errorf("worktree on %q (was %q), want new branch %q", branch, first, p.Branch)
+	}
+	repo, err := m.repoBase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := repo.BranchExists(first); !exists {
+		t.Errorf("old branch %s deleted; the refinery still needs it", first)
+	}
+
+	if beads, _ := m.SessionBeads("Toast"); len(beads) != 0 {
+		t.Errorf("SessionBeads before any = %v", beads)
+	}
+	for _, id := range []string{"gt-abc", "gt-def"} {
+		if err := m.AddSessionBead("Toast", id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, _ := m.SessionBeads("Toast"); !reflect.DeepEqual(got, []string{"gt-abc", "gt-def"}) {
+		t.Errorf("SessionBeads = %v, want [gt-abc gt-def]", got)
+	}
+	if err := m.AddSessionBead("Missing", "gt-x"); err != ErrPolecatNotFound {
+		t.Errorf("AddSessionBead(Missing) = %v, want ErrPolecatNotFound", err)
+	}
+}
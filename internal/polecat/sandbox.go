@@ -0,0 +1,96 @@
+package polecat
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/sandbox"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// cloneDirs are the per-rig directories holding agents' clones. A
+// sandboxed polecat sees none of them except its own.
+var cloneDirs = []string{"polecats", "crew", filepath.Join("refinery", "rig"), filepath.Join("mayor", "rig")}
+
+// agentStatePaths are home-directory paths agent runtimes write to.
+var agentStatePaths = []string{".claude", ".claude.json", ".cache"}
+
+// sandboxPolicy confines a polecat to its clone, plus the shared state gt
+// and the agent runtime write to: beads, git metadata, logs and the
+// runtime's home-directory files.
+func (m *SessionManager) sandboxPolicy(polecat, workDir string, cfg *config.SandboxConfig) *sandbox.Policy {
+	townRoot := filepath.Dir(m.rig.Path)
+	home, _ := os.UserHomeDir()
+
+	writable := []string{
+		m.polecatDir(polecat),
+		beads.ResolveBeadsDir(m.rig.Path),
+		filepath.Join(townRoot, ".beads"),
+		logging.Dir(townRoot),
+		filepath.Join(m.rig.Path, ".repo.git"),
+		filepath.Join(m.rig.Path, "mayor", "rig", ".git"), // worktrees of the mayor's clone
+	}
+	if home != "" {
+		for _, p := range agentStatePaths {
+			writable = append(writable, filepath.Join(home, p))
+		}
+	}
+	for _, p := range cfg.Writable {
+		writable = append(writable, m.settingsPath(p))
+	}
+
+	// Hide every rig's clones; the polecats/ settings stay readable
+	// because the runtime finds its settings by walking up from the clone.
+	var hidden []string
+	if entries, err := os.ReadDir(townRoot); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			for _, dir := range cloneDirs {
+				hidden = append(hidden, filepath.Join(townRoot, e.Name(), dir))
+			}
+		}
+	}
+
+	return &sandbox.Policy{
+		WorkDir:     workDir,
+		Writable:    existing(writable),
+		Hidden:      existing(hidden),
+		ReadOnly:    existing([]string{filepath.Join(m.rig.Path, "polecats", ".claude")}),
+		DenyNetwork: cfg.DenyNetwork,
+		MemoryMB:    cfg.MemoryMB,
+		CPUs:        cfg.CPUs,
+		MaxProcs:    cfg.MaxProcs,
+	}
+}
+
+// settingsPath resolves a path from rig settings: relative paths are
+// relative to the rig and "~/" is the home directory.
+func (m *SessionManager) settingsPath(p string) string {
+	p = util.ExpandHome(p)
+	if !filepath.IsAbs(p) {
+		return filepath.Join(m.rig.Path, p)
+	}
+	return p
+}
+
+// existing filters out paths that don't exist; backends can only expose
+// or hide what is there.
+func existing(paths []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		if _, err := os.Lstat(p); err == nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -10,13 +10,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/authz"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dryrun"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // debugSession logs non-fatal errors during session startup when GT_DEBUG_SESSION=1.
@@ -54,15 +58,30 @@ type SessionStartOptions struct {
 	// Issue is an optional issue ID to work on.
 	Issue string
 
+	// Bead is the bead already on the polecat's hook (set at spawn). It
+	// names the session transcript; unlike Issue it is not hooked again.
+	Bead string
+
 	// Command overrides the default "claude" command.
 	Command string
 
+	// Runtime is "local" or "docker". If empty, the rig's container
+	// settings decide.
+	Runtime string
+
+	// Sandbox overrides the rig's sandbox profile ("off" runs the agent
+	// unconfined). The rig's limits still apply.
+	Sandbox string
+
 	// Account specifies the account handle to use (overrides default).
 	Account string
 
 	// RuntimeConfigDir is resolved config directory for the runtime account.
 	// If set, this is injected as an environment variable.
 	RuntimeConfigDir string
+
+	// Tier is the model tier the session runs, exported as GT_TIER.
+	Tier string
 }
 
 // SessionInfo contains information about a running polecat session.
@@ -178,16 +197,30 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && opts.RuntimeConfigDir != "" {
 		command = config.PrependEnv(command, map[string]string{runtimeConfig.Session.ConfigDirEnv: opts.RuntimeConfigDir})
 	}
+	// Carry the trace context so the agent's gt commands join this trace
+	traceparent := telemetry.Traceparent()
+	if traceparent != "" {
+		command = config.PrependEnv(command, map[string]string{telemetry.EnvTraceparent: traceparent})
+	}
 
-	// Create session with command directly to avoid send-keys race condition.
-	// See: https://github.com/anthropics/gastown/issues/280
-	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
-		return fmt.Errorf("creating session: %w", err)
+	// Record the session transcript (non-fatal: the session runs without it)
+	townRoot := filepath.Dir(m.rig.Path)
+	bead := opts.Issue
+	if bead == "" {
+		bead = opts.Bead
+	}
+	var record *transcript.Entry
+	if !dryrun.Enabled() {
+		record, err = transcript.Begin(townRoot, m.rig.Name, polecat, sessionID, bead)
+		if err != nil {
+			debugSession("transcript.Begin", err)
+			record = nil
+		} else {
+			command = config.PrependEnv(command, map[string]string{transcript.EnvVar: record.Path})
+		}
 	}
 
-	// Set environment (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	townRoot := filepath.Dir(m.rig.Path)
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:             "polecat",
 		Rig:              m.rig.Name,
@@ -197,6 +230,40 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		RuntimeConfigDir: opts.RuntimeConfigDir,
 		BeadsNoDaemon:    true,
 	})
+	if traceparent != "" {
+		envVars[telemetry.EnvTraceparent] = traceparent
+	}
+	if record != nil {
+		envVars[transcript.EnvVar] = record.Path
+	}
+	if opts.Tier != "" {
+		envVars[EnvTier] = opts.Tier
+		command = config.PrependEnv(command, map[string]string{EnvTier: opts.Tier})
+	}
+	// The agent process gets its role token at spawn, not just the tmux
+	// session, since gt trusts the token over GT_ROLE.
+	if token := envVars[authz.EnvToken]; token != "" {
+		command = config.PrependEnv(command, map[string]string{authz.EnvToken: token})
+	}
+
+	// Run the agent in a container or sandbox per the rig's settings.
+	// Failing here beats starting an agent the rig asked to be confined
+	// without confinement.
+	command, err = m.runtimeCommand(polecat, workDir, command, opts.Runtime, opts.Sandbox, envVars)
+	if err != nil {
+		return fmt.Errorf("preparing agent runtime: %w", err)
+	}
+
+	// Create session with command directly to avoid send-keys race condition.
+	// See: https://github.com/anthropics/gastown/issues/280
+	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+	if record != nil {
+		debugSession("PipePaneToFile", m.tmux.PipePaneToFile(sessionID, record.Path))
+	}
+
+	// Set environment (non-fatal: session works without these)
 	for k, v := range envVars {
 		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
 	}
@@ -217,6 +284,11 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
 	debugSession("SetPaneDiedHook", m.tmux.SetPaneDiedHook(sessionID, agentID))
 
+	// Nothing was started, so there is nothing to wait for or nudge
+	if dryrun.Enabled() {
+		return nil
+	}
+
 	// Wait for Claude to start (non-fatal)
 	debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
 
@@ -272,6 +344,7 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 	if err := m.tmux.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	m.removeContainer(sessionID)
 
 	return nil
 }
@@ -280,6 +353,9 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 func (m *SessionManager) syncBeads(workDir string) error {
 	cmd := exec.Command("bd", "sync")
 	cmd.Dir = workDir
+	if dryrun.Command(cmd) {
+		return nil
+	}
 	return cmd.Run()
 }
 
@@ -454,6 +530,9 @@ func (m *SessionManager) hookIssue(issueID, agentID, workDir string) error {
 	cmd := exec.Command("bd", "update", issueID, "--status=hooked", "--assignee="+agentID) //nolint:gosec
 	cmd.Dir = workDir
 	cmd.Stderr = os.Stderr
+	if dryrun.Command(cmd) {
+		return nil
+	}
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("bd update failed: %w", err)
 	}
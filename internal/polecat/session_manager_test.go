@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -190,3 +191,60 @@ func TestPolecatCommandFormat(t *testing.T) {
 		t.Error("GT_ROLE must be 'polecat', not 'mayor' or 'crew'")
 	}
 }
+
+func TestSandboxPolicy(t *testing.T) {
+	town := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	rigPath := filepath.Join(town, "gastown")
+	clone := filepath.Join(rigPath, "polecats", "Toast", "gastown")
+	for _, dir := range []string{clone, filepath.Join(rigPath, ".beads"), filepath.Join(rigPath, "polecats", ".claude"),
+		filepath.Join(rigPath, "crew"), filepath.Join(town, "other", "polecats"), filepath.Join(rigPath, "scratch")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+
+	m := NewSessionManager(tmux.NewTmux(), &rig.Rig{Name: "gastown", Path: rigPath})
+	p := m.sandboxPolicy("Toast", clone, &config.SandboxConfig{Writable: []string{"scratch", "missing"}})
+
+	if p.WorkDir != clone {
+		t.Errorf("WorkDir = %s, want %s", p.WorkDir, clone)
+	}
+	want := []string{filepath.Join(rigPath, "polecats", "Toast"), filepath.Join(rigPath, ".beads"), filepath.Join(rigPath, "scratch")}
+	if strings.Join(p.Writable, ",") != strings.Join(want, ",") {
+		t.Errorf("Writable = %v, want %v", p.Writable, want)
+	}
+	want = []string{filepath.Join(rigPath, "polecats"), filepath.Join(rigPath, "crew"), filepath.Join(town, "other", "polecats")}
+	if strings.Join(p.Hidden, ",") != strings.Join(want, ",") {
+		t.Errorf("Hidden = %v, want %v", p.Hidden, want)
+	}
+	if len(p.ReadOnly) != 1 || p.ReadOnly[0] != filepath.Join(rigPath, "polecats", ".claude") {
+		t.Errorf("ReadOnly = %v, want the polecats settings dir", p.ReadOnly)
+	}
+}
+
+func TestResolveRuntime(t *testing.T) {
+	withContainer := &config.RigSettings{Container: &config.ContainerConfig{Image: "agent"}}
+	byDefault := &config.RigSettings{Container: &config.ContainerConfig{Image: "agent", Default: true}}
+
+	tests := []struct {
+		settings  *config.RigSettings
+		requested string
+		want      string
+		wantErr   bool
+	}{
+		{&config.RigSettings{}, "", "local", false},
+		{withContainer, "", "local", false},
+		{byDefault, "", "docker", false},
+		{byDefault, "local", "local", false},
+		{withContainer, "docker", "docker", false},
+		{&config.RigSettings{}, "docker", "", true},
+		{withContainer, "vm", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ResolveRuntime(tt.settings, tt.requested)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ResolveRuntime(%+v, %q) = %q, %v; want %q (error %v)", tt.settings.Container, tt.requested, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
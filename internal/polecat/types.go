@@ -13,7 +13,8 @@ import "time"
 //
 // The distinction matters: zombies completed their work; stalled polecats did not.
 // Neither is "idle" - stalled polecats are SUPPOSED to be working, zombies are
-// SUPPOSED to be dead. There is no idle pool where polecats wait for work.
+// SUPPOSED to be dead. The one exception is a rig's warm spares (StateWarm),
+// started ahead of demand so a sling doesn't wait for context loading.
 //
 // Note: These are SESSION states. The polecat IDENTITY (CV chain, mailbox, work
 // history) persists across sessions. A stalled or zombie session doesn't destroy
@@ -26,7 +27,7 @@ type State string
 const (
 	// StateWorking means the polecat session is actively working on an issue.
 	// This is the initial and primary state for transient polecats.
-	// Working is the ONLY healthy operating state - there is no idle pool.
+	// Working is the healthy operating state - apart from warm spares there is no idle pool.
 	StateWorking State = "working"
 
 	// StateDone means the polecat has completed its assigned work and called
@@ -40,6 +41,10 @@ const (
 	// Different from "stalled" (detected externally when session stops working).
 	StateStuck State = "stuck"
 
+	// StateWarm means the polecat is a warm spare: its session is primed
+	// with rig context and waits for gt sling to hand it work.
+	StateWarm State = "warm"
+
 	// StateActive is deprecated: use StateWorking.
 	// Kept only for backward compatibility with existing data.
 	StateActive State = "active"
@@ -0,0 +1,148 @@
+package polecat
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+// WarmFile marks a warm spare: a polecat whose session was started with
+// the rig's context but no work, waiting in polecats/<name>/ for a sling
+// to claim it. Claiming removes the mark.
+const WarmFile = ".warm"
+
+// ErrNoWarm means a rig has no live warm spare to claim.
+var ErrNoWarm = errors.New("no warm polecat available")
+
+// IsWarm reports whether a polecat is an unclaimed warm spare.
+func IsWarm(rigPath, name string) bool {
+	_, err := os.Stat(filepath.Join(rigPath, "polecats", name, WarmFile))
+	return err == nil
+}
+
+func (m *Manager) warmPath(name string) string {
+	return filepath.Join(m.polecatDir(name), WarmFile)
+}
+
+// MarkWarm marks a polecat as a warm spare.
+func (m *Manager) MarkWarm(name string) error {
+	if !m.exists(name) {
+		return ErrPolecatNotFound
+	}
+	return os.WriteFile(m.warmPath(name), []byte(time.Now().UTC().Format(time.RFC3339)+"\n"), 0644) //nolint:gosec // G306: marker holds no secrets
+}
+
+// UnmarkWarm turns a warm spare back into an ordinary polecat, as when
+// its session has died.
+func (m *Manager) UnmarkWarm(name string) error {
+	if err := os.Remove(m.warmPath(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ListWarm returns the rig's warm spares, longest waiting first.
+func (m *Manager) ListWarm() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(m.rig.Path, "polecats"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading polecats dir: %w", err)
+	}
+	since := make(map[string]string)
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		data, err := os.ReadFile(m.warmPath(e.Name())) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			continue
+		}
+		since[e.Name()] = strings.TrimSpace(string(data))
+		names = append(names, e.Name())
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		if since[names[i]] != since[names[j]] {
+			return since[names[i]] < since[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names, nil
+}
+
+// ClaimWarm hands the longest-waiting live warm spare the given bead: it
+// is unmarked and its worktree moved to a fresh branch for the bead on
+// the rig's latest default branch, since it may have waited a while. The
+// session is left running, primed, for the caller to send the work to.
+// alive reports whether a spare's session is still running; dead spares
+// are skipped. Returns ErrNoWarm when no spare is available.
+func (m *Manager) ClaimWarm(beadID string, alive func(name string) bool) (*Polecat, error) {
+	lock := flock.New(filepath.Join(m.rig.Path, "polecats", ".warm.lock"))
+	if err := os.MkdirAll(filepath.Dir(lock.Path()), 0755); err != nil {
+		return nil, fmt.Errorf("creating polecats dir: %w", err)
+	}
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking warm pool: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	names, err := m.ListWarm()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if !alive(name) {
+			continue
+		}
+		if err := m.UnmarkWarm(name); err != nil {
+			return nil, fmt.Errorf("claiming %s: %w", name, err)
+		}
+		return m.moveToBead(name, beadID, true)
+	}
+	return nil, ErrNoWarm
+}
+
+// moveToBead moves a polecat's worktree onto a new branch for the bead,
+// cut from origin's default branch. dropOld deletes the branch it was on.
+func (m *Manager) moveToBead(name, beadID string, dropOld bool) (*Polecat, error) {
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return nil, fmt.Errorf("finding repo base: %w", err)
+	}
+	if err := repoGit.Fetch("origin"); err != nil {
+		// Non-fatal - proceed with potentially stale code
+		fmt.Printf("Warning: could not fetch origin: %v\n", err)
+	}
+
+	clonePath := m.clonePath(name)
+	clone := m.clone(clonePath)
+	oldBranch, _ := clone.CurrentBranch()
+	branch := m.freeBranch(repoGit, m.branchName(name, AddOptions{HookBead: beadID}))
+	if err := clone.CheckoutNewBranch(branch, "origin/"+m.defaultBranch()); err != nil {
+		return nil, fmt.Errorf("moving %s to %s: %w", name, branch, err)
+	}
+	if dropOld && oldBranch != "" && oldBranch != branch {
+		_ = repoGit.DeleteBranch(oldBranch, true)
+	}
+
+	m.log.Info("polecat moved to bead", logging.KeyPolecat, name, logging.KeyBead, beadID, "branch", branch)
+	now := time.Now()
+	return &Polecat{
+		Name:      name,
+		Rig:       m.rig.Name,
+		State:     StateWorking,
+		ClonePath: clonePath,
+		Branch:    branch,
+		Issue:     beadID,
+		UpdatedAt: now,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package polecat
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// newWarmTestRig returns a manager for a rig whose mayor/rig is its own
+// origin, with one commit on main.
+func newWarmTestRig(t *testing.T) *Manager {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	root := t.TempDir()
+	mayorRig := filepath.Join(root, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"commit", "--allow-empty", "-m", "init"},
+		{"remote", "add", "origin", mayorRig},
+		{"fetch", "origin"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = mayorRig
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return NewManager(&rig.Rig{Name: "rig", Path: root}, git.NewGit(root))
+}
+
+func TestWarmPool(t *testing.T) {
+	m := newWarmTestRig(t)
+	for _, name := range []string{"Toast", "Nux"} {
+		if _, err := m.AddWithOptions(name, AddOptions{}); err != nil {
+			t.Fatalf("AddWithOptions(%s): %v", name, err)
+		}
+	}
+	if err := m.MarkWarm("Missing"); err != ErrPolecatNotFound {
+		t.Errorf("MarkWarm(Missing) = %v, want ErrPolecatNotFound", err)
+	}
+	if err := m.MarkWarm("Toast"); err != nil {
+		t.Fatal(err)
+	}
+	// Marks are timestamped to the second; write an earlier one for Nux
+	if err := os.WriteFile(m.warmPath("Nux"), []byte("2026-01-01T00:00:00Z\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	warm, err := m.ListWarm()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warm) != 2 || warm[0] != "Nux" || warm[1] != "Toast" {
+		t.Fatalf("ListWarm = %v, want [Nux Toast]", warm)
+	}
+	if !IsWarm(m.rig.Path, "Toast") {
+		t.Error("IsWarm(Toast) = false")
+	}
+
+	// Nux's session is dead, so Toast is claimed
+	alive := func(name string) bool { return name == "Toast" }
+	p, err := m.ClaimWarm("gt-abc", alive)
+	if err != nil {
+		t.Fatalf("ClaimWarm: %v", err)
+	}
+	if p.Name != "Toast" || p.Branch != "polecat/Toast/gt-abc" {
+		t.Errorf("claimed %s on %s, want Toast on polecat/Toast/gt-abc", p.Name, p.Branch)
+	}
+	if branch, _ := git.NewGit(p.ClonePath).CurrentBranch(); branch != p.Branch {
+		t.Errorf("worktree on %q, want %q", branch, p.Branch)
+	}
+	if IsWarm(m.rig.Path, "Toast") {
+		t.Error("Toast still warm after claim")
+	}
+
+	if _, err := m.ClaimWarm("gt-def", alive); err != ErrNoWarm {
+		t.Errorf("second ClaimWarm = %v, want ErrNoWarm", err)
+	}
+}
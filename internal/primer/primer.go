@@ -0,0 +1,491 @@
+// Package primer builds a compact "repo primer" for each rig: the layout
+// of its repository, the files worth reading first, the conventions its
+// manifests and docs reveal, and how to build and test it. Polecats are
+// primed with it so each one doesn't spend its first tokens rediscovering
+// the repository.
+//
+// A primer is built from the tree of the rig's default branch, read
+// straight from git without a checkout, and cached per rig at
+// <town>/.runtime/primers/<rig>.json with the commit it describes. It is
+// rebuilt when the branch moves.
+package primer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DirName is the primer cache directory within the town's .runtime
+// directory.
+const DirName = "primers"
+
+// MaxBytes caps a primer's text.
+const MaxBytes = 6000
+
+// Limits on each part of a primer.
+const (
+	maxTopDirs    = 20
+	maxSubdirs    = 12
+	maxKeyFiles   = 15
+	maxExcerpt    = 25 // Lines of README/CONTRIBUTING quoted
+	maxExcerptLen = 160
+)
+
+// Primer is a rig's cached repo primer.
+type Primer struct {
+	Rig    string    `json:"rig"`
+	Commit string    `json:"commit"`
+	Built  time.Time `json:"built"`
+	Text   string    `json:"text"`
+}
+
+// Source is the repository a primer is built from.
+type Source struct {
+	Rig     string
+	Commit  string
+	Files   []string                          // Every file path in the tree
+	Read    func(path string) (string, error) // A file's content
+	TestCmd string                            // The rig's configured test command, if any
+}
+
+// languages names the languages counted in a primer, by file extension.
+var languages = map[string]string{
+	".go": "Go", ".py": "Python", ".ts": "TypeScript", ".tsx": "TypeScript",
+	".js": "JavaScript", ".jsx": "JavaScript", ".rs": "Rust", ".java": "Java",
+	".kt": "Kotlin", ".rb": "Ruby", ".c": "C", ".h": "C", ".cc": "C++",
+	".cpp": "C++", ".cs": "C#", ".swift": "Swift", ".php": "PHP",
+	".scala": "Scala", ".ex": "Elixir", ".exs": "Elixir", ".sh": "Shell",
+	".md": "Markdown", ".sql": "SQL", ".proto": "Protobuf",
+}
+
+// keyFileNames are top-level files worth reading before changing
+// anything, in the order listed.
+var keyFileNames = []string{
+	"README.md", "README", "CONTRIBUTING.md", "AGENTS.md", "CLAUDE.md",
+	"ARCHITECTURE.md", "Makefile", "justfile", "go.mod", "package.json",
+	"Cargo.toml", "pyproject.toml", "setup.py", "requirements.txt",
+	"Gemfile", "pom.xml", "build.gradle", ".golangci.yml", ".editorconfig",
+}
+
+// Build renders a primer from a repository tree.
+func Build(src Source) *Primer {
+	var sb strings.Builder
+	short := src.Commit
+	if len(short) > 8 {
+		short = short[:8]
+	}
+	fmt.Fprintf(&sb, "## Repo Primer: %s @ %s\n\n", src.Rig, short)
+	sb.WriteString("What this repository looks like, so you can start without exploring it.\n")
+
+	top := make(map[string]bool)
+	for _, f := range src.Files {
+		if !strings.Contains(f, "/") {
+			top[f] = true
+		}
+	}
+
+	if langs := languageSummary(src.Files); langs != "" {
+		fmt.Fprintf(&sb, "\nLanguages: %s\n", langs)
+	}
+
+	sb.WriteString("\n### Layout\n")
+	sb.WriteString(layout(src.Files))
+
+	var keys []string
+	for _, name := range keyFileNames {
+		if top[name] {
+			keys = append(keys, name)
+		}
+	}
+	for _, f := range src.Files {
+		if len(keys) >= maxKeyFiles {
+			break
+		}
+		if strings.HasPrefix(f, "docs/") && strings.EqualFold(path.Ext(f), ".md") && strings.Count(f, "/") == 1 {
+			keys = append(keys, f)
+		}
+	}
+	if len(keys) > 0 {
+		sb.WriteString("\n### Key files\n")
+		for _, k := range keys {
+			sb.WriteString("- " + k + "\n")
+		}
+	}
+
+	if conv := conventions(src, top); len(conv) > 0 {
+		sb.WriteString("\n### Conventions\n")
+		for _, c := range conv {
+			sb.WriteString("- " + c + "\n")
+		}
+	}
+
+	if cmds := buildCommands(src, top); len(cmds) > 0 {
+		sb.WriteString("\n### Build and test\n")
+		for _, c := range cmds {
+			sb.WriteString("- " + c + "\n")
+		}
+	}
+
+	for _, name := range []string{"README.md", "README", "CONTRIBUTING.md"} {
+		if !top[name] || src.Read == nil {
+			continue
+		}
+		if ex := excerpt(src.Read, name); ex != "" {
+			fmt.Fprintf(&sb, "\n### %s (excerpt)\n%s", name, ex)
+		}
+	}
+
+	text := sb.String()
+	if len(text) > MaxBytes {
+		text = text[:MaxBytes]
+		if i := strings.LastIndex(text, "\n"); i > 0 {
+			text = text[:i+1]
+		}
+		text += "[primer truncated]\n"
+	}
+	return &Primer{Rig: src.Rig, Commit: src.Commit, Built: time.Now(), Text: text}
+}
+
+// languageSummary counts files per language, most first.
+func languageSummary(files []string) string {
+	counts := make(map[string]int)
+	for _, f := range files {
+		if l, ok := languages[strings.ToLower(path.Ext(f))]; ok {
+			counts[l]++
+		}
+	}
+	names := make([]string, 0, len(counts))
+	for l := range counts {
+		names = append(names, l)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, l := range names {
+		parts[i] = fmt.Sprintf("%s (%d files)", l, counts[l])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// layout lists top-level directories with their file counts and
+// subdirectories, then the top-level files.
+func layout(files []string) string {
+	counts := make(map[string]int)
+	subdirs := make(map[string]map[string]bool)
+	var topFiles []string
+	for _, f := range files {
+		dir, rest, ok := strings.Cut(f, "/")
+		if !ok {
+			topFiles = append(topFiles, f)
+			continue
+		}
+		counts[dir]++
+		if sub, _, ok := strings.Cut(rest, "/"); ok {
+			if subdirs[dir] == nil {
+				subdirs[dir] = make(map[string]bool)
+			}
+			subdirs[dir][sub] = true
+		}
+	}
+	dirs := make([]string, 0, len(counts))
+	for d := range counts {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+
+	var sb strings.Builder
+	for i, d := range dirs {
+		if i == maxTopDirs {
+			fmt.Fprintf(&sb, "- ...and %d more directories\n", len(dirs)-maxTopDirs)
+			break
+		}
+		line := fmt.Sprintf("- %s/ (%d files)", d, counts[d])
+		if subs := sortedKeys(subdirs[d]); len(subs) > 0 {
+			if len(subs) > maxSubdirs {
+				subs = append(subs[:maxSubdirs], "...")
+			}
+			line += ": " + strings.Join(subs, ", ")
+		}
+		sb.WriteString(line + "\n")
+	}
+	if len(topFiles) > 0 {
+		sort.Strings(topFiles)
+		if len(topFiles) > maxTopDirs {
+			topFiles = append(topFiles[:maxTopDirs], "...")
+		}
+		sb.WriteString("- Top-level files: " + strings.Join(topFiles, ", ") + "\n")
+	}
+	return sb.String()
+}
+
+// conventions notes what the manifests and file layout say about how the
+// code is organized.
+func conventions(src Source, top map[string]bool) []string {
+	var notes []string
+	if top["go.mod"] && src.Read != nil {
+		if mod, err := src.Read("go.mod"); err == nil {
+			for _, line := range strings.Split(mod, "\n") {
+				if m, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+					notes = append(notes, "Go module "+strings.TrimSpace(m)+"; import its packages by that path")
+					break
+				}
+			}
+		}
+	}
+
+	var goTests, testsDir, specFiles, pyTests int
+	for _, f := range src.Files {
+		base := path.Base(f)
+		switch {
+		case strings.HasSuffix(base, "_test.go"):
+			goTests++
+		case strings.HasPrefix(f, "tests/") || strings.HasPrefix(f, "test/") || strings.Contains(f, "/tests/"):
+			testsDir++
+		case strings.Contains(base, ".test.") || strings.Contains(base, ".spec."):
+			specFiles++
+		case strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"):
+			pyTests++
+		}
+	}
+	if goTests > 0 {
+		notes = append(notes, fmt.Sprintf("Go tests sit next to the code they test (*_test.go, %d files)", goTests))
+	}
+	if specFiles > 0 {
+		notes = append(notes, fmt.Sprintf("JS/TS tests are *.test.* / *.spec.* files (%d)", specFiles))
+	}
+	if pyTests > 0 {
+		notes = append(notes, fmt.Sprintf("Python tests are test_*.py files (%d)", pyTests))
+	}
+	if testsDir > 0 {
+		notes = append(notes, fmt.Sprintf("Tests also live under tests/ directories (%d files)", testsDir))
+	}
+	for _, cfg := range []struct{ file, note string }{
+		{".golangci.yml", "Lint with golangci-lint (.golangci.yml)"},
+		{".editorconfig", "Formatting rules in .editorconfig"},
+		{".prettierrc", "Formatted with Prettier (.prettierrc)"},
+		{".eslintrc.json", "Linted with ESLint (.eslintrc.json)"},
+		{"AGENTS.md", "Agent instructions in AGENTS.md: read it before changing code"},
+	} {
+		if top[cfg.file] {
+			notes = append(notes, cfg.note)
+		}
+	}
+	return notes
+}
+
+// buildCommands lists how to test and build the repository: the rig's
+// configured test command first, then what its manifests imply.
+func buildCommands(src Source, top map[string]bool) []string {
+	var cmds []string
+	if src.TestCmd != "" {
+		cmds = append(cmds, "Test (what the refinery runs before merging): "+src.TestCmd)
+	}
+	if top["Makefile"] && src.Read != nil {
+		if mk, err := src.Read("Makefile"); err == nil {
+			if targets := makeTargets(mk); len(targets) > 0 {
+				cmds = append(cmds, "Make targets: "+strings.Join(targets, ", "))
+			}
+		}
+	}
+	if top["package.json"] && src.Read != nil {
+		if data, err := src.Read("package.json"); err == nil {
+			var pkg struct {
+				Scripts map[string]string `json:"scripts"`
+			}
+			if json.Unmarshal([]byte(data), &pkg) == nil && len(pkg.Scripts) > 0 {
+				cmds = append(cmds, "npm scripts: "+strings.Join(sortedKeys(boolSet(pkg.Scripts)), ", "))
+			}
+		}
+	}
+	if src.TestCmd == "" {
+		switch {
+		case top["go.mod"]:
+			cmds = append(cmds, "Test: go test ./...")
+		case top["Cargo.toml"]:
+			cmds = append(cmds, "Test: cargo test")
+		case top["pyproject.toml"] || top["setup.py"]:
+			cmds = append(cmds, "Test: pytest")
+		case top["package.json"]:
+			cmds = append(cmds, "Test: npm test")
+		}
+	}
+	return cmds
+}
+
+// makeTargets returns a Makefile's plain targets, in order.
+func makeTargets(makefile string) []string {
+	var targets []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(makefile, "\n") {
+		if line == "" || line[0] == '\t' || line[0] == '#' || line[0] == '.' {
+			continue
+		}
+		name, rest, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(rest, "=") || strings.ContainsAny(name, " $%=") || seen[name] {
+			continue
+		}
+		seen[name] = true
+		targets = append(targets, name)
+		if len(targets) == maxSubdirs {
+			break
+		}
+	}
+	return targets
+}
+
+// excerpt quotes the start of a document, skipping blank lines and
+// badges.
+func excerpt(read func(string) (string, error), name string) string {
+	data, err := read(name)
+	if err != nil {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "[![") || strings.HasPrefix(line, "<img") {
+			continue
+		}
+		if len(line) > maxExcerptLen {
+			line = line[:maxExcerptLen] + "..."
+		}
+		lines = append(lines, line)
+		if len(lines) == maxExcerpt {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func boolSet(m map[string]string) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for k := range m {
+		set[k] = true
+	}
+	return set
+}
+
+// Path returns a rig's cached primer file.
+func Path(townRoot, rig string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, DirName, rig+".json")
+}
+
+// Load returns a rig's cached primer, or nil if none is cached.
+func Load(townRoot, rig string) (*Primer, error) {
+	data, err := os.ReadFile(Path(townRoot, rig)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p Primer
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing primer of %s: %w", rig, err)
+	}
+	return &p, nil
+}
+
+// Save caches a rig's primer.
+func Save(townRoot string, p *Primer) error {
+	if p.Rig == "" || strings.ContainsAny(p.Rig, `/\`) {
+		return fmt.Errorf("invalid rig name %q", p.Rig)
+	}
+	if err := os.MkdirAll(filepath.Dir(Path(townRoot, p.Rig)), 0755); err != nil {
+		return fmt.Errorf("creating primer directory: %w", err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(Path(townRoot, p.Rig), data, 0644)
+}
+
+// ErrNoRepo is returned by Ensure for a rig without a git repository to
+// read (a jj rig, or one not set up yet).
+var ErrNoRepo = errors.New("no git repository to build a primer from")
+
+// Ensure returns a rig's primer for the current tip of its default
+// branch, rebuilding and caching it when the branch has moved since the
+// cached one was built. rigPath is the rig's directory.
+func Ensure(townRoot, rig, rigPath, branch, testCmd string) (*Primer, error) {
+	repo, ref := repoAt(rigPath, branch)
+	if repo == nil {
+		return nil, ErrNoRepo
+	}
+	commit, err := repo.Rev(ref)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", ref, err)
+	}
+	if cached, err := Load(townRoot, rig); err == nil && cached != nil && cached.Commit == commit {
+		return cached, nil
+	}
+
+	files, err := repo.ListTree(commit)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", ref, err)
+	}
+	p := Build(Source{
+		Rig:     rig,
+		Commit:  commit,
+		Files:   files,
+		Read:    func(name string) (string, error) { return repo.ShowFile(commit, name) },
+		TestCmd: testCmd,
+	})
+	if err := Save(townRoot, p); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// repoAt finds the rig's repository and the ref of its default branch:
+// the shared bare repo's origin/<branch>, else the mayor's clone.
+func repoAt(rigPath, branch string) (*git.Git, string) {
+	if branch == "" {
+		branch = "main"
+	}
+	bare := filepath.Join(rigPath, ".repo.git")
+	if info, err := os.Stat(bare); err == nil && info.IsDir() {
+		g := git.NewGitWithDir(bare, "")
+		if _, err := g.Rev("origin/" + branch); err == nil {
+			return g, "origin/" + branch
+		}
+		return g, branch
+	}
+	mayor := filepath.Join(rigPath, "mayor", "rig")
+	if _, err := os.Stat(filepath.Join(mayor, ".git")); err == nil {
+		g := git.NewGit(mayor)
+		if _, err := g.Rev("origin/" + branch); err == nil {
+			return g, "origin/" + branch
+		}
+		return g, "HEAD"
+	}
+	return nil, ""
+}
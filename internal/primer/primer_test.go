@@ -0,0 +1,97 @@
+package primer
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	contents := map[string]string{
+		"README.md": "[![build](x)](y)\n# Widgets\n\nWidgets does things.\n",
+		"go.mod":    "module example.com/widgets\n\ngo 1.24\n",
+		"Makefile":  "VERSION := 1\n.PHONY: build\nbuild:\n\tgo build ./...\ntest: build\n\tgo test ./...\n",
+	}
+	p := Build(Source{
+		Rig:    "widgets",
+		Commit: "0123456789abcdef",
+		Files: []string{
+			"README.md", "go.mod", "Makefile", "AGENTS.md",
+			"cmd/widgets/main.go",
+			"internal/store/store.go", "internal/store/store_test.go",
+			"internal/api/api.go",
+			"docs/design.md",
+		},
+		Read: func(name string) (string, error) {
+			if c, ok := contents[name]; ok {
+				return c, nil
+			}
+			return "", fmt.Errorf("no %s", name)
+		},
+		TestCmd: "make test",
+	})
+
+	for _, want := range []string{
+		"## Repo Primer: widgets @ 01234567",
+		"Languages: Go (4 files), Markdown (3 files)",
+		"- internal/ (3 files): api, store",
+		"- AGENTS.md",
+		"- docs/design.md",
+		"Go module example.com/widgets",
+		"*_test.go, 1 files",
+		"Test (what the refinery runs before merging): make test",
+		"Make targets: build, test",
+		"# Widgets\nWidgets does things.",
+	} {
+		if !strings.Contains(p.Text, want) {
+			t.Errorf("primer missing %q:\n%s", want, p.Text)
+		}
+	}
+	if strings.Contains(p.Text, "[![build]") || strings.Contains(p.Text, "VERSION") {
+		t.Errorf("primer includes badges or make variables:\n%s", p.Text)
+	}
+}
+
+func TestEnsureRebuildsWhenBranchMoves(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	town := t.TempDir()
+	rigPath := filepath.Join(town, "widgets")
+	clone := filepath.Join(rigPath, "mayor", "rig")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", clone, "-c", "user.name=t", "-c", "user.email=t@t"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if out, err := exec.Command("git", "init", "-q", "-b", "main", clone).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	git("commit", "-q", "--allow-empty", "-m", "empty")
+
+	first, err := Ensure(town, "widgets", rigPath, "main", "")
+	if err != nil {
+		t.Fatalf("Ensure: %v", err)
+	}
+	again, err := Ensure(town, "widgets", rigPath, "main", "")
+	if err != nil || !again.Built.Equal(first.Built) {
+		t.Errorf("Ensure rebuilt an up-to-date primer (%v)", err)
+	}
+
+	git("commit", "-q", "--allow-empty", "-m", "second")
+	moved, err := Ensure(town, "widgets", rigPath, "main", "")
+	if err != nil {
+		t.Fatalf("Ensure: %v", err)
+	}
+	if moved.Commit == first.Commit {
+		t.Errorf("primer not rebuilt after the branch moved")
+	}
+
+	if _, err := Ensure(town, "other", filepath.Join(town, "other"), "main", ""); err != ErrNoRepo {
+		t.Errorf("Ensure without a repo = %v, want ErrNoRepo", err)
+	}
+}
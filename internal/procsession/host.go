@@ -0,0 +1,138 @@
+package procsession
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// inputPoll is how often the host checks for input to forward.
+const inputPoll = 100 * time.Millisecond
+
+// HostManager returns the manager the session host was started by.
+func HostManager() *Manager {
+	if dir := os.Getenv("GT_SESSION_DIR"); dir != "" {
+		return NewManagerAt(dir)
+	}
+	return NewManager()
+}
+
+// Host runs a session's command until it exits. It is the body of
+// 'gt session-host' and expects the session log as its stdout.
+func (m *Manager) Host(name string) error {
+	s, err := m.load(name)
+	if err != nil {
+		return err
+	}
+
+	exports, _ := SplitExports(s.Command)
+	cmd := shellCommand(s.Command)
+	cmd.Dir = s.WorkDir
+	cmd.Env = os.Environ()
+	for _, env := range []map[string]string{s.Env, exports} {
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			cmd.Env = append(cmd.Env, k+"="+env[k])
+		}
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stdout
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	startErr := cmd.Start()
+	if err := m.update(name, func(s *Session) error {
+		s.HostPID = os.Getpid()
+		if startErr != nil {
+			s.Exited, s.ExitCode = true, -1
+			return nil
+		}
+		s.PID = cmd.Process.Pid
+		return nil
+	}); err != nil {
+		if startErr == nil {
+			_ = cmd.Process.Kill()
+		}
+		return fmt.Errorf("recording session %s: %w", name, err)
+	}
+	if startErr != nil {
+		return fmt.Errorf("starting %q: %w", s.Command, startErr)
+	}
+
+	go m.forwardInput(name, stdin)
+
+	code := 0
+	if err := cmd.Wait(); err != nil {
+		code = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		}
+	}
+	// The session may have been renamed while it ran
+	if cur := m.findByHost(os.Getpid()); cur != "" {
+		name = cur
+	}
+	return m.update(name, func(s *Session) error {
+		s.Exited, s.ExitCode = true, code
+		return nil
+	})
+}
+
+// forwardInput copies what Send appends to the session's input file to
+// the command's stdin.
+func (m *Manager) forwardInput(name string, stdin io.WriteCloser) {
+	var offset int64
+	for {
+		time.Sleep(inputPoll)
+		f, err := os.Open(m.path(name, ".in"))
+		if os.IsNotExist(err) {
+			if _, err := m.load(name); errors.Is(err, ErrSessionNotFound) {
+				if cur := m.findByHost(os.Getpid()); cur != "" && cur != name {
+					name, offset = cur, 0
+				}
+			}
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if info, err := f.Stat(); err == nil && info.Size() < offset {
+			offset = 0 // truncated or replaced
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err == nil {
+			n, _ := io.Copy(stdin, f)
+			offset += n
+		}
+		_ = f.Close()
+	}
+}
+
+// findByHost returns the name of the session hosted by pid.
+func (m *Manager) findByHost(pid int) string {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if s, err := m.load(name); err == nil && s.HostPID == pid {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+
+package procsession
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+)
+
+// detachedProcAttr starts the host in its own session, so it outlives the
+// gt command that started it and its process group can be killed whole.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// shellCommand runs command with sh, or starts the user's shell if it is
+// empty.
+func shellCommand(command string) *exec.Cmd {
+	if command == "" {
+		return exec.Command(userShell()) //nolint:gosec // G204: the user's own shell
+	}
+	return exec.Command("sh", "-c", command) //nolint:gosec // G204: session commands are constructed internally
+}
+
+func userShell() string {
+	if sh := os.Getenv("SHELL"); sh != "" {
+		return sh
+	}
+	return "sh"
+}
+
+func defaultShellName() string {
+	return filepath.Base(userShell())
+}
+
+// killTree kills the host and everything it started.
+func killTree(hostPID int) {
+	_ = syscall.Kill(-hostPID, syscall.SIGKILL)
+}
@@ -0,0 +1,52 @@
+//go:build windows
+
+package procsession
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// detachedProcAttr starts the host without a console and outside gt's
+// process group, so it outlives the gt command that started it.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS,
+		HideWindow:    true,
+	}
+}
+
+// shellCommand runs command with cmd.exe, or starts it if command is
+// empty. The "export K=V && " prefix gt puts on startup commands is
+// applied by Host as environment rather than passed to cmd.exe.
+func shellCommand(command string) *exec.Cmd {
+	_, command = SplitExports(command)
+	if command == "" {
+		return exec.Command(userShell()) //nolint:gosec // G204: the user's own shell
+	}
+	shell := userShell()
+	c := exec.Command(shell) //nolint:gosec // G204: session commands are constructed internally
+	// cmd.exe does its own quote parsing; pass the command line through as is
+	c.SysProcAttr = &syscall.SysProcAttr{CmdLine: `"` + shell + `" /S /C "` + command + `"`}
+	return c
+}
+
+func userShell() string {
+	if sh := os.Getenv("COMSPEC"); sh != "" {
+		return sh
+	}
+	return "cmd.exe"
+}
+
+func defaultShellName() string {
+	return "cmd"
+}
+
+// killTree kills the host and everything it started.
+func killTree(hostPID int) {
+	_ = exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(hostPID)).Run() //nolint:gosec // G204: pid is an integer
+}
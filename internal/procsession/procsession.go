@@ -0,0 +1,409 @@
+// Package procsession runs agent sessions as plain background processes,
+// for hosts without tmux (notably Windows).
+//
+// Each session is a detached 'gt session-host <name>' process that starts
+// the session's command, appends its output to a log file and feeds it
+// anything written to the session's input file. Session records live in
+// the gastown state directory, so every gt process sees the same sessions:
+//
+//	<state>/sessions/<name>.json   record (command, pids, environment)
+//	<state>/sessions/<name>.log    output
+//	<state>/sessions/<name>.in     pending input
+//
+// There is no terminal: agents that need a TTY won't run interactively,
+// and "keys" are plain text written to the command's stdin.
+package procsession
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Common errors
+var (
+	ErrSessionExists   = errors.New("session already exists")
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// HostCommand is the hidden gt subcommand that hosts a session.
+const HostCommand = "session-host"
+
+// startTimeout is how long Start waits for the host to launch the command.
+const startTimeout = 5 * time.Second
+
+// Session is a session's record.
+type Session struct {
+	Name    string            `json:"name"`
+	WorkDir string            `json:"work_dir"`
+	Command string            `json:"command"`
+	Env     map[string]string `json:"env,omitempty"`
+	Created time.Time         `json:"created"`
+
+	// HostPID is the session-host process; PID is the command it runs.
+	HostPID int `json:"host_pid,omitempty"`
+	PID     int `json:"pid,omitempty"`
+
+	Exited   bool `json:"exited,omitempty"`
+	ExitCode int  `json:"exit_code,omitempty"`
+}
+
+// Alive reports whether the session's command is still running.
+func (s *Session) Alive() bool {
+	return !s.Exited && s.HostPID != 0 && util.ProcessAlive(s.HostPID)
+}
+
+// Program is the name of the program the command runs, like tmux's
+// pane_current_command: "claude" for "export A=b && claude --resume".
+func (s *Session) Program() string {
+	_, rest := SplitExports(s.Command)
+	rest = strings.TrimSpace(rest)
+	var prog string
+	if strings.HasPrefix(rest, `"`) {
+		prog, _, _ = strings.Cut(rest[1:], `"`)
+	} else if fields := strings.Fields(rest); len(fields) > 0 {
+		prog = fields[0]
+	}
+	if prog == "" {
+		return defaultShellName()
+	}
+	prog = path.Base(strings.ReplaceAll(prog, `\`, "/"))
+	if ext := path.Ext(prog); strings.EqualFold(ext, ".exe") {
+		prog = strings.TrimSuffix(prog, ext)
+	}
+	return prog
+}
+
+// SplitExports splits the "export K=V ... && " prefix gt puts on startup
+// commands from the command itself, so the variables can be set without a
+// POSIX shell.
+func SplitExports(command string) (map[string]string, string) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(command), "export ")
+	if !ok {
+		return nil, command
+	}
+	assignments, rest, ok := strings.Cut(rest, " && ")
+	if !ok {
+		return nil, command
+	}
+	env := make(map[string]string)
+	for _, a := range strings.Fields(assignments) {
+		k, v, ok := strings.Cut(a, "=")
+		if !ok {
+			return nil, command
+		}
+		env[k] = v
+	}
+	return env, rest
+}
+
+// Manager manages the sessions in a directory.
+type Manager struct {
+	dir string
+}
+
+// NewManager returns a manager for the sessions in the gastown state
+// directory.
+func NewManager() *Manager {
+	return NewManagerAt(filepath.Join(state.StateDir(), "sessions"))
+}
+
+// NewManagerAt returns a manager for the sessions in dir.
+func NewManagerAt(dir string) *Manager {
+	return &Manager{dir: dir}
+}
+
+func (m *Manager) path(name, ext string) string {
+	return filepath.Join(m.dir, name+ext)
+}
+
+// LogPath returns the path of a session's output log.
+func (m *Manager) LogPath(name string) string {
+	return m.path(name, ".log")
+}
+
+func (m *Manager) load(name string) (*Session, error) {
+	data, err := os.ReadFile(m.path(name, ".json"))
+	if os.IsNotExist(err) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("session %s: %w", name, err)
+	}
+	return &s, nil
+}
+
+// update applies fn to a session's record under the session's lock.
+func (m *Manager) update(name string, fn func(*Session) error) error {
+	lock := flock.New(m.path(name, ".lock"))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking session %s: %w", name, err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	s, err := m.load(name)
+	if err != nil {
+		return err
+	}
+	if err := fn(s); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(m.path(name, ".json"), s)
+}
+
+// Get returns a running session.
+func (m *Manager) Get(name string) (*Session, error) {
+	s, err := m.load(name)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Alive() {
+		return nil, ErrSessionNotFound
+	}
+	return s, nil
+}
+
+// List returns the running sessions, sorted by name.
+func (m *Manager) List() ([]*Session, error) {
+	entries, err := os.ReadDir(m.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []*Session
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if s, err := m.Get(name); err == nil {
+			out = append(out, s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Start starts a session running command in workDir. An empty command
+// starts the platform shell.
+func (m *Manager) Start(name, workDir, command string) error {
+	return m.start(&Session{Name: name, WorkDir: workDir, Command: command})
+}
+
+func (m *Manager) start(s *Session) error {
+	if existing, err := m.load(s.Name); err == nil && existing.Alive() {
+		return ErrSessionExists
+	}
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return fmt.Errorf("creating session directory: %w", err)
+	}
+	s.Created = time.Now()
+	s.HostPID, s.PID, s.Exited, s.ExitCode = 0, 0, false, 0
+	if err := util.AtomicWriteJSON(m.path(s.Name, ".json"), s); err != nil {
+		return err
+	}
+	_ = os.Remove(m.path(s.Name, ".in"))
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding gt: %w", err)
+	}
+	logFile, err := os.OpenFile(m.LogPath(s.Name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening session log: %w", err)
+	}
+	defer logFile.Close()
+
+	host := exec.Command(exe, HostCommand, s.Name) //nolint:gosec // G204: gt re-executing itself
+	host.Dir = s.WorkDir
+	host.Stdout = logFile
+	host.Stderr = logFile
+	host.Env = append(os.Environ(), "GT_SESSION_DIR="+m.dir)
+	host.SysProcAttr = detachedProcAttr()
+	if err := host.Start(); err != nil {
+		return fmt.Errorf("starting session host: %w", err)
+	}
+	// Reap the host if it exits while this process is still running
+	go func() { _ = host.Wait() }()
+
+	// Wait for the host to record the command it started
+	deadline := time.Now().Add(startTimeout)
+	for time.Now().Before(deadline) {
+		if cur, err := m.load(s.Name); err == nil && (cur.PID != 0 || cur.Exited) {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fmt.Errorf("session %s did not start within %s (see %s)", s.Name, startTimeout, m.LogPath(s.Name))
+}
+
+// Kill stops a session's command and host and removes its record. The
+// log is kept.
+func (m *Manager) Kill(name string) error {
+	s, err := m.load(name)
+	if err != nil {
+		return err
+	}
+	if s.HostPID != 0 && util.ProcessAlive(s.HostPID) {
+		killTree(s.HostPID)
+	}
+	_ = os.Remove(m.path(name, ".in"))
+	_ = os.Remove(m.path(name, ".lock"))
+	if err := os.Remove(m.path(name, ".json")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Respawn replaces a session's command, keeping its name, directory and
+// environment.
+func (m *Manager) Respawn(name, command string) error {
+	s, err := m.load(name)
+	if err != nil {
+		return err
+	}
+	if err := m.Kill(name); err != nil {
+		return err
+	}
+	s.Command = command
+	return m.start(s)
+}
+
+// Rename renames a running session.
+func (m *Manager) Rename(oldName, newName string) error {
+	if _, err := m.load(newName); err == nil {
+		return ErrSessionExists
+	}
+	if err := m.update(oldName, func(s *Session) error {
+		s.Name = newName
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, ext := range []string{".json", ".log", ".in"} {
+		if err := os.Rename(m.path(oldName, ext), m.path(newName, ext)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	_ = os.Remove(m.path(oldName, ".lock"))
+	return nil
+}
+
+// Send writes text to a session's stdin.
+func (m *Manager) Send(name, text string) error {
+	if _, err := m.Get(name); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(m.path(name, ".in"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(text)
+	return err
+}
+
+// Capture returns the last lines of a session's output, or all of it if
+// lines <= 0.
+func (m *Manager) Capture(name string, lines int) (string, error) {
+	if _, err := m.load(name); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(m.LogPath(name))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	out := strings.Split(strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+	if lines > 0 && len(out) > lines {
+		out = out[len(out)-lines:]
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// ClearLog discards a session's output so far.
+func (m *Manager) ClearLog(name string) error {
+	if _, err := m.load(name); err != nil {
+		return err
+	}
+	return os.Truncate(m.LogPath(name), 0)
+}
+
+// SetEnv records an environment variable for the session. Like tmux, it
+// applies to commands started afterwards (Respawn), not the running one.
+func (m *Manager) SetEnv(name, key, value string) error {
+	return m.update(name, func(s *Session) error {
+		if s.Env == nil {
+			s.Env = make(map[string]string)
+		}
+		s.Env[key] = value
+		return nil
+	})
+}
+
+// Attach shows a session's output as it is written and sends lines read
+// from in to it, until the session ends or in is closed.
+func (m *Manager) Attach(name string, in io.Reader, out io.Writer) error {
+	if _, err := m.Get(name); err != nil {
+		return err
+	}
+	if tail, err := m.Capture(name, 100); err == nil && tail != "" {
+		fmt.Fprintln(out, tail)
+	}
+
+	f, err := os.Open(m.LogPath(name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			if m.Send(name, scanner.Text()+"\n") != nil {
+				break
+			}
+		}
+		close(done)
+	}()
+
+	for {
+		if _, err := io.Copy(out, f); err != nil {
+			return err
+		}
+		if _, err := m.Get(name); err != nil {
+			fmt.Fprintf(out, "\n[session %s ended]\n", name)
+			return nil
+		}
+		select {
+		case <-done:
+			return nil
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
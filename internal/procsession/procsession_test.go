@@ -0,0 +1,131 @@
+package procsession
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestMain lets the test binary stand in for gt as the session host.
+func TestMain(m *testing.M) {
+	if len(os.Args) == 3 && os.Args[1] == HostCommand {
+		if err := HostManager().Host(os.Args[2]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestSplitExports(t *testing.T) {
+	env, rest := SplitExports("export GT_ROLE=polecat GT_RIG=gastown && claude --resume")
+	if rest != "claude --resume" || env["GT_ROLE"] != "polecat" || env["GT_RIG"] != "gastown" || len(env) != 2 {
+		t.Errorf("SplitExports = %v, %q", env, rest)
+	}
+	for _, cmd := range []string{"claude", "export FOO && claude", "export A=b"} {
+		if env, rest := SplitExports(cmd); env != nil || rest != cmd {
+			t.Errorf("SplitExports(%q) = %v, %q; want it unchanged", cmd, env, rest)
+		}
+	}
+}
+
+func TestProgram(t *testing.T) {
+	tests := map[string]string{
+		"export A=b && claude --resume":      "claude",
+		`"C:\Program Files\nodejs\node.exe"`: "node",
+		"/usr/local/bin/codex exec":          "codex",
+	}
+	for cmd, want := range tests {
+		if got := (&Session{Command: cmd}).Program(); got != want {
+			t.Errorf("Program(%q) = %q, want %q", cmd, got, want)
+		}
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell command")
+	}
+	m := NewManagerAt(t.TempDir())
+	t.Setenv("GT_SESSION_DIR", m.dir)
+
+	if err := m.Start("gt-test", t.TempDir(), "export GREETING=hello && echo $GREETING; while read line; do echo got $line; done"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = m.Kill("gt-test") })
+
+	if err := m.Start("gt-test", "", "true"); !errors.Is(err, ErrSessionExists) {
+		t.Errorf("second Start = %v, want ErrSessionExists", err)
+	}
+	sessions, err := m.List()
+	if err != nil || len(sessions) != 1 || sessions[0].Name != "gt-test" {
+		t.Fatalf("List = %v, %v", sessions, err)
+	}
+
+	if err := m.Send("gt-test", "ping\n"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "output", func() bool {
+		out, _ := m.Capture("gt-test", 0)
+		return out == "hello\ngot ping"
+	})
+	if out, _ := m.Capture("gt-test", 1); out != "got ping" {
+		t.Errorf("Capture(1) = %q", out)
+	}
+
+	if err := m.Rename("gt-test", "gt-renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send("gt-renamed", "pong\n"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "output after rename", func() bool {
+		out, _ := m.Capture("gt-renamed", 1)
+		return out == "got pong"
+	})
+
+	if err := m.Kill("gt-renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get("gt-renamed"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Get after Kill = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSessionExits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell command")
+	}
+	m := NewManagerAt(t.TempDir())
+	t.Setenv("GT_SESSION_DIR", m.dir)
+
+	if err := m.Start("gt-exit", "", "exit 3"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "exit", func() bool {
+		s, err := m.load("gt-exit")
+		return err == nil && s.Exited
+	})
+	s, _ := m.load("gt-exit")
+	if s.ExitCode != 3 {
+		t.Errorf("exit code = %d, want 3", s.ExitCode)
+	}
+	if _, err := m.Get("gt-exit"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Get after exit = %v, want ErrSessionNotFound", err)
+	}
+}
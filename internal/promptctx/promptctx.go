@@ -0,0 +1,185 @@
+// Package promptctx assembles an agent's prompt from components (role pack,
+// hooked bead, molecule step, file excerpts, prior step output, ...) under
+// a token budget.
+//
+// Each component has a priority. Required components are always kept; the
+// rest are kept in priority order while they fit, the first that doesn't
+// fit is trimmed to the space left, and anything after it is dropped. The
+// assembled text keeps the components in their original order and marks
+// each trim, and the Assembly reports what was cut, so a prompt is never
+// shortened silently.
+package promptctx
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Priority orders components for trimming; lower values are kept first.
+type Priority int
+
+// Priorities.
+const (
+	Required Priority = iota // Always kept, even over budget
+	High
+	Normal
+	Low
+)
+
+// String returns the priority's name.
+func (p Priority) String() string {
+	switch p {
+	case Required:
+		return "required"
+	case High:
+		return "high"
+	case Normal:
+		return "normal"
+	default:
+		return "low"
+	}
+}
+
+// minTrimTokens is the smallest remainder worth trimming a component to;
+// below it the component is dropped instead.
+const minTrimTokens = 100
+
+// Component is one part of a prompt.
+type Component struct {
+	Name     string
+	Priority Priority
+	Text     string
+}
+
+// Outcome is what the budget did to a component.
+type Outcome string
+
+// Outcomes.
+const (
+	Kept    Outcome = "kept"
+	Trimmed Outcome = "trimmed"
+	Dropped Outcome = "dropped"
+)
+
+// Part is a component with the result of budgeting it.
+type Part struct {
+	Component
+	Outcome Outcome
+	Tokens  int    // Tokens in the original text
+	Kept    int    // Tokens in the assembled text
+	text    string // Text as assembled
+}
+
+// Assembly is a budgeted prompt.
+type Assembly struct {
+	Budget int // Budget in tokens; 0 means unlimited
+	Tokens int // Tokens in the assembled text
+	Parts  []Part
+}
+
+// EstimateTokens estimates the tokens in text at four characters a token,
+// close enough for English prose and code to budget by.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// Assemble fits components into budget tokens. A budget of 0 or less keeps
+// everything.
+func Assemble(budget int, components []Component) *Assembly {
+	a := &Assembly{Budget: budget, Parts: make([]Part, len(components))}
+	for i, c := range components {
+		a.Parts[i] = Part{Component: c, Tokens: EstimateTokens(c.Text), Outcome: Kept, text: c.Text}
+	}
+
+	if budget > 0 {
+		remaining := budget
+		for _, p := range a.Parts {
+			if p.Priority == Required {
+				remaining -= p.Tokens
+			}
+		}
+		for prio := High; prio <= Low; prio++ {
+			for i := range a.Parts {
+				p := &a.Parts[i]
+				if p.Priority != prio {
+					continue
+				}
+				switch {
+				case p.Tokens <= remaining:
+					remaining -= p.Tokens
+				case remaining >= minTrimTokens:
+					p.text = trim(p.Text, remaining)
+					p.Outcome = Trimmed
+					remaining = 0
+				default:
+					p.text = ""
+					p.Outcome = Dropped
+				}
+			}
+		}
+	}
+
+	for i := range a.Parts {
+		a.Parts[i].Kept = EstimateTokens(a.Parts[i].text)
+		a.Tokens += a.Parts[i].Kept
+	}
+	return a
+}
+
+// Text returns the assembled prompt, components in their original order.
+func (a *Assembly) Text() string {
+	var b strings.Builder
+	for _, p := range a.Parts {
+		b.WriteString(p.text)
+	}
+	return b.String()
+}
+
+// Cut returns the parts that were trimmed or dropped.
+func (a *Assembly) Cut() []Part {
+	var cut []Part
+	for _, p := range a.Parts {
+		if p.Outcome != Kept {
+			cut = append(cut, p)
+		}
+	}
+	return cut
+}
+
+// Notice describes what was cut, for the end of the prompt, or "" if
+// nothing was.
+func (a *Assembly) Notice() string {
+	cut := a.Cut()
+	if len(cut) == 0 {
+		return ""
+	}
+	var items []string
+	for _, p := range cut {
+		if p.Outcome == Trimmed {
+			items = append(items, fmt.Sprintf("trimmed %s (%d of %d tokens kept)", p.Name, p.Kept, p.Tokens))
+		} else {
+			items = append(items, fmt.Sprintf("dropped %s (%d tokens)", p.Name, p.Tokens))
+		}
+	}
+	return fmt.Sprintf("[Context budget %d tokens: %s]", a.Budget, strings.Join(items, ", "))
+}
+
+// trim shortens text to about tokens tokens, at a line break where there
+// is one, and marks the cut.
+func trim(text string, tokens int) string {
+	marker := fmt.Sprintf("\n[... trimmed to fit the context budget; %d tokens cut]\n", EstimateTokens(text)-tokens)
+	keep := (tokens - EstimateTokens(marker)) * 4
+	if keep <= 0 {
+		return strings.TrimPrefix(marker, "\n")
+	}
+	runes := []rune(text)
+	if keep >= len(runes) {
+		return text
+	}
+	kept := string(runes[:keep])
+	if i := strings.LastIndexByte(kept, '\n'); i > 0 {
+		kept = kept[:i]
+	}
+	return kept + marker
+}
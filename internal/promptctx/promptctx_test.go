@@ -0,0 +1,71 @@
+package promptctx
+
+import (
+	"strings"
+	"testing"
+)
+
+// lines returns n lines of 40 characters (10 tokens each).
+func lines(n int) string {
+	return strings.Repeat(strings.Repeat("x", 39)+"\n", n)
+}
+
+func TestEstimateTokens(t *testing.T) {
+	for text, want := range map[string]int{"": 0, "abc": 1, "abcd": 1, "abcde": 2, "héllo wörld!": 3} {
+		if got := EstimateTokens(text); got != want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestAssemble_Unlimited(t *testing.T) {
+	a := Assemble(0, []Component{{Name: "a", Text: lines(100)}, {Name: "b", Priority: Low, Text: lines(100)}})
+	if len(a.Cut()) != 0 || a.Tokens != 2000 || a.Notice() != "" {
+		t.Errorf("cut %v, tokens %d", a.Cut(), a.Tokens)
+	}
+}
+
+func TestAssemble_PriorityOrder(t *testing.T) {
+	a := Assemble(1000, []Component{
+		{Name: "role", Priority: Required, Text: lines(50)},     // 500
+		{Name: "bd-prime", Priority: Low, Text: lines(30)},      // 300, dropped
+		{Name: "bead", Priority: High, Text: lines(20)},         // 200
+		{Name: "checkpoint", Priority: Normal, Text: lines(50)}, // 500, trimmed to 300
+	})
+
+	outcomes := map[string]Outcome{}
+	for _, p := range a.Parts {
+		outcomes[p.Name] = p.Outcome
+	}
+	want := map[string]Outcome{"role": Kept, "bead": Kept, "checkpoint": Trimmed, "bd-prime": Dropped}
+	for name, o := range want {
+		if outcomes[name] != o {
+			t.Errorf("%s: %s, want %s", name, outcomes[name], o)
+		}
+	}
+	if a.Tokens > 1000 {
+		t.Errorf("assembled %d tokens, over budget", a.Tokens)
+	}
+
+	text := a.Text()
+	if !strings.HasPrefix(text, lines(50)) {
+		t.Error("assembled text should keep the original order")
+	}
+	if !strings.Contains(text, "[... trimmed to fit the context budget;") {
+		t.Error("trimmed component should be marked")
+	}
+	notice := a.Notice()
+	if !strings.Contains(notice, "trimmed checkpoint") || !strings.Contains(notice, "dropped bd-prime (300 tokens)") {
+		t.Errorf("notice = %q", notice)
+	}
+}
+
+func TestAssemble_RequiredOverBudget(t *testing.T) {
+	a := Assemble(100, []Component{
+		{Name: "role", Priority: Required, Text: lines(50)},
+		{Name: "mail", Priority: High, Text: lines(1)},
+	})
+	if a.Parts[0].Outcome != Kept || a.Parts[1].Outcome != Dropped {
+		t.Errorf("outcomes = %s, %s", a.Parts[0].Outcome, a.Parts[1].Outcome)
+	}
+}
@@ -0,0 +1,291 @@
+// Package prompts manages versioned role prompt packs: the instructions
+// 'gt prime' gives the mayor, witness, polecats and the other roles.
+//
+// A pack is a directory in the town, <town>/prompts/<version>/, holding a
+// pack.json manifest and the templates it names. Each role's prompt is
+// composed from one or more parts, rendered with templates.RoleData and
+// joined in order. Roles a pack doesn't list come from the built-in pack
+// compiled into gt, so a pack can override only the polecat prompt.
+//
+// The town uses the pack named in settings/config.json
+// ("prompts": {"pack": "v2"}), or the built-in pack when none is named.
+package prompts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+const (
+	// Dir is the town directory holding prompt packs, one per version.
+	Dir = "prompts"
+
+	// ManifestFile names a pack's manifest inside its directory.
+	ManifestFile = "pack.json"
+
+	// SchemaVersion is the manifest schema this gt understands.
+	SchemaVersion = 1
+
+	// Builtin is the version name of the pack compiled into gt.
+	Builtin = "builtin"
+)
+
+// ErrNotFound is returned when a pack version doesn't exist in the town.
+var ErrNotFound = errors.New("prompt pack not found")
+
+// Roles are the roles a pack can give prompts for.
+var Roles = []string{"mayor", "deacon", "witness", "refinery", "polecat", "crew", "boot"}
+
+// Manifest describes a pack.
+type Manifest struct {
+	Schema      int                 `json:"schema"`
+	Version     string              `json:"version"`
+	Description string              `json:"description,omitempty"`
+	BasedOn     string              `json:"based_on,omitempty"` // Pack this one was copied from
+	Roles       map[string][]string `json:"roles"`              // Role to its parts, in order
+}
+
+// Pack is a loaded prompt pack.
+type Pack struct {
+	Manifest
+	Dir   string // Empty for the built-in pack
+	files fs.FS
+}
+
+// BuiltinPack returns the pack compiled into gt: one part per role, the
+// embedded role templates.
+func BuiltinPack() *Pack {
+	p := &Pack{
+		Manifest: Manifest{Schema: SchemaVersion, Version: Builtin, Description: "Compiled into gt", Roles: map[string][]string{}},
+		files:    templates.RolesFS(),
+	}
+	for _, role := range Roles {
+		p.Roles[role] = []string{role + ".md.tmpl"}
+	}
+	return p
+}
+
+// PacksDir returns the directory holding a town's packs.
+func PacksDir(townRoot string) string {
+	return filepath.Join(townRoot, Dir)
+}
+
+// Load reads pack version from the town. Builtin is always available.
+func Load(townRoot, version string) (*Pack, error) {
+	if version == "" || version == Builtin {
+		return BuiltinPack(), nil
+	}
+	dir := filepath.Join(PacksDir(townRoot), version)
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p := &Pack{Dir: dir, files: os.DirFS(dir)}
+	if err := json.Unmarshal(data, &p.Manifest); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Join(dir, ManifestFile), err)
+	}
+	return p, nil
+}
+
+// List returns the versions of the town's packs, sorted, without Builtin.
+func List(townRoot string) ([]string, error) {
+	entries, err := os.ReadDir(PacksDir(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, e := range entries {
+		if _, err := os.Stat(filepath.Join(PacksDir(townRoot), e.Name(), ManifestFile)); e.IsDir() && err == nil {
+			versions = append(versions, e.Name())
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// ActiveVersion returns the pack version the town's settings select.
+func ActiveVersion(townRoot string) string {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Prompts == nil || settings.Prompts.Pack == "" {
+		return Builtin
+	}
+	return settings.Prompts.Pack
+}
+
+// Active loads the town's selected pack.
+func Active(townRoot string) (*Pack, error) {
+	return Load(townRoot, ActiveVersion(townRoot))
+}
+
+// Source returns a role's prompt template: its parts joined in order.
+// Roles the pack doesn't list come from the built-in pack.
+func (p *Pack) Source(role string) (string, error) {
+	parts, ok := p.Roles[role]
+	if !ok {
+		if p.Version == Builtin {
+			return "", fmt.Errorf("no prompt for role %q", role)
+		}
+		return BuiltinPack().Source(role)
+	}
+	var buf strings.Builder
+	for _, part := range parts {
+		data, err := fs.ReadFile(p.files, part)
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", part, err)
+		}
+		buf.Write(data)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.String(), nil
+}
+
+// Render composes and renders a role's prompt.
+func (p *Pack) Render(role string, data templates.RoleData) (string, error) {
+	src, err := p.Source(role)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(role).Parse(src)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s prompt (pack %s): %w", role, p.Version, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering %s prompt (pack %s): %w", role, p.Version, err)
+	}
+	return buf.String(), nil
+}
+
+// Overrides reports whether the pack gives its own prompt for role.
+func (p *Pack) Overrides(role string) bool {
+	_, ok := p.Roles[role]
+	return ok
+}
+
+// Validate checks the manifest against the schema and that every role's
+// parts exist, parse and render with sample role data. All problems are
+// returned together.
+func (p *Pack) Validate() error {
+	var errs []error
+	if p.Schema != SchemaVersion {
+		errs = append(errs, fmt.Errorf("schema %d is not supported (want %d)", p.Schema, SchemaVersion))
+	}
+	if p.Version == "" {
+		errs = append(errs, fmt.Errorf("version is required"))
+	} else if p.Dir != "" && filepath.Base(p.Dir) != p.Version {
+		errs = append(errs, fmt.Errorf("version %q doesn't match directory %q", p.Version, filepath.Base(p.Dir)))
+	}
+	if len(p.Roles) == 0 {
+		errs = append(errs, fmt.Errorf("roles: at least one role is required"))
+	}
+
+	known := map[string]bool{}
+	for _, role := range Roles {
+		known[role] = true
+	}
+	roles := make([]string, 0, len(p.Roles))
+	for role := range p.Roles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		if !known[role] {
+			errs = append(errs, fmt.Errorf("roles: unknown role %q (valid: %s)", role, strings.Join(Roles, ", ")))
+			continue
+		}
+		if len(p.Roles[role]) == 0 {
+			errs = append(errs, fmt.Errorf("roles.%s: no parts", role))
+			continue
+		}
+		bad := false
+		for _, part := range p.Roles[role] {
+			if !fs.ValidPath(part) || path.IsAbs(part) {
+				errs = append(errs, fmt.Errorf("roles.%s: part %q must be a path inside the pack", role, part))
+				bad = true
+			}
+		}
+		if bad {
+			continue
+		}
+		if _, err := p.Render(role, sampleData(role)); err != nil {
+			errs = append(errs, fmt.Errorf("roles.%s: %w", role, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sampleData is role data with every field set, to render prompts with
+// during validation.
+func sampleData(role string) templates.RoleData {
+	return templates.RoleData{
+		Role:          role,
+		RigName:       "greenplace",
+		TownRoot:      "/home/user/gt",
+		TownName:      "gt",
+		WorkDir:       "/home/user/gt/greenplace/polecats/Toast",
+		DefaultBranch: "main",
+		Polecat:       "Toast",
+		Polecats:      []string{"Toast", "Nux"},
+		BeadsDir:      "/home/user/gt/greenplace/.beads",
+		IssuePrefix:   "gp",
+		MayorSession:  "hq-mayor",
+		DeaconSession: "hq-deacon",
+	}
+}
+
+// Create writes a new pack version to the town, copying every role's
+// composed prompt from `from` (one part per role).
+func Create(townRoot, version, description string, from *Pack) (*Pack, error) {
+	if version == "" || version == Builtin || !fs.ValidPath(version) || strings.Contains(version, "/") {
+		return nil, fmt.Errorf("invalid pack version %q", version)
+	}
+	dir := filepath.Join(PacksDir(townRoot), version)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("pack %s already exists", version)
+	}
+
+	m := Manifest{Schema: SchemaVersion, Version: version, Description: description, BasedOn: from.Version, Roles: map[string][]string{}}
+	if err := dryrun.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", dir, err)
+	}
+	for _, role := range Roles {
+		src, err := from.Source(role)
+		if err != nil {
+			return nil, err
+		}
+		part := role + ".md.tmpl"
+		if err := dryrun.WriteFile(filepath.Join(dir, part), []byte(src), 0644); err != nil { //nolint:gosec // G306: prompts are non-sensitive
+			return nil, fmt.Errorf("writing %s: %w", part, err)
+		}
+		m.Roles[role] = []string{part}
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := dryrun.WriteFile(filepath.Join(dir, ManifestFile), append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: manifest is non-sensitive
+		return nil, fmt.Errorf("writing %s: %w", ManifestFile, err)
+	}
+	return &Pack{Manifest: m, Dir: dir, files: os.DirFS(dir)}, nil
+}
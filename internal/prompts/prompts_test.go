@@ -0,0 +1,149 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+func writePack(t *testing.T, townRoot, version, manifest string, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(townRoot, Dir, version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBuiltinPackValidates(t *testing.T) {
+	if err := BuiltinPack().Validate(); err != nil {
+		t.Fatalf("built-in pack is invalid: %v", err)
+	}
+}
+
+func TestCreateAndLoad(t *testing.T) {
+	town := t.TempDir()
+	if _, err := Create(town, "v1", "first", BuiltinPack()); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := Create(town, "v1", "", BuiltinPack()); err == nil {
+		t.Error("Create over an existing pack should fail")
+	}
+
+	p, err := Load(town, "v1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if p.BasedOn != Builtin || p.Description != "first" {
+		t.Errorf("manifest = %+v", p.Manifest)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("copied pack is invalid: %v", err)
+	}
+	want, _ := BuiltinPack().Source("polecat")
+	if got, _ := p.Source("polecat"); got != want {
+		t.Error("copied polecat prompt differs from the built-in one")
+	}
+
+	versions, err := List(town)
+	if err != nil || len(versions) != 1 || versions[0] != "v1" {
+		t.Errorf("List = %v, %v", versions, err)
+	}
+	if _, err := Load(town, "v9"); err == nil {
+		t.Error("Load of a missing version should fail")
+	}
+}
+
+func TestRenderComposesPartsAndFallsBack(t *testing.T) {
+	town := t.TempDir()
+	writePack(t, town, "v2", `{"schema": 1, "version": "v2", "roles": {"polecat": ["base.md", "house-rules.md"]}}`,
+		map[string]string{
+			"base.md":        "You are {{ .Polecat }} in {{ .RigName }}.",
+			"house-rules.md": "Run the linter before gt done.\n",
+		})
+	p, err := Load(town, "v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.Render("polecat", templates.RoleData{Polecat: "Toast", RigName: "greenplace"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "You are Toast in greenplace.\nRun the linter before gt done.\n"; got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+
+	// Roles the pack doesn't list come from the built-in pack
+	if p.Overrides("witness") {
+		t.Error("pack should not override witness")
+	}
+	witness, err := p.Render("witness", sampleData("witness"))
+	if err != nil || !strings.Contains(witness, "Witness") {
+		t.Errorf("witness fallback: %v", err)
+	}
+}
+
+func TestValidateReportsProblems(t *testing.T) {
+	town := t.TempDir()
+	writePack(t, town, "bad", `{"schema": 2, "version": "other", "roles": {"jester": ["a.md"], "polecat": ["missing.md"], "crew": ["crew.md"]}}`,
+		map[string]string{"crew.md": "Hello {{ .NoSuchField }}"})
+	p, err := Load(town, "bad")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = p.Validate()
+	if err == nil {
+		t.Fatal("Validate should fail")
+	}
+	for _, want := range []string{"schema 2", `doesn't match directory "bad"`, `unknown role "jester"`, "missing.md", "roles.crew", "NoSuchField"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate error missing %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if d := Diff("a", "b", "same\n", "same\n"); d != "" {
+		t.Errorf("Diff of equal texts = %q", d)
+	}
+
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\ntwo\nthree\nfour\nFIVE\nsix\nseven\neight\nnine\nten\neleven\n"
+	want := `--- v1/polecat
++++ v2/polecat
+@@ -2,9 +2,10 @@
+ two
+ three
+ four
+-five
++FIVE
+ six
+ seven
+ eight
+ nine
+ ten
++eleven
+`
+	if got := Diff("v1/polecat", "v2/polecat", a, b); got != want {
+		t.Errorf("Diff =\n%s\nwant\n%s", got, want)
+	}
+
+	// Changes far apart get separate hunks
+	long := strings.Repeat("x\n", 20)
+	changed := "y\n" + strings.Repeat("x\n", 18) + "y\n"
+	if got := strings.Count(Diff("a", "b", long, changed), "@@ -"); got != 2 {
+		t.Errorf("expected 2 hunks, got %d", got)
+	}
+}
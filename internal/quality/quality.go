@@ -0,0 +1,256 @@
+// Package quality scores a polecat's work before it is submitted: does it
+// build, what share of the tests pass, how much the linter finds, and
+// whether the diff is a sane size. Work below the rig's threshold goes
+// back to the implement step instead of to the refinery.
+package quality
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Check names, as used in weights and reports.
+const (
+	CheckBuild = "build"
+	CheckTests = "tests"
+	CheckLint  = "lint"
+	CheckDiff  = "diff"
+)
+
+// Defaults for settings left unset.
+const (
+	DefaultMinScore        = 70
+	DefaultMaxLintFindings = 20
+	DefaultMaxDiffLines    = 1000
+)
+
+// defaultWeights is how much each check counts toward the score.
+var defaultWeights = map[string]float64{
+	CheckBuild: 3,
+	CheckTests: 4,
+	CheckLint:  2,
+	CheckDiff:  1,
+}
+
+// Config is a rig's resolved quality gate.
+type Config struct {
+	MinScore        int
+	BuildCommand    string
+	TestCommand     string
+	LintCommand     string
+	MaxLintFindings int
+	MaxDiffLines    int
+	Weights         map[string]float64
+}
+
+// ConfigFrom returns the quality gate a rig's settings configure, and
+// false when the gate is off.
+func ConfigFrom(settings *config.RigSettings) (Config, bool) {
+	if settings == nil || settings.QualityGate == nil || !settings.QualityGate.Enabled {
+		return Config{}, false
+	}
+	qg := settings.QualityGate
+	c := Config{
+		MinScore:        qg.MinScore,
+		BuildCommand:    qg.BuildCommand,
+		TestCommand:     qg.TestCommand,
+		LintCommand:     qg.LintCommand,
+		MaxLintFindings: qg.MaxLintFindings,
+		MaxDiffLines:    qg.MaxDiffLines,
+		Weights:         make(map[string]float64, len(defaultWeights)),
+	}
+	if c.MinScore == 0 {
+		c.MinScore = DefaultMinScore
+	}
+	if c.TestCommand == "" && settings.MergeQueue != nil {
+		c.TestCommand = settings.MergeQueue.TestCommand
+	}
+	if c.MaxLintFindings <= 0 {
+		c.MaxLintFindings = DefaultMaxLintFindings
+	}
+	if c.MaxDiffLines <= 0 {
+		c.MaxDiffLines = DefaultMaxDiffLines
+	}
+	for name, w := range defaultWeights {
+		c.Weights[name] = w
+	}
+	for name, w := range qg.Weights {
+		c.Weights[name] = w
+	}
+	return c, true
+}
+
+// Runner runs a shell command in dir and returns its combined output.
+type Runner func(dir, command string) (string, error)
+
+// Check is one scored check.
+type Check struct {
+	Name   string  `json:"name"`
+	Score  float64 `json:"score"` // 0-1
+	Weight float64 `json:"weight"`
+	Detail string  `json:"detail"`
+	Output string  `json:"output,omitempty"` // Tail of the command output, when it failed
+}
+
+// Report is the result of scoring work.
+type Report struct {
+	Checks      []Check `json:"checks"`
+	Score       int     `json:"score"` // 0-100
+	MinScore    int     `json:"min_score"`
+	BuildFailed bool    `json:"build_failed,omitempty"`
+	Passed      bool    `json:"passed"`
+}
+
+// outputTail is how many lines of a failing command's output a report keeps.
+const outputTail = 20
+
+// Score runs the configured checks in dir and scores the work. diff is
+// the branch's change against its target; nil skips the diff check.
+// Checks without a command are skipped and don't count.
+func Score(cfg Config, dir string, diff *git.DiffStat, run Runner) *Report {
+	r := &Report{MinScore: cfg.MinScore}
+	if cfg.BuildCommand != "" {
+		out, err := run(dir, cfg.BuildCommand)
+		c := Check{Name: CheckBuild, Score: 1, Detail: "builds"}
+		if err != nil {
+			c.Score, c.Detail, c.Output = 0, fmt.Sprintf("%s failed: %v", cfg.BuildCommand, err), tail(out)
+			r.BuildFailed = true
+		}
+		r.add(cfg, c)
+	}
+	if cfg.TestCommand != "" {
+		out, err := run(dir, cfg.TestCommand)
+		c := Check{Name: CheckTests}
+		passed, failed := CountTests(out)
+		switch {
+		case passed+failed > 0:
+			c.Score = float64(passed) / float64(passed+failed)
+			c.Detail = fmt.Sprintf("%d/%d passed", passed, passed+failed)
+		case err == nil:
+			c.Score, c.Detail = 1, "passed"
+		default:
+			c.Detail = fmt.Sprintf("%s failed: %v", cfg.TestCommand, err)
+		}
+		if err != nil {
+			c.Output = tail(out)
+		}
+		r.add(cfg, c)
+	}
+	if cfg.LintCommand != "" {
+		out, err := run(dir, cfg.LintCommand)
+		findings := 0
+		for _, line := range strings.Split(out, "\n") {
+			if strings.TrimSpace(line) != "" {
+				findings++
+			}
+		}
+		if err != nil && findings == 0 {
+			findings = 1
+		}
+		c := Check{
+			Name:   CheckLint,
+			Score:  math.Max(0, 1-float64(findings)/float64(cfg.MaxLintFindings)),
+			Detail: fmt.Sprintf("%d finding(s)", findings),
+		}
+		if findings > 0 {
+			c.Output = tail(out)
+		}
+		r.add(cfg, c)
+	}
+	if diff != nil {
+		lines := diff.Insertions + diff.Deletions
+		c := Check{
+			Name:   CheckDiff,
+			Score:  1,
+			Detail: fmt.Sprintf("%d line(s) in %d file(s)", lines, len(diff.Files)),
+		}
+		if lines > cfg.MaxDiffLines {
+			c.Score = float64(cfg.MaxDiffLines) / float64(lines)
+			c.Detail += fmt.Sprintf(", over the %d-line limit", cfg.MaxDiffLines)
+		}
+		r.add(cfg, c)
+	}
+
+	var total, weights float64
+	for _, c := range r.Checks {
+		total += c.Score * c.Weight
+		weights += c.Weight
+	}
+	r.Score = 100
+	if weights > 0 {
+		r.Score = int(math.Round(100 * total / weights))
+	}
+	r.Passed = r.Score >= r.MinScore && !r.BuildFailed
+	return r
+}
+
+func (r *Report) add(cfg Config, c Check) {
+	c.Weight = cfg.Weights[c.Name]
+	r.Checks = append(r.Checks, c)
+}
+
+var (
+	goTestResult = regexp.MustCompile(`(?m)^\s*--- (PASS|FAIL):`)
+	testSummary  = regexp.MustCompile(`(\d+) (passed|failed)`)
+)
+
+// CountTests reads passed and failed test counts from test output: go
+// test -v's per-test lines, or a "N passed, M failed" summary as pytest,
+// jest and others print.
+func CountTests(out string) (passed, failed int) {
+	for _, m := range goTestResult.FindAllStringSubmatch(out, -1) {
+		if m[1] == "PASS" {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	if passed+failed > 0 {
+		return passed, failed
+	}
+	for _, m := range testSummary.FindAllStringSubmatch(out, -1) {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "passed" {
+			passed += n
+		} else {
+			failed += n
+		}
+	}
+	return passed, failed
+}
+
+// Markdown renders the report for the bead and the implement step.
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+	verdict := "passed"
+	if !r.Passed {
+		verdict = "below the bar"
+	}
+	fmt.Fprintf(&sb, "## Quality gate: %d/100 (%s, needs %d)\n\n", r.Score, verdict, r.MinScore)
+	for _, c := range r.Checks {
+		fmt.Fprintf(&sb, "- %s: %d%% (weight %g) - %s\n", c.Name, int(math.Round(100*c.Score)), c.Weight, c.Detail)
+	}
+	if r.BuildFailed {
+		sb.WriteString("\nThe build fails, so the work can't be submitted whatever the score.\n")
+	}
+	for _, c := range r.Checks {
+		if c.Output != "" {
+			fmt.Fprintf(&sb, "\n### %s output\n\n```\n%s\n```\n", c.Name, c.Output)
+		}
+	}
+	return sb.String()
+}
+
+func tail(out string) string {
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) > outputTail {
+		lines = lines[len(lines)-outputTail:]
+	}
+	return strings.Join(lines, "\n")
+}
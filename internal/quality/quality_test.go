@@ -0,0 +1,91 @@
+package quality
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestConfigFrom(t *testing.T) {
+	if _, ok := ConfigFrom(&config.RigSettings{}); ok {
+		t.Error("gate on without quality_gate settings")
+	}
+	cfg, ok := ConfigFrom(&config.RigSettings{
+		MergeQueue:  &config.MergeQueueConfig{TestCommand: "go test ./..."},
+		QualityGate: &config.QualityGateConfig{Enabled: true, Weights: map[string]float64{CheckDiff: 0}},
+	})
+	if !ok {
+		t.Fatal("gate off with quality_gate.enabled")
+	}
+	if cfg.MinScore != DefaultMinScore || cfg.TestCommand != "go test ./..." || cfg.MaxDiffLines != DefaultMaxDiffLines {
+		t.Errorf("defaults not applied: %+v", cfg)
+	}
+	if cfg.Weights[CheckDiff] != 0 || cfg.Weights[CheckTests] != defaultWeights[CheckTests] {
+		t.Errorf("weights = %v", cfg.Weights)
+	}
+}
+
+func TestCountTests(t *testing.T) {
+	tests := []struct {
+		out            string
+		passed, failed int
+	}{
+		{"=== RUN TestA\n--- PASS: TestA (0.00s)\n    --- PASS: TestA/sub (0.00s)\n--- FAIL: TestB (0.01s)\nFAIL", 2, 1},
+		{"===== 12 passed, 3 failed in 1.2s =====", 12, 3},
+		{"ok  \tpkg\t0.1s", 0, 0},
+	}
+	for _, tt := range tests {
+		if p, f := CountTests(tt.out); p != tt.passed || f != tt.failed {
+			t.Errorf("CountTests(%q) = %d, %d; want %d, %d", tt.out, p, f, tt.passed, tt.failed)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	cfg, _ := ConfigFrom(&config.RigSettings{QualityGate: &config.QualityGateConfig{
+		Enabled:      true,
+		BuildCommand: "build",
+		TestCommand:  "test",
+		LintCommand:  "lint",
+		MaxDiffLines: 100,
+	}})
+	outputs := map[string]string{
+		"test": "--- PASS: TestA\n--- PASS: TestB\n--- PASS: TestC\n--- FAIL: TestD\n",
+		"lint": "a.go:1: unused\nb.go:2: shadow\n",
+	}
+	failing := map[string]bool{"test": true, "lint": true}
+	run := func(dir, command string) (string, error) {
+		if failing[command] {
+			return outputs[command], errors.New("exit status 1")
+		}
+		return outputs[command], nil
+	}
+	diff := &git.DiffStat{Insertions: 150, Deletions: 50, Files: make([]git.FileStat, 4)}
+
+	r := Score(cfg, "/work", diff, run)
+	// build 1*3 + tests 0.75*4 + lint 0.9*2 + diff 0.5*1 = 8.3 of 10
+	if r.Score != 83 || !r.Passed {
+		t.Errorf("score = %d, passed = %v; want 83, passed", r.Score, r.Passed)
+	}
+	md := r.Markdown()
+	for _, want := range []string{"Quality gate: 83/100 (passed, needs 70)", "- tests: 75% (weight 4) - 3/4 passed", "over the 100-line limit", "### lint output"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("report missing %q:\n%s", want, md)
+		}
+	}
+
+	// A failed build bounces the work even if the rest scores well
+	failing["build"] = true
+	cfg.MinScore = 50
+	if r := Score(cfg, "/work", nil, run); r.Passed || !r.BuildFailed {
+		t.Errorf("failed build passed the gate: %+v", r)
+	}
+
+	// No checks configured: nothing to hold the work back
+	if r := Score(Config{MinScore: 70}, "/work", nil, run); r.Score != 100 || !r.Passed {
+		t.Errorf("empty gate = %+v", r)
+	}
+}
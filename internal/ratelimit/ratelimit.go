@@ -0,0 +1,239 @@
+// Package ratelimit paces model API requests across the town, so a convoy
+// of polecats doesn't trip a provider's limits and fail together.
+//
+// Each provider has a token bucket sized by the town's rate_limits
+// settings, and a backoff: when any agent reports a 429, every caller of
+// the provider waits, twice as long for each 429 in a row up to a cap,
+// until a request succeeds. The state lives in .runtime/ratelimit.json
+// under a file lock, so polecats, gt sling and the daemon share it.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// StateFile holds the buckets and backoffs within the town's runtime
+// directory.
+const StateFile = "ratelimit.json"
+
+// BaseBackoff is the wait after a first 429.
+const BaseBackoff = 5 * time.Second
+
+// DefaultMaxBackoff caps the wait after repeated 429s.
+const DefaultMaxBackoff = 5 * time.Minute
+
+// Provider is one provider's shared state.
+type Provider struct {
+	Tokens  float64   `json:"tokens"`
+	Updated time.Time `json:"updated"`
+
+	// Strikes counts 429s since the last success.
+	Strikes      int       `json:"strikes,omitempty"`
+	BackoffUntil time.Time `json:"backoff_until,omitempty"`
+}
+
+// Limiter paces one town's requests.
+type Limiter struct {
+	townRoot string
+	limits   map[string]*config.RateLimitConfig
+	now      func() time.Time
+}
+
+// New returns a limiter for a town with the given per-provider limits.
+func New(townRoot string, limits map[string]*config.RateLimitConfig) *Limiter {
+	return &Limiter{townRoot: townRoot, limits: limits, now: time.Now}
+}
+
+// Load returns a limiter with the town's configured limits.
+func Load(townRoot string) (*Limiter, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return New(townRoot, settings.RateLimits), nil
+}
+
+// rate returns the provider's refill rate per second and bucket size; a
+// zero rate is unlimited.
+func (l *Limiter) rate(provider string) (perSecond, burst float64) {
+	cfg := l.limits[provider]
+	if cfg == nil || cfg.RequestsPerMinute <= 0 {
+		return 0, 0
+	}
+	burst = float64(cfg.Burst)
+	if burst <= 0 {
+		burst = float64(cfg.RequestsPerMinute)
+	}
+	return float64(cfg.RequestsPerMinute) / 60, burst
+}
+
+func (l *Limiter) maxBackoff(provider string) time.Duration {
+	if cfg := l.limits[provider]; cfg != nil && cfg.MaxBackoffSeconds > 0 {
+		return time.Duration(cfg.MaxBackoffSeconds) * time.Second
+	}
+	return DefaultMaxBackoff
+}
+
+// Take spends a request from the provider's budget and returns 0, or,
+// when the provider is backing off or its bucket is empty, spends nothing
+// and returns how long to wait before trying again.
+func (l *Limiter) Take(provider string) (time.Duration, error) {
+	var wait time.Duration
+	err := l.update(func(s map[string]*Provider) {
+		now := l.now()
+		p := l.refill(s, provider, now)
+		if now.Before(p.BackoffUntil) {
+			wait = p.BackoffUntil.Sub(now)
+			return
+		}
+		perSecond, _ := l.rate(provider)
+		if perSecond == 0 {
+			return
+		}
+		if p.Tokens < 1 {
+			wait = time.Duration((1 - p.Tokens) / perSecond * float64(time.Second))
+			return
+		}
+		p.Tokens--
+	})
+	return wait, err
+}
+
+// Wait blocks until a request to the provider may go, then spends it.
+func (l *Limiter) Wait(ctx context.Context, provider string) error {
+	for {
+		wait, err := l.Take(provider)
+		if err != nil || wait == 0 {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Hit records a 429 from the provider and returns how long every caller
+// now waits: doubling with each 429 in a row, capped, and never less than
+// the provider's Retry-After.
+func (l *Limiter) Hit(provider string, retryAfter time.Duration) (time.Duration, error) {
+	var backoff time.Duration
+	err := l.update(func(s map[string]*Provider) {
+		now := l.now()
+		p := l.refill(s, provider, now)
+		p.Strikes++
+		backoff = time.Duration(float64(BaseBackoff) * math.Pow(2, float64(p.Strikes-1)))
+		if max := l.maxBackoff(provider); backoff > max || backoff <= 0 {
+			backoff = max
+		}
+		if retryAfter > backoff {
+			backoff = retryAfter
+		}
+		if until := now.Add(backoff); until.After(p.BackoffUntil) {
+			p.BackoffUntil = until
+		}
+		p.Tokens = 0
+	})
+	return backoff, err
+}
+
+// Succeeded records a request the provider answered, ending any backoff
+// streak.
+func (l *Limiter) Succeeded(provider string) error {
+	return l.update(func(s map[string]*Provider) {
+		if p := s[provider]; p != nil {
+			p.Strikes = 0
+		}
+	})
+}
+
+// Status returns every provider's state, refilled to now.
+func (l *Limiter) Status() (map[string]*Provider, error) {
+	s, err := load(l.townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for name := range l.limits {
+		if s[name] == nil {
+			s[name] = &Provider{}
+		}
+	}
+	now := l.now()
+	for name := range s {
+		l.refill(s, name, now)
+	}
+	return s, nil
+}
+
+// refill returns the provider's state with tokens added for the time
+// since its last update.
+func (l *Limiter) refill(s map[string]*Provider, provider string, now time.Time) *Provider {
+	perSecond, burst := l.rate(provider)
+	p := s[provider]
+	if p == nil {
+		p = &Provider{Tokens: burst, Updated: now}
+		s[provider] = p
+	}
+	if elapsed := now.Sub(p.Updated).Seconds(); elapsed > 0 {
+		p.Tokens = math.Min(burst, p.Tokens+elapsed*perSecond)
+	}
+	if p.Tokens > burst {
+		p.Tokens = burst // The limit was lowered
+	}
+	p.Updated = now
+	return p
+}
+
+func statePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, StateFile)
+}
+
+func load(townRoot string) (map[string]*Provider, error) {
+	s := map[string]*Provider{}
+	data, err := os.ReadFile(statePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StateFile, err)
+	}
+	if s == nil {
+		s = map[string]*Provider{}
+	}
+	return s, nil
+}
+
+// update loads the state, applies fn and saves it under the state lock.
+func (l *Limiter) update(fn func(map[string]*Provider)) error {
+	path := statePath(l.townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking rate limit state: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	s, err := load(l.townRoot)
+	if err != nil {
+		return err
+	}
+	fn(s)
+	return util.AtomicWriteJSON(path, s)
+}
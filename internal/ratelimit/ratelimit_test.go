@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func newTestLimiter(t *testing.T, limits map[string]*config.RateLimitConfig) (*Limiter, *time.Time) {
+	t.Helper()
+	now := time.Date(2026, 1, 7, 12, 0, 0, 0, time.UTC)
+	l := New(t.TempDir(), limits)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestTakeBucket(t *testing.T) {
+	l, now := newTestLimiter(t, map[string]*config.RateLimitConfig{
+		"anthropic": {RequestsPerMinute: 60, Burst: 2},
+	})
+	for i := 0; i < 2; i++ {
+		if wait, err := l.Take("anthropic"); err != nil || wait != 0 {
+			t.Fatalf("Take %d = %v, %v; want immediate", i, wait, err)
+		}
+	}
+	wait, err := l.Take("anthropic")
+	if err != nil || wait != time.Second {
+		t.Fatalf("Take on empty bucket = %v, %v; want 1s", wait, err)
+	}
+
+	*now = now.Add(time.Second)
+	if wait, _ := l.Take("anthropic"); wait != 0 {
+		t.Errorf("Take after refill = %v, want immediate", wait)
+	}
+
+	// Unconfigured providers are unpaced
+	for i := 0; i < 10; i++ {
+		if wait, _ := l.Take("openai"); wait != 0 {
+			t.Fatalf("Take(openai) = %v, want immediate", wait)
+		}
+	}
+}
+
+func TestHitBackoff(t *testing.T) {
+	l, now := newTestLimiter(t, map[string]*config.RateLimitConfig{
+		"anthropic": {MaxBackoffSeconds: 12},
+	})
+	for _, want := range []time.Duration{5 * time.Second, 10 * time.Second, 12 * time.Second} {
+		got, err := l.Hit("anthropic", 0)
+		if err != nil || got != want {
+			t.Fatalf("Hit = %v, %v; want %v", got, err, want)
+		}
+	}
+	if wait, _ := l.Take("anthropic"); wait != 12*time.Second {
+		t.Errorf("Take while backing off = %v, want 12s", wait)
+	}
+	if got, _ := l.Hit("anthropic", time.Minute); got != time.Minute {
+		t.Errorf("Hit with Retry-After = %v, want 1m", got)
+	}
+
+	*now = now.Add(time.Minute)
+	if wait, _ := l.Take("anthropic"); wait != 0 {
+		t.Errorf("Take after backoff = %v, want immediate", wait)
+	}
+	if err := l.Succeeded("anthropic"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := l.Hit("anthropic", 0); got != BaseBackoff {
+		t.Errorf("Hit after success = %v, want %v", got, BaseBackoff)
+	}
+}
+
+func TestSharedState(t *testing.T) {
+	a, _ := newTestLimiter(t, nil)
+	b := New(a.townRoot, nil)
+	b.now = a.now
+	if _, err := a.Hit("anthropic", 0); err != nil {
+		t.Fatal(err)
+	}
+	if wait, _ := b.Take("anthropic"); wait != BaseBackoff {
+		t.Errorf("second limiter waits %v, want %v", wait, BaseBackoff)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Wait(ctx, "anthropic"); err == nil {
+		t.Error("Wait returned without waiting out the backoff")
+	}
+}
@@ -0,0 +1,273 @@
+// Package recycle is the context recycling policy for long-lived agent
+// sessions (Mayor, Deacon, witnesses, refineries, crew).
+//
+// Quality drops as a session's context window fills, long before the
+// runtime compacts it. After each turn the agent's Stop hook runs
+// 'gt context check', which reads how full the window is from the
+// session transcript and records it here. Past the policy's threshold the
+// agent is asked to write a summary of where it is and hand off
+// (gt handoff), so a fresh session starts from its hook and the summary.
+// If it carries on regardless and reaches the force threshold, the
+// session is handed off for it with the collected state.
+//
+// Each session's last reading is a JSON file at
+// <town>/.runtime/context/<session>.json.
+package recycle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Policy defaults.
+const (
+	DefaultThresholdPercent = 70
+	DefaultForcePercent     = 85
+	DefaultWindowTokens     = 200000
+)
+
+// DefaultRoles are the roles recycled when the settings name none.
+// Polecats are left out: they end with their bead (see gt done).
+var DefaultRoles = []string{"mayor", "deacon", "witness", "refinery", "crew"}
+
+// DirName is the context usage directory within the town's .runtime
+// directory.
+const DirName = "context"
+
+// tailBytes is how much of the end of a transcript is searched for the
+// last turn's usage.
+const tailBytes = 512 * 1024
+
+// Action is what the policy wants done with a session.
+type Action string
+
+const (
+	ActionNone  Action = ""
+	ActionAsk   Action = "ask"   // Ask the agent to summarize and hand off
+	ActionForce Action = "force" // Hand the session off for it
+)
+
+// Policy holds the recycling thresholds.
+type Policy struct {
+	Enabled   bool
+	Threshold int // Percent of the window at which the agent is asked
+	Force     int // Percent at which the session is handed off regardless
+	Window    int64
+	Roles     map[string]bool
+}
+
+// NewPolicy builds a policy from town settings, applying defaults. A nil
+// config recycles the default roles at the default thresholds.
+func NewPolicy(cfg *config.ContextRecycleConfig) Policy {
+	p := Policy{
+		Enabled:   true,
+		Threshold: DefaultThresholdPercent,
+		Force:     DefaultForcePercent,
+		Window:    DefaultWindowTokens,
+		Roles:     make(map[string]bool),
+	}
+	roles := DefaultRoles
+	if cfg != nil {
+		p.Enabled = !cfg.Disabled
+		if cfg.ThresholdPercent > 0 {
+			p.Threshold = cfg.ThresholdPercent
+		}
+		if cfg.ForcePercent > 0 {
+			p.Force = cfg.ForcePercent
+		}
+		if cfg.WindowTokens > 0 {
+			p.Window = cfg.WindowTokens
+		}
+		if len(cfg.Roles) > 0 {
+			roles = cfg.Roles
+		}
+	}
+	if p.Force < p.Threshold {
+		p.Force = p.Threshold
+	}
+	for _, r := range roles {
+		p.Roles[r] = true
+	}
+	return p
+}
+
+// Percent is how full a window of p.Window tokens is with tokens in use.
+func (p Policy) Percent(tokens int64) int {
+	if p.Window <= 0 {
+		return 0
+	}
+	return int(tokens * 100 / p.Window)
+}
+
+// Decide returns what to do with a session of role at percent full. asked
+// is whether the agent has already been asked to hand off.
+func (p Policy) Decide(role string, percent int, asked bool) Action {
+	switch {
+	case !p.Enabled || !p.Roles[role]:
+		return ActionNone
+	case percent >= p.Force && asked:
+		return ActionForce
+	case percent >= p.Threshold && !asked:
+		return ActionAsk
+	default:
+		return ActionNone
+	}
+}
+
+// transcriptLine is the part of a Claude Code transcript entry that
+// carries token usage.
+type transcriptLine struct {
+	Type        string `json:"type"`
+	IsSidechain bool   `json:"isSidechain"`
+	Message     struct {
+		Usage *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// ReadUsage returns the tokens in a session's context window after its
+// last turn: the prompt of the last main-thread assistant message in a
+// Claude Code transcript, cached or not, plus its output. Returns 0 when
+// the transcript has no usage yet.
+func ReadUsage(transcriptPath string) (int64, error) {
+	f, err := os.Open(transcriptPath) //nolint:gosec // G304: path comes from the agent runtime's hook
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	offset := info.Size() - tailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return 0, err
+	}
+	return lastUsage(data), nil
+}
+
+// lastUsage finds the last usage in transcript lines. A leading partial
+// line (from reading a tail) fails to parse and is skipped.
+func lastUsage(data []byte) int64 {
+	var tokens int64
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), tailBytes)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if !bytes.Contains(line, []byte(`"usage"`)) {
+			continue
+		}
+		var tl transcriptLine
+		if err := json.Unmarshal(line, &tl); err != nil {
+			continue
+		}
+		if tl.Type != "assistant" || tl.IsSidechain || tl.Message.Usage == nil {
+			continue
+		}
+		u := tl.Message.Usage
+		tokens = u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens + u.OutputTokens
+	}
+	return tokens
+}
+
+// Reading is a session's last recorded context usage.
+type Reading struct {
+	Session    string    `json:"session"`
+	Role       string    `json:"role"`
+	Transcript string    `json:"transcript,omitempty"`
+	Tokens     int64     `json:"tokens"`
+	Window     int64     `json:"window"`
+	Percent    int       `json:"percent"`
+	Asked      time.Time `json:"asked,omitempty"` // When the agent was asked to hand off
+	Updated    time.Time `json:"updated"`
+}
+
+// Dir returns the context usage directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, DirName)
+}
+
+// Path returns a session's reading file.
+func Path(townRoot, session string) string {
+	return filepath.Join(Dir(townRoot), session+".json")
+}
+
+// Read returns a session's last reading, or nil if it has none.
+func Read(townRoot, session string) (*Reading, error) {
+	data, err := os.ReadFile(Path(townRoot, session)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r Reading
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing context reading of %s: %w", session, err)
+	}
+	return &r, nil
+}
+
+// Write records a session's reading.
+func Write(townRoot string, r *Reading) error {
+	if r.Session == "" || strings.ContainsAny(r.Session, `/\`) {
+		return fmt.Errorf("invalid session name %q", r.Session)
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating context directory: %w", err)
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(Path(townRoot, r.Session), data, 0644)
+}
+
+// List returns every session's last reading, fullest first.
+func List(townRoot string) ([]*Reading, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var readings []*Reading
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if r, err := Read(townRoot, name); err == nil && r != nil {
+			readings = append(readings, r)
+		}
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		if readings[i].Percent != readings[j].Percent {
+			return readings[i].Percent > readings[j].Percent
+		}
+		return readings[i].Session < readings[j].Session
+	})
+	return readings, nil
+}
@@ -0,0 +1,93 @@
+package recycle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestDecide(t *testing.T) {
+	p := NewPolicy(nil)
+	tests := []struct {
+		role    string
+		percent int
+		asked   bool
+		want    Action
+	}{
+		{"mayor", 50, false, ActionNone},
+		{"mayor", 70, false, ActionAsk},
+		{"mayor", 80, true, ActionNone}, // Asked; not yet forced
+		{"mayor", 90, true, ActionForce},
+		{"crew", 95, false, ActionAsk}, // Always asked first
+		{"polecat", 95, true, ActionNone},
+	}
+	for _, tt := range tests {
+		if got := p.Decide(tt.role, tt.percent, tt.asked); got != tt.want {
+			t.Errorf("Decide(%s, %d%%, asked=%v) = %q, want %q", tt.role, tt.percent, tt.asked, got, tt.want)
+		}
+	}
+
+	p = NewPolicy(&config.ContextRecycleConfig{ThresholdPercent: 60, ForcePercent: 50, Roles: []string{"polecat"}})
+	if p.Force != 60 {
+		t.Errorf("Force = %d, want raised to the threshold 60", p.Force)
+	}
+	if p.Decide("mayor", 99, true) != ActionNone || p.Decide("polecat", 60, false) != ActionAsk {
+		t.Errorf("configured roles not applied: %+v", p)
+	}
+	if NewPolicy(&config.ContextRecycleConfig{Disabled: true}).Decide("mayor", 99, true) != ActionNone {
+		t.Error("disabled policy still recycles")
+	}
+}
+
+func TestReadUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	lines := `{"type":"user","message":{"content":"hi"}}
+{"type":"assistant","message":{"usage":{"input_tokens":10,"cache_creation_input_tokens":1000,"cache_read_input_tokens":20000,"output_tokens":50}}}
+{"type":"assistant","isSidechain":true,"message":{"usage":{"input_tokens":999999}}}
+{"type":"assistant","message":{"usage":{"input_tokens":5,"cache_creation_input_tokens":500,"cache_read_input_tokens":120000,"output_tokens":100}}}
+{"type":"user","message":{"content":"more"}}
+`
+	if err := os.WriteFile(path, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := ReadUsage(path)
+	if err != nil {
+		t.Fatalf("ReadUsage: %v", err)
+	}
+	if tokens != 120605 {
+		t.Errorf("tokens = %d, want 120605 from the last main-thread turn", tokens)
+	}
+	if pct := NewPolicy(nil).Percent(tokens); pct != 60 {
+		t.Errorf("Percent = %d, want 60", pct)
+	}
+}
+
+func TestReadingsRoundTrip(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+	for _, r := range []*Reading{
+		{Session: "hq-mayor", Role: "mayor", Tokens: 40000, Window: 200000, Percent: 20, Updated: now},
+		{Session: "gt-gastown-witness", Role: "witness", Tokens: 150000, Window: 200000, Percent: 75, Asked: now, Updated: now},
+	} {
+		if err := Write(town, r); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := Write(town, &Reading{Session: "../x"}); err == nil {
+		t.Error("Write accepted a session name with a path separator")
+	}
+
+	readings, err := List(town)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(readings) != 2 || readings[0].Session != "gt-gastown-witness" || !readings[0].Asked.Equal(now) {
+		t.Errorf("List = %+v, want the witness first", readings)
+	}
+	if r, err := Read(town, "hq-deacon"); r != nil || err != nil {
+		t.Errorf("Read(missing) = %+v, %v", r, err)
+	}
+}
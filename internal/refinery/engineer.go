@@ -5,8 +5,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,11 +16,20 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/flakes"
+	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/mrqueue"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/vcs"
 )
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -35,6 +46,11 @@ type MergeQueueConfig struct {
 	// OnConflict is the strategy for handling conflicts: "assign_back" or "auto_rebase".
 	OnConflict string `json:"on_conflict"`
 
+	// AutoResolveConflicts slings the conflict-resolution molecule on each
+	// conflict task as it is created, to the original polecat when it is
+	// still running and to a fresh one otherwise.
+	AutoResolveConflicts bool `json:"auto_resolve_conflicts"`
+
 	// RunTests controls whether to run tests before merging.
 	RunTests bool `json:"run_tests"`
 
@@ -47,11 +63,36 @@ type MergeQueueConfig struct {
 	// RetryFlakyTests is the number of times to retry flaky tests.
 	RetryFlakyTests int `json:"retry_flaky_tests"`
 
+	// FlakyThreshold is the failure rate on the target branch at or above
+	// which a test is flaky: a branch whose only failures are flaky tests
+	// merges anyway. Zero turns flake detection off.
+	FlakyThreshold float64 `json:"flaky_threshold"`
+
+	// FlakyMinRuns is how many target-branch runs a test needs before its
+	// failure rate counts.
+	FlakyMinRuns int `json:"flaky_min_runs"`
+
 	// PollInterval is how often to check for new MRs.
 	PollInterval time.Duration `json:"poll_interval"`
 
 	// MaxConcurrent is the maximum number of MRs to process concurrently.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// MaxTrainLength caps how many MRs a merge train merges and tests
+	// together. Below 2, trains are off and MRs merge one at a time.
+	MaxTrainLength int `json:"max_train_length"`
+
+	// TrainQueueDepth is how many ready MRs the queue needs before they
+	// merge in trains.
+	TrainQueueDepth int `json:"train_queue_depth"`
+
+	// PostMergeCommand runs after each successful merge is pushed, e.g. a
+	// deploy script or container build. Its output is commented on the
+	// merged beads; a failure files an escalation.
+	PostMergeCommand string `json:"post_merge_command"`
+
+	// PostMergeTimeout is how long PostMergeCommand may run.
+	PostMergeTimeout time.Duration `json:"post_merge_timeout"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -61,12 +102,19 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		TargetBranch:         "main",
 		IntegrationBranches:  true,
 		OnConflict:           "assign_back",
+		AutoResolveConflicts: true,
 		RunTests:             true,
 		TestCommand:          "",
 		DeleteMergedBranches: true,
 		RetryFlakyTests:      1,
+		FlakyThreshold:       0.2,
+		FlakyMinRuns:         5,
 		PollInterval:         30 * time.Second,
 		MaxConcurrent:        1,
+		MaxTrainLength:       0,
+		TrainQueueDepth:      3,
+		PostMergeCommand:     "",
+		PostMergeTimeout:     30 * time.Minute,
 	}
 }
 
@@ -76,10 +124,11 @@ type Engineer struct {
 	rig         *rig.Rig
 	beads       *beads.Beads
 	mrQueue     *mrqueue.Queue
-	git         *git.Git
+	git         vcs.Repo
 	config      *MergeQueueConfig
 	workDir     string
 	output      io.Writer // Output destination for user-facing messages
+	log         *slog.Logger
 	eventLogger *mrqueue.EventLogger
 	router      *mail.Router // Mail router for sending protocol messages
 
@@ -105,10 +154,11 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		rig:         r,
 		beads:       beads.New(r.Path),
 		mrQueue:     mrqueue.New(r.Path),
-		git:         git.NewGit(gitDir),
+		git:         vcs.Open(r.VCS, gitDir),
 		config:      cfg,
 		workDir:     gitDir,
 		output:      os.Stdout,
+		log:         logging.For("refinery").With(logging.KeyRig, r.Name),
 		eventLogger: mrqueue.NewEventLoggerFromRig(r.Path),
 		router:      mail.NewRouter(r.Path),
 		stopCh:      make(chan struct{}),
@@ -149,16 +199,23 @@ func (e *Engineer) LoadConfig() error {
 	// Parse merge_queue section into our config struct
 	// We need special handling for poll_interval (string -> Duration)
 	var mqRaw struct {
-		Enabled              *bool   `json:"enabled"`
-		TargetBranch         *string `json:"target_branch"`
-		IntegrationBranches  *bool   `json:"integration_branches"`
-		OnConflict           *string `json:"on_conflict"`
-		RunTests             *bool   `json:"run_tests"`
-		TestCommand          *string `json:"test_command"`
-		DeleteMergedBranches *bool   `json:"delete_merged_branches"`
-		RetryFlakyTests      *int    `json:"retry_flaky_tests"`
-		PollInterval         *string `json:"poll_interval"`
-		MaxConcurrent        *int    `json:"max_concurrent"`
+		Enabled              *bool    `json:"enabled"`
+		TargetBranch         *string  `json:"target_branch"`
+		IntegrationBranches  *bool    `json:"integration_branches"`
+		OnConflict           *string  `json:"on_conflict"`
+		AutoResolveConflicts *bool    `json:"auto_resolve_conflicts"`
+		RunTests             *bool    `json:"run_tests"`
+		TestCommand          *string  `json:"test_command"`
+		DeleteMergedBranches *bool    `json:"delete_merged_branches"`
+		RetryFlakyTests      *int     `json:"retry_flaky_tests"`
+		FlakyThreshold       *float64 `json:"flaky_threshold"`
+		FlakyMinRuns         *int     `json:"flaky_min_runs"`
+		PollInterval         *string  `json:"poll_interval"`
+		MaxConcurrent        *int     `json:"max_concurrent"`
+		MaxTrainLength       *int     `json:"max_train_length"`
+		TrainQueueDepth      *int     `json:"train_queue_depth"`
+		PostMergeCommand     *string  `json:"post_merge_command"`
+		PostMergeTimeout     *string  `json:"post_merge_timeout"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -178,6 +235,9 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.OnConflict != nil {
 		e.config.OnConflict = *mqRaw.OnConflict
 	}
+	if mqRaw.AutoResolveConflicts != nil {
+		e.config.AutoResolveConflicts = *mqRaw.AutoResolveConflicts
+	}
 	if mqRaw.RunTests != nil {
 		e.config.RunTests = *mqRaw.RunTests
 	}
@@ -190,9 +250,24 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.RetryFlakyTests != nil {
 		e.config.RetryFlakyTests = *mqRaw.RetryFlakyTests
 	}
+	if mqRaw.FlakyThreshold != nil {
+		e.config.FlakyThreshold = *mqRaw.FlakyThreshold
+	}
+	if mqRaw.FlakyMinRuns != nil {
+		e.config.FlakyMinRuns = *mqRaw.FlakyMinRuns
+	}
 	if mqRaw.MaxConcurrent != nil {
 		e.config.MaxConcurrent = *mqRaw.MaxConcurrent
 	}
+	if mqRaw.MaxTrainLength != nil {
+		e.config.MaxTrainLength = *mqRaw.MaxTrainLength
+	}
+	if mqRaw.TrainQueueDepth != nil {
+		e.config.TrainQueueDepth = *mqRaw.TrainQueueDepth
+	}
+	if mqRaw.PostMergeCommand != nil {
+		e.config.PostMergeCommand = *mqRaw.PostMergeCommand
+	}
 	if mqRaw.PollInterval != nil {
 		dur, err := time.ParseDuration(*mqRaw.PollInterval)
 		if err != nil {
@@ -200,6 +275,13 @@ func (e *Engineer) LoadConfig() error {
 		}
 		e.config.PollInterval = dur
 	}
+	if mqRaw.PostMergeTimeout != nil {
+		dur, err := time.ParseDuration(*mqRaw.PostMergeTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid post_merge_timeout %q: %w", *mqRaw.PostMergeTimeout, err)
+		}
+		e.config.PostMergeTimeout = dur
+	}
 
 	return nil
 }
@@ -209,6 +291,11 @@ func (e *Engineer) Config() *MergeQueueConfig {
 	return e.config
 }
 
+// FlakePolicy returns the flake detection settings.
+func (c *MergeQueueConfig) FlakePolicy() flakes.Policy {
+	return flakes.Policy{Threshold: c.FlakyThreshold, MinRuns: c.FlakyMinRuns}
+}
+
 // ProcessResult contains the result of processing a merge request.
 type ProcessResult struct {
 	Success     bool
@@ -216,8 +303,26 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
+
+	// Conflicts and ConflictHunks describe a conflict: the conflicted
+	// files and their diff with conflict markers.
+	Conflicts     []string
+	ConflictHunks string
+
+	// FlakyTests are failing tests let through because they are flaky on
+	// the target branch.
+	FlakyTests []string
+
+	// PostMerge is the post-merge command's run, if one is configured.
+	PostMerge *PostMergeResult
 }
 
+// conflictHunksMax caps the conflict hunks embedded in a conflict task.
+const conflictHunksMax = 16 * 1024
+
+// ConflictMolecule is the formula slung on conflict-resolution tasks.
+const ConflictMolecule = "mol-polecat-conflict-resolve"
+
 // ProcessMR processes a single merge request from a beads issue.
 func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult {
 	// Parse MR fields from description
@@ -234,6 +339,8 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Branch: %s\n", mrFields.Branch)
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mrFields.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mrFields.Worker)
+	e.log.Info("processing merge request", "mr", mr.ID, "branch", mrFields.Branch,
+		"target", mrFields.Target, logging.KeyPolecat, mrFields.Worker, logging.KeyBead, mrFields.SourceIssue)
 
 	return e.doMerge(ctx, mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
 }
@@ -241,8 +348,15 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 // doMerge performs the actual git merge operation.
 // This is the core merge logic shared by ProcessMR and ProcessMRFromQueue.
 func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+	stateLock, err := e.lockRig()
+	if err != nil {
+		return ProcessResult{Success: false, Error: err.Error()}
+	}
+	defer func() { _ = stateLock.Release() }()
+
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
+	e.log.Debug("checking local branch", "branch", branch, "target", target)
 	exists, err := e.git.BranchExists(branch)
 	if err != nil {
 		return ProcessResult{
@@ -274,7 +388,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 
 	// Step 3: Check for merge conflicts (using local branch)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
-	conflicts, err := e.git.CheckConflicts(branch, target)
+	conflicts, hunks, err := e.git.CheckConflictHunks(branch, target, conflictHunksMax)
 	if err != nil {
 		return ProcessResult{
 			Success:  false,
@@ -284,27 +398,23 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 	if len(conflicts) > 0 {
 		return ProcessResult{
-			Success:  false,
-			Conflict: true,
-			Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
+			Success:       false,
+			Conflict:      true,
+			Error:         fmt.Sprintf("merge conflicts in: %v", conflicts),
+			Conflicts:     conflicts,
+			ConflictHunks: hunks,
 		}
 	}
 
-	// Step 4: Run tests if configured
-	if e.config.RunTests && e.config.TestCommand != "" {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
-		if !result.Success {
-			return ProcessResult{
-				Success:     false,
-				TestsFailed: true,
-				Error:       result.Error,
-			}
+	// Step 4: Perform the actual merge, remembering where target was so a
+	// failed test run can undo it
+	premerge, err := e.git.Rev("HEAD")
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get %s SHA: %v", target, err),
 		}
-		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
 	}
-
-	// Step 5: Perform the actual merge
 	mergeMsg := fmt.Sprintf("Merge %s into %s", branch, target)
 	if sourceIssue != "" {
 		mergeMsg = fmt.Sprintf("Merge %s into %s (%s)", branch, target, sourceIssue)
@@ -317,9 +427,10 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		if conflictErr == nil && len(conflicts) > 0 {
 			_ = e.git.AbortMerge()
 			return ProcessResult{
-				Success:  false,
-				Conflict: true,
-				Error:    "merge conflict during actual merge",
+				Success:   false,
+				Conflict:  true,
+				Error:     "merge conflict during actual merge",
+				Conflicts: conflicts,
 			}
 		}
 		return ProcessResult{
@@ -328,6 +439,29 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Step 5: Run tests on the merged tree if configured
+	var flaky []string
+	if e.config.RunTests && e.config.TestCommand != "" {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
+		result := e.runTests(ctx, branch, target)
+		if !result.Success {
+			if err := e.git.ResetHard(premerge); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: resetting %s after failed tests: %v\n", target, err)
+			}
+			return ProcessResult{
+				Success:     false,
+				TestsFailed: true,
+				Error:       result.Error,
+			}
+		}
+		flaky = result.FlakyTests
+		if len(flaky) > 0 {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Tests passed apart from flaky tests: %s\n", strings.Join(flaky, ", "))
+		} else {
+			_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+		}
+	}
+
 	// Step 6: Get the merge commit SHA
 	mergeCommit, err := e.git.Rev("HEAD")
 	if err != nil {
@@ -347,14 +481,29 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
+	var sources []string
+	if sourceIssue != "" {
+		sources = append(sources, sourceIssue)
+	}
 	return ProcessResult{
 		Success:     true,
 		MergeCommit: mergeCommit,
+		FlakyTests:  flaky,
+		PostMerge:   e.RunPostMerge(ctx, mergeCommit, target, sources),
 	}
 }
 
-// runTests runs the configured test command and returns the result.
-func (e *Engineer) runTests(ctx context.Context) ProcessResult {
+// lockRig takes the rig's state lock, so a merge doesn't interleave with
+// another merge in the refinery's clone or with gt rig add/remove.
+func (e *Engineer) lockRig() (*lock.StateLock, error) {
+	return lock.AcquireState(filepath.Dir(e.rig.Path), lock.RigScope(e.rig.Name), lock.DefaultStateWait)
+}
+
+// runTests runs the configured test command on the merged tree and returns
+// the result. With flake detection on, per-test results go into the rig's
+// test history, and when the only failures are tests that are flaky on
+// target (they fail there too), the run counts as passed.
+func (e *Engineer) runTests(ctx context.Context, branch, target string) ProcessResult {
 	if e.config.TestCommand == "" {
 		return ProcessResult{Success: true}
 	}
@@ -366,24 +515,25 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 
 	var lastErr error
+	var lastOutput string
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, maxRetries)
 		}
 
-		// Note: TestCommand comes from rig's config.json (trusted infrastructure config),
-		// not from PR branches. Shell execution is intentional for flexibility (pipes, etc).
-		cmd := exec.CommandContext(ctx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
-		cmd.Dir = e.workDir
-		var stdout, stderr bytes.Buffer
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-
-		err := cmd.Run()
+		output, err := e.testCommand(ctx, e.workDir)
+		if errors.Is(err, errDryRun) {
+			return ProcessResult{Success: true}
+		}
 		if err == nil {
+			// The merged tree is what target becomes, so its results are
+			// a target run
+			e.recordTestRun(func(h *flakes.History) {
+				h.RecordMain(flakes.ParseGoTest(output), time.Now())
+			})
 			return ProcessResult{Success: true}
 		}
-		lastErr = err
+		lastErr, lastOutput = err, output
 
 		// Check if context was canceled
 		if ctx.Err() != nil {
@@ -394,11 +544,113 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		}
 	}
 
-	return ProcessResult{
+	failure := ProcessResult{
 		Success:     false,
 		TestsFailed: true,
 		Error:       fmt.Sprintf("tests failed after %d attempts: %v", maxRetries, lastErr),
 	}
+	policy := e.config.FlakePolicy()
+	results := flakes.ParseGoTest(lastOutput)
+	failed := flakes.Failed(results)
+	if !policy.Enabled() || len(failed) == 0 {
+		return failure
+	}
+
+	// Run target on its own so failures there count against the test, not
+	// the branch
+	_, _ = fmt.Fprintf(e.output, "[Engineer] %d test(s) failed; running them on %s to check for flakes...\n", len(failed), target)
+	baseline := e.baselineResults(ctx, target)
+
+	var flaky, real []string
+	e.recordTestRun(func(h *flakes.History) {
+		now := time.Now()
+		h.RecordBranch(results, branch, now)
+		h.RecordMain(baseline, now)
+		flaky, real = h.Classify(failed, policy)
+	})
+	if len(flaky) == 0 {
+		// Nothing flaky, or the history couldn't be read
+		return failure
+	}
+	if len(real) > 0 {
+		failure.Error = fmt.Sprintf("tests failed after %d attempts: %s", maxRetries, strings.Join(real, ", "))
+		return failure
+	}
+	e.log.Warn("merging despite flaky tests", "branch", branch, "tests", flaky)
+	return ProcessResult{Success: true, FlakyTests: flaky}
+}
+
+// errDryRun reports that a test run was skipped for --dry-run.
+var errDryRun = errors.New("dry run")
+
+// testCommand runs the configured test command in dir and returns its
+// combined output. A path-scoped rig's tests run in its subdirectory.
+func (e *Engineer) testCommand(ctx context.Context, dir string) (string, error) {
+	return e.shellCommand(ctx, dir, e.config.TestCommand, nil)
+}
+
+// shellCommand runs a configured command through sh in dir, or the rig's
+// subdirectory of it, with the rig's env and extra ("NAME=value") added,
+// and returns its combined output.
+func (e *Engineer) shellCommand(ctx context.Context, dir, command string, extra []string) (string, error) {
+	// Note: commands come from rig's config.json (trusted infrastructure config),
+	// not from PR branches. Shell execution is intentional for flexibility (pipes, etc).
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: command is from trusted rig config
+	cmd.Dir = dir
+	if e.rig != nil && e.rig.Subdir != "" {
+		cmd.Dir = filepath.Join(dir, e.rig.Subdir)
+	}
+	// Commands get the rig's env, secrets included; running tests without
+	// a DATABASE_URL they need would fail the branch for the wrong reason
+	var env []string
+	if e.rig != nil {
+		rigEnv, err := config.ResolveRigEnv(e.rig.Path)
+		if err != nil {
+			return "", fmt.Errorf("resolving rig env: %w", err)
+		}
+		for name, value := range rigEnv {
+			env = append(env, name+"="+value)
+		}
+	}
+	if env = append(env, extra...); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if dryrun.Command(cmd) {
+		return "", errDryRun
+	}
+	err := cmd.Run()
+	return out.String(), err
+}
+
+// baselineResults runs the tests on origin/<target> in a scratch worktree
+// and returns the per-test results, or nil if the run couldn't be made.
+func (e *Engineer) baselineResults(ctx context.Context, target string) []flakes.Result {
+	scratch := filepath.Join(constants.RigRuntimePath(e.rig.Path), fmt.Sprintf("flake-baseline-%d", os.Getpid()))
+	if err := e.git.WorktreeAddDetached(scratch, "origin/"+target); err != nil {
+		e.log.Warn("creating baseline worktree", "target", target, "error", err)
+		return nil
+	}
+	defer func() { _ = e.git.WorktreeRemove(scratch, true) }()
+
+	output, err := e.testCommand(ctx, scratch)
+	if ctx.Err() != nil || errors.Is(err, errDryRun) {
+		return nil
+	}
+	return flakes.ParseGoTest(output)
+}
+
+// recordTestRun applies fn to the rig's test history. History is advisory,
+// so failing to save it only logs.
+func (e *Engineer) recordTestRun(fn func(*flakes.History)) {
+	if !e.config.FlakePolicy().Enabled() {
+		return
+	}
+	if err := flakes.Update(e.rig.Path, fn); err != nil {
+		e.log.Warn("recording test history", "error", err)
+	}
 }
 
 // handleSuccess handles a successful merge completion.
@@ -464,6 +716,7 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 
 	// 5. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
+	e.log.Info("merged", "mr", mr.ID, "commit", result.MergeCommit)
 }
 
 // handleFailure handles a failed merge request.
@@ -477,6 +730,7 @@ func (e *Engineer) handleFailure(mr *beads.Issue, result ProcessResult) {
 
 	// Log the failure
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
+	e.log.Warn("merge failed", "mr", mr.ID, "error", result.Error)
 }
 
 // ProcessMRFromQueue processes a merge request from wisp queue.
@@ -487,11 +741,14 @@ func (e *Engineer) ProcessMRFromQueue(ctx context.Context, mr *mrqueue.MR) Proce
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mr.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
+	e.log.Info("processing merge request", "mr", mr.ID, "branch", mr.Branch,
+		"target", mr.Target, logging.KeyPolecat, mr.Worker, logging.KeyBead, mr.SourceIssue)
 
 	// Emit merge_started event
 	if err := e.eventLogger.LogMergeStarted(mr); err != nil {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to log merge_started event: %v\n", err)
 	}
+	_ = events.LogFeed(events.TypeMergeStarted, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""))
 
 	// Use the shared merge logic
 	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
@@ -503,6 +760,9 @@ func (e *Engineer) handleSuccessFromQueue(mr *mrqueue.MR, result ProcessResult)
 	if err := e.eventLogger.LogMerged(mr, result.MergeCommit); err != nil {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to log merged event: %v\n", err)
 	}
+	_ = events.LogFeed(events.TypeMerged, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""))
+	e.log.Info("merged", "mr", mr.ID, "commit", result.MergeCommit,
+		logging.KeyPolecat, mr.Worker, logging.KeyBead, mr.SourceIssue)
 
 	// Release merge slot if this was a conflict resolution
 	// The slot is held while conflict resolution is in progress
@@ -589,6 +849,9 @@ func (e *Engineer) handleFailureFromQueue(mr *mrqueue.MR, result ProcessResult)
 	if err := e.eventLogger.LogMergeFailed(mr, result.Error); err != nil {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to log merge_failed event: %v\n", err)
 	}
+	_ = events.LogFeed(events.TypeMergeFailed, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mr.Worker, mr.Branch, result.Error))
+	e.log.Warn("merge failed", "mr", mr.ID, "error", result.Error, "conflict", result.Conflict,
+		"tests_failed", result.TestsFailed, logging.KeyPolecat, mr.Worker, logging.KeyBead, mr.SourceIssue)
 
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
@@ -611,14 +874,18 @@ func (e *Engineer) handleFailureFromQueue(mr *mrqueue.MR, result ProcessResult)
 		taskID, err := e.createConflictResolutionTask(mr, result)
 		if err != nil {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to create conflict resolution task: %v\n", err)
-		} else {
+		} else if taskID != "" {
 			// Block the MR on the conflict resolution task
 			// When the task closes, the MR unblocks and re-enters the ready queue
 			if err := e.mrQueue.SetBlockedBy(mr.ID, taskID); err != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to block MR on task: %v\n", err)
 			} else {
+				mr.BlockedBy = taskID
 				_, _ = fmt.Fprintf(e.output, "[Engineer] MR %s blocked on conflict task %s (non-blocking delegation)\n", mr.ID, taskID)
 			}
+			if e.config.AutoResolveConflicts {
+				e.dispatchConflictResolution(mr, taskID)
+			}
 		}
 	}
 
@@ -640,14 +907,14 @@ func (e *Engineer) handleFailureFromQueue(mr *mrqueue.MR, result ProcessResult)
 //   Type: task
 //   Priority: inherit from original + boost (P2 -> P1)
 //   Parent: original MR bead
-//   Description: metadata including branch, conflict SHA, etc.
+//   Description: metadata including branch, conflict SHA, conflicted files and hunks
 //
 // Merge Slot Integration:
 // Before creating a conflict resolution task, we acquire the merge-slot for this rig.
 // This serializes conflict resolution - only one polecat can resolve conflicts at a time.
 // If the slot is already held, we skip creating the task and let the MR stay in queue.
 // When the current resolution completes and merges, the slot is released.
-func (e *Engineer) createConflictResolutionTask(mr *mrqueue.MR, _ ProcessResult) (string, error) { // result unused but kept for future merge diagnostics
+func (e *Engineer) createConflictResolutionTask(mr *mrqueue.MR, result ProcessResult) (string, error) {
 	// === MERGE SLOT GATE: Serialize conflict resolution ===
 	// Ensure merge slot exists (idempotent)
 	slotID, err := e.beads.MergeSlotEnsureExists()
@@ -724,6 +991,7 @@ The Refinery will automatically retry the merge after you force-push.`,
 		mr.Branch,
 		mr.Target,
 	)
+	description += conflictDetails(result)
 
 	// Create the conflict resolution task
 	taskTitle := fmt.Sprintf("Resolve merge conflicts: %s", originalTitle)
@@ -749,6 +1017,77 @@ The Refinery will automatically retry the merge after you force-push.`,
 	return task.ID, nil
 }
 
+// conflictDetails renders a conflict's files and hunks for the conflict
+// task, so the polecat resolving it starts from what git saw.
+func conflictDetails(result ProcessResult) string {
+	if len(result.Conflicts) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\n## Conflicted files\n")
+	for _, f := range result.Conflicts {
+		fmt.Fprintf(&b, "- %s\n", f)
+	}
+	if result.ConflictHunks != "" {
+		b.WriteString("\n## Conflict hunks\n```diff\n")
+		b.WriteString(result.ConflictHunks)
+		b.WriteString("\n```\n")
+	}
+	return b.String()
+}
+
+// dispatchConflictResolution slings the conflict-resolution molecule on a
+// conflict task so the conflict gets worked instead of sitting in bd ready.
+// The original polecat takes it if its session is still running; otherwise
+// gt sling spawns a fresh polecat. Failures only warn: the task remains for
+// manual dispatch.
+func (e *Engineer) dispatchConflictResolution(mr *mrqueue.MR, taskID string) {
+	targets := []string{e.rig.Name}
+	if mr.Worker != "" {
+		if alive, _ := tmux.NewTmux().HasSession(session.PolecatSessionName(e.rig.Name, mr.Worker)); alive {
+			targets = append([]string{e.rig.Name + "/" + mr.Worker}, targets...)
+		}
+	}
+
+	for _, target := range targets {
+		cmd := exec.Command("gt", "sling", ConflictMolecule, "--on", taskID, target) //nolint:gosec // G204: args are bead and rig names
+		cmd.Dir = e.rig.Path
+		if dryrun.Command(cmd) {
+			return
+		}
+		out, err := cmd.CombinedOutput()
+		if err == nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Dispatched %s on %s to %s\n", ConflictMolecule, taskID, target)
+			e.log.Info("dispatched conflict resolution", "mr", mr.ID, "task", taskID, "target", target)
+			return
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to sling %s to %s: %v (%s)\n", taskID, target, err, strings.TrimSpace(string(out)))
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Conflict task %s left for manual dispatch: gt sling %s --on %s %s\n", taskID, ConflictMolecule, taskID, e.rig.Name)
+}
+
+// HandleConflict re-checks a queued MR against its target and, when it
+// conflicts, handles it as a failed merge: the witness is told, a conflict
+// task carrying the hunks is created, the MR is blocked on it and the task
+// is dispatched. It returns the conflict task's ID, or "" when the MR
+// merges cleanly or the merge slot deferred the task.
+func (e *Engineer) HandleConflict(mr *mrqueue.MR) (string, error) {
+	conflicts, hunks, err := e.git.CheckConflictHunks(mr.Branch, mr.Target, conflictHunksMax)
+	if err != nil {
+		return "", fmt.Errorf("checking %s against %s: %w", mr.Branch, mr.Target, err)
+	}
+	if len(conflicts) == 0 {
+		return "", nil
+	}
+	e.handleFailureFromQueue(mr, ProcessResult{
+		Conflict:      true,
+		Error:         fmt.Sprintf("merge conflicts in: %v", conflicts),
+		Conflicts:     conflicts,
+		ConflictHunks: hunks,
+	})
+	return mr.BlockedBy, nil
+}
+
 // IsBeadOpen checks if a bead is still open (not closed).
 // This is used as a status checker for mrqueue.ListReady to filter blocked MRs.
 func (e *Engineer) IsBeadOpen(beadID string) (bool, error) {
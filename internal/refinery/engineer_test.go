@@ -1,12 +1,16 @@
 package refinery
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/flakes"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -28,6 +32,9 @@ func TestDefaultMergeQueueConfig(t *testing.T) {
 	if cfg.OnConflict != "assign_back" {
 		t.Errorf("expected OnConflict to be 'assign_back', got %q", cfg.OnConflict)
 	}
+	if !cfg.AutoResolveConflicts {
+		t.Error("expected AutoResolveConflicts to be true by default")
+	}
 }
 
 func TestEngineer_LoadConfig_NoFile(t *testing.T) {
@@ -76,6 +83,12 @@ func TestEngineer_LoadConfig_WithMergeQueue(t *testing.T) {
 			"max_concurrent": 2,
 			"run_tests":      false,
 			"test_command":   "make test",
+
+			"auto_resolve_conflicts": false,
+			"flaky_threshold":        0.5,
+			"max_train_length":       4,
+			"post_merge_command":     "./deploy.sh",
+			"post_merge_timeout":     "15m",
 		},
 	}
 
@@ -111,6 +124,19 @@ func TestEngineer_LoadConfig_WithMergeQueue(t *testing.T) {
 	if e.config.TestCommand != "make test" {
 		t.Errorf("expected TestCommand 'make test', got %q", e.config.TestCommand)
 	}
+	if e.config.AutoResolveConflicts {
+		t.Error("expected AutoResolveConflicts false")
+	}
+	if p := e.config.FlakePolicy(); p.Threshold != 0.5 || p.MinRuns != 5 {
+		t.Errorf("expected flake policy 0.5 with the default 5 runs, got %+v", p)
+	}
+
+	if e.config.MaxTrainLength != 4 || e.config.TrainQueueDepth != 3 {
+		t.Errorf("expected train length 4 with the default depth 3, got %d and %d", e.config.MaxTrainLength, e.config.TrainQueueDepth)
+	}
+	if e.config.PostMergeCommand != "./deploy.sh" || e.config.PostMergeTimeout != 15*time.Minute {
+		t.Errorf("expected post-merge ./deploy.sh for 15m, got %q for %v", e.config.PostMergeCommand, e.config.PostMergeTimeout)
+	}
 
 	// Check that defaults are preserved for unspecified fields
 	if e.config.OnConflict != "assign_back" {
@@ -118,6 +144,56 @@ func TestEngineer_LoadConfig_WithMergeQueue(t *testing.T) {
 	}
 }
 
+func TestEngineer_RunTests_Flaky(t *testing.T) {
+	tmpDir := t.TempDir()
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	e.SetOutput(io.Discard)
+	e.workDir = tmpDir
+	e.config.RetryFlakyTests = 1
+
+	// TestFlaky failed on main's last run; TestReal never has
+	if err := flakes.Update(tmpDir, func(h *flakes.History) {
+		h.RecordMain([]flakes.Result{{Test: "example.com/m.TestFlaky"}, {Test: "example.com/m.TestReal", Passed: true}}, time.Now())
+	}); err != nil {
+		t.Fatal(err)
+	}
+	failing := func(tests ...string) string {
+		cmd := "printf '=== RUN\\n"
+		for _, name := range tests {
+			cmd += "--- FAIL: " + name + " (0.00s)\\n"
+		}
+		return cmd + "FAIL\\texample.com/m\\t0.01s\\n'; exit 1"
+	}
+
+	e.config.TestCommand = failing("TestFlaky")
+	result := e.runTests(context.Background(), "polecat/Toast/gt-abc", "main")
+	if !result.Success || len(result.FlakyTests) != 1 || result.FlakyTests[0] != "example.com/m.TestFlaky" {
+		t.Errorf("flaky-only failure: got %+v, want success with the flaky test", result)
+	}
+
+	e.config.TestCommand = failing("TestFlaky", "TestReal")
+	result = e.runTests(context.Background(), "polecat/Toast/gt-abc", "main")
+	if result.Success || !result.TestsFailed || !strings.Contains(result.Error, "example.com/m.TestReal") ||
+		strings.Contains(result.Error, "TestFlaky") {
+		t.Errorf("real failure: got %+v, want a failure naming only TestReal", result)
+	}
+
+	h, err := flakes.Load(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := h.Tests["example.com/m.TestReal"]; rec == nil || rec.Branch.Failures != 1 || rec.LastBranch != "polecat/Toast/gt-abc" {
+		t.Errorf("TestReal record = %+v", rec)
+	}
+
+	// With detection off, any failure fails
+	e.config.FlakyThreshold = 0
+	e.config.TestCommand = failing("TestFlaky")
+	if result := e.runTests(context.Background(), "polecat/Toast/gt-abc", "main"); result.Success {
+		t.Error("expected failure with flake detection off")
+	}
+}
+
 func TestEngineer_LoadConfig_NoMergeQueueSection(t *testing.T) {
 	// Create a temp directory with config.json without merge_queue
 	tmpDir, err := os.MkdirTemp("", "engineer-test-*")
@@ -215,3 +291,20 @@ func TestEngineer_DeleteMergedBranchesConfig(t *testing.T) {
 		t.Error("expected DeleteMergedBranches to be true by default")
 	}
 }
+
+func TestConflictDetails(t *testing.T) {
+	if got := conflictDetails(ProcessResult{Conflict: true}); got != "" {
+		t.Errorf("conflictDetails without files = %q, want empty", got)
+	}
+
+	got := conflictDetails(ProcessResult{
+		Conflict:      true,
+		Conflicts:     []string{"README.md", "main.go"},
+		ConflictHunks: "<<<<<<< HEAD\nmain\n=======\nfeature\n>>>>>>> feature",
+	})
+	for _, want := range []string{"## Conflicted files", "- README.md", "- main.go", "## Conflict hunks", "```diff", "<<<<<<< HEAD"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("conflictDetails missing %q:\n%s", want, got)
+		}
+	}
+}
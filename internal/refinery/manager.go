@@ -29,6 +29,7 @@ var (
 	ErrNotRunning     = errors.New("refinery not running")
 	ErrAlreadyRunning = errors.New("refinery already running")
 	ErrNoQueue        = errors.New("no items in queue")
+	ErrPaused         = errors.New("refinery paused")
 )
 
 // Manager handles refinery lifecycle and queue operations.
@@ -109,6 +110,9 @@ func (m *Manager) Start(foreground bool) error {
 	if err != nil {
 		return err
 	}
+	if ref.State == StatePaused {
+		return ErrPaused
+	}
 
 	t := tmux.NewTmux()
 	sessionID := m.SessionName()
@@ -276,6 +280,39 @@ func (m *Manager) Stop() error {
 	return m.saveState(ref)
 }
 
+// Pause stops the refinery if it is running and keeps it from starting
+// until Resume. The merge queue is left as is.
+func (m *Manager) Pause() error {
+	ref, err := m.loadState()
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	if running, _ := t.HasSession(m.SessionName()); running {
+		if err := t.KillSession(m.SessionName()); err != nil {
+			return fmt.Errorf("killing session: %w", err)
+		}
+	}
+
+	ref.State = StatePaused
+	ref.PID = 0
+	return m.saveState(ref)
+}
+
+// Resume lets a paused refinery start again. It stays stopped until started.
+func (m *Manager) Resume() error {
+	ref, err := m.loadState()
+	if err != nil {
+		return err
+	}
+	if ref.State != StatePaused {
+		return nil
+	}
+	ref.State = StateStopped
+	return m.saveState(ref)
+}
+
 // Queue returns the current merge queue.
 // Uses beads merge-request issues as the source of truth (not git branches).
 func (m *Manager) Queue() ([]QueueItem, error) {
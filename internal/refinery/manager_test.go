@@ -170,3 +170,28 @@ func TestManager_RegisterMR(t *testing.T) {
 		t.Errorf("saved MR worker = %s, want Cheedo", saved.Worker)
 	}
 }
+
+func TestManager_PauseResume(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+
+	if err := mgr.Pause(); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	ref, err := mgr.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.State != StatePaused {
+		t.Errorf("State = %s, want paused", ref.State)
+	}
+	if err := mgr.Start(false); err != ErrPaused {
+		t.Errorf("Start while paused = %v, want ErrPaused", err)
+	}
+
+	if err := mgr.Resume(); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if ref, _ := mgr.Status(); ref.State != StateStopped {
+		t.Errorf("State after Resume = %s, want stopped", ref.State)
+	}
+}
@@ -0,0 +1,202 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// postMergeTailMax caps the post-merge output embedded in bead comments
+// and escalations; the full output is in the log file.
+const postMergeTailMax = 4 * 1024
+
+// PostMergeResult is one run of the post-merge command.
+type PostMergeResult struct {
+	Command     string   `json:"command"`
+	MergeCommit string   `json:"merge_commit"`
+	Beads       []string `json:"beads,omitempty"`
+	Success     bool     `json:"success"`
+	ExitCode    int      `json:"exit_code"`
+	Error       string   `json:"error,omitempty"`
+	Duration    string   `json:"duration"`
+	Log         string   `json:"log,omitempty"`        // full output
+	Escalation  string   `json:"escalation,omitempty"` // escalation bead filed for a failure
+	Output      string   `json:"-"`
+}
+
+// PostMergeLogPath returns where the post-merge output for a merge commit
+// is kept.
+func PostMergeLogPath(rigPath, commit string) string {
+	return filepath.Join(constants.RigRuntimePath(rigPath), "post-merge", commit+".log")
+}
+
+// RunPostMerge runs the configured post-merge command in the refinery's
+// clone, which is at commit (HEAD if empty), the merge just pushed to
+// target. The command gets the rig's env plus GT_MERGE_COMMIT,
+// GT_TARGET_BRANCH and GT_MERGED_BEADS (space-separated). Its output is
+// commented on each of the merged beads, and a failure files an
+// escalation: the merge stands, but a deploy that didn't happen needs
+// someone to look. Returns nil when no command is configured.
+func (e *Engineer) RunPostMerge(ctx context.Context, commit, target string, mergedBeads []string) *PostMergeResult {
+	command := e.config.PostMergeCommand
+	if command == "" {
+		return nil
+	}
+	if commit == "" {
+		commit, _ = e.git.Rev("HEAD")
+	}
+	if e.config.PostMergeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.config.PostMergeTimeout)
+		defer cancel()
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Running post-merge command: %s\n", command)
+	start := time.Now()
+	output, err := e.shellCommand(ctx, e.workDir, command, []string{
+		"GT_MERGE_COMMIT=" + commit,
+		"GT_TARGET_BRANCH=" + target,
+		"GT_MERGED_BEADS=" + strings.Join(mergedBeads, " "),
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
+	}
+	result := &PostMergeResult{
+		Command:     command,
+		MergeCommit: commit,
+		Beads:       mergedBeads,
+		Success:     err == nil,
+		Duration:    time.Since(start).Round(time.Second).String(),
+		Output:      output,
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		result.ExitCode = -1
+		result.Error = fmt.Sprintf("timed out after %s", e.config.PostMergeTimeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+		result.Error = err.Error()
+	case err != nil:
+		result.ExitCode = -1
+		result.Error = err.Error()
+	}
+
+	logPath := PostMergeLogPath(e.rig.Path, commit)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err == nil {
+		if err := os.WriteFile(logPath, []byte(output), 0600); err == nil {
+			result.Log = logPath
+		}
+	}
+
+	if result.Success {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Post-merge command passed (%s)\n", result.Duration)
+		e.log.Info("post-merge command passed", "commit", commit, "duration", result.Duration)
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Post-merge command failed: %s\n", result.Error)
+		e.log.Warn("post-merge command failed", "commit", commit, "error", result.Error)
+		id, err := e.escalatePostMerge(result)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to escalate post-merge failure: %v\n", err)
+		} else {
+			result.Escalation = id
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Escalated: %s\n", id)
+		}
+	}
+
+	comment := postMergeComment(result)
+	for _, id := range mergedBeads {
+		if err := e.beads.Comment(id, comment); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record post-merge output on %s: %v\n", id, err)
+		}
+	}
+	return result
+}
+
+// postMergeComment formats a post-merge run for a bead comment.
+func postMergeComment(r *PostMergeResult) string {
+	var sb strings.Builder
+	status := "passed"
+	if !r.Success {
+		status = "FAILED: " + r.Error
+	}
+	fmt.Fprintf(&sb, "Post-merge `%s` at %s %s (%s)", r.Command, shortSHA(r.MergeCommit), status, r.Duration)
+	if r.Escalation != "" {
+		fmt.Fprintf(&sb, ", escalated as %s", r.Escalation)
+	}
+	sb.WriteString("\n")
+	if tail := outputTail(r.Output, postMergeTailMax); tail != "" {
+		fmt.Fprintf(&sb, "\n```\n%s\n```\n", tail)
+	}
+	if r.Log != "" {
+		fmt.Fprintf(&sb, "\nFull log: %s\n", r.Log)
+	}
+	return sb.String()
+}
+
+// escalatePostMerge files an escalation bead in the town beads for a
+// failed post-merge run.
+func (e *Engineer) escalatePostMerge(r *PostMergeResult) (string, error) {
+	townRoot, err := workspace.Find(e.rig.Path)
+	if err != nil || townRoot == "" {
+		return "", fmt.Errorf("finding town root: %w", err)
+	}
+	actor := e.rig.Name + "/refinery"
+	title := fmt.Sprintf("Post-merge command failed on %s at %s", e.rig.Name, shortSHA(r.MergeCommit))
+	related := ""
+	if len(r.Beads) > 0 {
+		related = r.Beads[0]
+	}
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	issue, err := bd.CreateEscalationBead(title, &beads.EscalationFields{
+		Severity:    config.SeverityHigh,
+		Reason:      "post-merge command failed: " + r.Error,
+		Source:      "refinery:post-merge",
+		EscalatedBy: actor,
+		EscalatedAt: time.Now().Format(time.RFC3339),
+		RelatedBead: related,
+		Context: fmt.Sprintf("Rig: %s\nCommand: %s\nCommit: %s\nMerged: %s\nLog: %s\nOutput (tail):\n%s",
+			e.rig.Name, r.Command, r.MergeCommit, strings.Join(r.Beads, ", "), r.Log, outputTail(r.Output, postMergeTailMax)),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	payload := events.EscalationPayload(e.rig.Name, actor, "mayor/", title)
+	payload["severity"] = config.SeverityHigh
+	payload["source"] = "refinery:post-merge"
+	_ = events.LogFeed(events.TypeEscalationSent, actor, payload)
+	return issue.ID, nil
+}
+
+// outputTail returns the end of output, at most max bytes, starting on a
+// line boundary.
+func outputTail(output string, max int) string {
+	output = strings.TrimRight(output, "\n")
+	if len(output) <= max {
+		return output
+	}
+	tail := output[len(output)-max:]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 {
+		tail = tail[i+1:]
+	}
+	return "...\n" + tail
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,55 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestRunPostMerge(t *testing.T) {
+	rigPath := t.TempDir()
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: rigPath})
+	e.SetOutput(io.Discard)
+	e.workDir = rigPath
+
+	if r := e.RunPostMerge(context.Background(), "abc123", "main", nil); r != nil {
+		t.Fatalf("RunPostMerge with no command = %+v, want nil", r)
+	}
+
+	e.config.PostMergeCommand = `echo "deploying $GT_MERGE_COMMIT to $GT_TARGET_BRANCH for $GT_MERGED_BEADS"`
+	r := e.RunPostMerge(context.Background(), "abc123", "main", nil)
+	if r == nil || !r.Success || r.ExitCode != 0 {
+		t.Fatalf("RunPostMerge = %+v, want success", r)
+	}
+	if want := "deploying abc123 to main for"; !strings.Contains(r.Output, want) {
+		t.Errorf("output = %q, want it to contain %q", r.Output, want)
+	}
+	if data, err := os.ReadFile(PostMergeLogPath(rigPath, "abc123")); err != nil || string(data) != r.Output {
+		t.Errorf("log = %q (%v), want the output", data, err)
+	}
+
+	e.config.PostMergeCommand = "echo boom; exit 3"
+	r = e.RunPostMerge(context.Background(), "def456", "main", nil)
+	if r == nil || r.Success || r.ExitCode != 3 {
+		t.Fatalf("RunPostMerge = %+v, want exit 3", r)
+	}
+	comment := postMergeComment(r)
+	if !strings.Contains(comment, "FAILED") || !strings.Contains(comment, "boom") {
+		t.Errorf("comment = %q, want the failure and output", comment)
+	}
+}
+
+func TestOutputTail(t *testing.T) {
+	if got := outputTail("short\n", 100); got != "short" {
+		t.Errorf("outputTail(short) = %q", got)
+	}
+	long := strings.Repeat("line\n", 100)
+	got := outputTail(long, 22)
+	if got != "...\nline\nline\nline\nline" {
+		t.Errorf("outputTail(long) = %q", got)
+	}
+}
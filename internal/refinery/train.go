@@ -0,0 +1,466 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mrqueue"
+)
+
+// Merge train states.
+const (
+	TrainBuilding  = "building"  // merging the MRs onto target
+	TrainTesting   = "testing"   // testing the whole train
+	TrainBisecting = "bisecting" // the train failed; finding the culprit
+	TrainMerged    = "merged"    // pushed, possibly after dropping culprits
+	TrainFailed    = "failed"    // nothing landed
+)
+
+// Merge train car states.
+const (
+	CarQueued   = "queued"
+	CarMerged   = "merged"   // merged into the train, not yet pushed
+	CarLanded   = "landed"   // pushed to target
+	CarConflict = "conflict" // didn't merge onto the train; handled as a conflict
+	CarCulprit  = "culprit"  // bisection found it broke the tests
+	CarFailed   = "failed"   // any other failure
+)
+
+// TrainCar is one MR in a merge train.
+type TrainCar struct {
+	MR          string `json:"mr"`
+	Branch      string `json:"branch"`
+	Status      string `json:"status"`
+	MergeCommit string `json:"merge_commit,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// TrainState is a merge train's progress. It is saved as the train runs,
+// so a refinery that dies mid-train leaves a record of where it stopped.
+type TrainState struct {
+	ID         string     `json:"id"`
+	Target     string     `json:"target"`
+	Base       string     `json:"base,omitempty"` // target's SHA the train was built on
+	Status     string     `json:"status"`
+	Cars       []TrainCar `json:"cars"`
+	TestRuns   int        `json:"test_runs"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+
+	// PostMerge is the post-merge command's run on the pushed train.
+	PostMerge *PostMergeResult `json:"post_merge,omitempty"`
+}
+
+// Landed returns the cars that were pushed to target.
+func (s *TrainState) Landed() []TrainCar {
+	var cars []TrainCar
+	for _, c := range s.Cars {
+		if c.Status == CarLanded {
+			cars = append(cars, c)
+		}
+	}
+	return cars
+}
+
+// TrainStatePath returns where a rig's latest merge train is recorded.
+func TrainStatePath(rigPath string) string {
+	return filepath.Join(constants.RigRuntimePath(rigPath), "merge-train.json")
+}
+
+// LoadTrainState reads a rig's latest merge train, or nil if it has run
+// none.
+func LoadTrainState(rigPath string) (*TrainState, error) {
+	data, err := os.ReadFile(TrainStatePath(rigPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s TrainState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", TrainStatePath(rigPath), err)
+	}
+	return &s, nil
+}
+
+// TrainsEnabled reports whether the config allows merge trains.
+func (c *MergeQueueConfig) TrainsEnabled() bool {
+	return c.MaxTrainLength >= 2
+}
+
+// SelectTrain picks the next merge train from ready MRs, which are in
+// score order: the first MR and the MRs behind it with the same target, up
+// to max. MRs that have failed before only ever lead a train, so a branch
+// that is likely to fail again doesn't hold up others.
+func SelectTrain(ready []*mrqueue.MR, max int) []*mrqueue.MR {
+	if len(ready) == 0 || max < 1 {
+		return nil
+	}
+	train := []*mrqueue.MR{ready[0]}
+	for _, mr := range ready[1:] {
+		if len(train) >= max {
+			break
+		}
+		if mr.Target == ready[0].Target && mr.RetryCount == 0 {
+			train = append(train, mr)
+		}
+	}
+	return train
+}
+
+// NextTrain returns the MRs to merge as a train, or nil when trains are
+// off or the queue isn't deep enough for one.
+func (e *Engineer) NextTrain() ([]*mrqueue.MR, error) {
+	if !e.config.TrainsEnabled() {
+		return nil, nil
+	}
+	ready, err := e.ListReadyMRs()
+	if err != nil {
+		return nil, err
+	}
+	if len(ready) < e.config.TrainQueueDepth {
+		return nil, nil
+	}
+	if train := SelectTrain(ready, e.config.MaxTrainLength); len(train) >= 2 {
+		return train, nil
+	}
+	return nil, nil
+}
+
+// bisectCulprit finds the first of n cars that breaks the tests, given
+// that the target alone passes and all n cars together fail. fails
+// reports whether the first k cars fail; it is called about log2(n) times.
+func bisectCulprit(n int, fails func(k int) (bool, error)) (int, error) {
+	lo, hi := 0, n // the first lo cars pass, the first hi fail
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		failed, err := fails(mid)
+		if err != nil {
+			return 0, err
+		}
+		if failed {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi - 1, nil
+}
+
+// ProcessTrain merges mrs, which share a target, onto it together and
+// tests them once. MRs that conflict with the train are handled as
+// conflicts and left out. When the train's tests fail, the target is
+// tested alone; if it fails too, the train is abandoned and its MRs stay
+// queued. Otherwise bisection finds the MR that broke the tests; it is
+// failed and the rest are tested again, until a train passes and is
+// pushed or none is left. MRs are claimed for
+// workerID while the train runs.
+func (e *Engineer) ProcessTrain(ctx context.Context, workerID string, mrs []*mrqueue.MR) (*TrainState, error) {
+	if len(mrs) == 0 {
+		return nil, errors.New("empty merge train")
+	}
+	stateLock, err := e.lockRig()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stateLock.Release() }()
+
+	target := mrs[0].Target
+	state := &TrainState{
+		ID:        "train-" + time.Now().UTC().Format("20060102-150405"),
+		Target:    target,
+		Status:    TrainBuilding,
+		StartedAt: time.Now().UTC(),
+	}
+
+	// Claim the cars; one another worker has is left out
+	var cars []*mrqueue.MR
+	for _, mr := range mrs {
+		if mr.Target != target {
+			return nil, fmt.Errorf("MR %s targets %s, not %s", mr.ID, mr.Target, target)
+		}
+		if err := e.mrQueue.Claim(mr.ID, workerID); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Leaving %s out of the train: %v\n", mr.ID, err)
+			continue
+		}
+		cars = append(cars, mr)
+		state.Cars = append(state.Cars, TrainCar{MR: mr.ID, Branch: mr.Branch, Status: CarQueued})
+	}
+	defer func() {
+		for _, mr := range cars {
+			_ = e.mrQueue.Release(mr.ID) // no-op for landed MRs, which are removed
+		}
+	}()
+	car := func(mr *mrqueue.MR) *TrainCar {
+		for i := range state.Cars {
+			if state.Cars[i].MR == mr.ID {
+				return &state.Cars[i]
+			}
+		}
+		return nil
+	}
+	e.saveTrain(state)
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Merge train %s: %d MR(s) into %s\n", state.ID, len(cars), target)
+	e.log.Info("merge train started", "train", state.ID, "target", target, "mrs", len(cars))
+	if err := e.git.Checkout(target); err != nil {
+		return e.finishTrain(state, fmt.Errorf("checking out %s: %w", target, err))
+	}
+	if err := e.git.Pull("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+	}
+	base, err := e.git.Rev("HEAD")
+	if err != nil {
+		return e.finishTrain(state, fmt.Errorf("getting %s SHA: %w", target, err))
+	}
+	state.Base = base
+
+	// Build the train. A car that doesn't merge onto the cars before it is
+	// failed now and dropped.
+	var train []*mrqueue.MR
+	for _, mr := range cars {
+		if err := e.eventLogger.LogMergeStarted(mr); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to log merge_started event: %v\n", err)
+		}
+		_ = events.LogFeed(events.TypeMergeStarted, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""))
+
+		result := e.mergeCar(mr)
+		c := car(mr)
+		if !result.Success {
+			c.Status, c.Error = CarFailed, result.Error
+			if result.Conflict {
+				c.Status = CarConflict
+			}
+			e.handleFailureFromQueue(mr, result)
+			continue
+		}
+		c.Status, c.MergeCommit = CarMerged, result.MergeCommit
+		train = append(train, mr)
+	}
+	e.saveTrain(state)
+
+	// Test, dropping a culprit each time the train fails
+	baseTested := false
+	for len(train) > 0 && e.config.RunTests && e.config.TestCommand != "" {
+		state.Status = TrainTesting
+		e.saveTrain(state)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Testing train of %d: %s\n", len(train), e.config.TestCommand)
+		result := e.testTrain(ctx, state)
+		if result.Success {
+			break
+		}
+		if ctx.Err() != nil {
+			return e.finishTrain(state, e.resetTrain(base, ctx.Err()))
+		}
+
+		// Before blaming a car, check the target passes on its own: if it
+		// doesn't, no car broke the tests, and the train is abandoned with
+		// every car left in the queue
+		if !baseTested {
+			baseTested = true
+			if err := e.rebuildTrain(base, nil, car); err != nil {
+				return e.finishTrain(state, e.resetTrain(base, err))
+			}
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Train failed; testing %s without it...\n", target)
+			if r := e.testTrain(ctx, state); !r.Success {
+				if ctx.Err() != nil {
+					return e.finishTrain(state, e.resetTrain(base, ctx.Err()))
+				}
+				for _, mr := range train {
+					c := car(mr)
+					c.Status, c.MergeCommit = CarQueued, ""
+				}
+				return e.finishTrain(state, e.resetTrain(base, fmt.Errorf("%s fails its tests without the train: %s", target, r.Error)))
+			}
+		}
+
+		culprit := 0
+		if len(train) > 1 {
+			state.Status = TrainBisecting
+			e.saveTrain(state)
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Train failed; bisecting %d MR(s)...\n", len(train))
+			culprit, err = bisectCulprit(len(train), func(k int) (bool, error) {
+				if err := e.rebuildTrain(base, train[:k], car); err != nil {
+					return false, err
+				}
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Testing the first %d of the train...\n", k)
+				r := e.testTrain(ctx, state)
+				if !r.Success {
+					result = r
+				}
+				return !r.Success, ctx.Err()
+			})
+			if err != nil {
+				return e.finishTrain(state, e.resetTrain(base, err))
+			}
+		}
+
+		mr := train[culprit]
+		c := car(mr)
+		c.Status, c.MergeCommit = CarCulprit, ""
+		c.Error = fmt.Sprintf("broke the tests in merge train %s: %s", state.ID, result.Error)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Culprit: %s (%s)\n", mr.ID, mr.Branch)
+		e.handleFailureFromQueue(mr, ProcessResult{TestsFailed: true, Error: c.Error})
+
+		train = append(train[:culprit:culprit], train[culprit+1:]...)
+		if err := e.rebuildTrain(base, train, car); err != nil {
+			return e.finishTrain(state, e.resetTrain(base, err))
+		}
+	}
+	if len(train) == 0 {
+		return e.finishTrain(state, e.resetTrain(base, nil))
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing train to origin/%s...\n", target)
+	if err := e.git.Push("origin", target, false); err != nil {
+		err = e.resetTrain(base, fmt.Errorf("failed to push to origin: %w", err))
+		for _, mr := range train {
+			c := car(mr)
+			c.Status, c.Error = CarFailed, err.Error()
+			e.handleFailureFromQueue(mr, ProcessResult{Error: c.Error})
+		}
+		return e.finishTrain(state, err)
+	}
+	var merged []string
+	for _, mr := range train {
+		c := car(mr)
+		c.Status = CarLanded
+		e.handleSuccessFromQueue(mr, ProcessResult{Success: true, MergeCommit: c.MergeCommit})
+		if mr.SourceIssue != "" {
+			merged = append(merged, mr.SourceIssue)
+		}
+	}
+	// One post-merge run for the train: its head is what was pushed
+	state.PostMerge = e.RunPostMerge(ctx, car(train[len(train)-1]).MergeCommit, target, merged)
+	return e.finishTrain(state, nil)
+}
+
+// mergeCar merges one MR onto the train, the local target branch.
+func (e *Engineer) mergeCar(mr *mrqueue.MR) ProcessResult {
+	exists, err := e.git.BranchExists(mr.Branch)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to check branch %s: %v", mr.Branch, err)}
+	}
+	if !exists {
+		return ProcessResult{Error: fmt.Sprintf("branch %s not found locally", mr.Branch)}
+	}
+	conflicts, hunks, err := e.git.CheckConflictHunks(mr.Branch, mr.Target, conflictHunksMax)
+	if err != nil {
+		return ProcessResult{Conflict: true, Error: fmt.Sprintf("conflict check failed: %v", err)}
+	}
+	if len(conflicts) > 0 {
+		return ProcessResult{
+			Conflict:      true,
+			Error:         fmt.Sprintf("merge conflicts in: %v", conflicts),
+			Conflicts:     conflicts,
+			ConflictHunks: hunks,
+		}
+	}
+	if err := e.git.MergeNoFF(mr.Branch, mergeMessage(mr)); err != nil {
+		conflicts, conflictErr := e.git.GetConflictingFiles()
+		_ = e.git.AbortMerge()
+		if conflictErr == nil && len(conflicts) > 0 {
+			return ProcessResult{Conflict: true, Error: "merge conflict during actual merge", Conflicts: conflicts}
+		}
+		return ProcessResult{Error: fmt.Sprintf("merge failed: %v", err)}
+	}
+	sha, err := e.git.Rev("HEAD")
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to get merge commit SHA: %v", err)}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Merged %s into the train\n", mr.Branch)
+	return ProcessResult{Success: true, MergeCommit: sha}
+}
+
+func mergeMessage(mr *mrqueue.MR) string {
+	if mr.SourceIssue != "" {
+		return fmt.Sprintf("Merge %s into %s (%s)", mr.Branch, mr.Target, mr.SourceIssue)
+	}
+	return fmt.Sprintf("Merge %s into %s", mr.Branch, mr.Target)
+}
+
+// rebuildTrain resets target to base and merges cars onto it again,
+// recording each car's new merge commit. The cars merged cleanly in this
+// order before, so a failure here is an error, not a conflict.
+func (e *Engineer) rebuildTrain(base string, cars []*mrqueue.MR, car func(*mrqueue.MR) *TrainCar) error {
+	if err := e.git.ResetHard(base); err != nil {
+		return fmt.Errorf("resetting to %s: %w", base, err)
+	}
+	for _, mr := range cars {
+		if err := e.git.MergeNoFF(mr.Branch, mergeMessage(mr)); err != nil {
+			_ = e.git.AbortMerge()
+			return fmt.Errorf("re-merging %s: %w", mr.Branch, err)
+		}
+		sha, err := e.git.Rev("HEAD")
+		if err != nil {
+			return err
+		}
+		car(mr).MergeCommit = sha
+	}
+	return nil
+}
+
+// testTrain runs the tests on the train as it stands.
+func (e *Engineer) testTrain(ctx context.Context, state *TrainState) ProcessResult {
+	state.TestRuns++
+	return e.runTests(ctx, state.ID, state.Target)
+}
+
+// resetTrain puts target back where the train started, joining any
+// failure to err.
+func (e *Engineer) resetTrain(base string, err error) error {
+	if resetErr := e.git.ResetHard(base); resetErr != nil {
+		return errors.Join(err, fmt.Errorf("resetting to %s: %w", base, resetErr))
+	}
+	return err
+}
+
+// finishTrain records how the train ended and returns its final state.
+func (e *Engineer) finishTrain(state *TrainState, err error) (*TrainState, error) {
+	now := time.Now().UTC()
+	state.FinishedAt = &now
+	state.Status = TrainFailed
+	if len(state.Landed()) > 0 {
+		state.Status = TrainMerged
+	}
+	if err != nil {
+		state.Error = err.Error()
+	}
+	e.saveTrain(state)
+
+	var landed []string
+	for _, c := range state.Landed() {
+		landed = append(landed, c.MR)
+	}
+	e.log.Info("merge train finished", "train", state.ID, "status", state.Status,
+		"landed", strings.Join(landed, ","), "test_runs", state.TestRuns)
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Train %s %s: %d of %d landed, %d test run(s)\n",
+		state.ID, state.Status, len(landed), len(state.Cars), state.TestRuns)
+	return state, err
+}
+
+// saveTrain writes the train's state. The record is advisory, so failing
+// to write it only logs.
+func (e *Engineer) saveTrain(state *TrainState) {
+	path := TrainStatePath(e.rig.Path)
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0644) //nolint:gosec // G306: not sensitive
+	}
+	if err != nil {
+		e.log.Warn("saving merge train state", "train", state.ID, "error", err)
+	}
+}
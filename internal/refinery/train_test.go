@@ -0,0 +1,205 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mrqueue"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestSelectTrain(t *testing.T) {
+	ready := []*mrqueue.MR{
+		{ID: "a", Target: "main"},
+		{ID: "b", Target: "release"},
+		{ID: "c", Target: "main", RetryCount: 1},
+		{ID: "d", Target: "main"},
+		{ID: "e", Target: "main"},
+		{ID: "f", Target: "main"},
+	}
+	ids := func(mrs []*mrqueue.MR) string {
+		s := ""
+		for _, mr := range mrs {
+			s += mr.ID
+		}
+		return s
+	}
+
+	if got := ids(SelectTrain(ready, 3)); got != "ade" {
+		t.Errorf("SelectTrain(3) = %q, want %q", got, "ade")
+	}
+	if got := ids(SelectTrain(ready[1:], 3)); got != "b" {
+		t.Errorf("SelectTrain with no compatible MRs = %q, want %q", got, "b")
+	}
+	// An MR that failed before can still lead
+	if got := ids(SelectTrain(ready[2:], 2)); got != "cd" {
+		t.Errorf("SelectTrain led by a retry = %q, want %q", got, "cd")
+	}
+	if got := SelectTrain(nil, 3); got != nil {
+		t.Errorf("SelectTrain(nil) = %v, want nil", got)
+	}
+}
+
+func TestBisectCulprit(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		for culprit := 0; culprit < n; culprit++ {
+			runs := 0
+			got, err := bisectCulprit(n, func(k int) (bool, error) {
+				runs++
+				if k <= 0 || k >= n {
+					t.Fatalf("n=%d: tested known prefix %d", n, k)
+				}
+				return k > culprit, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != culprit {
+				t.Errorf("n=%d: culprit = %d, want %d", n, got, culprit)
+			}
+			if runs > 4 {
+				t.Errorf("n=%d: %d test runs, want at most log2(n)", n, runs)
+			}
+		}
+	}
+
+	boom := errors.New("boom")
+	if _, err := bisectCulprit(4, func(int) (bool, error) { return false, boom }); !errors.Is(err, boom) {
+		t.Errorf("error = %v, want %v", err, boom)
+	}
+}
+
+func TestTrainsEnabled(t *testing.T) {
+	cfg := DefaultMergeQueueConfig()
+	if cfg.TrainsEnabled() {
+		t.Error("trains should be off by default")
+	}
+	cfg.MaxTrainLength = 4
+	if !cfg.TrainsEnabled() {
+		t.Error("max_train_length 4 should turn trains on")
+	}
+}
+
+func TestProcessTrain(t *testing.T) {
+	// The train logs merge events to the town found from the working
+	// directory, so run it in a town of its own.
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"type":"town","version":2,"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(town)
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "t")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "t@t")
+	}
+
+	rigPath := filepath.Join(town, "r")
+	origin := filepath.Join(town, "origin.git")
+	clone := filepath.Join(rigPath, "refinery", "rig")
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", clone}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if out, err := exec.Command("git", "init", "-q", "--bare", "-b", "main", origin).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	if out, err := exec.Command("git", "clone", "-q", origin, clone).CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %v\n%s", err, out)
+	}
+	git("commit", "-q", "--allow-empty", "-m", "base")
+	git("push", "-q", "origin", "HEAD:main")
+	var mrs []*mrqueue.MR
+	for _, name := range []string{"a", "b", "bad"} {
+		git("checkout", "-q", "-b", "polecat/"+name, "main")
+		if err := os.WriteFile(filepath.Join(clone, name+".txt"), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", ".")
+		git("commit", "-q", "-m", name)
+		mrs = append(mrs, &mrqueue.MR{ID: "mr-" + name, Branch: "polecat/" + name, Target: "main"})
+	}
+	git("checkout", "-q", "main")
+
+	e := NewEngineer(&rig.Rig{Name: "r", Path: rigPath})
+	e.SetOutput(io.Discard)
+	e.config.RunTests = true
+	e.config.TestCommand = "test ! -e bad.txt"
+	for _, mr := range mrs {
+		if err := e.mrQueue.Submit(mr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state, err := e.ProcessTrain(context.Background(), "worker", mrs)
+	if err != nil {
+		t.Fatalf("ProcessTrain: %v", err)
+	}
+	want := map[string]string{"mr-a": CarLanded, "mr-b": CarLanded, "mr-bad": CarCulprit}
+	for _, c := range state.Cars {
+		if c.Status != want[c.MR] {
+			t.Errorf("%s = %s, want %s", c.MR, c.Status, want[c.MR])
+		}
+	}
+	if state.Status != TrainMerged {
+		t.Errorf("train status = %s, want %s", state.Status, TrainMerged)
+	}
+
+	// When target fails its tests on its own, no car is to blame: the
+	// train is abandoned and its MRs stay queued
+	mrs = nil
+	for _, name := range []string{"c", "d"} {
+		git("checkout", "-q", "-b", "polecat/"+name, "main")
+		if err := os.WriteFile(filepath.Join(clone, name+".txt"), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", ".")
+		git("commit", "-q", "-m", name)
+		mr := &mrqueue.MR{ID: "mr-" + name, Branch: "polecat/" + name, Target: "main"}
+		if err := e.mrQueue.Submit(mr); err != nil {
+			t.Fatal(err)
+		}
+		mrs = append(mrs, mr)
+	}
+	git("checkout", "-q", "main")
+	if err := os.WriteFile(filepath.Join(clone, "broken.txt"), []byte("broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "broken")
+	git("push", "-q", "origin", "HEAD:main")
+	e.config.TestCommand = "test ! -e broken.txt"
+
+	state, err = e.ProcessTrain(context.Background(), "worker", mrs)
+	if err == nil {
+		t.Fatal("ProcessTrain on a failing target succeeded")
+	}
+	if state.Status != TrainFailed {
+		t.Errorf("train status = %s, want %s", state.Status, TrainFailed)
+	}
+	for _, c := range state.Cars {
+		if c.Status != CarQueued {
+			t.Errorf("%s = %s, want %s", c.MR, c.Status, CarQueued)
+		}
+		if mr, err := e.mrQueue.Get(c.MR); err != nil || mr.ClaimedBy != "" {
+			t.Errorf("%s not left unclaimed in the queue: %+v, %v", c.MR, mr, err)
+		}
+	}
+
+	logged, err := os.ReadFile(filepath.Join(town, events.EventsFile))
+	if err != nil || len(logged) == 0 {
+		t.Errorf("no events logged to the test town: %v", err)
+	}
+}
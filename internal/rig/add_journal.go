@@ -0,0 +1,81 @@
+package rig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Steps of a local gt rig add, in order.
+const (
+	AddStepConfig     = "config"      // Rig config.json
+	AddStepBareRepo   = "bare-repo"   // Shared .repo.git and default branch
+	AddStepMayorClone = "mayor-clone" // Mayor's working clone
+	AddStepBeads      = "beads"       // Source repo and rig-level beads
+	AddStepRefinery   = "refinery"    // Refinery worktree
+	AddStepWorkspaces = "workspaces"  // Crew, witness and polecat directories
+	AddStepAgents     = "agents"      // Agent beads, patrol molecules and plugins
+)
+
+// AddJournal records the progress of a gt rig add, so one that fails part
+// way can be resumed from the failed step. It lives in the rig's runtime
+// directory until the rig is registered.
+type AddJournal struct {
+	Options     AddRigOptions `json:"options"`
+	PrefixGiven bool          `json:"prefix_given,omitempty"` // --prefix was given, not derived
+	Done        []string      `json:"done,omitempty"`
+	Failed      string        `json:"failed,omitempty"` // Step that failed last
+	Error       string        `json:"error,omitempty"`
+	Started     time.Time     `json:"started"`
+	Updated     time.Time     `json:"updated"`
+}
+
+// AddJournalPath returns where a rig's add journal is kept.
+func AddJournalPath(rigPath string) string {
+	return filepath.Join(rigPath, constants.DirRuntime, "add.json")
+}
+
+// LoadAddJournal reads a rig's add journal.
+func LoadAddJournal(rigPath string) (*AddJournal, error) {
+	data, err := os.ReadFile(AddJournalPath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var j AddJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("parsing add journal: %w", err)
+	}
+	return &j, nil
+}
+
+// Save writes the journal.
+func (j *AddJournal) Save(rigPath string) error {
+	j.Updated = time.Now()
+	path := AddJournalPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	if err := util.AtomicWriteJSON(path, j); err != nil {
+		return fmt.Errorf("writing add journal: %w", err)
+	}
+	return nil
+}
+
+// IsDone reports whether the journal records step as done.
+func (j *AddJournal) IsDone(step string) bool {
+	return slices.Contains(j.Done, step)
+}
+
+// FinishAdd removes a rig's add journal, once the rig is registered.
+func FinishAdd(rigPath string) error {
+	if err := os.Remove(AddJournalPath(rigPath)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package rig
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestAddRig_RollsBackOrResumes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root, rigsConfig := setupTestTown(t)
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+	missing := filepath.Join(root, "no-such-repo")
+	rigPath := filepath.Join(root, "widgets")
+
+	// A failed clone removes everything by default
+	if _, err := manager.AddRig(AddRigOptions{Name: "widgets", GitURL: missing}); err == nil {
+		t.Fatal("AddRig from a missing repo succeeded")
+	}
+	if _, err := os.Stat(rigPath); !os.IsNotExist(err) {
+		t.Fatalf("rig dir left behind after rollback: %v", err)
+	}
+
+	// With NoRollback the partial rig and its journal are kept
+	if _, err := manager.AddRig(AddRigOptions{Name: "widgets", GitURL: missing, NoRollback: true}); err == nil {
+		t.Fatal("AddRig from a missing repo succeeded")
+	}
+	j, err := LoadAddJournal(rigPath)
+	if err != nil {
+		t.Fatalf("LoadAddJournal: %v", err)
+	}
+	if !slices.Equal(j.Done, []string{AddStepConfig}) || j.Failed != AddStepBareRepo {
+		t.Errorf("journal done %v failed %q, want [config] and bare-repo", j.Done, j.Failed)
+	}
+	if j.Options.BeadsPrefix == "" || j.PrefixGiven {
+		t.Errorf("journal prefix %q (given %v), want a derived prefix", j.Options.BeadsPrefix, j.PrefixGiven)
+	}
+
+	// A plain add points at --resume, and a resume must use the same repo
+	if _, err := manager.AddRig(AddRigOptions{Name: "widgets", GitURL: missing}); !errors.Is(err, ErrAddIncomplete) {
+		t.Errorf("AddRig over a partial rig = %v, want ErrAddIncomplete", err)
+	}
+	if _, err := manager.AddRig(AddRigOptions{Name: "widgets", GitURL: "https://example.com/other.git", Resume: true}); err == nil || !strings.Contains(err.Error(), "was being added from") {
+		t.Errorf("resume from another repo = %v, want a mismatch error", err)
+	}
+
+	// Resuming retries from the failed step, and keeps the rig when it fails again
+	if _, err := manager.AddRig(AddRigOptions{Name: "widgets", Resume: true}); err == nil {
+		t.Fatal("resumed AddRig from a missing repo succeeded")
+	}
+	if j, err := LoadAddJournal(rigPath); err != nil || j.Failed != AddStepBareRepo {
+		t.Errorf("journal after resume = %+v, %v; want bare-repo still failed", j, err)
+	}
+
+	if err := FinishAdd(rigPath); err != nil {
+		t.Fatalf("FinishAdd: %v", err)
+	}
+	if _, err := LoadAddJournal(rigPath); !os.IsNotExist(err) {
+		t.Errorf("journal still there after FinishAdd: %v", err)
+	}
+}
@@ -15,13 +15,18 @@ import (
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/dryrun"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/jj"
+	"github.com/steveyegge/gastown/internal/vcs"
 )
 
 // Common errors
 var (
 	ErrRigNotFound = errors.New("rig not found")
 	ErrRigExists   = errors.New("rig already exists")
+
+	ErrAddIncomplete = errors.New("rig add is incomplete")
 )
 
 // RigConfig represents the rig-level configuration (config.json at rig root).
@@ -32,6 +37,9 @@ type RigConfig struct {
 	GitURL        string       `json:"git_url"`                  // repository URL
 	LocalRepo     string       `json:"local_repo,omitempty"`     // optional local reference repo
 	DefaultBranch string       `json:"default_branch,omitempty"` // main, master, etc.
+	Remote        string       `json:"remote,omitempty"`         // [user@]host:/path for a remote rig
+	Subdir        string       `json:"path,omitempty"`           // monorepo subdirectory the rig works in
+	VCS           string       `json:"vcs,omitempty"`            // version control backend, "jj" or "" for git
 	CreatedAt     time.Time    `json:"created_at"`               // when rig was created
 	Beads         *BeadsConfig `json:"beads,omitempty"`
 }
@@ -40,6 +48,13 @@ type RigConfig struct {
 type BeadsConfig struct {
 	Prefix     string `json:"prefix"`                // issue prefix (e.g., "gt")
 	SyncRemote string `json:"sync_remote,omitempty"` // git remote for bd sync
+
+	// FormerPrefixes are prefixes the rig used before gt prefix rename
+	FormerPrefixes []string `json:"former_prefixes,omitempty"`
+
+	// PendingRename is the prefix a rename started from while bd has yet
+	// to rewrite the IDs; renaming to Prefix again retries bd's step.
+	PendingRename string `json:"pending_rename,omitempty"`
 }
 
 // CurrentRigConfigVersion is the current schema version.
@@ -113,6 +128,12 @@ func (m *Manager) loadRig(name string, entry config.RigEntry) (*Rig, error) {
 		GitURL:    entry.GitURL,
 		LocalRepo: entry.LocalRepo,
 		Config:    entry.BeadsConfig,
+		Remote:    entry.Remote,
+		Subdir:    entry.Subdir,
+		VCS:       entry.VCS,
+	}
+	if entry.Remote != "" {
+		return m.loadRemoteRig(rig)
 	}
 
 	// Scan for polecats
@@ -168,6 +189,27 @@ type AddRigOptions struct {
 	BeadsPrefix   string // Beads issue prefix (defaults to derived from name)
 	LocalRepo     string // Optional local repo for reference clones
 	DefaultBranch string // Default branch (defaults to auto-detected from remote)
+	Remote        string // Optional [user@]host:/path to keep the rig on another machine
+	Subdir        string // Optional monorepo subdirectory to scope the rig to
+	VCS           string // Optional version control backend ("jj"; default git)
+	Resume        bool   // Continue an add that failed part way, from its journal
+	NoRollback    bool   // Keep a partial rig on failure, so it can be resumed
+}
+
+// cleanSubdir normalizes a rig's monorepo path, which must be a directory
+// inside the repo.
+func cleanSubdir(subdir string) (string, error) {
+	if subdir == "" {
+		return "", nil
+	}
+	clean := filepath.ToSlash(filepath.Clean(subdir))
+	if filepath.IsAbs(subdir) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("path %q must be a directory inside the repo", subdir)
+	}
+	if clean == "." {
+		return "", nil
+	}
+	return clean, nil
 }
 
 func resolveLocalRepo(path, gitURL string) (string, string) {
@@ -212,6 +254,12 @@ func resolveLocalRepo(path, gitURL string) (string, string) {
 //	├── witness/               # Witness agent (no clone)
 //	├── polecats/              # Worker directories (empty)
 //	└── crew/<crew>/           # Default human workspace
+//
+// Each step is recorded in an AddJournal. If a step fails, the rig
+// directory is removed, unless opts.NoRollback or opts.Resume is set: then
+// it is kept with its journal, and AddRig with opts.Resume continues from
+// the failed step. The journal is left in place on success too, until the
+// caller has registered the rig and calls FinishAdd.
 func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	if m.RigExists(opts.Name) {
 		return nil, ErrRigExists
@@ -227,305 +275,476 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 
 	rigPath := filepath.Join(m.townRoot, opts.Name)
 
+	if opts.Resume {
+		return m.resumeAddRig(opts, rigPath)
+	}
+
 	// Check if directory already exists
 	if _, err := os.Stat(rigPath); err == nil {
+		if _, jerr := LoadAddJournal(rigPath); jerr == nil {
+			return nil, fmt.Errorf("%w: an earlier add of %s failed part way; use --resume to continue it", ErrAddIncomplete, opts.Name)
+		}
 		return nil, fmt.Errorf("directory already exists: %s", rigPath)
 	}
 
-	// Track whether user explicitly provided --prefix (before deriving)
-	userProvidedPrefix := opts.BeadsPrefix != ""
+	subdir, err := cleanSubdir(opts.Subdir)
+	if err != nil {
+		return nil, err
+	}
+	opts.Subdir = subdir
+
+	kind, err := vcs.Normalize(opts.VCS)
+	if err != nil {
+		return nil, err
+	}
+	opts.VCS = ""
+	if kind == vcs.KindJJ {
+		if opts.Subdir != "" || opts.Remote != "" {
+			return nil, fmt.Errorf("jj rigs can't be path-scoped or remote yet")
+		}
+		if _, err := exec.LookPath("jj"); err != nil {
+			return nil, fmt.Errorf("jj rigs need jj installed: %w", err)
+		}
+		opts.VCS = kind
+	}
 
-	// Derive defaults
-	if opts.BeadsPrefix == "" {
-		opts.BeadsPrefix = deriveBeadsPrefix(opts.Name)
+	prefix := opts.BeadsPrefix
+	if prefix == "" {
+		prefix = deriveBeadsPrefix(opts.Name)
+	}
+	if err := m.checkPrefixFree(prefix, opts.Name); err != nil {
+		return nil, err
 	}
 
+	if opts.Remote != "" {
+		if opts.Subdir != "" {
+			return nil, fmt.Errorf("path-scoped rigs can't be remote yet")
+		}
+		return m.addRemoteRig(opts, rigPath)
+	}
+
+	journal := &AddJournal{
+		Options:     opts,
+		PrefixGiven: opts.BeadsPrefix != "",
+		Started:     time.Now(),
+	}
+	journal.Options.BeadsPrefix = prefix
 	localRepo, warn := resolveLocalRepo(opts.LocalRepo, opts.GitURL)
 	if warn != "" {
 		fmt.Printf("  Warning: %s\n", warn)
 	}
+	journal.Options.LocalRepo = localRepo
 
 	// Create container directory
-	if err := os.MkdirAll(rigPath, 0755); err != nil {
+	if err := dryrun.MkdirAll(rigPath, 0755); err != nil {
 		return nil, fmt.Errorf("creating rig directory: %w", err)
 	}
+	return m.runAddSteps(journal, rigPath)
+}
 
-	// Track cleanup on failure (best-effort cleanup)
-	cleanup := func() { _ = os.RemoveAll(rigPath) }
-	success := false
-	defer func() {
-		if !success {
-			cleanup()
-		}
-	}()
-
-	// Create rig config
-	rigConfig := &RigConfig{
-		Type:      "rig",
-		Version:   CurrentRigConfigVersion,
-		Name:      opts.Name,
-		GitURL:    opts.GitURL,
-		LocalRepo: localRepo,
-		CreatedAt: time.Now(),
-		Beads: &BeadsConfig{
-			Prefix: opts.BeadsPrefix,
-		},
+// resumeAddRig continues an add that failed part way, with the options it
+// was started with.
+func (m *Manager) resumeAddRig(opts AddRigOptions, rigPath string) (*Rig, error) {
+	journal, err := LoadAddJournal(rigPath)
+	if err != nil {
+		return nil, fmt.Errorf("nothing to resume for %s: %w", opts.Name, err)
 	}
-	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
-		return nil, fmt.Errorf("saving rig config: %w", err)
+	if opts.GitURL != "" && opts.GitURL != journal.Options.GitURL {
+		return nil, fmt.Errorf("%s was being added from %s, not %s", opts.Name, journal.Options.GitURL, opts.GitURL)
 	}
+	journal.Options.Resume = true
+	journal.Options.NoRollback = opts.NoRollback
+	if journal.Failed != "" {
+		fmt.Printf("  Resuming from step %q (failed: %s)\n", journal.Failed, journal.Error)
+	}
+	return m.runAddSteps(journal, rigPath)
+}
 
-	// Create shared bare repo as source of truth for refinery and polecats.
-	// This allows refinery to see polecat branches without pushing to remote.
-	// Mayor remains a separate clone (doesn't need branch visibility).
-	fmt.Printf("  Cloning repository (this may take a moment)...\n")
-	bareRepoPath := filepath.Join(rigPath, ".repo.git")
-	if localRepo != "" {
-		if err := m.git.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
-			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
-			_ = os.RemoveAll(bareRepoPath)
-			if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
-				return nil, fmt.Errorf("creating bare repo: %w", err)
-			}
-		}
-	} else {
-		if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
-			return nil, fmt.Errorf("creating bare repo: %w", err)
+// runAddSteps runs the steps of a local rig add that its journal doesn't
+// record as done.
+func (m *Manager) runAddSteps(journal *AddJournal, rigPath string) (*Rig, error) {
+	opts := journal.Options
+	localRepo := opts.LocalRepo
+
+	success := false
+	defer func() {
+		if success {
+			return
 		}
-	}
-	fmt.Printf("   ✓ Created shared bare repo\n")
-	bareGit := git.NewGitWithDir(bareRepoPath, "")
-
-	// Determine default branch: use provided value or auto-detect from remote
-	var defaultBranch string
-	if opts.DefaultBranch != "" {
-		defaultBranch = opts.DefaultBranch
-	} else {
-		// Try to get default branch from remote first, fall back to local detection
-		defaultBranch = bareGit.RemoteDefaultBranch()
-		if defaultBranch == "" {
-			defaultBranch = bareGit.DefaultBranch()
+		if opts.Resume || opts.NoRollback {
+			fmt.Printf("  Kept the partial rig at %s; continue with: gt rig add %s --resume\n", rigPath, opts.Name)
+			return
 		}
-	}
-	rigConfig.DefaultBranch = defaultBranch
-	// Re-save config with default branch
-	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
-		return nil, fmt.Errorf("updating rig config with default branch: %w", err)
-	}
+		// Everything the add created is inside the rig directory
+		fmt.Printf("  Rolling back: removing %s\n", rigPath)
+		_ = dryrun.RemoveAll(rigPath)
+	}()
 
-	// Create mayor as regular clone (separate from bare repo).
-	// Mayor doesn't need to see polecat branches - that's refinery's job.
-	// This also allows mayor to stay on the default branch without conflicting with refinery.
-	fmt.Printf("  Creating mayor clone...\n")
+	// Paths and handles every step may need, whether or not it ran now
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
 	mayorRigPath := filepath.Join(rigPath, "mayor", "rig")
-	if err := os.MkdirAll(filepath.Dir(mayorRigPath), 0755); err != nil {
-		return nil, fmt.Errorf("creating mayor dir: %w", err)
-	}
-	if localRepo != "" {
-		if err := m.git.CloneWithReference(opts.GitURL, mayorRigPath, localRepo); err != nil {
-			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
-			_ = os.RemoveAll(mayorRigPath)
-			if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
-				return nil, fmt.Errorf("cloning for mayor: %w", err)
-			}
-		}
-	} else {
-		if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
-			return nil, fmt.Errorf("cloning for mayor: %w", err)
-		}
+	refineryRigPath := filepath.Join(rigPath, "refinery", "rig")
+	crewPath := filepath.Join(rigPath, "crew")
+	witnessPath := filepath.Join(rigPath, "witness")
+	polecatsPath := filepath.Join(rigPath, "polecats")
+	var bareGit *git.Git
+	if opts.VCS != vcs.KindJJ {
+		bareGit = git.NewGitWithDir(bareRepoPath, "")
 	}
-
-	// Checkout the default branch for mayor (clone defaults to remote's HEAD, not our configured branch)
 	mayorGit := git.NewGitWithDir("", mayorRigPath)
-	if err := mayorGit.Checkout(defaultBranch); err != nil {
-		return nil, fmt.Errorf("checking out default branch for mayor: %w", err)
-	}
-	fmt.Printf("   ✓ Created mayor clone\n")
-
-	// Check if source repo has tracked .beads/ directory.
-	// If so, we need to initialize the database (beads.db is gitignored so it doesn't exist after clone).
-	sourceBeadsDir := filepath.Join(mayorRigPath, ".beads")
-	sourceBeadsDB := filepath.Join(sourceBeadsDir, "beads.db")
-	if _, err := os.Stat(sourceBeadsDir); err == nil {
-		// Tracked beads exist - try to detect prefix from existing issues
-		sourceBeadsConfig := filepath.Join(sourceBeadsDir, "config.yaml")
-		if sourcePrefix := detectBeadsPrefixFromConfig(sourceBeadsConfig); sourcePrefix != "" {
-			fmt.Printf("  Detected existing beads prefix '%s' from source repo\n", sourcePrefix)
-			// Only error on mismatch if user explicitly provided --prefix
-			if userProvidedPrefix && opts.BeadsPrefix != sourcePrefix {
-				return nil, fmt.Errorf("prefix mismatch: source repo uses '%s' but --prefix '%s' was provided; use --prefix %s to match existing issues", sourcePrefix, opts.BeadsPrefix, sourcePrefix)
+	var rigConfig *RigConfig
+	var runtimeConfig *config.RuntimeConfig
+
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{AddStepConfig, func() error {
+			rigConfig = &RigConfig{
+				Type:      "rig",
+				Version:   CurrentRigConfigVersion,
+				Name:      opts.Name,
+				GitURL:    opts.GitURL,
+				LocalRepo: localRepo,
+				Subdir:    opts.Subdir,
+				VCS:       opts.VCS,
+				CreatedAt: time.Now(),
+				Beads: &BeadsConfig{
+					Prefix: opts.BeadsPrefix,
+				},
 			}
-			// Use detected prefix (overrides derived prefix)
-			opts.BeadsPrefix = sourcePrefix
-			rigConfig.Beads.Prefix = sourcePrefix
-			// Re-save rig config with detected prefix
 			if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
-				return nil, fmt.Errorf("updating rig config with detected prefix: %w", err)
+				return fmt.Errorf("saving rig config: %w", err)
 			}
-		} else {
-			// Detection failed (no issues yet) - use derived/provided prefix
-			fmt.Printf("  Using prefix '%s' for tracked beads (no existing issues to detect from)\n", opts.BeadsPrefix)
-		}
-
-		// Initialize bd database if it doesn't exist.
-		// beads.db is gitignored so it won't exist after clone - we need to create it.
-		// bd init --prefix will create the database and auto-import from issues.jsonl.
-		if _, err := os.Stat(sourceBeadsDB); os.IsNotExist(err) {
-			cmd := exec.Command("bd", "init", "--prefix", opts.BeadsPrefix) // opts.BeadsPrefix validated earlier
-			cmd.Dir = mayorRigPath
-			if output, err := cmd.CombinedOutput(); err != nil {
-				fmt.Printf("  Warning: Could not init bd database: %v (%s)\n", err, strings.TrimSpace(string(output)))
+			return nil
+		}},
+
+		// Create shared bare repo as source of truth for refinery and polecats.
+		// This allows refinery to see polecat branches without pushing to remote.
+		// Mayor remains a separate clone (doesn't need branch visibility).
+		// A jj rig has none: mayor/rig is its repo, and the others its workspaces.
+		{AddStepBareRepo, func() error {
+			if opts.VCS != vcs.KindJJ {
+				_ = dryrun.RemoveAll(bareRepoPath) // Left by a failed attempt
+				fmt.Printf("  Cloning repository (this may take a moment)...\n")
+				if localRepo != "" {
+					if err := m.git.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
+						fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
+						_ = dryrun.RemoveAll(bareRepoPath)
+						if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
+							return fmt.Errorf("creating bare repo: %w", err)
+						}
+					}
+				} else {
+					if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
+						return fmt.Errorf("creating bare repo: %w", err)
+					}
+				}
+				fmt.Printf("   ✓ Created shared bare repo\n")
+				if opts.Subdir != "" {
+					// Worktrees share the bare repo's config, so this scopes them all
+					if err := bareGit.SetConfig(git.SparseScopeKey, opts.Subdir); err != nil {
+						return fmt.Errorf("scoping bare repo to %s: %w", opts.Subdir, err)
+					}
+				}
 			}
-			// Configure custom types for Gas Town (beads v0.46.0+)
-			configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
-			configCmd.Dir = mayorRigPath
-			_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
-		}
-	}
 
-	// Create mayor CLAUDE.md (overrides any from cloned repo)
-	if err := m.createRoleCLAUDEmd(mayorRigPath, "mayor", opts.Name, ""); err != nil {
-		return nil, fmt.Errorf("creating mayor CLAUDE.md: %w", err)
-	}
+			// Determine default branch: use provided value or auto-detect from remote
+			// (a jj rig detects it from the mayor clone, below)
+			defaultBranch := opts.DefaultBranch
+			if defaultBranch == "" && bareGit != nil {
+				// Try to get default branch from remote first, fall back to local detection
+				defaultBranch = bareGit.RemoteDefaultBranch()
+				if defaultBranch == "" {
+					defaultBranch = bareGit.DefaultBranch()
+				}
+			}
+			rigConfig.DefaultBranch = defaultBranch
+			// Re-save config with default branch
+			if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
+				return fmt.Errorf("updating rig config with default branch: %w", err)
+			}
+			return nil
+		}},
+
+		// Create mayor as regular clone (separate from bare repo).
+		// Mayor doesn't need to see polecat branches - that's refinery's job.
+		// This also allows mayor to stay on the default branch without conflicting with refinery.
+		{AddStepMayorClone, func() error {
+			fmt.Printf("  Creating mayor clone...\n")
+			_ = dryrun.RemoveAll(mayorRigPath) // Left by a failed attempt
+			if err := dryrun.MkdirAll(filepath.Dir(mayorRigPath), 0755); err != nil {
+				return fmt.Errorf("creating mayor dir: %w", err)
+			}
+			if localRepo != "" {
+				if err := m.git.CloneWithReference(opts.GitURL, mayorRigPath, localRepo); err != nil {
+					fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
+					_ = dryrun.RemoveAll(mayorRigPath)
+					if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
+						return fmt.Errorf("cloning for mayor: %w", err)
+					}
+				}
+			} else {
+				if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
+					return fmt.Errorf("cloning for mayor: %w", err)
+				}
+			}
 
-	// Initialize beads at rig level BEFORE creating worktrees.
-	// This ensures rig/.beads exists so worktree redirects can point to it.
-	fmt.Printf("  Initializing beads database...\n")
-	if err := m.initBeads(rigPath, opts.BeadsPrefix); err != nil {
-		return nil, fmt.Errorf("initializing beads: %w", err)
-	}
-	fmt.Printf("   ✓ Initialized beads (prefix: %s)\n", opts.BeadsPrefix)
+			// Checkout the default branch for mayor (clone defaults to remote's HEAD, not our configured branch)
+			if rigConfig.DefaultBranch == "" {
+				rigConfig.DefaultBranch = mayorGit.RemoteDefaultBranch()
+				if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
+					return fmt.Errorf("updating rig config with default branch: %w", err)
+				}
+			}
+			if err := mayorGit.Checkout(rigConfig.DefaultBranch); err != nil {
+				return fmt.Errorf("checking out default branch for mayor: %w", err)
+			}
+			if opts.Subdir != "" {
+				if info, err := os.Stat(filepath.Join(mayorRigPath, opts.Subdir)); !dryrun.Enabled() && (err != nil || !info.IsDir()) {
+					return fmt.Errorf("path %s is not a directory on %s", opts.Subdir, rigConfig.DefaultBranch)
+				}
+				// The monorepo's own .beads/ is left out, so the rig gets its own prefix
+				if err := git.SetSparseScope(mayorRigPath, opts.Subdir); err != nil {
+					return fmt.Errorf("scoping mayor clone to %s: %w", opts.Subdir, err)
+				}
+			}
+			if opts.VCS == vcs.KindJJ {
+				if err := jj.Colocate(mayorRigPath); err != nil {
+					return fmt.Errorf("initializing jj in mayor clone: %w", err)
+				}
+			}
+			fmt.Printf("   ✓ Created mayor clone\n")
+			return nil
+		}},
+
+		{AddStepBeads, func() error {
+			// Check if source repo has tracked .beads/ directory.
+			// If so, we need to initialize the database (beads.db is gitignored so it doesn't exist after clone).
+			sourceBeadsDir := filepath.Join(mayorRigPath, ".beads")
+			sourceBeadsDB := filepath.Join(sourceBeadsDir, "beads.db")
+			if _, err := os.Stat(sourceBeadsDir); err == nil {
+				// Tracked beads exist - try to detect prefix from existing issues
+				sourceBeadsConfig := filepath.Join(sourceBeadsDir, "config.yaml")
+				if sourcePrefix := detectBeadsPrefixFromConfig(sourceBeadsConfig); sourcePrefix != "" {
+					fmt.Printf("  Detected existing beads prefix '%s' from source repo\n", sourcePrefix)
+					// Only error on mismatch if user explicitly provided --prefix
+					if journal.PrefixGiven && opts.BeadsPrefix != sourcePrefix {
+						return fmt.Errorf("prefix mismatch: source repo uses '%s' but --prefix '%s' was provided; use --prefix %s to match existing issues", sourcePrefix, opts.BeadsPrefix, sourcePrefix)
+					}
+					if err := m.checkPrefixFree(sourcePrefix, opts.Name); err != nil {
+						return fmt.Errorf("source repo's beads: %w", err)
+					}
+					// Use detected prefix (overrides derived prefix)
+					opts.BeadsPrefix = sourcePrefix
+					journal.Options.BeadsPrefix = sourcePrefix
+					rigConfig.Beads.Prefix = sourcePrefix
+					// Re-save rig config with detected prefix
+					if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
+						return fmt.Errorf("updating rig config with detected prefix: %w", err)
+					}
+				} else {
+					// Detection failed (no issues yet) - use derived/provided prefix
+					fmt.Printf("  Using prefix '%s' for tracked beads (no existing issues to detect from)\n", opts.BeadsPrefix)
+				}
 
-	// Provision PRIME.md with Gas Town context for all workers in this rig.
-	// This is the fallback if SessionStart hook fails - ensures ALL workers
-	// (crew, polecats, refinery, witness) have GUPP and essential Gas Town context.
-	// PRIME.md is read by bd prime and output to the agent.
-	rigBeadsPath := filepath.Join(rigPath, ".beads")
-	if err := beads.ProvisionPrimeMD(rigBeadsPath); err != nil {
-		fmt.Printf("  Warning: Could not provision PRIME.md: %v\n", err)
-	}
+				// Initialize bd database if it doesn't exist.
+				// beads.db is gitignored so it won't exist after clone - we need to create it.
+				// bd init --prefix will create the database and auto-import from issues.jsonl.
+				if _, err := os.Stat(sourceBeadsDB); os.IsNotExist(err) {
+					cmd := exec.Command("bd", "init", "--prefix", opts.BeadsPrefix) // opts.BeadsPrefix validated earlier
+					cmd.Dir = mayorRigPath
+					if !dryrun.Command(cmd) {
+						if output, err := cmd.CombinedOutput(); err != nil {
+							fmt.Printf("  Warning: Could not init bd database: %v (%s)\n", err, strings.TrimSpace(string(output)))
+						}
+					}
+					// Configure custom types for Gas Town (beads v0.46.0+)
+					configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
+					configCmd.Dir = mayorRigPath
+					if !dryrun.Command(configCmd) {
+						_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
+					}
+				}
+			}
 
-	// Create refinery as worktree from bare repo on default branch.
-	// Refinery needs to see polecat branches (shared .repo.git) and merges them.
-	// Being on the default branch allows direct merge workflow.
-	fmt.Printf("  Creating refinery worktree...\n")
-	refineryRigPath := filepath.Join(rigPath, "refinery", "rig")
-	if err := os.MkdirAll(filepath.Dir(refineryRigPath), 0755); err != nil {
-		return nil, fmt.Errorf("creating refinery dir: %w", err)
-	}
-	if err := bareGit.WorktreeAddExisting(refineryRigPath, defaultBranch); err != nil {
-		return nil, fmt.Errorf("creating refinery worktree: %w", err)
-	}
-	fmt.Printf("   ✓ Created refinery worktree\n")
-	// Set up beads redirect for refinery (points to rig-level .beads)
-	if err := beads.SetupRedirect(m.townRoot, refineryRigPath); err != nil {
-		fmt.Printf("  Warning: Could not set up refinery beads redirect: %v\n", err)
-	}
-	// Create refinery CLAUDE.md (overrides any from cloned repo)
-	if err := m.createRoleCLAUDEmd(refineryRigPath, "refinery", opts.Name, ""); err != nil {
-		return nil, fmt.Errorf("creating refinery CLAUDE.md: %w", err)
-	}
-	// Create refinery hooks for patrol triggering (at refinery/ level, not rig/)
-	refineryPath := filepath.Dir(refineryRigPath)
-	runtimeConfig := config.LoadRuntimeConfig(rigPath)
-	if err := m.createPatrolHooks(refineryPath, runtimeConfig); err != nil {
-		fmt.Printf("  Warning: Could not create refinery hooks: %v\n", err)
-	}
+			// Create mayor CLAUDE.md (overrides any from cloned repo)
+			if err := m.createRoleCLAUDEmd(mayorRigPath, "mayor", opts.Name, ""); err != nil {
+				return fmt.Errorf("creating mayor CLAUDE.md: %w", err)
+			}
 
-	// Create empty crew directory with README (crew members added via gt crew add)
-	crewPath := filepath.Join(rigPath, "crew")
-	if err := os.MkdirAll(crewPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating crew dir: %w", err)
-	}
-	// Create README with instructions
-	readmePath := filepath.Join(crewPath, "README.md")
-	readmeContent := `# Crew Directory
+			// Initialize beads at rig level BEFORE creating worktrees.
+			// This ensures rig/.beads exists so worktree redirects can point to it.
+			fmt.Printf("  Initializing beads database...\n")
+			if err := m.initBeads(rigPath, opts.BeadsPrefix); err != nil {
+				return fmt.Errorf("initializing beads: %w", err)
+			}
+			fmt.Printf("   ✓ Initialized beads (prefix: %s)\n", opts.BeadsPrefix)
+
+			// Provision PRIME.md with Gas Town context for all workers in this rig.
+			// This is the fallback if SessionStart hook fails - ensures ALL workers
+			// (crew, polecats, refinery, witness) have GUPP and essential Gas Town context.
+			// PRIME.md is read by bd prime and output to the agent.
+			rigBeadsPath := filepath.Join(rigPath, ".beads")
+			if err := beads.ProvisionPrimeMD(rigBeadsPath); err != nil {
+				fmt.Printf("  Warning: Could not provision PRIME.md: %v\n", err)
+			}
+			return nil
+		}},
+
+		// Create refinery as worktree from bare repo on default branch.
+		// Refinery needs to see polecat branches (shared .repo.git) and merges them.
+		// Being on the default branch allows direct merge workflow.
+		{AddStepRefinery, func() error {
+			fmt.Printf("  Creating refinery worktree...\n")
+			if _, err := os.Stat(refineryRigPath); err == nil {
+				// Left by a failed attempt
+				_ = dryrun.RemoveAll(refineryRigPath)
+				if bareGit != nil {
+					_ = bareGit.WorktreePrune()
+				}
+			}
+			if err := dryrun.MkdirAll(filepath.Dir(refineryRigPath), 0755); err != nil {
+				return fmt.Errorf("creating refinery dir: %w", err)
+			}
+			if opts.VCS == vcs.KindJJ {
+				mayorJJ := jj.NewJJ(mayorRigPath)
+				if err := mayorJJ.WorktreeAddDetached(refineryRigPath, rigConfig.DefaultBranch); err != nil {
+					return fmt.Errorf("creating refinery workspace: %w", err)
+				}
+			} else if err := bareGit.WorktreeAddExisting(refineryRigPath, rigConfig.DefaultBranch); err != nil {
+				return fmt.Errorf("creating refinery worktree: %w", err)
+			}
+			fmt.Printf("   ✓ Created refinery worktree\n")
+			// Set up beads redirect for refinery (points to rig-level .beads)
+			if err := beads.SetupRedirect(m.townRoot, refineryRigPath); err != nil {
+				fmt.Printf("  Warning: Could not set up refinery beads redirect: %v\n", err)
+			}
+			// Create refinery CLAUDE.md (overrides any from cloned repo)
+			if err := m.createRoleCLAUDEmd(refineryRigPath, "refinery", opts.Name, ""); err != nil {
+				return fmt.Errorf("creating refinery CLAUDE.md: %w", err)
+			}
+			// Create refinery hooks for patrol triggering (at refinery/ level, not rig/)
+			if err := m.createPatrolHooks(filepath.Dir(refineryRigPath), runtimeConfig); err != nil {
+				fmt.Printf("  Warning: Could not create refinery hooks: %v\n", err)
+			}
+			return nil
+		}},
 
-This directory contains crew worker workspaces.
+		{AddStepWorkspaces, func() error {
+			// Create empty crew directory with README (crew members added via gt crew add)
+			if err := dryrun.MkdirAll(crewPath, 0755); err != nil {
+				return fmt.Errorf("creating crew dir: %w", err)
+			}
+			// Create README with instructions
+			readmePath := filepath.Join(crewPath, "README.md")
+			if err := dryrun.WriteFile(readmePath, []byte(crewReadme), 0644); err != nil {
+				return fmt.Errorf("creating crew README: %w", err)
+			}
 
-## Adding a Crew Member
+			// Create witness directory (no clone needed)
+			if err := dryrun.MkdirAll(witnessPath, 0755); err != nil {
+				return fmt.Errorf("creating witness dir: %w", err)
+			}
+			// Create witness hooks for patrol triggering
+			if err := m.createPatrolHooks(witnessPath, runtimeConfig); err != nil {
+				fmt.Printf("  Warning: Could not create witness hooks: %v\n", err)
+			}
 
-` + "```bash" + `
-gt crew add <name>    # Creates crew/<name>/ with a git clone
-` + "```" + `
+			// Create polecats directory (empty)
+			if err := dryrun.MkdirAll(polecatsPath, 0755); err != nil {
+				return fmt.Errorf("creating polecats dir: %w", err)
+			}
 
-## Crew vs Polecats
+			// Install Claude settings for all agent directories.
+			// Settings are placed in parent directories (not inside git repos) so Claude
+			// finds them via directory traversal without polluting source repos.
+			fmt.Printf("  Installing Claude settings...\n")
+			settingsRoles := []struct {
+				dir  string
+				role string
+			}{
+				{witnessPath, "witness"},
+				{filepath.Join(rigPath, "refinery"), "refinery"},
+				{crewPath, "crew"},
+				{polecatsPath, "polecat"},
+			}
+			for _, sr := range settingsRoles {
+				if err := claude.EnsureSettingsForRole(sr.dir, sr.role); err != nil {
+					fmt.Fprintf(os.Stderr, "  Warning: Could not create %s settings: %v\n", sr.role, err)
+				}
+			}
+			fmt.Printf("   ✓ Installed Claude settings\n")
+			return nil
+		}},
 
-- **Crew**: Persistent, user-managed workspaces (never auto-garbage-collected)
-- **Polecats**: Transient, witness-managed workers (cleaned up after work completes)
+		{AddStepAgents, func() error {
+			// Initialize beads at rig level
+			fmt.Printf("  Initializing beads database...\n")
+			if err := m.initBeads(rigPath, opts.BeadsPrefix); err != nil {
+				return fmt.Errorf("initializing beads: %w", err)
+			}
+			fmt.Printf("   ✓ Initialized beads (prefix: %s)\n", opts.BeadsPrefix)
 
-Use crew for your own workspace. Polecats are for batch work dispatch.
-`
-	if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
-		return nil, fmt.Errorf("creating crew README: %w", err)
-	}
+			// Create rig-level agent beads (witness, refinery) in rig beads.
+			// Town-level agents (mayor, deacon) are created by gt install in town beads.
+			if err := m.initAgentBeads(rigPath, opts.Name, opts.BeadsPrefix); err != nil {
+				// Non-fatal: log warning but continue
+				fmt.Fprintf(os.Stderr, "  Warning: Could not create agent beads: %v\n", err)
+			}
 
-	// Create witness directory (no clone needed)
-	witnessPath := filepath.Join(rigPath, "witness")
-	if err := os.MkdirAll(witnessPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating witness dir: %w", err)
-	}
-	// Create witness hooks for patrol triggering
-	if err := m.createPatrolHooks(witnessPath, runtimeConfig); err != nil {
-		fmt.Printf("  Warning: Could not create witness hooks: %v\n", err)
-	}
+			// Seed patrol molecules for this rig
+			if err := m.seedPatrolMolecules(rigPath); err != nil {
+				// Non-fatal: log warning but continue
+				fmt.Fprintf(os.Stderr, "  Warning: Could not seed patrol molecules: %v\n", err)
+			}
 
-	// Create polecats directory (empty)
-	polecatsPath := filepath.Join(rigPath, "polecats")
-	if err := os.MkdirAll(polecatsPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating polecats dir: %w", err)
+			// Create plugin directories
+			if err := m.createPluginDirectories(rigPath); err != nil {
+				// Non-fatal: log warning but continue
+				fmt.Fprintf(os.Stderr, "  Warning: Could not create plugin directories: %v\n", err)
+			}
+			return nil
+		}},
 	}
 
-	// Install Claude settings for all agent directories.
-	// Settings are placed in parent directories (not inside git repos) so Claude
-	// finds them via directory traversal without polluting source repos.
-	fmt.Printf("  Installing Claude settings...\n")
-	settingsRoles := []struct {
-		dir  string
-		role string
-	}{
-		{witnessPath, "witness"},
-		{filepath.Join(rigPath, "refinery"), "refinery"},
-		{crewPath, "crew"},
-		{polecatsPath, "polecat"},
-	}
-	for _, sr := range settingsRoles {
-		if err := claude.EnsureSettingsForRole(sr.dir, sr.role); err != nil {
-			fmt.Fprintf(os.Stderr, "  Warning: Could not create %s settings: %v\n", sr.role, err)
+	for _, step := range steps {
+		if rigConfig == nil && step.name != AddStepConfig {
+			// Resuming: later steps work from the saved config
+			cfg, err := LoadRigConfig(rigPath)
+			if err != nil {
+				return nil, fmt.Errorf("loading rig config: %w", err)
+			}
+			rigConfig = cfg
+			if rigConfig.Beads == nil {
+				rigConfig.Beads = &BeadsConfig{Prefix: opts.BeadsPrefix}
+			}
 		}
-	}
-	fmt.Printf("   ✓ Installed Claude settings\n")
-
-	// Initialize beads at rig level
-	fmt.Printf("  Initializing beads database...\n")
-	if err := m.initBeads(rigPath, opts.BeadsPrefix); err != nil {
-		return nil, fmt.Errorf("initializing beads: %w", err)
-	}
-	fmt.Printf("   ✓ Initialized beads (prefix: %s)\n", opts.BeadsPrefix)
-
-	// Create rig-level agent beads (witness, refinery) in rig beads.
-	// Town-level agents (mayor, deacon) are created by gt install in town beads.
-	if err := m.initAgentBeads(rigPath, opts.Name, opts.BeadsPrefix); err != nil {
-		// Non-fatal: log warning but continue
-		fmt.Fprintf(os.Stderr, "  Warning: Could not create agent beads: %v\n", err)
-	}
-
-	// Seed patrol molecules for this rig
-	if err := m.seedPatrolMolecules(rigPath); err != nil {
-		// Non-fatal: log warning but continue
-		fmt.Fprintf(os.Stderr, "  Warning: Could not seed patrol molecules: %v\n", err)
-	}
-
-	// Create plugin directories
-	if err := m.createPluginDirectories(rigPath); err != nil {
-		// Non-fatal: log warning but continue
-		fmt.Fprintf(os.Stderr, "  Warning: Could not create plugin directories: %v\n", err)
+		if runtimeConfig == nil && step.name == AddStepRefinery {
+			runtimeConfig = config.LoadRuntimeConfig(rigPath)
+		}
+		if journal.IsDone(step.name) {
+			continue
+		}
+		if err := step.run(); err != nil {
+			journal.Failed, journal.Error = step.name, err.Error()
+			_ = journal.Save(rigPath)
+			return nil, err
+		}
+		journal.Done = append(journal.Done, step.name)
+		journal.Failed, journal.Error = "", ""
+		if err := journal.Save(rigPath); err != nil {
+			return nil, err
+		}
+		opts = journal.Options
 	}
 
 	// Register in town config
 	m.config.Rigs[opts.Name] = config.RigEntry{
 		GitURL:    opts.GitURL,
 		LocalRepo: localRepo,
+		Subdir:    opts.Subdir,
+		VCS:       opts.VCS,
 		AddedAt:   time.Now(),
 		BeadsConfig: &config.BeadsConfig{
 			Prefix: opts.BeadsPrefix,
@@ -536,6 +755,25 @@ Use crew for your own workspace. Polecats are for batch work dispatch.
 	return m.loadRig(opts.Name, m.config.Rigs[opts.Name])
 }
 
+// crewReadme is the README gt rig add puts in a rig's crew directory.
+const crewReadme = `# Crew Directory
+
+This directory contains crew worker workspaces.
+
+## Adding a Crew Member
+
+` + "```bash" + `
+gt crew add <name>    # Creates crew/<name>/ with a git clone
+` + "```" + `
+
+## Crew vs Polecats
+
+- **Crew**: Persistent, user-managed workspaces (never auto-garbage-collected)
+- **Polecats**: Transient, witness-managed workers (cleaned up after work completes)
+
+Use crew for your own workspace. Polecats are for batch work dispatch.
+`
+
 // saveRigConfig writes the rig configuration to config.json.
 func (m *Manager) saveRigConfig(rigPath string, cfg *RigConfig) error {
 	configPath := filepath.Join(rigPath, "config.json")
@@ -543,7 +781,7 @@ func (m *Manager) saveRigConfig(rigPath string, cfg *RigConfig) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(configPath, data, 0644)
+	return dryrun.WriteFile(configPath, data, 0644)
 }
 
 // LoadRigConfig reads the rig configuration from config.json.
@@ -577,18 +815,18 @@ func (m *Manager) initBeads(rigPath, prefix string) error {
 	// If so, create a redirect file instead of a new database.
 	if _, err := os.Stat(mayorRigBeads); err == nil {
 		// Tracked beads exist - create redirect to mayor/rig/.beads
-		if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		if err := dryrun.MkdirAll(beadsDir, 0755); err != nil {
 			return err
 		}
 		redirectPath := filepath.Join(beadsDir, "redirect")
-		if err := os.WriteFile(redirectPath, []byte("mayor/rig/.beads\n"), 0644); err != nil {
+		if err := dryrun.WriteFile(redirectPath, []byte("mayor/rig/.beads\n"), 0644); err != nil {
 			return fmt.Errorf("creating redirect file: %w", err)
 		}
 		return nil
 	}
 
 	// No tracked beads - create local database
-	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(beadsDir, 0755); err != nil {
 		return err
 	}
 
@@ -607,13 +845,16 @@ func (m *Manager) initBeads(rigPath, prefix string) error {
 	cmd := exec.Command("bd", "init", "--prefix", prefix)
 	cmd.Dir = rigPath
 	cmd.Env = filteredEnv
-	_, err := cmd.CombinedOutput()
+	var err error
+	if !dryrun.Command(cmd) {
+		_, err = cmd.CombinedOutput()
+	}
 	if err != nil {
 		// bd might not be installed or failed, create minimal structure
 		// Note: beads currently expects YAML format for config
 		configPath := filepath.Join(beadsDir, "config.yaml")
 		configContent := fmt.Sprintf("prefix: %s\n", prefix)
-		if writeErr := os.WriteFile(configPath, []byte(configContent), 0644); writeErr != nil {
+		if writeErr := dryrun.WriteFile(configPath, []byte(configContent), 0644); writeErr != nil {
 			return writeErr
 		}
 	}
@@ -624,7 +865,9 @@ func (m *Manager) initBeads(rigPath, prefix string) error {
 	configCmd.Dir = rigPath
 	configCmd.Env = filteredEnv
 	// Ignore errors - older beads versions don't need this
-	_, _ = configCmd.CombinedOutput()
+	if !dryrun.Command(configCmd) {
+		_, _ = configCmd.CombinedOutput()
+	}
 
 	// Ensure database has repository fingerprint (GH #25).
 	// This is idempotent - safe on both new and legacy (pre-0.17.5) databases.
@@ -633,14 +876,16 @@ func (m *Manager) initBeads(rigPath, prefix string) error {
 	migrateCmd.Dir = rigPath
 	migrateCmd.Env = filteredEnv
 	// Ignore errors - fingerprint is optional for functionality
-	_, _ = migrateCmd.CombinedOutput()
+	if !dryrun.Command(migrateCmd) {
+		_, _ = migrateCmd.CombinedOutput()
+	}
 
 	// Ensure issues.jsonl exists to prevent bd auto-export from corrupting other files.
 	// bd init creates beads.db but not issues.jsonl in SQLite mode.
 	// Without issues.jsonl, bd's auto-export might write issues to other .jsonl files.
 	issuesJSONL := filepath.Join(beadsDir, "issues.jsonl")
 	if _, err := os.Stat(issuesJSONL); os.IsNotExist(err) {
-		if err := os.WriteFile(issuesJSONL, []byte{}, 0644); err != nil {
+		if err := dryrun.WriteFile(issuesJSONL, []byte{}, 0644); err != nil {
 			// Non-fatal but log it
 			fmt.Printf("   ⚠ Could not create issues.jsonl: %v\n", err)
 		}
@@ -980,7 +1225,7 @@ Full context is injected by ` + "`gt prime`" + ` at session start.
 	}
 
 	claudePath := filepath.Join(workspacePath, "CLAUDE.md")
-	return os.WriteFile(claudePath, []byte(bootstrap), 0644)
+	return dryrun.WriteFile(claudePath, []byte(bootstrap), 0644)
 }
 
 // createPatrolHooks creates .claude/settings.json with hooks for patrol roles.
@@ -995,7 +1240,7 @@ func (m *Manager) createPatrolHooks(workspacePath string, runtimeConfig *config.
 	}
 
 	settingsDir := filepath.Join(workspacePath, runtimeConfig.Hooks.Dir)
-	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(settingsDir, 0755); err != nil {
 		return fmt.Errorf("creating settings dir: %w", err)
 	}
 
@@ -1039,7 +1284,7 @@ func (m *Manager) createPatrolHooks(workspacePath string, runtimeConfig *config.
 }
 `
 	settingsPath := filepath.Join(settingsDir, runtimeConfig.Hooks.SettingsFile)
-	return os.WriteFile(settingsPath, []byte(hooksJSON), 0600)
+	return dryrun.WriteFile(settingsPath, []byte(hooksJSON), 0600)
 }
 
 // seedPatrolMolecules creates patrol molecule prototypes in the rig's beads database.
@@ -1048,6 +1293,9 @@ func (m *Manager) seedPatrolMolecules(rigPath string) error {
 	// Use bd command to seed molecules (more reliable than internal API)
 	cmd := exec.Command("bd", "mol", "seed", "--patrol")
 	cmd.Dir = rigPath
+	if dryrun.Command(cmd) {
+		return nil
+	}
 	if err := cmd.Run(); err != nil {
 		// Fallback: bd mol seed might not support --patrol yet
 		// Try creating them individually via bd create
@@ -1108,7 +1356,7 @@ func (m *Manager) seedPatrolMoleculesManually(rigPath string) error {
 func (m *Manager) createPluginDirectories(rigPath string) error {
 	// Town-level plugins directory
 	townPluginsDir := filepath.Join(m.townRoot, "plugins")
-	if err := os.MkdirAll(townPluginsDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(townPluginsDir, 0755); err != nil {
 		return fmt.Errorf("creating town plugins directory: %w", err)
 	}
 
@@ -1133,7 +1381,7 @@ Each plugin is a directory containing:
 
 See docs/deacon-plugins.md for full documentation.
 `
-		if writeErr := os.WriteFile(townReadme, []byte(content), 0644); writeErr != nil {
+		if writeErr := dryrun.WriteFile(townReadme, []byte(content), 0644); writeErr != nil {
 			// Non-fatal
 			return nil
 		}
@@ -1141,7 +1389,7 @@ See docs/deacon-plugins.md for full documentation.
 
 	// Rig-level plugins directory
 	rigPluginsDir := filepath.Join(rigPath, "plugins")
-	if err := os.MkdirAll(rigPluginsDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(rigPluginsDir, 0755); err != nil {
 		return fmt.Errorf("creating rig plugins directory: %w", err)
 	}
 
@@ -687,3 +687,39 @@ func TestSplitCompoundWord(t *testing.T) {
 		})
 	}
 }
+
+func TestCleanSubdir(t *testing.T) {
+	for in, want := range map[string]string{
+		"":                "",
+		".":               "",
+		"services/auth":   "services/auth",
+		"services/auth/":  "services/auth",
+		"./services//api": "services/api",
+	} {
+		if got, err := cleanSubdir(in); err != nil || got != want {
+			t.Errorf("cleanSubdir(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"/srv/mono", "..", "../other", "services/../../x"} {
+		if _, err := cleanSubdir(in); err == nil {
+			t.Errorf("cleanSubdir(%q) should fail", in)
+		}
+	}
+}
+
+func TestAddRig_RejectsUnsupportedVCS(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+
+	for _, opts := range []AddRigOptions{
+		{Name: "hgrig", GitURL: "git@github.com:test/test.git", VCS: "hg"},
+		{Name: "jjmono", GitURL: "git@github.com:test/test.git", VCS: "jj", Subdir: "services/auth"},
+	} {
+		if _, err := manager.AddRig(opts); err == nil {
+			t.Errorf("AddRig(%s, vcs %s) succeeded, want error", opts.Name, opts.VCS)
+		}
+		if _, err := os.Stat(filepath.Join(root, opts.Name)); !os.IsNotExist(err) {
+			t.Errorf("AddRig(%s) left its directory behind", opts.Name)
+		}
+	}
+}
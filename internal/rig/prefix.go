@@ -0,0 +1,206 @@
+package rig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrPrefixTaken is returned when a beads prefix belongs to another rig.
+var ErrPrefixTaken = errors.New("beads prefix is already in use")
+
+// TownPrefixOwner owns the town's own prefix in the prefix registry.
+const TownPrefixOwner = "town"
+
+// PrefixRegistry maps each beads prefix in the town to the rigs (or
+// TownPrefixOwner) that use it. It is gathered from rigs.json, each
+// registered rig's config.json and the town's routes.jsonl, so a prefix
+// claimed in any of them counts; more than one owner is a collision.
+type PrefixRegistry map[string][]string
+
+// LoadPrefixRegistry builds the town's prefix registry. rigsConfig may be
+// nil, in which case only routes.jsonl and the town prefix are read.
+func LoadPrefixRegistry(townRoot string, rigsConfig *config.RigsConfig) (PrefixRegistry, error) {
+	reg := PrefixRegistry{}
+	reg.add(beads.TownBeadsPrefix, TownPrefixOwner)
+	if rigsConfig != nil {
+		for name, entry := range rigsConfig.Rigs {
+			if entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
+				reg.add(entry.BeadsConfig.Prefix, name)
+			}
+			if cfg, err := LoadRigConfig(filepath.Join(townRoot, name)); err == nil && cfg.Beads != nil {
+				// Former prefixes stay reserved, so old IDs in commits stay unambiguous
+				for _, p := range append([]string{cfg.Beads.Prefix}, cfg.Beads.FormerPrefixes...) {
+					if p != "" {
+						reg.add(p, name)
+					}
+				}
+			}
+		}
+	}
+	routes, err := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		return reg, fmt.Errorf("loading routes: %w", err)
+	}
+	for _, r := range routes {
+		owner, _, _ := strings.Cut(filepath.ToSlash(r.Path), "/")
+		if owner == "." || owner == "" {
+			owner = TownPrefixOwner
+		}
+		reg.add(strings.TrimSuffix(r.Prefix, "-"), owner)
+	}
+	return reg, nil
+}
+
+func (r PrefixRegistry) add(prefix, owner string) {
+	if !slices.Contains(r[prefix], owner) {
+		r[prefix] = append(r[prefix], owner)
+		sort.Strings(r[prefix])
+	}
+}
+
+// Owners returns who uses prefix, other than except.
+func (r PrefixRegistry) Owners(prefix, except string) []string {
+	var owners []string
+	for _, o := range r[prefix] {
+		if o != except {
+			owners = append(owners, o)
+		}
+	}
+	return owners
+}
+
+// Collisions returns the prefixes with more than one owner.
+func (r PrefixRegistry) Collisions() map[string][]string {
+	out := make(map[string][]string)
+	for prefix, owners := range r {
+		if len(owners) > 1 {
+			out[prefix] = owners
+		}
+	}
+	return out
+}
+
+// checkPrefixFree returns ErrPrefixTaken if anyone but rigName uses prefix.
+func (m *Manager) checkPrefixFree(prefix, rigName string) error {
+	reg, err := LoadPrefixRegistry(m.townRoot, m.config)
+	if err != nil {
+		return err
+	}
+	if owners := reg.Owners(prefix, rigName); len(owners) > 0 {
+		return fmt.Errorf("%w: %q belongs to %s; choose another with --prefix", ErrPrefixTaken, prefix, strings.Join(owners, ", "))
+	}
+	return nil
+}
+
+// RenamePrefix moves a rig to a new beads prefix. The rig's config.json,
+// rigs.json and routes.jsonl are saved first, with the rename marked
+// pending, then bd rewrites the IDs of the rig's beads. If bd fails,
+// renaming to the same prefix again retries it. The old prefix is kept in
+// config.json as a former prefix, and its route stays, marked renamed to
+// the new one, so bead IDs in existing commits and mail still resolve (see
+// beads.CurrentID). It returns the old prefix.
+func (m *Manager) RenamePrefix(name, newPrefix string) (string, error) {
+	entry, ok := m.config.Rigs[name]
+	if !ok {
+		return "", ErrRigNotFound
+	}
+	if !isValidBeadsPrefix(newPrefix) {
+		return "", fmt.Errorf("invalid beads prefix %q", newPrefix)
+	}
+	rigPath := filepath.Join(m.townRoot, name)
+	rigConfig, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return "", fmt.Errorf("loading rig config: %w", err)
+	}
+	if rigConfig.Beads == nil {
+		rigConfig.Beads = &BeadsConfig{}
+	}
+	oldPrefix := ""
+	if entry.BeadsConfig != nil {
+		oldPrefix = entry.BeadsConfig.Prefix
+	}
+	if oldPrefix == "" {
+		oldPrefix = rigConfig.Beads.Prefix
+	}
+	if oldPrefix == "" {
+		return "", fmt.Errorf("rig %s has no beads prefix", name)
+	}
+	retry := oldPrefix == newPrefix && rigConfig.Beads.PendingRename != ""
+	if oldPrefix == newPrefix && !retry {
+		return "", fmt.Errorf("rig %s already uses prefix %q", name, newPrefix)
+	}
+	if err := m.checkPrefixFree(newPrefix, name); err != nil {
+		return "", err
+	}
+
+	// The rig's beads live where its route says
+	routePath := name
+	routes, _ := beads.LoadRoutes(filepath.Join(m.townRoot, ".beads"))
+	for _, r := range routes {
+		if r.Prefix == oldPrefix+"-" {
+			routePath = r.Path
+		}
+	}
+
+	if retry {
+		oldPrefix = rigConfig.Beads.PendingRename
+	} else {
+		rigConfig.Beads.Prefix = newPrefix
+		rigConfig.Beads.PendingRename = oldPrefix
+		rigConfig.Beads.FormerPrefixes = slices.DeleteFunc(rigConfig.Beads.FormerPrefixes, func(p string) bool { return p == newPrefix })
+		if !slices.Contains(rigConfig.Beads.FormerPrefixes, oldPrefix) {
+			rigConfig.Beads.FormerPrefixes = append(rigConfig.Beads.FormerPrefixes, oldPrefix)
+		}
+		if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
+			return "", fmt.Errorf("saving rig config: %w", err)
+		}
+		if entry.BeadsConfig == nil {
+			entry.BeadsConfig = &config.BeadsConfig{}
+		}
+		entry.BeadsConfig.Prefix = newPrefix
+		m.config.Rigs[name] = entry
+		if err := config.SaveRigsConfig(filepath.Join(m.townRoot, "mayor", "rigs.json"), m.config); err != nil {
+			return "", fmt.Errorf("saving rigs config: %w", err)
+		}
+	}
+	// Routes for former prefixes stay, so old IDs still find the rig's
+	// beads under the new prefix
+	if err := beads.AppendRoute(m.townRoot, beads.Route{Prefix: newPrefix + "-", Path: routePath}); err != nil {
+		return "", fmt.Errorf("adding route for %s: %w", newPrefix, err)
+	}
+	for _, p := range rigConfig.Beads.FormerPrefixes {
+		if err := beads.AppendRoute(m.townRoot, beads.Route{Prefix: p + "-", Path: routePath, RenamedTo: newPrefix + "-"}); err != nil {
+			return "", fmt.Errorf("updating route for %s: %w", p, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(m.townRoot, routePath, ".beads")); err == nil {
+		if _, err := beads.New(filepath.Join(m.townRoot, routePath)).Run("rename-prefix", newPrefix); err != nil {
+			return "", fmt.Errorf("renaming beads from %s to %s (retry with the same prefix): %w", oldPrefix, newPrefix, err)
+		}
+	}
+	rigConfig.Beads.PendingRename = ""
+	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
+		return "", fmt.Errorf("saving rig config: %w", err)
+	}
+	return oldPrefix, nil
+}
+
+// CurrentBeadID maps an ID under one of the rig's former prefixes to its
+// ID under the current one, and returns other IDs unchanged.
+func (c *BeadsConfig) CurrentBeadID(id string) string {
+	for _, former := range c.FormerPrefixes {
+		if rest, ok := strings.CutPrefix(id, former+"-"); ok {
+			return c.Prefix + "-" + rest
+		}
+	}
+	return id
+}
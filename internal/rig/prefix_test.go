@@ -0,0 +1,146 @@
+package rig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestPrefixRegistry(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	rigsConfig.Rigs["gastown"] = config.RigEntry{BeadsConfig: &config.BeadsConfig{Prefix: "gt"}}
+	rigsConfig.Rigs["beads"] = config.RigEntry{BeadsConfig: &config.BeadsConfig{Prefix: "bd"}}
+	if err := os.MkdirAll(filepath.Join(root, "gastown"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+	if err := manager.saveRigConfig(filepath.Join(root, "gastown"), &RigConfig{
+		Type: "rig", Name: "gastown",
+		Beads: &BeadsConfig{Prefix: "gt", FormerPrefixes: []string{"ga"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := beads.WriteRoutes(filepath.Join(root, ".beads"), []beads.Route{
+		{Prefix: "hq-", Path: "."},
+		{Prefix: "gt-", Path: "gastown/mayor/rig"},
+		{Prefix: "bd-", Path: "stray/mayor/rig"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := LoadPrefixRegistry(root, rigsConfig)
+	if err != nil {
+		t.Fatalf("LoadPrefixRegistry: %v", err)
+	}
+	if got := reg["ga"]; !slices.Equal(got, []string{"gastown"}) {
+		t.Errorf("former prefix ga owned by %v, want [gastown]", got)
+	}
+	collisions := reg.Collisions()
+	if len(collisions) != 1 || !slices.Equal(collisions["bd"], []string{"beads", "stray"}) {
+		t.Errorf("Collisions() = %v, want bd used by beads and stray", collisions)
+	}
+
+	for _, prefix := range []string{"gt", "ga", "hq"} {
+		_, err := manager.AddRig(AddRigOptions{Name: "newrig", GitURL: "https://example.com/x.git", BeadsPrefix: prefix})
+		if !errors.Is(err, ErrPrefixTaken) {
+			t.Errorf("AddRig with prefix %s = %v, want ErrPrefixTaken", prefix, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "newrig")); !os.IsNotExist(err) {
+		t.Errorf("refused add left a rig dir behind: %v", err)
+	}
+}
+
+func TestRenamePrefix(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	rigsConfig.Rigs["gastown"] = config.RigEntry{BeadsConfig: &config.BeadsConfig{Prefix: "gt"}}
+	rigsConfig.Rigs["beads"] = config.RigEntry{BeadsConfig: &config.BeadsConfig{Prefix: "bd"}}
+	rigPath := filepath.Join(root, "gastown")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := beads.WriteRoutes(filepath.Join(root, ".beads"), []beads.Route{{Prefix: "gt-", Path: "gastown/mayor/rig"}}); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+	if err := manager.saveRigConfig(rigPath, &RigConfig{Type: "rig", Name: "gastown", Beads: &BeadsConfig{Prefix: "gt"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := manager.RenamePrefix("gastown", "bd"); !errors.Is(err, ErrPrefixTaken) {
+		t.Errorf("RenamePrefix to a taken prefix = %v, want ErrPrefixTaken", err)
+	}
+	// No beads database at the route, so only the configuration moves
+	old, err := manager.RenamePrefix("gastown", "gtn")
+	if err != nil || old != "gt" {
+		t.Fatalf("RenamePrefix = %q, %v", old, err)
+	}
+	if got := rigsConfig.Rigs["gastown"].BeadsConfig.Prefix; got != "gtn" {
+		t.Errorf("rigs.json prefix = %q, want gtn", got)
+	}
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Beads.Prefix != "gtn" || !slices.Equal(cfg.Beads.FormerPrefixes, []string{"gt"}) {
+		t.Errorf("config.json beads = %+v, want gtn with former gt", cfg.Beads)
+	}
+	if got := cfg.Beads.CurrentBeadID("gt-abc12.3"); got != "gtn-abc12.3" {
+		t.Errorf("CurrentBeadID(gt-abc12.3) = %q", got)
+	}
+	routes, _ := beads.LoadRoutes(filepath.Join(root, ".beads"))
+	want := []beads.Route{{Prefix: "gt-", Path: "gastown/mayor/rig", RenamedTo: "gtn-"}, {Prefix: "gtn-", Path: "gastown/mayor/rig"}}
+	if !slices.Equal(routes, want) {
+		t.Errorf("routes = %+v, want gt- renamed to gtn-, both to gastown/mayor/rig", routes)
+	}
+	if got := beads.CurrentID(root, "gt-abc12"); got != "gtn-abc12" {
+		t.Errorf("beads.CurrentID(gt-abc12) = %q, want gtn-abc12", got)
+	}
+	if cfg.Beads.PendingRename != "" {
+		t.Errorf("pending rename = %q after a finished rename", cfg.Beads.PendingRename)
+	}
+	// A finished rename is not retried
+	if _, err := manager.RenamePrefix("gastown", "gtn"); err == nil {
+		t.Error("RenamePrefix to the current prefix after a finished rename succeeded")
+	}
+	saved, err := config.LoadRigsConfig(filepath.Join(root, "mayor", "rigs.json"))
+	if err != nil || saved.Rigs["gastown"].BeadsConfig.Prefix != "gtn" {
+		t.Errorf("rigs.json not saved with gtn: %v", err)
+	}
+
+	// When bd fails, the configuration has already moved, and renaming to
+	// the same prefix again retries bd's step
+	if err := os.MkdirAll(filepath.Join(root, "gastown", "mayor", "rig", ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	t.Setenv("PATH", writeFakeBD(t, "#!/bin/sh\nexit 1\n")+string(os.PathListSeparator)+path)
+	if _, err := manager.RenamePrefix("gastown", "gtx"); err == nil {
+		t.Fatal("RenamePrefix with bd failing succeeded")
+	}
+	if cfg, _ := LoadRigConfig(rigPath); cfg.Beads.Prefix != "gtx" || cfg.Beads.PendingRename != "gtn" || !slices.Equal(cfg.Beads.FormerPrefixes, []string{"gt", "gtn"}) {
+		t.Errorf("config.json beads after bd failed = %+v, want gtx pending from gtn with former gt, gtn", cfg.Beads)
+	}
+	t.Setenv("PATH", writeFakeBD(t, "#!/bin/sh\nexit 0\n")+string(os.PathListSeparator)+path)
+	if old, err := manager.RenamePrefix("gastown", "gtx"); err != nil || old != "gtn" {
+		t.Errorf("retried RenamePrefix = %q, %v; want gtn", old, err)
+	}
+	if cfg, _ := LoadRigConfig(rigPath); cfg.Beads.PendingRename != "" || !slices.Equal(cfg.Beads.FormerPrefixes, []string{"gt", "gtn"}) {
+		t.Errorf("config.json beads after retry = %+v, want no pending rename and former gt, gtn", cfg.Beads)
+	}
+	if got := beads.CurrentID(root, "gt-abc12"); got != "gtx-abc12" {
+		t.Errorf("beads.CurrentID(gt-abc12) after second rename = %q, want gtx-abc12", got)
+	}
+}
@@ -0,0 +1,183 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/transport"
+)
+
+// addRemoteRig creates a rig whose clones live on another machine. The
+// town keeps a stub directory with config.json; the bare repo, clones,
+// beads database and agent directories are created on the remote over SSH,
+// laid out as a local rig would be.
+func (m *Manager) addRemoteRig(opts AddRigOptions, rigPath string) (*Rig, error) {
+	if opts.LocalRepo != "" {
+		return nil, fmt.Errorf("--local-repo can't be used with a remote rig")
+	}
+	t, err := transport.NewSSH(opts.Remote, rigPath)
+	if err != nil {
+		return nil, err
+	}
+	if opts.BeadsPrefix == "" {
+		opts.BeadsPrefix = deriveBeadsPrefix(opts.Name)
+	}
+	if !isValidBeadsPrefix(opts.BeadsPrefix) {
+		return nil, fmt.Errorf("invalid beads prefix %q: must be alphanumeric with optional hyphens, start with letter, max 20 chars", opts.BeadsPrefix)
+	}
+
+	// run runs a command on the remote, in dir (a local path in the rig)
+	run := func(dir string, env []string, name string, args ...string) error {
+		cmd := t.Command(dir, env, name, args...)
+		if dryrun.Command(cmd) {
+			return nil
+		}
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s %s on %s: %v (%s)", name, strings.Join(args, " "), t.Host, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	if err := run("", nil, "mkdir", "-p", path.Dir(t.Root)); err != nil {
+		return nil, fmt.Errorf("creating remote parent directory: %w", err)
+	}
+	// Plain mkdir fails if the remote directory is already there
+	if err := run("", nil, "mkdir", t.Root); err != nil {
+		return nil, fmt.Errorf("remote rig directory: %w", err)
+	}
+
+	if err := dryrun.MkdirAll(rigPath, 0755); err != nil {
+		return nil, fmt.Errorf("creating rig directory: %w", err)
+	}
+	transport.Register(t)
+	success := false
+	defer func() {
+		if !success {
+			transport.Unregister(t)
+			_ = dryrun.RemoveAll(rigPath)
+			_ = run("", nil, "rm", "-rf", t.Root)
+		}
+	}()
+
+	rigConfig := &RigConfig{
+		Type:      "rig",
+		Version:   CurrentRigConfigVersion,
+		Name:      opts.Name,
+		GitURL:    opts.GitURL,
+		Remote:    opts.Remote,
+		CreatedAt: time.Now(),
+		Beads: &BeadsConfig{
+			Prefix: opts.BeadsPrefix,
+		},
+	}
+
+	fmt.Printf("  Cloning repository on %s (this may take a moment)...\n", t.Host)
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if err := run("", nil, "git", "clone", "--bare", opts.GitURL, bareRepoPath); err != nil {
+		return nil, fmt.Errorf("creating bare repo: %w", err)
+	}
+	if err := run("", nil, "git", "--git-dir="+bareRepoPath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+		return nil, fmt.Errorf("configuring bare repo: %w", err)
+	}
+	fmt.Printf("   ✓ Created shared bare repo\n")
+	bareGit := git.NewGitWithDir(bareRepoPath, "")
+
+	defaultBranch := opts.DefaultBranch
+	if defaultBranch == "" {
+		if defaultBranch = bareGit.RemoteDefaultBranch(); defaultBranch == "" {
+			defaultBranch = bareGit.DefaultBranch()
+		}
+	}
+	rigConfig.DefaultBranch = defaultBranch
+
+	dirs := []string{"mayor", "refinery", "crew", "witness", "polecats", ".beads"}
+	for i, d := range dirs {
+		dirs[i] = filepath.Join(rigPath, d)
+	}
+	if err := run("", nil, "mkdir", append([]string{"-p"}, dirs...)...); err != nil {
+		return nil, fmt.Errorf("creating agent directories: %w", err)
+	}
+
+	fmt.Printf("  Creating mayor clone...\n")
+	mayorRigPath := filepath.Join(rigPath, "mayor", "rig")
+	if err := run("", nil, "git", "clone", opts.GitURL, mayorRigPath); err != nil {
+		return nil, fmt.Errorf("cloning for mayor: %w", err)
+	}
+	if err := run(mayorRigPath, nil, "git", "checkout", defaultBranch); err != nil {
+		return nil, fmt.Errorf("checking out default branch for mayor: %w", err)
+	}
+	fmt.Printf("   ✓ Created mayor clone\n")
+
+	fmt.Printf("  Creating refinery worktree...\n")
+	if err := bareGit.WorktreeAddExisting(filepath.Join(rigPath, "refinery", "rig"), defaultBranch); err != nil {
+		return nil, fmt.Errorf("creating refinery worktree: %w", err)
+	}
+	fmt.Printf("   ✓ Created refinery worktree\n")
+
+	// Rig-level beads database, on the remote. Tracked beads in the source
+	// repo aren't redirected to as they are for local rigs.
+	fmt.Printf("  Initializing beads database...\n")
+	beadsEnv := []string{"BEADS_DIR=" + filepath.Join(rigPath, ".beads")}
+	if err := run(rigPath, beadsEnv, "bd", "init", "--prefix", opts.BeadsPrefix); err != nil {
+		return nil, fmt.Errorf("initializing beads: %w", err)
+	}
+	_ = run(rigPath, beadsEnv, "bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
+	fmt.Printf("   ✓ Initialized beads (prefix: %s)\n", opts.BeadsPrefix)
+
+	if err := m.initAgentBeads(rigPath, opts.Name, opts.BeadsPrefix); err != nil {
+		fmt.Fprintf(os.Stderr, "  Warning: Could not create agent beads: %v\n", err)
+	}
+
+	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
+		return nil, fmt.Errorf("saving rig config: %w", err)
+	}
+	m.config.Rigs[opts.Name] = config.RigEntry{
+		GitURL:  opts.GitURL,
+		Remote:  opts.Remote,
+		AddedAt: time.Now(),
+		BeadsConfig: &config.BeadsConfig{
+			Prefix: opts.BeadsPrefix,
+		},
+	}
+
+	success = true
+	return m.loadRig(opts.Name, m.config.Rigs[opts.Name])
+}
+
+// loadRemoteRig fills in a remote rig's agents from its directories on the
+// remote machine. An unreachable remote shows as a rig without agents.
+func (m *Manager) loadRemoteRig(rig *Rig) (*Rig, error) {
+	t, err := transport.NewSSH(rig.Remote, rig.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range []string{"", "polecats", "crew", "refinery", "mayor"} {
+		names, err := t.ReadDir(filepath.Join(rig.Path, dir))
+		if err != nil {
+			return rig, nil
+		}
+		for _, name := range names {
+			switch dir {
+			case "":
+				rig.HasWitness = rig.HasWitness || name == "witness"
+			case "polecats":
+				rig.Polecats = append(rig.Polecats, name)
+			case "crew":
+				rig.Crew = append(rig.Crew, name)
+			case "refinery":
+				rig.HasRefinery = rig.HasRefinery || name == "rig"
+			case "mayor":
+				rig.HasMayor = rig.HasMayor || name == "rig"
+			}
+		}
+	}
+	return rig, nil
+}
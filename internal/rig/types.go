@@ -19,6 +19,19 @@ type Rig struct {
 	// LocalRepo is an optional local repository used for reference clones.
 	LocalRepo string `json:"local_repo,omitempty"`
 
+	// Remote is the [user@]host:/path the rig's clones live at, for a rig
+	// on another machine (see package transport).
+	Remote string `json:"remote,omitempty"`
+
+	// Subdir is the directory of the repo a path-scoped rig works in (a
+	// service in a monorepo). Its clones check out only that directory and
+	// the repo's top-level files.
+	Subdir string `json:"subdir,omitempty"`
+
+	// VCS is the rig's version control backend (see package vcs); empty
+	// means git.
+	VCS string `json:"vcs,omitempty"`
+
 	// Config is the rig-level configuration.
 	Config *config.BeadsConfig `json:"config,omitempty"`
 
@@ -0,0 +1,63 @@
+package sandbox
+
+import (
+	"runtime"
+	"strconv"
+)
+
+// nsjail confines agents on Linux with namespaces and cgroups.
+type nsjail struct{}
+
+func (nsjail) Name() string { return "nsjail" }
+
+func (nsjail) Available() bool {
+	return runtime.GOOS == "linux" && onPath("nsjail")
+}
+
+func (nsjail) Wrap(command string, p *Policy) (string, error) {
+	return shellJoin(nsjailArgs(command, p)), nil
+}
+
+func nsjailArgs(command string, p *Policy) []string {
+	args := []string{"nsjail",
+		"--mode", "o", "--quiet",
+		// Agents run for hours with the caller's environment and limits,
+		// not nsjail's defaults (10 minutes, 1MB files, a clean env)
+		"--time_limit", "0", "--keep_env",
+		"--rlimit_as", "soft", "--rlimit_cpu", "soft", "--rlimit_fsize", "soft",
+		"--rlimit_nofile", "soft", "--rlimit_nproc", "soft", "--rlimit_stack", "soft",
+		// Everything read-only, then open up what the agent needs. /tmp
+		// stays shared so the agent can reach the tmux server.
+		"--bindmount_ro", "/",
+		"--bindmount", "/dev",
+		"--bindmount", "/tmp",
+	}
+	for _, dir := range p.Hidden {
+		args = append(args, "--tmpfsmount", dir)
+	}
+	for _, path := range p.ReadOnly {
+		args = append(args, "--bindmount_ro", path)
+	}
+	for _, path := range append([]string{p.WorkDir}, p.Writable...) {
+		args = append(args, "--bindmount", path)
+	}
+	args = append(args, "--cwd", p.WorkDir)
+
+	// nsjail gives the jail its own (empty) network namespace by default
+	if !p.DenyNetwork {
+		args = append(args, "--disable_clone_newnet")
+	}
+	if p.HasLimits() {
+		args = append(args, "--use_cgroupv2")
+	}
+	if p.MemoryMB > 0 {
+		args = append(args, "--cgroup_mem_max", strconv.Itoa(p.MemoryMB*1024*1024))
+	}
+	if p.CPUs > 0 {
+		args = append(args, "--cgroup_cpu_ms_per_sec", strconv.Itoa(int(p.CPUs*1000)))
+	}
+	if p.MaxProcs > 0 {
+		args = append(args, "--cgroup_pids_max", strconv.Itoa(p.MaxProcs))
+	}
+	return append(args, "--", "/bin/sh", "-c", command)
+}
@@ -0,0 +1,153 @@
+// Package sandbox confines polecat agents with OS facilities.
+//
+// A rig's sandbox profile (settings/config.json "sandbox") selects a
+// backend and a policy: the agent may write only to its clone and the few
+// shared paths gt itself needs (beads, git metadata, agent state), other
+// polecats' clones are hidden, and network access and resource limits are
+// optional. A backend turns that policy into a wrapper around the agent's
+// startup command, so sandboxing composes with every runtime preset.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Profile names that do not select a backend.
+const (
+	// Off runs agents unconfined (the default).
+	Off = "off"
+	// Auto picks the first backend available on this machine.
+	Auto = "auto"
+)
+
+var (
+	// ErrUnknownBackend is returned for a profile naming no known backend.
+	ErrUnknownBackend = errors.New("unknown sandbox backend")
+	// ErrUnavailable is returned when a backend's tool is not installed.
+	ErrUnavailable = errors.New("sandbox backend not available")
+	// ErrUnsupported is returned when a policy asks for something the
+	// backend cannot enforce.
+	ErrUnsupported = errors.New("not supported by sandbox backend")
+)
+
+// Policy describes what a sandboxed agent may touch.
+type Policy struct {
+	// WorkDir is the agent's clone. It is writable and the starting directory.
+	WorkDir string
+
+	// Writable lists further paths the agent may modify.
+	Writable []string
+
+	// Hidden lists directories whose contents the agent cannot see.
+	// Writable and ReadOnly paths inside them stay visible.
+	Hidden []string
+
+	// ReadOnly lists paths inside Hidden directories to expose read-only.
+	ReadOnly []string
+
+	// DenyNetwork cuts the agent off from the network. Filesystem sockets
+	// (tmux) keep working; the agent's model API does not, unless it is
+	// reached through one.
+	DenyNetwork bool
+
+	// MemoryMB caps the agent's memory (0 = no limit).
+	MemoryMB int
+
+	// CPUs caps the agent's CPU use in cores (0 = no limit).
+	CPUs float64
+
+	// MaxProcs caps the number of processes in the sandbox (0 = no limit).
+	MaxProcs int
+}
+
+// HasLimits reports whether the policy sets any resource limit.
+func (p *Policy) HasLimits() bool {
+	return p.MemoryMB > 0 || p.CPUs > 0 || p.MaxProcs > 0
+}
+
+// Backend enforces a Policy using an OS facility.
+type Backend interface {
+	// Name is the profile name that selects the backend.
+	Name() string
+
+	// Available reports whether the backend can run on this machine.
+	Available() bool
+
+	// Wrap returns a shell command running command under the policy.
+	Wrap(command string, p *Policy) (string, error)
+}
+
+// backends in the order Auto tries them.
+var backends = []Backend{nsjail{}, seatbelt{}}
+
+// Backends returns the known backends.
+func Backends() []Backend {
+	return backends
+}
+
+// Lookup returns the backend for a profile name. It returns nil for Off
+// (or an empty name), and an error when the backend is unknown or its tool
+// is missing: a rig that asks for a sandbox should not silently run
+// without one.
+func Lookup(name string) (Backend, error) {
+	switch name {
+	case "", Off:
+		return nil, nil
+	case Auto:
+		for _, b := range backends {
+			if b.Available() {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: none of %s is installed", ErrUnavailable, strings.Join(names(), ", "))
+	}
+	for _, b := range backends {
+		if b.Name() == name {
+			if !b.Available() {
+				return nil, fmt.Errorf("%w: %s is not installed", ErrUnavailable, name)
+			}
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q (known: %s)", ErrUnknownBackend, name, strings.Join(names(), ", "))
+}
+
+// Wrap runs command under the named profile's backend; with Off it
+// returns command unchanged.
+func Wrap(profile, command string, p *Policy) (string, error) {
+	b, err := Lookup(profile)
+	if err != nil || b == nil {
+		return command, err
+	}
+	return b.Wrap(command, p)
+}
+
+func names() []string {
+	out := make([]string, len(backends))
+	for i, b := range backends {
+		out[i] = b.Name()
+	}
+	return out
+}
+
+func onPath(tool string) bool {
+	_, err := exec.LookPath(tool)
+	return err == nil
+}
+
+// shellQuote quotes s as a single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellJoin quotes and joins an argument vector.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,105 @@
+package sandbox
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testPolicy() *Policy {
+	return &Policy{
+		WorkDir:  "/gt/gastown/polecats/Toast/gastown",
+		Writable: []string{"/gt/gastown/.beads"},
+		Hidden:   []string{"/gt/gastown/polecats"},
+		ReadOnly: []string{"/gt/gastown/polecats/.claude"},
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"", Off} {
+		if b, err := Lookup(name); b != nil || err != nil {
+			t.Errorf("Lookup(%q) = %v, %v; want nil, nil", name, b, err)
+		}
+	}
+	if _, err := Lookup("chroot"); !errors.Is(err, ErrUnknownBackend) {
+		t.Errorf("Lookup(chroot) err = %v, want ErrUnknownBackend", err)
+	}
+	for _, b := range Backends() {
+		if _, err := Lookup(b.Name()); !b.Available() && !errors.Is(err, ErrUnavailable) {
+			t.Errorf("Lookup(%s) err = %v, want ErrUnavailable", b.Name(), err)
+		}
+	}
+}
+
+func TestWrapOff(t *testing.T) {
+	got, err := Wrap(Off, "claude --resume", testPolicy())
+	if err != nil || got != "claude --resume" {
+		t.Errorf("Wrap(off) = %q, %v; want command unchanged", got, err)
+	}
+}
+
+func TestNsjailArgs(t *testing.T) {
+	p := testPolicy()
+	args := strings.Join(nsjailArgs("export A='b c' && claude", p), " ")
+
+	// The clone must be mounted after the tmpfs that hides its siblings
+	hide := strings.Index(args, "--tmpfsmount /gt/gastown/polecats")
+	clone := strings.Index(args, "--bindmount /gt/gastown/polecats/Toast/gastown")
+	if hide < 0 || clone < hide {
+		t.Errorf("clone not mounted after hidden dir:\n%s", args)
+	}
+	for _, want := range []string{"--bindmount_ro / ", "--bindmount_ro /gt/gastown/polecats/.claude",
+		"--cwd /gt/gastown/polecats/Toast/gastown", "--disable_clone_newnet",
+		"-- /bin/sh -c export A='b c' && claude"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("args missing %q:\n%s", want, args)
+		}
+	}
+	if strings.Contains(args, "cgroup") {
+		t.Errorf("args set cgroup limits without any configured:\n%s", args)
+	}
+
+	p.DenyNetwork = true
+	p.MemoryMB = 2048
+	p.MaxProcs = 256
+	args = strings.Join(nsjailArgs("claude", p), " ")
+	if strings.Contains(args, "--disable_clone_newnet") {
+		t.Errorf("network not denied:\n%s", args)
+	}
+	for _, want := range []string{"--use_cgroupv2", "--cgroup_mem_max 2147483648", "--cgroup_pids_max 256"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("args missing %q:\n%s", want, args)
+		}
+	}
+}
+
+func TestSeatbeltProfile(t *testing.T) {
+	p := testPolicy()
+	p.DenyNetwork = true
+	profile := seatbeltProfile(p)
+	for _, want := range []string{
+		"(deny file-write*)",
+		`(subpath "/gt/gastown/polecats/Toast/gastown")`,
+		`(deny file-read* (subpath "/gt/gastown/polecats"))`,
+		`(allow file-read* (subpath "/gt/gastown/polecats/.claude")`,
+		"(deny network*)",
+	} {
+		if !strings.Contains(profile, want) {
+			t.Errorf("profile missing %q:\n%s", want, profile)
+		}
+	}
+	if len(p.ReadOnly) != 1 {
+		t.Errorf("profile rendering modified the policy: %v", p.ReadOnly)
+	}
+
+	p.CPUs = 1
+	if _, err := (seatbelt{}).Wrap("claude", p); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Wrap with limits err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got, want := shellJoin([]string{"sh", "-c", "echo 'hi'"}), `'sh' '-c' 'echo '\''hi'\'''`; got != want {
+		t.Errorf("shellJoin = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,57 @@
+package sandbox
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// seatbelt confines agents on macOS with sandbox-exec profiles.
+type seatbelt struct{}
+
+func (seatbelt) Name() string { return "sandbox-exec" }
+
+func (seatbelt) Available() bool {
+	return runtime.GOOS == "darwin" && onPath("sandbox-exec")
+}
+
+func (seatbelt) Wrap(command string, p *Policy) (string, error) {
+	if p.HasLimits() {
+		return "", fmt.Errorf("resource limits are %w sandbox-exec (use nsjail)", ErrUnsupported)
+	}
+	return shellJoin([]string{"sandbox-exec", "-p", seatbeltProfile(p), "/bin/sh", "-c", command}), nil
+}
+
+// seatbeltProfile renders a policy as SBPL. Later rules take precedence,
+// so each deny is followed by the exceptions to it.
+func seatbeltProfile(p *Policy) string {
+	writable := append([]string{p.WorkDir}, p.Writable...)
+
+	var b strings.Builder
+	b.WriteString("(version 1)\n(allow default)\n")
+	b.WriteString("(deny file-write*)\n")
+	fmt.Fprintf(&b, "(allow file-write* %s)\n",
+		subpaths(append([]string{"/dev", "/private/tmp", "/private/var/folders"}, writable...)))
+	if len(p.Hidden) > 0 {
+		fmt.Fprintf(&b, "(deny file-read* %s)\n", subpaths(p.Hidden))
+		fmt.Fprintf(&b, "(allow file-read* %s)\n", subpaths(append(append([]string{}, p.ReadOnly...), writable...)))
+	}
+	if p.DenyNetwork {
+		b.WriteString("(deny network*)\n(allow network* (remote unix-socket))\n")
+	}
+	return b.String()
+}
+
+// subpaths renders paths as SBPL subpath filters. Profiles match resolved
+// paths (/tmp is /private/tmp), so symlinks are resolved where possible.
+func subpaths(paths []string) string {
+	filters := make([]string, len(paths))
+	for i, path := range paths {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		filters[i] = fmt.Sprintf("(subpath %q)", path)
+	}
+	return strings.Join(filters, " ")
+}
@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// QueueFile is the spawn queue within the town's .runtime directory.
+const QueueFile = "spawn-queue.json"
+
+// Request is a queued spawn.
+type Request struct {
+	ID       string    `json:"id"`
+	Rig      string    `json:"rig"`
+	Bead     string    `json:"bead"`     // bead (or formula) being slung
+	Priority int       `json:"priority"` // bead priority: 0 is most urgent
+	Args     []string  `json:"args"`     // gt arguments that re-run the sling
+	Enqueued time.Time `json:"enqueued"`
+}
+
+// Queue is the town's persistent spawn queue.
+type Queue struct {
+	path string
+}
+
+// NewQueue returns the spawn queue for a town.
+func NewQueue(townRoot string) *Queue {
+	return &Queue{path: filepath.Join(townRoot, ".runtime", QueueFile)}
+}
+
+// Add queues a spawn. A bead already queued for the same rig keeps its
+// place; the existing request is returned.
+func (q *Queue) Add(req Request) (*Request, error) {
+	var added Request
+	err := q.update(func(reqs []Request) []Request {
+		for _, r := range reqs {
+			if r.Rig == req.Rig && r.Bead == req.Bead {
+				added = r
+				return reqs
+			}
+		}
+		if req.ID == "" {
+			req.ID = newID()
+		}
+		if req.Enqueued.IsZero() {
+			req.Enqueued = time.Now().UTC()
+		}
+		added = req
+		return append(reqs, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
+
+// List returns queued spawns, most urgent first.
+func (q *Queue) List() ([]Request, error) {
+	reqs, err := q.load()
+	if err != nil {
+		return nil, err
+	}
+	return sortRequests(reqs), nil
+}
+
+// Position returns a request's 1-based place in the queue, or 0.
+func (q *Queue) Position(id string) int {
+	reqs, err := q.List()
+	if err != nil {
+		return 0
+	}
+	for i, r := range reqs {
+		if r.ID == id {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Remove drops a queued spawn by ID or bead, reporting whether one was
+// dropped.
+func (q *Queue) Remove(idOrBead string) (bool, error) {
+	removed := false
+	err := q.update(func(reqs []Request) []Request {
+		out := reqs[:0]
+		for _, r := range reqs {
+			if r.ID == idOrBead || r.Bead == idOrBead {
+				removed = true
+				continue
+			}
+			out = append(out, r)
+		}
+		return out
+	})
+	return removed, err
+}
+
+// update applies fn to the queue under an exclusive lock.
+func (q *Queue) update(fn func([]Request) []Request) error {
+	if err := os.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	lock := flock.New(q.path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking spawn queue: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	reqs, err := q.load()
+	if err != nil {
+		return err
+	}
+	reqs = fn(reqs)
+	if reqs == nil {
+		reqs = []Request{}
+	}
+	data, err := json.MarshalIndent(reqs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(q.path, append(data, '\n'), 0644)
+}
+
+func (q *Queue) load() ([]Request, error) {
+	data, err := os.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading spawn queue: %w", err)
+	}
+	var reqs []Request
+	if err := json.Unmarshal(data, &reqs); err != nil {
+		return nil, fmt.Errorf("parsing spawn queue: %w", err)
+	}
+	return reqs, nil
+}
+
+// sortRequests orders requests by priority, then by age.
+func sortRequests(reqs []Request) []Request {
+	out := append([]Request(nil), reqs...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Priority != out[j].Priority {
+			return out[i].Priority < out[j].Priority
+		}
+		return out[i].Enqueued.Before(out[j].Enqueued)
+	})
+	return out
+}
+
+// without returns reqs minus those in drop.
+func without(reqs, drop []Request) []Request {
+	dropped := make(map[string]bool, len(drop))
+	for _, r := range drop {
+		dropped[r.ID] = true
+	}
+	var out []Request
+	for _, r := range reqs {
+		if !dropped[r.ID] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func newID() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return "sq-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,161 @@
+// Package scheduler admits polecat spawns against town-wide limits.
+//
+// Every spawn into a rig asks the scheduler first. When the town or the rig
+// already runs its maximum number of polecats, the spawn is queued instead
+// (.runtime/spawn-queue.json) with the bead's priority, and the daemon
+// dispatches queued spawns, most urgent first, as polecats finish. A
+// dispatched spawn re-runs the original gt sling with EnvAdmitted set so it
+// is not queued a second time.
+package scheduler
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// EnvAdmitted marks a spawn the scheduler already admitted.
+const EnvAdmitted = "GT_SPAWN_ADMITTED"
+
+// RigLimitKey is the rig config property capping a rig's polecats
+// (gt rig config set <rig> max_polecats <n>; 0 = no limit).
+const RigLimitKey = "max_polecats"
+
+// Limits caps concurrent polecats. Zero means no limit.
+type Limits struct {
+	Town   int
+	PerRig map[string]int
+}
+
+// Running counts live polecat sessions per rig.
+type Running map[string]int
+
+// Total returns the number of live polecats in the town.
+func (r Running) Total() int {
+	n := 0
+	for _, c := range r {
+		n += c
+	}
+	return n
+}
+
+// Check reports whether another polecat may start in rigName and, if not,
+// which limit is full.
+func (l *Limits) Check(running Running, rigName string) (bool, string) {
+	if l.Town > 0 && running.Total() >= l.Town {
+		return false, fmt.Sprintf("town at %d/%d polecats", running.Total(), l.Town)
+	}
+	if max := l.PerRig[rigName]; max > 0 && running[rigName] >= max {
+		return false, fmt.Sprintf("%s at %d/%d polecats", rigName, running[rigName], max)
+	}
+	return true, ""
+}
+
+// State is a snapshot of the town's limits and live polecats.
+type State struct {
+	Limits  Limits
+	Running Running
+
+	// Warm counts warm spares, which wait for work and are not counted as
+	// running: claiming one starts no new session.
+	Warm Running
+}
+
+// Load reads the town's limits and counts its live polecats.
+func Load(townRoot string) (*State, error) {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil, fmt.Errorf("loading rigs: %w", err)
+	}
+
+	state := &State{
+		Limits:  Limits{PerRig: make(map[string]int)},
+		Running: make(Running),
+		Warm:    make(Running),
+	}
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.Scheduler != nil {
+		state.Limits.Town = settings.Scheduler.MaxPolecats
+	}
+
+	rigMgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	rigPaths := make(map[string]string)
+	for name := range rigsConfig.Rigs {
+		r, err := rigMgr.GetRig(name)
+		if err != nil {
+			continue
+		}
+		state.Limits.PerRig[name] = r.GetIntConfig(RigLimitKey)
+		state.Running[name] = 0
+		rigPaths[name] = r.Path
+	}
+
+	sessions, err := tmux.NewTmux().ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	for _, s := range sessions {
+		id, err := session.ParseSessionName(s)
+		if err != nil || id.Role != session.RolePolecat {
+			continue
+		}
+		rigPath, ok := rigPaths[id.Rig]
+		switch {
+		case !ok:
+		case polecat.IsWarm(rigPath, id.Name):
+			state.Warm[id.Rig]++
+		default:
+			state.Running[id.Rig]++
+		}
+	}
+	return state, nil
+}
+
+// Select returns the queued requests that fit under the limits, most
+// urgent first, counting each as running once selected.
+func Select(queued []Request, state *State) []Request {
+	running := make(Running, len(state.Running))
+	for k, v := range state.Running {
+		running[k] = v
+	}
+	var out []Request
+	for _, req := range sortRequests(queued) {
+		if ok, _ := state.Limits.Check(running, req.Rig); ok {
+			out = append(out, req)
+			running[req.Rig]++
+		}
+	}
+	return out
+}
+
+// Drain dispatches the queued spawns that fit under the limits. Each is
+// removed from the queue before dispatch; a dispatch that fails is
+// reported, not retried, since the sling itself was what failed.
+func Drain(townRoot string, dispatch func(Request) error) ([]Request, []error, error) {
+	state, err := Load(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	q := NewQueue(townRoot)
+	var picked []Request
+	err = q.update(func(reqs []Request) []Request {
+		picked = Select(reqs, state)
+		return without(reqs, picked)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var errs []error
+	for _, req := range picked {
+		if err := dispatch(req); err != nil {
+			errs = append(errs, fmt.Errorf("dispatching %s to %s: %w", req.Bead, req.Rig, err))
+		}
+	}
+	return picked, errs, nil
+}
@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimitsCheck(t *testing.T) {
+	limits := &Limits{Town: 4, PerRig: map[string]int{"gastown": 2, "beads": 0}}
+	tests := []struct {
+		running Running
+		rig     string
+		want    bool
+	}{
+		{Running{"gastown": 1}, "gastown", true},
+		{Running{"gastown": 2}, "gastown", false},
+		{Running{"gastown": 2}, "beads", true},
+		{Running{"gastown": 2, "beads": 2}, "beads", false}, // town is full
+		{Running{"beads": 3}, "beads", true},                // no rig limit
+	}
+	for _, tt := range tests {
+		got, reason := limits.Check(tt.running, tt.rig)
+		if got != tt.want {
+			t.Errorf("Check(%v, %s) = %v (%s), want %v", tt.running, tt.rig, got, reason, tt.want)
+		}
+		if !got && reason == "" {
+			t.Errorf("Check(%v, %s) refused without a reason", tt.running, tt.rig)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	now := time.Now()
+	queued := []Request{
+		{ID: "a", Rig: "gastown", Priority: 2, Enqueued: now},
+		{ID: "b", Rig: "gastown", Priority: 0, Enqueued: now.Add(time.Second)},
+		{ID: "c", Rig: "beads", Priority: 1, Enqueued: now},
+		{ID: "d", Rig: "gastown", Priority: 0, Enqueued: now.Add(2 * time.Second)},
+	}
+	state := &State{
+		Limits:  Limits{Town: 3, PerRig: map[string]int{"gastown": 2}},
+		Running: Running{"gastown": 1},
+	}
+
+	// One gastown slot and two town slots: the most urgent gastown bead,
+	// then the beads rig's
+	got := Select(queued, state)
+	if len(got) != 2 || got[0].ID != "b" || got[1].ID != "c" {
+		t.Errorf("Select = %v, want [b c]", ids(got))
+	}
+	if state.Running["gastown"] != 1 {
+		t.Errorf("Select modified the running counts: %v", state.Running)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	q := NewQueue(t.TempDir())
+
+	first, err := q.Add(Request{Rig: "gastown", Bead: "gt-low", Priority: 3})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := q.Add(Request{Rig: "gastown", Bead: "gt-high", Priority: 1}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	again, err := q.Add(Request{Rig: "gastown", Bead: "gt-low", Priority: 0})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if again.ID != first.ID || again.Priority != 3 {
+		t.Errorf("re-queued bead = %+v, want the original request", again)
+	}
+
+	reqs, err := q.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(reqs) != 2 || reqs[0].Bead != "gt-high" {
+		t.Errorf("List = %v, want gt-high first of 2", reqs)
+	}
+	if pos := q.Position(first.ID); pos != 2 {
+		t.Errorf("Position(gt-low) = %d, want 2", pos)
+	}
+
+	if removed, err := q.Remove("gt-high"); err != nil || !removed {
+		t.Errorf("Remove(gt-high) = %v, %v", removed, err)
+	}
+	if removed, _ := q.Remove("gt-high"); removed {
+		t.Error("Remove(gt-high) removed twice")
+	}
+	if reqs, _ := q.List(); len(reqs) != 1 {
+		t.Errorf("List after remove = %v", reqs)
+	}
+}
+
+func ids(reqs []Request) []string {
+	out := make([]string, len(reqs))
+	for i, r := range reqs {
+		out[i] = r.ID
+	}
+	return out
+}
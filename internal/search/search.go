@@ -0,0 +1,318 @@
+// Package search is a small full-text index over a town's beads, mail and
+// polecat transcripts, for 'gt search'.
+//
+// Each indexed item is a Doc holding its term counts; a query scores docs
+// with BM25. The index lives in <town>/.runtime/search-index.json and is
+// refreshed incrementally: beads are re-read only when their updated_at
+// changes, transcripts only when the file grows.
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// IndexFile is the index within the town's .runtime directory.
+const IndexFile = "search-index.json"
+
+// indexVersion changes when the tokenizer or doc layout does, so old
+// indexes are rebuilt rather than misread.
+const indexVersion = 1
+
+// Kinds of indexed item.
+const (
+	KindBead       = "bead"
+	KindMail       = "mail"
+	KindTranscript = "transcript"
+)
+
+// Doc is one indexed item: a bead, a mail message or a chunk of a
+// transcript.
+type Doc struct {
+	Key    string    `json:"key"`
+	Kind   string    `json:"kind"`
+	Source string    `json:"source"`         // What the doc was read from, for refreshes
+	Rig    string    `json:"rig,omitempty"`  // Empty for town-level items
+	Type   string    `json:"type,omitempty"` // Bead type (task, bug, ...)
+	ID     string    `json:"id,omitempty"`   // Bead or message ID; a transcript's bead
+	Title  string    `json:"title"`
+	Date   time.Time `json:"date"`
+	Stamp  string    `json:"stamp,omitempty"` // Source state when indexed
+
+	// Text is kept for beads and mail, to show snippets. Transcripts are
+	// read back from Path at Line instead.
+	Text string `json:"text,omitempty"`
+	Path string `json:"path,omitempty"`
+	Line int    `json:"line,omitempty"`
+
+	Terms  map[string]int `json:"terms"`
+	Length int            `json:"length"`
+}
+
+// Index is the set of indexed docs.
+type Index struct {
+	Version int             `json:"version"`
+	Docs    map[string]*Doc `json:"docs"`
+
+	// Sources records the state of each source when it was last indexed.
+	Sources map[string]string `json:"sources,omitempty"`
+}
+
+// New returns an empty index.
+func New() *Index {
+	return &Index{Version: indexVersion, Docs: map[string]*Doc{}, Sources: map[string]string{}}
+}
+
+// Path returns the index path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, IndexFile)
+}
+
+// Load reads a town's index. A missing or outdated index is empty.
+func Load(townRoot string) (*Index, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ix := New()
+	if err := json.Unmarshal(data, ix); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", IndexFile, err)
+	}
+	if ix.Version != indexVersion {
+		return New(), nil
+	}
+	if ix.Docs == nil {
+		ix.Docs = map[string]*Doc{}
+	}
+	if ix.Sources == nil {
+		ix.Sources = map[string]string{}
+	}
+	return ix, nil
+}
+
+// Update loads the index, applies fn and saves it, holding a lock so two
+// searches refreshing at once don't clobber each other.
+func Update(townRoot string, fn func(*Index) error) (*Index, error) {
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking search index: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	ix, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(ix); err != nil {
+		return nil, err
+	}
+	return ix, util.AtomicWriteJSON(path, ix)
+}
+
+// Put adds or replaces a doc, indexing its title (weighted double) and
+// text. body is indexed without being kept, for transcripts.
+func (ix *Index) Put(d *Doc, body string) {
+	d.Terms = map[string]int{}
+	d.Length = 0
+	for _, t := range Tokenize(d.Title) {
+		d.Terms[t] += 2
+		d.Length += 2
+	}
+	for _, t := range Tokenize(d.Text + "\n" + body) {
+		d.Terms[t]++
+		d.Length++
+	}
+	ix.Docs[d.Key] = d
+}
+
+// RemoveSource drops the docs read from source except those kept.
+func (ix *Index) RemoveSource(source string, keep map[string]bool) {
+	for key, d := range ix.Docs {
+		if d.Source == source && !keep[key] {
+			delete(ix.Docs, key)
+		}
+	}
+}
+
+// stopWords are too common to be worth indexing.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "the": true, "this": true,
+	"to": true, "was": true, "with": true,
+}
+
+// Tokenize lowercases s and splits it into indexable words: letters and
+// digits, dropping stop words and single characters. Plurals are folded
+// ("tests" is "test"), so either form finds both.
+func Tokenize(s string) []string {
+	var out []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) > 1 && !stopWords[w] {
+			if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+				w = w[:len(w)-1]
+			}
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// Query is a search with its filters.
+type Query struct {
+	Text  string
+	Rig   string    // Only docs in this rig ("town" for town-level ones)
+	Kind  string    // Only this kind of doc
+	Type  string    // Only beads of this type
+	Since time.Time // Only docs dated at or after Since
+	Until time.Time // Only docs dated before Until
+	Limit int
+}
+
+// Hit is a matching doc and its score.
+type Hit struct {
+	Doc   *Doc    `json:"doc"`
+	Score float64 `json:"score"`
+}
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Search returns the docs matching q, best first. Docs matching more of
+// the query's words rank higher; a doc must match at least one.
+func (ix *Index) Search(q Query) []Hit {
+	terms := Tokenize(q.Text)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var docs []*Doc
+	var total int
+	for _, d := range ix.Docs {
+		if q.matches(d) {
+			docs = append(docs, d)
+			total += d.Length
+		}
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	avgLen := float64(total) / float64(len(docs))
+
+	df := map[string]int{}
+	for _, d := range docs {
+		for _, t := range terms {
+			if d.Terms[t] > 0 {
+				df[t]++
+			}
+		}
+	}
+
+	n := float64(len(docs))
+	var hits []Hit
+	for _, d := range docs {
+		score := 0.0
+		for _, t := range terms {
+			tf := float64(d.Terms[t])
+			if tf == 0 {
+				continue
+			}
+			idf := math.Log(1 + (n-float64(df[t])+0.5)/(float64(df[t])+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(d.Length)/avgLen))
+		}
+		if score > 0 {
+			hits = append(hits, Hit{Doc: d, Score: score})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Doc.Date.After(hits[j].Doc.Date)
+	})
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits
+}
+
+func (q Query) matches(d *Doc) bool {
+	switch {
+	case q.Rig == "town" && d.Rig != "":
+		return false
+	case q.Rig != "" && q.Rig != "town" && d.Rig != q.Rig:
+		return false
+	case q.Kind != "" && d.Kind != q.Kind:
+		return false
+	case q.Type != "" && !strings.EqualFold(d.Type, q.Type):
+		return false
+	case !q.Since.IsZero() && d.Date.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !d.Date.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+// Snippet returns the first line of a hit that contains a query word,
+// trimmed to width, or the start of its text.
+func (h Hit) Snippet(query string, width int) string {
+	var lines []string
+	if h.Doc.Path != "" {
+		lines = transcriptLines(h.Doc.Path, h.Doc.Line, transcriptChunk)
+	} else {
+		lines = strings.Split(h.Doc.Text, "\n")
+	}
+	terms := Tokenize(query)
+	best := ""
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if best == "" {
+			best = line
+		}
+		lower := strings.ToLower(line)
+		if containsAny(lower, terms) {
+			best = line
+			break
+		}
+	}
+	if r := []rune(best); len(r) > width {
+		best = string(r[:width-1]) + "…"
+	}
+	return best
+}
+
+func containsAny(s string, terms []string) bool {
+	for _, t := range terms {
+		if strings.Contains(s, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package search
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Fix the flaky WebSocket tests (gt-abc): it's in ws_test.go, class")
+	want := []string{"fix", "flaky", "websocket", "test", "gt", "abc", "ws", "test", "go", "class"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC) }
+	ix := New()
+	ix.Put(&Doc{Key: "bead:gt-1", Kind: KindBead, Rig: "gastown", Type: "bug", ID: "gt-1",
+		Title: "Flaky websocket test", Text: "TestWebsocketReconnect fails one run in ten.", Date: day(3)}, "")
+	ix.Put(&Doc{Key: "bead:gt-2", Kind: KindBead, Rig: "gastown", Type: "task", ID: "gt-2",
+		Title: "Add websocket metrics", Text: "Count reconnects.", Date: day(5)}, "")
+	ix.Put(&Doc{Key: "mail:hq-1", Kind: KindMail, ID: "hq-1",
+		Title: "Deploy tonight", Text: "The websocket fix is flaky, hold off.", Date: day(6)}, "")
+	ix.Put(&Doc{Key: "bead:bd-1", Kind: KindBead, Rig: "beads", Type: "bug", ID: "bd-1",
+		Title: "Slow sync", Text: "Nothing to do with sockets.", Date: day(7)}, "")
+
+	keys := func(hits []Hit) []string {
+		var out []string
+		for _, h := range hits {
+			out = append(out, h.Doc.Key)
+		}
+		return out
+	}
+
+	// Docs matching more of the query, and in the title, rank first
+	if got, want := keys(ix.Search(Query{Text: "flaky websocket test"})), []string{"bead:gt-1", "mail:hq-1", "bead:gt-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ranking = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"rig", Query{Text: "websocket", Rig: "gastown", Type: "bug"}, []string{"bead:gt-1"}},
+		{"town", Query{Text: "websocket", Rig: "town"}, []string{"mail:hq-1"}},
+		{"kind", Query{Text: "websocket flaky", Kind: KindMail}, []string{"mail:hq-1"}},
+		{"dates", Query{Text: "websocket", Since: day(4), Until: day(6)}, []string{"bead:gt-2"}},
+		{"limit", Query{Text: "flaky websocket test", Limit: 1}, []string{"bead:gt-1"}},
+		{"no match", Query{Text: "kubernetes"}, nil},
+	}
+	for _, tt := range tests {
+		if got := keys(ix.Search(tt.q)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	hit := ix.Search(Query{Text: "reconnect", Kind: KindBead, Type: "task"})
+	if len(hit) != 1 || hit[0].Snippet("reconnect", 80) != "Count reconnects." {
+		t.Errorf("snippet hits = %v", hit)
+	}
+}
+
+func TestIndexTranscripts(t *testing.T) {
+	townRoot := t.TempDir()
+	e, err := transcript.Begin(townRoot, "gastown", "Toast", "gt-gastown-Toast", "gt-abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body strings.Builder
+	for i := 0; i < 50; i++ {
+		body.WriteString("running go test ./...\n")
+	}
+	body.WriteString("--- FAIL: TestWebsocketReconnect (3.00s)\n")
+	f, err := os.OpenFile(e.Path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(body.String())
+	_ = f.Close()
+
+	ix := New()
+	if err := ix.IndexTranscripts(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	hits := ix.Search(Query{Text: "TestWebsocketReconnect"})
+	if len(hits) != 1 {
+		t.Fatalf("hits = %v, want one transcript chunk", hits)
+	}
+	d := hits[0].Doc
+	if d.Kind != KindTranscript || d.Rig != "gastown" || d.ID != "gt-abc" || d.Line != transcriptChunk+1 {
+		t.Errorf("doc = %+v", d)
+	}
+	if got := hits[0].Snippet("TestWebsocketReconnect", 200); !strings.Contains(got, "FAIL: TestWebsocketReconnect") {
+		t.Errorf("snippet = %q", got)
+	}
+
+	// Unchanged transcripts aren't re-read; removed ones drop out
+	docs := len(ix.Docs)
+	if err := ix.IndexTranscripts(townRoot); err != nil || len(ix.Docs) != docs {
+		t.Errorf("reindex changed docs: %d -> %d (%v)", docs, len(ix.Docs), err)
+	}
+	if err := os.Remove(e.Path); err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.IndexTranscripts(townRoot); err != nil || len(ix.Docs) != 0 || len(ix.Sources) != 0 {
+		t.Errorf("after removal: %d docs, %d sources (%v)", len(ix.Docs), len(ix.Sources), err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := Update(townRoot, func(ix *Index) error {
+		ix.Put(&Doc{Key: "bead:gt-1", Kind: KindBead, Title: "Flaky websocket test"}, "")
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ix, err := Load(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hits := ix.Search(Query{Text: "websocket"}); len(hits) != 1 {
+		t.Errorf("hits after reload = %v", hits)
+	}
+}
@@ -0,0 +1,152 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+// transcriptChunk is how many transcript lines make one doc, so a hit
+// points near the match rather than at a whole session.
+const transcriptChunk = 40
+
+// IndexBeads brings the index up to date with a beads database: rig is
+// the rig it belongs to, or empty for the town's. Messages are indexed as
+// mail. Beads unchanged since they were indexed aren't re-read.
+func (ix *Index) IndexBeads(rig string, b *beads.Beads) error {
+	source := "beads:" + rig
+	issues, err := b.List(beads.ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return err
+	}
+
+	keep := map[string]bool{}
+	for _, issue := range issues {
+		kind := KindBead
+		if issue.Type == "message" {
+			kind = KindMail
+		}
+		key := kind + ":" + issue.ID
+		keep[key] = true
+		stamp := issue.UpdatedAt + "|" + issue.Status
+		if d := ix.Docs[key]; d != nil && d.Stamp == stamp {
+			continue
+		}
+
+		text := issue.Description
+		if kind == KindBead {
+			// Comments are best effort: older bd versions can't list them
+			if comments, err := b.Comments(issue.ID); err == nil {
+				for _, c := range comments {
+					text += fmt.Sprintf("\n%s: %s", c.Author, c.Text)
+				}
+			}
+		}
+		ix.Put(&Doc{
+			Key:    key,
+			Kind:   kind,
+			Source: source,
+			Rig:    rig,
+			Type:   issue.Type,
+			ID:     issue.ID,
+			Title:  issue.Title,
+			Date:   parseTime(issue.UpdatedAt, issue.CreatedAt),
+			Stamp:  stamp,
+			Text:   text,
+		}, "")
+	}
+	ix.RemoveSource(source, keep)
+	return nil
+}
+
+// IndexTranscripts brings the index up to date with the town's polecat
+// transcripts, re-reading only those that changed.
+func (ix *Index) IndexTranscripts(townRoot string) error {
+	entries, err := transcript.List(townRoot)
+	if err != nil {
+		return err
+	}
+	// Sessions for the same bead share a file; the latest entry names it
+	latest := map[string]transcript.Entry{}
+	for _, e := range entries {
+		latest[e.Path] = e
+	}
+
+	live := map[string]bool{}
+	for path, e := range latest {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		source := "transcript:" + path
+		live[source] = true
+		stamp := fmt.Sprintf("%d|%d", info.Size(), info.ModTime().UnixNano())
+		if ix.Sources[source] == stamp {
+			continue
+		}
+
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the transcript index
+		if err != nil {
+			continue
+		}
+		ix.RemoveSource(source, nil)
+		lines := strings.Split(transcript.Clean(string(data)), "\n")
+		title := e.Agent()
+		if e.Bead != "" {
+			title += " " + e.Bead
+		}
+		for start := 0; start < len(lines); start += transcriptChunk {
+			end := min(start+transcriptChunk, len(lines))
+			ix.Put(&Doc{
+				Key:    fmt.Sprintf("%s#%d", source, start+1),
+				Kind:   KindTranscript,
+				Source: source,
+				Rig:    e.Rig,
+				ID:     e.Bead,
+				Title:  title,
+				Date:   info.ModTime(),
+				Path:   path,
+				Line:   start + 1,
+			}, strings.Join(lines[start:end], "\n"))
+		}
+		ix.Sources[source] = stamp
+	}
+
+	// Drop transcripts that are gone
+	for key, d := range ix.Docs {
+		if d.Kind == KindTranscript && !live[d.Source] {
+			delete(ix.Docs, key)
+		}
+	}
+	for source := range ix.Sources {
+		if strings.HasPrefix(source, "transcript:") && !live[source] {
+			delete(ix.Sources, source)
+		}
+	}
+	return nil
+}
+
+// transcriptLines returns n cleaned lines of a transcript from line (1-based).
+func transcriptLines(path string, line, n int) []string {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the transcript index
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(transcript.Clean(string(data)), "\n")
+	start := min(max(line-1, 0), len(lines))
+	return lines[start:min(start+n, len(lines))]
+}
+
+// parseTime parses the first bd timestamp that parses.
+func parseTime(values ...string) time.Time {
+	for _, v := range values {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
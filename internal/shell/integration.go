@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/state"
@@ -17,16 +18,36 @@ const (
 	markerEnd   = "# --- End Gas Town ---"
 )
 
-func hookSourceLine() string {
+// PowerShell is the shell name for Windows PowerShell and pwsh.
+const PowerShell = "powershell"
+
+func hookSourceLine(rcPath string) string {
+	if isPowerShellProfile(rcPath) {
+		hook := filepath.Join(state.ConfigDir(), "shell-hook.ps1")
+		return fmt.Sprintf(`if (Test-Path '%s') { . '%s' }`, hook, hook)
+	}
 	return fmt.Sprintf(`[[ -f "%s/shell-hook.sh" ]] && source "%s/shell-hook.sh"`,
 		state.ConfigDir(), state.ConfigDir())
 }
 
+// isPowerShellProfile reports whether an RC file is a PowerShell profile.
+func isPowerShellProfile(rcPath string) bool {
+	return strings.EqualFold(filepath.Ext(rcPath), ".ps1")
+}
+
+// hookScriptName is the hook script each shell's RC file sources.
+func hookScriptName(shell string) string {
+	if shell == PowerShell {
+		return "shell-hook.ps1"
+	}
+	return "shell-hook.sh"
+}
+
 func Install() error {
 	shell := DetectShell()
 	rcPath := RCFilePath(shell)
 
-	if err := writeHookScript(); err != nil {
+	if err := writeHookScript(shell); err != nil {
 		return fmt.Errorf("writing hook script: %w", err)
 	}
 
@@ -34,6 +55,10 @@ func Install() error {
 		return fmt.Errorf("updating %s: %w", rcPath, err)
 	}
 
+	// Record what we touched so gt uninstall can undo it
+	_ = state.RecordInstall(state.KindFile, HookScriptPath(shell), "shell hook")
+	_ = state.RecordInstall(state.KindRCBlock, rcPath, shell)
+
 	return state.SetShellIntegration(shell)
 }
 
@@ -45,7 +70,7 @@ func Remove() error {
 		return fmt.Errorf("updating %s: %w", rcPath, err)
 	}
 
-	hookPath := filepath.Join(state.ConfigDir(), "shell-hook.sh")
+	hookPath := HookScriptPath(shell)
 	if err := os.Remove(hookPath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("removing hook script: %w", err)
 	}
@@ -53,6 +78,28 @@ func Remove() error {
 	return nil
 }
 
+// HookScriptPath returns where Install writes the hook script for a shell.
+func HookScriptPath(shell string) string {
+	return filepath.Join(state.ConfigDir(), hookScriptName(shell))
+}
+
+// RCFiles returns the RC file of every shell gt can integrate with.
+func RCFiles() []string {
+	return []string{RCFilePath("zsh"), RCFilePath("bash"), RCFilePath(PowerShell)}
+}
+
+// HasBlock reports whether an RC file contains the Gas Town block.
+func HasBlock(rcPath string) bool {
+	data, err := os.ReadFile(rcPath)
+	return err == nil && strings.Contains(string(data), markerStart)
+}
+
+// RemoveBlock removes the Gas Town block from an RC file, leaving the
+// rest of the file alone. A file without the block is left untouched.
+func RemoveBlock(rcPath string) error {
+	return removeFromRCFile(rcPath)
+}
+
 func DetectShell() string {
 	shell := os.Getenv("SHELL")
 	if strings.HasSuffix(shell, "zsh") {
@@ -61,6 +108,9 @@ func DetectShell() string {
 	if strings.HasSuffix(shell, "bash") {
 		return "bash"
 	}
+	if strings.HasSuffix(shell, "pwsh") || (shell == "" && runtime.GOOS == "windows") {
+		return PowerShell
+	}
 	return "zsh"
 }
 
@@ -69,19 +119,28 @@ func RCFilePath(shell string) string {
 	switch shell {
 	case "bash":
 		return filepath.Join(home, ".bashrc")
+	case PowerShell:
+		// $PROFILE for PowerShell 7; pwsh on Unix keeps it under ~/.config
+		if runtime.GOOS == "windows" {
+			return filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1")
+		}
+		return filepath.Join(home, ".config", "powershell", "Microsoft.PowerShell_profile.ps1")
 	default:
 		return filepath.Join(home, ".zshrc")
 	}
 }
 
-func writeHookScript() error {
+func writeHookScript(shell string) error {
 	dir := state.ConfigDir()
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
-	hookPath := filepath.Join(dir, "shell-hook.sh")
-	return os.WriteFile(hookPath, []byte(shellHookScript), 0644)
+	script := shellHookScript
+	if shell == PowerShell {
+		script = powerShellHookScript
+	}
+	return os.WriteFile(HookScriptPath(shell), []byte(script), 0644)
 }
 
 func addToRCFile(path string) error {
@@ -95,7 +154,11 @@ func addToRCFile(path string) error {
 		return updateRCFile(path, content)
 	}
 
-	block := fmt.Sprintf("\n%s\n%s\n%s\n", markerStart, hookSourceLine(), markerEnd)
+	block := fmt.Sprintf("\n%s\n%s\n%s\n", markerStart, hookSourceLine(path), markerEnd)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
 
 	if len(data) > 0 {
 		backupPath := path + ".gastown-backup"
@@ -145,7 +208,7 @@ func updateRCFile(path, content string) error {
 	}
 	endIdx += startIdx + len(markerEnd)
 
-	block := fmt.Sprintf("%s\n%s\n%s", markerStart, hookSourceLine(), markerEnd)
+	block := fmt.Sprintf("%s\n%s\n%s", markerStart, hookSourceLine(path), markerEnd)
 	newContent := content[:startIdx] + block + content[endIdx:]
 
 	return os.WriteFile(path, []byte(newContent), 0644)
@@ -301,3 +364,47 @@ esac
 
 _gastown_hook
 `
+
+var powerShellHookScript = `# Gas Town Shell Integration (PowerShell)
+# Installed by: gt install --shell
+# Location: ~/.config/gastown/shell-hook.ps1
+
+# Wrapper scripts (gt install --wrappers) live in ~/bin
+$_GastownBin = Join-Path $HOME 'bin'
+if (($env:Path -split [IO.Path]::PathSeparator) -notcontains $_GastownBin) {
+    $env:Path = $_GastownBin + [IO.Path]::PathSeparator + $env:Path
+}
+
+function global:_GastownEnabled {
+    if ($env:GASTOWN_DISABLED) { return $false }
+    if ($env:GASTOWN_ENABLED) { return $true }
+    $stateFile = Join-Path $HOME '.local/state/gastown/state.json'
+    (Test-Path $stateFile) -and ((Get-Content -Raw $stateFile) -match '"enabled":\s*true')
+}
+
+# Sets GT_TOWN_ROOT and GT_RIG from 'gt rig detect' when the directory changes.
+function global:_GastownHook {
+    if ($PWD.Path -eq $global:_GastownLastDir) { return }
+    $global:_GastownLastDir = $PWD.Path
+    Remove-Item Env:GT_TOWN_ROOT, Env:GT_RIG -ErrorAction SilentlyContinue
+
+    if (-not (_GastownEnabled)) { return }
+    if (-not (Get-Command gt -ErrorAction SilentlyContinue)) { return }
+    $repoRoot = git rev-parse --show-toplevel 2>$null
+    if (-not $repoRoot) { return }
+
+    foreach ($line in (gt rig detect $repoRoot 2>$null)) {
+        if ($line -match '^export (\w+)="(.*)"$') {
+            Set-Item -Path "Env:$($Matches[1])" -Value $Matches[2]
+        }
+    }
+}
+
+if (-not $global:_GastownPrompt) {
+    $global:_GastownPrompt = $function:prompt
+    function global:prompt {
+        _GastownHook
+        & $global:_GastownPrompt
+    }
+}
+`
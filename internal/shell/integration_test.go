@@ -45,6 +45,7 @@ func TestRCFilePath(t *testing.T) {
 	}{
 		{"zsh", filepath.Join(home, ".zshrc")},
 		{"bash", filepath.Join(home, ".bashrc")},
+		{PowerShell, filepath.Join(home, ".config", "powershell", "Microsoft.PowerShell_profile.ps1")},
 	}
 
 	for _, tt := range tests {
@@ -130,3 +131,21 @@ func TestUpdateRCFile(t *testing.T) {
 		t.Errorf("RC file has %d start markers, want 1", startCount)
 	}
 }
+
+func TestAddToPowerShellProfile(t *testing.T) {
+	// The profile's directory (Documents/PowerShell) often doesn't exist yet
+	rcPath := filepath.Join(t.TempDir(), "PowerShell", "Microsoft.PowerShell_profile.ps1")
+
+	if err := addToRCFile(rcPath); err != nil {
+		t.Fatalf("addToRCFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(rcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.Contains(content, "shell-hook.ps1") || strings.Contains(content, "source") {
+		t.Errorf("PowerShell profile should dot-source shell-hook.ps1, got:\n%s", content)
+	}
+}
@@ -0,0 +1,103 @@
+// ABOUTME: Install manifest recording what gt install put on the machine.
+// ABOUTME: Gives gt uninstall a recorded inverse of the install flow.
+
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Kinds of things an install touches.
+const (
+	KindTown    = "town"     // A town (HQ) directory gt install created
+	KindHarness = "harness"  // A harness registration (Detail is its name)
+	KindRCBlock = "rc-block" // A managed block in a shell RC file
+	KindFile    = "file"     // A file written outside any town
+	KindUnit    = "unit"     // A systemd or launchd unit for a daemon
+)
+
+// ManifestEntry is one thing the install flow touched.
+type ManifestEntry struct {
+	Kind   string    `json:"kind"`
+	Path   string    `json:"path"`
+	Detail string    `json:"detail,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Manifest lists everything gt install touched on this machine.
+type Manifest struct {
+	Entries []ManifestEntry `json:"entries"`
+}
+
+// ManifestPath returns the path to the install manifest.
+func ManifestPath() string {
+	return filepath.Join(StateDir(), "install-manifest.json")
+}
+
+// LoadManifest reads the install manifest. A missing manifest is empty.
+func LoadManifest() (*Manifest, error) {
+	data, err := os.ReadFile(ManifestPath())
+	if os.IsNotExist(err) {
+		return &Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// SaveManifest writes the install manifest atomically.
+func SaveManifest(m *Manifest) error {
+	if err := os.MkdirAll(StateDir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := ManifestPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, ManifestPath())
+}
+
+// RecordInstall adds an entry to the install manifest. Recording the same
+// kind and path again only updates its detail and time.
+func RecordInstall(kind, path, detail string) error {
+	m, err := LoadManifest()
+	if err != nil {
+		return err
+	}
+	m.Add(ManifestEntry{Kind: kind, Path: path, Detail: detail, At: time.Now()})
+	return SaveManifest(m)
+}
+
+// Add adds an entry, replacing any with the same kind and path.
+func (m *Manifest) Add(e ManifestEntry) {
+	for i := range m.Entries {
+		if m.Entries[i].Kind == e.Kind && m.Entries[i].Path == e.Path {
+			m.Entries[i] = e
+			return
+		}
+	}
+	m.Entries = append(m.Entries, e)
+}
+
+// OfKind returns the entries of the given kind, in the order recorded.
+func (m *Manifest) OfKind(kind string) []ManifestEntry {
+	var out []ManifestEntry
+	for _, e := range m.Entries {
+		if e.Kind == kind {
+			out = append(out, e)
+		}
+	}
+	return out
+}
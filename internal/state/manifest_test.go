@@ -0,0 +1,42 @@
+// ABOUTME: Tests for the install manifest.
+// ABOUTME: Verifies recording, de-duplication and filtering by kind.
+
+package state
+
+import (
+	"testing"
+)
+
+func TestRecordInstall(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	m, err := LoadManifest()
+	if err != nil || len(m.Entries) != 0 {
+		t.Fatalf("LoadManifest with no file = %+v, %v", m, err)
+	}
+
+	if err := RecordInstall(KindTown, "/home/user/gt", "gt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordInstall(KindRCBlock, "/home/user/.zshrc", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordInstall(KindTown, "/home/user/gt", "renamed"); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = LoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Entries) != 2 {
+		t.Fatalf("Entries = %+v, want 2 (re-recording replaces)", m.Entries)
+	}
+	towns := m.OfKind(KindTown)
+	if len(towns) != 1 || towns[0].Detail != "renamed" || towns[0].At.IsZero() {
+		t.Errorf("OfKind(town) = %+v", towns)
+	}
+	if got := m.OfKind(KindUnit); len(got) != 0 {
+		t.Errorf("OfKind(unit) = %+v, want none", got)
+	}
+}
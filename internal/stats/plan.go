@@ -0,0 +1,90 @@
+package stats
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultPoints is what an unestimated bead counts as, when nothing else
+// in its set is estimated either: a medium.
+const DefaultPoints = 3
+
+// PlanItem is a bead still to be done.
+type PlanItem struct {
+	Rig       string  `json:"rig,omitempty"`
+	ID        string  `json:"id"`
+	Title     string  `json:"title"`
+	Status    string  `json:"status"`
+	Points    float64 `json:"points"`
+	Estimated bool    `json:"estimated"` // False if Points is a stand-in
+}
+
+// Plan projects when a set of beads will be done at recent throughput.
+type Plan struct {
+	Remaining       []PlanItem `json:"remaining"`
+	Done            int        `json:"done"`
+	RemainingPoints float64    `json:"remaining_points"`
+	Unestimated     int        `json:"unestimated"`
+
+	// Throughput over the window, in points: closed beads count their
+	// estimate, or DefaultPoints.
+	Since     time.Time `json:"since"`
+	Until     time.Time `json:"until"`
+	Closed    int       `json:"closed"`
+	Completed float64   `json:"completed_points"`
+	PerDay    float64   `json:"points_per_day"`
+
+	// Days and ETA are zero when there is no throughput to project from.
+	Days float64   `json:"days,omitempty"`
+	ETA  time.Time `json:"eta,omitempty"`
+}
+
+// Project builds a plan for the remaining items from the beads closed in
+// [since, until). Unestimated items count as the mean of the estimated
+// ones, so a set sized in large points isn't diluted by stand-ins.
+func Project(remaining []PlanItem, done int, closed []Bead, since, until time.Time) *Plan {
+	p := &Plan{Remaining: remaining, Done: done, Since: since, Until: until}
+	if p.Remaining == nil {
+		p.Remaining = []PlanItem{}
+	}
+
+	var estimated float64
+	n := 0
+	for _, item := range remaining {
+		if item.Estimated {
+			estimated += item.Points
+			n++
+		}
+	}
+	standIn := float64(DefaultPoints)
+	if n > 0 {
+		standIn = estimated / float64(n)
+	}
+	for i := range p.Remaining {
+		if !p.Remaining[i].Estimated {
+			p.Remaining[i].Points = standIn
+			p.Unestimated++
+		}
+		p.RemainingPoints += p.Remaining[i].Points
+	}
+
+	for _, b := range closed {
+		if b.Closed.Before(since) || !b.Closed.Before(until) {
+			continue
+		}
+		p.Closed++
+		if b.Points > 0 {
+			p.Completed += b.Points
+		} else {
+			p.Completed += DefaultPoints
+		}
+	}
+	if days := until.Sub(since).Hours() / 24; days > 0 {
+		p.PerDay = p.Completed / days
+	}
+	if p.PerDay > 0 {
+		p.Days = math.Ceil(p.RemainingPoints/p.PerDay*10) / 10
+		p.ETA = until.Add(time.Duration(p.Days * 24 * float64(time.Hour)))
+	}
+	return p
+}
@@ -0,0 +1,41 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProject(t *testing.T) {
+	until := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	since := until.AddDate(0, 0, -10)
+
+	remaining := []PlanItem{
+		{ID: "gt-1", Points: 5, Estimated: true},
+		{ID: "gt-2", Points: 3, Estimated: true},
+		{ID: "gt-3"}, // Counts as the mean, 4
+	}
+	closed := []Bead{
+		{ID: "gt-a", Closed: since.Add(time.Hour), Points: 8},
+		{ID: "gt-b", Closed: since.Add(48 * time.Hour)}, // Unestimated: DefaultPoints
+		{ID: "gt-c", Closed: since.Add(-time.Hour), Points: 100},
+	}
+
+	p := Project(remaining, 2, closed, since, until)
+	if p.RemainingPoints != 12 || p.Unestimated != 1 {
+		t.Errorf("remaining = %v points, %d unestimated; want 12, 1", p.RemainingPoints, p.Unestimated)
+	}
+	if p.Closed != 2 || p.Completed != 11 {
+		t.Errorf("throughput = %d beads, %v points; want 2, 11", p.Closed, p.Completed)
+	}
+	if p.PerDay != 1.1 {
+		t.Errorf("PerDay = %v, want 1.1", p.PerDay)
+	}
+	if p.Days != 11 || !p.ETA.Equal(until.AddDate(0, 0, 11)) {
+		t.Errorf("Days = %v, ETA = %v", p.Days, p.ETA)
+	}
+
+	idle := Project(remaining, 0, nil, since, until)
+	if idle.PerDay != 0 || !idle.ETA.IsZero() {
+		t.Errorf("no throughput projected an ETA: %+v", idle)
+	}
+}
@@ -0,0 +1,317 @@
+// Package stats computes throughput analytics for 'gt stats': beads closed
+// per week, cycle times, molecule run outcomes and the refineries' merge
+// success rate, overall and per rig. Project turns the same throughput
+// into a completion date for 'gt plan'.
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/molruns"
+)
+
+// Duration is a time.Duration that marshals to JSON as seconds.
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Seconds())
+}
+
+// Bead is a closed unit of work.
+type Bead struct {
+	Rig     string    `json:"rig,omitempty"` // Empty for town-level beads
+	ID      string    `json:"id"`
+	Title   string    `json:"title,omitempty"`
+	Type    string    `json:"type"`
+	Created time.Time `json:"created"`
+	Closed  time.Time `json:"closed"`
+	Points  float64   `json:"points,omitempty"` // Its estimate; 0 if unestimated
+}
+
+// Merge is one refinery merge attempt.
+type Merge struct {
+	Rig    string    `json:"rig"`
+	At     time.Time `json:"at"`
+	OK     bool      `json:"ok"`
+	MR     string    `json:"mr,omitempty"`
+	Worker string    `json:"worker,omitempty"`
+	Branch string    `json:"branch,omitempty"`
+	Reason string    `json:"reason,omitempty"` // Why it failed
+}
+
+// Input is what a report is computed from.
+type Input struct {
+	Beads  []Bead
+	Runs   []*molruns.Run
+	Merges []Merge
+}
+
+// Options limits a report to a time window and optionally one rig.
+type Options struct {
+	Since time.Time
+	Until time.Time
+	Rig   string // "town" for town-level beads only
+}
+
+// Week is the number of beads closed in the week starting at Start.
+type Week struct {
+	Start  time.Time `json:"start"`
+	Closed int       `json:"closed"`
+}
+
+// Molecule is the run outcomes of one formula.
+type Molecule struct {
+	Formula     string   `json:"formula"`
+	Runs        int      `json:"runs"`
+	Complete    int      `json:"complete"`
+	Failed      int      `json:"failed"`
+	Burned      int      `json:"burned"`
+	MedianCycle Duration `json:"median_cycle_seconds"` // Of complete runs
+}
+
+// Merges counts merge attempts.
+type Merges struct {
+	Merged      int     `json:"merged"`
+	Failed      int     `json:"failed"`
+	SuccessRate float64 `json:"success_rate"` // Zero when there were no attempts
+}
+
+func (m *Merges) add(ok bool) {
+	if ok {
+		m.Merged++
+	} else {
+		m.Failed++
+	}
+	m.SuccessRate = float64(m.Merged) / float64(m.Merged+m.Failed)
+}
+
+// Rig is one rig's share of the report.
+type Rig struct {
+	Name        string   `json:"name"`
+	Closed      int      `json:"closed"`
+	MedianCycle Duration `json:"median_cycle_seconds"`
+	Runs        int      `json:"molecule_runs"`
+	Merges      Merges   `json:"merges"`
+}
+
+// Report is the analytics for a window.
+type Report struct {
+	Since       time.Time  `json:"since"`
+	Until       time.Time  `json:"until"`
+	Closed      int        `json:"closed"`
+	MedianCycle Duration   `json:"median_cycle_seconds"` // Bead created to closed
+	Weeks       []Week     `json:"weeks"`
+	Molecules   []Molecule `json:"molecules"`
+	Merges      Merges     `json:"merges"`
+	Rigs        []Rig      `json:"rigs"`
+}
+
+// Compute builds the report for o from in.
+func Compute(in Input, o Options) *Report {
+	rep := &Report{Since: o.Since, Until: o.Until, Weeks: []Week{}, Molecules: []Molecule{}, Rigs: []Rig{}}
+	rigs := map[string]*Rig{}
+	rigCycles := map[string][]time.Duration{}
+	rigFor := func(name string) *Rig {
+		if name == "" {
+			name = "town"
+		}
+		r, ok := rigs[name]
+		if !ok {
+			r = &Rig{Name: name}
+			rigs[name] = r
+		}
+		return r
+	}
+	inRig := func(rig string) bool {
+		switch o.Rig {
+		case "":
+			return true
+		case "town":
+			return rig == ""
+		}
+		return rig == o.Rig
+	}
+
+	for w := WeekStart(o.Since); w.Before(o.Until); w = w.AddDate(0, 0, 7) {
+		rep.Weeks = append(rep.Weeks, Week{Start: w})
+	}
+
+	var cycles []time.Duration
+	for _, b := range in.Beads {
+		if !inRig(b.Rig) || !o.within(b.Closed) {
+			continue
+		}
+		rep.Closed++
+		for i := range rep.Weeks {
+			if !b.Closed.Before(rep.Weeks[i].Start) && b.Closed.Before(rep.Weeks[i].Start.AddDate(0, 0, 7)) {
+				rep.Weeks[i].Closed++
+			}
+		}
+		r := rigFor(b.Rig)
+		r.Closed++
+		if !b.Created.IsZero() && b.Closed.After(b.Created) {
+			cycle := b.Closed.Sub(b.Created)
+			cycles = append(cycles, cycle)
+			rigCycles[r.Name] = append(rigCycles[r.Name], cycle)
+		}
+	}
+	rep.MedianCycle = median(cycles)
+	for name, c := range rigCycles {
+		rigs[name].MedianCycle = median(c)
+	}
+
+	molecules := map[string]*Molecule{}
+	runCycles := map[string][]time.Duration{}
+	for _, run := range in.Runs {
+		if !inRig(run.Rig) || !o.within(run.Started) {
+			continue
+		}
+		m, ok := molecules[run.Formula]
+		if !ok {
+			m = &Molecule{Formula: run.Formula}
+			molecules[run.Formula] = m
+		}
+		m.Runs++
+		switch run.Status {
+		case molruns.StatusComplete:
+			m.Complete++
+			runCycles[run.Formula] = append(runCycles[run.Formula], run.Duration())
+		case molruns.StatusFailed:
+			m.Failed++
+		case molruns.StatusBurned:
+			m.Burned++
+		}
+		rigFor(run.Rig).Runs++
+	}
+	for _, m := range molecules {
+		m.MedianCycle = median(runCycles[m.Formula])
+		rep.Molecules = append(rep.Molecules, *m)
+	}
+	sort.Slice(rep.Molecules, func(i, j int) bool {
+		if rep.Molecules[i].Runs != rep.Molecules[j].Runs {
+			return rep.Molecules[i].Runs > rep.Molecules[j].Runs
+		}
+		return rep.Molecules[i].Formula < rep.Molecules[j].Formula
+	})
+
+	for _, m := range in.Merges {
+		if !inRig(m.Rig) || !o.within(m.At) {
+			continue
+		}
+		rep.Merges.add(m.OK)
+		rigFor(m.Rig).Merges.add(m.OK)
+	}
+
+	for _, r := range rigs {
+		rep.Rigs = append(rep.Rigs, *r)
+	}
+	sort.Slice(rep.Rigs, func(i, j int) bool { return rep.Rigs[i].Name < rep.Rigs[j].Name })
+	return rep
+}
+
+func (o Options) within(t time.Time) bool {
+	return !t.Before(o.Since) && t.Before(o.Until)
+}
+
+// WeekStart returns midnight on the Monday of t's week, in t's location.
+func WeekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func median(ds []time.Duration) Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return Duration((sorted[mid-1] + sorted[mid]) / 2)
+	}
+	return Duration(sorted[mid])
+}
+
+// ClosedWork returns the closed beads in issues that are work items, not
+// bookkeeping. Steps of a molecule in issues are left out with it.
+func ClosedWork(rig string, issues []*beads.Issue) []Bead {
+	skip := map[string]bool{}
+	for _, i := range issues {
+		if beads.IsBookkeeping(i) {
+			skip[i.ID] = true
+		}
+	}
+	var out []Bead
+	for _, i := range issues {
+		if i.Status != "closed" || skip[i.ID] || (i.Parent != "" && skip[i.Parent]) {
+			continue
+		}
+		closed := parseTime(i.ClosedAt)
+		if closed.IsZero() {
+			closed = parseTime(i.UpdatedAt)
+		}
+		if closed.IsZero() {
+			continue
+		}
+		out = append(out, Bead{Rig: rig, ID: i.ID, Title: i.Title, Type: i.Type, Created: parseTime(i.CreatedAt), Closed: closed,
+			Points: beads.ParseEstimateFields(i).Points()})
+	}
+	return out
+}
+
+func parseTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// LoadMerges reads the refineries' merged and merge_failed events from
+// the town event log. A missing log has none.
+func LoadMerges(townRoot string) ([]Merge, error) {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var merges []Merge
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue // Skip malformed lines
+		}
+		if e.Type != events.TypeMerged && e.Type != events.TypeMergeFailed {
+			continue
+		}
+		rig, _, _ := strings.Cut(e.Actor, "/")
+		payload := func(key string) string {
+			v, _ := e.Payload[key].(string)
+			return v
+		}
+		merges = append(merges, Merge{
+			Rig:    rig,
+			At:     parseTime(e.Timestamp),
+			OK:     e.Type == events.TypeMerged,
+			MR:     payload("mr"),
+			Worker: payload("worker"),
+			Branch: payload("branch"),
+			Reason: payload("reason"),
+		})
+	}
+	return merges, scanner.Err()
+}
@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/molruns"
+)
+
+func TestCompute(t *testing.T) {
+	// Monday 2026-03-02 to Monday 2026-03-16: two weeks
+	day := func(d, h int) time.Time { return time.Date(2026, 3, d, h, 0, 0, 0, time.UTC) }
+	in := Input{
+		Beads: []Bead{
+			{Rig: "gastown", ID: "gt-1", Created: day(2, 0), Closed: day(2, 2)},
+			{Rig: "gastown", ID: "gt-2", Created: day(2, 0), Closed: day(3, 0)},
+			{Rig: "gastown", ID: "gt-3", Created: day(9, 0), Closed: day(9, 6)},
+			{Rig: "beads", ID: "bd-1", Created: day(1, 0), Closed: day(10, 0)},
+			{Rig: "beads", ID: "bd-old", Created: day(1, 0), Closed: day(1, 1)}, // Before the window
+		},
+		Runs: []*molruns.Run{
+			{Formula: "mol-polecat-work", Rig: "gastown", Status: molruns.StatusComplete, Started: day(2, 0), Finished: day(2, 1)},
+			{Formula: "mol-polecat-work", Rig: "gastown", Status: molruns.StatusComplete, Started: day(3, 0), Finished: day(3, 3)},
+			{Formula: "mol-polecat-work", Rig: "beads", Status: molruns.StatusFailed, Started: day(4, 0), Finished: day(4, 1)},
+			{Formula: "mol-review", Rig: "beads", Status: molruns.StatusBurned, Started: day(5, 0)},
+		},
+		Merges: []Merge{
+			{Rig: "gastown", At: day(2, 3), OK: true},
+			{Rig: "gastown", At: day(3, 3), OK: true},
+			{Rig: "gastown", At: day(4, 3), OK: false},
+			{Rig: "beads", At: day(10, 3), OK: true},
+		},
+	}
+	rep := Compute(in, Options{Since: day(2, 0), Until: day(16, 0)})
+
+	if rep.Closed != 4 || len(rep.Weeks) != 2 || rep.Weeks[0].Closed != 2 || rep.Weeks[1].Closed != 2 {
+		t.Errorf("closed = %d, weeks = %+v", rep.Closed, rep.Weeks)
+	}
+	// Cycles 2h, 6h, 24h, 216h
+	if got := time.Duration(rep.MedianCycle); got != 15*time.Hour {
+		t.Errorf("median cycle = %v, want 15h", got)
+	}
+	if len(rep.Molecules) != 2 {
+		t.Fatalf("molecules = %+v", rep.Molecules)
+	}
+	if m := rep.Molecules[0]; m.Formula != "mol-polecat-work" || m.Runs != 3 || m.Complete != 2 || m.Failed != 1 || time.Duration(m.MedianCycle) != 2*time.Hour {
+		t.Errorf("mol-polecat-work = %+v", m)
+	}
+	if rep.Merges.Merged != 3 || rep.Merges.Failed != 1 || rep.Merges.SuccessRate != 0.75 {
+		t.Errorf("merges = %+v", rep.Merges)
+	}
+	if len(rep.Rigs) != 2 || rep.Rigs[1].Name != "gastown" || rep.Rigs[1].Closed != 3 || rep.Rigs[1].Runs != 2 ||
+		time.Duration(rep.Rigs[1].MedianCycle) != 6*time.Hour {
+		t.Errorf("rigs = %+v", rep.Rigs)
+	}
+
+	one := Compute(in, Options{Since: day(2, 0), Until: day(16, 0), Rig: "beads"})
+	if one.Closed != 1 || one.Merges.Merged != 1 || len(one.Rigs) != 1 || one.Rigs[0].Name != "beads" {
+		t.Errorf("beads only = %+v", one)
+	}
+
+	data, err := json.Marshal(rep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]interface{}
+	_ = json.Unmarshal(data, &raw)
+	if raw["median_cycle_seconds"] != float64(15*3600) {
+		t.Errorf("median_cycle_seconds = %v", raw["median_cycle_seconds"])
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	sunday := time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC)
+	if got, want := WeekStart(sunday), time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("WeekStart(%v) = %v, want %v", sunday, got, want)
+	}
+}
+
+func TestClosedWork(t *testing.T) {
+	issues := []*beads.Issue{
+		{ID: "gt-1", Type: "task", Status: "closed", CreatedAt: "2026-03-02T00:00:00Z", ClosedAt: "2026-03-02T02:00:00Z"},
+		{ID: "gt-2", Type: "bug", Status: "open", CreatedAt: "2026-03-02T00:00:00Z"},
+		{ID: "gt-mr", Type: "task", Labels: []string{"gt:merge-request"}, Status: "closed", ClosedAt: "2026-03-02T03:00:00Z"},
+		{ID: "gt-mol", Type: "molecule", Status: "closed", ClosedAt: "2026-03-02T03:00:00Z"},
+		{ID: "gt-mol.1", Type: "task", Parent: "gt-mol", Status: "closed", ClosedAt: "2026-03-02T03:00:00Z"},
+	}
+	got := ClosedWork("gastown", issues)
+	if len(got) != 1 || got[0].ID != "gt-1" || got[0].Rig != "gastown" || got[0].Closed.Sub(got[0].Created) != 2*time.Hour {
+		t.Errorf("ClosedWork = %+v", got)
+	}
+}
+
+func TestLoadMerges(t *testing.T) {
+	townRoot := t.TempDir()
+	lines := []events.Event{
+		{Timestamp: "2026-03-02T03:00:00Z", Type: events.TypeMerged, Actor: "gastown/refinery"},
+		{Timestamp: "2026-03-02T04:00:00Z", Type: events.TypeMergeFailed, Actor: "gastown/refinery",
+			Payload: events.MergePayload("gt-mr1", "nux", "polecat/nux", "conflict")},
+		{Timestamp: "2026-03-02T05:00:00Z", Type: events.TypeSling, Actor: "mayor"},
+	}
+	f, err := os.Create(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range lines {
+		_ = json.NewEncoder(f).Encode(e)
+	}
+	_, _ = f.WriteString("not json\n")
+	_ = f.Close()
+
+	merges, err := LoadMerges(townRoot)
+	if err != nil || len(merges) != 2 || !merges[0].OK || merges[1].OK || merges[1].Rig != "gastown" {
+		t.Fatalf("LoadMerges = %+v, %v", merges, err)
+	}
+	if m := merges[1]; m.MR != "gt-mr1" || m.Worker != "nux" || m.Branch != "polecat/nux" || m.Reason != "conflict" {
+		t.Errorf("merges[1] = %+v", m)
+	}
+}
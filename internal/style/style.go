@@ -47,7 +47,7 @@ var (
 	ErrorPrefix = Error.Render(ui.IconFail)
 
 	// ArrowPrefix for action indicators
-	ArrowPrefix = Info.Render("→")
+	ArrowPrefix = Info.Render(ui.Glyph("→", "->"))
 )
 
 // PrintWarning prints a warning message with consistent formatting.
@@ -0,0 +1,144 @@
+// Package summary defines the state summary an agent writes about its
+// task: what is done, what remains, and the gotchas the next session must
+// not trip over. Summaries are stored as comments on the bead being
+// worked (gt summarize), so they outlive the session that wrote them and
+// travel with the bead through handoff, resume and context recycling; the
+// newest one is shown when a session primes on the bead.
+package summary
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Header opens every summary comment, marking it among a bead's comments.
+const Header = "## 📝 State summary"
+
+// Summary is an agent's account of where its task stands.
+type Summary struct {
+	Bead      string    `json:"bead"`
+	Agent     string    `json:"agent,omitempty"`
+	Written   time.Time `json:"written"`
+	Done      []string  `json:"done"`
+	Remaining []string  `json:"remaining"`
+	Gotchas   []string  `json:"gotchas,omitempty"`
+}
+
+// Section names, as written in a summary.
+const (
+	sectionDone      = "Done"
+	sectionRemaining = "Remaining"
+	sectionGotchas   = "Gotchas"
+)
+
+// Empty reports whether the summary says nothing.
+func (s *Summary) Empty() bool {
+	return len(s.Done) == 0 && len(s.Remaining) == 0 && len(s.Gotchas) == 0
+}
+
+// Format renders the summary as markdown, in the form Parse reads back.
+func Format(s *Summary) string {
+	var sb strings.Builder
+	sb.WriteString(Header + "\n")
+	byline := "_" + s.Written.UTC().Format(time.RFC3339)
+	if s.Agent != "" {
+		byline += " by " + s.Agent
+	}
+	sb.WriteString(byline + "_\n")
+	for _, sec := range []struct {
+		name  string
+		items []string
+	}{{sectionDone, s.Done}, {sectionRemaining, s.Remaining}, {sectionGotchas, s.Gotchas}} {
+		if len(sec.items) == 0 && sec.name == sectionGotchas {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n### %s\n", sec.name)
+		if len(sec.items) == 0 {
+			sb.WriteString("- (nothing)\n")
+		}
+		for _, item := range sec.items {
+			sb.WriteString("- " + item + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// Parse reads a summary written by Format. Returns nil if text is not one.
+func Parse(text string) *Summary {
+	text = strings.TrimSpace(text)
+	rest, ok := strings.CutPrefix(text, Header)
+	if !ok {
+		return nil
+	}
+	s := ParseSections(rest)
+	for _, line := range strings.Split(rest, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "_") || !strings.HasSuffix(line, "_") {
+			continue
+		}
+		when, agent, _ := strings.Cut(strings.Trim(line, "_"), " by ")
+		if t, err := time.Parse(time.RFC3339, when); err == nil {
+			s.Written, s.Agent = t, agent
+		}
+		break
+	}
+	return s
+}
+
+// ParseSections reads the Done, Remaining and Gotchas sections of
+// markdown an agent wrote: a heading naming each section (any level, any
+// case) followed by its items as a list. Lines outside a section are
+// ignored.
+func ParseSections(text string) *Summary {
+	s := &Summary{}
+	var into *[]string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if heading, ok := strings.CutPrefix(line, "#"); ok {
+			switch strings.ToLower(strings.Trim(heading, "# :")) {
+			case "done":
+				into = &s.Done
+			case "remaining", "todo", "next":
+				into = &s.Remaining
+			case "gotchas", "gotcha", "warnings":
+				into = &s.Gotchas
+			default:
+				into = nil
+			}
+			continue
+		}
+		item := strings.TrimSpace(strings.TrimLeft(line, "-*"))
+		if into == nil || item == "" || item == "(nothing)" || item == line {
+			continue
+		}
+		*into = append(*into, item)
+	}
+	return s
+}
+
+// Latest returns the newest summary among comment texts, oldest first as
+// bd lists them, or nil if none is a summary.
+func Latest(comments []string) *Summary {
+	for i := len(comments) - 1; i >= 0; i-- {
+		if s := Parse(comments[i]); s != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// Prompt asks an agent to write a summary of its work on bead and save it.
+func Prompt(bead string) string {
+	target := ""
+	if bead != "" {
+		target = " " + bead
+	}
+	return fmt.Sprintf(`Write a state summary of your current task so a fresh session can pick it up cold. Keep each item to one line.
+- Done: what is finished (commits, decisions made, things verified)
+- Remaining: what is left, in the order you would do it, including anything half-done
+- Gotchas: traps, dead ends already tried, and context that isn't in the code
+Save it with:
+  gt summarize%s --done "..." --remaining "..." --gotcha "..."
+(repeat a flag for each item)`, target)
+}
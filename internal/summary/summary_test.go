@@ -0,0 +1,73 @@
+package summary
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatParse(t *testing.T) {
+	s := &Summary{
+		Agent:     "gastown/crew/max",
+		Written:   time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+		Done:      []string{"Token refresh added", "Retry path has a mutex"},
+		Remaining: []string{"Tests for expiry"},
+		Gotchas:   []string{"The mock clock ignores time zones"},
+	}
+	text := Format(s)
+	if !strings.HasPrefix(text, Header) || !strings.Contains(text, "### Gotchas\n- The mock clock") {
+		t.Errorf("Format =\n%s", text)
+	}
+	got := Parse(text)
+	if got == nil {
+		t.Fatalf("Parse(Format(s)) = nil")
+	}
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("round trip = %+v, want %+v", got, s)
+	}
+
+	if Parse("Merged in abc123") != nil {
+		t.Error("Parse accepted a comment that isn't a summary")
+	}
+	if empty := Parse(Format(&Summary{Written: s.Written})); empty == nil || !empty.Empty() {
+		t.Errorf("empty summary round trip = %+v", empty)
+	}
+}
+
+func TestParseSections(t *testing.T) {
+	s := ParseSections(`Some preamble the agent wrote.
+
+## Done:
+- Wired the flag
+* Docs updated
+
+## Next
+1. Not a list item the parser knows
+- Ship it
+
+# GOTCHAS
+- CI is flaky on macOS
+`)
+	if strings.Join(s.Done, "|") != "Wired the flag|Docs updated" {
+		t.Errorf("Done = %q", s.Done)
+	}
+	if strings.Join(s.Remaining, "|") != "Ship it" {
+		t.Errorf("Remaining = %q", s.Remaining)
+	}
+	if strings.Join(s.Gotchas, "|") != "CI is flaky on macOS" {
+		t.Errorf("Gotchas = %q", s.Gotchas)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	older := Format(&Summary{Written: time.Unix(1, 0), Done: []string{"first"}})
+	newer := Format(&Summary{Written: time.Unix(2, 0), Done: []string{"second"}})
+	got := Latest([]string{older, newer, "LGTM"})
+	if got == nil || got.Done[0] != "second" {
+		t.Errorf("Latest = %+v, want the second summary", got)
+	}
+	if Latest([]string{"LGTM"}) != nil {
+		t.Error("Latest found a summary among plain comments")
+	}
+}
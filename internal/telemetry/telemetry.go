@@ -0,0 +1,158 @@
+// Package telemetry provides OpenTelemetry tracing for gt.
+//
+// Every gt invocation is a root span ("gt sling", "gt mol step done"), and
+// bd and git calls made while it runs are child spans. Tracing is exported
+// over OTLP/HTTP only when OTEL_EXPORTER_OTLP_ENDPOINT (or the traces-only
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) is set; otherwise spans are no-ops.
+//
+// Processes join their parent's trace through the W3C TRACEPARENT
+// environment variable. gt sets it in the sessions it spawns, so the gt
+// commands an agent runs (molecule steps, gt done) land in the same trace
+// as the sling that started the agent.
+//
+// The bd and git wrappers take no context, so the active span is tracked
+// per process: Start makes a span the parent of later spans until it ends,
+// while Leaf spans never become parents and are safe from any goroutine.
+package telemetry
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EnvTraceparent carries the W3C trace context between processes.
+const EnvTraceparent = "TRACEPARENT"
+
+// Exporter endpoint variables; either one enables export.
+const (
+	EnvEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+)
+
+const tracerName = "github.com/steveyegge/gastown"
+
+var (
+	mu      sync.Mutex
+	current = context.Background()
+)
+
+// Enabled reports whether an exporter endpoint is configured.
+func Enabled() bool {
+	return os.Getenv(EnvEndpoint) != "" || os.Getenv(EnvTracesEndpoint) != ""
+}
+
+// Init joins the trace named by TRACEPARENT, if any, and installs an OTLP
+// exporter when one is configured. The returned shutdown flushes pending
+// spans and must be called before the process exits.
+func Init(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	setCurrent(parentFromEnv())
+
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		res = resource.Default()
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// parentFromEnv returns a context carrying the remote span from TRACEPARENT.
+func parentFromEnv() context.Context {
+	tp := os.Getenv(EnvTraceparent)
+	if tp == "" {
+		return context.Background()
+	}
+	carrier := propagation.MapCarrier{"traceparent": tp}
+	return propagation.TraceContext{}.Extract(context.Background(), carrier)
+}
+
+// Span is an in-flight span.
+type Span struct {
+	span trace.Span
+	prev context.Context // restored on End; nil for leaf spans
+}
+
+// Start begins a span that is the parent of spans started until it ends.
+// Use it for the phases of a command, from the command's main goroutine.
+func Start(name string, attrs ...attribute.KeyValue) *Span {
+	return StartWith(name, trace.WithAttributes(attrs...))
+}
+
+// StartWith is Start with explicit span options, e.g. trace.WithTimestamp
+// for work that began before this process.
+func StartWith(name string, opts ...trace.SpanStartOption) *Span {
+	mu.Lock()
+	defer mu.Unlock()
+	ctx, span := otel.Tracer(tracerName).Start(current, name, opts...)
+	s := &Span{span: span, prev: current}
+	current = ctx
+	return s
+}
+
+// Leaf begins a span that never becomes a parent, such as a single bd or
+// git invocation.
+func Leaf(name string, attrs ...attribute.KeyValue) *Span {
+	_, span := otel.Tracer(tracerName).Start(Context(), name, trace.WithAttributes(attrs...))
+	return &Span{span: span}
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...attribute.KeyValue) {
+	s.span.SetAttributes(attrs...)
+}
+
+// End finishes the span, marking it failed if err is non-nil.
+func (s *Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+	if s.prev != nil {
+		setCurrent(s.prev)
+	}
+}
+
+// Context returns the context of the active span.
+func Context() context.Context {
+	mu.Lock()
+	defer mu.Unlock()
+	return current
+}
+
+// Traceparent returns the TRACEPARENT value for the active span, or "" if
+// there is no trace to join.
+func Traceparent() string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(Context(), carrier)
+	return carrier["traceparent"]
+}
+
+func setCurrent(ctx context.Context) {
+	mu.Lock()
+	current = ctx
+	mu.Unlock()
+}
@@ -0,0 +1,97 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const parentTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func withRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		setCurrent(context.Background())
+	})
+	return rec
+}
+
+func TestInitJoinsTraceparent(t *testing.T) {
+	t.Setenv(EnvTraceparent, parentTraceparent)
+	t.Setenv(EnvEndpoint, "")
+	t.Setenv(EnvTracesEndpoint, "")
+	t.Cleanup(func() { setCurrent(context.Background()) })
+
+	shutdown, err := Init(context.Background(), "gt-test")
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = shutdown(context.Background()) }()
+
+	if got := Traceparent(); got != parentTraceparent {
+		t.Errorf("Traceparent() = %q, want %q", got, parentTraceparent)
+	}
+}
+
+func TestTraceparentEmptyWithoutTrace(t *testing.T) {
+	setCurrent(context.Background())
+	if got := Traceparent(); got != "" {
+		t.Errorf("Traceparent() = %q, want empty", got)
+	}
+}
+
+func TestSpanNesting(t *testing.T) {
+	rec := withRecorder(t)
+
+	root := Start("gt sling")
+	Leaf("bd show").End(nil)
+	spawn := Start("polecat.spawn")
+	tp := Traceparent()
+	Leaf("git worktree").End(errors.New("boom"))
+	spawn.End(nil)
+	Leaf("bd update").End(nil)
+	root.End(nil)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range rec.Ended() {
+		spans[s.Name()] = s
+	}
+	if len(spans) != 5 {
+		t.Fatalf("got %d spans, want 5", len(spans))
+	}
+
+	rootID := spans["gt sling"].SpanContext().SpanID()
+	spawnID := spans["polecat.spawn"].SpanContext().SpanID()
+	for name, parent := range map[string]string{
+		"bd show":       "gt sling",
+		"polecat.spawn": "gt sling",
+		"git worktree":  "polecat.spawn",
+		"bd update":     "gt sling",
+	} {
+		want := rootID
+		if parent == "polecat.spawn" {
+			want = spawnID
+		}
+		if got := spans[name].Parent().SpanID(); got != want {
+			t.Errorf("%s parent = %s, want %s", name, got, parent)
+		}
+	}
+
+	if !strings.Contains(tp, spawnID.String()) {
+		t.Errorf("Traceparent() during spawn = %q, want span %s", tp, spawnID)
+	}
+	if got := spans["git worktree"].Status().Code; got != codes.Error {
+		t.Errorf("failed span status = %v, want Error", got)
+	}
+}
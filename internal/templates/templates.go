@@ -5,9 +5,12 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"text/template"
+
+	"github.com/steveyegge/gastown/internal/dryrun"
 )
 
 //go:embed roles/*.md.tmpl messages/*.md.tmpl
@@ -159,7 +162,16 @@ func CreateMayorCLAUDEmd(mayorDir, townRoot, townName, mayorSession, deaconSessi
 	}
 
 	claudePath := filepath.Join(mayorDir, "CLAUDE.md")
-	return os.WriteFile(claudePath, []byte(content), 0644)
+	return dryrun.WriteFile(claudePath, []byte(content), 0644)
+}
+
+// RolesFS returns the embedded role templates, named <role>.md.tmpl.
+func RolesFS() fs.FS {
+	sub, err := fs.Sub(templateFS, "roles")
+	if err != nil {
+		panic(err) // The directory is embedded; fs.Sub only fails on bad names
+	}
+	return sub
 }
 
 // GetAllRoleTemplates returns all role templates as a map of filename to content.
@@ -196,7 +208,7 @@ func ProvisionCommands(workspacePath string) error {
 
 	// Create .claude/commands/ directory
 	commandsDir := filepath.Join(workspacePath, ".claude", "commands")
-	if err := os.MkdirAll(commandsDir, 0755); err != nil {
+	if err := dryrun.MkdirAll(commandsDir, 0755); err != nil {
 		return fmt.Errorf("creating commands directory: %w", err)
 	}
 
@@ -217,7 +229,7 @@ func ProvisionCommands(workspacePath string) error {
 			return fmt.Errorf("reading %s: %w", entry.Name(), err)
 		}
 
-		if err := os.WriteFile(destPath, content, 0644); err != nil { //nolint:gosec // G306: template files are non-sensitive
+		if err := dryrun.WriteFile(destPath, content, 0644); err != nil { //nolint:gosec // G306: template files are non-sensitive
 			return fmt.Errorf("writing %s: %w", entry.Name(), err)
 		}
 	}
@@ -0,0 +1,316 @@
+// Package timeline reconstructs what happened across the town in a window
+// of time from the event log: spawns, molecule steps, merges, errors,
+// escalations and kills, in order and with how long things took. It is
+// for looking back at a run that ended badly; it records what happened,
+// not who is to blame.
+package timeline
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/townlog"
+)
+
+// Kinds group event types across subsystems.
+const (
+	KindSpawn      = "spawn"
+	KindWork       = "work"
+	KindStep       = "step"
+	KindMerge      = "merge"
+	KindEscalation = "escalation"
+	KindKill       = "kill"
+	KindError      = "error"
+	KindOther      = "other"
+)
+
+// TypeCrash is the type of crashes read from the town log, which the
+// event log doesn't carry.
+const TypeCrash = string(townlog.EventCrash)
+
+// kinds maps the event types a timeline shows to their kind. Types not
+// listed (mail, nudges, patrol chatter) are KindOther.
+var kinds = map[string]string{
+	events.TypeSpawn:            KindSpawn,
+	events.TypeBoot:             KindSpawn,
+	events.TypeSling:            KindWork,
+	events.TypeHook:             KindWork,
+	events.TypeUnhook:           KindWork,
+	events.TypeHandoff:          KindWork,
+	events.TypeDone:             KindWork,
+	events.TypeContextRecycle:   KindWork,
+	events.TypeStepComplete:     KindStep,
+	events.TypeMoleculeComplete: KindStep,
+	events.TypeStepSlow:         KindStep,
+	events.TypeGateAwaiting:     KindStep,
+	events.TypeGateApproved:     KindStep,
+	events.TypeGateRejected:     KindStep,
+	events.TypeMergeStarted:     KindMerge,
+	events.TypeMerged:           KindMerge,
+	events.TypeMergeFailed:      KindMerge,
+	events.TypeMergeSkipped:     KindMerge,
+	events.TypeEscalationSent:   KindEscalation,
+	events.TypeEscalationAcked:  KindEscalation,
+	events.TypeEscalationClosed: KindEscalation,
+	events.TypeKill:             KindKill,
+	events.TypeHalt:             KindKill,
+	events.TypeSessionDeath:     KindKill,
+	events.TypeMassDeath:        KindError,
+	events.TypeBudgetExceeded:   KindError,
+	TypeCrash:                   KindError,
+}
+
+// Entry is one event on the timeline.
+type Entry struct {
+	At       time.Time              `json:"at"`
+	Kind     string                 `json:"kind"`
+	Type     string                 `json:"type"`
+	Actor    string                 `json:"actor"`
+	Summary  string                 `json:"summary"`
+	Duration time.Duration          `json:"duration_ns,omitempty"` // Since the matching start (spawn, merge start, ...)
+	Since    string                 `json:"since,omitempty"`       // What Duration is measured from
+	Problem  bool                   `json:"problem,omitempty"`
+	Payload  map[string]interface{} `json:"payload,omitempty"`
+}
+
+// Timeline is the reconstructed window.
+type Timeline struct {
+	Since   time.Time      `json:"since"`
+	Until   time.Time      `json:"until"`
+	Entries []Entry        `json:"entries"`
+	Counts  map[string]int `json:"counts"` // Entries by kind
+}
+
+// Problems returns the entries that went wrong, in order.
+func (t *Timeline) Problems() []Entry {
+	var out []Entry
+	for _, e := range t.Entries {
+		if e.Problem {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// FromTownLog converts the town log's crashes to events, for merging into
+// the event log. Its other entries duplicate the event log.
+func FromTownLog(logged []townlog.Event) []*events.Event {
+	var out []*events.Event
+	for _, l := range logged {
+		if l.Type != townlog.EventCrash {
+			continue
+		}
+		out = append(out, &events.Event{
+			Timestamp: l.Timestamp.UTC().Format(time.RFC3339),
+			Source:    "townlog",
+			Type:      TypeCrash,
+			Actor:     l.Agent,
+			Payload:   map[string]interface{}{"context": l.Context},
+		})
+	}
+	return out
+}
+
+// Build orders the events between since and until into a timeline. With
+// all, event types of KindOther are kept too.
+func Build(evs []*events.Event, since, until time.Time, all bool) *Timeline {
+	t := &Timeline{Since: since, Until: until, Entries: []Entry{}, Counts: map[string]int{}}
+	for _, e := range evs {
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || at.Before(since) || !at.Before(until) {
+			continue
+		}
+		kind, ok := kinds[e.Type]
+		if !ok {
+			if !all {
+				continue
+			}
+			kind = KindOther
+		}
+		t.Entries = append(t.Entries, Entry{
+			At:      at,
+			Kind:    kind,
+			Type:    e.Type,
+			Actor:   e.Actor,
+			Summary: summarize(e),
+			Problem: isProblem(e),
+			Payload: e.Payload,
+		})
+	}
+	sort.SliceStable(t.Entries, func(i, j int) bool { return t.Entries[i].At.Before(t.Entries[j].At) })
+
+	t.pairDurations()
+	for _, e := range t.Entries {
+		t.Counts[e.Kind]++
+	}
+	return t
+}
+
+// pairDurations measures each ending event from the event that started it:
+// a session's end from its spawn, a merge from its start, a bead's done
+// from its sling, a step from the molecule's previous step, and an
+// escalation's ack or close from when it was sent.
+func (t *Timeline) pairDurations() {
+	started := map[string]time.Time{}
+	measure := func(e *Entry, key, since string) {
+		if at, ok := started[key]; ok {
+			e.Duration, e.Since = e.At.Sub(at), since
+		}
+	}
+	for i := range t.Entries {
+		e := &t.Entries[i]
+		str := func(key string) string {
+			v, _ := e.Payload[key].(string)
+			return v
+		}
+		switch e.Type {
+		case events.TypeSpawn:
+			started["session:"+agentKey(str("rig"), str("polecat"))] = e.At
+		case events.TypeSling, events.TypeHook:
+			if _, ok := started["bead:"+str("bead")]; !ok {
+				started["bead:"+str("bead")] = e.At
+			}
+		case events.TypeDone:
+			measure(e, "bead:"+str("bead"), "sling")
+			if e.Since == "" {
+				measure(e, "session:"+agentKey("", e.Actor), "spawn")
+			}
+		case events.TypeKill:
+			measure(e, "session:"+agentKey(str("rig"), str("target")), "spawn")
+		case events.TypeSessionDeath:
+			measure(e, "session:"+agentKey("", str("agent")), "spawn")
+		case TypeCrash:
+			measure(e, "session:"+agentKey("", e.Actor), "spawn")
+		case events.TypeMergeStarted:
+			started["merge:"+str("mr")] = e.At
+		case events.TypeMerged, events.TypeMergeFailed, events.TypeMergeSkipped:
+			measure(e, "merge:"+str("mr"), "merge start")
+		case events.TypeStepComplete:
+			key := "molecule:" + str("molecule")
+			measure(e, key, "previous step")
+			started[key] = e.At
+		case events.TypeMoleculeComplete:
+			measure(e, "molecule:"+str("molecule"), "last step")
+		case events.TypeEscalationSent:
+			id := str("escalation_id")
+			if id == "" {
+				id = str("rig") // gt escalate records the escalation's ID here
+			}
+			if _, ok := started["escalation:"+id]; !ok {
+				started["escalation:"+id] = e.At
+			}
+		case events.TypeEscalationAcked, events.TypeEscalationClosed:
+			measure(e, "escalation:"+str("escalation_id"), "escalation")
+		}
+	}
+}
+
+// agentKey identifies a polecat session from a rig and name, or from an
+// agent address ("gastown/polecats/Toast" or "gastown/Toast").
+func agentKey(rig, agent string) string {
+	agent = strings.TrimSuffix(agent, "/")
+	if rig == "" {
+		parts := strings.Split(agent, "/")
+		if len(parts) < 2 {
+			return strings.ToLower(agent)
+		}
+		rig = parts[0]
+	}
+	return strings.ToLower(rig + "/" + path.Base(agent))
+}
+
+// isProblem reports whether an event is something going wrong.
+func isProblem(e *events.Event) bool {
+	switch e.Type {
+	case events.TypeMergeFailed, events.TypeEscalationSent, events.TypeKill,
+		events.TypeMassDeath, events.TypeBudgetExceeded, events.TypeStepSlow,
+		events.TypeGateRejected, TypeCrash:
+		return true
+	case events.TypeSessionDeath:
+		caller, _ := e.Payload["caller"].(string)
+		return caller != "gt down" // A deliberate shutdown isn't a problem
+	}
+	return false
+}
+
+// summarize describes an event in a few words.
+func summarize(e *events.Event) string {
+	str := func(key string) string {
+		return fmt.Sprint(valueOr(e.Payload[key], ""))
+	}
+	with := func(s, key string) string {
+		if v := str(key); v != "" {
+			return fmt.Sprintf("%s (%s)", s, v)
+		}
+		return s
+	}
+	switch e.Type {
+	case events.TypeSpawn:
+		return fmt.Sprintf("spawned %s/%s", str("rig"), str("polecat"))
+	case events.TypeBoot:
+		return fmt.Sprintf("booted %s", str("rig"))
+	case events.TypeSling:
+		return fmt.Sprintf("slung %s to %s", str("bead"), str("target"))
+	case events.TypeHook:
+		return fmt.Sprintf("hooked %s", str("bead"))
+	case events.TypeUnhook:
+		return fmt.Sprintf("unhooked %s", str("bead"))
+	case events.TypeHandoff:
+		return with("handed off", "subject")
+	case events.TypeDone:
+		return fmt.Sprintf("finished %s on %s", str("bead"), str("branch"))
+	case events.TypeStepComplete:
+		return fmt.Sprintf("completed step %s: %s", str("step"), str("title"))
+	case events.TypeMoleculeComplete:
+		return fmt.Sprintf("completed molecule %s", str("molecule"))
+	case events.TypeStepSlow:
+		return with(fmt.Sprintf("step %s running long", str("step")), "title")
+	case events.TypeMergeStarted:
+		return fmt.Sprintf("started merging %s (%s)", str("branch"), str("mr"))
+	case events.TypeMerged:
+		return fmt.Sprintf("merged %s (%s)", str("branch"), str("mr"))
+	case events.TypeMergeFailed:
+		return with(fmt.Sprintf("merge of %s failed", str("branch")), "reason")
+	case events.TypeMergeSkipped:
+		return with(fmt.Sprintf("skipped %s", str("branch")), "reason")
+	case events.TypeEscalationSent:
+		if str("reescalated") == "true" {
+			return fmt.Sprintf("re-escalated %s to %s", str("escalation_id"), str("new_severity"))
+		}
+		return with(fmt.Sprintf("escalated %s [%s]", str("rig"), str("severity")), "reason")
+	case events.TypeEscalationAcked:
+		return fmt.Sprintf("acknowledged %s", str("escalation_id"))
+	case events.TypeEscalationClosed:
+		return with(fmt.Sprintf("closed %s", str("escalation_id")), "reason")
+	case events.TypeKill:
+		return with(fmt.Sprintf("killed %s", str("target")), "reason")
+	case events.TypeSessionDeath:
+		return with(fmt.Sprintf("session %s ended", valueOr(e.Payload["agent"], str("session"))), "reason")
+	case events.TypeMassDeath:
+		return fmt.Sprintf("%s sessions died within %s", str("count"), str("window"))
+	case TypeCrash:
+		return with("crashed", "context")
+	}
+
+	keys := make([]string, 0, len(e.Payload))
+	for k := range e.Payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]string, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, fmt.Sprintf("%s=%v", k, e.Payload[k]))
+	}
+	return strings.Join(fields, " ")
+}
+
+func valueOr(v interface{}, fallback string) interface{} {
+	if v == nil || v == "" {
+		return fallback
+	}
+	return v
+}
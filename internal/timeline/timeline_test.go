@@ -0,0 +1,74 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/townlog"
+)
+
+func TestBuild(t *testing.T) {
+	start := time.Date(2026, 3, 1, 1, 0, 0, 0, time.UTC)
+	at := func(min int) string { return start.Add(time.Duration(min) * time.Minute).Format(time.RFC3339) }
+	evs := []*events.Event{
+		// Out of order, as merged from two logs
+		{Timestamp: at(95), Type: events.TypeMergeFailed, Actor: "gastown/refinery", Payload: events.MergePayload("gt-mr1", "toast", "polecat/toast/gt-abc", "conflict")},
+		{Timestamp: at(0), Type: events.TypeSpawn, Actor: "gt", Payload: events.SpawnPayload("gastown", "Toast")},
+		{Timestamp: at(1), Type: events.TypeSling, Actor: "mayor", Payload: events.SlingPayload("gt-abc", "gastown/polecats/Toast")},
+		{Timestamp: at(2), Type: events.TypePolecatChecked, Actor: "gastown/witness", Payload: events.PolecatCheckPayload("gastown", "Toast", "working", "")},
+		{Timestamp: at(30), Type: events.TypeStepComplete, Actor: "gastown/polecats/Toast", Payload: events.StepPayload("gt-mol", "gt-mol.1", "Design")},
+		{Timestamp: at(75), Type: events.TypeStepComplete, Actor: "gastown/polecats/Toast", Payload: events.StepPayload("gt-mol", "gt-mol.2", "Implement")},
+		{Timestamp: at(80), Type: events.TypeDone, Actor: "gastown/polecats/Toast", Payload: events.DonePayload("gt-abc", "polecat/toast/gt-abc")},
+		{Timestamp: at(90), Type: events.TypeMergeStarted, Actor: "gastown/refinery", Payload: events.MergePayload("gt-mr1", "toast", "polecat/toast/gt-abc", "")},
+		{Timestamp: at(100), Type: events.TypeSessionDeath, Actor: "gastown/polecats/Toast", Payload: events.SessionDeathPayload("gt-gastown-Toast", "gastown/polecats/Toast", "zombie cleanup", "daemon")},
+		{Timestamp: at(600), Type: events.TypeSpawn, Actor: "gt", Payload: events.SpawnPayload("gastown", "Nux")}, // After the window
+	}
+	evs = append(evs, FromTownLog([]townlog.Event{
+		{Timestamp: start.Add(50 * time.Minute), Type: townlog.EventCrash, Agent: "gastown/Nux", Context: "exit 137"},
+		{Timestamp: start.Add(51 * time.Minute), Type: townlog.EventSpawn, Agent: "gastown/Nux"},
+	})...)
+
+	tl := Build(evs, start, start.Add(8*time.Hour-time.Minute), false)
+	if len(tl.Entries) != 9 {
+		for _, e := range tl.Entries {
+			t.Logf("%s %s %s", e.At.Format("15:04"), e.Type, e.Summary)
+		}
+		t.Fatalf("got %d entries, want 9 (patrol chatter and the town log's spawn left out)", len(tl.Entries))
+	}
+	for i := 1; i < len(tl.Entries); i++ {
+		if tl.Entries[i].At.Before(tl.Entries[i-1].At) {
+			t.Fatalf("entries out of order at %d", i)
+		}
+	}
+
+	durations := map[string]time.Duration{}
+	for _, e := range tl.Entries {
+		if e.Since != "" {
+			durations[e.Type+" "+e.Since] += e.Duration
+		}
+	}
+	want := map[string]time.Duration{
+		events.TypeDone + " sling":                 79 * time.Minute,
+		events.TypeStepComplete + " previous step": 45 * time.Minute,
+		events.TypeMergeFailed + " merge start":    5 * time.Minute,
+		events.TypeSessionDeath + " spawn":         100 * time.Minute,
+	}
+	for k, d := range want {
+		if durations[k] != d {
+			t.Errorf("%s = %s, want %s", k, durations[k], d)
+		}
+	}
+
+	problems := tl.Problems()
+	if len(problems) != 3 || problems[0].Type != TypeCrash || problems[1].Summary != "merge of polecat/toast/gt-abc failed (conflict)" {
+		t.Errorf("problems = %+v", problems)
+	}
+	if tl.Counts[KindMerge] != 2 || tl.Counts[KindStep] != 2 {
+		t.Errorf("counts = %v", tl.Counts)
+	}
+
+	if all := Build(evs, start, start.Add(time.Hour), true); all.Counts[KindOther] != 1 {
+		t.Errorf("with all, counts = %v; want the patrol check kept", all.Counts)
+	}
+}
@@ -0,0 +1,287 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/procsession"
+)
+
+// BackendEnv selects the session backend: "tmux", or "process" to run
+// sessions as background processes (see package procsession). Without it,
+// Windows hosts that don't have tmux use processes and everything else
+// uses tmux.
+const BackendEnv = "GT_SESSION_BACKEND"
+
+// useProcessBackend reports whether sessions should run as processes.
+func useProcessBackend() bool {
+	switch os.Getenv(BackendEnv) {
+	case "process":
+		return true
+	case "tmux":
+		return false
+	}
+	if runtime.GOOS != "windows" {
+		return false
+	}
+	_, err := exec.LookPath("tmux")
+	return err != nil
+}
+
+// IsProcessBackend reports whether t runs sessions as background processes
+// rather than in tmux.
+func (t *Tmux) IsProcessBackend() bool {
+	return t.procs != nil
+}
+
+// runProcess carries out a tmux command against the process backend, for
+// the subset of tmux that gt uses. Display-only commands (status bars, key
+// bindings, messages) succeed without doing anything.
+func (t *Tmux) runProcess(args []string) (string, error) {
+	verb, flags, rest := parseTmuxArgs(args)
+	target := sessionTarget(flags["t"])
+
+	var out string
+	var err error
+	switch verb {
+	case "new-session":
+		command := ""
+		if len(rest) > 0 {
+			command = rest[len(rest)-1]
+		}
+		err = t.procs.Start(flags["s"], flags["c"], command)
+
+	case "has-session":
+		_, err = t.procs.Get(target)
+
+	case "kill-session":
+		err = t.procs.Kill(target)
+
+	case "kill-server":
+		var sessions []*procsession.Session
+		if sessions, err = t.procs.List(); err == nil {
+			for _, s := range sessions {
+				_ = t.procs.Kill(s.Name)
+			}
+		}
+
+	case "list-sessions":
+		out, err = t.processListSessions(flags["F"], flags["f"])
+
+	case "list-panes":
+		var s *procsession.Session
+		if s, err = t.procs.Get(target); err == nil {
+			out = expandFormat(flags["F"], s, t.procs)
+		}
+
+	case "send-keys":
+		err = t.processSendKeys(target, flags, rest)
+
+	case "capture-pane":
+		lines := 0
+		if n, convErr := strconv.Atoi(strings.TrimPrefix(flags["S"], "-")); convErr == nil {
+			lines = n
+		}
+		out, err = t.procs.Capture(target, lines)
+
+	case "set-environment":
+		if len(rest) != 2 {
+			return "", fmt.Errorf("tmux %s: want a name and value", verb)
+		}
+		err = t.procs.SetEnv(target, rest[0], rest[1])
+
+	case "show-environment":
+		out, err = t.processShowEnvironment(target, rest)
+
+	case "rename-session":
+		if len(rest) != 1 {
+			return "", fmt.Errorf("tmux %s: want a new name", verb)
+		}
+		err = t.procs.Rename(target, rest[0])
+
+	case "respawn-pane":
+		command := ""
+		if len(rest) > 0 {
+			command = rest[len(rest)-1]
+		}
+		err = t.procs.Respawn(target, command)
+
+	case "clear-history":
+		err = t.procs.ClearLog(target)
+
+	case "attach-session":
+		err = t.procs.Attach(target, os.Stdin, os.Stdout)
+
+	case "set-option", "show-options", "bind-key", "set-hook", "display-message",
+		"display-popup", "pipe-pane", "select-window":
+		// No status bar, key table or windows to act on
+
+	default:
+		return "", fmt.Errorf("tmux %s is not supported by the process session backend", verb)
+	}
+
+	switch {
+	case errors.Is(err, procsession.ErrSessionNotFound):
+		return "", ErrSessionNotFound
+	case errors.Is(err, procsession.ErrSessionExists):
+		return "", ErrSessionExists
+	case err != nil:
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// tmuxFlagsWithValue are the flags gt passes to tmux that take a value.
+var tmuxFlagsWithValue = map[string]bool{"t": true, "s": true, "c": true, "F": true, "f": true, "S": true, "T": true}
+
+// parseTmuxArgs splits a tmux command into its verb, flags and the
+// remaining arguments. Flags without a value map to "".
+func parseTmuxArgs(args []string) (string, map[string]string, []string) {
+	flags := make(map[string]string)
+	var rest []string
+	for i := 1; i < len(args); i++ {
+		a := args[i]
+		if len(a) < 2 || a[0] != '-' || len(rest) > 0 {
+			rest = append(rest, a)
+			continue
+		}
+		name := a[1:]
+		if name == "l" {
+			// Everything after -l is literal text, even if it looks like a flag
+			flags[name] = ""
+			rest = append(rest, args[i+1:]...)
+			break
+		}
+		if tmuxFlagsWithValue[name] && i+1 < len(args) {
+			flags[name] = args[i+1]
+			i++
+			continue
+		}
+		flags[name] = ""
+	}
+	return args[0], flags, rest
+}
+
+// sessionTarget reduces a tmux target ("=name", "name:0", "name:0.1") to
+// a session name. Pane IDs in this backend are session names.
+func sessionTarget(target string) string {
+	target = strings.TrimPrefix(target, "=")
+	if i := strings.Index(target, ":"); i >= 0 {
+		target = target[:i]
+	}
+	return target
+}
+
+func (t *Tmux) processListSessions(format, filter string) (string, error) {
+	sessions, err := t.procs.List()
+	if err != nil {
+		return "", err
+	}
+	// The only filter gt uses: #{==:#{session_name},NAME}
+	only, filtered := strings.CutPrefix(filter, "#{==:#{session_name},")
+	only = strings.TrimSuffix(only, "}")
+
+	var lines []string
+	for _, s := range sessions {
+		if filtered && s.Name != only {
+			continue
+		}
+		lines = append(lines, expandFormat(format, s, t.procs))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// expandFormat fills in the tmux format variables gt asks for.
+func expandFormat(format string, s *procsession.Session, procs *procsession.Manager) string {
+	activity := s.Created
+	if info, err := os.Stat(procs.LogPath(s.Name)); err == nil {
+		activity = info.ModTime()
+	}
+	vars := map[string]string{
+		"session_name":           s.Name,
+		"session_id":             "$" + strconv.Itoa(s.HostPID),
+		"session_windows":        "1",
+		"session_attached":       "0",
+		"session_created":        strconv.FormatInt(s.Created.Unix(), 10),
+		"session_created_string": s.Created.Format(time.ANSIC),
+		"session_activity":       strconv.FormatInt(activity.Unix(), 10),
+		"window_activity":        strconv.FormatInt(activity.Unix(), 10),
+		"session_last_attached":  "",
+		"window_name":            s.Name,
+		"pane_id":                s.Name,
+		"pane_pid":               strconv.Itoa(s.PID),
+		"pane_current_command":   s.Program(),
+		"pane_current_path":      s.WorkDir,
+	}
+	var b strings.Builder
+	for {
+		start := strings.Index(format, "#{")
+		if start < 0 {
+			b.WriteString(format)
+			return b.String()
+		}
+		end := strings.Index(format[start:], "}")
+		if end < 0 {
+			b.WriteString(format)
+			return b.String()
+		}
+		b.WriteString(format[:start])
+		b.WriteString(vars[format[start+2:start+end]])
+		format = format[start+end+1:]
+	}
+}
+
+// processKeys maps the tmux key names gt sends to the input they stand for.
+// Keys with no meaning on a plain stdin (line editing, menus) are dropped.
+var processKeys = map[string]string{
+	"Enter": "\n",
+	"Space": " ",
+	"Tab":   "\t",
+}
+
+func (t *Tmux) processSendKeys(target string, flags map[string]string, rest []string) error {
+	var text strings.Builder
+	for _, key := range rest {
+		if _, literal := flags["l"]; literal {
+			text.WriteString(key)
+			continue
+		}
+		text.WriteString(processKeys[key])
+	}
+	if text.Len() == 0 {
+		_, err := t.procs.Get(target)
+		return err
+	}
+	return t.procs.Send(target, text.String())
+}
+
+func (t *Tmux) processShowEnvironment(target string, rest []string) (string, error) {
+	s, err := t.procs.Get(target)
+	if err != nil {
+		return "", err
+	}
+	if len(rest) > 0 {
+		v, ok := s.Env[rest[0]]
+		if !ok {
+			return "", fmt.Errorf("tmux show-environment: unknown variable: %s", rest[0])
+		}
+		return rest[0] + "=" + v, nil
+	}
+	keys := make([]string, 0, len(s.Env))
+	for k := range s.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, k+"="+s.Env[k])
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,53 @@
+package tmux
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/procsession"
+)
+
+func TestParseTmuxArgs(t *testing.T) {
+	verb, flags, rest := parseTmuxArgs([]string{"new-session", "-d", "-s", "gt-mayor", "-c", "/town", "claude --resume"})
+	if verb != "new-session" || flags["s"] != "gt-mayor" || flags["c"] != "/town" || !reflect.DeepEqual(rest, []string{"claude --resume"}) {
+		t.Errorf("new-session parsed as %s %v %v", verb, flags, rest)
+	}
+	if _, ok := flags["d"]; !ok {
+		t.Error("-d not recorded")
+	}
+
+	_, flags, rest = parseTmuxArgs([]string{"send-keys", "-t", "gt-mayor", "-l", "-- not a flag"})
+	if _, literal := flags["l"]; !literal || !reflect.DeepEqual(rest, []string{"-- not a flag"}) {
+		t.Errorf("send-keys parsed as %v %v", flags, rest)
+	}
+
+	_, flags, _ = parseTmuxArgs([]string{"capture-pane", "-p", "-t", "gt-mayor", "-S", "-50"})
+	if flags["S"] != "-50" {
+		t.Errorf("capture-pane -S = %q", flags["S"])
+	}
+}
+
+func TestSessionTarget(t *testing.T) {
+	for target, want := range map[string]string{"=gt-mayor": "gt-mayor", "gt-mayor:0": "gt-mayor", "gt-mayor:0.1": "gt-mayor", "gt-mayor": "gt-mayor"} {
+		if got := sessionTarget(target); got != want {
+			t.Errorf("sessionTarget(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestExpandFormat(t *testing.T) {
+	s := &procsession.Session{
+		Name:    "gt-mayor",
+		WorkDir: "/town",
+		Command: "export GT_ROLE=mayor && claude",
+		Created: time.Unix(1700000000, 0),
+		HostPID: 41,
+		PID:     42,
+	}
+	m := procsession.NewManagerAt(t.TempDir())
+	got := expandFormat("#{session_name}:#{session_id}|#{pane_current_command}|#{pane_pid}|#{pane_current_path}|#{session_activity}", s, m)
+	if want := "gt-mayor:$41|claude|42|/town|1700000000"; got != want {
+		t.Errorf("expandFormat = %q, want %q", got, want)
+	}
+}
@@ -7,12 +7,16 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dryrun"
+	"github.com/steveyegge/gastown/internal/procsession"
+	"github.com/steveyegge/gastown/internal/transport"
 )
 
 // Common errors
@@ -23,13 +27,35 @@ var (
 )
 
 // Tmux wraps tmux operations.
-type Tmux struct{}
+type Tmux struct {
+	// procs runs sessions as background processes instead of in tmux
+	// (see BackendEnv).
+	procs *procsession.Manager
+}
 
 // NewTmux creates a new Tmux wrapper.
 func NewTmux() *Tmux {
+	if useProcessBackend() {
+		return &Tmux{procs: procsession.NewManager()}
+	}
 	return &Tmux{}
 }
 
+// readOnlyCommands don't change sessions and run even in dry-run mode.
+var readOnlyCommands = map[string]bool{
+	"attach-session":   true,
+	"capture-pane":     true,
+	"display-message":  true,
+	"has-session":      true,
+	"list-panes":       true,
+	"list-sessions":    true,
+	"list-windows":     true,
+	"select-window":    true,
+	"show-environment": true,
+	"show-options":     true,
+	"switch-client":    true,
+}
+
 // run executes a tmux command and returns stdout.
 func (t *Tmux) run(args ...string) (string, error) {
 	cmd := exec.Command("tmux", args...)
@@ -37,6 +63,13 @@ func (t *Tmux) run(args ...string) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	if !readOnlyCommands[args[0]] && dryrun.Command(cmd) {
+		return "", nil
+	}
+	if t.procs != nil {
+		return t.runProcess(args)
+	}
+
 	err := cmd.Run()
 	if err != nil {
 		return "", t.wrapError(err, stderr.String(), args)
@@ -84,6 +117,11 @@ func (t *Tmux) NewSession(name, workDir string) error {
 // initial process of the pane.
 // See: https://github.com/anthropics/gastown/issues/280
 func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
+	// Agents of remote rigs run on the rig's machine, in a local session
+	if tr := transport.For(workDir); tr.Remote() {
+		command = tr.Wrap(workDir, command)
+		workDir = nearestLocalDir(workDir)
+	}
 	args := []string{"new-session", "-d", "-s", name}
 	if workDir != "" {
 		args = append(args, "-c", workDir)
@@ -94,6 +132,21 @@ func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
 	return err
 }
 
+// nearestLocalDir returns dir or its closest ancestor that exists here.
+// A remote rig's work directories may exist only on the remote machine.
+func nearestLocalDir(dir string) string {
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
 // EnsureSessionFresh ensures a session is available and healthy.
 // If the session exists but is a zombie (Claude not running), it kills the session first.
 // This prevents "session already exists" errors when trying to restart dead agents.
@@ -145,6 +198,9 @@ func (t *Tmux) KillServer() error {
 
 // IsAvailable checks if tmux is installed and can be invoked.
 func (t *Tmux) IsAvailable() bool {
+	if t.procs != nil {
+		return true
+	}
 	cmd := exec.Command("tmux", "-V")
 	return cmd.Run() == nil
 }
@@ -511,6 +567,13 @@ func (t *Tmux) CapturePaneLines(session string, lines int) ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// PipePaneToFile appends all further output of the session's pane to path.
+func (t *Tmux) PipePaneToFile(session, path string) error {
+	quoted := "'" + strings.ReplaceAll(path, "'", `'\''`) + "'"
+	_, err := t.run("pipe-pane", "-o", "-t", session, "cat >> "+quoted)
+	return err
+}
+
 // AttachSession attaches to an existing session.
 // Note: This replaces the current process with tmux attach.
 func (t *Tmux) AttachSession(session string) error {
@@ -659,6 +722,18 @@ func (t *Tmux) IsClaudeRunning(session string) bool {
 	if matched {
 		return true
 	}
+	// Containerized agents (docker runtime) run behind the engine client
+	for _, engine := range constants.ContainerEngines {
+		if cmd == engine {
+			return true
+		}
+	}
+	// Agents of remote rigs run behind ssh
+	for _, client := range constants.RemoteClients {
+		if cmd == client {
+			return true
+		}
+	}
 	// If pane command is a shell, check for claude/node child processes.
 	// This handles the case where sessions are started with "bash -c 'export ... && claude ...'"
 	for _, shell := range constants.SupportedShells {
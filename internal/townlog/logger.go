@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/dryrun"
 )
 
 // EventType represents the type of agent lifecycle event.
@@ -75,6 +77,11 @@ func NewLogger(townRoot string) *Logger {
 
 // LogEvent logs a single event to the town log.
 func (l *Logger) LogEvent(event Event) error {
+	// A dry run didn't do what the event would report
+	if dryrun.Enabled() {
+		return nil
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -0,0 +1,211 @@
+// Package townmetrics reports a town's state as Prometheus metrics: running
+// agents, merge queue depth, and totals from the town event log.
+package townmetrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/mrqueue"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// eventCounters are the event types counted from the town event log.
+var eventCounters = []struct {
+	eventType string
+	name      string
+	help      string
+}{
+	{events.TypeMerged, "gt_merges_succeeded_total", "Merge requests merged by refineries."},
+	{events.TypeMergeFailed, "gt_merges_failed_total", "Merge requests that failed to merge."},
+	{events.TypeDone, "gt_polecats_done_total", "Polecats that finished work with 'gt done'."},
+	{events.TypeSpawn, "gt_polecats_spawned_total", "Polecats spawned."},
+	{events.TypeEscalationSent, "gt_escalations_total", "Escalations sent."},
+	{events.TypeSessionDeath, "gt_session_deaths_total", "Agent sessions that died."},
+}
+
+// Collector reports the state of a town: running agents, merge queue
+// depth, and totals derived from the event log (~/gt/.events.jsonl).
+// Event totals are read incrementally and reset if the log is truncated.
+type Collector struct {
+	townRoot string
+	tmux     *tmux.Tmux
+
+	mu       sync.Mutex
+	offset   int64
+	partial  []byte
+	counts   map[string]float64 // event type → count
+	spentUSD float64
+}
+
+// NewCollector creates a collector for the town at townRoot.
+func NewCollector(townRoot string) *Collector {
+	return &Collector{
+		townRoot: townRoot,
+		tmux:     tmux.NewTmux(),
+		counts:   make(map[string]float64),
+	}
+}
+
+// NewRegistry returns a registry with the built-in instrumentation and
+// the town's metrics, ready to serve at /metrics.
+func NewRegistry(townRoot string) *metrics.Registry {
+	r := metrics.NewRegistry()
+	r.Register(metrics.CollectorFunc(metrics.Default.Gather))
+	r.Register(NewCollector(townRoot))
+	return r
+}
+
+// Collect implements metrics.Collector.
+func (c *Collector) Collect() []metrics.Family {
+	families := c.collectSessions()
+	families = append(families, c.collectQueues())
+	families = append(families, c.collectEvents()...)
+	return families
+}
+
+// collectSessions counts running agent sessions by role and polecats by rig.
+func (c *Collector) collectSessions() []metrics.Family {
+	sessions, err := c.tmux.ListSessions()
+	if err != nil {
+		return nil
+	}
+
+	byRole := make(map[string]float64)
+	polecats := make(map[string]float64)
+	for _, rig := range c.rigNames() {
+		polecats[rig] = 0
+	}
+	for _, name := range sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		byRole[string(id.Role)]++
+		if id.Role == session.RolePolecat {
+			polecats[id.Rig]++
+		}
+	}
+
+	agents := metrics.Family{Name: "gt_agent_sessions", Help: "Running agent tmux sessions by role.", Type: metrics.TypeGauge}
+	for _, role := range []session.Role{session.RoleMayor, session.RoleDeacon, session.RoleWitness, session.RoleRefinery, session.RoleCrew, session.RolePolecat} {
+		agents.Samples = append(agents.Samples, metrics.Sample{Labels: map[string]string{"role": string(role)}, Value: byRole[string(role)]})
+	}
+	running := metrics.Family{Name: "gt_polecats_running", Help: "Running polecat sessions by rig.", Type: metrics.TypeGauge}
+	for _, rig := range sortedKeys(polecats) {
+		running.Samples = append(running.Samples, metrics.Sample{Labels: map[string]string{"rig": rig}, Value: polecats[rig]})
+	}
+	return []metrics.Family{agents, running}
+}
+
+// collectQueues reports merge queue depth per rig.
+func (c *Collector) collectQueues() metrics.Family {
+	f := metrics.Family{Name: "gt_merge_queue_depth", Help: "Merge requests waiting in each rig's queue.", Type: metrics.TypeGauge}
+	for _, rig := range c.rigNames() {
+		mrs, err := mrqueue.New(filepath.Join(c.townRoot, rig)).List()
+		if err != nil {
+			continue
+		}
+		f.Samples = append(f.Samples, metrics.Sample{Labels: map[string]string{"rig": rig}, Value: float64(len(mrs))})
+	}
+	return f
+}
+
+// collectEvents reads events appended since the last scrape and reports totals.
+func (c *Collector) collectEvents() []metrics.Family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.readEvents()
+
+	var families []metrics.Family
+	for _, ec := range eventCounters {
+		families = append(families, metrics.Family{Name: ec.name, Help: ec.help, Type: metrics.TypeCounter, Samples: []metrics.Sample{{Value: c.counts[ec.eventType]}}})
+	}
+	families = append(families, metrics.Family{
+		Name:    "gt_spend_usd_total",
+		Help:    "Agent session spend in USD recorded by 'gt costs record'.",
+		Type:    metrics.TypeCounter,
+		Samples: []metrics.Sample{{Value: c.spentUSD}},
+	})
+	return families
+}
+
+func (c *Collector) readEvents() {
+	f, err := os.Open(filepath.Join(c.townRoot, events.EventsFile)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+	if info.Size() < c.offset {
+		// Log was truncated or rotated: start over
+		c.offset, c.partial, c.spentUSD = 0, nil, 0
+		c.counts = make(map[string]float64)
+	}
+	if _, err := f.Seek(c.offset, io.SeekStart); err != nil {
+		return
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return
+	}
+	c.offset += int64(len(data))
+
+	data = append(c.partial, data...)
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		c.partial = data
+		return
+	}
+	c.partial = append([]byte(nil), data[end+1:]...)
+
+	for _, line := range bytes.Split(data[:end], []byte("\n")) {
+		var e events.Event
+		if len(line) == 0 || json.Unmarshal(line, &e) != nil {
+			continue
+		}
+		c.counts[e.Type]++
+		if e.Type == events.TypeSessionEnd {
+			if cost, ok := e.Payload["cost_usd"].(float64); ok {
+				c.spentUSD += cost
+			}
+		}
+	}
+}
+
+func (c *Collector) rigNames() []string {
+	rigs, err := config.LoadRigsConfig(filepath.Join(c.townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(rigs.Rigs))
+	for name := range rigs.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
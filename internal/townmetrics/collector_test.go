@@ -0,0 +1,67 @@
+package townmetrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+func valueOf(families []metrics.Family, name string) (float64, bool) {
+	for _, f := range families {
+		if f.Name == name && len(f.Samples) > 0 {
+			return f.Samples[0].Value, true
+		}
+	}
+	return 0, false
+}
+
+func TestCollectEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, events.EventsFile)
+	lines := `{"ts":"2026-01-01T00:00:00Z","type":"merged","actor":"gastown/refinery"}
+{"ts":"2026-01-01T00:00:01Z","type":"merge_failed","actor":"gastown/refinery"}
+{"ts":"2026-01-01T00:00:02Z","type":"merged","actor":"gastown/refinery"}
+{"ts":"2026-01-01T00:00:03Z","type":"session_end","actor":"gastown/polecats/toast","payload":{"cost_usd":1.25}}
+{"ts":"2026-01-01T00:00:04Z","type":"merged"`
+	if err := os.WriteFile(path, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCollector(townRoot)
+	families := c.collectEvents()
+	if v, _ := valueOf(families, "gt_merges_succeeded_total"); v != 2 {
+		t.Errorf("merged = %v, want 2", v)
+	}
+	if v, _ := valueOf(families, "gt_merges_failed_total"); v != 1 {
+		t.Errorf("merge_failed = %v, want 1", v)
+	}
+	if v, _ := valueOf(families, "gt_spend_usd_total"); v != 1.25 {
+		t.Errorf("spend = %v, want 1.25", v)
+	}
+
+	// Completing the partial line counts it on the next scrape
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`,"actor":"gastown/refinery"}` + "\n")
+	_ = f.Close()
+	if v, _ := valueOf(c.collectEvents(), "gt_merges_succeeded_total"); v != 3 {
+		t.Errorf("merged after append = %v, want 3", v)
+	}
+
+	// Truncation resets totals
+	if err := os.WriteFile(path, []byte(`{"type":"merge_failed"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	families = c.collectEvents()
+	if v, _ := valueOf(families, "gt_merges_succeeded_total"); v != 0 {
+		t.Errorf("merged after truncate = %v, want 0", v)
+	}
+	if v, _ := valueOf(families, "gt_merges_failed_total"); v != 1 {
+		t.Errorf("merge_failed after truncate = %v, want 1", v)
+	}
+}
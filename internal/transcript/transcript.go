@@ -0,0 +1,289 @@
+// Package transcript records polecat session transcripts for 'gt logs'.
+//
+// When a polecat session starts, its pane output (agent stdout, prompts,
+// tool calls) is piped to a per-bead file under ~/gt/.logs/transcripts/ and
+// an entry is appended to index.jsonl so the file can be found by bead or
+// by polecat after the session is gone. The session's GT_TRANSCRIPT
+// variable names the file, so 'gt mol step done' can write step markers
+// into it; the markers let 'gt logs --step' pull out a single step.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+// EnvVar names the session's transcript file.
+const EnvVar = "GT_TRANSCRIPT"
+
+// DirName is the transcript directory within the town log directory.
+const DirName = "transcripts"
+
+// IndexFile lists recorded transcripts, one JSON entry per line.
+const IndexFile = "index.jsonl"
+
+// ErrNotFound is returned when no transcript matches a target.
+var ErrNotFound = errors.New("no transcript found")
+
+// Entry describes one recorded session.
+type Entry struct {
+	Bead    string    `json:"bead,omitempty"`
+	Rig     string    `json:"rig"`
+	Polecat string    `json:"polecat"`
+	Session string    `json:"session"`
+	Path    string    `json:"path"`
+	Started time.Time `json:"started"`
+}
+
+// Agent returns the entry's agent address (<rig>/<polecat>).
+func (e Entry) Agent() string {
+	return e.Rig + "/" + e.Polecat
+}
+
+// Dir returns the transcript directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(logging.Dir(townRoot), DirName)
+}
+
+// Begin registers a new session transcript and writes its header. Sessions
+// for the same bead share a file, so a respawned polecat's output follows
+// the previous attempt's.
+func Begin(townRoot, rig, polecat, session, bead string) (*Entry, error) {
+	dir := Dir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating transcript directory: %w", err)
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s-%s", rig, polecat, now.Format("20060102-150405"))
+	if bead != "" {
+		name = bead
+	}
+	e := &Entry{
+		Bead:    bead,
+		Rig:     rig,
+		Polecat: polecat,
+		Session: session,
+		Path:    filepath.Join(dir, strings.ReplaceAll(name, "/", "_")+".log"),
+		Started: now.UTC(),
+	}
+
+	header := fmt.Sprintf("[gt] session %s started %s (%s", session, e.Started.Format(time.RFC3339), e.Agent())
+	if bead != "" {
+		header += ", bead " + bead
+	}
+	if err := appendLine(e.Path, header+")"); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	if err := appendLine(filepath.Join(dir, IndexFile), string(data)); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// List returns recorded transcripts, oldest first.
+func List(townRoot string) ([]Entry, error) {
+	f, err := os.Open(filepath.Join(Dir(townRoot), IndexFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil && e.Path != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Find returns the most recent transcript for a bead ID or polecat. A
+// polecat may be given as <name>, <rig>/<name> or <rig>/polecats/<name>.
+func Find(townRoot, target string) (*Entry, error) {
+	entries, err := List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	target = strings.Replace(target, "/polecats/", "/", 1)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Bead == target || e.Polecat == target || e.Agent() == target {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for %s", ErrNotFound, target)
+}
+
+// Step marker kinds.
+const (
+	StepBegin = "begin"
+	StepEnd   = "end"
+)
+
+// markerPrefix starts every step marker line.
+const markerPrefix = "[gt] step "
+
+// Mark writes a step marker into a transcript.
+func Mark(path, kind, stepID, title string) error {
+	return appendLine(path, fmt.Sprintf("\n%s%s %s: %s", markerPrefix, kind, stepID, title))
+}
+
+// MarkCurrent writes a step marker into the calling session's transcript,
+// if it has one.
+func MarkCurrent(kind, stepID, title string) error {
+	path := os.Getenv(EnvVar)
+	if path == "" {
+		return nil
+	}
+	return Mark(path, kind, stepID, title)
+}
+
+// Step returns the part of a transcript covering the steps whose ID or
+// title contains query (case-insensitive). Output before a "begin" marker
+// belongs to that step; output before an "end" marker with no begin of its
+// own belongs to the step that ended.
+func Step(content, query string) (string, bool) {
+	query = strings.ToLower(query)
+	matches := func(label string) bool {
+		return strings.Contains(strings.ToLower(label), query)
+	}
+
+	var out []string
+	var segment []string
+	open := "" // label of the step begun by the last marker
+	found := false
+	for _, line := range strings.Split(content, "\n") {
+		kind, label, ok := parseMarker(line)
+		if !ok {
+			segment = append(segment, line)
+			continue
+		}
+		owner := open
+		if owner == "" && kind == StepEnd {
+			owner = label
+		}
+		if owner != "" && matches(owner) {
+			out = append(out, segment...)
+			if kind == StepEnd {
+				out = append(out, line)
+			}
+			found = true
+		}
+		segment, open = nil, ""
+		if kind == StepBegin {
+			segment, open = []string{line}, label
+		}
+	}
+	if open != "" && matches(open) {
+		out = append(out, segment...)
+		found = true
+	}
+	return strings.Join(out, "\n"), found
+}
+
+// Summary condenses a transcript for someone picking the work up: the
+// steps finished so far, the step in progress, and the last tail lines of
+// output.
+func Summary(content string, tail int) string {
+	var done []string
+	open := ""
+	var recent []string
+	for _, line := range strings.Split(Clean(content), "\n") {
+		if kind, label, ok := parseMarker(line); ok {
+			if kind == StepEnd {
+				done = append(done, label)
+				open = ""
+			} else {
+				open = label
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "[gt] ") {
+			continue
+		}
+		recent = append(recent, line)
+		if len(recent) > tail {
+			recent = recent[1:]
+		}
+	}
+
+	var b strings.Builder
+	for _, label := range done {
+		fmt.Fprintf(&b, "done: %s\n", label)
+	}
+	if open != "" {
+		fmt.Fprintf(&b, "in progress: %s\n", open)
+	}
+	if len(recent) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(strings.Join(recent, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// parseMarker recognizes a step marker line, returning its kind and
+// "<id>: <title>" label.
+func parseMarker(line string) (kind, label string, ok bool) {
+	rest, ok := strings.CutPrefix(Clean(line), markerPrefix)
+	if !ok {
+		return "", "", false
+	}
+	kind, label, ok = strings.Cut(rest, " ")
+	if !ok || (kind != StepBegin && kind != StepEnd) {
+		return "", "", false
+	}
+	return kind, label, true
+}
+
+// ansiRE matches terminal escape sequences (CSI, OSC and two-byte escapes).
+var ansiRE = regexp.MustCompile(`\x1b(\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)|[@-Z\\-_])`)
+
+// Clean strips terminal escape sequences from raw pane output and resolves
+// carriage-return redraws, keeping the last version of each line.
+func Clean(s string) string {
+	s = ansiRE.ReplaceAllString(s, "")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if j := strings.LastIndex(line, "\r"); j >= 0 {
+			line = line[j+1:]
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func appendLine(path, line string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: transcripts are not secrets
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
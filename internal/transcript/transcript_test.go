@@ -0,0 +1,136 @@
+package transcript
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBeginAndFind(t *testing.T) {
+	town := t.TempDir()
+
+	first, err := Begin(town, "gastown", "Toast", "gt-gastown-Toast", "gt-abc")
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if _, err := Begin(town, "gastown", "Nux", "gt-gastown-Nux", ""); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	// A respawn for the same bead shares the file
+	again, err := Begin(town, "gastown", "Toast", "gt-gastown-Toast", "gt-abc")
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if again.Path != first.Path {
+		t.Errorf("respawn path = %s, want %s", again.Path, first.Path)
+	}
+
+	for _, target := range []string{"gt-abc", "Toast", "gastown/Toast", "gastown/polecats/Toast"} {
+		e, err := Find(town, target)
+		if err != nil {
+			t.Fatalf("Find(%q): %v", target, err)
+		}
+		if e.Path != first.Path {
+			t.Errorf("Find(%q) = %s, want %s", target, e.Path, first.Path)
+		}
+	}
+	if e, err := Find(town, "Nux"); err != nil || e.Bead != "" {
+		t.Errorf("Find(Nux) = %+v, %v", e, err)
+	}
+	if _, err := Find(town, "gt-zzz"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find(gt-zzz) err = %v, want ErrNotFound", err)
+	}
+
+	data, err := os.ReadFile(first.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "[gt] session gt-gastown-Toast started"); n != 2 {
+		t.Errorf("got %d session headers, want 2", n)
+	}
+}
+
+func TestStep(t *testing.T) {
+	path := t.TempDir() + "/t.log"
+	if err := os.WriteFile(path, []byte("design work\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(Mark(path, StepEnd, "gt-m.1", "Design"))
+	must(Mark(path, StepBegin, "gt-m.2", "Implement"))
+	must(appendLine(path, "coding"))
+	must(Mark(path, StepEnd, "gt-m.2", "Implement"))
+	must(Mark(path, StepBegin, "gt-m.3", "Review"))
+	must(appendLine(path, "reviewing"))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+
+	tests := []struct {
+		query   string
+		want    []string
+		notWant []string
+	}{
+		{"design", []string{"design work"}, []string{"coding", "reviewing"}},
+		{"IMPLEMENT", []string{"coding"}, []string{"design work", "reviewing"}},
+		{"gt-m.3", []string{"reviewing"}, []string{"coding"}},
+	}
+	for _, tt := range tests {
+		got, ok := Step(content, tt.query)
+		if !ok {
+			t.Errorf("Step(%q) found nothing", tt.query)
+			continue
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(got, w) {
+				t.Errorf("Step(%q) missing %q:\n%s", tt.query, w, got)
+			}
+		}
+		for _, nw := range tt.notWant {
+			if strings.Contains(got, nw) {
+				t.Errorf("Step(%q) contains %q:\n%s", tt.query, nw, got)
+			}
+		}
+	}
+	if _, ok := Step(content, "submit"); ok {
+		t.Error("Step(submit) found a section, want none")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	content := "[gt] session gt-pr-Toast started\nread the spec\n" +
+		"\n[gt] step end gt-m.1: Design\n" +
+		"\n[gt] step begin gt-m.2: Implement\nline 1\nline 2\n\x1b[32mline 3\x1b[0m\n"
+
+	got := Summary(content, 2)
+	for _, want := range []string{"done: gt-m.1: Design", "in progress: gt-m.2: Implement", "line 2\nline 3"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Summary missing %q:\n%s", want, got)
+		}
+	}
+	for _, notWant := range []string{"line 1", "[gt] session", "\x1b"} {
+		if strings.Contains(got, notWant) {
+			t.Errorf("Summary contains %q:\n%s", notWant, got)
+		}
+	}
+	if got := Summary("", 10); got != "" {
+		t.Errorf("Summary of empty transcript = %q, want empty", got)
+	}
+}
+
+func TestClean(t *testing.T) {
+	raw := "\x1b[1;32m✓\x1b[0m done\r\nspinner 1\rspinner 2\n\x1b]0;title\x07plain"
+	want := "✓ done\nspinner 2\nplain"
+	if got := Clean(raw); got != want {
+		t.Errorf("Clean = %q, want %q", got, want)
+	}
+}
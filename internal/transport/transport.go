@@ -0,0 +1,303 @@
+// Package transport runs commands where a rig's files live: locally for
+// ordinary rigs, over SSH for remote rigs.
+//
+// A remote rig keeps a stub directory in the town (<town>/<rig>, holding
+// its config.json) while its clones live on another machine. Paths under
+// the stub map to the same relative paths under the remote root, so code
+// that builds paths the usual way (<rig>/polecats/<name>) runs git and bd
+// against the remote copies without knowing the rig is remote.
+package transport
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Transport runs commands against a rig's files.
+type Transport interface {
+	// Command returns a command that runs name with args in dir, with env
+	// (KEY=VALUE) added to the environment. Paths in dir, args and env
+	// values are local paths; remote transports translate them.
+	Command(dir string, env []string, name string, args ...string) *exec.Cmd
+
+	// Wrap returns a shell command line that runs command in dir, for an
+	// agent session started locally.
+	Wrap(dir, command string) string
+
+	// ReadDir returns the names of the subdirectories of dir, skipping
+	// hidden ones. A missing dir is empty.
+	ReadDir(dir string) ([]string, error)
+
+	// Remote reports whether commands run on another machine.
+	Remote() bool
+
+	String() string
+}
+
+// Local runs commands on this machine.
+type Local struct{}
+
+// Command implements Transport.
+func (Local) Command(dir string, env []string, name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...) //nolint:gosec // G204: callers pass trusted tools
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
+
+// Wrap implements Transport.
+func (Local) Wrap(_, command string) string { return command }
+
+// ReadDir implements Transport.
+func (Local) ReadDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// Remote implements Transport.
+func (Local) Remote() bool { return false }
+
+func (Local) String() string { return "local" }
+
+// SSH runs commands on another machine with ssh. LocalRoot (the rig's stub
+// directory) maps to Root on Host.
+type SSH struct {
+	Host      string // [user@]host
+	Root      string // rig directory on Host
+	LocalRoot string // rig directory in the town
+}
+
+// ParseRemote splits a "[user@]host:/path" remote rig location.
+func ParseRemote(spec string) (host, root string, err error) {
+	host, root, ok := strings.Cut(spec, ":")
+	if !ok || host == "" || root == "" {
+		return "", "", fmt.Errorf("invalid remote %q: want [user@]host:/path", spec)
+	}
+	if !path.IsAbs(root) {
+		return "", "", fmt.Errorf("invalid remote %q: the remote path must be absolute", spec)
+	}
+	return host, path.Clean(root), nil
+}
+
+// NewSSH returns the transport for a rig at the remote location spec whose
+// stub directory is localRoot.
+func NewSSH(spec, localRoot string) (*SSH, error) {
+	host, root, err := ParseRemote(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &SSH{Host: host, Root: root, LocalRoot: filepath.Clean(localRoot)}, nil
+}
+
+// Path maps a local path under LocalRoot to the remote path. Other strings
+// are returned unchanged.
+func (s *SSH) Path(p string) string {
+	if p == s.LocalRoot {
+		return s.Root
+	}
+	rel, ok := strings.CutPrefix(p, s.LocalRoot+string(filepath.Separator))
+	if !ok {
+		return p
+	}
+	return path.Join(s.Root, filepath.ToSlash(rel))
+}
+
+// mapArg maps an argument that is, or ends in (--flag=path), a local path.
+func (s *SSH) mapArg(arg string) string {
+	if flag, value, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(flag, "-") {
+		return flag + "=" + s.Path(value)
+	}
+	return s.Path(arg)
+}
+
+// script builds the remote shell command for Command.
+func (s *SSH) script(dir string, env []string, name string, args []string) string {
+	var b strings.Builder
+	if dir != "" {
+		b.WriteString("cd " + ShellQuote(s.Path(dir)) + " && ")
+	}
+	if len(env) > 0 {
+		b.WriteString("env")
+		for _, kv := range env {
+			k, v, _ := strings.Cut(kv, "=")
+			b.WriteString(" " + ShellQuote(k+"="+s.Path(v)))
+		}
+		b.WriteString(" ")
+	}
+	b.WriteString(ShellQuote(name))
+	for _, a := range args {
+		b.WriteString(" " + ShellQuote(s.mapArg(a)))
+	}
+	return b.String()
+}
+
+// Command implements Transport.
+func (s *SSH) Command(dir string, env []string, name string, args ...string) *exec.Cmd {
+	return exec.Command("ssh", "-o", "BatchMode=yes", s.Host, s.script(dir, env, name, args)) //nolint:gosec // G204: host comes from rigs.json
+}
+
+// Wrap implements Transport. The session's pane runs ssh with a terminal.
+// Paths in the rig embedded in command are translated too.
+func (s *SSH) Wrap(dir, command string) string {
+	remote := strings.ReplaceAll(command, s.LocalRoot, s.Root)
+	if dir != "" {
+		remote = "cd " + ShellQuote(s.Path(dir)) + " && " + remote
+	}
+	return "ssh -t " + ShellQuote(s.Host) + " " + ShellQuote(remote)
+}
+
+// ReadDir implements Transport.
+func (s *SSH) ReadDir(dir string) ([]string, error) {
+	script := "cd " + ShellQuote(s.Path(dir)) + " 2>/dev/null || exit 0; for d in */; do [ -d \"$d\" ] && echo \"${d%/}\"; done; exit 0"
+	out, err := exec.Command("ssh", "-o", "BatchMode=yes", s.Host, script).Output() //nolint:gosec // G204: host comes from rigs.json
+	if err != nil {
+		return nil, fmt.Errorf("listing %s on %s: %w", s.Path(dir), s.Host, err)
+	}
+	var names []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" && !strings.HasPrefix(line, ".") {
+			names = append(names, line)
+		}
+	}
+	return names, nil
+}
+
+// Remote implements Transport.
+func (s *SSH) Remote() bool { return true }
+
+func (s *SSH) String() string { return s.Host + ":" + s.Root }
+
+// ShellQuote quotes s as a single POSIX shell word.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// registered holds remote rigs set up by this process before they are in
+// rigs.json, keyed by stub directory.
+var (
+	registeredMu sync.Mutex
+	registered   = map[string]*SSH{}
+)
+
+// Register makes For use t for paths under t.LocalRoot, for a remote rig
+// that is being created and isn't in rigs.json yet.
+func Register(t *SSH) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	registered[t.LocalRoot] = t
+}
+
+// Unregister undoes Register.
+func Unregister(t *SSH) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	delete(registered, t.LocalRoot)
+}
+
+// townRemotes caches each town's remote rigs, keyed by town root.
+var (
+	townRemotesMu sync.Mutex
+	townRemotes   = map[string]cachedRemotes{}
+)
+
+type cachedRemotes struct {
+	modTime time.Time
+	remotes map[string]string // rig name -> remote spec
+}
+
+// remotesFor returns the remote rigs registered in a town's rigs.json.
+func remotesFor(townRoot string) map[string]string {
+	rigsPath := filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON)
+	info, err := os.Stat(rigsPath)
+	if err != nil {
+		return nil
+	}
+
+	townRemotesMu.Lock()
+	defer townRemotesMu.Unlock()
+	if c, ok := townRemotes[townRoot]; ok && c.modTime.Equal(info.ModTime()) {
+		return c.remotes
+	}
+	remotes := map[string]string{}
+	if rigsConfig, err := config.LoadRigsConfig(rigsPath); err == nil {
+		for name, entry := range rigsConfig.Rigs {
+			if entry.Remote != "" {
+				remotes[name] = entry.Remote
+			}
+		}
+	}
+	townRemotes[townRoot] = cachedRemotes{modTime: info.ModTime(), remotes: remotes}
+	return remotes
+}
+
+// For returns the transport for commands run in dir: SSH when dir is in a
+// remote rig, Local otherwise.
+func For(dir string) Transport {
+	if dir == "" {
+		return Local{}
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Local{}
+	}
+	if t := registeredFor(abs); t != nil {
+		return t
+	}
+	townRoot, err := workspace.Find(abs)
+	if err != nil || townRoot == "" || townRoot == abs {
+		return Local{}
+	}
+	remotes := remotesFor(townRoot)
+	if len(remotes) == 0 {
+		return Local{}
+	}
+	rel, err := filepath.Rel(townRoot, abs)
+	if err != nil {
+		return Local{}
+	}
+	rigName, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	spec, ok := remotes[rigName]
+	if !ok {
+		return Local{}
+	}
+	t, err := NewSSH(spec, filepath.Join(townRoot, rigName))
+	if err != nil {
+		return Local{}
+	}
+	return t
+}
+
+func registeredFor(abs string) *SSH {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	for root, t := range registered {
+		if abs == root || strings.HasPrefix(abs, root+string(filepath.Separator)) {
+			return t
+		}
+	}
+	return nil
+}
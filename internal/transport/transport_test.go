@@ -0,0 +1,111 @@
+package transport
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		spec, host, root string
+		wantErr          bool
+	}{
+		{"me@gpubox:/srv/gt/rig", "me@gpubox", "/srv/gt/rig", false},
+		{"gpubox:/srv/gt/rig/", "gpubox", "/srv/gt/rig", false},
+		{"gpubox:relative/path", "", "", true},
+		{"gpubox", "", "", true},
+		{":/srv", "", "", true},
+	}
+	for _, tt := range tests {
+		host, root, err := ParseRemote(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRemote(%q) err = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if host != tt.host || root != tt.root {
+			t.Errorf("ParseRemote(%q) = %q, %q; want %q, %q", tt.spec, host, root, tt.host, tt.root)
+		}
+	}
+}
+
+func TestSSHPathMapping(t *testing.T) {
+	s, err := NewSSH("me@box:/srv/rig", "/home/me/gt/rig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"/home/me/gt/rig":                     "/srv/rig",
+		"/home/me/gt/rig/polecats/toast":      "/srv/rig/polecats/toast",
+		"/home/me/gt/rigby":                   "/home/me/gt/rigby",
+		"/home/me/gt/mayor":                   "/home/me/gt/mayor",
+		"https://github.com/example/r.git":    "https://github.com/example/r.git",
+		"--git-dir=/home/me/gt/rig/.repo.git": "--git-dir=/srv/rig/.repo.git",
+	}
+	for in, want := range tests {
+		if got := s.mapArg(in); got != want {
+			t.Errorf("mapArg(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSSHCommand(t *testing.T) {
+	s, _ := NewSSH("me@box:/srv/rig", "/home/me/gt/rig")
+	cmd := s.Command("/home/me/gt/rig/mayor/rig", []string{"BEADS_DIR=/home/me/gt/rig/.beads"}, "bd", "show", "it's")
+	if cmd.Args[0] != "ssh" || cmd.Args[len(cmd.Args)-2] != "me@box" {
+		t.Fatalf("args = %q", cmd.Args)
+	}
+	script := cmd.Args[len(cmd.Args)-1]
+	want := `cd '/srv/rig/mayor/rig' && env 'BEADS_DIR=/srv/rig/.beads' 'bd' 'show' 'it'\''s'`
+	if script != want {
+		t.Errorf("script = %s\nwant     %s", script, want)
+	}
+}
+
+func TestSSHWrap(t *testing.T) {
+	s, _ := NewSSH("me@box:/srv/rig", "/home/me/gt/rig")
+	got := s.Wrap("/home/me/gt/rig/polecats/toast", "export GT_ROOT=/home/me/gt/rig && claude")
+	want := `ssh -t 'me@box' 'cd '\''/srv/rig/polecats/toast'\'' && export GT_ROOT=/srv/rig && claude'`
+	if got != want {
+		t.Errorf("Wrap = %s\nwant   %s", got, want)
+	}
+}
+
+func TestFor(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rigs := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{
+		"local":   {GitURL: "x"},
+		"faraway": {GitURL: "x", Remote: "me@box:/srv/faraway"},
+	}}
+	if err := config.SaveRigsConfig(filepath.Join(town, "mayor", "rigs.json"), rigs); err != nil {
+		t.Fatal(err)
+	}
+
+	if For(filepath.Join(town, "local", "polecats", "toast")).Remote() {
+		t.Error("local rig should use the local transport")
+	}
+	if For(town).Remote() {
+		t.Error("town root should use the local transport")
+	}
+	tr := For(filepath.Join(town, "faraway", "polecats", "toast"))
+	if !tr.Remote() || !strings.HasPrefix(tr.String(), "me@box:") {
+		t.Errorf("remote rig transport = %v", tr)
+	}
+
+	// A rig being created is found before it is in rigs.json
+	pending, _ := NewSSH("me@box:/srv/pending", filepath.Join(town, "pending"))
+	Register(pending)
+	defer Unregister(pending)
+	if got := For(filepath.Join(town, "pending", ".repo.git")); got != Transport(pending) {
+		t.Errorf("registered rig transport = %v", got)
+	}
+}
@@ -6,35 +6,34 @@ import (
 	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/ui"
 )
 
-// Styles for the convoy TUI
+// Styles for the convoy TUI, drawn from the shared ui palette so GT_THEME
+// and NO_COLOR apply here too.
 var (
 	titleStyle = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("12"))
+			Foreground(ui.ColorAccent)
 
-	selectedStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("236")).
-			Foreground(lipgloss.Color("15"))
+	selectedStyle = ui.SelectedStyle
 
-	convoyStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("15"))
+	convoyStyle = lipgloss.NewStyle()
 
 	issueOpenStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("11")) // yellow
+			Foreground(ui.ColorWarn) // yellow
 
 	issueClosedStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("10")) // green
+				Foreground(ui.ColorPass) // green
 
 	progressStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("8")) // gray
+			Foreground(ui.ColorMuted) // gray
 
 	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("8"))
+			Foreground(ui.ColorMuted)
 
 	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("9")) // red
+			Foreground(ui.ColorFail) // red
 )
 
 // renderView renders the entire view.
@@ -63,9 +62,9 @@ func (m Model) renderView() string {
 		isSelected := pos == m.cursor
 
 		// Convoy row
-		expandIcon := "▶"
+		expandIcon := ui.Glyph("▶", ">")
 		if c.Expanded {
-			expandIcon = "▼"
+			expandIcon = ui.Glyph("▼", "v")
 		}
 
 		statusIcon := statusToIcon(c.Status)
@@ -92,15 +91,15 @@ func (m Model) renderView() string {
 				isIssueSelected := pos == m.cursor
 
 				// Tree connector
-				connector := "├─"
+				connector := ui.Glyph("├─", "|-")
 				if ii == len(c.Issues)-1 {
-					connector = "└─"
+					connector = ui.Glyph("└─", "`-")
 				}
 
-				issueIcon := "○"
+				issueIcon := ui.StatusIconOpen
 				style := issueOpenStyle
 				if issue.Status == "closed" {
-					issueIcon = "✓"
+					issueIcon = ui.StatusIconClosed
 					style = issueClosedStyle
 				}
 
@@ -137,13 +136,13 @@ func (m Model) renderView() string {
 func statusToIcon(status string) string {
 	switch status {
 	case "open":
-		return "🚚"
+		return ui.Emoji("🚚", ui.StatusIconOpen)
 	case "closed":
-		return "✓"
+		return ui.StatusIconClosed
 	case "in_progress":
-		return "→"
+		return ui.Glyph("→", "->")
 	default:
-		return "●"
+		return ui.StatusIconBlocked
 	}
 }
 
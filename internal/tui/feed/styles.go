@@ -9,13 +9,13 @@ import (
 
 // Color palette using Ayu theme colors from ui package
 var (
-	colorPrimary   = ui.ColorAccent // Blue
-	colorSuccess   = ui.ColorPass   // Green
-	colorWarning   = ui.ColorWarn   // Yellow
-	colorError     = ui.ColorFail   // Red
-	colorDim       = ui.ColorMuted  // Gray
-	colorHighlight = lipgloss.AdaptiveColor{Light: "#59c2ff", Dark: "#59c2ff"} // Cyan (Ayu)
-	colorAccent    = lipgloss.AdaptiveColor{Light: "#d2a6ff", Dark: "#d2a6ff"} // Purple (Ayu)
+	colorPrimary   = ui.ColorAccent       // Blue
+	colorSuccess   = ui.ColorPass         // Green
+	colorWarning   = ui.ColorWarn         // Yellow
+	colorError     = ui.ColorFail         // Red
+	colorDim       = ui.ColorMuted        // Gray
+	colorHighlight = ui.ColorStatusHooked // Cyan (Ayu)
+	colorAccent    = ui.ColorTypeEpic     // Purple (Ayu)
 )
 
 // Styles for the feed TUI
@@ -27,8 +27,7 @@ var (
 			Padding(0, 1)
 
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("15"))
+			Bold(true)
 
 	FilterStyle = lipgloss.NewStyle().
 			Foreground(colorDim)
@@ -46,8 +45,7 @@ var (
 	RoleStyle = lipgloss.NewStyle().
 			Foreground(colorAccent)
 
-	AgentNameStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("15"))
+	AgentNameStyle = lipgloss.NewStyle()
 
 	AgentActiveStyle = lipgloss.NewStyle().
 				Foreground(colorSuccess)
@@ -82,9 +80,7 @@ var (
 				Foreground(colorWarning)
 
 	// Status bar styles
-	StatusBarStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("236")).
-			Foreground(colorDim).
+	StatusBarStyle = ui.SelectedStyle.
 			Padding(0, 1)
 
 	HelpKeyStyle = lipgloss.NewStyle().
@@ -100,14 +96,15 @@ var (
 				BorderForeground(colorPrimary).
 				Padding(0, 1)
 
-	// Role icons - uses centralized emojis from constants package
+	// Role icons - uses centralized emojis from constants package,
+	// replaced with short text tags when emoji are disabled (GT_GLYPHS)
 	RoleIcons = map[string]string{
-		constants.RoleMayor:    constants.EmojiMayor,
-		constants.RoleWitness:  constants.EmojiWitness,
-		constants.RoleRefinery: constants.EmojiRefinery,
-		constants.RoleCrew:     constants.EmojiCrew,
-		constants.RolePolecat:  constants.EmojiPolecat,
-		constants.RoleDeacon:   constants.EmojiDeacon,
+		constants.RoleMayor:    ui.Emoji(constants.EmojiMayor, "[M]"),
+		constants.RoleWitness:  ui.Emoji(constants.EmojiWitness, "[W]"),
+		constants.RoleRefinery: ui.Emoji(constants.EmojiRefinery, "[R]"),
+		constants.RoleCrew:     ui.Emoji(constants.EmojiCrew, "[C]"),
+		constants.RolePolecat:  ui.Emoji(constants.EmojiPolecat, "[P]"),
+		constants.RoleDeacon:   ui.Emoji(constants.EmojiDeacon, "[D]"),
 	}
 
 	// MQ event styles
@@ -125,36 +122,42 @@ var (
 	EventMergeSkippedStyle = lipgloss.NewStyle().
 				Foreground(colorWarning)
 
-	// Event symbols
+	// Event symbols (emoji fall back to unicode, unicode falls back to ASCII,
+	// using ui's ASCII tags for pass, failure and warning)
 	EventSymbols = map[string]string{
 		"create":   "+",
-		"update":   "→",
-		"complete": "✓",
-		"fail":     "✗",
-		"delete":   "⊘",
-		"pin":      "📌",
+		"update":   ui.Glyph("→", ">"),
+		"complete": ui.Glyph("✓", ui.ASCIIPass),
+		"fail":     ui.Glyph("✗", ui.ASCIIFail),
+		"delete":   ui.Glyph("⊘", "-"),
+		"pin":      ui.Glyph(ui.Emoji("📌", "▲"), "^"),
 		// Witness patrol events
-		"patrol_started":  constants.EmojiWitness,
-		"patrol_complete": "✓",
-		"polecat_checked": "·",
-		"polecat_nudged":  "⚡",
-		"escalation_sent": "⬆",
+		"patrol_started":  ui.Glyph(ui.Emoji(constants.EmojiWitness, "◉"), "o"),
+		"patrol_complete": ui.Glyph("✓", ui.ASCIIPass),
+		"polecat_checked": ui.Glyph("·", "."),
+		"polecat_nudged":  ui.Glyph("⚡", "!"),
+		"escalation_sent": ui.Glyph("⬆", "^"),
 		// Merge events
-		"merge_started": "⚙",
-		"merged":        "✓",
-		"merge_failed":  "✗",
-		"merge_skipped": "⊘",
+		"merge_started": ui.Glyph("⚙", "*"),
+		"merged":        ui.Glyph("✓", ui.ASCIIPass),
+		"merge_failed":  ui.Glyph("✗", ui.ASCIIFail),
+		"merge_skipped": ui.Glyph("⊘", "-"),
 		// General gt events
-		"sling":   "🎯",
-		"hook":    "🪝",
-		"unhook":  "↩",
-		"handoff": "🤝",
-		"done":    "✓",
-		"mail":    "✉",
-		"spawn":   "🚀",
-		"kill":    "💀",
-		"nudge":   "⚡",
-		"boot":    "🔌",
-		"halt":    "⏹",
+		"sling":   ui.Glyph(ui.Emoji("🎯", "◎"), "@"),
+		"hook":    ui.Glyph(ui.Emoji("🪝", "⚓"), "&"),
+		"unhook":  ui.Glyph("↩", "<"),
+		"handoff": ui.Glyph(ui.Emoji("🤝", "⇄"), "="),
+		"done":    ui.Glyph("✓", ui.ASCIIPass),
+		"mail":    ui.Glyph("✉", "m"),
+		"spawn":   ui.Glyph(ui.Emoji("🚀", "▲"), "+"),
+		"kill":    ui.Glyph(ui.Emoji("💀", "✗"), ui.ASCIIFail),
+		"nudge":   ui.Glyph("⚡", "!"),
+		"boot":    ui.Glyph(ui.Emoji("🔌", "⏻"), "b"),
+		"halt":    ui.Glyph("⏹", "#"),
+		"ingest":  ui.Glyph(ui.Emoji("📥", "↓"), "+"),
+		// Molecule events
+		"step_complete":     ui.Glyph("✓", ui.ASCIIPass),
+		"molecule_complete": ui.Glyph(ui.Emoji("🎉", "✓"), ui.ASCIIPass),
+		"step_slow":         ui.Glyph(ui.Emoji("🐢", "⚠"), ui.ASCIIWarn),
 	}
 )
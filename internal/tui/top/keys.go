@@ -0,0 +1,82 @@
+package top
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap defines the key bindings for the top TUI.
+type KeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	Peek     key.Binding
+	Kill     key.Binding
+	Restart  key.Binding
+	Escalate key.Binding
+	Refresh  key.Binding
+	Confirm  key.Binding
+	Cancel   key.Binding
+	Help     key.Binding
+	Quit     key.Binding
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Peek: key.NewBinding(
+			key.WithKeys("enter", "p"),
+			key.WithHelp("enter/p", "peek"),
+		),
+		Kill: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "kill session"),
+		),
+		Restart: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "restart session"),
+		),
+		Escalate: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "escalate"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "refresh"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "confirm"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc", "n"),
+			key.WithHelp("esc", "back"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "help"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to show in the help view.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Peek, k.Kill, k.Restart, k.Escalate, k.Quit, k.Help}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Peek, k.Refresh},
+		{k.Kill, k.Restart, k.Escalate},
+		{k.Confirm, k.Cancel, k.Help, k.Quit},
+	}
+}
@@ -0,0 +1,288 @@
+// Package top is the htop-style view of a town behind gt top: one row per
+// polecat with what it is working on, refreshed on an interval, and keys to
+// peek at, kill, restart or escalate the selected one.
+package top
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Row is one polecat.
+type Row struct {
+	Rig      string
+	Name     string
+	Address  string // rig/name
+	Session  string // tmux session
+	Running  bool   // Its session exists
+	State    string // Agent state from its agent bead
+	Bead     string // Hooked bead
+	Title    string // Hooked bead's title
+	Step     string // Molecule step from its last heartbeat
+	Elapsed  time.Duration
+	Tokens   int64  // Last token count its pane showed
+	LastLine string // Last line of output
+}
+
+// Action is something done to a polecat.
+type Action string
+
+// Actions on the selected polecat.
+const (
+	ActionKill     Action = "kill"
+	ActionRestart  Action = "restart"
+	ActionEscalate Action = "escalate"
+)
+
+// Source supplies the rows and carries out actions.
+type Source interface {
+	Rows() ([]Row, error)
+	Peek(row Row) (string, error)
+	Act(action Action, row Row) error
+}
+
+// Model is the bubbletea model for the top TUI.
+type Model struct {
+	source   Source
+	interval time.Duration
+
+	rows      []Row
+	cursor    int
+	err       error
+	fetching  bool
+	refreshed time.Time
+
+	peeking  bool
+	peekText string
+	confirm  Action // Action awaiting y/n
+	message  string // Outcome of the last action
+
+	// UI state
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
+}
+
+// New creates a top model refreshing from source every interval.
+func New(source Source, interval time.Duration) Model {
+	return Model{
+		source:   source,
+		interval: interval,
+		fetching: true,
+		keys:     DefaultKeyMap(),
+		help:     help.New(),
+	}
+}
+
+type rowsMsg struct {
+	rows []Row
+	err  error
+}
+
+type peekMsg struct {
+	address string
+	text    string
+	err     error
+}
+
+type actionMsg struct {
+	action Action
+	row    Row
+	err    error
+}
+
+type tickMsg time.Time
+
+// Init initializes the model.
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.fetch(), m.tick())
+}
+
+func (m Model) fetch() tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		rows, err := source.Rows()
+		return rowsMsg{rows: rows, err: err}
+	}
+}
+
+func (m Model) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+func (m Model) peek(row Row) tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		text, err := source.Peek(row)
+		return peekMsg{address: row.Address, text: text, err: err}
+	}
+}
+
+func (m Model) act(action Action, row Row) tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		return actionMsg{action: action, row: row, err: source.Act(action, row)}
+	}
+}
+
+// selected returns the row under the cursor.
+func (m Model) selected() (Row, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return Row{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// Update handles messages.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		return m, nil
+
+	case tickMsg:
+		cmds := []tea.Cmd{m.tick()}
+		if !m.fetching {
+			m.fetching = true
+			cmds = append(cmds, m.fetch())
+		}
+		if row, ok := m.selected(); ok && m.peeking {
+			cmds = append(cmds, m.peek(row))
+		}
+		return m, tea.Batch(cmds...)
+
+	case rowsMsg:
+		m.fetching = false
+		m.err = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+		// Keep the cursor on the same polecat as rows come and go
+		current, _ := m.selected()
+		m.rows = msg.rows
+		m.refreshed = time.Now()
+		m.cursor = min(m.cursor, max(len(m.rows)-1, 0))
+		for i, r := range m.rows {
+			if r.Address == current.Address {
+				m.cursor = i
+			}
+		}
+		return m, nil
+
+	case peekMsg:
+		if row, ok := m.selected(); ok && row.Address == msg.address {
+			m.peekText = msg.text
+			if msg.err != nil {
+				m.peekText = fmt.Sprintf("Can't capture %s: %v", msg.address, msg.err)
+			}
+		}
+		return m, nil
+
+	case actionMsg:
+		if msg.err != nil {
+			m.message = fmt.Sprintf("%s %s failed: %v", msg.action, msg.row.Address, msg.err)
+		} else {
+			m.message = fmt.Sprintf("%s %s: done", msg.action, msg.row.Address)
+		}
+		if !m.fetching {
+			m.fetching = true
+			return m, m.fetch()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.updateKey(msg)
+	}
+	return m, nil
+}
+
+func (m Model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirm != "" {
+		action := m.confirm
+		m.confirm = ""
+		if row, ok := m.selected(); ok && key.Matches(msg, m.keys.Confirm) {
+			m.message = fmt.Sprintf("%s %s...", action, row.Address)
+			return m, m.act(action, row)
+		}
+		m.message = ""
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = !m.showHelp
+
+	case key.Matches(msg, m.keys.Cancel):
+		m.peeking = false
+
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+			m.peekText = ""
+			return m, m.repeek()
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+			m.peekText = ""
+			return m, m.repeek()
+		}
+
+	case key.Matches(msg, m.keys.Peek):
+		if row, ok := m.selected(); ok {
+			m.peeking = !m.peeking
+			m.peekText = ""
+			if m.peeking {
+				return m, m.peek(row)
+			}
+		}
+
+	case key.Matches(msg, m.keys.Refresh):
+		if !m.fetching {
+			m.fetching = true
+			return m, m.fetch()
+		}
+
+	case key.Matches(msg, m.keys.Kill):
+		m.ask(ActionKill)
+	case key.Matches(msg, m.keys.Restart):
+		m.ask(ActionRestart)
+	case key.Matches(msg, m.keys.Escalate):
+		m.ask(ActionEscalate)
+	}
+	return m, nil
+}
+
+// ask starts confirming an action on the selected polecat.
+func (m *Model) ask(action Action) {
+	if row, ok := m.selected(); ok {
+		m.confirm = action
+		m.message = fmt.Sprintf("%s %s? (y/n)", action, row.Address)
+	}
+}
+
+// repeek refreshes the peek pane after the cursor moved.
+func (m Model) repeek() tea.Cmd {
+	row, ok := m.selected()
+	if !ok || !m.peeking {
+		return nil
+	}
+	return m.peek(row)
+}
+
+// View renders the model.
+func (m Model) View() string {
+	return m.renderView()
+}
@@ -0,0 +1,95 @@
+package top
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type fakeSource struct {
+	rows  []Row
+	acted []string
+}
+
+func (s *fakeSource) Rows() ([]Row, error)         { return s.rows, nil }
+func (s *fakeSource) Peek(row Row) (string, error) { return "pane of " + row.Address, nil }
+func (s *fakeSource) Act(action Action, row Row) error {
+	s.acted = append(s.acted, string(action)+" "+row.Address)
+	return nil
+}
+
+// run feeds msg to m and then every message its commands produce, except
+// ticks, which would never end.
+func run(m Model, msg tea.Msg) Model {
+	queue := []tea.Msg{msg}
+	for len(queue) > 0 {
+		next, cmd := m.Update(queue[0])
+		m, queue = next.(Model), queue[1:]
+		if cmd == nil {
+			continue
+		}
+		switch out := cmd().(type) {
+		case tea.BatchMsg:
+			for _, c := range out {
+				if c == nil {
+					continue
+				}
+				if msg := c(); msg != nil {
+					if _, tick := msg.(tickMsg); !tick {
+						queue = append(queue, msg)
+					}
+				}
+			}
+		case tickMsg, tea.QuitMsg:
+		default:
+			queue = append(queue, out)
+		}
+	}
+	return m
+}
+
+func keys(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestTopConfirmsActions(t *testing.T) {
+	src := &fakeSource{rows: []Row{{Address: "gastown/Toast", Running: true}, {Address: "gastown/Nux", Running: true}}}
+	m := run(New(src, time.Second), rowsMsg{rows: src.rows})
+
+	m = run(m, keys("j"))
+	m = run(m, keys("x"))
+	if len(src.acted) != 0 || !strings.Contains(m.message, "(y/n)") {
+		t.Fatalf("x should ask first: acted=%v message=%q", src.acted, m.message)
+	}
+	m = run(m, keys("y"))
+	if len(src.acted) != 1 || src.acted[0] != "kill gastown/Nux" {
+		t.Errorf("acted = %v, want [kill gastown/Nux]", src.acted)
+	}
+
+	m = run(m, keys("e"))
+	m = run(m, keys("n"))
+	if len(src.acted) != 1 {
+		t.Errorf("n should cancel: acted = %v", src.acted)
+	}
+
+	m = run(m, keys("p"))
+	if !m.peeking || m.peekText != "pane of gastown/Nux" {
+		t.Errorf("peek = %v %q", m.peeking, m.peekText)
+	}
+}
+
+func TestTopKeepsSelectionAcrossRefresh(t *testing.T) {
+	src := &fakeSource{}
+	m := run(New(src, time.Second), rowsMsg{rows: []Row{{Address: "a/One"}, {Address: "a/Two"}}})
+	m = run(m, keys("j"))
+	m = run(m, rowsMsg{rows: []Row{{Address: "a/Zero"}, {Address: "a/One"}, {Address: "a/Two"}}})
+	if row, _ := m.selected(); row.Address != "a/Two" {
+		t.Errorf("selected %s after refresh, want a/Two", row.Address)
+	}
+	m = run(m, rowsMsg{rows: []Row{{Address: "a/Zero"}}})
+	if row, _ := m.selected(); row.Address != "a/Zero" {
+		t.Errorf("selected %s after its row went, want a/Zero", row.Address)
+	}
+}
@@ -0,0 +1,179 @@
+package top
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+// Styles for the top TUI, drawn from the shared ui palette so GT_THEME and
+// NO_COLOR apply here too.
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(ui.ColorAccent)
+
+	selectedStyle = ui.SelectedStyle
+
+	headerStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(ui.ColorMuted)
+
+	mutedStyle = lipgloss.NewStyle().
+			Foreground(ui.ColorMuted)
+
+	deadStyle = lipgloss.NewStyle().
+			Foreground(ui.ColorFail)
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(ui.ColorFail)
+)
+
+// rowFormat lays out the table; the last output line takes what's left.
+const rowFormat = "%-22s %-9s %-12s %-18s %8s %8s  %s"
+
+// renderView renders the entire view.
+func (m Model) renderView() string {
+	var b strings.Builder
+
+	working := 0
+	for _, r := range m.rows {
+		if r.Bead != "" {
+			working++
+		}
+	}
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Gas Town · %d polecats, %d working", len(m.rows), working)))
+	if !m.refreshed.IsZero() {
+		b.WriteString(mutedStyle.Render("  refreshed " + m.refreshed.Format("15:04:05")))
+	}
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString("\n\n")
+	}
+
+	if len(m.rows) == 0 {
+		if m.fetching && m.refreshed.IsZero() {
+			b.WriteString("Loading...\n")
+		} else {
+			b.WriteString("No polecats.\n")
+			b.WriteString("Spawn one with: gt sling <bead> <rig>\n")
+		}
+	} else {
+		b.WriteString(headerStyle.Render(m.fit(fmt.Sprintf(rowFormat, "POLECAT", "STATE", "BEAD", "STEP", "ELAPSED", "TOKENS", "LAST OUTPUT"))))
+		b.WriteString("\n")
+		for i, r := range m.rows {
+			line := m.fit(formatRow(r))
+			switch {
+			case i == m.cursor:
+				line = selectedStyle.Render(line)
+			case !r.Running:
+				line = deadStyle.Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if m.peeking {
+		if row, ok := m.selected(); ok {
+			b.WriteString("\n")
+			b.WriteString(titleStyle.Render("── " + row.Address + " "))
+			b.WriteString("\n")
+			b.WriteString(m.peekPane())
+		}
+	}
+
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.message + "\n")
+	}
+	if m.showHelp {
+		b.WriteString(m.help.View(m.keys))
+	} else {
+		b.WriteString(mutedStyle.Render("j/k:select  enter:peek  x:kill  r:restart  e:escalate  q:quit  ?:help"))
+	}
+	return b.String()
+}
+
+// formatRow renders one polecat.
+func formatRow(r Row) string {
+	state := r.State
+	if !r.Running {
+		state = "dead"
+	} else if state == "" {
+		state = "idle"
+	}
+	elapsed, tokens := "-", "-"
+	if r.Elapsed > 0 {
+		elapsed = formatElapsed(r.Elapsed)
+	}
+	if r.Tokens > 0 {
+		tokens = formatTokens(r.Tokens)
+	}
+	return fmt.Sprintf(rowFormat,
+		truncate(r.Address, 22), truncate(state, 9), truncate(r.Bead, 12),
+		truncate(r.Step, 18), elapsed, tokens, r.LastLine)
+}
+
+// peekPane is the tail of the selected polecat's pane that fits below the
+// table.
+func (m Model) peekPane() string {
+	if m.peekText == "" {
+		return mutedStyle.Render("capturing...") + "\n"
+	}
+	lines := strings.Split(strings.TrimRight(m.peekText, "\n"), "\n")
+	room := m.height - len(m.rows) - 10
+	if room < 5 {
+		room = 5
+	}
+	if len(lines) > room {
+		lines = lines[len(lines)-room:]
+	}
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(m.fit(l) + "\n")
+	}
+	return b.String()
+}
+
+// fit cuts a line to the terminal width.
+func (m Model) fit(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+	return truncate(s, m.width)
+}
+
+// formatElapsed formats a duration as h:mm:ss.
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// formatTokens formats a token count compactly (e.g., "12.3k", "1.2M").
+func formatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// truncate shortens a string to the given rune length, preserving UTF-8.
+func truncate(s string, maxLen int) string {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	runes := []rune(s)
+	if maxLen <= 3 {
+		return "..."
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
@@ -0,0 +1,83 @@
+package triage
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap defines the key bindings for the triage TUI.
+type KeyMap struct {
+	Next     key.Binding
+	Prev     key.Binding
+	Priority key.Binding // 0-4
+	Label    key.Binding
+	Molecule key.Binding
+	Rig      key.Binding
+	Icebox   key.Binding
+	Undo     key.Binding
+	Help     key.Binding
+	Finish   key.Binding // Apply decisions and quit
+	Abort    key.Binding // Quit without applying
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Next: key.NewBinding(
+			key.WithKeys("down", "j", "n", "enter", " "),
+			key.WithHelp("j/enter", "next"),
+		),
+		Prev: key.NewBinding(
+			key.WithKeys("up", "k", "p"),
+			key.WithHelp("k", "previous"),
+		),
+		Priority: key.NewBinding(
+			key.WithKeys("0", "1", "2", "3", "4"),
+			key.WithHelp("0-4", "priority"),
+		),
+		Label: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "add label"),
+		),
+		Molecule: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "molecule"),
+		),
+		Rig: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "rig"),
+		),
+		Icebox: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "icebox"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "help"),
+		),
+		Finish: key.NewBinding(
+			key.WithKeys("q"),
+			key.WithHelp("q", "apply and quit"),
+		),
+		Abort: key.NewBinding(
+			key.WithKeys("ctrl+c"),
+			key.WithHelp("ctrl+c", "quit without applying"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to show in the help view.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Next, k.Priority, k.Label, k.Molecule, k.Rig, k.Icebox, k.Finish, k.Help}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Next, k.Prev},
+		{k.Priority, k.Label, k.Molecule, k.Rig},
+		{k.Icebox, k.Undo},
+		{k.Help, k.Finish, k.Abort},
+	}
+}
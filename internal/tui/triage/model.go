@@ -0,0 +1,269 @@
+// Package triage is the interactive inbox behind gt triage: step through new
+// beads one at a time and decide each one's priority, labels, molecule and
+// rig with single keystrokes, or dismiss it to the icebox. Nothing is
+// written while triaging; the caller applies Decisions once the user
+// finishes.
+package triage
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Item is a bead waiting to be triaged.
+type Item struct {
+	ID          string
+	Title       string
+	Type        string
+	Description string
+	Priority    int
+	Labels      []string
+	Rig         string // Rig whose beads hold it, "" for the town's
+}
+
+// Decision is what the user decided for one item.
+type Decision struct {
+	Priority *int
+	Labels   []string // Labels to add
+	Molecule string   // Formula to work it with
+	Rig      string   // Rig to work it in
+	Icebox   bool     // Dismissed
+}
+
+// Empty reports whether nothing was decided.
+func (d Decision) Empty() bool {
+	return d.Priority == nil && len(d.Labels) == 0 && d.Molecule == "" && d.Rig == "" && !d.Icebox
+}
+
+// Triaged is an item with the decision made for it.
+type Triaged struct {
+	Item     Item
+	Decision Decision
+}
+
+// mode is what keystrokes currently go to.
+type mode int
+
+const (
+	modeBrowse   mode = iota
+	modeMolecule      // Picking from molecules
+	modeRig           // Picking from rigs
+	modeLabel         // Typing a label
+)
+
+// Model is the bubbletea model for the triage TUI.
+type Model struct {
+	items     []Item
+	decisions []Decision
+	cursor    int
+	molecules []string
+	rigs      []string
+
+	mode     mode
+	pick     int    // Picker selection
+	input    []rune // Label being typed
+	finished bool
+
+	// UI state
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
+}
+
+// New creates a triage model over items. molecules and rigs are the
+// choices offered by the m and r pickers.
+func New(items []Item, molecules, rigs []string) Model {
+	return Model{
+		items:     items,
+		decisions: make([]Decision, len(items)),
+		molecules: molecules,
+		rigs:      rigs,
+		keys:      DefaultKeyMap(),
+		help:      help.New(),
+	}
+}
+
+// Init initializes the model.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Finished reports whether the user quit with q, asking for the decisions
+// to be applied, rather than aborting.
+func (m Model) Finished() bool {
+	return m.finished
+}
+
+// Decisions returns the items something was decided for, in order.
+func (m Model) Decisions() []Triaged {
+	var out []Triaged
+	for i, d := range m.decisions {
+		if !d.Empty() {
+			out = append(out, Triaged{Item: m.items[i], Decision: d})
+		}
+	}
+	return out
+}
+
+// Update handles messages.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		return m, nil
+
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Abort) {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case modeLabel:
+			return m.updateLabel(msg)
+		case modeMolecule, modeRig:
+			return m.updatePicker(msg)
+		}
+		return m.updateBrowse(msg)
+	}
+	return m, nil
+}
+
+func (m Model) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if len(m.items) == 0 {
+		if key.Matches(msg, m.keys.Finish) {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+	d := &m.decisions[m.cursor]
+
+	switch {
+	case key.Matches(msg, m.keys.Finish):
+		m.finished = true
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = !m.showHelp
+
+	case key.Matches(msg, m.keys.Next):
+		m.advance()
+
+	case key.Matches(msg, m.keys.Prev):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case key.Matches(msg, m.keys.Priority):
+		p := int(msg.String()[0] - '0')
+		d.Priority = &p
+
+	case key.Matches(msg, m.keys.Icebox):
+		d.Icebox = !d.Icebox
+		if d.Icebox {
+			m.advance()
+		}
+
+	case key.Matches(msg, m.keys.Undo):
+		*d = Decision{}
+
+	case key.Matches(msg, m.keys.Label):
+		m.mode, m.input = modeLabel, nil
+
+	case key.Matches(msg, m.keys.Molecule):
+		if len(m.molecules) > 0 {
+			m.mode, m.pick = modeMolecule, 0
+		}
+
+	case key.Matches(msg, m.keys.Rig):
+		if len(m.rigs) > 0 {
+			m.mode, m.pick = modeRig, 0
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateLabel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = modeBrowse
+	case tea.KeyEnter:
+		d := &m.decisions[m.cursor]
+		if l := strings.TrimSpace(string(m.input)); l != "" && !slices.Contains(d.Labels, l) {
+			d.Labels = append(d.Labels, l)
+		}
+		m.mode = modeBrowse
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case tea.KeyRunes:
+		// Labels are single words
+		for _, r := range msg.Runes {
+			if r != ' ' {
+				m.input = append(m.input, r)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	choices := m.choices()
+	switch s := msg.String(); {
+	case msg.Type == tea.KeyEsc:
+		m.mode = modeBrowse
+	case s == "up" || s == "k":
+		if m.pick > 0 {
+			m.pick--
+		}
+	case s == "down" || s == "j":
+		if m.pick < len(choices)-1 {
+			m.pick++
+		}
+	case msg.Type == tea.KeyEnter:
+		m.choose(choices[m.pick])
+	case len(s) == 1 && s >= "1" && s <= "9":
+		if n := int(s[0] - '1'); n < len(choices) {
+			m.choose(choices[n])
+		}
+	}
+	return m, nil
+}
+
+// choices are what the open picker offers.
+func (m Model) choices() []string {
+	if m.mode == modeRig {
+		return m.rigs
+	}
+	return m.molecules
+}
+
+// choose records the picker's choice and closes it.
+func (m *Model) choose(choice string) {
+	d := &m.decisions[m.cursor]
+	if m.mode == modeRig {
+		d.Rig = choice
+	} else {
+		d.Molecule = choice
+	}
+	m.mode = modeBrowse
+}
+
+// advance moves to the next item, staying on the last.
+func (m *Model) advance() {
+	if m.cursor < len(m.items)-1 {
+		m.cursor++
+	}
+}
+
+// View renders the model.
+func (m Model) View() string {
+	return m.renderView()
+}
@@ -0,0 +1,65 @@
+package triage
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func press(m Model, keys ...string) Model {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		next, _ := m.Update(msg)
+		m = next.(Model)
+	}
+	return m
+}
+
+func TestTriageKeystrokes(t *testing.T) {
+	items := []Item{{ID: "gt-1"}, {ID: "gt-2"}, {ID: "gt-3"}}
+	m := New(items, []string{"shiny", "mol-polecat-work"}, []string{"gastown", "beads"})
+
+	// gt-1: P1, molecule 2, rig beads, label "ui"; gt-2: icebox; gt-3: nothing
+	m = press(m, "1", "m", "2", "r", "j", "enter", "l", "u", "i", "enter", "n", "i", "q")
+
+	if !m.Finished() {
+		t.Fatal("q should finish")
+	}
+	got := m.Decisions()
+	if len(got) != 2 {
+		t.Fatalf("Decisions = %+v, want 2", got)
+	}
+	d := got[0].Decision
+	if got[0].Item.ID != "gt-1" || d.Priority == nil || *d.Priority != 1 || d.Molecule != "mol-polecat-work" || d.Rig != "beads" {
+		t.Errorf("gt-1 decision = %+v", d)
+	}
+	if len(d.Labels) != 1 || d.Labels[0] != "ui" {
+		t.Errorf("gt-1 labels = %v, want [ui]", d.Labels)
+	}
+	if got[1].Item.ID != "gt-2" || !got[1].Decision.Icebox {
+		t.Errorf("gt-2 decision = %+v, want icebox", got[1])
+	}
+}
+
+func TestTriageUndoAndAbort(t *testing.T) {
+	m := New([]Item{{ID: "gt-1"}}, nil, nil)
+	m = press(m, "3", "u", "m")
+	if len(m.Decisions()) != 0 {
+		t.Errorf("undo left %+v", m.Decisions())
+	}
+	if m.mode != modeBrowse {
+		t.Error("m with no molecules should not open a picker")
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if next.(Model).Finished() {
+		t.Error("ctrl+c should abort, not finish")
+	}
+}
@@ -0,0 +1,155 @@
+package triage
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+// descriptionLines is how much of a bead's description is shown.
+const descriptionLines = 8
+
+// Styles for the triage TUI, drawn from the shared ui palette so GT_THEME
+// and NO_COLOR apply here too.
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(ui.ColorAccent)
+
+	selectedStyle = ui.SelectedStyle
+
+	mutedStyle = lipgloss.NewStyle().
+			Foreground(ui.ColorMuted)
+
+	decisionStyle = lipgloss.NewStyle().
+			Foreground(ui.ColorPass)
+
+	iceboxStyle = lipgloss.NewStyle().
+			Foreground(ui.ColorWarn)
+)
+
+// renderView renders the entire view.
+func (m Model) renderView() string {
+	var b strings.Builder
+
+	if len(m.items) == 0 {
+		b.WriteString(titleStyle.Render("Triage"))
+		b.WriteString("\n\nNothing to triage.\n\n")
+		b.WriteString(mutedStyle.Render("q:quit"))
+		return b.String()
+	}
+
+	decided := 0
+	for _, d := range m.decisions {
+		if !d.Empty() {
+			decided++
+		}
+	}
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Triage %d/%d", m.cursor+1, len(m.items))))
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("  (%d decided)", decided)))
+	b.WriteString("\n\n")
+
+	item := m.items[m.cursor]
+	where := item.Rig
+	if where == "" {
+		where = "town"
+	}
+	fmt.Fprintf(&b, "%s  %s\n", selectedStyle.Render(item.ID), item.Title)
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("%s · P%d · %s", item.Type, item.Priority, where)))
+	b.WriteString("\n")
+	if len(item.Labels) > 0 {
+		b.WriteString(mutedStyle.Render("labels: " + strings.Join(item.Labels, ", ")))
+		b.WriteString("\n")
+	}
+	if desc := strings.TrimSpace(item.Description); desc != "" {
+		b.WriteString("\n")
+		lines := strings.Split(desc, "\n")
+		if len(lines) > descriptionLines {
+			lines = append(lines[:descriptionLines], "...")
+		}
+		for _, l := range lines {
+			b.WriteString("  " + truncate(l, m.lineWidth()) + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(renderDecision(m.decisions[m.cursor]))
+	b.WriteString("\n")
+
+	switch m.mode {
+	case modeLabel:
+		b.WriteString("\nlabel: " + string(m.input) + ui.Glyph("▏", "_") + "\n")
+		b.WriteString(mutedStyle.Render("enter:add  esc:cancel"))
+		return b.String()
+	case modeMolecule, modeRig:
+		b.WriteString("\n")
+		for i, c := range m.choices() {
+			line := fmt.Sprintf("  %s", c)
+			if i < 9 {
+				line = fmt.Sprintf("%d %s", i+1, c)
+			}
+			if i == m.pick {
+				line = selectedStyle.Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString(mutedStyle.Render("1-9/enter:choose  esc:cancel"))
+		return b.String()
+	}
+
+	// Help footer
+	b.WriteString("\n")
+	if m.showHelp {
+		b.WriteString(m.help.View(m.keys))
+	} else {
+		b.WriteString(mutedStyle.Render("0-4:priority  l:label  m:molecule  r:rig  i:icebox  u:undo  j/k:move  q:apply  ?:help"))
+	}
+	return b.String()
+}
+
+// renderDecision summarizes what has been decided for an item.
+func renderDecision(d Decision) string {
+	if d.Icebox {
+		return iceboxStyle.Render(ui.Glyph("❄", "*") + " icebox")
+	}
+	if d.Empty() {
+		return mutedStyle.Render("undecided")
+	}
+	var parts []string
+	if d.Priority != nil {
+		parts = append(parts, fmt.Sprintf("P%d", *d.Priority))
+	}
+	if d.Molecule != "" {
+		parts = append(parts, "molecule "+d.Molecule)
+	}
+	if d.Rig != "" {
+		parts = append(parts, "rig "+d.Rig)
+	}
+	if len(d.Labels) > 0 {
+		parts = append(parts, "+"+strings.Join(d.Labels, " +"))
+	}
+	return decisionStyle.Render(ui.Glyph("→", "->") + " " + strings.Join(parts, " · "))
+}
+
+// lineWidth is how wide description lines may be.
+func (m Model) lineWidth() int {
+	if m.width > 10 {
+		return m.width - 4
+	}
+	return 100
+}
+
+// truncate shortens a string to the given rune length, preserving UTF-8.
+func truncate(s string, maxLen int) string {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	runes := []rune(s)
+	if maxLen <= 3 {
+		return "..."
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
@@ -0,0 +1,129 @@
+package tutorial
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Action is one step of a mock agent's script. Each field is optional:
+// Say prints a transcript line, Path/Content writes a file, and Commit
+// stages everything and commits with that message.
+type Action struct {
+	Say     string
+	Path    string
+	Content string
+	Commit  string
+}
+
+// MockAgent replays a scripted session in a polecat worktree, standing in
+// for a real coding agent.
+type MockAgent struct {
+	Name    string
+	WorkDir string
+	Out     io.Writer     // transcript destination (nil discards)
+	Delay   time.Duration // pause between actions, to make the run watchable
+}
+
+// Run executes the script, stopping early if ctx is cancelled.
+func (a *MockAgent) Run(ctx context.Context, script []Action) error {
+	g := git.NewGit(a.WorkDir)
+	for _, act := range script {
+		if a.Delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(a.Delay):
+			}
+		}
+		if act.Say != "" && a.Out != nil {
+			_, _ = fmt.Fprintf(a.Out, "[%s] %s\n", a.Name, act.Say)
+		}
+		if act.Path != "" {
+			if err := writeFile(a.WorkDir, act.Path, act.Content); err != nil {
+				return fmt.Errorf("writing %s: %w", act.Path, err)
+			}
+		}
+		if act.Commit != "" {
+			if err := g.Add("."); err != nil {
+				return err
+			}
+			if err := g.Commit(act.Commit); err != nil {
+				return fmt.Errorf("committing: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// DemoBead is the fixture work item the tutorial slings.
+const DemoBead = "tut-1"
+
+// DemoTitle is the fixture work item's title.
+const DemoTitle = "Greet the user by name"
+
+// DemoScript is the mock polecat's session for DemoBead.
+func DemoScript() []Action {
+	return []Action{
+		{Say: "gt prime: I'm polecat toast in rig demo. Checking my hook..."},
+		{Say: fmt.Sprintf("Hooked: %s %q. Reading main.go.", DemoBead, DemoTitle)},
+		{Say: "Plan: add Greeting(name) with a test, then call it from main."},
+		{
+			Say:  "Writing greeting.go",
+			Path: "greeting.go",
+			Content: `package main
+
+import "fmt"
+
+// Greeting returns a friendly greeting for name.
+func Greeting(name string) string {
+	if name == "" {
+		name = "world"
+	}
+	return fmt.Sprintf("hello, %s", name)
+}
+`,
+		},
+		{
+			Say:  "Writing greeting_test.go",
+			Path: "greeting_test.go",
+			Content: `package main
+
+import "testing"
+
+func TestGreeting(t *testing.T) {
+	if got := Greeting("mayor"); got != "hello, mayor" {
+		t.Errorf("Greeting = %q", got)
+	}
+	if got := Greeting(""); got != "hello, world" {
+		t.Errorf("Greeting(\"\") = %q", got)
+	}
+}
+`,
+		},
+		{
+			Say:  "Updating main.go to use Greeting",
+			Path: "main.go",
+			Content: `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	name := ""
+	if len(os.Args) > 1 {
+		name = os.Args[1]
+	}
+	fmt.Println(Greeting(name))
+}
+`,
+		},
+		{Say: "Running tests... ok", Commit: fmt.Sprintf("Add Greeting (%s)", DemoBead)},
+		{Say: "gt done: branch polecat/toast submitted to the merge queue."},
+	}
+}
@@ -0,0 +1,209 @@
+// Package tutorial provides the sandbox fixture town and scripted mock
+// agent behind 'gt tutorial'.
+//
+// The sandbox mirrors a real town's layout (mayor/town.json, a rig with a
+// mayor/rig clone and polecat worktrees) but needs no beads database, tmux
+// or model credentials: the mock agent replays a fixed script of edits and
+// commits, so the walkthrough is free and deterministic.
+package tutorial
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Fixture names used by the sandbox.
+const (
+	TownName = "tutorial"
+	RigName  = "demo"
+	Branch   = "main"
+)
+
+// Sandbox is a throwaway fixture town.
+type Sandbox struct {
+	Root string // town root
+}
+
+// RigPath returns the demo rig directory.
+func (s *Sandbox) RigPath() string {
+	return filepath.Join(s.Root, RigName)
+}
+
+// RepoPath returns the rig's canonical clone (<rig>/mayor/rig).
+func (s *Sandbox) RepoPath() string {
+	return filepath.Join(s.RigPath(), "mayor", "rig")
+}
+
+// PolecatPath returns a polecat's worktree (<rig>/polecats/<name>/<rig>).
+func (s *Sandbox) PolecatPath(name string) string {
+	return filepath.Join(s.RigPath(), "polecats", name, RigName)
+}
+
+// PolecatBranch returns the branch a polecat works on.
+func PolecatBranch(name string) string {
+	return "polecat/" + name
+}
+
+// Create builds a fixture town in dir, which must not exist or be empty.
+func Create(dir string) (*Sandbox, error) {
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s is not empty", dir)
+	}
+	s := &Sandbox{Root: dir}
+
+	mayorDir := filepath.Join(dir, constants.DirMayor)
+	if err := os.MkdirAll(mayorDir, 0755); err != nil {
+		return nil, err
+	}
+	townConfig := &config.TownConfig{
+		Type:      "town",
+		Version:   config.CurrentTownVersion,
+		Name:      TownName,
+		CreatedAt: time.Now(),
+	}
+	if err := config.SaveTownConfig(filepath.Join(mayorDir, "town.json"), townConfig); err != nil {
+		return nil, fmt.Errorf("writing town config: %w", err)
+	}
+
+	if err := s.initRepo(); err != nil {
+		return nil, fmt.Errorf("creating demo repo: %w", err)
+	}
+
+	rigsConfig := &config.RigsConfig{
+		Version: config.CurrentRigsVersion,
+		Rigs: map[string]config.RigEntry{
+			RigName: {GitURL: s.RepoPath(), AddedAt: time.Now()},
+		},
+	}
+	if err := config.SaveRigsConfig(filepath.Join(mayorDir, constants.FileRigsJSON), rigsConfig); err != nil {
+		return nil, fmt.Errorf("writing rigs config: %w", err)
+	}
+	return s, nil
+}
+
+// initRepo creates the demo project with one commit on main.
+func (s *Sandbox) initRepo() error {
+	repo := s.RepoPath()
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		return err
+	}
+	// Local identity so commits work on machines without a global one
+	for _, args := range [][]string{
+		{"init", "-q", "-b", Branch},
+		{"config", "user.name", "Gas Town Tutorial"},
+		{"config", "user.email", "tutorial@gastown.invalid"},
+	} {
+		if _, err := runGit(repo, args...); err != nil {
+			return err
+		}
+	}
+	for path, content := range demoProject {
+		if err := writeFile(repo, path, content); err != nil {
+			return err
+		}
+	}
+	g := git.NewGit(repo)
+	if err := g.Add("."); err != nil {
+		return err
+	}
+	return g.Commit("Initial commit")
+}
+
+// Spawn creates a polecat worktree on a fresh branch and returns its agent.
+func (s *Sandbox) Spawn(name string) (*MockAgent, error) {
+	path := s.PolecatPath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := git.NewGit(s.RepoPath()).WorktreeAdd(path, PolecatBranch(name)); err != nil {
+		return nil, fmt.Errorf("creating worktree: %w", err)
+	}
+	return &MockAgent{Name: name, WorkDir: path}, nil
+}
+
+// Review returns the diffstat and diff a polecat's branch would merge.
+func (s *Sandbox) Review(name string) (stat, diff string, err error) {
+	rng := Branch + "..." + PolecatBranch(name)
+	if stat, err = runGit(s.RepoPath(), "diff", "--stat", rng); err != nil {
+		return "", "", err
+	}
+	if diff, err = runGit(s.RepoPath(), "diff", rng); err != nil {
+		return "", "", err
+	}
+	return stat, diff, nil
+}
+
+// Land merges a polecat's branch into main the way the refinery does
+// (a no-fast-forward merge commit) and removes the polecat worktree.
+func (s *Sandbox) Land(name, message string) (string, error) {
+	g := git.NewGit(s.RepoPath())
+	if err := g.Checkout(Branch); err != nil {
+		return "", err
+	}
+	if err := g.MergeNoFF(PolecatBranch(name), message); err != nil {
+		return "", fmt.Errorf("merging: %w", err)
+	}
+	commit, err := g.Rev("HEAD")
+	if err != nil {
+		return "", err
+	}
+	if err := g.WorktreeRemove(s.PolecatPath(name), true); err != nil {
+		return commit, fmt.Errorf("removing worktree: %w", err)
+	}
+	_ = os.Remove(filepath.Dir(s.PolecatPath(name)))
+	_ = g.DeleteBranch(PolecatBranch(name), false)
+	return commit, nil
+}
+
+// Log returns the one-line history of main.
+func (s *Sandbox) Log() (string, error) {
+	return runGit(s.RepoPath(), "log", "--oneline", "--graph", Branch)
+}
+
+// Remove deletes the sandbox.
+func (s *Sandbox) Remove() error {
+	return os.RemoveAll(s.Root)
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func writeFile(dir, path, content string) error {
+	full := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(full, []byte(content), 0644) //nolint:gosec // G306: sandbox files are not sensitive
+}
+
+// demoProject is the fixture rig's initial content.
+var demoProject = map[string]string{
+	"README.md": "# demo\n\nA tiny project for the Gas Town tutorial.\n",
+	"go.mod":    "module example.com/demo\n\ngo 1.21\n",
+	"main.go": `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+}
+`,
+}
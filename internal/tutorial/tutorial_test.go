@@ -0,0 +1,68 @@
+package tutorial
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSandboxWalkthrough(t *testing.T) {
+	s, err := Create(filepath.Join(t.TempDir(), "town"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(s.Root, "mayor", "town.json")); err != nil {
+		t.Errorf("missing town.json: %v", err)
+	}
+
+	agent, err := s.Spawn("toast")
+	if err != nil {
+		t.Fatalf("Spawn: %v", err)
+	}
+	var transcript bytes.Buffer
+	agent.Out = &transcript
+	if err := agent.Run(context.Background(), DemoScript()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.Contains(transcript.String(), "[toast] gt done") {
+		t.Errorf("transcript missing done line:\n%s", transcript.String())
+	}
+
+	stat, diff, err := s.Review("toast")
+	if err != nil {
+		t.Fatalf("Review: %v", err)
+	}
+	if !strings.Contains(stat, "greeting.go") || !strings.Contains(diff, "func Greeting") {
+		t.Errorf("unexpected review:\n%s\n%s", stat, diff)
+	}
+
+	if _, err := s.Land("toast", "Merge polecat/toast"); err != nil {
+		t.Fatalf("Land: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(s.RepoPath(), "greeting.go")); err != nil {
+		t.Errorf("greeting.go not on main: %v", err)
+	}
+	if _, err := os.Stat(s.PolecatPath("toast")); !os.IsNotExist(err) {
+		t.Errorf("polecat worktree not removed")
+	}
+	log, err := s.Log()
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if !strings.Contains(log, "Merge polecat/toast") {
+		t.Errorf("log missing merge commit:\n%s", log)
+	}
+}
+
+func TestCreateRejectsNonEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "x"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Create(dir); err == nil {
+		t.Error("expected error for non-empty dir")
+	}
+}
@@ -9,127 +9,124 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/muesli/termenv"
 )
 
 func init() {
-	if !ShouldUseColor() {
-		// disable colors when not appropriate (non-TTY, NO_COLOR, etc.)
-		lipgloss.SetColorProfile(termenv.Ascii)
-	} else {
-		// use TrueColor for distinct priority/status colors in modern terminals
-		lipgloss.SetColorProfile(termenv.TrueColor)
-	}
+	// Ascii for no-color (non-TTY, NO_COLOR, GT_THEME=no-color), otherwise
+	// the best profile the terminal advertises.
+	lipgloss.SetColorProfile(colorProfile())
 }
 
 // Ayu theme color palette
+// Each color carries a basic-ANSI replacement used by the high-contrast theme
+// (GT_THEME=high-contrast); an empty replacement means the terminal default.
 // Dark: https://terminalcolors.com/themes/ayu/dark/
 // Light: https://terminalcolors.com/themes/ayu/light/
 // Source: https://github.com/ayu-theme/ayu-colors
 var (
 	// Core semantic colors (Ayu theme - adaptive light/dark)
-	ColorPass = lipgloss.AdaptiveColor{
+	ColorPass = themed(lipgloss.AdaptiveColor{
 		Light: "#86b300", // ayu light bright green
 		Dark:  "#c2d94c", // ayu dark bright green
-	}
-	ColorWarn = lipgloss.AdaptiveColor{
+	}, "10")
+	ColorWarn = themed(lipgloss.AdaptiveColor{
 		Light: "#f2ae49", // ayu light bright yellow
 		Dark:  "#ffb454", // ayu dark bright yellow
-	}
-	ColorFail = lipgloss.AdaptiveColor{
+	}, "11")
+	ColorFail = themed(lipgloss.AdaptiveColor{
 		Light: "#f07171", // ayu light bright red
 		Dark:  "#f07178", // ayu dark bright red
-	}
-	ColorMuted = lipgloss.AdaptiveColor{
+	}, "9")
+	ColorMuted = themed(lipgloss.AdaptiveColor{
 		Light: "#828c99", // ayu light muted
 		Dark:  "#6c7680", // ayu dark muted
-	}
-	ColorAccent = lipgloss.AdaptiveColor{
+	}, "")
+	ColorAccent = themed(lipgloss.AdaptiveColor{
 		Light: "#399ee6", // ayu light bright blue
 		Dark:  "#59c2ff", // ayu dark bright blue
-	}
+	}, "14")
 
 	// === Workflow Status Colors ===
 	// Only actionable states get color - open/closed match standard text
-	ColorStatusOpen = lipgloss.AdaptiveColor{
+	ColorStatusOpen = themed(lipgloss.AdaptiveColor{
 		Light: "", // standard text color
 		Dark:  "",
-	}
-	ColorStatusInProgress = lipgloss.AdaptiveColor{
+	}, "")
+	ColorStatusInProgress = themed(lipgloss.AdaptiveColor{
 		Light: "#f2ae49", // yellow - active work, very visible
 		Dark:  "#ffb454",
-	}
-	ColorStatusClosed = lipgloss.AdaptiveColor{
+	}, "11")
+	ColorStatusClosed = themed(lipgloss.AdaptiveColor{
 		Light: "#9099a1", // slightly dimmed - visually shows "done"
 		Dark:  "#8090a0",
-	}
-	ColorStatusBlocked = lipgloss.AdaptiveColor{
+	}, "")
+	ColorStatusBlocked = themed(lipgloss.AdaptiveColor{
 		Light: "#f07171", // red - needs attention
 		Dark:  "#f26d78",
-	}
-	ColorStatusPinned = lipgloss.AdaptiveColor{
+	}, "9")
+	ColorStatusPinned = themed(lipgloss.AdaptiveColor{
 		Light: "#d2a6ff", // purple - special/elevated
 		Dark:  "#d2a6ff",
-	}
-	ColorStatusHooked = lipgloss.AdaptiveColor{
+	}, "13")
+	ColorStatusHooked = themed(lipgloss.AdaptiveColor{
 		Light: "#59c2ff", // cyan - actively worked by agent
 		Dark:  "#59c2ff",
-	}
+	}, "14")
 
 	// === Priority Colors ===
 	// P0/P1/P2 get color - they need attention
 	// P3/P4 are neutral (low/backlog don't need visual urgency)
-	ColorPriorityP0 = lipgloss.AdaptiveColor{
+	ColorPriorityP0 = themed(lipgloss.AdaptiveColor{
 		Light: "#f07171", // bright red - critical, demands attention
 		Dark:  "#f07178",
-	}
-	ColorPriorityP1 = lipgloss.AdaptiveColor{
+	}, "9")
+	ColorPriorityP1 = themed(lipgloss.AdaptiveColor{
 		Light: "#ff8f40", // orange - high priority, needs attention soon
 		Dark:  "#ff8f40",
-	}
-	ColorPriorityP2 = lipgloss.AdaptiveColor{
+	}, "11")
+	ColorPriorityP2 = themed(lipgloss.AdaptiveColor{
 		Light: "#e6b450", // muted gold - medium priority, visible but calm
 		Dark:  "#e6b450",
-	}
-	ColorPriorityP3 = lipgloss.AdaptiveColor{
+	}, "3")
+	ColorPriorityP3 = themed(lipgloss.AdaptiveColor{
 		Light: "", // neutral - low priority
 		Dark:  "",
-	}
-	ColorPriorityP4 = lipgloss.AdaptiveColor{
+	}, "")
+	ColorPriorityP4 = themed(lipgloss.AdaptiveColor{
 		Light: "", // neutral - backlog
 		Dark:  "",
-	}
+	}, "")
 
 	// === Issue Type Colors ===
 	// Bugs and epics get color - they need attention
 	// All other types use standard text
-	ColorTypeBug = lipgloss.AdaptiveColor{
+	ColorTypeBug = themed(lipgloss.AdaptiveColor{
 		Light: "#f07171", // bright red - bugs are problems
 		Dark:  "#f26d78",
-	}
-	ColorTypeFeature = lipgloss.AdaptiveColor{
+	}, "9")
+	ColorTypeFeature = themed(lipgloss.AdaptiveColor{
 		Light: "", // standard text color
 		Dark:  "",
-	}
-	ColorTypeTask = lipgloss.AdaptiveColor{
+	}, "")
+	ColorTypeTask = themed(lipgloss.AdaptiveColor{
 		Light: "", // standard text color
 		Dark:  "",
-	}
-	ColorTypeEpic = lipgloss.AdaptiveColor{
+	}, "")
+	ColorTypeEpic = themed(lipgloss.AdaptiveColor{
 		Light: "#d2a6ff", // purple - larger scope work
 		Dark:  "#d2a6ff",
-	}
-	ColorTypeChore = lipgloss.AdaptiveColor{
+	}, "13")
+	ColorTypeChore = themed(lipgloss.AdaptiveColor{
 		Light: "", // standard text color
 		Dark:  "",
-	}
+	}, "")
 
 	// === Issue ID Color ===
 	// IDs use standard text color - subtle, not attention-grabbing
-	ColorID = lipgloss.AdaptiveColor{
+	ColorID = themed(lipgloss.AdaptiveColor{
 		Light: "", // standard text color
 		Dark:  "",
-	}
+	}, "")
 )
 
 // Core styles - consistent across all commands
@@ -184,41 +181,51 @@ var CommandStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
 	Dark:  "#bfbdb6", // slightly brighter than standard
 })
 
+// ASCII replacements for GT_GLYPHS=ascii. One meaning gets one tag
+// everywhere (status icons, the feed, command output), so a pass never
+// reads like a failure.
+const (
+	ASCIIPass = "[ok]"
+	ASCIIFail = "[x]"
+	ASCIIWarn = "[!]"
+	ASCIIInfo = "[i]"
+)
+
 // Status icons - consistent semantic indicators
 // Design: small Unicode symbols, NOT emoji-style icons for visual consistency
-const (
-	IconPass = "✓"
-	IconWarn = "⚠"
-	IconFail = "✖"
+var (
+	IconPass = Glyph("✓", ASCIIPass)
+	IconWarn = Glyph("⚠", ASCIIWarn)
+	IconFail = Glyph("✖", ASCIIFail)
 	IconSkip = "-"
-	IconInfo = "ℹ"
+	IconInfo = Glyph("ℹ", ASCIIInfo)
 )
 
 // Issue status icons - used consistently across all commands
 // Design principle: icons > text labels for scannability
-const (
-	StatusIconOpen       = "○" // available to work (hollow circle)
-	StatusIconInProgress = "◐" // active work (half-filled)
-	StatusIconBlocked    = "●" // needs attention (filled circle)
-	StatusIconClosed     = "✓" // completed (checkmark)
-	StatusIconDeferred   = "❄" // scheduled for later (snowflake)
-	StatusIconPinned     = "📌" // elevated priority
+var (
+	StatusIconOpen       = Glyph("○", "o")             // available to work (hollow circle)
+	StatusIconInProgress = Glyph("◐", "~")             // active work (half-filled)
+	StatusIconBlocked    = Glyph("●", "*")             // needs attention (filled circle)
+	StatusIconClosed     = Glyph("✓", ASCIIPass)       // completed (checkmark)
+	StatusIconDeferred   = Glyph("❄", "z")             // scheduled for later (snowflake)
+	StatusIconPinned     = Glyph(Emoji("📌", "▲"), "^") // elevated priority
 )
 
 // Priority icon - small filled circle, colored by priority level
-const PriorityIcon = "●"
+var PriorityIcon = Glyph("●", "*")
 
 // Tree characters for hierarchical display
-const (
-	TreeChild  = "⎿ "  // child indicator
-	TreeLast   = "└─ " // last child / detail line
-	TreeIndent = "  "  // 2-space indent per level
+var (
+	TreeChild  = Glyph("⎿ ", "|_ ")  // child indicator
+	TreeLast   = Glyph("└─ ", "`- ") // last child / detail line
+	TreeIndent = "  "                // 2-space indent per level
 )
 
 // Separators - 42 characters wide
-const (
-	SeparatorLight = "──────────────────────────────────────────"
-	SeparatorHeavy = "══════════════════════════════════════════"
+var (
+	SeparatorLight = Glyph("──────────────────────────────────────────", "------------------------------------------")
+	SeparatorHeavy = Glyph("══════════════════════════════════════════", "==========================================")
 )
 
 // === Core Render Functions ===
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme names selectable via GT_THEME.
+const (
+	ThemeDefault      = "default"       // Ayu adaptive palette
+	ThemeHighContrast = "high-contrast" // saturated basic ANSI colors, bold emphasis
+	ThemeNoColor      = "no-color"      // no ANSI color at all (same as NO_COLOR)
+)
+
+// Glyph set names selectable via GT_GLYPHS.
+const (
+	GlyphsEmoji   = "emoji"   // unicode symbols plus emoji decorations (default on TTY)
+	GlyphsUnicode = "unicode" // unicode symbols, emoji replaced with text
+	GlyphsASCII   = "ascii"   // plain ASCII only, for screen readers and limited fonts
+)
+
+// activeTheme and activeGlyphs are resolved once from the environment.
+// They are package vars (not init-time assignments) so the palette and icon
+// vars in styles.go that depend on them are initialized in the right order.
+var (
+	activeTheme  = detectTheme()
+	activeGlyphs = detectGlyphs()
+)
+
+// ThemeName returns the active theme name.
+func ThemeName() string {
+	return activeTheme
+}
+
+// GlyphSetName returns the active glyph set name.
+func GlyphSetName() string {
+	return activeGlyphs
+}
+
+// IsHighContrast returns true when the high-contrast theme is active.
+func IsHighContrast() bool {
+	return activeTheme == ThemeHighContrast
+}
+
+// ValidThemes returns the selectable theme names.
+func ValidThemes() []string {
+	return []string{ThemeDefault, ThemeHighContrast, ThemeNoColor}
+}
+
+// ValidGlyphSets returns the selectable glyph set names.
+func ValidGlyphSets() []string {
+	return []string{GlyphsEmoji, GlyphsUnicode, GlyphsASCII}
+}
+
+// detectTheme picks the theme from GT_THEME, falling back to no-color when
+// color output is inappropriate (NO_COLOR, CLICOLOR=0, non-TTY).
+func detectTheme() string {
+	if !ShouldUseColor() {
+		return ThemeNoColor
+	}
+	return ConfiguredTheme()
+}
+
+// ConfiguredTheme returns the theme requested via GT_THEME, ignoring terminal
+// detection. Used by non-terminal outputs such as the web dashboard.
+func ConfiguredTheme() string {
+	switch strings.ToLower(os.Getenv("GT_THEME")) {
+	case ThemeHighContrast, "high", "hc":
+		return ThemeHighContrast
+	case ThemeNoColor, "none", "mono":
+		return ThemeNoColor
+	default:
+		return ThemeDefault
+	}
+}
+
+// detectGlyphs picks the glyph set from GT_GLYPHS. Without an explicit
+// choice, emoji are used only when ShouldUseEmoji allows them, and a
+// non-UTF-8 locale falls back to ASCII.
+func detectGlyphs() string {
+	switch strings.ToLower(os.Getenv("GT_GLYPHS")) {
+	case GlyphsASCII:
+		return GlyphsASCII
+	case GlyphsUnicode:
+		return GlyphsUnicode
+	case GlyphsEmoji:
+		return GlyphsEmoji
+	}
+	if !localeSupportsUTF8() {
+		return GlyphsASCII
+	}
+	if !ShouldUseEmoji() {
+		return GlyphsUnicode
+	}
+	return GlyphsEmoji
+}
+
+// localeSupportsUTF8 reports whether the locale environment looks UTF-8
+// capable. An unset locale is assumed capable (the common macOS/Linux case).
+func localeSupportsUTF8() bool {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return true
+}
+
+// colorProfile returns the lipgloss color profile for the active theme,
+// using terminal capability detection instead of assuming TrueColor.
+func colorProfile() termenv.Profile {
+	if activeTheme == ThemeNoColor {
+		return termenv.Ascii
+	}
+	if IsTerminal() {
+		if p := termenv.NewOutput(os.Stdout).EnvColorProfile(); p != termenv.Ascii {
+			return p
+		}
+	}
+	// Forced color (CLICOLOR_FORCE) on a non-TTY: keep distinct colors
+	return termenv.TrueColor
+}
+
+// themed returns the palette color for the active theme. High-contrast uses
+// basic ANSI colors, which every terminal renders with the user's own
+// (typically accessibility-tuned) palette.
+func themed(normal lipgloss.AdaptiveColor, highContrast string) lipgloss.TerminalColor {
+	if activeTheme == ThemeHighContrast {
+		if highContrast == "" {
+			return lipgloss.NoColor{}
+		}
+		return lipgloss.Color(highContrast)
+	}
+	return normal
+}
+
+// Glyph returns the unicode symbol, or its ASCII replacement when the ASCII
+// glyph set is active.
+func Glyph(unicode, ascii string) string {
+	if activeGlyphs == GlyphsASCII {
+		return ascii
+	}
+	return unicode
+}
+
+// Emoji returns the emoji decoration, or the text fallback when emoji are
+// disabled (unicode or ASCII glyph sets).
+func Emoji(emoji, fallback string) string {
+	if activeGlyphs == GlyphsEmoji {
+		return emoji
+	}
+	return fallback
+}
+
+// SelectedStyle highlights the cursor row in TUIs. The default theme uses a
+// subtle background; high-contrast and no-color use reverse video, which
+// stays visible without color.
+var SelectedStyle = selectedStyle()
+
+func selectedStyle() lipgloss.Style {
+	if activeTheme == ThemeDefault {
+		return lipgloss.NewStyle().
+			Background(lipgloss.Color("236")).
+			Foreground(lipgloss.Color("15"))
+	}
+	return lipgloss.NewStyle().Reverse(true)
+}
@@ -0,0 +1,70 @@
+package ui
+
+import "testing"
+
+func TestConfiguredTheme(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"", ThemeDefault},
+		{"high-contrast", ThemeHighContrast},
+		{"HC", ThemeHighContrast},
+		{"no-color", ThemeNoColor},
+		{"mono", ThemeNoColor},
+		{"bogus", ThemeDefault},
+	}
+	for _, tt := range tests {
+		t.Setenv("GT_THEME", tt.env)
+		if got := ConfiguredTheme(); got != tt.want {
+			t.Errorf("GT_THEME=%q: ConfiguredTheme() = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestDetectThemeNoColor(t *testing.T) {
+	t.Setenv("GT_THEME", ThemeHighContrast)
+	t.Setenv("NO_COLOR", "1")
+	if got := detectTheme(); got != ThemeNoColor {
+		t.Errorf("detectTheme() with NO_COLOR = %q, want %q", got, ThemeNoColor)
+	}
+}
+
+func TestDetectGlyphs(t *testing.T) {
+	t.Setenv("GT_GLYPHS", "ascii")
+	if got := detectGlyphs(); got != GlyphsASCII {
+		t.Errorf("GT_GLYPHS=ascii: got %q", got)
+	}
+
+	t.Setenv("GT_GLYPHS", "")
+	t.Setenv("LC_ALL", "C")
+	if got := detectGlyphs(); got != GlyphsASCII {
+		t.Errorf("non-UTF-8 locale: got %q, want %q", got, GlyphsASCII)
+	}
+
+	t.Setenv("LC_ALL", "en_US.UTF-8")
+	t.Setenv("GT_NO_EMOJI", "1")
+	if got := detectGlyphs(); got != GlyphsUnicode {
+		t.Errorf("GT_NO_EMOJI with UTF-8 locale: got %q, want %q", got, GlyphsUnicode)
+	}
+}
+
+func TestGlyphAndEmoji(t *testing.T) {
+	saved := activeGlyphs
+	defer func() { activeGlyphs = saved }()
+
+	activeGlyphs = GlyphsASCII
+	if Glyph("✓", "[ok]") != "[ok]" || Emoji("🚚", "*") != "*" {
+		t.Error("ascii glyph set should use fallbacks")
+	}
+
+	activeGlyphs = GlyphsUnicode
+	if Glyph("✓", "[ok]") != "✓" || Emoji("🚚", "*") != "*" {
+		t.Error("unicode glyph set should keep symbols but drop emoji")
+	}
+
+	activeGlyphs = GlyphsEmoji
+	if Glyph("✓", "[ok]") != "✓" || Emoji("🚚", "*") != "🚚" {
+		t.Error("emoji glyph set should keep both")
+	}
+}
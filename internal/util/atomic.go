@@ -4,6 +4,8 @@ package util
 import (
 	"encoding/json"
 	"os"
+
+	"github.com/steveyegge/gastown/internal/dryrun"
 )
 
 // AtomicWriteJSON writes JSON data to a file atomically.
@@ -23,6 +25,10 @@ func AtomicWriteJSON(path string, v interface{}) error {
 // This prevents data corruption if the process crashes during write.
 // The rename operation is atomic on POSIX systems.
 func AtomicWriteFile(path string, data []byte, perm os.FileMode) error {
+	if dryrun.Enabled() {
+		return dryrun.WriteFile(path, data, perm)
+	}
+
 	tmpFile := path + ".tmp"
 
 	// Write to temp file
@@ -0,0 +1,26 @@
+package util
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := map[string]string{
+		"~":            home,
+		"~/gt":         filepath.Join(home, "gt"),
+		"~/gt/mayor":   filepath.Join(home, "gt", "mayor"),
+		"~gt":          "~gt",
+		"/abs/~/path":  "/abs/~/path",
+		"relative/dir": "relative/dir",
+	}
+	for in, want := range tests {
+		if got := ExpandHome(in); got != want {
+			t.Errorf("ExpandHome(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+package util
+
+import (
+	"os"
+	"syscall"
+)
+
+// ProcessAlive reports whether a process with the given PID exists.
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	// EPERM means the process exists but belongs to someone else
+	return err == nil || err == syscall.EPERM
+}
+
+// TerminateProcess asks a process to exit (SIGTERM).
+func TerminateProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
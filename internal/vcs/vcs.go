@@ -0,0 +1,85 @@
+// Package vcs picks the version control backend for a rig. Rigs are git
+// repositories unless their config says "vcs": "jj", in which case the
+// polecat workspaces and the refinery run on Jujutsu (package jj) through
+// the same Repo operations.
+//
+// Plain directories without version control aren't supported yet: the
+// refinery's merge queue needs branches to merge.
+package vcs
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/jj"
+)
+
+// Backends.
+const (
+	KindGit = "git"
+	KindJJ  = "jj"
+)
+
+// Repo is the version control a rig's polecats, refinery and gt done use.
+// Refs are git-style ("origin/main", "HEAD"); each backend translates.
+type Repo interface {
+	WorkDir() string
+
+	Fetch(remote string) error
+	Pull(remote, branch string) error
+	Push(remote, branch string, force bool) error
+	DeleteRemoteBranch(remote, branch string) error
+
+	Checkout(ref string) error
+	CheckoutNewBranch(name, startPoint string) error
+	CurrentBranch() (string, error)
+	Rev(ref string) (string, error)
+	IsAncestor(ancestor, descendant string) (bool, error)
+	BranchExists(name string) (bool, error)
+	ListBranches(pattern string) ([]string, error)
+	DeleteBranch(name string, force bool) error
+
+	MergeNoFF(branch, message string) error
+	AbortMerge() error
+	ResetHard(ref string) error
+	GetConflictingFiles() ([]string, error)
+	CheckConflictHunks(source, target string, maxBytes int) ([]string, string, error)
+
+	CheckUncommittedWork() (*git.UncommittedWorkStatus, error)
+	CountCommitsBehind(ref string) (int, error)
+	CommitsAhead(base, branch string) (int, error)
+	BranchPushedToRemote(localBranch, remote string) (bool, int, error)
+
+	WorktreeAddFromRef(path, branch, startPoint string) error
+	WorktreeAddDetached(path, ref string) error
+	WorktreeRemove(path string, force bool) error
+	WorktreePrune() error
+	WorktreeList() ([]git.Worktree, error)
+}
+
+var (
+	_ Repo = (*git.Git)(nil)
+	_ Repo = (*jj.JJ)(nil)
+)
+
+// Normalize returns the backend a rig's vcs setting names, "" meaning git.
+func Normalize(kind string) (string, error) {
+	switch kind {
+	case "", KindGit:
+		return KindGit, nil
+	case KindJJ:
+		return KindJJ, nil
+	default:
+		return "", fmt.Errorf("unknown vcs %q (want %s or %s)", kind, KindGit, KindJJ)
+	}
+}
+
+// Open returns the repository of the given kind at dir. An unknown kind
+// opens as git, so a typo in a rig's config degrades to the default
+// rather than stopping its polecats; gt rig add validates with Normalize.
+func Open(kind, dir string) Repo {
+	if kind == KindJJ {
+		return jj.NewJJ(dir)
+	}
+	return git.NewGit(dir)
+}
@@ -0,0 +1,32 @@
+package vcs
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/jj"
+)
+
+func TestNormalize(t *testing.T) {
+	for kind, want := range map[string]string{"": KindGit, "git": KindGit, "jj": KindJJ} {
+		got, err := Normalize(kind)
+		if err != nil || got != want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", kind, got, err, want)
+		}
+	}
+	if _, err := Normalize("hg"); err == nil {
+		t.Error("Normalize(hg) should fail")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, ok := Open("", "/tmp/x").(*git.Git); !ok {
+		t.Error(`Open("") should be git`)
+	}
+	if _, ok := Open(KindJJ, "/tmp/x").(*jj.JJ); !ok {
+		t.Error("Open(jj) should be jj")
+	}
+	if got := Open(KindJJ, "/tmp/x").WorkDir(); got != "/tmp/x" {
+		t.Errorf("WorkDir = %q", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
 )
@@ -117,13 +118,13 @@ func GetRepoRoot() (string, error) {
 	}
 
 	// Try common development paths relative to home
-	home := os.Getenv("HOME")
+	home, _ := os.UserHomeDir()
 	if home != "" {
 		candidates := []string{
-			home + "/gt/gastown",
-			home + "/gastown",
-			home + "/src/gastown",
-			home + "/dev/gastown",
+			filepath.Join(home, "gt", "gastown"),
+			filepath.Join(home, "gastown"),
+			filepath.Join(home, "src", "gastown"),
+			filepath.Join(home, "dev", "gastown"),
 		}
 		for _, candidate := range candidates {
 			if isGitRepo(candidate) && hasGastownMarker(candidate) {
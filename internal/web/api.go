@@ -0,0 +1,117 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/federation"
+)
+
+// maxAPIIssues bounds how many issues a single peer request may ask for.
+const maxAPIIssues = 200
+
+// IssueFetcher defines the interface for looking up issue status for peer towns.
+type IssueFetcher interface {
+	FetchIssues(ids []string) ([]federation.IssueStatus, error)
+}
+
+// IssueAPIHandler serves GET /api/issues?ids=a,b,c so peer towns can track
+// this town's issues from their convoys.
+type IssueAPIHandler struct {
+	fetcher IssueFetcher
+	token   string
+}
+
+// NewIssueAPIHandler creates an issue API handler. If token is non-empty,
+// requests must carry a matching "Authorization: Bearer <token>" header.
+func NewIssueAPIHandler(fetcher IssueFetcher, token string) *IssueAPIHandler {
+	return &IssueAPIHandler{fetcher: fetcher, token: token}
+}
+
+// ServeHTTP handles issue status requests from peer towns.
+func (h *IssueAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.token != "" {
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(h.token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var ids []string
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if !beads.IsValidID(id) { // Anything else is refused before it reaches bd
+			http.Error(w, "invalid issue id", http.StatusBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		http.Error(w, "missing ids parameter", http.StatusBadRequest)
+		return
+	}
+	if len(ids) > maxAPIIssues {
+		http.Error(w, "too many ids", http.StatusBadRequest)
+		return
+	}
+
+	issues, err := h.fetcher.FetchIssues(ids)
+	if err != nil {
+		http.Error(w, "Failed to fetch issues", http.StatusInternalServerError)
+		return
+	}
+	if issues == nil {
+		issues = []federation.IssueStatus{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(issues)
+}
+
+// FetchIssues looks up issue status via bd, which routes by prefix to the
+// owning rig's database.
+func (f *LiveConvoyFetcher) FetchIssues(ids []string) ([]federation.IssueStatus, error) {
+	issues, err := f.showIssues(ids)
+	if err == nil {
+		return issues, nil
+	}
+
+	// Batch failed (usually one unknown ID) - fall back to individual lookups
+	// so known issues are still reported and unknown ones are simply omitted.
+	var result []federation.IssueStatus
+	for _, id := range ids {
+		if one, err := f.showIssues([]string{id}); err == nil {
+			result = append(result, one...)
+		}
+	}
+	return result, nil
+}
+
+// showIssues runs a single bd show for the given IDs. The IDs follow "--"
+// so none can be taken for a flag.
+func (f *LiveConvoyFetcher) showIssues(ids []string) ([]federation.IssueStatus, error) {
+	args := append([]string{"show", "--json", "--"}, ids...)
+
+	out, err := f.bd().Run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []federation.IssueStatus
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
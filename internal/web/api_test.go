@@ -0,0 +1,106 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/federation"
+)
+
+// MockIssueFetcher returns canned issue status for API tests.
+type MockIssueFetcher struct {
+	Issues map[string]federation.IssueStatus
+	Asked  []string
+}
+
+func (m *MockIssueFetcher) FetchIssues(ids []string) ([]federation.IssueStatus, error) {
+	m.Asked = ids
+	var out []federation.IssueStatus
+	for _, id := range ids {
+		if issue, ok := m.Issues[id]; ok {
+			out = append(out, issue)
+		}
+	}
+	return out, nil
+}
+
+func TestIssueAPIHandler_ReturnsKnownIssues(t *testing.T) {
+	mock := &MockIssueFetcher{Issues: map[string]federation.IssueStatus{
+		"gt-abc": {ID: "gt-abc", Title: "Fix auth", Status: "open"},
+	}}
+	handler := NewIssueAPIHandler(mock, "")
+
+	req := httptest.NewRequest("GET", "/api/issues?ids=gt-abc,%20gt-missing", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if len(mock.Asked) != 2 || mock.Asked[1] != "gt-missing" {
+		t.Errorf("fetcher asked for %v, want trimmed ids", mock.Asked)
+	}
+
+	var issues []federation.IssueStatus
+	if err := json.NewDecoder(w.Body).Decode(&issues); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != "gt-abc" {
+		t.Errorf("issues = %+v, want only gt-abc", issues)
+	}
+}
+
+func TestIssueAPIHandler_RequiresIDs(t *testing.T) {
+	handler := NewIssueAPIHandler(&MockIssueFetcher{}, "")
+
+	req := httptest.NewRequest("GET", "/api/issues", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestIssueAPIHandler_Token(t *testing.T) {
+	mock := &MockIssueFetcher{Issues: map[string]federation.IssueStatus{
+		"gt-abc": {ID: "gt-abc", Status: "open"},
+	}}
+	handler := NewIssueAPIHandler(mock, "s3cret")
+
+	req := httptest.NewRequest("GET", "/api/issues?ids=gt-abc", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("without token: status = %d, want 401", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/api/issues?ids=gt-abc", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("with token: status = %d, want 200", w.Code)
+	}
+}
+
+func TestIssueAPIHandler_RejectsInvalidIDs(t *testing.T) {
+	for _, ids := range []string{"--db=/tmp/x", "gt-abc,-v", "gt-abc,../etc", "GT-ABC", "abc"} {
+		mock := &MockIssueFetcher{}
+		handler := NewIssueAPIHandler(mock, "")
+
+		req := httptest.NewRequest("GET", "/api/issues?ids="+url.QueryEscape(ids), nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("ids=%q: status = %d, want 400", ids, w.Code)
+		}
+		if mock.Asked != nil {
+			t.Errorf("ids=%q: fetcher asked for %v", ids, mock.Asked)
+		}
+	}
+}
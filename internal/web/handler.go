@@ -3,6 +3,8 @@ package web
 import (
 	"html/template"
 	"net/http"
+
+	"github.com/steveyegge/gastown/internal/ui"
 )
 
 // ConvoyFetcher defines the interface for fetching convoy data.
@@ -55,6 +57,7 @@ func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Convoys:    convoys,
 		MergeQueue: mergeQueue,
 		Polecats:   polecats,
+		Theme:      ui.ConfiguredTheme(),
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -17,6 +17,7 @@ type ConvoyData struct {
 	Convoys    []ConvoyRow
 	MergeQueue []MergeQueueRow
 	Polecats   []PolecatRow
+	Theme      string // ui theme name, applied as a root element class
 }
 
 // PolecatRow represents a polecat worker in the dashboard.
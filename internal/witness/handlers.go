@@ -9,7 +9,10 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -17,6 +20,9 @@ import (
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
+// log is the witness component logger.
+var log = logging.For("witness")
+
 // HandlerResult tracks the result of handling a protocol message.
 type HandlerResult struct {
 	MessageID    string
@@ -51,6 +57,8 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message) *HandlerResul
 		result.Error = fmt.Errorf("parsing POLECAT_DONE: %w", err)
 		return result
 	}
+	log.Info("polecat done", logging.KeyRig, rigName, logging.KeyPolecat, payload.PolecatName,
+		logging.KeyBead, payload.IssueID, "exit", payload.Exit, "mr", payload.MRID)
 
 	// Handle PHASE_COMPLETE: recycle polecat (session ends but worktree stays)
 	// The polecat is registered as a waiter on the gate and will be re-dispatched
@@ -64,6 +72,14 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message) *HandlerResul
 		return result
 	}
 
+	// The polecat pulled its next bead and is still working: nothing to
+	// clean up, and its MR's MERGED signal must not nuke it
+	if payload.Next != "" {
+		result.Handled = true
+		result.Action = fmt.Sprintf("%s continues with %s (pending MR=%s)", payload.PolecatName, payload.Next, payload.MRID)
+		return result
+	}
+
 	// Check if this polecat has a pending MR
 	// ESCALATED/DEFERRED exits typically have no MR pending
 	hasPendingMR := payload.MRID != "" || payload.Exit == "COMPLETED"
@@ -196,6 +212,13 @@ func HandleHelp(workDir, rigName string, msg *mail.Message, router *mail.Router)
 		result.Handled = true
 		result.MailSent = mailID
 		result.Action = fmt.Sprintf("escalated '%s' to mayor: %s", payload.Topic, assessment.EscalationReason)
+
+		// The mail reaches the Mayor; the escalation record reaches a human
+		if escalationID, err := recordEscalation(workDir, rigName, payload, assessment.EscalationReason); err != nil {
+			log.Warn("recording escalation", logging.KeyRig, rigName, "agent", payload.Agent, "error", err)
+		} else {
+			result.Action += fmt.Sprintf(" (escalation %s)", escalationID)
+		}
 	}
 
 	return result
@@ -567,6 +590,39 @@ Requested at: %s`,
 	return msg.ID, nil
 }
 
+// recordEscalation files an escalation bead for a help request in the town
+// beads, with the polecat's report as its context, and logs it to the event
+// feed so it reaches the notification sinks routed for escalations.
+func recordEscalation(workDir, rigName string, payload *HelpPayload, reason string) (string, error) {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return "", fmt.Errorf("finding town root: %w", err)
+	}
+
+	actor := fmt.Sprintf("%s/witness", rigName)
+	title := fmt.Sprintf("%s needs help: %s", payload.Agent, payload.Topic)
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	issue, err := bd.CreateEscalationBead(title, &beads.EscalationFields{
+		Severity:    config.SeverityHigh,
+		Reason:      reason,
+		Source:      "witness:help",
+		EscalatedBy: actor,
+		EscalatedAt: time.Now().Format(time.RFC3339),
+		RelatedBead: payload.IssueID,
+		Context: fmt.Sprintf("Rig: %s\nAgent: %s\nProblem: %s\nTried: %s",
+			rigName, payload.Agent, payload.Problem, payload.Tried),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	eventPayload := events.EscalationPayload(issue.ID, payload.Agent, "mayor/", title)
+	eventPayload["severity"] = config.SeverityHigh
+	eventPayload["source"] = "witness:help"
+	_ = events.LogFeed(events.TypeEscalationSent, actor, eventPayload)
+	return issue.ID, nil
+}
+
 // RecoveryPayload contains data for RECOVERY_NEEDED escalation.
 type RecoveryPayload struct {
 	PolecatName   string
@@ -672,8 +728,10 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 	address := fmt.Sprintf("%s/%s", rigName, polecatName)
 
 	if err := util.ExecRun(workDir, "gt", "polecat", "nuke", address); err != nil {
+		log.Error("nuke failed", logging.KeyRig, rigName, logging.KeyPolecat, polecatName, "error", err)
 		return fmt.Errorf("nuke failed: %w", err)
 	}
+	log.Info("polecat nuked", logging.KeyRig, rigName, logging.KeyPolecat, polecatName)
 
 	return nil
 }
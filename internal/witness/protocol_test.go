@@ -40,7 +40,8 @@ func TestParsePolecatDone(t *testing.T) {
 	body := `Exit: MERGED
 Issue: gt-abc123
 MR: gt-mr-xyz
-Branch: feature-branch`
+Branch: feature-branch
+Next: gt-def456`
 
 	payload, err := ParsePolecatDone(subject, body)
 	if err != nil {
@@ -62,6 +63,9 @@ Branch: feature-branch`
 	if payload.Branch != "feature-branch" {
 		t.Errorf("Branch = %q, want %q", payload.Branch, "feature-branch")
 	}
+	if payload.Next != "gt-def456" {
+		t.Errorf("Next = %q, want %q", payload.Next, "gt-def456")
+	}
 }
 
 func TestParsePolecatDone_MinimalBody(t *testing.T) {
@@ -83,21 +83,29 @@ func FindOrError(startDir string) (string, error) {
 }
 
 // FindFromCwd locates the town root from the current working directory.
+// Outside any town, it returns the current harness (see Harnesses), if any.
 func FindFromCwd() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("getting current directory: %w", err)
 	}
-	return Find(cwd)
+	root, err := Find(cwd)
+	if err != nil || root != "" {
+		return root, err
+	}
+	return currentHarnessRoot(), nil
 }
 
 // FindFromCwdOrError is like FindFromCwd but returns an error if not found.
 func FindFromCwdOrError() (string, error) {
-	cwd, err := os.Getwd()
+	root, err := FindFromCwd()
 	if err != nil {
-		return "", fmt.Errorf("getting current directory: %w", err)
+		return "", err
 	}
-	return FindOrError(cwd)
+	if root == "" {
+		return "", ErrNotFound
+	}
+	return root, nil
 }
 
 // IsWorkspace checks if the given directory is a Gas Town workspace root.
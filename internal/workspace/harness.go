@@ -0,0 +1,140 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// A harness is a registered town (HQ). Registering several lets one
+// machine run, say, a work town and a personal town: gt finds the town
+// from the current directory as usual, and falls back to the current
+// harness when run outside any town.
+
+// Harness is a registered town.
+type Harness struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Harnesses is the machine's harness registry.
+type Harnesses struct {
+	// Current names the harness used outside any town.
+	Current   string    `json:"current,omitempty"`
+	Harnesses []Harness `json:"harnesses"`
+}
+
+// HarnessesPath returns the path of the harness registry.
+func HarnessesPath() string {
+	return filepath.Join(state.StateDir(), "harnesses.json")
+}
+
+// LoadHarnesses reads the harness registry. A missing registry is empty.
+func LoadHarnesses() (*Harnesses, error) {
+	h := &Harnesses{}
+	data, err := os.ReadFile(HarnessesPath())
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", HarnessesPath(), err)
+	}
+	return h, nil
+}
+
+// SaveHarnesses writes the harness registry.
+func SaveHarnesses(h *Harnesses) error {
+	if err := os.MkdirAll(state.StateDir(), 0755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	return util.AtomicWriteJSON(HarnessesPath(), h)
+}
+
+// Get returns the named harness, or nil.
+func (h *Harnesses) Get(name string) *Harness {
+	for i := range h.Harnesses {
+		if h.Harnesses[i].Name == name {
+			return &h.Harnesses[i]
+		}
+	}
+	return nil
+}
+
+// ByPath returns the harness registered for townRoot, or nil.
+func (h *Harnesses) ByPath(townRoot string) *Harness {
+	for i := range h.Harnesses {
+		if h.Harnesses[i].Path == townRoot {
+			return &h.Harnesses[i]
+		}
+	}
+	return nil
+}
+
+// Add registers townRoot under name, replacing any harness of that name.
+// The first harness added becomes current.
+func (h *Harnesses) Add(name, townRoot string) {
+	if existing := h.Get(name); existing != nil {
+		existing.Path = townRoot
+	} else {
+		h.Harnesses = append(h.Harnesses, Harness{Name: name, Path: townRoot})
+		sort.Slice(h.Harnesses, func(i, j int) bool { return h.Harnesses[i].Name < h.Harnesses[j].Name })
+	}
+	if h.Current == "" {
+		h.Current = name
+	}
+}
+
+// Remove unregisters the named harness, reporting whether it was there.
+func (h *Harnesses) Remove(name string) bool {
+	for i := range h.Harnesses {
+		if h.Harnesses[i].Name == name {
+			h.Harnesses = append(h.Harnesses[:i], h.Harnesses[i+1:]...)
+			if h.Current == name {
+				h.Current = ""
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveHarness returns the town root of the named harness.
+func ResolveHarness(name string) (string, error) {
+	h, err := LoadHarnesses()
+	if err != nil {
+		return "", err
+	}
+	harness := h.Get(name)
+	if harness == nil {
+		return "", fmt.Errorf("unknown harness %q (see 'gt harness list')", name)
+	}
+	if ok, _ := IsWorkspace(harness.Path); !ok {
+		return "", fmt.Errorf("harness %s: %s is not a Gas Town workspace", name, harness.Path)
+	}
+	return harness.Path, nil
+}
+
+// currentHarnessRoot returns the current harness's town root, or "" if
+// there is no usable current harness.
+func currentHarnessRoot() string {
+	h, err := LoadHarnesses()
+	if err != nil || h.Current == "" {
+		return ""
+	}
+	harness := h.Get(h.Current)
+	if harness == nil {
+		return ""
+	}
+	if ok, _ := IsWorkspace(harness.Path); !ok {
+		return ""
+	}
+	return harness.Path
+}
@@ -0,0 +1,90 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func makeTown(t *testing.T) string {
+	t.Helper()
+	root := realPath(t, t.TempDir())
+	if err := os.MkdirAll(filepath.Join(root, "mayor"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, PrimaryMarker), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return root
+}
+
+func TestHarnessesAddRemove(t *testing.T) {
+	h := &Harnesses{}
+	h.Add("work", "/towns/work")
+	h.Add("personal", "/towns/personal")
+
+	if h.Current != "work" {
+		t.Errorf("Current = %q, want first harness added", h.Current)
+	}
+	if h.Harnesses[0].Name != "personal" {
+		t.Errorf("harnesses not sorted: %+v", h.Harnesses)
+	}
+
+	h.Add("work", "/towns/work2")
+	if got := h.Get("work"); got == nil || got.Path != "/towns/work2" || len(h.Harnesses) != 2 {
+		t.Errorf("re-adding should replace the path: %+v", h.Harnesses)
+	}
+	if got := h.ByPath("/towns/personal"); got == nil || got.Name != "personal" {
+		t.Errorf("ByPath = %+v", got)
+	}
+
+	if !h.Remove("work") {
+		t.Fatal("Remove(work) = false")
+	}
+	if h.Current != "" || h.Get("work") != nil {
+		t.Errorf("after removing the current harness: %+v", h)
+	}
+	if h.Remove("work") {
+		t.Error("removing twice should report false")
+	}
+}
+
+func TestFindFromCwdFallsBackToCurrentHarness(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	work := makeTown(t)
+	personal := makeTown(t)
+
+	h := &Harnesses{}
+	h.Add("work", work)
+	h.Add("personal", personal)
+	h.Current = "personal"
+	if err := SaveHarnesses(h); err != nil {
+		t.Fatalf("SaveHarnesses: %v", err)
+	}
+
+	// Inside a town, the town wins
+	t.Chdir(filepath.Join(work, "mayor"))
+	if got, err := FindFromCwdOrError(); err != nil || got != work {
+		t.Errorf("in work town: got %q, %v; want %q", got, err, work)
+	}
+
+	// Outside any town, the current harness
+	t.Chdir(realPath(t, t.TempDir()))
+	if got, err := FindFromCwdOrError(); err != nil || got != personal {
+		t.Errorf("outside a town: got %q, %v; want %q", got, err, personal)
+	}
+
+	// A current harness that no longer exists is ignored
+	if err := os.RemoveAll(filepath.Join(personal, "mayor")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FindFromCwdOrError(); err != ErrNotFound {
+		t.Errorf("missing harness: err = %v, want ErrNotFound", err)
+	}
+	if _, err := ResolveHarness("personal"); err == nil {
+		t.Error("ResolveHarness should reject a harness whose town is gone")
+	}
+	if got, err := ResolveHarness("work"); err != nil || got != work {
+		t.Errorf("ResolveHarness(work) = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,9 @@
+@echo off
+rem ABOUTME: Wrapper script that runs gt prime before launching codex.
+rem ABOUTME: Ensures Gas Town context is available in Codex sessions.
+
+if not defined GASTOWN_DISABLED (
+    where gt >/dev/null 2>/dev/null && gt prime >/dev/null 2>nul
+)
+
+codex %*
@@ -8,6 +8,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+
+	"github.com/steveyegge/gastown/internal/state"
 )
 
 //go:embed scripts/*
@@ -23,8 +26,7 @@ func Install() error {
 		return fmt.Errorf("creating bin directory: %w", err)
 	}
 
-	wrappers := []string{"gt-codex", "gt-opencode"}
-	for _, name := range wrappers {
+	for _, name := range wrapperNames() {
 		content, err := scriptsFS.ReadFile("scripts/" + name)
 		if err != nil {
 			return fmt.Errorf("reading embedded %s: %w", name, err)
@@ -34,6 +36,7 @@ func Install() error {
 		if err := os.WriteFile(destPath, content, 0755); err != nil {
 			return fmt.Errorf("writing %s: %w", name, err)
 		}
+		_ = state.RecordInstall(state.KindFile, destPath, "wrapper script")
 	}
 
 	return nil
@@ -45,8 +48,7 @@ func Remove() error {
 		return err
 	}
 
-	wrappers := []string{"gt-codex", "gt-opencode"}
-	for _, name := range wrappers {
+	for _, name := range wrapperNames() {
 		destPath := filepath.Join(binDir, name)
 		if err := os.Remove(destPath); err != nil && !os.IsNotExist(err) {
 			return fmt.Errorf("removing %s: %w", name, err)
@@ -56,6 +58,24 @@ func Remove() error {
 	return nil
 }
 
+// wrapperNames are the scripts to install: bash scripts, or batch files
+// on Windows.
+func wrapperNames() []string {
+	if runtime.GOOS == "windows" {
+		return []string{"gt-codex.cmd", "gt-opencode.cmd"}
+	}
+	return []string{"gt-codex", "gt-opencode"}
+}
+
+// Paths returns the wrapper scripts Install writes.
+func Paths() []string {
+	var paths []string
+	for _, name := range wrapperNames() {
+		paths = append(paths, filepath.Join(BinDir(), name))
+	}
+	return paths
+}
+
 func binPath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {